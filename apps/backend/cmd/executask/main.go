@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/Sameer16536/ExecuTask/internal/config"
@@ -18,7 +19,8 @@ import (
 	"github.com/Sameer16536/ExecuTask/internal/service"
 )
 
-const DefaultContextTimeout = 30
+// shutdownFlushTimeout is left for flushing telemetry and closing pools once jobs stopped
+const shutdownFlushTimeout = 10 * time.Second
 
 func main() {
 	cfg, err := config.LoadConfig()
@@ -32,10 +34,8 @@ func main() {
 
 	log := logger.NewLoggerWithService(cfg.Observability, loggerService)
 
-	if cfg.Primary.Env != "local" {
-		if err := database.Migrate(context.Background(), &log, cfg); err != nil {
-			log.Fatal().Err(err).Msg("failed to migrate database")
-		}
+	if err := database.PrepareSchema(context.Background(), &log, cfg); err != nil {
+		log.Fatal().Err(err).Msg("failed to prepare database schema")
 	}
 
 	// Initialize server
@@ -45,7 +45,10 @@ func main() {
 	}
 
 	// Initialize repositories, services, and handlers
-	repos := repository.NewRepositories(srv)
+	repos, err := repository.NewRepositories(srv)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize repositories")
+	}
 	services, serviceErr := service.NewServices(srv, repos)
 	if serviceErr != nil {
 		log.Fatal().Err(serviceErr).Msg("could not create services")
@@ -58,7 +61,8 @@ func main() {
 	// Setup HTTP server
 	srv.SetupHTTPServer(r)
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	// Deploys stop the process with SIGTERM, Ctrl+C sends an interrupt
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 
 	// Start server
 	go func() {
@@ -67,14 +71,30 @@ func main() {
 		}
 	}()
 
+	// SIGHUP reloads the config values that are safe to change at runtime
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			// Failures are logged by the server, the current config stays in effect
+			_, _ = srv.ReloadConfig()
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	<-ctx.Done()
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout*time.Second)
+	// A second signal kills the process right away
+	stop()
+	signal.Stop(reload)
+
+	// Bounds the whole shutdown, the server enforces the request and job deadlines itself
+	shutdownConfig := cfg.Server.Shutdown
+	ctx, cancel := context.WithTimeout(context.Background(),
+		shutdownConfig.DrainDelay+shutdownConfig.Timeout+shutdownConfig.JobTimeout+shutdownFlushTimeout)
 
 	if err = srv.Shutdown(ctx); err != nil {
 		log.Fatal().Err(err).Msg("server forced to shutdown")
 	}
-	stop()
 	cancel()
 
 	log.Info().Msg("server exited properly")
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/database"
+	"github.com/Sameer16536/ExecuTask/internal/database/migrations"
+	"github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	rootCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Executask Database Migrations",
+		Long:  "Executask Database Migrations - Apply, revert and verify the embedded schema migrations",
+	}
+
+	// Status command
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the schema version, pending migrations and drift",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(func(ctx context.Context, runner *migrations.Runner) (*migrations.Status, error) {
+				return runner.Status(ctx)
+			})
+		},
+	}
+	rootCmd.AddCommand(statusCmd)
+
+	// Up command
+	upCmd := &cobra.Command{
+		Use:   "up",
+		Short: "Apply all pending migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(func(ctx context.Context, runner *migrations.Runner) (*migrations.Status, error) {
+				return runner.Up(ctx)
+			})
+		},
+	}
+	rootCmd.AddCommand(upCmd)
+
+	// Down command
+	var target int32
+	downCmd := &cobra.Command{
+		Use:   "down",
+		Short: "Revert the migrations above a version",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(func(ctx context.Context, runner *migrations.Runner) (*migrations.Status, error) {
+				return runner.Down(ctx, target)
+			})
+		},
+	}
+	downCmd.Flags().Int32Var(&target, "to", 0, "version to revert to")
+	_ = downCmd.MarkFlagRequired("to")
+	rootCmd.AddCommand(downCmd)
+
+	// Verify command
+	verifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Fail when the schema drifted from the applied migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(func(ctx context.Context, runner *migrations.Runner) (*migrations.Status, error) {
+				return runner.Verify(ctx)
+			})
+		},
+	}
+	rootCmd.AddCommand(verifyCmd)
+
+	// Accept command
+	acceptCmd := &cobra.Command{
+		Use:   "accept",
+		Short: "Record the live schema as expected after reviewing a manual change",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(func(ctx context.Context, runner *migrations.Runner) (*migrations.Status, error) {
+				return runner.Accept(ctx)
+			})
+		},
+	}
+	rootCmd.AddCommand(acceptCmd)
+
+	// Partition command
+	var partitionCount int
+	partitionCmd := &cobra.Command{
+		Use:   "partition",
+		Short: "Hash partition the todos table by user, in a maintenance window",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(func(ctx context.Context, runner *migrations.Runner) (*migrations.Status, error) {
+				return runner.Partition(ctx, partitionCount)
+			})
+		},
+	}
+	partitionCmd.Flags().IntVar(&partitionCount, "partitions", 16, "number of hash partitions")
+	rootCmd.AddCommand(partitionCmd)
+
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func run(fn func(ctx context.Context, runner *migrations.Runner) (*migrations.Status, error)) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	loggerService := logger.NewLoggerService(cfg.Observability)
+	defer loggerService.Shutdown()
+	log := logger.NewLoggerWithService(cfg.Observability, loggerService)
+
+	ctx := context.Background()
+	runner, conn, err := database.OpenMigrations(ctx, &log, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer conn.Close(ctx)
+
+	status, err := fn(ctx, runner)
+	var drift *migrations.DriftError
+	if errors.As(err, &drift) {
+		status = drift.Status
+	}
+	if status != nil {
+		printStatus(status)
+	}
+
+	return err
+}
+
+func printStatus(status *migrations.Status) {
+	fmt.Printf("version:  %d of %d\n", status.Current, status.Latest)
+	if len(status.Pending) > 0 {
+		fmt.Printf("pending:  %s\n", strings.Join(status.Pending, ", "))
+	}
+	if len(status.Edited) > 0 {
+		fmt.Printf("edited:   %s\n", strings.Join(status.Edited, ", "))
+	}
+	if status.SchemaChanged {
+		fmt.Println("schema:   changed outside of migrations")
+	}
+	if status.Unrecorded {
+		fmt.Println("history:  not recorded yet, verify or up adopts the applied migrations")
+	}
+	if !status.Drifted() && !status.Unrecorded {
+		fmt.Println("schema:   matches the applied migrations")
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestGeneratedClientsAreCurrent regenerates the clients the way go generate does in
+// pkg/client and fails when the committed files differ, run task generate to update them
+func TestGeneratedClientsAreCurrent(t *testing.T) {
+	committedGo := filepath.Join("..", "..", "pkg", "client", "client.gen.go")
+	committedTS := filepath.Join("..", "..", "..", "..", "packages", "sdk", "src", "client.gen.ts")
+
+	dir := t.TempDir()
+	goOut := filepath.Join(dir, "client.gen.go")
+	tsOut := filepath.Join(dir, "client.gen.ts")
+
+	for range 2 {
+		require.NoError(t, run(goOut, "client", tsOut, ""))
+
+		for generated, committed := range map[string]string{goOut: committedGo, tsOut: committedTS} {
+			want, err := os.ReadFile(committed)
+			require.NoError(t, err)
+			got, err := os.ReadFile(generated)
+			require.NoError(t, err)
+			require.Equal(t, string(want), string(got), "%s is out of date, run task generate", committed)
+		}
+	}
+}
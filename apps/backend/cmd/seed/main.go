@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/database"
+	"github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/Sameer16536/ExecuTask/internal/seed"
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	rootCmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Executask Development Data Seeder",
+		Long:  "Executask Development Data Seeder - Fill a development database with realistic data for load testing",
+	}
+
+	// Run command
+	var scale string
+	var users, todos int
+	var seedValue uint64
+	runCmd := &cobra.Command{
+		Use:   "run",
+		Short: "Seed users, categories, nested todos, comments and attachments",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, ok := seed.Presets[scale]
+			if !ok {
+				return fmt.Errorf("unknown scale '%s', use one of %s", scale, strings.Join(presetNames(), ", "))
+			}
+			if cmd.Flags().Changed("users") {
+				cfg.Users = users
+			}
+			if cmd.Flags().Changed("todos") {
+				cfg.Todos = todos
+			}
+			if cmd.Flags().Changed("seed") {
+				cfg.Seed = seedValue
+			}
+
+			return withSeeder(func(ctx context.Context, seeder *seed.Seeder) error {
+				result, err := seeder.Run(ctx, cfg)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("seeded %d users, %d categories, %d todos, %d comments, %d attachments\n",
+					result.Users, result.Categories, result.Todos, result.Comments, result.Attachments)
+				return nil
+			})
+		},
+	}
+	runCmd.Flags().StringVar(&scale, "scale", "small", "preset scale: "+strings.Join(presetNames(), ", "))
+	runCmd.Flags().IntVar(&users, "users", 0, "number of users, overrides the preset")
+	runCmd.Flags().IntVar(&todos, "todos", 0, "total number of todos, overrides the preset")
+	runCmd.Flags().Uint64Var(&seedValue, "seed", 0, "random seed, the same seed generates the same data")
+	rootCmd.AddCommand(runCmd)
+
+	// Reset command
+	resetCmd := &cobra.Command{
+		Use:   "reset",
+		Short: "Delete everything owned by seeded users",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withSeeder(func(ctx context.Context, seeder *seed.Seeder) error {
+				return seeder.Reset(ctx)
+			})
+		},
+	}
+	rootCmd.AddCommand(resetCmd)
+
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func withSeeder(fn func(ctx context.Context, seeder *seed.Seeder) error) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if cfg.Primary.Env == "production" {
+		return errors.New("seeding is disabled in production")
+	}
+
+	loggerService := logger.NewLoggerService(cfg.Observability)
+	defer loggerService.Shutdown()
+	log := logger.NewLoggerWithService(cfg.Observability, loggerService)
+
+	db, err := database.New(cfg, &log, loggerService)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer db.Close()
+
+	return fn(context.Background(), seed.NewSeeder(db.Pool, &log))
+}
+
+func presetNames() []string {
+	names := make([]string, 0, len(seed.Presets))
+	for name := range seed.Presets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
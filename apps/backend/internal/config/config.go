@@ -1,14 +1,8 @@
 package config
 
 import (
-	"os"
 	"strings"
-
-	"github.com/go-playground/validator/v10"
-	_ "github.com/joho/godotenv/autoload"
-	"github.com/knadh/koanf/providers/env"
-	"github.com/knadh/koanf/v2"
-	"github.com/rs/zerolog"
+	"time"
 )
 
 type Config struct {
@@ -20,7 +14,37 @@ type Config struct {
 	Integration   IntegrationConfig    `koanf:"integration" validate:"required"`
 	Observability *ObservabilityConfig `koanf:"observability"`
 	AWS           AWSConfig            `koanf:"aws" validate:"required"`
+	Storage       *StorageConfig       `koanf:"storage"`
 	Cron          *CronConfig          `koanf:"cron"`
+	Sync          *SyncConfig          `koanf:"sync"`
+	API           *APIConfig           `koanf:"api"`
+	Changes       *ChangesConfig       `koanf:"changes"`
+	RBAC          *RBACConfig          `koanf:"rbac"`
+	Search        *SearchConfig        `koanf:"search"`
+	Embedding     *EmbeddingConfig     `koanf:"embedding"`
+	Features      *FeaturesConfig      `koanf:"features"`
+	Usage         *UsageConfig         `koanf:"usage"`
+	Export        *ExportConfig        `koanf:"export"`
+	Retention     *RetentionConfig     `koanf:"retention"`
+	Attachments   *AttachmentsConfig   `koanf:"attachments"`
+	Encryption    *EncryptionConfig    `koanf:"encryption"`
+	Analytics     *AnalyticsConfig     `koanf:"analytics"`
+	LLM           *LLMConfig           `koanf:"llm"`
+	Transcription *TranscriptionConfig `koanf:"transcription"`
+	InboundEmail  *InboundEmailConfig  `koanf:"inbound_email"`
+	LinkPreviews  *LinkPreviewsConfig  `koanf:"link_previews"`
+	Covers        *CoversConfig        `koanf:"covers"`
+	Scanning      *ScanningConfig      `koanf:"scanning"`
+	APIKeys       *APIKeysConfig       `koanf:"api_keys"`
+	Mail          *MailConfig          `koanf:"mail"`
+	Alerts        *AlertsConfig        `koanf:"alerts"`
+	Breakers      *BreakersConfig      `koanf:"breakers"`
+	Outbound      *OutboundConfig      `koanf:"outbound"`
+	Todos         *TodosConfig         `koanf:"todos"`
+	Webhooks      *WebhooksConfig      `koanf:"webhooks"`
+
+	// sources are the flattened values the config was built from
+	sources map[string]interface{}
 }
 
 type Primary struct {
@@ -33,6 +57,172 @@ type ServerConfig struct {
 	WriteTimeout       int      `koanf:"write_timeout" validate:"required"`
 	IdleTimeout        int      `koanf:"idle_timeout" validate:"required"`
 	CORSAllowedOrigins []string `koanf:"cors_allowed_origins" validate:"required"`
+	// Timeouts bound the work a request may start, repository calls inherit the request context
+	Timeouts *TimeoutConfig `koanf:"timeouts"`
+	// Compression negotiates gzip or brotli response bodies
+	Compression *CompressionConfig `koanf:"compression"`
+	// PayloadBudget flags responses that grew past an expected size
+	PayloadBudget *PayloadBudgetConfig `koanf:"payload_budget"`
+	// BodyLimits cap the size of request bodies
+	BodyLimits *BodyLimitConfig `koanf:"body_limits"`
+	// SecurityHeaders harden every response, HTML views get a content security policy of their own
+	SecurityHeaders *SecurityHeadersConfig `koanf:"security_headers"`
+	// Shutdown bounds how long a stopping server drains requests and jobs
+	Shutdown *ShutdownConfig `koanf:"shutdown"`
+	// RateLimit caps the requests per client IP, it can be changed with a config reload
+	RateLimit *RateLimitConfig `koanf:"rate_limit"`
+	// Mode is the starting point of the maintenance and read-only switches, admins flip them at runtime
+	Mode *ModeConfig `koanf:"mode"`
+	// FaultInjection lets operators inject latency, errors and dropped responses, for staging only
+	FaultInjection *FaultInjectionConfig `koanf:"fault_injection"`
+}
+
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `koanf:"requests_per_second" validate:"gt=0"`
+	// Burst is how many requests may arrive at once, 0 allows one second worth of them
+	Burst int `koanf:"burst" validate:"min=0"`
+}
+
+func DefaultRateLimitConfig() *RateLimitConfig {
+	return &RateLimitConfig{
+		RequestsPerSecond: 20,
+	}
+}
+
+type TimeoutConfig struct {
+	// Request is the deadline of every request context, 0 disables it. Streamed responses are exempt.
+	Request time.Duration `koanf:"request"`
+	// DetachOnDisconnect lets in-flight queries finish when the client goes away,
+	// by default they are cancelled with the request
+	DetachOnDisconnect bool `koanf:"detach_on_disconnect"`
+}
+
+func DefaultTimeoutConfig() *TimeoutConfig {
+	return &TimeoutConfig{
+		Request: 20 * time.Second,
+	}
+}
+
+type CompressionConfig struct {
+	Disabled bool `koanf:"disabled"`
+	// MinSizeBytes leaves smaller bodies uncompressed, the encoding overhead isn't worth it
+	MinSizeBytes int `koanf:"min_size_bytes"`
+}
+
+func DefaultCompressionConfig() *CompressionConfig {
+	return &CompressionConfig{
+		MinSizeBytes: 1024,
+	}
+}
+
+type PayloadBudgetConfig struct {
+	// MaxBytes is the uncompressed size a buffered response is expected to stay under, 0 disables the guard
+	MaxBytes int64 `koanf:"max_bytes"`
+	// Reject fails responses over the budget with a 500 instead of only logging them
+	Reject bool `koanf:"reject"`
+}
+
+func DefaultPayloadBudgetConfig() *PayloadBudgetConfig {
+	return &PayloadBudgetConfig{
+		MaxBytes: 1 << 20,
+	}
+}
+
+type BodyLimitConfig struct {
+	// DefaultBytes caps the bodies of every route without a limit of its own, 0 is unlimited
+	DefaultBytes int64 `koanf:"default_bytes" validate:"min=0"`
+	// UploadBytes caps the bodies of the file upload routes
+	UploadBytes int64 `koanf:"upload_bytes" validate:"min=0"`
+	// Routes overrides the limit of routes by their path, such as /api/v1/todos/:id/attachments
+	Routes map[string]int64 `koanf:"routes"`
+}
+
+func DefaultBodyLimitConfig() *BodyLimitConfig {
+	return &BodyLimitConfig{
+		DefaultBytes: 1 << 20,
+		UploadBytes:  100 << 20,
+	}
+}
+
+type SecurityHeadersConfig struct {
+	// HSTSMaxAge is the Strict-Transport-Security max-age in seconds, sent over HTTPS only. 0
+	// leaves the header out.
+	HSTSMaxAge            int  `koanf:"hsts_max_age" validate:"min=0"`
+	HSTSIncludeSubdomains bool `koanf:"hsts_include_subdomains"`
+	HSTSPreload           bool `koanf:"hsts_preload"`
+	// ContentSecurityPolicy is the policy of every route without one of its own. JSON responses
+	// load nothing, so the default allows nothing.
+	ContentSecurityPolicy string `koanf:"content_security_policy"`
+	ReferrerPolicy        string `koanf:"referrer_policy"`
+	// Routes overrides the content security policy of routes by their path, such as /docs
+	Routes map[string]string `koanf:"routes"`
+}
+
+func DefaultSecurityHeadersConfig() *SecurityHeadersConfig {
+	return &SecurityHeadersConfig{
+		HSTSMaxAge:            365 * 24 * 60 * 60,
+		HSTSIncludeSubdomains: true,
+		ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'; base-uri 'none'; form-action 'none'",
+		ReferrerPolicy:        "no-referrer",
+	}
+}
+
+type ShutdownConfig struct {
+	// DrainDelay keeps serving while readiness already fails, so load balancers stop routing
+	// new requests before the listener closes
+	DrainDelay time.Duration `koanf:"drain_delay"`
+	// Timeout is how long in-flight requests may take to finish, the rest are cut
+	Timeout time.Duration `koanf:"timeout"`
+	// JobTimeout is how long running jobs may take to finish, unfinished ones are requeued
+	JobTimeout time.Duration `koanf:"job_timeout"`
+}
+
+func DefaultShutdownConfig() *ShutdownConfig {
+	return &ShutdownConfig{
+		Timeout:    30 * time.Second,
+		JobTimeout: 20 * time.Second,
+	}
+}
+
+// ServerMode restricts what every instance serves, for migrations and incident response
+type ServerMode string
+
+const (
+	ModeNormal ServerMode = "normal"
+	// ModeMaintenance answers everything but health checks and the admin API with a 503
+	ModeMaintenance ServerMode = "maintenance"
+	// ModeReadOnly rejects every write, reads are served as usual
+	ModeReadOnly ServerMode = "read_only"
+)
+
+type ModeConfig struct {
+	// Default applies until an admin sets a mode, and whenever Redis can't be reached at startup
+	Default ServerMode `koanf:"default" validate:"omitempty,oneof=normal maintenance read_only"`
+	// RetryAfter is what rejected clients are told to wait before trying again
+	RetryAfter time.Duration `koanf:"retry_after"`
+	// RefreshInterval is how long an instance trusts the mode it read, switches take up to this long to apply
+	RefreshInterval time.Duration `koanf:"refresh_interval"`
+}
+
+func DefaultModeConfig() *ModeConfig {
+	return &ModeConfig{
+		Default:         ModeNormal,
+		RetryAfter:      5 * time.Minute,
+		RefreshInterval: 5 * time.Second,
+	}
+}
+
+type FaultInjectionConfig struct {
+	// Enabled allows operators to set fault rules, it can't be turned on in production
+	Enabled bool `koanf:"enabled"`
+	// MaxTTL caps how long rules stay active, forgotten rules expire on their own
+	MaxTTL time.Duration `koanf:"max_ttl"`
+}
+
+func DefaultFaultInjectionConfig() *FaultInjectionConfig {
+	return &FaultInjectionConfig{
+		MaxTTL: time.Hour,
+	}
 }
 
 type DatabaseConfig struct {
@@ -46,26 +236,132 @@ type DatabaseConfig struct {
 	MaxIdleConns    int    `koanf:"max_idle_conns" validate:"required"`
 	ConnMaxLifetime int    `koanf:"conn_max_lifetime" validate:"required"`
 	ConnMaxIdleTime int    `koanf:"conn_max_idle_time" validate:"required"`
+	// MinConns are kept open even when idle, capped at MaxOpenConns
+	MinConns int `koanf:"min_conns" validate:"omitempty,min=0"`
+	// StatementTimeout aborts any single statement running longer on the server, 0 disables it
+	StatementTimeout time.Duration `koanf:"statement_timeout"`
+	// Replicas serve heavy reads, the primary above serves everything else
+	Replicas *ReplicaConfig `koanf:"replicas"`
+	// Migrations decides what the API does with the schema on startup
+	Migrations *MigrationsConfig `koanf:"migrations"`
+}
+
+const (
+	// MigrationsModeMigrate applies pending migrations on startup
+	MigrationsModeMigrate = "migrate"
+	// MigrationsModeVerify refuses to start with pending migrations, they are applied by cmd/migrate
+	MigrationsModeVerify = "verify"
+	// MigrationsModeOff leaves the schema alone
+	MigrationsModeOff = "off"
+)
+
+type MigrationsConfig struct {
+	// Mode is migrate, verify or off. Both migrate and verify refuse to start on a drifted schema.
+	Mode string `koanf:"mode" validate:"omitempty,oneof=migrate verify off"`
+}
+
+// DefaultMigrationsConfig migrates on startup, local databases are migrated by hand
+func DefaultMigrationsConfig(env string) *MigrationsConfig {
+	mode := MigrationsModeMigrate
+	if env == "local" {
+		mode = MigrationsModeOff
+	}
+	return &MigrationsConfig{
+		Mode: mode,
+	}
+}
+
+type ReplicaConfig struct {
+	// Hosts are host or host:port entries sharing the primary's credentials and database
+	Hosts []string `koanf:"hosts"`
+	// MaxLagSeconds takes a replica out of rotation while it lags further behind the primary
+	MaxLagSeconds int `koanf:"max_lag_seconds"`
+	// ReadYourWritesSeconds keeps a user's reads on the primary for this long after a write
+	ReadYourWritesSeconds int `koanf:"read_your_writes_seconds"`
+}
+
+func DefaultReplicaConfig() *ReplicaConfig {
+	return &ReplicaConfig{
+		Hosts:                 []string{},
+		MaxLagSeconds:         5,
+		ReadYourWritesSeconds: 10,
+	}
 }
+
 type RedisConfig struct {
 	Address  string `koanf:"address" validate:"required"`
 	Password string `koanf:"password"`
 }
 
 type IntegrationConfig struct {
-	ResendAPIKey string `koanf:"resend_api_key" validate:"required"`
+	// ResendAPIKey is only required by the resend mail driver
+	ResendAPIKey string `koanf:"resend_api_key"`
 }
 
 type AuthConfig struct {
 	SecretKey string `koanf:"secret_key" validate:"required"`
+	// Cookie lets browsers keep their session token in a cookie scripts can't read
+	Cookie *SessionCookieConfig `koanf:"cookie"`
+}
+
+// SessionCookieConfig is the cookie session mode. Browsers exchange their session token for an
+// HTTP-only cookie, the unsafe requests made with it must echo the CSRF cookie in a header.
+// Bearer tokens and API keys keep working alongside it.
+type SessionCookieConfig struct {
+	Enabled bool `koanf:"enabled"`
+	// Name is the cookie holding the session token, CSRFName the one holding the CSRF token
+	Name     string `koanf:"name"`
+	CSRFName string `koanf:"csrf_name"`
+	// Domain scopes the cookies to a parent domain, they are scoped to the API host without it
+	Domain   string `koanf:"domain"`
+	SameSite string `koanf:"same_site" validate:"omitempty,oneof=lax strict none"`
+	// Insecure leaves out the Secure attribute, for local development over plain HTTP
+	Insecure bool `koanf:"insecure"`
+}
+
+func DefaultSessionCookieConfig() *SessionCookieConfig {
+	return &SessionCookieConfig{
+		Name:     "executask_session",
+		CSRFName: "executask_csrf",
+		SameSite: "lax",
+	}
 }
 
+// AWSConfig holds the credentials of every AWS service. They are only required by the s3 and
+// minio storage drivers, features such as KMS or Transcribe fail without them.
 type AWSConfig struct {
-	Region          string `koanf:"region" validate:"required"`
-	AccessKeyID     string `koanf:"access_key_id" validate:"required"`
-	SecretAccessKey string `koanf:"secret_access_key" validate:"required"`
+	Region          string `koanf:"region"`
+	AccessKeyID     string `koanf:"access_key_id"`
+	SecretAccessKey string `koanf:"secret_access_key"`
 	EndpointURL     string `koanf:"endpoint_url"`
-	S3Bucket        string `koanf:"s3_bucket" validate:"required"`
+	// S3Bucket holds attachments, exports and backups, the filesystem driver makes it a directory
+	S3Bucket string `koanf:"s3_bucket" validate:"required"`
+}
+
+const (
+	StorageDriverS3         = "s3"
+	StorageDriverMinIO      = "minio"
+	StorageDriverFileSystem = "filesystem"
+)
+
+// StorageConfig selects where objects are stored, so self-hosted deployments and integration
+// tests can run without AWS
+type StorageConfig struct {
+	// Driver is s3, minio for a MinIO server at aws.endpoint_url, or filesystem
+	Driver string `koanf:"driver" validate:"omitempty,oneof=s3 minio filesystem"`
+	// Path is the directory the filesystem driver keeps buckets in
+	Path string `koanf:"path" validate:"required_if=Driver filesystem"`
+	// PublicURL is where clients reach the API, the download links of the filesystem driver
+	// point at its /blobs route
+	PublicURL string `koanf:"public_url" validate:"required_if=Driver filesystem,omitempty,url"`
+	// SigningKey signs the download links of the filesystem driver
+	SigningKey string `koanf:"signing_key" validate:"required_if=Driver filesystem"`
+}
+
+func DefaultStorageConfig() *StorageConfig {
+	return &StorageConfig{
+		Driver: StorageDriverS3,
+	}
 }
 
 type CronConfig struct {
@@ -73,6 +369,8 @@ type CronConfig struct {
 	BatchSize                   int `koanf:"batch_size"`
 	ReminderHours               int `koanf:"reminder_hours"`
 	MaxTodosPerUserNotification int `koanf:"max_todos_per_user_notification"`
+	// VacuumDeadTupleRatio is the share of dead tuples at which partition-maintenance vacuums a partition
+	VacuumDeadTupleRatio float64 `koanf:"vacuum_dead_tuple_ratio"`
 }
 
 func DefaultCronConfig() *CronConfig {
@@ -81,6 +379,597 @@ func DefaultCronConfig() *CronConfig {
 		BatchSize:                   100,
 		ReminderHours:               24,
 		MaxTodosPerUserNotification: 10,
+		VacuumDeadTupleRatio:        0.2,
+	}
+}
+
+type SyncConfig struct {
+	ConflictStrategy string `koanf:"conflict_strategy" validate:"omitempty,oneof=server_wins client_wins field_merge"`
+	MaxChangedTodos  int    `koanf:"max_changed_todos"`
+}
+
+func DefaultSyncConfig() *SyncConfig {
+	return &SyncConfig{
+		ConflictStrategy: "field_merge",
+		MaxChangedTodos:  500,
+	}
+}
+
+type ChangesConfig struct {
+	RetentionDays int `koanf:"retention_days"`
+}
+
+func DefaultChangesConfig() *ChangesConfig {
+	return &ChangesConfig{
+		RetentionDays: 30,
+	}
+}
+
+type SearchConfig struct {
+	// SimilarityThreshold is the minimum pg_trgm word similarity (0-1) for a fuzzy match,
+	// lower values tolerate more typos
+	SimilarityThreshold float64 `koanf:"similarity_threshold" validate:"omitempty,gte=0,lte=1"`
+	// DuplicateThreshold is the minimum pg_trgm similarity (0-1) of the titles of an open todo
+	// and a new one for the open todo to be reported as a possible duplicate
+	DuplicateThreshold float64 `koanf:"duplicate_threshold" validate:"omitempty,gte=0,lte=1"`
+}
+
+func DefaultSearchConfig() *SearchConfig {
+	return &SearchConfig{
+		SimilarityThreshold: 0.4,
+		DuplicateThreshold:  0.5,
+	}
+}
+
+// EmbeddingConfig selects the provider of the vectors behind semantic search
+type EmbeddingConfig struct {
+	// Provider computes the embeddings, empty disables semantic search
+	Provider string `koanf:"provider" validate:"omitempty,oneof=openai"`
+	APIKey   string `koanf:"api_key"`
+	// BaseURL points the OpenAI provider at any API compatible endpoint
+	BaseURL string `koanf:"base_url"`
+	Model   string `koanf:"model"`
+	// BatchSize is the number of todos embedded per provider call
+	BatchSize int `koanf:"batch_size" validate:"omitempty,min=1,max=2048"`
+	// MinSimilarity is the cosine similarity (0-1) a todo needs to be a semantic match
+	MinSimilarity float64 `koanf:"min_similarity" validate:"omitempty,gte=0,lte=1"`
+}
+
+func DefaultEmbeddingConfig() *EmbeddingConfig {
+	return &EmbeddingConfig{
+		BaseURL:       "https://api.openai.com/v1",
+		Model:         "text-embedding-3-small",
+		BatchSize:     100,
+		MinSimilarity: 0.3,
+	}
+}
+
+// RBACConfig grants deployment wide roles to Clerk user IDs
+type RBACConfig struct {
+	Admins    []string `koanf:"admins"`
+	Operators []string `koanf:"operators"`
+}
+
+func DefaultRBACConfig() *RBACConfig {
+	return &RBACConfig{
+		Admins:    []string{},
+		Operators: []string{},
+	}
+}
+
+// Feature flag names, flags gate features that are still being rolled out
+const (
+	FeatureSync                = "sync"
+	FeatureSemanticSearch      = "semantic_search"
+	FeatureTodoSuggestions     = "todo_suggestions"
+	FeatureReminderSuggestions = "reminder_suggestions"
+)
+
+type FeaturesConfig struct {
+	// Flags are keyed by name, overrides stored in the database take precedence
+	Flags map[string]FeatureFlagConfig `koanf:"flags" validate:"dive"`
+}
+
+type FeatureFlagConfig struct {
+	// Enabled turns the feature on for everyone
+	Enabled bool `koanf:"enabled"`
+	// RolloutPercent turns a disabled feature on for a stable share of users
+	RolloutPercent int `koanf:"rollout_percent" validate:"min=0,max=100"`
+}
+
+// DefaultFeaturesConfig lists every known flag, features that shipped before flags existed
+// stay on
+func DefaultFeaturesConfig() *FeaturesConfig {
+	return &FeaturesConfig{
+		Flags: map[string]FeatureFlagConfig{
+			FeatureSync:                {Enabled: true},
+			FeatureSemanticSearch:      {Enabled: true},
+			FeatureTodoSuggestions:     {Enabled: true},
+			FeatureReminderSuggestions: {Enabled: true},
+		},
+	}
+}
+
+// UsageConfig tunes usage metering, see the usage-rollup job
+type UsageConfig struct {
+	// FlushInterval is how often an instance adds its buffered counts to Redis
+	FlushInterval time.Duration `koanf:"flush_interval"`
+	// RollupLookbackHours is how many completed hours every rollup aggregates again, counts
+	// flushed late still end up in their hour
+	RollupLookbackHours int `koanf:"rollup_lookback_hours" validate:"omitempty,min=1,max=48"`
+}
+
+func DefaultUsageConfig() *UsageConfig {
+	return &UsageConfig{
+		FlushInterval:       10 * time.Second,
+		RollupLookbackHours: 3,
+	}
+}
+
+// ExportConfig tunes account data exports and todo list PDFs
+type ExportConfig struct {
+	// LinkTTL is how long a download link stays valid, the email carries one and every fetch of
+	// the export mints a new one
+	LinkTTL time.Duration `koanf:"link_ttl" validate:"omitempty,max=168h"`
+	// Retention is how long an archive is kept before the account-export-cleanup job deletes it
+	Retention time.Duration `koanf:"retention"`
+	// PDFInlineLimit is the most todos a list PDF is rendered for within the request, longer
+	// lists are exported by a background job
+	PDFInlineLimit int `koanf:"pdf_inline_limit" validate:"min=0"`
+}
+
+func DefaultExportConfig() *ExportConfig {
+	return &ExportConfig{
+		LinkTTL:        24 * time.Hour,
+		Retention:      7 * 24 * time.Hour,
+		PDFInlineLimit: 100,
+	}
+}
+
+// RetentionConfig is the retention policy of personal todos and of workspaces without a
+// policy of their own. A zero rule keeps the data forever.
+type RetentionConfig struct {
+	// ArchivedTodoMonths purges archived todos untouched for that many months
+	ArchivedTodoMonths int `koanf:"archived_todo_months" validate:"min=0"`
+	// MaxCommentsPerTodo deletes the oldest comments of a todo beyond that many
+	MaxCommentsPerTodo int `koanf:"max_comments_per_todo" validate:"min=0"`
+	// AttachmentDays expires attachments uploaded that many days ago
+	AttachmentDays int `koanf:"attachment_days" validate:"min=0"`
+	// Enforce lets the data-retention job delete, it only reports what it would delete otherwise
+	Enforce bool `koanf:"enforce"`
+}
+
+func DefaultRetentionConfig() *RetentionConfig {
+	return &RetentionConfig{}
+}
+
+// AttachmentsConfig tunes attachment storage, the attachment-reconcile job applies the
+// lifecycle settings
+type AttachmentsConfig struct {
+	// UserQuotaBytes caps the attachment bytes across a user's todos, 0 is unlimited
+	UserQuotaBytes int64 `koanf:"user_quota_bytes" validate:"min=0"`
+	// InfrequentAccessDays moves objects uploaded that many days ago to the infrequent access
+	// storage class, 0 keeps every object standard
+	InfrequentAccessDays int `koanf:"infrequent_access_days" validate:"min=0"`
+	// OrphanGracePeriod is how old an object without an attachment record gets before it is
+	// deleted, an upload stores the object before it inserts the record
+	OrphanGracePeriod time.Duration `koanf:"orphan_grace_period"`
+	// AllowedContentTypes are the types uploads may have, told from their first bytes. An entry
+	// is a media type or a family such as image/*.
+	AllowedContentTypes []string `koanf:"allowed_content_types"`
+	// ProcessMaxBytes caps the images whose metadata is stripped and thumbnail rendered, larger
+	// ones are stored as uploaded
+	ProcessMaxBytes int64 `koanf:"process_max_bytes" validate:"min=0"`
+}
+
+func DefaultAttachmentsConfig() *AttachmentsConfig {
+	return &AttachmentsConfig{
+		OrphanGracePeriod: 24 * time.Hour,
+		AllowedContentTypes: []string{
+			"image/*", "audio/*", "video/*", "text/plain", "application/pdf", "application/ogg",
+			"application/zip", "application/gzip", "application/vnd.ms-office",
+		},
+		ProcessMaxBytes: 25 << 20,
+	}
+}
+
+// EncryptionConfig picks the master key sensitive fields are encrypted under. Data keys are
+// wrapped by the KMS key when one is set, by the local master key otherwise.
+type EncryptionConfig struct {
+	// KMSKeyID is the ID, ARN or alias of the KMS key, reached with the AWS credentials
+	KMSKeyID string `koanf:"kms_key_id"`
+	// KMSEndpointURL overrides the regional KMS endpoint, e.g. for LocalStack
+	KMSEndpointURL string `koanf:"kms_endpoint_url" validate:"omitempty,url"`
+	// MasterKey is a base64 encoded 32 byte key for development without KMS
+	MasterKey string `koanf:"master_key" validate:"omitempty,base64"`
+	// RotationDays is how old the active data key gets before the encryption-key-rotation job
+	// replaces it, 0 only rotates on demand
+	RotationDays int `koanf:"rotation_days" validate:"min=0"`
+}
+
+func DefaultEncryptionConfig() *EncryptionConfig {
+	return &EncryptionConfig{
+		RotationDays: 90,
+	}
+}
+
+const (
+	AnalyticsSinkS3      = "s3"
+	AnalyticsSinkKinesis = "kinesis"
+)
+
+// AnalyticsConfig opts a deployment into anonymized product analytics. Events only carry a
+// hash of the user and the properties their schema allows, and go to their own sink rather
+// than the operational logs.
+type AnalyticsConfig struct {
+	Enabled bool   `koanf:"enabled"`
+	Sink    string `koanf:"sink" validate:"omitempty,oneof=s3 kinesis"`
+	// S3Prefix is where the s3 sink writes batches in the AWS bucket
+	S3Prefix string `koanf:"s3_prefix"`
+	// KinesisStream names the stream of the kinesis sink
+	KinesisStream string `koanf:"kinesis_stream"`
+	// Salt keys the hash replacing user and workspace IDs, changing it unlinks earlier events
+	Salt          string        `koanf:"salt"`
+	BatchSize     int           `koanf:"batch_size" validate:"min=0,max=500"`
+	FlushInterval time.Duration `koanf:"flush_interval"`
+}
+
+func DefaultAnalyticsConfig() *AnalyticsConfig {
+	return &AnalyticsConfig{
+		Sink:          AnalyticsSinkS3,
+		S3Prefix:      "analytics/",
+		BatchSize:     100,
+		FlushInterval: 30 * time.Second,
+	}
+}
+
+// LLMConfig selects the provider behind subtask suggestions
+type LLMConfig struct {
+	// Provider proposes the subtasks, local needs no model and empty disables suggestions
+	Provider string `koanf:"provider" validate:"omitempty,oneof=openai local"`
+	APIKey   string `koanf:"api_key"`
+	// BaseURL points the OpenAI provider at any API compatible endpoint, self-hosted ones included
+	BaseURL string        `koanf:"base_url"`
+	Model   string        `koanf:"model"`
+	Timeout time.Duration `koanf:"timeout"`
+}
+
+func DefaultLLMConfig() *LLMConfig {
+	return &LLMConfig{
+		BaseURL: "https://api.openai.com/v1",
+		Model:   "gpt-4o-mini",
+		Timeout: 20 * time.Second,
+	}
+}
+
+const TranscriptionProviderAWS = "aws"
+
+// TranscriptionConfig selects the provider voice note attachments are transcribed with
+type TranscriptionConfig struct {
+	// Provider transcribes the audio, aws is AWS Transcribe and empty disables transcription
+	Provider string `koanf:"provider" validate:"omitempty,oneof=aws"`
+	// LanguageCode is the language spoken in voice notes, e.g. en-US. Empty lets the provider
+	// identify it.
+	LanguageCode string `koanf:"language_code"`
+	// EndpointURL overrides the regional AWS Transcribe endpoint, e.g. for LocalStack
+	EndpointURL string `koanf:"endpoint_url" validate:"omitempty,url"`
+	// PollInterval is how often a running transcription is checked on
+	PollInterval time.Duration `koanf:"poll_interval"`
+}
+
+func DefaultTranscriptionConfig() *TranscriptionConfig {
+	return &TranscriptionConfig{
+		PollInterval: 5 * time.Second,
+	}
+}
+
+// InboundEmailConfig lets users comment on a todo by replying to the notification emails about it
+type InboundEmailConfig struct {
+	// ReplyDomain receives the replies, e.g. reply.executask.app. Empty disables email replies.
+	ReplyDomain string `koanf:"reply_domain" validate:"omitempty,hostname"`
+	// Secret authenticates the inbound email provider, it is sent in the X-Inbound-Secret header
+	Secret string `koanf:"secret" validate:"required_with=ReplyDomain"`
+	// TokenTTL is how long the reply address of an email keeps working
+	TokenTTL time.Duration `koanf:"token_ttl"`
+}
+
+func DefaultInboundEmailConfig() *InboundEmailConfig {
+	return &InboundEmailConfig{
+		TokenTTL: 30 * 24 * time.Hour,
+	}
+}
+
+// LinkPreviewsConfig unfurls the links of todo descriptions and comments into preview cards
+type LinkPreviewsConfig struct {
+	Enabled bool `koanf:"enabled"`
+	// Timeout bounds fetching a page, redirects included
+	Timeout time.Duration `koanf:"timeout"`
+	// TTL is how long a preview is kept before the page is fetched again
+	TTL time.Duration `koanf:"ttl"`
+	// FailureTTL is how long a page that couldn't be previewed is left alone
+	FailureTTL time.Duration `koanf:"failure_ttl"`
+}
+
+func DefaultLinkPreviewsConfig() *LinkPreviewsConfig {
+	return &LinkPreviewsConfig{
+		Timeout:    5 * time.Second,
+		TTL:        7 * 24 * time.Hour,
+		FailureTTL: 6 * time.Hour,
+	}
+}
+
+// CoversConfig tunes todo cover images, the thumbnails of which are rendered by a background job
+type CoversConfig struct {
+	// StockPrefix is where the stock images covers can be picked from are kept in the bucket
+	StockPrefix string `koanf:"stock_prefix"`
+	// MaxSourceBytes caps the images thumbnails are rendered of
+	MaxSourceBytes int64 `koanf:"max_source_bytes" validate:"min=0"`
+}
+
+func DefaultCoversConfig() *CoversConfig {
+	return &CoversConfig{
+		StockPrefix:    "covers/stock/",
+		MaxSourceBytes: 20 << 20,
+	}
+}
+
+const (
+	ScanningProviderClamAV = "clamav"
+	ScanningProviderHTTP   = "http"
+)
+
+// ScanningConfig selects the virus scanner attachments go through before they can be downloaded
+type ScanningConfig struct {
+	// Provider scans the uploads, clamav is a clamd daemon, http an external scanning API and
+	// empty disables scanning
+	Provider string `koanf:"provider" validate:"omitempty,oneof=clamav http"`
+	// ClamAVAddress is the host:port clamd listens on for TCP connections
+	ClamAVAddress string `koanf:"clamav_address" validate:"required_if=Provider clamav"`
+	// APIURL receives the file as the body of a POST, see the scan package for the response
+	APIURL string `koanf:"api_url" validate:"required_if=Provider http,omitempty,url"`
+	APIKey string `koanf:"api_key"`
+	// Timeout bounds the scan of one file
+	Timeout time.Duration `koanf:"timeout"`
+}
+
+func DefaultScanningConfig() *ScanningConfig {
+	return &ScanningConfig{
+		Timeout: 2 * time.Minute,
+	}
+}
+
+type APIConfig struct {
+	// V1DeprecatedAt and V1SunsetAt are dates (YYYY-MM-DD) advertised on every v1 response
+	V1DeprecatedAt  string `koanf:"v1_deprecated_at"`
+	V1SunsetAt      string `koanf:"v1_sunset_at"`
+	DeprecationLink string `koanf:"deprecation_link"`
+}
+
+func DefaultAPIConfig() *APIConfig {
+	return &APIConfig{
+		V1DeprecatedAt: "2026-10-16",
+	}
+}
+
+// APIKeysConfig lets users call the API with keys of their own instead of sessions. Every key
+// is on a plan, which sets its rate limit and daily quota on top of the per IP rate limit.
+type APIKeysConfig struct {
+	Enabled bool `koanf:"enabled"`
+	// DefaultPlan is the plan of new keys, admins move keys to other plans
+	DefaultPlan string `koanf:"default_plan"`
+	// Plans are keyed by name, they can be changed with a config reload
+	Plans map[string]APIPlanConfig `koanf:"plans" validate:"dive"`
+	// MaxKeysPerUser caps the keys a user may hold at once
+	MaxKeysPerUser int `koanf:"max_keys_per_user" validate:"min=0"`
+}
+
+type APIPlanConfig struct {
+	RequestsPerSecond float64 `koanf:"requests_per_second" validate:"gt=0"`
+	// Burst is how many requests may arrive at once, 0 allows one second worth of them
+	Burst int `koanf:"burst" validate:"min=0"`
+	// RequestsPerDay is the quota of a key per UTC day, 0 is unlimited
+	RequestsPerDay int64 `koanf:"requests_per_day" validate:"min=0"`
+}
+
+const APIPlanFree = "free"
+
+func DefaultAPIKeysConfig() *APIKeysConfig {
+	return &APIKeysConfig{
+		DefaultPlan: APIPlanFree,
+		Plans: map[string]APIPlanConfig{
+			APIPlanFree: {RequestsPerSecond: 2, Burst: 10, RequestsPerDay: 1000},
+			"pro":       {RequestsPerSecond: 20, RequestsPerDay: 100000},
+		},
+		MaxKeysPerUser: 10,
+	}
+}
+
+const (
+	MailDriverResend = "resend"
+	MailDriverSES    = "ses"
+	MailDriverSMTP   = "smtp"
+	MailDriverLog    = "log"
+)
+
+// MailConfig selects how emails are sent. The log driver only logs them, for development.
+type MailConfig struct {
+	// Driver is resend, ses with the AWS credentials, smtp or log
+	Driver      string `koanf:"driver" validate:"omitempty,oneof=resend ses smtp log"`
+	FromName    string `koanf:"from_name"`
+	FromAddress string `koanf:"from_address" validate:"omitempty,email"`
+	// SESConfigurationSet tracks deliveries, bounces and complaints of the ses driver
+	SESConfigurationSet string     `koanf:"ses_configuration_set"`
+	SMTP                SMTPConfig `koanf:"smtp"`
+	// DKIM signs the emails of the smtp driver. Resend and SES sign with the keys of the
+	// domains verified with them.
+	DKIM DKIMConfig `koanf:"dkim"`
+}
+
+type SMTPConfig struct {
+	Host     string `koanf:"host"`
+	Port     int    `koanf:"port"`
+	Username string `koanf:"username"`
+	Password string `koanf:"password"`
+}
+
+type DKIMConfig struct {
+	Domain   string `koanf:"domain"`
+	Selector string `koanf:"selector"`
+	// PrivateKey is the PEM encoded RSA key whose public half the selector publishes
+	PrivateKey string `koanf:"private_key"`
+}
+
+func DefaultMailConfig() *MailConfig {
+	return &MailConfig{
+		Driver:      MailDriverResend,
+		FromName:    "Boilerplate",
+		FromAddress: "onboarding@resend.dev",
+		SMTP: SMTPConfig{
+			Port: 587,
+		},
+	}
+}
+
+const (
+	AlertsSinkSNS = "sns"
+	AlertsSinkLog = "log"
+)
+
+// AlertsConfig pages on-call about operational trouble. Every instance checks the alarms on
+// the interval, an alarm is published when it starts and when it stops firing.
+type AlertsConfig struct {
+	Enabled bool `koanf:"enabled"`
+	// Sink is sns, publishing to the topic, or log
+	Sink        string `koanf:"sink" validate:"omitempty,oneof=sns log"`
+	SNSTopicARN string `koanf:"sns_topic_arn"`
+	// CloudWatchNamespace receives the value of every check on every interval, empty records
+	// nothing
+	CloudWatchNamespace string        `koanf:"cloudwatch_namespace"`
+	Interval            time.Duration `koanf:"interval"`
+	// Cooldown is how long an alarm published by one instance isn't published by another
+	Cooldown time.Duration `koanf:"cooldown"`
+	// QueueBacklog is the number of jobs waiting in the queues the backlog alarm fires at
+	QueueBacklog int `koanf:"queue_backlog" validate:"min=0"`
+	// DBPoolUsage is the share of the database connections in use the alarm fires at
+	DBPoolUsage float64 `koanf:"db_pool_usage" validate:"min=0,max=1"`
+}
+
+func DefaultAlertsConfig() *AlertsConfig {
+	return &AlertsConfig{
+		Sink:         AlertsSinkLog,
+		Interval:     time.Minute,
+		Cooldown:     30 * time.Minute,
+		QueueBacklog: 1000,
+		DBPoolUsage:  0.9,
+	}
+}
+
+// BreakerConfig tunes the circuit breaker and bulkhead of an external dependency
+type BreakerConfig struct {
+	// FailureThreshold is how many consecutive failures open the breaker
+	FailureThreshold int `koanf:"failure_threshold" validate:"min=0"`
+	// OpenTimeout is how long an open breaker fails calls before letting one through again
+	OpenTimeout time.Duration `koanf:"open_timeout"`
+	// MaxConcurrent caps the calls in flight to the dependency, 0 is unlimited
+	MaxConcurrent int `koanf:"max_concurrent" validate:"min=0"`
+	// Timeout bounds every call to the dependency, 0 leaves it to the client
+	Timeout time.Duration `koanf:"timeout"`
+}
+
+// BreakersConfig keeps a slow or failing third party from stalling the requests calling it.
+// Dependencies are named after the service they call, such as s3, sns, llm, email,
+// link_previews or embedding, their unset fields are taken from the default.
+type BreakersConfig struct {
+	Default      BreakerConfig            `koanf:"default"`
+	Dependencies map[string]BreakerConfig `koanf:"dependencies"`
+}
+
+func DefaultBreakersConfig() *BreakersConfig {
+	return &BreakersConfig{
+		Default: BreakerConfig{
+			FailureThreshold: 5,
+			OpenTimeout:      30 * time.Second,
+		},
+		Dependencies: map[string]BreakerConfig{
+			"llm":           {MaxConcurrent: 20},
+			"link_previews": {MaxConcurrent: 20},
+		},
+	}
+}
+
+// Breaker is the config of a dependency, filled from the default
+func (c *BreakersConfig) Breaker(name string) BreakerConfig {
+	breaker := c.Dependencies[name]
+	if breaker.FailureThreshold == 0 {
+		breaker.FailureThreshold = c.Default.FailureThreshold
+	}
+	if breaker.OpenTimeout <= 0 {
+		breaker.OpenTimeout = c.Default.OpenTimeout
+	}
+	if breaker.MaxConcurrent == 0 {
+		breaker.MaxConcurrent = c.Default.MaxConcurrent
+	}
+	if breaker.Timeout <= 0 {
+		breaker.Timeout = c.Default.Timeout
+	}
+	return breaker
+}
+
+// OutboundConfig is the policy of the HTTP clients calling third parties. Their timeouts are
+// set per destination, e.g. LLM.Timeout.
+type OutboundConfig struct {
+	// MaxRetries is how many times an idempotent call is retried after a transient failure
+	MaxRetries int `koanf:"max_retries" validate:"min=0"`
+	// RetryBaseDelay doubles with every retry, jittered, up to RetryMaxDelay
+	RetryBaseDelay time.Duration `koanf:"retry_base_delay"`
+	// RetryMaxDelay also caps the Retry-After a destination may ask for, a longer one isn't
+	// waited for
+	RetryMaxDelay time.Duration `koanf:"retry_max_delay"`
+	// MaxConnsPerHost caps the connections to a destination, 0 is unlimited
+	MaxConnsPerHost     int           `koanf:"max_conns_per_host" validate:"min=0"`
+	MaxIdleConnsPerHost int           `koanf:"max_idle_conns_per_host" validate:"min=0"`
+	IdleConnTimeout     time.Duration `koanf:"idle_conn_timeout"`
+}
+
+func DefaultOutboundConfig() *OutboundConfig {
+	return &OutboundConfig{
+		MaxRetries:          2,
+		RetryBaseDelay:      200 * time.Millisecond,
+		RetryMaxDelay:       5 * time.Second,
+		MaxConnsPerHost:     50,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
+
+// TodosConfig holds the settings of todos themselves
+type TodosConfig struct {
+	// UndoWindow is how long a completion can be undone. What the completion sets off, such as
+	// rule notifications and follow-ups, waits for the window to pass.
+	UndoWindow time.Duration `koanf:"undo_window"`
+}
+
+func DefaultTodosConfig() *TodosConfig {
+	return &TodosConfig{
+		UndoWindow: 30 * time.Second,
+	}
+}
+
+// WebhooksConfig tunes the delivery of workspace events to the endpoints their admins register
+type WebhooksConfig struct {
+	// Timeout bounds a delivery, the endpoint answering included
+	Timeout time.Duration `koanf:"timeout"`
+	// MaxEndpointsPerWorkspace caps the endpoints of a workspace
+	MaxEndpointsPerWorkspace int `koanf:"max_endpoints_per_workspace"`
+	// AllowPrivateAddresses lets endpoints point at loopback and private addresses, for local
+	// development only
+	AllowPrivateAddresses bool `koanf:"allow_private_addresses"`
+}
+
+func DefaultWebhooksConfig() *WebhooksConfig {
+	return &WebhooksConfig{
+		Timeout:                  10 * time.Second,
+		MaxEndpointsPerWorkspace: 10,
 	}
 }
 
@@ -151,46 +1040,3 @@ func parseMapString(value string) (map[string]string, bool) {
 
 	return result, true
 }
-
-func LoadConfig() (*Config, error) {
-	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger()
-
-	k := koanf.New(".")
-
-	err := k.Load(env.Provider("EXECUTASK_", ".", func(s string) string {
-		return strings.ToLower(strings.TrimPrefix(s, "EXECUTASK_"))
-	}), nil)
-	if err != nil {
-		logger.Fatal().Err(err).Msg("could not load initial env variables")
-	}
-
-	mainConfig := &Config{}
-
-	err = k.Unmarshal("", mainConfig)
-	if err != nil {
-		logger.Fatal().Err(err).Msg("could not unmarshal main config")
-	}
-
-	validate := validator.New()
-
-	err = validate.Struct(mainConfig)
-	if err != nil {
-		logger.Fatal().Err(err).Msg("config validation failed")
-	}
-
-	// Set default observability config if not provided
-	if mainConfig.Observability == nil {
-		mainConfig.Observability = DefaultObservabilityConfig()
-	}
-
-	// Override service name and environment from primary config
-	mainConfig.Observability.ServiceName = "executask"
-	mainConfig.Observability.Environment = mainConfig.Primary.Env
-
-	// Validate observability config
-	if err := mainConfig.Observability.Validate(); err != nil {
-		logger.Fatal().Err(err).Msg("invalid observability config")
-	}
-
-	return mainConfig, nil
-}
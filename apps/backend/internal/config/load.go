@@ -0,0 +1,572 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/providers/env"
+	"github.com/knadh/koanf/v2"
+)
+
+const (
+	envPrefix = "EXECUTASK_"
+	// ConfigFileEnv points at an optional JSON config file, every other source overrides it
+	ConfigFileEnv = envPrefix + "CONFIG_FILE"
+	// SecretsDirEnv points at an optional directory of mounted secrets, one file per key. Secrets
+	// managers reach the server this way, through their Kubernetes or Docker secret mounts.
+	SecretsDirEnv = envPrefix + "SECRETS_DIR"
+)
+
+// LoadConfig reads the config from its sources, later ones win: the JSON file, the secrets
+// directory and the environment. The result is validated so a misconfigured server fails
+// at startup rather than on the first request that needs the value.
+func LoadConfig() (*Config, error) {
+	k := koanf.New(".")
+
+	if path := os.Getenv(ConfigFileEnv); path != "" {
+		if err := k.Load(fileProvider(path), nil); err != nil {
+			return nil, fmt.Errorf("could not load config file %s: %w", path, err)
+		}
+	}
+
+	if dir := os.Getenv(SecretsDirEnv); dir != "" {
+		if err := k.Load(secretsDirProvider(dir), nil); err != nil {
+			return nil, fmt.Errorf("could not load secrets from %s: %w", dir, err)
+		}
+	}
+
+	if err := k.Load(env.Provider(envPrefix, ".", envKey), nil); err != nil {
+		return nil, fmt.Errorf("could not load env variables: %w", err)
+	}
+
+	mainConfig := &Config{}
+	if err := k.Unmarshal("", mainConfig); err != nil {
+		return nil, fmt.Errorf("could not unmarshal config: %w", err)
+	}
+
+	if err := validator.New().Struct(mainConfig); err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
+
+	applyDefaults(mainConfig)
+
+	if err := mainConfig.Observability.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid observability config: %w", err)
+	}
+
+	if mainConfig.Server.FaultInjection.Enabled && mainConfig.Primary.Env == "production" {
+		return nil, errors.New("fault injection can't be enabled in production")
+	}
+
+	if analytics := mainConfig.Analytics; analytics.Enabled {
+		if analytics.Salt == "" {
+			return nil, errors.New("analytics needs a salt to hash user IDs with")
+		}
+		if analytics.Sink == AnalyticsSinkKinesis && analytics.KinesisStream == "" {
+			return nil, errors.New("the kinesis analytics sink needs a stream")
+		}
+	}
+
+	if storage := mainConfig.Storage; storage.Driver != StorageDriverFileSystem {
+		if aws := mainConfig.AWS; aws.Region == "" || aws.AccessKeyID == "" || aws.SecretAccessKey == "" {
+			return nil, fmt.Errorf("the %s storage driver needs the AWS region and credentials", storage.Driver)
+		}
+		if storage.Driver == StorageDriverMinIO && mainConfig.AWS.EndpointURL == "" {
+			return nil, errors.New("the minio storage driver needs the endpoint URL of the server")
+		}
+	}
+
+	if cookie := mainConfig.Auth.Cookie; cookie.Enabled {
+		if slices.Contains(mainConfig.Server.CORSAllowedOrigins, "*") {
+			return nil, errors.New("cookie sessions need the allowed CORS origins listed, not *")
+		}
+		if cookie.SameSite == "none" && cookie.Insecure {
+			return nil, errors.New("cookie sessions with SameSite=None need secure cookies")
+		}
+	}
+
+	if _, ok := mainConfig.APIKeys.Plans[mainConfig.APIKeys.DefaultPlan]; !ok {
+		return nil, fmt.Errorf("the default API plan %q is not one of the plans", mainConfig.APIKeys.DefaultPlan)
+	}
+
+	switch mail := mainConfig.Mail; mail.Driver {
+	case MailDriverResend:
+		if mainConfig.Integration.ResendAPIKey == "" {
+			return nil, errors.New("the resend mail driver needs the Resend API key")
+		}
+	case MailDriverSES:
+		if aws := mainConfig.AWS; aws.Region == "" || aws.AccessKeyID == "" || aws.SecretAccessKey == "" {
+			return nil, errors.New("the ses mail driver needs the AWS region and credentials")
+		}
+	case MailDriverSMTP:
+		if mail.SMTP.Host == "" {
+			return nil, errors.New("the smtp mail driver needs the host of the server")
+		}
+		if dkim := mail.DKIM; (dkim.Domain != "" || dkim.Selector != "" || dkim.PrivateKey != "") &&
+			(dkim.Domain == "" || dkim.Selector == "" || dkim.PrivateKey == "") {
+			return nil, errors.New("DKIM signing needs the domain, the selector and the private key")
+		}
+	}
+
+	if alerts := mainConfig.Alerts; alerts.Enabled {
+		if alerts.Sink == AlertsSinkSNS && alerts.SNSTopicARN == "" {
+			return nil, errors.New("the sns alerts sink needs a topic ARN")
+		}
+		if aws := mainConfig.AWS; (alerts.Sink == AlertsSinkSNS || alerts.CloudWatchNamespace != "") &&
+			(aws.Region == "" || aws.AccessKeyID == "" || aws.SecretAccessKey == "") {
+			return nil, errors.New("publishing alerts to SNS or CloudWatch needs the AWS region and credentials")
+		}
+	}
+
+	// Kept to tell which values a reload changes
+	mainConfig.sources = k.All()
+
+	return mainConfig, nil
+}
+
+// applyDefaults fills every optional section the sources left out
+func applyDefaults(mainConfig *Config) {
+	// Set default sync config if not provided
+	if mainConfig.Sync == nil {
+		mainConfig.Sync = DefaultSyncConfig()
+	}
+
+	// Set default changes config if not provided
+	if mainConfig.Changes == nil {
+		mainConfig.Changes = DefaultChangesConfig()
+	}
+
+	// Set default API config if not provided
+	if mainConfig.API == nil {
+		mainConfig.API = DefaultAPIConfig()
+	}
+
+	// Set default replica config if not provided
+	if mainConfig.Database.Replicas == nil {
+		mainConfig.Database.Replicas = DefaultReplicaConfig()
+	}
+
+	// Set default migrations config if not provided
+	if mainConfig.Database.Migrations == nil {
+		mainConfig.Database.Migrations = DefaultMigrationsConfig(mainConfig.Primary.Env)
+	}
+	if mainConfig.Database.Migrations.Mode == "" {
+		mainConfig.Database.Migrations.Mode = DefaultMigrationsConfig(mainConfig.Primary.Env).Mode
+	}
+
+	// Set default timeout config if not provided
+	if mainConfig.Server.Timeouts == nil {
+		mainConfig.Server.Timeouts = DefaultTimeoutConfig()
+	}
+
+	// Set default compression config if not provided
+	if mainConfig.Server.Compression == nil {
+		mainConfig.Server.Compression = DefaultCompressionConfig()
+	}
+
+	if mainConfig.Auth.Cookie == nil {
+		mainConfig.Auth.Cookie = DefaultSessionCookieConfig()
+	}
+	if mainConfig.Auth.Cookie.Name == "" {
+		mainConfig.Auth.Cookie.Name = DefaultSessionCookieConfig().Name
+	}
+	if mainConfig.Auth.Cookie.CSRFName == "" {
+		mainConfig.Auth.Cookie.CSRFName = DefaultSessionCookieConfig().CSRFName
+	}
+	if mainConfig.Auth.Cookie.SameSite == "" {
+		mainConfig.Auth.Cookie.SameSite = DefaultSessionCookieConfig().SameSite
+	}
+
+	if mainConfig.Server.BodyLimits == nil {
+		mainConfig.Server.BodyLimits = DefaultBodyLimitConfig()
+	}
+
+	if mainConfig.Server.SecurityHeaders == nil {
+		mainConfig.Server.SecurityHeaders = DefaultSecurityHeadersConfig()
+	}
+	if mainConfig.Server.SecurityHeaders.ContentSecurityPolicy == "" {
+		mainConfig.Server.SecurityHeaders.ContentSecurityPolicy = DefaultSecurityHeadersConfig().ContentSecurityPolicy
+	}
+	if mainConfig.Server.SecurityHeaders.ReferrerPolicy == "" {
+		mainConfig.Server.SecurityHeaders.ReferrerPolicy = DefaultSecurityHeadersConfig().ReferrerPolicy
+	}
+
+	// Set default payload budget config if not provided
+	if mainConfig.Server.PayloadBudget == nil {
+		mainConfig.Server.PayloadBudget = DefaultPayloadBudgetConfig()
+	}
+
+	// Set default rate limit config if not provided
+	if mainConfig.Server.RateLimit == nil {
+		mainConfig.Server.RateLimit = DefaultRateLimitConfig()
+	}
+
+	// Set default shutdown config if not provided
+	if mainConfig.Server.Shutdown == nil {
+		mainConfig.Server.Shutdown = DefaultShutdownConfig()
+	}
+
+	// Set default mode config if not provided
+	if mainConfig.Server.Mode == nil {
+		mainConfig.Server.Mode = DefaultModeConfig()
+	}
+	defaultMode := DefaultModeConfig()
+	if mainConfig.Server.Mode.Default == "" {
+		mainConfig.Server.Mode.Default = defaultMode.Default
+	}
+	if mainConfig.Server.Mode.RetryAfter <= 0 {
+		mainConfig.Server.Mode.RetryAfter = defaultMode.RetryAfter
+	}
+	if mainConfig.Server.Mode.RefreshInterval <= 0 {
+		mainConfig.Server.Mode.RefreshInterval = defaultMode.RefreshInterval
+	}
+
+	// Set default fault injection config if not provided
+	if mainConfig.Server.FaultInjection == nil {
+		mainConfig.Server.FaultInjection = DefaultFaultInjectionConfig()
+	}
+	if mainConfig.Server.FaultInjection.MaxTTL <= 0 {
+		mainConfig.Server.FaultInjection.MaxTTL = DefaultFaultInjectionConfig().MaxTTL
+	}
+
+	// Set default RBAC config if not provided
+	if mainConfig.RBAC == nil {
+		mainConfig.RBAC = DefaultRBACConfig()
+	}
+
+	// Set default search config if not provided
+	if mainConfig.Search == nil {
+		mainConfig.Search = DefaultSearchConfig()
+	}
+
+	// Set default embedding config if not provided
+	if mainConfig.Embedding == nil {
+		mainConfig.Embedding = DefaultEmbeddingConfig()
+	}
+
+	// Set default feature flags, flags missing from the config keep their default
+	if mainConfig.Features == nil {
+		mainConfig.Features = &FeaturesConfig{}
+	}
+	if mainConfig.Features.Flags == nil {
+		mainConfig.Features.Flags = map[string]FeatureFlagConfig{}
+	}
+	for name, flag := range DefaultFeaturesConfig().Flags {
+		if _, ok := mainConfig.Features.Flags[name]; !ok {
+			mainConfig.Features.Flags[name] = flag
+		}
+	}
+
+	// Set default usage config if not provided
+	if mainConfig.Usage == nil {
+		mainConfig.Usage = DefaultUsageConfig()
+	}
+	if mainConfig.Usage.FlushInterval <= 0 {
+		mainConfig.Usage.FlushInterval = DefaultUsageConfig().FlushInterval
+	}
+	if mainConfig.Usage.RollupLookbackHours <= 0 {
+		mainConfig.Usage.RollupLookbackHours = DefaultUsageConfig().RollupLookbackHours
+	}
+
+	if mainConfig.Export == nil {
+		mainConfig.Export = DefaultExportConfig()
+	}
+	if mainConfig.Export.LinkTTL <= 0 {
+		mainConfig.Export.LinkTTL = DefaultExportConfig().LinkTTL
+	}
+	if mainConfig.Export.Retention <= 0 {
+		mainConfig.Export.Retention = DefaultExportConfig().Retention
+	}
+	if mainConfig.Export.PDFInlineLimit <= 0 {
+		mainConfig.Export.PDFInlineLimit = DefaultExportConfig().PDFInlineLimit
+	}
+
+	if mainConfig.Retention == nil {
+		mainConfig.Retention = DefaultRetentionConfig()
+	}
+
+	if mainConfig.Attachments == nil {
+		mainConfig.Attachments = DefaultAttachmentsConfig()
+	}
+	if mainConfig.Attachments.OrphanGracePeriod <= 0 {
+		mainConfig.Attachments.OrphanGracePeriod = DefaultAttachmentsConfig().OrphanGracePeriod
+	}
+	if len(mainConfig.Attachments.AllowedContentTypes) == 0 {
+		mainConfig.Attachments.AllowedContentTypes = DefaultAttachmentsConfig().AllowedContentTypes
+	}
+	if mainConfig.Attachments.ProcessMaxBytes <= 0 {
+		mainConfig.Attachments.ProcessMaxBytes = DefaultAttachmentsConfig().ProcessMaxBytes
+	}
+
+	if mainConfig.Encryption == nil {
+		mainConfig.Encryption = DefaultEncryptionConfig()
+	}
+
+	if mainConfig.Analytics == nil {
+		mainConfig.Analytics = DefaultAnalyticsConfig()
+	}
+	if mainConfig.Analytics.Sink == "" {
+		mainConfig.Analytics.Sink = DefaultAnalyticsConfig().Sink
+	}
+	if mainConfig.Analytics.S3Prefix == "" {
+		mainConfig.Analytics.S3Prefix = DefaultAnalyticsConfig().S3Prefix
+	}
+	if mainConfig.Analytics.BatchSize <= 0 {
+		mainConfig.Analytics.BatchSize = DefaultAnalyticsConfig().BatchSize
+	}
+	if mainConfig.Analytics.FlushInterval <= 0 {
+		mainConfig.Analytics.FlushInterval = DefaultAnalyticsConfig().FlushInterval
+	}
+
+	if mainConfig.LLM == nil {
+		mainConfig.LLM = DefaultLLMConfig()
+	}
+
+	if mainConfig.Transcription == nil {
+		mainConfig.Transcription = DefaultTranscriptionConfig()
+	}
+	if mainConfig.Transcription.PollInterval <= 0 {
+		mainConfig.Transcription.PollInterval = DefaultTranscriptionConfig().PollInterval
+	}
+
+	if mainConfig.InboundEmail == nil {
+		mainConfig.InboundEmail = DefaultInboundEmailConfig()
+	}
+	if mainConfig.InboundEmail.TokenTTL <= 0 {
+		mainConfig.InboundEmail.TokenTTL = DefaultInboundEmailConfig().TokenTTL
+	}
+
+	if mainConfig.LinkPreviews == nil {
+		mainConfig.LinkPreviews = DefaultLinkPreviewsConfig()
+	}
+	if mainConfig.LinkPreviews.Timeout <= 0 {
+		mainConfig.LinkPreviews.Timeout = DefaultLinkPreviewsConfig().Timeout
+	}
+	if mainConfig.LinkPreviews.TTL <= 0 {
+		mainConfig.LinkPreviews.TTL = DefaultLinkPreviewsConfig().TTL
+	}
+	if mainConfig.LinkPreviews.FailureTTL <= 0 {
+		mainConfig.LinkPreviews.FailureTTL = DefaultLinkPreviewsConfig().FailureTTL
+	}
+
+	if mainConfig.Covers == nil {
+		mainConfig.Covers = DefaultCoversConfig()
+	}
+	if mainConfig.Covers.StockPrefix == "" {
+		mainConfig.Covers.StockPrefix = DefaultCoversConfig().StockPrefix
+	}
+	if mainConfig.Covers.MaxSourceBytes <= 0 {
+		mainConfig.Covers.MaxSourceBytes = DefaultCoversConfig().MaxSourceBytes
+	}
+
+	if mainConfig.Scanning == nil {
+		mainConfig.Scanning = DefaultScanningConfig()
+	}
+	if mainConfig.Scanning.Timeout <= 0 {
+		mainConfig.Scanning.Timeout = DefaultScanningConfig().Timeout
+	}
+
+	if mainConfig.Storage == nil {
+		mainConfig.Storage = DefaultStorageConfig()
+	}
+	if mainConfig.Storage.Driver == "" {
+		mainConfig.Storage.Driver = DefaultStorageConfig().Driver
+	}
+
+	if mainConfig.APIKeys == nil {
+		mainConfig.APIKeys = DefaultAPIKeysConfig()
+	}
+	if mainConfig.APIKeys.DefaultPlan == "" {
+		mainConfig.APIKeys.DefaultPlan = DefaultAPIKeysConfig().DefaultPlan
+	}
+	if len(mainConfig.APIKeys.Plans) == 0 {
+		mainConfig.APIKeys.Plans = DefaultAPIKeysConfig().Plans
+	}
+	if mainConfig.APIKeys.MaxKeysPerUser <= 0 {
+		mainConfig.APIKeys.MaxKeysPerUser = DefaultAPIKeysConfig().MaxKeysPerUser
+	}
+
+	if mainConfig.Mail == nil {
+		mainConfig.Mail = DefaultMailConfig()
+	}
+	if mainConfig.Mail.Driver == "" {
+		mainConfig.Mail.Driver = DefaultMailConfig().Driver
+	}
+	if mainConfig.Mail.FromName == "" {
+		mainConfig.Mail.FromName = DefaultMailConfig().FromName
+	}
+	if mainConfig.Mail.FromAddress == "" {
+		mainConfig.Mail.FromAddress = DefaultMailConfig().FromAddress
+	}
+	if mainConfig.Mail.SMTP.Port <= 0 {
+		mainConfig.Mail.SMTP.Port = DefaultMailConfig().SMTP.Port
+	}
+
+	if mainConfig.Alerts == nil {
+		mainConfig.Alerts = DefaultAlertsConfig()
+	}
+	if mainConfig.Alerts.Sink == "" {
+		mainConfig.Alerts.Sink = DefaultAlertsConfig().Sink
+	}
+	if mainConfig.Alerts.Interval <= 0 {
+		mainConfig.Alerts.Interval = DefaultAlertsConfig().Interval
+	}
+	if mainConfig.Alerts.Cooldown <= 0 {
+		mainConfig.Alerts.Cooldown = DefaultAlertsConfig().Cooldown
+	}
+	if mainConfig.Alerts.QueueBacklog <= 0 {
+		mainConfig.Alerts.QueueBacklog = DefaultAlertsConfig().QueueBacklog
+	}
+	if mainConfig.Alerts.DBPoolUsage <= 0 {
+		mainConfig.Alerts.DBPoolUsage = DefaultAlertsConfig().DBPoolUsage
+	}
+
+	if mainConfig.Breakers == nil {
+		mainConfig.Breakers = DefaultBreakersConfig()
+	}
+	if mainConfig.Breakers.Default.FailureThreshold <= 0 {
+		mainConfig.Breakers.Default.FailureThreshold = DefaultBreakersConfig().Default.FailureThreshold
+	}
+	if mainConfig.Breakers.Default.OpenTimeout <= 0 {
+		mainConfig.Breakers.Default.OpenTimeout = DefaultBreakersConfig().Default.OpenTimeout
+	}
+	if mainConfig.Breakers.Dependencies == nil {
+		mainConfig.Breakers.Dependencies = DefaultBreakersConfig().Dependencies
+	}
+
+	if mainConfig.Outbound == nil {
+		mainConfig.Outbound = DefaultOutboundConfig()
+	}
+	if mainConfig.Outbound.RetryBaseDelay <= 0 {
+		mainConfig.Outbound.RetryBaseDelay = DefaultOutboundConfig().RetryBaseDelay
+	}
+	if mainConfig.Outbound.RetryMaxDelay <= 0 {
+		mainConfig.Outbound.RetryMaxDelay = DefaultOutboundConfig().RetryMaxDelay
+	}
+	if mainConfig.Outbound.MaxIdleConnsPerHost <= 0 {
+		mainConfig.Outbound.MaxIdleConnsPerHost = DefaultOutboundConfig().MaxIdleConnsPerHost
+	}
+	if mainConfig.Outbound.IdleConnTimeout <= 0 {
+		mainConfig.Outbound.IdleConnTimeout = DefaultOutboundConfig().IdleConnTimeout
+	}
+
+	if mainConfig.Todos == nil {
+		mainConfig.Todos = DefaultTodosConfig()
+	}
+	if mainConfig.Todos.UndoWindow < 0 {
+		mainConfig.Todos.UndoWindow = 0
+	}
+
+	if mainConfig.Webhooks == nil {
+		mainConfig.Webhooks = DefaultWebhooksConfig()
+	}
+	if mainConfig.Webhooks.Timeout <= 0 {
+		mainConfig.Webhooks.Timeout = DefaultWebhooksConfig().Timeout
+	}
+	if mainConfig.Webhooks.MaxEndpointsPerWorkspace <= 0 {
+		mainConfig.Webhooks.MaxEndpointsPerWorkspace = DefaultWebhooksConfig().MaxEndpointsPerWorkspace
+	}
+
+	// Set default observability config if not provided
+	if mainConfig.Observability == nil {
+		mainConfig.Observability = DefaultObservabilityConfig()
+	}
+
+	// Override service name and environment from primary config
+	mainConfig.Observability.ServiceName = "executask"
+	mainConfig.Observability.Environment = mainConfig.Primary.Env
+
+	// Set default observability config if not provided
+	if mainConfig.Observability == nil {
+		mainConfig.Observability = DefaultObservabilityConfig()
+	}
+
+	// Override service name and environment from primary config
+	mainConfig.Observability.ServiceName = "executask"
+	mainConfig.Observability.Environment = mainConfig.Primary.Env
+}
+
+// envKey maps EXECUTASK_SERVER.PORT to server.port
+func envKey(name string) string {
+	return strings.ToLower(strings.TrimPrefix(name, envPrefix))
+}
+
+// fileProvider reads a JSON config file shaped like the koanf keys, e.g. {"server": {"port": "8080"}}
+type fileProvider string
+
+func (p fileProvider) ReadBytes() ([]byte, error) {
+	return os.ReadFile(string(p))
+}
+
+func (p fileProvider) Read() (map[string]interface{}, error) {
+	data, err := p.ReadBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	return lowerKeys(values), nil
+}
+
+// secretsDirProvider reads one value per file, named like the environment variable or the key:
+// EXECUTASK_DATABASE.PASSWORD and database.password both set the database password
+type secretsDirProvider string
+
+func (p secretsDirProvider) ReadBytes() ([]byte, error) {
+	return nil, fmt.Errorf("secrets directory provider does not support raw bytes")
+}
+
+func (p secretsDirProvider) Read() (map[string]interface{}, error) {
+	entries, err := os.ReadDir(string(p))
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{}, len(entries))
+	for _, entry := range entries {
+		// Kubernetes mounts secrets through hidden ..data symlinks next to the visible files
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(string(p), entry.Name())
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		values[envKey(entry.Name())] = strings.TrimRight(string(data), "\r\n")
+	}
+
+	return maps.Unflatten(values, "."), nil
+}
+
+func lowerKeys(values map[string]interface{}) map[string]interface{} {
+	lowered := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		if nested, ok := value.(map[string]interface{}); ok {
+			value = lowerKeys(nested)
+		}
+		lowered[strings.ToLower(key)] = value
+	}
+	return lowered
+}
@@ -11,6 +11,7 @@ type ObservabilityConfig struct {
 	Logging      LoggingConfig      `koanf:"logging" validate:"required"`
 	NewRelic     NewRelicConfig     `koanf:"new_relic" validate:"required"`
 	HealthChecks HealthChecksConfig `koanf:"health_checks" validate:"required"`
+	Tracing      TracingConfig      `koanf:"tracing"`
 }
 
 type LoggingConfig struct {
@@ -26,6 +27,18 @@ type NewRelicConfig struct {
 	DebugLogging              bool   `koanf:"debug_logging"`
 }
 
+// TracingConfig exports OpenTelemetry spans over OTLP/HTTP. The standard OTEL_EXPORTER_OTLP_*
+// variables, e.g. OTEL_EXPORTER_OTLP_HEADERS, are honoured as well.
+type TracingConfig struct {
+	// Enabled exports spans, without it trace IDs of incoming requests still reach the logs
+	Enabled bool `koanf:"enabled"`
+	// Endpoint is the collector's base URL, e.g. http://localhost:4318
+	Endpoint string `koanf:"endpoint"`
+	// SampleRatio is the share of new traces that are recorded, requests continuing a trace
+	// follow the caller's decision
+	SampleRatio float64 `koanf:"sample_ratio"`
+}
+
 type HealthChecksConfig struct {
 	Enabled  bool          `koanf:"enabled"`
 	Interval time.Duration `koanf:"interval" validate:"min=1s"`
@@ -54,6 +67,11 @@ func DefaultObservabilityConfig() *ObservabilityConfig {
 			Timeout:  5 * time.Second,
 			Checks:   []string{"database", "redis"},
 		},
+		Tracing: TracingConfig{
+			Enabled:     false,
+			Endpoint:    "http://localhost:4318",
+			SampleRatio: 1,
+		},
 	}
 }
 
@@ -75,6 +93,11 @@ func (c *ObservabilityConfig) Validate() error {
 		return fmt.Errorf("logging slow_query_threshold must be non-negative")
 	}
 
+	// Validate tracing sample ratio
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("tracing sample_ratio must be between 0 and 1")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// reloadableKeys are the config keys a reload applies, prefixes end with a dot. Every other
+// change waits for a restart.
+var reloadableKeys = []string{
+	"server.rate_limit.",
+	"observability.logging.level",
+	"features.",
+	"api_keys.plans.",
+}
+
+// ReloadResult lists the keys a reload changed, never their values as some are secrets
+type ReloadResult struct {
+	Applied         []string
+	RestartRequired []string
+}
+
+// Live is the config of a running server. Reload swaps in the values that are safe to change
+// at runtime, readers get a consistent snapshot from Get.
+type Live struct {
+	current  atomic.Pointer[Config]
+	mu       sync.Mutex
+	onReload []func(cfg *Config)
+}
+
+func NewLive(cfg *Config) *Live {
+	live := &Live{}
+	live.current.Store(cfg)
+	return live
+}
+
+// Get returns the current config, it must not be modified
+func (l *Live) Get() *Config {
+	return l.current.Load()
+}
+
+// OnReload registers fn to run with the new config after every reload that applied a change
+func (l *Live) OnReload(fn func(cfg *Config)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.onReload = append(l.onReload, fn)
+}
+
+// Reload reads every source again. An invalid config is rejected as a whole and the current
+// one stays in effect.
+func (l *Live) Reload() (*ReloadResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fresh, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	current := l.current.Load()
+	result := &ReloadResult{
+		Applied:         []string{},
+		RestartRequired: []string{},
+	}
+
+	// Changes waiting for a restart keep being reported, the sources of next only take the applied ones
+	sources := make(map[string]interface{}, len(current.sources))
+	for key, value := range current.sources {
+		sources[key] = value
+	}
+
+	for _, key := range changedKeys(current.sources, fresh.sources) {
+		if !isReloadable(key) {
+			result.RestartRequired = append(result.RestartRequired, key)
+			continue
+		}
+
+		result.Applied = append(result.Applied, key)
+		if value, ok := fresh.sources[key]; ok {
+			sources[key] = value
+		} else {
+			delete(sources, key)
+		}
+	}
+
+	if len(result.Applied) == 0 {
+		return result, nil
+	}
+
+	next := *current
+	next.sources = sources
+	next.Server.RateLimit = fresh.Server.RateLimit
+	next.Features = fresh.Features
+
+	apiKeys := *current.APIKeys
+	apiKeys.Plans = fresh.APIKeys.Plans
+	next.APIKeys = &apiKeys
+
+	observability := *current.Observability
+	observability.Logging.Level = fresh.Observability.Logging.Level
+	next.Observability = &observability
+
+	l.current.Store(&next)
+
+	for _, fn := range l.onReload {
+		fn(&next)
+	}
+
+	return result, nil
+}
+
+func changedKeys(before, after map[string]interface{}) []string {
+	keys := []string{}
+	for key, value := range after {
+		if !reflect.DeepEqual(before[key], value) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+func isReloadable(key string) bool {
+	for _, reloadable := range reloadableKeys {
+		if key == reloadable || (strings.HasSuffix(reloadable, ".") && strings.HasPrefix(key, reloadable)) {
+			return true
+		}
+	}
+	return false
+}
@@ -6,9 +6,12 @@ import (
 
 	"github.com/Sameer16536/ExecuTask/internal/config"
 	"github.com/Sameer16536/ExecuTask/internal/database"
+	"github.com/Sameer16536/ExecuTask/internal/lib/correlation"
+	"github.com/Sameer16536/ExecuTask/internal/lib/tracing"
 	"github.com/Sameer16536/ExecuTask/internal/logger"
 	"github.com/Sameer16536/ExecuTask/internal/repository"
 	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 	"github.com/redis/go-redis/v9"
 )
@@ -27,6 +30,11 @@ func NewJobContext() (*JobContext, error) {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
+	tracerProvider, err := server.NewTracerProvider(cfg.Observability)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize tracer provider: %w", err)
+	}
+
 	loggerService := logger.NewLoggerService(cfg.Observability)
 	loggerInstance := logger.NewLoggerWithService(cfg.Observability, loggerService)
 
@@ -42,11 +50,12 @@ func NewJobContext() (*JobContext, error) {
 	})
 
 	srv := &server.Server{
-		Config:        cfg,
-		Logger:        &loggerInstance,
-		LoggerService: loggerService,
-		DB:            db,
-		Redis:         redisClient,
+		Config:         cfg,
+		Logger:         &loggerInstance,
+		LoggerService:  loggerService,
+		TracerProvider: tracerProvider,
+		DB:             db,
+		Redis:          redisClient,
 	}
 
 	jobClient, err := initJobClient(cfg)
@@ -54,7 +63,10 @@ func NewJobContext() (*JobContext, error) {
 		return nil, fmt.Errorf("failed to initialize job client: %w", err)
 	}
 
-	repositories := repository.NewRepositories(srv)
+	repositories, err := repository.NewRepositories(srv)
+	if err != nil {
+		return nil, err
+	}
 
 	return &JobContext{
 		Config:        cfg,
@@ -75,6 +87,9 @@ func (c *JobContext) Close() {
 	if c.JobClient != nil {
 		c.JobClient.Close()
 	}
+	if c.Server != nil && c.Server.TracerProvider != nil {
+		_ = c.Server.TracerProvider.Shutdown(context.Background())
+	}
 	if c.LoggerService != nil {
 		c.LoggerService.Shutdown()
 	}
@@ -117,22 +132,23 @@ func NewJobRunner(job Job) (*JobRunner, error) {
 func (r *JobRunner) Run() error {
 	defer r.ctx.Close()
 
-	r.ctx.Server.Logger.Info().
+	// Every task the run enqueues carries its ID, tying the deliveries back to the run
+	runID := uuid.New().String()
+	logger := r.ctx.Server.Logger.With().
 		Str("job", r.job.Name()).
-		Msg("Starting cron job")
+		Str("correlation_id", runID).
+		Logger()
 
-	ctx := context.Background()
+	logger.Info().Msg("Starting cron job")
+
+	ctx, span := tracing.Tracer().Start(correlation.WithID(context.Background(), runID), r.job.Name())
 	err := r.job.Run(ctx, r.ctx)
+	tracing.End(span, err)
 	if err != nil {
-		r.ctx.Server.Logger.Error().
-			Err(err).
-			Str("job", r.job.Name()).
-			Msg("Failed to run cron job")
+		logger.Error().Err(err).Msg("Failed to run cron job")
 		return err
 	}
 
-	r.ctx.Server.Logger.Info().
-		Str("job", r.job.Name()).
-		Msg("Cron job completed successfully")
+	logger.Info().Msg("Cron job completed successfully")
 	return nil
 }
@@ -2,10 +2,21 @@ package cron
 
 import (
 	"context"
+	"strings"
 	"time"
 
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/lib/aws"
+	"github.com/Sameer16536/ExecuTask/internal/lib/blob"
+	"github.com/Sameer16536/ExecuTask/internal/lib/embedding"
 	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/lib/metering"
+	"github.com/Sameer16536/ExecuTask/internal/model/cover"
+	"github.com/Sameer16536/ExecuTask/internal/model/retention"
+	"github.com/Sameer16536/ExecuTask/internal/model/rule"
 	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/model/usage"
+	"github.com/Sameer16536/ExecuTask/internal/model/workspace"
 	"github.com/google/uuid"
 )
 
@@ -50,7 +61,7 @@ func (j *DueDateRemindersJob) Run(ctx context.Context, jobCtx *JobContext) error
 			TaskType:  "due_date_reminder",
 		}
 
-		err := job.EnqueueReminderEmail(jobCtx.JobClient, reminderTask)
+		err := job.EnqueueReminderEmail(ctx, jobCtx.JobClient, reminderTask)
 		if err != nil {
 			jobCtx.Server.Logger.Error().
 				Err(err).
@@ -120,7 +131,7 @@ func (j *OverdueNotificationsJob) Run(ctx context.Context, jobCtx *JobContext) e
 			TaskType:  "overdue_notification",
 		}
 
-		err := job.EnqueueReminderEmail(jobCtx.JobClient, overdueTask)
+		err := job.EnqueueReminderEmail(ctx, jobCtx.JobClient, overdueTask)
 		if err != nil {
 			jobCtx.Server.Logger.Error().
 				Err(err).
@@ -208,7 +219,7 @@ func (j *WeeklyReportsJob) Run(ctx context.Context, jobCtx *JobContext) error {
 			OverdueTodos:   overdueTodos,
 		}
 
-		err = job.EnqueueWeeklyReportEmail(jobCtx.JobClient, weeklyReportTask)
+		err = job.EnqueueWeeklyReportEmail(ctx, jobCtx.JobClient, weeklyReportTask)
 		if err != nil {
 			jobCtx.Server.Logger.Error().
 				Err(err).
@@ -275,7 +286,12 @@ func (j *AutoArchiveJob) Run(ctx context.Context, jobCtx *JobContext) error {
 		userTodos[todo.UserID]++
 	}
 
-	err = jobCtx.Repositories.Todo.ArchiveTodos(ctx, todoIDs)
+	userIDs := make([]string, 0, len(userTodos))
+	for userID := range userTodos {
+		userIDs = append(userIDs, userID)
+	}
+
+	err = jobCtx.Repositories.Todo.ArchiveTodos(ctx, todoIDs, userIDs)
 	if err != nil {
 		return err
 	}
@@ -293,3 +309,975 @@ func (j *AutoArchiveJob) Run(ctx context.Context, jobCtx *JobContext) error {
 
 	return nil
 }
+
+type ChangeRetentionJob struct{}
+
+func (j *ChangeRetentionJob) Name() string {
+	return "change-retention"
+}
+
+func (j *ChangeRetentionJob) Description() string {
+	return "Purge change feed events past the retention window"
+}
+
+func (j *ChangeRetentionJob) Run(ctx context.Context, jobCtx *JobContext) error {
+	retentionDays := jobCtx.Config.Changes.RetentionDays
+	if retentionDays <= 0 {
+		retentionDays = config.DefaultChangesConfig().RetentionDays
+	}
+
+	cutoffDate := time.Now().AddDate(0, 0, -retentionDays)
+
+	deletedCount, err := jobCtx.Repositories.Change.DeleteChangesOlderThan(ctx, cutoffDate)
+	if err != nil {
+		return err
+	}
+
+	jobCtx.Server.Logger.Info().
+		Time("cutoff_date", cutoffDate).
+		Int64("deleted_count", deletedCount).
+		Msg("Purged expired change events")
+
+	return nil
+}
+
+type RefreshStatsJob struct{}
+
+func (j *RefreshStatsJob) Name() string {
+	return "refresh-stats"
+}
+
+func (j *RefreshStatsJob) Description() string {
+	return "Refresh the materialized dashboard aggregates"
+}
+
+func (j *RefreshStatsJob) Run(ctx context.Context, jobCtx *JobContext) error {
+	start := time.Now()
+
+	if err := jobCtx.Repositories.Stats.RefreshStats(ctx); err != nil {
+		return err
+	}
+
+	jobCtx.Server.Logger.Info().
+		Dur("duration", time.Since(start)).
+		Msg("Refreshed dashboard aggregates")
+
+	return nil
+}
+
+type PartitionMaintenanceJob struct{}
+
+func (j *PartitionMaintenanceJob) Name() string {
+	return "partition-maintenance"
+}
+
+func (j *PartitionMaintenanceJob) Description() string {
+	return "Vacuum bloated todo partitions and analyze the partitioned table"
+}
+
+func (j *PartitionMaintenanceJob) Run(ctx context.Context, jobCtx *JobContext) error {
+	const table = "todos"
+
+	deadTupleRatio := jobCtx.Config.Cron.VacuumDeadTupleRatio
+	if deadTupleRatio <= 0 {
+		deadTupleRatio = config.DefaultCronConfig().VacuumDeadTupleRatio
+	}
+
+	partitioned, err := jobCtx.Repositories.Maintenance.IsPartitioned(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	partitions, err := jobCtx.Repositories.Maintenance.GetTablePartitions(ctx, table)
+	if err != nil {
+		return err
+	}
+
+	var bloated []string
+	var totalTuples, largestTuples int64
+	for _, partition := range partitions {
+		totalTuples += partition.LiveTuples
+		largestTuples = max(largestTuples, partition.LiveTuples)
+
+		ratio := float64(partition.DeadTuples) / float64(max(partition.LiveTuples, 1))
+		if ratio >= deadTupleRatio {
+			bloated = append(bloated, partition.Name)
+		}
+
+		jobCtx.Server.Logger.Info().
+			Str("partition", partition.Name).
+			Int64("live_tuples", partition.LiveTuples).
+			Int64("dead_tuples", partition.DeadTuples).
+			Int64("table_bytes", partition.TableBytes).
+			Int64("index_bytes", partition.IndexBytes).
+			Msg("Partition size")
+	}
+
+	if len(bloated) > 0 {
+		if err := jobCtx.Repositories.Maintenance.VacuumTables(ctx, bloated); err != nil {
+			return err
+		}
+	}
+
+	// The planner's estimates across partitions come from the parent, which autovacuum skips
+	if partitioned {
+		if err := jobCtx.Repositories.Maintenance.AnalyzeTable(ctx, table); err != nil {
+			return err
+		}
+	}
+
+	// A hash partition far above the average means a handful of users own most todos
+	skew := 0.0
+	if totalTuples > 0 {
+		skew = float64(largestTuples) / (float64(totalTuples) / float64(len(partitions)))
+	}
+
+	jobCtx.Server.Logger.Info().
+		Bool("partitioned", partitioned).
+		Int("partition_count", len(partitions)).
+		Int("vacuumed_count", len(bloated)).
+		Float64("skew", skew).
+		Msg("Todo partitions maintained")
+
+	return nil
+}
+
+type EmbedTodosJob struct{}
+
+func (j *EmbedTodosJob) Name() string {
+	return "embed-todos"
+}
+
+func (j *EmbedTodosJob) Description() string {
+	return "Compute the embeddings behind semantic search for new and edited todos"
+}
+
+func (j *EmbedTodosJob) Run(ctx context.Context, jobCtx *JobContext) error {
+	provider := embedding.NewProvider(jobCtx.Config.Embedding, jobCtx.Config.Outbound)
+	if provider == nil {
+		jobCtx.Server.Logger.Info().Msg("No embedding provider configured, semantic search is disabled")
+		return nil
+	}
+
+	batchSize := config.DefaultEmbeddingConfig().BatchSize
+	if jobCtx.Config.Embedding.BatchSize > 0 {
+		batchSize = jobCtx.Config.Embedding.BatchSize
+	}
+
+	deletedCount, err := jobCtx.Repositories.Search.DeleteOrphanedEmbeddings(ctx)
+	if err != nil {
+		return err
+	}
+
+	embeddedCount := 0
+	for {
+		pending, err := jobCtx.Repositories.Search.GetPendingEmbeddings(ctx, provider.Model(), batchSize)
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		texts := make([]string, len(pending))
+		for i, item := range pending {
+			texts[i] = item.Text
+		}
+
+		// Shares the breaker of the search queries, an outage ends the run until the next one
+		var vectors [][]float32
+		err = jobCtx.Server.Breakers.Get("embedding").Do(ctx, func(ctx context.Context) error {
+			var err error
+			vectors, err = provider.Embed(ctx, texts)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := jobCtx.Repositories.Search.UpsertEmbeddings(ctx, provider.Model(), pending, vectors); err != nil {
+			return err
+		}
+
+		embeddedCount += len(pending)
+		if len(pending) < batchSize {
+			break
+		}
+	}
+
+	jobCtx.Server.Logger.Info().
+		Str("model", provider.Model()).
+		Int("embedded_count", embeddedCount).
+		Int64("deleted_count", deletedCount).
+		Msg("Todo embeddings updated")
+
+	return nil
+}
+
+type UsageRollupJob struct{}
+
+func (j *UsageRollupJob) Name() string {
+	return "usage-rollup"
+}
+
+func (j *UsageRollupJob) Description() string {
+	return "Aggregate metered usage into hourly records and snapshot storage"
+}
+
+func (j *UsageRollupJob) Run(ctx context.Context, jobCtx *JobContext) error {
+	lookbackHours := jobCtx.Config.Usage.RollupLookbackHours
+	currentHour := time.Now().UTC().Truncate(time.Hour)
+
+	// Completed hours only, the current one is still being counted. Hours rolled up before are
+	// rolled up again, counts flushed late replace what was stored.
+	var records []usage.Record
+	for i := lookbackHours; i >= 1; i-- {
+		hour := currentHour.Add(-time.Duration(i) * time.Hour)
+
+		counts, err := metering.ReadBucket(ctx, jobCtx.Server.Redis, hour)
+		if err != nil {
+			return err
+		}
+
+		for field, quantity := range counts {
+			subjectType, subjectID, metric, ok := metering.ParseField(field)
+			if !ok {
+				jobCtx.Server.Logger.Warn().Str("field", field).Msg("Skipping malformed usage counter")
+				continue
+			}
+
+			records = append(records, usage.Record{
+				Hour:        hour,
+				SubjectType: subjectType,
+				SubjectID:   subjectID,
+				Metric:      metric,
+				Quantity:    quantity,
+			})
+		}
+	}
+
+	if err := jobCtx.Repositories.Usage.UpsertHourly(ctx, records); err != nil {
+		return err
+	}
+
+	snapshotCount, err := jobCtx.Repositories.Usage.SnapshotStorage(ctx, currentHour.Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+
+	jobCtx.Server.Logger.Info().
+		Int("lookback_hours", lookbackHours).
+		Int("record_count", len(records)).
+		Int64("storage_snapshot_count", snapshotCount).
+		Msg("Usage rolled up")
+
+	return nil
+}
+
+type AccountExportCleanupJob struct{}
+
+func (j *AccountExportCleanupJob) Name() string {
+	return "account-export-cleanup"
+}
+
+func (j *AccountExportCleanupJob) Description() string {
+	return "Delete expired account export archives from storage"
+}
+
+func (j *AccountExportCleanupJob) Run(ctx context.Context, jobCtx *JobContext) error {
+	exports, err := jobCtx.Repositories.Export.GetExpiredAccountExports(ctx, time.Now(), jobCtx.Config.Cron.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	if len(exports) == 0 {
+		jobCtx.Server.Logger.Info().Msg("No expired account exports to delete")
+		return nil
+	}
+
+	awsClient, err := aws.NewAWS(jobCtx.Server)
+	if err != nil {
+		return err
+	}
+
+	deletedCount := 0
+	for _, exportItem := range exports {
+		err := awsClient.Blobs.DeleteObject(ctx, jobCtx.Config.AWS.S3Bucket, *exportItem.ObjectKey)
+		if err != nil {
+			// Left for the next run
+			jobCtx.Server.Logger.Error().
+				Err(err).
+				Str("export_id", exportItem.ID.String()).
+				Msg("Failed to delete account export archive")
+			continue
+		}
+
+		if err := jobCtx.Repositories.Export.ClearAccountExportObject(ctx, exportItem.ID); err != nil {
+			return err
+		}
+		deletedCount++
+	}
+
+	jobCtx.Server.Logger.Info().
+		Int("expired_count", len(exports)).
+		Int("deleted_count", deletedCount).
+		Msg("Deleted expired account exports")
+
+	return nil
+}
+
+type DataRetentionJob struct{}
+
+func (j *DataRetentionJob) Name() string {
+	return "data-retention"
+}
+
+func (j *DataRetentionJob) Description() string {
+	return "Apply the retention policies, reporting what they select until they are enforced"
+}
+
+func (j *DataRetentionJob) Run(ctx context.Context, jobCtx *JobContext) error {
+	policies, err := jobCtx.Repositories.Retention.GetPolicies(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The default policy covers personal todos and the workspaces without a policy
+	targets := []retention.Policy{{
+		Rules:   retention.RulesFromConfig(jobCtx.Config.Retention),
+		Enforce: jobCtx.Config.Retention.Enforce,
+	}}
+	targets = append(targets, policies...)
+
+	var awsClient *aws.AWS
+	now := time.Now()
+
+	for _, target := range targets {
+		if target.Rules.IsEmpty() {
+			continue
+		}
+
+		// Every run reports first, an enforced policy deletes what the report announced
+		report, err := jobCtx.Repositories.Retention.PreviewRetention(ctx, target.WorkspaceID, target.Rules, now)
+		if err != nil {
+			return err
+		}
+
+		jobCtx.Server.Logger.Info().
+			Str("workspace_id", target.WorkspaceID).
+			Bool("dry_run", !target.Enforce).
+			Int64("archived_todos", report.ArchivedTodos).
+			Int64("comments", report.Comments).
+			Int64("attachments", report.Attachments).
+			Int64("attachment_bytes", report.AttachmentBytes).
+			Msg("Retention report")
+
+		if !target.Enforce {
+			continue
+		}
+
+		if awsClient == nil {
+			awsClient, err = aws.NewAWS(jobCtx.Server)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := j.enforce(ctx, jobCtx, awsClient, target, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// enforce deletes in batches what the rules of a policy select
+func (j *DataRetentionJob) enforce(ctx context.Context, jobCtx *JobContext, awsClient *aws.AWS,
+	policy retention.Policy, now time.Time,
+) error {
+	batchSize := jobCtx.Config.Cron.BatchSize
+	var purgedCount, trimmedCount, expiredCount int64
+
+	if archivedBefore := policy.Rules.ArchivedBefore(now); archivedBefore != nil {
+		for {
+			purged, downloadKeys, err := jobCtx.Repositories.Retention.PurgeArchivedTodos(ctx, policy.WorkspaceID,
+				*archivedBefore, batchSize)
+			if err != nil {
+				return err
+			}
+			purgedCount += purged
+
+			// The records are gone, an object that fails to delete is only logged
+			for _, key := range downloadKeys {
+				if err := awsClient.Blobs.DeleteObject(ctx, jobCtx.Config.AWS.S3Bucket, key); err != nil {
+					jobCtx.Server.Logger.Error().
+						Err(err).
+						Str("s3_key", key).
+						Msg("Failed to delete attachment of purged todo from S3")
+				}
+			}
+
+			if purged < int64(batchSize) {
+				break
+			}
+		}
+	}
+
+	if policy.Rules.MaxCommentsPerTodo != nil {
+		for {
+			trimmed, err := jobCtx.Repositories.Retention.TrimComments(ctx, policy.WorkspaceID,
+				*policy.Rules.MaxCommentsPerTodo, batchSize)
+			if err != nil {
+				return err
+			}
+			trimmedCount += trimmed
+
+			if trimmed < int64(batchSize) {
+				break
+			}
+		}
+	}
+
+	if uploadedBefore := policy.Rules.UploadedBefore(now); uploadedBefore != nil {
+		for {
+			attachments, err := jobCtx.Repositories.Retention.GetExpiredAttachments(ctx, policy.WorkspaceID,
+				*uploadedBefore, batchSize)
+			if err != nil {
+				return err
+			}
+
+			deletedIDs := make([]uuid.UUID, 0, len(attachments))
+			for _, attachment := range attachments {
+				if err := awsClient.Blobs.DeleteObject(ctx, jobCtx.Config.AWS.S3Bucket, attachment.DownloadKey); err != nil {
+					// Left for the next run
+					jobCtx.Server.Logger.Error().
+						Err(err).
+						Str("attachment_id", attachment.ID.String()).
+						Msg("Failed to delete expired attachment from S3")
+					continue
+				}
+				deletedIDs = append(deletedIDs, attachment.ID)
+			}
+
+			if len(deletedIDs) > 0 {
+				deleted, err := jobCtx.Repositories.Retention.DeleteAttachments(ctx, deletedIDs)
+				if err != nil {
+					return err
+				}
+				expiredCount += deleted
+			}
+
+			// A failed object would come back in the next batch, stop instead of spinning on it
+			if len(attachments) < batchSize || len(deletedIDs) < len(attachments) {
+				break
+			}
+		}
+	}
+
+	jobCtx.Server.Logger.Info().
+		Str("workspace_id", policy.WorkspaceID).
+		Int64("purged_todos", purgedCount).
+		Int64("trimmed_comments", trimmedCount).
+		Int64("expired_attachments", expiredCount).
+		Msg("Retention policy enforced")
+
+	return nil
+}
+
+// infrequentAccessMinBytes is the size infrequent access storage bills at least, smaller
+// objects would cost more there than in standard storage
+const infrequentAccessMinBytes = 128 * 1024
+
+type AttachmentReconcileJob struct{}
+
+func (j *AttachmentReconcileJob) Name() string {
+	return "attachment-reconcile"
+}
+
+func (j *AttachmentReconcileJob) Description() string {
+	return "Delete attachment, thumbnail and cover objects without a record and move old attachments to infrequent access storage"
+}
+
+func (j *AttachmentReconcileJob) Run(ctx context.Context, jobCtx *JobContext) error {
+	awsClient, err := aws.NewAWS(jobCtx.Server)
+	if err != nil {
+		return err
+	}
+
+	bucket := jobCtx.Config.AWS.S3Bucket
+	now := time.Now()
+	orphanCutoff := now.Add(-jobCtx.Config.Attachments.OrphanGracePeriod)
+
+	var infrequentCutoff time.Time
+	if days := jobCtx.Config.Attachments.InfrequentAccessDays; days > 0 {
+		infrequentCutoff = now.AddDate(0, 0, -days)
+	}
+
+	var scannedCount, orphanCount, movedCount, failedCount int
+	err = awsClient.Blobs.ListObjects(ctx, bucket, todo.AttachmentKeyPrefix, func(objects []blob.ObjectInfo) error {
+		if len(objects) == 0 {
+			return nil
+		}
+		scannedCount += len(objects)
+
+		keys := make([]string, len(objects))
+		for i, object := range objects {
+			keys[i] = object.Key
+		}
+
+		recordedKeys, err := jobCtx.Repositories.Todo.GetRecordedAttachmentKeys(ctx, keys)
+		if err != nil {
+			return err
+		}
+		recorded := make(map[string]bool, len(recordedKeys))
+		for _, key := range recordedKeys {
+			recorded[key] = true
+		}
+
+		for _, object := range objects {
+			if !recorded[object.Key] {
+				// A recent object may belong to an upload whose record isn't inserted yet
+				if !object.LastModified.Before(orphanCutoff) {
+					continue
+				}
+
+				if err := awsClient.Blobs.DeleteObject(ctx, bucket, object.Key); err != nil {
+					// Left for the next run
+					jobCtx.Server.Logger.Error().
+						Err(err).
+						Str("s3_key", object.Key).
+						Msg("Failed to delete orphaned attachment object")
+					failedCount++
+					continue
+				}
+				orphanCount++
+				continue
+			}
+
+			if infrequentCutoff.IsZero() || !object.LastModified.Before(infrequentCutoff) ||
+				object.Size < infrequentAccessMinBytes || (object.StorageClass != "" && object.StorageClass != "STANDARD") {
+				continue
+			}
+
+			if err := awsClient.Blobs.SetStorageClass(ctx, bucket, object.Key, "STANDARD_IA"); err != nil {
+				jobCtx.Server.Logger.Error().
+					Err(err).
+					Str("s3_key", object.Key).
+					Msg("Failed to move attachment object to infrequent access storage")
+				failedCount++
+				continue
+			}
+			movedCount++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = awsClient.Blobs.ListObjects(ctx, bucket, todo.ThumbnailKeyPrefix, func(objects []blob.ObjectInfo) error {
+		if len(objects) == 0 {
+			return nil
+		}
+		scannedCount += len(objects)
+
+		keys := make([]string, len(objects))
+		for i, object := range objects {
+			keys[i] = object.Key
+		}
+
+		recordedKeys, err := jobCtx.Repositories.Todo.GetRecordedThumbnailKeys(ctx, keys)
+		if err != nil {
+			return err
+		}
+		recorded := make(map[string]bool, len(recordedKeys))
+		for _, key := range recordedKeys {
+			recorded[key] = true
+		}
+
+		for _, object := range objects {
+			// A recent object may belong to an attachment whose processing isn't recorded yet
+			if recorded[object.Key] || !object.LastModified.Before(orphanCutoff) {
+				continue
+			}
+
+			if err := awsClient.Blobs.DeleteObject(ctx, bucket, object.Key); err != nil {
+				jobCtx.Server.Logger.Error().
+					Err(err).
+					Str("s3_key", object.Key).
+					Msg("Failed to delete orphaned attachment thumbnail")
+				failedCount++
+				continue
+			}
+			orphanCount++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Cover thumbnails are stored under the generation of the cover they were rendered for
+	err = awsClient.Blobs.ListObjects(ctx, bucket, cover.KeyPrefix, func(objects []blob.ObjectInfo) error {
+		if len(objects) == 0 {
+			return nil
+		}
+		scannedCount += len(objects)
+
+		generations := make([]uuid.UUID, 0, len(objects))
+		objectGenerations := make(map[string]uuid.UUID, len(objects))
+		for _, object := range objects {
+			segments := strings.Split(strings.TrimPrefix(object.Key, cover.KeyPrefix), "/")
+			if len(segments) != 3 {
+				continue
+			}
+			generation, err := uuid.Parse(segments[1])
+			if err != nil {
+				continue
+			}
+			generations = append(generations, generation)
+			objectGenerations[object.Key] = generation
+		}
+
+		liveGenerations, err := jobCtx.Repositories.Cover.GetLiveGenerations(ctx, generations)
+		if err != nil {
+			return err
+		}
+		live := make(map[uuid.UUID]bool, len(liveGenerations))
+		for _, generation := range liveGenerations {
+			live[generation] = true
+		}
+
+		for _, object := range objects {
+			generation, ok := objectGenerations[object.Key]
+			// A recent object may belong to a render whose cover isn't saved yet
+			if !ok || live[generation] || !object.LastModified.Before(orphanCutoff) {
+				continue
+			}
+
+			if err := awsClient.Blobs.DeleteObject(ctx, bucket, object.Key); err != nil {
+				jobCtx.Server.Logger.Error().
+					Err(err).
+					Str("s3_key", object.Key).
+					Msg("Failed to delete orphaned cover thumbnail")
+				failedCount++
+				continue
+			}
+			orphanCount++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	jobCtx.Server.Logger.Info().
+		Int("scanned_count", scannedCount).
+		Int("orphan_count", orphanCount).
+		Int("moved_count", movedCount).
+		Int("failed_count", failedCount).
+		Msg("Reconciled attachment storage")
+
+	return nil
+}
+
+type EncryptionKeyRotationJob struct{}
+
+func (j *EncryptionKeyRotationJob) Name() string {
+	return "encryption-key-rotation"
+}
+
+func (j *EncryptionKeyRotationJob) Description() string {
+	return "Replace the data key once it is due and encrypt older values with the active one"
+}
+
+func (j *EncryptionKeyRotationJob) Run(ctx context.Context, jobCtx *JobContext) error {
+	keyring := jobCtx.Repositories.Keyring
+	if !keyring.Enabled() {
+		jobCtx.Server.Logger.Info().Msg("Encryption is not configured, nothing to rotate")
+		return nil
+	}
+
+	active, err := jobCtx.Repositories.Encryption.GetActiveDataKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	rotationDays := jobCtx.Config.Encryption.RotationDays
+	if active == nil || (rotationDays > 0 && active.CreatedAt.Before(time.Now().AddDate(0, 0, -rotationDays))) {
+		dataKey, err := keyring.Rotate(ctx)
+		if err != nil {
+			return err
+		}
+
+		jobCtx.Server.Logger.Info().
+			Str("data_key_id", dataKey.ID.String()).
+			Str("master_key_id", dataKey.MasterKeyID).
+			Msg("Rotated data key")
+	}
+
+	var rewrittenCount int64
+	for {
+		rewritten, err := jobCtx.Repositories.Encryption.ReencryptColumns(ctx, keyring, jobCtx.Config.Cron.BatchSize)
+		if err != nil {
+			return err
+		}
+		rewrittenCount += rewritten
+
+		if rewritten == 0 {
+			break
+		}
+	}
+
+	jobCtx.Server.Logger.Info().
+		Int64("rewritten_count", rewrittenCount).
+		Msg("Encrypted values with the active data key")
+
+	return nil
+}
+
+// ------------
+
+type RuleOverdueTriggersJob struct{}
+
+func (j *RuleOverdueTriggersJob) Name() string {
+	return "rule-overdue-triggers"
+}
+
+func (j *RuleOverdueTriggersJob) Description() string {
+	return "Enqueue automation rule evaluations for overdue todos"
+}
+
+// Run raises the overdue event for every overdue todo of the users with an overdue rule. The
+// evaluation skips the todos a rule already ran on, so passes can overlap.
+func (j *RuleOverdueTriggersJob) Run(ctx context.Context, jobCtx *JobContext) error {
+	userIDs, err := jobCtx.Repositories.Rule.GetUserIDsWithEnabledRules(ctx, rule.TriggerTodoOverdue)
+	if err != nil {
+		return err
+	}
+
+	enqueuedCount := 0
+	for _, userID := range userIDs {
+		todos, err := jobCtx.Repositories.Todo.GetOverdueTodosForUser(ctx, userID)
+		if err != nil {
+			jobCtx.Server.Logger.Error().
+				Err(err).
+				Str("user_id", userID).
+				Msg("Failed to load overdue todos")
+			continue
+		}
+
+		for _, todoItem := range todos {
+			err := job.EnqueueRuleEvaluation(ctx, jobCtx.JobClient, &job.RuleEvaluationTask{
+				Event: rule.Event{
+					UserID:  userID,
+					Trigger: rule.TriggerTodoOverdue,
+					TodoID:  todoItem.ID,
+				},
+			})
+			if err != nil {
+				jobCtx.Server.Logger.Error().
+					Err(err).
+					Str("todo_id", todoItem.ID.String()).
+					Str("user_id", userID).
+					Msg("Failed to enqueue rule evaluation")
+				continue
+			}
+			enqueuedCount++
+		}
+	}
+
+	jobCtx.Server.Logger.Info().
+		Int("user_count", len(userIDs)).
+		Int("enqueued_count", enqueuedCount).
+		Msg("Overdue rule evaluations enqueued")
+
+	return nil
+}
+
+type CommentReplyTokenCleanupJob struct{}
+
+func (j *CommentReplyTokenCleanupJob) Name() string {
+	return "comment-reply-token-cleanup"
+}
+
+func (j *CommentReplyTokenCleanupJob) Description() string {
+	return "Delete the expired reply addresses of notification emails"
+}
+
+func (j *CommentReplyTokenCleanupJob) Run(ctx context.Context, jobCtx *JobContext) error {
+	deletedCount, err := jobCtx.Repositories.Comment.DeleteExpiredReplyTokens(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	jobCtx.Server.Logger.Info().
+		Int64("deleted_count", deletedCount).
+		Msg("Deleted expired reply tokens")
+
+	return nil
+}
+
+type AgingPoliciesJob struct{}
+
+func (j *AgingPoliciesJob) Name() string {
+	return "aging-policies"
+}
+
+func (j *AgingPoliciesJob) Description() string {
+	return "Enqueue the escalation of todos breaching the aging policies of workspaces"
+}
+
+// Run queues one evaluation per workspace with policies. A todo is escalated once per policy,
+// so passes can overlap.
+func (j *AgingPoliciesJob) Run(ctx context.Context, jobCtx *JobContext) error {
+	workspaceIDs, err := jobCtx.Repositories.Workspace.GetAgingPolicyWorkspaceIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	enqueuedCount := 0
+	for _, workspaceID := range workspaceIDs {
+		err := job.EnqueueAgingPolicyEvaluation(ctx, jobCtx.JobClient, &job.AgingPolicyEvaluationTask{
+			WorkspaceID: workspaceID,
+		})
+		if err != nil {
+			jobCtx.Server.Logger.Error().
+				Err(err).
+				Str("workspace_id", workspaceID).
+				Msg("Failed to enqueue aging policy evaluation")
+			continue
+		}
+		enqueuedCount++
+	}
+
+	jobCtx.Server.Logger.Info().
+		Int("workspace_count", len(workspaceIDs)).
+		Int("enqueued_count", enqueuedCount).
+		Msg("Aging policy evaluations enqueued")
+
+	return nil
+}
+
+type WorkspacePurgeJob struct{}
+
+func (j *WorkspacePurgeJob) Name() string {
+	return "workspace-purge"
+}
+
+func (j *WorkspacePurgeJob) Description() string {
+	return "Enqueue the purge of frozen workspaces whose retention ran out"
+}
+
+// Run queues one purge per workspace due. The objects of its attachments are left to the
+// attachment reconciliation, its backups are kept.
+func (j *WorkspacePurgeJob) Run(ctx context.Context, jobCtx *JobContext) error {
+	workspaceIDs, err := jobCtx.Repositories.Workspace.GetPurgeDueWorkspaceIDs(ctx, time.Now(), workspace.MaxPurgesPerPass)
+	if err != nil {
+		return err
+	}
+
+	enqueuedCount := 0
+	for _, workspaceID := range workspaceIDs {
+		err := job.EnqueueWorkspacePurge(ctx, jobCtx.JobClient, &job.WorkspacePurgeTask{
+			WorkspaceID: workspaceID,
+		})
+		if err != nil {
+			jobCtx.Server.Logger.Error().
+				Err(err).
+				Str("workspace_id", workspaceID).
+				Msg("Failed to enqueue workspace purge")
+			continue
+		}
+		enqueuedCount++
+	}
+
+	jobCtx.Server.Logger.Info().
+		Int("workspace_count", len(workspaceIDs)).
+		Int("enqueued_count", enqueuedCount).
+		Msg("Workspace purges enqueued")
+
+	return nil
+}
+
+type MyDayRolloverJob struct{}
+
+func (j *MyDayRolloverJob) Name() string {
+	return "my-day-rollover"
+}
+
+func (j *MyDayRolloverJob) Description() string {
+	return "Move the todos left open at the end of their day to review"
+}
+
+// Run rolls over every day that ended, in whatever timezone it was planned in. Days end on the
+// quarter hour in some timezones, the job should run at least that often.
+func (j *MyDayRolloverJob) Run(ctx context.Context, jobCtx *JobContext) error {
+	rolledCount, err := jobCtx.Repositories.MyDay.RollOver(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	jobCtx.Server.Logger.Info().
+		Int64("rolled_count", rolledCount).
+		Msg("Rolled over my day todos")
+
+	return nil
+}
+
+type ReportSchedulesJob struct{}
+
+func (j *ReportSchedulesJob) Name() string {
+	return "report-schedules"
+}
+
+func (j *ReportSchedulesJob) Description() string {
+	return "Enqueue the scheduled reports that are due"
+}
+
+// Run queues a run of every schedule due and moves it to its next time. A schedule due several
+// times since the job last ran, while the job was down, is sent once.
+func (j *ReportSchedulesJob) Run(ctx context.Context, jobCtx *JobContext) error {
+	now := time.Now()
+	schedules, err := jobCtx.Repositories.Report.GetDueSchedules(ctx, now, jobCtx.Config.Cron.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	enqueuedCount := 0
+	for _, schedule := range schedules {
+		err := job.EnqueueReportRun(ctx, jobCtx.JobClient, &job.ReportRunTask{
+			UserID:     schedule.UserID,
+			ScheduleID: schedule.ID,
+			RunAt:      schedule.NextRunAt,
+		})
+		if err != nil {
+			jobCtx.Server.Logger.Error().
+				Err(err).
+				Str("schedule_id", schedule.ID.String()).
+				Str("user_id", schedule.UserID).
+				Msg("Failed to enqueue report run")
+			continue
+		}
+
+		if err := jobCtx.Repositories.Report.SetNextRun(ctx, schedule.ID, schedule.Timing.NextRun(now)); err != nil {
+			jobCtx.Server.Logger.Error().
+				Err(err).
+				Str("schedule_id", schedule.ID.String()).
+				Msg("Failed to move report schedule to its next run")
+			continue
+		}
+
+		enqueuedCount++
+	}
+
+	jobCtx.Server.Logger.Info().
+		Int("enqueued_count", enqueuedCount).
+		Int("total_schedules", len(schedules)).
+		Msg("Report runs enqueued")
+
+	return nil
+}
@@ -18,6 +18,21 @@ func NewJobRegistry() *JobRegistry {
 	registry.Register(&OverdueNotificationsJob{})
 	registry.Register(&WeeklyReportsJob{})
 	registry.Register(&AutoArchiveJob{})
+	registry.Register(&ChangeRetentionJob{})
+	registry.Register(&RefreshStatsJob{})
+	registry.Register(&PartitionMaintenanceJob{})
+	registry.Register(&EmbedTodosJob{})
+	registry.Register(&UsageRollupJob{})
+	registry.Register(&AccountExportCleanupJob{})
+	registry.Register(&DataRetentionJob{})
+	registry.Register(&AttachmentReconcileJob{})
+	registry.Register(&EncryptionKeyRotationJob{})
+	registry.Register(&RuleOverdueTriggersJob{})
+	registry.Register(&CommentReplyTokenCleanupJob{})
+	registry.Register(&AgingPoliciesJob{})
+	registry.Register(&WorkspacePurgeJob{})
+	registry.Register(&MyDayRolloverJob{})
+	registry.Register(&ReportSchedulesJob{})
 
 	return registry
 }
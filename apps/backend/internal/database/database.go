@@ -6,6 +6,7 @@ import (
 	"net"
 	"net/url"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/Sameer16536/ExecuTask/internal/config"
@@ -15,12 +16,19 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/tracelog"
 	"github.com/newrelic/go-agent/v3/integrations/nrpgx5"
+	"github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/rs/zerolog"
 )
 
 type Database struct {
+	// Pool is the primary, it serves every write and every read that isn't routed to a replica
 	Pool *pgxpool.Pool
 	log  *zerolog.Logger
+
+	replicas    []*replica
+	next        atomic.Uint64
+	maxLag      time.Duration
+	stopMonitor chan struct{}
 }
 
 // multiTracer allows chaining multiple tracers
@@ -51,11 +59,99 @@ func (mt *multiTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data p
 	}
 }
 
+// TraceBatchStart implements pgx batch tracer interface
+func (mt *multiTracer) TraceBatchStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	for _, tracer := range mt.tracers {
+		if t, ok := tracer.(pgx.BatchTracer); ok {
+			ctx = t.TraceBatchStart(ctx, conn, data)
+		}
+	}
+	return ctx
+}
+
+// TraceBatchQuery implements pgx batch tracer interface
+func (mt *multiTracer) TraceBatchQuery(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchQueryData) {
+	for _, tracer := range mt.tracers {
+		if t, ok := tracer.(pgx.BatchTracer); ok {
+			t.TraceBatchQuery(ctx, conn, data)
+		}
+	}
+}
+
+// TraceBatchEnd implements pgx batch tracer interface
+func (mt *multiTracer) TraceBatchEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchEndData) {
+	for _, tracer := range mt.tracers {
+		if t, ok := tracer.(pgx.BatchTracer); ok {
+			t.TraceBatchEnd(ctx, conn, data)
+		}
+	}
+}
+
+// TraceConnectStart implements pgx connect tracer interface
+func (mt *multiTracer) TraceConnectStart(ctx context.Context, data pgx.TraceConnectStartData) context.Context {
+	for _, tracer := range mt.tracers {
+		if t, ok := tracer.(pgx.ConnectTracer); ok {
+			ctx = t.TraceConnectStart(ctx, data)
+		}
+	}
+	return ctx
+}
+
+// TraceConnectEnd implements pgx connect tracer interface
+func (mt *multiTracer) TraceConnectEnd(ctx context.Context, data pgx.TraceConnectEndData) {
+	for _, tracer := range mt.tracers {
+		if t, ok := tracer.(pgx.ConnectTracer); ok {
+			t.TraceConnectEnd(ctx, data)
+		}
+	}
+}
+
+// TracePrepareStart implements pgx prepare tracer interface
+func (mt *multiTracer) TracePrepareStart(ctx context.Context, conn *pgx.Conn, data pgx.TracePrepareStartData) context.Context {
+	for _, tracer := range mt.tracers {
+		if t, ok := tracer.(pgx.PrepareTracer); ok {
+			ctx = t.TracePrepareStart(ctx, conn, data)
+		}
+	}
+	return ctx
+}
+
+// TracePrepareEnd implements pgx prepare tracer interface
+func (mt *multiTracer) TracePrepareEnd(ctx context.Context, conn *pgx.Conn, data pgx.TracePrepareEndData) {
+	for _, tracer := range mt.tracers {
+		if t, ok := tracer.(pgx.PrepareTracer); ok {
+			t.TracePrepareEnd(ctx, conn, data)
+		}
+	}
+}
+
 const DatabasePingTimeout = 10
 
 func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerConfig.LoggerService) (*Database, error) {
 	hostPort := net.JoinHostPort(cfg.Database.Host, strconv.Itoa(cfg.Database.Port))
 
+	pool, err := newPool(cfg, logger, loggerService, hostPort)
+	if err != nil {
+		return nil, err
+	}
+
+	database := &Database{
+		Pool: pool,
+		log:  logger,
+	}
+
+	logger.Info().Msg("connected to the database")
+
+	if err := database.connectReplicas(cfg, loggerService); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return database, nil
+}
+
+// newPool connects to one database server, the primary or a replica
+func newPool(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerConfig.LoggerService, hostPort string) (*pgxpool.Pool, error) {
 	// URL-encode the password
 	encodedPassword := url.QueryEscape(cfg.Database.Password)
 	dsn := fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=%s",
@@ -71,30 +167,53 @@ func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerConfig
 		return nil, fmt.Errorf("failed to parse pgx pool config: %w", err)
 	}
 
+	// Pool sizing and connection recycling, shared by the primary and every replica
+	if cfg.Database.MaxOpenConns > 0 {
+		pgxPoolConfig.MaxConns = int32(cfg.Database.MaxOpenConns)
+	}
+	pgxPoolConfig.MinConns = int32(min(cfg.Database.MinConns, int(pgxPoolConfig.MaxConns)))
+	if cfg.Database.ConnMaxLifetime > 0 {
+		pgxPoolConfig.MaxConnLifetime = time.Duration(cfg.Database.ConnMaxLifetime) * time.Second
+	}
+	if cfg.Database.ConnMaxIdleTime > 0 {
+		pgxPoolConfig.MaxConnIdleTime = time.Duration(cfg.Database.ConnMaxIdleTime) * time.Second
+	}
+
+	// A server side backstop for statements that outlive their request context
+	if cfg.Database.StatementTimeout > 0 {
+		pgxPoolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.Database.StatementTimeout.Milliseconds(), 10)
+	}
+
+	tracers := []any{}
+
 	// Add New Relic PostgreSQL instrumentation
+	var nrApp *newrelic.Application
 	if loggerService != nil && loggerService.GetApplication() != nil {
-		pgxPoolConfig.ConnConfig.Tracer = nrpgx5.NewTracer()
+		nrApp = loggerService.GetApplication()
+		tracers = append(tracers, nrpgx5.NewTracer())
 	}
 
 	if cfg.Primary.Env == "local" {
 		globalLevel := logger.GetLevel()
 		pgxLogger := loggerConfig.NewPgxLogger(globalLevel)
-		// Chain tracers - New Relic first, then local logging
-		if pgxPoolConfig.ConnConfig.Tracer != nil {
-			// If New Relic tracer exists, create a multi-tracer
-			localTracer := &tracelog.TraceLog{
-				Logger:   pgxzero.NewLogger(pgxLogger),
-				LogLevel: tracelog.LogLevel(loggerConfig.GetPgxTraceLogLevel(globalLevel)),
-			}
-			pgxPoolConfig.ConnConfig.Tracer = &multiTracer{
-				tracers: []any{pgxPoolConfig.ConnConfig.Tracer, localTracer},
-			}
-		} else {
-			pgxPoolConfig.ConnConfig.Tracer = &tracelog.TraceLog{
-				Logger:   pgxzero.NewLogger(pgxLogger),
-				LogLevel: tracelog.LogLevel(loggerConfig.GetPgxTraceLogLevel(globalLevel)),
-			}
-		}
+		tracers = append(tracers, &tracelog.TraceLog{
+			Logger:   pgxzero.NewLogger(pgxLogger),
+			LogLevel: tracelog.LogLevel(loggerConfig.GetPgxTraceLogLevel(globalLevel)),
+		})
+	}
+
+	// Per query metrics and the slow query log, attributed to the calling repository method
+	tracers = append(tracers, &queryTracer{
+		log:       logger,
+		app:       nrApp,
+		threshold: cfg.Observability.Logging.SlowQueryThreshold,
+	})
+
+	// Chain tracers - New Relic first, then local logging, then query instrumentation
+	if len(tracers) == 1 {
+		pgxPoolConfig.ConnConfig.Tracer = tracers[0].(pgx.QueryTracer)
+	} else {
+		pgxPoolConfig.ConnConfig.Tracer = &multiTracer{tracers: tracers}
 	}
 
 	pool, err := pgxpool.NewWithConfig(context.Background(), pgxPoolConfig)
@@ -102,24 +221,19 @@ func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerConfig
 		return nil, fmt.Errorf("failed to create pgx pool: %w", err)
 	}
 
-	database := &Database{
-		Pool: pool,
-		log:  logger,
-	}
-
 	ctx, cancel := context.WithTimeout(context.Background(), DatabasePingTimeout*time.Second)
 	defer cancel()
 	if err = pool.Ping(ctx); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping database at %s: %w", hostPort, err)
 	}
 
-	logger.Info().Msg("connected to the database")
-
-	return database, nil
+	return pool, nil
 }
 
 func (db *Database) Close() error {
 	db.log.Info().Msg("closing database connection pool")
+	db.closeReplicas()
 	db.Pool.Close()
 	return nil
 }
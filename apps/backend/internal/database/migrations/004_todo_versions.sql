@@ -0,0 +1,20 @@
+ALTER TABLE todos
+ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- Bump version trigger function
+CREATE OR REPLACE FUNCTION trigger_increment_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER increment_version_todos
+    BEFORE UPDATE ON todos
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_increment_version();
+
+
+-- INDEX for incremental sync
+CREATE INDEX idx_todos_user_updated_at ON todos(user_id,updated_at);
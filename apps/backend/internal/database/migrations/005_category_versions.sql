@@ -0,0 +1,7 @@
+ALTER TABLE todo_categories
+ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TRIGGER increment_version_todo_categories
+    BEFORE UPDATE ON todo_categories
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_increment_version();
@@ -0,0 +1,60 @@
+CREATE TABLE change_events(
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    user_id TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_id UUID NOT NULL,
+    action TEXT NOT NULL,
+    version INTEGER
+);
+
+CREATE INDEX idx_change_events_user_id_id ON change_events(user_id, id);
+CREATE INDEX idx_change_events_created_at ON change_events(created_at);
+
+
+-- Record every write to a user owned entity
+CREATE OR REPLACE FUNCTION trigger_record_change_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    row_data JSONB;
+    change_action TEXT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_data = to_jsonb(OLD);
+        change_action = 'deleted';
+    ELSIF TG_OP = 'INSERT' THEN
+        row_data = to_jsonb(NEW);
+        change_action = 'created';
+    ELSE
+        row_data = to_jsonb(NEW);
+        change_action = 'updated';
+    END IF;
+
+    INSERT INTO change_events (user_id, entity_type, entity_id, action, version)
+    VALUES (
+        row_data->>'user_id',
+        TG_ARGV[0],
+        (row_data->>'id')::UUID,
+        change_action,
+        (row_data->>'version')::INTEGER
+    );
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_change_event_todos
+    AFTER INSERT OR UPDATE OR DELETE ON todos
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_record_change_event('todo');
+
+CREATE TRIGGER record_change_event_todo_categories
+    AFTER INSERT OR UPDATE OR DELETE ON todo_categories
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_record_change_event('category');
+
+CREATE TRIGGER record_change_event_todo_comments
+    AFTER INSERT OR UPDATE OR DELETE ON todo_comments
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_record_change_event('comment');
@@ -0,0 +1,207 @@
+ALTER TABLE todos
+ADD COLUMN subtask_count INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN completed_subtask_count INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN comment_count INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN unread_comment_count INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN comments_read_at TIMESTAMPTZ;
+
+ALTER TABLE todo_categories
+ADD COLUMN open_todo_count INTEGER NOT NULL DEFAULT 0;
+
+
+-- Backfill without bumping versions or recording change events
+ALTER TABLE todos DISABLE TRIGGER USER;
+ALTER TABLE todo_categories DISABLE TRIGGER USER;
+
+UPDATE todos t
+SET
+    subtask_count = s.total,
+    completed_subtask_count = s.completed
+FROM (
+    SELECT
+        parent_todo_id,
+        COUNT(*) AS total,
+        COUNT(*) FILTER (WHERE status = 'completed') AS completed
+    FROM
+        todos
+    WHERE
+        parent_todo_id IS NOT NULL
+    GROUP BY
+        parent_todo_id
+) s
+WHERE
+    t.id = s.parent_todo_id;
+
+UPDATE todos t
+SET
+    comment_count = s.total
+FROM (
+    SELECT
+        todo_id,
+        COUNT(*) AS total
+    FROM
+        todo_comments
+    GROUP BY
+        todo_id
+) s
+WHERE
+    t.id = s.todo_id;
+
+UPDATE todo_categories c
+SET
+    open_todo_count = s.total
+FROM (
+    SELECT
+        category_id,
+        COUNT(*) AS total
+    FROM
+        todos
+    WHERE
+        category_id IS NOT NULL
+        AND status NOT IN ('completed', 'archived')
+    GROUP BY
+        category_id
+) s
+WHERE
+    c.id = s.category_id;
+
+ALTER TABLE todos ENABLE TRIGGER USER;
+ALTER TABLE todo_categories ENABLE TRIGGER USER;
+
+
+-- Subtask and open todo counters follow todo writes
+CREATE OR REPLACE FUNCTION trigger_maintain_todo_counters()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND OLD.parent_todo_id IS NOT DISTINCT FROM NEW.parent_todo_id
+        AND OLD.category_id IS NOT DISTINCT FROM NEW.category_id
+        AND OLD.status = NEW.status THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        IF OLD.parent_todo_id IS NOT NULL THEN
+            UPDATE todos
+            SET
+                subtask_count = subtask_count - 1,
+                completed_subtask_count = completed_subtask_count - (OLD.status = 'completed')::INTEGER
+            WHERE
+                id = OLD.parent_todo_id;
+        END IF;
+
+        IF OLD.category_id IS NOT NULL AND OLD.status NOT IN ('completed', 'archived') THEN
+            UPDATE todo_categories
+            SET
+                open_todo_count = open_todo_count - 1
+            WHERE
+                id = OLD.category_id;
+        END IF;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        IF NEW.parent_todo_id IS NOT NULL THEN
+            UPDATE todos
+            SET
+                subtask_count = subtask_count + 1,
+                completed_subtask_count = completed_subtask_count + (NEW.status = 'completed')::INTEGER
+            WHERE
+                id = NEW.parent_todo_id;
+        END IF;
+
+        IF NEW.category_id IS NOT NULL AND NEW.status NOT IN ('completed', 'archived') THEN
+            UPDATE todo_categories
+            SET
+                open_todo_count = open_todo_count + 1
+            WHERE
+                id = NEW.category_id;
+        END IF;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER maintain_todo_counters
+    AFTER INSERT OR DELETE OR UPDATE OF parent_todo_id, category_id, status ON todos
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_maintain_todo_counters();
+
+
+-- Comment counters follow comment writes. Comments by anyone but the owner are unread
+-- until the owner marks the todo's comments as read.
+CREATE OR REPLACE FUNCTION trigger_maintain_comment_counters()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE todos
+        SET
+            comment_count = comment_count + 1,
+            unread_comment_count = unread_comment_count + (NEW.user_id <> user_id)::INTEGER
+        WHERE
+            id = NEW.todo_id;
+    ELSE
+        UPDATE todos
+        SET
+            comment_count = comment_count - 1,
+            unread_comment_count = unread_comment_count - (
+                OLD.user_id <> user_id
+                AND (comments_read_at IS NULL OR OLD.created_at > comments_read_at)
+            )::INTEGER
+        WHERE
+            id = OLD.todo_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER maintain_comment_counters
+    AFTER INSERT OR DELETE ON todo_comments
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_maintain_comment_counters();
+
+
+-- Counter updates come from the triggers above, they are not edits of the row:
+-- skip the updated_at, version and change event triggers for them
+DROP TRIGGER set_updated_at_todos ON todos;
+CREATE TRIGGER set_updated_at_todos
+    BEFORE UPDATE ON todos
+    FOR EACH ROW
+    WHEN (pg_trigger_depth() < 1)
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+DROP TRIGGER increment_version_todos ON todos;
+CREATE TRIGGER increment_version_todos
+    BEFORE UPDATE ON todos
+    FOR EACH ROW
+    WHEN (pg_trigger_depth() < 1)
+    EXECUTE FUNCTION trigger_increment_version();
+
+DROP TRIGGER record_change_event_todos ON todos;
+CREATE TRIGGER record_change_event_todos
+    AFTER INSERT OR UPDATE OR DELETE ON todos
+    FOR EACH ROW
+    WHEN (pg_trigger_depth() < 1)
+    EXECUTE FUNCTION trigger_record_change_event('todo');
+
+DROP TRIGGER set_updated_at_todo_categories ON todo_categories;
+CREATE TRIGGER set_updated_at_todo_categories
+    BEFORE UPDATE ON todo_categories
+    FOR EACH ROW
+    WHEN (pg_trigger_depth() < 1)
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+DROP TRIGGER increment_version_todo_categories ON todo_categories;
+CREATE TRIGGER increment_version_todo_categories
+    BEFORE UPDATE ON todo_categories
+    FOR EACH ROW
+    WHEN (pg_trigger_depth() < 1)
+    EXECUTE FUNCTION trigger_increment_version();
+
+DROP TRIGGER record_change_event_todo_categories ON todo_categories;
+CREATE TRIGGER record_change_event_todo_categories
+    AFTER INSERT OR UPDATE OR DELETE ON todo_categories
+    FOR EACH ROW
+    WHEN (pg_trigger_depth() < 1)
+    EXECUTE FUNCTION trigger_record_change_event('category');
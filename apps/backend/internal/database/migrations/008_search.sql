@@ -0,0 +1,10 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Full text search over title and description, the expression must match the repository's search query
+CREATE INDEX idx_todos_search ON todos USING GIN (
+    to_tsvector('english', title || ' ' || coalesce(description, ''))
+);
+
+-- Trigram indexes serve substring (ILIKE) matches and typo tolerant similarity lookups
+CREATE INDEX idx_todos_title_trgm ON todos USING GIN (title gin_trgm_ops);
+CREATE INDEX idx_todo_categories_name_trgm ON todo_categories USING GIN (name gin_trgm_ops);
@@ -0,0 +1,48 @@
+-- Dashboard aggregates, refreshed by the refresh-stats cron job so the overview never scans todos.
+-- Every view has a unique index so it can be refreshed concurrently without blocking reads.
+CREATE MATERIALIZED VIEW user_stats_summary AS
+SELECT
+    user_id,
+    COUNT(*) AS total,
+    COUNT(*) FILTER (WHERE completed_at IS NOT NULL) AS completed,
+    COUNT(*) FILTER (WHERE status IN ('draft', 'active')) AS open,
+    AVG(EXTRACT(EPOCH FROM (completed_at - created_at)))::DOUBLE PRECISION AS avg_completion_seconds,
+    NOW() AS refreshed_at
+FROM
+    todos
+GROUP BY
+    user_id;
+
+CREATE UNIQUE INDEX idx_user_stats_summary_user_id ON user_stats_summary(user_id);
+
+CREATE MATERIALIZED VIEW user_daily_completions AS
+SELECT
+    user_id,
+    (completed_at AT TIME ZONE 'UTC')::DATE AS day,
+    COUNT(*) AS completed
+FROM
+    todos
+WHERE
+    completed_at IS NOT NULL
+GROUP BY
+    user_id,
+    day;
+
+CREATE UNIQUE INDEX idx_user_daily_completions_user_day ON user_daily_completions(user_id, day);
+
+CREATE MATERIALIZED VIEW user_category_stats AS
+SELECT
+    user_id,
+    category_id,
+    COUNT(*) AS total,
+    COUNT(*) FILTER (WHERE completed_at IS NOT NULL) AS completed,
+    AVG(EXTRACT(EPOCH FROM (completed_at - created_at)))::DOUBLE PRECISION AS avg_completion_seconds
+FROM
+    todos
+WHERE
+    category_id IS NOT NULL
+GROUP BY
+    user_id,
+    category_id;
+
+CREATE UNIQUE INDEX idx_user_category_stats_user_category ON user_category_stats(user_id, category_id);
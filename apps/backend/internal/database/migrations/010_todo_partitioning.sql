@@ -0,0 +1,203 @@
+-- Counter updates also match the owner so they stay on a single partition once todos are partitioned
+CREATE OR REPLACE FUNCTION trigger_maintain_todo_counters()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND OLD.parent_todo_id IS NOT DISTINCT FROM NEW.parent_todo_id
+        AND OLD.category_id IS NOT DISTINCT FROM NEW.category_id
+        AND OLD.status = NEW.status THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        IF OLD.parent_todo_id IS NOT NULL THEN
+            UPDATE todos
+            SET
+                subtask_count = subtask_count - 1,
+                completed_subtask_count = completed_subtask_count - (OLD.status = 'completed')::INTEGER
+            WHERE
+                id = OLD.parent_todo_id
+                AND user_id = OLD.user_id;
+        END IF;
+
+        IF OLD.category_id IS NOT NULL AND OLD.status NOT IN ('completed', 'archived') THEN
+            UPDATE todo_categories
+            SET
+                open_todo_count = open_todo_count - 1
+            WHERE
+                id = OLD.category_id;
+        END IF;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        IF NEW.parent_todo_id IS NOT NULL THEN
+            UPDATE todos
+            SET
+                subtask_count = subtask_count + 1,
+                completed_subtask_count = completed_subtask_count + (NEW.status = 'completed')::INTEGER
+            WHERE
+                id = NEW.parent_todo_id
+                AND user_id = NEW.user_id;
+        END IF;
+
+        IF NEW.category_id IS NOT NULL AND NEW.status NOT IN ('completed', 'archived') THEN
+            UPDATE todo_categories
+            SET
+                open_todo_count = open_todo_count + 1
+            WHERE
+                id = NEW.category_id;
+        END IF;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+
+-- A partitioned todos table can only be referenced through (id, user_id). Comments and
+-- attachments may be written by collaborators, so their link to the todo is kept by triggers.
+CREATE OR REPLACE FUNCTION trigger_check_todo_exists()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- FOR KEY SHARE is the lock a foreign key check takes on the referenced row
+    PERFORM 1 FROM todos WHERE id = NEW.todo_id FOR KEY SHARE;
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'todo % referenced by % does not exist', NEW.todo_id, TG_TABLE_NAME
+            USING ERRCODE = 'foreign_key_violation';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION trigger_delete_todo_dependents()
+RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM todo_comments WHERE todo_id = OLD.id;
+    DELETE FROM todo_attachments WHERE todo_id = OLD.id;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+
+-- Converts todos to a table hash partitioned by user_id, so every index is split per partition
+-- on large multi tenant installs. It rewrites the table under an exclusive lock, so it is not
+-- run by the migrations: call it in a maintenance window, e.g. CALL partition_todos(16).
+-- Indexes, triggers and the dashboard views are recreated from their current definitions.
+CREATE OR REPLACE PROCEDURE partition_todos(partition_count INTEGER)
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    index_defs TEXT[];
+    trigger_defs TEXT[];
+    foreign_key_defs TEXT[];
+    view_defs TEXT[];
+    view_index_defs TEXT[];
+    sort_order_seq TEXT;
+    def TEXT;
+BEGIN
+    IF partition_count < 2 THEN
+        RAISE EXCEPTION 'partition_count must be at least 2, got %', partition_count;
+    END IF;
+
+    IF EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = 'todos'::REGCLASS) THEN
+        RAISE NOTICE 'todos is already partitioned';
+        RETURN;
+    END IF;
+
+    LOCK TABLE todos, todo_comments, todo_attachments IN ACCESS EXCLUSIVE MODE;
+
+    SELECT COALESCE(array_agg(pg_get_indexdef(indexrelid)), '{}')
+    INTO index_defs
+    FROM pg_index
+    WHERE indrelid = 'todos'::REGCLASS AND NOT indisprimary;
+
+    SELECT COALESCE(array_agg(pg_get_triggerdef(oid)), '{}')
+    INTO trigger_defs
+    FROM pg_trigger
+    WHERE tgrelid = 'todos'::REGCLASS AND NOT tgisinternal;
+
+    -- The parent reference is recreated by hand, it has to include user_id
+    SELECT COALESCE(array_agg(format('ALTER TABLE todos ADD CONSTRAINT %I %s', conname, pg_get_constraintdef(oid))), '{}')
+    INTO foreign_key_defs
+    FROM pg_constraint
+    WHERE conrelid = 'todos'::REGCLASS AND contype = 'f' AND confrelid <> 'todos'::REGCLASS;
+
+    SELECT
+        COALESCE(array_agg(format('CREATE MATERIALIZED VIEW %I AS %s', v.relname, rtrim(pg_get_viewdef(v.oid), ';'))), '{}')
+    INTO view_defs
+    FROM pg_class v
+    WHERE v.relkind = 'm' AND v.oid IN (
+        SELECT r.ev_class
+        FROM pg_rewrite r
+        JOIN pg_depend d ON d.objid = r.oid
+        WHERE d.refobjid = 'todos'::REGCLASS
+    );
+
+    SELECT COALESCE(array_agg(pg_get_indexdef(i.indexrelid)), '{}')
+    INTO view_index_defs
+    FROM pg_index i
+    WHERE i.indrelid IN (
+        SELECT r.ev_class
+        FROM pg_rewrite r
+        JOIN pg_depend d ON d.objid = r.oid
+        WHERE d.refobjid = 'todos'::REGCLASS
+    );
+
+    -- The sort order sequence would otherwise be dropped with the old table
+    sort_order_seq := pg_get_serial_sequence('todos', 'sort_order');
+    IF sort_order_seq IS NOT NULL THEN
+        EXECUTE format('ALTER SEQUENCE %s OWNED BY NONE', sort_order_seq);
+    END IF;
+
+    ALTER TABLE todos RENAME TO todos_unpartitioned;
+
+    CREATE TABLE todos (
+        LIKE todos_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS
+    ) PARTITION BY HASH (user_id);
+
+    FOR i IN 0 .. partition_count - 1 LOOP
+        EXECUTE format(
+            'CREATE TABLE %I PARTITION OF todos FOR VALUES WITH (MODULUS %s, REMAINDER %s)',
+            'todos_p' || i, partition_count, i
+        );
+    END LOOP;
+
+    -- Copied before the triggers exist, moving rows is not an edit
+    INSERT INTO todos SELECT * FROM todos_unpartitioned;
+
+    -- Takes the dashboard views and the comment and attachment foreign keys with it
+    DROP TABLE todos_unpartitioned CASCADE;
+
+    IF sort_order_seq IS NOT NULL THEN
+        EXECUTE format('ALTER SEQUENCE %s OWNED BY todos.sort_order', sort_order_seq);
+    END IF;
+
+    ALTER TABLE todos ADD PRIMARY KEY (id, user_id);
+    ALTER TABLE todos
+        ADD CONSTRAINT todos_parent_todo_id_fkey
+        FOREIGN KEY (parent_todo_id, user_id) REFERENCES todos (id, user_id);
+
+    FOREACH def IN ARRAY index_defs || trigger_defs || foreign_key_defs || view_defs || view_index_defs LOOP
+        EXECUTE def;
+    END LOOP;
+
+    CREATE TRIGGER check_todo_exists_todo_comments
+        BEFORE INSERT OR UPDATE OF todo_id ON todo_comments
+        FOR EACH ROW
+        EXECUTE FUNCTION trigger_check_todo_exists();
+
+    CREATE TRIGGER check_todo_exists_todo_attachments
+        BEFORE INSERT OR UPDATE OF todo_id ON todo_attachments
+        FOR EACH ROW
+        EXECUTE FUNCTION trigger_check_todo_exists();
+
+    CREATE TRIGGER delete_todo_dependents
+        AFTER DELETE ON todos
+        FOR EACH ROW
+        EXECUTE FUNCTION trigger_delete_todo_dependents();
+
+    ANALYZE todos;
+END;
+$$;
@@ -0,0 +1,28 @@
+-- Semantic search is optional: the embedding index is only created where the pgvector extension
+-- is available. Vectors are 1536 dimensional, they must match embedding.Dimensions.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'vector') THEN
+        RAISE NOTICE 'pgvector is not available, semantic search stays disabled';
+        RETURN;
+    END IF;
+
+    CREATE EXTENSION IF NOT EXISTS vector;
+
+    -- Keyed by todo without a foreign key so it works with a partitioned todos table, embeddings
+    -- of deleted todos are removed by the embed-todos job
+    CREATE TABLE todo_embeddings (
+        todo_id UUID PRIMARY KEY,
+        updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+        user_id TEXT NOT NULL,
+        model TEXT NOT NULL,
+        -- Hash of the embedded text, a todo whose title or description changed is embedded again
+        content_hash TEXT NOT NULL,
+        embedding vector(1536) NOT NULL
+    );
+
+    CREATE INDEX idx_todo_embeddings_user_id ON todo_embeddings(user_id);
+    CREATE INDEX idx_todo_embeddings_embedding ON todo_embeddings USING hnsw (embedding vector_cosine_ops);
+END;
+$$;
@@ -0,0 +1,43 @@
+-- Security relevant actions, who did them from where and what they changed. Entries are never
+-- edited or removed, not even by the application.
+CREATE TABLE audit_log(
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    actor_id TEXT NOT NULL,
+    actor_role TEXT NOT NULL,
+    -- Set when a support user acts through an impersonation session
+    impersonator_id TEXT,
+    action TEXT NOT NULL,
+    entity_type TEXT,
+    entity_id TEXT,
+    ip_address TEXT,
+    user_agent TEXT,
+    request_id TEXT,
+    before JSONB,
+    after JSONB
+);
+
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX idx_audit_log_actor_id_id ON audit_log(actor_id, id);
+CREATE INDEX idx_audit_log_entity ON audit_log(entity_type, entity_id);
+CREATE INDEX idx_audit_log_action_id ON audit_log(action, id);
+
+
+CREATE OR REPLACE FUNCTION trigger_prevent_audit_log_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only, % is not allowed', TG_OP
+        USING ERRCODE = 'insufficient_privilege';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER prevent_audit_log_change
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_prevent_audit_log_change();
+
+CREATE TRIGGER prevent_audit_log_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION trigger_prevent_audit_log_change();
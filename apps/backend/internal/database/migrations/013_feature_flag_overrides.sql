@@ -0,0 +1,23 @@
+-- Per user, per workspace and global overrides of the feature flags in the config. A global
+-- override has an empty subject_id.
+CREATE TABLE feature_flag_overrides(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    flag TEXT NOT NULL,
+    scope TEXT NOT NULL CHECK (scope IN ('user', 'workspace', 'global')),
+    subject_id TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL,
+    -- The admin who set the override
+    updated_by TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX feature_flag_overrides_unique_subject ON feature_flag_overrides(flag, scope, subject_id);
+CREATE INDEX idx_feature_flag_overrides_subject ON feature_flag_overrides(scope, subject_id);
+
+
+CREATE TRIGGER set_updated_at_feature_flag_overrides
+    BEFORE UPDATE ON feature_flag_overrides
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
@@ -0,0 +1,18 @@
+-- Hourly usage per user and per workspace, written by the usage-rollup job. Counters such as
+-- api_calls hold what happened during the hour, gauges such as storage_bytes the value at its end.
+CREATE TABLE usage_hourly(
+    hour TIMESTAMPTZ NOT NULL,
+    subject_type TEXT NOT NULL CHECK (subject_type IN ('user', 'workspace')),
+    subject_id TEXT NOT NULL,
+    metric TEXT NOT NULL,
+    quantity BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (subject_type, subject_id, metric, hour)
+);
+
+CREATE INDEX idx_usage_hourly_hour ON usage_hourly(hour);
+
+---- create above / drop below ----
+
+DROP TABLE usage_hourly;
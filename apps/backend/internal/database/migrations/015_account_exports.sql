@@ -0,0 +1,33 @@
+-- Account data exports (takeout). The archive is assembled by a background job and stored in
+-- S3, the row tracks its progress and where it ended up.
+CREATE TABLE account_exports(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    user_id TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    object_key TEXT,
+    size_bytes BIGINT,
+    -- Why the export failed, safe to show to the user
+    error TEXT,
+    completed_at TIMESTAMPTZ,
+    -- The archive is deleted from S3 once it expires
+    expires_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_account_exports_user_id_created_at ON account_exports(user_id, created_at DESC);
+
+-- At most one export in progress per user
+CREATE UNIQUE INDEX account_exports_unique_in_progress ON account_exports(user_id)
+    WHERE status IN ('pending', 'running');
+
+
+CREATE TRIGGER set_updated_at_account_exports
+    BEFORE UPDATE ON account_exports
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE account_exports;
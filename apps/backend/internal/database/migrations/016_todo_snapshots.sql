@@ -0,0 +1,87 @@
+-- The tables owned along with a todo reference it through (id, user_id), so they keep working
+-- once todos is partitioned by user_id. The unpartitioned table is keyed by id alone, the pair
+-- gets a key of its own; a partitioned table has it as its primary key already.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = 'todos'::REGCLASS) THEN
+        ALTER TABLE todos ADD CONSTRAINT todos_id_user_id_key UNIQUE (id, user_id);
+    END IF;
+END;
+$$;
+
+
+-- The editable fields of every version of a todo, so an edit can be undone. Counter updates
+-- don't bump the version and don't take a snapshot either.
+CREATE TABLE todo_snapshots(
+    todo_id UUID NOT NULL,
+    user_id TEXT NOT NULL,
+    version INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    title TEXT NOT NULL,
+    description TEXT,
+    priority TEXT NOT NULL,
+    status TEXT NOT NULL,
+    due_date TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    parent_todo_id UUID,
+    category_id UUID,
+    metadata JSONB,
+
+    PRIMARY KEY (todo_id, version),
+    FOREIGN KEY (todo_id, user_id) REFERENCES todos (id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_todo_snapshots_user_id ON todo_snapshots(user_id);
+
+
+-- Snapshot every write of a todo, keeping its 50 most recent versions
+CREATE OR REPLACE FUNCTION trigger_record_todo_snapshot()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO todo_snapshots (
+        todo_id, user_id, version, title, description, priority, status, due_date, completed_at,
+        parent_todo_id, category_id, metadata
+    )
+    VALUES (
+        NEW.id, NEW.user_id, NEW.version, NEW.title, NEW.description, NEW.priority, NEW.status,
+        NEW.due_date, NEW.completed_at, NEW.parent_todo_id, NEW.category_id, NEW.metadata
+    )
+    ON CONFLICT (todo_id, version) DO NOTHING;
+
+    DELETE FROM todo_snapshots
+    WHERE
+        todo_id = NEW.id
+        AND version <= NEW.version - 50;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_todo_snapshot_todos
+    AFTER INSERT OR UPDATE ON todos
+    FOR EACH ROW
+    WHEN (pg_trigger_depth() < 1)
+    EXECUTE FUNCTION trigger_record_todo_snapshot();
+
+
+-- Todos edited before snapshots existed start with their current version
+INSERT INTO todo_snapshots (
+    todo_id, user_id, version, created_at, title, description, priority, status, due_date,
+    completed_at, parent_todo_id, category_id, metadata
+)
+SELECT
+    id, user_id, version, updated_at, title, description, priority, status, due_date,
+    completed_at, parent_todo_id, category_id, metadata
+FROM
+    todos;
+
+---- create above / drop below ----
+
+DROP TRIGGER record_todo_snapshot_todos ON todos;
+
+DROP FUNCTION trigger_record_todo_snapshot();
+
+DROP TABLE todo_snapshots;
+
+ALTER TABLE todos DROP CONSTRAINT IF EXISTS todos_id_user_id_key;
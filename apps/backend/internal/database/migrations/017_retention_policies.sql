@@ -0,0 +1,38 @@
+-- The workspace (Clerk organization) active when a todo was created, NULL for personal todos.
+-- Retention policies apply per workspace through it.
+ALTER TABLE todos ADD COLUMN workspace_id TEXT;
+
+CREATE INDEX idx_todos_workspace_id ON todos(workspace_id) WHERE workspace_id IS NOT NULL;
+
+-- Per workspace retention rules, personal todos and workspaces without a row follow the
+-- retention config. A NULL rule keeps the data forever.
+CREATE TABLE retention_policies(
+    workspace_id TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- Archived todos untouched for this long are purged
+    archived_todo_months INT CHECK (archived_todo_months > 0),
+    -- Only the newest comments of a todo are kept
+    max_comments_per_todo INT CHECK (max_comments_per_todo > 0),
+    -- Attachments uploaded this long ago expire
+    attachment_days INT CHECK (attachment_days > 0),
+    -- Until enforced, the data-retention job only reports what the rules would delete
+    enforce BOOLEAN NOT NULL DEFAULT FALSE,
+    -- The admin who set the policy
+    updated_by TEXT NOT NULL
+);
+
+
+CREATE TRIGGER set_updated_at_retention_policies
+    BEFORE UPDATE ON retention_policies
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE retention_policies;
+
+DROP INDEX idx_todos_workspace_id;
+
+ALTER TABLE todos DROP COLUMN workspace_id;
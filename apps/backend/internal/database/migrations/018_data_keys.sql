@@ -0,0 +1,20 @@
+-- Data keys of the envelope encryption of sensitive fields. A key is only stored wrapped by
+-- the master key, in KMS or the config. Sealed values name the key they were encrypted with.
+CREATE TABLE data_keys(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    wrapped_key BYTEA NOT NULL,
+    -- The KMS key ARN or the fingerprint of the local master key that wrapped it
+    master_key_id TEXT NOT NULL,
+    -- Retired keys only decrypt, the encryption-key-rotation job moves their values to the
+    -- active key
+    retired_at TIMESTAMPTZ
+);
+
+-- At most one active key
+CREATE UNIQUE INDEX data_keys_unique_active ON data_keys((retired_at IS NULL)) WHERE retired_at IS NULL;
+
+---- create above / drop below ----
+
+DROP TABLE data_keys;
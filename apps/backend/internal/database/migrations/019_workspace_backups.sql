@@ -0,0 +1,62 @@
+-- Logical backups of a workspace. The archive is assembled by a background job and stored in
+-- S3, the row tracks its progress and where it ended up.
+CREATE TABLE workspace_backups(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    workspace_id TEXT NOT NULL,
+    requested_by TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    object_key TEXT,
+    size_bytes BIGINT,
+    -- Rows per resource the archive holds
+    counts JSONB,
+    error TEXT,
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_workspace_backups_workspace_id_created_at ON workspace_backups(workspace_id, created_at DESC);
+
+-- At most one backup in progress per workspace
+CREATE UNIQUE INDEX workspace_backups_unique_in_progress ON workspace_backups(workspace_id)
+    WHERE status IN ('pending', 'running');
+
+CREATE TRIGGER set_updated_at_workspace_backups
+    BEFORE UPDATE ON workspace_backups
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+
+-- Restores of a backup into a workspace holding no data, run by a background job
+CREATE TABLE workspace_restores(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    backup_id UUID NOT NULL REFERENCES workspace_backups ON DELETE CASCADE,
+    target_workspace_id TEXT NOT NULL,
+    requested_by TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    -- Rows per resource the restore created
+    counts JSONB,
+    error TEXT,
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_workspace_restores_backup_id ON workspace_restores(backup_id);
+
+-- At most one restore in progress per target workspace
+CREATE UNIQUE INDEX workspace_restores_unique_in_progress ON workspace_restores(target_workspace_id)
+    WHERE status IN ('pending', 'running');
+
+CREATE TRIGGER set_updated_at_workspace_restores
+    BEFORE UPDATE ON workspace_restores
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE workspace_restores;
+
+DROP TABLE workspace_backups;
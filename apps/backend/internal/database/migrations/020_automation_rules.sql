@@ -0,0 +1,50 @@
+-- Per user "when X then Y" rules. A background job evaluates them against the changes of the
+-- user's todos, conditions and actions are kept as JSON documents.
+CREATE TABLE automation_rules(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    trigger TEXT NOT NULL CHECK (trigger IN ('todo_created', 'todo_completed', 'todo_overdue', 'todo_tagged')),
+    conditions JSONB NOT NULL DEFAULT '{}',
+    actions JSONB NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE INDEX idx_automation_rules_user_id_trigger ON automation_rules(user_id, trigger) WHERE enabled;
+
+CREATE TRIGGER set_updated_at_automation_rules
+    BEFORE UPDATE ON automation_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+
+-- The execution log, one row per run of a rule against a todo. Rows go with their rule.
+CREATE TABLE rule_executions(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    rule_id UUID NOT NULL REFERENCES automation_rules ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    todo_id UUID NOT NULL,
+    trigger TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('succeeded', 'failed', 'skipped')),
+    -- Rules that ran before this one off the same change
+    depth INT NOT NULL DEFAULT 0,
+    actions_applied JSONB NOT NULL DEFAULT '[]',
+    error TEXT
+);
+
+CREATE INDEX idx_rule_executions_rule_id_created_at ON rule_executions(rule_id, created_at DESC);
+
+-- Overdue rules run once per todo, the lookup goes through this index
+CREATE INDEX idx_rule_executions_rule_id_todo_id ON rule_executions(rule_id, todo_id)
+    WHERE trigger = 'todo_overdue';
+
+---- create above / drop below ----
+
+DROP TABLE rule_executions;
+
+DROP TABLE automation_rules;
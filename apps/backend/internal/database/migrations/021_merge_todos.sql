@@ -0,0 +1,77 @@
+-- Merging todos moves comments to another todo, their counters follow the move
+CREATE OR REPLACE FUNCTION trigger_maintain_comment_counters()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD.todo_id = NEW.todo_id THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE todos
+        SET
+            comment_count = comment_count - 1,
+            unread_comment_count = unread_comment_count - (
+                OLD.user_id <> user_id
+                AND (comments_read_at IS NULL OR OLD.created_at > comments_read_at)
+            )::INTEGER
+        WHERE
+            id = OLD.todo_id;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE todos
+        SET
+            comment_count = comment_count + 1,
+            unread_comment_count = unread_comment_count + (
+                NEW.user_id <> user_id
+                AND (comments_read_at IS NULL OR NEW.created_at > comments_read_at)
+            )::INTEGER
+        WHERE
+            id = NEW.todo_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER maintain_comment_counters ON todo_comments;
+
+CREATE TRIGGER maintain_comment_counters
+    AFTER INSERT OR DELETE OR UPDATE OF todo_id ON todo_comments
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_maintain_comment_counters();
+
+---- create above / drop below ----
+
+DROP TRIGGER maintain_comment_counters ON todo_comments;
+
+CREATE TRIGGER maintain_comment_counters
+    AFTER INSERT OR DELETE ON todo_comments
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_maintain_comment_counters();
+
+CREATE OR REPLACE FUNCTION trigger_maintain_comment_counters()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE todos
+        SET
+            comment_count = comment_count + 1,
+            unread_comment_count = unread_comment_count + (NEW.user_id <> user_id)::INTEGER
+        WHERE
+            id = NEW.todo_id;
+    ELSE
+        UPDATE todos
+        SET
+            comment_count = comment_count - 1,
+            unread_comment_count = unread_comment_count - (
+                OLD.user_id <> user_id
+                AND (comments_read_at IS NULL OR OLD.created_at > comments_read_at)
+            )::INTEGER
+        WHERE
+            id = OLD.todo_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
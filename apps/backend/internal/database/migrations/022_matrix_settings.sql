@@ -0,0 +1,21 @@
+-- Per user thresholds of the Eisenhower matrix, users without a row get the defaults
+CREATE TABLE matrix_settings(
+    user_id TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- Todos due within this many hours, or overdue, are urgent
+    urgent_within_hours INT NOT NULL CHECK (urgent_within_hours > 0),
+    -- Todos of this priority or above are important
+    important_priority TEXT NOT NULL CHECK (important_priority IN ('low', 'medium', 'high'))
+);
+
+
+CREATE TRIGGER set_updated_at_matrix_settings
+    BEFORE UPDATE ON matrix_settings
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE matrix_settings;
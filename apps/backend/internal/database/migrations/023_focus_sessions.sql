@@ -0,0 +1,38 @@
+-- Focus (Pomodoro) sessions spent on a todo, the time tracked on it. Sessions go with their todo.
+CREATE TABLE focus_sessions(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    user_id TEXT NOT NULL,
+    todo_id UUID NOT NULL,
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paused', 'completed')),
+    planned_minutes INT NOT NULL CHECK (planned_minutes > 0),
+    -- Time focused up to the last pause, the stretch running since resumed_at adds to it
+    focused_seconds INT NOT NULL DEFAULT 0 CHECK (focused_seconds >= 0),
+    -- Set while the session is active
+    resumed_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMPTZ,
+
+    FOREIGN KEY (todo_id, user_id) REFERENCES todos (id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_focus_sessions_user_id_created_at ON focus_sessions(user_id, created_at DESC);
+
+CREATE INDEX idx_focus_sessions_todo_id ON focus_sessions(todo_id);
+
+CREATE INDEX idx_focus_sessions_user_id_completed_at ON focus_sessions(user_id, completed_at)
+    WHERE status = 'completed';
+
+-- At most one session running or paused per user
+CREATE UNIQUE INDEX focus_sessions_unique_open ON focus_sessions(user_id)
+    WHERE status IN ('active', 'paused');
+
+CREATE TRIGGER set_updated_at_focus_sessions
+    BEFORE UPDATE ON focus_sessions
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE focus_sessions;
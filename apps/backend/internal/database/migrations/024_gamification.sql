@@ -0,0 +1,38 @@
+-- Badges awarded to users, each at most once. Awards show up in the change feed so clients can
+-- celebrate them.
+CREATE TABLE user_badges(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    user_id TEXT NOT NULL,
+    badge TEXT NOT NULL,
+
+    UNIQUE (user_id, badge)
+);
+
+CREATE TRIGGER record_change_event_user_badges
+    AFTER INSERT OR DELETE ON user_badges
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_record_change_event('badge');
+
+
+-- The number of todos a user means to complete each week, tracked against the daily
+-- completion aggregates
+CREATE TABLE weekly_goals(
+    user_id TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    target INT NOT NULL CHECK (target > 0)
+);
+
+CREATE TRIGGER set_updated_at_weekly_goals
+    BEFORE UPDATE ON weekly_goals
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE weekly_goals;
+
+DROP TABLE user_badges;
@@ -0,0 +1,10 @@
+-- Voice note attachments are transcribed in the background, other attachments keep NULL
+ALTER TABLE todo_attachments
+    ADD COLUMN transcription_status TEXT CHECK (transcription_status IN ('pending', 'completed', 'failed')),
+    ADD COLUMN transcript TEXT;
+
+---- create above / drop below ----
+
+ALTER TABLE todo_attachments
+    DROP COLUMN transcript,
+    DROP COLUMN transcription_status;
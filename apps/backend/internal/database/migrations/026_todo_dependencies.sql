@@ -0,0 +1,19 @@
+-- A todo is blocked until the todos it depends on are done. Links go with either todo.
+CREATE TABLE todo_dependencies(
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    user_id TEXT NOT NULL,
+    todo_id UUID NOT NULL,
+    depends_on_id UUID NOT NULL CHECK (depends_on_id <> todo_id),
+
+    PRIMARY KEY (todo_id, depends_on_id),
+    FOREIGN KEY (todo_id, user_id) REFERENCES todos (id, user_id) ON DELETE CASCADE,
+    FOREIGN KEY (depends_on_id, user_id) REFERENCES todos (id, user_id) ON DELETE CASCADE
+);
+
+-- Cascades walk from a todo to the todos it blocks
+CREATE INDEX idx_todo_dependencies_depends_on_id ON todo_dependencies(depends_on_id);
+
+---- create above / drop below ----
+
+DROP TABLE todo_dependencies;
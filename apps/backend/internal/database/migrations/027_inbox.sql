@@ -0,0 +1,14 @@
+-- Quick captures waiting to be triaged. Only the title is kept, triage moves it into a todo.
+CREATE TABLE inbox_items(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    user_id TEXT NOT NULL,
+    title TEXT NOT NULL
+);
+
+CREATE INDEX idx_inbox_items_user_id_created_at ON inbox_items(user_id, created_at);
+
+---- create above / drop below ----
+
+DROP TABLE inbox_items;
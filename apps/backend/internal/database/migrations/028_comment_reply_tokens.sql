@@ -0,0 +1,20 @@
+-- Reply addresses of the notification emails about a todo. A reply sent to one is posted as a
+-- comment on the todo by the user the email went to.
+CREATE TABLE comment_reply_tokens(
+    token TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMPTZ NOT NULL,
+
+    user_id TEXT NOT NULL,
+    todo_id UUID NOT NULL,
+
+    FOREIGN KEY (todo_id, user_id) REFERENCES todos (id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_comment_reply_tokens_todo_id ON comment_reply_tokens(todo_id);
+
+CREATE INDEX idx_comment_reply_tokens_expires_at ON comment_reply_tokens(expires_at);
+
+---- create above / drop below ----
+
+DROP TABLE comment_reply_tokens;
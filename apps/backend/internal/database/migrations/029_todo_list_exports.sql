@@ -0,0 +1,24 @@
+-- Printable PDF exports of a filtered todo list too large to render within a request share the
+-- lifecycle of account exports. Each kind gets its own export in progress.
+ALTER TABLE account_exports
+    ADD COLUMN kind TEXT NOT NULL DEFAULT 'account' CHECK (kind IN ('account', 'todo_list')),
+    -- The filters of a todo list export
+    ADD COLUMN filters JSONB;
+
+DROP INDEX account_exports_unique_in_progress;
+
+CREATE UNIQUE INDEX account_exports_unique_in_progress ON account_exports(user_id, kind)
+    WHERE status IN ('pending', 'running');
+
+---- create above / drop below ----
+
+DELETE FROM account_exports WHERE kind <> 'account';
+
+DROP INDEX account_exports_unique_in_progress;
+
+CREATE UNIQUE INDEX account_exports_unique_in_progress ON account_exports(user_id)
+    WHERE status IN ('pending', 'running');
+
+ALTER TABLE account_exports
+    DROP COLUMN filters,
+    DROP COLUMN kind;
@@ -0,0 +1,73 @@
+-- Todos the admins of a workspace publish for its members to start from
+CREATE TABLE workspace_templates(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    workspace_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT,
+    priority TEXT,
+    -- Titles of the subtasks created with the todo
+    subtasks JSONB NOT NULL DEFAULT '[]',
+    due_in_days INT CHECK (due_in_days >= 0),
+    -- The admin who published the template
+    created_by TEXT NOT NULL,
+
+    CONSTRAINT workspace_templates_unique_name UNIQUE (workspace_id, name)
+);
+
+CREATE TRIGGER set_updated_at_workspace_templates
+    BEFORE UPDATE ON workspace_templates
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+-- The categories and sample todos every member joining the workspace gets
+CREATE TABLE workspace_onboarding_kits(
+    workspace_id TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    categories JSONB NOT NULL DEFAULT '[]',
+    todos JSONB NOT NULL DEFAULT '[]',
+    -- The admin who set the kit
+    updated_by TEXT NOT NULL
+);
+
+CREATE TRIGGER set_updated_at_workspace_onboarding_kits
+    BEFORE UPDATE ON workspace_onboarding_kits
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+-- The users seen in a workspace, a user is onboarded when their row is added
+CREATE TABLE workspace_members(
+    workspace_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+-- Users who already have todos in a workspace joined before there were kits
+INSERT INTO
+    workspace_members (workspace_id, user_id, joined_at)
+SELECT
+    workspace_id,
+    user_id,
+    MIN(created_at)
+FROM
+    todos
+WHERE
+    workspace_id IS NOT NULL
+GROUP BY
+    workspace_id,
+    user_id;
+
+---- create above / drop below ----
+
+DROP TABLE workspace_members;
+
+DROP TABLE workspace_onboarding_kits;
+
+DROP TABLE workspace_templates;
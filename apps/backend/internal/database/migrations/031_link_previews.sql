@@ -0,0 +1,15 @@
+-- Preview cards of the pages linked from todo descriptions and comments, one per URL and shared
+-- by every mention of it. Pages that couldn't be previewed are kept as failed until retried.
+CREATE TABLE link_previews(
+    url TEXT PRIMARY KEY,
+    status TEXT NOT NULL CHECK (status IN ('ready', 'failed')),
+    title TEXT,
+    description TEXT,
+    image_url TEXT,
+    site_name TEXT,
+    fetched_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+---- create above / drop below ----
+
+DROP TABLE link_previews;
@@ -0,0 +1,44 @@
+-- How long the open todos of a workspace may stay open before they are escalated
+CREATE TABLE workspace_aging_policies(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    workspace_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    -- The policy covers todos of every priority when unset
+    priority TEXT,
+    max_age_hours INT NOT NULL CHECK (max_age_hours > 0),
+    -- Added to the todos breaching the policy
+    tag TEXT NOT NULL,
+    -- Whether the owners of the todos get an email
+    notify BOOLEAN NOT NULL DEFAULT TRUE,
+    -- The admin who created the policy
+    created_by TEXT NOT NULL,
+
+    CONSTRAINT workspace_aging_policies_unique_name UNIQUE (workspace_id, name)
+);
+
+CREATE TRIGGER set_updated_at_workspace_aging_policies
+    BEFORE UPDATE ON workspace_aging_policies
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+-- A todo breaches a policy once, it is escalated when its row is added
+CREATE TABLE todo_sla_breaches(
+    policy_id UUID NOT NULL REFERENCES workspace_aging_policies ON DELETE CASCADE,
+    todo_id UUID NOT NULL,
+    user_id TEXT NOT NULL,
+    breached_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (policy_id, todo_id),
+    FOREIGN KEY (todo_id, user_id) REFERENCES todos (id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_todo_sla_breaches_todo_id ON todo_sla_breaches(todo_id);
+
+---- create above / drop below ----
+
+DROP TABLE todo_sla_breaches;
+
+DROP TABLE workspace_aging_policies;
@@ -0,0 +1,33 @@
+-- The cover image of a todo, picked from its attachments or the stock library. The thumbnails
+-- are rendered in the background and stored under a key per generation.
+CREATE TABLE todo_covers(
+    todo_id UUID PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    source TEXT NOT NULL CHECK (source IN ('attachment', 'stock')),
+    -- The cover keeps its thumbnails when the attachment is deleted, so no foreign key
+    attachment_id UUID,
+    stock_key TEXT,
+    status TEXT NOT NULL CHECK (status IN ('pending', 'ready', 'failed')),
+    generation UUID NOT NULL,
+    -- Thumbnail object keys by size name
+    variant_keys JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT todo_covers_source_check CHECK (
+        (source = 'attachment' AND attachment_id IS NOT NULL AND stock_key IS NULL)
+        OR (source = 'stock' AND stock_key IS NOT NULL AND attachment_id IS NULL)
+    ),
+    FOREIGN KEY (todo_id, user_id) REFERENCES todos (id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_todo_covers_generation ON todo_covers(generation);
+
+CREATE TRIGGER set_updated_at_todo_covers
+    BEFORE UPDATE ON todo_covers
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE todo_covers;
@@ -0,0 +1,11 @@
+-- Image attachments are processed after upload: their metadata is stripped and a thumbnail is
+-- rendered
+ALTER TABLE todo_attachments
+    ADD COLUMN thumbnail_key TEXT,
+    ADD COLUMN processed_at TIMESTAMPTZ;
+
+---- create above / drop below ----
+
+ALTER TABLE todo_attachments
+    DROP COLUMN processed_at,
+    DROP COLUMN thumbnail_key;
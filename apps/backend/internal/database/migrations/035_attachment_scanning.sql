@@ -0,0 +1,13 @@
+-- Attachments are quarantined until a virus scan clears them. Those uploaded while scanning is
+-- disabled have no scan status and are served as is.
+ALTER TABLE todo_attachments
+    ADD COLUMN scan_status TEXT CHECK (scan_status IN ('pending', 'clean', 'infected', 'failed')),
+    ADD COLUMN scan_signature TEXT,
+    ADD COLUMN scanned_at TIMESTAMPTZ;
+
+---- create above / drop below ----
+
+ALTER TABLE todo_attachments
+    DROP COLUMN scanned_at,
+    DROP COLUMN scan_signature,
+    DROP COLUMN scan_status;
@@ -0,0 +1,24 @@
+-- Notes on a todo only their author reads, kept apart from the todo so nothing reading todos
+-- picks them up, such as workspace backups and the workload and breach reports. Every read and
+-- write is scoped to the author.
+CREATE TABLE todo_private_notes(
+    todo_id UUID NOT NULL,
+    user_id TEXT NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (todo_id, user_id),
+    FOREIGN KEY (todo_id, user_id) REFERENCES todos (id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_todo_private_notes_user_id ON todo_private_notes(user_id);
+
+CREATE TRIGGER set_updated_at_todo_private_notes
+    BEFORE UPDATE ON todo_private_notes
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE todo_private_notes;
@@ -0,0 +1,28 @@
+-- Per user shortcuts typed into todo titles, such as #tomorrow or #p1. The #tag is removed from
+-- the title and the fields it names are set on the todo, unless the request sets them itself.
+CREATE TABLE magic_tags(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    user_id TEXT NOT NULL,
+    -- Lower cased and without the #
+    tag TEXT NOT NULL,
+    priority TEXT CHECK (priority IN ('low', 'medium', 'high')),
+    -- The todo is due at the end of the day that many days after it is written
+    due_in_days INTEGER CHECK (due_in_days >= 0),
+    category_id UUID REFERENCES todo_categories ON DELETE SET NULL,
+    -- Where the days of due_in_days are counted
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+
+    CONSTRAINT magic_tags_tag_key UNIQUE (user_id, tag)
+);
+
+CREATE TRIGGER set_updated_at_magic_tags
+    BEFORE UPDATE ON magic_tags
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE magic_tags;
@@ -0,0 +1,16 @@
+-- When each user last looked at a todo. Kept apart from the todo so recording a view doesn't
+-- bump its version or updated_at, or show up in the change feed.
+CREATE TABLE todo_views(
+    todo_id UUID NOT NULL,
+    user_id TEXT NOT NULL,
+    viewed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (todo_id, user_id),
+    FOREIGN KEY (todo_id, user_id) REFERENCES todos (id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_todo_views_user_id ON todo_views(user_id);
+
+---- create above / drop below ----
+
+DROP TABLE todo_views;
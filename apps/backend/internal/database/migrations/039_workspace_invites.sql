@@ -0,0 +1,47 @@
+-- Shareable links that let people join a workspace. Only the hash of the token is kept, the
+-- link is shown once when it is created.
+CREATE TABLE workspace_invites(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    workspace_id TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    -- Email domains allowed to join, anyone with the link may join when empty
+    allowed_domains TEXT[] NOT NULL DEFAULT '{}',
+    -- The link may be used any number of times when unset
+    max_uses INT CHECK (max_uses > 0),
+    use_count INT NOT NULL DEFAULT 0,
+    -- The link never expires when unset
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    -- The admin who created the link
+    created_by TEXT NOT NULL
+);
+
+CREATE INDEX idx_workspace_invites_workspace_id ON workspace_invites(workspace_id);
+
+CREATE TRIGGER set_updated_at_workspace_invites
+    BEFORE UPDATE ON workspace_invites
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+-- Who joined through an invite, a user accepts an invite once
+CREATE TABLE workspace_invite_acceptances(
+    invite_id UUID NOT NULL REFERENCES workspace_invites ON DELETE CASCADE,
+    workspace_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    -- The verified address the user joined with
+    email TEXT NOT NULL,
+    accepted_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (invite_id, user_id)
+);
+
+CREATE INDEX idx_workspace_invite_acceptances_workspace_id ON workspace_invite_acceptances(workspace_id);
+
+---- create above / drop below ----
+
+DROP TABLE workspace_invite_acceptances;
+
+DROP TABLE workspace_invites;
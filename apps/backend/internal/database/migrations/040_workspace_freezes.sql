@@ -0,0 +1,27 @@
+-- Archived workspaces. Writes to a frozen workspace are rejected, its final backup is kept
+-- and its data is purged once purge_after passes. Deleting the row reactivates the workspace
+-- until it was purged.
+CREATE TABLE workspace_freezes(
+    workspace_id TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- The admin who froze the workspace
+    frozen_by TEXT NOT NULL,
+    reason TEXT,
+    -- The final export bundle
+    backup_id UUID REFERENCES workspace_backups ON DELETE SET NULL,
+    purge_after TIMESTAMPTZ NOT NULL,
+    purged_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_workspace_freezes_purge_after ON workspace_freezes(purge_after) WHERE purged_at IS NULL;
+
+CREATE TRIGGER set_updated_at_workspace_freezes
+    BEFORE UPDATE ON workspace_freezes
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE workspace_freezes;
@@ -0,0 +1,19 @@
+-- The locale each user chose to read the app and its emails in. Users without a row follow the
+-- Accept-Language of their browser and get their emails in English.
+CREATE TABLE user_locales(
+    user_id TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- A locale with a catalog, such as en or es
+    locale TEXT NOT NULL
+);
+
+CREATE TRIGGER set_updated_at_user_locales
+    BEFORE UPDATE ON user_locales
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE user_locales;
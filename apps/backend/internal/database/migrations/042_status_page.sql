@@ -0,0 +1,45 @@
+-- The history of the public status page. Each instance folds the outcome of its deep health
+-- check into the row of every component for the day, at most once a minute.
+CREATE TABLE status_checks(
+    component TEXT NOT NULL,
+    day DATE NOT NULL,
+    checks INTEGER NOT NULL DEFAULT 0,
+    degraded INTEGER NOT NULL DEFAULT 0,
+    failures INTEGER NOT NULL DEFAULT 0,
+    -- The outcome of the latest check, the current status of the component
+    last_status TEXT NOT NULL,
+    last_checked_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (component, day)
+);
+
+CREATE INDEX idx_status_checks_day ON status_checks(day);
+
+-- Incidents announced on the status page by operators
+CREATE TABLE status_incidents(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    title TEXT NOT NULL,
+    message TEXT NOT NULL,
+    -- investigating, identified, monitoring or resolved
+    status TEXT NOT NULL,
+    -- minor incidents degrade the components, major ones take them down
+    impact TEXT NOT NULL,
+    components TEXT[] NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    resolved_at TIMESTAMPTZ,
+    created_by TEXT NOT NULL
+);
+
+CREATE INDEX idx_status_incidents_started_at ON status_incidents(started_at DESC);
+
+CREATE TRIGGER set_updated_at_status_incidents
+    BEFORE UPDATE ON status_incidents
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE status_incidents;
+DROP TABLE status_checks;
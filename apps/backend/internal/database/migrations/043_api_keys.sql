@@ -0,0 +1,28 @@
+-- Keys users call the API with instead of a session. Only a hash of the secret is kept, the
+-- secret is shown once when the key is created. Deleting the row revokes the key.
+CREATE TABLE api_keys(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    -- The start of the secret, to tell keys apart
+    prefix TEXT NOT NULL,
+    -- SHA-256 of the secret
+    secret_hash BYTEA NOT NULL UNIQUE,
+    -- One of the plans of the config, it sets the rate limit and daily quota of the key
+    plan TEXT NOT NULL,
+    last_used_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id);
+
+CREATE TRIGGER set_updated_at_api_keys
+    BEFORE UPDATE ON api_keys
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE api_keys;
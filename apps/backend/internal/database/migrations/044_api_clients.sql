@@ -0,0 +1,23 @@
+-- Frontends outside of the app calling the API from a browser, such as embedded widgets and
+-- partner apps. Their origins are allowed by CORS on top of the configured ones.
+CREATE TABLE api_clients(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    name TEXT NOT NULL,
+    -- Origins as browsers send them, scheme://host[:port]
+    allowed_origins TEXT[] NOT NULL,
+    -- Disabled clients keep their origins but aren't allowed any
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by TEXT NOT NULL
+);
+
+CREATE TRIGGER set_updated_at_api_clients
+    BEFORE UPDATE ON api_clients
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE api_clients;
@@ -0,0 +1,20 @@
+-- Typeahead suggestions rank what the user goes back to often and lately. Every view of a todo
+-- is counted, not only the latest.
+ALTER TABLE todo_views ADD COLUMN view_count INTEGER NOT NULL DEFAULT 1;
+
+-- Suggestions match the start of titles, category names and tags as the user types. The
+-- text_pattern_ops indexes serve prefixes of one or two characters, which the trigram indexes
+-- can't, whatever the collation of the database.
+CREATE INDEX idx_todos_user_title_prefix ON todos(user_id, lower(title) text_pattern_ops);
+CREATE INDEX idx_todo_categories_user_name_prefix ON todo_categories(user_id, lower(name) text_pattern_ops);
+
+-- Only the todos carrying tags are read to suggest tags
+CREATE INDEX idx_todos_user_tagged ON todos(user_id) WHERE jsonb_typeof(metadata->'tags') = 'array';
+
+---- create above / drop below ----
+
+DROP INDEX idx_todos_user_tagged;
+DROP INDEX idx_todo_categories_user_name_prefix;
+DROP INDEX idx_todos_user_title_prefix;
+
+ALTER TABLE todo_views DROP COLUMN view_count;
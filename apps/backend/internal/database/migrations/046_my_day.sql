@@ -0,0 +1,23 @@
+-- The todos each user picks for a day, apart from their due dates. When the day ends in the
+-- timezone it was planned in, the todos still open go to review until the user plans them
+-- again or drops them.
+CREATE TABLE my_day_todos(
+    user_id TEXT NOT NULL,
+    todo_id UUID NOT NULL,
+    day DATE NOT NULL,
+    -- The end of the day where it was planned
+    ends_at TIMESTAMPTZ NOT NULL,
+    state TEXT NOT NULL DEFAULT 'planned' CHECK (state IN ('planned', 'review', 'reviewed')),
+    added_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (user_id, day, todo_id),
+    FOREIGN KEY (todo_id, user_id) REFERENCES todos (id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_my_day_todos_todo_id ON my_day_todos(todo_id, user_id);
+-- The rollover job only reads the days that ended with todos still planned
+CREATE INDEX idx_my_day_todos_planned_ends_at ON my_day_todos(ends_at) WHERE state = 'planned';
+
+---- create above / drop below ----
+
+DROP TABLE my_day_todos;
@@ -0,0 +1,26 @@
+-- Endpoints the admins of a workspace register to receive its todo and comment events. Every
+-- delivery is signed with the secret of its endpoint, which is sealed by the keyring.
+CREATE TABLE webhook_endpoints(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    workspace_id TEXT NOT NULL,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    -- The event types delivered, see GET /v1/webhooks/events
+    events TEXT[] NOT NULL CHECK (cardinality(events) > 0),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by TEXT NOT NULL
+);
+
+CREATE INDEX idx_webhook_endpoints_workspace_id ON webhook_endpoints(workspace_id);
+
+CREATE TRIGGER set_updated_at_webhook_endpoints
+    BEFORE UPDATE ON webhook_endpoints
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE webhook_endpoints;
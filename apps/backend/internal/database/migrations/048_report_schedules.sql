@@ -0,0 +1,51 @@
+-- Reports of the todos matching a set of filters, rendered on a schedule and emailed to
+-- stakeholders as a download link. Every run is stored as an export of its own, which the
+-- account-export-cleanup job deletes once the export retention passes.
+CREATE TABLE report_schedules(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    format TEXT NOT NULL CHECK (format IN ('pdf', 'csv')),
+    filters JSONB NOT NULL DEFAULT '{}',
+    recipients TEXT[] NOT NULL CHECK (cardinality(recipients) > 0),
+    frequency TEXT NOT NULL CHECK (frequency IN ('daily', 'weekly', 'monthly')),
+    -- The hour of the day the report is sent at, in its timezone
+    hour SMALLINT NOT NULL CHECK (hour BETWEEN 0 AND 23),
+    -- The day of weekly reports, 0 for Sunday
+    weekday SMALLINT CHECK (weekday BETWEEN 0 AND 6),
+    -- The day of monthly reports, every month has it
+    day_of_month SMALLINT CHECK (day_of_month BETWEEN 1 AND 28),
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    next_run_at TIMESTAMPTZ NOT NULL,
+    paused_at TIMESTAMPTZ,
+    last_run_at TIMESTAMPTZ,
+    -- Why the last run failed, NULL when it succeeded
+    last_error TEXT
+);
+
+CREATE INDEX idx_report_schedules_user_id ON report_schedules(user_id);
+-- The report-schedules job only reads the schedules due that aren't paused
+CREATE INDEX idx_report_schedules_due ON report_schedules(next_run_at) WHERE paused_at IS NULL;
+
+CREATE TRIGGER set_updated_at_report_schedules
+    BEFORE UPDATE ON report_schedules
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+-- The runs are stored as completed exports right away, they never hold the export in progress
+ALTER TABLE account_exports
+    DROP CONSTRAINT account_exports_kind_check,
+    ADD CONSTRAINT account_exports_kind_check CHECK (kind IN ('account', 'todo_list', 'report'));
+
+---- create above / drop below ----
+
+DELETE FROM account_exports WHERE kind = 'report';
+
+ALTER TABLE account_exports
+    DROP CONSTRAINT account_exports_kind_check,
+    ADD CONSTRAINT account_exports_kind_check CHECK (kind IN ('account', 'todo_list'));
+
+DROP TABLE report_schedules;
@@ -0,0 +1,194 @@
+-- The statuses and priority scale a workspace uses instead of the built-in ones. Todos keep
+-- their built-in status and priority, the workflow keys map onto them: a status counting as
+-- done stands for completed, the others for draft or active, and the levels of the scale spread
+-- over low, medium and high.
+CREATE TABLE workspace_workflows(
+    workspace_id TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- [{key, name, color, countsAsDone}] in the order they are shown
+    statuses JSONB NOT NULL DEFAULT '[]',
+    -- [{key, name, color}] from the lowest level
+    priorities JSONB NOT NULL DEFAULT '[]',
+    -- The admin who set the workflow
+    updated_by TEXT NOT NULL
+);
+
+CREATE TRIGGER set_updated_at_workspace_workflows
+    BEFORE UPDATE ON workspace_workflows
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+ALTER TABLE todos
+    ADD COLUMN workflow_status TEXT,
+    ADD COLUMN workflow_priority TEXT;
+
+CREATE INDEX idx_todos_workspace_workflow_status ON todos(workspace_id, workflow_status) WHERE workflow_status IS NOT NULL;
+
+
+-- Whether the status of the workflow counts as done, NULL when the workflow has no such status
+CREATE OR REPLACE FUNCTION workflow_status_done(statuses JSONB, status_key TEXT)
+RETURNS BOOLEAN AS $$
+    SELECT
+        (s->>'countsAsDone')::BOOLEAN
+    FROM
+        jsonb_array_elements(statuses) s
+    WHERE
+        s->>'key' = status_key
+    LIMIT
+        1;
+$$ LANGUAGE sql IMMUTABLE;
+
+-- The workflow status of a todo in status: current_key while it fits the status, otherwise the
+-- first status done or not done like the todo. Archived todos fit any status.
+CREATE OR REPLACE FUNCTION workflow_status_key(statuses JSONB, status TEXT, current_key TEXT)
+RETURNS TEXT AS $$
+DECLARE
+    done BOOLEAN := workflow_status_done(statuses, current_key);
+BEGIN
+    IF done IS NOT NULL AND (status = 'archived' OR done = (status = 'completed')) THEN
+        RETURN current_key;
+    END IF;
+
+    RETURN (
+        SELECT
+            s->>'key'
+        FROM
+            jsonb_array_elements(statuses) WITH ORDINALITY AS e(s, ord)
+        WHERE
+            (s->>'countsAsDone')::BOOLEAN = (status IN ('completed', 'archived'))
+        ORDER BY
+            ord
+        LIMIT
+            1
+    );
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- The level of the priority scale, from 0 for the lowest, NULL when the scale has no such level
+CREATE OR REPLACE FUNCTION workflow_priority_level(priorities JSONB, priority_key TEXT)
+RETURNS INTEGER AS $$
+    SELECT
+        (ord - 1)::INTEGER
+    FROM
+        jsonb_array_elements(priorities) WITH ORDINALITY AS e(p, ord)
+    WHERE
+        p->>'key' = priority_key
+    LIMIT
+        1;
+$$ LANGUAGE sql IMMUTABLE;
+
+-- The built-in priority a level stands for: the lowest level is low, the highest is high and
+-- the levels between spread evenly over the three. A scale of a single level is medium.
+CREATE OR REPLACE FUNCTION workflow_level_priority(priority_level INTEGER, levels INTEGER)
+RETURNS TEXT AS $$
+    SELECT
+        (ARRAY['low', 'medium', 'high'])[
+            CASE
+                WHEN levels < 2 THEN 2
+                ELSE (2 * priority_level + (levels - 1) / 2) / (levels - 1) + 1
+            END
+        ];
+$$ LANGUAGE sql IMMUTABLE;
+
+-- The level of a todo of the built-in priority: current_key while it stands for the priority,
+-- otherwise the lowest level standing for it or above, the highest level when none does
+CREATE OR REPLACE FUNCTION workflow_priority_key(priorities JSONB, priority TEXT, current_key TEXT)
+RETURNS TEXT AS $$
+DECLARE
+    levels INTEGER := jsonb_array_length(priorities);
+    current_level INTEGER := workflow_priority_level(priorities, current_key);
+    priority_rank INTEGER := array_position(ARRAY['low', 'medium', 'high'], priority);
+BEGIN
+    IF current_level IS NOT NULL AND workflow_level_priority(current_level, levels) = priority THEN
+        RETURN current_key;
+    END IF;
+
+    RETURN (
+        SELECT
+            p->>'key'
+        FROM
+            jsonb_array_elements(priorities) WITH ORDINALITY AS e(p, ord)
+        ORDER BY
+            array_position(ARRAY['low', 'medium', 'high'],
+                workflow_level_priority((ord - 1)::INTEGER, levels)) < priority_rank,
+            ord
+        LIMIT
+            1
+    );
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+
+-- Keep the workflow keys of a todo and its built-in status and priority in step, whichever of
+-- them is written: a key changed on its own moves the built-in value, completing or reopening
+-- the todo; otherwise the built-in value wins and the key is picked from it. Todos outside a
+-- workspace with a workflow have no keys.
+CREATE OR REPLACE FUNCTION trigger_sync_workflow()
+RETURNS TRIGGER AS $$
+DECLARE
+    workflow workspace_workflows%ROWTYPE;
+    status_done BOOLEAN;
+    priority_level INTEGER;
+BEGIN
+    SELECT * INTO workflow FROM workspace_workflows WHERE workspace_id = NEW.workspace_id;
+    IF NOT FOUND THEN
+        NEW.workflow_status = NULL;
+        NEW.workflow_priority = NULL;
+        RETURN NEW;
+    END IF;
+
+    status_done = workflow_status_done(workflow.statuses, NEW.workflow_status);
+    IF status_done IS NOT NULL AND TG_OP = 'UPDATE'
+        AND NEW.workflow_status IS DISTINCT FROM OLD.workflow_status
+        AND NEW.status = OLD.status
+    THEN
+        IF status_done AND NEW.status IN ('draft', 'active') THEN
+            NEW.status = 'completed';
+            NEW.completed_at = NOW();
+        ELSIF NOT status_done AND NEW.status = 'completed' THEN
+            NEW.status = 'active';
+            NEW.completed_at = NULL;
+        END IF;
+    ELSE
+        NEW.workflow_status = workflow_status_key(workflow.statuses, NEW.status, NEW.workflow_status);
+    END IF;
+
+    priority_level = workflow_priority_level(workflow.priorities, NEW.workflow_priority);
+    IF priority_level IS NOT NULL AND TG_OP = 'UPDATE'
+        AND NEW.workflow_priority IS DISTINCT FROM OLD.workflow_priority
+        AND NEW.priority = OLD.priority
+    THEN
+        NEW.priority = workflow_level_priority(priority_level, jsonb_array_length(workflow.priorities));
+    ELSE
+        NEW.workflow_priority = workflow_priority_key(workflow.priorities, NEW.priority, NEW.workflow_priority);
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER sync_workflow_todos
+    BEFORE INSERT OR UPDATE OF status, priority, workflow_status, workflow_priority, workspace_id ON todos
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_sync_workflow();
+
+---- create above / drop below ----
+
+DROP TRIGGER sync_workflow_todos ON todos;
+
+DROP FUNCTION trigger_sync_workflow();
+DROP FUNCTION workflow_priority_key(JSONB, TEXT, TEXT);
+DROP FUNCTION workflow_level_priority(INTEGER, INTEGER);
+DROP FUNCTION workflow_priority_level(JSONB, TEXT);
+DROP FUNCTION workflow_status_key(JSONB, TEXT, TEXT);
+DROP FUNCTION workflow_status_done(JSONB, TEXT);
+
+DROP INDEX idx_todos_workspace_workflow_status;
+
+ALTER TABLE todos
+    DROP COLUMN workflow_status,
+    DROP COLUMN workflow_priority;
+
+DROP TABLE workspace_workflows;
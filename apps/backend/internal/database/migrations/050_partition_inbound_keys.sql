@@ -0,0 +1,264 @@
+-- partition_todos dropped the foreign keys of the tables referencing todos with the old table,
+-- and their ON DELETE CASCADE with them. It now recreates them against the partitioned table,
+-- they all reference (id, user_id). Run it through the migrate partition command, which records
+-- the partitioned schema as the expected one; called by hand, the schema is reported as drifted
+-- until migrate accept records it.
+--
+-- Converts todos to a table hash partitioned by user_id, so every index is split per partition
+-- on large multi tenant installs. It rewrites the table under an exclusive lock, so it is not
+-- run by the migrations: call it in a maintenance window. Indexes, triggers, foreign keys and
+-- the dashboard views are recreated from their current definitions.
+CREATE OR REPLACE PROCEDURE partition_todos(partition_count INTEGER)
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    index_defs TEXT[];
+    trigger_defs TEXT[];
+    foreign_key_defs TEXT[];
+    inbound_key_defs TEXT[];
+    referencing_tables TEXT;
+    view_defs TEXT[];
+    view_index_defs TEXT[];
+    sort_order_seq TEXT;
+    def TEXT;
+BEGIN
+    IF partition_count < 2 THEN
+        RAISE EXCEPTION 'partition_count must be at least 2, got %', partition_count;
+    END IF;
+
+    IF EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = 'todos'::REGCLASS) THEN
+        RAISE NOTICE 'todos is already partitioned';
+        RETURN;
+    END IF;
+
+    LOCK TABLE todos, todo_comments, todo_attachments IN ACCESS EXCLUSIVE MODE;
+
+    -- The primary key on (id, user_id) replaces the unique key the other tables referenced
+    SELECT COALESCE(array_agg(pg_get_indexdef(indexrelid)), '{}')
+    INTO index_defs
+    FROM pg_index
+    WHERE indrelid = 'todos'::REGCLASS AND NOT indisprimary
+        AND indexrelid IS DISTINCT FROM to_regclass('todos_id_user_id_key');
+
+    SELECT COALESCE(array_agg(pg_get_triggerdef(oid)), '{}')
+    INTO trigger_defs
+    FROM pg_trigger
+    WHERE tgrelid = 'todos'::REGCLASS AND NOT tgisinternal;
+
+    -- The parent reference is recreated by hand, it has to include user_id
+    SELECT COALESCE(array_agg(format('ALTER TABLE todos ADD CONSTRAINT %I %s', conname, pg_get_constraintdef(oid))), '{}')
+    INTO foreign_key_defs
+    FROM pg_constraint
+    WHERE conrelid = 'todos'::REGCLASS AND contype = 'f' AND confrelid <> 'todos'::REGCLASS;
+
+    -- Foreign keys of other tables into todos go with the old table. Those on (id, user_id) are
+    -- recreated, comments and attachments reference the id alone and are checked by triggers.
+    SELECT
+        COALESCE(array_agg(format('ALTER TABLE %s ADD CONSTRAINT %I %s', conrelid::REGCLASS, conname, pg_get_constraintdef(oid))), '{}'),
+        string_agg(DISTINCT conrelid::REGCLASS::TEXT, ', ')
+    INTO inbound_key_defs, referencing_tables
+    FROM pg_constraint
+    WHERE confrelid = 'todos'::REGCLASS AND conrelid <> 'todos'::REGCLASS AND contype = 'f'
+        AND array_length(confkey, 1) > 1;
+
+    -- No row may reference a todo that is gone while the keys are down
+    IF referencing_tables IS NOT NULL THEN
+        EXECUTE format('LOCK TABLE %s IN SHARE ROW EXCLUSIVE MODE', referencing_tables);
+    END IF;
+
+    SELECT
+        COALESCE(array_agg(format('CREATE MATERIALIZED VIEW %I AS %s', v.relname, rtrim(pg_get_viewdef(v.oid), ';'))), '{}')
+    INTO view_defs
+    FROM pg_class v
+    WHERE v.relkind = 'm' AND v.oid IN (
+        SELECT r.ev_class
+        FROM pg_rewrite r
+        JOIN pg_depend d ON d.objid = r.oid
+        WHERE d.refobjid = 'todos'::REGCLASS
+    );
+
+    SELECT COALESCE(array_agg(pg_get_indexdef(i.indexrelid)), '{}')
+    INTO view_index_defs
+    FROM pg_index i
+    WHERE i.indrelid IN (
+        SELECT r.ev_class
+        FROM pg_rewrite r
+        JOIN pg_depend d ON d.objid = r.oid
+        WHERE d.refobjid = 'todos'::REGCLASS
+    );
+
+    -- The sort order sequence would otherwise be dropped with the old table
+    sort_order_seq := pg_get_serial_sequence('todos', 'sort_order');
+    IF sort_order_seq IS NOT NULL THEN
+        EXECUTE format('ALTER SEQUENCE %s OWNED BY NONE', sort_order_seq);
+    END IF;
+
+    ALTER TABLE todos RENAME TO todos_unpartitioned;
+
+    CREATE TABLE todos (
+        LIKE todos_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS
+    ) PARTITION BY HASH (user_id);
+
+    FOR i IN 0 .. partition_count - 1 LOOP
+        EXECUTE format(
+            'CREATE TABLE %I PARTITION OF todos FOR VALUES WITH (MODULUS %s, REMAINDER %s)',
+            'todos_p' || i, partition_count, i
+        );
+    END LOOP;
+
+    -- Copied before the triggers exist, moving rows is not an edit
+    INSERT INTO todos SELECT * FROM todos_unpartitioned;
+
+    -- Takes the dashboard views and every foreign key into todos with it
+    DROP TABLE todos_unpartitioned CASCADE;
+
+    IF sort_order_seq IS NOT NULL THEN
+        EXECUTE format('ALTER SEQUENCE %s OWNED BY todos.sort_order', sort_order_seq);
+    END IF;
+
+    ALTER TABLE todos ADD PRIMARY KEY (id, user_id);
+    ALTER TABLE todos
+        ADD CONSTRAINT todos_parent_todo_id_fkey
+        FOREIGN KEY (parent_todo_id, user_id) REFERENCES todos (id, user_id);
+
+    FOREACH def IN ARRAY index_defs || trigger_defs || foreign_key_defs || inbound_key_defs || view_defs || view_index_defs LOOP
+        EXECUTE def;
+    END LOOP;
+
+    CREATE TRIGGER check_todo_exists_todo_comments
+        BEFORE INSERT OR UPDATE OF todo_id ON todo_comments
+        FOR EACH ROW
+        EXECUTE FUNCTION trigger_check_todo_exists();
+
+    CREATE TRIGGER check_todo_exists_todo_attachments
+        BEFORE INSERT OR UPDATE OF todo_id ON todo_attachments
+        FOR EACH ROW
+        EXECUTE FUNCTION trigger_check_todo_exists();
+
+    CREATE TRIGGER delete_todo_dependents
+        AFTER DELETE ON todos
+        FOR EACH ROW
+        EXECUTE FUNCTION trigger_delete_todo_dependents();
+
+    ANALYZE todos;
+END;
+$$;
+
+---- create above / drop below ----
+
+CREATE OR REPLACE PROCEDURE partition_todos(partition_count INTEGER)
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    index_defs TEXT[];
+    trigger_defs TEXT[];
+    foreign_key_defs TEXT[];
+    view_defs TEXT[];
+    view_index_defs TEXT[];
+    sort_order_seq TEXT;
+    def TEXT;
+BEGIN
+    IF partition_count < 2 THEN
+        RAISE EXCEPTION 'partition_count must be at least 2, got %', partition_count;
+    END IF;
+
+    IF EXISTS (SELECT 1 FROM pg_partitioned_table WHERE partrelid = 'todos'::REGCLASS) THEN
+        RAISE NOTICE 'todos is already partitioned';
+        RETURN;
+    END IF;
+
+    LOCK TABLE todos, todo_comments, todo_attachments IN ACCESS EXCLUSIVE MODE;
+
+    SELECT COALESCE(array_agg(pg_get_indexdef(indexrelid)), '{}')
+    INTO index_defs
+    FROM pg_index
+    WHERE indrelid = 'todos'::REGCLASS AND NOT indisprimary;
+
+    SELECT COALESCE(array_agg(pg_get_triggerdef(oid)), '{}')
+    INTO trigger_defs
+    FROM pg_trigger
+    WHERE tgrelid = 'todos'::REGCLASS AND NOT tgisinternal;
+
+    -- The parent reference is recreated by hand, it has to include user_id
+    SELECT COALESCE(array_agg(format('ALTER TABLE todos ADD CONSTRAINT %I %s', conname, pg_get_constraintdef(oid))), '{}')
+    INTO foreign_key_defs
+    FROM pg_constraint
+    WHERE conrelid = 'todos'::REGCLASS AND contype = 'f' AND confrelid <> 'todos'::REGCLASS;
+
+    SELECT
+        COALESCE(array_agg(format('CREATE MATERIALIZED VIEW %I AS %s', v.relname, rtrim(pg_get_viewdef(v.oid), ';'))), '{}')
+    INTO view_defs
+    FROM pg_class v
+    WHERE v.relkind = 'm' AND v.oid IN (
+        SELECT r.ev_class
+        FROM pg_rewrite r
+        JOIN pg_depend d ON d.objid = r.oid
+        WHERE d.refobjid = 'todos'::REGCLASS
+    );
+
+    SELECT COALESCE(array_agg(pg_get_indexdef(i.indexrelid)), '{}')
+    INTO view_index_defs
+    FROM pg_index i
+    WHERE i.indrelid IN (
+        SELECT r.ev_class
+        FROM pg_rewrite r
+        JOIN pg_depend d ON d.objid = r.oid
+        WHERE d.refobjid = 'todos'::REGCLASS
+    );
+
+    -- The sort order sequence would otherwise be dropped with the old table
+    sort_order_seq := pg_get_serial_sequence('todos', 'sort_order');
+    IF sort_order_seq IS NOT NULL THEN
+        EXECUTE format('ALTER SEQUENCE %s OWNED BY NONE', sort_order_seq);
+    END IF;
+
+    ALTER TABLE todos RENAME TO todos_unpartitioned;
+
+    CREATE TABLE todos (
+        LIKE todos_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS
+    ) PARTITION BY HASH (user_id);
+
+    FOR i IN 0 .. partition_count - 1 LOOP
+        EXECUTE format(
+            'CREATE TABLE %I PARTITION OF todos FOR VALUES WITH (MODULUS %s, REMAINDER %s)',
+            'todos_p' || i, partition_count, i
+        );
+    END LOOP;
+
+    -- Copied before the triggers exist, moving rows is not an edit
+    INSERT INTO todos SELECT * FROM todos_unpartitioned;
+
+    -- Takes the dashboard views and the comment and attachment foreign keys with it
+    DROP TABLE todos_unpartitioned CASCADE;
+
+    IF sort_order_seq IS NOT NULL THEN
+        EXECUTE format('ALTER SEQUENCE %s OWNED BY todos.sort_order', sort_order_seq);
+    END IF;
+
+    ALTER TABLE todos ADD PRIMARY KEY (id, user_id);
+    ALTER TABLE todos
+        ADD CONSTRAINT todos_parent_todo_id_fkey
+        FOREIGN KEY (parent_todo_id, user_id) REFERENCES todos (id, user_id);
+
+    FOREACH def IN ARRAY index_defs || trigger_defs || foreign_key_defs || view_defs || view_index_defs LOOP
+        EXECUTE def;
+    END LOOP;
+
+    CREATE TRIGGER check_todo_exists_todo_comments
+        BEFORE INSERT OR UPDATE OF todo_id ON todo_comments
+        FOR EACH ROW
+        EXECUTE FUNCTION trigger_check_todo_exists();
+
+    CREATE TRIGGER check_todo_exists_todo_attachments
+        BEFORE INSERT OR UPDATE OF todo_id ON todo_attachments
+        FOR EACH ROW
+        EXECUTE FUNCTION trigger_check_todo_exists();
+
+    CREATE TRIGGER delete_todo_dependents
+        AFTER DELETE ON todos
+        FOR EACH ROW
+        EXECUTE FUNCTION trigger_delete_todo_dependents();
+
+    ANALYZE todos;
+END;
+$$;
@@ -0,0 +1,610 @@
+-- The status and priority of a todo become keys of the scales of its workspace, the built-in
+-- statuses and priorities being the keys of the scales of the workspaces without a workflow.
+-- Every status belongs to a category, the stage of the lifecycle it stands for: draft, active,
+-- completed (the statuses counting as done) or archived. The category of the status and the
+-- level of the priority are kept next to them for the counters and queries to read, the todos
+-- trigger works them out from the scales.
+DROP TRIGGER sync_workflow_todos ON todos;
+
+DROP FUNCTION trigger_sync_workflow();
+DROP FUNCTION workflow_priority_key(JSONB, TEXT, TEXT);
+DROP FUNCTION workflow_level_priority(INTEGER, INTEGER);
+DROP FUNCTION workflow_priority_level(JSONB, TEXT);
+DROP FUNCTION workflow_status_key(JSONB, TEXT, TEXT);
+DROP FUNCTION workflow_status_done(JSONB, TEXT);
+
+
+-- Statuses counting as done become completed ones and the others active ones, statuses keyed
+-- draft or archived keep standing for those. Scales without a draft or an archived status get
+-- one, todos are created and archived into them.
+UPDATE workspace_workflows w
+SET
+    statuses = (
+        SELECT
+            jsonb_agg(s ORDER BY ord)
+        FROM (
+            SELECT
+                e.ord,
+                (e.s - 'countsAsDone') || jsonb_build_object('category',
+                    CASE
+                        WHEN (e.s->>'countsAsDone')::BOOLEAN AND e.s->>'key' = 'archived' THEN 'archived'
+                        WHEN (e.s->>'countsAsDone')::BOOLEAN THEN 'completed'
+                        WHEN e.s->>'key' = 'draft' THEN 'draft'
+                        ELSE 'active'
+                    END
+                ) AS s
+            FROM
+                jsonb_array_elements(w.statuses) WITH ORDINALITY AS e(s, ord)
+            UNION ALL
+            SELECT
+                0,
+                jsonb_build_object(
+                    'key', CASE WHEN w.statuses @> '[{"key": "draft"}]' THEN 'draft-status' ELSE 'draft' END,
+                    'name', 'Draft', 'color', '#9ca3af', 'category', 'draft'
+                )
+            WHERE
+                NOT w.statuses @> '[{"key": "draft", "countsAsDone": false}]'
+            UNION ALL
+            SELECT
+                jsonb_array_length(w.statuses) + 1,
+                jsonb_build_object(
+                    'key', CASE WHEN w.statuses @> '[{"key": "archived"}]' THEN 'archived-status' ELSE 'archived' END,
+                    'name', 'Archived', 'color', '#6b7280', 'category', 'archived'
+                )
+            WHERE
+                NOT w.statuses @> '[{"key": "archived", "countsAsDone": true}]'
+        ) converted
+    )
+WHERE
+    jsonb_array_length(w.statuses) > 0;
+
+
+-- The scales of the todos of a workspace, the built-in ones when it has none of its own
+CREATE OR REPLACE FUNCTION workflow_statuses(workspace TEXT)
+RETURNS JSONB AS $$
+    SELECT
+        COALESCE(
+            (
+                SELECT
+                    statuses
+                FROM
+                    workspace_workflows
+                WHERE
+                    workspace_id = workspace
+                    AND jsonb_array_length(statuses) > 0
+            ),
+            '[
+                {"key": "draft", "category": "draft"},
+                {"key": "active", "category": "active"},
+                {"key": "completed", "category": "completed"},
+                {"key": "archived", "category": "archived"}
+            ]'
+        );
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION workflow_priorities(workspace TEXT)
+RETURNS JSONB AS $$
+    SELECT
+        COALESCE(
+            (
+                SELECT
+                    priorities
+                FROM
+                    workspace_workflows
+                WHERE
+                    workspace_id = workspace
+                    AND jsonb_array_length(priorities) > 0
+            ),
+            '[{"key": "low"}, {"key": "medium"}, {"key": "high"}]'
+        );
+$$ LANGUAGE sql STABLE;
+
+-- The category of a status of the scale, NULL when the scale has no such status
+CREATE OR REPLACE FUNCTION workflow_status_category(statuses JSONB, status_key TEXT)
+RETURNS TEXT AS $$
+    SELECT
+        s->>'category'
+    FROM
+        jsonb_array_elements(statuses) s
+    WHERE
+        s->>'key' = status_key
+    LIMIT
+        1;
+$$ LANGUAGE sql IMMUTABLE;
+
+-- The status of the scale a key stands for: the status of that key, or for a built-in status
+-- the scale doesn't have the first status of its category. Any other key stands for the first
+-- status of the fallback category.
+CREATE OR REPLACE FUNCTION workflow_resolve_status(statuses JSONB, status_key TEXT, fallback TEXT)
+RETURNS TEXT AS $$
+    SELECT
+        s->>'key'
+    FROM
+        jsonb_array_elements(statuses) WITH ORDINALITY AS e(s, ord)
+    WHERE
+        s->>'key' = status_key
+        OR s->>'category' = CASE
+            WHEN status_key IN ('draft', 'active', 'completed', 'archived') THEN status_key
+            ELSE fallback
+        END
+    ORDER BY
+        s->>'key' = status_key DESC,
+        ord
+    LIMIT
+        1;
+$$ LANGUAGE sql IMMUTABLE;
+
+-- The level of a priority of the scale, from 0 for the lowest, NULL when the scale has no such
+-- priority
+CREATE OR REPLACE FUNCTION workflow_priority_level(priorities JSONB, priority_key TEXT)
+RETURNS INTEGER AS $$
+    SELECT
+        (ord - 1)::INTEGER
+    FROM
+        jsonb_array_elements(priorities) WITH ORDINALITY AS e(p, ord)
+    WHERE
+        p->>'key' = priority_key
+    LIMIT
+        1;
+$$ LANGUAGE sql IMMUTABLE;
+
+-- The level of the scale a key stands for: the level of that key, or for a built-in priority the
+-- scale doesn't have its lowest, middle or highest level. Any other key stands for the fallback
+-- level, within the scale.
+CREATE OR REPLACE FUNCTION workflow_resolve_priority(priorities JSONB, priority_key TEXT, fallback INTEGER)
+RETURNS INTEGER AS $$
+    SELECT
+        COALESCE(
+            workflow_priority_level(priorities, priority_key),
+            CASE priority_key
+                WHEN 'low' THEN 0
+                WHEN 'medium' THEN (jsonb_array_length(priorities) - 1) / 2
+                WHEN 'high' THEN jsonb_array_length(priorities) - 1
+            END,
+            LEAST(fallback, jsonb_array_length(priorities) - 1)
+        );
+$$ LANGUAGE sql IMMUTABLE;
+
+
+ALTER TABLE todos
+    ADD COLUMN status_category TEXT NOT NULL DEFAULT 'draft',
+    ADD COLUMN priority_level INTEGER NOT NULL DEFAULT 1;
+
+-- Todos take the status and priority they showed in the workflow of their workspace, archived
+-- todos the archived status. Backfilled without bumping versions or recording change events.
+ALTER TABLE todos DISABLE TRIGGER USER;
+
+UPDATE todos
+SET
+    status = workflow_resolve_status(
+        workflow_statuses(workspace_id),
+        CASE
+            WHEN status = 'archived' THEN status
+            ELSE COALESCE(workflow_status, status)
+        END,
+        status
+    ),
+    priority = COALESCE(workflow_priority, priority);
+
+UPDATE todos
+SET
+    status_category = workflow_status_category(workflow_statuses(workspace_id), status),
+    priority_level = workflow_resolve_priority(workflow_priorities(workspace_id), priority, 1);
+
+UPDATE todos
+SET
+    priority = workflow_priorities(workspace_id)->priority_level->>'key';
+
+ALTER TABLE todos ENABLE TRIGGER USER;
+
+DROP INDEX idx_todos_workspace_workflow_status;
+
+ALTER TABLE todos
+    DROP COLUMN workflow_status,
+    DROP COLUMN workflow_priority;
+
+CREATE INDEX idx_todos_user_status_category ON todos(user_id, status_category);
+CREATE INDEX idx_todos_workspace_status ON todos(workspace_id, status) WHERE workspace_id IS NOT NULL;
+
+
+-- Settle the status and priority of a todo on the scales of its workspace whichever writes
+-- them: a built-in key stands for the status or level the scale has for it, any other key the
+-- scale doesn't have leaves the todo in the first status of its category and at its level. Only
+-- a move to another category completes or reopens the todo, an archived todo keeps when it was
+-- completed.
+CREATE OR REPLACE FUNCTION trigger_resolve_workflow()
+RETURNS TRIGGER AS $$
+DECLARE
+    statuses JSONB := workflow_statuses(NEW.workspace_id);
+    priorities JSONB := workflow_priorities(NEW.workspace_id);
+    fallback_level INTEGER := (jsonb_array_length(priorities) - 1) / 2;
+BEGIN
+    NEW.status = workflow_resolve_status(statuses, NEW.status, NEW.status_category);
+    NEW.status_category = workflow_status_category(statuses, NEW.status);
+
+    IF TG_OP = 'INSERT' OR NEW.status_category IS DISTINCT FROM OLD.status_category THEN
+        IF NEW.status_category = 'completed' THEN
+            IF NEW.completed_at IS NULL
+                OR (TG_OP = 'UPDATE' AND NEW.completed_at IS NOT DISTINCT FROM OLD.completed_at)
+            THEN
+                NEW.completed_at = NOW();
+            END IF;
+        ELSIF NEW.status_category IN ('draft', 'active') THEN
+            NEW.completed_at = NULL;
+        END IF;
+    END IF;
+
+    IF TG_OP = 'UPDATE' THEN
+        fallback_level = OLD.priority_level;
+    END IF;
+    NEW.priority_level = workflow_resolve_priority(priorities, NEW.priority, fallback_level);
+    NEW.priority = priorities->NEW.priority_level->>'key';
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER resolve_workflow_todos
+    BEFORE INSERT OR UPDATE OF status, priority, workspace_id ON todos
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_resolve_workflow();
+
+
+-- The counters count the todos by the category of their status, which moves with the workspace
+CREATE OR REPLACE FUNCTION trigger_maintain_todo_counters()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND OLD.parent_todo_id IS NOT DISTINCT FROM NEW.parent_todo_id
+        AND OLD.category_id IS NOT DISTINCT FROM NEW.category_id
+        AND OLD.status_category = NEW.status_category THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        IF OLD.parent_todo_id IS NOT NULL THEN
+            UPDATE todos
+            SET
+                subtask_count = subtask_count - 1,
+                completed_subtask_count = completed_subtask_count - (OLD.status_category = 'completed')::INTEGER
+            WHERE
+                id = OLD.parent_todo_id
+                AND user_id = OLD.user_id;
+        END IF;
+
+        IF OLD.category_id IS NOT NULL AND OLD.status_category NOT IN ('completed', 'archived') THEN
+            UPDATE todo_categories
+            SET
+                open_todo_count = open_todo_count - 1
+            WHERE
+                id = OLD.category_id;
+        END IF;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        IF NEW.parent_todo_id IS NOT NULL THEN
+            UPDATE todos
+            SET
+                subtask_count = subtask_count + 1,
+                completed_subtask_count = completed_subtask_count + (NEW.status_category = 'completed')::INTEGER
+            WHERE
+                id = NEW.parent_todo_id
+                AND user_id = NEW.user_id;
+        END IF;
+
+        IF NEW.category_id IS NOT NULL AND NEW.status_category NOT IN ('completed', 'archived') THEN
+            UPDATE todo_categories
+            SET
+                open_todo_count = open_todo_count + 1
+            WHERE
+                id = NEW.category_id;
+        END IF;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER maintain_todo_counters ON todos;
+
+CREATE TRIGGER maintain_todo_counters
+    AFTER INSERT OR DELETE OR UPDATE OF parent_todo_id, category_id, status, workspace_id ON todos
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_maintain_todo_counters();
+
+
+DROP MATERIALIZED VIEW user_stats_summary;
+
+CREATE MATERIALIZED VIEW user_stats_summary AS
+SELECT
+    user_id,
+    COUNT(*) AS total,
+    COUNT(*) FILTER (WHERE completed_at IS NOT NULL) AS completed,
+    COUNT(*) FILTER (WHERE status_category IN ('draft', 'active')) AS open,
+    AVG(EXTRACT(EPOCH FROM (completed_at - created_at)))::DOUBLE PRECISION AS avg_completion_seconds,
+    NOW() AS refreshed_at
+FROM
+    todos
+GROUP BY
+    user_id;
+
+CREATE UNIQUE INDEX idx_user_stats_summary_user_id ON user_stats_summary(user_id);
+
+---- create above / drop below ----
+
+DROP MATERIALIZED VIEW user_stats_summary;
+
+CREATE MATERIALIZED VIEW user_stats_summary AS
+SELECT
+    user_id,
+    COUNT(*) AS total,
+    COUNT(*) FILTER (WHERE completed_at IS NOT NULL) AS completed,
+    COUNT(*) FILTER (WHERE status IN ('draft', 'active')) AS open,
+    AVG(EXTRACT(EPOCH FROM (completed_at - created_at)))::DOUBLE PRECISION AS avg_completion_seconds,
+    NOW() AS refreshed_at
+FROM
+    todos
+GROUP BY
+    user_id;
+
+CREATE UNIQUE INDEX idx_user_stats_summary_user_id ON user_stats_summary(user_id);
+
+DROP TRIGGER maintain_todo_counters ON todos;
+
+CREATE TRIGGER maintain_todo_counters
+    AFTER INSERT OR DELETE OR UPDATE OF parent_todo_id, category_id, status ON todos
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_maintain_todo_counters();
+
+CREATE OR REPLACE FUNCTION trigger_maintain_todo_counters()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND OLD.parent_todo_id IS NOT DISTINCT FROM NEW.parent_todo_id
+        AND OLD.category_id IS NOT DISTINCT FROM NEW.category_id
+        AND OLD.status = NEW.status THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        IF OLD.parent_todo_id IS NOT NULL THEN
+            UPDATE todos
+            SET
+                subtask_count = subtask_count - 1,
+                completed_subtask_count = completed_subtask_count - (OLD.status = 'completed')::INTEGER
+            WHERE
+                id = OLD.parent_todo_id
+                AND user_id = OLD.user_id;
+        END IF;
+
+        IF OLD.category_id IS NOT NULL AND OLD.status NOT IN ('completed', 'archived') THEN
+            UPDATE todo_categories
+            SET
+                open_todo_count = open_todo_count - 1
+            WHERE
+                id = OLD.category_id;
+        END IF;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        IF NEW.parent_todo_id IS NOT NULL THEN
+            UPDATE todos
+            SET
+                subtask_count = subtask_count + 1,
+                completed_subtask_count = completed_subtask_count + (NEW.status = 'completed')::INTEGER
+            WHERE
+                id = NEW.parent_todo_id
+                AND user_id = NEW.user_id;
+        END IF;
+
+        IF NEW.category_id IS NOT NULL AND NEW.status NOT IN ('completed', 'archived') THEN
+            UPDATE todo_categories
+            SET
+                open_todo_count = open_todo_count + 1
+            WHERE
+                id = NEW.category_id;
+        END IF;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER resolve_workflow_todos ON todos;
+
+DROP FUNCTION trigger_resolve_workflow();
+
+-- The keys go back next to the built-in status and priority they showed in, completed todos
+-- keep a completed key and the others an active one
+ALTER TABLE todos
+    ADD COLUMN workflow_status TEXT,
+    ADD COLUMN workflow_priority TEXT;
+
+CREATE INDEX idx_todos_workspace_workflow_status ON todos(workspace_id, workflow_status) WHERE workflow_status IS NOT NULL;
+
+ALTER TABLE todos DISABLE TRIGGER USER;
+
+UPDATE todos t
+SET
+    workflow_status = CASE WHEN jsonb_array_length(w.statuses) > 0 THEN t.status END,
+    workflow_priority = CASE WHEN jsonb_array_length(w.priorities) > 0 THEN t.priority END
+FROM
+    workspace_workflows w
+WHERE
+    w.workspace_id = t.workspace_id;
+
+UPDATE todos
+SET
+    status = status_category,
+    priority = (ARRAY['low', 'medium', 'high'])[
+        CASE
+            WHEN jsonb_array_length(workflow_priorities(workspace_id)) < 2 THEN 2
+            ELSE (2 * priority_level + (jsonb_array_length(workflow_priorities(workspace_id)) - 1) / 2)
+                / (jsonb_array_length(workflow_priorities(workspace_id)) - 1) + 1
+        END
+    ];
+
+ALTER TABLE todos ENABLE TRIGGER USER;
+
+DROP INDEX idx_todos_workspace_status;
+DROP INDEX idx_todos_user_status_category;
+
+ALTER TABLE todos
+    DROP COLUMN status_category,
+    DROP COLUMN priority_level;
+
+DROP FUNCTION workflow_resolve_priority(JSONB, TEXT, INTEGER);
+DROP FUNCTION workflow_priority_level(JSONB, TEXT);
+DROP FUNCTION workflow_resolve_status(JSONB, TEXT, TEXT);
+DROP FUNCTION workflow_status_category(JSONB, TEXT);
+DROP FUNCTION workflow_priorities(TEXT);
+DROP FUNCTION workflow_statuses(TEXT);
+
+UPDATE workspace_workflows
+SET
+    statuses = (
+        SELECT
+            jsonb_agg(
+                (s - 'category') || jsonb_build_object('countsAsDone', s->>'category' IN ('completed', 'archived'))
+                ORDER BY ord
+            )
+        FROM
+            jsonb_array_elements(statuses) WITH ORDINALITY AS e(s, ord)
+    )
+WHERE
+    jsonb_array_length(statuses) > 0;
+
+-- The workflow functions and trigger of 048_workspace_workflows
+CREATE OR REPLACE FUNCTION workflow_status_done(statuses JSONB, status_key TEXT)
+RETURNS BOOLEAN AS $$
+    SELECT
+        (s->>'countsAsDone')::BOOLEAN
+    FROM
+        jsonb_array_elements(statuses) s
+    WHERE
+        s->>'key' = status_key
+    LIMIT
+        1;
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION workflow_status_key(statuses JSONB, status TEXT, current_key TEXT)
+RETURNS TEXT AS $$
+DECLARE
+    done BOOLEAN := workflow_status_done(statuses, current_key);
+BEGIN
+    IF done IS NOT NULL AND (status = 'archived' OR done = (status = 'completed')) THEN
+        RETURN current_key;
+    END IF;
+
+    RETURN (
+        SELECT
+            s->>'key'
+        FROM
+            jsonb_array_elements(statuses) WITH ORDINALITY AS e(s, ord)
+        WHERE
+            (s->>'countsAsDone')::BOOLEAN = (status IN ('completed', 'archived'))
+        ORDER BY
+            ord
+        LIMIT
+            1
+    );
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION workflow_priority_level(priorities JSONB, priority_key TEXT)
+RETURNS INTEGER AS $$
+    SELECT
+        (ord - 1)::INTEGER
+    FROM
+        jsonb_array_elements(priorities) WITH ORDINALITY AS e(p, ord)
+    WHERE
+        p->>'key' = priority_key
+    LIMIT
+        1;
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION workflow_level_priority(priority_level INTEGER, levels INTEGER)
+RETURNS TEXT AS $$
+    SELECT
+        (ARRAY['low', 'medium', 'high'])[
+            CASE
+                WHEN levels < 2 THEN 2
+                ELSE (2 * priority_level + (levels - 1) / 2) / (levels - 1) + 1
+            END
+        ];
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION workflow_priority_key(priorities JSONB, priority TEXT, current_key TEXT)
+RETURNS TEXT AS $$
+DECLARE
+    levels INTEGER := jsonb_array_length(priorities);
+    current_level INTEGER := workflow_priority_level(priorities, current_key);
+    priority_rank INTEGER := array_position(ARRAY['low', 'medium', 'high'], priority);
+BEGIN
+    IF current_level IS NOT NULL AND workflow_level_priority(current_level, levels) = priority THEN
+        RETURN current_key;
+    END IF;
+
+    RETURN (
+        SELECT
+            p->>'key'
+        FROM
+            jsonb_array_elements(priorities) WITH ORDINALITY AS e(p, ord)
+        ORDER BY
+            array_position(ARRAY['low', 'medium', 'high'],
+                workflow_level_priority((ord - 1)::INTEGER, levels)) < priority_rank,
+            ord
+        LIMIT
+            1
+    );
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION trigger_sync_workflow()
+RETURNS TRIGGER AS $$
+DECLARE
+    workflow workspace_workflows%ROWTYPE;
+    status_done BOOLEAN;
+    priority_level INTEGER;
+BEGIN
+    SELECT * INTO workflow FROM workspace_workflows WHERE workspace_id = NEW.workspace_id;
+    IF NOT FOUND THEN
+        NEW.workflow_status = NULL;
+        NEW.workflow_priority = NULL;
+        RETURN NEW;
+    END IF;
+
+    status_done = workflow_status_done(workflow.statuses, NEW.workflow_status);
+    IF status_done IS NOT NULL AND TG_OP = 'UPDATE'
+        AND NEW.workflow_status IS DISTINCT FROM OLD.workflow_status
+        AND NEW.status = OLD.status
+    THEN
+        IF status_done AND NEW.status IN ('draft', 'active') THEN
+            NEW.status = 'completed';
+            NEW.completed_at = NOW();
+        ELSIF NOT status_done AND NEW.status = 'completed' THEN
+            NEW.status = 'active';
+            NEW.completed_at = NULL;
+        END IF;
+    ELSE
+        NEW.workflow_status = workflow_status_key(workflow.statuses, NEW.status, NEW.workflow_status);
+    END IF;
+
+    priority_level = workflow_priority_level(workflow.priorities, NEW.workflow_priority);
+    IF priority_level IS NOT NULL AND TG_OP = 'UPDATE'
+        AND NEW.workflow_priority IS DISTINCT FROM OLD.workflow_priority
+        AND NEW.priority = OLD.priority
+    THEN
+        NEW.priority = workflow_level_priority(priority_level, jsonb_array_length(workflow.priorities));
+    ELSE
+        NEW.workflow_priority = workflow_priority_key(workflow.priorities, NEW.priority, NEW.workflow_priority);
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER sync_workflow_todos
+    BEFORE INSERT OR UPDATE OF status, priority, workflow_status, workflow_priority, workspace_id ON todos
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_sync_workflow();
@@ -0,0 +1,21 @@
+-- Per user overrides of the attachment storage quota, users without a row follow the
+-- attachments config
+CREATE TABLE storage_quotas(
+    user_id TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- 0 lifts the quota
+    quota_bytes BIGINT NOT NULL CHECK (quota_bytes >= 0),
+    -- The admin who set the quota
+    updated_by TEXT NOT NULL
+);
+
+CREATE TRIGGER set_updated_at_storage_quotas
+    BEFORE UPDATE ON storage_quotas
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE storage_quotas;
@@ -0,0 +1,475 @@
+// Package migrations applies the embedded SQL migrations and guards the schema they produce.
+// Every applied migration is recorded with a checksum of its up SQL, and the schema is
+// fingerprinted after every run. Editing an applied migration or changing the schema by hand
+// is reported as drift.
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	tern "github.com/jackc/tern/v2/migrate"
+	"github.com/rs/zerolog"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+const (
+	versionTable = "schema_version"
+	historyTable = "schema_migrations"
+)
+
+// lockKey serializes runners across instances, the schema must not change between applying
+// migrations and fingerprinting the result
+const lockKey = 7_254_118_003
+
+// Runner applies migrations over a dedicated connection
+type Runner struct {
+	conn     *pgx.Conn
+	migrator *tern.Migrator
+	logger   *zerolog.Logger
+}
+
+// Applied is a migration recorded in the history
+type Applied struct {
+	Version  int32
+	Name     string
+	Checksum string
+}
+
+// Status compares the database with the embedded migrations
+type Status struct {
+	Current int32
+	Latest  int32
+	// Pending are the names of the migrations not applied yet
+	Pending []string
+	// Edited are the names of applied migrations whose SQL changed since
+	Edited []string
+	// SchemaChanged is set when the live schema differs from the one the migrations left
+	SchemaChanged bool
+	// Unrecorded is set when the database was migrated before the history was kept
+	Unrecorded bool
+}
+
+// Drifted tells whether the live schema no longer matches the applied migrations
+func (s *Status) Drifted() bool {
+	return len(s.Edited) > 0 || s.SchemaChanged
+}
+
+// DriftError refuses to work on a schema that drifted from its migrations
+type DriftError struct {
+	Status *Status
+}
+
+func (e *DriftError) Error() string {
+	var problems []string
+	if len(e.Status.Edited) > 0 {
+		problems = append(problems, "applied migrations were edited: "+strings.Join(e.Status.Edited, ", "))
+	}
+	if e.Status.SchemaChanged {
+		problems = append(problems, "the schema was changed outside of migrations")
+	}
+	return fmt.Sprintf("database schema drifted at version %d: %s", e.Status.Current, strings.Join(problems, "; "))
+}
+
+// New loads the embedded migrations for a connection, the caller closes the connection
+func New(ctx context.Context, conn *pgx.Conn, logger *zerolog.Logger) (*Runner, error) {
+	migrator, err := tern.NewMigrator(ctx, conn, versionTable)
+	if err != nil {
+		return nil, fmt.Errorf("constructing database migrator: %w", err)
+	}
+	if err := migrator.LoadMigrations(files); err != nil {
+		return nil, fmt.Errorf("loading database migrations: %w", err)
+	}
+
+	migrator.OnStart = func(sequence int32, name, direction, _ string) {
+		logger.Info().Int32("version", sequence).Str("name", name).Str("direction", direction).Msg("running migration")
+	}
+
+	_, err = conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS `+historyTable+`(
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			-- Set on the latest version only, the schema it left behind
+			schema_fingerprint TEXT
+		)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("creating migration history table: %w", err)
+	}
+
+	return &Runner{
+		conn:     conn,
+		migrator: migrator,
+		logger:   logger,
+	}, nil
+}
+
+// Status reports pending migrations and drift
+func (r *Runner) Status(ctx context.Context) (*Status, error) {
+	var status *Status
+	err := r.locked(ctx, func() (err error) {
+		status, err = r.status(ctx)
+		return err
+	})
+	return status, err
+}
+
+// Verify fails with a DriftError when the schema drifted. A database migrated before the
+// history was kept is adopted as it is.
+func (r *Runner) Verify(ctx context.Context) (*Status, error) {
+	var status *Status
+	err := r.locked(ctx, func() (err error) {
+		status, err = r.verify(ctx)
+		return err
+	})
+	return status, err
+}
+
+// Up applies the pending migrations. It refuses to when the schema drifted, migrations
+// written against the expected schema could do damage on another one.
+func (r *Runner) Up(ctx context.Context) (*Status, error) {
+	return r.migrateTo(ctx, -1)
+}
+
+// Down reverts the migrations above target. Migrations without a down section can't be reverted.
+func (r *Runner) Down(ctx context.Context, target int32) (*Status, error) {
+	return r.migrateTo(ctx, target)
+}
+
+// Accept records the live schema and the current migration files as the expected ones, after
+// an operator reviewed a change made by hand
+func (r *Runner) Accept(ctx context.Context) (*Status, error) {
+	var status *Status
+	err := r.locked(ctx, func() error {
+		current, err := r.migrator.GetCurrentVersion(ctx)
+		if err != nil {
+			return fmt.Errorf("retrieving current database migration version: %w", err)
+		}
+
+		if _, err := r.conn.Exec(ctx, "DELETE FROM "+historyTable); err != nil {
+			return fmt.Errorf("clearing migration history: %w", err)
+		}
+		if err := r.record(ctx, 0, current); err != nil {
+			return err
+		}
+
+		status, err = r.status(ctx)
+		return err
+	})
+	return status, err
+}
+
+// Partition converts todos to a table hash partitioned by user_id, see partition_todos, and
+// records the partitioned schema as the expected one. It refuses to on a drifted schema.
+func (r *Runner) Partition(ctx context.Context, partitionCount int) (*Status, error) {
+	var status *Status
+	err := r.locked(ctx, func() error {
+		before, err := r.verify(ctx)
+		if err != nil {
+			return err
+		}
+
+		if _, err := r.conn.Exec(ctx, "CALL partition_todos($1)", partitionCount); err != nil {
+			return fmt.Errorf("partitioning todos: %w", err)
+		}
+		if err := r.record(ctx, before.Current, before.Current); err != nil {
+			return err
+		}
+
+		r.logger.Info().Int("partitions", partitionCount).Msg("partitioned todos")
+
+		status, err = r.status(ctx)
+		return err
+	})
+	return status, err
+}
+
+func (r *Runner) migrateTo(ctx context.Context, target int32) (*Status, error) {
+	var status *Status
+	err := r.locked(ctx, func() error {
+		before, err := r.verify(ctx)
+		if err != nil {
+			return err
+		}
+
+		if target < 0 {
+			// A database ahead of this build was migrated by a newer one, leave it be
+			target = max(before.Latest, before.Current)
+		}
+		if target == before.Current {
+			status = before
+			return nil
+		}
+
+		migrateErr := r.migrator.MigrateTo(ctx, target)
+
+		// Whatever got applied before a failure is recorded, so the next run doesn't see it as drift
+		current, err := r.migrator.GetCurrentVersion(ctx)
+		if err != nil {
+			return errors.Join(migrateErr, fmt.Errorf("retrieving current database migration version: %w", err))
+		}
+		if current < before.Current {
+			_, err = r.conn.Exec(ctx, "DELETE FROM "+historyTable+" WHERE version > $1", current)
+			if err != nil {
+				migrateErr = errors.Join(migrateErr, fmt.Errorf("pruning migration history: %w", err))
+			}
+		}
+		if err := r.record(ctx, min(before.Current, current), current); err != nil {
+			return errors.Join(migrateErr, err)
+		}
+		if migrateErr != nil {
+			return migrateErr
+		}
+
+		r.logger.Info().Int32("from", before.Current).Int32("to", current).Msg("migrated database schema")
+
+		status, err = r.status(ctx)
+		return err
+	})
+	return status, err
+}
+
+func (r *Runner) verify(ctx context.Context) (*Status, error) {
+	status, err := r.status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if status.Unrecorded {
+		r.logger.Warn().Int32("version", status.Current).Msg("adopting migrations applied before the history was kept")
+		if err := r.record(ctx, 0, status.Current); err != nil {
+			return nil, err
+		}
+		return r.status(ctx)
+	}
+
+	if status.Drifted() {
+		return status, &DriftError{Status: status}
+	}
+
+	return status, nil
+}
+
+func (r *Runner) status(ctx context.Context) (*Status, error) {
+	current, err := r.migrator.GetCurrentVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving current database migration version: %w", err)
+	}
+
+	status := &Status{
+		Current: current,
+		Latest:  int32(len(r.migrator.Migrations)),
+		Pending: []string{},
+		Edited:  []string{},
+	}
+
+	for _, migration := range r.migrator.Migrations[min(int(current), len(r.migrator.Migrations)):] {
+		status.Pending = append(status.Pending, migration.Name)
+	}
+
+	applied, err := r.history(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if current > 0 && len(applied) == 0 {
+		status.Unrecorded = true
+		return status, nil
+	}
+
+	for _, migration := range r.migrator.Migrations[:min(int(current), len(r.migrator.Migrations))] {
+		recorded, ok := applied[migration.Sequence]
+		if !ok || recorded.Checksum != checksum(migration) {
+			status.Edited = append(status.Edited, migration.Name)
+		}
+	}
+
+	if current > 0 {
+		var expected *string
+		err := r.conn.QueryRow(ctx, "SELECT schema_fingerprint FROM "+historyTable+" WHERE version = $1", current).
+			Scan(&expected)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("reading schema fingerprint: %w", err)
+		}
+
+		live, err := r.fingerprint(ctx)
+		if err != nil {
+			return nil, err
+		}
+		status.SchemaChanged = expected == nil || *expected != live
+	}
+
+	return status, nil
+}
+
+func (r *Runner) history(ctx context.Context) (map[int32]Applied, error) {
+	rows, err := r.conn.Query(ctx, "SELECT version, name, checksum FROM "+historyTable)
+	if err != nil {
+		return nil, fmt.Errorf("reading migration history: %w", err)
+	}
+
+	applied, err := pgx.CollectRows(rows, pgx.RowToStructByPos[Applied])
+	if err != nil {
+		return nil, fmt.Errorf("collecting migration history: %w", err)
+	}
+
+	history := make(map[int32]Applied, len(applied))
+	for _, migration := range applied {
+		history[migration.Version] = migration
+	}
+	return history, nil
+}
+
+// record stores the migrations in (from, to] and the fingerprint of the schema at to. Versions
+// of a newer build are beyond the loaded migrations, only the fingerprint is stored for them.
+func (r *Runner) record(ctx context.Context, from, to int32) error {
+	loaded := int32(len(r.migrator.Migrations))
+	for _, migration := range r.migrator.Migrations[min(from, loaded):min(to, loaded)] {
+		_, err := r.conn.Exec(ctx, `
+			INSERT INTO `+historyTable+` (version, name, checksum)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (version) DO UPDATE
+			SET
+				name = EXCLUDED.name,
+				checksum = EXCLUDED.checksum,
+				applied_at = NOW()
+		`, migration.Sequence, migration.Name, checksum(migration))
+		if err != nil {
+			return fmt.Errorf("recording migration %s: %w", migration.Name, err)
+		}
+	}
+
+	if to == 0 {
+		return nil
+	}
+
+	fingerprint, err := r.fingerprint(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.conn.Exec(ctx, "UPDATE "+historyTable+" SET schema_fingerprint = (CASE WHEN version = $1 THEN $2 END)",
+		to, fingerprint)
+	if err != nil {
+		return fmt.Errorf("recording schema fingerprint: %w", err)
+	}
+
+	return nil
+}
+
+// fingerprint hashes the definitions of the tables, indexes, constraints, triggers, routines
+// and views of the public schema. Extension objects and the migration bookkeeping are left out.
+func (r *Runner) fingerprint(ctx context.Context) (string, error) {
+	stmt := `
+		WITH
+			items AS (
+				SELECT
+					format('column %s.%s %s %s %s', c.table_name, c.column_name, c.data_type, c.is_nullable, COALESCE(c.column_default, '')) AS item
+				FROM
+					information_schema.columns c
+				WHERE
+					c.table_schema = 'public'
+					AND c.table_name::text <> ALL (@excluded::text[])
+				UNION ALL
+				SELECT
+					format('index %s', i.indexdef)
+				FROM
+					pg_indexes i
+				WHERE
+					i.schemaname = 'public'
+					AND i.tablename::text <> ALL (@excluded::text[])
+				UNION ALL
+				SELECT
+					format('constraint %s.%s %s', con.conrelid::regclass, con.conname, pg_get_constraintdef(con.oid))
+				FROM
+					pg_constraint con
+					JOIN pg_class rel ON rel.oid = con.conrelid
+					JOIN pg_namespace n ON n.oid = rel.relnamespace
+				WHERE
+					n.nspname = 'public'
+					AND rel.relname::text <> ALL (@excluded::text[])
+				UNION ALL
+				SELECT
+					format('trigger %s', pg_get_triggerdef(t.oid))
+				FROM
+					pg_trigger t
+					JOIN pg_class rel ON rel.oid = t.tgrelid
+					JOIN pg_namespace n ON n.oid = rel.relnamespace
+				WHERE
+					n.nspname = 'public'
+					AND NOT t.tgisinternal
+				UNION ALL
+				SELECT
+					format('routine %s %s', p.oid::regprocedure, md5(pg_get_functiondef(p.oid)))
+				FROM
+					pg_proc p
+					JOIN pg_namespace n ON n.oid = p.pronamespace
+				WHERE
+					n.nspname = 'public'
+					AND p.prokind IN ('f', 'p')
+					AND NOT EXISTS (
+						SELECT
+							1
+						FROM
+							pg_depend d
+						WHERE
+							d.classid = 'pg_proc'::regclass
+							AND d.objid = p.oid
+							AND d.deptype = 'e'
+					)
+				UNION ALL
+				SELECT
+					format('view %s %s', c.oid::regclass, md5(pg_get_viewdef(c.oid)))
+				FROM
+					pg_class c
+					JOIN pg_namespace n ON n.oid = c.relnamespace
+				WHERE
+					n.nspname = 'public'
+					AND c.relkind IN ('v', 'm')
+			)
+		SELECT
+			md5(COALESCE(string_agg(item, E'\n' ORDER BY item), ''))
+		FROM
+			items
+	`
+
+	var fingerprint string
+	err := r.conn.QueryRow(ctx, stmt, pgx.NamedArgs{
+		"excluded": []string{versionTable, historyTable},
+	}).Scan(&fingerprint)
+	if err != nil {
+		return "", fmt.Errorf("fingerprinting database schema: %w", err)
+	}
+
+	return fingerprint, nil
+}
+
+// locked runs fn holding the migration lock, concurrent runners wait for each other
+func (r *Runner) locked(ctx context.Context, fn func() error) (err error) {
+	if _, err := r.conn.Exec(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("acquiring migration lock: %w", err)
+	}
+	defer func() {
+		if _, unlockErr := r.conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", lockKey); unlockErr != nil {
+			err = errors.Join(err, fmt.Errorf("releasing migration lock: %w", unlockErr))
+		}
+	}()
+
+	return fn()
+}
+
+// checksum covers the up SQL only, adding a down section to an applied migration is no drift
+func checksum(migration *tern.Migration) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(migration.UpSQL)))
+	return hex.EncodeToString(sum[:])
+}
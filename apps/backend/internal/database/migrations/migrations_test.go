@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var todoOwnerReference = regexp.MustCompile(`REFERENCES todos\s*\(\s*id\s*,\s*user_id\s*\)`)
+
+// TestTodoOwnerReferencesHaveKey checks that no migration references todos through
+// (id, user_id) before the pair has a unique key, a fresh database fails to migrate otherwise
+func TestTodoOwnerReferencesHaveKey(t *testing.T) {
+	names, err := fs.Glob(files, "*.sql")
+	require.NoError(t, err)
+	sort.Strings(names)
+
+	keyed := false
+	for _, name := range names {
+		content, err := fs.ReadFile(files, name)
+		require.NoError(t, err)
+
+		up, _, _ := strings.Cut(string(content), "---- create above / drop below ----")
+		key := strings.Index(up, "CONSTRAINT todos_id_user_id_key UNIQUE (id, user_id)")
+
+		for _, loc := range todoOwnerReference.FindAllStringIndex(up, -1) {
+			// partition_todos recreates the references it collects, it doesn't write any
+			if strings.Contains(up[:loc[0]], "CREATE OR REPLACE PROCEDURE partition_todos") {
+				continue
+			}
+			require.True(t, keyed || (key >= 0 && key < loc[0]),
+				"%s references todos (id, user_id) before the pair has a unique key", name)
+		}
+
+		if key >= 0 {
+			keyed = true
+		}
+	}
+	require.True(t, keyed, "no migration adds the todos (id, user_id) key")
+}
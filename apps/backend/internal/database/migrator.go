@@ -2,24 +2,21 @@ package database
 
 import (
 	"context"
-	"embed"
 	"fmt"
-	"io/fs"
 	"net"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/database/migrations"
 
 	"github.com/jackc/pgx/v5"
-	tern "github.com/jackc/tern/v2/migrate"
 	"github.com/rs/zerolog"
 )
 
-//go:embed migrations/*.sql
-var migrations embed.FS
-
-func Migrate(ctx context.Context, logger *zerolog.Logger, cfg *config.Config) error {
+// OpenMigrations connects a migration runner to the primary, the caller closes the connection
+func OpenMigrations(ctx context.Context, logger *zerolog.Logger, cfg *config.Config) (*migrations.Runner, *pgx.Conn, error) {
 	hostPort := net.JoinHostPort(cfg.Database.Host, strconv.Itoa(cfg.Database.Port))
 
 	// URL-encode the password
@@ -34,32 +31,59 @@ func Migrate(ctx context.Context, logger *zerolog.Logger, cfg *config.Config) er
 
 	conn, err := pgx.Connect(ctx, dsn)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	defer conn.Close(ctx)
 
-	m, err := tern.NewMigrator(ctx, conn, "schema_version")
+	runner, err := migrations.New(ctx, conn, logger)
 	if err != nil {
-		return fmt.Errorf("constructing database migrator: %w", err)
+		conn.Close(ctx)
+		return nil, nil, err
 	}
-	subtree, err := fs.Sub(migrations, "migrations")
+
+	return runner, conn, nil
+}
+
+// Migrate applies the pending migrations, it fails when the schema drifted
+func Migrate(ctx context.Context, logger *zerolog.Logger, cfg *config.Config) error {
+	runner, conn, err := OpenMigrations(ctx, logger, cfg)
 	if err != nil {
-		return fmt.Errorf("retrieving database migrations subtree: %w", err)
-	}
-	if err := m.LoadMigrations(subtree); err != nil {
-		return fmt.Errorf("loading database migrations: %w", err)
+		return err
 	}
-	from, err := m.GetCurrentVersion(ctx)
+	defer conn.Close(ctx)
+
+	status, err := runner.Up(ctx)
 	if err != nil {
-		return fmt.Errorf("retreiving current database migration version")
-	}
-	if err := m.Migrate(ctx); err != nil {
 		return err
 	}
-	if from == int32(len(m.Migrations)) {
-		logger.Info().Msgf("database schema up to date, version %d", len(m.Migrations))
-	} else {
-		logger.Info().Msgf("migrated database schema, from %d to %d", from, len(m.Migrations))
-	}
+
+	logger.Info().Msgf("database schema up to date, version %d", status.Current)
 	return nil
 }
+
+// PrepareSchema migrates or verifies the schema on startup, as configured
+func PrepareSchema(ctx context.Context, logger *zerolog.Logger, cfg *config.Config) error {
+	switch cfg.Database.Migrations.Mode {
+	case config.MigrationsModeMigrate:
+		return Migrate(ctx, logger, cfg)
+	case config.MigrationsModeVerify:
+		runner, conn, err := OpenMigrations(ctx, logger, cfg)
+		if err != nil {
+			return err
+		}
+		defer conn.Close(ctx)
+
+		status, err := runner.Verify(ctx)
+		if err != nil {
+			return err
+		}
+		if len(status.Pending) > 0 {
+			return fmt.Errorf("database schema at version %d has pending migrations: %s",
+				status.Current, strings.Join(status.Pending, ", "))
+		}
+
+		logger.Info().Msgf("database schema verified, version %d", status.Current)
+		return nil
+	default:
+		return nil
+	}
+}
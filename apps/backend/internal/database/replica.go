@@ -0,0 +1,204 @@
+package database
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	loggerConfig "github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const replicaCheckInterval = 5 * time.Second
+
+// replicaLagQuery reports how far a replica is behind, 0 when it has replayed everything it received
+const replicaLagQuery = `
+	SELECT
+		CASE
+			WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE COALESCE(EXTRACT(EPOCH FROM NOW() - pg_last_xact_replay_timestamp()), 0)
+		END
+`
+
+type replica struct {
+	host    string
+	pool    *pgxpool.Pool
+	healthy atomic.Bool
+}
+
+// readPolicyKey carries the ReadPolicy of a request in its context
+type readPolicyKey struct{}
+
+// ReadPolicy decides whether the reads of a request may be served by a replica.
+// Writes and reads that must see the caller's recent writes stay on the primary.
+type ReadPolicy struct {
+	primary bool
+	// recentWrite reports whether the caller wrote recently, it is only asked once
+	recentWrite func() bool
+	once        sync.Once
+	wrote       bool
+}
+
+// WithPrimary pins every read made with ctx to the primary
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readPolicyKey{}, &ReadPolicy{primary: true})
+}
+
+// WithReadYourWrites lets reads made with ctx use a replica unless recentWrite reports
+// that the caller wrote within the replica lag window
+func WithReadYourWrites(ctx context.Context, recentWrite func() bool) context.Context {
+	return context.WithValue(ctx, readPolicyKey{}, &ReadPolicy{recentWrite: recentWrite})
+}
+
+func usePrimary(ctx context.Context) bool {
+	policy, ok := ctx.Value(readPolicyKey{}).(*ReadPolicy)
+	if !ok {
+		return false
+	}
+
+	if policy.primary || policy.recentWrite == nil {
+		return policy.primary
+	}
+
+	policy.once.Do(func() {
+		policy.wrote = policy.recentWrite()
+	})
+	return policy.wrote
+}
+
+// Reader returns what heavy read queries should run on: the transaction carried by ctx,
+// a healthy replica when the read policy of ctx allows it, the primary otherwise
+func (db *Database) Reader(ctx context.Context) Querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	if len(db.replicas) == 0 || usePrimary(ctx) {
+		return db.Pool
+	}
+
+	// Round robin over the replicas, skipping the ones that lag or are down
+	start := db.next.Add(1)
+	for i := range db.replicas {
+		candidate := db.replicas[(start+uint64(i))%uint64(len(db.replicas))]
+		if candidate.healthy.Load() {
+			return candidate.pool
+		}
+	}
+
+	return db.Pool
+}
+
+// HasReplicas reports whether any replica is configured
+func (db *Database) HasReplicas() bool {
+	return len(db.replicas) > 0
+}
+
+// ReplicasInRotation returns how many replicas currently serve reads and how many are configured
+func (db *Database) ReplicasInRotation() (healthy, total int) {
+	for _, r := range db.replicas {
+		if r.healthy.Load() {
+			healthy++
+		}
+	}
+	return healthy, len(db.replicas)
+}
+
+// ReplicaPoolStats returns the connection pool statistics of every replica, keyed by host
+func (db *Database) ReplicaPoolStats() map[string]*pgxpool.Stat {
+	stats := make(map[string]*pgxpool.Stat, len(db.replicas))
+	for _, r := range db.replicas {
+		stats[r.host] = r.pool.Stat()
+	}
+	return stats
+}
+
+func (db *Database) connectReplicas(cfg *config.Config, loggerService *loggerConfig.LoggerService) error {
+	replicaConfig := cfg.Database.Replicas
+	if replicaConfig == nil || len(replicaConfig.Hosts) == 0 {
+		return nil
+	}
+
+	db.maxLag = time.Duration(replicaConfig.MaxLagSeconds) * time.Second
+
+	for _, host := range replicaConfig.Hosts {
+		hostPort := host
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			hostPort = net.JoinHostPort(host, strconv.Itoa(cfg.Database.Port))
+		}
+
+		// A replica that is down must not keep the API from starting
+		pool, err := newPool(cfg, db.log, loggerService, hostPort)
+		if err != nil {
+			db.log.Error().Err(err).Str("replica", hostPort).Msg("failed to connect to database replica, skipping it")
+			continue
+		}
+
+		r := &replica{host: hostPort, pool: pool}
+		r.healthy.Store(true)
+		db.replicas = append(db.replicas, r)
+	}
+
+	if len(db.replicas) == 0 {
+		db.log.Warn().Msg("no database replica is reachable, serving all reads from the primary")
+		return nil
+	}
+
+	db.log.Info().Int("replicas", len(db.replicas)).Msg("connected to the database replicas")
+
+	db.stopMonitor = make(chan struct{})
+	go db.monitorReplicas()
+
+	return nil
+}
+
+// monitorReplicas takes replicas that are down or lag too far behind out of rotation
+// and puts them back once they catch up
+func (db *Database) monitorReplicas() {
+	ticker := time.NewTicker(replicaCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-db.stopMonitor:
+			return
+		case <-ticker.C:
+			for _, r := range db.replicas {
+				db.checkReplica(r)
+			}
+		}
+	}
+}
+
+func (db *Database) checkReplica(r *replica) {
+	ctx, cancel := context.WithTimeout(context.Background(), replicaCheckInterval)
+	defer cancel()
+
+	var lagSeconds float64
+	err := r.pool.QueryRow(ctx, replicaLagQuery).Scan(&lagSeconds)
+	lag := time.Duration(lagSeconds * float64(time.Second))
+	healthy := err == nil && lag <= db.maxLag
+
+	if healthy == r.healthy.Swap(healthy) {
+		return
+	}
+
+	if healthy {
+		db.log.Info().Str("replica", r.host).Dur("lag", lag).Msg("database replica back in rotation")
+	} else {
+		db.log.Warn().Err(err).Str("replica", r.host).Dur("lag", lag).Msg("database replica out of rotation")
+	}
+}
+
+func (db *Database) closeReplicas() {
+	if db.stopMonitor != nil {
+		close(db.stopMonitor)
+	}
+	for _, r := range db.replicas {
+		r.pool.Close()
+	}
+}
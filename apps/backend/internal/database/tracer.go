@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/tracing"
+	loggerConfig "github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxLoggedSQL caps the statement text written to the slow query log
+const maxLoggedSQL = 1000
+
+type queryTraceKey struct{}
+
+type queryTrace struct {
+	start  time.Time
+	sql    string
+	caller string
+	span   trace.Span
+}
+
+// queryTracer times every query and attributes it to the repository method that issued it.
+// Queries slower than threshold are logged, durations and row counts are recorded as
+// New Relic custom metrics which aggregate into a per method distribution. Each query is
+// also an OpenTelemetry span named after that method, nested under the request's span.
+type queryTracer struct {
+	log       *zerolog.Logger
+	app       *newrelic.Application
+	threshold time.Duration
+}
+
+// TraceQueryStart implements pgx tracer interface
+func (qt *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	caller := queryCaller()
+
+	ctx, span := tracing.Tracer().Start(ctx, caller,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBQueryText(data.SQL),
+		),
+	)
+
+	return context.WithValue(ctx, queryTraceKey{}, &queryTrace{
+		start:  time.Now(),
+		sql:    data.SQL,
+		caller: caller,
+		span:   span,
+	})
+}
+
+// TraceQueryEnd implements pgx tracer interface
+func (qt *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	query, ok := ctx.Value(queryTraceKey{}).(*queryTrace)
+	if !ok {
+		return
+	}
+
+	duration := time.Since(query.start)
+	rows := data.CommandTag.RowsAffected()
+
+	query.span.SetAttributes(attribute.Int64("db.response.returned_rows", rows))
+	tracing.End(query.span, data.Err)
+
+	if qt.app != nil {
+		qt.app.RecordCustomMetric("Custom/Database/"+query.caller+"/Duration", float64(duration.Microseconds())/1000)
+		qt.app.RecordCustomMetric("Custom/Database/"+query.caller+"/Rows", float64(rows))
+	}
+
+	if qt.threshold == 0 || duration < qt.threshold {
+		return
+	}
+
+	sql := strings.Join(strings.Fields(query.sql), " ")
+	if len(sql) > maxLoggedSQL {
+		sql = sql[:maxLoggedSQL] + "..."
+	}
+
+	log := loggerConfig.WithSpanContext(*qt.log, ctx)
+	event := log.Warn()
+	if data.Err != nil {
+		event = event.Err(data.Err)
+	}
+	event.
+		Str("event", "slow_query").
+		Str("caller", query.caller).
+		Dur("duration", duration).
+		Int64("rows", rows).
+		Str("sql", sql).
+		Msg("slow database query")
+}
+
+// queryCaller returns the first function up the stack outside pgx and this package,
+// e.g. repository.TodoRepository.GetTodos
+func queryCaller() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+		function := frame.Function
+		if function != "" &&
+			!strings.Contains(function, "github.com/jackc/") &&
+			!strings.Contains(function, "/internal/database.") {
+			return callerName(function)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// callerName shortens a fully qualified function name to package.Type.Method, folding closures
+// into the function that declares them
+func callerName(function string) string {
+	name := function[strings.LastIndex(function, "/")+1:]
+	name = strings.NewReplacer("(*", "", ")", "").Replace(name)
+
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if i > 1 && (strings.HasPrefix(part, "func") || isDigits(part)) {
+			parts = parts[:i]
+			break
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
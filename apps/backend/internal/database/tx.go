@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// txKey carries the transaction of a unit of work in its context
+type txKey struct{}
+
+// Querier runs statements, either on a pool or on the transaction of a unit of work
+type Querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+var (
+	_ Querier = (*pgxpool.Pool)(nil)
+	_ Querier = (pgx.Tx)(nil)
+)
+
+// Writer returns what writes and reads that must see them run on: the transaction carried
+// by ctx, the primary otherwise
+func (db *Database) Writer(ctx context.Context) Querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return db.Pool
+}
+
+// TxManager runs units of work spanning several repositories in one transaction
+type TxManager struct {
+	db *Database
+}
+
+func NewTxManager(db *Database) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTx runs fn in a transaction, committed when fn returns nil and rolled back otherwise.
+// Repositories called with the ctx given to fn run on the transaction. A nested call runs in
+// a savepoint of the outer transaction, so its failure can be handled without aborting it.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	var beginner interface {
+		Begin(ctx context.Context) (pgx.Tx, error)
+	} = m.db.Pool
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		beginner = tx
+	}
+
+	return pgx.BeginFunc(ctx, beginner, func(tx pgx.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
@@ -0,0 +1,291 @@
+package errs
+
+import (
+	"net/http"
+	"sort"
+)
+
+// Error codes are part of the API contract: clients branch on them, so a code is never
+// renamed or reused for a different condition. Codes of database constraint errors are
+// derived from the table, see sqlerr.
+const (
+	// Generic codes, one per HTTP status the API answers with
+	CodeBadRequest           = "BAD_REQUEST"
+	CodeUnauthorized         = "UNAUTHORIZED"
+	CodePaymentRequired      = "PAYMENT_REQUIRED"
+	CodeForbidden            = "FORBIDDEN"
+	CodeNotFound             = "NOT_FOUND"
+	CodeMethodNotAllowed     = "METHOD_NOT_ALLOWED"
+	CodeConflict             = "CONFLICT"
+	CodeGone                 = "GONE"
+	CodePreconditionFailed   = "PRECONDITION_FAILED"
+	CodeRequestTooLarge      = "REQUEST_ENTITY_TOO_LARGE"
+	CodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+	CodeTooManyRequests      = "TOO_MANY_REQUESTS"
+	CodeInternal             = "INTERNAL_SERVER_ERROR"
+	CodeServiceUnavailable   = "SERVICE_UNAVAILABLE"
+	CodeGatewayTimeout       = "GATEWAY_TIMEOUT"
+
+	// Request handling
+	CodeValidationFailed     = "VALIDATION_FAILED"
+	CodeRequestTimeout       = "REQUEST_TIMEOUT"
+	CodeQueryTimeout         = "QUERY_TIMEOUT"
+	CodeTransactionConflict  = "TRANSACTION_CONFLICT"
+	CodeDatabaseBusy         = "DATABASE_BUSY"
+	CodeDependencyDown       = "DEPENDENCY_UNAVAILABLE"
+	CodeResponseTooLarge     = "RESPONSE_TOO_LARGE"
+	CodeETagMismatch         = "ETAG_MISMATCH"
+	CodeIdempotencyKeyReused = "IDEMPOTENCY_KEY_REUSED"
+	CodeIdempotencyKeyInUse  = "IDEMPOTENCY_KEY_IN_USE"
+	CodeCursorExpired        = "CURSOR_EXPIRED"
+
+	// Server state
+	CodeMaintenance     = "MAINTENANCE"
+	CodeReadOnly        = "READ_ONLY"
+	CodeFeatureDisabled = "FEATURE_DISABLED"
+
+	CodeFaultInjectionDisabled = "FAULT_INJECTION_DISABLED"
+
+	// Domain
+	CodeTodoNotFound               = "TODO_NOT_FOUND"
+	CodeTodoVersionConflict        = "TODO_VERSION_CONFLICT"
+	CodeTodoVersionNotFound        = "TODO_VERSION_NOT_FOUND"
+	CodeTodoNotCompleted           = "TODO_NOT_COMPLETED"
+	CodeUndoWindowExpired          = "UNDO_WINDOW_EXPIRED"
+	CodeAttachmentNotFound         = "ATTACHMENT_NOT_FOUND"
+	CodeSemanticSearchDisabled     = "SEMANTIC_SEARCH_DISABLED"
+	CodeSuggestionsDisabled        = "SUGGESTIONS_DISABLED"
+	CodeFeatureFlagNotFound        = "FEATURE_FLAG_NOT_FOUND"
+	CodeFeatureOverrideNotFound    = "FEATURE_OVERRIDE_NOT_FOUND"
+	CodeProfileNotFound            = "PROFILE_NOT_FOUND"
+	CodeConfigInvalid              = "CONFIG_INVALID"
+	CodeWorkspaceRequired          = "WORKSPACE_REQUIRED"
+	CodeExportNotFound             = "EXPORT_NOT_FOUND"
+	CodeExportInProgress           = "EXPORT_IN_PROGRESS"
+	CodeExportTooLarge             = "EXPORT_TOO_LARGE"
+	CodeRetentionPolicyNotFound    = "RETENTION_POLICY_NOT_FOUND"
+	CodeStorageQuotaExceeded       = "STORAGE_QUOTA_EXCEEDED"
+	CodeBackupNotFound             = "BACKUP_NOT_FOUND"
+	CodeBackupInProgress           = "BACKUP_IN_PROGRESS"
+	CodeBackupNotCompleted         = "BACKUP_NOT_COMPLETED"
+	CodeRestoreNotFound            = "RESTORE_NOT_FOUND"
+	CodeRestoreInProgress          = "RESTORE_IN_PROGRESS"
+	CodeWorkspaceNotEmpty          = "WORKSPACE_NOT_EMPTY"
+	CodeRuleNotFound               = "RULE_NOT_FOUND"
+	CodeRuleLimitReached           = "RULE_LIMIT_REACHED"
+	CodeFocusSessionNotFound       = "FOCUS_SESSION_NOT_FOUND"
+	CodeFocusSessionOpen           = "FOCUS_SESSION_OPEN"
+	CodeFocusSessionState          = "FOCUS_SESSION_INVALID_STATE"
+	CodeGoalNotFound               = "GOAL_NOT_FOUND"
+	CodeDependencyNotFound         = "DEPENDENCY_NOT_FOUND"
+	CodeDependencyExists           = "DEPENDENCY_EXISTS"
+	CodeDependencyCycle            = "DEPENDENCY_CYCLE"
+	CodeInboxItemNotFound          = "INBOX_ITEM_NOT_FOUND"
+	CodeTemplateNotFound           = "TEMPLATE_NOT_FOUND"
+	CodeTemplateExists             = "TEMPLATE_EXISTS"
+	CodeOnboardingKitNotFound      = "ONBOARDING_KIT_NOT_FOUND"
+	CodeWorkflowNotFound           = "WORKFLOW_NOT_FOUND"
+	CodeAgingPolicyNotFound        = "AGING_POLICY_NOT_FOUND"
+	CodeAgingPolicyExists          = "AGING_POLICY_EXISTS"
+	CodeCoverNotFound              = "COVER_NOT_FOUND"
+	CodeAttachmentTypeForbidden    = "ATTACHMENT_TYPE_FORBIDDEN"
+	CodeThumbnailNotFound          = "THUMBNAIL_NOT_FOUND"
+	CodeAttachmentQuarantined      = "ATTACHMENT_QUARANTINED"
+	CodeAttachmentInfected         = "ATTACHMENT_INFECTED"
+	CodeNoteNotFound               = "NOTE_NOT_FOUND"
+	CodeMagicTagNotFound           = "MAGIC_TAG_NOT_FOUND"
+	CodeMagicTagLimitReached       = "MAGIC_TAG_LIMIT_REACHED"
+	CodeInviteNotFound             = "INVITE_NOT_FOUND"
+	CodeInviteExpired              = "INVITE_EXPIRED"
+	CodeInviteUsedUp               = "INVITE_USED_UP"
+	CodeInviteDomainNotAllowed     = "INVITE_DOMAIN_NOT_ALLOWED"
+	CodeInviteAlreadyAccepted      = "INVITE_ALREADY_ACCEPTED"
+	CodeWorkspaceFrozen            = "WORKSPACE_FROZEN"
+	CodeWorkspaceNotFrozen         = "WORKSPACE_NOT_FROZEN"
+	CodeWorkspacePurged            = "WORKSPACE_PURGED"
+	CodeIncidentNotFound           = "INCIDENT_NOT_FOUND"
+	CodeAPIKeyNotFound             = "API_KEY_NOT_FOUND"
+	CodeAPIKeyLimitReached         = "API_KEY_LIMIT_REACHED"
+	CodeAPIKeysDisabled            = "API_KEYS_DISABLED"
+	CodeAPIPlanNotFound            = "API_PLAN_NOT_FOUND"
+	CodeAPIKeyRateLimited          = "API_KEY_RATE_LIMITED"
+	CodeAPIQuotaExceeded           = "API_QUOTA_EXCEEDED"
+	CodeCookieSessionsDisabled     = "COOKIE_SESSIONS_DISABLED"
+	CodeAPIClientNotFound          = "API_CLIENT_NOT_FOUND"
+	CodeCSRFTokenInvalid           = "CSRF_TOKEN_INVALID"
+	CodeMyDayTodoNotFound          = "MY_DAY_TODO_NOT_FOUND"
+	CodeReportScheduleNotFound     = "REPORT_SCHEDULE_NOT_FOUND"
+	CodeReportScheduleLimitReached = "REPORT_SCHEDULE_LIMIT_REACHED"
+	CodeWebhookNotFound            = "WEBHOOK_NOT_FOUND"
+	CodeWebhookLimitReached        = "WEBHOOK_LIMIT_REACHED"
+	CodeStorageQuotaNotFound       = "STORAGE_QUOTA_NOT_FOUND"
+	CodeWorkspaceNotFound          = "WORKSPACE_NOT_FOUND"
+)
+
+// Definition describes an error code: the status it is answered with, whether repeating the
+// same request later can succeed, and the message shown when the error carries none that is
+// safe to show.
+type Definition struct {
+	Code      string `json:"code"`
+	Status    int    `json:"status"`
+	Retryable bool   `json:"retryable"`
+	Message   string `json:"message"`
+}
+
+var catalog = map[string]Definition{}
+
+func define(code string, status int, retryable bool, message string) {
+	catalog[code] = Definition{Code: code, Status: status, Retryable: retryable, Message: message}
+}
+
+func init() {
+	define(CodeBadRequest, http.StatusBadRequest, false, "The request is invalid")
+	define(CodeUnauthorized, http.StatusUnauthorized, false, "Authentication is required")
+	define(CodePaymentRequired, http.StatusPaymentRequired, false, "Your plan doesn't cover this request")
+	define(CodeForbidden, http.StatusForbidden, false, "You are not allowed to perform this action")
+	define(CodeNotFound, http.StatusNotFound, false, "Resource not found")
+	define(CodeMethodNotAllowed, http.StatusMethodNotAllowed, false, "The method is not allowed for this route")
+	define(CodeConflict, http.StatusConflict, false, "The request conflicts with the current state")
+	define(CodeGone, http.StatusGone, false, "The resource is no longer available")
+	define(CodePreconditionFailed, http.StatusPreconditionFailed, false, "A precondition of the request failed")
+	define(CodeRequestTooLarge, http.StatusRequestEntityTooLarge, false, "The request body is too large")
+	define(CodeUnsupportedMediaType, http.StatusUnsupportedMediaType, false, "The request content type is not supported")
+	define(CodeTooManyRequests, http.StatusTooManyRequests, true, "Too many requests, slow down and retry")
+	define(CodeInternal, http.StatusInternalServerError, false, "Something went wrong on our side")
+	define(CodeServiceUnavailable, http.StatusServiceUnavailable, true, "The service is temporarily unavailable")
+	define(CodeGatewayTimeout, http.StatusGatewayTimeout, true, "The request took too long to process")
+
+	define(CodeValidationFailed, http.StatusBadRequest, false, "Validation failed")
+	define(CodeRequestTimeout, http.StatusGatewayTimeout, true, "The request took too long to process")
+	define(CodeQueryTimeout, http.StatusGatewayTimeout, true, "The request took too long to process")
+	define(CodeTransactionConflict, http.StatusConflict, true, "The request raced with another change, retry it")
+	define(CodeDatabaseBusy, http.StatusServiceUnavailable, true, "The service is busy, retry shortly")
+	define(CodeDependencyDown, http.StatusServiceUnavailable, true,
+		"A service this request depends on is unavailable, retry shortly")
+	define(CodeResponseTooLarge, http.StatusInternalServerError, false,
+		"The response is larger than allowed, narrow the request with filters or pagination")
+	define(CodeETagMismatch, http.StatusPreconditionFailed, false, "The resource changed since it was read")
+	define(CodeIdempotencyKeyReused, http.StatusBadRequest, false,
+		"The idempotency key was used with a different request")
+	define(CodeIdempotencyKeyInUse, http.StatusConflict, true, "A request with this idempotency key is in progress")
+	define(CodeCursorExpired, http.StatusGone, false, "The cursor expired, start a full sync")
+
+	define(CodeMaintenance, http.StatusServiceUnavailable, true, "The service is down for maintenance")
+	define(CodeReadOnly, http.StatusServiceUnavailable, true, "The service is read-only for now")
+	define(CodeFeatureDisabled, http.StatusNotFound, false, "Resource not found")
+	define(CodeFaultInjectionDisabled, http.StatusConflict, false, "Fault injection is not enabled on this deployment")
+
+	define(CodeTodoNotFound, http.StatusNotFound, false, "Todo not found")
+	define(CodeTodoVersionConflict, http.StatusConflict, false, "The todo was changed by someone else")
+	define(CodeTodoVersionNotFound, http.StatusNotFound, false, "That version of the todo is no longer kept")
+	define(CodeTodoNotCompleted, http.StatusConflict, false, "The todo is not completed")
+	define(CodeUndoWindowExpired, http.StatusGone, false, "The completion can no longer be undone")
+	define(CodeAttachmentNotFound, http.StatusNotFound, false, "Attachment not found")
+	define(CodeSemanticSearchDisabled, http.StatusBadRequest, false, "Semantic search is not available")
+	define(CodeSuggestionsDisabled, http.StatusServiceUnavailable, false, "Subtask suggestions are not available")
+	define(CodeFeatureFlagNotFound, http.StatusNotFound, false, "Feature flag not found")
+	define(CodeFeatureOverrideNotFound, http.StatusNotFound, false, "Feature flag override not found")
+	define(CodeProfileNotFound, http.StatusNotFound, false, "Profile not found")
+	define(CodeConfigInvalid, http.StatusBadRequest, false, "The configuration is invalid")
+	define(CodeWorkspaceRequired, http.StatusBadRequest, false, "Select a workspace first")
+	define(CodeExportNotFound, http.StatusNotFound, false, "Export not found")
+	define(CodeExportInProgress, http.StatusConflict, false, "An export of your account is already in progress")
+	define(CodeExportTooLarge, http.StatusBadRequest, false, "Too many todos to print at once, request an export instead")
+	define(CodeRetentionPolicyNotFound, http.StatusNotFound, false, "Retention policy not found")
+	define(CodeStorageQuotaExceeded, http.StatusRequestEntityTooLarge, false,
+		"The upload would exceed your attachment storage quota")
+	define(CodeBackupNotFound, http.StatusNotFound, false, "Backup not found")
+	define(CodeBackupInProgress, http.StatusConflict, false, "A backup of this workspace is already in progress")
+	define(CodeBackupNotCompleted, http.StatusConflict, false, "Only a completed backup can be restored")
+	define(CodeRestoreNotFound, http.StatusNotFound, false, "Restore not found")
+	define(CodeRestoreInProgress, http.StatusConflict, false, "A restore into this workspace is already in progress")
+	define(CodeWorkspaceNotEmpty, http.StatusConflict, false, "A backup can only be restored into a workspace without todos")
+	define(CodeRuleNotFound, http.StatusNotFound, false, "Rule not found")
+	define(CodeRuleLimitReached, http.StatusConflict, false, "You have reached the maximum number of rules")
+	define(CodeFocusSessionNotFound, http.StatusNotFound, false, "Focus session not found")
+	define(CodeFocusSessionOpen, http.StatusConflict, false, "Another focus session is still open")
+	define(CodeFocusSessionState, http.StatusConflict, false, "The focus session can't change to that state")
+	define(CodeGoalNotFound, http.StatusNotFound, false, "No weekly goal is set")
+	define(CodeDependencyNotFound, http.StatusNotFound, false, "The todo doesn't depend on that todo")
+	define(CodeDependencyExists, http.StatusConflict, false, "The todo already depends on that todo")
+	define(CodeDependencyCycle, http.StatusConflict, false, "The dependency would make the todos block each other")
+	define(CodeInboxItemNotFound, http.StatusNotFound, false, "Inbox item not found")
+	define(CodeTemplateNotFound, http.StatusNotFound, false, "Template not found")
+	define(CodeTemplateExists, http.StatusConflict, false, "The workspace already has a template with that name")
+	define(CodeOnboardingKitNotFound, http.StatusNotFound, false, "The workspace has no onboarding kit")
+	define(CodeWorkflowNotFound, http.StatusNotFound, false, "The workspace has no workflow")
+	define(CodeAgingPolicyNotFound, http.StatusNotFound, false, "Aging policy not found")
+	define(CodeAgingPolicyExists, http.StatusConflict, false, "The workspace already has an aging policy with that name")
+	define(CodeCoverNotFound, http.StatusNotFound, false, "The todo has no cover")
+	define(CodeAttachmentTypeForbidden, http.StatusUnsupportedMediaType, false, "Files of this type can't be attached")
+	define(CodeThumbnailNotFound, http.StatusNotFound, false, "The attachment has no thumbnail")
+	define(CodeAttachmentQuarantined, http.StatusConflict, true, "The attachment is quarantined until it is scanned for viruses")
+	define(CodeAttachmentInfected, http.StatusGone, false, "The attachment was blocked by the virus scan")
+	define(CodeNoteNotFound, http.StatusNotFound, false, "The todo has no private note")
+	define(CodeMagicTagNotFound, http.StatusNotFound, false, "Magic tag not found")
+	define(CodeMagicTagLimitReached, http.StatusConflict, false, "You have reached the maximum number of magic tags")
+	define(CodeInviteNotFound, http.StatusNotFound, false, "Invite not found")
+	define(CodeInviteExpired, http.StatusGone, false, "The invite link expired or was revoked")
+	define(CodeInviteUsedUp, http.StatusGone, false, "The invite link was used as many times as it allows")
+	define(CodeInviteDomainNotAllowed, http.StatusForbidden, false,
+		"The invite is limited to email domains none of your verified addresses belong to")
+	define(CodeInviteAlreadyAccepted, http.StatusConflict, false, "You already joined with this invite")
+	define(CodeWorkspaceFrozen, http.StatusConflict, false, "The workspace is frozen, changes can't be saved")
+	define(CodeWorkspaceNotFrozen, http.StatusNotFound, false, "The workspace is not frozen")
+	define(CodeWorkspacePurged, http.StatusGone, false, "The workspace was purged and can't be reactivated")
+	define(CodeIncidentNotFound, http.StatusNotFound, false, "Incident not found")
+	define(CodeAPIKeyNotFound, http.StatusNotFound, false, "API key not found")
+	define(CodeAPIKeyLimitReached, http.StatusConflict, false, "You have reached the maximum number of API keys")
+	define(CodeAPIKeysDisabled, http.StatusNotFound, false, "API keys are not available")
+	define(CodeAPIPlanNotFound, http.StatusBadRequest, false, "No API plan has that name")
+	define(CodeAPIKeyRateLimited, http.StatusTooManyRequests, true, "Too many requests with this API key, slow down and retry")
+	define(CodeAPIQuotaExceeded, http.StatusPaymentRequired, false,
+		"The API key used up the daily quota of its plan, wait for the reset or move to a larger plan")
+	define(CodeAPIClientNotFound, http.StatusNotFound, false, "API client not found")
+	define(CodeCookieSessionsDisabled, http.StatusNotFound, false, "Cookie sessions are not available")
+	define(CodeCSRFTokenInvalid, http.StatusForbidden, false,
+		"The CSRF token is missing or doesn't match, fetch a new one and retry")
+	define(CodeMyDayTodoNotFound, http.StatusNotFound, false, "The todo is not on your day")
+	define(CodeReportScheduleNotFound, http.StatusNotFound, false, "Report schedule not found")
+	define(CodeReportScheduleLimitReached, http.StatusConflict, false, "You have reached the maximum number of scheduled reports")
+	define(CodeWebhookNotFound, http.StatusNotFound, false, "Webhook endpoint not found")
+	define(CodeWebhookLimitReached, http.StatusConflict, false, "The workspace has reached the maximum number of webhook endpoints")
+	define(CodeStorageQuotaNotFound, http.StatusNotFound, false, "The user has no storage quota override")
+	define(CodeWorkspaceNotFound, http.StatusNotFound, false, "Workspace not found")
+}
+
+// Lookup returns the definition of a code, derived codes such as TODO_ALREADY_EXISTS have none
+func Lookup(code string) (Definition, bool) {
+	definition, ok := catalog[code]
+	return definition, ok
+}
+
+// Catalog lists every defined code sorted by code
+func Catalog() []Definition {
+	definitions := make([]Definition, 0, len(catalog))
+	for _, definition := range catalog {
+		definitions = append(definitions, definition)
+	}
+
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Code < definitions[j].Code
+	})
+
+	return definitions
+}
+
+// CodeForStatus is the generic code of a status without a more specific one
+func CodeForStatus(status int) string {
+	return MakeUpperCaseWithUnderscores(http.StatusText(status))
+}
+
+// IsRetryableStatus tells whether a status is transient by nature, for codes without a definition
+func IsRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
@@ -6,9 +6,32 @@ import (
 
 type FieldError struct {
 	Field string `json:"field"`
+	// stable machine readable reason, see the FieldCode constants
+	Code  string `json:"code"`
 	Error string `json:"error"`
+	// Message and Args are what Error was formatted from, the error handler formats them again
+	// in the locale of the request. Error is taken as the message when Message is empty.
+	Message string `json:"-"`
+	Args    []any  `json:"-"`
 }
 
+// Field error codes clients can branch on, independent of the human readable message
+const (
+	FieldCodeRequired         = "required"
+	FieldCodeTooShort         = "too_short"
+	FieldCodeTooLong          = "too_long"
+	FieldCodeTooSmall         = "too_small"
+	FieldCodeTooLarge         = "too_large"
+	FieldCodeTooMany          = "too_many"
+	FieldCodeInvalidChoice    = "invalid_choice"
+	FieldCodeInvalidFormat    = "invalid_format"
+	FieldCodeInvalidType      = "invalid_type"
+	FieldCodeInvalidValue     = "invalid_value"
+	FieldCodeInvalidReference = "invalid_reference"
+	FieldCodeUnknownField     = "unknown_field"
+	FieldCodeDateInPast       = "date_in_past"
+)
+
 type ActionType string
 
 const (
@@ -30,6 +53,10 @@ type HTTPError struct {
 	Errors []FieldError `json:"errors"`
 	// action to be taken
 	Action *Action `json:"action"`
+	// repeating the request later can succeed, or-ed with the definition of the code
+	Retryable bool `json:"retryable"`
+	// debug detail, logged and only shown to clients outside production
+	Detail string `json:"-"`
 }
 
 func (e *HTTPError) Error() string {
@@ -44,13 +71,34 @@ func (e *HTTPError) Is(target error) bool {
 
 func (e *HTTPError) WithMessage(message string) *HTTPError {
 	return &HTTPError{
-		Code:     e.Code,
-		Message:  message,
-		Status:   e.Status,
-		Override: e.Override,
-		Errors:   e.Errors,
-		Action:   e.Action,
+		Code:      e.Code,
+		Message:   message,
+		Status:    e.Status,
+		Override:  e.Override,
+		Errors:    e.Errors,
+		Action:    e.Action,
+		Retryable: e.Retryable,
+		Detail:    e.Detail,
+	}
+}
+
+// WithDetail attaches debug detail, typically the underlying error, without changing what
+// users are shown
+func (e *HTTPError) WithDetail(detail string) *HTTPError {
+	err := e.WithMessage(e.Message)
+	err.Detail = detail
+	return err
+}
+
+// IsRetryable tells whether repeating the request later can succeed
+func (e *HTTPError) IsRetryable() bool {
+	if e.Retryable {
+		return true
+	}
+	if definition, ok := Lookup(e.Code); ok {
+		return definition.Retryable
 	}
+	return IsRetryableStatus(e.Status)
 }
 
 func MakeUpperCaseWithUnderscores(str string) string {
@@ -0,0 +1,57 @@
+package errs
+
+import (
+	"strings"
+)
+
+// MIMEApplicationProblemJSON is the media type of RFC 7807 problem details
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// Problem is an RFC 7807 problem details document. Code, Message, Override, Errors and
+// Action are extension members that keep the shape clients of HTTPError already parse,
+// Retryable tells clients whether repeating the request can succeed, RequestID lets users
+// quote the failing request to support and Debug carries the underlying error outside
+// production.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Override  bool         `json:"override"`
+	Errors    []FieldError `json:"errors"`
+	Action    *Action      `json:"action"`
+	Retryable bool         `json:"retryable"`
+	RequestID string       `json:"requestId,omitempty"`
+	Debug     string       `json:"debug,omitempty"`
+}
+
+// NewProblem renders an HTTPError as problem details for the request path instance
+func NewProblem(err *HTTPError, title, instance string) Problem {
+	fieldErrors := err.Errors
+	if fieldErrors == nil {
+		fieldErrors = []FieldError{}
+	}
+
+	return Problem{
+		Type:      ProblemType(err.Code),
+		Title:     title,
+		Status:    err.Status,
+		Detail:    err.Message,
+		Instance:  instance,
+		Code:      err.Code,
+		Message:   err.Message,
+		Override:  err.Override,
+		Errors:    fieldErrors,
+		Action:    err.Action,
+		Retryable: err.IsRetryable(),
+	}
+}
+
+// ProblemType derives the problem type URI from an error code: VALIDATION_FAILED
+// becomes urn:executask:problem:validation-failed
+func ProblemType(code string) string {
+	return "urn:executask:problem:" + strings.ReplaceAll(strings.ToLower(code), "_", "-")
+}
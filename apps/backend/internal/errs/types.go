@@ -6,7 +6,7 @@ import (
 
 func NewUnauthorizedError(message string, override bool) *HTTPError {
 	return &HTTPError{
-		Code:     MakeUpperCaseWithUnderscores(http.StatusText(http.StatusUnauthorized)),
+		Code:     CodeUnauthorized,
 		Message:  message,
 		Status:   http.StatusUnauthorized,
 		Override: override,
@@ -15,7 +15,7 @@ func NewUnauthorizedError(message string, override bool) *HTTPError {
 
 func NewForbiddenError(message string, override bool) *HTTPError {
 	return &HTTPError{
-		Code:     MakeUpperCaseWithUnderscores(http.StatusText(http.StatusForbidden)),
+		Code:     CodeForbidden,
 		Message:  message,
 		Status:   http.StatusForbidden,
 		Override: override,
@@ -23,7 +23,7 @@ func NewForbiddenError(message string, override bool) *HTTPError {
 }
 
 func NewBadRequestError(message string, override bool, code *string, errors []FieldError, action *Action) *HTTPError {
-	formattedCode := MakeUpperCaseWithUnderscores(http.StatusText(http.StatusBadRequest))
+	formattedCode := CodeBadRequest
 
 	if code != nil {
 		formattedCode = *code
@@ -39,8 +39,19 @@ func NewBadRequestError(message string, override bool, code *string, errors []Fi
 	}
 }
 
+// NewValidationError reports request fields that failed validation, one entry per failing rule
+func NewValidationError(errors []FieldError) *HTTPError {
+	return &HTTPError{
+		Code:     CodeValidationFailed,
+		Message:  "Validation failed",
+		Status:   http.StatusBadRequest,
+		Override: true,
+		Errors:   errors,
+	}
+}
+
 func NewNotFoundError(message string, override bool, code *string) *HTTPError {
-	formattedCode := MakeUpperCaseWithUnderscores(http.StatusText(http.StatusNotFound))
+	formattedCode := CodeNotFound
 
 	if code != nil {
 		formattedCode = *code
@@ -54,9 +65,144 @@ func NewNotFoundError(message string, override bool, code *string) *HTTPError {
 	}
 }
 
+func NewConflictError(message string, override bool, code *string) *HTTPError {
+	formattedCode := CodeConflict
+
+	if code != nil {
+		formattedCode = *code
+	}
+
+	return &HTTPError{
+		Code:     formattedCode,
+		Message:  message,
+		Status:   http.StatusConflict,
+		Override: override,
+	}
+}
+
+func NewGoneError(message string, override bool, code *string) *HTTPError {
+	formattedCode := CodeGone
+
+	if code != nil {
+		formattedCode = *code
+	}
+
+	return &HTTPError{
+		Code:     formattedCode,
+		Message:  message,
+		Status:   http.StatusGone,
+		Override: override,
+	}
+}
+
+func NewPreconditionFailedError(message string, override bool, code *string) *HTTPError {
+	formattedCode := CodePreconditionFailed
+
+	if code != nil {
+		formattedCode = *code
+	}
+
+	return &HTTPError{
+		Code:     formattedCode,
+		Message:  message,
+		Status:   http.StatusPreconditionFailed,
+		Override: override,
+	}
+}
+
+func NewRequestTooLargeError(message string, override bool, code *string) *HTTPError {
+	formattedCode := CodeRequestTooLarge
+
+	if code != nil {
+		formattedCode = *code
+	}
+
+	return &HTTPError{
+		Code:     formattedCode,
+		Message:  message,
+		Status:   http.StatusRequestEntityTooLarge,
+		Override: override,
+	}
+}
+
+func NewUnsupportedMediaTypeError(message string, override bool, code *string) *HTTPError {
+	formattedCode := CodeUnsupportedMediaType
+
+	if code != nil {
+		formattedCode = *code
+	}
+
+	return &HTTPError{
+		Code:     formattedCode,
+		Message:  message,
+		Status:   http.StatusUnsupportedMediaType,
+		Override: override,
+	}
+}
+
+func NewTooManyRequestsError(message string, override bool, code *string) *HTTPError {
+	formattedCode := CodeTooManyRequests
+
+	if code != nil {
+		formattedCode = *code
+	}
+
+	return &HTTPError{
+		Code:     formattedCode,
+		Message:  message,
+		Status:   http.StatusTooManyRequests,
+		Override: override,
+	}
+}
+
+func NewPaymentRequiredError(message string, override bool, code *string) *HTTPError {
+	formattedCode := CodePaymentRequired
+
+	if code != nil {
+		formattedCode = *code
+	}
+
+	return &HTTPError{
+		Code:     formattedCode,
+		Message:  message,
+		Status:   http.StatusPaymentRequired,
+		Override: override,
+	}
+}
+
+func NewGatewayTimeoutError(message string, override bool, code *string) *HTTPError {
+	formattedCode := CodeGatewayTimeout
+
+	if code != nil {
+		formattedCode = *code
+	}
+
+	return &HTTPError{
+		Code:     formattedCode,
+		Message:  message,
+		Status:   http.StatusGatewayTimeout,
+		Override: override,
+	}
+}
+
+func NewServiceUnavailableError(message string, override bool, code *string) *HTTPError {
+	formattedCode := CodeServiceUnavailable
+
+	if code != nil {
+		formattedCode = *code
+	}
+
+	return &HTTPError{
+		Code:     formattedCode,
+		Message:  message,
+		Status:   http.StatusServiceUnavailable,
+		Override: override,
+	}
+}
+
 func NewInternalServerError() *HTTPError {
 	return &HTTPError{
-		Code:     MakeUpperCaseWithUnderscores(http.StatusText(http.StatusInternalServerError)),
+		Code:     CodeInternal,
 		Message:  http.StatusText(http.StatusInternalServerError),
 		Status:   http.StatusInternalServerError,
 		Override: false,
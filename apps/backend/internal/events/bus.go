@@ -0,0 +1,67 @@
+package events
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// Handler reacts to an event. Handlers run while the request that raised the event is served,
+// they hand slow work to jobs.
+type Handler func(ctx context.Context, event Event) error
+
+type subscriber struct {
+	name   string
+	types  []Type
+	handle Handler
+}
+
+// Bus delivers every published event to the subscribers of its type, in the order they
+// subscribed. A failing subscriber is logged and doesn't keep the others from the event.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []subscriber
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers the handler for the event types, all of them when none is given. name
+// identifies the subscriber in the logs.
+func (b *Bus) Subscribe(name string, handler Handler, types ...Type) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers = append(b.subscribers, subscriber{name: name, types: types, handle: handler})
+}
+
+// Publish delivers the events in order. Subscribers log through the logger of ctx, see
+// zerolog.Ctx. A nil bus drops the events.
+func (b *Bus) Publish(ctx context.Context, events ...Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	subscribers := slices.Clone(b.subscribers)
+	b.mu.RUnlock()
+
+	for _, event := range events {
+		for _, sub := range subscribers {
+			if len(sub.types) > 0 && !slices.Contains(sub.types, event.Type()) {
+				continue
+			}
+
+			if err := sub.handle(ctx, event); err != nil {
+				zerolog.Ctx(ctx).Error().
+					Err(err).
+					Str("event", string(event.Type())).
+					Str("subscriber", sub.name).
+					Msg("event subscriber failed")
+			}
+		}
+	}
+}
@@ -0,0 +1,260 @@
+// Package events carries what happened to todos and comments from the services that change
+// them to the subscribers reacting to it: the business event log, analytics, automation rules,
+// link previews and webhooks.
+package events
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// Type identifies the kind of an event, it is the event field of its log line
+type Type string
+
+const (
+	TypeTodoCreated         Type = "todo_created"
+	TypeTodoUpdated         Type = "todo_updated"
+	TypeTodoCompleted       Type = "todo_completed"
+	TypeTodosCompleted      Type = "todos_completed"
+	TypeTodoUncompleted     Type = "todo_uncompleted"
+	TypeTodoTagged          Type = "todo_tagged"
+	TypeTodosMerged         Type = "todos_merged"
+	TypeTodoVersionRestored Type = "todo_version_restored"
+	TypeTodoDeleted         Type = "todo_deleted"
+	TypeTodoRead            Type = "todo_read"
+	TypeCommentAdded        Type = "comment_added"
+	TypeCommentUpdated      Type = "comment_updated"
+	TypeCommentDeleted      Type = "comment_deleted"
+	TypeCommentsRead        Type = "comments_read"
+)
+
+// Event is a change that happened, subscribers can't undo it
+type Event interface {
+	Type() Type
+	// User is the user who made the change
+	User() string
+	// Workspace is the workspace the change was made in, empty for the personal space
+	Workspace() string
+	// MarshalZerologObject adds the fields of the event to its log line
+	MarshalZerologObject(e *zerolog.Event)
+}
+
+// Actor is who made the change, embedded in every event
+type Actor struct {
+	UserID      string
+	WorkspaceID string
+}
+
+func (a Actor) User() string {
+	return a.UserID
+}
+
+func (a Actor) Workspace() string {
+	return a.WorkspaceID
+}
+
+type TodoCreated struct {
+	Actor
+	Todo *todo.Todo
+}
+
+func (e *TodoCreated) Type() Type { return TypeTodoCreated }
+
+func (e *TodoCreated) MarshalZerologObject(z *zerolog.Event) {
+	z.Str("todo_id", e.Todo.ID.String()).
+		Str("title", e.Todo.Title).
+		Str("category_id", optionalID(e.Todo.CategoryID)).
+		Str("priority", string(e.Todo.Priority))
+}
+
+// TodoUpdated is raised for every update, a completion raises TodoCompleted as well
+type TodoUpdated struct {
+	Actor
+	Todo *todo.Todo
+	// DescriptionChanged tells whether the update set the description
+	DescriptionChanged bool
+}
+
+func (e *TodoUpdated) Type() Type { return TypeTodoUpdated }
+
+func (e *TodoUpdated) MarshalZerologObject(z *zerolog.Event) {
+	z.Str("todo_id", e.Todo.ID.String()).
+		Str("title", e.Todo.Title).
+		Str("category_id", optionalID(e.Todo.CategoryID)).
+		Str("priority", string(e.Todo.Priority)).
+		Str("status", string(e.Todo.Status))
+}
+
+// TodoCompleted is raised when an open todo is completed on its own
+type TodoCompleted struct {
+	Actor
+	Todo *todo.Todo
+}
+
+func (e *TodoCompleted) Type() Type { return TypeTodoCompleted }
+
+func (e *TodoCompleted) MarshalZerologObject(z *zerolog.Event) {
+	z.Str("todo_id", e.Todo.ID.String()).
+		Str("priority", string(e.Todo.Priority))
+}
+
+// TodosCompleted is raised once for todos completed together, the subscribers handle them as
+// a batch
+type TodosCompleted struct {
+	Actor
+	Todos                 []todo.Todo
+	AlreadyCompletedCount int
+	BlockedCount          int
+}
+
+func (e *TodosCompleted) Type() Type { return TypeTodosCompleted }
+
+func (e *TodosCompleted) MarshalZerologObject(z *zerolog.Event) {
+	z.Int("todo_count", len(e.Todos)).
+		Int("already_completed_count", e.AlreadyCompletedCount).
+		Int("blocked_count", e.BlockedCount)
+}
+
+// TodoUncompleted is raised when a completion is undone within its window, CompletedAt is when
+// the completion being undone happened
+type TodoUncompleted struct {
+	Actor
+	Todo        *todo.Todo
+	CompletedAt time.Time
+}
+
+func (e *TodoUncompleted) Type() Type { return TypeTodoUncompleted }
+
+func (e *TodoUncompleted) MarshalZerologObject(z *zerolog.Event) {
+	z.Str("todo_id", e.Todo.ID.String()).
+		Dur("undone_after", time.Since(e.CompletedAt))
+}
+
+// TodoTagged is raised for every tag a todo gains, those of a new todo included
+type TodoTagged struct {
+	Actor
+	TodoID uuid.UUID
+	Tag    string
+}
+
+func (e *TodoTagged) Type() Type { return TypeTodoTagged }
+
+func (e *TodoTagged) MarshalZerologObject(z *zerolog.Event) {
+	z.Str("todo_id", e.TodoID.String()).
+		Str("tag", e.Tag)
+}
+
+type TodosMerged struct {
+	Actor
+	Todo        *todo.Todo
+	SourceCount int
+}
+
+func (e *TodosMerged) Type() Type { return TypeTodosMerged }
+
+func (e *TodosMerged) MarshalZerologObject(z *zerolog.Event) {
+	z.Str("todo_id", e.Todo.ID.String()).
+		Int("source_count", e.SourceCount)
+}
+
+type TodoVersionRestored struct {
+	Actor
+	Todo            *todo.Todo
+	RestoredVersion int
+	PreviousVersion int
+}
+
+func (e *TodoVersionRestored) Type() Type { return TypeTodoVersionRestored }
+
+func (e *TodoVersionRestored) MarshalZerologObject(z *zerolog.Event) {
+	z.Str("todo_id", e.Todo.ID.String()).
+		Int("restored_version", e.RestoredVersion).
+		Int("previous_version", e.PreviousVersion).
+		Int("version", e.Todo.Version)
+}
+
+type TodoDeleted struct {
+	Actor
+	TodoID uuid.UUID
+}
+
+func (e *TodoDeleted) Type() Type { return TypeTodoDeleted }
+
+func (e *TodoDeleted) MarshalZerologObject(z *zerolog.Event) {
+	z.Str("todo_id", e.TodoID.String())
+}
+
+type TodoRead struct {
+	Actor
+	TodoID uuid.UUID
+}
+
+func (e *TodoRead) Type() Type { return TypeTodoRead }
+
+func (e *TodoRead) MarshalZerologObject(z *zerolog.Event) {
+	z.Str("todo_id", e.TodoID.String())
+}
+
+const (
+	SourceAPI   = "api"
+	SourceEmail = "email"
+)
+
+type CommentAdded struct {
+	Actor
+	Comment *comment.Comment
+	// Source is where the comment was written, api or email
+	Source string
+}
+
+func (e *CommentAdded) Type() Type { return TypeCommentAdded }
+
+func (e *CommentAdded) MarshalZerologObject(z *zerolog.Event) {
+	z.Str("comment_id", e.Comment.ID.String()).
+		Str("todo_id", e.Comment.TodoID.String()).
+		Str("source", e.Source)
+}
+
+type CommentUpdated struct {
+	Actor
+	Comment *comment.Comment
+}
+
+func (e *CommentUpdated) Type() Type { return TypeCommentUpdated }
+
+func (e *CommentUpdated) MarshalZerologObject(z *zerolog.Event) {
+	z.Str("comment_id", e.Comment.ID.String())
+}
+
+type CommentDeleted struct {
+	Actor
+	CommentID uuid.UUID
+}
+
+func (e *CommentDeleted) Type() Type { return TypeCommentDeleted }
+
+func (e *CommentDeleted) MarshalZerologObject(z *zerolog.Event) {
+	z.Str("comment_id", e.CommentID.String())
+}
+
+type CommentsRead struct {
+	Actor
+	TodoID uuid.UUID
+}
+
+func (e *CommentsRead) Type() Type { return TypeCommentsRead }
+
+func (e *CommentsRead) MarshalZerologObject(z *zerolog.Event) {
+	z.Str("todo_id", e.TodoID.String())
+}
+
+func optionalID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
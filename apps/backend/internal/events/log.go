@@ -0,0 +1,34 @@
+package events
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+var messages = map[Type]string{
+	TypeTodoCreated:         "Todo created successfully",
+	TypeTodoUpdated:         "Todo updated successfully",
+	TypeTodoCompleted:       "Todo completed successfully",
+	TypeTodosCompleted:      "Todos completed successfully",
+	TypeTodoUncompleted:     "Todo completion undone",
+	TypeTodoTagged:          "Todo tagged",
+	TypeTodosMerged:         "Todos merged successfully",
+	TypeTodoVersionRestored: "Todo version restored",
+	TypeTodoDeleted:         "Todo deleted successfully",
+	TypeTodoRead:            "Todo marked as read",
+	TypeCommentAdded:        "Comment added successfully",
+	TypeCommentUpdated:      "Comment updated successfully",
+	TypeCommentDeleted:      "Comment deleted successfully",
+	TypeCommentsRead:        "Comments marked as read",
+}
+
+// Log writes the business event log line of every event
+func Log(ctx context.Context, event Event) error {
+	zerolog.Ctx(ctx).Info().
+		Str("event", string(event.Type())).
+		EmbedObject(event).
+		Msg(messages[event.Type()])
+
+	return nil
+}
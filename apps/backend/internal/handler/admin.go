@@ -0,0 +1,503 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/admin"
+	"github.com/Sameer16536/ExecuTask/internal/model/apiclient"
+	"github.com/Sameer16536/ExecuTask/internal/model/apikey"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/model/backup"
+	"github.com/Sameer16536/ExecuTask/internal/model/feature"
+	"github.com/Sameer16536/ExecuTask/internal/model/retention"
+	"github.com/Sameer16536/ExecuTask/internal/model/statuspage"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type AdminHandler struct {
+	Handler
+	adminService *service.AdminService
+	auditService *service.AuditService
+	featureFlags *service.FeatureFlagService
+	retention    *service.RetentionService
+	backups      *service.BackupService
+	statusPage   *service.StatusPageService
+	apiKeys      *service.APIKeyService
+	apiClients   *service.APIClientService
+}
+
+func NewAdminHandler(s *server.Server, adminService *service.AdminService, auditService *service.AuditService,
+	featureFlags *service.FeatureFlagService, retention *service.RetentionService, backups *service.BackupService,
+	statusPage *service.StatusPageService, apiKeys *service.APIKeyService, apiClients *service.APIClientService,
+) *AdminHandler {
+	return &AdminHandler{
+		Handler:      NewHandler(s),
+		adminService: adminService,
+		auditService: auditService,
+		featureFlags: featureFlags,
+		retention:    retention,
+		backups:      backups,
+		statusPage:   statusPage,
+		apiKeys:      apiKeys,
+		apiClients:   apiClients,
+	}
+}
+
+func (h *AdminHandler) GetUser(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *admin.GetUserPayload) (*admin.User, error) {
+			return h.adminService.GetUser(c, payload.ID)
+		},
+		http.StatusOK,
+		&admin.GetUserPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) GetStorageQuota(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *admin.GetStorageQuotaPayload) (*admin.UserStorage, error) {
+			return h.adminService.GetStorageQuota(c, payload.UserID)
+		},
+		http.StatusOK,
+		&admin.GetStorageQuotaPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) SetStorageQuota(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *admin.SetStorageQuotaPayload) (*admin.UserStorage, error) {
+			userID := middleware.GetUserID(c)
+			return h.adminService.SetStorageQuota(c, userID, payload)
+		},
+		http.StatusOK,
+		&admin.SetStorageQuotaPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) DeleteStorageQuota(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *admin.DeleteStorageQuotaPayload) error {
+			return h.adminService.DeleteStorageQuota(c, payload.UserID)
+		},
+		http.StatusNoContent,
+		&admin.DeleteStorageQuotaPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) GetWorkspace(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *admin.GetWorkspacePayload) (*admin.Workspace, error) {
+			return h.adminService.GetWorkspace(c, payload.WorkspaceID)
+		},
+		http.StatusOK,
+		&admin.GetWorkspacePayload{},
+	)(c)
+}
+
+func (h *AdminHandler) GetJobs(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *admin.GetJobsQuery) (*model.PaginatedResponse[admin.Job], error) {
+			return h.adminService.GetJobs(c, query)
+		},
+		http.StatusOK,
+		&admin.GetJobsQuery{},
+	)(c)
+}
+
+func (h *AdminHandler) RetryJob(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *admin.RetryJobPayload) error {
+			return h.adminService.RetryJob(c, payload)
+		},
+		http.StatusNoContent,
+		&admin.RetryJobPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) GetServerMode(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *admin.GetServerModePayload) (*admin.ServerMode, error) {
+			return h.adminService.GetServerMode(c), nil
+		},
+		http.StatusOK,
+		&admin.GetServerModePayload{},
+	)(c)
+}
+
+func (h *AdminHandler) SetServerMode(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *admin.SetServerModePayload) (*admin.ServerMode, error) {
+			userID := middleware.GetUserID(c)
+			return h.adminService.SetServerMode(c, userID, payload)
+		},
+		http.StatusOK,
+		&admin.SetServerModePayload{},
+	)(c)
+}
+
+func (h *AdminHandler) GetFaultInjection(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *admin.GetFaultInjectionPayload) (*admin.FaultInjection, error) {
+			return h.adminService.GetFaultInjection(c), nil
+		},
+		http.StatusOK,
+		&admin.GetFaultInjectionPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) SetFaultInjection(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *admin.SetFaultInjectionPayload) (*admin.FaultInjection, error) {
+			userID := middleware.GetUserID(c)
+			return h.adminService.SetFaultInjection(c, userID, payload)
+		},
+		http.StatusOK,
+		&admin.SetFaultInjectionPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) ClearFaultInjection(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *admin.ClearFaultInjectionPayload) error {
+			return h.adminService.ClearFaultInjection(c)
+		},
+		http.StatusNoContent,
+		&admin.ClearFaultInjectionPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) ReloadConfig(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *admin.ReloadConfigPayload) (*admin.ConfigReload, error) {
+			return h.adminService.ReloadConfig(c)
+		},
+		http.StatusOK,
+		&admin.ReloadConfigPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) GetRuntimeStats(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *admin.GetRuntimeStatsPayload) (*admin.RuntimeStats, error) {
+			return h.adminService.GetRuntimeStats(c), nil
+		},
+		http.StatusOK,
+		&admin.GetRuntimeStatsPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) GetAuditLog(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *audit.GetAuditLogQuery) (*model.PaginatedResponse[audit.Entry], error) {
+			return h.auditService.GetEntries(c, query)
+		},
+		http.StatusOK,
+		&audit.GetAuditLogQuery{},
+	)(c)
+}
+
+func (h *AdminHandler) ExportAuditLog(c echo.Context) error {
+	return HandleStream(
+		h.Handler,
+		func(c echo.Context, query *audit.ExportAuditLogQuery, stream *ResponseStream) error {
+			if *query.Format == audit.ExportFormatCSV {
+				stream.SetContentType(MIMETextCSV)
+				stream.SetFilename("audit-log.csv")
+
+				writer := csv.NewWriter(stream)
+				if err := writer.Write(audit.CSVHeader); err != nil {
+					return err
+				}
+
+				err := h.auditService.ExportEntries(c, query, func(entry *audit.Entry) error {
+					return writer.Write(entry.CSVRecord())
+				})
+				if err != nil {
+					return err
+				}
+
+				writer.Flush()
+				return writer.Error()
+			}
+
+			stream.SetContentType(MIMEApplicationNDJSON)
+			stream.SetFilename("audit-log.ndjson")
+
+			encoder := json.NewEncoder(stream)
+			return h.auditService.ExportEntries(c, query, func(entry *audit.Entry) error {
+				return encoder.Encode(entry)
+			})
+		},
+		http.StatusOK,
+		&audit.ExportAuditLogQuery{},
+	)(c)
+}
+
+func (h *AdminHandler) GetFeatureFlags(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *feature.GetFlagsPayload) ([]feature.Flag, error) {
+			return h.featureFlags.GetFlags(c, payload)
+		},
+		http.StatusOK,
+		&feature.GetFlagsPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) SetFeatureFlagOverride(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *feature.SetOverridePayload) (*feature.Override, error) {
+			userID := middleware.GetUserID(c)
+			return h.featureFlags.SetOverride(c, userID, payload)
+		},
+		http.StatusOK,
+		&feature.SetOverridePayload{},
+	)(c)
+}
+
+func (h *AdminHandler) DeleteFeatureFlagOverride(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *feature.DeleteOverridePayload) error {
+			return h.featureFlags.DeleteOverride(c, payload)
+		},
+		http.StatusNoContent,
+		&feature.DeleteOverridePayload{},
+	)(c)
+}
+
+func (h *AdminHandler) GetRetentionPolicies(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *retention.GetPoliciesPayload) (*retention.Overview, error) {
+			return h.retention.GetPolicies(c, payload)
+		},
+		http.StatusOK,
+		&retention.GetPoliciesPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) SetRetentionPolicy(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *retention.SetPolicyPayload) (*retention.Policy, error) {
+			userID := middleware.GetUserID(c)
+			return h.retention.SetPolicy(c, userID, payload)
+		},
+		http.StatusOK,
+		&retention.SetPolicyPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) DeleteRetentionPolicy(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *retention.DeletePolicyPayload) error {
+			return h.retention.DeletePolicy(c, payload)
+		},
+		http.StatusNoContent,
+		&retention.DeletePolicyPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) PreviewRetentionPolicy(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *retention.PreviewPolicyPayload) (*retention.Report, error) {
+			return h.retention.PreviewPolicy(c, payload)
+		},
+		http.StatusOK,
+		&retention.PreviewPolicyPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) CreateWorkspaceBackup(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *backup.CreateBackupPayload) (*backup.Backup, error) {
+			userID := middleware.GetUserID(c)
+			return h.backups.RequestBackup(c, userID, payload)
+		},
+		http.StatusAccepted,
+		&backup.CreateBackupPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) GetWorkspaceBackups(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *backup.GetBackupsPayload) ([]backup.Backup, error) {
+			return h.backups.GetBackups(c, payload)
+		},
+		http.StatusOK,
+		&backup.GetBackupsPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) GetWorkspaceBackup(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *backup.GetBackupPayload) (*backup.Backup, error) {
+			return h.backups.GetBackup(c, payload)
+		},
+		http.StatusOK,
+		&backup.GetBackupPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) RestoreWorkspaceBackup(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *backup.RestoreBackupPayload) (*backup.Restore, error) {
+			userID := middleware.GetUserID(c)
+			return h.backups.RequestRestore(c, userID, payload)
+		},
+		http.StatusAccepted,
+		&backup.RestoreBackupPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) GetWorkspaceRestore(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *backup.GetRestorePayload) (*backup.Restore, error) {
+			return h.backups.GetRestore(c, payload)
+		},
+		http.StatusOK,
+		&backup.GetRestorePayload{},
+	)(c)
+}
+
+func (h *AdminHandler) GetIncidents(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *statuspage.GetIncidentsQuery) ([]statuspage.Incident, error) {
+			return h.statusPage.GetIncidents(c, query)
+		},
+		http.StatusOK,
+		&statuspage.GetIncidentsQuery{},
+	)(c)
+}
+
+func (h *AdminHandler) CreateIncident(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *statuspage.CreateIncidentPayload) (*statuspage.Incident, error) {
+			userID := middleware.GetUserID(c)
+			return h.statusPage.CreateIncident(c, userID, payload)
+		},
+		http.StatusCreated,
+		&statuspage.CreateIncidentPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) UpdateIncident(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *statuspage.UpdateIncidentPayload) (*statuspage.Incident, error) {
+			return h.statusPage.UpdateIncident(c, payload)
+		},
+		http.StatusOK,
+		&statuspage.UpdateIncidentPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) DeleteIncident(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *statuspage.DeleteIncidentPayload) error {
+			return h.statusPage.DeleteIncident(c, payload)
+		},
+		http.StatusNoContent,
+		&statuspage.DeleteIncidentPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) GetUserAPIKeys(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *apikey.GetUserKeysQuery) ([]apikey.Key, error) {
+			return h.apiKeys.GetUserKeys(c, query)
+		},
+		http.StatusOK,
+		&apikey.GetUserKeysQuery{},
+	)(c)
+}
+
+func (h *AdminHandler) SetAPIKeyPlan(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *apikey.SetKeyPlanPayload) (*apikey.Key, error) {
+			return h.apiKeys.SetKeyPlan(c, payload)
+		},
+		http.StatusOK,
+		&apikey.SetKeyPlanPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) GetAPIClients(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *apiclient.GetAPIClientsPayload) ([]apiclient.APIClient, error) {
+			return h.apiClients.GetClients(c, payload)
+		},
+		http.StatusOK,
+		&apiclient.GetAPIClientsPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) CreateAPIClient(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *apiclient.CreateAPIClientPayload) (*apiclient.APIClient, error) {
+			userID := middleware.GetUserID(c)
+			return h.apiClients.CreateClient(c, userID, payload)
+		},
+		http.StatusCreated,
+		&apiclient.CreateAPIClientPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) UpdateAPIClient(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *apiclient.UpdateAPIClientPayload) (*apiclient.APIClient, error) {
+			return h.apiClients.UpdateClient(c, payload)
+		},
+		http.StatusOK,
+		&apiclient.UpdateAPIClientPayload{},
+	)(c)
+}
+
+func (h *AdminHandler) DeleteAPIClient(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *apiclient.DeleteAPIClientPayload) error {
+			return h.apiClients.DeleteClient(c, payload)
+		},
+		http.StatusNoContent,
+		&apiclient.DeleteAPIClientPayload{},
+	)(c)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/apikey"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type APIKeyHandler struct {
+	Handler
+	apiKeyService *service.APIKeyService
+}
+
+func NewAPIKeyHandler(s *server.Server, apiKeyService *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		Handler:       NewHandler(s),
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (h *APIKeyHandler) GetKeys(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *apikey.GetKeysPayload) ([]apikey.Key, error) {
+			userID := middleware.GetUserID(c)
+			return h.apiKeyService.GetKeys(c, userID, payload)
+		},
+		http.StatusOK,
+		&apikey.GetKeysPayload{},
+	)(c)
+}
+
+func (h *APIKeyHandler) CreateKey(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *apikey.CreateKeyPayload) (*apikey.CreatedKey, error) {
+			userID := middleware.GetUserID(c)
+			return h.apiKeyService.CreateKey(c, userID, payload)
+		},
+		http.StatusCreated,
+		&apikey.CreateKeyPayload{},
+	)(c)
+}
+
+func (h *APIKeyHandler) DeleteKey(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *apikey.DeleteKeyPayload) error {
+			userID := middleware.GetUserID(c)
+			return h.apiKeyService.DeleteKey(c, userID, payload)
+		},
+		http.StatusNoContent,
+		&apikey.DeleteKeyPayload{},
+	)(c)
+}
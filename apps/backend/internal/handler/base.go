@@ -1,8 +1,15 @@
 package handler
 
 import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
 	"time"
 
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/etag"
+	"github.com/Sameer16536/ExecuTask/internal/lib/tracing"
 	"github.com/Sameer16536/ExecuTask/internal/middleware"
 	"github.com/Sameer16536/ExecuTask/internal/server"
 	"github.com/Sameer16536/ExecuTask/internal/validation"
@@ -34,13 +41,31 @@ type ResponseHandler interface {
 	AddAttributes(txn *newrelic.Transaction, result interface{})
 }
 
-// JSONResponseHandler handles JSON responses
+// JSONResponseHandler handles JSON responses, including the JSON:API and NDJSON variants
 type JSONResponseHandler struct {
 	status int
 }
 
 func (h JSONResponseHandler) Handle(c echo.Context, result interface{}) error {
-	return c.JSON(h.status, result)
+	serializer := negotiateSerializer(c)
+	c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+
+	attachLinks(c, result)
+
+	if tagged, ok := result.(etag.Tagged); ok {
+		if tag := tagged.ETag(); tag != "" {
+			tag = representationTag(serializer, tag)
+			c.Response().Header().Set(etag.HeaderETag, tag)
+
+			method := c.Request().Method
+			if (method == http.MethodGet || method == http.MethodHead) &&
+				etag.Matches(c.Request().Header.Get(etag.HeaderIfNoneMatch), tag) {
+				return c.NoContent(http.StatusNotModified)
+			}
+		}
+	}
+
+	return serializer.Serialize(c, h.status, result)
 }
 
 func (h JSONResponseHandler) GetOperation() string {
@@ -96,13 +121,21 @@ func (h FileResponseHandler) AddAttributes(txn *newrelic.Transaction, result int
 	}
 }
 
-// handleRequest is the unified handler function that eliminates code duplication
+// handleRequest is the unified handler function that eliminates code duplication. The work runs
+// in a span named after the handler method, services and repositories start theirs under it.
 func handleRequest[Req validation.Validatable](
 	c echo.Context,
+	name string,
 	req Req,
 	handler func(c echo.Context, req Req) (interface{}, error),
 	responseHandler ResponseHandler,
-) error {
+) (err error) {
+	ctx, span := tracing.Tracer().Start(c.Request().Context(), name)
+	defer func() {
+		tracing.End(span, err)
+	}()
+	c.SetRequest(c.Request().WithContext(ctx))
+
 	start := time.Now()
 	method := c.Request().Method
 	path := c.Path()
@@ -213,8 +246,9 @@ func Handle[Req validation.Validatable, Res any](
 	status int,
 	req Req,
 ) echo.HandlerFunc {
+	name := operationName(handler)
 	return func(c echo.Context) error {
-		return handleRequest(c, req, func(c echo.Context, req Req) (interface{}, error) {
+		return handleRequest(c, name, req, func(c echo.Context, req Req) (interface{}, error) {
 			return handler(c, req)
 		}, JSONResponseHandler{status: status})
 	}
@@ -228,8 +262,9 @@ func HandleFile[Req validation.Validatable](
 	filename string,
 	contentType string,
 ) echo.HandlerFunc {
+	name := operationName(handler)
 	return func(c echo.Context) error {
-		return handleRequest(c, req, func(c echo.Context, req Req) (interface{}, error) {
+		return handleRequest(c, name, req, func(c echo.Context, req Req) (interface{}, error) {
 			return handler(c, req)
 		}, FileResponseHandler{
 			status:      status,
@@ -246,10 +281,64 @@ func HandleNoContent[Req validation.Validatable](
 	status int,
 	req Req,
 ) echo.HandlerFunc {
+	name := operationName(handler)
 	return func(c echo.Context) error {
-		return handleRequest(c, req, func(c echo.Context, req Req) (interface{}, error) {
+		return handleRequest(c, name, req, func(c echo.Context, req Req) (interface{}, error) {
 			err := handler(c, req)
 			return nil, err
 		}, NoContentResponseHandler{status: status})
 	}
 }
+
+// operationName names the handler method that declared fn, e.g. "TodoHandler.GetTodos"
+func operationName(fn any) string {
+	name := handlerMethodName(runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name())
+	if name == "" {
+		return "handler"
+	}
+
+	// Handlers pass a function literal, drop its closure suffix
+	if idx := strings.Index(name, ".func"); idx >= 0 {
+		name = name[:idx]
+	}
+	return name
+}
+
+// setETag sets the entity tag of the resource a write left behind, so the client can make its
+// next write conditional without fetching the resource first. The write already happened, a
+// failed load is logged and leaves the tag out.
+func setETag[T etag.Tagged](c echo.Context, load func() (T, error)) {
+	current, err := load()
+	if err != nil {
+		middleware.GetLogger(c).Warn().Err(err).Msg("failed to load the entity tag of the written resource")
+		return
+	}
+
+	c.Response().Header().Set(etag.HeaderETag, representationTag(negotiateSerializer(c), current.ETag()))
+}
+
+// checkIfMatch enforces an If-Match precondition against the current state of a
+// resource. load is only called when the header is present; the loaded resource is
+// returned so callers can guard the write itself.
+func checkIfMatch[T etag.Tagged](c echo.Context, load func() (T, error)) (T, bool, error) {
+	var current T
+
+	ifMatch := c.Request().Header.Get(etag.HeaderIfMatch)
+	if ifMatch == "" {
+		return current, false, nil
+	}
+
+	current, err := load()
+	if err != nil {
+		return current, false, err
+	}
+
+	// The client sends back the tag of the representation it fetched
+	tag := current.ETag()
+	if !etag.MatchesStrong(ifMatch, tag) && !etag.MatchesStrong(ifMatch, representationTag(negotiateSerializer(c), tag)) {
+		code := errs.CodeETagMismatch
+		return current, false, errs.NewPreconditionFailedError("resource has changed since it was fetched", false, &code)
+	}
+
+	return current, true, nil
+}
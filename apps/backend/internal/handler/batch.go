@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/batch"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+const batchPath = "/api/v1/batch"
+
+// forwardedBatchHeaders are copied from the batch request onto every sub-request. Sub-requests
+// are authenticated with the identity verified for the batch, whether it came with a session
+// or an API key, the session token is only forwarded for the session recorders.
+var forwardedBatchHeaders = []string{
+	echo.HeaderAuthorization,
+	"Accept-Language",
+	"User-Agent",
+	// The rate limiter and the audit log see the client, not the batch
+	echo.HeaderXForwardedFor,
+	echo.HeaderXRealIP,
+}
+
+type BatchHandler struct {
+	Handler
+}
+
+func NewBatchHandler(s *server.Server) *BatchHandler {
+	return &BatchHandler{
+		Handler: NewHandler(s),
+	}
+}
+
+func (h *BatchHandler) ExecuteBatch(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *batch.BatchPayload) (*batch.BatchResponse, error) {
+			logger := middleware.GetLogger(c)
+
+			responses := make([]batch.SubResponse, 0, len(payload.Requests))
+			for i, subRequest := range payload.Requests {
+				response := h.dispatch(c, i, subRequest)
+				responses = append(responses, response)
+			}
+
+			logger.Info().
+				Str("event", "batch_executed").
+				Int("request_count", len(payload.Requests)).
+				Msg("Batch executed successfully")
+
+			return &batch.BatchResponse{Responses: responses}, nil
+		},
+		http.StatusOK,
+		&batch.BatchPayload{},
+	)(c)
+}
+
+// dispatch runs a single sub-request in-process through the whole middleware chain, the way
+// it would run on its own: it is authenticated, rate limited, validated and metered again.
+func (h *BatchHandler) dispatch(c echo.Context, index int, subRequest batch.SubRequest) batch.SubResponse {
+	response := batch.SubResponse{ID: subRequest.ID}
+
+	if strings.HasPrefix(subRequest.Path, batchPath) {
+		return errorSubResponse(response, http.StatusBadRequest, "nested batch requests are not allowed")
+	}
+
+	parent := c.Request()
+	ctx := middleware.WithIdentity(parent.Context(), c)
+	req, err := http.NewRequestWithContext(ctx, subRequest.Method, subRequest.Path, bytes.NewReader(subRequest.Body))
+	if err != nil {
+		return errorSubResponse(response, http.StatusBadRequest, "invalid sub-request path")
+	}
+
+	for key, value := range subRequest.Headers {
+		req.Header.Set(key, value)
+	}
+	for _, key := range forwardedBatchHeaders {
+		if value := parent.Header.Get(key); value != "" {
+			req.Header.Set(key, value)
+		}
+	}
+	if len(subRequest.Body) > 0 {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	// Sub-response bodies are embedded in the batch response, which is compressed as a whole
+	req.Header.Del(echo.HeaderAcceptEncoding)
+	// The request ID middleware picks it up, so the sub-request logs correlate with the batch
+	req.Header.Set(middleware.RequestIDHeader, fmt.Sprintf("%s-%d", middleware.GetRequestID(c), index))
+	req.RemoteAddr = parent.RemoteAddr
+
+	recorder := httptest.NewRecorder()
+	c.Echo().ServeHTTP(recorder, req)
+
+	response.Status = recorder.Code
+	response.Headers = map[string]string{}
+	for key := range recorder.Header() {
+		response.Headers[key] = recorder.Header().Get(key)
+	}
+
+	responseBody := recorder.Body.Bytes()
+	if len(responseBody) == 0 {
+		return response
+	}
+
+	if json.Valid(responseBody) {
+		response.Body = json.RawMessage(bytes.TrimSpace(responseBody))
+	} else {
+		encoded, _ := json.Marshal(string(responseBody))
+		response.Body = encoded
+	}
+
+	return response
+}
+
+func errorSubResponse(response batch.SubResponse, status int, message string) batch.SubResponse {
+	body, _ := json.Marshal(map[string]interface{}{
+		"code":     errs.MakeUpperCaseWithUnderscores(http.StatusText(status)),
+		"message":  message,
+		"status":   status,
+		"override": false,
+	})
+
+	response.Status = status
+	response.Headers = map[string]string{echo.HeaderContentType: echo.MIMEApplicationJSON}
+	response.Body = body
+	return response
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/batch"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteBatchRunsGlobalMiddlewares(t *testing.T) {
+	logger := zerolog.Nop()
+	h := NewBatchHandler(&server.Server{Config: &config.Config{}, Logger: &logger})
+
+	var calls atomic.Int32
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			calls.Add(1)
+			// Stands in for the schema validation and the body limit
+			if c.Request().Header.Get("X-Reject") != "" {
+				return echo.NewHTTPError(http.StatusUnprocessableEntity, "rejected")
+			}
+			return next(c)
+		}
+	})
+	e.POST(batchPath, h.ExecuteBatch)
+	e.GET("/api/v1/ping", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{
+			"requestId": middleware.GetRequestID(c),
+			"auth":      c.Request().Header.Get(echo.HeaderAuthorization),
+		})
+	})
+
+	body, err := json.Marshal(batch.BatchPayload{Requests: []batch.SubRequest{
+		{ID: "first", Method: http.MethodGet, Path: "/api/v1/ping"},
+		{ID: "second", Method: http.MethodGet, Path: "/api/v1/ping", Headers: map[string]string{"X-Reject": "1"}},
+		{ID: "nested", Method: http.MethodPost, Path: batchPath},
+	}})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, batchPath, strings.NewReader(string(body)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer token")
+	req.Header.Set(middleware.RequestIDHeader, "batch-1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var response batch.BatchResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Responses, 3)
+
+	// The batch and the two dispatched sub-requests, the nested batch is refused before dispatch
+	require.EqualValues(t, 3, calls.Load())
+
+	var first map[string]string
+	require.Equal(t, http.StatusOK, response.Responses[0].Status)
+	require.NoError(t, json.Unmarshal(response.Responses[0].Body, &first))
+	require.Equal(t, "batch-1-0", first["requestId"])
+	require.Equal(t, "Bearer token", first["auth"])
+
+	require.Equal(t, http.StatusUnprocessableEntity, response.Responses[1].Status)
+	require.Equal(t, http.StatusBadRequest, response.Responses[2].Status)
+}
+
+// countingAPIKeys accepts one secret and counts how often keys are verified
+type countingAPIKeys struct {
+	calls atomic.Int32
+}
+
+func (k *countingAPIKeys) AuthenticateAPIKey(c echo.Context, secret string) (string, string, error) {
+	k.calls.Add(1)
+	if secret != "secret" {
+		return "", "", errs.NewUnauthorizedError("Unauthorized", false)
+	}
+	return "user_key_owner", "key_1", nil
+}
+
+func TestExecuteBatchForwardsVerifiedIdentity(t *testing.T) {
+	logger := zerolog.Nop()
+	s := &server.Server{Config: &config.Config{}, Logger: &logger}
+	h := NewBatchHandler(s)
+
+	apiKeys := &countingAPIKeys{}
+	auth := middleware.NewAuthMiddleware(s)
+	auth.SetAPIKeyAuthenticator(apiKeys)
+
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	e.POST(batchPath, h.ExecuteBatch, auth.RequireAuth)
+	e.GET("/api/v1/me", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{
+			"userId":   middleware.GetUserID(c),
+			"apiKeyId": c.Get(middleware.APIKeyIDKey).(string),
+		})
+	}, auth.RequireAuth)
+
+	body, err := json.Marshal(batch.BatchPayload{Requests: []batch.SubRequest{
+		{ID: "first", Method: http.MethodGet, Path: "/api/v1/me"},
+		// A sub-request can't switch to another identity
+		{ID: "other", Method: http.MethodGet, Path: "/api/v1/me", Headers: map[string]string{
+			middleware.APIKeyHeader: "other",
+		}},
+	}})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, batchPath, strings.NewReader(string(body)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(middleware.APIKeyHeader, "secret")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var response batch.BatchResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Responses, 2)
+
+	for _, sub := range response.Responses {
+		require.Equal(t, http.StatusOK, sub.Status, string(sub.Body))
+		require.JSONEq(t, `{"userId":"user_key_owner","apiKeyId":"key_1"}`, string(sub.Body))
+	}
+
+	// The key is verified for the batch only
+	require.EqualValues(t, 1, apiKeys.calls.Load())
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/blob"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+type BlobHandler struct {
+	Handler
+	blobs blob.Store
+}
+
+func NewBlobHandler(s *server.Server, blobs blob.Store) *BlobHandler {
+	return &BlobHandler{
+		Handler: NewHandler(s),
+		blobs:   blobs,
+	}
+}
+
+// GetBlob serves the download links of the filesystem storage driver, other drivers sign
+// links of their own and the route is not found
+func (h *BlobHandler) GetBlob(c echo.Context) error {
+	files, ok := h.blobs.(*blob.FileSystem)
+	if !ok {
+		return errs.NewNotFoundError("Route not found", false, nil)
+	}
+
+	objectPath, err := url.PathUnescape(c.Param("*"))
+	if err != nil {
+		return errs.NewNotFoundError("Resource not found", false, nil)
+	}
+
+	file, fileName, err := files.OpenSigned(objectPath, c.QueryParams())
+	if err != nil {
+		if errors.Is(err, blob.ErrInvalidLink) {
+			return errs.NewForbiddenError("the download link is invalid or expired", false)
+		}
+		if errors.Is(err, fs.ErrNotExist) {
+			return errs.NewNotFoundError("Resource not found", false, nil)
+		}
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	// Objects can be larger than the server's write timeout and the payload budget allow
+	if err := http.NewResponseController(c.Response()).SetWriteDeadline(time.Time{}); err != nil {
+		middleware.GetLogger(c).Debug().Err(err).Msg("could not lift the write deadline for the download")
+	}
+	middleware.LiftDeadline(c)
+	middleware.ExemptFromPayloadBudget(c)
+
+	header := c.Response().Header()
+	header.Set("Cache-Control", "private, max-age=3600")
+	if fileName != "" {
+		header.Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName))
+	}
+
+	http.ServeContent(c.Response(), c.Request(), path.Base(objectPath), info.ModTime(), file)
+	return nil
+}
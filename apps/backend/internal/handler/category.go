@@ -54,6 +54,13 @@ func (h *CategoryHandler) UpdateCategory(c echo.Context) error {
 		h.Handler,
 		func(c echo.Context, payload *category.UpdateCategoryPayload) (*category.Category, error) {
 			userID := middleware.GetUserID(c)
+
+			if _, _, err := checkIfMatch(c, func() (*category.Category, error) {
+				return h.categoryService.GetCategoryByID(c, userID, payload.ID)
+			}); err != nil {
+				return nil, err
+			}
+
 			return h.categoryService.UpdateCategory(c, userID, payload.ID, payload)
 		},
 		http.StatusOK,
@@ -66,9 +73,40 @@ func (h *CategoryHandler) DeleteCategory(c echo.Context) error {
 		h.Handler,
 		func(c echo.Context, payload *category.DeleteCategoryPayload) error {
 			userID := middleware.GetUserID(c)
+
+			if _, _, err := checkIfMatch(c, func() (*category.Category, error) {
+				return h.categoryService.GetCategoryByID(c, userID, payload.ID)
+			}); err != nil {
+				return err
+			}
+
 			return h.categoryService.DeleteCategory(c, userID, payload.ID)
 		},
 		http.StatusNoContent,
 		&category.DeleteCategoryPayload{},
 	)(c)
 }
+
+func (h *CategoryHandler) ExportCategoryBundle(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *category.ExportBundleQuery) (*category.Bundle, error) {
+			userID := middleware.GetUserID(c)
+			return h.categoryService.ExportBundle(c, userID, query)
+		},
+		http.StatusOK,
+		&category.ExportBundleQuery{},
+	)(c)
+}
+
+func (h *CategoryHandler) ImportCategoryBundle(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *category.ImportBundlePayload) (*category.ImportResult, error) {
+			userID := middleware.GetUserID(c)
+			return h.categoryService.ImportBundle(c, userID, payload)
+		},
+		http.StatusCreated,
+		&category.ImportBundlePayload{},
+	)(c)
+}
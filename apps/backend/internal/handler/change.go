@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/change"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type ChangeHandler struct {
+	Handler
+	changeService *service.ChangeService
+}
+
+func NewChangeHandler(s *server.Server, changeService *service.ChangeService) *ChangeHandler {
+	return &ChangeHandler{
+		Handler:       NewHandler(s),
+		changeService: changeService,
+	}
+}
+
+func (h *ChangeHandler) GetChanges(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *change.GetChangesQuery) (*change.ChangesResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.changeService.GetChanges(c, userID, query)
+		},
+		http.StatusOK,
+		&change.GetChangesQuery{},
+	)(c)
+}
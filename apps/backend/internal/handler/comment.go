@@ -5,6 +5,7 @@ import (
 
 	"github.com/Sameer16536/ExecuTask/internal/middleware"
 	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
 	"github.com/Sameer16536/ExecuTask/internal/server"
 	"github.com/Sameer16536/ExecuTask/internal/service"
 	"github.com/labstack/echo/v4"
@@ -46,6 +47,18 @@ func (h *CommentHandler) GetCommentsByTodoID(c echo.Context) error {
 	)(c)
 }
 
+func (h *CommentHandler) MarkCommentsRead(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *comment.MarkCommentsReadPayload) (*todo.Todo, error) {
+			userID := middleware.GetUserID(c)
+			return h.commentService.MarkCommentsRead(c, userID, payload.TodoID)
+		},
+		http.StatusOK,
+		&comment.MarkCommentsReadPayload{},
+	)(c)
+}
+
 func (h *CommentHandler) UpdateComment(c echo.Context) error {
 	return Handle(
 		h.Handler,
@@ -69,3 +82,17 @@ func (h *CommentHandler) DeleteComment(c echo.Context) error {
 		&comment.DeleteCommentPayload{},
 	)(c)
 }
+
+// ReceiveEmailReply is called by the inbound email provider, it authenticates with a shared
+// secret instead of a user session
+func (h *CommentHandler) ReceiveEmailReply(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *comment.EmailReplyPayload) error {
+			secret := c.Request().Header.Get(comment.InboundSecretHeader)
+			return h.commentService.ReceiveEmailReply(c, secret, payload)
+		},
+		http.StatusAccepted,
+		&comment.EmailReplyPayload{},
+	)(c)
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+// versionShim turns a service result into the representation of a specific API version.
+// Handlers and services stay version agnostic, only the shape on the wire changes.
+type versionShim func(result interface{}) interface{}
+
+var versionShims = map[string]versionShim{
+	middleware.APIVersionV2: envelopeV2,
+}
+
+type paginated interface {
+	Items() interface{}
+	Meta() model.PageMeta
+}
+
+// pageLinked is a page that carries links to its neighbours
+type pageLinked interface {
+	PageLinks() model.Links
+}
+
+// V2Envelope is the v2 response body: the resource under data, pagination under meta
+type V2Envelope struct {
+	Data  interface{}     `json:"data"`
+	Meta  *model.PageMeta `json:"meta,omitempty"`
+	Links model.Links     `json:"_links,omitempty"`
+}
+
+func adaptForVersion(c echo.Context, result interface{}) interface{} {
+	shim, ok := versionShims[middleware.GetAPIVersion(c)]
+	if !ok {
+		return result
+	}
+	return shim(result)
+}
+
+func envelopeV2(result interface{}) interface{} {
+	if page, ok := result.(paginated); ok {
+		meta := page.Meta()
+		envelope := V2Envelope{Data: page.Items(), Meta: &meta}
+		if linked, ok := result.(pageLinked); ok {
+			envelope.Links = linked.PageLinks()
+		}
+		return envelope
+	}
+	return V2Envelope{Data: result}
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/cover"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type CoverHandler struct {
+	Handler
+	coverService *service.CoverService
+}
+
+func NewCoverHandler(s *server.Server, coverService *service.CoverService) *CoverHandler {
+	return &CoverHandler{
+		Handler:      NewHandler(s),
+		coverService: coverService,
+	}
+}
+
+func (h *CoverHandler) GetCover(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *cover.GetCoverPayload) (*cover.Cover, error) {
+			userID := middleware.GetUserID(c)
+			return h.coverService.GetCover(c, userID, payload.TodoID)
+		},
+		http.StatusOK,
+		&cover.GetCoverPayload{},
+	)(c)
+}
+
+func (h *CoverHandler) SetCover(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *cover.SetCoverPayload) (*cover.Cover, error) {
+			userID := middleware.GetUserID(c)
+			return h.coverService.SetCover(c, userID, payload)
+		},
+		http.StatusAccepted,
+		&cover.SetCoverPayload{},
+	)(c)
+}
+
+func (h *CoverHandler) DeleteCover(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *cover.DeleteCoverPayload) error {
+			userID := middleware.GetUserID(c)
+			return h.coverService.DeleteCover(c, userID, payload.TodoID)
+		},
+		http.StatusNoContent,
+		&cover.DeleteCoverPayload{},
+	)(c)
+}
+
+func (h *CoverHandler) GetStockImages(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *cover.GetStockImagesPayload) ([]cover.StockImage, error) {
+			return h.coverService.GetStockImages(c)
+		},
+		http.StatusOK,
+		&cover.GetStockImagesPayload{},
+	)(c)
+}
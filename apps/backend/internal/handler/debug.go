@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/pprof"
+	runtimePprof "runtime/pprof"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+// DebugHandler serves the net/http/pprof profiles and goroutine dumps. Its responses are
+// raw profiles and text, not API resources, so they stay out of the OpenAPI document.
+type DebugHandler struct {
+	Handler
+}
+
+func NewDebugHandler(s *server.Server) *DebugHandler {
+	return &DebugHandler{
+		Handler: NewHandler(s),
+	}
+}
+
+// PprofIndex lists the available profiles
+func (h *DebugHandler) PprofIndex(c echo.Context) error {
+	// pprof.Index resolves profile names against /debug/pprof/, the group is mounted deeper
+	if name := c.Param("profile"); name != "" {
+		return h.serveProfile(c, pprof.Handler(name))
+	}
+
+	return h.serveProfile(c, http.HandlerFunc(pprof.Index))
+}
+
+func (h *DebugHandler) PprofCmdline(c echo.Context) error {
+	return h.serveProfile(c, http.HandlerFunc(pprof.Cmdline))
+}
+
+// PprofProfile records a CPU profile for ?seconds=, 30 by default
+func (h *DebugHandler) PprofProfile(c echo.Context) error {
+	return h.serveProfile(c, http.HandlerFunc(pprof.Profile))
+}
+
+func (h *DebugHandler) PprofSymbol(c echo.Context) error {
+	return h.serveProfile(c, http.HandlerFunc(pprof.Symbol))
+}
+
+// PprofTrace records an execution trace for ?seconds=, 1 by default
+func (h *DebugHandler) PprofTrace(c echo.Context) error {
+	return h.serveProfile(c, http.HandlerFunc(pprof.Trace))
+}
+
+// GoroutineDump writes the stack of every goroutine as plain text, the same dump an
+// unrecovered panic prints
+func (h *DebugHandler) GoroutineDump(c echo.Context) error {
+	profile := runtimePprof.Lookup("goroutine")
+	if profile == nil {
+		code := errs.CodeProfileNotFound
+		return errs.NewNotFoundError("goroutine profile not available", false, &code)
+	}
+
+	middleware.GetLogger(c).Info().Msg("Admin dumped goroutines")
+
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextPlainCharsetUTF8)
+	c.Response().Header().Set("Cache-Control", "no-store")
+	c.Response().WriteHeader(http.StatusOK)
+	return profile.WriteTo(c.Response(), 2)
+}
+
+func (h *DebugHandler) serveProfile(c echo.Context, profileHandler http.Handler) error {
+	// CPU profiles and traces run for as long as the caller asks
+	middleware.LiftDeadline(c)
+
+	middleware.GetLogger(c).Info().
+		Str("path", c.Request().URL.Path).
+		Msg("Admin requested profile")
+
+	c.Response().Header().Set("Cache-Control", "no-store")
+	profileHandler.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
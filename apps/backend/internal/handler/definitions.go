@@ -0,0 +1,898 @@
+package handler
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/openapi"
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/admin"
+	"github.com/Sameer16536/ExecuTask/internal/model/apiclient"
+	"github.com/Sameer16536/ExecuTask/internal/model/apikey"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/model/backup"
+	"github.com/Sameer16536/ExecuTask/internal/model/batch"
+	"github.com/Sameer16536/ExecuTask/internal/model/category"
+	"github.com/Sameer16536/ExecuTask/internal/model/change"
+	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/cover"
+	"github.com/Sameer16536/ExecuTask/internal/model/dependency"
+	"github.com/Sameer16536/ExecuTask/internal/model/export"
+	"github.com/Sameer16536/ExecuTask/internal/model/feature"
+	"github.com/Sameer16536/ExecuTask/internal/model/focus"
+	"github.com/Sameer16536/ExecuTask/internal/model/gamification"
+	"github.com/Sameer16536/ExecuTask/internal/model/health"
+	"github.com/Sameer16536/ExecuTask/internal/model/inbox"
+	"github.com/Sameer16536/ExecuTask/internal/model/locale"
+	"github.com/Sameer16536/ExecuTask/internal/model/magictag"
+	"github.com/Sameer16536/ExecuTask/internal/model/matrix"
+	"github.com/Sameer16536/ExecuTask/internal/model/note"
+	"github.com/Sameer16536/ExecuTask/internal/model/planner"
+	"github.com/Sameer16536/ExecuTask/internal/model/report"
+	"github.com/Sameer16536/ExecuTask/internal/model/retention"
+	"github.com/Sameer16536/ExecuTask/internal/model/rule"
+	"github.com/Sameer16536/ExecuTask/internal/model/search"
+	"github.com/Sameer16536/ExecuTask/internal/model/session"
+	"github.com/Sameer16536/ExecuTask/internal/model/stats"
+	"github.com/Sameer16536/ExecuTask/internal/model/statuspage"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/model/usage"
+	"github.com/Sameer16536/ExecuTask/internal/model/webhook"
+	"github.com/Sameer16536/ExecuTask/internal/model/workspace"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	readErrors  = []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError}
+	writeErrors = []int{
+		http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusConflict,
+		http.StatusPreconditionFailed, http.StatusInternalServerError,
+	}
+	sessionErrors = []int{
+		http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError,
+	}
+	adminErrors = []int{
+		http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound,
+		http.StatusInternalServerError,
+	}
+	webhookErrors = []int{
+		http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict,
+		http.StatusInternalServerError,
+	}
+)
+
+// operations are the typed endpoint definitions the OpenAPI document is generated
+// from, keyed by handler method. Paths come from the router so they can't drift.
+var operations = map[string]openapi.Operation{
+	"HealthHandler.GetStatusPage": {
+		ID: "getStatusPage", Summary: "Get the component health history and incidents of the status page", Tags: []string{"Health"},
+		Request: statuspage.GetPagePayload{}, Response: statuspage.Page{}, Errors: []int{http.StatusInternalServerError},
+		Public: true,
+	},
+	"HealthHandler.Liveness": {
+		ID: "getLiveness", Summary: "Check that the API process is alive", Tags: []string{"Health"},
+		Response: health.Report{}, Public: true,
+	},
+	"HealthHandler.Readiness": {
+		ID: "getReadiness", Summary: "Probe the dependencies of the API", Tags: []string{"Health"},
+		Response: health.Report{}, Errors: []int{http.StatusServiceUnavailable}, Public: true,
+	},
+
+	// Todos
+	"TodoHandler.CreateTodo": {
+		ID: "createTodo", Summary: "Create a todo", Tags: []string{"Todos"},
+		Request: todo.CreateTodoPayload{}, Response: todo.CreatedTodo{}, Status: http.StatusCreated, Errors: writeErrors,
+	},
+	"TodoHandler.GetTodos": {
+		ID: "getTodos", Summary: "List todos", Tags: []string{"Todos"},
+		Request: todo.GetTodosQuery{}, Response: model.PaginatedResponse[todo.PopulatedTodo]{}, Errors: readErrors,
+	},
+	"TodoHandler.ExportTodos": {
+		ID: "exportTodos", Summary: "Export todos as NDJSON or CSV", Tags: []string{"Todos"},
+		Request: todo.ExportTodosQuery{}, Produces: []string{MIMEApplicationNDJSON, MIMETextCSV}, Errors: readErrors,
+	},
+	"ExportHandler.ExportTodoListPDF": {
+		ID: "exportTodoListPdf", Summary: "Print the todos matching the filters as a PDF", Tags: []string{"Todos"},
+		Request: todo.ExportTodosPDFQuery{}, Produces: []string{MIMEApplicationPDF}, Errors: readErrors,
+	},
+	"TodoHandler.GetTodoStats": {
+		ID: "getTodoStats", Summary: "Get todo statistics", Tags: []string{"Todos"},
+		Request: todo.GetTodoStatsPayload{}, Response: todo.TodoStats{}, Errors: readErrors,
+	},
+	"TodoHandler.GetTodoByID": {
+		ID: "getTodoById", Summary: "Get a todo", Tags: []string{"Todos"},
+		Request: todo.GetTodoByIDPayload{}, Response: todo.PopulatedTodo{}, Errors: readErrors,
+	},
+	"ExportHandler.ExportTodoPDF": {
+		ID: "exportTodoPdf", Summary: "Print a todo with its subtasks, comments and attachments as a PDF", Tags: []string{"Todos"},
+		Request: todo.ExportTodoPDFPayload{}, Produces: []string{MIMEApplicationPDF}, Errors: readErrors,
+	},
+	"TodoHandler.UpdateTodo": {
+		ID: "updateTodo", Summary: "Update a todo", Tags: []string{"Todos"},
+		Request: todo.UpdateTodoPayload{}, Response: todo.Todo{}, Errors: writeErrors,
+	},
+	"TodoHandler.DeleteTodo": {
+		ID: "deleteTodo", Summary: "Delete a todo", Tags: []string{"Todos"},
+		Request: todo.DeleteTodoPayload{}, Status: http.StatusNoContent, Errors: writeErrors,
+	},
+	"TodoHandler.CompleteTodos": {
+		ID: "completeTodos", Summary: "Complete several todos at once", Tags: []string{"Todos"},
+		Request: todo.CompleteTodosPayload{}, Response: todo.BulkCompletion{}, Errors: writeErrors,
+	},
+	"TodoHandler.UncompleteTodo": {
+		ID: "uncompleteTodo", Summary: "Undo the completion of a todo shortly after it", Tags: []string{"Todos"},
+		Request: todo.UncompleteTodoPayload{}, Response: todo.Todo{}, Errors: writeErrors,
+	},
+	"TodoHandler.MarkTodoRead": {
+		ID: "markTodoRead", Summary: "Mark a todo and its comments as read", Tags: []string{"Todos"},
+		Request: todo.MarkTodoReadPayload{}, Response: todo.Todo{}, Errors: writeErrors,
+	},
+	"TodoHandler.MergeTodos": {
+		ID: "mergeTodos", Summary: "Merge duplicate todos into one", Tags: []string{"Todos"},
+		Request: todo.MergeTodosPayload{}, Response: todo.Todo{}, Errors: writeErrors,
+	},
+	"TodoHandler.SuggestSubtasks": {
+		ID: "suggestSubtasks", Summary: "Suggest a subtask breakdown of a todo", Tags: []string{"Todos"},
+		Request: todo.SuggestSubtasksPayload{}, Response: todo.SubtaskSuggestions{},
+		Errors: append([]int{http.StatusServiceUnavailable}, readErrors...),
+	},
+	"TodoHandler.GetTodoVersions": {
+		ID: "getTodoVersions", Summary: "List the kept versions of a todo", Tags: []string{"Todos"},
+		Request: todo.GetTodoVersionsPayload{}, Response: []todo.TodoVersion{}, Errors: readErrors,
+	},
+	"TodoHandler.RestoreTodoVersion": {
+		ID: "restoreTodoVersion", Summary: "Restore a todo to an earlier version", Tags: []string{"Todos"},
+		Request: todo.RestoreTodoVersionPayload{}, Response: todo.Todo{}, Errors: writeErrors,
+	},
+	"TodoHandler.UploadTodoAttachment": {
+		ID: "uploadTodoAttachment", Summary: "Upload a todo attachment", Tags: []string{"Todos"},
+		Request: todo.UploadTodoAttachmentPayload{}, Response: todo.TodoAttachment{}, Status: http.StatusCreated,
+		Errors: append([]int{http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType}, writeErrors...), Upload: "file",
+	},
+	"TodoHandler.DeleteTodoAttachment": {
+		ID: "deleteTodoAttachment", Summary: "Delete a todo attachment", Tags: []string{"Todos"},
+		Request: todo.DeleteTodoAttachmentPayload{}, Status: http.StatusNoContent, Errors: writeErrors,
+	},
+	"TodoHandler.GetAttachmentPresignedURL": {
+		ID: "getAttachmentPresignedUrl", Summary: "Get an attachment download URL", Tags: []string{"Todos"},
+		Request: todo.GetAttachmentPresignedURLPayload{}, Response: struct {
+			URL string `json:"url"`
+		}{}, Errors: append([]int{http.StatusConflict, http.StatusGone}, readErrors...),
+	},
+	"TodoHandler.GetAttachmentThumbnailURL": {
+		ID: "getAttachmentThumbnailUrl", Summary: "Get an image attachment thumbnail URL", Tags: []string{"Todos"},
+		Request: todo.GetAttachmentThumbnailURLPayload{}, Response: struct {
+			URL string `json:"url"`
+		}{}, Errors: readErrors,
+	},
+
+	// Comments
+	"CommentHandler.AddComment": {
+		ID: "addComment", Summary: "Comment on a todo", Tags: []string{"Comments"},
+		Request: comment.AddCommentPayload{}, Response: comment.Comment{}, Status: http.StatusCreated, Errors: writeErrors,
+	},
+	"CommentHandler.GetCommentsByTodoID": {
+		ID: "getCommentsByTodoId", Summary: "List comments of a todo", Tags: []string{"Comments"},
+		Request: comment.GetCommentsByTodoIDPayload{}, Response: []comment.Comment{}, Errors: readErrors,
+	},
+	"CommentHandler.MarkCommentsRead": {
+		ID: "markCommentsRead", Summary: "Mark the comments of a todo as read", Tags: []string{"Comments"},
+		Request: comment.MarkCommentsReadPayload{}, Response: todo.Todo{}, Errors: writeErrors,
+	},
+	"CommentHandler.UpdateComment": {
+		ID: "updateComment", Summary: "Update a comment", Tags: []string{"Comments"},
+		Request: comment.UpdateCommentPayload{}, Response: comment.Comment{}, Errors: writeErrors,
+	},
+	"CommentHandler.DeleteComment": {
+		ID: "deleteComment", Summary: "Delete a comment", Tags: []string{"Comments"},
+		Request: comment.DeleteCommentPayload{}, Status: http.StatusNoContent, Errors: writeErrors,
+	},
+	"CommentHandler.ReceiveEmailReply": {
+		ID: "receiveEmailReply", Summary: "Receive a reply to a notification email, for the inbound email provider", Tags: []string{"Comments"},
+		Request: comment.EmailReplyPayload{}, Status: http.StatusAccepted, Errors: writeErrors, Public: true,
+	},
+
+	// Categories
+	"CategoryHandler.CreateCategory": {
+		ID: "createCategory", Summary: "Create a category", Tags: []string{"Categories"},
+		Request: category.CreateCategoryPayload{}, Response: category.Category{}, Status: http.StatusCreated,
+		Errors: writeErrors,
+	},
+	"CategoryHandler.GetCategories": {
+		ID: "getCategories", Summary: "List categories", Tags: []string{"Categories"},
+		Request: category.GetCategoriesQuery{}, Response: model.PaginatedResponse[category.Category]{}, Errors: readErrors,
+	},
+	"CategoryHandler.UpdateCategory": {
+		ID: "updateCategory", Summary: "Update a category", Tags: []string{"Categories"},
+		Request: category.UpdateCategoryPayload{}, Response: category.Category{}, Errors: writeErrors,
+	},
+	"CategoryHandler.DeleteCategory": {
+		ID: "deleteCategory", Summary: "Delete a category", Tags: []string{"Categories"},
+		Request: category.DeleteCategoryPayload{}, Status: http.StatusNoContent, Errors: writeErrors,
+	},
+	"CategoryHandler.ExportCategoryBundle": {
+		ID: "exportCategoryBundle", Summary: "Export categories as a shareable bundle", Tags: []string{"Categories"},
+		Request: category.ExportBundleQuery{}, Response: category.Bundle{}, Errors: readErrors,
+	},
+	"CategoryHandler.ImportCategoryBundle": {
+		ID: "importCategoryBundle", Summary: "Import a category bundle", Tags: []string{"Categories"},
+		Request: category.ImportBundlePayload{}, Response: category.ImportResult{}, Status: http.StatusCreated,
+		Errors: writeErrors,
+	},
+
+	// Sync, batch and change feed
+	"SyncHandler.SyncTodos": {
+		ID: "syncTodos", Summary: "Push offline changes and pull updates", Tags: []string{"Sync"},
+		Request: todo.SyncTodosPayload{}, Response: todo.SyncResponse{}, Errors: writeErrors,
+	},
+	"BatchHandler.ExecuteBatch": {
+		ID: "executeBatch", Summary: "Execute several requests at once", Tags: []string{"Batch"},
+		Request: batch.BatchPayload{}, Response: batch.BatchResponse{}, Errors: writeErrors,
+	},
+	"ChangeHandler.GetChanges": {
+		ID: "getChanges", Summary: "List changes after a cursor", Tags: []string{"Changes"},
+		Request: change.GetChangesQuery{}, Response: change.ChangesResponse{},
+		Errors: append([]int{http.StatusGone}, readErrors...),
+	},
+
+	// Stats
+	"StatsHandler.GetOverview": {
+		ID: "getStatsOverview", Summary: "Get dashboard stats", Tags: []string{"Stats"},
+		Request: stats.GetOverviewQuery{}, Response: stats.Overview{}, Errors: readErrors,
+	},
+	"StatsHandler.GetProductivity": {
+		ID: "getStatsProductivity", Summary: "Get productivity analytics over a window", Tags: []string{"Stats"},
+		Request: stats.GetProductivityQuery{}, Response: stats.Productivity{}, Errors: readErrors,
+	},
+
+	// Search
+	"SearchHandler.Search": {
+		ID: "searchTodos", Summary: "Search todos by keyword or meaning", Tags: []string{"Search"},
+		Request: search.SearchQuery{}, Response: search.Results{},
+		Errors: append([]int{http.StatusServiceUnavailable}, readErrors...),
+	},
+	"SearchHandler.Suggest": {
+		ID: "suggest", Summary: "Suggest todos, categories and tags as the user types", Tags: []string{"Search"},
+		Request: search.SuggestQuery{}, Response: search.Suggestions{}, Errors: readErrors,
+	},
+
+	// Usage
+	"UsageHandler.GetUsage": {
+		ID: "getUsage", Summary: "Get metered usage of the user or their workspace", Tags: []string{"Usage"},
+		Request: usage.GetUsageQuery{}, Response: usage.Usage{}, Errors: readErrors,
+	},
+	"UsageHandler.GetAPIUsage": {
+		ID: "getApiUsage", Summary: "Summarize the API usage of the caller over the last 30 days", Tags: []string{"Usage"},
+		Request: usage.GetAPIUsagePayload{}, Response: usage.APIUsage{}, Errors: readErrors,
+	},
+
+	// Exports
+	"ExportHandler.RequestAccountExport": {
+		ID: "requestAccountExport", Summary: "Request an export of all account data", Tags: []string{"Exports"},
+		Request: export.RequestAccountExportPayload{}, Response: export.AccountExport{}, Status: http.StatusAccepted,
+		Errors: writeErrors,
+	},
+	"ExportHandler.GetAccountExport": {
+		ID: "getAccountExport", Summary: "Get an account export and its download link", Tags: []string{"Exports"},
+		Request: export.GetAccountExportPayload{}, Response: export.AccountExport{}, Errors: readErrors,
+	},
+	"ExportHandler.RequestTodoListExport": {
+		ID: "requestTodoListExport", Summary: "Request a PDF of the todos matching the filters", Tags: []string{"Exports"},
+		Request: export.RequestTodoListExportPayload{}, Response: export.AccountExport{}, Status: http.StatusAccepted,
+		Errors: writeErrors,
+	},
+	"ExportHandler.GetTodoListExport": {
+		ID: "getTodoListExport", Summary: "Get a todo list PDF export and its download link", Tags: []string{"Exports"},
+		Request: export.GetTodoListExportPayload{}, Response: export.AccountExport{}, Errors: readErrors,
+	},
+
+	// Automation rules
+	"RuleHandler.CreateRule": {
+		ID: "createRule", Summary: "Create an automation rule", Tags: []string{"Rules"},
+		Request: rule.CreateRulePayload{}, Response: rule.Rule{}, Status: http.StatusCreated, Errors: writeErrors,
+	},
+	"RuleHandler.GetRules": {
+		ID: "getRules", Summary: "List automation rules", Tags: []string{"Rules"},
+		Request: rule.GetRulesQuery{}, Response: []rule.Rule{}, Errors: readErrors,
+	},
+	"RuleHandler.GetRule": {
+		ID: "getRule", Summary: "Get an automation rule", Tags: []string{"Rules"},
+		Request: rule.GetRulePayload{}, Response: rule.Rule{}, Errors: readErrors,
+	},
+	"RuleHandler.UpdateRule": {
+		ID: "updateRule", Summary: "Update an automation rule", Tags: []string{"Rules"},
+		Request: rule.UpdateRulePayload{}, Response: rule.Rule{}, Errors: writeErrors,
+	},
+	"RuleHandler.DeleteRule": {
+		ID: "deleteRule", Summary: "Delete an automation rule", Tags: []string{"Rules"},
+		Request: rule.DeleteRulePayload{}, Status: http.StatusNoContent, Errors: writeErrors,
+	},
+	"RuleHandler.GetRuleExecutions": {
+		ID: "getRuleExecutions", Summary: "List the latest executions of an automation rule", Tags: []string{"Rules"},
+		Request: rule.GetExecutionsQuery{}, Response: []rule.Execution{}, Errors: readErrors,
+	},
+
+	"APIKeyHandler.GetKeys": {
+		ID: "getAPIKeys", Summary: "List the API keys of the user", Tags: []string{"API keys"},
+		Request: apikey.GetKeysPayload{}, Response: []apikey.Key{}, Errors: readErrors, SessionOnly: true,
+	},
+	"APIKeyHandler.CreateKey": {
+		ID: "createAPIKey", Summary: "Create an API key, its secret is only returned once", Tags: []string{"API keys"},
+		Request: apikey.CreateKeyPayload{}, Response: apikey.CreatedKey{}, Status: http.StatusCreated,
+		Errors: writeErrors, SessionOnly: true,
+	},
+	"APIKeyHandler.DeleteKey": {
+		ID: "deleteAPIKey", Summary: "Revoke an API key", Tags: []string{"API keys"},
+		Request: apikey.DeleteKeyPayload{}, Status: http.StatusNoContent, Errors: writeErrors, SessionOnly: true,
+	},
+
+	// Webhooks
+	"WebhookHandler.GetEventTypes": {
+		ID: "getWebhookEventTypes", Summary: "List the webhook event types with the JSON Schema of their payloads",
+		Tags: []string{"Webhooks"}, Request: webhook.GetEventTypesPayload{}, Response: []webhook.EventType{},
+		Errors: readErrors,
+	},
+	"WebhookHandler.GetEndpoints": {
+		ID: "getWebhookEndpoints", Summary: "List the webhook endpoints of the workspace", Tags: []string{"Webhooks"},
+		Request: webhook.GetEndpointsPayload{}, Response: []webhook.Endpoint{}, Errors: adminErrors,
+	},
+	"WebhookHandler.CreateEndpoint": {
+		ID: "createWebhookEndpoint", Summary: "Register a webhook endpoint, its signing secret is only returned once",
+		Tags: []string{"Webhooks"}, Request: webhook.CreateEndpointPayload{}, Response: webhook.CreatedEndpoint{},
+		Status: http.StatusCreated, Errors: webhookErrors,
+	},
+	"WebhookHandler.UpdateEndpoint": {
+		ID: "updateWebhookEndpoint", Summary: "Update a webhook endpoint", Tags: []string{"Webhooks"},
+		Request: webhook.UpdateEndpointPayload{}, Response: webhook.Endpoint{}, Errors: webhookErrors,
+	},
+	"WebhookHandler.DeleteEndpoint": {
+		ID: "deleteWebhookEndpoint", Summary: "Delete a webhook endpoint", Tags: []string{"Webhooks"},
+		Request: webhook.DeleteEndpointPayload{}, Status: http.StatusNoContent, Errors: webhookErrors,
+	},
+	"WebhookHandler.TestEndpoint": {
+		ID: "testWebhookEndpoint", Summary: "Send a signed sample payload to a webhook endpoint", Tags: []string{"Webhooks"},
+		Request: webhook.TestEndpointPayload{}, Response: webhook.TestDelivery{}, Errors: webhookErrors,
+	},
+
+	// Cookie sessions
+	"SessionHandler.CreateSession": {
+		ID: "createCookieSession", Summary: "Move the session token into an HTTP-only cookie", Tags: []string{"Sessions"},
+		Request: session.CreateSessionPayload{}, Response: session.CookieSession{}, Status: http.StatusCreated,
+		Errors: sessionErrors, SessionOnly: true,
+	},
+	"SessionHandler.DeleteSession": {
+		ID: "deleteCookieSession", Summary: "Clear the cookies of the cookie session", Tags: []string{"Sessions"},
+		Request: session.DeleteSessionPayload{}, Status: http.StatusNoContent, Errors: sessionErrors, SessionOnly: true,
+	},
+	"SessionHandler.GetCSRFToken": {
+		ID: "getCSRFToken", Summary: "Get the CSRF token to send with the unsafe requests of a cookie session",
+		Tags: []string{"Sessions"}, Request: session.GetCSRFTokenPayload{}, Response: session.CSRFToken{},
+		Errors: sessionErrors, SessionOnly: true,
+	},
+	"MagicTagHandler.GetMagicTags": {
+		ID: "getMagicTags", Summary: "List the magic tags applied to todo titles", Tags: []string{"Magic tags"},
+		Request: magictag.GetMagicTagsPayload{}, Response: []magictag.MagicTag{}, Errors: readErrors,
+	},
+	"MagicTagHandler.CreateMagicTag": {
+		ID: "createMagicTag", Summary: "Create a magic tag such as #tomorrow or #p1", Tags: []string{"Magic tags"},
+		Request: magictag.CreateMagicTagPayload{}, Response: magictag.MagicTag{}, Status: http.StatusCreated,
+		Errors: writeErrors,
+	},
+	"MagicTagHandler.UpdateMagicTag": {
+		ID: "updateMagicTag", Summary: "Replace a magic tag", Tags: []string{"Magic tags"},
+		Request: magictag.UpdateMagicTagPayload{}, Response: magictag.MagicTag{}, Errors: writeErrors,
+	},
+	"MagicTagHandler.DeleteMagicTag": {
+		ID: "deleteMagicTag", Summary: "Delete a magic tag", Tags: []string{"Magic tags"},
+		Request: magictag.DeleteMagicTagPayload{}, Status: http.StatusNoContent, Errors: writeErrors,
+	},
+
+	// Reports
+	"ReportHandler.GetSchedules": {
+		ID: "getReportSchedules", Summary: "List the scheduled reports", Tags: []string{"Reports"},
+		Request: report.GetSchedulesPayload{}, Response: []report.Schedule{}, Errors: readErrors,
+	},
+	"ReportHandler.CreateSchedule": {
+		ID: "createReportSchedule", Summary: "Schedule a report emailed to its recipients", Tags: []string{"Reports"},
+		Request: report.CreateSchedulePayload{}, Response: report.Schedule{}, Status: http.StatusCreated,
+		Errors: writeErrors,
+	},
+	"ReportHandler.GetSchedule": {
+		ID: "getReportSchedule", Summary: "Get a scheduled report", Tags: []string{"Reports"},
+		Request: report.GetSchedulePayload{}, Response: report.Schedule{}, Errors: readErrors,
+	},
+	"ReportHandler.UpdateSchedule": {
+		ID: "updateReportSchedule", Summary: "Replace a scheduled report", Tags: []string{"Reports"},
+		Request: report.UpdateSchedulePayload{}, Response: report.Schedule{}, Errors: writeErrors,
+	},
+	"ReportHandler.PauseSchedule": {
+		ID: "pauseReportSchedule", Summary: "Stop sending a scheduled report", Tags: []string{"Reports"},
+		Request: report.PauseSchedulePayload{}, Response: report.Schedule{}, Errors: writeErrors,
+	},
+	"ReportHandler.ResumeSchedule": {
+		ID: "resumeReportSchedule", Summary: "Send a paused report again from its next time due", Tags: []string{"Reports"},
+		Request: report.ResumeSchedulePayload{}, Response: report.Schedule{}, Errors: writeErrors,
+	},
+	"ReportHandler.DeleteSchedule": {
+		ID: "deleteReportSchedule", Summary: "Delete a scheduled report", Tags: []string{"Reports"},
+		Request: report.DeleteSchedulePayload{}, Status: http.StatusNoContent, Errors: writeErrors,
+	},
+
+	// Planner
+	"PlannerHandler.GetPlanner": {
+		ID: "getPlanner", Summary: "Get the todos of a week bucketed by day", Tags: []string{"Planner"},
+		Request: planner.GetPlannerQuery{}, Response: planner.Planner{}, Errors: readErrors,
+	},
+	"PlannerHandler.GetForecast": {
+		ID: "getForecast", Summary: "Predict when open todos get done going by past estimates", Tags: []string{"Planner"},
+		Request: planner.GetForecastQuery{}, Response: planner.Forecast{}, Errors: readErrors,
+	},
+	"PlannerHandler.Schedule": {
+		ID: "scheduleTodos", Summary: "Assign due dates to todos in bulk", Tags: []string{"Planner"},
+		Request: planner.SchedulePayload{}, Response: []todo.Todo{}, Errors: writeErrors,
+	},
+	"PlannerHandler.GetMyDay": {
+		ID: "getMyDay", Summary: "Get the todos picked for today and those left open to review", Tags: []string{"Planner"},
+		Request: planner.GetMyDayQuery{}, Response: planner.MyDay{}, Errors: readErrors,
+	},
+	"PlannerHandler.AddMyDayTodos": {
+		ID: "addMyDayTodos", Summary: "Pick todos for today without changing their due dates", Tags: []string{"Planner"},
+		Request: planner.AddMyDayTodosPayload{}, Response: planner.MyDay{}, Errors: writeErrors,
+	},
+	"PlannerHandler.RemoveMyDayTodo": {
+		ID: "removeMyDayTodo", Summary: "Take a todo off today or drop it from review", Tags: []string{"Planner"},
+		Request: planner.RemoveMyDayTodoPayload{}, Status: http.StatusNoContent, Errors: writeErrors,
+	},
+	"PlannerHandler.GetDaySummaries": {
+		ID: "getDaySummaries", Summary: "Get how much of the todos picked for each day got done", Tags: []string{"Planner"},
+		Request: planner.GetDaySummariesQuery{}, Response: []planner.DaySummary{}, Errors: readErrors,
+	},
+
+	// Eisenhower matrix
+	"MatrixHandler.GetMatrix": {
+		ID: "getMatrix", Summary: "Get the open todos bucketed into urgent and important quadrants", Tags: []string{"Matrix"},
+		Request: matrix.GetMatrixQuery{}, Response: matrix.Matrix{}, Errors: readErrors,
+	},
+	"MatrixHandler.GetSettings": {
+		ID: "getMatrixSettings", Summary: "Get the thresholds of the matrix", Tags: []string{"Matrix"},
+		Request: matrix.GetSettingsPayload{}, Response: matrix.Settings{}, Errors: readErrors,
+	},
+	"MatrixHandler.UpdateSettings": {
+		ID: "updateMatrixSettings", Summary: "Update the thresholds of the matrix", Tags: []string{"Matrix"},
+		Request: matrix.UpdateSettingsPayload{}, Response: matrix.Settings{}, Errors: writeErrors,
+	},
+
+	// Locale
+	"LocaleHandler.GetPreference": {
+		ID: "getLocale", Summary: "Get the locale the user chose", Tags: []string{"Locale"},
+		Request: locale.GetPreferencePayload{}, Response: locale.Preference{}, Errors: readErrors,
+	},
+	"LocaleHandler.UpdatePreference": {
+		ID: "updateLocale", Summary: "Choose the locale of responses and emails", Tags: []string{"Locale"},
+		Request: locale.UpdatePreferencePayload{}, Response: locale.Preference{}, Errors: writeErrors,
+	},
+	"LocaleHandler.ResetPreference": {
+		ID: "resetLocale", Summary: "Follow the Accept-Language of requests again", Tags: []string{"Locale"},
+		Request: locale.ResetPreferencePayload{}, Status: http.StatusNoContent, Errors: writeErrors,
+	},
+
+	// Focus sessions
+	"FocusHandler.StartSession": {
+		ID: "startFocusSession", Summary: "Start a focus session on a todo", Tags: []string{"Focus"},
+		Request: focus.StartSessionPayload{}, Response: focus.Session{}, Status: http.StatusCreated, Errors: writeErrors,
+	},
+	"FocusHandler.GetSessions": {
+		ID: "getFocusSessions", Summary: "List the latest focus sessions", Tags: []string{"Focus"},
+		Request: focus.GetSessionsQuery{}, Response: []focus.Session{}, Errors: readErrors,
+	},
+	"FocusHandler.GetCurrentSession": {
+		ID: "getCurrentFocusSession", Summary: "Get the open focus session", Tags: []string{"Focus"},
+		Request: focus.GetCurrentSessionPayload{}, Response: focus.Session{}, Errors: readErrors,
+	},
+	"FocusHandler.PauseSession": {
+		ID: "pauseFocusSession", Summary: "Pause the active focus session", Tags: []string{"Focus"},
+		Request: focus.SessionPayload{}, Response: focus.Session{}, Errors: writeErrors,
+	},
+	"FocusHandler.ResumeSession": {
+		ID: "resumeFocusSession", Summary: "Resume a paused focus session", Tags: []string{"Focus"},
+		Request: focus.SessionPayload{}, Response: focus.Session{}, Errors: writeErrors,
+	},
+	"FocusHandler.CompleteSession": {
+		ID: "completeFocusSession", Summary: "Complete a focus session", Tags: []string{"Focus"},
+		Request: focus.SessionPayload{}, Response: focus.Session{}, Errors: writeErrors,
+	},
+
+	// Gamification
+	"GamificationHandler.GetOverview": {
+		ID: "getGamification", Summary: "Get streaks, badges and weekly goal progress", Tags: []string{"Gamification"},
+		Request: gamification.GetOverviewPayload{}, Response: gamification.Overview{}, Errors: readErrors,
+	},
+	"GamificationHandler.SetGoal": {
+		ID: "setWeeklyGoal", Summary: "Set the weekly completion goal", Tags: []string{"Gamification"},
+		Request: gamification.SetGoalPayload{}, Response: gamification.GoalProgress{}, Errors: writeErrors,
+	},
+	"GamificationHandler.DeleteGoal": {
+		ID: "deleteWeeklyGoal", Summary: "Remove the weekly completion goal", Tags: []string{"Gamification"},
+		Request: gamification.DeleteGoalPayload{}, Status: http.StatusNoContent, Errors: writeErrors,
+	},
+
+	"CoverHandler.GetStockImages": {
+		ID: "getStockCoverImages", Summary: "List the stock images todo covers can be picked from", Tags: []string{"Covers"},
+		Request: cover.GetStockImagesPayload{}, Response: []cover.StockImage{}, Errors: readErrors,
+	},
+	"CoverHandler.GetCover": {
+		ID: "getTodoCover", Summary: "Get the cover of a todo with links to its thumbnails", Tags: []string{"Covers"},
+		Request: cover.GetCoverPayload{}, Response: cover.Cover{}, Errors: readErrors,
+	},
+	"CoverHandler.SetCover": {
+		ID: "setTodoCover", Summary: "Set the cover of a todo from an image attachment or a stock image", Tags: []string{"Covers"},
+		Request: cover.SetCoverPayload{}, Response: cover.Cover{}, Status: http.StatusAccepted,
+		Errors: append([]int{http.StatusGone}, writeErrors...),
+	},
+	"CoverHandler.DeleteCover": {
+		ID: "deleteTodoCover", Summary: "Remove the cover of a todo", Tags: []string{"Covers"},
+		Request: cover.DeleteCoverPayload{}, Status: http.StatusNoContent, Errors: writeErrors,
+	},
+
+	"NoteHandler.GetNote": {
+		ID: "getTodoPrivateNote", Summary: "Get the private note the user wrote on a todo", Tags: []string{"Notes"},
+		Request: note.GetNotePayload{}, Response: note.Note{}, Errors: readErrors,
+	},
+	"NoteHandler.SetNote": {
+		ID: "setTodoPrivateNote", Summary: "Write a private note on a todo, only its author can read it", Tags: []string{"Notes"},
+		Request: note.SetNotePayload{}, Response: note.Note{}, Errors: writeErrors,
+	},
+	"NoteHandler.DeleteNote": {
+		ID: "deleteTodoPrivateNote", Summary: "Delete the private note the user wrote on a todo", Tags: []string{"Notes"},
+		Request: note.DeleteNotePayload{}, Status: http.StatusNoContent, Errors: writeErrors,
+	},
+
+	"DependencyHandler.GetDependencies": {
+		ID: "getTodoDependencies", Summary: "List the todos a todo waits on and the ones waiting on it", Tags: []string{"Dependencies"},
+		Request: dependency.GetDependenciesPayload{}, Response: dependency.Dependencies{}, Errors: readErrors,
+	},
+	"DependencyHandler.AddDependency": {
+		ID: "addTodoDependency", Summary: "Make a todo wait on another", Tags: []string{"Dependencies"},
+		Request: dependency.AddDependencyPayload{}, Response: dependency.Dependency{}, Status: http.StatusCreated, Errors: writeErrors,
+	},
+	"DependencyHandler.RemoveDependency": {
+		ID: "removeTodoDependency", Summary: "Stop a todo waiting on another", Tags: []string{"Dependencies"},
+		Request: dependency.RemoveDependencyPayload{}, Status: http.StatusNoContent, Errors: writeErrors,
+	},
+	"DependencyHandler.PreviewDueDateShift": {
+		ID: "previewDueDateShift", Summary: "Preview moving a due date along with the todos waiting on it", Tags: []string{"Dependencies"},
+		Request: dependency.ShiftDueDatePayload{}, Response: dependency.Shift{}, Errors: writeErrors,
+	},
+	"DependencyHandler.ShiftDueDate": {
+		ID: "shiftDueDate", Summary: "Move a due date along with the todos waiting on it", Tags: []string{"Dependencies"},
+		Request: dependency.ShiftDueDatePayload{}, Response: dependency.Shift{}, Errors: writeErrors,
+	},
+
+	"InboxHandler.CaptureItem": {
+		ID: "captureInboxItem", Summary: "Capture a title into the inbox", Tags: []string{"Inbox"},
+		Request: inbox.CapturePayload{}, Response: inbox.Item{}, Status: http.StatusCreated, Errors: writeErrors,
+	},
+	"InboxHandler.GetItems": {
+		ID: "getInboxItems", Summary: "List the inbox, oldest first", Tags: []string{"Inbox"},
+		Request: inbox.GetItemsQuery{}, Response: []inbox.Item{}, Errors: readErrors,
+	},
+	"InboxHandler.TriageItem": {
+		ID: "triageInboxItem", Summary: "Promote an inbox item into a todo", Tags: []string{"Inbox"},
+		Request: inbox.TriagePayload{}, Response: todo.Todo{}, Status: http.StatusCreated, Errors: writeErrors,
+	},
+	"InboxHandler.DiscardItem": {
+		ID: "discardInboxItem", Summary: "Discard an inbox item", Tags: []string{"Inbox"},
+		Request: inbox.DiscardPayload{}, Status: http.StatusNoContent, Errors: writeErrors,
+	},
+
+	// Workspace templates
+	"WorkspaceHandler.GetTemplates": {
+		ID: "getWorkspaceTemplates", Summary: "List the templates of the active workspace", Tags: []string{"Templates"},
+		Request: workspace.GetActiveTemplatesPayload{}, Response: []workspace.Template{}, Errors: readErrors,
+	},
+	"WorkspaceHandler.ApplyTemplate": {
+		ID: "applyWorkspaceTemplate", Summary: "Create a todo and its subtasks from a workspace template", Tags: []string{"Templates"},
+		Request: workspace.ApplyTemplatePayload{}, Response: todo.Todo{}, Status: http.StatusCreated, Errors: writeErrors,
+	},
+	"WorkspaceHandler.GetWorkload": {
+		ID: "getWorkspaceWorkload", Summary: "Sum the open todos and estimates of every member per week", Tags: []string{"Workspaces"},
+		Request: workspace.GetWorkloadQuery{}, Response: workspace.Workload{}, Errors: append([]int{http.StatusForbidden}, readErrors...),
+	},
+	"WorkspaceHandler.GetBreachReport": {
+		ID: "getWorkspaceSLABreaches", Summary: "List the open todos breaching the aging policies of the workspace", Tags: []string{"Workspaces"},
+		Request: workspace.GetBreachReportPayload{}, Response: workspace.BreachReport{}, Errors: append([]int{http.StatusForbidden}, readErrors...),
+	},
+	"WorkspaceHandler.GetWorkflow": {
+		ID: "getWorkspaceWorkflow", Summary: "Get the statuses and priorities of the workspace", Tags: []string{"Workspaces"},
+		Request: workspace.GetWorkspaceWorkflowPayload{}, Response: workspace.Workflow{}, Errors: append([]int{http.StatusForbidden}, readErrors...),
+	},
+	"WorkspaceHandler.AcceptInvite": {
+		ID: "acceptWorkspaceInvite", Summary: "Join a workspace through an invite link", Tags: []string{"Workspaces"},
+		Request: workspace.AcceptInvitePayload{}, Response: workspace.InviteAcceptance{},
+		Errors: append([]int{http.StatusForbidden, http.StatusGone}, writeErrors...),
+	},
+	"WorkspaceHandler.GetWorkspaceTemplates": {
+		ID: "getManagedWorkspaceTemplates", Summary: "List the templates of a workspace", Tags: []string{"Templates"},
+		Request: workspace.GetTemplatesPayload{}, Response: []workspace.Template{}, Errors: adminErrors,
+	},
+	"WorkspaceHandler.CreateTemplate": {
+		ID: "createWorkspaceTemplate", Summary: "Publish a todo template to a workspace", Tags: []string{"Templates"},
+		Request: workspace.CreateTemplatePayload{}, Response: workspace.Template{}, Status: http.StatusCreated,
+		Errors: append([]int{http.StatusConflict}, adminErrors...),
+	},
+	"WorkspaceHandler.DeleteTemplate": {
+		ID: "deleteWorkspaceTemplate", Summary: "Remove a template of a workspace", Tags: []string{"Templates"},
+		Request: workspace.DeleteTemplatePayload{}, Status: http.StatusNoContent,
+		Errors: append([]int{http.StatusConflict}, adminErrors...),
+	},
+	"WorkspaceHandler.GetOnboardingKit": {
+		ID: "getWorkspaceOnboardingKit", Summary: "Get the onboarding kit of a workspace", Tags: []string{"Workspaces"},
+		Request: workspace.GetOnboardingKitPayload{}, Response: workspace.OnboardingKit{}, Errors: adminErrors,
+	},
+	"WorkspaceHandler.SetOnboardingKit": {
+		ID: "setWorkspaceOnboardingKit", Summary: "Set the categories and todos new members of a workspace get", Tags: []string{"Workspaces"},
+		Request: workspace.SetOnboardingKitPayload{}, Response: workspace.OnboardingKit{},
+		Errors: append([]int{http.StatusConflict}, adminErrors...),
+	},
+	"WorkspaceHandler.DeleteOnboardingKit": {
+		ID: "deleteWorkspaceOnboardingKit", Summary: "Remove the onboarding kit of a workspace", Tags: []string{"Workspaces"},
+		Request: workspace.DeleteOnboardingKitPayload{}, Status: http.StatusNoContent,
+		Errors: append([]int{http.StatusConflict}, adminErrors...),
+	},
+	"WorkspaceHandler.GetAgingPolicies": {
+		ID: "getWorkspaceAgingPolicies", Summary: "List the aging policies of a workspace", Tags: []string{"Workspaces"},
+		Request: workspace.GetAgingPoliciesPayload{}, Response: []workspace.AgingPolicy{}, Errors: adminErrors,
+	},
+	"WorkspaceHandler.CreateAgingPolicy": {
+		ID: "createWorkspaceAgingPolicy", Summary: "Escalate the todos of a workspace left open too long", Tags: []string{"Workspaces"},
+		Request: workspace.CreateAgingPolicyPayload{}, Response: workspace.AgingPolicy{}, Status: http.StatusCreated,
+		Errors: append([]int{http.StatusConflict}, adminErrors...),
+	},
+	"WorkspaceHandler.DeleteAgingPolicy": {
+		ID: "deleteWorkspaceAgingPolicy", Summary: "Remove an aging policy of a workspace", Tags: []string{"Workspaces"},
+		Request: workspace.DeleteAgingPolicyPayload{}, Status: http.StatusNoContent,
+		Errors: append([]int{http.StatusConflict}, adminErrors...),
+	},
+	"WorkspaceHandler.GetInvites": {
+		ID: "getWorkspaceInvites", Summary: "List the pending and closed invites of a workspace and who accepted them", Tags: []string{"Workspaces"},
+		Request: workspace.GetInvitesPayload{}, Response: workspace.Invitations{}, Errors: adminErrors,
+	},
+	"WorkspaceHandler.CreateInvite": {
+		ID: "createWorkspaceInvite", Summary: "Create a shareable invite link to a workspace", Tags: []string{"Workspaces"},
+		Request: workspace.CreateInvitePayload{}, Response: workspace.Invite{}, Status: http.StatusCreated,
+		Errors: append([]int{http.StatusConflict}, adminErrors...),
+	},
+	"WorkspaceHandler.RevokeInvite": {
+		ID: "revokeWorkspaceInvite", Summary: "Stop an invite link of a workspace from being accepted", Tags: []string{"Workspaces"},
+		Request: workspace.RevokeInvitePayload{}, Status: http.StatusNoContent,
+		Errors: append([]int{http.StatusConflict}, adminErrors...),
+	},
+	"WorkspaceHandler.GetFreeze": {
+		ID: "getWorkspaceFreeze", Summary: "Get the freeze of a workspace and when its data is purged", Tags: []string{"Workspaces"},
+		Request: workspace.GetFreezePayload{}, Response: workspace.Freeze{}, Errors: adminErrors,
+	},
+	"WorkspaceHandler.FreezeWorkspace": {
+		ID: "freezeWorkspace", Summary: "Freeze a workspace, export it and schedule its data for deletion", Tags: []string{"Workspaces"},
+		Request: workspace.FreezeWorkspacePayload{}, Response: workspace.Freeze{}, Status: http.StatusCreated,
+		Errors: append([]int{http.StatusConflict}, adminErrors...),
+	},
+	"WorkspaceHandler.ReactivateWorkspace": {
+		ID: "reactivateWorkspace", Summary: "Lift the freeze of a workspace not purged yet", Tags: []string{"Workspaces"},
+		Request: workspace.ReactivateWorkspacePayload{}, Status: http.StatusNoContent,
+		Errors: append([]int{http.StatusGone}, adminErrors...),
+	},
+	"WorkspaceHandler.SetWorkflow": {
+		ID: "setWorkspaceWorkflow", Summary: "Replace the statuses and priority scale of a workspace, moving its todos onto them", Tags: []string{"Workspaces"},
+		Request: workspace.SetWorkflowPayload{}, Response: workspace.Workflow{},
+		Errors: append([]int{http.StatusConflict}, adminErrors...),
+	},
+	"WorkspaceHandler.DeleteWorkflow": {
+		ID: "deleteWorkspaceWorkflow", Summary: "Bring a workspace back to the built-in statuses and priorities", Tags: []string{"Workspaces"},
+		Request: workspace.DeleteWorkflowPayload{}, Status: http.StatusNoContent,
+		Errors: append([]int{http.StatusConflict}, adminErrors...),
+	},
+
+	// Admin
+	"AdminHandler.GetUser": {
+		ID: "adminGetUser", Summary: "Look up a user", Tags: []string{"Admin"},
+		Request: admin.GetUserPayload{}, Response: admin.User{}, Errors: adminErrors,
+	},
+	"AdminHandler.GetStorageQuota": {
+		ID: "adminGetStorageQuota", Summary: "Get the attachment storage use and quota of a user", Tags: []string{"Admin"},
+		Request: admin.GetStorageQuotaPayload{}, Response: admin.UserStorage{}, Errors: adminErrors,
+	},
+	"AdminHandler.SetStorageQuota": {
+		ID: "adminSetStorageQuota", Summary: "Override the attachment storage quota of a user", Tags: []string{"Admin"},
+		Request: admin.SetStorageQuotaPayload{}, Response: admin.UserStorage{}, Errors: adminErrors,
+	},
+	"AdminHandler.DeleteStorageQuota": {
+		ID: "adminDeleteStorageQuota", Summary: "Remove the storage quota override of a user", Tags: []string{"Admin"},
+		Request: admin.DeleteStorageQuotaPayload{}, Status: http.StatusNoContent, Errors: adminErrors,
+	},
+	"AdminHandler.GetWorkspace": {
+		ID: "adminGetWorkspace", Summary: "Inspect a workspace", Tags: []string{"Admin"},
+		Request: admin.GetWorkspacePayload{}, Response: admin.Workspace{}, Errors: adminErrors,
+	},
+	"AdminHandler.GetJobs": {
+		ID: "adminGetJobs", Summary: "List failed background jobs", Tags: []string{"Admin"},
+		Request: admin.GetJobsQuery{}, Response: model.PaginatedResponse[admin.Job]{}, Errors: adminErrors,
+	},
+	"AdminHandler.RetryJob": {
+		ID: "adminRetryJob", Summary: "Retry a failed background job", Tags: []string{"Admin"},
+		Request: admin.RetryJobPayload{}, Status: http.StatusNoContent, Errors: adminErrors,
+	},
+	"AdminHandler.GetServerMode": {
+		ID: "adminGetServerMode", Summary: "Get the maintenance and read-only mode", Tags: []string{"Admin"},
+		Request: admin.GetServerModePayload{}, Response: admin.ServerMode{}, Errors: adminErrors,
+	},
+	"AdminHandler.SetServerMode": {
+		ID: "adminSetServerMode", Summary: "Switch every instance to maintenance, read-only or normal mode", Tags: []string{"Admin"},
+		Request: admin.SetServerModePayload{}, Response: admin.ServerMode{}, Errors: adminErrors,
+	},
+	"AdminHandler.GetFaultInjection": {
+		ID: "adminGetFaultInjection", Summary: "Get the active fault injection rules", Tags: []string{"Admin"},
+		Request: admin.GetFaultInjectionPayload{}, Response: admin.FaultInjection{}, Errors: adminErrors,
+	},
+	"AdminHandler.SetFaultInjection": {
+		ID: "adminSetFaultInjection", Summary: "Inject latency, errors or dropped responses into API routes", Tags: []string{"Admin"},
+		Request: admin.SetFaultInjectionPayload{}, Response: admin.FaultInjection{},
+		Errors: append([]int{http.StatusConflict}, adminErrors...),
+	},
+	"AdminHandler.ClearFaultInjection": {
+		ID: "adminClearFaultInjection", Summary: "Stop every injected fault", Tags: []string{"Admin"},
+		Request: admin.ClearFaultInjectionPayload{}, Status: http.StatusNoContent, Errors: adminErrors,
+	},
+	"AdminHandler.ReloadConfig": {
+		ID: "adminReloadConfig", Summary: "Reload the runtime-safe config values of this instance", Tags: []string{"Admin"},
+		Request: admin.ReloadConfigPayload{}, Response: admin.ConfigReload{}, Errors: adminErrors,
+	},
+	"AdminHandler.GetRuntimeStats": {
+		ID: "adminGetRuntimeStats", Summary: "Get runtime, GC and connection pool statistics", Tags: []string{"Admin"},
+		Request: admin.GetRuntimeStatsPayload{}, Response: admin.RuntimeStats{}, Errors: adminErrors,
+	},
+	"AdminHandler.GetAuditLog": {
+		ID: "adminGetAuditLog", Summary: "List audit log entries", Tags: []string{"Admin"},
+		Request: audit.GetAuditLogQuery{}, Response: model.PaginatedResponse[audit.Entry]{}, Errors: adminErrors,
+	},
+	"AdminHandler.ExportAuditLog": {
+		ID: "adminExportAuditLog", Summary: "Export the audit log as NDJSON or CSV", Tags: []string{"Admin"},
+		Request: audit.ExportAuditLogQuery{}, Produces: []string{MIMEApplicationNDJSON, MIMETextCSV}, Errors: adminErrors,
+	},
+	"AdminHandler.GetFeatureFlags": {
+		ID: "adminGetFeatureFlags", Summary: "List feature flags and their overrides", Tags: []string{"Admin"},
+		Request: feature.GetFlagsPayload{}, Response: []feature.Flag{}, Errors: adminErrors,
+	},
+	"AdminHandler.SetFeatureFlagOverride": {
+		ID: "adminSetFeatureFlagOverride", Summary: "Force a feature flag on or off", Tags: []string{"Admin"},
+		Request: feature.SetOverridePayload{}, Response: feature.Override{}, Errors: adminErrors,
+	},
+	"AdminHandler.DeleteFeatureFlagOverride": {
+		ID: "adminDeleteFeatureFlagOverride", Summary: "Remove a feature flag override", Tags: []string{"Admin"},
+		Request: feature.DeleteOverridePayload{}, Status: http.StatusNoContent, Errors: adminErrors,
+	},
+	"AdminHandler.GetRetentionPolicies": {
+		ID: "adminGetRetentionPolicies", Summary: "List the default and per workspace retention policies", Tags: []string{"Admin"},
+		Request: retention.GetPoliciesPayload{}, Response: retention.Overview{}, Errors: adminErrors,
+	},
+	"AdminHandler.SetRetentionPolicy": {
+		ID: "adminSetRetentionPolicy", Summary: "Set the retention policy of a workspace", Tags: []string{"Admin"},
+		Request: retention.SetPolicyPayload{}, Response: retention.Policy{}, Errors: adminErrors,
+	},
+	"AdminHandler.DeleteRetentionPolicy": {
+		ID: "adminDeleteRetentionPolicy", Summary: "Remove the retention policy of a workspace", Tags: []string{"Admin"},
+		Request: retention.DeletePolicyPayload{}, Status: http.StatusNoContent, Errors: adminErrors,
+	},
+	"AdminHandler.PreviewRetentionPolicy": {
+		ID: "adminPreviewRetentionPolicy", Summary: "Report what a retention policy would delete", Tags: []string{"Admin"},
+		Request: retention.PreviewPolicyPayload{}, Response: retention.Report{}, Errors: adminErrors,
+	},
+	"AdminHandler.GetWorkspaceBackups": {
+		ID: "adminGetWorkspaceBackups", Summary: "List the backups of a workspace", Tags: []string{"Admin"},
+		Request: backup.GetBackupsPayload{}, Response: []backup.Backup{}, Errors: adminErrors,
+	},
+	"AdminHandler.CreateWorkspaceBackup": {
+		ID: "adminCreateWorkspaceBackup", Summary: "Back up a workspace to S3", Tags: []string{"Admin"},
+		Request: backup.CreateBackupPayload{}, Response: backup.Backup{}, Status: http.StatusAccepted,
+		Errors: append([]int{http.StatusConflict}, adminErrors...),
+	},
+	"AdminHandler.GetWorkspaceBackup": {
+		ID: "adminGetWorkspaceBackup", Summary: "Get the progress of a workspace backup", Tags: []string{"Admin"},
+		Request: backup.GetBackupPayload{}, Response: backup.Backup{}, Errors: adminErrors,
+	},
+	"AdminHandler.RestoreWorkspaceBackup": {
+		ID: "adminRestoreWorkspaceBackup", Summary: "Restore a backup into an empty workspace", Tags: []string{"Admin"},
+		Request: backup.RestoreBackupPayload{}, Response: backup.Restore{}, Status: http.StatusAccepted,
+		Errors: append([]int{http.StatusConflict}, adminErrors...),
+	},
+	"AdminHandler.GetWorkspaceRestore": {
+		ID: "adminGetWorkspaceRestore", Summary: "Get the progress of a workspace restore", Tags: []string{"Admin"},
+		Request: backup.GetRestorePayload{}, Response: backup.Restore{}, Errors: adminErrors,
+	},
+	"AdminHandler.GetIncidents": {
+		ID: "adminGetIncidents", Summary: "List the incidents of the status page", Tags: []string{"Admin"},
+		Request: statuspage.GetIncidentsQuery{}, Response: []statuspage.Incident{}, Errors: adminErrors,
+	},
+	"AdminHandler.CreateIncident": {
+		ID: "adminCreateIncident", Summary: "Announce an incident on the status page", Tags: []string{"Admin"},
+		Request: statuspage.CreateIncidentPayload{}, Response: statuspage.Incident{}, Status: http.StatusCreated,
+		Errors: adminErrors,
+	},
+	"AdminHandler.UpdateIncident": {
+		ID: "adminUpdateIncident", Summary: "Follow up an incident of the status page", Tags: []string{"Admin"},
+		Request: statuspage.UpdateIncidentPayload{}, Response: statuspage.Incident{}, Errors: adminErrors,
+	},
+	"AdminHandler.DeleteIncident": {
+		ID: "adminDeleteIncident", Summary: "Take an incident off the status page", Tags: []string{"Admin"},
+		Request: statuspage.DeleteIncidentPayload{}, Status: http.StatusNoContent, Errors: adminErrors,
+	},
+	"AdminHandler.GetUserAPIKeys": {
+		ID: "adminGetUserAPIKeys", Summary: "List the API keys of a user", Tags: []string{"Admin"},
+		Request: apikey.GetUserKeysQuery{}, Response: []apikey.Key{}, Errors: adminErrors,
+	},
+	"AdminHandler.SetAPIKeyPlan": {
+		ID: "adminSetAPIKeyPlan", Summary: "Move an API key to another plan", Tags: []string{"Admin"},
+		Request: apikey.SetKeyPlanPayload{}, Response: apikey.Key{}, Errors: adminErrors,
+	},
+	"AdminHandler.GetAPIClients": {
+		ID: "adminGetAPIClients", Summary: "List the API clients allowed to call the API from browsers", Tags: []string{"Admin"},
+		Request: apiclient.GetAPIClientsPayload{}, Response: []apiclient.APIClient{}, Errors: adminErrors,
+	},
+	"AdminHandler.CreateAPIClient": {
+		ID: "adminCreateAPIClient", Summary: "Register an API client and the origins it calls the API from",
+		Tags: []string{"Admin"}, Request: apiclient.CreateAPIClientPayload{}, Response: apiclient.APIClient{},
+		Status: http.StatusCreated, Errors: adminErrors,
+	},
+	"AdminHandler.UpdateAPIClient": {
+		ID: "adminUpdateAPIClient", Summary: "Change the origins of an API client or disable it", Tags: []string{"Admin"},
+		Request: apiclient.UpdateAPIClientPayload{}, Response: apiclient.APIClient{}, Errors: adminErrors,
+	},
+	"AdminHandler.DeleteAPIClient": {
+		ID: "adminDeleteAPIClient", Summary: "Remove an API client, its origins are no longer allowed", Tags: []string{"Admin"},
+		Request: apiclient.DeleteAPIClientPayload{}, Status: http.StatusNoContent, Errors: adminErrors,
+	},
+}
+
+// BuildOpenAPIDocument generates the OpenAPI document for every registered route
+// that has an operation definition
+func BuildOpenAPIDocument(routes []*echo.Route) *openapi.Document {
+	document := openapi.NewDocument("ExecuTask API", "1.0.0", errs.Problem{})
+
+	// Echo lists the routes in map order, colliding schema names go to the first operation
+	// registered, so the routes are sorted to produce the same document on every run
+	routes = slices.Clone(routes)
+	slices.SortFunc(routes, func(a, b *echo.Route) int {
+		return cmp.Or(strings.Compare(a.Path, b.Path), strings.Compare(a.Method, b.Method))
+	})
+
+	for _, route := range routes {
+		op, ok := operations[handlerMethodName(route.Name)]
+		if !ok {
+			continue
+		}
+
+		// v2 serves the same handlers wrapped in an envelope
+		if strings.HasPrefix(route.Path, "/api/v2/") {
+			op.ID += "V2"
+			op.Enveloped = true
+		}
+
+		document.Add(route.Method, route.Path, op)
+	}
+
+	return document
+}
+
+// handlerMethodName turns a route name such as
+// ".../internal/handler.(*TodoHandler).CreateTodo-fm" into "TodoHandler.CreateTodo"
+func handlerMethodName(routeName string) string {
+	idx := strings.LastIndex(routeName, "(*")
+	if idx < 0 {
+		return ""
+	}
+
+	name := strings.TrimSuffix(routeName[idx+2:], "-fm")
+	return strings.Replace(name, ").", ".", 1)
+}
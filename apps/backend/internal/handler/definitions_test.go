@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildOpenAPIDocumentIsDeterministic registers every defined operation in shuffled orders,
+// colliding schema names must resolve the same way whatever order the routes come in
+func TestBuildOpenAPIDocumentIsDeterministic(t *testing.T) {
+	names := make([]string, 0, len(operations))
+	for name := range operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	routes := make([]*echo.Route, 0, len(names))
+	for _, name := range names {
+		handlerType, method, _ := strings.Cut(name, ".")
+		routes = append(routes, &echo.Route{
+			Method: http.MethodPost,
+			Path:   "/api/v1/" + operations[name].ID,
+			Name:   "github.com/Sameer16536/ExecuTask/internal/handler.(*" + handlerType + ")." + method + "-fm",
+		})
+	}
+
+	expected, err := json.Marshal(BuildOpenAPIDocument(routes))
+	require.NoError(t, err)
+
+	random := rand.New(rand.NewSource(1))
+	for range 5 {
+		random.Shuffle(len(routes), func(i, j int) { routes[i], routes[j] = routes[j], routes[i] })
+
+		actual, err := json.Marshal(BuildOpenAPIDocument(routes))
+		require.NoError(t, err)
+		require.JSONEq(t, string(expected), string(actual))
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/dependency"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type DependencyHandler struct {
+	Handler
+	dependencyService *service.DependencyService
+}
+
+func NewDependencyHandler(s *server.Server, dependencyService *service.DependencyService) *DependencyHandler {
+	return &DependencyHandler{
+		Handler:           NewHandler(s),
+		dependencyService: dependencyService,
+	}
+}
+
+func (h *DependencyHandler) GetDependencies(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *dependency.GetDependenciesPayload) (*dependency.Dependencies, error) {
+			userID := middleware.GetUserID(c)
+			return h.dependencyService.GetDependencies(c, userID, payload.TodoID)
+		},
+		http.StatusOK,
+		&dependency.GetDependenciesPayload{},
+	)(c)
+}
+
+func (h *DependencyHandler) AddDependency(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *dependency.AddDependencyPayload) (*dependency.Dependency, error) {
+			userID := middleware.GetUserID(c)
+			return h.dependencyService.AddDependency(c, userID, payload)
+		},
+		http.StatusCreated,
+		&dependency.AddDependencyPayload{},
+	)(c)
+}
+
+func (h *DependencyHandler) RemoveDependency(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *dependency.RemoveDependencyPayload) error {
+			userID := middleware.GetUserID(c)
+			return h.dependencyService.RemoveDependency(c, userID, payload)
+		},
+		http.StatusNoContent,
+		&dependency.RemoveDependencyPayload{},
+	)(c)
+}
+
+func (h *DependencyHandler) PreviewDueDateShift(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *dependency.ShiftDueDatePayload) (*dependency.Shift, error) {
+			userID := middleware.GetUserID(c)
+			return h.dependencyService.PreviewShift(c, userID, payload)
+		},
+		http.StatusOK,
+		&dependency.ShiftDueDatePayload{},
+	)(c)
+}
+
+func (h *DependencyHandler) ShiftDueDate(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *dependency.ShiftDueDatePayload) (*dependency.Shift, error) {
+			userID := middleware.GetUserID(c)
+			return h.dependencyService.ShiftDueDate(c, userID, payload)
+		},
+		http.StatusOK,
+		&dependency.ShiftDueDatePayload{},
+	)(c)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/export"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type ExportHandler struct {
+	Handler
+	exportService *service.ExportService
+}
+
+func NewExportHandler(s *server.Server, exportService *service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		Handler:       NewHandler(s),
+		exportService: exportService,
+	}
+}
+
+func (h *ExportHandler) RequestAccountExport(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *export.RequestAccountExportPayload) (*export.AccountExport, error) {
+			userID := middleware.GetUserID(c)
+			return h.exportService.RequestAccountExport(c, userID)
+		},
+		http.StatusAccepted,
+		&export.RequestAccountExportPayload{},
+	)(c)
+}
+
+func (h *ExportHandler) GetAccountExport(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *export.GetAccountExportPayload) (*export.AccountExport, error) {
+			userID := middleware.GetUserID(c)
+			return h.exportService.GetAccountExport(c, userID, payload.ID)
+		},
+		http.StatusOK,
+		&export.GetAccountExportPayload{},
+	)(c)
+}
+
+func (h *ExportHandler) RequestTodoListExport(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *export.RequestTodoListExportPayload) (*export.AccountExport, error) {
+			userID := middleware.GetUserID(c)
+			return h.exportService.RequestTodoListExport(c, userID, payload)
+		},
+		http.StatusAccepted,
+		&export.RequestTodoListExportPayload{},
+	)(c)
+}
+
+func (h *ExportHandler) GetTodoListExport(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *export.GetTodoListExportPayload) (*export.AccountExport, error) {
+			userID := middleware.GetUserID(c)
+			return h.exportService.GetTodoListExport(c, userID, payload.ID)
+		},
+		http.StatusOK,
+		&export.GetTodoListExportPayload{},
+	)(c)
+}
+
+func (h *ExportHandler) ExportTodoPDF(c echo.Context) error {
+	return HandleFile(
+		h.Handler,
+		func(c echo.Context, payload *todo.ExportTodoPDFPayload) ([]byte, error) {
+			userID := middleware.GetUserID(c)
+			return h.exportService.ExportTodoPDF(c, userID, payload)
+		},
+		http.StatusOK,
+		&todo.ExportTodoPDFPayload{},
+		"todo.pdf",
+		MIMEApplicationPDF,
+	)(c)
+}
+
+func (h *ExportHandler) ExportTodoListPDF(c echo.Context) error {
+	return HandleFile(
+		h.Handler,
+		func(c echo.Context, query *todo.ExportTodosPDFQuery) ([]byte, error) {
+			userID := middleware.GetUserID(c)
+			return h.exportService.ExportTodoListPDF(c, userID, query)
+		},
+		http.StatusOK,
+		&todo.ExportTodosPDFQuery{},
+		"todos.pdf",
+		MIMEApplicationPDF,
+	)(c)
+}
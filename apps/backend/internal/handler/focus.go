@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/focus"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type FocusHandler struct {
+	Handler
+	focusService *service.FocusService
+}
+
+func NewFocusHandler(s *server.Server, focusService *service.FocusService) *FocusHandler {
+	return &FocusHandler{
+		Handler:      NewHandler(s),
+		focusService: focusService,
+	}
+}
+
+func (h *FocusHandler) StartSession(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *focus.StartSessionPayload) (*focus.Session, error) {
+			userID := middleware.GetUserID(c)
+			return h.focusService.StartSession(c, userID, payload)
+		},
+		http.StatusCreated,
+		&focus.StartSessionPayload{},
+	)(c)
+}
+
+func (h *FocusHandler) GetSessions(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *focus.GetSessionsQuery) ([]focus.Session, error) {
+			userID := middleware.GetUserID(c)
+			return h.focusService.GetSessions(c, userID, query)
+		},
+		http.StatusOK,
+		&focus.GetSessionsQuery{},
+	)(c)
+}
+
+func (h *FocusHandler) GetCurrentSession(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, _ *focus.GetCurrentSessionPayload) (*focus.Session, error) {
+			userID := middleware.GetUserID(c)
+			return h.focusService.GetCurrentSession(c, userID)
+		},
+		http.StatusOK,
+		&focus.GetCurrentSessionPayload{},
+	)(c)
+}
+
+func (h *FocusHandler) PauseSession(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *focus.SessionPayload) (*focus.Session, error) {
+			userID := middleware.GetUserID(c)
+			return h.focusService.PauseSession(c, userID, payload)
+		},
+		http.StatusOK,
+		&focus.SessionPayload{},
+	)(c)
+}
+
+func (h *FocusHandler) ResumeSession(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *focus.SessionPayload) (*focus.Session, error) {
+			userID := middleware.GetUserID(c)
+			return h.focusService.ResumeSession(c, userID, payload)
+		},
+		http.StatusOK,
+		&focus.SessionPayload{},
+	)(c)
+}
+
+func (h *FocusHandler) CompleteSession(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *focus.SessionPayload) (*focus.Session, error) {
+			userID := middleware.GetUserID(c)
+			return h.focusService.CompleteSession(c, userID, payload)
+		},
+		http.StatusOK,
+		&focus.SessionPayload{},
+	)(c)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/gamification"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type GamificationHandler struct {
+	Handler
+	gamificationService *service.GamificationService
+}
+
+func NewGamificationHandler(s *server.Server, gamificationService *service.GamificationService) *GamificationHandler {
+	return &GamificationHandler{
+		Handler:             NewHandler(s),
+		gamificationService: gamificationService,
+	}
+}
+
+func (h *GamificationHandler) GetOverview(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, _ *gamification.GetOverviewPayload) (*gamification.Overview, error) {
+			userID := middleware.GetUserID(c)
+			return h.gamificationService.GetOverview(c, userID)
+		},
+		http.StatusOK,
+		&gamification.GetOverviewPayload{},
+	)(c)
+}
+
+func (h *GamificationHandler) SetGoal(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *gamification.SetGoalPayload) (*gamification.GoalProgress, error) {
+			userID := middleware.GetUserID(c)
+			return h.gamificationService.SetGoal(c, userID, payload)
+		},
+		http.StatusOK,
+		&gamification.SetGoalPayload{},
+	)(c)
+}
+
+func (h *GamificationHandler) DeleteGoal(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, _ *gamification.DeleteGoalPayload) error {
+			userID := middleware.GetUserID(c)
+			return h.gamificationService.DeleteGoal(c, userID)
+		},
+		http.StatusNoContent,
+		&gamification.DeleteGoalPayload{},
+	)(c)
+}
@@ -6,19 +6,73 @@ import (
 )
 
 type Handlers struct {
-	Health   *HealthHandler
-	OpenAPI  *OpenAPIHandler
-	Todo     *TodoHandler
-	Comment  *CommentHandler
-	Category *CategoryHandler
+	Health       *HealthHandler
+	OpenAPI      *OpenAPIHandler
+	Todo         *TodoHandler
+	Comment      *CommentHandler
+	Category     *CategoryHandler
+	Sync         *SyncHandler
+	Batch        *BatchHandler
+	Change       *ChangeHandler
+	Admin        *AdminHandler
+	Stats        *StatsHandler
+	Search       *SearchHandler
+	Debug        *DebugHandler
+	Usage        *UsageHandler
+	Export       *ExportHandler
+	Rule         *RuleHandler
+	Planner      *PlannerHandler
+	Matrix       *MatrixHandler
+	Focus        *FocusHandler
+	Gamification *GamificationHandler
+	Dependency   *DependencyHandler
+	Inbox        *InboxHandler
+	Workspace    *WorkspaceHandler
+	Cover        *CoverHandler
+	Note         *NoteHandler
+	MagicTag     *MagicTagHandler
+	Report       *ReportHandler
+	Locale       *LocaleHandler
+	APIKey       *APIKeyHandler
+	Webhook      *WebhookHandler
+	Session      *SessionHandler
+	Blob         *BlobHandler
 }
 
 func NewHandlers(s *server.Server, services *service.Services) *Handlers {
 	return &Handlers{
-		Health:   NewHealthHandler(s),
-		OpenAPI:  NewOpenAPIHandler(s),
-		Todo:     NewTodoHandler(s, services.Todo),
+		Health:  NewHealthHandler(s, services.Health, services.StatusPage),
+		OpenAPI: NewOpenAPIHandler(s),
+		Todo: NewTodoHandler(s, services.Todo, services.Suggestion, services.Reminder,
+			services.Transcription, services.Scan),
 		Comment:  NewCommentHandler(s, services.Comment),
 		Category: NewCategoryHandler(s, services.Category),
+		Sync:     NewSyncHandler(s, services.Sync),
+		Batch:    NewBatchHandler(s),
+		Change:   NewChangeHandler(s, services.Change),
+		Admin: NewAdminHandler(s, services.Admin, services.Audit, services.Features, services.Retention,
+			services.Backup, services.StatusPage, services.APIKey, services.APIClient),
+		Stats:        NewStatsHandler(s, services.Stats),
+		Search:       NewSearchHandler(s, services.Search),
+		Debug:        NewDebugHandler(s),
+		Usage:        NewUsageHandler(s, services.Usage),
+		Export:       NewExportHandler(s, services.Export),
+		Rule:         NewRuleHandler(s, services.Rule),
+		Planner:      NewPlannerHandler(s, services.Planner),
+		Matrix:       NewMatrixHandler(s, services.Matrix),
+		Focus:        NewFocusHandler(s, services.Focus),
+		Gamification: NewGamificationHandler(s, services.Gamification),
+		Dependency:   NewDependencyHandler(s, services.Dependency),
+		Inbox:        NewInboxHandler(s, services.Inbox),
+		Workspace:    NewWorkspaceHandler(s, services.Workspace),
+		Cover:        NewCoverHandler(s, services.Cover),
+		Note:         NewNoteHandler(s, services.Note),
+		MagicTag:     NewMagicTagHandler(s, services.MagicTag),
+		Report:       NewReportHandler(s, services.Report),
+		Locale:       NewLocaleHandler(s, services.Locale),
+		APIKey:       NewAPIKeyHandler(s, services.APIKey),
+		Webhook:      NewWebhookHandler(s, services.Webhook),
+		Session:      NewSessionHandler(s, services.Session),
+		Blob:         NewBlobHandler(s, services.Blobs),
 	}
 }
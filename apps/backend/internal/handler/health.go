@@ -1,144 +1,61 @@
 package handler
 
 import (
-	"context"
-	"fmt"
 	"net/http"
-	"time"
 
-	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/health"
+	"github.com/Sameer16536/ExecuTask/internal/model/statuspage"
 	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
 
 	"github.com/labstack/echo/v4"
 )
 
 type HealthHandler struct {
 	Handler
+	healthService     *service.HealthService
+	statusPageService *service.StatusPageService
 }
 
-func NewHealthHandler(s *server.Server) *HealthHandler {
+func NewHealthHandler(s *server.Server, healthService *service.HealthService,
+	statusPageService *service.StatusPageService,
+) *HealthHandler {
 	return &HealthHandler{
-		Handler: NewHandler(s),
+		Handler:           NewHandler(s),
+		healthService:     healthService,
+		statusPageService: statusPageService,
 	}
 }
 
-func (h *HealthHandler) CheckHealth(c echo.Context) error {
-	start := time.Now()
-	logger := middleware.GetLogger(c).With().
-		Str("operation", "health_check").
-		Logger()
-
-	response := map[string]interface{}{
-		"status":      "healthy",
-		"timestamp":   time.Now().UTC(),
-		"environment": h.server.Config.Primary.Env,
-		"checks":      make(map[string]interface{}),
-	}
-
-	checks := response["checks"].(map[string]interface{})
-	isHealthy := true
-
-	// Check database connectivity
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	dbStart := time.Now()
-	if err := h.server.DB.Pool.Ping(ctx); err != nil {
-		checks["database"] = map[string]interface{}{
-			"status":        "unhealthy",
-			"response_time": time.Since(dbStart).String(),
-			"error":         err.Error(),
-		}
-		isHealthy = false
-		logger.Error().Err(err).Dur("response_time", time.Since(dbStart)).Msg("database health check failed")
-		if h.server.LoggerService != nil && h.server.LoggerService.GetApplication() != nil {
-			h.server.LoggerService.GetApplication().RecordCustomEvent(
-				"HealthCheckError", map[string]interface{}{
-					"check_type":       "database",
-					"operation":        "health_check",
-					"error_type":       "database_unhealthy",
-					"response_time_ms": time.Since(dbStart).Milliseconds(),
-					"error_message":    err.Error(),
-				})
-		}
-	} else {
-		checks["database"] = map[string]interface{}{
-			"status":        "healthy",
-			"response_time": time.Since(dbStart).String(),
-		}
-		logger.Info().Dur("response_time", time.Since(dbStart)).Msg("database health check passed")
-	}
-
-	// Database connection metrics are automatically captured by New Relic nrpgx5 integration
-
-	// Check Redis connectivity
-	if h.server.Redis != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+// Liveness answers as long as the process serves requests, orchestrators restart it otherwise
+func (h *HealthHandler) Liveness(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.healthService.Liveness())
+}
 
-		redisStart := time.Now()
-		if err := h.server.Redis.Ping(ctx).Err(); err != nil {
-			checks["redis"] = map[string]interface{}{
-				"status":        "unhealthy",
-				"response_time": time.Since(redisStart).String(),
-				"error":         err.Error(),
-			}
-			logger.Error().Err(err).Dur("response_time", time.Since(redisStart)).Msg("redis health check failed")
-			if h.server.LoggerService != nil && h.server.LoggerService.GetApplication() != nil {
-				h.server.LoggerService.GetApplication().RecordCustomEvent(
-					"HealthCheckError", map[string]interface{}{
-						"check_type":       "redis",
-						"operation":        "health_check",
-						"error_type":       "redis_unhealthy",
-						"response_time_ms": time.Since(redisStart).Milliseconds(),
-						"error_message":    err.Error(),
-					})
-			}
-		} else {
-			checks["redis"] = map[string]interface{}{
-				"status":        "healthy",
-				"response_time": time.Since(redisStart).String(),
-			}
-			logger.Info().Dur("response_time", time.Since(redisStart)).Msg("redis health check passed")
-		}
-	}
+// Readiness probes every dependency. Only an unhealthy instance answers 503 so it is taken
+// out of the load balancer, a degraded one keeps serving what it can.
+func (h *HealthHandler) Readiness(c echo.Context) error {
+	report := h.healthService.Readiness(c.Request().Context())
 
-	// Set overall status
-	if !isHealthy {
-		response["status"] = "unhealthy"
-		logger.Warn().
-			Dur("total_duration", time.Since(start)).
-			Msg("health check failed")
-		if h.server.LoggerService != nil && h.server.LoggerService.GetApplication() != nil {
-			h.server.LoggerService.GetApplication().RecordCustomEvent(
-				"HealthCheckError", map[string]interface{}{
-					"check_type":        "overall",
-					"operation":         "health_check",
-					"error_type":        "overall_unhealthy",
-					"total_duration_ms": time.Since(start).Milliseconds(),
-				})
-		}
-		return c.JSON(http.StatusServiceUnavailable, response)
+	status := http.StatusOK
+	if report.Status == health.StatusUnhealthy {
+		status = http.StatusServiceUnavailable
 	}
 
-	logger.Info().
-		Dur("total_duration", time.Since(start)).
-		Msg("health check passed")
-
-	err := c.JSON(http.StatusOK, response)
-	if err != nil {
-		logger.Error().Err(err).Msg("failed to write JSON response")
-		if h.server.LoggerService != nil && h.server.LoggerService.GetApplication() != nil {
-			h.server.LoggerService.GetApplication().RecordCustomEvent(
-				"HealthCheckError", map[string]interface{}{
-					"check_type":    "response",
-					"operation":     "health_check",
-					"error_type":    "json_response_error",
-					"error_message": err.Error(),
-				})
-		}
-		return fmt.Errorf("failed to write JSON response: %w", err)
-	}
+	return c.JSON(status, report)
+}
 
-	return nil
+// GetStatusPage answers the public status page. Shared caches may keep it briefly, the
+// history only moves once a minute.
+func (h *HealthHandler) GetStatusPage(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "public, max-age=30")
+
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *statuspage.GetPagePayload) (*statuspage.Page, error) {
+			return h.statusPageService.GetPage(c, payload)
+		},
+		http.StatusOK,
+		&statuspage.GetPagePayload{},
+	)(c)
 }
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/inbox"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type InboxHandler struct {
+	Handler
+	inboxService *service.InboxService
+}
+
+func NewInboxHandler(s *server.Server, inboxService *service.InboxService) *InboxHandler {
+	return &InboxHandler{
+		Handler:      NewHandler(s),
+		inboxService: inboxService,
+	}
+}
+
+func (h *InboxHandler) CaptureItem(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *inbox.CapturePayload) (*inbox.Item, error) {
+			userID := middleware.GetUserID(c)
+			return h.inboxService.CaptureItem(c, userID, payload)
+		},
+		http.StatusCreated,
+		&inbox.CapturePayload{},
+	)(c)
+}
+
+func (h *InboxHandler) GetItems(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *inbox.GetItemsQuery) ([]inbox.Item, error) {
+			userID := middleware.GetUserID(c)
+			return h.inboxService.GetItems(c, userID, query)
+		},
+		http.StatusOK,
+		&inbox.GetItemsQuery{},
+	)(c)
+}
+
+func (h *InboxHandler) TriageItem(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *inbox.TriagePayload) (*todo.Todo, error) {
+			userID := middleware.GetUserID(c)
+			return h.inboxService.TriageItem(c, userID, payload)
+		},
+		http.StatusCreated,
+		&inbox.TriagePayload{},
+	)(c)
+}
+
+func (h *InboxHandler) DiscardItem(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *inbox.DiscardPayload) error {
+			userID := middleware.GetUserID(c)
+			return h.inboxService.DiscardItem(c, userID, payload)
+		},
+		http.StatusNoContent,
+		&inbox.DiscardPayload{},
+	)(c)
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/category"
+	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/labstack/echo/v4"
+)
+
+const linkBuilderKey = "link_builder"
+
+// linkable is implemented by resources and pages that carry a _links section
+type linkable interface {
+	SetLinks(links model.Links)
+}
+
+// LinkBuilder resolves handler methods to the paths the router serves them at, so links
+// follow the routes and always point at the API version of the current request
+type LinkBuilder struct {
+	// routes maps "version Handler.Method" to a route path such as /api/v1/todos/:id
+	routes map[string]string
+}
+
+func NewLinkBuilder(routes []*echo.Route) *LinkBuilder {
+	b := &LinkBuilder{routes: map[string]string{}}
+
+	for _, route := range routes {
+		version := routeVersion(route.Path)
+		name := handlerMethodName(route.Name)
+		if version == "" || name == "" {
+			continue
+		}
+		b.routes[version+" "+name] = route.Path
+	}
+
+	return b
+}
+
+// Middleware makes the builder available to the response handlers
+func (b *LinkBuilder) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(linkBuilderKey, b)
+			return next(c)
+		}
+	}
+}
+
+// Href fills the route params of a handler method in order, false when the version doesn't serve it
+func (b *LinkBuilder) Href(version, method string, params ...string) (string, bool) {
+	path, ok := b.routes[version+" "+method]
+	if !ok {
+		return "", false
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		if len(params) == 0 {
+			return "", false
+		}
+		segments[i] = url.PathEscape(params[0])
+		params = params[1:]
+	}
+
+	return strings.Join(segments, "/"), true
+}
+
+// attachLinks fills the _links section of a handler result and of every item of a page
+func attachLinks(c echo.Context, result interface{}) {
+	b, ok := c.Get(linkBuilderKey).(*LinkBuilder)
+	if !ok {
+		return
+	}
+
+	version := middleware.GetAPIVersion(c)
+
+	if page, ok := result.(paginated); ok {
+		items := reflect.ValueOf(page.Items())
+		for i := 0; i < items.Len(); i++ {
+			b.attach(version, items.Index(i).Addr().Interface())
+		}
+
+		if target, ok := result.(linkable); ok {
+			target.SetLinks(pageLinks(c, page.Meta()))
+		}
+		return
+	}
+
+	b.attach(version, result)
+}
+
+func (b *LinkBuilder) attach(version string, resource interface{}) {
+	links := model.Links{}
+	add := func(rel, method string, params ...string) {
+		if href, ok := b.Href(version, method, params...); ok {
+			links[rel] = model.Link{Href: href}
+		}
+	}
+
+	switch r := resource.(type) {
+	case *todo.PopulatedTodo:
+		for i := range r.Children {
+			b.attach(version, &r.Children[i])
+		}
+		for i := range r.Comments {
+			b.attach(version, &r.Comments[i])
+		}
+		for i := range r.Attachments {
+			b.attach(version, &r.Attachments[i])
+		}
+		if r.Category != nil {
+			b.attach(version, r.Category)
+		}
+		b.attach(version, &r.Todo)
+		return
+
+	case *todo.Todo:
+		id := r.ID.String()
+		add("self", "TodoHandler.GetTodoByID", id)
+		if r.ParentTodoID != nil {
+			add("parent", "TodoHandler.GetTodoByID", r.ParentTodoID.String())
+		}
+		add("comments", "CommentHandler.GetCommentsByTodoID", id)
+		add("attachments", "TodoHandler.UploadTodoAttachment", id)
+		add("versions", "TodoHandler.GetTodoVersions", id)
+
+	case *todo.TodoAttachment:
+		add("self", "TodoHandler.DeleteTodoAttachment", r.TodoID.String(), r.ID.String())
+		add("parent", "TodoHandler.GetTodoByID", r.TodoID.String())
+		add("download", "TodoHandler.GetAttachmentPresignedURL", r.TodoID.String(), r.ID.String())
+
+	case *comment.Comment:
+		add("self", "CommentHandler.UpdateComment", r.ID.String())
+		add("parent", "TodoHandler.GetTodoByID", r.TodoID.String())
+
+	case *category.Category:
+		add("self", "CategoryHandler.UpdateCategory", r.ID.String())
+
+	default:
+		return
+	}
+
+	if len(links) > 0 {
+		resource.(linkable).SetLinks(links)
+	}
+}
+
+// pageLinks points at neighbouring pages by rewriting the page parameter of the request URL
+func pageLinks(c echo.Context, meta model.PageMeta) model.Links {
+	withPage := func(page int) model.Link {
+		u := *c.Request().URL
+		query := u.Query()
+		query.Set("page", strconv.Itoa(page))
+		u.RawQuery = query.Encode()
+		return model.Link{Href: u.RequestURI()}
+	}
+
+	links := model.Links{"self": withPage(meta.Page)}
+	if meta.Page < meta.TotalPages {
+		links["next"] = withPage(meta.Page + 1)
+	}
+	if meta.Page > 1 {
+		links["prev"] = withPage(meta.Page - 1)
+	}
+
+	return links
+}
+
+// routeVersion returns the API version of a route path, "" for unversioned routes
+func routeVersion(path string) string {
+	rest, ok := strings.CutPrefix(path, "/api/")
+	if !ok {
+		return ""
+	}
+	version, _, _ := strings.Cut(rest, "/")
+	return version
+}
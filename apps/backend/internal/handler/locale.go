@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/locale"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type LocaleHandler struct {
+	Handler
+	localeService *service.LocaleService
+}
+
+func NewLocaleHandler(s *server.Server, localeService *service.LocaleService) *LocaleHandler {
+	return &LocaleHandler{
+		Handler:       NewHandler(s),
+		localeService: localeService,
+	}
+}
+
+func (h *LocaleHandler) GetPreference(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *locale.GetPreferencePayload) (*locale.Preference, error) {
+			userID := middleware.GetUserID(c)
+			return h.localeService.GetPreference(c, userID, payload)
+		},
+		http.StatusOK,
+		&locale.GetPreferencePayload{},
+	)(c)
+}
+
+func (h *LocaleHandler) UpdatePreference(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *locale.UpdatePreferencePayload) (*locale.Preference, error) {
+			userID := middleware.GetUserID(c)
+			return h.localeService.UpdatePreference(c, userID, payload)
+		},
+		http.StatusOK,
+		&locale.UpdatePreferencePayload{},
+	)(c)
+}
+
+func (h *LocaleHandler) ResetPreference(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *locale.ResetPreferencePayload) error {
+			userID := middleware.GetUserID(c)
+			return h.localeService.ResetPreference(c, userID, payload)
+		},
+		http.StatusNoContent,
+		&locale.ResetPreferencePayload{},
+	)(c)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/magictag"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type MagicTagHandler struct {
+	Handler
+	magicTagService *service.MagicTagService
+}
+
+func NewMagicTagHandler(s *server.Server, magicTagService *service.MagicTagService) *MagicTagHandler {
+	return &MagicTagHandler{
+		Handler:         NewHandler(s),
+		magicTagService: magicTagService,
+	}
+}
+
+func (h *MagicTagHandler) GetMagicTags(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *magictag.GetMagicTagsPayload) ([]magictag.MagicTag, error) {
+			userID := middleware.GetUserID(c)
+			return h.magicTagService.GetMagicTags(c, userID)
+		},
+		http.StatusOK,
+		&magictag.GetMagicTagsPayload{},
+	)(c)
+}
+
+func (h *MagicTagHandler) CreateMagicTag(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *magictag.CreateMagicTagPayload) (*magictag.MagicTag, error) {
+			userID := middleware.GetUserID(c)
+			return h.magicTagService.CreateMagicTag(c, userID, payload)
+		},
+		http.StatusCreated,
+		&magictag.CreateMagicTagPayload{},
+	)(c)
+}
+
+func (h *MagicTagHandler) UpdateMagicTag(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *magictag.UpdateMagicTagPayload) (*magictag.MagicTag, error) {
+			userID := middleware.GetUserID(c)
+			return h.magicTagService.UpdateMagicTag(c, userID, payload)
+		},
+		http.StatusOK,
+		&magictag.UpdateMagicTagPayload{},
+	)(c)
+}
+
+func (h *MagicTagHandler) DeleteMagicTag(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *magictag.DeleteMagicTagPayload) error {
+			userID := middleware.GetUserID(c)
+			return h.magicTagService.DeleteMagicTag(c, userID, payload.ID)
+		},
+		http.StatusNoContent,
+		&magictag.DeleteMagicTagPayload{},
+	)(c)
+}
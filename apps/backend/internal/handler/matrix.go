@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/matrix"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type MatrixHandler struct {
+	Handler
+	matrixService *service.MatrixService
+}
+
+func NewMatrixHandler(s *server.Server, matrixService *service.MatrixService) *MatrixHandler {
+	return &MatrixHandler{
+		Handler:       NewHandler(s),
+		matrixService: matrixService,
+	}
+}
+
+func (h *MatrixHandler) GetMatrix(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *matrix.GetMatrixQuery) (*matrix.Matrix, error) {
+			userID := middleware.GetUserID(c)
+			return h.matrixService.GetMatrix(c, userID, query)
+		},
+		http.StatusOK,
+		&matrix.GetMatrixQuery{},
+	)(c)
+}
+
+func (h *MatrixHandler) GetSettings(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *matrix.GetSettingsPayload) (*matrix.Settings, error) {
+			userID := middleware.GetUserID(c)
+			return h.matrixService.GetSettings(c, userID, payload)
+		},
+		http.StatusOK,
+		&matrix.GetSettingsPayload{},
+	)(c)
+}
+
+func (h *MatrixHandler) UpdateSettings(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *matrix.UpdateSettingsPayload) (*matrix.Settings, error) {
+			userID := middleware.GetUserID(c)
+			return h.matrixService.UpdateSettings(c, userID, payload)
+		},
+		http.StatusOK,
+		&matrix.UpdateSettingsPayload{},
+	)(c)
+}
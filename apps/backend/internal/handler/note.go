@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/note"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type NoteHandler struct {
+	Handler
+	noteService *service.NoteService
+}
+
+func NewNoteHandler(s *server.Server, noteService *service.NoteService) *NoteHandler {
+	return &NoteHandler{
+		Handler:     NewHandler(s),
+		noteService: noteService,
+	}
+}
+
+func (h *NoteHandler) GetNote(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *note.GetNotePayload) (*note.Note, error) {
+			userID := middleware.GetUserID(c)
+			return h.noteService.GetNote(c, userID, payload.TodoID)
+		},
+		http.StatusOK,
+		&note.GetNotePayload{},
+	)(c)
+}
+
+func (h *NoteHandler) SetNote(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *note.SetNotePayload) (*note.Note, error) {
+			userID := middleware.GetUserID(c)
+			return h.noteService.SetNote(c, userID, payload)
+		},
+		http.StatusOK,
+		&note.SetNotePayload{},
+	)(c)
+}
+
+func (h *NoteHandler) DeleteNote(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *note.DeleteNotePayload) error {
+			userID := middleware.GetUserID(c)
+			return h.noteService.DeleteNote(c, userID, payload.TodoID)
+		},
+		http.StatusNoContent,
+		&note.DeleteNotePayload{},
+	)(c)
+}
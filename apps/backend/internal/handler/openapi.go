@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/openapi"
 	"github.com/Sameer16536/ExecuTask/internal/server"
 
 	"github.com/labstack/echo/v4"
@@ -12,6 +14,7 @@ import (
 
 type OpenAPIHandler struct {
 	Handler
+	document *openapi.Document
 }
 
 func NewOpenAPIHandler(s *server.Server) *OpenAPIHandler {
@@ -36,3 +39,21 @@ func (h *OpenAPIHandler) ServeOpenAPIUI(c echo.Context) error {
 
 	return nil
 }
+
+// SetDocument sets the generated document, the router calls it once all routes are registered
+func (h *OpenAPIHandler) SetDocument(document *openapi.Document) {
+	h.document = document
+}
+
+func (h *OpenAPIHandler) ServeOpenAPISpec(c echo.Context) error {
+	if h.document == nil {
+		return fmt.Errorf("OpenAPI document has not been generated")
+	}
+
+	return c.JSON(http.StatusOK, h.document)
+}
+
+// ServeErrorCatalog lists the error codes of the API so clients can map them ahead of time
+func (h *OpenAPIHandler) ServeErrorCatalog(c echo.Context) error {
+	return c.JSON(http.StatusOK, errs.Catalog())
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/planner"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type PlannerHandler struct {
+	Handler
+	plannerService *service.PlannerService
+}
+
+func NewPlannerHandler(s *server.Server, plannerService *service.PlannerService) *PlannerHandler {
+	return &PlannerHandler{
+		Handler:        NewHandler(s),
+		plannerService: plannerService,
+	}
+}
+
+func (h *PlannerHandler) GetPlanner(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *planner.GetPlannerQuery) (*planner.Planner, error) {
+			userID := middleware.GetUserID(c)
+			return h.plannerService.GetPlanner(c, userID, query)
+		},
+		http.StatusOK,
+		&planner.GetPlannerQuery{},
+	)(c)
+}
+
+func (h *PlannerHandler) Schedule(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *planner.SchedulePayload) ([]todo.Todo, error) {
+			userID := middleware.GetUserID(c)
+			return h.plannerService.Schedule(c, userID, payload)
+		},
+		http.StatusOK,
+		&planner.SchedulePayload{},
+	)(c)
+}
+
+func (h *PlannerHandler) GetForecast(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *planner.GetForecastQuery) (*planner.Forecast, error) {
+			userID := middleware.GetUserID(c)
+			return h.plannerService.GetForecast(c, userID, query)
+		},
+		http.StatusOK,
+		&planner.GetForecastQuery{},
+	)(c)
+}
+
+func (h *PlannerHandler) GetMyDay(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *planner.GetMyDayQuery) (*planner.MyDay, error) {
+			userID := middleware.GetUserID(c)
+			return h.plannerService.GetMyDay(c, userID, query.Location())
+		},
+		http.StatusOK,
+		&planner.GetMyDayQuery{},
+	)(c)
+}
+
+func (h *PlannerHandler) AddMyDayTodos(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *planner.AddMyDayTodosPayload) (*planner.MyDay, error) {
+			userID := middleware.GetUserID(c)
+			return h.plannerService.AddToMyDay(c, userID, payload)
+		},
+		http.StatusOK,
+		&planner.AddMyDayTodosPayload{},
+	)(c)
+}
+
+func (h *PlannerHandler) RemoveMyDayTodo(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *planner.RemoveMyDayTodoPayload) error {
+			userID := middleware.GetUserID(c)
+			return h.plannerService.RemoveFromMyDay(c, userID, payload)
+		},
+		http.StatusNoContent,
+		&planner.RemoveMyDayTodoPayload{},
+	)(c)
+}
+
+func (h *PlannerHandler) GetDaySummaries(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *planner.GetDaySummariesQuery) ([]planner.DaySummary, error) {
+			userID := middleware.GetUserID(c)
+			return h.plannerService.GetDaySummaries(c, userID, query)
+		},
+		http.StatusOK,
+		&planner.GetDaySummariesQuery{},
+	)(c)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/report"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type ReportHandler struct {
+	Handler
+	reportService *service.ReportService
+}
+
+func NewReportHandler(s *server.Server, reportService *service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		Handler:       NewHandler(s),
+		reportService: reportService,
+	}
+}
+
+func (h *ReportHandler) GetSchedules(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *report.GetSchedulesPayload) ([]report.Schedule, error) {
+			userID := middleware.GetUserID(c)
+			return h.reportService.GetSchedules(c, userID)
+		},
+		http.StatusOK,
+		&report.GetSchedulesPayload{},
+	)(c)
+}
+
+func (h *ReportHandler) CreateSchedule(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *report.CreateSchedulePayload) (*report.Schedule, error) {
+			userID := middleware.GetUserID(c)
+			return h.reportService.CreateSchedule(c, userID, payload)
+		},
+		http.StatusCreated,
+		&report.CreateSchedulePayload{},
+	)(c)
+}
+
+func (h *ReportHandler) GetSchedule(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *report.GetSchedulePayload) (*report.Schedule, error) {
+			userID := middleware.GetUserID(c)
+			return h.reportService.GetSchedule(c, userID, payload.ID)
+		},
+		http.StatusOK,
+		&report.GetSchedulePayload{},
+	)(c)
+}
+
+func (h *ReportHandler) UpdateSchedule(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *report.UpdateSchedulePayload) (*report.Schedule, error) {
+			userID := middleware.GetUserID(c)
+			return h.reportService.UpdateSchedule(c, userID, payload)
+		},
+		http.StatusOK,
+		&report.UpdateSchedulePayload{},
+	)(c)
+}
+
+func (h *ReportHandler) PauseSchedule(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *report.PauseSchedulePayload) (*report.Schedule, error) {
+			userID := middleware.GetUserID(c)
+			return h.reportService.PauseSchedule(c, userID, payload.ID)
+		},
+		http.StatusOK,
+		&report.PauseSchedulePayload{},
+	)(c)
+}
+
+func (h *ReportHandler) ResumeSchedule(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *report.ResumeSchedulePayload) (*report.Schedule, error) {
+			userID := middleware.GetUserID(c)
+			return h.reportService.ResumeSchedule(c, userID, payload.ID)
+		},
+		http.StatusOK,
+		&report.ResumeSchedulePayload{},
+	)(c)
+}
+
+func (h *ReportHandler) DeleteSchedule(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *report.DeleteSchedulePayload) error {
+			userID := middleware.GetUserID(c)
+			return h.reportService.DeleteSchedule(c, userID, payload.ID)
+		},
+		http.StatusNoContent,
+		&report.DeleteSchedulePayload{},
+	)(c)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/rule"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type RuleHandler struct {
+	Handler
+	ruleService *service.RuleService
+}
+
+func NewRuleHandler(s *server.Server, ruleService *service.RuleService) *RuleHandler {
+	return &RuleHandler{
+		Handler:     NewHandler(s),
+		ruleService: ruleService,
+	}
+}
+
+func (h *RuleHandler) CreateRule(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *rule.CreateRulePayload) (*rule.Rule, error) {
+			userID := middleware.GetUserID(c)
+			return h.ruleService.CreateRule(c, userID, payload)
+		},
+		http.StatusCreated,
+		&rule.CreateRulePayload{},
+	)(c)
+}
+
+func (h *RuleHandler) GetRules(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *rule.GetRulesQuery) ([]rule.Rule, error) {
+			userID := middleware.GetUserID(c)
+			return h.ruleService.GetRules(c, userID)
+		},
+		http.StatusOK,
+		&rule.GetRulesQuery{},
+	)(c)
+}
+
+func (h *RuleHandler) GetRule(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *rule.GetRulePayload) (*rule.Rule, error) {
+			userID := middleware.GetUserID(c)
+			return h.ruleService.GetRule(c, userID, payload.ID)
+		},
+		http.StatusOK,
+		&rule.GetRulePayload{},
+	)(c)
+}
+
+func (h *RuleHandler) UpdateRule(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *rule.UpdateRulePayload) (*rule.Rule, error) {
+			userID := middleware.GetUserID(c)
+			return h.ruleService.UpdateRule(c, userID, payload)
+		},
+		http.StatusOK,
+		&rule.UpdateRulePayload{},
+	)(c)
+}
+
+func (h *RuleHandler) DeleteRule(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *rule.DeleteRulePayload) error {
+			userID := middleware.GetUserID(c)
+			return h.ruleService.DeleteRule(c, userID, payload.ID)
+		},
+		http.StatusNoContent,
+		&rule.DeleteRulePayload{},
+	)(c)
+}
+
+func (h *RuleHandler) GetRuleExecutions(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *rule.GetExecutionsQuery) ([]rule.Execution, error) {
+			userID := middleware.GetUserID(c)
+			return h.ruleService.GetExecutions(c, userID, query)
+		},
+		http.StatusOK,
+		&rule.GetExecutionsQuery{},
+	)(c)
+}
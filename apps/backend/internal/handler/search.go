@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/search"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type SearchHandler struct {
+	Handler
+	searchService *service.SearchService
+}
+
+func NewSearchHandler(s *server.Server, searchService *service.SearchService) *SearchHandler {
+	return &SearchHandler{
+		Handler:       NewHandler(s),
+		searchService: searchService,
+	}
+}
+
+func (h *SearchHandler) Search(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *search.SearchQuery) (*search.Results, error) {
+			userID := middleware.GetUserID(c)
+			return h.searchService.Search(c, userID, query)
+		},
+		http.StatusOK,
+		&search.SearchQuery{},
+	)(c)
+}
+
+func (h *SearchHandler) Suggest(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *search.SuggestQuery) (*search.Suggestions, error) {
+			userID := middleware.GetUserID(c)
+			return h.searchService.Suggest(c, userID, query)
+		},
+		http.StatusOK,
+		&search.SuggestQuery{},
+	)(c)
+}
@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"encoding/json"
+	"mime"
+	"reflect"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/etag"
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	MIMEApplicationJSONAPI = "application/vnd.api+json"
+	MIMEApplicationNDJSON  = "application/x-ndjson"
+	MIMETextCSV            = "text/csv"
+	MIMEApplicationPDF     = "application/pdf"
+)
+
+// Serializer writes a handler result in a specific media type
+type Serializer interface {
+	ContentType() string
+	Serialize(c echo.Context, status int, result interface{}) error
+}
+
+// serializers are matched against the Accept header in order, the first one is the default
+var serializers = []Serializer{
+	jsonSerializer{},
+	jsonAPISerializer{},
+	ndjsonSerializer{},
+}
+
+// negotiateSerializer picks the serializer for the request's Accept header,
+// falling back to plain JSON when nothing more specific was asked for
+func negotiateSerializer(c echo.Context) Serializer {
+	accept := c.Request().Header.Get(echo.HeaderAccept)
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		for _, serializer := range serializers {
+			if serializer.ContentType() == mediaType {
+				return serializer
+			}
+		}
+	}
+
+	return serializers[0]
+}
+
+// -----------------------------------------------------------------------------------------
+
+type jsonSerializer struct{}
+
+func (jsonSerializer) ContentType() string {
+	return echo.MIMEApplicationJSON
+}
+
+func (jsonSerializer) Serialize(c echo.Context, status int, result interface{}) error {
+	return c.JSON(status, adaptForVersion(c, result))
+}
+
+// -----------------------------------------------------------------------------------------
+
+// jsonAPIType is implemented by models that know their JSON:API resource type
+type jsonAPIType interface {
+	JSONAPIType() string
+}
+
+type jsonAPIResource struct {
+	Type       string                     `json:"type"`
+	ID         string                     `json:"id,omitempty"`
+	Attributes map[string]json.RawMessage `json:"attributes"`
+	Links      map[string]string          `json:"links,omitempty"`
+}
+
+type jsonAPIDocument struct {
+	Data  interface{}       `json:"data"`
+	Meta  interface{}       `json:"meta,omitempty"`
+	Links map[string]string `json:"links,omitempty"`
+}
+
+type jsonAPISerializer struct{}
+
+func (jsonAPISerializer) ContentType() string {
+	return MIMEApplicationJSONAPI
+}
+
+func (jsonAPISerializer) Serialize(c echo.Context, status int, result interface{}) error {
+	document := jsonAPIDocument{}
+
+	if page, ok := result.(paginated); ok {
+		items := reflect.ValueOf(page.Items())
+		resources := make([]jsonAPIResource, 0, items.Len())
+		for i := 0; i < items.Len(); i++ {
+			resource, err := toJSONAPIResource(items.Index(i).Addr().Interface())
+			if err != nil {
+				return err
+			}
+			resources = append(resources, *resource)
+		}
+
+		document.Data = resources
+		document.Meta = page.Meta()
+		if linked, ok := result.(pageLinked); ok {
+			document.Links = jsonAPILinks(linked.PageLinks())
+		}
+	} else {
+		resource, err := toJSONAPIResource(result)
+		if err != nil {
+			return err
+		}
+		document.Data = resource
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationJSONAPI)
+	c.Response().WriteHeader(status)
+	return json.NewEncoder(c.Response()).Encode(document)
+}
+
+func toJSONAPIResource(value interface{}) (*jsonAPIResource, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &attributes); err != nil {
+		return nil, err
+	}
+
+	resource := &jsonAPIResource{
+		Type:       resourceType(value),
+		Attributes: attributes,
+	}
+
+	if rawID, ok := attributes["id"]; ok {
+		_ = json.Unmarshal(rawID, &resource.ID)
+		delete(attributes, "id")
+	}
+
+	// JSON:API keeps links next to the attributes, as plain URLs
+	if rawLinks, ok := attributes["_links"]; ok {
+		var links model.Links
+		_ = json.Unmarshal(rawLinks, &links)
+		resource.Links = jsonAPILinks(links)
+		delete(attributes, "_links")
+	}
+
+	return resource, nil
+}
+
+func jsonAPILinks(links model.Links) map[string]string {
+	if len(links) == 0 {
+		return nil
+	}
+
+	hrefs := make(map[string]string, len(links))
+	for rel, link := range links {
+		hrefs[rel] = link.Href
+	}
+	return hrefs
+}
+
+func resourceType(value interface{}) string {
+	if typed, ok := value.(jsonAPIType); ok {
+		return typed.JSONAPIType()
+	}
+
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return strings.ToLower(t.Name())
+}
+
+// -----------------------------------------------------------------------------------------
+
+// ndjsonSerializer streams list items one JSON document per line, flushing as it
+// goes so large exports don't have to be buffered by the client
+type ndjsonSerializer struct{}
+
+func (ndjsonSerializer) ContentType() string {
+	return MIMEApplicationNDJSON
+}
+
+func (ndjsonSerializer) Serialize(c echo.Context, status int, result interface{}) error {
+	response := c.Response()
+	response.Header().Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+	response.WriteHeader(status)
+
+	encoder := json.NewEncoder(response)
+
+	page, ok := result.(paginated)
+	if !ok {
+		return encoder.Encode(result)
+	}
+
+	items := reflect.ValueOf(page.Items())
+	for i := 0; i < items.Len(); i++ {
+		if err := encoder.Encode(items.Index(i).Addr().Interface()); err != nil {
+			return err
+		}
+		response.Flush()
+	}
+
+	return nil
+}
+
+// representationTag keeps entity tags distinct across media types of the same resource
+func representationTag(serializer Serializer, tag string) string {
+	if serializer.ContentType() == echo.MIMEApplicationJSON {
+		return tag
+	}
+	return etag.Strong(tag, serializer.ContentType())
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/session"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type SessionHandler struct {
+	Handler
+	sessionService *service.SessionService
+}
+
+func NewSessionHandler(s *server.Server, sessionService *service.SessionService) *SessionHandler {
+	return &SessionHandler{
+		Handler:        NewHandler(s),
+		sessionService: sessionService,
+	}
+}
+
+func (h *SessionHandler) CreateSession(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *session.CreateSessionPayload) (*session.CookieSession, error) {
+			return h.sessionService.CreateSession(c, payload)
+		},
+		http.StatusCreated,
+		&session.CreateSessionPayload{},
+	)(c)
+}
+
+func (h *SessionHandler) DeleteSession(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *session.DeleteSessionPayload) error {
+			return h.sessionService.DeleteSession(c, payload)
+		},
+		http.StatusNoContent,
+		&session.DeleteSessionPayload{},
+	)(c)
+}
+
+func (h *SessionHandler) GetCSRFToken(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *session.GetCSRFTokenPayload) (*session.CSRFToken, error) {
+			return h.sessionService.GetCSRFToken(c, payload)
+		},
+		http.StatusOK,
+		&session.GetCSRFTokenPayload{},
+	)(c)
+}
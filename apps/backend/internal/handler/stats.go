@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/stats"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type StatsHandler struct {
+	Handler
+	statsService *service.StatsService
+}
+
+func NewStatsHandler(s *server.Server, statsService *service.StatsService) *StatsHandler {
+	return &StatsHandler{
+		Handler:      NewHandler(s),
+		statsService: statsService,
+	}
+}
+
+func (h *StatsHandler) GetOverview(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *stats.GetOverviewQuery) (*stats.Overview, error) {
+			userID := middleware.GetUserID(c)
+			return h.statsService.GetOverview(c, userID, query)
+		},
+		http.StatusOK,
+		&stats.GetOverviewQuery{},
+	)(c)
+}
+
+func (h *StatsHandler) GetProductivity(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *stats.GetProductivityQuery) (*stats.Productivity, error) {
+			userID := middleware.GetUserID(c)
+			return h.statsService.GetProductivity(c, userID, query)
+		},
+		http.StatusOK,
+		&stats.GetProductivityQuery{},
+	)(c)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"bufio"
+	"net/http"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/labstack/echo/v4"
+	"github.com/newrelic/go-agent/v3/newrelic"
+)
+
+// streamBufferSize is how much of a stream is buffered before it is flushed to the client.
+// Errors before the first flush still get a regular error response.
+const streamBufferSize = 32 << 10
+
+// HandlerFuncStream represents a typed handler function that writes its response body itself
+type HandlerFuncStream[Req validation.Validatable] func(c echo.Context, req Req, stream *ResponseStream) error
+
+// ResponseStream is a buffered response body flushed to the client in chunks. The status and
+// headers are only sent with the first chunk.
+type ResponseStream struct {
+	c       echo.Context
+	status  int
+	buffer  *bufio.Writer
+	written int64
+}
+
+func newResponseStream(c echo.Context, status int) *ResponseStream {
+	// Large streams outlive the server's write timeout and the request deadline, the client's
+	// pace bounds them instead
+	if err := http.NewResponseController(c.Response()).SetWriteDeadline(time.Time{}); err != nil {
+		middleware.GetLogger(c).Debug().Err(err).Msg("could not lift the write deadline for the stream")
+	}
+	middleware.LiftDeadline(c)
+	middleware.ExemptFromPayloadBudget(c)
+
+	stream := &ResponseStream{c: c, status: status}
+	stream.buffer = bufio.NewWriterSize(streamFlusher{stream}, streamBufferSize)
+	return stream
+}
+
+// SetContentType sets the media type of the body, it must be called before writing
+func (s *ResponseStream) SetContentType(contentType string) {
+	s.c.Response().Header().Set(echo.HeaderContentType, contentType)
+}
+
+// SetFilename makes clients save the body as a download
+func (s *ResponseStream) SetFilename(filename string) {
+	s.c.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename="+filename)
+}
+
+func (s *ResponseStream) Write(p []byte) (int, error) {
+	n, err := s.buffer.Write(p)
+	s.written += int64(n)
+	return n, err
+}
+
+// Committed reports whether part of the body already reached the client
+func (s *ResponseStream) Committed() bool {
+	return s.c.Response().Committed
+}
+
+// Close flushes what is left, writing the headers if nothing was flushed yet
+func (s *ResponseStream) Close() error {
+	if err := s.buffer.Flush(); err != nil {
+		return err
+	}
+	if !s.c.Response().Committed {
+		s.c.Response().WriteHeader(s.status)
+	}
+	return nil
+}
+
+// streamFlusher pushes every chunk the buffer hands over straight to the client
+type streamFlusher struct {
+	stream *ResponseStream
+}
+
+func (f streamFlusher) Write(p []byte) (int, error) {
+	response := f.stream.c.Response()
+	if !response.Committed {
+		response.WriteHeader(f.stream.status)
+	}
+
+	n, err := response.Write(p)
+	if err != nil {
+		return n, err
+	}
+	response.Flush()
+	return n, nil
+}
+
+// StreamResponseHandler finishes responses the handler streamed itself
+type StreamResponseHandler struct{}
+
+func (h StreamResponseHandler) Handle(c echo.Context, result interface{}) error {
+	return result.(*ResponseStream).Close()
+}
+
+func (h StreamResponseHandler) GetOperation() string {
+	return "handler_stream"
+}
+
+func (h StreamResponseHandler) AddAttributes(txn *newrelic.Transaction, result interface{}) {
+	if txn != nil {
+		if stream, ok := result.(*ResponseStream); ok {
+			txn.AddAttribute("stream.size_bytes", stream.written)
+		}
+	}
+}
+
+// HandleStream wraps a streaming handler with validation, error handling, logging, metrics, and tracing.
+// A failure after the first chunk was sent aborts the connection so clients can't mistake a
+// truncated body for a complete one.
+func HandleStream[Req validation.Validatable](
+	h Handler,
+	handler HandlerFuncStream[Req],
+	status int,
+	req Req,
+) echo.HandlerFunc {
+	name := operationName(handler)
+	return func(c echo.Context) error {
+		return handleRequest(c, name, req, func(c echo.Context, req Req) (interface{}, error) {
+			stream := newResponseStream(c, status)
+			if err := handler(c, req, stream); err != nil {
+				if stream.Committed() {
+					middleware.GetLogger(c).Error().Err(err).Int64("written_bytes", stream.written).
+						Msg("stream failed after the response started, aborting the connection")
+					panic(http.ErrAbortHandler)
+				}
+				return nil, err
+			}
+			return stream, nil
+		}, StreamResponseHandler{})
+	}
+}
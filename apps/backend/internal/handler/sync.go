@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type SyncHandler struct {
+	Handler
+	syncService *service.SyncService
+}
+
+func NewSyncHandler(s *server.Server, syncService *service.SyncService) *SyncHandler {
+	return &SyncHandler{
+		Handler:     NewHandler(s),
+		syncService: syncService,
+	}
+}
+
+func (h *SyncHandler) SyncTodos(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *todo.SyncTodosPayload) (*todo.SyncResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.syncService.SyncTodos(c, userID, payload)
+		},
+		http.StatusOK,
+		&todo.SyncTodosPayload{},
+	)(c)
+}
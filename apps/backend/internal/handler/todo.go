@@ -1,9 +1,10 @@
 package handler
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"net/http"
 
-	"github.com/Sameer16536/ExecuTask/internal/errs"
 	"github.com/Sameer16536/ExecuTask/internal/middleware"
 	"github.com/Sameer16536/ExecuTask/internal/model"
 	"github.com/Sameer16536/ExecuTask/internal/model/todo"
@@ -14,22 +15,50 @@ import (
 
 type TodoHandler struct {
 	Handler
-	todoService *service.TodoService
+	todoService          *service.TodoService
+	suggestionService    *service.SuggestionService
+	reminderService      *service.ReminderService
+	transcriptionService *service.TranscriptionService
+	scanService          *service.AttachmentScanService
 }
 
-func NewTodoHandler(s *server.Server, todoService *service.TodoService) *TodoHandler {
+func NewTodoHandler(s *server.Server, todoService *service.TodoService,
+	suggestionService *service.SuggestionService, reminderService *service.ReminderService,
+	transcriptionService *service.TranscriptionService, scanService *service.AttachmentScanService,
+) *TodoHandler {
 	return &TodoHandler{
-		Handler:     NewHandler(s),
-		todoService: todoService,
+		Handler:              NewHandler(s),
+		todoService:          todoService,
+		suggestionService:    suggestionService,
+		reminderService:      reminderService,
+		transcriptionService: transcriptionService,
+		scanService:          scanService,
 	}
 }
 
 func (h *TodoHandler) CreateTodo(c echo.Context) error {
 	return Handle(
 		h.Handler,
-		func(c echo.Context, payload *todo.CreateTodoPayload) (*todo.Todo, error) {
+		func(c echo.Context, payload *todo.CreateTodoPayload) (*todo.CreatedTodo, error) {
 			userID := middleware.GetUserID(c)
-			return h.todoService.CreateTodo(c, userID, payload)
+			todoItem, err := h.todoService.CreateTodo(c, userID, payload)
+			if err != nil {
+				return nil, err
+			}
+
+			created := &todo.CreatedTodo{
+				Todo:        *todoItem,
+				Suggestions: h.suggestionService.SuggestForTodo(c, userID, payload, todoItem),
+			}
+			if payload.CheckDuplicates {
+				created.Duplicates = h.todoService.FindDuplicates(c, userID, todoItem)
+			}
+
+			setETag(c, func() (*todo.PopulatedTodo, error) {
+				return h.todoService.GetTodoByID(c, userID, todoItem.ID, nil)
+			})
+
+			return created, nil
 		},
 		http.StatusCreated,
 		&todo.CreateTodoPayload{},
@@ -41,7 +70,13 @@ func (h *TodoHandler) GetTodoByID(c echo.Context) error {
 		h.Handler,
 		func(c echo.Context, payload *todo.GetTodoByIDPayload) (*todo.PopulatedTodo, error) {
 			userID := middleware.GetUserID(c)
-			return h.todoService.GetTodoByID(c, userID, payload.ID)
+			todoItem, err := h.todoService.GetTodoByID(c, userID, payload.ID, &payload.ShapeQuery)
+			if err != nil {
+				return nil, err
+			}
+
+			todoItem.SuggestedReminders = h.reminderService.SuggestReminders(c, userID, &todoItem.Todo)
+			return todoItem, nil
 		},
 		http.StatusOK,
 		&todo.GetTodoByIDPayload{},
@@ -60,23 +95,162 @@ func (h *TodoHandler) GetTodos(c echo.Context) error {
 	)(c)
 }
 
+func (h *TodoHandler) ExportTodos(c echo.Context) error {
+	return HandleStream(
+		h.Handler,
+		func(c echo.Context, query *todo.ExportTodosQuery, stream *ResponseStream) error {
+			userID := middleware.GetUserID(c)
+
+			if *query.Format == todo.ExportFormatCSV {
+				stream.SetContentType(MIMETextCSV)
+				stream.SetFilename("todos.csv")
+
+				writer := csv.NewWriter(stream)
+				if err := writer.Write(todo.CSVHeader); err != nil {
+					return err
+				}
+
+				err := h.todoService.ExportTodos(c, userID, query, func(todoItem *todo.Todo) error {
+					return writer.Write(todoItem.CSVRecord())
+				})
+				if err != nil {
+					return err
+				}
+
+				writer.Flush()
+				return writer.Error()
+			}
+
+			stream.SetContentType(MIMEApplicationNDJSON)
+			stream.SetFilename("todos.ndjson")
+
+			encoder := json.NewEncoder(stream)
+			return h.todoService.ExportTodos(c, userID, query, func(todoItem *todo.Todo) error {
+				return encoder.Encode(todoItem)
+			})
+		},
+		http.StatusOK,
+		&todo.ExportTodosQuery{},
+	)(c)
+}
+
 func (h *TodoHandler) UpdateTodo(c echo.Context) error {
 	return Handle(
 		h.Handler,
 		func(c echo.Context, payload *todo.UpdateTodoPayload) (*todo.Todo, error) {
 			userID := middleware.GetUserID(c)
-			return h.todoService.UpdateTodo(c, userID, payload)
+
+			current, matched, err := checkIfMatch(c, func() (*todo.PopulatedTodo, error) {
+				return h.todoService.GetTodoByID(c, userID, payload.ID, nil)
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			// Guard the write itself so a concurrent update can't slip in after the check
+			if matched && payload.Version == nil {
+				payload.Version = &current.Version
+			}
+
+			updated, err := h.todoService.UpdateTodo(c, userID, payload)
+			if err != nil {
+				return nil, err
+			}
+
+			setETag(c, func() (*todo.PopulatedTodo, error) {
+				return h.todoService.GetTodoByID(c, userID, updated.ID, nil)
+			})
+
+			return updated, nil
 		},
 		http.StatusOK,
 		&todo.UpdateTodoPayload{},
 	)(c)
 }
 
+func (h *TodoHandler) MergeTodos(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *todo.MergeTodosPayload) (*todo.Todo, error) {
+			userID := middleware.GetUserID(c)
+			return h.todoService.MergeTodos(c, userID, payload)
+		},
+		http.StatusOK,
+		&todo.MergeTodosPayload{},
+	)(c)
+}
+
+func (h *TodoHandler) SuggestSubtasks(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *todo.SuggestSubtasksPayload) (*todo.SubtaskSuggestions, error) {
+			userID := middleware.GetUserID(c)
+			return h.todoService.SuggestSubtasks(c, userID, payload)
+		},
+		http.StatusOK,
+		&todo.SuggestSubtasksPayload{},
+	)(c)
+}
+
+func (h *TodoHandler) GetTodoVersions(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *todo.GetTodoVersionsPayload) ([]todo.TodoVersion, error) {
+			userID := middleware.GetUserID(c)
+			return h.todoService.GetTodoVersions(c, userID, payload.ID)
+		},
+		http.StatusOK,
+		&todo.GetTodoVersionsPayload{},
+	)(c)
+}
+
+func (h *TodoHandler) RestoreTodoVersion(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *todo.RestoreTodoVersionPayload) (*todo.Todo, error) {
+			userID := middleware.GetUserID(c)
+
+			current, matched, err := checkIfMatch(c, func() (*todo.PopulatedTodo, error) {
+				return h.todoService.GetTodoByID(c, userID, payload.ID, nil)
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			// Guard the write itself so a concurrent update can't slip in after the check
+			var expectedVersion *int
+			if matched {
+				expectedVersion = &current.Version
+			}
+
+			restored, err := h.todoService.RestoreTodoVersion(c, userID, payload, expectedVersion)
+			if err != nil {
+				return nil, err
+			}
+
+			setETag(c, func() (*todo.PopulatedTodo, error) {
+				return h.todoService.GetTodoByID(c, userID, restored.ID, nil)
+			})
+
+			return restored, nil
+		},
+		http.StatusOK,
+		&todo.RestoreTodoVersionPayload{},
+	)(c)
+}
+
 func (h *TodoHandler) DeleteTodo(c echo.Context) error {
 	return HandleNoContent(
 		h.Handler,
 		func(c echo.Context, payload *todo.DeleteTodoPayload) error {
 			userID := middleware.GetUserID(c)
+
+			if _, _, err := checkIfMatch(c, func() (*todo.PopulatedTodo, error) {
+				return h.todoService.GetTodoByID(c, userID, payload.ID, nil)
+			}); err != nil {
+				return err
+			}
+
 			return h.todoService.DeleteTodo(c, userID, payload.ID)
 		},
 		http.StatusNoContent,
@@ -84,6 +258,42 @@ func (h *TodoHandler) DeleteTodo(c echo.Context) error {
 	)(c)
 }
 
+func (h *TodoHandler) CompleteTodos(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *todo.CompleteTodosPayload) (*todo.BulkCompletion, error) {
+			userID := middleware.GetUserID(c)
+			return h.todoService.CompleteTodos(c, userID, payload)
+		},
+		http.StatusOK,
+		&todo.CompleteTodosPayload{},
+	)(c)
+}
+
+func (h *TodoHandler) UncompleteTodo(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *todo.UncompleteTodoPayload) (*todo.Todo, error) {
+			userID := middleware.GetUserID(c)
+			return h.todoService.UncompleteTodo(c, userID, payload.ID)
+		},
+		http.StatusOK,
+		&todo.UncompleteTodoPayload{},
+	)(c)
+}
+
+func (h *TodoHandler) MarkTodoRead(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *todo.MarkTodoReadPayload) (*todo.Todo, error) {
+			userID := middleware.GetUserID(c)
+			return h.todoService.MarkTodoRead(c, userID, payload.ID)
+		},
+		http.StatusOK,
+		&todo.MarkTodoReadPayload{},
+	)(c)
+}
+
 func (h *TodoHandler) GetTodoStats(c echo.Context) error {
 	return Handle(
 		h.Handler,
@@ -102,21 +312,19 @@ func (h *TodoHandler) UploadTodoAttachment(c echo.Context) error {
 		func(c echo.Context, payload *todo.UploadTodoAttachmentPayload) (*todo.TodoAttachment, error) {
 			userID := middleware.GetUserID(c)
 
-			form, err := c.MultipartForm()
+			fileName, file, err := fileUpload(c, "file")
 			if err != nil {
-				return nil, errs.NewBadRequestError("multipart form not found", false, nil, nil, nil)
-			}
-
-			files := form.File["file"]
-			if len(files) == 0 {
-				return nil, errs.NewBadRequestError("no file found", false, nil, nil, nil)
+				return nil, err
 			}
 
-			if len(files) > 1 {
-				return nil, errs.NewBadRequestError("only one file allowed per upload", false, nil, nil, nil)
+			attachment, err := h.todoService.UploadTodoAttachment(c, userID, payload.TodoID, fileName, file)
+			if err != nil {
+				return nil, err
 			}
 
-			return h.todoService.UploadTodoAttachment(c, userID, payload.TodoID, files[0])
+			// Quarantined until scanned for viruses, voice notes get a transcript once cleared
+			attachment = h.scanService.QueueScan(c, userID, attachment)
+			return h.transcriptionService.QueueTranscription(c, userID, attachment), nil
 		},
 		http.StatusCreated,
 		&todo.UploadTodoAttachmentPayload{},
@@ -155,3 +363,24 @@ func (h *TodoHandler) GetAttachmentPresignedURL(c echo.Context) error {
 		&todo.GetAttachmentPresignedURLPayload{},
 	)(c)
 }
+
+func (h *TodoHandler) GetAttachmentThumbnailURL(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *todo.GetAttachmentThumbnailURLPayload) (*struct {
+			URL string `json:"url"`
+		}, error,
+		) {
+			userID := middleware.GetUserID(c)
+			url, err := h.todoService.GetAttachmentThumbnailURL(c, userID, payload.TodoID, payload.AttachmentID)
+			if err != nil {
+				return nil, err
+			}
+			return &struct {
+				URL string `json:"url"`
+			}{URL: url}, nil
+		},
+		http.StatusOK,
+		&todo.GetAttachmentThumbnailURLPayload{},
+	)(c)
+}
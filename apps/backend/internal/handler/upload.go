@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/labstack/echo/v4"
+)
+
+// fileUpload streams the single file of a multipart upload from its form field, the body is
+// never buffered. The other fields before it are skipped, another file after it fails the
+// read once the file was read to its end.
+func fileUpload(c echo.Context, field string) (string, io.Reader, error) {
+	reader, err := c.Request().MultipartReader()
+	if err != nil {
+		return "", nil, errs.NewBadRequestError("multipart form not found", false, nil, nil, nil)
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return "", nil, errs.NewBadRequestError("no file found", false, nil, nil, nil)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+
+		if part.FormName() == field && part.FileName() != "" {
+			return part.FileName(), &singleFileReader{part: part, reader: reader, field: field}, nil
+		}
+	}
+}
+
+// singleFileReader reads the file part, then makes sure no other file follows it
+type singleFileReader struct {
+	part   *multipart.Part
+	reader *multipart.Reader
+	field  string
+}
+
+func (r *singleFileReader) Read(p []byte) (int, error) {
+	n, err := r.part.Read(p)
+	if !errors.Is(err, io.EOF) {
+		return n, err
+	}
+
+	for {
+		part, err := r.reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return n, io.EOF
+		}
+		if err != nil {
+			return n, err
+		}
+		if part.FormName() == r.field {
+			return n, errs.NewBadRequestError("only one file allowed per upload", false, nil, nil, nil)
+		}
+	}
+}
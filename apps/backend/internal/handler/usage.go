@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/usage"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type UsageHandler struct {
+	Handler
+	usageService *service.UsageService
+}
+
+func NewUsageHandler(s *server.Server, usageService *service.UsageService) *UsageHandler {
+	return &UsageHandler{
+		Handler:      NewHandler(s),
+		usageService: usageService,
+	}
+}
+
+func (h *UsageHandler) GetUsage(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *usage.GetUsageQuery) (*usage.Usage, error) {
+			userID := middleware.GetUserID(c)
+			workspaceID := middleware.GetWorkspaceID(c)
+			return h.usageService.GetUsage(c, userID, workspaceID, query)
+		},
+		http.StatusOK,
+		&usage.GetUsageQuery{},
+	)(c)
+}
+
+func (h *UsageHandler) GetAPIUsage(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, _ *usage.GetAPIUsagePayload) (*usage.APIUsage, error) {
+			userID := middleware.GetUserID(c)
+			return h.usageService.GetAPIUsage(c, userID)
+		},
+		http.StatusOK,
+		&usage.GetAPIUsagePayload{},
+	)(c)
+}
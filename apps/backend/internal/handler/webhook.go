@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/webhook"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookHandler struct {
+	Handler
+	webhookService *service.WebhookService
+}
+
+func NewWebhookHandler(s *server.Server, webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		Handler:        NewHandler(s),
+		webhookService: webhookService,
+	}
+}
+
+func (h *WebhookHandler) GetEventTypes(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *webhook.GetEventTypesPayload) ([]webhook.EventType, error) {
+			return h.webhookService.GetEventTypes(c, payload), nil
+		},
+		http.StatusOK,
+		&webhook.GetEventTypesPayload{},
+	)(c)
+}
+
+func (h *WebhookHandler) GetEndpoints(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *webhook.GetEndpointsPayload) ([]webhook.Endpoint, error) {
+			return h.webhookService.GetEndpoints(c, payload)
+		},
+		http.StatusOK,
+		&webhook.GetEndpointsPayload{},
+	)(c)
+}
+
+func (h *WebhookHandler) CreateEndpoint(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *webhook.CreateEndpointPayload) (*webhook.CreatedEndpoint, error) {
+			userID := middleware.GetUserID(c)
+			return h.webhookService.CreateEndpoint(c, userID, payload)
+		},
+		http.StatusCreated,
+		&webhook.CreateEndpointPayload{},
+	)(c)
+}
+
+func (h *WebhookHandler) UpdateEndpoint(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *webhook.UpdateEndpointPayload) (*webhook.Endpoint, error) {
+			return h.webhookService.UpdateEndpoint(c, payload)
+		},
+		http.StatusOK,
+		&webhook.UpdateEndpointPayload{},
+	)(c)
+}
+
+func (h *WebhookHandler) DeleteEndpoint(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *webhook.DeleteEndpointPayload) error {
+			return h.webhookService.DeleteEndpoint(c, payload)
+		},
+		http.StatusNoContent,
+		&webhook.DeleteEndpointPayload{},
+	)(c)
+}
+
+func (h *WebhookHandler) TestEndpoint(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *webhook.TestEndpointPayload) (*webhook.TestDelivery, error) {
+			return h.webhookService.TestEndpoint(c, payload)
+		},
+		http.StatusOK,
+		&webhook.TestEndpointPayload{},
+	)(c)
+}
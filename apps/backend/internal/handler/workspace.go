@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/model/workspace"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type WorkspaceHandler struct {
+	Handler
+	workspaceService *service.WorkspaceService
+}
+
+func NewWorkspaceHandler(s *server.Server, workspaceService *service.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		Handler:          NewHandler(s),
+		workspaceService: workspaceService,
+	}
+}
+
+func (h *WorkspaceHandler) GetTemplates(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *workspace.GetActiveTemplatesPayload) ([]workspace.Template, error) {
+			return h.workspaceService.GetActiveTemplates(c)
+		},
+		http.StatusOK,
+		&workspace.GetActiveTemplatesPayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) ApplyTemplate(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *workspace.ApplyTemplatePayload) (*todo.Todo, error) {
+			userID := middleware.GetUserID(c)
+			return h.workspaceService.ApplyTemplate(c, userID, payload)
+		},
+		http.StatusCreated,
+		&workspace.ApplyTemplatePayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) GetWorkload(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, query *workspace.GetWorkloadQuery) (*workspace.Workload, error) {
+			return h.workspaceService.GetWorkload(c, query)
+		},
+		http.StatusOK,
+		&workspace.GetWorkloadQuery{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) GetBreachReport(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *workspace.GetBreachReportPayload) (*workspace.BreachReport, error) {
+			return h.workspaceService.GetBreachReport(c, payload)
+		},
+		http.StatusOK,
+		&workspace.GetBreachReportPayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) GetWorkflow(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *workspace.GetWorkspaceWorkflowPayload) (*workspace.Workflow, error) {
+			return h.workspaceService.GetWorkspaceWorkflow(c, payload)
+		},
+		http.StatusOK,
+		&workspace.GetWorkspaceWorkflowPayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) AcceptInvite(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *workspace.AcceptInvitePayload) (*workspace.InviteAcceptance, error) {
+			userID := middleware.GetUserID(c)
+			return h.workspaceService.AcceptInvite(c, userID, payload)
+		},
+		http.StatusOK,
+		&workspace.AcceptInvitePayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) GetWorkspaceTemplates(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *workspace.GetTemplatesPayload) ([]workspace.Template, error) {
+			return h.workspaceService.GetTemplates(c, payload.WorkspaceID)
+		},
+		http.StatusOK,
+		&workspace.GetTemplatesPayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) CreateTemplate(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *workspace.CreateTemplatePayload) (*workspace.Template, error) {
+			userID := middleware.GetUserID(c)
+			return h.workspaceService.CreateTemplate(c, userID, payload)
+		},
+		http.StatusCreated,
+		&workspace.CreateTemplatePayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) DeleteTemplate(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *workspace.DeleteTemplatePayload) error {
+			return h.workspaceService.DeleteTemplate(c, payload)
+		},
+		http.StatusNoContent,
+		&workspace.DeleteTemplatePayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) GetOnboardingKit(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *workspace.GetOnboardingKitPayload) (*workspace.OnboardingKit, error) {
+			return h.workspaceService.GetOnboardingKit(c, payload.WorkspaceID)
+		},
+		http.StatusOK,
+		&workspace.GetOnboardingKitPayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) SetOnboardingKit(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *workspace.SetOnboardingKitPayload) (*workspace.OnboardingKit, error) {
+			userID := middleware.GetUserID(c)
+			return h.workspaceService.SetOnboardingKit(c, userID, payload)
+		},
+		http.StatusOK,
+		&workspace.SetOnboardingKitPayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) DeleteOnboardingKit(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *workspace.DeleteOnboardingKitPayload) error {
+			return h.workspaceService.DeleteOnboardingKit(c, payload)
+		},
+		http.StatusNoContent,
+		&workspace.DeleteOnboardingKitPayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) GetAgingPolicies(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *workspace.GetAgingPoliciesPayload) ([]workspace.AgingPolicy, error) {
+			return h.workspaceService.GetAgingPolicies(c, payload.WorkspaceID)
+		},
+		http.StatusOK,
+		&workspace.GetAgingPoliciesPayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) CreateAgingPolicy(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *workspace.CreateAgingPolicyPayload) (*workspace.AgingPolicy, error) {
+			userID := middleware.GetUserID(c)
+			return h.workspaceService.CreateAgingPolicy(c, userID, payload)
+		},
+		http.StatusCreated,
+		&workspace.CreateAgingPolicyPayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) DeleteAgingPolicy(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *workspace.DeleteAgingPolicyPayload) error {
+			return h.workspaceService.DeleteAgingPolicy(c, payload)
+		},
+		http.StatusNoContent,
+		&workspace.DeleteAgingPolicyPayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) GetInvites(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *workspace.GetInvitesPayload) (*workspace.Invitations, error) {
+			return h.workspaceService.GetInvitations(c, payload.WorkspaceID)
+		},
+		http.StatusOK,
+		&workspace.GetInvitesPayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) CreateInvite(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *workspace.CreateInvitePayload) (*workspace.Invite, error) {
+			userID := middleware.GetUserID(c)
+			return h.workspaceService.CreateInvite(c, userID, payload)
+		},
+		http.StatusCreated,
+		&workspace.CreateInvitePayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) RevokeInvite(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *workspace.RevokeInvitePayload) error {
+			return h.workspaceService.RevokeInvite(c, payload)
+		},
+		http.StatusNoContent,
+		&workspace.RevokeInvitePayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) GetFreeze(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *workspace.GetFreezePayload) (*workspace.Freeze, error) {
+			return h.workspaceService.GetFreeze(c, payload.WorkspaceID)
+		},
+		http.StatusOK,
+		&workspace.GetFreezePayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) FreezeWorkspace(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *workspace.FreezeWorkspacePayload) (*workspace.Freeze, error) {
+			userID := middleware.GetUserID(c)
+			return h.workspaceService.FreezeWorkspace(c, userID, payload)
+		},
+		http.StatusCreated,
+		&workspace.FreezeWorkspacePayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) ReactivateWorkspace(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *workspace.ReactivateWorkspacePayload) error {
+			return h.workspaceService.ReactivateWorkspace(c, payload)
+		},
+		http.StatusNoContent,
+		&workspace.ReactivateWorkspacePayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) SetWorkflow(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *workspace.SetWorkflowPayload) (*workspace.Workflow, error) {
+			userID := middleware.GetUserID(c)
+			return h.workspaceService.SetWorkflow(c, userID, payload)
+		},
+		http.StatusOK,
+		&workspace.SetWorkflowPayload{},
+	)(c)
+}
+
+func (h *WorkspaceHandler) DeleteWorkflow(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, payload *workspace.DeleteWorkflowPayload) error {
+			return h.workspaceService.DeleteWorkflow(c, payload)
+		},
+		http.StatusNoContent,
+		&workspace.DeleteWorkflowPayload{},
+	)(c)
+}
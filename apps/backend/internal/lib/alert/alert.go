@@ -0,0 +1,74 @@
+package alert
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type State string
+
+const (
+	StateFiring   State = "firing"
+	StateResolved State = "resolved"
+)
+
+// Alert is an operational event worth paging someone for, or the end of one
+type Alert struct {
+	Name    string  `json:"name"`
+	State   State   `json:"state"`
+	Summary string  `json:"summary"`
+	Value   float64 `json:"value"`
+	// Threshold is the value the alarm fires at, zero for one-off events
+	Threshold   float64 `json:"threshold,omitempty"`
+	Environment string  `json:"environment"`
+	// Instance is set for alarms about a single instance, such as its database pool
+	Instance string    `json:"instance,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// Sink delivers alerts to the on-call tooling, the SNS sink of lib/aws is one
+type Sink interface {
+	Publish(ctx context.Context, alert *Alert) error
+}
+
+// Measurement is the value a check measured, recorded whether or not its alarm fires
+type Measurement struct {
+	Name     string
+	Value    float64
+	Instance string
+	At       time.Time
+}
+
+// Recorder keeps every measurement, so dashboards and alarms of their own can be built on
+// them. The CloudWatch recorder of lib/aws is one.
+type Recorder interface {
+	Record(ctx context.Context, environment string, measurements []Measurement) error
+}
+
+// LogSink writes alerts to the logs, for deployments without on-call tooling
+type LogSink struct {
+	logger *zerolog.Logger
+}
+
+func NewLogSink(logger *zerolog.Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+func (s *LogSink) Publish(ctx context.Context, alert *Alert) error {
+	event := s.logger.Error()
+	if alert.State == StateResolved {
+		event = s.logger.Info()
+	}
+
+	event.
+		Str("alert", alert.Name).
+		Str("state", string(alert.State)).
+		Float64("value", alert.Value).
+		Float64("threshold", alert.Threshold).
+		Str("instance", alert.Instance).
+		Msg(alert.Summary)
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+// evaluateTimeout bounds an evaluation of every check and the alerts it publishes
+const evaluateTimeout = 30 * time.Second
+
+// Check measures a value whose alarm fires once it reaches the threshold
+type Check struct {
+	Name string
+	// Summary describes the alarm while it fires, given the measured value
+	Summary   func(value float64) string
+	Threshold float64
+	// PerInstance checks measure the instance they run on, the others the whole deployment
+	PerInstance bool
+	Measure     func(ctx context.Context) (float64, error)
+}
+
+// Monitor evaluates the checks on an interval and publishes an alert when an alarm starts or
+// stops firing. Every instance runs the checks, Redis makes sure a deployment wide alarm is
+// published once rather than by each of them. A nil monitor publishes nothing.
+type Monitor struct {
+	sink        Sink
+	recorder    Recorder
+	redis       *redis.Client
+	environment string
+	instance    string
+	cooldown    time.Duration
+	logger      *zerolog.Logger
+
+	checks []Check
+	mu     sync.Mutex
+	firing map[string]bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewMonitor publishes to the sink and, when not nil, records the measurements. An alarm
+// published by one instance isn't published again by another before the cooldown passes.
+func NewMonitor(sink Sink, recorder Recorder, redisClient *redis.Client, environment string,
+	cooldown time.Duration, logger *zerolog.Logger,
+) *Monitor {
+	instance, _ := os.Hostname()
+
+	return &Monitor{
+		sink:        sink,
+		recorder:    recorder,
+		redis:       redisClient,
+		environment: environment,
+		instance:    instance,
+		cooldown:    cooldown,
+		logger:      logger,
+		firing:      make(map[string]bool),
+	}
+}
+
+// Register adds a check, before Start
+func (m *Monitor) Register(check Check) {
+	m.checks = append(m.checks, check)
+}
+
+// Evaluate runs every check once, publishing the alarms that started or stopped firing
+func (m *Monitor) Evaluate(ctx context.Context) {
+	measurements := make([]Measurement, 0, len(m.checks))
+	now := time.Now().UTC()
+
+	for _, check := range m.checks {
+		value, err := check.Measure(ctx)
+		if err != nil {
+			m.logger.Warn().Err(err).Str("alert", check.Name).Msg("failed to measure alarm check")
+			continue
+		}
+
+		measurement := Measurement{Name: check.Name, Value: value, At: now}
+		if check.PerInstance {
+			measurement.Instance = m.instance
+		}
+		measurements = append(measurements, measurement)
+
+		firing := value >= check.Threshold
+		m.mu.Lock()
+		changed := m.firing[check.Name] != firing
+		m.firing[check.Name] = firing
+		m.mu.Unlock()
+		if !changed {
+			continue
+		}
+
+		alert := &Alert{
+			Name:        check.Name,
+			State:       StateFiring,
+			Summary:     check.Summary(value),
+			Value:       value,
+			Threshold:   check.Threshold,
+			Environment: m.environment,
+			Instance:    measurement.Instance,
+			At:          now,
+		}
+		if !firing {
+			alert.State = StateResolved
+			alert.Summary = fmt.Sprintf("%s resolved", check.Name)
+		}
+		m.publish(ctx, alert)
+	}
+
+	if m.recorder != nil && len(measurements) > 0 {
+		if err := m.recorder.Record(ctx, m.environment, measurements); err != nil {
+			m.logger.Warn().Err(err).Msg("failed to record alarm measurements")
+		}
+	}
+}
+
+// Raise publishes a one-off event, such as a component switching itself off. The same event
+// isn't published again before the cooldown passes.
+func (m *Monitor) Raise(ctx context.Context, name, summary string) {
+	if m == nil {
+		return
+	}
+
+	m.publish(ctx, &Alert{
+		Name:        name,
+		State:       StateFiring,
+		Summary:     summary,
+		Environment: m.environment,
+		At:          time.Now().UTC(),
+	})
+}
+
+// publish claims the alert in Redis first, the instance that claims it publishes it. A
+// resolution is published by the instance that clears the claim. Without Redis every
+// instance publishes.
+func (m *Monitor) publish(ctx context.Context, alert *Alert) {
+	if m.redis != nil {
+		key := fmt.Sprintf("alerts:%s:%s:%s", m.environment, alert.Name, alert.Instance)
+
+		var claimed bool
+		var err error
+		if alert.State == StateFiring {
+			claimed, err = m.redis.SetNX(ctx, key, m.instance, m.cooldown).Result()
+		} else {
+			var deleted int64
+			deleted, err = m.redis.Del(ctx, key).Result()
+			claimed = deleted > 0
+		}
+		if err != nil {
+			m.logger.Warn().Err(err).Str("alert", alert.Name).Msg("failed to claim alert, publishing it anyway")
+		} else if !claimed {
+			return
+		}
+	}
+
+	if err := m.sink.Publish(ctx, alert); err != nil {
+		m.logger.Error().
+			Err(err).
+			Str("alert", alert.Name).
+			Str("state", string(alert.State)).
+			Str("summary", alert.Summary).
+			Msg("failed to publish alert")
+		return
+	}
+
+	m.logger.Info().
+		Str("alert", alert.Name).
+		Str("state", string(alert.State)).
+		Msg("alert published")
+}
+
+// Start evaluates the checks every interval until Stop
+func (m *Monitor) Start(interval time.Duration) {
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+
+	go func() {
+		defer close(m.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), evaluateTimeout)
+				m.Evaluate(ctx)
+				cancel()
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the evaluation loop
+func (m *Monitor) Stop() {
+	if m == nil || m.stop == nil {
+		return
+	}
+
+	close(m.stop)
+	<-m.done
+}
@@ -0,0 +1,226 @@
+package analytics
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// flushTimeout bounds a flush, events that didn't make it are retried with the next one
+	flushTimeout = 10 * time.Second
+	// maxBufferedBatches bounds the events held while the sink is unreachable, the oldest are
+	// dropped beyond it
+	maxBufferedBatches = 20
+)
+
+// Sink receives batches of events, at most the batch size at a time
+type Sink interface {
+	Write(ctx context.Context, events []Event) error
+}
+
+// Emitter scrubs tracked events and hands them to the sink in batches, so tracking costs no
+// round trip on the request path. A nil emitter tracks nothing.
+type Emitter struct {
+	sink        Sink
+	salt        []byte
+	environment string
+	batchSize   int
+	logger      *zerolog.Logger
+
+	mu      sync.Mutex
+	pending []Event
+	dropped int
+
+	flush chan struct{}
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+func NewEmitter(sink Sink, salt, environment string, batchSize int, logger *zerolog.Logger) *Emitter {
+	return &Emitter{
+		sink:        sink,
+		salt:        []byte(salt),
+		environment: environment,
+		batchSize:   batchSize,
+		logger:      logger,
+		flush:       make(chan struct{}, 1),
+	}
+}
+
+// Track queues an event of the user and, when set, their active workspace. Unknown events
+// are dropped and so are the properties the schema of the event doesn't allow.
+func (e *Emitter) Track(userID, workspaceID, name string, properties Properties) {
+	if e == nil || userID == "" {
+		return
+	}
+
+	schema, ok := Schemas[name]
+	if !ok {
+		e.logger.Warn().Str("event", name).Msg("dropping analytics event without a schema")
+		return
+	}
+
+	event := Event{
+		SchemaVersion: SchemaVersion,
+		ID:            uuid.New(),
+		Name:          name,
+		OccurredAt:    time.Now().UTC().Truncate(time.Minute),
+		Environment:   e.environment,
+		AnonymousID:   e.anonymize(userID),
+		Properties:    scrub(schema, properties),
+	}
+	if workspaceID != "" {
+		event.WorkspaceID = e.anonymize(workspaceID)
+	}
+
+	e.mu.Lock()
+	e.pending = append(e.pending, event)
+	full := len(e.pending) >= e.batchSize
+	e.mu.Unlock()
+
+	if full {
+		select {
+		case e.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// anonymize replaces an ID with a keyed hash, stable per salt so events of one user can be
+// linked without the ID being recoverable
+func (e *Emitter) anonymize(id string) string {
+	mac := hmac.New(sha256.New, e.salt)
+	mac.Write([]byte(id))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// scrub keeps the properties the schema lists with a value of their kind
+func scrub(schema map[string]Property, properties Properties) map[string]any {
+	scrubbed := make(map[string]any, len(properties))
+
+	for key, value := range properties {
+		property, ok := schema[key]
+		if !ok {
+			continue
+		}
+
+		switch property.Kind {
+		case KindBool:
+			if v, ok := value.(bool); ok {
+				scrubbed[key] = v
+			}
+		case KindNumber:
+			switch v := value.(type) {
+			case int:
+				scrubbed[key] = v
+			case int64:
+				scrubbed[key] = v
+			case float64:
+				scrubbed[key] = v
+			}
+		case KindEnum:
+			if v, ok := value.(string); ok && slices.Contains(property.Values, v) {
+				scrubbed[key] = v
+			}
+		}
+	}
+
+	return scrubbed
+}
+
+// Flush writes the pending events in batches. On failure they are kept for the next flush,
+// up to maxBufferedBatches.
+func (e *Emitter) Flush(ctx context.Context) error {
+	e.mu.Lock()
+	pending := e.pending
+	e.pending = nil
+	e.mu.Unlock()
+
+	for len(pending) > 0 {
+		batch := pending[:min(e.batchSize, len(pending))]
+
+		if err := e.sink.Write(ctx, batch); err != nil {
+			e.requeue(pending)
+			return err
+		}
+
+		pending = pending[len(batch):]
+	}
+
+	return nil
+}
+
+func (e *Emitter) requeue(events []Event) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.pending = append(events, e.pending...)
+	if limit := e.batchSize * maxBufferedBatches; len(e.pending) > limit {
+		e.dropped += len(e.pending) - limit
+		e.pending = slices.Clone(e.pending[len(e.pending)-limit:])
+	}
+}
+
+// Start flushes every interval, and as soon as a batch is full, until Stop
+func (e *Emitter) Start(interval time.Duration) {
+	e.stop = make(chan struct{})
+	e.done = make(chan struct{})
+
+	go func() {
+		defer close(e.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				e.flushWithTimeout(context.Background())
+			case <-e.flush:
+				e.flushWithTimeout(context.Background())
+			case <-e.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the flush loop and flushes what is left
+func (e *Emitter) Stop(ctx context.Context) {
+	if e == nil {
+		return
+	}
+
+	if e.stop != nil {
+		close(e.stop)
+		<-e.done
+	}
+
+	e.flushWithTimeout(ctx)
+}
+
+func (e *Emitter) flushWithTimeout(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, flushTimeout)
+	defer cancel()
+
+	if err := e.Flush(ctx); err != nil {
+		e.logger.Warn().Err(err).Msg("failed to flush analytics events, retrying with the next flush")
+	}
+
+	e.mu.Lock()
+	dropped := e.dropped
+	e.dropped = 0
+	e.mu.Unlock()
+
+	if dropped > 0 {
+		e.logger.Warn().Int("dropped_count", dropped).Msg("dropped analytics events the sink couldn't take")
+	}
+}
@@ -0,0 +1,117 @@
+package analytics
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SchemaVersion is carried by every event, it changes when the envelope or a property
+// changes meaning
+const SchemaVersion = 1
+
+// Product events
+const (
+	EventTodoCreated            = "todo_created"
+	EventTodoCompleted          = "todo_completed"
+	EventTodoUncompleted        = "todo_uncompleted"
+	EventTodoDeleted            = "todo_deleted"
+	EventCommentAdded           = "comment_added"
+	EventAttachmentUploaded     = "attachment_uploaded"
+	EventCategoryCreated        = "category_created"
+	EventCategoryBundleImported = "category_bundle_imported"
+	EventSearchPerformed        = "search_performed"
+	EventSubtasksSuggested      = "subtasks_suggested"
+	EventAccountExportRequested = "account_export_requested"
+	EventFocusSessionCompleted  = "focus_session_completed"
+)
+
+// Event is what a sink receives. It identifies no one: the user and workspace are salted
+// hashes and the time is truncated to the minute.
+type Event struct {
+	SchemaVersion int            `json:"schemaVersion"`
+	ID            uuid.UUID      `json:"id"`
+	Name          string         `json:"name"`
+	OccurredAt    time.Time      `json:"occurredAt"`
+	Environment   string         `json:"environment"`
+	AnonymousID   string         `json:"anonymousId"`
+	WorkspaceID   string         `json:"workspaceId,omitempty"`
+	Properties    map[string]any `json:"properties"`
+}
+
+// Properties are what a caller tracks, those the schema of the event doesn't list are dropped
+type Properties map[string]any
+
+type Kind int
+
+const (
+	KindBool Kind = iota
+	KindNumber
+	KindEnum
+)
+
+// Property describes a value an event may carry. Free text is never allowed, a string has
+// to be one of the values of an enum.
+type Property struct {
+	Kind   Kind
+	Values []string
+}
+
+var (
+	boolProperty   = Property{Kind: KindBool}
+	numberProperty = Property{Kind: KindNumber}
+	priorities     = Property{Kind: KindEnum, Values: []string{"low", "medium", "high"}}
+)
+
+// Schemas lists every event and the properties it may carry
+var Schemas = map[string]map[string]Property{
+	EventTodoCreated: {
+		"priority":     priorities,
+		"has_due_date": boolProperty,
+		"has_category": boolProperty,
+		"is_subtask":   boolProperty,
+	},
+	EventTodoCompleted: {
+		"priority":  priorities,
+		"age_hours": numberProperty,
+	},
+	EventTodoUncompleted: {
+		"priority": priorities,
+	},
+	EventTodoDeleted:  {},
+	EventCommentAdded: {},
+	EventAttachmentUploaded: {
+		"size_bytes": numberProperty,
+		"media_type": {Kind: KindEnum, Values: []string{"image", "video", "audio", "text", "application", "other"}},
+	},
+	EventCategoryCreated: {},
+	EventCategoryBundleImported: {
+		"created_count": numberProperty,
+		"skipped_count": numberProperty,
+	},
+	EventSearchPerformed: {
+		"mode":         {Kind: KindEnum, Values: []string{"keyword", "semantic"}},
+		"result_count": numberProperty,
+	},
+	EventSubtasksSuggested: {
+		"provider":         {Kind: KindEnum, Values: []string{"openai", "local"}},
+		"suggestion_count": numberProperty,
+	},
+	EventAccountExportRequested: {},
+	EventFocusSessionCompleted: {
+		"planned_minutes": numberProperty,
+		"focused_minutes": numberProperty,
+	},
+}
+
+// MediaType is the top-level type of a MIME type, the only part of an upload's type events carry
+func MediaType(mimeType string) string {
+	mediaType, _, _ := strings.Cut(mimeType, "/")
+	switch mediaType {
+	case "image", "video", "audio", "text", "application":
+		return mediaType
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,56 @@
+package analytics
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ObjectPutter stores an object, the S3 client of lib/aws is one
+type ObjectPutter interface {
+	PutObject(ctx context.Context, bucket string, key string, body io.Reader, contentType string) error
+}
+
+// S3Sink writes every batch as a gzipped NDJSON object, partitioned by day and hour so query
+// engines such as Athena can prune them: <prefix>dt=2026-01-02/hour=15/<time>-<id>.ndjson.gz
+type S3Sink struct {
+	client ObjectPutter
+	bucket string
+	prefix string
+}
+
+func NewS3Sink(client ObjectPutter, bucket, prefix string) *S3Sink {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	return &S3Sink{client: client, bucket: bucket, prefix: prefix}
+}
+
+func (s *S3Sink) Write(ctx context.Context, events []Event) error {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+
+	encoder := json.NewEncoder(writer)
+	for i := range events {
+		if err := encoder.Encode(&events[i]); err != nil {
+			return fmt.Errorf("failed to encode analytics event: %w", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to compress analytics events: %w", err)
+	}
+
+	now := time.Now().UTC()
+	key := fmt.Sprintf("%sdt=%s/hour=%02d/%s-%s.ndjson.gz", s.prefix, now.Format("2006-01-02"), now.Hour(),
+		now.Format("20060102T150405Z"), uuid.NewString())
+
+	return s.client.PutObject(ctx, s.bucket, key, &buffer, "application/gzip")
+}
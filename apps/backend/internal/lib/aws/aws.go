@@ -3,14 +3,18 @@ package aws
 import (
 	"context"
 
+	serverConfig "github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/lib/blob"
 	"github.com/Sameer16536/ExecuTask/internal/server"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/smithy-go/middleware"
 )
 
 type AWS struct {
-	S3 *S3Client
+	// Blobs is the storage driver the config selects, S3 unless self-hosted
+	Blobs blob.Store
 }
 
 func NewAWS(server *server.Server) (*AWS, error) {
@@ -23,9 +27,13 @@ func NewAWS(server *server.Server) (*AWS, error) {
 			awsConfig.SecretAccessKey,
 			"",
 		)),
+		config.WithAPIOptions([]func(*middleware.Stack) error{
+			addTracingMiddleware,
+			addBreakerMiddleware(server.Breakers),
+		}),
 	}
 
-	// Add custom endpoint if provided (for S3-compatible services like Sevalla)
+	// Add custom endpoint if provided (for S3-compatible services like Sevalla or MinIO)
 	if awsConfig.EndpointURL != "" {
 		configOptions = append(configOptions, config.WithEndpointResolverWithOptions(
 			aws.EndpointResolverWithOptionsFunc(func(service, region string,
@@ -44,7 +52,17 @@ func NewAWS(server *server.Server) (*AWS, error) {
 		return nil, err
 	}
 
+	var blobs blob.Store
+	switch server.Config.Storage.Driver {
+	case serverConfig.StorageDriverFileSystem:
+		blobs = blob.NewFileSystem(server.Config.Storage)
+	case serverConfig.StorageDriverMinIO:
+		blobs = NewS3Client(server, cfg, true)
+	default:
+		blobs = NewS3Client(server, cfg, false)
+	}
+
 	return &AWS{
-		S3: NewS3Client(server, cfg),
+		Blobs: blobs,
 	}, nil
 }
@@ -0,0 +1,44 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/breaker"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// breakerMiddleware runs every AWS API call, retries included, through the breaker of the
+// service, e.g. s3 for S3.PutObject
+func breakerMiddleware(breakers *breaker.Registry) middleware.InitializeMiddleware {
+	return middleware.InitializeMiddlewareFunc("CircuitBreaker",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (
+			out middleware.InitializeOutput, metadata middleware.Metadata, err error,
+		) {
+			b := breakers.Get(strings.ToLower(awsmiddleware.GetServiceID(ctx)))
+
+			err = b.Do(ctx, func(ctx context.Context) error {
+				var err error
+				out, metadata, err = next.HandleInitialize(ctx, in)
+
+				var responseErr interface{ HTTPStatusCode() int }
+				if errors.As(err, &responseErr) {
+					return rejected(responseErr.HTTPStatusCode(), err)
+				}
+				return err
+			})
+
+			return out, metadata, err
+		},
+	)
+}
+
+// addBreakerMiddleware runs inside the tracing middleware, calls failed by an open breaker
+// still show up in the traces
+func addBreakerMiddleware(breakers *breaker.Registry) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(breakerMiddleware(breakers), middleware.After)
+	}
+}
@@ -0,0 +1,55 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/alert"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+)
+
+// cloudWatchMaxMetrics is the most values PutMetricData takes in one call
+const cloudWatchMaxMetrics = 1000
+
+// CloudWatchRecorder puts the measurements of the alarm checks in a CloudWatch namespace,
+// dimensioned by environment and, for per instance checks, by instance
+type CloudWatchRecorder struct {
+	service   *queryService
+	namespace string
+}
+
+func NewCloudWatchRecorder(server *server.Server, namespace string) *CloudWatchRecorder {
+	return &CloudWatchRecorder{
+		service:   newQueryService(server.Config.AWS, server.Config.Outbound, server.Breakers, "monitoring", "2010-08-01"),
+		namespace: namespace,
+	}
+}
+
+func (r *CloudWatchRecorder) Record(ctx context.Context, environment string, measurements []alert.Measurement) error {
+	for start := 0; start < len(measurements); start += cloudWatchMaxMetrics {
+		chunk := measurements[start:min(start+cloudWatchMaxMetrics, len(measurements))]
+
+		params := url.Values{}
+		params.Set("Namespace", r.namespace)
+		for i, measurement := range chunk {
+			member := fmt.Sprintf("MetricData.member.%d.", i+1)
+			params.Set(member+"MetricName", measurement.Name)
+			params.Set(member+"Value", strconv.FormatFloat(measurement.Value, 'f', -1, 64))
+			params.Set(member+"Timestamp", measurement.At.Format("2006-01-02T15:04:05Z"))
+			params.Set(member+"Dimensions.member.1.Name", "Environment")
+			params.Set(member+"Dimensions.member.1.Value", environment)
+			if measurement.Instance != "" {
+				params.Set(member+"Dimensions.member.2.Name", "Instance")
+				params.Set(member+"Dimensions.member.2.Value", measurement.Instance)
+			}
+		}
+
+		if err := r.service.call(ctx, "PutMetricData", params); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
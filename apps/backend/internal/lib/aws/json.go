@@ -0,0 +1,142 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/lib/breaker"
+	"github.com/Sameer16536/ExecuTask/internal/lib/outbound"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// jsonService calls an AWS service over its JSON protocol with requests signed like the SDK
+// clients, for the services whose few calls don't justify the service module
+type jsonService struct {
+	name        string
+	target      string
+	client      *http.Client
+	endpoint    string
+	region      string
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	breaker     *breaker.Breaker
+}
+
+type jsonServiceError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+
+	service   string
+	operation string
+	status    int
+}
+
+func (e *jsonServiceError) Error() string {
+	return fmt.Sprintf("%s %s returned %d: %s: %s", e.service, e.operation, e.status, e.Type, e.Message)
+}
+
+// isServiceError tells whether the service rejected the call with the error type, e.g.
+// ConflictException. Some services prefix the type with its namespace.
+func isServiceError(err error, errorType string) bool {
+	var serviceErr *jsonServiceError
+	return errors.As(err, &serviceErr) &&
+		(serviceErr.Type == errorType || strings.HasSuffix(serviceErr.Type, "#"+errorType))
+}
+
+// newJSONService reaches the regional endpoint of the service unless endpointURL overrides
+// it. target prefixes the operation in the X-Amz-Target header. Calls go through the breaker
+// of the service.
+func newJSONService(awsConfig config.AWSConfig, outboundCfg *config.OutboundConfig, breakers *breaker.Registry,
+	name, target, endpointURL string,
+) *jsonService {
+	endpoint := endpointURL
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com/", name, awsConfig.Region)
+	}
+
+	return &jsonService{
+		name:     name,
+		target:   target,
+		client:   outbound.New(outboundCfg, outbound.Options{Timeout: 10 * time.Second}),
+		endpoint: endpoint,
+		region:   awsConfig.Region,
+		credentials: credentials.NewStaticCredentialsProvider(
+			awsConfig.AccessKeyID,
+			awsConfig.SecretAccessKey,
+			"",
+		),
+		signer:  v4.NewSigner(),
+		breaker: breakers.Get(name),
+	}
+}
+
+func (c *jsonService) call(ctx context.Context, operation string, input any, output any) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s %s request: %w", c.name, operation, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build %s %s request: %w", c.name, operation, err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", c.target+"."+operation)
+
+	creds, err := c.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+
+	payloadHash := sha256.Sum256(body)
+	err = c.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(payloadHash[:]), c.name, c.region, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to sign %s %s request: %w", c.name, operation, err)
+	}
+
+	return c.breaker.Do(ctx, func(ctx context.Context) error {
+		res, err := c.client.Do(req.WithContext(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to call %s %s: %w", c.name, operation, err)
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			message, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+
+			decoded := &jsonServiceError{service: c.name, operation: operation, status: res.StatusCode}
+			if json.Unmarshal(message, decoded) == nil && decoded.Type != "" {
+				return rejected(res.StatusCode, decoded)
+			}
+			return rejected(res.StatusCode, fmt.Errorf("%s %s returned %d: %s", c.name, operation, res.StatusCode,
+				strings.TrimSpace(string(message))))
+		}
+
+		if err := json.NewDecoder(res.Body).Decode(output); err != nil {
+			return fmt.Errorf("failed to decode %s %s response: %w", c.name, operation, err)
+		}
+
+		return nil
+	})
+}
+
+// rejected keeps the errors of a service turning down the call from tripping its breaker, the
+// service is up. Throttling and server errors count as failures.
+func rejected(status int, err error) error {
+	if status >= 400 && status < 500 && status != http.StatusTooManyRequests {
+		return breaker.Expected(err)
+	}
+	return err
+}
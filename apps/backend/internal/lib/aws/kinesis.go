@@ -0,0 +1,69 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/analytics"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+)
+
+// kinesisMaxRecords is the most records PutRecords takes in one call
+const kinesisMaxRecords = 500
+
+// KinesisSink puts analytics events on a Kinesis data stream, one JSON record per event
+// partitioned by the anonymous user
+type KinesisSink struct {
+	service *jsonService
+	stream  string
+}
+
+func NewKinesisSink(server *server.Server, stream string) *KinesisSink {
+	return &KinesisSink{
+		service: newJSONService(server.Config.AWS, server.Config.Outbound, server.Breakers, "kinesis", "Kinesis_20131202", ""),
+		stream:  stream,
+	}
+}
+
+type kinesisRecord struct {
+	Data         []byte `json:"Data"`
+	PartitionKey string `json:"PartitionKey"`
+}
+
+type kinesisPutRecordsInput struct {
+	StreamName string          `json:"StreamName"`
+	Records    []kinesisRecord `json:"Records"`
+}
+
+type kinesisPutRecordsOutput struct {
+	FailedRecordCount int `json:"FailedRecordCount"`
+}
+
+// Write fails when any record was rejected, the emitter then retries the whole batch so a
+// few events may arrive twice. Consumers deduplicate on the event ID.
+func (k *KinesisSink) Write(ctx context.Context, events []analytics.Event) error {
+	for start := 0; start < len(events); start += kinesisMaxRecords {
+		chunk := events[start:min(start+kinesisMaxRecords, len(events))]
+
+		records := make([]kinesisRecord, len(chunk))
+		for i := range chunk {
+			data, err := json.Marshal(&chunk[i])
+			if err != nil {
+				return fmt.Errorf("failed to encode analytics event: %w", err)
+			}
+			records[i] = kinesisRecord{Data: data, PartitionKey: chunk[i].AnonymousID}
+		}
+
+		var output kinesisPutRecordsOutput
+		err := k.service.call(ctx, "PutRecords", kinesisPutRecordsInput{StreamName: k.stream, Records: records}, &output)
+		if err != nil {
+			return err
+		}
+		if output.FailedRecordCount > 0 {
+			return fmt.Errorf("kinesis rejected %d of %d analytics records", output.FailedRecordCount, len(records))
+		}
+	}
+
+	return nil
+}
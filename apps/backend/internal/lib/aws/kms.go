@@ -0,0 +1,67 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/Sameer16536/ExecuTask/internal/server"
+)
+
+// KMSClient wraps data keys with a KMS key over the KMS JSON protocol, the two calls it needs
+// don't justify the service module
+type KMSClient struct {
+	service *jsonService
+	keyID   string
+}
+
+// NewKMSClient reaches KMS with the AWS credentials of the config. keyID is the ID, ARN or
+// alias new data keys are generated under, endpointURL overrides the regional endpoint.
+func NewKMSClient(server *server.Server, keyID string, endpointURL string) *KMSClient {
+	return &KMSClient{
+		service: newJSONService(server.Config.AWS, server.Config.Outbound, server.Breakers, "kms", "TrentService", endpointURL),
+		keyID:   keyID,
+	}
+}
+
+type kmsGenerateDataKeyInput struct {
+	KeyId   string `json:"KeyId"`
+	KeySpec string `json:"KeySpec"`
+}
+
+type kmsGenerateDataKeyOutput struct {
+	CiphertextBlob []byte `json:"CiphertextBlob"`
+	Plaintext      []byte `json:"Plaintext"`
+	KeyId          string `json:"KeyId"`
+}
+
+type kmsDecryptInput struct {
+	CiphertextBlob []byte `json:"CiphertextBlob"`
+	KeyId          string `json:"KeyId"`
+}
+
+type kmsDecryptOutput struct {
+	Plaintext []byte `json:"Plaintext"`
+}
+
+// GenerateDataKey reports the ARN of the key that wrapped the data key, an alias may point
+// elsewhere later
+func (c *KMSClient) GenerateDataKey(ctx context.Context) ([]byte, []byte, string, error) {
+	var output kmsGenerateDataKeyOutput
+	err := c.service.call(ctx, "GenerateDataKey", kmsGenerateDataKeyInput{KeyId: c.keyID, KeySpec: "AES_256"}, &output)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return output.Plaintext, output.CiphertextBlob, output.KeyId, nil
+}
+
+// UnwrapDataKey decrypts with the KMS key that wrapped the data key, so keys wrapped before the
+// configured key changed still open
+func (c *KMSClient) UnwrapDataKey(ctx context.Context, masterKeyID string, wrapped []byte) ([]byte, error) {
+	var output kmsDecryptOutput
+	err := c.service.call(ctx, "Decrypt", kmsDecryptInput{CiphertextBlob: wrapped, KeyId: masterKeyID}, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Plaintext, nil
+}
@@ -0,0 +1,106 @@
+package aws
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/lib/breaker"
+	"github.com/Sameer16536/ExecuTask/internal/lib/outbound"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// queryService calls an AWS service over its query protocol, form encoded actions answered
+// in XML, for the services that never moved to JSON such as SNS
+type queryService struct {
+	name        string
+	version     string
+	client      *http.Client
+	endpoint    string
+	region      string
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	breaker     *breaker.Breaker
+}
+
+type queryServiceError struct {
+	Code    string `xml:"Error>Code"`
+	Message string `xml:"Error>Message"`
+}
+
+// newQueryService reaches the regional endpoint of the service, whose API version is sent
+// with every action. Calls go through the breaker of the service.
+func newQueryService(awsConfig config.AWSConfig, outboundCfg *config.OutboundConfig, breakers *breaker.Registry,
+	name, version string,
+) *queryService {
+	return &queryService{
+		name:     name,
+		version:  version,
+		client:   outbound.New(outboundCfg, outbound.Options{Timeout: 10 * time.Second}),
+		endpoint: fmt.Sprintf("https://%s.%s.amazonaws.com/", name, awsConfig.Region),
+		region:   awsConfig.Region,
+		credentials: credentials.NewStaticCredentialsProvider(
+			awsConfig.AccessKeyID,
+			awsConfig.SecretAccessKey,
+			"",
+		),
+		signer:  v4.NewSigner(),
+		breaker: breakers.Get(name),
+	}
+}
+
+// call runs the action, the response is only checked for an error
+func (c *queryService) call(ctx context.Context, action string, params url.Values) error {
+	params.Set("Action", action)
+	params.Set("Version", c.version)
+	body := params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, strings.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build %s %s request: %w", c.name, action, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	creds, err := c.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+
+	payloadHash := sha256.Sum256([]byte(body))
+	err = c.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(payloadHash[:]), c.name, c.region, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to sign %s %s request: %w", c.name, action, err)
+	}
+
+	return c.breaker.Do(ctx, func(ctx context.Context) error {
+		res, err := c.client.Do(req.WithContext(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to call %s %s: %w", c.name, action, err)
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			message, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+
+			var decoded queryServiceError
+			if xml.Unmarshal(message, &decoded) == nil && decoded.Code != "" {
+				return rejected(res.StatusCode, fmt.Errorf("%s %s returned %d: %s: %s", c.name, action,
+					res.StatusCode, decoded.Code, decoded.Message))
+			}
+			return rejected(res.StatusCode, fmt.Errorf("%s %s returned %d: %s", c.name, action, res.StatusCode,
+				strings.TrimSpace(string(message))))
+		}
+
+		return nil
+	})
+}
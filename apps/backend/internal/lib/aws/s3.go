@@ -3,14 +3,18 @@ package aws
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 
+	"github.com/Sameer16536/ExecuTask/internal/lib/blob"
 	"github.com/Sameer16536/ExecuTask/internal/server"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
 type S3Client struct {
@@ -18,46 +22,159 @@ type S3Client struct {
 	client *s3.Client
 }
 
-func NewS3Client(server *server.Server, cfg aws.Config) *S3Client {
+// NewS3Client is the S3 storage driver. Path style addressing reaches servers such as MinIO,
+// which serve buckets under the path rather than a subdomain.
+func NewS3Client(server *server.Server, cfg aws.Config, usePathStyle bool) *S3Client {
 	return &S3Client{
 		server: server,
-		client: s3.NewFromConfig(cfg),
+		client: s3.NewFromConfig(cfg, func(options *s3.Options) {
+			options.UsePathStyle = usePathStyle
+		}),
 	}
 }
 
+// uploadPartSize is the size of the parts large uploads are streamed to S3 in, the most an
+// upload holds in memory. S3 takes parts of 5 MiB and more.
+const uploadPartSize = 8 << 20
+
+// UploadFile streams the file, one request when it fits in a part and a multipart upload
+// otherwise. A failed multipart upload is aborted, no parts are left behind.
 func (s *S3Client) UploadFile(ctx context.Context, bucket string, fileName string, file io.Reader) (string, error) {
 	fileKey := fmt.Sprintf("%s_%d", fileName, time.Now().Unix())
 
-	var buffer bytes.Buffer
-	_, err := io.Copy(&buffer, file)
-	if err != nil {
+	part := make([]byte, uploadPartSize)
+	n, err := io.ReadFull(file, part)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
 		return "", fmt.Errorf("failed to read file: %w", err)
 	}
+	contentType := http.DetectContentType(part[:n])
 
-	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+	if n < uploadPartSize {
+		_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:      aws.String(bucket),
+			Key:         aws.String(fileKey),
+			Body:        bytes.NewReader(part[:n]),
+			ContentType: aws.String(contentType),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to upload file to S3: %w", err)
+		}
+		return fileKey, nil
+	}
+
+	upload, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
 		Bucket:      aws.String(bucket),
 		Key:         aws.String(fileKey),
-		Body:        bytes.NewReader(buffer.Bytes()),
-		ContentType: aws.String(http.DetectContentType(buffer.Bytes())),
+		ContentType: aws.String(contentType),
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to upload file to S3: %w", err)
+		return "", fmt.Errorf("failed to start multipart upload to S3: %w", err)
+	}
+
+	if err := s.uploadParts(ctx, bucket, fileKey, upload.UploadId, part, file); err != nil {
+		// Aborted even when the upload was cancelled, the parts would be billed otherwise
+		_, abortErr := s.client.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(bucket),
+			Key:      aws.String(fileKey),
+			UploadId: upload.UploadId,
+		})
+		if abortErr != nil {
+			s.server.Logger.Error().Err(abortErr).Str("key", fileKey).Msg("failed to abort multipart upload")
+		}
+		return "", err
 	}
 
 	return fileKey, nil
 }
 
+// uploadParts uploads the first part, already read, then the rest of the file
+func (s *S3Client) uploadParts(ctx context.Context, bucket, key string, uploadID *string, part []byte,
+	file io.Reader,
+) error {
+	completed := []types.CompletedPart{}
+
+	n := len(part)
+	for partNumber := int32(1); n > 0; partNumber++ {
+		output, err := s.client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(bucket),
+			Key:        aws.String(key),
+			UploadId:   uploadID,
+			PartNumber: aws.Int32(partNumber),
+			Body:       bytes.NewReader(part[:n]),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to upload part %d to S3: %w", partNumber, err)
+		}
+		completed = append(completed, types.CompletedPart{ETag: output.ETag, PartNumber: aws.Int32(partNumber)})
+
+		n, err = io.ReadFull(file, part)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+	}
+
+	_, err := s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucket),
+		Key:             aws.String(key),
+		UploadId:        uploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload to S3: %w", err)
+	}
+
+	return nil
+}
+
+// PutObject stores a body under the exact key given. Seekable bodies such as files are
+// streamed, others should go through UploadFile.
+func (s *S3Client) PutObject(ctx context.Context, bucket string, key string, body io.Reader, contentType string) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// GetObject opens an object for reading, the caller closes it
+func (s *S3Client) GetObject(ctx context.Context, bucket string, key string) (io.ReadCloser, error) {
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object %s: %w", key, err)
+	}
+
+	return output.Body, nil
+}
+
 func (s *S3Client) CreatePresignedUrl(ctx context.Context, bucket string, objectKey string) (string, error) {
+	return s.CreatePresignedDownloadUrl(ctx, bucket, objectKey, time.Minute*60, "")
+}
+
+// CreatePresignedDownloadUrl signs a link valid for the given duration. With a file name the
+// browser saves the object under it.
+func (s *S3Client) CreatePresignedDownloadUrl(ctx context.Context, bucket string, objectKey string,
+	expiration time.Duration, fileName string,
+) (string, error) {
 	presignClient := s3.NewPresignClient(s.client)
 
-	expiration := time.Minute * 60
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(objectKey),
+	}
+	if fileName != "" {
+		input.ResponseContentDisposition = aws.String(fmt.Sprintf("attachment; filename=%q", fileName))
+	}
 
-	presignedUrl, err := presignClient.PresignGetObject(ctx,
-		&s3.GetObjectInput{
-			Bucket: aws.String(bucket),
-			Key:    aws.String(objectKey),
-		},
-		s3.WithPresignExpires(expiration))
+	presignedUrl, err := presignClient.PresignGetObject(ctx, input, s3.WithPresignExpires(expiration))
 	if err != nil {
 		return "", err
 	}
@@ -76,3 +193,67 @@ func (s *S3Client) DeleteObject(ctx context.Context, bucket string, key string)
 
 	return nil
 }
+
+// ListObjects walks the objects under a prefix, fn is called with every page of at most 1000
+func (s *S3Client) ListObjects(ctx context.Context, bucket string, prefix string,
+	fn func(objects []blob.ObjectInfo) error,
+) error {
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list objects under %s: %w", prefix, err)
+		}
+
+		objects := make([]blob.ObjectInfo, 0, len(page.Contents))
+		for _, object := range page.Contents {
+			objects = append(objects, blob.ObjectInfo{
+				Key:          aws.ToString(object.Key),
+				Size:         aws.ToInt64(object.Size),
+				LastModified: aws.ToTime(object.LastModified),
+				StorageClass: string(object.StorageClass),
+			})
+		}
+
+		if err := fn(objects); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetStorageClass moves an object to another storage class by copying it onto itself, its
+// content type and metadata are kept
+func (s *S3Client) SetStorageClass(ctx context.Context, bucket string, key string, storageClass string) error {
+	source := (&url.URL{Path: bucket + "/" + key}).EscapedPath()
+
+	_, err := s.client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:            aws.String(bucket),
+		Key:               aws.String(key),
+		CopySource:        aws.String(source),
+		MetadataDirective: types.MetadataDirectiveCopy,
+		StorageClass:      types.StorageClass(storageClass),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set storage class of object %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// HeadBucket checks that the bucket exists and the credentials may access it
+func (s *S3Client) HeadBucket(ctx context.Context, bucket string) error {
+	_, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to access bucket %s: %w", bucket, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/alert"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+)
+
+// snsMaxSubject is the longest subject SNS takes, email subscriptions show it
+const snsMaxSubject = 100
+
+// SNSSink publishes alerts to an SNS topic the on-call tooling subscribes to. The message is
+// the alert as JSON, the subject a line for email subscriptions.
+type SNSSink struct {
+	service  *queryService
+	topicARN string
+}
+
+func NewSNSSink(server *server.Server, topicARN string) *SNSSink {
+	return &SNSSink{
+		service:  newQueryService(server.Config.AWS, server.Config.Outbound, server.Breakers, "sns", "2010-03-31"),
+		topicARN: topicARN,
+	}
+}
+
+func (s *SNSSink) Publish(ctx context.Context, a *alert.Alert) error {
+	message, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	subject := fmt.Sprintf("[%s] %s %s", a.Environment, a.Name, a.State)
+	if len(subject) > snsMaxSubject {
+		subject = subject[:snsMaxSubject]
+	}
+
+	params := url.Values{}
+	params.Set("TopicArn", s.topicARN)
+	params.Set("Subject", subject)
+	params.Set("Message", string(message))
+	params.Set("MessageAttributes.entry.1.Name", "state")
+	params.Set("MessageAttributes.entry.1.Value.DataType", "String")
+	params.Set("MessageAttributes.entry.1.Value.StringValue", string(a.State))
+
+	return s.service.call(ctx, "Publish", params)
+}
@@ -0,0 +1,40 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/tracing"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingMiddleware wraps every AWS API call, retries included, in a client span named
+// after the service and operation, e.g. S3.PutObject
+var tracingMiddleware = middleware.InitializeMiddlewareFunc("OpenTelemetryTracing",
+	func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (
+		out middleware.InitializeOutput, metadata middleware.Metadata, err error,
+	) {
+		service := awsmiddleware.GetServiceID(ctx)
+		operation := awsmiddleware.GetOperationName(ctx)
+
+		ctx, span := tracing.Tracer().Start(ctx, service+"."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.RPCSystemKey.String("aws-api"),
+				semconv.RPCService(service),
+				semconv.RPCMethod(operation),
+			),
+		)
+		defer func() {
+			tracing.End(span, err)
+		}()
+
+		return next.HandleInitialize(ctx, in)
+	},
+)
+
+func addTracingMiddleware(stack *middleware.Stack) error {
+	return stack.Initialize.Add(tracingMiddleware, middleware.Before)
+}
@@ -0,0 +1,141 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/lib/transcribe"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+)
+
+// transcribeJobPrefix keeps the jobs of the app apart from others in the account
+const transcribeJobPrefix = "executask-"
+
+// TranscribeProvider transcribes voice notes with AWS Transcribe over its JSON protocol, reading
+// the audio straight from the bucket
+type TranscribeProvider struct {
+	service      *jsonService
+	pollInterval time.Duration
+}
+
+func NewTranscribeProvider(server *server.Server, cfg *config.TranscriptionConfig) *TranscribeProvider {
+	return &TranscribeProvider{
+		service:      newJSONService(server.Config.AWS, server.Config.Outbound, server.Breakers, "transcribe", "Transcribe", cfg.EndpointURL),
+		pollInterval: cfg.PollInterval,
+	}
+}
+
+type transcribeStartJobInput struct {
+	TranscriptionJobName string          `json:"TranscriptionJobName"`
+	LanguageCode         string          `json:"LanguageCode,omitempty"`
+	IdentifyLanguage     bool            `json:"IdentifyLanguage,omitempty"`
+	MediaFormat          string          `json:"MediaFormat"`
+	Media                transcribeMedia `json:"Media"`
+}
+
+type transcribeMedia struct {
+	MediaFileUri string `json:"MediaFileUri"`
+}
+
+type transcribeGetJobInput struct {
+	TranscriptionJobName string `json:"TranscriptionJobName"`
+}
+
+type transcribeJobOutput struct {
+	TranscriptionJob struct {
+		TranscriptionJobStatus string `json:"TranscriptionJobStatus"`
+		FailureReason          string `json:"FailureReason"`
+		Transcript             struct {
+			TranscriptFileUri string `json:"TranscriptFileUri"`
+		} `json:"Transcript"`
+	} `json:"TranscriptionJob"`
+}
+
+type transcribeResult struct {
+	Results struct {
+		Transcripts []struct {
+			Transcript string `json:"transcript"`
+		} `json:"transcripts"`
+	} `json:"results"`
+}
+
+func (p *TranscribeProvider) Name() string {
+	return config.TranscriptionProviderAWS
+}
+
+func (p *TranscribeProvider) Transcribe(ctx context.Context, audio transcribe.Audio) (string, error) {
+	jobName := transcribeJobPrefix + audio.ID
+
+	input := transcribeStartJobInput{
+		TranscriptionJobName: jobName,
+		LanguageCode:         audio.LanguageCode,
+		IdentifyLanguage:     audio.LanguageCode == "",
+		MediaFormat:          audio.Format,
+		Media:                transcribeMedia{MediaFileUri: fmt.Sprintf("s3://%s/%s", audio.Bucket, audio.Key)},
+	}
+	var started transcribeJobOutput
+	err := p.service.call(ctx, "StartTranscriptionJob", input, &started)
+	// A retry finds the job it started before
+	if err != nil && !isServiceError(err, "ConflictException") {
+		return "", err
+	}
+
+	ticker := time.NewTicker(p.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		var output transcribeJobOutput
+		if err := p.service.call(ctx, "GetTranscriptionJob", transcribeGetJobInput{TranscriptionJobName: jobName}, &output); err != nil {
+			return "", err
+		}
+
+		job := output.TranscriptionJob
+		switch job.TranscriptionJobStatus {
+		case "COMPLETED":
+			return p.fetchTranscript(ctx, job.Transcript.TranscriptFileUri)
+		case "FAILED":
+			return "", fmt.Errorf("transcription job %s failed: %s", jobName, job.FailureReason)
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// fetchTranscript downloads the result of a job, the URI is presigned by the service
+func (p *TranscribeProvider) fetchTranscript(ctx context.Context, uri string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build transcript request: %w", err)
+	}
+
+	res, err := p.service.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download transcript: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("transcript download returned %d", res.StatusCode)
+	}
+
+	var result transcribeResult
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode transcript: %w", err)
+	}
+
+	parts := make([]string, 0, len(result.Results.Transcripts))
+	for _, transcript := range result.Results.Transcripts {
+		parts = append(parts, transcript.Transcript)
+	}
+
+	return strings.TrimSpace(strings.Join(parts, " ")), nil
+}
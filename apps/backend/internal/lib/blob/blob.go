@@ -0,0 +1,45 @@
+// Package blob stores the objects of the API, attachments, exports and backups among them, in
+// buckets keyed like S3. The S3 driver lives in the aws package, FileSystem keeps objects on a
+// local disk for self-hosted deployments and integration tests.
+package blob
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Store is implemented by every storage driver
+type Store interface {
+	// UploadFile streams an upload of any size and stores it under the file name suffixed with
+	// the time, it returns the key. An upload failing midway stores nothing.
+	UploadFile(ctx context.Context, bucket string, fileName string, file io.Reader) (string, error)
+	// PutObject stores a body under the exact key given
+	PutObject(ctx context.Context, bucket string, key string, body io.Reader, contentType string) error
+	// GetObject opens an object for reading, the caller closes it
+	GetObject(ctx context.Context, bucket string, key string) (io.ReadCloser, error)
+	// CreatePresignedUrl signs a download link valid for an hour
+	CreatePresignedUrl(ctx context.Context, bucket string, objectKey string) (string, error)
+	// CreatePresignedDownloadUrl signs a link valid for the given duration. With a file name the
+	// browser saves the object under it.
+	CreatePresignedDownloadUrl(ctx context.Context, bucket string, objectKey string, expiration time.Duration,
+		fileName string) (string, error)
+	DeleteObject(ctx context.Context, bucket string, key string) error
+	// ListObjects walks the objects under a prefix, fn is called with every page of at most 1000
+	ListObjects(ctx context.Context, bucket string, prefix string, fn func(objects []ObjectInfo) error) error
+	// SetStorageClass moves an object to another storage class, drivers without classes ignore it
+	SetStorageClass(ctx context.Context, bucket string, key string, storageClass string) error
+	// HeadBucket checks that the bucket can be reached
+	HeadBucket(ctx context.Context, bucket string) error
+}
+
+// ObjectInfo describes a listed object
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+	StorageClass string
+}
+
+// listPageSize matches the page size of S3 listings
+const listPageSize = 1000
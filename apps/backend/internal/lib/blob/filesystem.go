@@ -0,0 +1,283 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+)
+
+// ErrInvalidLink is returned for download links that expired or weren't signed by the store
+var ErrInvalidLink = errors.New("invalid or expired download link")
+
+// tempPrefix marks the files being written, they are renamed into place once complete
+const tempPrefix = ".upload-"
+
+// FileSystem keeps every bucket in a directory under its root and every object in a file at
+// its key. Download links point at the /blobs route of the API, signed with the signing key.
+// Content types aren't kept, downloads are typed from the extension of the key.
+type FileSystem struct {
+	root       string
+	publicURL  string
+	signingKey []byte
+	now        func() time.Time
+}
+
+func NewFileSystem(cfg *config.StorageConfig) *FileSystem {
+	return &FileSystem{
+		root:       cfg.Path,
+		publicURL:  strings.TrimSuffix(cfg.PublicURL, "/"),
+		signingKey: []byte(cfg.SigningKey),
+		now:        time.Now,
+	}
+}
+
+func (f *FileSystem) UploadFile(ctx context.Context, bucket string, fileName string, file io.Reader) (string, error) {
+	fileKey := fmt.Sprintf("%s_%d", fileName, f.now().Unix())
+
+	if err := f.PutObject(ctx, bucket, fileKey, file, ""); err != nil {
+		return "", err
+	}
+
+	return fileKey, nil
+}
+
+// PutObject writes the body next to its key first, readers never see a partial object
+func (f *FileSystem) PutObject(ctx context.Context, bucket string, key string, body io.Reader, _ string) error {
+	objectPath, err := f.objectPath(bucket, key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(objectPath), 0o750); err != nil {
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(objectPath), tempPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := io.Copy(temp, body); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+	if err := os.Rename(temp.Name(), objectPath); err != nil {
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (f *FileSystem) GetObject(ctx context.Context, bucket string, key string) (io.ReadCloser, error) {
+	objectPath, err := f.objectPath(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(objectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object %s: %w", key, err)
+	}
+
+	return file, nil
+}
+
+func (f *FileSystem) CreatePresignedUrl(ctx context.Context, bucket string, objectKey string) (string, error) {
+	return f.CreatePresignedDownloadUrl(ctx, bucket, objectKey, time.Minute*60, "")
+}
+
+func (f *FileSystem) CreatePresignedDownloadUrl(ctx context.Context, bucket string, objectKey string,
+	expiration time.Duration, fileName string,
+) (string, error) {
+	if _, err := f.objectPath(bucket, objectKey); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(f.now().Add(expiration).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	if fileName != "" {
+		query.Set("filename", fileName)
+	}
+	query.Set("signature", f.sign(bucket+"/"+objectKey, expires, fileName))
+
+	link := url.URL{Path: "/blobs/" + bucket + "/" + objectKey, RawQuery: query.Encode()}
+	return f.publicURL + link.String(), nil
+}
+
+// DeleteObject removes an object and the directories it leaves empty. Deleting a missing
+// object succeeds, as it does on S3.
+func (f *FileSystem) DeleteObject(ctx context.Context, bucket string, key string) error {
+	objectPath, err := f.objectPath(bucket, key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(objectPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+
+	bucketPath := filepath.Join(f.root, bucket)
+	for dir := filepath.Dir(objectPath); dir != bucketPath; dir = filepath.Dir(dir) {
+		// Fails on the first directory still holding objects
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// ListObjects walks the objects under a prefix in the order of their keys, as S3 lists them
+func (f *FileSystem) ListObjects(ctx context.Context, bucket string, prefix string,
+	fn func(objects []ObjectInfo) error,
+) error {
+	bucketPath, err := f.objectPath(bucket, ".")
+	if err != nil {
+		return err
+	}
+
+	// Walk from the deepest directory every key under the prefix is in
+	start := bucketPath
+	if dir := path.Dir(prefix + "_"); dir != "." {
+		if !filepath.IsLocal(filepath.FromSlash(dir)) {
+			return fmt.Errorf("failed to list objects under %s: invalid prefix", prefix)
+		}
+		start = filepath.Join(bucketPath, filepath.FromSlash(dir))
+	}
+
+	objects := make([]ObjectInfo, 0, listPageSize)
+	err = filepath.WalkDir(start, func(objectPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), tempPrefix) {
+			return nil
+		}
+
+		relative, err := filepath.Rel(bucketPath, objectPath)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(relative)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+			StorageClass: "STANDARD",
+		})
+
+		if len(objects) == listPageSize {
+			if err := fn(objects); err != nil {
+				return err
+			}
+			objects = make([]ObjectInfo, 0, listPageSize)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list objects under %s: %w", prefix, err)
+	}
+
+	if len(objects) > 0 {
+		return fn(objects)
+	}
+	return nil
+}
+
+// SetStorageClass does nothing, a disk has a single class
+func (f *FileSystem) SetStorageClass(ctx context.Context, bucket string, key string, storageClass string) error {
+	return nil
+}
+
+// HeadBucket checks that the root is a directory, the bucket is created with its first object
+func (f *FileSystem) HeadBucket(ctx context.Context, bucket string) error {
+	info, err := os.Stat(f.root)
+	if err != nil {
+		return fmt.Errorf("failed to access bucket %s: %w", bucket, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("failed to access bucket %s: %s is not a directory", bucket, f.root)
+	}
+
+	return nil
+}
+
+// OpenSigned opens the object of a download link, objectPath is the bucket and the key. The
+// file name is the one the link asks the browser to save the object under, if any.
+func (f *FileSystem) OpenSigned(objectPath string, query url.Values) (*os.File, string, error) {
+	expires := query.Get("expires")
+	fileName := query.Get("filename")
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || f.now().Unix() > expiresAt {
+		return nil, "", ErrInvalidLink
+	}
+	signature := f.sign(objectPath, expires, fileName)
+	if !hmac.Equal([]byte(signature), []byte(query.Get("signature"))) {
+		return nil, "", ErrInvalidLink
+	}
+
+	bucket, key, ok := strings.Cut(objectPath, "/")
+	if !ok {
+		return nil, "", ErrInvalidLink
+	}
+	filePath, err := f.objectPath(bucket, key)
+	if err != nil {
+		return nil, "", ErrInvalidLink
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return file, fileName, nil
+}
+
+func (f *FileSystem) sign(objectPath, expires, fileName string) string {
+	mac := hmac.New(sha256.New, f.signingKey)
+	mac.Write([]byte(objectPath + "\n" + expires + "\n" + fileName))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// objectPath maps a key to its file, keys can't reach outside of their bucket
+func (f *FileSystem) objectPath(bucket, key string) (string, error) {
+	if !filepath.IsLocal(bucket) || strings.ContainsAny(bucket, `/\`) {
+		return "", fmt.Errorf("invalid bucket %q", bucket)
+	}
+	if !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+
+	return filepath.Join(f.root, bucket, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,233 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	// ErrOpen fails the calls to a dependency that kept failing, until it had time to recover
+	ErrOpen = errors.New("circuit breaker is open")
+	// ErrBulkheadFull fails the calls past the concurrency cap of a dependency
+	ErrBulkheadFull = errors.New("too many calls in flight")
+)
+
+type State string
+
+const (
+	// StateClosed lets every call through
+	StateClosed State = "closed"
+	// StateOpen fails every call at once
+	StateOpen State = "open"
+	// StateHalfOpen lets a single call through to probe whether the dependency recovered
+	StateHalfOpen State = "half_open"
+)
+
+// Settings tune the breaker of a dependency
+type Settings struct {
+	// FailureThreshold is how many consecutive failures trip the breaker, 0 never trips it
+	FailureThreshold int
+	// OpenTimeout is how long a tripped breaker fails calls before probing the dependency
+	OpenTimeout time.Duration
+	// MaxConcurrent caps the calls in flight, 0 is unlimited
+	MaxConcurrent int
+	// Timeout bounds every call, 0 leaves it to the caller
+	Timeout time.Duration
+}
+
+// Breaker guards the calls to a dependency. Past the failure threshold it opens and calls fail
+// with ErrOpen without reaching the dependency. After the open timeout one call probes it, a
+// success closes the breaker again and a failure keeps it open for another timeout. The bulkhead
+// fails calls with ErrBulkheadFull rather than queueing them behind a slow dependency.
+type Breaker struct {
+	name     string
+	settings Settings
+	logger   *zerolog.Logger
+	slots    chan struct{}
+	now      func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func New(name string, settings Settings, logger *zerolog.Logger) *Breaker {
+	var slots chan struct{}
+	if settings.MaxConcurrent > 0 {
+		slots = make(chan struct{}, settings.MaxConcurrent)
+	}
+
+	return &Breaker{
+		name:     name,
+		settings: settings,
+		logger:   logger,
+		slots:    slots,
+		now:      time.Now,
+		state:    StateClosed,
+	}
+}
+
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State is the state of the breaker, an open breaker past its timeout is reported half open
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.settings.OpenTimeout {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// Do calls fn unless the breaker is open or the bulkhead full. Errors fn wraps with Expected
+// don't count as failures, nor do calls whose context the caller cancelled. A nil breaker
+// calls fn as is.
+func (b *Breaker) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if b == nil {
+		return unwrapExpected(fn(ctx))
+	}
+
+	probe, err := b.allow()
+	if err != nil {
+		return err
+	}
+
+	if b.slots != nil {
+		select {
+		case b.slots <- struct{}{}:
+			defer func() { <-b.slots }()
+		default:
+			if probe {
+				b.release()
+			}
+			return ErrBulkheadFull
+		}
+	}
+
+	if b.settings.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.settings.Timeout)
+		defer cancel()
+	}
+
+	err = fn(ctx)
+
+	var expected *expectedError
+	switch {
+	case err == nil:
+		b.record(true)
+	case errors.As(err, &expected):
+		b.record(true)
+		err = expected.err
+	case errors.Is(ctx.Err(), context.Canceled):
+		// The caller gave up, the dependency may well have been fine
+		b.release()
+	default:
+		b.record(false)
+	}
+
+	return err
+}
+
+// allow tells whether a call may go through, probe is true for the call probing an open breaker
+func (b *Breaker) allow() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateClosed:
+		return false, nil
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.settings.OpenTimeout {
+			return false, ErrOpen
+		}
+		b.state = StateHalfOpen
+	}
+
+	if b.probing {
+		return false, ErrOpen
+	}
+	b.probing = true
+	return true, nil
+}
+
+// release lets another call probe the dependency, for a probe that never reached it
+func (b *Breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+func (b *Breaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	if success {
+		if b.state != StateClosed {
+			b.logger.Info().Str("dependency", b.name).Msg("circuit breaker closed, the dependency recovered")
+		}
+		b.state = StateClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == StateHalfOpen ||
+		(b.settings.FailureThreshold > 0 && b.failures >= b.settings.FailureThreshold && b.state == StateClosed) {
+		if b.state == StateClosed {
+			b.logger.Warn().
+				Str("dependency", b.name).
+				Int("failures", b.failures).
+				Dur("open_timeout", b.settings.OpenTimeout).
+				Msg("circuit breaker opened, calls to the dependency fail fast")
+		}
+		b.state = StateOpen
+		b.openedAt = b.now()
+	}
+}
+
+type expectedError struct {
+	err error
+}
+
+func (e *expectedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *expectedError) Unwrap() error {
+	return e.err
+}
+
+// Expected marks an error of a dependency that answered as it should, such as a not found or
+// a rejected input. It doesn't count as a failure and Do returns err itself.
+func Expected(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &expectedError{err: err}
+}
+
+func unwrapExpected(err error) error {
+	var expected *expectedError
+	if errors.As(err, &expected) {
+		return expected.err
+	}
+	return err
+}
+
+// IsUnavailable tells whether the call failed without reaching the dependency
+func IsUnavailable(err error) bool {
+	return errors.Is(err, ErrOpen) || errors.Is(err, ErrBulkheadFull)
+}
@@ -0,0 +1,76 @@
+package breaker
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/rs/zerolog"
+)
+
+// Registry holds a breaker per dependency, shared by every caller of the dependency on the
+// instance. Breakers aren't shared between instances, each finds out about an outage itself.
+type Registry struct {
+	cfg    *config.BreakersConfig
+	logger *zerolog.Logger
+
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+}
+
+func NewRegistry(cfg *config.BreakersConfig, logger *zerolog.Logger) *Registry {
+	return &Registry{
+		cfg:      cfg,
+		logger:   logger,
+		breakers: map[string]*Breaker{},
+	}
+}
+
+// Get returns the breaker of a dependency, created from its config on first use. A nil
+// registry returns a nil breaker, which lets every call through.
+func (r *Registry) Get(name string) *Breaker {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.breakers[name]
+	if !ok {
+		cfg := r.cfg.Breaker(name)
+		b = New(name, Settings{
+			FailureThreshold: cfg.FailureThreshold,
+			OpenTimeout:      cfg.OpenTimeout,
+			MaxConcurrent:    cfg.MaxConcurrent,
+			Timeout:          cfg.Timeout,
+		}, r.logger)
+		r.breakers[name] = b
+	}
+
+	return b
+}
+
+// Open lists the dependencies whose breaker is open or probing, sorted by name
+func (r *Registry) Open() []string {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	breakers := make([]*Breaker, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		breakers = append(breakers, b)
+	}
+	r.mu.Unlock()
+
+	open := []string{}
+	for _, b := range breakers {
+		if b.State() != StateClosed {
+			open = append(open, b.Name())
+		}
+	}
+	sort.Strings(open)
+
+	return open
+}
@@ -0,0 +1,68 @@
+package classify
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+)
+
+// NaiveBayes is a multinomial naive Bayes classifier over the words of the texts, with add-one
+// smoothing so unseen words don't rule a label out
+type NaiveBayes struct{}
+
+func NewNaiveBayes() *NaiveBayes {
+	return &NaiveBayes{}
+}
+
+func (m *NaiveBayes) Rank(examples []Example, text string) []Prediction {
+	if len(examples) == 0 {
+		return []Prediction{}
+	}
+
+	labelCounts := map[string]int{}
+	wordCounts := map[string]map[string]int{}
+	wordTotals := map[string]int{}
+	vocabulary := map[string]bool{}
+	for _, example := range examples {
+		labelCounts[example.Label]++
+		if wordCounts[example.Label] == nil {
+			wordCounts[example.Label] = map[string]int{}
+		}
+		for _, token := range Tokenize(example.Text) {
+			wordCounts[example.Label][token]++
+			wordTotals[example.Label]++
+			vocabulary[token] = true
+		}
+	}
+
+	tokens := Tokenize(text)
+	predictions := make([]Prediction, 0, len(labelCounts))
+	for label, count := range labelCounts {
+		// Log probabilities, the product over many words would underflow
+		score := math.Log(float64(count) / float64(len(examples)))
+		for _, token := range tokens {
+			score += math.Log(float64(wordCounts[label][token]+1) / float64(wordTotals[label]+len(vocabulary)))
+		}
+		predictions = append(predictions, Prediction{Label: label, Confidence: score})
+	}
+
+	// Softmax turns the scores into confidences summing to 1
+	best := slices.MaxFunc(predictions, func(a, b Prediction) int {
+		return cmp.Compare(a.Confidence, b.Confidence)
+	}).Confidence
+	var total float64
+	for i := range predictions {
+		predictions[i].Confidence = math.Exp(predictions[i].Confidence - best)
+		total += predictions[i].Confidence
+	}
+	for i := range predictions {
+		predictions[i].Confidence /= total
+	}
+
+	slices.SortFunc(predictions, func(a, b Prediction) int {
+		return cmp.Or(cmp.Compare(b.Confidence, a.Confidence), strings.Compare(a.Label, b.Label))
+	})
+
+	return predictions
+}
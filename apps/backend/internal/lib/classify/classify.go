@@ -0,0 +1,46 @@
+package classify
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Example is a text and the label it was given
+type Example struct {
+	Text  string
+	Label string
+}
+
+// Prediction is a label and the confidence (0-1) that it fits the text
+type Prediction struct {
+	Label      string
+	Confidence float64
+}
+
+// Model ranks labels for a text from the examples of a single user. It learns on every call,
+// histories are small enough that nothing is kept between calls.
+type Model interface {
+	// Rank returns the labels of the examples, most likely first, none when there are no examples
+	Rank(examples []Example, text string) []Prediction
+}
+
+// stopWords carry no signal about what a todo is about
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "to": true, "of": true, "for": true, "in": true,
+	"on": true, "at": true, "with": true, "my": true, "is": true, "it": true, "or": true, "by": true,
+}
+
+// Tokenize lowercases the words of the text, dropping stop words and single characters
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		if len([]rune(word)) > 1 && !stopWords[word] {
+			tokens = append(tokens, word)
+		}
+	}
+	return tokens
+}
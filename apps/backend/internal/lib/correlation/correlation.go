@@ -0,0 +1,22 @@
+// Package correlation carries the ID that ties one end-to-end action together across the
+// request that started it, the jobs it enqueued and every log line they wrote.
+package correlation
+
+import (
+	"context"
+)
+
+type contextKey struct{}
+
+// WithID returns a copy of ctx carrying the correlation ID
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// ID returns the correlation ID of ctx, "" when it carries none
+func ID(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
@@ -0,0 +1,166 @@
+// Package deliver signs the webhook events of a workspace and posts them to the endpoints it
+// registered. Endpoints are URLs of third parties, so only public addresses are connected to
+// unless the config allows private ones for local development.
+package deliver
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/lib/outbound"
+	"github.com/Sameer16536/ExecuTask/internal/lib/unfurl"
+	"github.com/google/uuid"
+)
+
+const (
+	// SignatureHeader carries t=<unix seconds>,v1=<hex HMAC-SHA256>, see Sign
+	SignatureHeader = "X-ExecuTask-Signature"
+	EventHeader     = "X-ExecuTask-Event"
+	// DeliveryHeader is the ID of the delivery, its retries keep it
+	DeliveryHeader = "X-ExecuTask-Delivery"
+	userAgent      = "ExecuTask-Webhooks/1.0 (+https://executask.app)"
+	// maxResponseBytes is how much of the answer of an endpoint is kept
+	maxResponseBytes = 1 << 10
+)
+
+var (
+	ErrForbiddenHost = errors.New("the endpoint points at a private address")
+	ErrSignature     = errors.New("the signature doesn't match the payload")
+)
+
+// Sign returns the signature header of a body sent at timestamp. The signature is the HMAC-SHA256
+// of "<unix seconds>.<body>" keyed with the secret of the endpoint, so a delivery can neither be
+// forged nor replayed later with another timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + unix + ",v1=" + signature(secret, unix, body)
+}
+
+// Verify checks a signature header against the body, rejecting those signed more than tolerance
+// away from now. Receivers written in Go can use it as is.
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var unix, signed string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			unix = value
+		case "v1":
+			signed = value
+		}
+	}
+
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil || signed == "" {
+		return ErrSignature
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: signed %s ago", ErrSignature, age)
+	}
+	if !hmac.Equal([]byte(signed), []byte(signature(secret, unix, body))) {
+		return ErrSignature
+	}
+
+	return nil
+}
+
+func signature(secret, unix string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Response is how an endpoint answered a delivery
+type Response struct {
+	StatusCode int
+	// Body is the start of the answer
+	Body     string
+	Duration time.Duration
+}
+
+// OK tells whether the endpoint accepted the delivery
+func (r *Response) OK() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+type Client struct {
+	http *http.Client
+}
+
+// New posts deliveries through a client that never follows redirects, a delivery failing is
+// retried by its job rather than by the client
+func New(outboundCfg *config.OutboundConfig, cfg *config.WebhooksConfig) *Client {
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivateAddresses {
+		// The address is checked once resolved, a host can't be pointed elsewhere after the check
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !unfurl.IsPublic(ip) {
+				return ErrForbiddenHost
+			}
+			return nil
+		}
+	}
+
+	return &Client{
+		http: outbound.New(outboundCfg, outbound.Options{
+			Timeout:     cfg.Timeout,
+			DialContext: dialer.DialContext,
+			// Never through a proxy, it would connect to addresses the dialer can't check
+			Direct:                 true,
+			MaxResponseHeaderBytes: 64 << 10,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}),
+	}
+}
+
+// Send posts a signed delivery. The error is only set when the endpoint couldn't be reached,
+// check Response.OK for whether it accepted the delivery.
+func (c *Client) Send(ctx context.Context, url, secret, event string, deliveryID uuid.UUID,
+	body []byte,
+) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(DeliveryHeader, deliveryID.String())
+	req.Header.Set(SignatureHeader, Sign(secret, time.Now(), body))
+
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	answer, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Body:       string(answer),
+		Duration:   time.Since(start),
+	}, nil
+}
@@ -2,50 +2,120 @@ package email
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"html/template"
+	"net/mail"
+	"path"
+	"time"
 
 	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/lib/breaker"
+	"github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/pkg/errors"
-	"github.com/resend/resend-go/v2"
 	"github.com/rs/zerolog"
+	"golang.org/x/text/language"
 )
 
+// sendTimeout bounds a delivery, the job is retried rather than holding its worker
+const sendTimeout = 30 * time.Second
+
 type Client struct {
-	client *resend.Client
+	mailer Mailer
+	from   string
 	logger *zerolog.Logger
+	nrApp  *newrelic.Application
+	// breaker fails deliveries at once while the mail driver is down, nil lets every one through
+	breaker *breaker.Breaker
 }
 
-func NewClient(cfg *config.Config, logger *zerolog.Logger) *Client {
-	return &Client{
-		client: resend.NewClient(cfg.Integration.ResendAPIKey),
-		logger: logger,
+// NewClient sends through the configured mail driver. Deliveries are counted per driver in
+// New Relic when nrApp isn't nil.
+func NewClient(cfg *config.Config, logger *zerolog.Logger, nrApp *newrelic.Application,
+	breaker *breaker.Breaker,
+) (*Client, error) {
+	mailer, err := NewMailer(cfg, logger)
+	if err != nil {
+		return nil, err
 	}
+
+	from := mail.Address{Name: cfg.Mail.FromName, Address: cfg.Mail.FromAddress}
+
+	return &Client{
+		mailer:  mailer,
+		from:    from.String(),
+		logger:  logger,
+		nrApp:   nrApp,
+		breaker: breaker,
+	}, nil
+}
+
+func (c *Client) SendEmail(locale language.Tag, to, subject string, templateName Template, data map[string]any) error {
+	return c.SendReplyableEmail(locale, to, "", subject, templateName, data)
 }
 
-func (c *Client) SendEmail(to, subject string, templateName Template, data map[string]any) error {
+// SendReplyableEmail sends an email whose replies go to replyTo, an empty replyTo leaves them
+// going to the sender. The template is written in the locale.
+func (c *Client) SendReplyableEmail(locale language.Tag, to, replyTo, subject string, templateName Template,
+	data map[string]any,
+) error {
 	tmplPath := fmt.Sprintf("%s/%s.html", "templates/emails", templateName)
 
-	tmpl, err := template.ParseFiles(tmplPath)
+	tmpl, err := template.New(path.Base(tmplPath)).Funcs(templateFuncs(locale)).ParseFiles(tmplPath)
 	if err != nil {
 		return errors.Wrapf(err, "failed to parse email template %s", templateName)
 	}
 
+	data["Locale"] = locale.String()
+
 	var body bytes.Buffer
 	if err := tmpl.Execute(&body, data); err != nil {
 		return errors.Wrapf(err, "failed to execute email template %s", templateName)
 	}
 
-	params := &resend.SendEmailRequest{
-		From:    fmt.Sprintf("%s <%s>", "Boilerplate", "onboarding@resend.dev"),
-		To:      []string{to},
+	return c.send(&Message{
+		From:    c.from,
+		To:      to,
+		ReplyTo: replyTo,
 		Subject: subject,
-		Html:    body.String(),
+		HTML:    body.String(),
+	}, templateName)
+}
+
+// send delivers through the mailer and records the outcome and the latency of the driver
+func (c *Client) send(message *Message, templateName Template) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	driver := c.mailer.Driver()
+	start := time.Now()
+	err := c.breaker.Do(ctx, func(ctx context.Context) error {
+		return c.mailer.Send(ctx, message)
+	})
+	duration := time.Since(start)
+
+	if breaker.IsUnavailable(err) {
+		// Deferred, the job retries once the breaker lets deliveries through again
+		return fmt.Errorf("email delivery through %s deferred: %w", driver, err)
+	}
+
+	if c.nrApp != nil {
+		outcome := "Delivered"
+		if err != nil {
+			outcome = "Failed"
+		}
+		c.nrApp.RecordCustomMetric("Custom/Email/"+driver+"/"+outcome, 1)
+		c.nrApp.RecordCustomMetric("Custom/Email/"+driver+"/Duration", float64(duration.Microseconds())/1000)
+		c.nrApp.RecordCustomEvent("EmailDelivery", map[string]interface{}{
+			"driver":      driver,
+			"template":    string(templateName),
+			"delivered":   err == nil,
+			"duration_ms": duration.Milliseconds(),
+		})
 	}
 
-	_, err = c.client.Emails.Send(params)
 	if err != nil {
-		return fmt.Errorf("failed to send email: %w", err)
+		return fmt.Errorf("failed to send email through %s: %w", driver, err)
 	}
 
 	return nil
@@ -0,0 +1,157 @@
+package email
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+)
+
+// header is a header field of a message, kept in order
+type header struct {
+	name  string
+	value string
+}
+
+// dkimSigner signs messages with rsa-sha256 and relaxed canonicalization of the headers and
+// the body, as RFC 6376 describes
+type dkimSigner struct {
+	domain   string
+	selector string
+	key      *rsa.PrivateKey
+}
+
+// newDKIMSigner returns nil without a domain, the messages are sent unsigned
+func newDKIMSigner(cfg *config.DKIMConfig) (*dkimSigner, error) {
+	if cfg.Domain == "" {
+		return nil, nil
+	}
+
+	// Environment variables often carry the key with escaped newlines
+	block, _ := pem.Decode([]byte(strings.ReplaceAll(cfg.PrivateKey, `\n`, "\n")))
+	if block == nil {
+		return nil, errors.New("failed to decode the DKIM private key, it isn't PEM encoded")
+	}
+
+	var key *rsa.PrivateKey
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the DKIM private key: %w", err)
+		}
+		key = parsed
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the DKIM private key: %w", err)
+		}
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("the DKIM private key isn't an RSA key")
+		}
+		key = rsaKey
+	default:
+		return nil, fmt.Errorf("unsupported DKIM private key type %q", block.Type)
+	}
+
+	return &dkimSigner{
+		domain:   cfg.Domain,
+		selector: cfg.Selector,
+		key:      key,
+	}, nil
+}
+
+// sign returns the DKIM-Signature header covering every header given and the body, which
+// must use CRLF line endings
+func (s *dkimSigner) sign(headers []header, body string, now time.Time) (header, error) {
+	bodyHash := sha256.Sum256([]byte(relaxedBody(body)))
+
+	names := make([]string, 0, len(headers))
+	for _, h := range headers {
+		names = append(names, strings.ToLower(h.name))
+	}
+
+	signature := header{
+		name: "DKIM-Signature",
+		value: "v=1; a=rsa-sha256; c=relaxed/relaxed; d=" + s.domain + "; s=" + s.selector +
+			"; t=" + strconv.FormatInt(now.Unix(), 10) + "; h=" + strings.Join(names, ":") +
+			"; bh=" + base64.StdEncoding.EncodeToString(bodyHash[:]) + "; b=",
+	}
+
+	signed, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, headerHash(headers, signature))
+	if err != nil {
+		return header{}, fmt.Errorf("failed to sign message with DKIM: %w", err)
+	}
+	signature.value += base64.StdEncoding.EncodeToString(signed)
+
+	return signature, nil
+}
+
+// headerHash hashes the signed headers in order, then the signature header with an empty b=
+// and without its line ending
+func headerHash(headers []header, signature header) []byte {
+	hash := sha256.New()
+	for _, h := range headers {
+		hash.Write([]byte(relaxedHeader(h) + "\r\n"))
+	}
+	hash.Write([]byte(relaxedHeader(signature)))
+
+	return hash.Sum(nil)
+}
+
+// relaxedHeader lowercases the name, unfolds the value and collapses its whitespace
+func relaxedHeader(h header) string {
+	value := strings.NewReplacer("\r\n", "", "\n", "").Replace(h.value)
+	return strings.ToLower(strings.Trim(h.name, " \t")) + ":" + strings.Trim(collapseWhitespace(value), " ")
+}
+
+// relaxedBody collapses the whitespace of every line, drops the trailing whitespace and the
+// empty lines at the end
+func relaxedBody(body string) string {
+	lines := strings.Split(body, "\r\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(collapseWhitespace(line), " ")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+// collapseWhitespace reduces every run of spaces and tabs to a single space, other whitespace
+// is kept as RFC 6376 only canonicalizes those two
+func collapseWhitespace(s string) string {
+	var collapsed strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' {
+			space = true
+			continue
+		}
+		if space {
+			collapsed.WriteByte(' ')
+			space = false
+		}
+		collapsed.WriteRune(r)
+	}
+	if space {
+		collapsed.WriteByte(' ')
+	}
+
+	return collapsed.String()
+}
@@ -0,0 +1,311 @@
+package email
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// The ed25519 example of RFC 8463 appendix A, signed by another implementation with a
+// published key
+const (
+	rfc8463PublicKey = "11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo="
+	rfc8463Signature = "v=1; a=ed25519-sha256; c=relaxed/relaxed;\r\n" +
+		" d=football.example.com; i=@football.example.com;\r\n" +
+		" q=dns/txt; s=brisbane; t=1528637909; h=from : to :\r\n" +
+		" subject : date : message-id : from : subject : date;\r\n" +
+		" bh=2jUSOH9NhtVGCQWNr9BrIAPreKQjO6Sn7XIkfJVOzv8=;\r\n" +
+		" b=/gCrinpcQOoIfuHNQIbq4pgh9kyIK3AQUdt9OdqQehSwhEIug4D11Bus\r\n" +
+		" Fa3bT3FY5OsU7ZbnKELq+eXdp1Q1Dw=="
+	rfc8463Body = "Hi.\r\n\r\nWe lost the game.  Are you hungry yet?\r\n\r\nJoe.\r\n"
+)
+
+var rfc8463Headers = []header{
+	{name: "From", value: "Joe SixPack <joe@football.example.com>"},
+	{name: "To", value: "Suzie Q <suzie@shopping.example.net>"},
+	{name: "Subject", value: "Is dinner ready?"},
+	{name: "Date", value: "Fri, 11 Jul 2003 21:00:37 -0700 (PDT)"},
+	{name: "Message-ID", value: "<20030712040037.46341.5F8J@football.example.com>"},
+}
+
+func rfc8463Message() []byte {
+	var message bytes.Buffer
+	message.WriteString("DKIM-Signature: " + rfc8463Signature + "\r\n")
+	for _, h := range rfc8463Headers {
+		message.WriteString(h.name + ": " + h.value + "\r\n")
+	}
+	message.WriteString("\r\n" + rfc8463Body)
+
+	return message.Bytes()
+}
+
+func rfc8463Key(t *testing.T) ed25519.PublicKey {
+	t.Helper()
+
+	key, err := base64.StdEncoding.DecodeString(rfc8463PublicKey)
+	require.NoError(t, err)
+
+	return ed25519.PublicKey(key)
+}
+
+var (
+	headerFieldPattern = regexp.MustCompile(`[!-9;-~]+[ \t]*:[^\r\n]*\r\n(?:[ \t][^\r\n]*\r\n)*`)
+	signatureBPattern  = regexp.MustCompile(`((?:^|;)[ \t\r\n]*b[ \t\r\n]*=)[^;]*`)
+	foldingPattern     = regexp.MustCompile(`\r\n([ \t])`)
+	whitespacePattern  = regexp.MustCompile(`[ \t]+`)
+	lineEndPattern     = regexp.MustCompile(`[ \t]+(\r\n|$)`)
+	emptyLinesPattern  = regexp.MustCompile(`(\r\n)+$`)
+	fwsPattern         = regexp.MustCompile(`[ \t\r\n]+`)
+)
+
+// verifyDKIM checks the first DKIM-Signature of a raw message the way a receiving server
+// does, it shares no code with the signer
+func verifyDKIM(raw []byte, publicKey crypto.PublicKey) error {
+	headerBlock, body, ok := bytes.Cut(raw, []byte("\r\n\r\n"))
+	if !ok {
+		return errors.New("message has no body separator")
+	}
+	fields := headerFieldPattern.FindAllString(string(headerBlock)+"\r\n", -1)
+
+	signatureField := ""
+	for _, field := range fields {
+		if strings.EqualFold(strings.TrimRight(field[:strings.Index(field, ":")], " \t"), "DKIM-Signature") {
+			signatureField = field
+			break
+		}
+	}
+	if signatureField == "" {
+		return errors.New("message isn't signed")
+	}
+
+	tags := map[string]string{}
+	for _, tag := range strings.Split(signatureField[strings.Index(signatureField, ":")+1:], ";") {
+		name, value, _ := strings.Cut(tag, "=")
+		tags[strings.TrimSpace(name)] = fwsPattern.ReplaceAllString(value, "")
+	}
+
+	canonicalBody := lineEndPattern.ReplaceAllString(string(body), "$1")
+	canonicalBody = whitespacePattern.ReplaceAllString(canonicalBody, " ")
+	canonicalBody = emptyLinesPattern.ReplaceAllString(canonicalBody, "")
+	if canonicalBody != "" {
+		canonicalBody += "\r\n"
+	}
+	bodyHash := sha256.Sum256([]byte(canonicalBody))
+	if base64.StdEncoding.EncodeToString(bodyHash[:]) != tags["bh"] {
+		return errors.New("body hash doesn't match")
+	}
+
+	canonical := func(field string) string {
+		name, value, _ := strings.Cut(strings.TrimSuffix(field, "\r\n"), ":")
+		value = whitespacePattern.ReplaceAllString(foldingPattern.ReplaceAllString(value, "$1"), " ")
+		return strings.ToLower(strings.TrimRight(name, " \t")) + ":" + strings.Trim(value, " ")
+	}
+
+	// Each name in h= takes the next instance of the header from the bottom, names without
+	// one left are skipped
+	hash := sha256.New()
+	used := map[int]bool{}
+	for _, name := range strings.Split(tags["h"], ":") {
+		for i := len(fields) - 1; i >= 0; i-- {
+			fieldName := strings.TrimRight(fields[i][:strings.Index(fields[i], ":")], " \t")
+			if !used[i] && strings.EqualFold(fieldName, name) {
+				used[i] = true
+				hash.Write([]byte(canonical(fields[i]) + "\r\n"))
+				break
+			}
+		}
+	}
+	hash.Write([]byte(canonical(signatureBPattern.ReplaceAllString(signatureField, "$1"))))
+	sum := hash.Sum(nil)
+
+	signature, err := base64.StdEncoding.DecodeString(tags["b"])
+	if err != nil {
+		return fmt.Errorf("signature isn't base64: %w", err)
+	}
+	switch tags["a"] {
+	case "rsa-sha256":
+		return rsa.VerifyPKCS1v15(publicKey.(*rsa.PublicKey), crypto.SHA256, sum, signature)
+	case "ed25519-sha256":
+		if !ed25519.Verify(publicKey.(ed25519.PublicKey), sum, signature) {
+			return errors.New("ed25519 signature doesn't match")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported algorithm %q", tags["a"])
+	}
+}
+
+func TestVerifierAcceptsRFC8463Example(t *testing.T) {
+	key := rfc8463Key(t)
+	message := rfc8463Message()
+	require.NoError(t, verifyDKIM(message, key))
+
+	tampered := bytes.Replace(message, []byte("Is dinner ready?"), []byte("Is lunch ready?"), 1)
+	require.Error(t, verifyDKIM(tampered, key))
+
+	tampered = bytes.Replace(message, []byte("We lost"), []byte("We won"), 1)
+	require.Error(t, verifyDKIM(tampered, key))
+}
+
+func TestCanonicalizationMatchesRFC8463Example(t *testing.T) {
+	bodyHash := sha256.Sum256([]byte(relaxedBody(rfc8463Body)))
+	require.Equal(t, "2jUSOH9NhtVGCQWNr9BrIAPreKQjO6Sn7XIkfJVOzv8=", base64.StdEncoding.EncodeToString(bodyHash[:]))
+
+	unsigned, signature, _ := strings.Cut(rfc8463Signature, "b=/")
+	signature = strings.ReplaceAll(strings.ReplaceAll("/"+signature, "\r\n", ""), " ", "")
+	signed, err := base64.StdEncoding.DecodeString(signature)
+	require.NoError(t, err)
+
+	hash := headerHash(rfc8463Headers, header{name: "DKIM-Signature", value: unsigned + "b="})
+	require.True(t, ed25519.Verify(rfc8463Key(t), hash, signed))
+}
+
+func TestRelaxedHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header header
+		want   string
+	}{
+		// RFC 6376 section 3.4.5
+		{name: "plain", header: header{name: "A", value: " X"}, want: "a:X"},
+		{name: "folded", header: header{name: "B ", value: " Y\t\r\n\tZ  "}, want: "b:Y Z"},
+		{name: "trailing whitespace", header: header{name: "Subject", value: "Hello \t "}, want: "subject:Hello"},
+		{name: "inner whitespace", header: header{name: "To", value: "Joe \t  <joe@example.com>"}, want: "to:Joe <joe@example.com>"},
+		{name: "folded twice", header: header{name: "H", value: "a\r\n b\r\n\tc"}, want: "h:a b c"},
+		{name: "empty", header: header{name: "X-Empty", value: ""}, want: "x-empty:"},
+		{name: "non-breaking space", header: header{name: "X", value: "a\u00a0 b"}, want: "x:a\u00a0 b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, relaxedHeader(tt.header))
+		})
+	}
+}
+
+func TestRelaxedBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		// RFC 6376 section 3.4.5
+		{name: "example", body: " C \r\nD \t E\r\n\r\n\r\n", want: " C\r\nD E\r\n"},
+		{name: "trailing whitespace", body: "Hello \t\r\nWorld\t\r\n", want: "Hello\r\nWorld\r\n"},
+		{name: "whitespace only line", body: "a\r\n \t \r\nb\r\n", want: "a\r\n\r\nb\r\n"},
+		{name: "empty", body: "", want: ""},
+		{name: "empty lines", body: "\r\n\r\n", want: ""},
+		{name: "whitespace only", body: " \r\n\t\r\n", want: ""},
+		{name: "missing line ending", body: "a  b ", want: "a b\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, relaxedBody(tt.body))
+		})
+	}
+
+	// The body hash of an empty body, as RFC 6376 section 3.4.4 defines it
+	empty := sha256.Sum256([]byte(relaxedBody("")))
+	require.Equal(t, "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=", base64.StdEncoding.EncodeToString(empty[:]))
+}
+
+func newTestDKIMSigner(t *testing.T) (*dkimSigner, *rsa.PublicKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	// Configured the way environment variables carry it, with escaped newlines
+	encoded := strings.ReplaceAll(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), "\n", `\n`)
+	signer, err := newDKIMSigner(&config.DKIMConfig{Domain: "executask.com", Selector: "mail", PrivateKey: encoded})
+	require.NoError(t, err)
+
+	return signer, &key.PublicKey
+}
+
+func TestSignedMessagesVerify(t *testing.T) {
+	signer, publicKey := newTestDKIMSigner(t)
+	mailer := &SMTPMailer{
+		dkim: signer,
+		now:  func() time.Time { return time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC) },
+	}
+	from := &mail.Address{Name: "ExecuTask", Address: "noreply@executask.com"}
+
+	messages := map[string]*Message{
+		"plain": {
+			From:    from.String(),
+			To:      "joe@example.com",
+			Subject: "Your todo is due",
+			HTML:    "<p>Ship the release</p>",
+		},
+		"trailing whitespace": {
+			From:    from.String(),
+			To:      "joe@example.com",
+			Subject: "Your todo   is due ",
+			HTML:    "<p>Ship the release</p>   \r\n\t\r\n<p>Today</p>  \n\n\n",
+		},
+		"long lines": {
+			From:    from.String(),
+			To:      "Joe Sixpack <joe@example.com>",
+			ReplyTo: "support@executask.com",
+			Subject: "Rappel : votre tâche arrive à échéance",
+			HTML:    "<p>" + strings.Repeat("Ship the release ", 40) + "</p>",
+		},
+		"empty body": {
+			From:    from.String(),
+			To:      "joe@example.com",
+			Subject: "Nothing to say",
+		},
+	}
+
+	for name, message := range messages {
+		t.Run(name, func(t *testing.T) {
+			raw, err := mailer.buildMessage(message, from)
+			require.NoError(t, err)
+			require.True(t, bytes.HasPrefix(raw, []byte("DKIM-Signature: v=1; a=rsa-sha256; c=relaxed/relaxed; d=executask.com; s=mail;")))
+			require.NoError(t, verifyDKIM(raw, publicKey))
+
+			tampered := bytes.Replace(raw, []byte("\r\nTo: "), []byte("\r\nTo: eve@example.com, "), 1)
+			require.Error(t, verifyDKIM(tampered, publicKey))
+
+			tampered = append(bytes.Clone(raw), []byte("<p>Unsubscribe</p>\r\n")...)
+			require.Error(t, verifyDKIM(tampered, publicKey))
+		})
+	}
+}
+
+func TestDKIMSignerConfig(t *testing.T) {
+	signer, err := newDKIMSigner(&config.DKIMConfig{})
+	require.NoError(t, err)
+	require.Nil(t, signer)
+
+	_, err = newDKIMSigner(&config.DKIMConfig{Domain: "executask.com", Selector: "mail", PrivateKey: "not a key"})
+	require.Error(t, err)
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	encoded := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	_, err = newDKIMSigner(&config.DKIMConfig{Domain: "executask.com", Selector: "mail", PrivateKey: encoded})
+	require.ErrorContains(t, err, "isn't an RSA key")
+}
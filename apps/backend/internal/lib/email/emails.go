@@ -1,64 +1,75 @@
 package email
 
 import (
-	"fmt"
+	"strings"
 	"time"
 
+	"github.com/Sameer16536/ExecuTask/internal/lib/i18n"
+	"github.com/Sameer16536/ExecuTask/internal/lib/utils"
+	"github.com/Sameer16536/ExecuTask/internal/model/rule"
 	"github.com/Sameer16536/ExecuTask/internal/model/todo"
 	"github.com/google/uuid"
+	"golang.org/x/text/language"
 )
 
-func (c *Client) SendWelcomeEmail(to, firstName string) error {
+func (c *Client) SendWelcomeEmail(locale language.Tag, to, firstName string) error {
 	data := map[string]any{
 		"UserFirstName": firstName,
 	}
 
 	return c.SendEmail(
+		locale,
 		to,
-		"Welcome to Boilerplate!",
+		i18n.T(locale, "Welcome to Boilerplate!"),
 		TemplateWelcome,
 		data,
 	)
 }
 
-func (c *Client) SendDueDateReminderEmail(to, todoTitle string, todoID uuid.UUID, dueDate time.Time) error {
+func (c *Client) SendDueDateReminderEmail(locale language.Tag, to, replyTo, todoTitle string, todoID uuid.UUID, dueDate time.Time) error {
 	data := map[string]interface{}{
 		"TodoTitle":    todoTitle,
 		"TodoID":       todoID.String(),
-		"DueDate":      dueDate.Format("Monday, January 2, 2006 at 3:04 PM"),
+		"DueDate":      i18n.FormatTime(locale, dueDate, i18n.LayoutDateTime),
 		"DaysUntilDue": int(dueDate.Sub(time.Now()).Hours() / 24),
+		"ReplyHint":    replyHint(locale, replyTo),
 	}
 
-	return c.SendEmail(
+	return c.SendReplyableEmail(
+		locale,
 		to,
-		fmt.Sprintf("Reminder: '%s' is due soon", todoTitle),
+		replyTo,
+		i18n.T(locale, "Reminder: '%s' is due soon", todoTitle),
 		TemplateDueDateReminder,
 		data,
 	)
 }
 
-func (c *Client) SendOverdueNotificationEmail(to, todoTitle string, todoID uuid.UUID, dueDate time.Time) error {
+func (c *Client) SendOverdueNotificationEmail(locale language.Tag, to, replyTo, todoTitle string, todoID uuid.UUID, dueDate time.Time) error {
 	data := map[string]interface{}{
 		"TodoTitle":   todoTitle,
 		"TodoID":      todoID.String(),
-		"DueDate":     dueDate.Format("Monday, January 2, 2006 at 3:04 PM"),
+		"DueDate":     i18n.FormatTime(locale, dueDate, i18n.LayoutDateTime),
 		"DaysOverdue": int(time.Now().Sub(dueDate).Hours() / 24),
+		"ReplyHint":   replyHint(locale, replyTo),
 	}
 
-	return c.SendEmail(
+	return c.SendReplyableEmail(
+		locale,
 		to,
-		fmt.Sprintf("Overdue: '%s' needs your attention", todoTitle),
+		replyTo,
+		i18n.T(locale, "Overdue: '%s' needs your attention", todoTitle),
 		TemplateOverdueNotification,
 		data,
 	)
 }
 
-func (c *Client) SendWeeklyReportEmail(to string, weekStart, weekEnd time.Time,
+func (c *Client) SendWeeklyReportEmail(locale language.Tag, to string, weekStart, weekEnd time.Time,
 	completedCount, activeCount, overdueCount int, completedTodos, overdueTodos []todo.PopulatedTodo,
 ) error {
 	data := map[string]interface{}{
-		"WeekStart":      weekStart.Format("January 2, 2006"),
-		"WeekEnd":        weekEnd.Format("January 2, 2006"),
+		"WeekStart":      i18n.FormatTime(locale, weekStart, i18n.LayoutDate),
+		"WeekEnd":        i18n.FormatTime(locale, weekEnd, i18n.LayoutDate),
 		"CompletedCount": completedCount,
 		"ActiveCount":    activeCount,
 		"OverdueCount":   overdueCount,
@@ -69,10 +80,205 @@ func (c *Client) SendWeeklyReportEmail(to string, weekStart, weekEnd time.Time,
 	}
 
 	return c.SendEmail(
+		locale,
 		to,
-		fmt.Sprintf("Your Weekly Productivity Report (%s - %s)",
-			weekStart.Format("Jan 2"), weekEnd.Format("Jan 2")),
+		i18n.T(locale, "Your Weekly Productivity Report (%s - %s)",
+			i18n.FormatTime(locale, weekStart, i18n.LayoutShortDate),
+			i18n.FormatTime(locale, weekEnd, i18n.LayoutShortDate)),
 		TemplateWeeklyReport,
 		data,
 	)
 }
+
+func (c *Client) SendAccountExportReadyEmail(locale language.Tag, to, downloadURL string, sizeBytes int64,
+	linkExpiresAt, expiresAt time.Time,
+) error {
+	data := map[string]interface{}{
+		"DownloadURL":   downloadURL,
+		"Size":          utils.FormatSize(sizeBytes),
+		"LinkExpiresAt": i18n.FormatTime(locale, linkExpiresAt, i18n.LayoutDateTime),
+		"ExpiresAt":     i18n.FormatTime(locale, expiresAt, i18n.LayoutLongDate),
+	}
+
+	return c.SendEmail(
+		locale,
+		to,
+		i18n.T(locale, "Your data export is ready"),
+		TemplateAccountExportReady,
+		data,
+	)
+}
+
+func (c *Client) SendTodoListExportReadyEmail(locale language.Tag, to, downloadURL string, sizeBytes int64,
+	linkExpiresAt, expiresAt time.Time,
+) error {
+	data := map[string]interface{}{
+		"DownloadURL":   downloadURL,
+		"Size":          utils.FormatSize(sizeBytes),
+		"LinkExpiresAt": i18n.FormatTime(locale, linkExpiresAt, i18n.LayoutDateTime),
+		"ExpiresAt":     i18n.FormatTime(locale, expiresAt, i18n.LayoutLongDate),
+	}
+
+	return c.SendEmail(
+		locale,
+		to,
+		i18n.T(locale, "Your todo list PDF is ready"),
+		TemplateTodoListExportReady,
+		data,
+	)
+}
+
+func (c *Client) SendRuleNotificationEmail(locale language.Tag, to, replyTo, ruleName, todoTitle string, todoID uuid.UUID, message *string) error {
+	data := map[string]interface{}{
+		"RuleName":  ruleName,
+		"TodoTitle": todoTitle,
+		"TodoID":    todoID.String(),
+		"Message":   i18n.T(locale, "Take a look at the todo to see what changed."),
+		"ReplyHint": replyHint(locale, replyTo),
+	}
+	if message != nil {
+		data["Message"] = *message
+	}
+
+	return c.SendReplyableEmail(
+		locale,
+		to,
+		replyTo,
+		i18n.T(locale, "Rule '%s' ran on '%s'", ruleName, todoTitle),
+		TemplateRuleNotification,
+		data,
+	)
+}
+
+// SendRuleDigestEmail tells in one email what the rules that ran off a bulk change had to say
+func (c *Client) SendRuleDigestEmail(locale language.Tag, to string, notices []rule.Notice) error {
+	items := make([]map[string]string, 0, len(notices))
+	for _, notice := range notices {
+		message := i18n.T(locale, "Take a look at the todo to see what changed.")
+		if notice.Message != nil {
+			message = *notice.Message
+		}
+		items = append(items, map[string]string{
+			"RuleName":  notice.RuleName,
+			"TodoTitle": notice.TodoTitle,
+			"TodoID":    notice.TodoID.String(),
+			"Message":   message,
+		})
+	}
+
+	data := map[string]interface{}{
+		"Count":   len(notices),
+		"Notices": items,
+	}
+
+	subject := i18n.T(locale, "Your rules sent %d notifications", len(notices))
+	if len(notices) == 1 {
+		subject = i18n.T(locale, "Rule '%s' ran on '%s'", notices[0].RuleName, notices[0].TodoTitle)
+	}
+
+	return c.SendEmail(
+		locale,
+		to,
+		subject,
+		TemplateRuleDigest,
+		data,
+	)
+}
+
+func (c *Client) SendSLABreachEmail(locale language.Tag, to, replyTo, policyName string, maxAgeHours int, todoTitle string,
+	todoID uuid.UUID, openedAt time.Time,
+) error {
+	data := map[string]interface{}{
+		"PolicyName": policyName,
+		"MaxAge":     formatAge(locale, maxAgeHours),
+		"TodoTitle":  todoTitle,
+		"TodoID":     todoID.String(),
+		"OpenedAt":   i18n.FormatTime(locale, openedAt, i18n.LayoutDateTime),
+		"ReplyHint":  replyHint(locale, replyTo),
+	}
+
+	return c.SendReplyableEmail(
+		locale,
+		to,
+		replyTo,
+		i18n.T(locale, "'%s' has been open longer than '%s' allows", todoTitle, policyName),
+		TemplateSLABreach,
+		data,
+	)
+}
+
+// formatAge spells out an age in hours, in days when it is whole days
+func formatAge(locale language.Tag, hours int) string {
+	if hours%24 == 0 {
+		return i18n.N(locale, "%d days", hours/24)
+	}
+	return i18n.N(locale, "%d hours", hours)
+}
+
+func (c *Client) SendBadgeAwardedEmail(locale language.Tag, to, badgeName, badgeDescription string) error {
+	// Badges are named in code, the catalogs translate them like any other message
+	badgeName = i18n.T(locale, badgeName)
+	badgeDescription = i18n.T(locale, badgeDescription)
+
+	data := map[string]interface{}{
+		"BadgeName":        badgeName,
+		"BadgeDescription": badgeDescription,
+	}
+
+	return c.SendEmail(
+		locale,
+		to,
+		i18n.T(locale, "You earned the '%s' badge", badgeName),
+		TemplateBadgeAwarded,
+		data,
+	)
+}
+
+func (c *Client) SendAttachmentBlockedEmail(locale language.Tag, to, fileName, todoTitle string, todoID uuid.UUID, signature string) error {
+	data := map[string]interface{}{
+		"FileName":  fileName,
+		"TodoTitle": todoTitle,
+		"TodoID":    todoID.String(),
+		"Signature": signature,
+	}
+
+	return c.SendEmail(
+		locale,
+		to,
+		i18n.T(locale, "'%s' was blocked by the virus scan", fileName),
+		TemplateAttachmentBlocked,
+		data,
+	)
+}
+
+// SendScheduledReportEmail sends a run of a scheduled report to one of its recipients, in the
+// locale of the user who scheduled it
+func (c *Client) SendScheduledReportEmail(locale language.Tag, to, ownerEmail, reportName, format,
+	downloadURL string, sizeBytes int64, linkExpiresAt, expiresAt time.Time,
+) error {
+	data := map[string]interface{}{
+		"ReportName":    reportName,
+		"Format":        strings.ToUpper(format),
+		"OwnerEmail":    ownerEmail,
+		"DownloadURL":   downloadURL,
+		"Size":          utils.FormatSize(sizeBytes),
+		"LinkExpiresAt": i18n.FormatTime(locale, linkExpiresAt, i18n.LayoutDateTime),
+		"ExpiresAt":     i18n.FormatTime(locale, expiresAt, i18n.LayoutLongDate),
+	}
+
+	return c.SendEmail(
+		locale,
+		to,
+		i18n.T(locale, "Scheduled report: '%s'", reportName),
+		TemplateScheduledReport,
+		data,
+	)
+}
+
+// replyHint tells the recipient they can answer the email, when its replies are taken in
+func replyHint(locale language.Tag, replyTo string) string {
+	if replyTo == "" {
+		return ""
+	}
+	return i18n.T(locale, "Reply to this email to comment on the todo.")
+}
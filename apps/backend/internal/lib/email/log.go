@@ -0,0 +1,32 @@
+package email
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// LogMailer logs emails instead of sending them, so development needs no provider
+type LogMailer struct {
+	logger *zerolog.Logger
+}
+
+func NewLogMailer(logger *zerolog.Logger) *LogMailer {
+	return &LogMailer{logger: logger}
+}
+
+func (m *LogMailer) Driver() string {
+	return "log"
+}
+
+func (m *LogMailer) Send(ctx context.Context, message *Message) error {
+	m.logger.Info().
+		Str("from", message.From).
+		Str("to", message.To).
+		Str("reply_to", message.ReplyTo).
+		Str("subject", message.Subject).
+		Str("html", message.HTML).
+		Msg("Email not sent, the log mail driver only logs emails")
+
+	return nil
+}
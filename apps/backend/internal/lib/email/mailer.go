@@ -0,0 +1,42 @@
+package email
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/rs/zerolog"
+)
+
+// Message is a rendered email, ready for any driver to send
+type Message struct {
+	// From is the sender as an address header, e.g. "ExecuTask <noreply@executask.com>"
+	From    string
+	To      string
+	ReplyTo string
+	Subject string
+	HTML    string
+}
+
+// Mailer sends emails through one provider, the mail driver of the config selects it
+type Mailer interface {
+	// Driver names the mailer in logs and metrics
+	Driver() string
+	Send(ctx context.Context, message *Message) error
+}
+
+// NewMailer builds the mailer of the configured driver
+func NewMailer(cfg *config.Config, logger *zerolog.Logger) (Mailer, error) {
+	switch cfg.Mail.Driver {
+	case config.MailDriverSES:
+		return NewSESMailer(cfg.AWS, cfg.Outbound, cfg.Mail.SESConfigurationSet), nil
+	case config.MailDriverSMTP:
+		return NewSMTPMailer(&cfg.Mail.SMTP, &cfg.Mail.DKIM)
+	case config.MailDriverLog:
+		return NewLogMailer(logger), nil
+	case config.MailDriverResend:
+		return NewResendMailer(cfg.Integration.ResendAPIKey), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.Mail.Driver)
+	}
+}
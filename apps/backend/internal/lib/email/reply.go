@@ -0,0 +1,66 @@
+package email
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+// replyAddressPrefix starts the local part of reply addresses, the token follows the plus
+const replyAddressPrefix = "reply+"
+
+// quoteHeader matches the line mail clients put above the quoted message, e.g.
+// "On Mon, Jan 15, 2026 at 5:00 PM Executask <reply+...> wrote:"
+var quoteHeader = regexp.MustCompile(`(?i)^on\s.+wrote:$`)
+
+// ReplyAddress is the address the replies to an email carrying the token go to
+func ReplyAddress(token, domain string) string {
+	return replyAddressPrefix + token + "@" + domain
+}
+
+// ParseReplyToken finds the token among the recipients of an inbound email, only addresses at
+// the reply domain count
+func ParseReplyToken(recipients []string, domain string) (string, bool) {
+	for _, recipient := range recipients {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil {
+			continue
+		}
+
+		local, host, ok := strings.Cut(address.Address, "@")
+		if !ok || !strings.EqualFold(host, domain) {
+			continue
+		}
+
+		if token, ok := strings.CutPrefix(strings.ToLower(local), replyAddressPrefix); ok && token != "" {
+			return token, true
+		}
+	}
+
+	return "", false
+}
+
+// ExtractReply keeps what the sender wrote above the quoted message and their signature
+func ExtractReply(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	kept := make([]string, 0, len(lines))
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if quoteHeader.MatchString(trimmed) ||
+			// Gmail wraps long quote headers, the "wrote:" ends up on the next line
+			(strings.HasPrefix(strings.ToLower(trimmed), "on ") && i+1 < len(lines) &&
+				strings.HasSuffix(strings.TrimSpace(lines[i+1]), "wrote:")) ||
+			strings.HasPrefix(trimmed, "-----Original Message-----") ||
+			strings.HasPrefix(trimmed, "________________") ||
+			line == "-- " || trimmed == "--" {
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
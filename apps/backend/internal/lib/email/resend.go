@@ -0,0 +1,30 @@
+package email
+
+import (
+	"context"
+
+	"github.com/resend/resend-go/v2"
+)
+
+type ResendMailer struct {
+	client *resend.Client
+}
+
+func NewResendMailer(apiKey string) *ResendMailer {
+	return &ResendMailer{client: resend.NewClient(apiKey)}
+}
+
+func (m *ResendMailer) Driver() string {
+	return "resend"
+}
+
+func (m *ResendMailer) Send(ctx context.Context, message *Message) error {
+	_, err := m.client.Emails.SendWithContext(ctx, &resend.SendEmailRequest{
+		From:    message.From,
+		To:      []string{message.To},
+		Subject: message.Subject,
+		Html:    message.HTML,
+		ReplyTo: message.ReplyTo,
+	})
+	return err
+}
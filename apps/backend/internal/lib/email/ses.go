@@ -0,0 +1,121 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/lib/outbound"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// SESMailer sends through the SES v2 API, signed like the SDK clients. SES signs the emails
+// with the DKIM keys of the verified domain.
+type SESMailer struct {
+	client           *http.Client
+	endpoint         string
+	region           string
+	configurationSet string
+	credentials      aws.CredentialsProvider
+	signer           *v4.Signer
+}
+
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+type sesSendEmailInput struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	ReplyToAddresses []string `json:"ReplyToAddresses,omitempty"`
+	Content          struct {
+		Simple struct {
+			Subject sesContent `json:"Subject"`
+			Body    struct {
+				Html sesContent `json:"Html"`
+			} `json:"Body"`
+		} `json:"Simple"`
+	} `json:"Content"`
+	ConfigurationSetName string `json:"ConfigurationSetName,omitempty"`
+}
+
+func NewSESMailer(awsConfig config.AWSConfig, outboundCfg *config.OutboundConfig, configurationSet string) *SESMailer {
+	return &SESMailer{
+		client:           outbound.New(outboundCfg, outbound.Options{Timeout: 10 * time.Second}),
+		endpoint:         fmt.Sprintf("https://email.%s.amazonaws.com/v2/email/outbound-emails", awsConfig.Region),
+		region:           awsConfig.Region,
+		configurationSet: configurationSet,
+		credentials: credentials.NewStaticCredentialsProvider(
+			awsConfig.AccessKeyID,
+			awsConfig.SecretAccessKey,
+			"",
+		),
+		signer: v4.NewSigner(),
+	}
+}
+
+func (m *SESMailer) Driver() string {
+	return "ses"
+}
+
+func (m *SESMailer) Send(ctx context.Context, message *Message) error {
+	input := sesSendEmailInput{
+		FromEmailAddress:     message.From,
+		ConfigurationSetName: m.configurationSet,
+	}
+	input.Destination.ToAddresses = []string{message.To}
+	if message.ReplyTo != "" {
+		input.ReplyToAddresses = []string{message.ReplyTo}
+	}
+	input.Content.Simple.Subject = sesContent{Data: message.Subject, Charset: "UTF-8"}
+	input.Content.Simple.Body.Html = sesContent{Data: message.HTML, Charset: "UTF-8"}
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to encode SES request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build SES request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	creds, err := m.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+
+	payloadHash := sha256.Sum256(body)
+	err = m.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(payloadHash[:]), "ses", m.region, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to sign SES request: %w", err)
+	}
+
+	res, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call SES: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("SES returned %d: %s: %s", res.StatusCode, res.Header.Get("X-Amzn-ErrorType"),
+			strings.TrimSpace(string(message)))
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+)
+
+// smtpImplicitTLSPort is the submission port whose connections start with TLS, the others
+// upgrade with STARTTLS when the server offers it
+const smtpImplicitTLSPort = 465
+
+// SMTPMailer sends through any SMTP server, signing the emails with DKIM when configured
+type SMTPMailer struct {
+	host     string
+	port     int
+	username string
+	password string
+	dkim     *dkimSigner
+	now      func() time.Time
+}
+
+func NewSMTPMailer(cfg *config.SMTPConfig, dkimConfig *config.DKIMConfig) (*SMTPMailer, error) {
+	dkim, err := newDKIMSigner(dkimConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SMTPMailer{
+		host:     cfg.Host,
+		port:     cfg.Port,
+		username: cfg.Username,
+		password: cfg.Password,
+		dkim:     dkim,
+		now:      time.Now,
+	}, nil
+}
+
+func (m *SMTPMailer) Driver() string {
+	return "smtp"
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, message *Message) error {
+	from, err := mail.ParseAddress(message.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", message.From, err)
+	}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient %q: %w", message.To, err)
+	}
+
+	data, err := m.buildMessage(message, from)
+	if err != nil {
+		return err
+	}
+
+	client, err := m.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if m.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return fmt.Errorf("failed to authenticate with SMTP server: %w", err)
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("SMTP server rejected sender: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("SMTP server rejected recipient: %w", err)
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to start SMTP data: %w", err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to write SMTP data: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("SMTP server rejected message: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// dial connects and upgrades to TLS, the whole exchange has to finish before ctx is done
+func (m *SMTPMailer) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(m.host, strconv.Itoa(m.port))
+	tlsConfig := &tls.Config{ServerName: m.host}
+
+	var conn net.Conn
+	var err error
+	if m.port == smtpImplicitTLSPort {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SMTP server %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to greet SMTP server %s: %w", addr, err)
+	}
+
+	if ok, _ := client.Extension("STARTTLS"); ok && m.port != smtpImplicitTLSPort {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to start TLS with SMTP server %s: %w", addr, err)
+		}
+	}
+
+	return client, nil
+}
+
+// buildMessage writes the headers and the quoted-printable HTML body with CRLF line endings
+func (m *SMTPMailer) buildMessage(message *Message, from *mail.Address) ([]byte, error) {
+	var body bytes.Buffer
+	encoder := quotedprintable.NewWriter(&body)
+	if _, err := encoder.Write([]byte(strings.ReplaceAll(message.HTML, "\r\n", "\n"))); err != nil {
+		return nil, fmt.Errorf("failed to encode email body: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode email body: %w", err)
+	}
+	encodedBody := strings.ReplaceAll(strings.ReplaceAll(body.String(), "\r\n", "\n"), "\n", "\r\n")
+
+	messageID := make([]byte, 16)
+	rand.Read(messageID)
+
+	now := m.now()
+	domain := from.Address[strings.LastIndex(from.Address, "@")+1:]
+	headers := []header{
+		{name: "From", value: message.From},
+		{name: "To", value: message.To},
+		{name: "Subject", value: mime.QEncoding.Encode("UTF-8", message.Subject)},
+		{name: "Date", value: now.Format(time.RFC1123Z)},
+		{name: "Message-ID", value: "<" + hex.EncodeToString(messageID) + "@" + domain + ">"},
+		{name: "MIME-Version", value: "1.0"},
+		{name: "Content-Type", value: `text/html; charset="UTF-8"`},
+		{name: "Content-Transfer-Encoding", value: "quoted-printable"},
+	}
+	if message.ReplyTo != "" {
+		headers = append(headers, header{name: "Reply-To", value: message.ReplyTo})
+	}
+
+	if m.dkim != nil {
+		signature, err := m.dkim.sign(headers, encodedBody, now)
+		if err != nil {
+			return nil, err
+		}
+		headers = append([]header{signature}, headers...)
+	}
+
+	var data bytes.Buffer
+	for _, h := range headers {
+		data.WriteString(h.name + ": " + h.value + "\r\n")
+	}
+	data.WriteString("\r\n")
+	data.WriteString(encodedBody)
+
+	return data.Bytes(), nil
+}
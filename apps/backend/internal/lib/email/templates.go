@@ -1,5 +1,12 @@
 package email
 
+import (
+	"html/template"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/i18n"
+	"golang.org/x/text/language"
+)
+
 type Template string
 
 const (
@@ -7,4 +14,26 @@ const (
 	TemplateDueDateReminder     Template = "due-date-reminder"
 	TemplateOverdueNotification Template = "overdue-notification"
 	TemplateWeeklyReport        Template = "weekly-report"
+	TemplateAccountExportReady  Template = "account-export-ready"
+	TemplateRuleNotification    Template = "rule-notification"
+	TemplateRuleDigest          Template = "rule-digest"
+	TemplateBadgeAwarded        Template = "badge-awarded"
+	TemplateTodoListExportReady Template = "todo-list-export-ready"
+	TemplateSLABreach           Template = "sla-breach"
+	TemplateAttachmentBlocked   Template = "attachment-blocked"
+	TemplateScheduledReport     Template = "scheduled-report"
 )
+
+// templateFuncs translate the messages of the templates into the locale of the recipient,
+// {{t `key` args}} and {{tn `key` count args}} for messages that count something. Keys are
+// raw strings, the exported templates escape quotes.
+func templateFuncs(locale language.Tag) template.FuncMap {
+	return template.FuncMap{
+		"t": func(key string, args ...any) string {
+			return i18n.T(locale, key, args...)
+		},
+		"tn": func(key string, count int, args ...any) string {
+			return i18n.N(locale, key, count, args...)
+		},
+	}
+}
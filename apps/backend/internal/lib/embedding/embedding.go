@@ -0,0 +1,60 @@
+package embedding
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+)
+
+// Dimensions is the size of the vectors in todo_embeddings, see migration 011
+const Dimensions = 1536
+
+const ProviderOpenAI = "openai"
+
+// Provider turns text into vectors whose cosine similarity reflects how related the texts are
+type Provider interface {
+	// Model identifies the vectors, those of different models can't be compared
+	Model() string
+	// Embed returns one vector of Dimensions per text, in the order of texts
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// NewProvider returns the configured provider, nil when semantic search is disabled
+func NewProvider(cfg *config.EmbeddingConfig, outboundCfg *config.OutboundConfig) Provider {
+	if cfg == nil {
+		return nil
+	}
+
+	defaults := config.DefaultEmbeddingConfig()
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaults.BaseURL
+	}
+	model := cfg.Model
+	if model == "" {
+		model = defaults.Model
+	}
+
+	switch cfg.Provider {
+	case ProviderOpenAI:
+		return NewOpenAIProvider(outboundCfg, baseURL, cfg.APIKey, model)
+	default:
+		return nil
+	}
+}
+
+// Format renders a vector in the pgvector text format, e.g. [0.1,0.2]
+func Format(vector []float32) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, value := range vector {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(value), 'f', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
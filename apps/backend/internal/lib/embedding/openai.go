@@ -0,0 +1,110 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/lib/outbound"
+)
+
+// OpenAIProvider calls the embeddings endpoint of the OpenAI API or of a compatible server
+type OpenAIProvider struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+	model   string
+}
+
+func NewOpenAIProvider(outboundCfg *config.OutboundConfig, baseURL, apiKey, model string) *OpenAIProvider {
+	return &OpenAIProvider{
+		client:  outbound.New(outboundCfg, outbound.Options{Timeout: 30 * time.Second}),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+	}
+}
+
+type openAIRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions"`
+}
+
+type openAIResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+func (p *OpenAIProvider) Model() string {
+	return p.model
+}
+
+func (p *OpenAIProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+
+	body, err := json.Marshal(openAIRequest{
+		Model:      p.model,
+		Input:      texts,
+		Dimensions: Dimensions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode embeddings request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build embeddings request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	// Embedding changes nothing, a failed call can be sent again
+	res, err := p.client.Do(outbound.Idempotent(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to call embeddings endpoint: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return nil, fmt.Errorf("embeddings endpoint returned %d: %s", res.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var decoded openAIResponse
+	if err := json.NewDecoder(res.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode embeddings response: %w", err)
+	}
+
+	// Entries carry the position of their input, they aren't guaranteed to be in order
+	vectors := make([][]float32, len(texts))
+	for _, entry := range decoded.Data {
+		if entry.Index < 0 || entry.Index >= len(texts) {
+			return nil, fmt.Errorf("embeddings response has an entry for unknown input %d", entry.Index)
+		}
+		if len(entry.Embedding) != Dimensions {
+			return nil, fmt.Errorf("embeddings response has %d dimensions, expected %d", len(entry.Embedding), Dimensions)
+		}
+		vectors[entry.Index] = entry.Embedding
+	}
+
+	for i, vector := range vectors {
+		if vector == nil {
+			return nil, fmt.Errorf("embeddings response is missing input %d", i)
+		}
+	}
+
+	return vectors, nil
+}
@@ -0,0 +1,278 @@
+package envelope
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/encryption"
+	"github.com/google/uuid"
+)
+
+// prefix starts every sealed value, the data key ID and the base64 nonce and ciphertext
+// follow: enc:v1:<data key id>:<payload>
+const prefix = "enc:v1:"
+
+// activeKeyTTL bounds how long a process keeps encrypting with a data key another process
+// may have rotated out
+const activeKeyTTL = 5 * time.Minute
+
+var ErrNotConfigured = errors.New("encryption is not configured, set a KMS key or a master key")
+
+// Wrapper protects data keys with a master key that never leaves it
+type Wrapper interface {
+	// GenerateDataKey returns a new 256 bit key in plaintext and wrapped, along with the ID of
+	// the master key that wrapped it
+	GenerateDataKey(ctx context.Context) (plaintext []byte, wrapped []byte, masterKeyID string, err error)
+	// UnwrapDataKey recovers a data key wrapped by the named master key
+	UnwrapDataKey(ctx context.Context, masterKeyID string, wrapped []byte) ([]byte, error)
+}
+
+// KeyStore keeps the wrapped data keys
+type KeyStore interface {
+	// GetActiveDataKey returns nil before the first key is created
+	GetActiveDataKey(ctx context.Context) (*encryption.DataKey, error)
+	GetDataKey(ctx context.Context, id uuid.UUID) (*encryption.DataKey, error)
+	// CreateDataKey stores a key as the active one and retires the previous
+	CreateDataKey(ctx context.Context, wrapped []byte, masterKeyID string) (*encryption.DataKey, error)
+}
+
+// Keyring seals field values with envelope encryption: values are encrypted with AES-GCM
+// under a data key, which is stored wrapped by the master key. Unwrapped data keys are cached
+// so the master key is only reached once per key and process.
+type Keyring struct {
+	wrapper Wrapper
+	store   KeyStore
+
+	mu             sync.Mutex
+	ciphers        map[uuid.UUID]cipher.AEAD
+	activeID       uuid.UUID
+	activeLoadedAt time.Time
+}
+
+// NewKeyring returns a keyring, without a wrapper it passes plaintext through and fails to
+// seal or open anything
+func NewKeyring(wrapper Wrapper, store KeyStore) *Keyring {
+	return &Keyring{
+		wrapper: wrapper,
+		store:   store,
+		ciphers: map[uuid.UUID]cipher.AEAD{},
+	}
+}
+
+func (k *Keyring) Enabled() bool {
+	return k.wrapper != nil
+}
+
+// IsSealed tells whether a value was sealed by a keyring
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// KeyID returns the data key a value is sealed with
+func KeyID(value string) (uuid.UUID, bool) {
+	if !IsSealed(value) {
+		return uuid.Nil, false
+	}
+
+	idPart, _, ok := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(idPart)
+	return id, err == nil
+}
+
+// Encrypt seals a value with the active data key, creating the first key when there is none
+func (k *Keyring) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	id, aead, err := k.activeCipher(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	// The key ID is authenticated so a payload can't be passed off as sealed by another key
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), id[:])
+
+	return prefix + id.String() + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Seal encrypts a value of a sealed field, or returns it as it is while encryption isn't
+// configured. The plaintext values are sealed by re-encryption once it is.
+func (k *Keyring) Seal(ctx context.Context, plaintext string) (string, error) {
+	if !k.Enabled() {
+		return plaintext, nil
+	}
+	return k.Encrypt(ctx, plaintext)
+}
+
+// Decrypt opens a sealed value. Values that aren't sealed are returned as they are, they
+// were stored before their field was encrypted.
+func (k *Keyring) Decrypt(ctx context.Context, value string) (string, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+
+	id, ok := KeyID(value)
+	if !ok {
+		return "", errors.New("sealed value is malformed")
+	}
+	_, payload, _ := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+
+	sealed, err := base64.RawStdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", fmt.Errorf("sealed value is malformed: %w", err)
+	}
+
+	aead, err := k.cipher(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("sealed value is malformed")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, id[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value sealed with data key %s: %w", id, err)
+	}
+
+	return string(plaintext), nil
+}
+
+// ActiveKeyID returns the data key values are sealed with
+func (k *Keyring) ActiveKeyID(ctx context.Context) (uuid.UUID, error) {
+	id, _, err := k.activeCipher(ctx)
+	return id, err
+}
+
+// Rotate creates a new active data key. The retired keys keep decrypting until the values
+// they sealed are encrypted again.
+func (k *Keyring) Rotate(ctx context.Context) (*encryption.DataKey, error) {
+	if k.wrapper == nil {
+		return nil, ErrNotConfigured
+	}
+
+	plaintext, wrapped, masterKeyID, err := k.wrapper.GenerateDataKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	aead, err := newCipher(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := k.store.CreateDataKey(ctx, wrapped, masterKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	k.ciphers[dataKey.ID] = aead
+	k.activeID = dataKey.ID
+	k.activeLoadedAt = time.Now()
+	k.mu.Unlock()
+
+	return dataKey, nil
+}
+
+func (k *Keyring) activeCipher(ctx context.Context) (uuid.UUID, cipher.AEAD, error) {
+	if k.wrapper == nil {
+		return uuid.Nil, nil, ErrNotConfigured
+	}
+
+	k.mu.Lock()
+	if k.activeID != uuid.Nil && time.Since(k.activeLoadedAt) < activeKeyTTL {
+		id, aead := k.activeID, k.ciphers[k.activeID]
+		k.mu.Unlock()
+		return id, aead, nil
+	}
+	k.mu.Unlock()
+
+	dataKey, err := k.store.GetActiveDataKey(ctx)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	if dataKey == nil {
+		dataKey, err = k.Rotate(ctx)
+		if err != nil {
+			// Another process may have created the first key meanwhile
+			dataKey, _ = k.store.GetActiveDataKey(ctx)
+			if dataKey == nil {
+				return uuid.Nil, nil, err
+			}
+		}
+	}
+
+	aead, err := k.cipher(ctx, dataKey.ID)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	k.mu.Lock()
+	k.activeID = dataKey.ID
+	k.activeLoadedAt = time.Now()
+	k.mu.Unlock()
+
+	return dataKey.ID, aead, nil
+}
+
+// cipher returns the cipher of a data key, unwrapping it on first use
+func (k *Keyring) cipher(ctx context.Context, id uuid.UUID) (cipher.AEAD, error) {
+	if k.wrapper == nil {
+		return nil, ErrNotConfigured
+	}
+
+	k.mu.Lock()
+	aead, ok := k.ciphers[id]
+	k.mu.Unlock()
+	if ok {
+		return aead, nil
+	}
+
+	dataKey, err := k.store.GetDataKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := k.wrapper.UnwrapDataKey(ctx, dataKey.MasterKeyID, dataKey.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key %s: %w", id, err)
+	}
+
+	aead, err = newCipher(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	k.ciphers[id] = aead
+	k.mu.Unlock()
+
+	return aead, nil
+}
+
+func newCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data key: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
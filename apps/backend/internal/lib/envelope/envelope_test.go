@@ -0,0 +1,110 @@
+package envelope_test
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/envelope"
+	"github.com/Sameer16536/ExecuTask/internal/repository/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func newWrapper(t *testing.T) envelope.Wrapper {
+	t.Helper()
+
+	masterKey := make([]byte, 32)
+	_, err := rand.Read(masterKey)
+	require.NoError(t, err)
+	wrapper, err := envelope.NewLocalWrapper(base64.StdEncoding.EncodeToString(masterKey))
+	require.NoError(t, err)
+
+	return wrapper
+}
+
+func TestKeyringRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	keyring := envelope.NewKeyring(newWrapper(t), memory.NewEncryptionRepository(memory.NewStore()))
+
+	sealed, err := keyring.Seal(ctx, "secret")
+	require.NoError(t, err)
+	require.True(t, envelope.IsSealed(sealed))
+	require.NotContains(t, sealed, "secret")
+
+	// Every seal takes a fresh nonce
+	again, err := keyring.Encrypt(ctx, "secret")
+	require.NoError(t, err)
+	require.NotEqual(t, sealed, again)
+
+	for _, value := range []string{sealed, again} {
+		opened, err := keyring.Decrypt(ctx, value)
+		require.NoError(t, err)
+		require.Equal(t, "secret", opened)
+	}
+
+	// Values stored before their field was sealed are read as they are
+	opened, err := keyring.Decrypt(ctx, "plain")
+	require.NoError(t, err)
+	require.Equal(t, "plain", opened)
+
+	// A payload moved under another key ID doesn't open
+	id, ok := envelope.KeyID(sealed)
+	require.True(t, ok)
+	rotated, err := keyring.Rotate(ctx)
+	require.NoError(t, err)
+	_, err = keyring.Decrypt(ctx, "enc:v1:"+rotated.ID.String()+sealed[len("enc:v1:"+id.String()):])
+	require.Error(t, err)
+}
+
+func TestKeyringRotation(t *testing.T) {
+	ctx := context.Background()
+	wrapper := newWrapper(t)
+	store := memory.NewEncryptionRepository(memory.NewStore())
+	keyring := envelope.NewKeyring(wrapper, store)
+
+	before, err := keyring.Encrypt(ctx, "secret")
+	require.NoError(t, err)
+	previous, _ := envelope.KeyID(before)
+
+	rotated, err := keyring.Rotate(ctx)
+	require.NoError(t, err)
+	require.NotEqual(t, previous, rotated.ID)
+
+	retired, err := store.GetDataKey(ctx, previous)
+	require.NoError(t, err)
+	require.NotNil(t, retired.RetiredAt)
+
+	after, err := keyring.Encrypt(ctx, "secret")
+	require.NoError(t, err)
+	id, _ := envelope.KeyID(after)
+	require.Equal(t, rotated.ID, id)
+
+	// Another process unwraps both keys from the store, retired keys keep opening their values
+	other := envelope.NewKeyring(wrapper, store)
+	for _, value := range []string{before, after} {
+		opened, err := other.Decrypt(ctx, value)
+		require.NoError(t, err)
+		require.Equal(t, "secret", opened)
+	}
+
+	again, err := other.Encrypt(ctx, "secret")
+	require.NoError(t, err)
+	id, _ = envelope.KeyID(again)
+	require.Equal(t, rotated.ID, id)
+}
+
+func TestKeyringWithoutWrapper(t *testing.T) {
+	ctx := context.Background()
+	keyring := envelope.NewKeyring(nil, memory.NewEncryptionRepository(memory.NewStore()))
+	require.False(t, keyring.Enabled())
+
+	stored, err := keyring.Seal(ctx, "secret")
+	require.NoError(t, err)
+	require.Equal(t, "secret", stored)
+
+	_, err = keyring.Encrypt(ctx, "secret")
+	require.ErrorIs(t, err, envelope.ErrNotConfigured)
+	_, err = keyring.Rotate(ctx)
+	require.ErrorIs(t, err, envelope.ErrNotConfigured)
+}
@@ -0,0 +1,74 @@
+package envelope
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// LocalWrapper wraps data keys with a master key from the config. It is meant for development,
+// deployments keep the master key in KMS.
+type LocalWrapper struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// NewLocalWrapper takes a base64 encoded 32 byte master key
+func NewLocalWrapper(masterKey string) (*LocalWrapper, error) {
+	key, err := base64.StdEncoding.DecodeString(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("master key is not base64: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("master key must be 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// The ID is a fingerprint so a changed master key is told apart from the one that wrapped a key
+	fingerprint := sha256.Sum256(key)
+
+	return &LocalWrapper{
+		id:   "local:" + hex.EncodeToString(fingerprint[:8]),
+		aead: aead,
+	}, nil
+}
+
+func (w *LocalWrapper) GenerateDataKey(ctx context.Context) ([]byte, []byte, string, error) {
+	plaintext := make([]byte, 32)
+	if _, err := rand.Read(plaintext); err != nil {
+		return nil, nil, "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	nonce := make([]byte, w.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return plaintext, w.aead.Seal(nonce, nonce, plaintext, nil), w.id, nil
+}
+
+func (w *LocalWrapper) UnwrapDataKey(ctx context.Context, masterKeyID string, wrapped []byte) ([]byte, error) {
+	if masterKeyID != w.id {
+		return nil, fmt.Errorf("data key was wrapped by master key %s, not by the configured %s", masterKeyID, w.id)
+	}
+	if len(wrapped) < w.aead.NonceSize() {
+		return nil, errors.New("wrapped data key is malformed")
+	}
+
+	nonce, ciphertext := wrapped[:w.aead.NonceSize()], wrapped[w.aead.NonceSize():]
+	return w.aead.Open(nil, nonce, ciphertext, nil)
+}
@@ -0,0 +1,61 @@
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	HeaderETag        = "ETag"
+	HeaderIfMatch     = "If-Match"
+	HeaderIfNoneMatch = "If-None-Match"
+)
+
+// Tagged is implemented by responses that can describe their own version
+type Tagged interface {
+	ETag() string
+}
+
+// Strong builds a quoted strong entity tag from the given version parts
+func Strong(parts ...string) string {
+	hash := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return `"` + hex.EncodeToString(hash[:16]) + `"`
+}
+
+// Matches reports whether an If-None-Match header value matches tag. The header may hold a
+// list of tags or "*"; it uses the weak comparison, weak tags compare by their opaque value.
+func Matches(header, tag string) bool {
+	return matches(header, tag, false)
+}
+
+// MatchesStrong reports whether an If-Match header value matches tag. It uses the strong
+// comparison RFC 9110 requires for If-Match: weak tags never match.
+func MatchesStrong(header, tag string) bool {
+	return matches(header, tag, true)
+}
+
+func matches(header, tag string, strong bool) bool {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if strings.HasPrefix(candidate, "W/") {
+			if strong {
+				continue
+			}
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == tag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,40 @@
+package etag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatches(t *testing.T) {
+	tag := Strong("todo", "1")
+
+	tests := []struct {
+		name   string
+		header string
+		weak   bool
+		strong bool
+	}{
+		{name: "empty header", header: "", weak: false, strong: false},
+		{name: "any", header: "*", weak: true, strong: true},
+		{name: "same tag", header: tag, weak: true, strong: true},
+		{name: "weak tag", header: "W/" + tag, weak: true, strong: false},
+		{name: "other tag", header: Strong("todo", "2"), weak: false, strong: false},
+		{name: "listed tag", header: Strong("todo", "2") + " , " + tag, weak: true, strong: true},
+		{name: "listed weak tag", header: Strong("todo", "2") + ", W/" + tag, weak: true, strong: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.weak, Matches(tt.header, tag))
+			require.Equal(t, tt.strong, MatchesStrong(tt.header, tag))
+		})
+	}
+}
+
+func TestStrong(t *testing.T) {
+	require.Equal(t, Strong("todo", "1"), Strong("todo", "1"))
+	require.NotEqual(t, Strong("todo", "1"), Strong("todo", "2"))
+	// Parts are separated, moving characters between them changes the tag
+	require.NotEqual(t, Strong("ab", "c"), Strong("a", "bc"))
+}
@@ -0,0 +1,34 @@
+package i18n
+
+import (
+	"regexp"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// Layouts of the dates shown to users, the catalogs translate them into layouts of their own
+const (
+	LayoutDateTime  = "Monday, January 2, 2006 at 3:04 PM"
+	LayoutLongDate  = "Monday, January 2, 2006"
+	LayoutDate      = "January 2, 2006"
+	LayoutShortDate = "Jan 2"
+)
+
+// dateNames matches the English month and day names time.Format writes, short names only as
+// whole words
+var dateNames = regexp.MustCompile(`\b(January|February|March|April|May|June|July|August|September|October|November|December|` +
+	`Monday|Tuesday|Wednesday|Thursday|Friday|Saturday|Sunday|` +
+	`Jan|Feb|Mar|Apr|Jun|Jul|Aug|Sep|Oct|Nov|Dec|Mon|Tue|Wed|Thu|Fri|Sat|Sun)\b`)
+
+// FormatTime formats t with the layout of the locale, month and day names translated
+func FormatTime(tag language.Tag, t time.Time, layout string) string {
+	formatted := t.Format(T(tag, layout))
+	if tag == Default {
+		return formatted
+	}
+
+	return dateNames.ReplaceAllStringFunc(formatted, func(name string) string {
+		return T(tag, name)
+	})
+}
@@ -0,0 +1,164 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// Default is the locale messages fall back to, the code is written in it
+var Default = language.English
+
+// Supported are the locales with a catalog, Default first
+var Supported = []language.Tag{language.English, language.Spanish, language.French}
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// catalogs holds the messages of every supported locale by their base language
+var catalogs = loadCatalogs()
+
+var matcher = language.NewMatcher(Supported)
+
+// entry is a message of a catalog, with a form per plural category when it counts something
+type entry struct {
+	text  string
+	forms map[string]string
+}
+
+func (e *entry) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &e.text); err == nil {
+		return nil
+	}
+	return json.Unmarshal(data, &e.forms)
+}
+
+func loadCatalogs() map[language.Base]map[string]entry {
+	loaded := make(map[language.Base]map[string]entry, len(Supported))
+	for _, tag := range Supported {
+		base, _ := tag.Base()
+		data, err := locales.ReadFile(path.Join("locales", base.String()+".json"))
+		if err != nil {
+			panic(fmt.Sprintf("missing catalog of locale %s: %v", tag, err))
+		}
+
+		messages := map[string]entry{}
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("invalid catalog of locale %s: %v", tag, err))
+		}
+		loaded[base] = messages
+	}
+	return loaded
+}
+
+// Negotiate picks the supported locale that best matches an Accept-Language header, Default
+// when none does
+func Negotiate(acceptLanguage string) language.Tag {
+	if strings.TrimSpace(acceptLanguage) == "" {
+		return Default
+	}
+
+	desired, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(desired) == 0 {
+		return Default
+	}
+
+	_, index, confidence := matcher.Match(desired...)
+	if confidence == language.No {
+		return Default
+	}
+	return Supported[index]
+}
+
+// Parse reads a locale chosen by a user, only supported locales are accepted
+func Parse(locale string) (language.Tag, bool) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return Default, false
+	}
+
+	for _, supported := range Supported {
+		if tag == supported {
+			return supported, true
+		}
+	}
+	return Default, false
+}
+
+// IsSupported tells whether a locale has a catalog, for validating what users choose
+func IsSupported(locale string) bool {
+	_, ok := Parse(locale)
+	return ok
+}
+
+// T translates a message into the locale and formats it with the args. Messages are looked up
+// by key, which is the English text itself for messages written in code. A message missing
+// from the catalog of the locale comes from the Default one, or is the key as is.
+func T(tag language.Tag, key string, args ...any) string {
+	message := key
+	if e, ok := lookup(tag, key); ok {
+		message = e.text
+		if e.forms != nil {
+			message = e.forms["other"]
+		}
+	}
+	return format(message, args)
+}
+
+// N translates a message that counts something, picking the plural form of the locale for
+// count. The count is the first arg of the message.
+func N(tag language.Tag, key string, count int, args ...any) string {
+	args = append([]any{count}, args...)
+
+	e, ok := lookup(tag, key)
+	if !ok {
+		return format(key, args)
+	}
+	if e.forms == nil {
+		return format(e.text, args)
+	}
+
+	form := "other"
+	switch plural.Cardinal.MatchPlural(tag, count, 0, 0, 0, 0) {
+	case plural.Zero:
+		form = "zero"
+	case plural.One:
+		form = "one"
+	case plural.Two:
+		form = "two"
+	case plural.Few:
+		form = "few"
+	case plural.Many:
+		form = "many"
+	}
+
+	message, ok := e.forms[form]
+	if !ok {
+		message = e.forms["other"]
+	}
+	return format(message, args)
+}
+
+func lookup(tag language.Tag, key string) (entry, bool) {
+	base, _ := tag.Base()
+	if e, ok := catalogs[base][key]; ok {
+		return e, true
+	}
+
+	defaultBase, _ := Default.Base()
+	e, ok := catalogs[defaultBase][key]
+	return e, ok
+}
+
+// format leaves messages without args alone, a literal percent sign in them isn't a verb
+func format(message string, args []any) string {
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
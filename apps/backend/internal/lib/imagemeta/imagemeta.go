@@ -0,0 +1,174 @@
+// Package imagemeta removes the metadata cameras and editors embed in JPEG and PNG images, such
+// as EXIF with GPS positions, XMP, IPTC and text chunks. The image data is copied as is, nothing
+// is decoded or encoded again.
+package imagemeta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+var ErrMalformed = errors.New("the image is malformed")
+
+const (
+	markerSOS   = 0xda
+	markerEOI   = 0xd9
+	markerAPP1  = 0xe1
+	markerAPP13 = 0xed
+	markerCOM   = 0xfe
+
+	orientationTag = 0x0112
+)
+
+var (
+	exifHeader   = []byte("Exif\x00\x00")
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+)
+
+// pngMetadataChunks are the ancillary chunks dropped from PNG images
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+// StripJPEG drops the APP1 (EXIF, XMP), APP13 (IPTC) and comment segments. The EXIF orientation
+// goes with them, so it is returned for the caller to apply: 1 when the image is upright or
+// has no EXIF, up to 8 as the EXIF specification numbers them.
+func StripJPEG(data []byte) ([]byte, int, error) {
+	if len(data) < 4 || data[0] != 0xff || data[1] != 0xd8 {
+		return nil, 0, ErrMalformed
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:2])
+	orientation := 1
+
+	pos := 2
+	for pos < len(data) {
+		if data[pos] != 0xff {
+			return nil, 0, ErrMalformed
+		}
+		// Markers may be padded with fill bytes
+		for pos+1 < len(data) && data[pos+1] == 0xff {
+			pos++
+		}
+		if pos+1 >= len(data) {
+			return nil, 0, ErrMalformed
+		}
+
+		marker := data[pos+1]
+		switch {
+		case marker == markerSOS || marker == markerEOI:
+			// The entropy coded data follows, metadata segments only come before it
+			out.Write(data[pos:])
+			return out.Bytes(), orientation, nil
+		case marker == 0x01 || (marker >= 0xd0 && marker <= 0xd7):
+			out.Write(data[pos : pos+2])
+			pos += 2
+			continue
+		}
+
+		if pos+4 > len(data) {
+			return nil, 0, ErrMalformed
+		}
+		end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:pos+4]))
+		if end > len(data) || end < pos+4 {
+			return nil, 0, ErrMalformed
+		}
+		segment := data[pos:end]
+
+		switch marker {
+		case markerAPP1:
+			if payload := segment[4:]; bytes.HasPrefix(payload, exifHeader) {
+				if value, ok := exifOrientation(payload[len(exifHeader):]); ok {
+					orientation = value
+				}
+			}
+		case markerAPP13, markerCOM:
+		default:
+			out.Write(segment)
+		}
+		pos = end
+	}
+
+	return nil, 0, ErrMalformed
+}
+
+// exifOrientation reads the orientation tag of the first IFD of a TIFF structure
+func exifOrientation(tiff []byte) (int, bool) {
+	if len(tiff) < 8 {
+		return 0, false
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, false
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 0, false
+	}
+	count := int(order.Uint16(tiff[offset : offset+2]))
+
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0, false
+		}
+		if order.Uint16(tiff[entry:entry+2]) != orientationTag {
+			continue
+		}
+		value := int(order.Uint16(tiff[entry+8 : entry+10]))
+		if value < 1 || value > 8 {
+			return 0, false
+		}
+		return value, true
+	}
+
+	return 0, false
+}
+
+// StripPNG drops the EXIF, text and modification time chunks
+func StripPNG(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, ErrMalformed
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(pngSignature)
+
+	pos := len(pngSignature)
+	for pos < len(data) {
+		if pos+8 > len(data) {
+			return nil, ErrMalformed
+		}
+		length := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		chunkType := string(data[pos+4 : pos+8])
+		// Length, type, data and CRC
+		end := pos + 12 + length
+		if length < 0 || end > len(data) || end < pos {
+			return nil, ErrMalformed
+		}
+
+		if !pngMetadataChunks[chunkType] {
+			out.Write(data[pos:end])
+		}
+		pos = end
+
+		if chunkType == "IEND" {
+			return out.Bytes(), nil
+		}
+	}
+
+	return nil, ErrMalformed
+}
@@ -0,0 +1,63 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskAgingPolicyEvaluation = "workspace:aging_policies"
+	TaskSLABreachEmail        = "email:sla_breach"
+)
+
+// AgingPolicyEvaluationTask escalates the todos of the workspace breaching its aging policies
+type AgingPolicyEvaluationTask struct {
+	TaskMetadata
+	WorkspaceID string `json:"workspace_id"`
+}
+
+// EnqueueAgingPolicyEvaluation queues the evaluation of the policies of a workspace, a workspace
+// already waiting for one is not queued twice
+func EnqueueAgingPolicyEvaluation(ctx context.Context, client *asynq.Client, task *AgingPolicyEvaluationTask) error {
+	asynqTask, err := newTask(ctx, TaskAgingPolicyEvaluation, task,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(5*time.Minute),
+		asynq.TaskID("aging-policies:"+task.WorkspaceID))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
+	return err
+}
+
+type SLABreachEmailTask struct {
+	TaskMetadata
+	UserID      string    `json:"user_id"`
+	PolicyName  string    `json:"policy_name"`
+	MaxAgeHours int       `json:"max_age_hours"`
+	TodoID      uuid.UUID `json:"todo_id"`
+	TodoTitle   string    `json:"todo_title"`
+	OpenedAt    time.Time `json:"opened_at"`
+}
+
+func EnqueueSLABreachEmail(ctx context.Context, client *asynq.Client, task *SLABreachEmailTask) error {
+	asynqTask, err := newTask(ctx, TaskSLABreachEmail, task,
+		asynq.MaxRetry(3),
+		asynq.Queue("default"),
+		asynq.Timeout(30*time.Second))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	return err
+}
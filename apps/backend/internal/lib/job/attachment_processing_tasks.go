@@ -0,0 +1,30 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const TaskAttachmentProcessing = "attachment:process"
+
+type AttachmentProcessingTask struct {
+	TaskMetadata
+	TodoID       uuid.UUID `json:"todo_id"`
+	AttachmentID uuid.UUID `json:"attachment_id"`
+}
+
+func EnqueueAttachmentProcessing(ctx context.Context, client *asynq.Client, task *AttachmentProcessingTask) error {
+	asynqTask, err := newTask(ctx, TaskAttachmentProcessing, task,
+		asynq.MaxRetry(3),
+		asynq.Queue("default"),
+		asynq.Timeout(2*time.Minute))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	return err
+}
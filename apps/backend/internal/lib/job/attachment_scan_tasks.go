@@ -0,0 +1,56 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskAttachmentScan         = "attachment:scan"
+	TaskAttachmentBlockedEmail = "email:attachment_blocked"
+)
+
+type AttachmentScanTask struct {
+	TaskMetadata
+	UserID       string    `json:"user_id"`
+	TodoID       uuid.UUID `json:"todo_id"`
+	AttachmentID uuid.UUID `json:"attachment_id"`
+}
+
+func EnqueueAttachmentScan(ctx context.Context, client *asynq.Client, task *AttachmentScanTask) error {
+	asynqTask, err := newTask(ctx, TaskAttachmentScan, task,
+		asynq.MaxRetry(5),
+		asynq.Queue("critical"), // The attachment can't be downloaded until it is scanned
+		asynq.Timeout(5*time.Minute))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	return err
+}
+
+type AttachmentBlockedEmailTask struct {
+	TaskMetadata
+	UserID    string    `json:"user_id"`
+	TodoID    uuid.UUID `json:"todo_id"`
+	TodoTitle string    `json:"todo_title"`
+	FileName  string    `json:"file_name"`
+	Signature string    `json:"signature"`
+}
+
+func EnqueueAttachmentBlockedEmail(ctx context.Context, client *asynq.Client, task *AttachmentBlockedEmailTask) error {
+	asynqTask, err := newTask(ctx, TaskAttachmentBlockedEmail, task,
+		asynq.MaxRetry(3),
+		asynq.Queue("default"),
+		asynq.Timeout(30*time.Second))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	return err
+}
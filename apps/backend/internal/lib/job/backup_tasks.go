@@ -0,0 +1,50 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskWorkspaceBackup  = "backup:workspace"
+	TaskWorkspaceRestore = "backup:restore"
+)
+
+type WorkspaceBackupTask struct {
+	TaskMetadata
+	BackupID uuid.UUID `json:"backup_id"`
+}
+
+type WorkspaceRestoreTask struct {
+	TaskMetadata
+	RestoreID uuid.UUID `json:"restore_id"`
+}
+
+func EnqueueWorkspaceBackup(ctx context.Context, client *asynq.Client, task *WorkspaceBackupTask) error {
+	asynqTask, err := newTask(ctx, TaskWorkspaceBackup, task,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(time.Hour)) // Copies every attachment of the workspace into the archive
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	return err
+}
+
+func EnqueueWorkspaceRestore(ctx context.Context, client *asynq.Client, task *WorkspaceRestoreTask) error {
+	asynqTask, err := newTask(ctx, TaskWorkspaceRestore, task,
+		asynq.MaxRetry(3),
+		asynq.Queue("default"),
+		asynq.Timeout(time.Hour))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	return err
+}
@@ -0,0 +1,45 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const TaskCommentEmailReply = "email:comment_reply"
+
+// CommentEmailReplyTask is an email received at a reply address, Text still holds the quoted
+// message
+type CommentEmailReplyTask struct {
+	TaskMetadata
+	MessageID string   `json:"message_id"`
+	From      string   `json:"from"`
+	To        []string `json:"to"`
+	Text      string   `json:"text"`
+}
+
+// EnqueueCommentEmailReply queues an inbound reply. Providers deliver an email again when they
+// don't hear back in time, a message already queued is not queued twice.
+func EnqueueCommentEmailReply(ctx context.Context, client *asynq.Client, task *CommentEmailReplyTask) error {
+	opts := []asynq.Option{
+		asynq.MaxRetry(3),
+		asynq.Queue("default"),
+		asynq.Timeout(30 * time.Second),
+	}
+	if task.MessageID != "" {
+		opts = append(opts, asynq.TaskID("comment-reply:"+task.MessageID), asynq.Retention(24*time.Hour))
+	}
+
+	asynqTask, err := newTask(ctx, TaskCommentEmailReply, task, opts...)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
+	return err
+}
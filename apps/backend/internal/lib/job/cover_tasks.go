@@ -0,0 +1,31 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const TaskCoverRender = "cover:render"
+
+type CoverRenderTask struct {
+	TaskMetadata
+	UserID     string    `json:"user_id"`
+	TodoID     uuid.UUID `json:"todo_id"`
+	Generation uuid.UUID `json:"generation"`
+}
+
+func EnqueueCoverRender(ctx context.Context, client *asynq.Client, task *CoverRenderTask) error {
+	asynqTask, err := newTask(ctx, TaskCoverRender, task,
+		asynq.MaxRetry(3),
+		asynq.Queue("default"),
+		asynq.Timeout(2*time.Minute))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	return err
+}
@@ -1,7 +1,8 @@
 package job
 
 import (
-	"encoding/json"
+	"context"
+	"strings"
 	"time"
 
 	"github.com/Sameer16536/ExecuTask/internal/model/todo"
@@ -15,27 +16,35 @@ const (
 	TaskWeeklyReportEmail = "email:weekly_report"
 )
 
+// IsNotificationEmail tells whether a task sends an email to users, the inbound replies
+// handled by TaskCommentEmailReply are not
+func IsNotificationEmail(taskType string) bool {
+	return strings.HasPrefix(taskType, "email:") && taskType != TaskCommentEmailReply
+}
+
 type WelcomeEmailPayload struct {
+	TaskMetadata
 	To        string `json:"to"`
 	FirstName string `json:"first_name"`
+	// Locale is the locale the email is written in, the default when empty
+	Locale string `json:"locale,omitempty"`
 }
 
-func NewWelcomeEmailTask(to, firstName string) (*asynq.Task, error) {
-	payload, err := json.Marshal(WelcomeEmailPayload{
+func NewWelcomeEmailTask(ctx context.Context, to, firstName, locale string) (*asynq.Task, error) {
+	payload := &WelcomeEmailPayload{
 		To:        to,
 		FirstName: firstName,
-	})
-	if err != nil {
-		return nil, err
+		Locale:    locale,
 	}
 
-	return asynq.NewTask(TaskWelcome, payload,
+	return newTask(ctx, TaskWelcome, payload,
 		asynq.MaxRetry(3),
 		asynq.Queue("default"),
-		asynq.Timeout(30*time.Second)), nil
+		asynq.Timeout(30*time.Second))
 }
 
 type ReminderEmailTask struct {
+	TaskMetadata
 	UserID    string    `json:"user_id"`
 	TodoID    uuid.UUID `json:"todo_id"`
 	TodoTitle string    `json:"todo_title"`
@@ -43,22 +52,21 @@ type ReminderEmailTask struct {
 	TaskType  string    `json:"task_type"` // "due_date_reminder" or "overdue_notification"
 }
 
-func EnqueueReminderEmail(client *asynq.Client, task *ReminderEmailTask) error {
-	payload, err := json.Marshal(task)
-	if err != nil {
-		return err
-	}
-
-	asynqTask := asynq.NewTask(TaskReminderEmail, payload,
+func EnqueueReminderEmail(ctx context.Context, client *asynq.Client, task *ReminderEmailTask) error {
+	asynqTask, err := newTask(ctx, TaskReminderEmail, task,
 		asynq.MaxRetry(3),
 		asynq.Queue("default"),
 		asynq.Timeout(30*time.Second))
+	if err != nil {
+		return err
+	}
 
-	_, err = client.Enqueue(asynqTask)
+	_, err = client.EnqueueContext(ctx, asynqTask)
 	return err
 }
 
 type WeeklyReportEmailTask struct {
+	TaskMetadata
 	UserID         string               `json:"user_id"`
 	WeekStart      time.Time            `json:"week_start"`
 	WeekEnd        time.Time            `json:"week_end"`
@@ -69,17 +77,15 @@ type WeeklyReportEmailTask struct {
 	OverdueTodos   []todo.PopulatedTodo `json:"overdue_todos"`
 }
 
-func EnqueueWeeklyReportEmail(client *asynq.Client, task *WeeklyReportEmailTask) error {
-	payload, err := json.Marshal(task)
-	if err != nil {
-		return err
-	}
-
-	asynqTask := asynq.NewTask(TaskWeeklyReportEmail, payload,
+func EnqueueWeeklyReportEmail(ctx context.Context, client *asynq.Client, task *WeeklyReportEmailTask) error {
+	asynqTask, err := newTask(ctx, TaskWeeklyReportEmail, task,
 		asynq.MaxRetry(3),
 		asynq.Queue("default"),
 		asynq.Timeout(60*time.Second)) // Longer timeout for report generation
+	if err != nil {
+		return err
+	}
 
-	_, err = client.Enqueue(asynqTask)
+	_, err = client.EnqueueContext(ctx, asynqTask)
 	return err
 }
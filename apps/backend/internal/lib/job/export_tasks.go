@@ -0,0 +1,52 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskAccountExport  = "export:account"
+	TaskTodoListExport = "export:todo_list"
+)
+
+type AccountExportTask struct {
+	TaskMetadata
+	UserID   string    `json:"user_id"`
+	ExportID uuid.UUID `json:"export_id"`
+}
+
+func EnqueueAccountExport(ctx context.Context, client *asynq.Client, task *AccountExportTask) error {
+	asynqTask, err := newTask(ctx, TaskAccountExport, task,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(30*time.Minute)) // Copies every attachment into the archive
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	return err
+}
+
+type TodoListExportTask struct {
+	TaskMetadata
+	UserID   string    `json:"user_id"`
+	ExportID uuid.UUID `json:"export_id"`
+}
+
+func EnqueueTodoListExport(ctx context.Context, client *asynq.Client, task *TodoListExportTask) error {
+	asynqTask, err := newTask(ctx, TaskTodoListExport, task,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(15*time.Minute)) // Reads every todo with its comments and attachments
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	return err
+}
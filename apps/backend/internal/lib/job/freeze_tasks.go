@@ -0,0 +1,36 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const TaskWorkspacePurge = "workspace:purge"
+
+// WorkspacePurgeTask deletes the data of a frozen workspace whose retention ran out
+type WorkspacePurgeTask struct {
+	TaskMetadata
+	WorkspaceID string `json:"workspace_id"`
+}
+
+// EnqueueWorkspacePurge queues the purge of a workspace, a workspace already waiting for one is
+// not queued twice
+func EnqueueWorkspacePurge(ctx context.Context, client *asynq.Client, task *WorkspacePurgeTask) error {
+	asynqTask, err := newTask(ctx, TaskWorkspacePurge, task,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(10*time.Minute),
+		asynq.TaskID("workspace-purge:"+task.WorkspaceID))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
+	return err
+}
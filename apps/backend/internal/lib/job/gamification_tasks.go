@@ -0,0 +1,62 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskBadgeEvaluation   = "gamification:evaluate_badges"
+	TaskBadgeAwardedEmail = "email:badge_awarded"
+
+	// badgeEvaluationDelay collects the completions of a burst into one evaluation
+	badgeEvaluationDelay = 30 * time.Second
+)
+
+type BadgeEvaluationTask struct {
+	TaskMetadata
+	UserID string `json:"user_id"`
+}
+
+// EnqueueBadgeEvaluation queues a check of the badges of a user shortly. While one is waiting
+// for the user, the check already covers the new completion and nothing more is queued.
+func EnqueueBadgeEvaluation(ctx context.Context, client *asynq.Client, task *BadgeEvaluationTask) error {
+	asynqTask, err := newTask(ctx, TaskBadgeEvaluation, task,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(time.Minute),
+		asynq.ProcessIn(badgeEvaluationDelay),
+		asynq.TaskID("badges:"+task.UserID))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
+	return err
+}
+
+type BadgeAwardedEmailTask struct {
+	TaskMetadata
+	UserID           string `json:"user_id"`
+	BadgeName        string `json:"badge_name"`
+	BadgeDescription string `json:"badge_description"`
+}
+
+func EnqueueBadgeAwardedEmail(ctx context.Context, client *asynq.Client, task *BadgeAwardedEmailTask) error {
+	asynqTask, err := newTask(ctx, TaskBadgeAwardedEmail, task,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(30*time.Second))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	return err
+}
@@ -6,32 +6,56 @@ import (
 	"fmt"
 
 	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/lib/breaker"
 	"github.com/Sameer16536/ExecuTask/internal/lib/email"
+	"github.com/Sameer16536/ExecuTask/internal/lib/i18n"
+	"github.com/Sameer16536/ExecuTask/internal/model/usage"
+	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
+	"github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/rs/zerolog"
+	"golang.org/x/text/language"
 )
 
-func (j *JobService) InitHandlers(config *config.Config, logger *zerolog.Logger) {
-	j.emailClient = email.NewClient(config, logger)
+// InitHandlers creates the clients of the handlers. Emails go through the email breaker, while
+// it's open the email jobs fail at once and are retried later.
+func (j *JobService) InitHandlers(config *config.Config, logger *zerolog.Logger, nrApp *newrelic.Application,
+	breakers *breaker.Registry,
+) error {
+	emailClient, err := email.NewClient(config, logger, nrApp, breakers.Get("email"))
+	if err != nil {
+		return fmt.Errorf("failed to create email client: %w", err)
+	}
+	j.emailClient = emailClient
+
+	return nil
 }
 
 func (j *JobService) handleWelcomeEmailTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
 	var p WelcomeEmailPayload
 	if err := json.Unmarshal(t.Payload(), &p); err != nil {
 		return fmt.Errorf("failed to unmarshal welcome email payload: %w", err)
 	}
 
-	j.logger.Info().
+	logger.Info().
 		Str("type", "welcome").
 		Str("to", p.To).
 		Msg("Processing welcome email task")
 
+	locale, ok := i18n.Parse(p.Locale)
+	if !ok {
+		locale = i18n.Default
+	}
+
 	err := j.emailClient.SendWelcomeEmail(
+		locale,
 		p.To,
 		p.FirstName,
 	)
 	if err != nil {
-		j.logger.Error().
+		logger.Error().
 			Str("type", "welcome").
 			Str("to", p.To).
 			Err(err).
@@ -39,7 +63,7 @@ func (j *JobService) handleWelcomeEmailTask(ctx context.Context, t *asynq.Task)
 		return err
 	}
 
-	j.logger.Info().
+	logger.Info().
 		Str("type", "welcome").
 		Str("to", p.To).
 		Msg("Successfully sent welcome email")
@@ -47,12 +71,14 @@ func (j *JobService) handleWelcomeEmailTask(ctx context.Context, t *asynq.Task)
 }
 
 func (j *JobService) handleReminderEmailTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
 	var p ReminderEmailTask
 	if err := json.Unmarshal(t.Payload(), &p); err != nil {
 		return fmt.Errorf("failed to unmarshal reminder email payload: %w", err)
 	}
 
-	j.logger.Info().
+	logger.Info().
 		Str("type", p.TaskType).
 		Str("user_id", p.UserID).
 		Str("todo_id", p.TodoID.String()).
@@ -61,7 +87,7 @@ func (j *JobService) handleReminderEmailTask(ctx context.Context, t *asynq.Task)
 
 	userEmail, err := j.authService.GetUserEmail(ctx, p.UserID)
 	if err != nil {
-		j.logger.Error().
+		logger.Error().
 			Str("type", p.TaskType).
 			Str("user_id", p.UserID).
 			Err(err).
@@ -69,17 +95,23 @@ func (j *JobService) handleReminderEmailTask(ctx context.Context, t *asynq.Task)
 		return fmt.Errorf("failed to resolve user email for user %s: %w", p.UserID, err)
 	}
 
+	replyTo := j.replyAddress(ctx, p.UserID, p.TodoID)
+
 	switch p.TaskType {
 	case "due_date_reminder":
 		err = j.emailClient.SendDueDateReminderEmail(
+			j.userLocale(ctx, p.UserID),
 			userEmail,
+			replyTo,
 			p.TodoTitle,
 			p.TodoID,
 			p.DueDate,
 		)
 	case "overdue_notification":
 		err = j.emailClient.SendOverdueNotificationEmail(
+			j.userLocale(ctx, p.UserID),
 			userEmail,
+			replyTo,
 			p.TodoTitle,
 			p.TodoID,
 			p.DueDate,
@@ -89,7 +121,7 @@ func (j *JobService) handleReminderEmailTask(ctx context.Context, t *asynq.Task)
 	}
 
 	if err != nil {
-		j.logger.Error().
+		logger.Error().
 			Str("type", p.TaskType).
 			Str("user_id", p.UserID).
 			Str("todo_id", p.TodoID.String()).
@@ -98,7 +130,9 @@ func (j *JobService) handleReminderEmailTask(ctx context.Context, t *asynq.Task)
 		return err
 	}
 
-	j.logger.Info().
+	j.usage.Record(p.UserID, "", usage.MetricNotifications, 1)
+
+	logger.Info().
 		Str("type", p.TaskType).
 		Str("user_id", p.UserID).
 		Str("todo_id", p.TodoID.String()).
@@ -107,12 +141,14 @@ func (j *JobService) handleReminderEmailTask(ctx context.Context, t *asynq.Task)
 }
 
 func (j *JobService) handleWeeklyReportEmailTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
 	var p WeeklyReportEmailTask
 	if err := json.Unmarshal(t.Payload(), &p); err != nil {
 		return fmt.Errorf("failed to unmarshal weekly report email payload: %w", err)
 	}
 
-	j.logger.Info().
+	logger.Info().
 		Str("type", "weekly_report").
 		Str("user_id", p.UserID).
 		Int("completed_count", p.CompletedCount).
@@ -122,7 +158,7 @@ func (j *JobService) handleWeeklyReportEmailTask(ctx context.Context, t *asynq.T
 
 	userEmail, err := j.authService.GetUserEmail(ctx, p.UserID)
 	if err != nil {
-		j.logger.Error().
+		logger.Error().
 			Str("type", "weekly_report").
 			Str("user_id", p.UserID).
 			Err(err).
@@ -131,6 +167,7 @@ func (j *JobService) handleWeeklyReportEmailTask(ctx context.Context, t *asynq.T
 	}
 
 	err = j.emailClient.SendWeeklyReportEmail(
+		j.userLocale(ctx, p.UserID),
 		userEmail,
 		p.WeekStart,
 		p.WeekEnd,
@@ -141,7 +178,7 @@ func (j *JobService) handleWeeklyReportEmailTask(ctx context.Context, t *asynq.T
 		p.OverdueTodos,
 	)
 	if err != nil {
-		j.logger.Error().
+		logger.Error().
 			Str("type", "weekly_report").
 			Str("user_id", p.UserID).
 			Err(err).
@@ -149,9 +186,979 @@ func (j *JobService) handleWeeklyReportEmailTask(ctx context.Context, t *asynq.T
 		return err
 	}
 
-	j.logger.Info().
+	j.usage.Record(p.UserID, "", usage.MetricNotifications, 1)
+
+	logger.Info().
 		Str("type", "weekly_report").
 		Str("user_id", p.UserID).
 		Msg("Successfully sent weekly report email")
 	return nil
 }
+
+func (j *JobService) handleAccountExportTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p AccountExportTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal account export payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "account_export").
+		Str("user_id", p.UserID).
+		Str("export_id", p.ExportID.String()).
+		Msg("Processing account export task")
+
+	result, err := j.exporter.ExportAccount(ctx, p.UserID, p.ExportID)
+	if err != nil {
+		logger.Error().
+			Str("type", "account_export").
+			Str("user_id", p.UserID).
+			Str("export_id", p.ExportID.String()).
+			Err(err).
+			Msg("Failed to build account export")
+
+		// The user is left waiting otherwise, the export is failed once retries run out
+		retryCount, _ := asynq.GetRetryCount(ctx)
+		maxRetry, _ := asynq.GetMaxRetry(ctx)
+		if retryCount >= maxRetry {
+			if failErr := j.exporter.FailAccountExport(ctx, p.ExportID, "The export could not be built, request a new one"); failErr != nil {
+				logger.Error().
+					Str("export_id", p.ExportID.String()).
+					Err(failErr).
+					Msg("Failed to mark account export as failed")
+			}
+		}
+		return err
+	}
+
+	if result.DownloadURL == nil {
+		// Nothing to send, the archive expired before a retry got here
+		return nil
+	}
+
+	userEmail, err := j.authService.GetUserEmail(ctx, p.UserID)
+	if err != nil {
+		logger.Error().
+			Str("type", "account_export").
+			Str("user_id", p.UserID).
+			Err(err).
+			Msg("Failed to resolve user email")
+		return fmt.Errorf("failed to resolve user email for user %s: %w", p.UserID, err)
+	}
+
+	// Retrying after a failed send reuses the stored archive
+	err = j.emailClient.SendAccountExportReadyEmail(
+		j.userLocale(ctx, p.UserID),
+		userEmail,
+		*result.DownloadURL,
+		*result.SizeBytes,
+		*result.LinkExpiresAt,
+		*result.ExpiresAt,
+	)
+	if err != nil {
+		logger.Error().
+			Str("type", "account_export").
+			Str("user_id", p.UserID).
+			Str("export_id", p.ExportID.String()).
+			Err(err).
+			Msg("Failed to send account export email")
+		return err
+	}
+
+	j.usage.Record(p.UserID, "", usage.MetricNotifications, 1)
+
+	logger.Info().
+		Str("type", "account_export").
+		Str("user_id", p.UserID).
+		Str("export_id", p.ExportID.String()).
+		Int64("size_bytes", *result.SizeBytes).
+		Msg("Successfully completed account export")
+	return nil
+}
+
+func (j *JobService) handleTodoListExportTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p TodoListExportTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal todo list export payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "todo_list_export").
+		Str("user_id", p.UserID).
+		Str("export_id", p.ExportID.String()).
+		Msg("Processing todo list export task")
+
+	result, err := j.exporter.ExportTodoList(ctx, p.UserID, p.ExportID)
+	if err != nil {
+		logger.Error().
+			Str("type", "todo_list_export").
+			Str("user_id", p.UserID).
+			Str("export_id", p.ExportID.String()).
+			Err(err).
+			Msg("Failed to build todo list export")
+
+		if lastAttempt(ctx) {
+			if failErr := j.exporter.FailAccountExport(ctx, p.ExportID, "The PDF could not be built, request a new one"); failErr != nil {
+				logger.Error().
+					Str("export_id", p.ExportID.String()).
+					Err(failErr).
+					Msg("Failed to mark todo list export as failed")
+			}
+		}
+		return err
+	}
+
+	if result.DownloadURL == nil {
+		// Nothing to send, the PDF expired before a retry got here
+		return nil
+	}
+
+	userEmail, err := j.authService.GetUserEmail(ctx, p.UserID)
+	if err != nil {
+		logger.Error().
+			Str("type", "todo_list_export").
+			Str("user_id", p.UserID).
+			Err(err).
+			Msg("Failed to resolve user email")
+		return fmt.Errorf("failed to resolve user email for user %s: %w", p.UserID, err)
+	}
+
+	err = j.emailClient.SendTodoListExportReadyEmail(
+		j.userLocale(ctx, p.UserID),
+		userEmail,
+		*result.DownloadURL,
+		*result.SizeBytes,
+		*result.LinkExpiresAt,
+		*result.ExpiresAt,
+	)
+	if err != nil {
+		logger.Error().
+			Str("type", "todo_list_export").
+			Str("user_id", p.UserID).
+			Str("export_id", p.ExportID.String()).
+			Err(err).
+			Msg("Failed to send todo list export email")
+		return err
+	}
+
+	j.usage.Record(p.UserID, "", usage.MetricNotifications, 1)
+
+	logger.Info().
+		Str("type", "todo_list_export").
+		Str("user_id", p.UserID).
+		Str("export_id", p.ExportID.String()).
+		Int64("size_bytes", *result.SizeBytes).
+		Msg("Successfully completed todo list export")
+	return nil
+}
+
+func (j *JobService) handleWorkspaceBackupTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p WorkspaceBackupTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal workspace backup payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "workspace_backup").
+		Str("backup_id", p.BackupID.String()).
+		Msg("Processing workspace backup task")
+
+	if err := j.backups.RunBackup(ctx, p.BackupID); err != nil {
+		logger.Error().
+			Str("type", "workspace_backup").
+			Str("backup_id", p.BackupID.String()).
+			Err(err).
+			Msg("Failed to build workspace backup")
+
+		// The operator is left waiting otherwise, the backup is failed once retries run out
+		if lastAttempt(ctx) {
+			if failErr := j.backups.FailBackup(ctx, p.BackupID, "The backup could not be built, request a new one"); failErr != nil {
+				logger.Error().
+					Str("backup_id", p.BackupID.String()).
+					Err(failErr).
+					Msg("Failed to mark workspace backup as failed")
+			}
+		}
+		return err
+	}
+
+	logger.Info().
+		Str("type", "workspace_backup").
+		Str("backup_id", p.BackupID.String()).
+		Msg("Successfully completed workspace backup")
+	return nil
+}
+
+func (j *JobService) handleWorkspaceRestoreTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p WorkspaceRestoreTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal workspace restore payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "workspace_restore").
+		Str("restore_id", p.RestoreID.String()).
+		Msg("Processing workspace restore task")
+
+	if err := j.backups.RunRestore(ctx, p.RestoreID); err != nil {
+		logger.Error().
+			Str("type", "workspace_restore").
+			Str("restore_id", p.RestoreID.String()).
+			Err(err).
+			Msg("Failed to restore workspace backup")
+
+		if lastAttempt(ctx) {
+			if failErr := j.backups.FailRestore(ctx, p.RestoreID, "The backup could not be restored, request a new restore"); failErr != nil {
+				logger.Error().
+					Str("restore_id", p.RestoreID.String()).
+					Err(failErr).
+					Msg("Failed to mark workspace restore as failed")
+			}
+		}
+		return err
+	}
+
+	logger.Info().
+		Str("type", "workspace_restore").
+		Str("restore_id", p.RestoreID.String()).
+		Msg("Successfully completed workspace restore")
+	return nil
+}
+
+func (j *JobService) handleRuleEvaluationTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p RuleEvaluationTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal rule evaluation payload: %w", err)
+	}
+
+	logger.Debug().
+		Str("type", "rule_evaluation").
+		Str("user_id", p.Event.UserID).
+		Str("trigger", string(p.Event.Trigger)).
+		Str("todo_id", p.Event.TodoID.String()).
+		Int("depth", p.Event.Depth).
+		Msg("Processing rule evaluation task")
+
+	if err := j.rules.EvaluateRules(ctx, &p.Event); err != nil {
+		logger.Error().
+			Str("type", "rule_evaluation").
+			Str("user_id", p.Event.UserID).
+			Str("todo_id", p.Event.TodoID.String()).
+			Err(err).
+			Msg("Failed to evaluate rules")
+		return err
+	}
+
+	return nil
+}
+
+func (j *JobService) handleRuleBatchEvaluationTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p RuleBatchEvaluationTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal rule batch evaluation payload: %w", err)
+	}
+
+	logger.Debug().
+		Str("type", "rule_batch_evaluation").
+		Str("user_id", p.UserID).
+		Int("event_count", len(p.Events)).
+		Msg("Processing rule batch evaluation task")
+
+	if err := j.rules.EvaluateBatch(ctx, p.UserID, p.Events); err != nil {
+		logger.Error().
+			Str("type", "rule_batch_evaluation").
+			Str("user_id", p.UserID).
+			Err(err).
+			Msg("Failed to evaluate rules")
+		return err
+	}
+
+	return nil
+}
+
+func (j *JobService) handleRuleNotificationEmailTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p RuleNotificationEmailTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal rule notification email payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "rule_notification").
+		Str("user_id", p.UserID).
+		Str("todo_id", p.TodoID.String()).
+		Msg("Processing rule notification email task")
+
+	userEmail, err := j.authService.GetUserEmail(ctx, p.UserID)
+	if err != nil {
+		logger.Error().
+			Str("type", "rule_notification").
+			Str("user_id", p.UserID).
+			Err(err).
+			Msg("Failed to resolve user email")
+		return fmt.Errorf("failed to resolve user email for user %s: %w", p.UserID, err)
+	}
+
+	err = j.emailClient.SendRuleNotificationEmail(
+		j.userLocale(ctx, p.UserID),
+		userEmail,
+		j.replyAddress(ctx, p.UserID, p.TodoID),
+		p.RuleName,
+		p.TodoTitle,
+		p.TodoID,
+		p.Message,
+	)
+	if err != nil {
+		logger.Error().
+			Str("type", "rule_notification").
+			Str("user_id", p.UserID).
+			Str("todo_id", p.TodoID.String()).
+			Err(err).
+			Msg("Failed to send rule notification email")
+		return err
+	}
+
+	j.usage.Record(p.UserID, "", usage.MetricNotifications, 1)
+
+	logger.Info().
+		Str("type", "rule_notification").
+		Str("user_id", p.UserID).
+		Str("todo_id", p.TodoID.String()).
+		Msg("Successfully sent rule notification email")
+	return nil
+}
+
+func (j *JobService) handleRuleDigestEmailTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p RuleDigestEmailTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal rule digest email payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "rule_digest").
+		Str("user_id", p.UserID).
+		Int("notice_count", len(p.Notices)).
+		Msg("Processing rule digest email task")
+
+	userEmail, err := j.authService.GetUserEmail(ctx, p.UserID)
+	if err != nil {
+		logger.Error().
+			Str("type", "rule_digest").
+			Str("user_id", p.UserID).
+			Err(err).
+			Msg("Failed to resolve user email")
+		return fmt.Errorf("failed to resolve user email for user %s: %w", p.UserID, err)
+	}
+
+	if err := j.emailClient.SendRuleDigestEmail(j.userLocale(ctx, p.UserID), userEmail, p.Notices); err != nil {
+		logger.Error().
+			Str("type", "rule_digest").
+			Str("user_id", p.UserID).
+			Err(err).
+			Msg("Failed to send rule digest email")
+		return err
+	}
+
+	j.usage.Record(p.UserID, "", usage.MetricNotifications, 1)
+
+	logger.Info().
+		Str("type", "rule_digest").
+		Str("user_id", p.UserID).
+		Msg("Successfully sent rule digest email")
+	return nil
+}
+
+func (j *JobService) handleBadgeEvaluationTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p BadgeEvaluationTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal badge evaluation payload: %w", err)
+	}
+
+	logger.Debug().
+		Str("type", "badge_evaluation").
+		Str("user_id", p.UserID).
+		Msg("Processing badge evaluation task")
+
+	if err := j.badges.EvaluateBadges(ctx, p.UserID); err != nil {
+		logger.Error().
+			Str("type", "badge_evaluation").
+			Str("user_id", p.UserID).
+			Err(err).
+			Msg("Failed to evaluate badges")
+		return err
+	}
+
+	return nil
+}
+
+func (j *JobService) handleBadgeAwardedEmailTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p BadgeAwardedEmailTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal badge awarded email payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "badge_awarded").
+		Str("user_id", p.UserID).
+		Str("badge", p.BadgeName).
+		Msg("Processing badge awarded email task")
+
+	userEmail, err := j.authService.GetUserEmail(ctx, p.UserID)
+	if err != nil {
+		logger.Error().
+			Str("type", "badge_awarded").
+			Str("user_id", p.UserID).
+			Err(err).
+			Msg("Failed to resolve user email")
+		return fmt.Errorf("failed to resolve user email for user %s: %w", p.UserID, err)
+	}
+
+	locale := j.userLocale(ctx, p.UserID)
+	if err := j.emailClient.SendBadgeAwardedEmail(locale, userEmail, p.BadgeName, p.BadgeDescription); err != nil {
+		logger.Error().
+			Str("type", "badge_awarded").
+			Str("user_id", p.UserID).
+			Err(err).
+			Msg("Failed to send badge awarded email")
+		return err
+	}
+
+	j.usage.Record(p.UserID, "", usage.MetricNotifications, 1)
+
+	logger.Info().
+		Str("type", "badge_awarded").
+		Str("user_id", p.UserID).
+		Str("badge", p.BadgeName).
+		Msg("Successfully sent badge awarded email")
+	return nil
+}
+
+func (j *JobService) handleAttachmentTranscriptionTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p AttachmentTranscriptionTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal attachment transcription payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "attachment_transcription").
+		Str("user_id", p.UserID).
+		Str("attachment_id", p.AttachmentID.String()).
+		Msg("Processing attachment transcription task")
+
+	if err := j.transcriber.TranscribeAttachment(ctx, p.UserID, p.TodoID, p.AttachmentID); err != nil {
+		logger.Error().
+			Str("type", "attachment_transcription").
+			Str("attachment_id", p.AttachmentID.String()).
+			Err(err).
+			Msg("Failed to transcribe attachment")
+
+		// The attachment would show as pending forever otherwise
+		if lastAttempt(ctx) {
+			if failErr := j.transcriber.FailTranscription(ctx, p.TodoID, p.AttachmentID); failErr != nil {
+				logger.Error().
+					Str("attachment_id", p.AttachmentID.String()).
+					Err(failErr).
+					Msg("Failed to mark attachment transcription as failed")
+			}
+		}
+		return err
+	}
+
+	logger.Info().
+		Str("type", "attachment_transcription").
+		Str("attachment_id", p.AttachmentID.String()).
+		Msg("Successfully transcribed attachment")
+	return nil
+}
+
+func (j *JobService) handleCommentEmailReplyTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p CommentEmailReplyTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal comment email reply payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "comment_reply").
+		Str("message_id", p.MessageID).
+		Msg("Processing comment email reply task")
+
+	if err := j.replies.PostEmailReply(ctx, &p); err != nil {
+		logger.Error().
+			Str("type", "comment_reply").
+			Str("message_id", p.MessageID).
+			Err(err).
+			Msg("Failed to post comment email reply")
+		return err
+	}
+
+	return nil
+}
+
+func (j *JobService) handleLinkPreviewTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p LinkPreviewTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal link preview payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "link_preview").
+		Int("url_count", len(p.URLs)).
+		Msg("Processing link preview task")
+
+	if err := j.unfurler.UnfurlLinks(ctx, p.URLs); err != nil {
+		logger.Error().
+			Str("type", "link_preview").
+			Err(err).
+			Msg("Failed to unfurl links")
+		return err
+	}
+
+	return nil
+}
+
+func (j *JobService) handleAgingPolicyEvaluationTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p AgingPolicyEvaluationTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal aging policy evaluation payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "aging_policies").
+		Str("workspace_id", p.WorkspaceID).
+		Msg("Processing aging policy evaluation task")
+
+	if err := j.aging.EnforceAgingPolicies(ctx, p.WorkspaceID); err != nil {
+		logger.Error().
+			Str("type", "aging_policies").
+			Str("workspace_id", p.WorkspaceID).
+			Err(err).
+			Msg("Failed to enforce aging policies")
+		return err
+	}
+
+	return nil
+}
+
+func (j *JobService) handleWorkspacePurgeTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p WorkspacePurgeTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal workspace purge payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "workspace_purge").
+		Str("workspace_id", p.WorkspaceID).
+		Msg("Processing workspace purge task")
+
+	if err := j.purger.PurgeWorkspace(ctx, p.WorkspaceID); err != nil {
+		logger.Error().
+			Str("type", "workspace_purge").
+			Str("workspace_id", p.WorkspaceID).
+			Err(err).
+			Msg("Failed to purge workspace")
+		return err
+	}
+
+	return nil
+}
+
+func (j *JobService) handleSLABreachEmailTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p SLABreachEmailTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal sla breach email payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "sla_breach").
+		Str("user_id", p.UserID).
+		Str("todo_id", p.TodoID.String()).
+		Msg("Processing sla breach email task")
+
+	userEmail, err := j.authService.GetUserEmail(ctx, p.UserID)
+	if err != nil {
+		logger.Error().
+			Str("type", "sla_breach").
+			Str("user_id", p.UserID).
+			Err(err).
+			Msg("Failed to resolve user email")
+		return fmt.Errorf("failed to resolve user email for user %s: %w", p.UserID, err)
+	}
+
+	err = j.emailClient.SendSLABreachEmail(
+		j.userLocale(ctx, p.UserID),
+		userEmail,
+		j.replyAddress(ctx, p.UserID, p.TodoID),
+		p.PolicyName,
+		p.MaxAgeHours,
+		p.TodoTitle,
+		p.TodoID,
+		p.OpenedAt,
+	)
+	if err != nil {
+		logger.Error().
+			Str("type", "sla_breach").
+			Str("user_id", p.UserID).
+			Str("todo_id", p.TodoID.String()).
+			Err(err).
+			Msg("Failed to send sla breach email")
+		return err
+	}
+
+	j.usage.Record(p.UserID, "", usage.MetricNotifications, 1)
+
+	logger.Info().
+		Str("type", "sla_breach").
+		Str("user_id", p.UserID).
+		Str("todo_id", p.TodoID.String()).
+		Msg("Successfully sent sla breach email")
+	return nil
+}
+
+func (j *JobService) handleCoverRenderTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p CoverRenderTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal cover render payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "cover_render").
+		Str("user_id", p.UserID).
+		Str("todo_id", p.TodoID.String()).
+		Msg("Processing cover render task")
+
+	if err := j.covers.RenderCover(ctx, p.UserID, p.TodoID, p.Generation); err != nil {
+		logger.Error().
+			Str("type", "cover_render").
+			Str("todo_id", p.TodoID.String()).
+			Err(err).
+			Msg("Failed to render cover")
+
+		// The cover would show as pending forever otherwise
+		if lastAttempt(ctx) {
+			if failErr := j.covers.FailCover(ctx, p.TodoID, p.Generation); failErr != nil {
+				logger.Error().
+					Str("todo_id", p.TodoID.String()).
+					Err(failErr).
+					Msg("Failed to mark cover as failed")
+			}
+		}
+		return err
+	}
+
+	logger.Info().
+		Str("type", "cover_render").
+		Str("todo_id", p.TodoID.String()).
+		Msg("Successfully rendered cover")
+	return nil
+}
+
+func (j *JobService) handleAttachmentProcessingTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p AttachmentProcessingTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal attachment processing payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "attachment_processing").
+		Str("todo_id", p.TodoID.String()).
+		Str("attachment_id", p.AttachmentID.String()).
+		Msg("Processing attachment processing task")
+
+	if err := j.processor.ProcessAttachment(ctx, p.TodoID, p.AttachmentID); err != nil {
+		logger.Error().
+			Str("type", "attachment_processing").
+			Str("attachment_id", p.AttachmentID.String()).
+			Err(err).
+			Msg("Failed to process attachment")
+		return err
+	}
+
+	logger.Info().
+		Str("type", "attachment_processing").
+		Str("attachment_id", p.AttachmentID.String()).
+		Msg("Successfully processed attachment")
+	return nil
+}
+
+func (j *JobService) handleAttachmentScanTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p AttachmentScanTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal attachment scan payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "attachment_scan").
+		Str("user_id", p.UserID).
+		Str("attachment_id", p.AttachmentID.String()).
+		Msg("Processing attachment scan task")
+
+	if err := j.scanner.ScanAttachment(ctx, p.UserID, p.TodoID, p.AttachmentID); err != nil {
+		logger.Error().
+			Str("type", "attachment_scan").
+			Str("attachment_id", p.AttachmentID.String()).
+			Err(err).
+			Msg("Failed to scan attachment")
+
+		// The attachment would show as pending forever otherwise, it stays quarantined
+		if lastAttempt(ctx) {
+			if failErr := j.scanner.FailScan(ctx, p.TodoID, p.AttachmentID); failErr != nil {
+				logger.Error().
+					Str("attachment_id", p.AttachmentID.String()).
+					Err(failErr).
+					Msg("Failed to mark attachment scan as failed")
+			}
+		}
+		return err
+	}
+
+	logger.Info().
+		Str("type", "attachment_scan").
+		Str("attachment_id", p.AttachmentID.String()).
+		Msg("Successfully scanned attachment")
+	return nil
+}
+
+func (j *JobService) handleAttachmentBlockedEmailTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p AttachmentBlockedEmailTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal attachment blocked email payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "attachment_blocked").
+		Str("user_id", p.UserID).
+		Str("todo_id", p.TodoID.String()).
+		Msg("Processing attachment blocked email task")
+
+	userEmail, err := j.authService.GetUserEmail(ctx, p.UserID)
+	if err != nil {
+		logger.Error().
+			Str("type", "attachment_blocked").
+			Str("user_id", p.UserID).
+			Err(err).
+			Msg("Failed to resolve user email")
+		return fmt.Errorf("failed to resolve user email for user %s: %w", p.UserID, err)
+	}
+
+	err = j.emailClient.SendAttachmentBlockedEmail(j.userLocale(ctx, p.UserID), userEmail, p.FileName, p.TodoTitle,
+		p.TodoID, p.Signature)
+	if err != nil {
+		logger.Error().
+			Str("type", "attachment_blocked").
+			Str("user_id", p.UserID).
+			Str("todo_id", p.TodoID.String()).
+			Err(err).
+			Msg("Failed to send attachment blocked email")
+		return err
+	}
+
+	j.usage.Record(p.UserID, "", usage.MetricNotifications, 1)
+
+	logger.Info().
+		Str("type", "attachment_blocked").
+		Str("user_id", p.UserID).
+		Str("todo_id", p.TodoID.String()).
+		Msg("Successfully sent attachment blocked email")
+	return nil
+}
+
+func (j *JobService) handleReportRunTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p ReportRunTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal report run payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "report_run").
+		Str("user_id", p.UserID).
+		Str("schedule_id", p.ScheduleID.String()).
+		Msg("Processing report run task")
+
+	if err := j.reports.RunReport(ctx, p.UserID, p.ScheduleID, p.RunAt); err != nil {
+		logger.Error().
+			Str("type", "report_run").
+			Str("user_id", p.UserID).
+			Str("schedule_id", p.ScheduleID.String()).
+			Err(err).
+			Msg("Failed to run report")
+
+		// The schedule shows why its last run failed, the next run is tried as usual
+		if lastAttempt(ctx) {
+			if failErr := j.reports.FailReport(ctx, p.ScheduleID, p.RunAt, "The report could not be built"); failErr != nil {
+				logger.Error().
+					Str("schedule_id", p.ScheduleID.String()).
+					Err(failErr).
+					Msg("Failed to record failed report run")
+			}
+		}
+		return err
+	}
+
+	logger.Info().
+		Str("type", "report_run").
+		Str("user_id", p.UserID).
+		Str("schedule_id", p.ScheduleID.String()).
+		Msg("Successfully ran report")
+	return nil
+}
+
+func (j *JobService) handleScheduledReportEmailTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p ScheduledReportEmailTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal scheduled report email payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "scheduled_report").
+		Str("user_id", p.UserID).
+		Str("schedule_id", p.ScheduleID.String()).
+		Msg("Processing scheduled report email task")
+
+	// Recipients are told who sends them the report
+	ownerEmail, err := j.authService.GetUserEmail(ctx, p.UserID)
+	if err != nil {
+		logger.Error().
+			Str("type", "scheduled_report").
+			Str("user_id", p.UserID).
+			Err(err).
+			Msg("Failed to resolve user email")
+		return fmt.Errorf("failed to resolve user email for user %s: %w", p.UserID, err)
+	}
+
+	err = j.emailClient.SendScheduledReportEmail(
+		j.userLocale(ctx, p.UserID),
+		p.To,
+		ownerEmail,
+		p.ReportName,
+		p.Format,
+		p.DownloadURL,
+		p.SizeBytes,
+		p.LinkExpiresAt,
+		p.ExpiresAt,
+	)
+	if err != nil {
+		logger.Error().
+			Str("type", "scheduled_report").
+			Str("user_id", p.UserID).
+			Str("schedule_id", p.ScheduleID.String()).
+			Err(err).
+			Msg("Failed to send scheduled report email")
+		return err
+	}
+
+	j.usage.Record(p.UserID, "", usage.MetricNotifications, 1)
+
+	logger.Info().
+		Str("type", "scheduled_report").
+		Str("user_id", p.UserID).
+		Str("schedule_id", p.ScheduleID.String()).
+		Msg("Successfully sent scheduled report email")
+	return nil
+}
+
+func (j *JobService) handleWebhookDeliveryTask(ctx context.Context, t *asynq.Task) error {
+	logger := j.taskLogger(ctx)
+
+	var p WebhookDeliveryTask
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal webhook delivery payload: %w", err)
+	}
+
+	logger.Info().
+		Str("type", "webhook_delivery").
+		Str("workspace_id", p.WorkspaceID).
+		Str("endpoint_id", p.EndpointID.String()).
+		Str("event", p.Event).
+		Str("delivery_id", p.DeliveryID.String()).
+		Msg("Processing webhook delivery task")
+
+	if err := j.webhooks.DeliverWebhook(ctx, &p); err != nil {
+		logger.Error().
+			Str("type", "webhook_delivery").
+			Str("endpoint_id", p.EndpointID.String()).
+			Str("delivery_id", p.DeliveryID.String()).
+			Bool("last_attempt", lastAttempt(ctx)).
+			Err(err).
+			Msg("Failed to deliver webhook")
+		return err
+	}
+
+	return nil
+}
+
+// replyAddress is the reply address of an email about a todo. Failing to get one only costs
+// the recipient the option to reply, the email goes out without it.
+func (j *JobService) replyAddress(ctx context.Context, userID string, todoID uuid.UUID) string {
+	if j.replies == nil {
+		return ""
+	}
+
+	address, err := j.replies.ReplyAddress(ctx, userID, todoID)
+	if err != nil {
+		j.taskLogger(ctx).Warn().
+			Str("user_id", userID).
+			Str("todo_id", todoID.String()).
+			Err(err).
+			Msg("Failed to issue reply address, sending without it")
+		return ""
+	}
+
+	return address
+}
+
+// userLocale is the locale of the emails to the user, the default before a resolver is set
+func (j *JobService) userLocale(ctx context.Context, userID string) language.Tag {
+	if j.locales == nil {
+		return i18n.Default
+	}
+	return j.locales.UserLocale(ctx, userID)
+}
+
+// lastAttempt tells whether a failing task won't be retried
+func lastAttempt(ctx context.Context) bool {
+	retryCount, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	return retryCount >= maxRetry
+}
@@ -2,25 +2,162 @@ package job
 
 import (
 	"context"
+	"time"
 
 	"github.com/Sameer16536/ExecuTask/internal/config"
 	"github.com/Sameer16536/ExecuTask/internal/lib/email"
+	"github.com/Sameer16536/ExecuTask/internal/lib/metering"
+	"github.com/Sameer16536/ExecuTask/internal/model/export"
+	"github.com/Sameer16536/ExecuTask/internal/model/rule"
+	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 	"github.com/rs/zerolog"
+	"golang.org/x/text/language"
 )
 
 type JobService struct {
 	Client      *asynq.Client
+	Inspector   *asynq.Inspector
 	server      *asynq.Server
 	logger      *zerolog.Logger
 	authService AuthServiceInterface
+	exporter    AccountExporter
+	backups     WorkspaceBackups
+	rules       RuleEvaluator
+	badges      BadgeEvaluator
+	transcriber Transcriber
+	replies     CommentReplies
+	unfurler    LinkUnfurler
+	aging       AgingPolicyEnforcer
+	purger      WorkspacePurger
+	covers      CoverRenderer
+	processor   AttachmentProcessor
+	scanner     AttachmentScanner
+	locales     LocaleResolver
+	reports     ReportRunner
+	webhooks    WebhookDeliverer
 	emailClient *email.Client
+	usage       *metering.Meter
 }
 
 type AuthServiceInterface interface {
 	GetUserEmail(ctx context.Context, userID string) (string, error)
 }
 
+// AccountExporter builds account archives and todo list PDFs, the export service implements it
+type AccountExporter interface {
+	// ExportAccount builds and stores the archive, an export finished before is returned as is.
+	// The result carries a fresh download link.
+	ExportAccount(ctx context.Context, userID string, exportID uuid.UUID) (*export.AccountExport, error)
+	// ExportTodoList prints and stores the PDF, like ExportAccount
+	ExportTodoList(ctx context.Context, userID string, exportID uuid.UUID) (*export.AccountExport, error)
+	FailAccountExport(ctx context.Context, exportID uuid.UUID, reason string) error
+}
+
+// WorkspaceBackups runs workspace backups and restores, the backup service implements it
+type WorkspaceBackups interface {
+	// RunBackup stores the archive of a backup, a backup finished before is left as is
+	RunBackup(ctx context.Context, backupID uuid.UUID) error
+	FailBackup(ctx context.Context, backupID uuid.UUID, reason string) error
+	// RunRestore recreates the data of a backup, a restore finished before is left as is
+	RunRestore(ctx context.Context, restoreID uuid.UUID) error
+	FailRestore(ctx context.Context, restoreID uuid.UUID, reason string) error
+}
+
+// RuleEvaluator runs the automation rules of a user against a change of a todo, the rule
+// service implements it
+type RuleEvaluator interface {
+	EvaluateRules(ctx context.Context, event *rule.Event) error
+	EvaluateBatch(ctx context.Context, userID string, events []rule.Event) error
+}
+
+// BadgeEvaluator awards the badges a user earned since they were last checked, the
+// gamification service implements it
+type BadgeEvaluator interface {
+	EvaluateBadges(ctx context.Context, userID string) error
+}
+
+// Transcriber transcribes voice note attachments into their todo, the transcription service
+// implements it
+type Transcriber interface {
+	// TranscribeAttachment does nothing for attachments no longer pending
+	TranscribeAttachment(ctx context.Context, userID string, todoID, attachmentID uuid.UUID) error
+	FailTranscription(ctx context.Context, todoID, attachmentID uuid.UUID) error
+}
+
+// CommentReplies lets the recipients of the notification emails about a todo comment on it by
+// replying, the comment service implements it
+type CommentReplies interface {
+	// ReplyAddress returns the address replies to an email about the todo go to, empty when
+	// email replies are disabled
+	ReplyAddress(ctx context.Context, userID string, todoID uuid.UUID) (string, error)
+	PostEmailReply(ctx context.Context, reply *CommentEmailReplyTask) error
+}
+
+// LinkUnfurler fetches the previews of links found in descriptions and comments, the preview
+// service implements it
+type LinkUnfurler interface {
+	// UnfurlLinks stores a preview of every URL, a page that can't be previewed is stored as failed
+	UnfurlLinks(ctx context.Context, urls []string) error
+}
+
+// AgingPolicyEnforcer escalates the todos breaching the aging policies of a workspace, the
+// workspace service implements it
+type AgingPolicyEnforcer interface {
+	EnforceAgingPolicies(ctx context.Context, workspaceID string) error
+}
+
+// WorkspacePurger deletes the data of frozen workspaces, the workspace service implements it
+type WorkspacePurger interface {
+	// PurgeWorkspace deletes the data of a workspace due for purging, one reactivated or purged
+	// before is left as is
+	PurgeWorkspace(ctx context.Context, workspaceID string) error
+}
+
+// CoverRenderer renders the thumbnails of todo covers, the cover service implements it
+type CoverRenderer interface {
+	// RenderCover does nothing for a cover replaced or removed since the generation was set
+	RenderCover(ctx context.Context, userID string, todoID, generation uuid.UUID) error
+	FailCover(ctx context.Context, todoID, generation uuid.UUID) error
+}
+
+// AttachmentProcessor strips the metadata of image attachments and renders their thumbnails,
+// the attachment processing service implements it
+type AttachmentProcessor interface {
+	// ProcessAttachment does nothing for attachments deleted or processed before
+	ProcessAttachment(ctx context.Context, todoID, attachmentID uuid.UUID) error
+}
+
+// AttachmentScanner scans uploaded attachments for viruses, the attachment scan service
+// implements it
+type AttachmentScanner interface {
+	// ScanAttachment does nothing for attachments deleted or scanned before
+	ScanAttachment(ctx context.Context, userID string, todoID, attachmentID uuid.UUID) error
+	FailScan(ctx context.Context, todoID, attachmentID uuid.UUID) error
+}
+
+// LocaleResolver tells which locale to write the emails to a user in, the locale service
+// implements it
+type LocaleResolver interface {
+	UserLocale(ctx context.Context, userID string) language.Tag
+}
+
+// ReportRunner renders the runs of scheduled reports and queues their emails, the report
+// service implements it
+type ReportRunner interface {
+	// RunReport does nothing for schedules deleted or paused since the run was queued
+	RunReport(ctx context.Context, userID string, scheduleID uuid.UUID, runAt time.Time) error
+	FailReport(ctx context.Context, scheduleID uuid.UUID, runAt time.Time, reason string) error
+}
+
+// WebhookDeliverer posts the events of workspaces to their endpoints, the webhook service
+// implements it
+type WebhookDeliverer interface {
+	// DeliverWebhook does nothing for endpoints deleted, disabled or unsubscribed from the event
+	// since the delivery was queued, it fails when the endpoint doesn't accept the delivery
+	DeliverWebhook(ctx context.Context, task *WebhookDeliveryTask) error
+}
+
 func NewJobService(logger *zerolog.Logger, cfg *config.Config) *JobService {
 	redisAddr := cfg.Redis.Address
 
@@ -30,10 +167,23 @@ func NewJobService(logger *zerolog.Logger, cfg *config.Config) *JobService {
 		DB:       0,
 	})
 
+	inspector := asynq.NewInspector(asynq.RedisClientOpt{
+		Addr:     redisAddr,
+		Password: cfg.Redis.Password,
+		DB:       0,
+	})
+
+	shutdownConfig := cfg.Server.Shutdown
+	if shutdownConfig == nil {
+		shutdownConfig = config.DefaultShutdownConfig()
+	}
+
 	server := asynq.NewServer(
 		asynq.RedisClientOpt{Addr: redisAddr, Password: cfg.Redis.Password, DB: 0},
 		asynq.Config{
 			Concurrency: 10,
+			// Tasks still running when it passes go back to their queue and are retried
+			ShutdownTimeout: shutdownConfig.JobTimeout,
 			Queues: map[string]int{
 				"critical": 6, // Higher priority queue for important emails
 				"default":  3, // Default priority for most emails
@@ -43,9 +193,10 @@ func NewJobService(logger *zerolog.Logger, cfg *config.Config) *JobService {
 	)
 
 	return &JobService{
-		Client: client,
-		server: server,
-		logger: logger,
+		Client:    client,
+		Inspector: inspector,
+		server:    server,
+		logger:    logger,
 	}
 }
 
@@ -53,12 +204,101 @@ func (j *JobService) SetAuthService(authService AuthServiceInterface) {
 	j.authService = authService
 }
 
+func (j *JobService) SetAccountExporter(exporter AccountExporter) {
+	j.exporter = exporter
+}
+
+func (j *JobService) SetWorkspaceBackups(backups WorkspaceBackups) {
+	j.backups = backups
+}
+
+func (j *JobService) SetRuleEvaluator(rules RuleEvaluator) {
+	j.rules = rules
+}
+
+func (j *JobService) SetBadgeEvaluator(badges BadgeEvaluator) {
+	j.badges = badges
+}
+
+func (j *JobService) SetTranscriber(transcriber Transcriber) {
+	j.transcriber = transcriber
+}
+
+func (j *JobService) SetCommentReplies(replies CommentReplies) {
+	j.replies = replies
+}
+
+func (j *JobService) SetLinkUnfurler(unfurler LinkUnfurler) {
+	j.unfurler = unfurler
+}
+
+func (j *JobService) SetAgingPolicyEnforcer(aging AgingPolicyEnforcer) {
+	j.aging = aging
+}
+
+func (j *JobService) SetWorkspacePurger(purger WorkspacePurger) {
+	j.purger = purger
+}
+
+func (j *JobService) SetCoverRenderer(covers CoverRenderer) {
+	j.covers = covers
+}
+
+func (j *JobService) SetAttachmentProcessor(processor AttachmentProcessor) {
+	j.processor = processor
+}
+
+func (j *JobService) SetAttachmentScanner(scanner AttachmentScanner) {
+	j.scanner = scanner
+}
+
+func (j *JobService) SetLocaleResolver(locales LocaleResolver) {
+	j.locales = locales
+}
+
+func (j *JobService) SetReportRunner(reports ReportRunner) {
+	j.reports = reports
+}
+
+func (j *JobService) SetWebhookDeliverer(webhooks WebhookDeliverer) {
+	j.webhooks = webhooks
+}
+
+// SetUsageMeter counts sent notifications towards the usage of their recipient
+func (j *JobService) SetUsageMeter(meter *metering.Meter) {
+	j.usage = meter
+}
+
 func (j *JobService) Start() error {
 	// Register task handlers
 	mux := asynq.NewServeMux()
+	mux.Use(tracingMiddleware)
 	mux.HandleFunc(TaskWelcome, j.handleWelcomeEmailTask)
 	mux.HandleFunc(TaskReminderEmail, j.handleReminderEmailTask)
 	mux.HandleFunc(TaskWeeklyReportEmail, j.handleWeeklyReportEmailTask)
+	mux.HandleFunc(TaskAccountExport, j.handleAccountExportTask)
+	mux.HandleFunc(TaskTodoListExport, j.handleTodoListExportTask)
+	mux.HandleFunc(TaskWorkspaceBackup, j.handleWorkspaceBackupTask)
+	mux.HandleFunc(TaskWorkspaceRestore, j.handleWorkspaceRestoreTask)
+	mux.HandleFunc(TaskRuleEvaluation, j.handleRuleEvaluationTask)
+	mux.HandleFunc(TaskRuleBatchEvaluation, j.handleRuleBatchEvaluationTask)
+	mux.HandleFunc(TaskRuleNotificationEmail, j.handleRuleNotificationEmailTask)
+	mux.HandleFunc(TaskRuleDigestEmail, j.handleRuleDigestEmailTask)
+	mux.HandleFunc(TaskBadgeEvaluation, j.handleBadgeEvaluationTask)
+	mux.HandleFunc(TaskBadgeAwardedEmail, j.handleBadgeAwardedEmailTask)
+	mux.HandleFunc(TaskAttachmentTranscription, j.handleAttachmentTranscriptionTask)
+	mux.HandleFunc(TaskCommentEmailReply, j.handleCommentEmailReplyTask)
+	mux.HandleFunc(TaskLinkPreview, j.handleLinkPreviewTask)
+	mux.HandleFunc(TaskAgingPolicyEvaluation, j.handleAgingPolicyEvaluationTask)
+	mux.HandleFunc(TaskSLABreachEmail, j.handleSLABreachEmailTask)
+	mux.HandleFunc(TaskWorkspacePurge, j.handleWorkspacePurgeTask)
+	mux.HandleFunc(TaskCoverRender, j.handleCoverRenderTask)
+	mux.HandleFunc(TaskAttachmentProcessing, j.handleAttachmentProcessingTask)
+	mux.HandleFunc(TaskAttachmentScan, j.handleAttachmentScanTask)
+	mux.HandleFunc(TaskAttachmentBlockedEmail, j.handleAttachmentBlockedEmailTask)
+	mux.HandleFunc(TaskReportRun, j.handleReportRunTask)
+	mux.HandleFunc(TaskScheduledReportEmail, j.handleScheduledReportEmailTask)
+	mux.HandleFunc(TaskWebhookDelivery, j.handleWebhookDeliveryTask)
 
 	j.logger.Info().Msg("Starting background job server")
 	if err := j.server.Start(mux); err != nil {
@@ -68,8 +308,10 @@ func (j *JobService) Start() error {
 	return nil
 }
 
+// Stop waits for running tasks to finish, up to the job shutdown timeout, then closes the clients
 func (j *JobService) Stop() {
 	j.logger.Info().Msg("Stopping background job server")
 	j.server.Shutdown()
 	j.Client.Close()
+	j.Inspector.Close()
 }
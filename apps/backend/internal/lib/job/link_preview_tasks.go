@@ -0,0 +1,29 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const TaskLinkPreview = "link:unfurl"
+
+// LinkPreviewTask holds the URLs found in a description or comment that have no fresh preview
+type LinkPreviewTask struct {
+	TaskMetadata
+	URLs []string `json:"urls"`
+}
+
+func EnqueueLinkPreview(ctx context.Context, client *asynq.Client, task *LinkPreviewTask) error {
+	asynqTask, err := newTask(ctx, TaskLinkPreview, task,
+		asynq.MaxRetry(2),
+		asynq.Queue("low"),
+		asynq.Timeout(2*time.Minute))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	return err
+}
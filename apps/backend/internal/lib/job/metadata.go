@@ -0,0 +1,55 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/correlation"
+	"github.com/hibiken/asynq"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// TaskMetadata travels inside every task payload, asynq tasks have no headers. It ties the
+// task to the request or cron run that enqueued it.
+type TaskMetadata struct {
+	CorrelationID string `json:"correlation_id,omitempty"`
+	// TraceContext is the W3C trace context of the enqueuer, the task span continues its trace
+	TraceContext map[string]string `json:"trace_context,omitempty"`
+}
+
+func (m *TaskMetadata) stamp(ctx context.Context) {
+	if m.CorrelationID == "" {
+		m.CorrelationID = correlation.ID(ctx)
+	}
+
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) > 0 {
+		m.TraceContext = carrier
+	}
+}
+
+type stampedTask interface {
+	stamp(ctx context.Context)
+}
+
+// newTask stamps the payload with the correlation ID and trace of ctx and marshals it
+func newTask(ctx context.Context, typeName string, payload stampedTask, opts ...asynq.Option) (*asynq.Task, error) {
+	payload.stamp(ctx)
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(typeName, data, opts...), nil
+}
+
+// taskMetadata reads the metadata of a task whatever its type, tasks enqueued before it existed
+// have none
+func taskMetadata(t *asynq.Task) TaskMetadata {
+	var metadata TaskMetadata
+	_ = json.Unmarshal(t.Payload(), &metadata)
+	return metadata
+}
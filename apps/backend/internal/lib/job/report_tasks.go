@@ -0,0 +1,76 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskReportRun            = "report:run"
+	TaskScheduledReportEmail = "email:scheduled_report"
+)
+
+// ReportRunTask renders the run of a scheduled report due at RunAt and emails it to the recipients
+type ReportRunTask struct {
+	TaskMetadata
+	UserID     string    `json:"user_id"`
+	ScheduleID uuid.UUID `json:"schedule_id"`
+	RunAt      time.Time `json:"run_at"`
+}
+
+// EnqueueReportRun queues a run of a schedule, a run already queued is not queued twice
+func EnqueueReportRun(ctx context.Context, client *asynq.Client, task *ReportRunTask) error {
+	asynqTask, err := newTask(ctx, TaskReportRun, task,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(15*time.Minute), // Reads every todo with its comments and attachments
+		asynq.TaskID(fmt.Sprintf("report:%s:%d", task.ScheduleID.String(), task.RunAt.Unix())))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
+	return err
+}
+
+// ScheduledReportEmailTask sends the link to a run of a scheduled report to one of its recipients
+type ScheduledReportEmailTask struct {
+	TaskMetadata
+	UserID        string    `json:"user_id"`
+	ScheduleID    uuid.UUID `json:"schedule_id"`
+	RunAt         time.Time `json:"run_at"`
+	To            string    `json:"to"`
+	ReportName    string    `json:"report_name"`
+	Format        string    `json:"format"`
+	DownloadURL   string    `json:"download_url"`
+	SizeBytes     int64     `json:"size_bytes"`
+	LinkExpiresAt time.Time `json:"link_expires_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// EnqueueScheduledReportEmail queues the email of a recipient, a recipient already queued for
+// the run is not queued twice when the run is retried
+func EnqueueScheduledReportEmail(ctx context.Context, client *asynq.Client, task *ScheduledReportEmailTask) error {
+	asynqTask, err := newTask(ctx, TaskScheduledReportEmail, task,
+		asynq.MaxRetry(3),
+		asynq.Queue("default"),
+		asynq.Timeout(30*time.Second),
+		asynq.TaskID(fmt.Sprintf("report-email:%s:%d:%s", task.ScheduleID.String(), task.RunAt.Unix(), task.To)))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,112 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/rule"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskRuleEvaluation        = "rule:evaluate"
+	TaskRuleBatchEvaluation   = "rule:evaluate_batch"
+	TaskRuleNotificationEmail = "email:rule_notification"
+	TaskRuleDigestEmail       = "email:rule_digest"
+)
+
+type RuleEvaluationTask struct {
+	TaskMetadata
+	Event rule.Event `json:"event"`
+	// Delay holds the evaluation back, completions wait out their undo window
+	Delay time.Duration `json:"-"`
+}
+
+func EnqueueRuleEvaluation(ctx context.Context, client *asynq.Client, task *RuleEvaluationTask) error {
+	opts := []asynq.Option{
+		asynq.MaxRetry(3),
+		asynq.Queue("default"),
+		asynq.Timeout(time.Minute),
+	}
+	if task.Delay > 0 {
+		opts = append(opts, asynq.ProcessIn(task.Delay))
+	}
+
+	asynqTask, err := newTask(ctx, TaskRuleEvaluation, task, opts...)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	return err
+}
+
+// RuleBatchEvaluationTask evaluates the events of a bulk change in one go, so their
+// notifications can be coalesced
+type RuleBatchEvaluationTask struct {
+	TaskMetadata
+	UserID string        `json:"user_id"`
+	Events []rule.Event  `json:"events"`
+	Delay  time.Duration `json:"-"`
+}
+
+func EnqueueRuleBatchEvaluation(ctx context.Context, client *asynq.Client, task *RuleBatchEvaluationTask) error {
+	opts := []asynq.Option{
+		asynq.MaxRetry(3),
+		asynq.Queue("default"),
+		asynq.Timeout(5 * time.Minute),
+	}
+	if task.Delay > 0 {
+		opts = append(opts, asynq.ProcessIn(task.Delay))
+	}
+
+	asynqTask, err := newTask(ctx, TaskRuleBatchEvaluation, task, opts...)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	return err
+}
+
+type RuleNotificationEmailTask struct {
+	TaskMetadata
+	UserID    string    `json:"user_id"`
+	RuleName  string    `json:"rule_name"`
+	TodoID    uuid.UUID `json:"todo_id"`
+	TodoTitle string    `json:"todo_title"`
+	Message   *string   `json:"message"`
+}
+
+func EnqueueRuleNotificationEmail(ctx context.Context, client *asynq.Client, task *RuleNotificationEmailTask) error {
+	asynqTask, err := newTask(ctx, TaskRuleNotificationEmail, task,
+		asynq.MaxRetry(3),
+		asynq.Queue("default"),
+		asynq.Timeout(30*time.Second))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	return err
+}
+
+type RuleDigestEmailTask struct {
+	TaskMetadata
+	UserID  string        `json:"user_id"`
+	Notices []rule.Notice `json:"notices"`
+}
+
+func EnqueueRuleDigestEmail(ctx context.Context, client *asynq.Client, task *RuleDigestEmailTask) error {
+	asynqTask, err := newTask(ctx, TaskRuleDigestEmail, task,
+		asynq.MaxRetry(3),
+		asynq.Queue("default"),
+		asynq.Timeout(30*time.Second))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	return err
+}
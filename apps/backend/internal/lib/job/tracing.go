@@ -0,0 +1,70 @@
+package job
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/correlation"
+	"github.com/Sameer16536/ExecuTask/internal/lib/tracing"
+	"github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingMiddleware runs every task in a consumer span named after the task type. The span
+// continues the trace of the enqueuer and the task keeps its correlation ID, tasks without
+// metadata start a trace of their own and are correlated by their task ID.
+func tracingMiddleware(next asynq.Handler) asynq.Handler {
+	return asynq.HandlerFunc(func(ctx context.Context, t *asynq.Task) (err error) {
+		metadata := taskMetadata(t)
+		if metadata.TraceContext != nil {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(metadata.TraceContext))
+		}
+
+		attributes := []attribute.KeyValue{
+			semconv.MessagingSystemKey.String("asynq"),
+			semconv.MessagingOperationTypeProcess,
+		}
+		correlationID := metadata.CorrelationID
+		if taskID, ok := asynq.GetTaskID(ctx); ok {
+			attributes = append(attributes, semconv.MessagingMessageID(taskID))
+			if correlationID == "" {
+				correlationID = taskID
+			}
+		}
+		if correlationID != "" {
+			ctx = correlation.WithID(ctx, correlationID)
+			attributes = append(attributes, attribute.String("correlation.id", correlationID))
+		}
+		if queue, ok := asynq.GetQueueName(ctx); ok {
+			attributes = append(attributes, semconv.MessagingDestinationName(queue))
+		}
+		if retried, ok := asynq.GetRetryCount(ctx); ok {
+			attributes = append(attributes, attribute.String("messaging.asynq.retry_count", strconv.Itoa(retried)))
+		}
+
+		ctx, span := tracing.Tracer().Start(ctx, t.Type(),
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(attributes...),
+		)
+		defer func() {
+			tracing.End(span, err)
+		}()
+
+		return next.ProcessTask(ctx, t)
+	})
+}
+
+// taskLogger returns the job logger with the correlation ID, trace and span of the running task
+func (j *JobService) taskLogger(ctx context.Context) *zerolog.Logger {
+	taskLogger := logger.WithSpanContext(*j.logger, ctx)
+	if correlationID := correlation.ID(ctx); correlationID != "" {
+		taskLogger = taskLogger.With().Str("correlation_id", correlationID).Logger()
+	}
+	return &taskLogger
+}
@@ -0,0 +1,31 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const TaskAttachmentTranscription = "attachment:transcribe"
+
+type AttachmentTranscriptionTask struct {
+	TaskMetadata
+	UserID       string    `json:"user_id"`
+	TodoID       uuid.UUID `json:"todo_id"`
+	AttachmentID uuid.UUID `json:"attachment_id"`
+}
+
+func EnqueueAttachmentTranscription(ctx context.Context, client *asynq.Client, task *AttachmentTranscriptionTask) error {
+	asynqTask, err := newTask(ctx, TaskAttachmentTranscription, task,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(30*time.Minute)) // The provider takes about as long as the recording
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	return err
+}
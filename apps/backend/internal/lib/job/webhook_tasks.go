@@ -0,0 +1,37 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const TaskWebhookDelivery = "webhook:deliver"
+
+// WebhookDeliveryTask posts the payload of an event to one endpoint, signed when it is sent
+type WebhookDeliveryTask struct {
+	TaskMetadata
+	WorkspaceID string          `json:"workspace_id"`
+	EndpointID  uuid.UUID       `json:"endpoint_id"`
+	Event       string          `json:"event"`
+	DeliveryID  uuid.UUID       `json:"delivery_id"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// EnqueueWebhookDelivery retries a delivery the endpoint didn't accept with the backoff of
+// asynq, over about a day
+func EnqueueWebhookDelivery(ctx context.Context, client *asynq.Client, task *WebhookDeliveryTask) error {
+	asynqTask, err := newTask(ctx, TaskWebhookDelivery, task,
+		asynq.MaxRetry(10),
+		asynq.Queue("default"),
+		asynq.Timeout(time.Minute))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.EnqueueContext(ctx, asynqTask)
+	return err
+}
@@ -0,0 +1,105 @@
+package llm
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+)
+
+const (
+	ProviderOpenAI = "openai"
+	ProviderLocal  = "local"
+)
+
+// Subtask is a step a provider proposes, nothing is created until the user confirms it
+type Subtask struct {
+	Title       string
+	Description string
+}
+
+// SubtaskRequest describes the todo to break down
+type SubtaskRequest struct {
+	Title       string
+	Description string
+	// Existing are the titles of the subtasks the todo already has, they aren't proposed again
+	Existing []string
+	Limit    int
+}
+
+// Provider proposes subtask breakdowns of todos
+type Provider interface {
+	// Name is the provider as configured, e.g. openai
+	Name() string
+	// Model identifies what produced the suggestions
+	Model() string
+	// SuggestSubtasks returns at most Limit subtasks, possibly none
+	SuggestSubtasks(ctx context.Context, req SubtaskRequest) ([]Subtask, error)
+}
+
+// NewProvider returns the configured provider, nil when subtask suggestions are disabled
+func NewProvider(cfg *config.LLMConfig, outboundCfg *config.OutboundConfig) Provider {
+	if cfg == nil {
+		return nil
+	}
+
+	defaults := config.DefaultLLMConfig()
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaults.BaseURL
+	}
+	model := cfg.Model
+	if model == "" {
+		model = defaults.Model
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaults.Timeout
+	}
+
+	switch cfg.Provider {
+	case ProviderOpenAI:
+		return NewOpenAIProvider(outboundCfg, baseURL, cfg.APIKey, model, timeout)
+	case ProviderLocal:
+		return NewLocalProvider()
+	default:
+		return nil
+	}
+}
+
+// Clean trims the titles and drops the empty ones, duplicates and existing subtasks, then keeps
+// at most limit subtasks with titles that fit a todo
+func Clean(subtasks []Subtask, existing []string, limit int) []Subtask {
+	seen := map[string]bool{}
+	for _, title := range existing {
+		seen[strings.ToLower(strings.TrimSpace(title))] = true
+	}
+
+	cleaned := []Subtask{}
+	for _, subtask := range subtasks {
+		title := truncate(strings.TrimSpace(subtask.Title), 255)
+		key := strings.ToLower(title)
+		if title == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		cleaned = append(cleaned, Subtask{
+			Title:       title,
+			Description: truncate(strings.TrimSpace(subtask.Description), 1000),
+		})
+		if len(cleaned) == limit {
+			break
+		}
+	}
+
+	return cleaned
+}
+
+func truncate(text string, maxRunes int) string {
+	runes := []rune(text)
+	if len(runes) <= maxRunes {
+		return text
+	}
+	return string(runes[:maxRunes])
+}
@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// listItem matches the bullets, numbers and checkboxes a line of a list starts with
+var listItem = regexp.MustCompile(`^\s*(?:[-*•]|\d+[.)]|\[[ xX]?\])\s+`)
+
+// LocalProvider needs no model: it proposes the items of a list written in the description,
+// or failing that its sentences. It keeps the feature usable offline and in development.
+type LocalProvider struct{}
+
+func NewLocalProvider() *LocalProvider {
+	return &LocalProvider{}
+}
+
+func (p *LocalProvider) Name() string {
+	return ProviderLocal
+}
+
+func (p *LocalProvider) Model() string {
+	return "heuristic"
+}
+
+func (p *LocalProvider) SuggestSubtasks(ctx context.Context, req SubtaskRequest) ([]Subtask, error) {
+	var items, sentences []Subtask
+	for _, line := range strings.Split(req.Description, "\n") {
+		if listItem.MatchString(line) {
+			items = append(items, Subtask{Title: listItem.ReplaceAllString(line, "")})
+			continue
+		}
+		for _, sentence := range splitSentences(line) {
+			if strings.TrimSpace(sentence) != "" {
+				sentences = append(sentences, Subtask{Title: sentence})
+			}
+		}
+	}
+
+	if len(items) > 0 {
+		return Clean(items, req.Existing, req.Limit), nil
+	}
+	// A single sentence is the todo itself rather than a breakdown
+	if len(sentences) < 2 {
+		return []Subtask{}, nil
+	}
+	return Clean(sentences, req.Existing, req.Limit), nil
+}
+
+func splitSentences(text string) []string {
+	sentences := []string{}
+	start := 0
+	for i, r := range text {
+		if r == '.' || r == '!' || r == '?' || r == ';' {
+			sentences = append(sentences, text[start:i])
+			start = i + 1
+		}
+	}
+	return append(sentences, text[start:])
+}
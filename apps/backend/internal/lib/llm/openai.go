@@ -0,0 +1,143 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/lib/outbound"
+)
+
+const subtaskInstructions = `You break a todo down into concrete subtasks. Answer with a JSON object of the form
+{"subtasks": [{"title": "...", "description": "..."}]}. Titles are short imperative steps of at most 255
+characters, descriptions are one sentence or empty. Propose at most %d subtasks, in the order they should be
+done, and none that repeat an existing subtask. Propose none when the todo is a single step.`
+
+// OpenAIProvider calls the chat completions endpoint of the OpenAI API or of a compatible
+// server, which includes self-hosted models served by Ollama or vLLM
+type OpenAIProvider struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+	model   string
+}
+
+func NewOpenAIProvider(outboundCfg *config.OutboundConfig, baseURL, apiKey, model string,
+	timeout time.Duration,
+) *OpenAIProvider {
+	return &OpenAIProvider{
+		client:  outbound.New(outboundCfg, outbound.Options{Timeout: timeout}),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+	}
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIRequest struct {
+	Model          string          `json:"model"`
+	Messages       []openAIMessage `json:"messages"`
+	ResponseFormat struct {
+		Type string `json:"type"`
+	} `json:"response_format"`
+	Temperature float64 `json:"temperature"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+}
+
+type openAISubtasks struct {
+	Subtasks []struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	} `json:"subtasks"`
+}
+
+func (p *OpenAIProvider) Name() string {
+	return ProviderOpenAI
+}
+
+func (p *OpenAIProvider) Model() string {
+	return p.model
+}
+
+func (p *OpenAIProvider) SuggestSubtasks(ctx context.Context, req SubtaskRequest) ([]Subtask, error) {
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Title: %s\n", req.Title)
+	if req.Description != "" {
+		fmt.Fprintf(&prompt, "Description: %s\n", req.Description)
+	}
+	if len(req.Existing) > 0 {
+		fmt.Fprintf(&prompt, "Existing subtasks: %s\n", strings.Join(req.Existing, "; "))
+	}
+
+	payload := openAIRequest{
+		Model: p.model,
+		Messages: []openAIMessage{
+			{Role: "system", Content: fmt.Sprintf(subtaskInstructions, req.Limit)},
+			{Role: "user", Content: prompt.String()},
+		},
+		Temperature: 0.2,
+	}
+	payload.ResponseFormat.Type = "json_object"
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode chat completions request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build chat completions request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	// Proposing subtasks changes nothing, a failed call can be sent again
+	res, err := p.client.Do(outbound.Idempotent(httpReq))
+	if err != nil {
+		return nil, fmt.Errorf("failed to call chat completions endpoint: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return nil, fmt.Errorf("chat completions endpoint returned %d: %s", res.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var decoded openAIResponse
+	if err := json.NewDecoder(res.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode chat completions response: %w", err)
+	}
+	if len(decoded.Choices) == 0 {
+		return nil, fmt.Errorf("chat completions response has no choices")
+	}
+
+	var proposed openAISubtasks
+	if err := json.Unmarshal([]byte(decoded.Choices[0].Message.Content), &proposed); err != nil {
+		return nil, fmt.Errorf("failed to decode proposed subtasks: %w", err)
+	}
+
+	subtasks := make([]Subtask, 0, len(proposed.Subtasks))
+	for _, subtask := range proposed.Subtasks {
+		subtasks = append(subtasks, Subtask{Title: subtask.Title, Description: subtask.Description})
+	}
+
+	// The model is asked to respect these, it isn't trusted to
+	return Clean(subtasks, req.Existing, req.Limit), nil
+}
@@ -0,0 +1,137 @@
+package merge
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Strategy decides what happens to a client write that was made against a stale version
+type Strategy string
+
+const (
+	// StrategyServerWins discards the whole stale write and keeps the server state
+	StrategyServerWins Strategy = "server_wins"
+	// StrategyClientWins applies every client field on top of the server state
+	StrategyClientWins Strategy = "client_wins"
+	// StrategyFieldMerge applies client fields the server hasn't touched since the base version
+	StrategyFieldMerge Strategy = "field_merge"
+)
+
+func (s Strategy) IsValid() bool {
+	switch s {
+	case StrategyServerWins, StrategyClientWins, StrategyFieldMerge:
+		return true
+	}
+	return false
+}
+
+// ParseStrategy converts a configured or requested strategy name into a Strategy
+func ParseStrategy(value string) (Strategy, error) {
+	strategy := Strategy(value)
+	if !strategy.IsValid() {
+		return "", fmt.Errorf("unknown conflict strategy: %s", value)
+	}
+	return strategy, nil
+}
+
+// Fields holds JSON encoded field values keyed by their JSON name
+type Fields map[string]json.RawMessage
+
+// Result describes how a stale client write was resolved
+type Result struct {
+	// Apply holds the client fields that should be written on top of the server state
+	Apply Fields
+	// Conflicts lists fields edited by both the client and the server since the base version
+	Conflicts []string
+	// Discarded lists client fields that were dropped by the strategy
+	Discarded []string
+}
+
+// HasChanges reports whether anything should be written
+func (r *Result) HasChanges() bool {
+	return len(r.Apply) > 0
+}
+
+// Resolve merges a client write made against base into the current server state.
+//
+// base must contain the value the client saw for every field it changed. A field
+// counts as a conflict only when the server changed it since base and the client
+// is writing a different value than the server now holds.
+func Resolve(strategy Strategy, base, server, client Fields) (*Result, error) {
+	if !strategy.IsValid() {
+		return nil, fmt.Errorf("unknown conflict strategy: %s", strategy)
+	}
+
+	result := &Result{
+		Apply:     Fields{},
+		Conflicts: []string{},
+		Discarded: []string{},
+	}
+
+	for _, field := range sortedKeys(client) {
+		clientValue := client[field]
+
+		baseValue, ok := base[field]
+		if !ok {
+			return nil, fmt.Errorf("missing base value for field %s", field)
+		}
+
+		serverValue := server[field]
+
+		// Client ended up where the server already is, nothing to write
+		if equal(clientValue, serverValue) {
+			continue
+		}
+
+		serverChanged := !equal(baseValue, serverValue)
+		if serverChanged {
+			result.Conflicts = append(result.Conflicts, field)
+		}
+
+		switch strategy {
+		case StrategyServerWins:
+			result.Discarded = append(result.Discarded, field)
+		case StrategyClientWins:
+			result.Apply[field] = clientValue
+		case StrategyFieldMerge:
+			if serverChanged {
+				result.Discarded = append(result.Discarded, field)
+			} else {
+				result.Apply[field] = clientValue
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// equal compares two JSON values semantically so formatting differences don't count as edits
+func equal(a, b json.RawMessage) bool {
+	if len(a) == 0 {
+		a = json.RawMessage("null")
+	}
+	if len(b) == 0 {
+		b = json.RawMessage("null")
+	}
+
+	var left, right interface{}
+	if err := json.Unmarshal(a, &left); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(b, &right); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(left, right)
+}
+
+func sortedKeys(fields Fields) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
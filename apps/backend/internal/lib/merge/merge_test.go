@@ -0,0 +1,165 @@
+package merge
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func fields(values map[string]string) Fields {
+	result := Fields{}
+	for field, value := range values {
+		result[field] = json.RawMessage(value)
+	}
+	return result
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  Strategy
+		base      map[string]string
+		server    map[string]string
+		client    map[string]string
+		apply     map[string]string
+		conflicts []string
+		discarded []string
+	}{
+		{
+			name:      "server wins keeps a clean edit out",
+			strategy:  StrategyServerWins,
+			base:      map[string]string{"title": `"a"`},
+			server:    map[string]string{"title": `"a"`},
+			client:    map[string]string{"title": `"b"`},
+			apply:     map[string]string{},
+			discarded: []string{"title"},
+		},
+		{
+			name:      "server wins discards a conflicting edit",
+			strategy:  StrategyServerWins,
+			base:      map[string]string{"title": `"a"`},
+			server:    map[string]string{"title": `"s"`},
+			client:    map[string]string{"title": `"c"`},
+			apply:     map[string]string{},
+			conflicts: []string{"title"},
+			discarded: []string{"title"},
+		},
+		{
+			name:     "client wins applies a clean edit",
+			strategy: StrategyClientWins,
+			base:     map[string]string{"title": `"a"`},
+			server:   map[string]string{"title": `"a"`},
+			client:   map[string]string{"title": `"b"`},
+			apply:    map[string]string{"title": `"b"`},
+		},
+		{
+			name:      "client wins overwrites a conflicting edit",
+			strategy:  StrategyClientWins,
+			base:      map[string]string{"title": `"a"`},
+			server:    map[string]string{"title": `"s"`},
+			client:    map[string]string{"title": `"c"`},
+			apply:     map[string]string{"title": `"c"`},
+			conflicts: []string{"title"},
+		},
+		{
+			name:     "field merge applies disjoint edits",
+			strategy: StrategyFieldMerge,
+			base:     map[string]string{"title": `"a"`, "priority": `"low"`},
+			server:   map[string]string{"title": `"a"`, "priority": `"high"`, "status": `"active"`},
+			client:   map[string]string{"title": `"b"`},
+			apply:    map[string]string{"title": `"b"`},
+		},
+		{
+			name:      "field merge keeps the server side of a conflict",
+			strategy:  StrategyFieldMerge,
+			base:      map[string]string{"title": `"a"`, "description": `"d"`},
+			server:    map[string]string{"title": `"s"`, "description": `"d"`},
+			client:    map[string]string{"title": `"c"`, "description": `"e"`},
+			apply:     map[string]string{"description": `"e"`},
+			conflicts: []string{"title"},
+			discarded: []string{"title"},
+		},
+		{
+			name:     "an edit the server made too is no conflict",
+			strategy: StrategyFieldMerge,
+			base:     map[string]string{"status": `"active"`},
+			server:   map[string]string{"status": `"completed"`},
+			client:   map[string]string{"status": `"completed"`},
+			apply:    map[string]string{},
+		},
+		{
+			name:     "a null field set by the client",
+			strategy: StrategyFieldMerge,
+			base:     map[string]string{"dueDate": `null`},
+			server:   map[string]string{},
+			client:   map[string]string{"dueDate": `"2030-01-01T00:00:00Z"`},
+			apply:    map[string]string{"dueDate": `"2030-01-01T00:00:00Z"`},
+		},
+		{
+			name:     "a field cleared by the client",
+			strategy: StrategyFieldMerge,
+			base:     map[string]string{"dueDate": `"2030-01-01T00:00:00Z"`},
+			server:   map[string]string{"dueDate": `"2030-01-01T00:00:00Z"`},
+			client:   map[string]string{"dueDate": `null`},
+			apply:    map[string]string{"dueDate": `null`},
+		},
+		{
+			name:     "formatting differences are no edits",
+			strategy: StrategyFieldMerge,
+			base:     map[string]string{"metadata": `{"tags":["a","b"],"color":null}`},
+			server:   map[string]string{"metadata": `{ "color": null, "tags": [ "a", "b" ] }`},
+			client:   map[string]string{"metadata": `{"tags":["a","b"], "color":"#fff"}`},
+			apply:    map[string]string{"metadata": `{"tags":["a","b"], "color":"#fff"}`},
+		},
+		{
+			name:     "a client value formatted unlike the server's is nothing to write",
+			strategy: StrategyClientWins,
+			base:     map[string]string{"metadata": `{"tags":[]}`},
+			server:   map[string]string{"metadata": `{"tags":["a"]}`},
+			client:   map[string]string{"metadata": `{ "tags" : [ "a" ] }`},
+			apply:    map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Resolve(tt.strategy, fields(tt.base), fields(tt.server), fields(tt.client))
+			require.NoError(t, err)
+
+			require.Equal(t, fields(tt.apply), result.Apply)
+			require.Equal(t, append([]string{}, tt.conflicts...), result.Conflicts)
+			require.Equal(t, append([]string{}, tt.discarded...), result.Discarded)
+			require.Equal(t, len(tt.apply) > 0, result.HasChanges())
+		})
+	}
+}
+
+func TestResolveRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy Strategy
+		base     map[string]string
+	}{
+		{name: "unknown strategy", strategy: "last_write_wins", base: map[string]string{"title": `"a"`}},
+		{name: "missing base value", strategy: StrategyFieldMerge, base: map[string]string{"priority": `"low"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Resolve(tt.strategy, fields(tt.base), Fields{}, fields(map[string]string{"title": `"b"`}))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseStrategy(t *testing.T) {
+	for _, strategy := range []Strategy{StrategyServerWins, StrategyClientWins, StrategyFieldMerge} {
+		parsed, err := ParseStrategy(string(strategy))
+		require.NoError(t, err)
+		require.Equal(t, strategy, parsed)
+	}
+
+	_, err := ParseStrategy("merge")
+	require.Error(t, err)
+}
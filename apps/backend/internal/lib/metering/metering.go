@@ -0,0 +1,181 @@
+package metering
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/usage"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+const (
+	bucketKeyPrefix = "usage:"
+	// bucketTTL keeps raw counters long enough for the rollup to catch up after missed runs
+	bucketTTL = 48 * time.Hour
+	// flushTimeout bounds a flush, counts that didn't make it are retried with the next one
+	flushTimeout = 5 * time.Second
+)
+
+// BucketKey is the Redis hash holding the raw counters of an hour, one field per subject and metric
+func BucketKey(hour time.Time) string {
+	return bucketKeyPrefix + hour.UTC().Format("2006010215")
+}
+
+// Field names a counter inside a bucket, e.g. user:user_2ab:api_calls
+func Field(subjectType usage.SubjectType, subjectID string, metric usage.Metric) string {
+	return string(subjectType) + ":" + subjectID + ":" + string(metric)
+}
+
+// ParseField reverses Field, the subject ID may itself contain colons
+func ParseField(field string) (usage.SubjectType, string, usage.Metric, bool) {
+	subjectType, rest, ok := strings.Cut(field, ":")
+	if !ok {
+		return "", "", "", false
+	}
+
+	i := strings.LastIndex(rest, ":")
+	if i <= 0 || i == len(rest)-1 {
+		return "", "", "", false
+	}
+
+	return usage.SubjectType(subjectType), rest[:i], usage.Metric(rest[i+1:]), true
+}
+
+type counterKey struct {
+	hour  time.Time
+	field string
+}
+
+// Meter counts usage in memory and periodically adds it to the hourly buckets in Redis, so
+// metering costs no round trip on the request path
+type Meter struct {
+	redis   *redis.Client
+	logger  *zerolog.Logger
+	mu      sync.Mutex
+	pending map[counterKey]int64
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func NewMeter(redisClient *redis.Client, logger *zerolog.Logger) *Meter {
+	return &Meter{
+		redis:   redisClient,
+		logger:  logger,
+		pending: make(map[counterKey]int64),
+	}
+}
+
+// Record counts n units of a metric for the user and, when set, their active workspace
+func (m *Meter) Record(userID, workspaceID string, metric usage.Metric, n int64) {
+	if m == nil || userID == "" || n <= 0 {
+		return
+	}
+
+	hour := time.Now().UTC().Truncate(time.Hour)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pending[counterKey{hour: hour, field: Field(usage.SubjectUser, userID, metric)}] += n
+	if workspaceID != "" {
+		m.pending[counterKey{hour: hour, field: Field(usage.SubjectWorkspace, workspaceID, metric)}] += n
+	}
+}
+
+// Flush adds the pending counts to Redis. On failure they are kept for the next flush.
+func (m *Meter) Flush(ctx context.Context) error {
+	m.mu.Lock()
+	pending := m.pending
+	m.pending = make(map[counterKey]int64)
+	m.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	pipe := m.redis.Pipeline()
+	buckets := make(map[string]struct{})
+	for key, n := range pending {
+		bucket := BucketKey(key.hour)
+		pipe.HIncrBy(ctx, bucket, key.field, n)
+		buckets[bucket] = struct{}{}
+	}
+	for bucket := range buckets {
+		pipe.Expire(ctx, bucket, bucketTTL)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		m.mu.Lock()
+		for key, n := range pending {
+			m.pending[key] += n
+		}
+		m.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// Start flushes every interval until Stop
+func (m *Meter) Start(interval time.Duration) {
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+
+	go func() {
+		defer close(m.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				m.flushWithTimeout(context.Background())
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the flush loop and flushes what is left, the caller still owns the Redis client
+func (m *Meter) Stop(ctx context.Context) {
+	if m.stop != nil {
+		close(m.stop)
+		<-m.done
+	}
+
+	m.flushWithTimeout(ctx)
+}
+
+func (m *Meter) flushWithTimeout(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, flushTimeout)
+	defer cancel()
+
+	if err := m.Flush(ctx); err != nil {
+		m.logger.Warn().Err(err).Msg("failed to flush usage counters, retrying with the next flush")
+	}
+}
+
+// ReadBucket returns the raw counters of an hour, keyed by field
+func ReadBucket(ctx context.Context, redisClient *redis.Client, hour time.Time) (map[string]int64, error) {
+	values, err := redisClient.HGetAll(ctx, BucketKey(hour)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(values))
+	for field, value := range values {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		counts[field] = n
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,252 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Operation is the typed definition of an endpoint. Request is the payload struct
+// the handler binds (param, query and json tags), Response the struct it returns.
+type Operation struct {
+	ID       string
+	Summary  string
+	Tags     []string
+	Request  interface{}
+	Response interface{}
+	Status   int
+	Errors   []int
+	// Public operations don't require a bearer token
+	Public bool
+	// SessionOnly operations require a bearer token, API keys aren't accepted. Admin operations
+	// always are.
+	SessionOnly bool
+	// Enveloped responses are wrapped as {"data": ..., "meta": ...}
+	Enveloped bool
+	// Upload is the multipart form field of a file upload, if the operation takes one
+	Upload string
+	// Produces lists the media types of a streamed response body, replacing the JSON response
+	Produces []string
+}
+
+// Document is an OpenAPI 3.1 document generated from operation definitions
+type Document struct {
+	title      string
+	version    string
+	generator  *schemaGenerator
+	paths      map[string]map[string]interface{}
+	bodies     map[string]Schema
+	uploads    map[string]bool
+	errorModel interface{}
+}
+
+// NewDocument creates an empty document. errorModel is the problem+json body of every error response.
+func NewDocument(title, version string, errorModel interface{}) *Document {
+	return &Document{
+		title:      title,
+		version:    version,
+		generator:  newSchemaGenerator(),
+		paths:      map[string]map[string]interface{}{},
+		bodies:     map[string]Schema{},
+		uploads:    map[string]bool{},
+		errorModel: errorModel,
+	}
+}
+
+// Add registers an operation served at an echo route path such as /api/v1/todos/:id
+func (d *Document) Add(method, routePath string, op Operation) {
+	path := toOpenAPIPath(routePath)
+
+	operation := map[string]interface{}{
+		"operationId": op.ID,
+		"summary":     op.Summary,
+		"tags":        op.Tags,
+	}
+
+	switch {
+	case op.Public:
+	case op.SessionOnly || strings.HasPrefix(routePath, "/admin/"):
+		operation["security"] = []map[string][]string{{"bearerAuth": {}}}
+	default:
+		operation["security"] = []map[string][]string{{"bearerAuth": {}}, {"apiKeyAuth": {}}}
+	}
+
+	parameters := []map[string]interface{}{}
+	if op.Request != nil {
+		requestType := reflect.TypeOf(op.Request)
+		for requestType.Kind() == reflect.Pointer {
+			requestType = requestType.Elem()
+		}
+
+		parameters = d.parameters(requestType)
+
+		// Only payloads with json fields have a body, params and query live on the same struct
+		if object := d.generator.objectSchema(requestType); len(object["properties"].(Schema)) > 0 {
+			body := d.generator.schemaFor(requestType)
+			d.bodies[method+" "+routePath] = body
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": body},
+				},
+			}
+		}
+	}
+	operation["parameters"] = parameters
+
+	if op.Upload != "" {
+		d.uploads[method+" "+routePath] = true
+		operation["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"multipart/form-data": map[string]interface{}{
+					"schema": Schema{
+						"type": "object",
+						"properties": Schema{
+							op.Upload: Schema{"type": "string", "contentMediaType": "application/octet-stream"},
+						},
+						"required": []string{op.Upload},
+					},
+				},
+			},
+		}
+	}
+
+	responses := map[string]interface{}{}
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	success := map[string]interface{}{"description": http.StatusText(status)}
+	if op.Response != nil && status != http.StatusNoContent {
+		schema := d.generator.schemaFor(reflect.TypeOf(op.Response))
+		if op.Enveloped {
+			schema = Schema{
+				"type": "object",
+				"properties": Schema{
+					"data": schema,
+					"meta": Schema{"type": "object"},
+				},
+				"required": []string{"data"},
+			}
+		}
+
+		success["content"] = map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schema},
+		}
+	}
+	if len(op.Produces) > 0 {
+		content := map[string]interface{}{}
+		for _, mediaType := range op.Produces {
+			content[mediaType] = map[string]interface{}{"schema": Schema{"type": "string"}}
+		}
+		success["content"] = content
+	}
+	responses[strconv.Itoa(status)] = success
+
+	errorSchema := d.generator.schemaFor(reflect.TypeOf(d.errorModel))
+	for _, code := range op.Errors {
+		responses[strconv.Itoa(code)] = map[string]interface{}{
+			"description": http.StatusText(code),
+			"content": map[string]interface{}{
+				"application/problem+json": map[string]interface{}{"schema": errorSchema},
+			},
+		}
+	}
+	operation["responses"] = responses
+
+	if d.paths[path] == nil {
+		d.paths[path] = map[string]interface{}{}
+	}
+	d.paths[path][strings.ToLower(method)] = operation
+}
+
+func (d *Document) parameters(requestType reflect.Type) []map[string]interface{} {
+	parameters := []map[string]interface{}{}
+
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+
+			if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+				collect(field.Type)
+				continue
+			}
+
+			for _, in := range []string{"path", "query"} {
+				tagName := in
+				if in == "path" {
+					tagName = "param"
+				}
+
+				name := field.Tag.Get(tagName)
+				if name == "" {
+					continue
+				}
+
+				schema, required := d.generator.parameterSchema(field)
+				parameters = append(parameters, map[string]interface{}{
+					"name":     name,
+					"in":       in,
+					"required": required || in == "path",
+					"schema":   schema,
+				})
+			}
+		}
+	}
+	collect(requestType)
+
+	return parameters
+}
+
+// HasBody reports whether a JSON request body was declared for the route
+func (d *Document) HasBody(method, routePath string) bool {
+	_, ok := d.bodies[method+" "+routePath]
+	return ok
+}
+
+// IsUpload tells whether the operation at an echo route path takes a file upload
+func (d *Document) IsUpload(method, routePath string) bool {
+	return d.uploads[method+" "+routePath]
+}
+
+// MarshalJSON renders the full document
+func (d *Document) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"openapi": "3.1.0",
+		"info": map[string]interface{}{
+			"title":   d.title,
+			"version": d.version,
+		},
+		"paths": d.paths,
+		"components": map[string]interface{}{
+			"schemas": d.generator.components,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{
+					"type":         "http",
+					"scheme":       "bearer",
+					"bearerFormat": "JWT",
+				},
+				"apiKeyAuth": map[string]interface{}{
+					"type": "apiKey",
+					"in":   "header",
+					"name": "X-API-Key",
+				},
+			},
+		},
+	})
+}
+
+func toOpenAPIPath(routePath string) string {
+	segments := strings.Split(routePath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + strings.TrimPrefix(segment, ":") + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,302 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schema is a JSON Schema (draft 2020-12) object as used by OpenAPI 3.1
+type Schema map[string]interface{}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	uuidType       = reflect.TypeOf(uuid.UUID{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	genericArgs    = regexp.MustCompile(`[^\[\],]*/`)
+	nonAlphaNum    = regexp.MustCompile(`[^A-Za-z0-9]+`)
+)
+
+// schemaGenerator reflects Go types into schemas, collecting named structs as components
+type schemaGenerator struct {
+	components map[string]Schema
+	names      map[reflect.Type]string
+	types      map[string]reflect.Type
+	// refPrefix is where the components are referenced from
+	refPrefix string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		components: map[string]Schema{},
+		names:      map[reflect.Type]string{},
+		types:      map[string]reflect.Type{},
+		refPrefix:  "#/components/schemas/",
+	}
+}
+
+// SchemaOf returns a standalone JSON Schema of the type of v, the named structs it uses are
+// defined under $defs
+func SchemaOf(v interface{}) Schema {
+	g := newSchemaGenerator()
+	g.refPrefix = "#/$defs/"
+
+	schema := Schema{"$schema": "https://json-schema.org/draft/2020-12/schema"}
+	for key, value := range g.schemaFor(reflect.TypeOf(v)) {
+		schema[key] = value
+	}
+	if len(g.components) > 0 {
+		defs := Schema{}
+		for name, component := range g.components {
+			defs[name] = component
+		}
+		schema["$defs"] = defs
+	}
+	return schema
+}
+
+// schemaFor returns the schema of t, named structs are referenced from components
+func (g *schemaGenerator) schemaFor(t reflect.Type) Schema {
+	switch {
+	case t == timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case t == uuidType:
+		return Schema{"type": "string", "format": "uuid"}
+	case t == rawMessageType:
+		return Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(g.schemaFor(t.Elem()))
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": "array", "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.objectSchema(t)
+		}
+
+		name, ok := g.names[t]
+		if !ok {
+			name = g.componentName(t)
+
+			// Reserve the name first so recursive types terminate
+			g.components[name] = Schema{}
+			g.components[name] = g.objectSchema(t)
+		}
+		return Schema{"$ref": g.refPrefix + name}
+	}
+
+	return Schema{}
+}
+
+// objectSchema builds the schema of a struct from its json and validate tags
+func (g *schemaGenerator) objectSchema(t reflect.Type) Schema {
+	properties := Schema{}
+	required := []string{}
+
+	g.collectFields(t, properties, &required)
+
+	schema := Schema{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (g *schemaGenerator) collectFields(t reflect.Type, properties Schema, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, ok := jsonName(field)
+		if !ok {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+				g.collectFields(field.Type, properties, required)
+			}
+			continue
+		}
+
+		schema := g.schemaFor(field.Type)
+		isRequired := applyValidateTag(schema, field)
+		properties[name] = schema
+
+		if isRequired {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// parameterSchema builds the schema of a path or query parameter field
+func (g *schemaGenerator) parameterSchema(field reflect.StructField) (Schema, bool) {
+	t := field.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	schema := g.schemaFor(t)
+	isRequired := applyValidateTag(schema, field)
+	return schema, isRequired
+}
+
+// applyValidateTag translates go-playground validator rules into schema keywords
+// and reports whether the field is required
+func applyValidateTag(schema Schema, field reflect.StructField) bool {
+	tag := field.Tag.Get("validate")
+	if tag == "" {
+		return false
+	}
+
+	target := schema
+	if anyOf, ok := schema["anyOf"].([]Schema); ok && len(anyOf) > 0 {
+		target = anyOf[0]
+	}
+
+	kind := field.Type.Kind()
+	if kind == reflect.Pointer {
+		kind = field.Type.Elem().Kind()
+	}
+
+	isRequired := false
+	for _, rule := range strings.Split(tag, ",") {
+		key, param, _ := strings.Cut(rule, "=")
+
+		switch key {
+		case "dive":
+			return isRequired
+		case "required":
+			isRequired = true
+		case "textmin", "textmax":
+			// Lengths of text count graphemes, the request validation does as well
+			if value, err := strconv.Atoi(param); err == nil {
+				target[map[string]string{"textmin": "minLength", "textmax": "maxLength"}[key]] = value
+			}
+		case "min", "max":
+			value, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			switch kind {
+			case reflect.String:
+				target[map[string]string{"min": "minLength", "max": "maxLength"}[key]] = int(value)
+			case reflect.Slice, reflect.Array:
+				target[map[string]string{"min": "minItems", "max": "maxItems"}[key]] = int(value)
+			case reflect.Map:
+				target[map[string]string{"min": "minProperties", "max": "maxProperties"}[key]] = int(value)
+			default:
+				target[map[string]string{"min": "minimum", "max": "maximum"}[key]] = value
+			}
+		case "oneof":
+			values := []interface{}{}
+			for _, value := range strings.Fields(param) {
+				values = append(values, value)
+			}
+			target["enum"] = values
+		case "uuid":
+			target["format"] = "uuid"
+		case "email":
+			target["format"] = "email"
+		case "url":
+			target["format"] = "uri"
+		case "hexcolor":
+			target["pattern"] = "^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$"
+		case "startswith":
+			target["pattern"] = "^" + regexp.QuoteMeta(param)
+		}
+	}
+
+	return isRequired
+}
+
+func nullable(schema Schema) Schema {
+	if t, ok := schema["type"].(string); ok && len(schema) > 0 {
+		schema["type"] = []string{t, "null"}
+		return schema
+	}
+	return Schema{"anyOf": []Schema{schema, {"type": "null"}}}
+}
+
+func jsonName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	tag := field.Tag.Get("json")
+	if tag == "-" || tag == "" {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+// componentName derives a stable component name such as PopulatedTodo, falling back
+// to a package qualified name such as TodoPopulatedTodo when two types collide
+func (g *schemaGenerator) componentName(t reflect.Type) string {
+	name := pascalCase(genericArgs.ReplaceAllString(t.Name(), ""), false)
+
+	if existing, taken := g.types[name]; taken && existing != t {
+		pkg := t.PkgPath()
+		if idx := strings.LastIndex(pkg, "/"); idx >= 0 {
+			pkg = pkg[idx+1:]
+		}
+		name = pascalCase(pkg, true) + name
+	}
+
+	g.names[t] = name
+	g.types[name] = t
+	return name
+}
+
+// pascalCase joins the alphanumeric parts of value, capitalizing each one.
+// Package qualifiers inside generic arguments are dropped unless keepQualifiers is set.
+func pascalCase(value string, keepQualifiers bool) string {
+	var builder strings.Builder
+	for _, part := range nonAlphaNum.Split(value, -1) {
+		if part == "" {
+			continue
+		}
+		builder.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	if keepQualifiers {
+		return builder.String()
+	}
+
+	// PaginatedResponse[todo.PopulatedTodo] -> PaginatedResponsePopulatedTodo
+	if open := strings.Index(value, "["); open >= 0 {
+		args := strings.Split(strings.TrimSuffix(value[open+1:], "]"), ",")
+		result := pascalCase(value[:open], false)
+		for _, arg := range args {
+			if idx := strings.LastIndex(arg, "."); idx >= 0 {
+				arg = arg[idx+1:]
+			}
+			result += pascalCase(arg, false)
+		}
+		return result
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,201 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+)
+
+// Issue describes a single place where a request body doesn't match its schema
+type Issue struct {
+	Field string
+	Code  string
+	// Message is a message of the catalog, formatted with Args
+	Message string
+	Args    []any
+}
+
+// ValidateBody checks a JSON request body against the schema declared for the route.
+// Routes without a declared body are not checked.
+func (d *Document) ValidateBody(method, routePath string, body []byte) []Issue {
+	schema, ok := d.bodies[method+" "+routePath]
+	if !ok {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []Issue{{Field: "body", Code: errs.FieldCodeInvalidType, Message: "must be valid JSON"}}
+	}
+
+	var issues []Issue
+	d.validate(schema, value, "", &issues)
+	return issues
+}
+
+func (d *Document) validate(schema Schema, value interface{}, path string, issues *[]Issue) {
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, found := d.generator.components[strings.TrimPrefix(ref, "#/components/schemas/")]
+		if !found {
+			return
+		}
+		schema = resolved
+	}
+
+	if anyOf, ok := schema["anyOf"].([]Schema); ok {
+		for _, candidate := range anyOf {
+			var candidateIssues []Issue
+			d.validate(candidate, value, path, &candidateIssues)
+			if len(candidateIssues) == 0 {
+				return
+			}
+		}
+		d.validate(anyOf[0], value, path, issues)
+		return
+	}
+
+	if !matchesType(schema["type"], value) {
+		*issues = append(*issues, Issue{Field: fieldName(path), Code: errs.FieldCodeInvalidType, Message: "must be of type %s", Args: []any{typeName(schema["type"])}})
+		return
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		if s, isString := value.(string); isString && !containsValue(enum, s) {
+			*issues = append(*issues, Issue{Field: fieldName(path), Code: errs.FieldCodeInvalidChoice, Message: "must be one of the allowed values"})
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		// Counted like the textmin and textmax rules, an emoji is one character however it is encoded
+		length := validation.Graphemes(v)
+		if limit, ok := schema["minLength"].(int); ok && length < limit {
+			*issues = append(*issues, Issue{Field: fieldName(path), Code: errs.FieldCodeTooShort, Message: "must be at least %s characters", Args: []any{fmt.Sprint(limit)}})
+		}
+		if limit, ok := schema["maxLength"].(int); ok && length > limit {
+			*issues = append(*issues, Issue{Field: fieldName(path), Code: errs.FieldCodeTooLong, Message: "must not exceed %s characters", Args: []any{fmt.Sprint(limit)}})
+		}
+	case float64:
+		if limit, ok := schema["minimum"].(float64); ok && v < limit {
+			*issues = append(*issues, Issue{Field: fieldName(path), Code: errs.FieldCodeTooSmall, Message: "must be at least %s", Args: []any{fmt.Sprint(limit)}})
+		}
+		if limit, ok := schema["maximum"].(float64); ok && v > limit {
+			*issues = append(*issues, Issue{Field: fieldName(path), Code: errs.FieldCodeTooLarge, Message: "must not exceed %s", Args: []any{fmt.Sprint(limit)}})
+		}
+	case []interface{}:
+		if limit, ok := schema["maxItems"].(int); ok && len(v) > limit {
+			*issues = append(*issues, Issue{Field: fieldName(path), Code: errs.FieldCodeTooMany, Message: "must not contain more than %s items", Args: []any{fmt.Sprint(limit)}})
+		}
+		if items, ok := schema["items"].(Schema); ok {
+			for i, item := range v {
+				d.validate(items, item, fmt.Sprintf("%s[%d]", path, i), issues)
+			}
+		}
+	case map[string]interface{}:
+		d.validateObject(schema, v, path, issues)
+	}
+}
+
+func (d *Document) validateObject(schema Schema, value map[string]interface{}, path string, issues *[]Issue) {
+	if required, ok := schema["required"].([]string); ok {
+		for _, name := range required {
+			if _, present := value[name]; !present {
+				*issues = append(*issues, Issue{Field: joinPath(path, name), Code: errs.FieldCodeRequired, Message: "is required"})
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(Schema)
+	for name, fieldValue := range value {
+		if propertySchema, ok := properties[name].(Schema); ok {
+			d.validate(propertySchema, fieldValue, joinPath(path, name), issues)
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				*issues = append(*issues, Issue{Field: joinPath(path, name), Code: errs.FieldCodeUnknownField, Message: "is not a known field"})
+			}
+		case Schema:
+			d.validate(additional, fieldValue, joinPath(path, name), issues)
+		}
+	}
+}
+
+func matchesType(declared interface{}, value interface{}) bool {
+	switch t := declared.(type) {
+	case nil:
+		return true
+	case string:
+		return matchesSingleType(t, value)
+	case []string:
+		for _, single := range t {
+			if matchesSingleType(single, value) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchesSingleType(declared string, value interface{}) bool {
+	switch declared {
+	case "null":
+		return value == nil
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	}
+	return true
+}
+
+func typeName(declared interface{}) string {
+	if types, ok := declared.([]string); ok {
+		return strings.Join(types, " or ")
+	}
+	return fmt.Sprint(declared)
+}
+
+func containsValue(values []interface{}, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func fieldName(path string) string {
+	if path == "" {
+		return "body"
+	}
+	return path
+}
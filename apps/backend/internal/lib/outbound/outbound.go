@@ -0,0 +1,238 @@
+// Package outbound builds the HTTP clients calling third parties, with the retry, timeout and
+// connection pooling policy of the config.
+package outbound
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+)
+
+// Options adapt a client to its destination
+type Options struct {
+	// Timeout bounds every attempt, reading the response body included, 0 leaves it to the
+	// context of the request
+	Timeout time.Duration
+	// DialContext replaces the default dialer, e.g. to check the addresses connected to
+	DialContext func(ctx context.Context, network, address string) (net.Conn, error)
+	// Direct never connects through the proxy of the environment
+	Direct bool
+	// MaxResponseHeaderBytes limits the headers of a response, 0 is the default of net/http
+	MaxResponseHeaderBytes int64
+	// CheckRedirect is the redirect policy of the client, nil follows up to 10 redirects
+	CheckRedirect func(req *http.Request, via []*http.Request) error
+}
+
+// New returns a client pooling its connections per destination host. Idempotent requests are
+// retried with jittered exponential backoff after network failures and after 429, 502, 503 and
+// 504 responses, honoring Retry-After.
+func New(cfg *config.OutboundConfig, opts Options) *http.Client {
+	dialer := &net.Dialer{Timeout: opts.Timeout, KeepAlive: 30 * time.Second}
+	dialContext := opts.DialContext
+	if dialContext == nil {
+		dialContext = dialer.DialContext
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if opts.Direct {
+		proxy = nil
+	}
+
+	base := &http.Transport{
+		Proxy:                  proxy,
+		DialContext:            dialContext,
+		ForceAttemptHTTP2:      true,
+		MaxIdleConns:           100,
+		MaxIdleConnsPerHost:    cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:        cfg.MaxConnsPerHost,
+		IdleConnTimeout:        cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:    10 * time.Second,
+		ExpectContinueTimeout:  time.Second,
+		MaxResponseHeaderBytes: opts.MaxResponseHeaderBytes,
+	}
+
+	return &http.Client{
+		Transport: &transport{
+			base:       base,
+			timeout:    opts.Timeout,
+			maxRetries: cfg.MaxRetries,
+			baseDelay:  cfg.RetryBaseDelay,
+			maxDelay:   cfg.RetryMaxDelay,
+		},
+		CheckRedirect: opts.CheckRedirect,
+	}
+}
+
+type idempotentKey struct{}
+
+// Idempotent marks a request safe to retry whatever its method, such as a POST that only
+// computes an answer. Requests with an Idempotency-Key header are retried as well.
+func Idempotent(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), idempotentKey{}, true))
+}
+
+type transport struct {
+	base       http.RoundTripper
+	timeout    time.Duration
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	retries := 0
+	if isIdempotent(req) {
+		retries = t.maxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		try := req
+		if attempt > 0 {
+			try = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				try.Body = body
+			}
+		}
+
+		res, err := t.attempt(try)
+		if attempt >= retries || !shouldRetry(req.Context(), res, err) {
+			return res, err
+		}
+
+		delay, ok := t.delay(attempt, res)
+		if !ok {
+			return res, err
+		}
+		if res != nil {
+			// Drained so the connection goes back to the pool
+			_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 4<<10))
+			res.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends the request once, within the timeout until its body is closed
+func (t *transport) attempt(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	res, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	res.Body = &cancelBody{ReadCloser: res.Body, cancel: cancel}
+
+	return res, nil
+}
+
+// delay is how long to wait before the next attempt, false when the destination asked for a
+// longer wait than the policy allows
+func (t *transport) delay(attempt int, res *http.Response) (time.Duration, bool) {
+	if res != nil {
+		if wait, ok := retryAfter(res.Header.Get("Retry-After")); ok {
+			return wait, wait <= t.maxDelay
+		}
+	}
+
+	backoff := t.baseDelay << attempt
+	if backoff <= 0 || backoff > t.maxDelay {
+		backoff = t.maxDelay
+	}
+	// Full jitter, clients failing together don't retry together
+	return time.Duration(rand.Int64N(int64(backoff) + 1)), true
+}
+
+func isIdempotent(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// The body can't be sent again
+		return false
+	}
+	if marked, _ := req.Context().Value(idempotentKey{}).(bool); marked {
+		return true
+	}
+	if req.Header.Get("Idempotency-Key") != "" {
+		return true
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// shouldRetry tells whether the failure is transient. Errors the caller caused, such as a
+// cancelled context or an address the dialer refused, are final.
+func shouldRetry(ctx context.Context, res *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		var netErr net.Error
+		return (errors.As(err, &netErr) && netErr.Timeout()) ||
+			errors.Is(err, context.DeadlineExceeded) ||
+			errors.Is(err, syscall.ECONNRESET) ||
+			errors.Is(err, syscall.ECONNREFUSED) ||
+			errors.Is(err, io.ErrUnexpectedEOF) ||
+			errors.Is(err, io.EOF)
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter reads a Retry-After header, in seconds or as an HTTP date
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+// cancelBody releases the timeout of an attempt once its response is read
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
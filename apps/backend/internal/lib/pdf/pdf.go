@@ -0,0 +1,305 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// A4 in points, with the margins every page keeps clear
+const (
+	pageWidth    = 595.28
+	pageHeight   = 841.89
+	marginX      = 56.0
+	marginTop    = 56.0
+	marginBottom = 64.0
+	footerY      = 32.0
+	contentWidth = pageWidth - 2*marginX
+	// labelWidth is the column the labels of fields take up
+	labelWidth = 96.0
+)
+
+type font struct {
+	resource string
+	name     string
+	widths   *[95]int
+	fallback int
+}
+
+var (
+	regular = font{resource: "F1", name: "Helvetica", widths: &helveticaWidths, fallback: 556}
+	bold    = font{resource: "F2", name: "Helvetica-Bold", widths: &helveticaBoldWidths, fallback: 611}
+)
+
+// width measures text set in the font, in points
+func (f font) width(text string, size float64) float64 {
+	units := 0
+	for _, r := range text {
+		if r >= ' ' && r <= '~' {
+			units += f.widths[r-' ']
+		} else {
+			units += f.fallback
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// Document is a text document laid out top to bottom on A4 pages. It only uses the standard
+// Helvetica fonts, which every reader ships, so nothing needs to be embedded. Characters
+// outside Windows-1252 are printed as "?".
+type Document struct {
+	title   string
+	created time.Time
+	pages   []*bytes.Buffer
+	y       float64
+}
+
+func New(title string, created time.Time) *Document {
+	d := &Document{title: title, created: created}
+	d.newPage()
+	return d
+}
+
+// Title sets the document heading
+func (d *Document) Title(text string) {
+	d.block(bold, 18, text, 0)
+	d.Space(4)
+}
+
+// Heading starts a section
+func (d *Document) Heading(text string) {
+	// Keep a heading together with the first lines below it
+	d.ensure(13*1.35 + 3*10*1.35)
+	d.Space(6)
+	d.block(bold, 13, text, 0)
+	d.Space(2)
+}
+
+// Paragraph prints text wrapped to the page, line breaks in the text are kept
+func (d *Document) Paragraph(text string) {
+	d.block(regular, 10, text, 0)
+	d.Space(4)
+}
+
+// Note prints text smaller and in grey
+func (d *Document) Note(text string) {
+	d.block(regular, 8.5, text, 0.45)
+	d.Space(2)
+}
+
+// Field prints a bold label with its value wrapped in the column next to it
+func (d *Document) Field(label, value string) {
+	lines := wrap(regular, 10, contentWidth-labelWidth, value)
+	for i, line := range lines {
+		d.ensure(10 * 1.35)
+		d.y -= 10 * 1.35
+		if i == 0 {
+			d.text(bold, 10, marginX, label, 0)
+		}
+		d.text(regular, 10, marginX+labelWidth, line, 0)
+	}
+}
+
+// Bullet prints an item of a list, nested lists go a level deeper
+func (d *Document) Bullet(level int, text string) {
+	indent := 12 * float64(level)
+	lines := wrap(regular, 10, contentWidth-indent-12, text)
+	for i, line := range lines {
+		d.ensure(10 * 1.35)
+		d.y -= 10 * 1.35
+		if i == 0 {
+			d.text(regular, 10, marginX+indent, "-", 0)
+		}
+		d.text(regular, 10, marginX+indent+12, line, 0)
+	}
+	d.Space(1)
+}
+
+// Rule draws a line across the page
+func (d *Document) Rule() {
+	d.ensure(12)
+	d.y -= 6
+	fmt.Fprintf(d.page(), "q 0.8 G 0.5 w %.2f %.2f m %.2f %.2f l S Q\n", marginX, d.y, pageWidth-marginX, d.y)
+	d.y -= 6
+}
+
+func (d *Document) Space(points float64) {
+	d.y -= points
+}
+
+// PageBreak starts a new page unless nothing was printed on the current one yet
+func (d *Document) PageBreak() {
+	if d.y < pageHeight-marginTop {
+		d.newPage()
+	}
+}
+
+// WriteTo writes the document as a PDF file, every page gets a footer with the title and
+// the page number
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	out := &offsetWriter{w: w}
+	offsets := []int64{}
+	object := func(body string) {
+		offsets = append(offsets, out.n)
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1 to 5 are the catalog, the page tree, the fonts and the info dictionary, each
+	// page then takes two: the page and its content stream
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for _, f := range []font{regular, bold} {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", f.name))
+	}
+	object(fmt.Sprintf("<< /Title %s /Producer (ExecuTask) /CreationDate (D:%s) >>",
+		literal(d.title), d.created.UTC().Format("20060102150405Z")))
+
+	for i, content := range d.pages {
+		footer := &bytes.Buffer{}
+		writeText(footer, regular, 8, marginX, footerY, d.title, 0.45)
+		number := fmt.Sprintf("Page %d of %d", i+1, len(d.pages))
+		writeText(footer, regular, 8, pageWidth-marginX-regular.width(number, 8), footerY, number, 0.45)
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 7+2*i))
+		stream := content.String() + footer.String()
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream))
+	}
+
+	xref := out.n
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, xref)
+
+	return out.n, out.err
+}
+
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - marginTop
+}
+
+// ensure starts a new page when the next height points don't fit on the current one
+func (d *Document) ensure(height float64) {
+	if d.y-height < marginBottom {
+		d.newPage()
+	}
+}
+
+func (d *Document) block(f font, size float64, text string, grey float64) {
+	for _, line := range wrap(f, size, contentWidth, text) {
+		d.ensure(size * 1.35)
+		d.y -= size * 1.35
+		d.text(f, size, marginX, line, grey)
+	}
+}
+
+func (d *Document) text(f font, size, x float64, text string, grey float64) {
+	writeText(d.page(), f, size, x, d.y, text, grey)
+}
+
+func writeText(w io.Writer, f font, size, x, y float64, text string, grey float64) {
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(w, "BT %.2f g /%s %.1f Tf %.2f %.2f Td %s Tj ET\n", grey, f.resource, size, x, y, literal(text))
+}
+
+// wrap breaks text into lines no wider than width, breaking inside words only when a word
+// doesn't fit on a line of its own. Blank lines in the text are kept.
+func wrap(f font, size, width float64, text string) []string {
+	lines := []string{}
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := ""
+		for _, word := range strings.FieldsFunc(paragraph, unicode.IsSpace) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if f.width(candidate, size) <= width {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+
+			line = ""
+			for _, r := range word {
+				if line != "" && f.width(line+string(r), size) > width {
+					lines = append(lines, line)
+					line = ""
+				}
+				line += string(r)
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// literal encodes text as a PDF string in Windows-1252, the encoding of the fonts
+func literal(text string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range text {
+		c, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok {
+			c = '?'
+		}
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\t':
+			b.WriteByte(' ')
+		default:
+			if c < ' ' {
+				continue
+			}
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// offsetWriter counts the bytes written for the cross-reference table and keeps the first error
+type offsetWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (o *offsetWriter) Write(p []byte) (int, error) {
+	if o.err != nil {
+		return 0, o.err
+	}
+	n, err := o.w.Write(p)
+	o.n += int64(n)
+	o.err = err
+	return n, err
+}
+
+func (o *offsetWriter) WriteString(s string) {
+	o.Write([]byte(s))
+}
@@ -0,0 +1,81 @@
+// Package quickadd reads the #tags typed into todo titles. A #tag starts a word and runs over
+// letters, digits, dashes and underscores: "Call mum #tomorrow #p1" carries tomorrow and p1,
+// while "issue#42" carries none.
+package quickadd
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Hashtag is a #tag found in a title
+type Hashtag struct {
+	// Name is the tag without the #, lower cased
+	Name string
+	// Start and End are the byte offsets of the tag in the title, the # included
+	Start int
+	End   int
+}
+
+// Hashtags returns the #tags of the title in the order they are typed
+func Hashtags(title string) []Hashtag {
+	tags := []Hashtag{}
+
+	prev := ' '
+	for i := 0; i < len(title); {
+		r, size := utf8.DecodeRuneInString(title[i:])
+		if r != '#' || !unicode.IsSpace(prev) {
+			prev = r
+			i += size
+			continue
+		}
+
+		end := i + size
+		for end < len(title) {
+			next, nextSize := utf8.DecodeRuneInString(title[end:])
+			if !isTagRune(next) {
+				break
+			}
+			end += nextSize
+		}
+
+		if end > i+size {
+			tags = append(tags, Hashtag{Name: strings.ToLower(title[i+size : end]), Start: i, End: end})
+		}
+		prev = r
+		i = end
+	}
+
+	return tags
+}
+
+// Strip removes the #tags from the title and collapses the spaces left around them
+func Strip(title string, tags []Hashtag) string {
+	var b strings.Builder
+	last := 0
+	for _, tag := range tags {
+		b.WriteString(title[last:tag.Start])
+		last = tag.End
+	}
+	b.WriteString(title[last:])
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// IsTag tells whether the name is a valid #tag, without the #
+func IsTag(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !isTagRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_'
+}
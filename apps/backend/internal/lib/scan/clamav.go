@@ -0,0 +1,109 @@
+package scan
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+)
+
+// clamAVChunkSize is how much of the file goes in each INSTREAM chunk, clamd rejects streams
+// past its StreamMaxLength whatever the chunking
+const clamAVChunkSize = 64 * 1024
+
+// ClamAVScanner streams files to a clamd daemon with the INSTREAM command
+type ClamAVScanner struct {
+	address string
+	timeout time.Duration
+}
+
+func NewClamAVScanner(address string, timeout time.Duration) *ClamAVScanner {
+	return &ClamAVScanner{
+		address: address,
+		timeout: timeout,
+	}
+}
+
+func (s *ClamAVScanner) Name() string {
+	return config.ScanningProviderClamAV
+}
+
+func (s *ClamAVScanner) Scan(ctx context.Context, file io.Reader) (*Verdict, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, fmt.Errorf("failed to set clamd deadline: %w", err)
+	}
+
+	// The z prefix terminates commands and replies with a null byte
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, fmt.Errorf("failed to send clamd command: %w", err)
+	}
+
+	chunk := make([]byte, clamAVChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := file.Read(chunk)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return nil, fmt.Errorf("failed to stream file to clamd: %w", err)
+			}
+			if _, err := conn.Write(chunk[:n]); err != nil {
+				return nil, fmt.Errorf("failed to stream file to clamd: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read file to scan: %w", readErr)
+		}
+	}
+
+	// A chunk of length zero ends the stream
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return nil, fmt.Errorf("failed to end clamd stream: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+
+	return parseClamAVReply(strings.TrimSuffix(reply, "\x00"))
+}
+
+// parseClamAVReply reads replies of the form "stream: OK", "stream: <signature> FOUND" and
+// "<message> ERROR"
+func parseClamAVReply(reply string) (*Verdict, error) {
+	result := strings.TrimSpace(reply)
+	if _, after, ok := strings.Cut(result, ": "); ok {
+		result = after
+	}
+
+	switch {
+	case result == "OK":
+		return &Verdict{}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return &Verdict{Infected: true, Signature: strings.TrimSuffix(result, " FOUND")}, nil
+	default:
+		return nil, fmt.Errorf("clamd failed to scan the file: %s", result)
+	}
+}
@@ -0,0 +1,77 @@
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/lib/outbound"
+)
+
+// HTTPScanner posts files to an external scanning API. The API answers 200 with a JSON object
+// of the form {"infected": false} or {"infected": true, "signature": "..."}, any other status
+// is a failed scan.
+type HTTPScanner struct {
+	client *http.Client
+	url    string
+	apiKey string
+}
+
+func NewHTTPScanner(outboundCfg *config.OutboundConfig, url, apiKey string, timeout time.Duration) *HTTPScanner {
+	return &HTTPScanner{
+		client: outbound.New(outboundCfg, outbound.Options{Timeout: timeout}),
+		url:    url,
+		apiKey: apiKey,
+	}
+}
+
+type httpVerdict struct {
+	Infected  bool   `json:"infected"`
+	Signature string `json:"signature"`
+}
+
+func (s *HTTPScanner) Name() string {
+	return config.ScanningProviderHTTP
+}
+
+func (s *HTTPScanner) Scan(ctx context.Context, file io.Reader) (*Verdict, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build scan request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call scanning API: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return nil, fmt.Errorf("scanning API returned %d: %s", res.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var decoded httpVerdict
+	if err := json.NewDecoder(res.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode scanning API response: %w", err)
+	}
+
+	verdict := &Verdict{Infected: decoded.Infected}
+	if decoded.Infected {
+		verdict.Signature = decoded.Signature
+		if verdict.Signature == "" {
+			verdict.Signature = "unknown"
+		}
+	}
+
+	return verdict, nil
+}
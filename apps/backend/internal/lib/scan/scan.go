@@ -0,0 +1,47 @@
+// Package scan checks files for viruses before they are served, with a clamd daemon or an
+// external scanning API.
+package scan
+
+import (
+	"context"
+	"io"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+)
+
+// Verdict is the outcome of a scan
+type Verdict struct {
+	Infected bool
+	// Signature names what was found, empty for clean files
+	Signature string
+}
+
+// Scanner checks files for viruses
+type Scanner interface {
+	// Name is the provider as configured, e.g. clamav
+	Name() string
+	// Scan reads the file to its end. An error means the file couldn't be scanned, not that it
+	// is infected.
+	Scan(ctx context.Context, file io.Reader) (*Verdict, error)
+}
+
+// NewScanner returns the configured scanner, nil when scanning is disabled
+func NewScanner(cfg *config.ScanningConfig, outboundCfg *config.OutboundConfig) Scanner {
+	if cfg == nil {
+		return nil
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultScanningConfig().Timeout
+	}
+
+	switch cfg.Provider {
+	case config.ScanningProviderClamAV:
+		return NewClamAVScanner(cfg.ClamAVAddress, timeout)
+	case config.ScanningProviderHTTP:
+		return NewHTTPScanner(outboundCfg, cfg.APIURL, cfg.APIKey, timeout)
+	default:
+		return nil
+	}
+}
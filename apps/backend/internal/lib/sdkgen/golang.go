@@ -0,0 +1,328 @@
+package sdkgen
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+// goGenerator renders the typed part of the Go client, the transport lives in
+// hand written files of the same package
+type goGenerator struct {
+	spec    *Spec
+	imports map[string]bool
+	// inline object schemas that need a named type, keyed by type name
+	inline map[string]*Schema
+	queued []string
+}
+
+// GenerateGo renders the Go client types and methods for the given operations
+func GenerateGo(spec *Spec, pkg string, operations []*Operation) ([]byte, error) {
+	g := &goGenerator{
+		spec:    spec,
+		imports: map[string]bool{},
+		inline:  map[string]*Schema{},
+	}
+
+	var body strings.Builder
+	g.writeOperations(&body, operations)
+	g.writeTypes(&body)
+
+	var file strings.Builder
+	file.WriteString("// Code generated by sdkgen from the ExecuTask OpenAPI document. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&file, "package %s\n\n", pkg)
+
+	imports := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+
+	if len(imports) > 0 {
+		file.WriteString("import (\n")
+		for _, path := range imports {
+			fmt.Fprintf(&file, "\t%q\n", path)
+		}
+		file.WriteString(")\n\n")
+	}
+	file.WriteString(body.String())
+
+	formatted, err := format.Source([]byte(file.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated go client: %w", err)
+	}
+	return formatted, nil
+}
+
+func (g *goGenerator) writeTypes(w *strings.Builder) {
+	for _, name := range g.spec.SchemaNames() {
+		g.writeNamedType(w, name, g.spec.Components.Schemas[name], fmt.Sprintf("%s is the %s schema of the API", name, name))
+	}
+
+	// Inline types can queue further inline types while being written
+	for i := 0; i < len(g.queued); i++ {
+		name := g.queued[i]
+		g.writeNamedType(w, name, g.inline[name], fmt.Sprintf("%s is an inline schema of the API", name))
+	}
+}
+
+func (g *goGenerator) writeNamedType(w *strings.Builder, name string, schema *Schema, doc string) {
+	fmt.Fprintf(w, "// %s\n", doc)
+
+	if len(schema.Properties) == 0 {
+		fmt.Fprintf(w, "type %s %s\n\n", name, g.goType(schema, name))
+		return
+	}
+
+	fmt.Fprintf(w, "type %s struct {\n", name)
+	for _, property := range schema.PropertyNames() {
+		fieldType := g.goType(schema.Properties[property], name+goName(property))
+
+		tag := property
+		if !schema.IsRequired(property) {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(w, "\t%s %s `json:%q`\n", goName(property), fieldType, tag)
+	}
+	w.WriteString("}\n\n")
+}
+
+// goType maps a schema to a Go type, hint names inline objects
+func (g *goGenerator) goType(schema *Schema, hint string) string {
+	if schema == nil {
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	}
+
+	if inner, nullable := schema.NonNull(); nullable {
+		t := g.goType(inner, hint)
+		if strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || t == "json.RawMessage" {
+			return t
+		}
+		return "*" + t
+	}
+
+	if schema.Ref != "" {
+		return RefName(schema.Ref)
+	}
+
+	switch schema.Primary() {
+	case "string":
+		if schema.Format == "date-time" {
+			g.imports["time"] = true
+			return "time.Time"
+		}
+		return "string"
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.goType(schema.Items, hint+"Item")
+	case "object":
+		if len(schema.Properties) > 0 {
+			if _, ok := g.inline[hint]; !ok {
+				g.inline[hint] = schema
+				g.queued = append(g.queued, hint)
+			}
+			return hint
+		}
+		if value := schema.ValueSchema(); value != nil {
+			return "map[string]" + g.goType(value, hint+"Value")
+		}
+	}
+
+	g.imports["encoding/json"] = true
+	return "json.RawMessage"
+}
+
+func (g *goGenerator) writeOperations(w *strings.Builder, operations []*Operation) {
+	g.imports["context"] = true
+	g.imports["net/http"] = true
+
+	for _, op := range operations {
+		method := goName(op.OperationID)
+		query := op.QueryParams()
+
+		if len(query) > 0 {
+			g.writeParams(w, method+"Params", query)
+		}
+
+		args := []string{"ctx context.Context"}
+		pathExpr := g.pathExpression(op, &args)
+
+		queryExpr := "nil"
+		if len(query) > 0 {
+			args = append(args, "params *"+method+"Params")
+			queryExpr = "params.query()"
+		}
+
+		bodyExpr := ""
+		if body := op.JSONBody(); body != nil {
+			args = append(args, "body "+g.goType(body, method+"Request"))
+			bodyExpr = "body"
+		}
+
+		upload := op.UploadField()
+		if upload != "" {
+			g.imports["io"] = true
+			args = append(args, "filename string", "file io.Reader")
+		}
+
+		response, _ := op.Success()
+		resultType := ""
+		if response != nil {
+			resultType = g.goType(response, method+"Response")
+		}
+
+		fmt.Fprintf(w, "// %s calls %s %s", method, op.Method, op.Path)
+		if op.Summary != "" {
+			fmt.Fprintf(w, ": %s", strings.ToLower(op.Summary[:1])+op.Summary[1:])
+		}
+		w.WriteString("\n")
+
+		call := fmt.Sprintf("c.do(ctx, http.Method%s, %s, %s, %s, %%s)", httpMethodName(op.Method), pathExpr, queryExpr, orNil(bodyExpr))
+		if upload != "" {
+			call = fmt.Sprintf("c.upload(ctx, http.Method%s, %s, %q, filename, file, %%s)", httpMethodName(op.Method), pathExpr, upload)
+		}
+
+		switch {
+		case resultType == "":
+			fmt.Fprintf(w, "func (c *Client) %s(%s) error {\n", method, strings.Join(args, ", "))
+			fmt.Fprintf(w, "\treturn %s\n}\n\n", fmt.Sprintf(call, "nil"))
+		case isValueType(resultType):
+			fmt.Fprintf(w, "func (c *Client) %s(%s) (%s, error) {\n", method, strings.Join(args, ", "), resultType)
+			fmt.Fprintf(w, "\tvar out %s\n", resultType)
+			fmt.Fprintf(w, "\tif err := %s; err != nil {\n\t\treturn nil, err\n\t}\n", fmt.Sprintf(call, "&out"))
+			w.WriteString("\treturn out, nil\n}\n\n")
+		default:
+			fmt.Fprintf(w, "func (c *Client) %s(%s) (*%s, error) {\n", method, strings.Join(args, ", "), resultType)
+			fmt.Fprintf(w, "\tvar out %s\n", resultType)
+			fmt.Fprintf(w, "\tif err := %s; err != nil {\n\t\treturn nil, err\n\t}\n", fmt.Sprintf(call, "&out"))
+			w.WriteString("\treturn &out, nil\n}\n\n")
+		}
+
+		g.writeIterator(w, op, method, args, response)
+	}
+}
+
+// writeIterator adds a Go 1.23 iterator over every page of paginated list operations
+func (g *goGenerator) writeIterator(w *strings.Builder, op *Operation, method string, args []string, response *Schema) {
+	if !hasQueryParam(op, "page") && !hasQueryParam(op, "cursor") {
+		return
+	}
+
+	callArgs := []string{}
+	for _, arg := range args {
+		callArgs = append(callArgs, strings.Fields(arg)[0])
+	}
+	call := strings.Join(callArgs, ", ")
+	call = strings.Replace(call, "params", "&next", 1)
+
+	if items := g.spec.PageItems(response); items != nil && hasQueryParam(op, "page") {
+		itemType := g.goType(items, method+"Item")
+		g.imports["iter"] = true
+
+		fmt.Fprintf(w, "// %sIter iterates over every page of %s, starting at params.Page\n", method, method)
+		fmt.Fprintf(w, "func (c *Client) %sIter(%s) iter.Seq2[%s, error] {\n", method, strings.Join(args, ", "), itemType)
+		fmt.Fprintf(w, "\tnext := %sParams{}\n\tif params != nil {\n\t\tnext = *params\n\t}\n\n", method)
+		fmt.Fprintf(w, "\treturn paginate(next.Page, func(page int) ([]%s, int, error) {\n", itemType)
+		fmt.Fprintf(w, "\t\tnext.Page = &page\n\t\tres, err := c.%s(%s)\n", method, call)
+		w.WriteString("\t\tif err != nil {\n\t\t\treturn nil, 0, err\n\t\t}\n\t\treturn res.Data, res.TotalPages, nil\n\t})\n}\n\n")
+		return
+	}
+
+	if items, list := g.spec.CursorItems(response); items != nil && hasQueryParam(op, "cursor") {
+		itemType := g.goType(items, method+"Item")
+		g.imports["iter"] = true
+
+		fmt.Fprintf(w, "// %sIter follows the cursor of %s until the feed is caught up, starting at params.Cursor\n", method, method)
+		fmt.Fprintf(w, "func (c *Client) %sIter(%s) iter.Seq2[%s, error] {\n", method, strings.Join(args, ", "), itemType)
+		fmt.Fprintf(w, "\tnext := %sParams{}\n\tif params != nil {\n\t\tnext = *params\n\t}\n\n", method)
+		fmt.Fprintf(w, "\treturn follow(next.Cursor, func(cursor *string) ([]%s, string, bool, error) {\n", itemType)
+		fmt.Fprintf(w, "\t\tnext.Cursor = cursor\n\t\tres, err := c.%s(%s)\n", method, call)
+		fmt.Fprintf(w, "\t\tif err != nil {\n\t\t\treturn nil, \"\", false, err\n\t\t}\n\t\treturn res.%s, res.NextCursor, res.HasMore, nil\n\t})\n}\n\n", goName(list))
+	}
+}
+
+func (g *goGenerator) writeParams(w *strings.Builder, name string, params []Parameter) {
+	g.imports["net/url"] = true
+
+	fmt.Fprintf(w, "// %s are the query parameters of %s\n", name, strings.TrimSuffix(name, "Params"))
+	fmt.Fprintf(w, "type %s struct {\n", name)
+	for _, param := range params {
+		fieldType := g.goType(param.Schema, name+goName(param.Name))
+		if !param.Required && !isValueType(fieldType) {
+			fieldType = "*" + fieldType
+		}
+		fmt.Fprintf(w, "\t%s %s\n", goName(param.Name), fieldType)
+	}
+	w.WriteString("}\n\n")
+
+	fmt.Fprintf(w, "func (p *%s) query() url.Values {\n", name)
+	w.WriteString("\tvalues := url.Values{}\n\tif p == nil {\n\t\treturn values\n\t}\n\n")
+	for _, param := range params {
+		field := "p." + goName(param.Name)
+		if param.Required {
+			fmt.Fprintf(w, "\tvalues.Set(%q, formatValue(%s))\n", param.Name, field)
+			continue
+		}
+		fmt.Fprintf(w, "\tif %s != nil {\n\t\tvalues.Set(%q, formatValue(*%s))\n\t}\n", field, param.Name, field)
+	}
+	w.WriteString("\treturn values\n}\n\n")
+}
+
+// pathExpression builds the request path, adding path parameters to args
+func (g *goGenerator) pathExpression(op *Operation, args *[]string) string {
+	params := op.PathParams()
+	if len(params) == 0 {
+		return fmt.Sprintf("%q", op.Path)
+	}
+
+	g.imports["net/url"] = true
+
+	parts := []string{}
+	literal := ""
+	for _, segment := range strings.Split(strings.TrimPrefix(op.Path, "/"), "/") {
+		if !strings.HasPrefix(segment, "{") {
+			literal += "/" + segment
+			continue
+		}
+
+		name := goArgName(strings.Trim(segment, "{}"))
+		*args = append(*args, name+" string")
+		parts = append(parts, fmt.Sprintf("%q", literal+"/"), fmt.Sprintf("url.PathEscape(%s)", name))
+		literal = ""
+	}
+	if literal != "" {
+		parts = append(parts, fmt.Sprintf("%q", literal))
+	}
+	return strings.Join(parts, " + ")
+}
+
+func hasQueryParam(op *Operation, name string) bool {
+	for _, param := range op.QueryParams() {
+		if param.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isValueType(t string) bool {
+	return strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || t == "json.RawMessage"
+}
+
+func httpMethodName(method string) string {
+	return strings.ToUpper(method[:1]) + strings.ToLower(method[1:])
+}
+
+func orNil(expr string) string {
+	if expr == "" {
+		return "nil"
+	}
+	return expr
+}
@@ -0,0 +1,65 @@
+package sdkgen
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	wordBoundary = regexp.MustCompile(`[A-Z]?[a-z0-9]+|[A-Z]+(?:[A-Z][a-z]|$)?`)
+	identifier   = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	initialisms  = map[string]bool{"id": true, "url": true, "uri": true, "api": true, "json": true, "http": true, "uuid": true, "ttl": true}
+)
+
+// words splits camelCase, PascalCase and snake_case identifiers into lower case words
+func words(name string) []string {
+	result := []string{}
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		for _, word := range wordBoundary.FindAllString(part, -1) {
+			result = append(result, strings.ToLower(word))
+		}
+	}
+	return result
+}
+
+// goName exports name following Go initialism conventions: getTodoById -> GetTodoByID
+func goName(name string) string {
+	var builder strings.Builder
+	for _, word := range words(name) {
+		if initialisms[word] {
+			builder.WriteString(strings.ToUpper(word))
+			continue
+		}
+		builder.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return builder.String()
+}
+
+// goArgName is the unexported form of goName: attachmentId -> attachmentID
+func goArgName(name string) string {
+	exported := goName(name)
+	first := words(name)[0]
+	if initialisms[first] {
+		return first + exported[len(first):]
+	}
+	return strings.ToLower(exported[:1]) + exported[1:]
+}
+
+// tsName turns an operation or component name into a TypeScript type name
+func tsName(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// tsProperty quotes property names that aren't valid identifiers
+func tsProperty(name string) string {
+	if identifier.MatchString(name) {
+		return name
+	}
+	return `"` + name + `"`
+}
@@ -0,0 +1,350 @@
+package sdkgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Spec is the subset of an OpenAPI 3.1 document the client generators understand
+type Spec struct {
+	Info struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	} `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary"`
+	Tags        []string              `json:"tags"`
+	Parameters  []Parameter           `json:"parameters"`
+	RequestBody *Body                 `json:"requestBody"`
+	Responses   map[string]Body       `json:"responses"`
+	Security    []map[string][]string `json:"security"`
+	Method      string                `json:"-"`
+	Path        string                `json:"-"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type Body struct {
+	Content map[string]struct {
+		Schema *Schema `json:"schema"`
+	} `json:"content"`
+}
+
+// Schema is a JSON Schema object. Type is either a single type or a list including "null".
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 Types              `json:"type"`
+	Format               string             `json:"format"`
+	Enum                 []interface{}      `json:"enum"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *Schema            `json:"items"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	AnyOf                []*Schema          `json:"anyOf"`
+}
+
+// Types accepts both "type": "string" and "type": ["string", "null"]
+type Types []string
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("invalid schema type: %s", data)
+	}
+	*t = list
+	return nil
+}
+
+// Parse decodes a rendered OpenAPI document
+func Parse(raw []byte) (*Spec, error) {
+	spec := &Spec{}
+	if err := json.Unmarshal(raw, spec); err != nil {
+		return nil, fmt.Errorf("failed to parse openapi document: %w", err)
+	}
+	return spec, nil
+}
+
+// Operations returns every operation accepted by include, ordered by path and method
+func (s *Spec) Operations(include func(op *Operation) bool) []*Operation {
+	operations := []*Operation{}
+	for path, methods := range s.Paths {
+		for method, op := range methods {
+			op.Method = strings.ToUpper(method)
+			op.Path = path
+			if include == nil || include(op) {
+				operations = append(operations, op)
+			}
+		}
+	}
+
+	sort.Slice(operations, func(i, j int) bool {
+		if operations[i].Path != operations[j].Path {
+			return operations[i].Path < operations[j].Path
+		}
+		return methodOrder(operations[i].Method) < methodOrder(operations[j].Method)
+	})
+	return operations
+}
+
+// SchemaNames returns the component names in a stable order
+func (s *Spec) SchemaNames() []string {
+	names := make([]string, 0, len(s.Components.Schemas))
+	for name := range s.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve follows a component reference
+func (s *Spec) Resolve(schema *Schema) *Schema {
+	if schema == nil || schema.Ref == "" {
+		return schema
+	}
+	return s.Components.Schemas[RefName(schema.Ref)]
+}
+
+// PageItems returns the item schema when schema is a page of the PaginatedResponse shape
+func (s *Spec) PageItems(schema *Schema) *Schema {
+	resolved := s.Resolve(schema)
+	if resolved == nil || resolved.Properties["page"] == nil || resolved.Properties["totalPages"] == nil {
+		return nil
+	}
+
+	data := resolved.Properties["data"]
+	if data == nil || data.Items == nil {
+		return nil
+	}
+	return data.Items
+}
+
+// CursorItems returns the item schema and its list property when schema is a cursor page
+// with nextCursor and hasMore
+func (s *Spec) CursorItems(schema *Schema) (*Schema, string) {
+	resolved := s.Resolve(schema)
+	if resolved == nil || resolved.Properties["nextCursor"] == nil || resolved.Properties["hasMore"] == nil {
+		return nil, ""
+	}
+
+	for name, property := range resolved.Properties {
+		if property.Items != nil {
+			return property.Items, name
+		}
+	}
+	return nil, ""
+}
+
+// RefName returns the component name of a "#/components/schemas/Name" reference
+func RefName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// PathParams returns the path parameters in the order they appear in the path
+func (o *Operation) PathParams() []Parameter {
+	params := []Parameter{}
+	for _, segment := range strings.Split(o.Path, "/") {
+		if !strings.HasPrefix(segment, "{") {
+			continue
+		}
+
+		name := strings.Trim(segment, "{}")
+		param := Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: Types{"string"}}}
+		for _, declared := range o.Parameters {
+			if declared.In == "path" && declared.Name == name {
+				param = declared
+			}
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+func (o *Operation) QueryParams() []Parameter {
+	params := []Parameter{}
+	for _, param := range o.Parameters {
+		if param.In == "query" {
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// JSONBody returns the schema of the application/json request body, if any
+func (o *Operation) JSONBody() *Schema {
+	if o.RequestBody == nil {
+		return nil
+	}
+	if content, ok := o.RequestBody.Content["application/json"]; ok {
+		return content.Schema
+	}
+	return nil
+}
+
+// UploadField returns the file field of a multipart/form-data request body, if any
+func (o *Operation) UploadField() string {
+	if o.RequestBody == nil {
+		return ""
+	}
+
+	content, ok := o.RequestBody.Content["multipart/form-data"]
+	if !ok || content.Schema == nil {
+		return ""
+	}
+
+	for name := range content.Schema.Properties {
+		return name
+	}
+	return ""
+}
+
+// Success returns the schema of the lowest 2xx response, nil when it has no body
+func (o *Operation) Success() (*Schema, int) {
+	best := 0
+	for code := range o.Responses {
+		status, err := strconv.Atoi(code)
+		if err != nil || status < 200 || status >= 300 {
+			continue
+		}
+		if best == 0 || status < best {
+			best = status
+		}
+	}
+
+	if best == 0 {
+		return nil, http.StatusOK
+	}
+
+	content, ok := o.Responses[strconv.Itoa(best)].Content["application/json"]
+	if !ok {
+		return nil, best
+	}
+	return content.Schema, best
+}
+
+// IsDownload reports whether the operation streams a non JSON body, such as an export
+func (o *Operation) IsDownload() bool {
+	for code, response := range o.Responses {
+		if !strings.HasPrefix(code, "2") || len(response.Content) == 0 {
+			continue
+		}
+		if _, ok := response.Content["application/json"]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPublic reports whether the operation can be called without a bearer token
+func (o *Operation) IsPublic() bool {
+	return len(o.Security) == 0
+}
+
+// NonNull strips "null" from a schema, reporting whether it was nullable
+func (s *Schema) NonNull() (*Schema, bool) {
+	if s == nil {
+		return nil, false
+	}
+
+	if len(s.AnyOf) == 2 {
+		for i, option := range s.AnyOf {
+			if len(option.Type) == 1 && option.Type[0] == "null" {
+				return s.AnyOf[1-i], true
+			}
+		}
+	}
+
+	nullable := false
+	types := Types{}
+	for _, t := range s.Type {
+		if t == "null" {
+			nullable = true
+			continue
+		}
+		types = append(types, t)
+	}
+
+	if !nullable {
+		return s, false
+	}
+
+	stripped := *s
+	stripped.Type = types
+	return &stripped, true
+}
+
+// Primary returns the single non-null type of the schema, "" when it has none
+func (s *Schema) Primary() string {
+	for _, t := range s.Type {
+		if t != "null" {
+			return t
+		}
+	}
+	return ""
+}
+
+// ValueSchema returns the schema of map values, nil for closed objects
+func (s *Schema) ValueSchema() *Schema {
+	if len(s.AdditionalProperties) == 0 || string(s.AdditionalProperties) == "false" {
+		return nil
+	}
+
+	value := &Schema{}
+	if string(s.AdditionalProperties) == "true" {
+		return value
+	}
+	if err := json.Unmarshal(s.AdditionalProperties, value); err != nil {
+		return &Schema{}
+	}
+	return value
+}
+
+// IsRequired reports whether name is listed as required
+func (s *Schema) IsRequired(name string) bool {
+	for _, required := range s.Required {
+		if required == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PropertyNames returns the property names in a stable order
+func (s *Schema) PropertyNames() []string {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func methodOrder(method string) int {
+	for i, m := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		if m == method {
+			return i
+		}
+	}
+	return len(method)
+}
@@ -0,0 +1,190 @@
+package sdkgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// GenerateTypeScript renders the TypeScript SDK types and the ExecuTaskClient class
+// for the given operations. The transport lives in the hand written base client.
+func GenerateTypeScript(spec *Spec, operations []*Operation) []byte {
+	var w strings.Builder
+	w.WriteString("// Code generated by sdkgen from the ExecuTask OpenAPI document. DO NOT EDIT.\n\n")
+	w.WriteString("import { BaseClient } from \"./base.js\";\n\n")
+
+	for _, name := range spec.SchemaNames() {
+		schema := spec.Components.Schemas[name]
+		if len(schema.Properties) == 0 {
+			fmt.Fprintf(&w, "export type %s = %s;\n\n", tsName(name), tsType(schema, ""))
+			continue
+		}
+		fmt.Fprintf(&w, "export interface %s %s\n\n", tsName(name), tsObject(schema, ""))
+	}
+
+	for _, op := range operations {
+		if query := op.QueryParams(); len(query) > 0 {
+			fmt.Fprintf(&w, "export interface %sQuery {\n", tsName(op.OperationID))
+			for _, param := range query {
+				optional := "?"
+				if param.Required {
+					optional = ""
+				}
+				fmt.Fprintf(&w, "  %s%s: %s;\n", tsProperty(param.Name), optional, tsType(param.Schema, "  "))
+			}
+			w.WriteString("}\n\n")
+		}
+	}
+
+	w.WriteString("export class ExecuTaskClient extends BaseClient {\n")
+	for i, op := range operations {
+		if i > 0 {
+			w.WriteString("\n")
+		}
+		writeTypeScriptOperation(&w, spec, op)
+	}
+	w.WriteString("}\n")
+
+	return []byte(w.String())
+}
+
+func writeTypeScriptOperation(w *strings.Builder, spec *Spec, op *Operation) {
+	name := op.OperationID
+	args := []string{}
+	options := []string{}
+
+	path := op.Path
+	for _, param := range op.PathParams() {
+		arg := strings.Trim(param.Name, "{}")
+		args = append(args, arg+": string")
+		path = strings.Replace(path, "{"+param.Name+"}", "${encodeURIComponent("+arg+")}", 1)
+	}
+
+	query := op.QueryParams()
+	if len(query) > 0 {
+		// The query can only be left out when none of its parameters is required
+		arg := fmt.Sprintf("query: %sQuery = {}", tsName(name))
+		for _, param := range query {
+			if param.Required {
+				arg = fmt.Sprintf("query: %sQuery", tsName(name))
+				break
+			}
+		}
+		args = append(args, arg)
+		options = append(options, "query")
+	}
+
+	if body := op.JSONBody(); body != nil {
+		args = append(args, "body: "+tsType(body, "  "))
+		options = append(options, "body")
+	}
+
+	if upload := op.UploadField(); upload != "" {
+		args = append(args, "file: Blob", "filename?: string")
+		options = append(options, fmt.Sprintf("upload: { field: %q, file, filename }", upload))
+	}
+
+	response, _ := op.Success()
+	resultType := "void"
+	if response != nil {
+		resultType = tsType(response, "  ")
+	}
+
+	if op.Summary != "" {
+		fmt.Fprintf(w, "  /** %s */\n", op.Summary)
+	}
+	fmt.Fprintf(w, "  %s(%s): Promise<%s> {\n", name, strings.Join(args, ", "), resultType)
+	if len(options) == 0 {
+		fmt.Fprintf(w, "    return this.request<%s>(%q, `%s`);\n", resultType, op.Method, path)
+	} else {
+		fmt.Fprintf(w, "    return this.request<%s>(%q, `%s`, { %s });\n", resultType, op.Method, path, strings.Join(options, ", "))
+	}
+	w.WriteString("  }\n")
+
+	// Every page of a list, as an async iterator
+	callArgs := []string{}
+	for _, arg := range args {
+		callArgs = append(callArgs, strings.TrimSuffix(strings.Fields(arg)[0], ":"))
+	}
+
+	if items := spec.PageItems(response); items != nil && hasQueryParam(op, "page") {
+		call := strings.Replace(strings.Join(callArgs, ", "), "query", "{ ...query, page }", 1)
+		fmt.Fprintf(w, "\n  /** Iterates over every page of %s, starting at query.page */\n", name)
+		fmt.Fprintf(w, "  %sIter(%s): AsyncGenerator<%s> {\n", name, strings.Join(args, ", "), tsType(items, "  "))
+		fmt.Fprintf(w, "    return this.paginate(query.page, (page) => this.%s(%s));\n", name, call)
+		w.WriteString("  }\n")
+	}
+
+	if items, list := spec.CursorItems(response); items != nil && hasQueryParam(op, "cursor") {
+		call := strings.Replace(strings.Join(callArgs, ", "), "query", "{ ...query, cursor }", 1)
+		fmt.Fprintf(w, "\n  /** Follows the cursor of %s until the feed is caught up, starting at query.cursor */\n", name)
+		fmt.Fprintf(w, "  %sIter(%s): AsyncGenerator<%s> {\n", name, strings.Join(args, ", "), tsType(items, "  "))
+		fmt.Fprintf(w, "    return this.follow(query.cursor, async (cursor) => {\n")
+		fmt.Fprintf(w, "      const res = await this.%s(%s);\n", name, call)
+		fmt.Fprintf(w, "      return { items: res.%s ?? [], nextCursor: res.nextCursor, hasMore: res.hasMore };\n", list)
+		w.WriteString("    });\n  }\n")
+	}
+}
+
+// tsObject renders an inline object type, indent is the indentation of its closing brace
+func tsObject(schema *Schema, indent string) string {
+	var w strings.Builder
+	w.WriteString("{\n")
+	for _, property := range schema.PropertyNames() {
+		optional := "?"
+		if schema.IsRequired(property) {
+			optional = ""
+		}
+		fmt.Fprintf(&w, "%s  %s%s: %s;\n", indent, tsProperty(property), optional, tsType(schema.Properties[property], indent+"  "))
+	}
+	w.WriteString(indent + "}")
+	return w.String()
+}
+
+func tsType(schema *Schema, indent string) string {
+	if schema == nil {
+		return "unknown"
+	}
+
+	if inner, nullable := schema.NonNull(); nullable {
+		return tsType(inner, indent) + " | null"
+	}
+
+	if schema.Ref != "" {
+		return tsName(RefName(schema.Ref))
+	}
+
+	if len(schema.Enum) > 0 {
+		values := make([]string, 0, len(schema.Enum))
+		for _, value := range schema.Enum {
+			encoded, _ := json.Marshal(value)
+			values = append(values, string(encoded))
+		}
+		return strings.Join(values, " | ")
+	}
+
+	switch schema.Primary() {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		item := tsType(schema.Items, indent)
+		if strings.Contains(item, "|") {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	case "object":
+		if len(schema.Properties) > 0 {
+			return tsObject(schema, indent)
+		}
+		if value := schema.ValueSchema(); value != nil {
+			return "Record<string, " + tsType(value, indent) + ">"
+		}
+		return "Record<string, never>"
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,79 @@
+// Package sniff tells the content type of a file from its first bytes, never from what the
+// client claims it is. It knows the signatures of net/http plus executables, scripts and a few
+// audio and image formats the standard library leaves as binary.
+package sniff
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// HeadSize is how many leading bytes ContentType looks at
+const HeadSize = 512
+
+type signature struct {
+	offset      int
+	magic       []byte
+	contentType string
+}
+
+// signatures are checked in order before the standard library, which takes executables for
+// application/octet-stream and scripts for text/plain
+var signatures = []signature{
+	{0, []byte("MZ"), "application/x-msdownload"},
+	{0, []byte("\x7fELF"), "application/x-elf"},
+	{0, []byte{0xfe, 0xed, 0xfa, 0xce}, "application/x-mach-binary"},
+	{0, []byte{0xfe, 0xed, 0xfa, 0xcf}, "application/x-mach-binary"},
+	{0, []byte{0xce, 0xfa, 0xed, 0xfe}, "application/x-mach-binary"},
+	{0, []byte{0xcf, 0xfa, 0xed, 0xfe}, "application/x-mach-binary"},
+	{0, []byte{0xca, 0xfe, 0xba, 0xbe}, "application/x-mach-binary"},
+	// An AMR recording, ahead of the scripts it would be taken for
+	{0, []byte("#!AMR"), "audio/amr"},
+	{0, []byte("#!"), "text/x-shellscript"},
+	{0, []byte("fLaC"), "audio/flac"},
+	{0, []byte{0xd0, 0xcf, 0x11, 0xe0, 0xa1, 0xb1, 0x1a, 0xe1}, "application/vnd.ms-office"},
+	{4, []byte("ftypM4A"), "audio/mp4"},
+	{4, []byte("ftypheic"), "image/heic"},
+	{4, []byte("ftypheix"), "image/heic"},
+	{4, []byte("ftypmif1"), "image/heif"},
+}
+
+// ContentType returns the media type of the content starting with head, without parameters.
+// Content nothing is known about is application/octet-stream.
+func ContentType(head []byte) string {
+	if len(head) > HeadSize {
+		head = head[:HeadSize]
+	}
+
+	for _, sig := range signatures {
+		if len(head) >= sig.offset+len(sig.magic) && bytes.Equal(head[sig.offset:sig.offset+len(sig.magic)], sig.magic) {
+			return sig.contentType
+		}
+	}
+
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mediaType
+}
+
+// Allowed tells whether the media type matches one of the patterns, either a type such as
+// application/pdf or a whole family such as image/*
+func Allowed(mediaType string, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if family, ok := strings.CutSuffix(pattern, "/*"); ok {
+			if strings.HasPrefix(mediaType, family+"/") {
+				return true
+			}
+			continue
+		}
+		if mediaType == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+// Package thumbnail renders JPEG thumbnails of JPEG, PNG and GIF images with the standard
+// library. Images are decoded whole, so their dimensions are checked against a budget first.
+// Transparent pixels are laid over white, JPEG has no alpha.
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+)
+
+const (
+	// maxPixels caps the images decoded, a 50 megapixel image takes about 200MB decoded
+	maxPixels = 50_000_000
+	quality   = 85
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("the image is not a JPEG, PNG or GIF")
+	ErrTooLarge          = errors.New("the image is too large to render thumbnails of")
+)
+
+// Decode reads an image, the first frame of an animated GIF
+func Decode(data []byte) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return nil, ErrUnsupportedFormat
+		}
+		return nil, fmt.Errorf("failed to read image header: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > maxPixels {
+		return nil, ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	return img, nil
+}
+
+// Fill crops the image to the aspect of width by height around its center, then scales it
+// down to width by height. An image smaller than that is cropped but not scaled up.
+func Fill(src image.Image, width, height int) image.Image {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+
+	cropWidth, cropHeight := srcWidth, srcWidth*height/width
+	if cropHeight > srcHeight {
+		cropWidth, cropHeight = srcHeight*width/height, srcHeight
+	}
+	cropWidth, cropHeight = max(cropWidth, 1), max(cropHeight, 1)
+
+	x0 := bounds.Min.X + (srcWidth-cropWidth)/2
+	y0 := bounds.Min.Y + (srcHeight-cropHeight)/2
+	area := image.Rect(x0, y0, x0+cropWidth, y0+cropHeight)
+
+	if cropWidth < width {
+		width, height = cropWidth, cropHeight
+	}
+
+	return scale(src, area, width, height)
+}
+
+// Fit scales the image down to fit in width by height, keeping its aspect. An image that
+// already fits keeps its size.
+func Fit(src image.Image, width, height int) image.Image {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	if srcWidth <= width && srcHeight <= height {
+		return scale(src, bounds, srcWidth, srcHeight)
+	}
+
+	fitWidth, fitHeight := width, srcHeight*width/srcWidth
+	if fitHeight > height {
+		fitWidth, fitHeight = srcWidth*height/srcHeight, height
+	}
+
+	return scale(src, bounds, max(fitWidth, 1), max(fitHeight, 1))
+}
+
+// Orient turns the image upright according to its EXIF orientation, 1 to 8
+func Orient(src image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dstWidth, dstHeight := w, h
+	// Orientations 5 to 8 swap the sides
+	if orientation >= 5 {
+		dstWidth, dstHeight = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			dst.Set(x, y, src.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+
+	return dst
+}
+
+// scale resamples an area of the image to width by height, each pixel averages the source
+// pixels it covers. Transparent pixels are laid over white, JPEG has no alpha.
+func scale(src image.Image, area image.Rectangle, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	areaWidth, areaHeight := area.Dx(), area.Dy()
+
+	for y := 0; y < height; y++ {
+		sy0 := area.Min.Y + y*areaHeight/height
+		sy1 := max(area.Min.Y+(y+1)*areaHeight/height, sy0+1)
+
+		for x := 0; x < width; x++ {
+			sx0 := area.Min.X + x*areaWidth/width
+			sx1 := max(area.Min.X+(x+1)*areaWidth/width, sx0+1)
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+
+			// The channels are premultiplied, adding the missing coverage in white composites them
+			white := 0xffff - a/n
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8((r/n + white) >> 8),
+				G: uint8((g/n + white) >> 8),
+				B: uint8((b/n + white) >> 8),
+				A: 0xff,
+			})
+		}
+	}
+
+	return dst
+}
+
+// EncodeJPEG writes the thumbnail as a JPEG
+func EncodeJPEG(w io.Writer, img image.Image) error {
+	return EncodeJPEGQuality(w, img, quality)
+}
+
+// EncodeJPEGQuality writes the image as a JPEG of the quality, 1 to 100
+func EncodeJPEGQuality(w io.Writer, img image.Image, quality int) error {
+	if err := jpeg.Encode(w, img, &jpeg.Options{Quality: quality}); err != nil {
+		return fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package transcribe
+
+import (
+	"context"
+	"path"
+	"strings"
+)
+
+// mediaFormats maps the extensions of the audio files voice notes are recorded as to the
+// format name providers expect
+var mediaFormats = map[string]string{
+	".mp3":  "mp3",
+	".mp4":  "mp4",
+	".m4a":  "m4a",
+	".wav":  "wav",
+	".flac": "flac",
+	".ogg":  "ogg",
+	".oga":  "ogg",
+	".opus": "ogg",
+	".amr":  "amr",
+	".webm": "webm",
+}
+
+// MediaFormat tells whether the file is audio that can be transcribed and in which format.
+// Recorders pick containers content sniffing can't tell apart from video, so the extension
+// decides.
+func MediaFormat(fileName string) (string, bool) {
+	format, ok := mediaFormats[strings.ToLower(path.Ext(fileName))]
+	return format, ok
+}
+
+// Audio is a voice note stored in the bucket
+type Audio struct {
+	// ID is stable across retries, providers name their jobs after it
+	ID     string
+	Bucket string
+	Key    string
+	Format string
+	// LanguageCode is the language spoken, empty when the provider should identify it
+	LanguageCode string
+}
+
+// Provider turns voice notes into text
+type Provider interface {
+	// Name is the provider as configured, e.g. aws
+	Name() string
+	// Transcribe blocks until the transcript is ready. Asking again for the same audio picks
+	// up the job already started.
+	Transcribe(ctx context.Context, audio Audio) (string, error)
+}
@@ -0,0 +1,273 @@
+// Package unfurl reads the preview card of a web page from its OpenGraph and HTML metadata.
+// Pages are fetched on behalf of users, so only public addresses are ever connected to.
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/lib/outbound"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	// maxBodyBytes is how much of a page is read, the head comes first
+	maxBodyBytes = 512 << 10
+	maxRedirects = 3
+	maxTitle     = 300
+	maxText      = 1000
+	userAgent    = "ExecuTaskBot/1.0 (+https://executask.app)"
+)
+
+var (
+	ErrUnsupportedURL = errors.New("only http and https links on the standard ports can be previewed")
+	ErrForbiddenHost  = errors.New("the link points at a private address")
+	ErrNotHTML        = errors.New("the link is not an HTML page")
+	ErrPageStatus     = errors.New("the page answered with an error")
+)
+
+// IsPageError tells whether the link can't be previewed although its site answered
+func IsPageError(err error) bool {
+	return errors.Is(err, ErrUnsupportedURL) || errors.Is(err, ErrForbiddenHost) ||
+		errors.Is(err, ErrNotHTML) || errors.Is(err, ErrPageStatus)
+}
+
+// Card is what a page tells about itself
+type Card struct {
+	// URL is where the page ended up after redirects
+	URL         string
+	Title       string
+	Description string
+	ImageURL    string
+	SiteName    string
+}
+
+type Client struct {
+	http *http.Client
+}
+
+// New fetches pages through a client pooling its connections per site. Pages are fetched with
+// GET, a site failing transiently is retried.
+func New(outboundCfg *config.OutboundConfig, timeout time.Duration) *Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		// The address is checked once resolved, a host can't be pointed elsewhere after the check
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !IsPublic(ip) {
+				return ErrForbiddenHost
+			}
+			return nil
+		},
+	}
+
+	return &Client{
+		http: outbound.New(outboundCfg, outbound.Options{
+			Timeout:     timeout,
+			DialContext: dialer.DialContext,
+			// Never through a proxy, it would connect to addresses the dialer can't check
+			Direct:                 true,
+			MaxResponseHeaderBytes: 64 << 10,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return fmt.Errorf("stopped after %d redirects", maxRedirects)
+				}
+				return checkURL(req.URL)
+			},
+		}),
+	}
+}
+
+// Fetch reads the card of the page at rawURL
+func (c *Client) Fetch(ctx context.Context, rawURL string) (*Card, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, ErrUnsupportedURL
+	}
+	if err := checkURL(target); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrPageStatus, resp.StatusCode)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, ErrNotHTML
+	}
+
+	body, err := charset.NewReader(io.LimitReader(resp.Body, maxBodyBytes), contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	card := parse(body, resp.Request.URL)
+	card.URL = resp.Request.URL.String()
+	return card, nil
+}
+
+// checkURL allows http and https on their standard ports, without credentials
+func checkURL(u *url.URL) error {
+	if u.User != nil || u.Hostname() == "" {
+		return ErrUnsupportedURL
+	}
+
+	switch u.Scheme {
+	case "http":
+		if port := u.Port(); port != "" && port != "80" {
+			return ErrUnsupportedURL
+		}
+	case "https":
+		if port := u.Port(); port != "" && port != "443" {
+			return ErrUnsupportedURL
+		}
+	default:
+		return ErrUnsupportedURL
+	}
+
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, not routable on the internet
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPublic tells whether the address is on the public internet, loopback, private, link-local
+// (cloud metadata services among them) and other special addresses are not
+func IsPublic(ip net.IP) bool {
+	return ip.IsGlobalUnicast() &&
+		!ip.IsPrivate() &&
+		!ip.IsLoopback() &&
+		!ip.IsLinkLocalUnicast() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// parse reads the metadata of the head of the page. OpenGraph tags win over the plain ones.
+func parse(body io.Reader, base *url.URL) *Card {
+	meta := map[string]string{}
+	title := ""
+	inTitle := false
+
+	tokenizer := html.NewTokenizer(body)
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return newCard(meta, title, base)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			switch string(name) {
+			case "body":
+				return newCard(meta, title, base)
+			case "title":
+				inTitle = title == ""
+			case "meta":
+				if !hasAttr {
+					continue
+				}
+				key, content := "", ""
+				for {
+					attr, value, more := tokenizer.TagAttr()
+					switch string(attr) {
+					case "property", "name":
+						key = strings.ToLower(string(value))
+					case "content":
+						content = string(value)
+					}
+					if !more {
+						break
+					}
+				}
+				if key != "" && meta[key] == "" {
+					meta[key] = content
+				}
+			}
+		case html.TextToken:
+			if inTitle {
+				title += string(tokenizer.Text())
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "head":
+				return newCard(meta, title, base)
+			}
+		}
+	}
+}
+
+func newCard(meta map[string]string, title string, base *url.URL) *Card {
+	first := func(keys ...string) string {
+		for _, key := range keys {
+			if value := clean(meta[key]); value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	card := &Card{
+		Title:       truncate(first("og:title", "twitter:title"), maxTitle),
+		Description: truncate(first("og:description", "twitter:description", "description"), maxText),
+		SiteName:    truncate(first("og:site_name", "application-name"), maxTitle),
+	}
+	if card.Title == "" {
+		card.Title = truncate(clean(title), maxTitle)
+	}
+
+	// Images may be relative to the page, only web images are kept
+	if image := first("og:image", "og:image:url", "twitter:image"); image != "" {
+		if resolved, err := base.Parse(image); err == nil && (resolved.Scheme == "http" || resolved.Scheme == "https") {
+			card.ImageURL = resolved.String()
+		}
+	}
+
+	return card
+}
+
+// clean collapses the whitespace of text
+func clean(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// truncate cuts text to at most limit bytes without splitting a character
+func truncate(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	text = text[:limit]
+	for !utf8.ValidString(text) {
+		text = text[:len(text)-1]
+	}
+	return strings.TrimSpace(text) + "…"
+}
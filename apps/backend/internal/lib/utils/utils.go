@@ -13,3 +13,19 @@ func PrintJSON(v interface{}) {
 	}
 	fmt.Println("JSON:", string(json))
 }
+
+// FormatSize renders a byte count the way people read it, e.g. 12.4 MB
+func FormatSize(bytes int64) string {
+	const unit = 1000
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "kMGTPE"[exp])
+}
@@ -1,10 +1,12 @@
 package logger
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/Sameer16536/ExecuTask/internal/config"
@@ -12,6 +14,7 @@ import (
 	"github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/pkgerrors"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // LoggerService manages New Relic integration and logger creation
@@ -61,23 +64,43 @@ func (ls *LoggerService) GetApplication() *newrelic.Application {
 	return ls.nrApp
 }
 
-// NewLoggerWithService creates a logger with full config and logger service
-func NewLoggerWithService(cfg *config.ObservabilityConfig, loggerService *LoggerService) zerolog.Logger {
-	var logLevel zerolog.Level
-	level := cfg.GetLogLevel()
+// currentLevel is the level of the application loggers, SetLevel changes it at runtime
+var currentLevel atomic.Int32
+
+// SetLevel changes the level of every logger created by NewLoggerWithService, unknown levels
+// fall back to info
+func SetLevel(level string) {
+	currentLevel.Store(int32(parseLevel(level)))
+}
+
+// levelHook drops events below the current level. The loggers themselves are built at debug
+// level, their own level can't change once created.
+type levelHook struct{}
 
+func (levelHook) Run(e *zerolog.Event, level zerolog.Level, _ string) {
+	if level != zerolog.NoLevel && level < zerolog.Level(currentLevel.Load()) {
+		e.Discard()
+	}
+}
+
+func parseLevel(level string) zerolog.Level {
 	switch level {
 	case "debug":
-		logLevel = zerolog.DebugLevel
+		return zerolog.DebugLevel
 	case "info":
-		logLevel = zerolog.InfoLevel
+		return zerolog.InfoLevel
 	case "warn":
-		logLevel = zerolog.WarnLevel
+		return zerolog.WarnLevel
 	case "error":
-		logLevel = zerolog.ErrorLevel
+		return zerolog.ErrorLevel
 	default:
-		logLevel = zerolog.InfoLevel
+		return zerolog.InfoLevel
 	}
+}
+
+// NewLoggerWithService creates a logger with full config and logger service
+func NewLoggerWithService(cfg *config.ObservabilityConfig, loggerService *LoggerService) zerolog.Logger {
+	SetLevel(cfg.GetLogLevel())
 
 	// Don't set global level - let each logger have its own level
 	zerolog.TimeFieldFormat = "2006-01-02 15:04:05"
@@ -107,7 +130,8 @@ func NewLoggerWithService(cfg *config.ObservabilityConfig, loggerService *Logger
 	// Note: New Relic log forwarding is now handled automatically by zerologWriter integration
 
 	logger := zerolog.New(writer).
-		Level(logLevel).
+		Level(zerolog.DebugLevel).
+		Hook(levelHook{}).
 		With().
 		Timestamp().
 		Str("service", cfg.ServiceName).
@@ -137,6 +161,20 @@ func WithTraceContext(logger zerolog.Logger, txn *newrelic.Transaction) zerolog.
 		Logger()
 }
 
+// WithSpanContext adds the OpenTelemetry trace and span of ctx to logger, it is unchanged
+// when ctx carries no span
+func WithSpanContext(logger zerolog.Logger, ctx context.Context) zerolog.Logger {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return logger
+	}
+
+	return logger.With().
+		Str("trace.id", spanContext.TraceID().String()).
+		Str("span.id", spanContext.SpanID().String()).
+		Logger()
+}
+
 // NewPgxLogger creates a database logger
 func NewPgxLogger(level zerolog.Level) zerolog.Logger {
 	writer := zerolog.ConsoleWriter{
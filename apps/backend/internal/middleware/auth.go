@@ -1,19 +1,75 @@
 package middleware
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"net/http"
 	"time"
 
 	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/i18n"
 	"github.com/Sameer16536/ExecuTask/internal/server"
 	"github.com/clerk/clerk-sdk-go/v2"
 	clerkhttp "github.com/clerk/clerk-sdk-go/v2/http"
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	// APIKeyHeader carries the secret of an API key, in place of a session token
+	APIKeyHeader = "X-API-Key"
+	// CSRFHeader echoes the CSRF cookie on the unsafe requests of cookie sessions
+	CSRFHeader = "X-CSRF-Token"
+)
+
+// identityKeys are the context values authentication sets, an Identity carries them over
+var identityKeys = []string{
+	UserIDKey, UserRoleKey, "permissions", SessionIDKey, WorkspaceIDKey, ImpersonatorIDKey, APIKeyIDKey,
+	CookieSessionKey,
+}
+
+// identityContextKey keys the verified identity in a request context, clients can't set it
+type identityContextKey struct{}
+
+// WithIdentity returns ctx carrying the identity authentication verified for the request of c.
+// Requests made in-process with it, such as the sub-requests of a batch, are authenticated as c
+// was without verifying a token or key again. ctx is returned as it is when c isn't
+// authenticated.
+func WithIdentity(ctx context.Context, c echo.Context) context.Context {
+	if GetUserID(c) == "" {
+		return ctx
+	}
+
+	values := map[string]any{}
+	for _, key := range identityKeys {
+		if value := c.Get(key); value != nil {
+			values[key] = value
+		}
+	}
+	return context.WithValue(ctx, identityContextKey{}, values)
+}
+
 type AuthMiddleware struct {
-	server *server.Server
+	server   *server.Server
+	guards   []SessionGuard
+	sessions []SessionRecorder
+	apiKeys  APIKeyAuthenticator
+}
+
+// SessionGuard sees every authenticated request before the recorders and may reject it, e.g.
+// writes to a frozen workspace
+type SessionGuard interface {
+	GuardSession(c echo.Context) error
+}
+
+// SessionRecorder sees every authenticated request, e.g. to record the start of a session
+type SessionRecorder interface {
+	RecordSession(c echo.Context)
+}
+
+// APIKeyAuthenticator resolves the secret of an API key to the user it belongs to
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(c echo.Context, secret string) (userID string, keyID string, err error)
 }
 
 func NewAuthMiddleware(s *server.Server) *AuthMiddleware {
@@ -22,21 +78,87 @@ func NewAuthMiddleware(s *server.Server) *AuthMiddleware {
 	}
 }
 
+// AddSessionGuard adds a guard, guards see requests in the order they were added
+func (auth *AuthMiddleware) AddSessionGuard(guard SessionGuard) {
+	auth.guards = append(auth.guards, guard)
+}
+
+// AddSessionRecorder adds a recorder, recorders see requests in the order they were added
+func (auth *AuthMiddleware) AddSessionRecorder(recorder SessionRecorder) {
+	auth.sessions = append(auth.sessions, recorder)
+}
+
+// SetAPIKeyAuthenticator lets requests authenticate with an API key, without it the header
+// is ignored
+func (auth *AuthMiddleware) SetAPIKeyAuthenticator(authenticator APIKeyAuthenticator) {
+	auth.apiKeys = authenticator
+}
+
+// RequireAuth authenticates the request with its Clerk session, or with its API key. Requests
+// made with a key act as the user owning it, outside of any workspace and without a role.
+// With cookie sessions enabled the session token may come from the session cookie instead of
+// the Authorization header.
 func (auth *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	withSession := auth.requireSession(next)
+	return func(c echo.Context) error {
+		// The identity verified for the request this one was made from takes precedence over
+		// any token or key the request carries
+		if values, ok := c.Request().Context().Value(identityContextKey{}).(map[string]any); ok {
+			for key, value := range values {
+				c.Set(key, value)
+			}
+			return auth.authenticated(c, next)
+		}
+
+		secret := c.Request().Header.Get(APIKeyHeader)
+		if secret == "" || auth.apiKeys == nil {
+			if err := auth.sessionFromCookie(c); err != nil {
+				return err
+			}
+			return withSession(c)
+		}
+
+		start := time.Now()
+		userID, keyID, err := auth.apiKeys.AuthenticateAPIKey(c, secret)
+		if err != nil {
+			auth.server.Logger.Error().
+				Err(err).
+				Str("function", "RequireAuth").
+				Str("request_id", GetRequestID(c)).
+				Dur("duration", time.Since(start)).
+				Msg("could not authenticate API key")
+			return err
+		}
+
+		c.Set(UserIDKey, userID)
+		c.Set(APIKeyIDKey, keyID)
+
+		auth.server.Logger.Info().
+			Str("function", "RequireAuth").
+			Str("user_id", userID).
+			Str("api_key_id", keyID).
+			Str("request_id", GetRequestID(c)).
+			Dur("duration", time.Since(start)).
+			Msg("user authenticated successfully with API key")
+
+		return auth.authenticated(c, next)
+	}
+}
+
+func (auth *AuthMiddleware) requireSession(next echo.HandlerFunc) echo.HandlerFunc {
 	return echo.WrapMiddleware(
 		clerkhttp.WithHeaderAuthorization(
 			clerkhttp.AuthorizationFailureHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				start := time.Now()
 
-				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Content-Type", errs.MIMEApplicationProblemJSON)
 				w.WriteHeader(http.StatusUnauthorized)
 
-				response := map[string]string{
-					"code":     "UNAUTHORIZED",
-					"message":  "Unauthorized",
-					"override": "false",
-					"status":   "401",
-				}
+				// The echo context isn't at hand, the locale is negotiated again
+				message := i18n.T(i18n.Negotiate(r.Header.Get(headerAcceptLanguage)), "Unauthorized")
+				response := errs.NewProblem(errs.NewUnauthorizedError(message, false),
+					http.StatusText(http.StatusUnauthorized), r.URL.Path)
+				response.RequestID = w.Header().Get(RequestIDHeader)
 
 				if err := json.NewEncoder(w).Encode(response); err != nil {
 					auth.server.Logger.Error().Err(err).Str("function", "RequireAuth").Dur(
@@ -61,6 +183,11 @@ func (auth *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc
 		c.Set("user_id", claims.Subject)
 		c.Set("user_role", claims.ActiveOrganizationRole)
 		c.Set("permissions", claims.Claims.ActiveOrganizationPermissions)
+		c.Set(SessionIDKey, claims.SessionID)
+		c.Set(WorkspaceIDKey, claims.ActiveOrganizationID)
+		if impersonatorID := impersonatorOf(claims); impersonatorID != "" {
+			c.Set(ImpersonatorIDKey, impersonatorID)
+		}
 
 		auth.server.Logger.Info().
 			Str("function", "RequireAuth").
@@ -69,6 +196,73 @@ func (auth *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc
 			Dur("duration", time.Since(start)).
 			Msg("user authenticated successfully")
 
-		return next(c)
+		return auth.authenticated(c, next)
 	})
 }
+
+// sessionFromCookie hands the token of the session cookie to Clerk when the request has no
+// Authorization header. Browsers send the cookie along with requests other sites make, so the
+// unsafe ones must also carry the CSRF token in a header, which other sites can't read.
+func (auth *AuthMiddleware) sessionFromCookie(c echo.Context) error {
+	cfg := auth.server.Config.Auth.Cookie
+	if cfg == nil || !cfg.Enabled || c.Request().Header.Get(echo.HeaderAuthorization) != "" {
+		return nil
+	}
+
+	session, err := c.Cookie(cfg.Name)
+	if err != nil || session.Value == "" {
+		return nil
+	}
+
+	switch c.Request().Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		token := c.Request().Header.Get(CSRFHeader)
+		csrf, err := c.Cookie(cfg.CSRFName)
+		if err != nil || token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(csrf.Value)) != 1 {
+			auth.server.Logger.Warn().
+				Str("function", "RequireAuth").
+				Str("request_id", GetRequestID(c)).
+				Msg("rejected cookie session request without a valid CSRF token")
+			return &errs.HTTPError{
+				Code:    errs.CodeCSRFTokenInvalid,
+				Message: "the CSRF token is missing or doesn't match the CSRF cookie",
+				Status:  http.StatusForbidden,
+			}
+		}
+	}
+
+	c.Request().Header.Set(echo.HeaderAuthorization, "Bearer "+session.Value)
+	c.Set(CookieSessionKey, true)
+	return nil
+}
+
+// authenticated passes an authenticated request through the guards and recorders
+func (auth *AuthMiddleware) authenticated(c echo.Context, next echo.HandlerFunc) error {
+	for _, guard := range auth.guards {
+		if err := guard.GuardSession(c); err != nil {
+			return err
+		}
+	}
+
+	for _, recorder := range auth.sessions {
+		recorder.RecordSession(c)
+	}
+
+	return next(c)
+}
+
+// impersonatorOf returns the user behind the act claim of an impersonation session
+func impersonatorOf(claims *clerk.SessionClaims) string {
+	if len(claims.Actor) == 0 {
+		return ""
+	}
+
+	var actor struct {
+		Subject string `json:"sub"`
+	}
+	if err := json.Unmarshal(claims.Actor, &actor); err != nil {
+		return ""
+	}
+	return actor.Subject
+}
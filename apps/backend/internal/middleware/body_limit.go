@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/openapi"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+type BodyLimitMiddleware struct {
+	server *server.Server
+	config *config.BodyLimitConfig
+}
+
+func NewBodyLimitMiddleware(s *server.Server) *BodyLimitMiddleware {
+	limitConfig := s.Config.Server.BodyLimits
+	if limitConfig == nil {
+		limitConfig = config.DefaultBodyLimitConfig()
+	}
+
+	return &BodyLimitMiddleware{
+		server: s,
+		config: limitConfig,
+	}
+}
+
+// Limit caps the request body of every route, the upload operations of the OpenAPI document
+// get the upload limit. A body announcing a larger size is rejected before it is read, one
+// growing past the limit fails the read with an *http.MaxBytesError.
+func (m *BodyLimitMiddleware) Limit(document *openapi.Document) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.Body == nil || req.Body == http.NoBody {
+				return next(c)
+			}
+
+			limit := m.config.DefaultBytes
+			if document.IsUpload(req.Method, c.Path()) {
+				limit = m.config.UploadBytes
+			}
+			if routeLimit, ok := m.config.Routes[c.Path()]; ok {
+				limit = routeLimit
+			}
+			if limit <= 0 {
+				return next(c)
+			}
+
+			if req.ContentLength > limit {
+				GetLogger(c).Warn().
+					Int64("content_length", req.ContentLength).
+					Int64("limit_bytes", limit).
+					Msg("request body over the limit of the route")
+				return errs.NewRequestTooLargeError("", false, nil)
+			}
+
+			req.Body = http.MaxBytesReader(c.Response(), req.Body, limit)
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/andybalholm/brotli"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+
+	// brotliLevel trades ratio for latency, the higher levels are meant for static assets
+	brotliLevel = 4
+)
+
+// supportedEncodings in order of preference when a client accepts several equally
+var supportedEncodings = []string{encodingBrotli, encodingGzip}
+
+var encoderPools = map[string]*sync.Pool{
+	encodingBrotli: {New: func() any { return brotli.NewWriterLevel(io.Discard, brotliLevel) }},
+	encodingGzip:   {New: func() any { return gzip.NewWriter(io.Discard) }},
+}
+
+// encoder is the part of gzip.Writer and brotli.Writer the middleware relies on
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+type CompressionMiddleware struct {
+	server *server.Server
+	config *config.CompressionConfig
+}
+
+func NewCompressionMiddleware(s *server.Server) *CompressionMiddleware {
+	compressionConfig := s.Config.Server.Compression
+	if compressionConfig == nil {
+		compressionConfig = config.DefaultCompressionConfig()
+	}
+
+	return &CompressionMiddleware{
+		server: s,
+		config: compressionConfig,
+	}
+}
+
+// Compress encodes response bodies with the best encoding the client accepts. Bodies are
+// buffered up to the minimum size, so small and non textual responses go out untouched.
+func (m *CompressionMiddleware) Compress(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if m.config.Disabled {
+			return next(c)
+		}
+
+		res := c.Response()
+		res.Header().Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+
+		encoding := negotiateEncoding(c.Request().Header.Get(echo.HeaderAcceptEncoding))
+		if encoding == "" || c.Request().Method == http.MethodHead {
+			return next(c)
+		}
+
+		writer := &compressWriter{
+			ResponseWriter: res.Writer,
+			encoding:       encoding,
+			minSize:        m.config.MinSizeBytes,
+		}
+		res.Writer = writer
+
+		defer func() {
+			if err := writer.Close(); err != nil {
+				GetLogger(c).Warn().Err(err).Msg("failed to finish compressed response")
+			}
+			res.Writer = writer.ResponseWriter
+		}()
+
+		return next(c)
+	}
+}
+
+// negotiateEncoding picks the supported encoding with the highest quality in an Accept-Encoding
+// header, "" when the client only takes identity
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+
+	qualities := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		qualities[name] = quality
+	}
+
+	best, bestQuality := "", 0.0
+	for _, encoding := range supportedEncodings {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality, ok = qualities["*"]
+		}
+		if ok && quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+
+	return best
+}
+
+// compressible reports whether a media type is worth compressing, binary formats such as
+// images and archives already are
+func compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "text/") && mediaType != "text/event-stream":
+		return true
+	case strings.HasSuffix(mediaType, "json"), strings.HasSuffix(mediaType, "+xml"):
+		return true
+	case mediaType == "application/xml", mediaType == "application/javascript":
+		return true
+	}
+	return false
+}
+
+// compressWriter holds back the status and the first bytes of the body until it knows
+// whether the response is worth compressing
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status  int
+	buffer  []byte
+	decided bool
+	encoder encoder
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.status = status
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+
+		w.buffer = append(w.buffer, p...)
+		if len(w.buffer) < w.minSize {
+			return len(p), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	if w.encoder != nil {
+		return w.encoder.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush sends what was written so far, streamed responses are compressed chunk by chunk
+func (w *compressWriter) Flush() {
+	if !w.decided && w.status != 0 {
+		if err := w.decide(true); err != nil {
+			return
+		}
+	}
+
+	if w.encoder != nil {
+		if err := w.encoder.Flush(); err != nil {
+			return
+		}
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to lift the write deadline
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Close sends a response that never reached the minimum size and finishes the encoded body
+func (w *compressWriter) Close() error {
+	if !w.decided {
+		// Nothing was written, the error handler writes its own response
+		if w.status == 0 {
+			return nil
+		}
+		if err := w.decide(len(w.buffer) >= w.minSize); err != nil {
+			return err
+		}
+	}
+
+	if w.encoder == nil {
+		return nil
+	}
+
+	err := w.encoder.Close()
+	w.encoder.Reset(io.Discard)
+	encoderPools[w.encoding].Put(w.encoder)
+	w.encoder = nil
+	return err
+}
+
+// decide sends the held back status and body, encoded when worthwhile and allowed
+func (w *compressWriter) decide(worthwhile bool) error {
+	w.decided = true
+
+	header := w.ResponseWriter.Header()
+	if worthwhile && len(w.buffer) > 0 && header.Get(echo.HeaderContentEncoding) == "" &&
+		compressible(header.Get(echo.HeaderContentType)) && bodyAllowed(w.status) {
+		w.encoder = encoderPools[w.encoding].Get().(encoder)
+		w.encoder.Reset(w.ResponseWriter)
+
+		header.Del(echo.HeaderContentLength)
+		header.Set(echo.HeaderContentEncoding, w.encoding)
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+
+	buffered := w.buffer
+	w.buffer = nil
+	if len(buffered) == 0 {
+		return nil
+	}
+
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(buffered)
+	} else {
+		_, err = w.ResponseWriter.Write(buffered)
+	}
+	return err
+}
+
+func bodyAllowed(status int) bool {
+	return status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+}
@@ -8,12 +8,18 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
-	UserIDKey   = "user_id"
-	UserRoleKey = "user_role"
-	LoggerKey   = "logger"
+	UserIDKey         = "user_id"
+	UserRoleKey       = "user_role"
+	SessionIDKey      = "session_id"
+	WorkspaceIDKey    = "workspace_id"
+	ImpersonatorIDKey = "impersonator_id"
+	APIKeyIDKey       = "api_key_id"
+	CookieSessionKey  = "cookie_session"
+	LoggerKey         = "logger"
 )
 
 type ContextEnhancer struct {
@@ -38,8 +44,10 @@ func (ce *ContextEnhancer) EnhanceContext() echo.MiddlewareFunc {
 				Str("ip", c.RealIP()).
 				Logger()
 
-			// Add trace context if available
-			if txn := newrelic.FromContext(c.Request().Context()); txn != nil {
+			// Add trace context if available, OpenTelemetry first as it follows the caller's trace
+			if trace.SpanContextFromContext(c.Request().Context()).IsValid() {
+				contextLogger = logger.WithSpanContext(contextLogger, c.Request().Context())
+			} else if txn := newrelic.FromContext(c.Request().Context()); txn != nil {
 				contextLogger = logger.WithTraceContext(contextLogger, txn)
 			}
 
@@ -87,6 +95,54 @@ func GetUserID(c echo.Context) string {
 	return ""
 }
 
+// GetUserRole returns the role of the user in their active Clerk organization, such as
+// org:admin, "" for personal accounts and API keys
+func GetUserRole(c echo.Context) string {
+	if userRole, ok := c.Get(UserRoleKey).(string); ok {
+		return userRole
+	}
+	return ""
+}
+
+func GetSessionID(c echo.Context) string {
+	if sessionID, ok := c.Get(SessionIDKey).(string); ok {
+		return sessionID
+	}
+	return ""
+}
+
+// GetWorkspaceID returns the active Clerk organization of the user, "" for personal accounts
+func GetWorkspaceID(c echo.Context) string {
+	if workspaceID, ok := c.Get(WorkspaceIDKey).(string); ok {
+		return workspaceID
+	}
+	return ""
+}
+
+// GetImpersonatorID returns the support user acting as the authenticated user, "" outside of
+// impersonation sessions
+func GetImpersonatorID(c echo.Context) string {
+	if impersonatorID, ok := c.Get(ImpersonatorIDKey).(string); ok {
+		return impersonatorID
+	}
+	return ""
+}
+
+// GetAPIKeyID returns the API key the request authenticated with, "" for sessions
+func GetAPIKeyID(c echo.Context) string {
+	if keyID, ok := c.Get(APIKeyIDKey).(string); ok {
+		return keyID
+	}
+	return ""
+}
+
+// IsCookieSession tells whether the request authenticated with the session cookie rather than
+// a header
+func IsCookieSession(c echo.Context) bool {
+	cookie, _ := c.Get(CookieSessionKey).(bool)
+	return cookie
+}
+
 func GetLogger(c echo.Context) *zerolog.Logger {
 	if logger, ok := c.Get(LoggerKey).(*zerolog.Logger); ok {
 		return logger
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderFaultInjected lists the faults injected into a response, so test clients can tell them
+// from real failures
+const HeaderFaultInjected = "X-Fault-Injected"
+
+// faultDatabaseCodes are the SQLSTATEs behind the injected database errors
+var faultDatabaseCodes = map[server.FaultKind]string{
+	server.FaultDatabaseBusy:         "53300",
+	server.FaultSerializationFailure: "40001",
+	server.FaultQueryTimeout:         "57014",
+}
+
+type FaultInjectionMiddleware struct {
+	server *server.Server
+}
+
+func NewFaultInjectionMiddleware(s *server.Server) *FaultInjectionMiddleware {
+	return &FaultInjectionMiddleware{
+		server: s,
+	}
+}
+
+// Inject applies the fault rules operators set to API routes. It does nothing unless fault
+// injection is enabled in the config, health checks and the admin API are never affected.
+func (m *FaultInjectionMiddleware) Inject(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !m.server.Config.Server.FaultInjection.Enabled || !strings.HasPrefix(c.Request().URL.Path, "/api/") {
+			return next(c)
+		}
+
+		rule := m.server.Faults(c.Request().Context()).Match(c.Request().Method, c.Path())
+		if rule == nil {
+			return next(c)
+		}
+
+		var injected []string
+		logger := GetLogger(c)
+
+		if roll(rule.LatencyPercent) && rule.Latency > 0 {
+			injected = append(injected, "latency")
+			c.Response().Header().Set(HeaderFaultInjected, strings.Join(injected, ","))
+
+			timer := time.NewTimer(rule.Latency)
+			select {
+			case <-timer.C:
+			case <-c.Request().Context().Done():
+				timer.Stop()
+				return c.Request().Context().Err()
+			}
+		}
+
+		if roll(rule.DropPercent) {
+			logger.Warn().
+				Str("event", "fault_injected").
+				Str("fault", "drop").
+				Str("route", c.Path()).
+				Msg("dropping response")
+
+			// net/http closes the connection without writing anything, the recover
+			// middleware passes this panic on
+			panic(http.ErrAbortHandler)
+		}
+
+		if roll(rule.ErrorPercent) {
+			injected = append(injected, "error")
+			c.Response().Header().Set(HeaderFaultInjected, strings.Join(injected, ","))
+
+			logger.Warn().
+				Str("event", "fault_injected").
+				Strs("faults", injected).
+				Str("error_kind", string(rule.ErrorKind)).
+				Str("route", c.Path()).
+				Msg("injecting error")
+
+			return faultError(rule.ErrorKind)
+		}
+
+		if len(injected) > 0 {
+			logger.Warn().
+				Str("event", "fault_injected").
+				Strs("faults", injected).
+				Dur("latency", rule.Latency).
+				Str("route", c.Path()).
+				Msg("injected latency")
+		}
+
+		return next(c)
+	}
+}
+
+// faultError fails like the real thing would, database errors go through the same mapping
+// as genuine ones
+func faultError(kind server.FaultKind) error {
+	if code, ok := faultDatabaseCodes[kind]; ok {
+		return fmt.Errorf("injected fault: %w", &pgconn.PgError{
+			Severity: "ERROR",
+			Code:     code,
+			Message:  "injected " + string(kind),
+		})
+	}
+	return errors.New("injected fault: internal error")
+}
+
+func roll(percent int) bool {
+	return percent > 0 && rand.IntN(100) < percent
+}
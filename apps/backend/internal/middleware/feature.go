@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+// FeatureFlagsKey holds the feature flags of the request once they were evaluated
+const FeatureFlagsKey = "feature_flags"
+
+// FlagEvaluator returns the feature flags of the authenticated user, keyed by name
+type FlagEvaluator interface {
+	FeatureFlags(c echo.Context) map[string]bool
+}
+
+type FeatureFlagMiddleware struct {
+	server    *server.Server
+	evaluator FlagEvaluator
+}
+
+func NewFeatureFlagMiddleware(s *server.Server) *FeatureFlagMiddleware {
+	return &FeatureFlagMiddleware{
+		server: s,
+	}
+}
+
+func (m *FeatureFlagMiddleware) SetEvaluator(evaluator FlagEvaluator) {
+	m.evaluator = evaluator
+}
+
+// Require hides the routes of a feature that is off for the user, as if they didn't exist.
+// It must run after RequireAuth.
+func (m *FeatureFlagMiddleware) Require(flag string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if m.evaluator == nil || !m.evaluator.FeatureFlags(c)[flag] {
+				GetLogger(c).Debug().
+					Str("feature", flag).
+					Str("path", c.Request().URL.Path).
+					Msg("feature disabled for user")
+				code := errs.CodeFeatureDisabled
+				return errs.NewNotFoundError("Not Found", false, &code)
+			}
+
+			return next(c)
+		}
+	}
+}
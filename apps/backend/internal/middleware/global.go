@@ -2,18 +2,30 @@ package middleware
 
 import (
 	"net/http"
+	"regexp"
+	"strings"
 
 	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/breaker"
+	"github.com/Sameer16536/ExecuTask/internal/lib/i18n"
 	"github.com/Sameer16536/ExecuTask/internal/server"
 	"github.com/Sameer16536/ExecuTask/internal/sqlerr"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
+	"golang.org/x/text/language"
 )
 
 type GlobalMiddlewares struct {
-	server *server.Server
+	server  *server.Server
+	origins OriginPolicy
+}
+
+// OriginPolicy allows browser origins on top of the configured ones, e.g. those of the API
+// clients registered by admins
+type OriginPolicy interface {
+	AllowsOrigin(origin string) bool
 }
 
 func NewGlobalMiddlewares(s *server.Server) *GlobalMiddlewares {
@@ -22,12 +34,42 @@ func NewGlobalMiddlewares(s *server.Server) *GlobalMiddlewares {
 	}
 }
 
+// SetOriginPolicy allows the origins of the policy, without it only the configured ones are
+func (global *GlobalMiddlewares) SetOriginPolicy(policy OriginPolicy) {
+	global.origins = policy
+}
+
+// CORS lets the configured origins call the API, those of the first party apps, then the ones
+// the origin policy allows. Cookie sessions need browsers to send cookies along with their
+// requests, which only listed origins may ask for.
 func (global *GlobalMiddlewares) CORS() echo.MiddlewareFunc {
+	cookie := global.server.Config.Auth.Cookie
+	configured := make([]*regexp.Regexp, 0, len(global.server.Config.Server.CORSAllowedOrigins))
+	for _, origin := range global.server.Config.Server.CORSAllowedOrigins {
+		configured = append(configured, originPattern(origin))
+	}
+
 	return middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: global.server.Config.Server.CORSAllowedOrigins,
+		AllowOriginFunc: func(origin string) (bool, error) {
+			for _, pattern := range configured {
+				if pattern.MatchString(origin) {
+					return true, nil
+				}
+			}
+			return global.origins != nil && global.origins.AllowsOrigin(origin), nil
+		},
+		AllowCredentials: cookie != nil && cookie.Enabled,
 	})
 }
 
+// originPattern matches an origin the way echo matches AllowOrigins, * and ? are wildcards
+func originPattern(origin string) *regexp.Regexp {
+	pattern := regexp.QuoteMeta(strings.ToLower(origin))
+	pattern = strings.ReplaceAll(pattern, `\*`, ".*")
+	pattern = strings.ReplaceAll(pattern, `\?`, ".")
+	return regexp.MustCompile("(?i)^" + pattern + "$")
+}
+
 func (global *GlobalMiddlewares) RequestLogger() echo.MiddlewareFunc {
 	return middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
 		LogURI:     true,
@@ -95,14 +137,23 @@ func (global *GlobalMiddlewares) Recover() echo.MiddlewareFunc {
 	return middleware.Recover()
 }
 
-func (global *GlobalMiddlewares) Secure() echo.MiddlewareFunc {
-	return middleware.Secure()
-}
-
 func (global *GlobalMiddlewares) GlobalErrorHandler(err error, c echo.Context) {
 	// First try to handle database errors and convert them to appropriate HTTP errors
 	originalErr := err
 
+	// A body over its limit fails wherever it is read, while binding or streaming an upload
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		err = errs.NewRequestTooLargeError("", false, nil)
+	}
+
+	// A third party whose breaker is open, or with too many calls in flight, wasn't called
+	if breaker.IsUnavailable(err) {
+		code := errs.CodeDependencyDown
+		err = errs.NewServiceUnavailableError("A service this request depends on is unavailable, please retry shortly",
+			false, &code)
+	}
+
 	// Try to handle known database errors
 	// Only do this for errors that haven't already been converted to HTTPError
 	var httpErr *errs.HTTPError
@@ -126,6 +177,8 @@ func (global *GlobalMiddlewares) GlobalErrorHandler(err error, c echo.Context) {
 	var message string
 	var fieldErrors []errs.FieldError
 	var action *errs.Action
+	var retryable bool
+	var detail string
 
 	switch {
 	case errors.As(err, &httpErr):
@@ -134,10 +187,12 @@ func (global *GlobalMiddlewares) GlobalErrorHandler(err error, c echo.Context) {
 		message = httpErr.Message
 		fieldErrors = httpErr.Errors
 		action = httpErr.Action
+		retryable = httpErr.Retryable
+		detail = httpErr.Detail
 
 	case errors.As(err, &echoErr):
 		status = echoErr.Code
-		code = errs.MakeUpperCaseWithUnderscores(http.StatusText(status))
+		code = errs.CodeForStatus(status)
 		if msg, ok := echoErr.Message.(string); ok {
 			message = msg
 		} else {
@@ -146,11 +201,21 @@ func (global *GlobalMiddlewares) GlobalErrorHandler(err error, c echo.Context) {
 
 	default:
 		status = http.StatusInternalServerError
-		code = errs.MakeUpperCaseWithUnderscores(
-			http.StatusText(http.StatusInternalServerError))
+		code = errs.CodeInternal
 		message = http.StatusText(http.StatusInternalServerError)
 	}
 
+	// Bare status texts give users nothing to act on, the catalogue message of the code does
+	if definition, ok := errs.Lookup(code); ok && (message == "" || message == http.StatusText(status)) {
+		message = definition.Message
+	}
+
+	// The underlying error stays out of the message, it goes to the logs and, outside
+	// production, to the debug member
+	if detail == "" && originalErr.Error() != message {
+		detail = originalErr.Error()
+	}
+
 	// Log the original error to help with debugging
 	// Use enhanced logger from context which already includes request_id, method, path, ip, user context, and trace context
 	logger := *GetLogger(c)
@@ -159,16 +224,60 @@ func (global *GlobalMiddlewares) GlobalErrorHandler(err error, c echo.Context) {
 		Err(originalErr).
 		Int("status", status).
 		Str("error_code", code).
+		Str("detail", detail).
 		Msg(message)
 
 	if !c.Response().Committed {
-		_ = c.JSON(status, errs.HTTPError{
-			Code:     code,
-			Message:  message,
-			Status:   status,
-			Override: httpErr != nil && httpErr.Override,
-			Errors:   fieldErrors,
-			Action:   action,
-		})
+		// Users read the messages in their locale, the logs keep them in English
+		locale := GetLocale(c)
+		message = i18n.T(locale, message)
+		fieldErrors = localizeFieldErrors(locale, fieldErrors)
+
+		problem := errs.NewProblem(&errs.HTTPError{
+			Code:      code,
+			Message:   message,
+			Status:    status,
+			Override:  httpErr != nil && httpErr.Override,
+			Errors:    fieldErrors,
+			Action:    action,
+			Retryable: retryable,
+		}, http.StatusText(status), c.Request().URL.Path)
+		problem.RequestID = GetRequestID(c)
+		if global.exposeDebug() {
+			problem.Debug = detail
+		}
+
+		// Errors are RFC 7807 problem details, c.JSON keeps an already set content type
+		c.Response().Header().Set(echo.HeaderContentType, errs.MIMEApplicationProblemJSON)
+		_ = c.JSON(status, problem)
+	}
+}
+
+// localizeFieldErrors formats the messages of field errors again in the locale
+func localizeFieldErrors(locale language.Tag, fieldErrors []errs.FieldError) []errs.FieldError {
+	if len(fieldErrors) == 0 {
+		return fieldErrors
+	}
+
+	localized := make([]errs.FieldError, len(fieldErrors))
+	for i, fieldError := range fieldErrors {
+		if fieldError.Message != "" {
+			fieldError.Error = i18n.T(locale, fieldError.Message, fieldError.Args...)
+		} else {
+			fieldError.Error = i18n.T(locale, fieldError.Error)
+		}
+		localized[i] = fieldError
+	}
+	return localized
+}
+
+// exposeDebug tells whether error details may reach clients, only local and development
+// servers show them
+func (global *GlobalMiddlewares) exposeDebug() bool {
+	switch global.server.Config.Primary.Env {
+	case "local", "development":
+		return true
+	default:
+		return false
 	}
 }
@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	idempotencyKeyMaxLength   = 255
+	idempotencyResponseTTL    = 24 * time.Hour
+	idempotencyLockTTL        = time.Minute
+	idempotencyRedisKeyPrefix = "idempotency"
+)
+
+type IdempotencyMiddleware struct {
+	server *server.Server
+}
+
+func NewIdempotencyMiddleware(s *server.Server) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		server: s,
+	}
+}
+
+type idempotentResponse struct {
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType"`
+	Body        []byte `json:"body"`
+}
+
+// idempotencyRecorder tees everything written to the client so it can be replayed later
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *idempotencyRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Idempotent caches the first successful response per Idempotency-Key and user,
+// and replays it for retries. Must run after RequireAuth.
+func (m *IdempotencyMiddleware) Idempotent(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := c.Request().Header.Get(IdempotencyKeyHeader)
+		if key == "" || c.Request().Method != http.MethodPost {
+			return next(c)
+		}
+
+		if len(key) > idempotencyKeyMaxLength {
+			return errs.NewBadRequestError("Idempotency-Key must be at most 255 characters", false, nil, nil, nil)
+		}
+
+		logger := GetLogger(c)
+		ctx := c.Request().Context()
+		redisKey := idempotencyRedisKeyPrefix + ":" + GetUserID(c) + ":" + key
+		lockKey := redisKey + ":lock"
+
+		fingerprint, err := requestFingerprint(c)
+		if err != nil {
+			return err
+		}
+
+		cached, err := m.server.Redis.Get(ctx, redisKey).Bytes()
+		switch {
+		case err == nil:
+			var response idempotentResponse
+			if err := json.Unmarshal(cached, &response); err != nil {
+				logger.Error().Err(err).Str("idempotency_key", key).Msg("failed to decode cached idempotent response")
+				return next(c)
+			}
+
+			if response.Fingerprint != fingerprint {
+				code := errs.CodeIdempotencyKeyReused
+				return errs.NewBadRequestError("Idempotency-Key was already used for a different request", false,
+					&code, nil, nil)
+			}
+
+			c.Response().Header().Set(IdempotentReplayedHeader, "true")
+			return c.Blob(response.Status, response.ContentType, response.Body)
+		case !errors.Is(err, redis.Nil):
+			// Redis being down shouldn't take writes down with it
+			logger.Error().Err(err).Str("idempotency_key", key).Msg("failed to read idempotent response")
+			return next(c)
+		}
+
+		acquired, err := m.server.Redis.SetNX(ctx, lockKey, fingerprint, idempotencyLockTTL).Result()
+		if err != nil {
+			logger.Error().Err(err).Str("idempotency_key", key).Msg("failed to acquire idempotency lock")
+			return next(c)
+		}
+		if !acquired {
+			code := errs.CodeIdempotencyKeyInUse
+			return errs.NewConflictError("a request with this Idempotency-Key is still being processed", false, &code)
+		}
+		defer func() {
+			if err := m.server.Redis.Del(ctx, lockKey).Err(); err != nil {
+				logger.Error().Err(err).Str("idempotency_key", key).Msg("failed to release idempotency lock")
+			}
+		}()
+
+		recorder := &idempotencyRecorder{ResponseWriter: c.Response().Writer}
+		c.Response().Writer = recorder
+
+		if err := next(c); err != nil {
+			// Errors are rendered later by the global error handler, let the client retry them
+			return err
+		}
+
+		status := c.Response().Status
+		if status >= http.StatusInternalServerError {
+			return nil
+		}
+
+		encoded, err := json.Marshal(idempotentResponse{
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: c.Response().Header().Get(echo.HeaderContentType),
+			Body:        recorder.body.Bytes(),
+		})
+		if err != nil {
+			logger.Error().Err(err).Str("idempotency_key", key).Msg("failed to encode idempotent response")
+			return nil
+		}
+
+		if err := m.server.Redis.Set(ctx, redisKey, encoded, idempotencyResponseTTL).Err(); err != nil {
+			logger.Error().Err(err).Str("idempotency_key", key).Msg("failed to store idempotent response")
+		}
+
+		return nil
+	}
+}
+
+// requestFingerprint hashes the method, path and body so a key can't be reused for a different
+// request. File uploads are streamed by their handlers, only their size is hashed.
+func requestFingerprint(c echo.Context) (string, error) {
+	req := c.Request()
+
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.Path + "\n"))
+
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+	if mediaType == echo.MIMEMultipartForm {
+		hash.Write([]byte(strconv.FormatInt(req.ContentLength, 10)))
+	} else if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return "", errs.NewRequestTooLargeError("", false, nil)
+			}
+			return "", errs.NewBadRequestError("failed to read request body", false, nil, nil, nil)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		hash.Write(body)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/lib/i18n"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/text/language"
+)
+
+const (
+	LocaleKey = "locale"
+
+	headerAcceptLanguage  = "Accept-Language"
+	headerContentLanguage = "Content-Language"
+)
+
+type LocaleMiddleware struct {
+	server *server.Server
+}
+
+func NewLocaleMiddleware(s *server.Server) *LocaleMiddleware {
+	return &LocaleMiddleware{
+		server: s,
+	}
+}
+
+// Negotiate picks the locale of the request from its Accept-Language header. The locale chosen
+// by the user overrides it once the request is authenticated.
+func (l *LocaleMiddleware) Negotiate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Response().Header().Add(echo.HeaderVary, headerAcceptLanguage)
+		SetLocale(c, RequestedLocale(c))
+		return next(c)
+	}
+}
+
+// RequestedLocale is the locale negotiated from the Accept-Language of the request
+func RequestedLocale(c echo.Context) language.Tag {
+	return i18n.Negotiate(c.Request().Header.Get(headerAcceptLanguage))
+}
+
+// SetLocale sets the locale the response is written in
+func SetLocale(c echo.Context, locale language.Tag) {
+	c.Set(LocaleKey, locale)
+	c.Response().Header().Set(headerContentLanguage, locale.String())
+}
+
+// GetLocale returns the locale the response is written in, the default outside of requests
+// that went through Negotiate
+func GetLocale(c echo.Context) language.Tag {
+	if locale, ok := c.Get(LocaleKey).(language.Tag); ok {
+		return locale
+	}
+	return i18n.Default
+}
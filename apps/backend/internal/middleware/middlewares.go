@@ -11,6 +11,20 @@ type Middlewares struct {
 	ContextEnhancer *ContextEnhancer
 	Tracing         *TracingMiddleware
 	RateLimit       *RateLimitMiddleware
+	Idempotency     *IdempotencyMiddleware
+	Version         *VersionMiddleware
+	RBAC            *RBACMiddleware
+	ReadRouting     *ReadRoutingMiddleware
+	Timeout         *TimeoutMiddleware
+	Compression     *CompressionMiddleware
+	PayloadBudget   *PayloadBudgetMiddleware
+	BodyLimit       *BodyLimitMiddleware
+	SecurityHeaders *SecurityHeadersMiddleware
+	FeatureFlags    *FeatureFlagMiddleware
+	Mode            *ModeMiddleware
+	Usage           *UsageMiddleware
+	FaultInjection  *FaultInjectionMiddleware
+	Locale          *LocaleMiddleware
 }
 
 func NewMiddlewares(s *server.Server) *Middlewares {
@@ -26,5 +40,19 @@ func NewMiddlewares(s *server.Server) *Middlewares {
 		ContextEnhancer: NewContextEnhancer(s),
 		Tracing:         NewTracingMiddleware(s, nrApp),
 		RateLimit:       NewRateLimitMiddleware(s),
+		Idempotency:     NewIdempotencyMiddleware(s),
+		Version:         NewVersionMiddleware(s),
+		RBAC:            NewRBACMiddleware(s),
+		ReadRouting:     NewReadRoutingMiddleware(s),
+		Timeout:         NewTimeoutMiddleware(s),
+		Compression:     NewCompressionMiddleware(s),
+		PayloadBudget:   NewPayloadBudgetMiddleware(s),
+		BodyLimit:       NewBodyLimitMiddleware(s),
+		SecurityHeaders: NewSecurityHeadersMiddleware(s),
+		FeatureFlags:    NewFeatureFlagMiddleware(s),
+		Mode:            NewModeMiddleware(s),
+		Usage:           NewUsageMiddleware(s),
+		FaultInjection:  NewFaultInjectionMiddleware(s),
+		Locale:          NewLocaleMiddleware(s),
 	}
 }
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+// modeExemptPrefixes stay served in every mode: health checks for the load balancer and the
+// admin API so operators can diagnose and switch the mode back
+var modeExemptPrefixes = []string{"/status", "/healthz", "/readyz", "/admin/"}
+
+type ModeMiddleware struct {
+	server *server.Server
+}
+
+func NewModeMiddleware(s *server.Server) *ModeMiddleware {
+	return &ModeMiddleware{
+		server: s,
+	}
+}
+
+// Enforce rejects requests the current server mode doesn't allow: everything in maintenance,
+// writes in read-only. Rejections are 503s with a Retry-After.
+func (m *ModeMiddleware) Enforce(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		path := c.Request().URL.Path
+		for _, prefix := range modeExemptPrefixes {
+			if strings.HasPrefix(path, prefix) {
+				return next(c)
+			}
+		}
+
+		state := m.server.Mode(c.Request().Context())
+
+		var code, message string
+		switch state.Mode {
+		case config.ModeMaintenance:
+			code = errs.CodeMaintenance
+			message = "The service is down for maintenance"
+		case config.ModeReadOnly:
+			if isSafeMethod(c.Request().Method) {
+				return next(c)
+			}
+			code = errs.CodeReadOnly
+			message = "The service is read-only for now, changes can't be saved"
+		default:
+			return next(c)
+		}
+
+		if state.Message != "" {
+			message = state.Message
+		}
+
+		retryAfter := m.server.Config.Server.Mode.RetryAfter
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+
+		GetLogger(c).Debug().
+			Str("mode", string(state.Mode)).
+			Str("method", c.Request().Method).
+			Str("path", path).
+			Msg("request rejected by server mode")
+
+		return errs.NewServiceUnavailableError(message, true, &code)
+	}
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+// payloadBudgetExemptKey marks responses that are expected to be large, such as streamed exports
+const payloadBudgetExemptKey = "payload_budget_exempt"
+
+var errPayloadOverBudget = errors.New("response payload over budget")
+
+type PayloadBudgetMiddleware struct {
+	server *server.Server
+	config *config.PayloadBudgetConfig
+}
+
+func NewPayloadBudgetMiddleware(s *server.Server) *PayloadBudgetMiddleware {
+	budgetConfig := s.Config.Server.PayloadBudget
+	if budgetConfig == nil {
+		budgetConfig = config.DefaultPayloadBudgetConfig()
+	}
+
+	return &PayloadBudgetMiddleware{
+		server: s,
+		config: budgetConfig,
+	}
+}
+
+// ExemptFromPayloadBudget excludes the rest of the response from the payload budget
+func ExemptFromPayloadBudget(c echo.Context) {
+	c.Set(payloadBudgetExemptKey, true)
+}
+
+// Guard measures the uncompressed size of every response and reports those over the budget,
+// they usually are list endpoints that lost their pagination. With Reject the oversized body is
+// never sent and the request fails instead.
+func (m *PayloadBudgetMiddleware) Guard(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if m.config.MaxBytes <= 0 {
+			return next(c)
+		}
+
+		res := c.Response()
+		writer := &budgetWriter{
+			ResponseWriter: res.Writer,
+			c:              c,
+			budget:         m.config.MaxBytes,
+			reject:         m.config.Reject,
+		}
+		res.Writer = writer
+		defer func() {
+			res.Writer = writer.ResponseWriter
+		}()
+
+		err := next(c)
+
+		// Bodiless responses never wrote, release the held back status
+		if !writer.rejected && writer.status != 0 {
+			writer.sendHeader()
+		}
+
+		if writer.exceeded {
+			GetLogger(c).Warn().
+				Str("event", "payload_over_budget").
+				Str("route", c.Path()).
+				Int64("size_bytes", writer.size).
+				Int64("budget_bytes", m.config.MaxBytes).
+				Bool("rejected", writer.rejected).
+				Msg("response payload exceeded its budget")
+		}
+
+		if writer.rejected {
+			// Nothing reached the client, let the error handler write the response
+			res.Writer = writer.ResponseWriter
+			res.Committed = false
+			res.Size = 0
+
+			return &errs.HTTPError{
+				Code:    errs.CodeResponseTooLarge,
+				Message: "The response is larger than allowed, narrow the request with filters or pagination",
+				Status:  http.StatusInternalServerError,
+			}
+		}
+
+		return err
+	}
+}
+
+// budgetWriter counts body bytes and, when rejecting, holds back the status until the first
+// write shows whether the body fits
+type budgetWriter struct {
+	http.ResponseWriter
+	c      echo.Context
+	budget int64
+	reject bool
+
+	status   int
+	size     int64
+	sent     bool
+	exceeded bool
+	rejected bool
+}
+
+func (w *budgetWriter) WriteHeader(status int) {
+	if !w.reject || w.sent {
+		w.sent = true
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.status = status
+}
+
+func (w *budgetWriter) Write(p []byte) (int, error) {
+	if w.rejected {
+		return 0, errPayloadOverBudget
+	}
+
+	w.size += int64(len(p))
+	if w.size > w.budget && !w.exceeded && !w.isExempt() {
+		w.exceeded = true
+
+		// Once the client has part of the body the response can only be logged
+		if w.reject && !w.sent {
+			w.rejected = true
+			return 0, errPayloadOverBudget
+		}
+	}
+
+	w.sendHeader()
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *budgetWriter) Flush() {
+	w.sendHeader()
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to lift the write deadline
+func (w *budgetWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *budgetWriter) sendHeader() {
+	if w.sent {
+		return
+	}
+	w.sent = true
+
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *budgetWriter) isExempt() bool {
+	exempt, _ := w.c.Get(payloadBudgetExemptKey).(bool)
+	return exempt
+}
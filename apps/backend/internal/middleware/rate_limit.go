@@ -1,17 +1,64 @@
 package middleware
 
 import (
+	"math"
+	"sync"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
 	"github.com/Sameer16536/ExecuTask/internal/server"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+	"golang.org/x/time/rate"
 )
 
 type RateLimitMiddleware struct {
 	server *server.Server
+	store  *reloadableRateLimiterStore
 }
 
 func NewRateLimitMiddleware(s *server.Server) *RateLimitMiddleware {
 	return &RateLimitMiddleware{
 		server: s,
+		store:  &reloadableRateLimiterStore{server: s},
+	}
+}
+
+// Store returns the per client IP limiter store, it follows the rate limit of the current config
+func (r *RateLimitMiddleware) Store() echoMiddleware.RateLimiterStore {
+	return r.store
+}
+
+// reloadableRateLimiterStore replaces its memory store when a reload changes the rate limit,
+// clients start over with a full burst
+type reloadableRateLimiterStore struct {
+	server *server.Server
+	mu     sync.Mutex
+	config config.RateLimitConfig
+	store  *echoMiddleware.RateLimiterMemoryStore
+}
+
+func (s *reloadableRateLimiterStore) Allow(identifier string) (bool, error) {
+	current := config.DefaultRateLimitConfig()
+	if cfg := s.server.CurrentConfig().Server.RateLimit; cfg != nil {
+		current = cfg
 	}
+
+	s.mu.Lock()
+	if s.store == nil || *current != s.config {
+		burst := current.Burst
+		if burst == 0 {
+			burst = int(math.Ceil(current.RequestsPerSecond))
+		}
+
+		s.config = *current
+		s.store = echoMiddleware.NewRateLimiterMemoryStoreWithConfig(echoMiddleware.RateLimiterMemoryStoreConfig{
+			Rate:  rate.Limit(current.RequestsPerSecond),
+			Burst: burst,
+		})
+	}
+	store := s.store
+	s.mu.Unlock()
+
+	return store.Allow(identifier)
 }
 
 func (r *RateLimitMiddleware) RecordRateLimitHit(endpoint string) {
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+// Role is a deployment wide role, unrelated to Clerk organization roles
+type Role string
+
+const (
+	RoleUser     Role = "user"
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+// rank orders roles so a higher role passes every check of a lower one
+func (r Role) rank() int {
+	switch r {
+	case RoleAdmin:
+		return 2
+	case RoleOperator:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type RBACMiddleware struct {
+	server *server.Server
+	roles  map[string]Role
+}
+
+func NewRBACMiddleware(s *server.Server) *RBACMiddleware {
+	rbacConfig := s.Config.RBAC
+	if rbacConfig == nil {
+		rbacConfig = config.DefaultRBACConfig()
+	}
+
+	roles := map[string]Role{}
+	for _, userID := range rbacConfig.Operators {
+		roles[userID] = RoleOperator
+	}
+	for _, userID := range rbacConfig.Admins {
+		roles[userID] = RoleAdmin
+	}
+
+	return &RBACMiddleware{
+		server: s,
+		roles:  roles,
+	}
+}
+
+// RoleOf returns the role granted to a user, RoleUser when none is configured
+func (m *RBACMiddleware) RoleOf(userID string) Role {
+	if role, ok := m.roles[userID]; ok {
+		return role
+	}
+	return RoleUser
+}
+
+// RequireRole rejects requests from users below the given role. It must run after RequireAuth.
+func (m *RBACMiddleware) RequireRole(role Role) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID := GetUserID(c)
+			granted := m.RoleOf(userID)
+
+			if userID == "" || granted.rank() < role.rank() {
+				m.server.Logger.Warn().
+					Str("function", "RequireRole").
+					Str("request_id", GetRequestID(c)).
+					Str("user_id", userID).
+					Str("required_role", string(role)).
+					Str("path", c.Request().URL.Path).
+					Msg("insufficient role")
+				return errs.NewForbiddenError("You don't have access to this resource", false)
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/database"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+const readYourWritesRedisKeyPrefix = "read_your_writes"
+
+type ReadRoutingMiddleware struct {
+	server *server.Server
+	window time.Duration
+}
+
+func NewReadRoutingMiddleware(s *server.Server) *ReadRoutingMiddleware {
+	replicaConfig := s.Config.Database.Replicas
+	if replicaConfig == nil {
+		replicaConfig = config.DefaultReplicaConfig()
+	}
+
+	return &ReadRoutingMiddleware{
+		server: s,
+		window: time.Duration(replicaConfig.ReadYourWritesSeconds) * time.Second,
+	}
+}
+
+// RouteReads sets the read policy of the request: writes stay on the primary and mark the
+// user as a recent writer, reads go to replicas unless the user wrote within the window
+func (m *ReadRoutingMiddleware) RouteReads(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if m.server.DB == nil || !m.server.DB.HasReplicas() {
+			return next(c)
+		}
+
+		req := c.Request()
+
+		switch req.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			ctx := database.WithReadYourWrites(req.Context(), func() bool {
+				return m.wroteRecently(c)
+			})
+			c.SetRequest(req.WithContext(ctx))
+			return next(c)
+		}
+
+		c.SetRequest(req.WithContext(database.WithPrimary(req.Context())))
+		err := next(c)
+
+		// The user is only known once the route's auth middleware ran
+		if userID := GetUserID(c); userID != "" {
+			key := readYourWritesRedisKeyPrefix + ":" + userID
+			if redisErr := m.server.Redis.Set(req.Context(), key, 1, m.window).Err(); redisErr != nil {
+				GetLogger(c).Warn().Err(redisErr).Msg("failed to record recent write, reads may be served by a lagging replica")
+			}
+		}
+
+		return err
+	}
+}
+
+func (m *ReadRoutingMiddleware) wroteRecently(c echo.Context) bool {
+	userID := GetUserID(c)
+	if userID == "" {
+		return false
+	}
+
+	n, err := m.server.Redis.Exists(c.Request().Context(), readYourWritesRedisKeyPrefix+":"+userID).Result()
+	if err != nil {
+		// Without the marker the primary is the only safe choice
+		GetLogger(c).Warn().Err(err).Msg("failed to check for recent writes, reading from the primary")
+		return true
+	}
+
+	return n > 0
+}
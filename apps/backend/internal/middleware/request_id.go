@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"github.com/Sameer16536/ExecuTask/internal/lib/correlation"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
@@ -8,19 +9,26 @@ import (
 const (
 	RequestIDHeader = "X-Request-ID"
 	RequestIDKey    = "request_id"
+	// maxRequestIDLength caps caller supplied IDs, they end up in every log line of the request
+	maxRequestIDLength = 128
 )
 
+// RequestID takes the caller's request ID or generates one, echoes it in the response and makes
+// it the correlation ID that jobs enqueued by the request carry on
 func RequestID() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			requestID := c.Request().Header.Get(RequestIDHeader)
-			if requestID == "" {
+			if !validRequestID(requestID) {
 				requestID = uuid.New().String() // 4c90fc3f-39cc-4b04-af21-c83ee64aa67e
 			}
 
 			c.Set(RequestIDKey, requestID)
 			c.Response().Header().Set(RequestIDHeader, requestID)
 
+			req := c.Request()
+			c.SetRequest(req.WithContext(correlation.WithID(req.Context(), requestID)))
+
 			return next(c)
 		}
 	}
@@ -32,3 +40,20 @@ func GetRequestID(c echo.Context) string {
 	}
 	return ""
 }
+
+// validRequestID accepts short IDs of URL safe characters, anything else could forge log fields
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range requestID {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
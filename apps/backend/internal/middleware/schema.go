@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/openapi"
+	"github.com/labstack/echo/v4"
+)
+
+// SchemaValidation rejects JSON request bodies that don't match the schema declared in the OpenAPI document
+func (global *GlobalMiddlewares) SchemaValidation(document *openapi.Document) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.Body == nil || !document.HasBody(req.Method, c.Path()) {
+				return next(c)
+			}
+
+			mediaType, _, _ := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+			if mediaType != echo.MIMEApplicationJSON {
+				return next(c)
+			}
+
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					return errs.NewRequestTooLargeError("", false, nil)
+				}
+				return errs.NewBadRequestError("failed to read request body", false, nil, nil, nil)
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			issues := document.ValidateBody(req.Method, c.Path(), body)
+			if len(issues) == 0 {
+				return next(c)
+			}
+
+			fieldErrors := make([]errs.FieldError, 0, len(issues))
+			for _, issue := range issues {
+				fieldErrors = append(fieldErrors, errs.FieldError{
+					Field:   issue.Field,
+					Code:    issue.Code,
+					Error:   fmt.Sprintf(issue.Message, issue.Args...),
+					Message: issue.Message,
+					Args:    issue.Args,
+				})
+			}
+
+			GetLogger(c).Warn().Int("issue_count", len(issues)).Msg("request body does not match schema")
+
+			return errs.NewValidationError(fieldErrors)
+		}
+	}
+}
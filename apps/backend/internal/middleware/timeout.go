@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+// requestBaseContextKey holds the request context before the deadline was applied
+const requestBaseContextKey = "request_base_context"
+
+type TimeoutMiddleware struct {
+	server *server.Server
+	config *config.TimeoutConfig
+}
+
+func NewTimeoutMiddleware(s *server.Server) *TimeoutMiddleware {
+	timeoutConfig := s.Config.Server.Timeouts
+	if timeoutConfig == nil {
+		timeoutConfig = config.DefaultTimeoutConfig()
+	}
+
+	return &TimeoutMiddleware{
+		server: s,
+		config: timeoutConfig,
+	}
+}
+
+// RequestContext applies the timeout policy to the request context, which handlers pass on to
+// every repository call: it ends at the request deadline and, unless detached, when the client disconnects
+func (m *TimeoutMiddleware) RequestContext(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+
+		base := req.Context()
+		if m.config.DetachOnDisconnect {
+			base = context.WithoutCancel(base)
+		}
+		c.Set(requestBaseContextKey, base)
+
+		ctx := base
+		if m.config.Request > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(base, m.config.Request)
+			defer cancel()
+		}
+
+		c.SetRequest(req.WithContext(ctx))
+		return next(c)
+	}
+}
+
+// LiftDeadline exempts the rest of the request from the request deadline, for responses such as
+// exports that legitimately run longer. Disconnects still cancel it.
+func LiftDeadline(c echo.Context) {
+	base, ok := c.Get(requestBaseContextKey).(context.Context)
+	if !ok {
+		return
+	}
+
+	req := c.Request()
+	c.SetRequest(req.WithContext(&liftedContext{Context: req.Context(), base: base}))
+}
+
+// liftedContext keeps the values of the request context but is only cancelled with base
+type liftedContext struct {
+	context.Context
+	base context.Context
+}
+
+func (l *liftedContext) Deadline() (time.Time, bool) {
+	return l.base.Deadline()
+}
+
+func (l *liftedContext) Done() <-chan struct{} {
+	return l.base.Done()
+}
+
+func (l *liftedContext) Err() error {
+	return l.base.Err()
+}
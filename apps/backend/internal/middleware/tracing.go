@@ -1,11 +1,21 @@
 package middleware
 
 import (
+	"fmt"
+	"net/http"
+
 	"github.com/labstack/echo/v4"
 	"github.com/newrelic/go-agent/v3/integrations/nrecho-v4"
 	"github.com/newrelic/go-agent/v3/integrations/nrpkgerrors"
 	"github.com/newrelic/go-agent/v3/newrelic"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/Sameer16536/ExecuTask/internal/lib/tracing"
 	"github.com/Sameer16536/ExecuTask/internal/server"
 )
 
@@ -32,6 +42,53 @@ func (tm *TracingMiddleware) NewRelicMiddleware() echo.MiddlewareFunc {
 	return nrecho.Middleware(tm.nrApp)
 }
 
+// OpenTelemetryMiddleware starts a server span per request, continuing the caller's trace from
+// the traceparent header. Handlers, services and repositories start their spans from the
+// request context, so they nest under it.
+func (tm *TracingMiddleware) OpenTelemetryMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			route := c.Path()
+
+			ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+			ctx, span := tracing.Tracer().Start(ctx, req.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(req.Method),
+					semconv.HTTPRoute(route),
+					semconv.URLPath(req.URL.Path),
+					semconv.ClientAddress(c.RealIP()),
+					semconv.UserAgentOriginal(req.UserAgent()),
+					attribute.String("request.id", GetRequestID(c)),
+				),
+			)
+			defer span.End()
+
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+			if err != nil {
+				// Let the error handler write the response now, its status belongs on the span
+				c.Error(err)
+				err = nil
+			}
+
+			status := c.Response().Status
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+			if userID, ok := c.Get(UserIDKey).(string); ok && userID != "" {
+				span.SetAttributes(attribute.String("user.id", userID))
+			}
+			// Client errors are the caller's, only server errors fail the span
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, fmt.Sprintf("%d %s", status, http.StatusText(status)))
+			}
+
+			return err
+		}
+	}
+}
+
 // EnhanceTracing adds custom attributes to New Relic transactions
 func (tm *TracingMiddleware) EnhanceTracing() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
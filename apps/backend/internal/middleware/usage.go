@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/usage"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+// UsageMiddleware meters API calls per user and workspace
+type UsageMiddleware struct {
+	server *server.Server
+}
+
+func NewUsageMiddleware(s *server.Server) *UsageMiddleware {
+	return &UsageMiddleware{
+		server: s,
+	}
+}
+
+// Meter counts authenticated API calls once they are handled, failed ones included. It runs
+// globally, the user is known by the time the route's auth middleware returned.
+func (m *UsageMiddleware) Meter(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		err := next(c)
+
+		if strings.HasPrefix(c.Request().URL.Path, "/api/") {
+			m.server.Usage.Record(GetUserID(c), GetWorkspaceID(c), usage.MetricAPICalls, 1)
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	APIVersionKey    = "api_version"
+	APIVersionHeader = "API-Version"
+
+	APIVersionV1 = "v1"
+	APIVersionV2 = "v2"
+)
+
+// DeprecationPolicy describes how a deprecated API version is advertised to clients
+type DeprecationPolicy struct {
+	DeprecatedAt time.Time
+	SunsetAt     *time.Time
+	Link         string
+}
+
+type VersionMiddleware struct {
+	server       *server.Server
+	deprecations map[string]DeprecationPolicy
+}
+
+func NewVersionMiddleware(s *server.Server) *VersionMiddleware {
+	apiConfig := s.Config.API
+	if apiConfig == nil {
+		apiConfig = config.DefaultAPIConfig()
+	}
+
+	deprecations := map[string]DeprecationPolicy{}
+
+	if apiConfig.V1DeprecatedAt != "" {
+		deprecatedAt, err := time.Parse(time.DateOnly, apiConfig.V1DeprecatedAt)
+		if err != nil {
+			s.Logger.Error().Err(err).Str("value", apiConfig.V1DeprecatedAt).Msg("invalid v1 deprecation date, ignoring")
+		} else {
+			policy := DeprecationPolicy{
+				DeprecatedAt: deprecatedAt,
+				Link:         apiConfig.DeprecationLink,
+			}
+
+			if apiConfig.V1SunsetAt != "" {
+				sunsetAt, err := time.Parse(time.DateOnly, apiConfig.V1SunsetAt)
+				if err != nil {
+					s.Logger.Error().Err(err).Str("value", apiConfig.V1SunsetAt).Msg("invalid v1 sunset date, ignoring")
+				} else {
+					policy.SunsetAt = &sunsetAt
+				}
+			}
+
+			deprecations[APIVersionV1] = policy
+		}
+	}
+
+	return &VersionMiddleware{
+		server:       s,
+		deprecations: deprecations,
+	}
+}
+
+// Serve tags the request with the API version and advertises deprecation
+// (RFC 9745) and sunset (RFC 8594) information for deprecated versions.
+func (v *VersionMiddleware) Serve(version string) echo.MiddlewareFunc {
+	policy, deprecated := v.deprecations[version]
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(APIVersionKey, version)
+
+			header := c.Response().Header()
+			header.Set(APIVersionHeader, version)
+
+			if deprecated {
+				header.Set("Deprecation", "@"+strconv.FormatInt(policy.DeprecatedAt.Unix(), 10))
+
+				if policy.SunsetAt != nil {
+					header.Set("Sunset", policy.SunsetAt.UTC().Format(http.TimeFormat))
+				}
+
+				if policy.Link != "" {
+					header.Add("Link", "<"+policy.Link+`>; rel="deprecation"; type="text/html"`)
+				}
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// GetAPIVersion returns the API version the request was routed through
+func GetAPIVersion(c echo.Context) string {
+	if version, ok := c.Get(APIVersionKey).(string); ok {
+		return version
+	}
+	return APIVersionV1
+}
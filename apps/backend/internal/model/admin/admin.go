@@ -0,0 +1,199 @@
+package admin
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/workspace"
+)
+
+// User is the operator view of an account: the Clerk profile next to what it owns here
+type User struct {
+	ID           string     `json:"id"`
+	Email        *string    `json:"email"`
+	FirstName    *string    `json:"firstName"`
+	LastName     *string    `json:"lastName"`
+	Role         string     `json:"role"`
+	Banned       bool       `json:"banned"`
+	Locked       bool       `json:"locked"`
+	CreatedAt    *time.Time `json:"createdAt"`
+	LastSignInAt *time.Time `json:"lastSignInAt"`
+	Stats        UserStats  `json:"stats"`
+}
+
+type UserStats struct {
+	Todos           int        `json:"todos" db:"todos"`
+	CompletedTodos  int        `json:"completedTodos" db:"completed_todos"`
+	Categories      int        `json:"categories" db:"categories"`
+	Comments        int        `json:"comments" db:"comments"`
+	Attachments     int        `json:"attachments" db:"attachments"`
+	AttachmentBytes int64      `json:"attachmentBytes" db:"attachment_bytes"`
+	LastActivityAt  *time.Time `json:"lastActivityAt" db:"last_activity_at"`
+}
+
+// StorageQuota overrides the attachment storage quota of the config for one user
+type StorageQuota struct {
+	UserID string `json:"userId" db:"user_id"`
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+	// QuotaBytes caps the attachment bytes across the todos of the user, 0 is unlimited
+	QuotaBytes int64  `json:"quotaBytes" db:"quota_bytes"`
+	UpdatedBy  string `json:"updatedBy" db:"updated_by"`
+}
+
+// UserStorage is where a user stands against the attachment storage quota
+type UserStorage struct {
+	UserID    string `json:"userId"`
+	UsedBytes int64  `json:"usedBytes"`
+	// QuotaBytes is the quota in effect, 0 is unlimited
+	QuotaBytes int64 `json:"quotaBytes"`
+	// DefaultQuotaBytes is the quota of the config, it applies when there is no override
+	DefaultQuotaBytes int64         `json:"defaultQuotaBytes"`
+	Override          *StorageQuota `json:"override"`
+}
+
+// Workspace is the operator view of a workspace (Clerk organization): what it holds here and
+// whether it is frozen
+type Workspace struct {
+	ID     string            `json:"id"`
+	Stats  WorkspaceStats    `json:"stats"`
+	Freeze *workspace.Freeze `json:"freeze"`
+}
+
+type WorkspaceStats struct {
+	Members         int        `json:"members" db:"members"`
+	Todos           int        `json:"todos" db:"todos"`
+	CompletedTodos  int        `json:"completedTodos" db:"completed_todos"`
+	Templates       int        `json:"templates" db:"templates"`
+	AgingPolicies   int        `json:"agingPolicies" db:"aging_policies"`
+	PendingInvites  int        `json:"pendingInvites" db:"pending_invites"`
+	Webhooks        int        `json:"webhooks" db:"webhooks"`
+	Attachments     int        `json:"attachments" db:"attachments"`
+	AttachmentBytes int64      `json:"attachmentBytes" db:"attachment_bytes"`
+	LastActivityAt  *time.Time `json:"lastActivityAt" db:"last_activity_at"`
+}
+
+// Job is a background task that failed and is waiting for a retry or was archived
+type Job struct {
+	ID            string     `json:"id"`
+	Queue         string     `json:"queue"`
+	Type          string     `json:"type"`
+	State         string     `json:"state"`
+	Payload       string     `json:"payload"`
+	MaxRetry      int        `json:"maxRetry"`
+	Retried       int        `json:"retried"`
+	LastError     string     `json:"lastError"`
+	LastFailedAt  *time.Time `json:"lastFailedAt"`
+	NextProcessAt *time.Time `json:"nextProcessAt"`
+}
+
+// TablePartition is one partition of a partitioned table, or the table itself when it isn't
+// partitioned. Tuple counts are the statistics collector's estimates.
+type TablePartition struct {
+	Name          string     `json:"name" db:"name"`
+	LiveTuples    int64      `json:"liveTuples" db:"live_tuples"`
+	DeadTuples    int64      `json:"deadTuples" db:"dead_tuples"`
+	TableBytes    int64      `json:"tableBytes" db:"table_bytes"`
+	IndexBytes    int64      `json:"indexBytes" db:"index_bytes"`
+	LastVacuumAt  *time.Time `json:"lastVacuumAt" db:"last_vacuum_at"`
+	LastAnalyzeAt *time.Time `json:"lastAnalyzeAt" db:"last_analyze_at"`
+}
+
+// RuntimeStats is a snapshot of the process: scheduler, heap, garbage collector and the
+// connection pools it holds
+type RuntimeStats struct {
+	Timestamp  time.Time         `json:"timestamp"`
+	GoVersion  string            `json:"goVersion"`
+	Goroutines int               `json:"goroutines"`
+	GOMAXPROCS int               `json:"gomaxprocs"`
+	NumCPU     int               `json:"numCpu"`
+	Memory     MemoryStats       `json:"memory"`
+	GC         GCStats           `json:"gc"`
+	Database   DatabasePoolStats `json:"database"`
+	Redis      *RedisPoolStats   `json:"redis"`
+}
+
+type MemoryStats struct {
+	HeapAllocBytes    uint64 `json:"heapAllocBytes"`
+	HeapInuseBytes    uint64 `json:"heapInuseBytes"`
+	HeapIdleBytes     uint64 `json:"heapIdleBytes"`
+	HeapReleasedBytes uint64 `json:"heapReleasedBytes"`
+	HeapObjects       uint64 `json:"heapObjects"`
+	StackInuseBytes   uint64 `json:"stackInuseBytes"`
+	TotalAllocBytes   uint64 `json:"totalAllocBytes"`
+	SysBytes          uint64 `json:"sysBytes"`
+	Mallocs           uint64 `json:"mallocs"`
+	Frees             uint64 `json:"frees"`
+}
+
+type GCStats struct {
+	NumGC        uint32     `json:"numGc"`
+	NumForcedGC  uint32     `json:"numForcedGc"`
+	NextGCBytes  uint64     `json:"nextGcBytes"`
+	PauseTotalMs float64    `json:"pauseTotalMs"`
+	LastPauseMs  float64    `json:"lastPauseMs"`
+	LastGCAt     *time.Time `json:"lastGcAt"`
+	CPUFraction  float64    `json:"cpuFraction"`
+}
+
+type DatabasePoolStats struct {
+	Primary PoolStats `json:"primary"`
+	// Replicas are keyed by host
+	Replicas map[string]PoolStats `json:"replicas"`
+}
+
+type PoolStats struct {
+	TotalConns           int32   `json:"totalConns"`
+	IdleConns            int32   `json:"idleConns"`
+	AcquiredConns        int32   `json:"acquiredConns"`
+	ConstructingConns    int32   `json:"constructingConns"`
+	MaxConns             int32   `json:"maxConns"`
+	AcquireCount         int64   `json:"acquireCount"`
+	EmptyAcquireCount    int64   `json:"emptyAcquireCount"`
+	CanceledAcquireCount int64   `json:"canceledAcquireCount"`
+	AcquireDurationMs    float64 `json:"acquireDurationMs"`
+}
+
+type RedisPoolStats struct {
+	Hits       uint32 `json:"hits"`
+	Misses     uint32 `json:"misses"`
+	Timeouts   uint32 `json:"timeouts"`
+	TotalConns uint32 `json:"totalConns"`
+	IdleConns  uint32 `json:"idleConns"`
+	StaleConns uint32 `json:"staleConns"`
+}
+
+// ServerMode is the maintenance or read-only switch every instance follows
+type ServerMode struct {
+	Mode      string     `json:"mode"`
+	Message   string     `json:"message"`
+	UpdatedBy *string    `json:"updatedBy"`
+	UpdatedAt *time.Time `json:"updatedAt"`
+}
+
+// FaultInjection is the set of fault rules every instance applies to API routes until they expire
+type FaultInjection struct {
+	// Enabled tells whether the config allows fault injection on this deployment
+	Enabled   bool        `json:"enabled"`
+	Rules     []FaultRule `json:"rules"`
+	UpdatedBy *string     `json:"updatedBy"`
+	UpdatedAt *time.Time  `json:"updatedAt"`
+	ExpiresAt *time.Time  `json:"expiresAt"`
+}
+
+type FaultRule struct {
+	Route          string `json:"route"`
+	Method         string `json:"method"`
+	LatencyPercent int    `json:"latencyPercent"`
+	LatencyMs      int64  `json:"latencyMs"`
+	ErrorPercent   int    `json:"errorPercent"`
+	ErrorKind      string `json:"errorKind"`
+	DropPercent    int    `json:"dropPercent"`
+}
+
+// ConfigReload lists the config keys a reload changed. Values are left out, some are secrets.
+type ConfigReload struct {
+	Applied []string `json:"applied"`
+	// RestartRequired changed in the sources but only take effect after a restart
+	RestartRequired []string `json:"restartRequired"`
+}
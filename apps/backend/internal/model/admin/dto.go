@@ -0,0 +1,209 @@
+package admin
+
+import (
+	"strconv"
+
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+)
+
+const (
+	JobStateRetry    = "retry"
+	JobStateArchived = "archived"
+)
+
+// ------------------------------------------------------------
+
+type GetUserPayload struct {
+	ID string `param:"id" validate:"required,min=1,max=255"`
+}
+
+func (p *GetUserPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetStorageQuotaPayload struct {
+	UserID string `param:"id" validate:"required,min=1,max=255"`
+}
+
+func (p *GetStorageQuotaPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type SetStorageQuotaPayload struct {
+	UserID string `param:"id" validate:"required,min=1,max=255"`
+	// QuotaBytes replaces the quota of the config for the user, 0 lifts it
+	QuotaBytes *int64 `json:"quotaBytes" validate:"required,min=0"`
+}
+
+func (p *SetStorageQuotaPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type DeleteStorageQuotaPayload struct {
+	UserID string `param:"id" validate:"required,min=1,max=255"`
+}
+
+func (p *DeleteStorageQuotaPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetWorkspacePayload struct {
+	WorkspaceID string `param:"workspaceId" validate:"required,min=1,max=255"`
+}
+
+func (p *GetWorkspacePayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetJobsQuery struct {
+	Queue *string `query:"queue" validate:"omitempty,oneof=critical default low"`
+	State *string `query:"state" validate:"omitempty,oneof=retry archived"`
+	Page  *int    `query:"page" validate:"omitempty,min=1"`
+	Limit *int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+func (q *GetJobsQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	// Set defaults
+	if q.Queue == nil {
+		defaultQueue := "default"
+		q.Queue = &defaultQueue
+	}
+	if q.State == nil {
+		defaultState := JobStateRetry
+		q.State = &defaultState
+	}
+	if q.Page == nil {
+		defaultPage := 1
+		q.Page = &defaultPage
+	}
+	if q.Limit == nil {
+		defaultLimit := 20
+		q.Limit = &defaultLimit
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type RetryJobPayload struct {
+	Queue string `param:"queue" validate:"required,oneof=critical default low"`
+	ID    string `param:"id" validate:"required,min=1,max=255"`
+}
+
+func (p *RetryJobPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetRuntimeStatsPayload struct{}
+
+func (p *GetRuntimeStatsPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetServerModePayload struct{}
+
+func (p *GetServerModePayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type SetServerModePayload struct {
+	Mode string `json:"mode" validate:"required,oneof=normal maintenance read_only"`
+	// Message replaces the generic one rejected clients get
+	Message string `json:"message" validate:"max=500"`
+}
+
+func (p *SetServerModePayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetFaultInjectionPayload struct{}
+
+func (p *GetFaultInjectionPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type FaultRulePayload struct {
+	// Route is the registered path, e.g. /api/v1/todos/:id, * matches every API route
+	Route          string `json:"route" validate:"required,max=200"`
+	Method         string `json:"method" validate:"omitempty,oneof=GET POST PUT PATCH DELETE"`
+	LatencyPercent int    `json:"latencyPercent" validate:"min=0,max=100"`
+	LatencyMs      int64  `json:"latencyMs" validate:"min=0,max=60000"`
+	ErrorPercent   int    `json:"errorPercent" validate:"min=0,max=100"`
+	// ErrorKind defaults to database_busy
+	ErrorKind   string `json:"errorKind" validate:"omitempty,oneof=database_busy serialization_failure query_timeout internal"`
+	DropPercent int    `json:"dropPercent" validate:"min=0,max=100"`
+}
+
+type SetFaultInjectionPayload struct {
+	// Rules are matched in order, the first one covering a request applies
+	Rules []FaultRulePayload `json:"rules" validate:"required,min=1,max=20,dive"`
+	// TTLSeconds is how long the rules stay active, capped by the configured maximum
+	TTLSeconds *int `json:"ttlSeconds" validate:"omitempty,min=1"`
+}
+
+func (p *SetFaultInjectionPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	var fieldErrors validation.CustomValidationErrors
+	for i, rule := range p.Rules {
+		field := "rules[" + strconv.Itoa(i) + "]"
+		if rule.LatencyPercent == 0 && rule.ErrorPercent == 0 && rule.DropPercent == 0 {
+			fieldErrors = append(fieldErrors, validation.CustomValidationError{
+				Field: field, Message: "must inject latency, errors or drops",
+			})
+		}
+		if rule.LatencyPercent > 0 && rule.LatencyMs == 0 {
+			fieldErrors = append(fieldErrors, validation.CustomValidationError{
+				Field: field + ".latencyMs", Message: "is required when latencyPercent is set",
+			})
+		}
+	}
+	if len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type ClearFaultInjectionPayload struct{}
+
+func (p *ClearFaultInjectionPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type ReloadConfigPayload struct{}
+
+func (p *ReloadConfigPayload) Validate() error {
+	return nil
+}
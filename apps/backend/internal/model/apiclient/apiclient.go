@@ -0,0 +1,38 @@
+package apiclient
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+)
+
+// APIClient is a frontend registered to call the API from browsers of its own origins, such as an
+// embedded widget or a partner app
+type APIClient struct {
+	model.BaseWithId
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+	Name string `json:"name" db:"name"`
+	// AllowedOrigins are allowed by CORS while the client is enabled
+	AllowedOrigins []string `json:"allowedOrigins" db:"allowed_origins"`
+	Enabled        bool     `json:"enabled" db:"enabled"`
+	CreatedBy      string   `json:"-" db:"created_by"`
+}
+
+// NormalizeOrigin returns an origin the way browsers send it in the Origin header, lowercase
+// and without a path. ok is false for anything else than an http or https origin.
+func NormalizeOrigin(origin string) (normalized string, ok bool) {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" || u.User != nil || (u.Path != "" && u.Path != "/") ||
+		u.RawQuery != "" || u.Fragment != "" {
+		return "", false
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return "", false
+	}
+
+	return scheme + "://" + strings.ToLower(u.Host), true
+}
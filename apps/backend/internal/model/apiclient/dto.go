@@ -0,0 +1,101 @@
+package apiclient
+
+import (
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type GetAPIClientsPayload struct{}
+
+func (p *GetAPIClientsPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type CreateAPIClientPayload struct {
+	Name           string   `json:"name" validate:"required,min=1,max=100"`
+	AllowedOrigins []string `json:"allowedOrigins" validate:"required,min=1,max=20,dive,min=1,max=255"`
+	// Enabled is true when nil
+	Enabled *bool `json:"enabled"`
+}
+
+func (p *CreateAPIClientPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	origins, err := normalizeOrigins(p.AllowedOrigins)
+	if err != nil {
+		return err
+	}
+	p.AllowedOrigins = origins
+
+	if p.Enabled == nil {
+		enabled := true
+		p.Enabled = &enabled
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type UpdateAPIClientPayload struct {
+	ID             uuid.UUID `param:"id" validate:"required,uuid"`
+	Name           *string   `json:"name" validate:"omitempty,min=1,max=100"`
+	AllowedOrigins []string  `json:"allowedOrigins" validate:"omitempty,min=1,max=20,dive,min=1,max=255"`
+	Enabled        *bool     `json:"enabled"`
+}
+
+func (p *UpdateAPIClientPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if p.AllowedOrigins != nil {
+		origins, err := normalizeOrigins(p.AllowedOrigins)
+		if err != nil {
+			return err
+		}
+		p.AllowedOrigins = origins
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type DeleteAPIClientPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *DeleteAPIClientPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// normalizeOrigins checks every origin and drops the duplicates
+func normalizeOrigins(origins []string) ([]string, error) {
+	normalized := make([]string, 0, len(origins))
+	seen := make(map[string]bool, len(origins))
+	for i, origin := range origins {
+		o, ok := NormalizeOrigin(origin)
+		if !ok {
+			return nil, validation.CustomValidationErrors{{
+				Field:   fmt.Sprintf("allowedOrigins[%d]", i),
+				Code:    errs.FieldCodeInvalidValue,
+				Message: "must be an http or https origin such as https://app.example.com",
+			}}
+		}
+		if !seen[o] {
+			seen[o] = true
+			normalized = append(normalized, o)
+		}
+	}
+	return normalized, nil
+}
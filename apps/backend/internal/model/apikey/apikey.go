@@ -0,0 +1,32 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+)
+
+// SecretPrefix starts every secret, so leaked keys are easy to recognize
+const SecretPrefix = "xt_"
+
+// Key lets its user call the API without a session. The secret itself is never stored.
+type Key struct {
+	model.BaseWithId
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+	UserID string `json:"-" db:"user_id"`
+	Name   string `json:"name" db:"name"`
+	// Prefix is the start of the secret, to tell keys apart
+	Prefix     string `json:"prefix" db:"prefix"`
+	SecretHash []byte `json:"-" db:"secret_hash"`
+	// Plan sets the rate limit and daily quota of the key
+	Plan string `json:"plan" db:"plan"`
+	// LastUsedAt is accurate to a few minutes, nil for a key never used
+	LastUsedAt *time.Time `json:"lastUsedAt" db:"last_used_at"`
+}
+
+// CreatedKey is a new key with its secret, which can't be shown again
+type CreatedKey struct {
+	Key
+	Secret string `json:"secret"`
+}
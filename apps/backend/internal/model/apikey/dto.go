@@ -0,0 +1,56 @@
+package apikey
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type GetKeysPayload struct{}
+
+func (p *GetKeysPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type CreateKeyPayload struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+func (p *CreateKeyPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type DeleteKeyPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *DeleteKeyPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetUserKeysQuery struct {
+	UserID string `query:"userId" validate:"required,min=1,max=255"`
+}
+
+func (p *GetUserKeysQuery) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type SetKeyPlanPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+	// Plan is one of the plans of the config
+	Plan string `json:"plan" validate:"required,min=1,max=50"`
+}
+
+func (p *SetKeyPlanPayload) Validate() error {
+	return validation.Struct(p)
+}
@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+type Action string
+
+const (
+	ActionLogin         Action = "auth.login"
+	ActionImpersonation Action = "auth.impersonation"
+
+	ActionTodoDeleted       Action = "todo.deleted"
+	ActionTodoMerged        Action = "todo.merged"
+	ActionCategoryDeleted   Action = "category.deleted"
+	ActionCommentDeleted    Action = "comment.deleted"
+	ActionAttachmentDeleted Action = "attachment.deleted"
+	// ActionAttachmentBlocked is recorded by the virus scan, on behalf of the uploader
+	ActionAttachmentBlocked Action = "attachment.blocked"
+	ActionRuleDeleted       Action = "rule.deleted"
+	ActionAccountExport     Action = "account.export_requested"
+	ActionInviteAccepted    Action = "workspace.invite_accepted"
+	ActionAPIKeyCreated     Action = "api_key.created"
+	ActionAPIKeyRevoked     Action = "api_key.revoked"
+	ActionWebhookChanged    Action = "webhook.endpoint_changed"
+
+	ActionAdminUserViewed      Action = "admin.user_viewed"
+	ActionAdminJobRetried      Action = "admin.job_retried"
+	ActionAdminAuditExported   Action = "admin.audit_log_exported"
+	ActionAdminFeatureFlag     Action = "admin.feature_flag_changed"
+	ActionAdminServerMode      Action = "admin.server_mode_changed"
+	ActionAdminConfigReload    Action = "admin.config_reloaded"
+	ActionAdminFaultInjection  Action = "admin.fault_injection_changed"
+	ActionAdminRetention       Action = "admin.retention_policy_changed"
+	ActionAdminBackup          Action = "admin.workspace_backup_requested"
+	ActionAdminRestore         Action = "admin.workspace_restore_requested"
+	ActionAdminTemplate        Action = "admin.workspace_template_changed"
+	ActionAdminOnboardingKit   Action = "admin.onboarding_kit_changed"
+	ActionAdminWorkflow        Action = "admin.workspace_workflow_changed"
+	ActionAdminAgingPolicy     Action = "admin.aging_policy_changed"
+	ActionAdminInvite          Action = "admin.workspace_invite_changed"
+	ActionAdminFreeze          Action = "admin.workspace_freeze_changed"
+	ActionAdminIncident        Action = "admin.status_incident_changed"
+	ActionAdminAPIKeyPlan      Action = "admin.api_key_plan_changed"
+	ActionAdminAPIClient       Action = "admin.api_client_changed"
+	ActionAdminStorageQuota    Action = "admin.storage_quota_changed"
+	ActionAdminWorkspaceViewed Action = "admin.workspace_viewed"
+)
+
+// Entry is one row of the append-only audit log. Before and After are snapshots of the
+// affected entity, null when it didn't exist on that side of the action.
+type Entry struct {
+	ID             int64           `json:"id" db:"id"`
+	CreatedAt      time.Time       `json:"createdAt" db:"created_at"`
+	ActorID        string          `json:"actorId" db:"actor_id"`
+	ActorRole      string          `json:"actorRole" db:"actor_role"`
+	ImpersonatorID *string         `json:"impersonatorId" db:"impersonator_id"`
+	Action         Action          `json:"action" db:"action"`
+	EntityType     *string         `json:"entityType" db:"entity_type"`
+	EntityID       *string         `json:"entityId" db:"entity_id"`
+	IPAddress      *string         `json:"ipAddress" db:"ip_address"`
+	UserAgent      *string         `json:"userAgent" db:"user_agent"`
+	RequestID      *string         `json:"requestId" db:"request_id"`
+	Before         json.RawMessage `json:"before" db:"before"`
+	After          json.RawMessage `json:"after" db:"after"`
+}
+
+// Event is what a service reports, the actor and request details are filled in when recorded
+type Event struct {
+	Action     Action
+	EntityType string
+	EntityID   string
+	Before     any
+	After      any
+}
+
+// CSVHeader lists the columns of a CSV export, in the order of CSVRecord
+var CSVHeader = []string{
+	"id", "createdAt", "actorId", "actorRole", "impersonatorId", "action", "entityType", "entityId",
+	"ipAddress", "userAgent", "requestId", "before", "after",
+}
+
+// CSVRecord flattens an entry into one CSV row, snapshots are kept as JSON
+func (e *Entry) CSVRecord() []string {
+	return []string{
+		strconv.FormatInt(e.ID, 10),
+		e.CreatedAt.Format(time.RFC3339),
+		e.ActorID,
+		e.ActorRole,
+		csvString(e.ImpersonatorID),
+		string(e.Action),
+		csvString(e.EntityType),
+		csvString(e.EntityID),
+		csvString(e.IPAddress),
+		csvString(e.UserAgent),
+		csvString(e.RequestID),
+		string(e.Before),
+		string(e.After),
+	}
+}
+
+func csvString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+)
+
+// Export formats
+const (
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatCSV    = "csv"
+)
+
+// ------------------------------------------------------------
+
+// Filter narrows the audit log, it is shared by listing and exporting
+type Filter struct {
+	ActorID    *string    `query:"actorId" validate:"omitempty,min=1,max=255"`
+	Action     *Action    `query:"action" validate:"omitempty,min=1,max=100"`
+	EntityType *string    `query:"entityType" validate:"omitempty,min=1,max=100"`
+	EntityID   *string    `query:"entityId" validate:"omitempty,min=1,max=255"`
+	From       *time.Time `query:"from"`
+	To         *time.Time `query:"to"`
+}
+
+func (f *Filter) validateRange() error {
+	if f.From != nil && f.To != nil && f.To.Before(*f.From) {
+		return validation.CustomValidationErrors{
+			{Field: "to", Message: "must not be before from"},
+		}
+	}
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetAuditLogQuery struct {
+	Filter
+	Page  *int `query:"page" validate:"omitempty,min=1"`
+	Limit *int `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+func (q *GetAuditLogQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if err := q.validateRange(); err != nil {
+		return err
+	}
+
+	// Set defaults
+	if q.Page == nil {
+		defaultPage := 1
+		q.Page = &defaultPage
+	}
+	if q.Limit == nil {
+		defaultLimit := 50
+		q.Limit = &defaultLimit
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type ExportAuditLogQuery struct {
+	Filter
+	Format *string `query:"format" validate:"omitempty,oneof=ndjson csv"`
+}
+
+func (q *ExportAuditLogQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if err := q.validateRange(); err != nil {
+		return err
+	}
+
+	if q.Format == nil {
+		defaultFormat := ExportFormatNDJSON
+		q.Format = &defaultFormat
+	}
+
+	return nil
+}
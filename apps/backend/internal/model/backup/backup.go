@@ -0,0 +1,164 @@
+package backup
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/category"
+	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// Backup is a logical copy of a workspace stored in S3: its todos, their comments and
+// attachment files, and the categories they use
+type Backup struct {
+	model.Base
+	WorkspaceID string         `json:"workspaceId" db:"workspace_id"`
+	RequestedBy string         `json:"requestedBy" db:"requested_by"`
+	Status      Status         `json:"status" db:"status"`
+	ObjectKey   *string        `json:"-" db:"object_key"`
+	SizeBytes   *int64         `json:"sizeBytes" db:"size_bytes"`
+	Counts      map[string]int `json:"counts" db:"counts"`
+	Error       *string        `json:"error" db:"error"`
+	CompletedAt *time.Time     `json:"completedAt" db:"completed_at"`
+}
+
+// Restore recreates the data of a backup in a workspace that holds none, with new IDs so it
+// never collides with what is left of the original
+type Restore struct {
+	model.Base
+	BackupID          uuid.UUID      `json:"backupId" db:"backup_id"`
+	TargetWorkspaceID string         `json:"targetWorkspaceId" db:"target_workspace_id"`
+	RequestedBy       string         `json:"requestedBy" db:"requested_by"`
+	Status            Status         `json:"status" db:"status"`
+	Counts            map[string]int `json:"counts" db:"counts"`
+	Error             *string        `json:"error" db:"error"`
+	CompletedAt       *time.Time     `json:"completedAt" db:"completed_at"`
+}
+
+// Manifest describes the archive of a backup, it is written to manifest.json
+type Manifest struct {
+	BackupID    string         `json:"backupId"`
+	WorkspaceID string         `json:"workspaceId"`
+	GeneratedAt time.Time      `json:"generatedAt"`
+	Counts      map[string]int `json:"counts"`
+	// MissingAttachments lists attachments whose file could not be read from storage
+	MissingAttachments []string `json:"missingAttachments"`
+}
+
+// Contents are the rows of a workspace a backup holds
+type Contents struct {
+	Categories  []category.Category
+	Todos       []todo.Todo
+	Comments    []comment.Comment
+	Attachments []todo.TodoAttachment
+}
+
+func (c *Contents) Counts() map[string]int {
+	return map[string]int{
+		"categories":  len(c.Categories),
+		"todos":       len(c.Todos),
+		"comments":    len(c.Comments),
+		"attachments": len(c.Attachments),
+	}
+}
+
+// Remap moves the contents into the target workspace under new IDs, keeping the links between
+// them. Todos are ordered parents first so they can be inserted in order. The categories are
+// left to the caller, it returns the attachment ID each new one was copied from.
+func (c *Contents) Remap(targetWorkspaceID string) map[uuid.UUID]uuid.UUID {
+	todoIDs := make(map[uuid.UUID]uuid.UUID, len(c.Todos))
+	for i := range c.Todos {
+		todoIDs[c.Todos[i].ID] = uuid.New()
+	}
+
+	todos := make([]todo.Todo, 0, len(c.Todos))
+	for _, item := range c.Todos {
+		item.ID = todoIDs[item.ID]
+		item.WorkspaceID = &targetWorkspaceID
+		if item.ParentTodoID != nil {
+			parentID, ok := todoIDs[*item.ParentTodoID]
+			if ok {
+				item.ParentTodoID = &parentID
+			} else {
+				// The parent lives outside the workspace, the todo is restored at the top level
+				item.ParentTodoID = nil
+			}
+		}
+		todos = append(todos, item)
+	}
+	slices.SortStableFunc(todos, func(a, b todo.Todo) int {
+		return cmp.Compare(depth(a), depth(b))
+	})
+	c.Todos = todos
+
+	comments := make([]comment.Comment, 0, len(c.Comments))
+	for _, commentItem := range c.Comments {
+		todoID, ok := todoIDs[commentItem.TodoID]
+		if !ok {
+			continue
+		}
+		commentItem.ID = uuid.New()
+		commentItem.TodoID = todoID
+		comments = append(comments, commentItem)
+	}
+	c.Comments = comments
+
+	sources := make(map[uuid.UUID]uuid.UUID, len(c.Attachments))
+	attachments := make([]todo.TodoAttachment, 0, len(c.Attachments))
+	for _, attachment := range c.Attachments {
+		todoID, ok := todoIDs[attachment.TodoID]
+		if !ok {
+			continue
+		}
+		newID := uuid.New()
+		sources[newID] = attachment.ID
+		attachment.ID = newID
+		attachment.TodoID = todoID
+		attachments = append(attachments, attachment)
+	}
+	c.Attachments = attachments
+
+	return sources
+}
+
+// RemapCategories points the todos at the categories they were restored into, a todo whose
+// category is gone loses it
+func (c *Contents) RemapCategories(categoryIDs map[uuid.UUID]uuid.UUID) {
+	for i := range c.Todos {
+		item := &c.Todos[i]
+		if item.CategoryID == nil {
+			continue
+		}
+		if categoryID, ok := categoryIDs[*item.CategoryID]; ok {
+			item.CategoryID = &categoryID
+		} else {
+			item.CategoryID = nil
+		}
+	}
+}
+
+// depth orders subtasks after their parents, todos nest one level deep
+func depth(item todo.Todo) int {
+	if item.ParentTodoID != nil {
+		return 1
+	}
+	return 0
+}
+
+// ArchiveAttachmentName is where the file of an attachment is stored in the archive
+func ArchiveAttachmentName(attachmentID uuid.UUID) string {
+	return "attachments/" + attachmentID.String()
+}
@@ -0,0 +1,58 @@
+package backup
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type CreateBackupPayload struct {
+	WorkspaceID string `param:"workspaceId" validate:"required,min=1,max=255"`
+}
+
+func (p *CreateBackupPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetBackupsPayload struct {
+	WorkspaceID string `param:"workspaceId" validate:"required,min=1,max=255"`
+}
+
+func (p *GetBackupsPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetBackupPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *GetBackupPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type RestoreBackupPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+	// TargetWorkspaceID is the workspace the data is restored into, it must hold no todos
+	TargetWorkspaceID string `json:"targetWorkspaceId" validate:"required,min=1,max=255"`
+}
+
+func (p *RestoreBackupPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetRestorePayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *GetRestorePayload) Validate() error {
+	return validation.Struct(p)
+}
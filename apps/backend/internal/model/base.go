@@ -1,8 +1,10 @@
 package model
 
 import (
+	"strconv"
 	"time"
 
+	"github.com/Sameer16536/ExecuTask/internal/lib/etag"
 	"github.com/google/uuid"
 )
 
@@ -18,16 +20,81 @@ type BaseWithUpdatedAt struct {
 	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
 }
 
+// Link is a hypermedia reference to a related resource
+type Link struct {
+	Href string `json:"href"`
+}
+
+// Links are keyed by relation: self, parent, comments, next, ...
+type Links map[string]Link
+
+// BaseWithLinks carries the _links section, it is filled in by the handler and never stored
+type BaseWithLinks struct {
+	Links Links `json:"_links,omitempty" db:"-"`
+}
+
+func (b *BaseWithLinks) SetLinks(links Links) {
+	b.Links = links
+}
+
 type Base struct {
 	BaseWithId
 	BaseWithCreatedAt
 	BaseWithUpdatedAt
+	BaseWithLinks
 }
 
 type PaginatedResponse[T interface{}] struct {
-	Data       []T `json:"data"`
+	Data       []T   `json:"data"`
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int   `json:"total"`
+	TotalPages int   `json:"totalPages"`
+	Links      Links `json:"_links,omitempty"`
+}
+
+type PageMeta struct {
 	Page       int `json:"page"`
 	Limit      int `json:"limit"`
 	Total      int `json:"total"`
 	TotalPages int `json:"totalPages"`
 }
+
+func (p *PaginatedResponse[T]) SetLinks(links Links) {
+	p.Links = links
+}
+
+func (p *PaginatedResponse[T]) PageLinks() Links {
+	return p.Links
+}
+
+func (p *PaginatedResponse[T]) Items() interface{} {
+	return p.Data
+}
+
+func (p *PaginatedResponse[T]) Meta() PageMeta {
+	return PageMeta{
+		Page:       p.Page,
+		Limit:      p.Limit,
+		Total:      p.Total,
+		TotalPages: p.TotalPages,
+	}
+}
+
+// ETag combines the tags of every item on the page with the pagination state.
+// It is empty when the items can't be tagged.
+func (p *PaginatedResponse[T]) ETag() string {
+	parts := []string{
+		"page", strconv.Itoa(p.Page), strconv.Itoa(p.Limit), strconv.Itoa(p.Total),
+	}
+
+	for i := range p.Data {
+		tagged, ok := any(&p.Data[i]).(etag.Tagged)
+		if !ok {
+			return ""
+		}
+		parts = append(parts, tagged.ETag())
+	}
+
+	return etag.Strong(parts...)
+}
@@ -0,0 +1,38 @@
+package batch
+
+import (
+	"encoding/json"
+
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+)
+
+// ------------------------------------------------------------
+
+type SubRequest struct {
+	ID      string            `json:"id" validate:"omitempty,max=100"`
+	Method  string            `json:"method" validate:"required,oneof=GET POST PUT PATCH DELETE"`
+	Path    string            `json:"path" validate:"required,startswith=/api/v1/,max=2048"`
+	Headers map[string]string `json:"headers"`
+	Body    json.RawMessage   `json:"body"`
+}
+
+type BatchPayload struct {
+	Requests []SubRequest `json:"requests" validate:"required,min=1,max=50,dive"`
+}
+
+func (p *BatchPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type SubResponse struct {
+	ID      string            `json:"id,omitempty"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+type BatchResponse struct {
+	Responses []SubResponse `json:"responses"`
+}
@@ -0,0 +1,164 @@
+package category
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+// BundleFormat identifies category bundles, BundleVersion grows when their shape changes so
+// a server can refuse bundles newer than it understands
+const (
+	BundleFormat  = "executask.category-bundle"
+	BundleVersion = 1
+)
+
+// Conflict handling of an import, for categories named like one the user already has
+const (
+	ImportConflictSkip   = "skip"
+	ImportConflictRename = "rename"
+)
+
+// Bundle is a portable set of categories users share as a starting setup. It carries no IDs,
+// owners or todos, so importing it never touches existing data.
+type Bundle struct {
+	Format      string           `json:"format" validate:"required"`
+	Version     int              `json:"version" validate:"required,min=1"`
+	Name        string           `json:"name" validate:"required,text,textmin=1,textmax=100"`
+	Description *string          `json:"description" validate:"omitempty,max=1000"`
+	ExportedAt  time.Time        `json:"exportedAt"`
+	Categories  []BundleCategory `json:"categories" validate:"required,min=1,max=100,unique=Name,dive"`
+}
+
+type BundleCategory struct {
+	Name        string  `json:"name" validate:"required,text,textmin=1,textmax=100"`
+	Color       string  `json:"color" validate:"required,hexcolor"`
+	Description *string `json:"description" validate:"omitempty,max=255"`
+}
+
+// NewBundle packs categories, stripped of everything that belongs to their owner
+func NewBundle(name string, description *string, categories []Category) *Bundle {
+	bundle := &Bundle{
+		Format:      BundleFormat,
+		Version:     BundleVersion,
+		Name:        name,
+		Description: description,
+		ExportedAt:  time.Now().UTC(),
+		Categories:  make([]BundleCategory, len(categories)),
+	}
+
+	for i, categoryItem := range categories {
+		bundle.Categories[i] = BundleCategory{
+			Name:        categoryItem.Name,
+			Color:       categoryItem.Color,
+			Description: categoryItem.Description,
+		}
+	}
+
+	return bundle
+}
+
+// ImportResult tells what an import created and which bundle categories it left out
+type ImportResult struct {
+	Created []Category `json:"created"`
+	// Skipped are the names of the bundle categories the user already had
+	Skipped []string `json:"skipped"`
+}
+
+// ------------------------------------------------------------
+
+type ExportBundleQuery struct {
+	// IDs is a comma separated list of the categories to export, all of them when left out
+	IDs         *string `query:"ids" validate:"omitempty,max=4000"`
+	Name        *string `query:"name" validate:"omitempty,text,textmin=1,textmax=100"`
+	Description *string `query:"description" validate:"omitempty,max=1000"`
+
+	categoryIDs []uuid.UUID
+}
+
+func (q *ExportBundleQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if q.IDs != nil {
+		for _, value := range strings.Split(*q.IDs, ",") {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+
+			id, err := uuid.Parse(value)
+			if err != nil {
+				return validation.CustomValidationErrors{{
+					Field:   "ids",
+					Code:    errs.FieldCodeInvalidFormat,
+					Message: "not a category ID: " + value,
+				}}
+			}
+			if !slices.Contains(q.categoryIDs, id) {
+				q.categoryIDs = append(q.categoryIDs, id)
+			}
+		}
+
+		if len(q.categoryIDs) > 100 {
+			return validation.CustomValidationErrors{{
+				Field:   "ids",
+				Code:    errs.FieldCodeTooMany,
+				Message: "a bundle holds at most 100 categories",
+			}}
+		}
+	}
+
+	if q.Name == nil {
+		defaultName := "My categories"
+		q.Name = &defaultName
+	}
+
+	return nil
+}
+
+// CategoryIDs returns the parsed ids, nil to export every category
+func (q *ExportBundleQuery) CategoryIDs() []uuid.UUID {
+	return q.categoryIDs
+}
+
+// ------------------------------------------------------------
+
+type ImportBundlePayload struct {
+	Bundle     Bundle  `json:"bundle" validate:"required"`
+	OnConflict *string `json:"onConflict" validate:"omitempty,oneof=skip rename"`
+}
+
+func (p *ImportBundlePayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if p.Bundle.Format != BundleFormat {
+		return validation.CustomValidationErrors{{
+			Field:   "bundle.format",
+			Code:    errs.FieldCodeInvalidValue,
+			Message: "not a category bundle",
+		}}
+	}
+	if p.Bundle.Version > BundleVersion {
+		return validation.CustomValidationErrors{{
+			Field:   "bundle.version",
+			Code:    errs.FieldCodeInvalidValue,
+			Message: fmt.Sprintf("bundle version %d is newer than the supported %d", p.Bundle.Version, BundleVersion),
+		}}
+	}
+
+	if p.OnConflict == nil {
+		defaultConflict := ImportConflictSkip
+		p.OnConflict = &defaultConflict
+	}
+
+	return nil
+}
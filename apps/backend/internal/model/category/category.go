@@ -1,6 +1,11 @@
 package category
 
-import "github.com/Sameer16536/ExecuTask/internal/model"
+import (
+	"strconv"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/etag"
+	"github.com/Sameer16536/ExecuTask/internal/model"
+)
 
 type Category struct {
 	model.Base
@@ -8,4 +13,16 @@ type Category struct {
 	Name        string  `json:"name" db:"name"`
 	Color       string  `json:"color" db:"color"`
 	Description *string `json:"description" db:"description"`
+	Version     int     `json:"version" db:"version"`
+
+	// OpenTodoCount is maintained by a database trigger and doesn't bump the version
+	OpenTodoCount int `json:"openTodoCount" db:"open_todo_count"`
+}
+
+func (c *Category) ETag() string {
+	return etag.Strong("category", c.ID.String(), strconv.Itoa(c.Version), strconv.Itoa(c.OpenTodoCount))
+}
+
+func (c *Category) JSONAPIType() string {
+	return "categories"
 }
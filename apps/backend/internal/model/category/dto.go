@@ -1,34 +1,32 @@
 package category
 
 import (
-	"github.com/go-playground/validator/v10"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
 	"github.com/google/uuid"
 )
 
 // ------------------------------------------------------------
 type CreateCategoryPayload struct {
-	Name        string  `json:"name" validate:"required,min=1,max=100"`
+	Name        string  `json:"name" validate:"required,text,textmin=1,textmax=100"`
 	Color       string  `json:"color" validate:"required,hexcolor"`
 	Description *string `json:"description" validate:"omitempty,max=255"`
 }
 
 func (p *CreateCategoryPayload) Validate() error {
-	validate := validator.New()
-	return validate.Struct(p)
+	return validation.Struct(p)
 }
 
 // ------------------------------------------------------------
 
 type UpdateCategoryPayload struct {
 	ID          uuid.UUID `param:"id" validate:"required,uuid"`
-	Name        *string   `json:"name" validate:"omitempty,min=1,max=100"`
+	Name        *string   `json:"name" validate:"omitempty,text,textmin=1,textmax=100"`
 	Color       *string   `json:"color" validate:"omitempty,hexcolor"`
 	Description *string   `json:"description" validate:"omitempty,max=255"`
 }
 
 func (p *UpdateCategoryPayload) Validate() error {
-	validate := validator.New()
-	return validate.Struct(p)
+	return validation.Struct(p)
 }
 
 // ------------------------------------------------------------
@@ -42,9 +40,7 @@ type GetCategoriesQuery struct {
 }
 
 func (q *GetCategoriesQuery) Validate() error {
-	validate := validator.New()
-
-	if err := validate.Struct(q); err != nil {
+	if err := validation.Struct(q); err != nil {
 		return err
 	}
 
@@ -74,6 +70,5 @@ type DeleteCategoryPayload struct {
 }
 
 func (p *DeleteCategoryPayload) Validate() error {
-	validate := validator.New()
-	return validate.Struct(p)
+	return validation.Struct(p)
 }
@@ -0,0 +1,52 @@
+package change
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Action string
+
+const (
+	ActionCreated Action = "created"
+	ActionUpdated Action = "updated"
+	ActionDeleted Action = "deleted"
+)
+
+// Change is a compact record of a single write, clients refetch the entity if they care
+type Change struct {
+	ID         int64     `json:"-" db:"id"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+	UserID     string    `json:"-" db:"user_id"`
+	EntityType string    `json:"entityType" db:"entity_type"`
+	EntityID   uuid.UUID `json:"entityId" db:"entity_id"`
+	Action     Action    `json:"action" db:"action"`
+	Version    *int      `json:"version" db:"version"`
+}
+
+const cursorPrefix = "c1:"
+
+// EncodeCursor turns an event id into an opaque cursor
+func EncodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(id, 10)))
+}
+
+// DecodeCursor returns the event id a cursor points at
+func DecodeCursor(cursor string) (int64, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(decoded), cursorPrefix) {
+		return 0, errors.New("malformed cursor")
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(string(decoded), cursorPrefix), 10, 64)
+	if err != nil || id < 0 {
+		return 0, errors.New("malformed cursor")
+	}
+
+	return id, nil
+}
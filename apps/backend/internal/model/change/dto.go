@@ -0,0 +1,41 @@
+package change
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+)
+
+// ------------------------------------------------------------
+
+type GetChangesQuery struct {
+	Cursor *string `query:"cursor" validate:"omitempty,max=100"`
+	Limit  *int    `query:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+func (q *GetChangesQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if q.Cursor != nil {
+		if _, err := DecodeCursor(*q.Cursor); err != nil {
+			return validation.CustomValidationErrors{
+				{Field: "cursor", Message: "is not a valid cursor"},
+			}
+		}
+	}
+
+	if q.Limit == nil {
+		defaultLimit := 100
+		q.Limit = &defaultLimit
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type ChangesResponse struct {
+	Changes    []Change `json:"changes"`
+	NextCursor string   `json:"nextCursor"`
+	HasMore    bool     `json:"hasMore"`
+}
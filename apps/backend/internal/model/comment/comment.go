@@ -1,7 +1,10 @@
 package comment
 
 import (
+	"time"
+
 	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/preview"
 	"github.com/google/uuid"
 )
 
@@ -10,4 +13,20 @@ type Comment struct {
 	TodoID  uuid.UUID `json:"todoId" db:"todo_id"`
 	UserID  string    `json:"userId" db:"user_id"`
 	Content string    `json:"content" db:"content"`
+	// LinkPreviews are only attached when the comments of a todo are read
+	LinkPreviews []preview.Preview `json:"linkPreviews,omitempty" db:"-"`
+}
+
+func (c *Comment) JSONAPIType() string {
+	return "comments"
+}
+
+// ReplyToken routes the replies to a notification email about a todo, a reply is posted as a
+// comment of the user the email was sent to
+type ReplyToken struct {
+	Token     string    `db:"token"`
+	CreatedAt time.Time `db:"created_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+	UserID    string    `db:"user_id"`
+	TodoID    uuid.UUID `db:"todo_id"`
 }
@@ -1,7 +1,7 @@
 package comment
 
 import (
-	"github.com/go-playground/validator/v10"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
 	"github.com/google/uuid"
 )
 
@@ -13,8 +13,7 @@ type AddCommentPayload struct {
 }
 
 func (p *AddCommentPayload) Validate() error {
-	validate := validator.New()
-	return validate.Struct(p)
+	return validation.Struct(p)
 }
 
 // ------------------------------------------------------------
@@ -24,8 +23,17 @@ type GetCommentsByTodoIDPayload struct {
 }
 
 func (p *GetCommentsByTodoIDPayload) Validate() error {
-	validate := validator.New()
-	return validate.Struct(p)
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type MarkCommentsReadPayload struct {
+	TodoID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *MarkCommentsReadPayload) Validate() error {
+	return validation.Struct(p)
 }
 
 // ------------------------------------------------------------
@@ -36,8 +44,7 @@ type UpdateCommentPayload struct {
 }
 
 func (p *UpdateCommentPayload) Validate() error {
-	validate := validator.New()
-	return validate.Struct(p)
+	return validation.Struct(p)
 }
 
 // ------------------------------------------------------------
@@ -47,6 +54,23 @@ type DeleteCommentPayload struct {
 }
 
 func (p *DeleteCommentPayload) Validate() error {
-	validate := validator.New()
-	return validate.Struct(p)
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+// InboundSecretHeader carries the secret the inbound email provider authenticates with
+const InboundSecretHeader = "X-Inbound-Secret"
+
+// EmailReplyPayload is an email received at a reply address, as forwarded by the inbound email
+// provider. Text is the plain text body, quoted text and all.
+type EmailReplyPayload struct {
+	MessageID string   `json:"messageId" validate:"max=998"`
+	From      string   `json:"from" validate:"required"`
+	To        []string `json:"to" validate:"required,min=1"`
+	Text      string   `json:"text"`
+}
+
+func (p *EmailReplyPayload) Validate() error {
+	return validation.Struct(p)
 }
@@ -0,0 +1,88 @@
+package cover
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KeyPrefix is where the thumbnails of covers are stored in the bucket
+const KeyPrefix = "todos/covers/"
+
+// Source is where the image of a cover comes from
+type Source string
+
+const (
+	SourceAttachment Source = "attachment"
+	SourceStock      Source = "stock"
+)
+
+// Status of the thumbnails of a cover, the variants are only served once ready
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusReady   Status = "ready"
+	StatusFailed  Status = "failed"
+)
+
+// Size is a thumbnail variant. The image is cropped to the aspect of the size around its
+// center, then scaled down to fit it; small images are never scaled up.
+type Size struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+// Sizes are the variants rendered for every cover
+var Sizes = []Size{
+	{Name: "small", Width: 320, Height: 180},
+	{Name: "medium", Width: 800, Height: 450},
+	{Name: "large", Width: 1600, Height: 900},
+}
+
+// Cover is the image shown on top of a todo, one per todo
+type Cover struct {
+	TodoID       uuid.UUID  `json:"todoId" db:"todo_id"`
+	UserID       string     `json:"-" db:"user_id"`
+	Source       Source     `json:"source" db:"source"`
+	AttachmentID *uuid.UUID `json:"attachmentId" db:"attachment_id"`
+	StockKey     *string    `json:"stockKey" db:"stock_key"`
+	Status       Status     `json:"status" db:"status"`
+	// Generation changes every time the cover is set, thumbnails rendered for a cover replaced
+	// in the meantime are thrown away
+	Generation uuid.UUID `json:"-" db:"generation"`
+	// VariantKeys are the thumbnail objects by size name
+	VariantKeys map[string]string `json:"-" db:"variant_keys"`
+	CreatedAt   time.Time         `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time         `json:"updatedAt" db:"updated_at"`
+
+	// Variants are signed links to the thumbnails by size name, empty until they are ready
+	Variants map[string]string `json:"variants" db:"-"`
+}
+
+// VariantKey is where the thumbnail of a size is stored, each generation gets its own keys so
+// a render never overwrites the thumbnails being served
+func VariantKey(todoID, generation uuid.UUID, size string) string {
+	return KeyPrefix + todoID.String() + "/" + generation.String() + "/" + size + ".jpg"
+}
+
+// IsImage tells whether thumbnails can be rendered from an attachment of the MIME type
+func IsImage(mimeType *string) bool {
+	if mimeType == nil {
+		return false
+	}
+	switch strings.ToLower(*mimeType) {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+// StockImage is an image of the stock library covers can be picked from
+type StockImage struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
@@ -0,0 +1,71 @@
+package cover
+
+import (
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type GetCoverPayload struct {
+	TodoID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *GetCoverPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+// SetCoverPayload picks the image of the cover, either an image attachment of the todo or an
+// image of the stock library
+type SetCoverPayload struct {
+	TodoID       uuid.UUID  `param:"id" validate:"required,uuid"`
+	AttachmentID *uuid.UUID `json:"attachmentId" validate:"omitempty,uuid"`
+	StockKey     *string    `json:"stockKey" validate:"omitempty,min=1,max=1024"`
+}
+
+func (p *SetCoverPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if (p.AttachmentID == nil) == (p.StockKey == nil) {
+		return validation.CustomValidationErrors{{
+			Field:   "attachmentId",
+			Code:    errs.FieldCodeRequired,
+			Message: "exactly one of attachmentId and stockKey must be set",
+		}}
+	}
+
+	if p.StockKey != nil && strings.Contains(*p.StockKey, "..") {
+		return validation.CustomValidationErrors{{
+			Field:   "stockKey",
+			Code:    errs.FieldCodeInvalidFormat,
+			Message: "stockKey must name an image of the stock library",
+		}}
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type DeleteCoverPayload struct {
+	TodoID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *DeleteCoverPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetStockImagesPayload struct{}
+
+func (p *GetStockImagesPayload) Validate() error {
+	return nil
+}
@@ -0,0 +1,59 @@
+package dependency
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+// Dependency records that a todo is blocked until the todo it depends on is done
+type Dependency struct {
+	model.BaseWithCreatedAt
+	UserID      string    `json:"-" db:"user_id"`
+	TodoID      uuid.UUID `json:"todoId" db:"todo_id"`
+	DependsOnID uuid.UUID `json:"dependsOnId" db:"depends_on_id"`
+}
+
+// Dependencies are the direct links of a todo in both directions
+type Dependencies struct {
+	TodoID    uuid.UUID   `json:"todoId"`
+	BlockedBy []todo.Todo `json:"blockedBy"`
+	Blocking  []todo.Todo `json:"blocking"`
+}
+
+// Shift is a due date move of a todo, with the moves it cascades to the todos it blocks.
+// Dependents shift by the same amount so the gaps of the plan are kept.
+type Shift struct {
+	TodoID uuid.UUID `json:"todoId"`
+	// ShiftSeconds is how far the due date moves, 0 when the todo had none to move from
+	ShiftSeconds int64 `json:"shiftSeconds"`
+	// Changes start with the todo itself, its dependents follow in dependency order
+	Changes []Change      `json:"changes"`
+	Skipped []SkippedTodo `json:"skipped"`
+	Applied bool          `json:"applied"`
+}
+
+type Change struct {
+	TodoID uuid.UUID  `json:"todoId"`
+	Title  string     `json:"title"`
+	From   *time.Time `json:"from"`
+	To     time.Time  `json:"to"`
+}
+
+// SkippedTodo is a dependent the cascade reached but leaves as is
+type SkippedTodo struct {
+	TodoID uuid.UUID  `json:"todoId"`
+	Title  string     `json:"title"`
+	Reason SkipReason `json:"reason"`
+}
+
+type SkipReason string
+
+const (
+	// SkipNoDueDate todos have nothing to shift, the todos they block still are
+	SkipNoDueDate SkipReason = "no_due_date"
+	// SkipPast todos would be due before now
+	SkipPast SkipReason = "would_be_past"
+)
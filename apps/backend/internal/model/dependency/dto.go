@@ -0,0 +1,75 @@
+package dependency
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type GetDependenciesPayload struct {
+	TodoID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *GetDependenciesPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type AddDependencyPayload struct {
+	TodoID      uuid.UUID `param:"id" validate:"required,uuid"`
+	DependsOnID uuid.UUID `json:"dependsOnId" validate:"required,uuid"`
+}
+
+func (p *AddDependencyPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if p.DependsOnID == p.TodoID {
+		return validation.CustomValidationErrors{{
+			Field:   "dependsOnId",
+			Code:    errs.FieldCodeInvalidReference,
+			Message: "a todo can't depend on itself",
+		}}
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type RemoveDependencyPayload struct {
+	TodoID      uuid.UUID `param:"id" validate:"required,uuid"`
+	DependsOnID uuid.UUID `param:"dependsOnId" validate:"required,uuid"`
+}
+
+func (p *RemoveDependencyPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type ShiftDueDatePayload struct {
+	TodoID  uuid.UUID `param:"id" validate:"required,uuid"`
+	DueDate time.Time `json:"dueDate" validate:"required"`
+	// Cascade moves the todos blocked by this one by the same amount, on unless turned off
+	Cascade *bool `json:"cascade"`
+}
+
+func (p *ShiftDueDatePayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if p.Cascade == nil {
+		defaultCascade := true
+		p.Cascade = &defaultCascade
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+package encryption
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DataKey encrypts sensitive fields. It is only stored wrapped by the master key, the
+// newest one is active and the retired ones still decrypt what they encrypted.
+type DataKey struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
+	WrappedKey  []byte     `json:"-" db:"wrapped_key"`
+	MasterKeyID string     `json:"masterKeyId" db:"master_key_id"`
+	RetiredAt   *time.Time `json:"retiredAt" db:"retired_at"`
+}
@@ -0,0 +1,58 @@
+package export
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+type RequestAccountExportPayload struct{}
+
+func (p *RequestAccountExportPayload) Validate() error {
+	return nil
+}
+
+// -----------------------------------------------------------------------------------------
+
+type GetAccountExportPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *GetAccountExportPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// -----------------------------------------------------------------------------------------
+
+type RequestTodoListExportPayload struct {
+	Search     *string    `json:"search" validate:"omitempty,text,textmin=1,textmax=255"`
+	Status     *string    `json:"status" validate:"omitempty,max=500"`
+	Priority   *string    `json:"priority" validate:"omitempty,max=500"`
+	CategoryID *uuid.UUID `json:"categoryId" validate:"omitempty,uuid"`
+	Timezone   *string    `json:"tz" validate:"omitempty,timezone"`
+}
+
+func (p *RequestTodoListExportPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// Filters are the filters the export is stored with
+func (p *RequestTodoListExportPayload) Filters() *todo.ExportTodosPDFQuery {
+	return &todo.ExportTodosPDFQuery{
+		Search:     p.Search,
+		Status:     p.Status,
+		Priority:   p.Priority,
+		CategoryID: p.CategoryID,
+		Timezone:   p.Timezone,
+	}
+}
+
+// -----------------------------------------------------------------------------------------
+
+type GetTodoListExportPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *GetTodoListExportPayload) Validate() error {
+	return validation.Struct(p)
+}
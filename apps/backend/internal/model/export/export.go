@@ -0,0 +1,57 @@
+package export
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+type Kind string
+
+const (
+	KindAccount Kind = "account"
+	// KindTodoList is a printable PDF of the todos matching a set of filters
+	KindTodoList Kind = "todo_list"
+	// KindReport is a run of a scheduled report, a PDF or a CSV stored complete right away
+	KindReport Kind = "report"
+)
+
+// AccountExport is a takeout of everything a user stored: one JSON file per resource and the
+// attachment files, zipped. Todo list exports are PDFs going through the same lifecycle.
+type AccountExport struct {
+	model.Base
+	UserID      string     `json:"userId" db:"user_id"`
+	Kind        Kind       `json:"kind" db:"kind"`
+	Status      Status     `json:"status" db:"status"`
+	ObjectKey   *string    `json:"-" db:"object_key"`
+	SizeBytes   *int64     `json:"sizeBytes" db:"size_bytes"`
+	Error       *string    `json:"error" db:"error"`
+	CompletedAt *time.Time `json:"completedAt" db:"completed_at"`
+	ExpiresAt   *time.Time `json:"expiresAt" db:"expires_at"`
+	// Filters picked the todos of a todo list export
+	Filters *todo.ExportTodosPDFQuery `json:"filters,omitempty" db:"filters"`
+
+	// DownloadURL is a presigned link to the archive, set while it is available
+	DownloadURL   *string    `json:"downloadUrl,omitempty" db:"-"`
+	LinkExpiresAt *time.Time `json:"linkExpiresAt,omitempty" db:"-"`
+}
+
+// Manifest describes the archive, it is written to manifest.json
+type Manifest struct {
+	ExportID    string         `json:"exportId"`
+	UserID      string         `json:"userId"`
+	GeneratedAt time.Time      `json:"generatedAt"`
+	Counts      map[string]int `json:"counts"`
+	// MissingAttachments lists attachments whose file could not be read from storage
+	MissingAttachments []string `json:"missingAttachments"`
+}
@@ -0,0 +1,61 @@
+package feature
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+)
+
+// ------------------------------------------------------------
+
+type GetFlagsPayload struct{}
+
+func (p *GetFlagsPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type SetOverridePayload struct {
+	Flag      string `param:"flag" validate:"required,min=1,max=100"`
+	Scope     Scope  `json:"scope" validate:"required,oneof=user workspace global"`
+	SubjectID string `json:"subjectId" validate:"max=255"`
+	Enabled   *bool  `json:"enabled" validate:"required"`
+}
+
+func (p *SetOverridePayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	return validateSubject(p.Scope, p.SubjectID)
+}
+
+// ------------------------------------------------------------
+
+type DeleteOverridePayload struct {
+	Flag      string `param:"flag" validate:"required,min=1,max=100"`
+	Scope     Scope  `query:"scope" validate:"required,oneof=user workspace global"`
+	SubjectID string `query:"subjectId" validate:"max=255"`
+}
+
+func (p *DeleteOverridePayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	return validateSubject(p.Scope, p.SubjectID)
+}
+
+// validateSubject requires a subject for user and workspace overrides, global ones have none
+func validateSubject(scope Scope, subjectID string) error {
+	if scope == ScopeGlobal && subjectID != "" {
+		return validation.CustomValidationErrors{
+			{Field: "subjectId", Message: "must be empty for a global override"},
+		}
+	}
+	if scope != ScopeGlobal && subjectID == "" {
+		return validation.CustomValidationErrors{
+			{Field: "subjectId", Message: "is required for a user or workspace override"},
+		}
+	}
+	return nil
+}
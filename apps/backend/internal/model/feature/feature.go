@@ -0,0 +1,35 @@
+package feature
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/model"
+)
+
+type Scope string
+
+const (
+	ScopeUser      Scope = "user"
+	ScopeWorkspace Scope = "workspace"
+	// ScopeGlobal overrides the config for everyone without a redeploy
+	ScopeGlobal Scope = "global"
+)
+
+// Override forces a flag on or off for one user, one workspace or everyone. The most
+// specific override wins: user, then workspace, then global.
+type Override struct {
+	model.BaseWithId
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+	Flag      string `json:"flag" db:"flag"`
+	Scope     Scope  `json:"scope" db:"scope"`
+	SubjectID string `json:"subjectId" db:"subject_id"`
+	Enabled   bool   `json:"enabled" db:"enabled"`
+	UpdatedBy string `json:"updatedBy" db:"updated_by"`
+}
+
+// Flag is the operator view of a flag: its configured state and every override of it
+type Flag struct {
+	Name           string     `json:"name"`
+	Enabled        bool       `json:"enabled"`
+	RolloutPercent int        `json:"rolloutPercent"`
+	Overrides      []Override `json:"overrides"`
+}
@@ -0,0 +1,65 @@
+package focus
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type StartSessionPayload struct {
+	TodoID         uuid.UUID `json:"todoId" validate:"required,uuid"`
+	PlannedMinutes *int      `json:"plannedMinutes" validate:"omitempty,min=1,max=240"`
+}
+
+func (p *StartSessionPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if p.PlannedMinutes == nil {
+		defaultPlannedMinutes := DefaultPlannedMinutes
+		p.PlannedMinutes = &defaultPlannedMinutes
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetSessionsQuery struct {
+	TodoID *uuid.UUID `query:"todoId" validate:"omitempty,uuid"`
+	Limit  *int       `query:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+func (q *GetSessionsQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if q.Limit == nil {
+		defaultLimit := 50
+		q.Limit = &defaultLimit
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetCurrentSessionPayload struct{}
+
+func (p *GetCurrentSessionPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+// SessionPayload targets a session for pause, resume and complete
+type SessionPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *SessionPayload) Validate() error {
+	return validation.Struct(p)
+}
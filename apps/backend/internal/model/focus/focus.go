@@ -0,0 +1,80 @@
+package focus
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/google/uuid"
+)
+
+type Status string
+
+const (
+	StatusActive    Status = "active"
+	StatusPaused    Status = "paused"
+	StatusCompleted Status = "completed"
+)
+
+// DefaultPlannedMinutes is the length of a Pomodoro
+const DefaultPlannedMinutes = 25
+
+// Session is a stretch of focused work on a todo. A user has at most one session open, active
+// or paused, at a time.
+type Session struct {
+	model.Base
+	UserID         string    `json:"userId" db:"user_id"`
+	TodoID         uuid.UUID `json:"todoId" db:"todo_id"`
+	Status         Status    `json:"status" db:"status"`
+	PlannedMinutes int       `json:"plannedMinutes" db:"planned_minutes"`
+	// FocusedSeconds is the time focused up to the last pause, see Elapsed for the running total
+	FocusedSeconds int `json:"focusedSeconds" db:"focused_seconds"`
+	// ResumedAt is when the running stretch started, nil unless the session is active
+	ResumedAt   *time.Time `json:"resumedAt" db:"resumed_at"`
+	CompletedAt *time.Time `json:"completedAt" db:"completed_at"`
+}
+
+// Elapsed is the time focused so far, the running stretch included
+func (s *Session) Elapsed(now time.Time) int {
+	if s.ResumedAt == nil || now.Before(*s.ResumedAt) {
+		return s.FocusedSeconds
+	}
+	return s.FocusedSeconds + int(now.Sub(*s.ResumedAt).Seconds())
+}
+
+// Pause banks the running stretch
+func (s *Session) Pause(now time.Time) error {
+	if s.Status != StatusActive {
+		return invalidTransition("only an active session can be paused")
+	}
+	s.FocusedSeconds = s.Elapsed(now)
+	s.ResumedAt = nil
+	s.Status = StatusPaused
+	return nil
+}
+
+func (s *Session) Resume(now time.Time) error {
+	if s.Status != StatusPaused {
+		return invalidTransition("only a paused session can be resumed")
+	}
+	s.ResumedAt = &now
+	s.Status = StatusActive
+	return nil
+}
+
+// Complete banks the running stretch, if any, and closes the session
+func (s *Session) Complete(now time.Time) error {
+	if s.Status == StatusCompleted {
+		return invalidTransition("the session is already completed")
+	}
+	s.FocusedSeconds = s.Elapsed(now)
+	s.ResumedAt = nil
+	s.CompletedAt = &now
+	s.Status = StatusCompleted
+	return nil
+}
+
+func invalidTransition(message string) error {
+	code := errs.CodeFocusSessionState
+	return errs.NewConflictError(message, false, &code)
+}
@@ -0,0 +1,31 @@
+package gamification
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+)
+
+// ------------------------------------------------------------
+
+type GetOverviewPayload struct{}
+
+func (p *GetOverviewPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type SetGoalPayload struct {
+	Target int `json:"target" validate:"required,min=1,max=500"`
+}
+
+func (p *SetGoalPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type DeleteGoalPayload struct{}
+
+func (p *DeleteGoalPayload) Validate() error {
+	return nil
+}
@@ -0,0 +1,108 @@
+package gamification
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/stats"
+)
+
+// StreakWindowDays is how far back streaks are looked for
+const StreakWindowDays = 365
+
+type BadgeCode string
+
+const (
+	BadgeFirstCompletion    BadgeCode = "first_completion"
+	BadgeTenCompletions     BadgeCode = "ten_completions"
+	BadgeHundredCompletions BadgeCode = "hundred_completions"
+	BadgeWeekStreak         BadgeCode = "week_streak"
+	BadgeMonthStreak        BadgeCode = "month_streak"
+	BadgeWeeklyGoalMet      BadgeCode = "weekly_goal_met"
+)
+
+// BadgeDefinition describes a badge and what earns it
+type BadgeDefinition struct {
+	Code        BadgeCode
+	Name        string
+	Description string
+	earned      func(progress *Progress) bool
+}
+
+// EarnedBy tells whether the progress of a user qualifies for the badge
+func (d BadgeDefinition) EarnedBy(progress *Progress) bool {
+	return d.earned(progress)
+}
+
+// Badges is the catalog, in the order they are listed
+var Badges = []BadgeDefinition{
+	{
+		Code: BadgeFirstCompletion, Name: "First Step", Description: "Complete your first todo",
+		earned: func(p *Progress) bool { return p.Completed >= 1 },
+	},
+	{
+		Code: BadgeTenCompletions, Name: "Getting Things Done", Description: "Complete 10 todos",
+		earned: func(p *Progress) bool { return p.Completed >= 10 },
+	},
+	{
+		Code: BadgeHundredCompletions, Name: "Centurion", Description: "Complete 100 todos",
+		earned: func(p *Progress) bool { return p.Completed >= 100 },
+	},
+	{
+		Code: BadgeWeekStreak, Name: "On a Roll", Description: "Keep a 7 day streak",
+		earned: func(p *Progress) bool { return p.Streaks.Longest >= 7 },
+	},
+	{
+		Code: BadgeMonthStreak, Name: "Unstoppable", Description: "Keep a 30 day streak",
+		earned: func(p *Progress) bool { return p.Streaks.Longest >= 30 },
+	},
+	{
+		Code: BadgeWeeklyGoalMet, Name: "Goal Getter", Description: "Reach your weekly completion goal",
+		earned: func(p *Progress) bool { return p.Goal != nil && p.Goal.Met },
+	},
+}
+
+// Award records that a user earned a badge
+type Award struct {
+	model.BaseWithId
+	model.BaseWithCreatedAt
+	UserID string    `db:"user_id"`
+	Badge  BadgeCode `db:"badge"`
+}
+
+// Badge is a badge of the catalog as a user sees it, AwardedAt is nil until they earn it
+type Badge struct {
+	Code        BadgeCode  `json:"code"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	AwardedAt   *time.Time `json:"awardedAt"`
+}
+
+// Goal is the number of todos a user means to complete each week
+type Goal struct {
+	UserID string `json:"-" db:"user_id"`
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+	Target int `json:"target" db:"target"`
+}
+
+// GoalProgress counts the completions of the current UTC week, Monday to Sunday, from the
+// daily completion aggregates. They are refreshed periodically, so the count can lag a little.
+type GoalProgress struct {
+	WeekStart string `json:"weekStart"`
+	Target    int    `json:"target"`
+	Completed int    `json:"completed"`
+	Met       bool   `json:"met"`
+}
+
+// Progress is what badges are awarded on
+type Progress struct {
+	Completed int           `json:"completed"`
+	Streaks   stats.Streaks `json:"streaks"`
+	Goal      *GoalProgress `json:"goal"`
+}
+
+type Overview struct {
+	Progress
+	Badges []Badge `json:"badges"`
+}
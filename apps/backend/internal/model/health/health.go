@@ -0,0 +1,29 @@
+package health
+
+import "time"
+
+type Status string
+
+const (
+	StatusHealthy Status = "healthy"
+	// StatusDegraded means a dependency the API can work without is failing, the instance
+	// keeps serving traffic
+	StatusDegraded  Status = "degraded"
+	StatusUnhealthy Status = "unhealthy"
+)
+
+// Check is the outcome of probing one dependency
+type Check struct {
+	Status    Status `json:"status"`
+	LatencyMs int64  `json:"latencyMs"`
+	// Critical dependencies make the instance unhealthy when they fail, the others degrade it
+	Critical bool    `json:"critical"`
+	Error    *string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status      Status           `json:"status"`
+	Timestamp   time.Time        `json:"timestamp"`
+	Environment string           `json:"environment"`
+	Checks      map[string]Check `json:"checks,omitempty"`
+}
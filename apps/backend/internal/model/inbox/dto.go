@@ -0,0 +1,81 @@
+package inbox
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+// CapturePayload takes the title alone, everything else is decided at triage
+type CapturePayload struct {
+	Title string `json:"title" validate:"required,text,textmin=1,textmax=255"`
+}
+
+func (p *CapturePayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetItemsQuery struct {
+	Limit *int `query:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+func (q *GetItemsQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if q.Limit == nil {
+		defaultLimit := 50
+		q.Limit = &defaultLimit
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+// TriagePayload promotes an item into a todo, the title of the item is kept unless replaced
+type TriagePayload struct {
+	ID          uuid.UUID      `param:"id" validate:"required,uuid"`
+	Title       *string        `json:"title" validate:"omitempty,text,textmin=1,textmax=255"`
+	Description *string        `json:"description" validate:"omitempty,max=1000"`
+	Priority    *todo.Priority `json:"priority" validate:"omitempty,oneof=low medium high"`
+	DueDate     *time.Time     `json:"dueDate"`
+	CategoryID  *uuid.UUID     `json:"categoryId" validate:"omitempty,uuid"`
+}
+
+func (p *TriagePayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// CreatePayload is the todo the item turns into
+func (p *TriagePayload) CreatePayload(item *Item) *todo.CreateTodoPayload {
+	title := item.Title
+	if p.Title != nil {
+		title = *p.Title
+	}
+
+	return &todo.CreateTodoPayload{
+		Title:       title,
+		Description: p.Description,
+		Priority:    p.Priority,
+		DueDate:     p.DueDate,
+		CategoryID:  p.CategoryID,
+	}
+}
+
+// ------------------------------------------------------------
+
+type DiscardPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *DiscardPayload) Validate() error {
+	return validation.Struct(p)
+}
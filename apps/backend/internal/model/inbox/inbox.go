@@ -0,0 +1,14 @@
+package inbox
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/model"
+)
+
+// Item is a thought captured in a hurry, it waits in the inbox until triage turns it into a todo
+// or discards it
+type Item struct {
+	model.BaseWithId
+	model.BaseWithCreatedAt
+	UserID string `json:"-" db:"user_id"`
+	Title  string `json:"title" db:"title"`
+}
@@ -0,0 +1,32 @@
+package locale
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+)
+
+// ------------------------------------------------------------
+
+type GetPreferencePayload struct{}
+
+func (p *GetPreferencePayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type UpdatePreferencePayload struct {
+	// Locale is one of the locales with a catalog, see i18n.Supported
+	Locale string `json:"locale" validate:"required,oneof=en es fr"`
+}
+
+func (p *UpdatePreferencePayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type ResetPreferencePayload struct{}
+
+func (p *ResetPreferencePayload) Validate() error {
+	return nil
+}
@@ -0,0 +1,10 @@
+package locale
+
+// Preference is the locale a user reads the app and its emails in
+type Preference struct {
+	// Locale is the locale the user chose, nil when they follow the Accept-Language of their
+	// browser. Emails are sent in English then.
+	Locale *string `json:"locale"`
+	// Supported lists the locales that can be chosen
+	Supported []string `json:"supported"`
+}
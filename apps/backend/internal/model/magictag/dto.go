@@ -0,0 +1,97 @@
+package magictag
+
+import (
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/quickadd"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type GetMagicTagsPayload struct{}
+
+func (p *GetMagicTagsPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+// Definition names the #tag and the fields it sets, at least one of them
+type Definition struct {
+	Tag        string         `json:"tag" validate:"required,text,textmin=1,textmax=50"`
+	Priority   *todo.Priority `json:"priority" validate:"omitempty,oneof=low medium high"`
+	DueInDays  *int           `json:"dueInDays" validate:"omitempty,min=0,max=365"`
+	CategoryID *uuid.UUID     `json:"categoryId" validate:"omitempty,uuid"`
+	Timezone   *string        `json:"timezone" validate:"omitempty,timezone"`
+}
+
+// normalize drops the # and the case of the tag and defaults the timezone to UTC
+func (d *Definition) normalize() error {
+	d.Tag = strings.ToLower(strings.TrimPrefix(d.Tag, "#"))
+	if !quickadd.IsTag(d.Tag) {
+		return validation.CustomValidationErrors{{
+			Field:   "tag",
+			Code:    errs.FieldCodeInvalidFormat,
+			Message: "tag must be made of letters, digits, dashes and underscores",
+		}}
+	}
+
+	if d.Priority == nil && d.DueInDays == nil && d.CategoryID == nil {
+		return validation.CustomValidationErrors{{
+			Field:   "priority",
+			Code:    errs.FieldCodeRequired,
+			Message: "at least one of priority, dueInDays and categoryId must be set",
+		}}
+	}
+
+	if d.Timezone == nil {
+		defaultTimezone := "UTC"
+		d.Timezone = &defaultTimezone
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type CreateMagicTagPayload struct {
+	Definition
+}
+
+func (p *CreateMagicTagPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	return p.normalize()
+}
+
+// ------------------------------------------------------------
+
+// UpdateMagicTagPayload replaces the magic tag as a whole, fields left out are cleared
+type UpdateMagicTagPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+	Definition
+}
+
+func (p *UpdateMagicTagPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	return p.normalize()
+}
+
+// ------------------------------------------------------------
+
+type DeleteMagicTagPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *DeleteMagicTagPayload) Validate() error {
+	return validation.Struct(p)
+}
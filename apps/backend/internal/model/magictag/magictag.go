@@ -0,0 +1,92 @@
+package magictag
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/quickadd"
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+// MaxPerUser keeps the lookup made on every todo written cheap
+const MaxPerUser = 50
+
+// MagicTag is a shortcut typed into a todo title, such as #tomorrow or #p1. Its #tag is removed
+// from the title and the fields it names are set on the todo.
+type MagicTag struct {
+	model.Base
+	UserID string `json:"-" db:"user_id"`
+	// Tag is matched against the #tags of titles ignoring case, it is stored without the #
+	Tag      string         `json:"tag" db:"tag"`
+	Priority *todo.Priority `json:"priority" db:"priority"`
+	// DueInDays makes the todo due at the end of the day that many days after it is written,
+	// 0 for the same day
+	DueInDays  *int       `json:"dueInDays" db:"due_in_days"`
+	CategoryID *uuid.UUID `json:"categoryId" db:"category_id"`
+	// Timezone is where the days of DueInDays are counted
+	Timezone string `json:"timezone" db:"timezone"`
+}
+
+// DueDate is when a todo written at now is due, nil when the tag sets no due date
+func (t *MagicTag) DueDate(now time.Time) *time.Time {
+	if t.DueInDays == nil {
+		return nil
+	}
+
+	location, err := time.LoadLocation(t.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	local := now.In(location)
+	due := time.Date(local.Year(), local.Month(), local.Day()+*t.DueInDays, 23, 59, 0, 0, location)
+	return &due
+}
+
+// Shortcuts are the fields the magic tags of a title set
+type Shortcuts struct {
+	// Title is the title without the #tags of the magic tags
+	Title      string
+	Priority   *todo.Priority
+	DueDate    *time.Time
+	CategoryID *uuid.UUID
+	// Applied lists the magic tags found in the title
+	Applied []string
+}
+
+// Apply reads the magic tags of a title written at now. A tag setting a field set by an earlier
+// one overrides it. #tags that aren't magic tags stay in the title, as does a title made only of
+// magic tags since a todo can't go without one.
+func Apply(title string, tags []MagicTag, now time.Time) *Shortcuts {
+	byName := make(map[string]*MagicTag, len(tags))
+	for i := range tags {
+		byName[tags[i].Tag] = &tags[i]
+	}
+
+	shortcuts := &Shortcuts{Title: title, Applied: []string{}}
+	matched := []quickadd.Hashtag{}
+	for _, hashtag := range quickadd.Hashtags(title) {
+		tag, ok := byName[hashtag.Name]
+		if !ok {
+			continue
+		}
+		matched = append(matched, hashtag)
+		shortcuts.Applied = append(shortcuts.Applied, tag.Tag)
+
+		if tag.Priority != nil {
+			shortcuts.Priority = tag.Priority
+		}
+		if due := tag.DueDate(now); due != nil {
+			shortcuts.DueDate = due
+		}
+		if tag.CategoryID != nil {
+			shortcuts.CategoryID = tag.CategoryID
+		}
+	}
+
+	if stripped := quickadd.Strip(title, matched); len(matched) > 0 && stripped != "" {
+		shortcuts.Title = stripped
+	}
+
+	return shortcuts
+}
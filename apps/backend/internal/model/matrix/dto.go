@@ -0,0 +1,44 @@
+package matrix
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+)
+
+// ------------------------------------------------------------
+
+type GetMatrixQuery struct{}
+
+func (q *GetMatrixQuery) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetSettingsPayload struct{}
+
+func (p *GetSettingsPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type UpdateSettingsPayload struct {
+	UrgentWithinHours *int           `json:"urgentWithinHours" validate:"omitempty,min=1,max=720"`
+	ImportantPriority *todo.Priority `json:"importantPriority" validate:"omitempty,oneof=low medium high"`
+}
+
+func (p *UpdateSettingsPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// Apply returns the settings with the fields of the payload that are set
+func (p *UpdateSettingsPayload) Apply(settings Settings) Settings {
+	if p.UrgentWithinHours != nil {
+		settings.UrgentWithinHours = *p.UrgentWithinHours
+	}
+	if p.ImportantPriority != nil {
+		settings.ImportantPriority = *p.ImportantPriority
+	}
+	return settings
+}
@@ -0,0 +1,59 @@
+package matrix
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+)
+
+const (
+	// DefaultUrgentWithinHours makes the todos due in the next two days urgent
+	DefaultUrgentWithinHours = 48
+	DefaultImportantPriority = todo.PriorityHigh
+	// MaxTodos caps the open todos laid out on the matrix
+	MaxTodos = 500
+)
+
+// Settings are the thresholds a user buckets their todos with
+type Settings struct {
+	// UrgentWithinHours makes the todos due that soon urgent, overdue todos always are
+	UrgentWithinHours int `json:"urgentWithinHours" db:"urgent_within_hours"`
+	// ImportantPriority is the lowest priority an important todo has
+	ImportantPriority todo.Priority `json:"importantPriority" db:"important_priority"`
+}
+
+func DefaultSettings() Settings {
+	return Settings{
+		UrgentWithinHours: DefaultUrgentWithinHours,
+		ImportantPriority: DefaultImportantPriority,
+	}
+}
+
+// IsUrgent tells whether a todo is due within the urgent window, todos without a due date never are
+func (s Settings) IsUrgent(item *todo.Todo, now time.Time) bool {
+	if item.DueDate == nil {
+		return false
+	}
+	return item.DueDate.Before(now.Add(time.Duration(s.UrgentWithinHours) * time.Hour))
+}
+
+// IsImportant tells whether a todo is at least at the level the important priority stands for
+// on the scale of its workspace
+func (s Settings) IsImportant(item *todo.Todo, importantLevel int) bool {
+	return item.PriorityLevel >= importantLevel
+}
+
+// Matrix lays the open todos of a user out on the four quadrants, each sorted soonest due first
+type Matrix struct {
+	Settings Settings `json:"settings"`
+	// Do is urgent and important
+	Do []todo.Todo `json:"do"`
+	// Schedule is important but not urgent
+	Schedule []todo.Todo `json:"schedule"`
+	// Delegate is urgent but not important
+	Delegate []todo.Todo `json:"delegate"`
+	// Eliminate is neither urgent nor important
+	Eliminate []todo.Todo `json:"eliminate"`
+	// Truncated is set when the user has more open todos than the matrix lays out
+	Truncated bool `json:"truncated"`
+}
@@ -0,0 +1,37 @@
+package note
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type GetNotePayload struct {
+	TodoID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *GetNotePayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type SetNotePayload struct {
+	TodoID uuid.UUID `param:"id" validate:"required,uuid"`
+	Body   string    `json:"body" validate:"required,min=1,max=10000"`
+}
+
+func (p *SetNotePayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type DeleteNotePayload struct {
+	TodoID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *DeleteNotePayload) Validate() error {
+	return validation.Struct(p)
+}
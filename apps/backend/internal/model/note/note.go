@@ -0,0 +1,17 @@
+package note
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Note is a private note on a todo, only its author reads it. Notes are never embedded in the
+// todo, so they stay out of everything else reading it, such as workspace backups.
+type Note struct {
+	TodoID    uuid.UUID `json:"todoId" db:"todo_id"`
+	UserID    string    `json:"-" db:"user_id"`
+	Body      string    `json:"body" db:"body"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
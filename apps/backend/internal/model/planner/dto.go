@@ -0,0 +1,269 @@
+package planner
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type GetPlannerQuery struct {
+	// Week is any day (YYYY-MM-DD) of the week to plan, the current week by default
+	Week            *string `query:"week" validate:"omitempty,datetime=2006-01-02"`
+	Timezone        *string `query:"tz" validate:"omitempty,timezone"`
+	CapacityMinutes *int    `query:"capacity" validate:"omitempty,min=1,max=1440"`
+}
+
+func (q *GetPlannerQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if q.Timezone == nil {
+		defaultTimezone := "UTC"
+		q.Timezone = &defaultTimezone
+	}
+	if q.CapacityMinutes == nil {
+		defaultCapacity := DefaultCapacityMinutes
+		q.CapacityMinutes = &defaultCapacity
+	}
+
+	return nil
+}
+
+// Location is the timezone of the query, it was checked by Validate
+func (q *GetPlannerQuery) Location() *time.Location {
+	location, _ := time.LoadLocation(*q.Timezone)
+	return location
+}
+
+// ------------------------------------------------------------
+
+type Assignment struct {
+	TodoID uuid.UUID `json:"todoId" validate:"required,uuid"`
+	// Date is the day (YYYY-MM-DD) the todo is planned for, in the timezone of the payload
+	Date string `json:"date" validate:"required,datetime=2006-01-02"`
+}
+
+type SchedulePayload struct {
+	Timezone    *string      `json:"timezone" validate:"omitempty,timezone"`
+	Assignments []Assignment `json:"assignments" validate:"required,min=1,max=100,dive"`
+}
+
+func (p *SchedulePayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if p.Timezone == nil {
+		defaultTimezone := "UTC"
+		p.Timezone = &defaultTimezone
+	}
+
+	today := time.Now().In(p.Location()).Format(time.DateOnly)
+	seen := map[uuid.UUID]bool{}
+	var validationErrors validation.CustomValidationErrors
+	for i, assignment := range p.Assignments {
+		if seen[assignment.TodoID] {
+			validationErrors = append(validationErrors, validation.CustomValidationError{
+				Field:   fmt.Sprintf("assignments[%d].todoId", i),
+				Code:    errs.FieldCodeInvalidValue,
+				Message: "a todo can only be scheduled once per request",
+			})
+		}
+		seen[assignment.TodoID] = true
+
+		// ISO dates sort as text
+		if assignment.Date < today {
+			validationErrors = append(validationErrors, validation.CustomValidationError{
+				Field:   fmt.Sprintf("assignments[%d].date", i),
+				Code:    errs.FieldCodeDateInPast,
+				Message: "must not be in the past",
+			})
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return validationErrors
+	}
+	return nil
+}
+
+// Location is the timezone of the payload, it was checked by Validate
+func (p *SchedulePayload) Location() *time.Location {
+	location, _ := time.LoadLocation(*p.Timezone)
+	return location
+}
+
+// ------------------------------------------------------------
+
+// GetForecastQuery picks the open todos to forecast, every open todo by default
+type GetForecastQuery struct {
+	Timezone        *string `query:"tz" validate:"omitempty,timezone"`
+	CapacityMinutes *int    `query:"capacity" validate:"omitempty,min=1,max=1440"`
+	// Priority is a key of the priority scales of the workspaces of the todos
+	Priority     *todo.Priority `query:"priority" validate:"omitempty,min=1,max=40"`
+	CategoryID   *uuid.UUID     `query:"categoryId" validate:"omitempty,uuid"`
+	ParentTodoID *uuid.UUID     `query:"parentTodoId" validate:"omitempty,uuid"`
+	Tag          *string        `query:"tag" validate:"omitempty,text,textmin=1,textmax=50"`
+}
+
+func (q *GetForecastQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if q.Timezone == nil {
+		defaultTimezone := "UTC"
+		q.Timezone = &defaultTimezone
+	}
+	if q.CapacityMinutes == nil {
+		defaultCapacity := DefaultCapacityMinutes
+		q.CapacityMinutes = &defaultCapacity
+	}
+
+	return nil
+}
+
+// Location is the timezone of the query, it was checked by Validate
+func (q *GetForecastQuery) Location() *time.Location {
+	location, _ := time.LoadLocation(*q.Timezone)
+	return location
+}
+
+// Matches tells whether an open todo is one of those to forecast
+func (q *GetForecastQuery) Matches(item *todo.Todo) bool {
+	if q.Priority != nil && item.Priority != *q.Priority {
+		return false
+	}
+	if q.CategoryID != nil && (item.CategoryID == nil || *item.CategoryID != *q.CategoryID) {
+		return false
+	}
+	if q.ParentTodoID != nil && (item.ParentTodoID == nil || *item.ParentTodoID != *q.ParentTodoID) {
+		return false
+	}
+	if q.Tag != nil {
+		if item.Metadata == nil {
+			return false
+		}
+		return slices.ContainsFunc(item.Metadata.Tags, func(tag string) bool {
+			return strings.EqualFold(tag, *q.Tag)
+		})
+	}
+	return true
+}
+
+// ------------------------------------------------------------
+
+type GetMyDayQuery struct {
+	Timezone *string `query:"tz" validate:"omitempty,timezone"`
+}
+
+func (q *GetMyDayQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if q.Timezone == nil {
+		defaultTimezone := "UTC"
+		q.Timezone = &defaultTimezone
+	}
+
+	return nil
+}
+
+// Location is the timezone of the query, it was checked by Validate
+func (q *GetMyDayQuery) Location() *time.Location {
+	location, _ := time.LoadLocation(*q.Timezone)
+	return location
+}
+
+// ------------------------------------------------------------
+
+type AddMyDayTodosPayload struct {
+	TodoIDs  []uuid.UUID `json:"todoIds" validate:"required,min=1,max=50,unique,dive,required"`
+	Timezone *string     `json:"timezone" validate:"omitempty,timezone"`
+}
+
+func (p *AddMyDayTodosPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if p.Timezone == nil {
+		defaultTimezone := "UTC"
+		p.Timezone = &defaultTimezone
+	}
+
+	return nil
+}
+
+// Location is the timezone of the payload, it was checked by Validate
+func (p *AddMyDayTodosPayload) Location() *time.Location {
+	location, _ := time.LoadLocation(*p.Timezone)
+	return location
+}
+
+// ------------------------------------------------------------
+
+type RemoveMyDayTodoPayload struct {
+	TodoID   uuid.UUID `param:"todoId" validate:"required,uuid"`
+	Timezone *string   `query:"tz" validate:"omitempty,timezone"`
+}
+
+func (p *RemoveMyDayTodoPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if p.Timezone == nil {
+		defaultTimezone := "UTC"
+		p.Timezone = &defaultTimezone
+	}
+
+	return nil
+}
+
+// Location is the timezone of the payload, it was checked by Validate
+func (p *RemoveMyDayTodoPayload) Location() *time.Location {
+	location, _ := time.LoadLocation(*p.Timezone)
+	return location
+}
+
+// ------------------------------------------------------------
+
+type GetDaySummariesQuery struct {
+	// Days is how many days to summarize, today included
+	Days     *int    `query:"days" validate:"omitempty,min=1,max=90"`
+	Timezone *string `query:"tz" validate:"omitempty,timezone"`
+}
+
+func (q *GetDaySummariesQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if q.Days == nil {
+		defaultDays := 7
+		q.Days = &defaultDays
+	}
+	if q.Timezone == nil {
+		defaultTimezone := "UTC"
+		q.Timezone = &defaultTimezone
+	}
+
+	return nil
+}
+
+// Location is the timezone of the query, it was checked by Validate
+func (q *GetDaySummariesQuery) Location() *time.Location {
+	location, _ := time.LoadLocation(*q.Timezone)
+	return location
+}
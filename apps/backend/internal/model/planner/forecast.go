@@ -0,0 +1,158 @@
+package planner
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxForecastTodos caps the open todos laid out by a forecast, the rest are left out
+	MaxForecastTodos = 200
+	// MaxEstimateSamples bounds the completed todos the estimate accuracy is learned from
+	MaxEstimateSamples = 100
+	// MinEstimateSamples is how many completed todos it takes before estimates are corrected,
+	// fewer are taken at face value
+	MinEstimateSamples = 5
+	// DefaultEstimateMinutes stands in for a missing estimate when there is no history to go by
+	DefaultEstimateMinutes = 60
+	// The correction is kept within these bounds, a few runaway todos shouldn't swing it further
+	minEstimateRatio = 0.5
+	maxEstimateRatio = 3
+)
+
+// EstimateSample is a completed todo with an estimate and the time focused on it
+type EstimateSample struct {
+	TodoID           uuid.UUID `json:"todoId" db:"todo_id"`
+	EstimatedMinutes int       `json:"estimatedMinutes" db:"estimated_minutes"`
+	ActualSeconds    int       `json:"actualSeconds" db:"actual_seconds"`
+}
+
+// Accuracy is how the time the user spends compares to what they estimate
+type Accuracy struct {
+	SampleCount int `json:"sampleCount"`
+	// Ratio is the time spent per estimated minute, 1.5 means todos take half again as long
+	// as estimated. It is 1 until there are enough samples.
+	Ratio float64 `json:"ratio"`
+	// AverageMinutes is what a todo without an estimate is assumed to take
+	AverageMinutes int `json:"averageMinutes"`
+}
+
+// NewAccuracy learns the accuracy of the estimates from completed todos, longer todos weigh
+// more than short ones
+func NewAccuracy(samples []EstimateSample) Accuracy {
+	accuracy := Accuracy{SampleCount: len(samples), Ratio: 1, AverageMinutes: DefaultEstimateMinutes}
+	if len(samples) < MinEstimateSamples {
+		return accuracy
+	}
+
+	estimated, actual := 0, 0
+	for _, sample := range samples {
+		estimated += sample.EstimatedMinutes * 60
+		actual += sample.ActualSeconds
+	}
+	if estimated == 0 || actual == 0 {
+		return accuracy
+	}
+
+	accuracy.Ratio = min(max(float64(actual)/float64(estimated), minEstimateRatio), maxEstimateRatio)
+	accuracy.AverageMinutes = max(actual/len(samples)/60, 1)
+	return accuracy
+}
+
+// Adjust is how long a todo is expected to take, its estimate corrected by the accuracy
+func (a Accuracy) Adjust(estimatedMinutes *int) int {
+	if estimatedMinutes == nil {
+		return a.AverageMinutes
+	}
+	return max(int(float64(*estimatedMinutes)*a.Ratio+0.5), 1)
+}
+
+// ForecastedTodo is an open todo with the day it is expected to be done
+type ForecastedTodo struct {
+	TodoID           uuid.UUID     `json:"todoId"`
+	Title            string        `json:"title"`
+	Priority         todo.Priority `json:"priority"`
+	DueDate          *time.Time    `json:"dueDate"`
+	EstimatedMinutes *int          `json:"estimatedMinutes"`
+	// ForecastMinutes is the estimate corrected by the accuracy of past estimates
+	ForecastMinutes int `json:"forecastMinutes"`
+	// CompletionDate is the day (YYYY-MM-DD) the todo is expected to be done
+	CompletionDate string `json:"completionDate"`
+	// Late tells whether the todo is expected to be done after its due date
+	Late bool `json:"late"`
+}
+
+type Forecast struct {
+	Timezone        string           `json:"timezone"`
+	CapacityMinutes int              `json:"capacityMinutes"`
+	Accuracy        Accuracy         `json:"accuracy"`
+	Todos           []ForecastedTodo `json:"todos"`
+	// CompletionDate is the day the last todo is expected to be done, nil without todos
+	CompletionDate *string `json:"completionDate"`
+	LateCount      int     `json:"lateCount"`
+	// Truncated tells whether the user has more open todos than a forecast reads, the latest
+	// due of them are left out
+	Truncated bool `json:"truncated"`
+}
+
+// NewForecast works through the todos in order from today, a day holding capacityMinutes of
+// work. A todo is done on the day its work runs out, todos longer than a day spill over.
+func NewForecast(todos []todo.Todo, accuracy Accuracy, today time.Time, capacityMinutes int) *Forecast {
+	forecast := &Forecast{
+		Timezone:        today.Location().String(),
+		CapacityMinutes: capacityMinutes,
+		Accuracy:        accuracy,
+		Todos:           make([]ForecastedTodo, 0, len(todos)),
+	}
+
+	day := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	left := capacityMinutes
+	for _, item := range todos {
+		var estimate *int
+		if item.Metadata != nil {
+			estimate = item.Metadata.EstimatedMinutes
+		}
+		minutes := accuracy.Adjust(estimate)
+
+		// Fill the day, move on to the next while work remains
+		for remaining := minutes; ; {
+			if left == 0 {
+				day = day.AddDate(0, 0, 1)
+				left = capacityMinutes
+			}
+			spent := min(remaining, left)
+			left -= spent
+			remaining -= spent
+			if remaining == 0 {
+				break
+			}
+		}
+
+		forecasted := ForecastedTodo{
+			TodoID:           item.ID,
+			Title:            item.Title,
+			Priority:         item.Priority,
+			DueDate:          item.DueDate,
+			EstimatedMinutes: estimate,
+			ForecastMinutes:  minutes,
+			CompletionDate:   day.Format(time.DateOnly),
+		}
+		if item.DueDate != nil {
+			// ISO dates sort as text
+			forecasted.Late = forecasted.CompletionDate > item.DueDate.In(today.Location()).Format(time.DateOnly)
+		}
+		if forecasted.Late {
+			forecast.LateCount++
+		}
+		forecast.Todos = append(forecast.Todos, forecasted)
+	}
+
+	if len(forecast.Todos) > 0 {
+		completion := forecast.Todos[len(forecast.Todos)-1].CompletionDate
+		forecast.CompletionDate = &completion
+	}
+
+	return forecast
+}
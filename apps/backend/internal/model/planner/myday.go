@@ -0,0 +1,69 @@
+package planner
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+)
+
+// MyDayState is where a todo picked for a day stands
+type MyDayState string
+
+const (
+	MyDayPlanned MyDayState = "planned"
+	// MyDayReview is a todo left open when its day ended, until it is planned again or dropped
+	MyDayReview MyDayState = "review"
+	// MyDayReviewed is a todo the user planned again or dropped after its day, it still counts
+	// as rolled over in the summary of the day
+	MyDayReviewed MyDayState = "reviewed"
+)
+
+// MyDayTodo is a todo on the list of the day. Picking it leaves its due date as it was.
+type MyDayTodo struct {
+	todo.Todo
+	AddedAt time.Time `json:"addedAt" db:"added_at"`
+}
+
+// ReviewTodo is a todo left open at the end of the day it was planned for, the latest one
+// when it was left open on several days
+type ReviewTodo struct {
+	todo.Todo
+	// PlannedFor is the day (YYYY-MM-DD) the todo was left open on
+	PlannedFor string `json:"plannedFor" db:"planned_for"`
+}
+
+// DaySummary tells how much of the list of a day got done
+type DaySummary struct {
+	Date    string `json:"date" db:"date"`
+	Planned int    `json:"planned" db:"planned"`
+	// Completed are the todos completed before the day ended
+	Completed int `json:"completed" db:"completed"`
+	// Rolled are the todos left open when the day ended
+	Rolled int `json:"rolled" db:"rolled"`
+	// CompletionRate is Completed over Planned, 0 for a day without todos
+	CompletionRate float64 `json:"completionRate" db:"-"`
+}
+
+// WithRate fills the completion rate of the summary
+func (s DaySummary) WithRate() DaySummary {
+	if s.Planned > 0 {
+		s.CompletionRate = float64(s.Completed) / float64(s.Planned)
+	}
+	return s
+}
+
+type MyDay struct {
+	Date     string      `json:"date"`
+	Timezone string      `json:"timezone"`
+	Todos    []MyDayTodo `json:"todos"`
+	// Review lists the todos left open on earlier days, until they are planned again or dropped
+	Review  []ReviewTodo `json:"review"`
+	Summary DaySummary   `json:"summary"`
+}
+
+// DayBounds returns the date (YYYY-MM-DD) of now in the location and when that day ends
+func DayBounds(now time.Time, location *time.Location) (string, time.Time) {
+	local := now.In(location)
+	endsAt := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, location)
+	return local.Format(time.DateOnly), endsAt
+}
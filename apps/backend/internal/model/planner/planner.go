@@ -0,0 +1,63 @@
+package planner
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+)
+
+const (
+	// DefaultCapacityMinutes is the work a day is assumed to fit unless the client says otherwise
+	DefaultCapacityMinutes = 480
+	// MaxBacklog caps the unscheduled todos listed next to the week
+	MaxBacklog = 100
+)
+
+// Day is a day of the week in the user's timezone with the todos due on it
+type Day struct {
+	Date  string      `json:"date"`
+	Todos []todo.Todo `json:"todos"`
+	// EstimatedMinutes sums the estimates of the open todos of the day
+	EstimatedMinutes int `json:"estimatedMinutes"`
+	// UnestimatedCount is the open todos of the day without an estimate, the sum leaves them out
+	UnestimatedCount int  `json:"unestimatedCount"`
+	OverCapacity     bool `json:"overCapacity"`
+	// ForecastMinutes is what the open todos of the day are expected to take going by past
+	// estimates, todos without an estimate included
+	ForecastMinutes int `json:"forecastMinutes"`
+	// Overcommitted tells whether the day holds more than its capacity once estimates are
+	// corrected, a day can be overcommitted without being over capacity on paper
+	Overcommitted bool `json:"overcommitted"`
+}
+
+type Planner struct {
+	// Week is the Monday the week starts on
+	Week            string `json:"week"`
+	Timezone        string `json:"timezone"`
+	CapacityMinutes int    `json:"capacityMinutes"`
+	// Accuracy is how the user's past estimates held up, the forecast of the days uses it
+	Accuracy Accuracy `json:"accuracy"`
+	Days     []Day    `json:"days"`
+	// Backlog is the open todos without a due date, high priority first
+	Backlog []todo.Todo `json:"backlog"`
+}
+
+// WeekStart returns midnight of the Monday of the week the day falls in, in the day's location
+func WeekStart(day time.Time) time.Time {
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	// Weekday counts from Sunday, weeks start on Monday
+	offset := (int(midnight.Weekday()) + 6) % 7
+	return midnight.AddDate(0, 0, -offset)
+}
+
+// DueAt is when a todo scheduled on the day becomes due: at the local time of day of its
+// current due date, or at the end of the day when it had none
+func DueAt(day time.Time, current *time.Time) time.Time {
+	if current == nil {
+		return time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 0, 0, day.Location())
+	}
+
+	local := current.In(day.Location())
+	return time.Date(day.Year(), day.Month(), day.Day(), local.Hour(), local.Minute(), local.Second(), 0,
+		day.Location())
+}
@@ -0,0 +1,59 @@
+package preview
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MaxLinksPerText caps the links of a description or comment that get a preview
+const MaxLinksPerText = 5
+
+// Status of a preview, a failed one is kept so the page isn't fetched on every mention
+type Status string
+
+const (
+	StatusReady  Status = "ready"
+	StatusFailed Status = "failed"
+)
+
+// Preview is the card of a linked page. Previews are shared by every todo and comment linking
+// to the same URL.
+type Preview struct {
+	URL         string    `json:"url" db:"url"`
+	Status      Status    `json:"-" db:"status"`
+	Title       *string   `json:"title" db:"title"`
+	Description *string   `json:"description" db:"description"`
+	ImageURL    *string   `json:"imageUrl" db:"image_url"`
+	SiteName    *string   `json:"siteName" db:"site_name"`
+	FetchedAt   time.Time `json:"fetchedAt" db:"fetched_at"`
+}
+
+// IsFresh tells whether the preview can be served as is, or the page should be fetched again
+func (p *Preview) IsFresh(now time.Time, ttl, failureTTL time.Duration) bool {
+	if p.Status == StatusFailed {
+		return now.Sub(p.FetchedAt) < failureTTL
+	}
+	return now.Sub(p.FetchedAt) < ttl
+}
+
+var linkPattern = regexp.MustCompile(`https?://[^\s<>"'\x60()\[\]{}]+`)
+
+// ExtractURLs returns the distinct links of a text in order of appearance, up to
+// MaxLinksPerText. Punctuation ending a sentence is not taken as part of a link.
+func ExtractURLs(text string) []string {
+	urls := []string{}
+	seen := map[string]bool{}
+	for _, match := range linkPattern.FindAllString(text, -1) {
+		link := strings.TrimRight(match, ".,;:!?*_~")
+		if seen[link] || len(link) > 2048 {
+			continue
+		}
+		seen[link] = true
+		urls = append(urls, link)
+		if len(urls) == MaxLinksPerText {
+			break
+		}
+	}
+	return urls
+}
@@ -0,0 +1,137 @@
+package report
+
+import (
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type GetSchedulesPayload struct{}
+
+func (p *GetSchedulesPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+// Definition is what a schedule sends, to whom and when
+type Definition struct {
+	Name       string   `json:"name" validate:"required,text,textmin=1,textmax=100"`
+	Format     Format   `json:"format" validate:"required,oneof=pdf csv"`
+	Filters    Filters  `json:"filters"`
+	Recipients []string `json:"recipients" validate:"required,min=1,max=10,unique,dive,required,email"`
+	Timing
+}
+
+// normalize checks the day the frequency needs and drops the one it doesn't, lower cases the
+// recipients and defaults the timezone to UTC
+func (d *Definition) normalize() error {
+	switch d.Frequency {
+	case FrequencyWeekly:
+		if d.Weekday == nil {
+			return validation.CustomValidationErrors{{
+				Field:   "weekday",
+				Code:    errs.FieldCodeRequired,
+				Message: "weekday is required for weekly reports",
+			}}
+		}
+		d.DayOfMonth = nil
+	case FrequencyMonthly:
+		if d.DayOfMonth == nil {
+			return validation.CustomValidationErrors{{
+				Field:   "dayOfMonth",
+				Code:    errs.FieldCodeRequired,
+				Message: "dayOfMonth is required for monthly reports",
+			}}
+		}
+		d.Weekday = nil
+	default:
+		d.Weekday = nil
+		d.DayOfMonth = nil
+	}
+
+	for i, recipient := range d.Recipients {
+		d.Recipients[i] = strings.ToLower(recipient)
+	}
+
+	if d.Timezone == "" {
+		d.Timezone = "UTC"
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type CreateSchedulePayload struct {
+	Definition
+}
+
+func (p *CreateSchedulePayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	return p.normalize()
+}
+
+// ------------------------------------------------------------
+
+// UpdateSchedulePayload replaces the schedule as a whole, fields left out are cleared. A
+// changed timing moves the next run, a paused schedule stays paused.
+type UpdateSchedulePayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+	Definition
+}
+
+func (p *UpdateSchedulePayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	return p.normalize()
+}
+
+// ------------------------------------------------------------
+
+type GetSchedulePayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *GetSchedulePayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type PauseSchedulePayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *PauseSchedulePayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type ResumeSchedulePayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *ResumeSchedulePayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type DeleteSchedulePayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *DeleteSchedulePayload) Validate() error {
+	return validation.Struct(p)
+}
@@ -0,0 +1,121 @@
+package report
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+// MaxPerUser bounds the reports a user schedules, every run renders the whole matching list
+const MaxPerUser = 20
+
+type Format string
+
+const (
+	FormatPDF Format = "pdf"
+	FormatCSV Format = "csv"
+)
+
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "daily"
+	FrequencyWeekly  Frequency = "weekly"
+	FrequencyMonthly Frequency = "monthly"
+)
+
+// Filters pick the todos of a report, as they pick those of a todo list export
+type Filters struct {
+	Search     *string    `json:"search,omitempty" validate:"omitempty,text,textmin=1,textmax=255"`
+	Status     *string    `json:"status,omitempty" validate:"omitempty,max=500"`
+	Priority   *string    `json:"priority,omitempty" validate:"omitempty,max=500"`
+	CategoryID *uuid.UUID `json:"categoryId,omitempty" validate:"omitempty,uuid"`
+}
+
+// Timing is when a report is sent: every day, on a day of the week or on a day of the month,
+// at an hour of its timezone
+type Timing struct {
+	Frequency Frequency `json:"frequency" db:"frequency" validate:"required,oneof=daily weekly monthly"`
+	Hour      int       `json:"hour" db:"hour" validate:"min=0,max=23"`
+	// Weekday is the day of weekly reports, 0 for Sunday
+	Weekday *int `json:"weekday" db:"weekday" validate:"omitempty,min=0,max=6"`
+	// DayOfMonth is the day of monthly reports, every month has it
+	DayOfMonth *int   `json:"dayOfMonth" db:"day_of_month" validate:"omitempty,min=1,max=28"`
+	Timezone   string `json:"timezone" db:"timezone" validate:"omitempty,timezone"`
+}
+
+// Location is the timezone of the timing, UTC when it can't be loaded
+func (t *Timing) Location() *time.Location {
+	location, err := time.LoadLocation(t.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// NextRun is the first time the report is due after after. Times are counted on the calendar
+// of the timezone, a DST change doesn't move the hour.
+func (t *Timing) NextRun(after time.Time) time.Time {
+	location := t.Location()
+	local := after.In(location)
+
+	var next time.Time
+	switch t.Frequency {
+	case FrequencyWeekly:
+		days := 0
+		if t.Weekday != nil {
+			days = (*t.Weekday - int(local.Weekday()) + 7) % 7
+		}
+		next = time.Date(local.Year(), local.Month(), local.Day()+days, t.Hour, 0, 0, 0, location)
+		if !next.After(after) {
+			next = time.Date(local.Year(), local.Month(), local.Day()+days+7, t.Hour, 0, 0, 0, location)
+		}
+	case FrequencyMonthly:
+		day := 1
+		if t.DayOfMonth != nil {
+			day = *t.DayOfMonth
+		}
+		next = time.Date(local.Year(), local.Month(), day, t.Hour, 0, 0, 0, location)
+		if !next.After(after) {
+			next = time.Date(local.Year(), local.Month()+1, day, t.Hour, 0, 0, 0, location)
+		}
+	default:
+		next = time.Date(local.Year(), local.Month(), local.Day(), t.Hour, 0, 0, 0, location)
+		if !next.After(after) {
+			next = time.Date(local.Year(), local.Month(), local.Day()+1, t.Hour, 0, 0, 0, location)
+		}
+	}
+
+	return next
+}
+
+// Schedule sends a report of the todos matching its filters to its recipients on its timing.
+// Every run is stored as an export and the recipients are emailed a link to it.
+type Schedule struct {
+	model.Base
+	UserID     string   `json:"-" db:"user_id"`
+	Name       string   `json:"name" db:"name"`
+	Format     Format   `json:"format" db:"format"`
+	Filters    Filters  `json:"filters" db:"filters"`
+	Recipients []string `json:"recipients" db:"recipients"`
+	Timing
+	// NextRunAt is when the report is sent next, it is kept while the schedule is paused
+	NextRunAt time.Time  `json:"nextRunAt" db:"next_run_at"`
+	PausedAt  *time.Time `json:"pausedAt" db:"paused_at"`
+	LastRunAt *time.Time `json:"lastRunAt" db:"last_run_at"`
+	// LastError tells why the last run failed, nil when it succeeded
+	LastError *string `json:"lastError" db:"last_error"`
+}
+
+// Query is the todo list export query the report is rendered with
+func (s *Schedule) Query() *todo.ExportTodosPDFQuery {
+	return &todo.ExportTodosPDFQuery{
+		Search:     s.Filters.Search,
+		Status:     s.Filters.Status,
+		Priority:   s.Filters.Priority,
+		CategoryID: s.Filters.CategoryID,
+		Timezone:   &s.Timezone,
+	}
+}
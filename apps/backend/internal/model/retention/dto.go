@@ -0,0 +1,48 @@
+package retention
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+)
+
+// ------------------------------------------------------------
+
+type GetPoliciesPayload struct{}
+
+func (p *GetPoliciesPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type SetPolicyPayload struct {
+	WorkspaceID        string `param:"workspaceId" validate:"required,min=1,max=255"`
+	ArchivedTodoMonths *int   `json:"archivedTodoMonths" validate:"omitempty,min=1,max=120"`
+	MaxCommentsPerTodo *int   `json:"maxCommentsPerTodo" validate:"omitempty,min=1,max=10000"`
+	AttachmentDays     *int   `json:"attachmentDays" validate:"omitempty,min=1,max=3650"`
+	Enforce            *bool  `json:"enforce" validate:"required"`
+}
+
+func (p *SetPolicyPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type DeletePolicyPayload struct {
+	WorkspaceID string `param:"workspaceId" validate:"required,min=1,max=255"`
+}
+
+func (p *DeletePolicyPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type PreviewPolicyPayload struct {
+	// WorkspaceID selects a workspace policy, the default policy when empty
+	WorkspaceID string `query:"workspaceId" validate:"max=255"`
+}
+
+func (p *PreviewPolicyPayload) Validate() error {
+	return validation.Struct(p)
+}
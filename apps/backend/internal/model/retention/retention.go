@@ -0,0 +1,90 @@
+package retention
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/model"
+)
+
+// Rules select the data retention deletes, a nil rule keeps the data forever
+type Rules struct {
+	// ArchivedTodoMonths purges archived todos untouched for that many months
+	ArchivedTodoMonths *int `json:"archivedTodoMonths" db:"archived_todo_months"`
+	// MaxCommentsPerTodo deletes the oldest comments of a todo beyond that many
+	MaxCommentsPerTodo *int `json:"maxCommentsPerTodo" db:"max_comments_per_todo"`
+	// AttachmentDays expires attachments uploaded that many days ago
+	AttachmentDays *int `json:"attachmentDays" db:"attachment_days"`
+}
+
+// RulesFromConfig turns the zero values of the config, which keep data forever, into nil rules
+func RulesFromConfig(cfg *config.RetentionConfig) Rules {
+	rule := func(value int) *int {
+		if value <= 0 {
+			return nil
+		}
+		return &value
+	}
+
+	return Rules{
+		ArchivedTodoMonths: rule(cfg.ArchivedTodoMonths),
+		MaxCommentsPerTodo: rule(cfg.MaxCommentsPerTodo),
+		AttachmentDays:     rule(cfg.AttachmentDays),
+	}
+}
+
+// IsEmpty tells whether the rules keep everything
+func (r Rules) IsEmpty() bool {
+	return r.ArchivedTodoMonths == nil && r.MaxCommentsPerTodo == nil && r.AttachmentDays == nil
+}
+
+// ArchivedBefore is when an archived todo must have been last touched to be purged, nil
+// when archived todos are kept
+func (r Rules) ArchivedBefore(now time.Time) *time.Time {
+	if r.ArchivedTodoMonths == nil {
+		return nil
+	}
+	cutoff := now.AddDate(0, -*r.ArchivedTodoMonths, 0)
+	return &cutoff
+}
+
+// UploadedBefore is when an attachment must have been uploaded to expire, nil when
+// attachments are kept
+func (r Rules) UploadedBefore(now time.Time) *time.Time {
+	if r.AttachmentDays == nil {
+		return nil
+	}
+	cutoff := now.AddDate(0, 0, -*r.AttachmentDays)
+	return &cutoff
+}
+
+// Policy overrides the configured rules for the todos created in a workspace
+type Policy struct {
+	WorkspaceID string `json:"workspaceId" db:"workspace_id"`
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+	Rules
+	// Enforce lets the data-retention job delete, it only reports what it would delete otherwise
+	Enforce   bool   `json:"enforce" db:"enforce"`
+	UpdatedBy string `json:"updatedBy" db:"updated_by"`
+}
+
+// Overview is the operator view of retention: the configured default and every workspace policy
+type Overview struct {
+	Default         Rules    `json:"default"`
+	DefaultEnforced bool     `json:"defaultEnforced"`
+	Policies        []Policy `json:"policies"`
+}
+
+// Report counts what the rules of a policy select. Every rule is counted on its own, the
+// comments and attachments of a purged todo go with it.
+type Report struct {
+	// WorkspaceID is empty for the default policy
+	WorkspaceID     string `json:"workspaceId" db:"-"`
+	Rules           Rules  `json:"rules" db:"-"`
+	DryRun          bool   `json:"dryRun" db:"-"`
+	ArchivedTodos   int64  `json:"archivedTodos" db:"archived_todos"`
+	Comments        int64  `json:"comments" db:"comments"`
+	Attachments     int64  `json:"attachments" db:"attachments"`
+	AttachmentBytes int64  `json:"attachmentBytes" db:"attachment_bytes"`
+}
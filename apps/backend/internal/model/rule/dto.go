@@ -0,0 +1,125 @@
+package rule
+
+import (
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type CreateRulePayload struct {
+	Name       string      `json:"name" validate:"required,min=1,max=100"`
+	Trigger    Trigger     `json:"trigger" validate:"required,oneof=todo_created todo_completed todo_overdue todo_tagged"`
+	Conditions *Conditions `json:"conditions"`
+	Actions    []Action    `json:"actions" validate:"required,min=1,max=10,dive"`
+	Enabled    *bool       `json:"enabled"`
+}
+
+func (p *CreateRulePayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if err := validateActions(p.Actions); err != nil {
+		return err
+	}
+
+	if p.Conditions == nil {
+		p.Conditions = &Conditions{}
+	}
+	if p.Enabled == nil {
+		defaultEnabled := true
+		p.Enabled = &defaultEnabled
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type UpdateRulePayload struct {
+	ID         uuid.UUID   `param:"id" validate:"required,uuid"`
+	Name       *string     `json:"name" validate:"omitempty,min=1,max=100"`
+	Trigger    *Trigger    `json:"trigger" validate:"omitempty,oneof=todo_created todo_completed todo_overdue todo_tagged"`
+	Conditions *Conditions `json:"conditions"`
+	Actions    []Action    `json:"actions" validate:"omitempty,min=1,max=10,dive"`
+	Enabled    *bool       `json:"enabled"`
+}
+
+func (p *UpdateRulePayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	return validateActions(p.Actions)
+}
+
+// validateActions checks each action carries the field its type needs
+func validateActions(actions []Action) error {
+	var validationErrors validation.CustomValidationErrors
+	for i, action := range actions {
+		if field, ok := action.requiredField(); !ok {
+			validationErrors = append(validationErrors, validation.CustomValidationError{
+				Field:   fmt.Sprintf("actions[%d].%s", i, field),
+				Code:    errs.FieldCodeRequired,
+				Message: fmt.Sprintf("%s is required by %s actions", field, action.Type),
+			})
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return validationErrors
+	}
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetRulesQuery struct{}
+
+func (q *GetRulesQuery) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetRulePayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *GetRulePayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type DeleteRulePayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *DeleteRulePayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetExecutionsQuery struct {
+	ID    uuid.UUID `param:"id" validate:"required,uuid"`
+	Limit *int      `query:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+func (q *GetExecutionsQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if q.Limit == nil {
+		defaultLimit := 50
+		q.Limit = &defaultLimit
+	}
+
+	return nil
+}
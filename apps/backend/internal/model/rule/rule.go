@@ -0,0 +1,218 @@
+package rule
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+// Trigger is the change of a todo a rule reacts to
+type Trigger string
+
+const (
+	TriggerTodoCreated   Trigger = "todo_created"
+	TriggerTodoCompleted Trigger = "todo_completed"
+	TriggerTodoOverdue   Trigger = "todo_overdue"
+	TriggerTodoTagged    Trigger = "todo_tagged"
+)
+
+type ActionType string
+
+const (
+	ActionSetPriority    ActionType = "set_priority"
+	ActionMoveCategory   ActionType = "move_category"
+	ActionAddTag         ActionType = "add_tag"
+	ActionNotify         ActionType = "notify"
+	ActionCreateFollowUp ActionType = "create_follow_up"
+)
+
+// MaxDepth caps how many rules fire one after the other off a single change of a todo. The
+// changes a rule makes can trigger other rules, but never the rules that led to it.
+const MaxDepth = 5
+
+// MaxRulesPerUser keeps the evaluation of an event cheap
+const MaxRulesPerUser = 50
+
+// Rule runs its actions on a todo when the trigger fires and every condition holds
+type Rule struct {
+	model.Base
+	UserID     string     `json:"userId" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	Trigger    Trigger    `json:"trigger" db:"trigger"`
+	Conditions Conditions `json:"conditions" db:"conditions"`
+	Actions    []Action   `json:"actions" db:"actions"`
+	Enabled    bool       `json:"enabled" db:"enabled"`
+}
+
+// Conditions all have to hold for a rule to run, those left out hold for every todo
+type Conditions struct {
+	// Priority is a key of the priority scale of the workspace of the todo
+	Priority   *todo.Priority `json:"priority,omitempty" validate:"omitempty,min=1,max=40"`
+	CategoryID *uuid.UUID     `json:"categoryId,omitempty" validate:"omitempty,uuid"`
+	// Tag is a tag the todo carries. On todo_tagged it has to be the tag that was added.
+	Tag           *string `json:"tag,omitempty" validate:"omitempty,text,textmin=1,textmax=50"`
+	TitleContains *string `json:"titleContains,omitempty" validate:"omitempty,text,textmin=1,textmax=255"`
+}
+
+// Match tells whether the todo the event is about meets the conditions
+func (c *Conditions) Match(item *todo.Todo, event *Event) bool {
+	if c.Priority != nil && item.Priority != *c.Priority {
+		return false
+	}
+	if c.CategoryID != nil && (item.CategoryID == nil || *item.CategoryID != *c.CategoryID) {
+		return false
+	}
+	if c.Tag != nil {
+		if event.Trigger == TriggerTodoTagged {
+			if event.Tag == nil || !strings.EqualFold(*event.Tag, *c.Tag) {
+				return false
+			}
+		} else if !HasTag(item, *c.Tag) {
+			return false
+		}
+	}
+	if c.TitleContains != nil && !strings.Contains(strings.ToLower(item.Title), strings.ToLower(*c.TitleContains)) {
+		return false
+	}
+	return true
+}
+
+// Action is one change a rule makes, only the fields of its type are set
+type Action struct {
+	Type ActionType `json:"type" validate:"required,oneof=set_priority move_category add_tag notify create_follow_up"`
+	// Priority is set by set_priority, and given to the todo create_follow_up creates
+	Priority   *todo.Priority `json:"priority,omitempty" validate:"omitempty,oneof=low medium high"`
+	CategoryID *uuid.UUID     `json:"categoryId,omitempty" validate:"omitempty,uuid"`
+	Tag        *string        `json:"tag,omitempty" validate:"omitempty,text,textmin=1,textmax=50"`
+	// Message is added to the notification email
+	Message *string `json:"message,omitempty" validate:"omitempty,max=500"`
+	// Title and DueInDays describe the follow-up todo, it is due that many days after the rule ran
+	Title     *string `json:"title,omitempty" validate:"omitempty,text,textmin=1,textmax=255"`
+	DueInDays *int    `json:"dueInDays,omitempty" validate:"omitempty,min=1,max=365"`
+}
+
+// requiredField names the field the action type can't do without, empty when there is none
+func (a *Action) requiredField() (string, bool) {
+	switch a.Type {
+	case ActionSetPriority:
+		return "priority", a.Priority != nil
+	case ActionMoveCategory:
+		return "categoryId", a.CategoryID != nil
+	case ActionAddTag:
+		return "tag", a.Tag != nil
+	case ActionCreateFollowUp:
+		return "title", a.Title != nil
+	}
+	return "", true
+}
+
+// HasTag tells whether the todo carries the tag, tags are compared ignoring case
+func HasTag(item *todo.Todo, tag string) bool {
+	if item.Metadata == nil {
+		return false
+	}
+	return slices.ContainsFunc(item.Metadata.Tags, func(existing string) bool {
+		return strings.EqualFold(existing, tag)
+	})
+}
+
+// Event is a change of a todo the rules of its owner are evaluated against
+type Event struct {
+	UserID  string    `json:"userId"`
+	Trigger Trigger   `json:"trigger"`
+	TodoID  uuid.UUID `json:"todoId"`
+	// Tag is the tag that was added, set on todo_tagged
+	Tag *string `json:"tag,omitempty"`
+	// Depth counts the rules that ran before this event, Chain lists them
+	Depth int         `json:"depth"`
+	Chain []uuid.UUID `json:"chain,omitempty"`
+	// CompletedAt is when the todo was completed, set on todo_completed. Completions can be
+	// undone for a while, the rules don't run for one that was.
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// Notice is what a notify action tells about a todo, the notices of a bulk change are sent
+// together
+type Notice struct {
+	RuleName  string    `json:"ruleName"`
+	TodoID    uuid.UUID `json:"todoId"`
+	TodoTitle string    `json:"todoTitle"`
+	Message   *string   `json:"message,omitempty"`
+}
+
+// Next is the event a change made by the rule raises
+func (e *Event) Next(ruleID uuid.UUID, trigger Trigger, todoID uuid.UUID, tag *string) *Event {
+	return &Event{
+		UserID:  e.UserID,
+		Trigger: trigger,
+		TodoID:  todoID,
+		Tag:     tag,
+		Depth:   e.Depth + 1,
+		Chain:   append(slices.Clone(e.Chain), ruleID),
+	}
+}
+
+// InChain tells whether the rule led to the event, running it again would loop
+func (e *Event) InChain(ruleID uuid.UUID) bool {
+	return slices.Contains(e.Chain, ruleID)
+}
+
+// Undone tells whether the completion the event is about was undone: the todo is open again,
+// or was completed another time since
+func (e *Event) Undone(item *todo.Todo) bool {
+	if e.Trigger != TriggerTodoCompleted || e.CompletedAt == nil {
+		return false
+	}
+	return item.StatusCategory != todo.StatusCompleted || item.CompletedAt == nil || !item.CompletedAt.Equal(*e.CompletedAt)
+}
+
+type ExecutionStatus string
+
+const (
+	ExecutionSucceeded ExecutionStatus = "succeeded"
+	ExecutionFailed    ExecutionStatus = "failed"
+	// ExecutionSkipped is logged when loop protection kept a matching rule from running, or
+	// the completion it listened to was undone
+	ExecutionSkipped ExecutionStatus = "skipped"
+)
+
+// Execution is a line of the log of a rule: one run against a todo
+type Execution struct {
+	model.BaseWithId
+	model.BaseWithCreatedAt
+	RuleID  uuid.UUID       `json:"ruleId" db:"rule_id"`
+	UserID  string          `json:"userId" db:"user_id"`
+	TodoID  uuid.UUID       `json:"todoId" db:"todo_id"`
+	Trigger Trigger         `json:"trigger" db:"trigger"`
+	Status  ExecutionStatus `json:"status" db:"status"`
+	Depth   int             `json:"depth" db:"depth"`
+	// ActionsApplied are the actions that went through, in order
+	ActionsApplied []ActionType `json:"actionsApplied" db:"actions_applied"`
+	Error          *string      `json:"error" db:"error"`
+}
+
+// NewExecution starts the log line of a run, the caller fills in how it went
+func NewExecution(ruleItem *Rule, event *Event) *Execution {
+	return &Execution{
+		RuleID:         ruleItem.ID,
+		UserID:         event.UserID,
+		TodoID:         event.TodoID,
+		Trigger:        event.Trigger,
+		Status:         ExecutionSucceeded,
+		Depth:          event.Depth,
+		ActionsApplied: []ActionType{},
+	}
+}
+
+// FollowUpDueDate is when the todo created by a create_follow_up action is due
+func (a *Action) FollowUpDueDate(now time.Time) *time.Time {
+	if a.DueInDays == nil {
+		return nil
+	}
+	dueDate := now.AddDate(0, 0, *a.DueInDays)
+	return &dueDate
+}
@@ -0,0 +1,51 @@
+package search
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+)
+
+// ------------------------------------------------------------
+
+type SearchQuery struct {
+	Q     string `query:"q" validate:"required,text,textmin=1,textmax=255"`
+	Mode  *Mode  `query:"mode" validate:"omitempty,oneof=keyword semantic"`
+	Limit *int   `query:"limit" validate:"omitempty,min=1,max=50"`
+}
+
+func (q *SearchQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if q.Mode == nil {
+		defaultMode := ModeKeyword
+		q.Mode = &defaultMode
+	}
+
+	if q.Limit == nil {
+		defaultLimit := 20
+		q.Limit = &defaultLimit
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type SuggestQuery struct {
+	Q     string `query:"q" validate:"required,text,textmin=1,textmax=100"`
+	Limit *int   `query:"limit" validate:"omitempty,min=1,max=20"`
+}
+
+func (q *SuggestQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if q.Limit == nil {
+		defaultLimit := 8
+		q.Limit = &defaultLimit
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package search
+
+import (
+	"math"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+type Mode string
+
+const (
+	// ModeKeyword matches todos by full text, substring and typo tolerant title similarity
+	ModeKeyword Mode = "keyword"
+	// ModeSemantic matches todos by the meaning of their text, even without a shared word
+	ModeSemantic Mode = "semantic"
+)
+
+// Result is a matched todo, Score is the keyword relevance or the cosine similarity (0-1)
+// depending on the mode. Higher is better in both.
+type Result struct {
+	todo.Todo
+	Score float64 `json:"score" db:"score"`
+}
+
+type Results struct {
+	Mode Mode     `json:"mode"`
+	Data []Result `json:"data"`
+}
+
+// PendingEmbedding is a todo without an embedding of its current text by the current model
+type PendingEmbedding struct {
+	TodoID      uuid.UUID `db:"todo_id"`
+	UserID      string    `db:"user_id"`
+	Text        string    `db:"text"`
+	ContentHash string    `db:"content_hash"`
+}
+
+// SuggestionType is the kind of thing a typeahead suggestion leads to
+type SuggestionType string
+
+const (
+	SuggestionTodo     SuggestionType = "todo"
+	SuggestionCategory SuggestionType = "category"
+	SuggestionTag      SuggestionType = "tag"
+)
+
+// suggestionHalfLife is how long it takes for a use to count half as much
+const suggestionHalfLife = 7 * 24 * time.Hour
+
+// Candidate is something of the user matching what they typed, with how often and how lately
+// they used it: views of a todo, todos in a category or carrying a tag
+type Candidate struct {
+	Type  SuggestionType `db:"type"`
+	ID    *uuid.UUID     `db:"id"`
+	Label string         `db:"label"`
+	// Prefix is set when the label starts with what was typed, not only contains it
+	Prefix     bool      `db:"prefix"`
+	Uses       int       `db:"uses"`
+	LastUsedAt time.Time `db:"last_used_at"`
+}
+
+// Score ranks candidates by frecency: uses count logarithmically and fade by half every week
+// since the last one. Labels starting with what was typed count twice as much as those merely
+// containing it.
+func (c *Candidate) Score(now time.Time) float64 {
+	age := max(now.Sub(c.LastUsedAt), 0)
+	frecency := math.Log2(1+float64(c.Uses)) * math.Exp2(-float64(age)/float64(suggestionHalfLife))
+
+	score := 1 + frecency
+	if c.Prefix {
+		score *= 2
+	}
+	return score
+}
+
+// Suggestion is a quick result of the typeahead, ID is set for todos and categories
+type Suggestion struct {
+	Type  SuggestionType `json:"type"`
+	ID    *uuid.UUID     `json:"id,omitempty"`
+	Label string         `json:"label"`
+	Score float64        `json:"score"`
+}
+
+type Suggestions struct {
+	Data []Suggestion `json:"data"`
+}
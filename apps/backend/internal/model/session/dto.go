@@ -0,0 +1,25 @@
+package session
+
+// ------------------------------------------------------------
+
+type CreateSessionPayload struct{}
+
+func (p *CreateSessionPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type DeleteSessionPayload struct{}
+
+func (p *DeleteSessionPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetCSRFTokenPayload struct{}
+
+func (p *GetCSRFTokenPayload) Validate() error {
+	return nil
+}
@@ -0,0 +1,17 @@
+package session
+
+import "time"
+
+// CookieSession is a cookie session. The session token itself is in an HTTP-only cookie, scripts only
+// ever see the CSRF token to echo in the X-CSRF-Token header.
+type CookieSession struct {
+	// ExpiresAt is when the session token expires, the cookie expires with it
+	ExpiresAt time.Time `json:"expiresAt"`
+	CSRFToken string    `json:"csrfToken"`
+}
+
+// CSRFToken is the token unsafe requests of a cookie session echo in the X-CSRF-Token header.
+// Its cookie can't be read by pages on another domain than the API, they fetch it instead.
+type CSRFToken struct {
+	Token string `json:"token"`
+}
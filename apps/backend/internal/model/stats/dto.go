@@ -0,0 +1,43 @@
+package stats
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+)
+
+// ------------------------------------------------------------
+
+type GetOverviewQuery struct {
+	Days *int `query:"days" validate:"omitempty,min=1,max=365"`
+}
+
+func (q *GetOverviewQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if q.Days == nil {
+		defaultDays := 30
+		q.Days = &defaultDays
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetProductivityQuery struct {
+	Days *int `query:"days" validate:"omitempty,oneof=7 30 90 365"`
+}
+
+func (q *GetProductivityQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if q.Days == nil {
+		defaultDays := 30
+		q.Days = &defaultDays
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+// Summary is a user's totals as of the last refresh of the dashboard aggregates
+type Summary struct {
+	Total                    int        `json:"total" db:"total"`
+	Completed                int        `json:"completed" db:"completed"`
+	Open                     int        `json:"open" db:"open"`
+	AverageCompletionSeconds *float64   `json:"averageCompletionSeconds" db:"avg_completion_seconds"`
+	RefreshedAt              *time.Time `json:"refreshedAt" db:"refreshed_at"`
+}
+
+// DailyCompletions counts the todos completed on a UTC day (YYYY-MM-DD)
+type DailyCompletions struct {
+	Day       string `json:"day" db:"day"`
+	Completed int    `json:"completed" db:"completed"`
+}
+
+type CategoryStats struct {
+	CategoryID               uuid.UUID `json:"categoryId" db:"category_id"`
+	Name                     string    `json:"name" db:"name"`
+	Color                    string    `json:"color" db:"color"`
+	Total                    int       `json:"total" db:"total"`
+	Completed                int       `json:"completed" db:"completed"`
+	AverageCompletionSeconds *float64  `json:"averageCompletionSeconds" db:"avg_completion_seconds"`
+}
+
+type Overview struct {
+	Summary
+	CompletedPerDay []DailyCompletions `json:"completedPerDay"`
+	Categories      []CategoryStats    `json:"categories"`
+}
+
+// Streaks count consecutive UTC days with at least one completion or completed focus session
+// within the window, the current streak still counts when nothing was completed yet today
+type Streaks struct {
+	Current int `json:"current"`
+	Longest int `json:"longest"`
+}
+
+// HeatmapCell counts the completions on a UTC weekday (0 is Sunday) and hour
+type HeatmapCell struct {
+	Weekday   int `json:"weekday" db:"weekday"`
+	Hour      int `json:"hour" db:"hour"`
+	Completed int `json:"completed" db:"completed"`
+}
+
+type PriorityCompletion struct {
+	Priority                 todo.Priority `json:"priority" db:"priority"`
+	Completed                int           `json:"completed" db:"completed"`
+	AverageCompletionSeconds *float64      `json:"averageCompletionSeconds" db:"avg_completion_seconds"`
+}
+
+type CategoryCompletion struct {
+	CategoryID               uuid.UUID `json:"categoryId" db:"category_id"`
+	Name                     string    `json:"name" db:"name"`
+	Color                    string    `json:"color" db:"color"`
+	Completed                int       `json:"completed" db:"completed"`
+	AverageCompletionSeconds *float64  `json:"averageCompletionSeconds" db:"avg_completion_seconds"`
+}
+
+// OverdueRatio covers the todos that fell due within the window, a todo is overdue when it was
+// completed after its due date or is still not completed
+type OverdueRatio struct {
+	Due     int     `json:"due" db:"due"`
+	Overdue int     `json:"overdue" db:"overdue"`
+	Ratio   float64 `json:"ratio" db:"-"`
+}
+
+// FocusTotals sums the focus sessions completed within the window
+type FocusTotals struct {
+	Sessions       int `json:"sessions" db:"sessions"`
+	FocusedMinutes int `json:"focusedMinutes" db:"focused_minutes"`
+}
+
+// Productivity is computed live from the todos over the selected window, unlike the overview
+type Productivity struct {
+	Days         int                  `json:"days"`
+	Since        time.Time            `json:"since"`
+	Streaks      Streaks              `json:"streaks"`
+	Heatmap      []HeatmapCell        `json:"heatmap"`
+	ByPriority   []PriorityCompletion `json:"byPriority"`
+	ByCategory   []CategoryCompletion `json:"byCategory"`
+	OverdueRatio OverdueRatio         `json:"overdueRatio"`
+	Focus        FocusTotals          `json:"focus"`
+}
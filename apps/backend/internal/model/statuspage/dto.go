@@ -0,0 +1,95 @@
+package statuspage
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type GetPagePayload struct{}
+
+func (p *GetPagePayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetIncidentsQuery struct {
+	// Days is how far back incidents are listed by when they started, open ones always are
+	Days *int `query:"days" validate:"omitempty,min=1,max=365"`
+}
+
+func (p *GetIncidentsQuery) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if p.Days == nil {
+		defaultDays := 90
+		p.Days = &defaultDays
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type CreateIncidentPayload struct {
+	Title      string          `json:"title" validate:"required,min=1,max=200"`
+	Message    string          `json:"message" validate:"required,min=1,max=5000"`
+	Status     *IncidentStatus `json:"status" validate:"omitempty,oneof=investigating identified monitoring resolved"`
+	Impact     Impact          `json:"impact" validate:"required,oneof=minor major"`
+	Components []Component     `json:"components" validate:"required,min=1,max=4,unique,dive,oneof=api database job_queue notifications"`
+	// StartedAt backdates an incident noticed late, it started now when nil
+	StartedAt *time.Time `json:"startedAt"`
+}
+
+func (p *CreateIncidentPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if p.StartedAt != nil && p.StartedAt.After(time.Now()) {
+		return validation.CustomValidationErrors{{
+			Field:   "startedAt",
+			Code:    errs.FieldCodeInvalidValue,
+			Message: "startedAt can't be in the future",
+		}}
+	}
+
+	if p.Status == nil {
+		defaultStatus := IncidentInvestigating
+		p.Status = &defaultStatus
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type UpdateIncidentPayload struct {
+	ID         uuid.UUID       `param:"id" validate:"required,uuid"`
+	Title      *string         `json:"title" validate:"omitempty,min=1,max=200"`
+	Message    *string         `json:"message" validate:"omitempty,min=1,max=5000"`
+	Status     *IncidentStatus `json:"status" validate:"omitempty,oneof=investigating identified monitoring resolved"`
+	Impact     *Impact         `json:"impact" validate:"omitempty,oneof=minor major"`
+	Components []Component     `json:"components" validate:"omitempty,min=1,max=4,unique,dive,oneof=api database job_queue notifications"`
+}
+
+func (p *UpdateIncidentPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type DeleteIncidentPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *DeleteIncidentPayload) Validate() error {
+	return validation.Struct(p)
+}
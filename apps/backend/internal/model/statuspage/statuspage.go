@@ -0,0 +1,100 @@
+package statuspage
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/health"
+)
+
+// Component is a part of the service shown on the status page, made of the dependencies the
+// deep health check probes
+type Component string
+
+const (
+	ComponentAPI           Component = "api"
+	ComponentDatabase      Component = "database"
+	ComponentJobQueue      Component = "job_queue"
+	ComponentNotifications Component = "notifications"
+)
+
+// Components are listed on the status page in this order
+var Components = []Component{ComponentAPI, ComponentDatabase, ComponentJobQueue, ComponentNotifications}
+
+type IncidentStatus string
+
+const (
+	IncidentInvestigating IncidentStatus = "investigating"
+	IncidentIdentified    IncidentStatus = "identified"
+	IncidentMonitoring    IncidentStatus = "monitoring"
+	IncidentResolved      IncidentStatus = "resolved"
+)
+
+type Impact string
+
+const (
+	// ImpactMinor degrades the components of an open incident
+	ImpactMinor Impact = "minor"
+	// ImpactMajor makes the components of an open incident unhealthy
+	ImpactMajor Impact = "major"
+)
+
+// Status is what an open incident makes its components at best
+func (i Impact) Status() health.Status {
+	if i == ImpactMajor {
+		return health.StatusUnhealthy
+	}
+	return health.StatusDegraded
+}
+
+// Day rolls up the health checks of a component over a day
+type Day struct {
+	Component Component `json:"-" db:"component"`
+	Day       time.Time `json:"day" db:"day"`
+	Checks    int       `json:"checks" db:"checks"`
+	Degraded  int       `json:"degraded" db:"degraded"`
+	Failures  int       `json:"failures" db:"failures"`
+	// LastStatus is the outcome of the latest check of the day
+	LastStatus    health.Status `json:"-" db:"last_status"`
+	LastCheckedAt time.Time     `json:"-" db:"last_checked_at"`
+}
+
+// Incident is an outage or degradation announced by operators
+type Incident struct {
+	model.BaseWithId
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+	Title      string         `json:"title" db:"title"`
+	Message    string         `json:"message" db:"message"`
+	Status     IncidentStatus `json:"status" db:"status"`
+	Impact     Impact         `json:"impact" db:"impact"`
+	Components []Component    `json:"components" db:"components"`
+	StartedAt  time.Time      `json:"startedAt" db:"started_at"`
+	ResolvedAt *time.Time     `json:"resolvedAt" db:"resolved_at"`
+	CreatedBy  string         `json:"-" db:"created_by"`
+}
+
+// IsOpen tells whether the incident still affects its components
+func (i *Incident) IsOpen() bool {
+	return i.Status != IncidentResolved
+}
+
+// ComponentStatus is a component with its current status and the history of its checks
+type ComponentStatus struct {
+	Component Component     `json:"component"`
+	Status    health.Status `json:"status"`
+	// CheckedAt is when the component was last checked, nil when it never was
+	CheckedAt *time.Time `json:"checkedAt"`
+	// Uptime is the share of the checks of the history that passed, nil without any check
+	Uptime  *float64 `json:"uptime"`
+	History []Day    `json:"history"`
+}
+
+// Page is the public status page: the status of every component and the incidents that are
+// open or were resolved lately
+type Page struct {
+	Status      health.Status     `json:"status"`
+	Components  []ComponentStatus `json:"components"`
+	Incidents   []Incident        `json:"incidents"`
+	HistoryDays int               `json:"historyDays"`
+}
@@ -1,10 +1,41 @@
 package todo
 
 import (
+	"time"
+
 	"github.com/Sameer16536/ExecuTask/internal/model"
 	"github.com/google/uuid"
 )
 
+const (
+	// AttachmentKeyPrefix is where attachment objects are stored in the bucket
+	AttachmentKeyPrefix = "todos/attachments/"
+	// ThumbnailKeyPrefix is where the thumbnails of image attachments are stored, apart from the
+	// attachments so reconciling either doesn't take the other for orphans
+	ThumbnailKeyPrefix = "todos/thumbnails/"
+)
+
+// TranscriptionStatus tracks the transcript of a voice note, it is nil for other attachments
+type TranscriptionStatus string
+
+const (
+	TranscriptionPending   TranscriptionStatus = "pending"
+	TranscriptionCompleted TranscriptionStatus = "completed"
+	TranscriptionFailed    TranscriptionStatus = "failed"
+)
+
+// ScanStatus tracks the virus scan of an attachment, it is nil for those uploaded while scanning
+// was disabled
+type ScanStatus string
+
+const (
+	ScanPending  ScanStatus = "pending"
+	ScanClean    ScanStatus = "clean"
+	ScanInfected ScanStatus = "infected"
+	// ScanFailed keeps the attachment quarantined, the scanner couldn't tell
+	ScanFailed ScanStatus = "failed"
+)
+
 type TodoAttachment struct {
 	model.Base
 	TodoID      uuid.UUID `json:"todoId" db:"todo_id"`
@@ -13,4 +44,46 @@ type TodoAttachment struct {
 	DownloadKey string    `json:"downloadKey" db:"download_key"`
 	FileSize    *int64    `json:"fileSize" db:"file_size"`
 	MimeType    *string   `json:"mimeType" db:"mime_type"`
+
+	TranscriptionStatus *TranscriptionStatus `json:"transcriptionStatus" db:"transcription_status"`
+	// Transcript is kept whole, the todo description only gets what fits
+	Transcript *string `json:"transcript" db:"transcript"`
+
+	// ThumbnailKey is set once the thumbnail of an image is rendered
+	ThumbnailKey *string `json:"thumbnailKey" db:"thumbnail_key"`
+	// ProcessedAt is when the metadata of an image was stripped, nil until then and for other files
+	ProcessedAt *time.Time `json:"processedAt" db:"processed_at"`
+
+	ScanStatus *ScanStatus `json:"scanStatus" db:"scan_status"`
+	// ScanSignature names what the scanner found in an infected file
+	ScanSignature *string    `json:"scanSignature" db:"scan_signature"`
+	ScannedAt     *time.Time `json:"scannedAt" db:"scanned_at"`
+}
+
+// ThumbnailKey is where the thumbnail of an attachment is stored
+func ThumbnailKey(attachmentID uuid.UUID) string {
+	return ThumbnailKeyPrefix + attachmentID.String() + ".jpg"
+}
+
+// IsProcessable tells whether the attachment is an image whose metadata is stripped and which
+// gets a thumbnail
+func (a *TodoAttachment) IsProcessable() bool {
+	if a.MimeType == nil {
+		return false
+	}
+	switch *a.MimeType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+// IsAvailable tells whether the attachment may be downloaded and processed, it is not while it is
+// quarantined or after it was found infected
+func (a *TodoAttachment) IsAvailable() bool {
+	return a.ScanStatus == nil || *a.ScanStatus == ScanClean
+}
+
+func (a *TodoAttachment) JSONAPIType() string {
+	return "attachments"
 }
@@ -1,73 +1,167 @@
 package todo
 
 import (
+	"slices"
 	"time"
 
-	"github.com/go-playground/validator/v10"
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
 	"github.com/google/uuid"
 )
 
 // -----------------------------------------------------------------------------------------
 
 type CreateTodoPayload struct {
-	Title        string     `json:"title" validate:"required,min=1,max=255"`
-	Description  *string    `json:"description" validate:"omitempty,max=1000"`
-	Priority     *Priority  `json:"priority" validate:"omitempty,oneof=low medium high"`
+	Title       string  `json:"title" validate:"required,text,textmin=1,textmax=255"`
+	Description *string `json:"description" validate:"omitempty,max=1000"`
+	// Status and Priority are keys of the scales of the workspace, new todos are drafts at the
+	// middle priority unless they say otherwise
+	Status       *Status    `json:"status" validate:"omitempty,min=1,max=40"`
+	Priority     *Priority  `json:"priority" validate:"omitempty,min=1,max=40"`
 	DueDate      *time.Time `json:"dueDate"`
 	ParentTodoID *uuid.UUID `json:"parentTodoId" validate:"omitempty,uuid"`
 	CategoryID   *uuid.UUID `json:"categoryId" validate:"omitempty,uuid"`
 	Metadata     *Metadata  `json:"metadata"`
+	// CheckDuplicates reports open todos that look like this one with the response, the todo
+	// is created either way
+	CheckDuplicates bool `json:"checkDuplicates"`
 }
 
 func (p *CreateTodoPayload) Validate() error {
-	validate := validator.New()
-	return validate.Struct(p)
+	return validation.Struct(p)
 }
 
 // -----------------------------------------------------------------------------------------
 
 type UpdateTodoPayload struct {
-	ID           uuid.UUID  `param:"id" validate:"required,uuid"`
-	Title        *string    `json:"title" validate:"omitempty,min=1,max=255"`
-	Description  *string    `json:"description" validate:"omitempty,max=1000"`
-	Status       *Status    `json:"status" validate:"omitempty,oneof=draft active completed archived"`
-	Priority     *Priority  `json:"priority" validate:"omitempty,oneof=low medium high"`
+	ID          uuid.UUID `param:"id" validate:"required,uuid"`
+	Title       *string   `json:"title" validate:"omitempty,text,textmin=1,textmax=255"`
+	Description *string   `json:"description" validate:"omitempty,max=1000"`
+	// Status and Priority are keys of the scales of the workspace of the todo, a status of
+	// another category completes, reopens or archives it
+	Status       *Status    `json:"status" validate:"omitempty,min=1,max=40"`
+	Priority     *Priority  `json:"priority" validate:"omitempty,min=1,max=40"`
 	DueDate      *time.Time `json:"dueDate"`
 	ParentTodoID *uuid.UUID `json:"parentTodoId" validate:"omitempty,uuid"`
 	CategoryID   *uuid.UUID `json:"categoryId" validate:"omitempty,uuid"`
 	Metadata     *Metadata  `json:"metadata"`
+	Version      *int       `json:"version" validate:"omitempty,min=1"`
+	// Clear lists the fields set back to null, by JSON name. The pointer fields can't tell a
+	// null from a field left out, sync writes the nulls clients push this way.
+	Clear []string `json:"-"`
+}
+
+// ClearableFields are the nullable todo fields an update can clear, by JSON name with their column
+var ClearableFields = map[string]string{
+	"dueDate":      "due_date",
+	"parentTodoId": "parent_todo_id",
+	"categoryId":   "category_id",
+	"metadata":     "metadata",
+}
+
+// Clears tells whether the update sets the field back to null
+func (p *UpdateTodoPayload) Clears(field string) bool {
+	return slices.Contains(p.Clear, field)
 }
 
 func (p *UpdateTodoPayload) Validate() error {
-	validate := validator.New()
-	return validate.Struct(p)
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	set := map[string]bool{
+		"dueDate":      p.DueDate != nil,
+		"parentTodoId": p.ParentTodoID != nil,
+		"categoryId":   p.CategoryID != nil,
+		"metadata":     p.Metadata != nil,
+	}
+	var fieldErrors validation.CustomValidationErrors
+	for _, field := range p.Clear {
+		switch _, ok := ClearableFields[field]; {
+		case !ok:
+			fieldErrors = append(fieldErrors, validation.CustomValidationError{
+				Field:   field,
+				Code:    errs.FieldCodeInvalidValue,
+				Message: "must not be null",
+			})
+		case set[field]:
+			fieldErrors = append(fieldErrors, validation.CustomValidationError{
+				Field:   field,
+				Code:    errs.FieldCodeInvalidValue,
+				Message: "can't be set and cleared at once",
+			})
+		}
+	}
+	if len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+
+	if p.ParentTodoID != nil && *p.ParentTodoID == p.ID {
+		return validation.CustomValidationErrors{{
+			Field:   "parentTodoId",
+			Code:    errs.FieldCodeInvalidReference,
+			Message: "todo cannot be its own parent",
+		}}
+	}
+
+	return nil
+}
+
+// ValidateAgainst applies the rules that depend on the status the todo ends up in, which
+// depends on the scale of its workspace: a due date moved into the past is only allowed on
+// drafts. category is the category of the status after the update.
+func (p *UpdateTodoPayload) ValidateAgainst(category Status) error {
+	if p.DueDate == nil {
+		return nil
+	}
+
+	if category != StatusDraft && validation.IsPast(*p.DueDate) {
+		return validation.CustomValidationErrors{{
+			Field:   "dueDate",
+			Code:    errs.FieldCodeDateInPast,
+			Message: "must not be in the past unless status is draft",
+		}}
+	}
+
+	return nil
 }
 
 // -----------------------------------------------------------------------------------------
 
+// SortRelevance orders searched todos by their combined full text and similarity score
+const SortRelevance = "relevance"
+
 type GetTodosQuery struct {
-	Page         *int       `query:"page" validate:"omitempty,min=1"`
-	Limit        *int       `query:"limit" validate:"omitempty,min=1,max=100"`
-	Sort         *string    `query:"sort" validate:"omitempty,oneof=created_at updated_at title priority status due_date"`
-	Order        *string    `query:"order" validate:"omitempty,oneof=asc desc"`
-	Search       *string    `query:"search" validate:"omitempty,min=1,max=255"`
-	Status       *Status    `query:"status" validate:"omitempty,oneof=draft active completed archived"`
-	Priority     *Priority  `query:"priority" validate:"omitempty,oneof=low medium high"`
+	Page   *int    `query:"page" validate:"omitempty,min=1"`
+	Limit  *int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Sort   *string `query:"sort" validate:"omitempty,oneof=created_at updated_at title priority status due_date relevance"`
+	Order  *string `query:"order" validate:"omitempty,oneof=asc desc"`
+	Search *string `query:"search" validate:"omitempty,text,textmin=1,textmax=255"`
+	// Status and Priority keep the todos on any of the comma separated keys of the scales of
+	// their workspace, a built-in status any status of its category. Completed keeps the todos
+	// in a status counting as done or in none.
+	Status       *string    `query:"status" validate:"omitempty,max=500"`
+	Priority     *string    `query:"priority" validate:"omitempty,max=500"`
 	CategoryID   *uuid.UUID `query:"categoryId" validate:"omitempty,uuid"`
 	ParentTodoID *uuid.UUID `query:"parentTodoId" validate:"omitempty,uuid"`
 	DueFrom      *time.Time `query:"dueFrom"`
 	DueTo        *time.Time `query:"dueTo"`
 	Overdue      *bool      `query:"overdue"`
 	Completed    *bool      `query:"completed"`
+	// Unread keeps the todos changed or commented on since the user last viewed them
+	Unread *bool `query:"unread"`
+	ShapeQuery
 }
 
 func (q *GetTodosQuery) Validate() error {
-	validate := validator.New()
-
-	if err := validate.Struct(q); err != nil {
+	if err := validation.Struct(q); err != nil {
 		return err
 	}
 
+	if fieldErrors := q.validateShape(); len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+
 	// Set defaults for pagination
 	if q.Page == nil {
 		defaultPage := 1
@@ -79,8 +173,12 @@ func (q *GetTodosQuery) Validate() error {
 		q.Limit = &defaultLimit
 	}
 
+	// Searches rank the best matches first unless another order is requested
 	if q.Sort == nil {
 		defaultSort := "created_at"
+		if q.Search != nil {
+			defaultSort = SortRelevance
+		}
 		q.Sort = &defaultSort
 	}
 
@@ -92,15 +190,139 @@ func (q *GetTodosQuery) Validate() error {
 	return nil
 }
 
+// Statuses are the status keys filtered on, nil when the filter isn't set
+func (q *GetTodosQuery) Statuses() []string {
+	return splitList(q.Status)
+}
+
+// Priorities are the priority keys filtered on, nil when the filter isn't set
+func (q *GetTodosQuery) Priorities() []string {
+	return splitList(q.Priority)
+}
+
+// -----------------------------------------------------------------------------------------
+
+// Export formats
+const (
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatCSV    = "csv"
+)
+
+type ExportTodosQuery struct {
+	Format *string `query:"format" validate:"omitempty,oneof=ndjson csv"`
+	Search *string `query:"search" validate:"omitempty,text,textmin=1,textmax=255"`
+	// Status and Priority keep the todos on any of the comma separated keys, like GetTodosQuery
+	Status     *string    `query:"status" validate:"omitempty,max=500"`
+	Priority   *string    `query:"priority" validate:"omitempty,max=500"`
+	CategoryID *uuid.UUID `query:"categoryId" validate:"omitempty,uuid"`
+}
+
+func (q *ExportTodosQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if q.Format == nil {
+		defaultFormat := ExportFormatNDJSON
+		q.Format = &defaultFormat
+	}
+
+	return nil
+}
+
+// Statuses are the status keys filtered on, nil when the filter isn't set
+func (q *ExportTodosQuery) Statuses() []string {
+	return splitList(q.Status)
+}
+
+// Priorities are the priority keys filtered on, nil when the filter isn't set
+func (q *ExportTodosQuery) Priorities() []string {
+	return splitList(q.Priority)
+}
+
+// -----------------------------------------------------------------------------------------
+
+// ExportTodosPDFQuery filters the todos printed to a PDF, dates are printed in Timezone
+type ExportTodosPDFQuery struct {
+	Search     *string    `query:"search" json:"search,omitempty" validate:"omitempty,text,textmin=1,textmax=255"`
+	Status     *string    `query:"status" json:"status,omitempty" validate:"omitempty,max=500"`
+	Priority   *string    `query:"priority" json:"priority,omitempty" validate:"omitempty,max=500"`
+	CategoryID *uuid.UUID `query:"categoryId" json:"categoryId,omitempty" validate:"omitempty,uuid"`
+	Timezone   *string    `query:"tz" json:"tz,omitempty" validate:"omitempty,timezone"`
+}
+
+func (q *ExportTodosPDFQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if q.Timezone == nil {
+		defaultTimezone := "UTC"
+		q.Timezone = &defaultTimezone
+	}
+
+	return nil
+}
+
+// Filters is the query the todos to print are streamed with
+func (q *ExportTodosPDFQuery) Filters() *ExportTodosQuery {
+	return &ExportTodosQuery{
+		Search:     q.Search,
+		Status:     q.Status,
+		Priority:   q.Priority,
+		CategoryID: q.CategoryID,
+	}
+}
+
+// Location is the time zone of the query, UTC when it can't be loaded
+func (q *ExportTodosPDFQuery) Location() *time.Location {
+	return loadLocation(q.Timezone)
+}
+
+// -----------------------------------------------------------------------------------------
+
+type ExportTodoPDFPayload struct {
+	ID       uuid.UUID `param:"id" validate:"required,uuid"`
+	Timezone *string   `query:"tz" validate:"omitempty,timezone"`
+}
+
+func (p *ExportTodoPDFPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// Location is the time zone dates are printed in, UTC unless asked otherwise
+func (p *ExportTodoPDFPayload) Location() *time.Location {
+	return loadLocation(p.Timezone)
+}
+
+func loadLocation(name *string) *time.Location {
+	if name == nil {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(*name)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
 // -----------------------------------------------------------------------------------------
 
 type GetTodoByIDPayload struct {
 	ID uuid.UUID `param:"id" validate:"required,uuid"`
+	ShapeQuery
 }
 
 func (p *GetTodoByIDPayload) Validate() error {
-	validate := validator.New()
-	return validate.Struct(p)
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if fieldErrors := p.validateShape(); len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+
+	return nil
 }
 
 // -----------------------------------------------------------------------------------------
@@ -110,8 +332,107 @@ type DeleteTodoPayload struct {
 }
 
 func (p *DeleteTodoPayload) Validate() error {
-	validate := validator.New()
-	return validate.Struct(p)
+	return validation.Struct(p)
+}
+
+// -----------------------------------------------------------------------------------------
+
+type CompleteTodosPayload struct {
+	IDs []uuid.UUID `json:"ids" validate:"required,min=1,max=50,unique,dive,required"`
+}
+
+func (p *CompleteTodosPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// -----------------------------------------------------------------------------------------
+
+type UncompleteTodoPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *UncompleteTodoPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// -----------------------------------------------------------------------------------------
+
+type MarkTodoReadPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *MarkTodoReadPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// -----------------------------------------------------------------------------------------
+// Todo Version DTOs
+// -----------------------------------------------------------------------------------------
+
+type GetTodoVersionsPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *GetTodoVersionsPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// -----------------------------------------------------------------------------------------
+
+type SuggestSubtasksPayload struct {
+	ID    uuid.UUID `param:"id" validate:"required,uuid"`
+	Limit *int      `json:"limit" validate:"omitempty,min=1,max=10"`
+}
+
+func (p *SuggestSubtasksPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if p.Limit == nil {
+		defaultLimit := 5
+		p.Limit = &defaultLimit
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------------------
+
+type MergeTodosPayload struct {
+	ID        uuid.UUID   `param:"id" validate:"required,uuid"`
+	SourceIDs []uuid.UUID `json:"sourceIds" validate:"required,min=1,max=20,dive,required"`
+}
+
+func (p *MergeTodosPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	seen := map[uuid.UUID]bool{p.ID: true}
+	for _, sourceID := range p.SourceIDs {
+		if seen[sourceID] {
+			return validation.CustomValidationErrors{{
+				Field:   "sourceIds",
+				Code:    errs.FieldCodeInvalidReference,
+				Message: "source todos must be distinct and differ from the todo they're merged into",
+			}}
+		}
+		seen[sourceID] = true
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------------------
+
+type RestoreTodoVersionPayload struct {
+	ID      uuid.UUID `param:"id" validate:"required,uuid"`
+	Version int       `param:"version" validate:"required,min=1"`
+}
+
+func (p *RestoreTodoVersionPayload) Validate() error {
+	return validation.Struct(p)
 }
 
 // -----------------------------------------------------------------------------------------
@@ -133,8 +454,7 @@ type UploadTodoAttachmentPayload struct {
 }
 
 func (p *UploadTodoAttachmentPayload) Validate() error {
-	validate := validator.New()
-	return validate.Struct(p)
+	return validation.Struct(p)
 }
 
 // -----------------------------------------------------------------------------------------
@@ -145,8 +465,7 @@ type DeleteTodoAttachmentPayload struct {
 }
 
 func (p *DeleteTodoAttachmentPayload) Validate() error {
-	validate := validator.New()
-	return validate.Struct(p)
+	return validation.Struct(p)
 }
 
 // -----------------------------------------------------------------------------------------
@@ -157,6 +476,16 @@ type GetAttachmentPresignedURLPayload struct {
 }
 
 func (p *GetAttachmentPresignedURLPayload) Validate() error {
-	validate := validator.New()
-	return validate.Struct(p)
+	return validation.Struct(p)
+}
+
+// -----------------------------------------------------------------------------------------
+
+type GetAttachmentThumbnailURLPayload struct {
+	TodoID       uuid.UUID `param:"id" validate:"required,uuid"`
+	AttachmentID uuid.UUID `param:"attachmentId" validate:"required,uuid"`
+}
+
+func (p *GetAttachmentThumbnailURLPayload) Validate() error {
+	return validation.Struct(p)
 }
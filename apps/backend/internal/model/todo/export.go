@@ -0,0 +1,54 @@
+package todo
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CSVHeader lists the columns of a CSV export, in the order of CSVRecord
+var CSVHeader = []string{
+	"id", "title", "description", "status", "priority", "dueDate", "completedAt",
+	"parentTodoId", "categoryId", "tags", "sortOrder", "version", "createdAt", "updatedAt",
+}
+
+// CSVRecord flattens a todo into one CSV row, tags are joined with ";"
+func (t *Todo) CSVRecord() []string {
+	var tags string
+	if t.Metadata != nil {
+		tags = strings.Join(t.Metadata.Tags, ";")
+	}
+
+	return []string{
+		t.ID.String(),
+		t.Title,
+		t.Description,
+		string(t.Status),
+		string(t.Priority),
+		csvTime(t.DueDate),
+		csvTime(t.CompletedAt),
+		csvUUID(t.ParentTodoID),
+		csvUUID(t.CategoryID),
+		tags,
+		strconv.Itoa(t.SortOrder),
+		strconv.Itoa(t.Version),
+		t.CreatedAt.Format(time.RFC3339),
+		t.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func csvUUID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
@@ -0,0 +1,206 @@
+package todo
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+)
+
+// Expandable relations of a todo
+const (
+	ExpandCategory      = "category"
+	ExpandSubtasks      = "subtasks"
+	ExpandComments      = "comments"
+	ExpandLatestComment = "comments.latest"
+	ExpandAttachments   = "attachments"
+)
+
+// expandKeys maps an expansion to the JSON key it populates
+var expandKeys = map[string]string{
+	ExpandCategory:      "category",
+	ExpandSubtasks:      "children",
+	ExpandComments:      "comments",
+	ExpandLatestComment: "comments",
+	ExpandAttachments:   "attachments",
+}
+
+// SparseFields are the todo fields clients can select with ?fields=
+var SparseFields = map[string]bool{
+	"id":           true,
+	"createdAt":    true,
+	"updatedAt":    true,
+	"userId":       true,
+	"title":        true,
+	"description":  true,
+	"priority":     true,
+	"status":       true,
+	"dueDate":      true,
+	"completedAt":  true,
+	"parentTodoId": true,
+	"categoryId":   true,
+	"metadata":     true,
+	"sortOrder":    true,
+	"version":      true,
+
+	"statusCategory": true,
+	"priorityLevel":  true,
+
+	"subtaskCount":          true,
+	"completedSubtaskCount": true,
+	"commentCount":          true,
+	"unreadCommentCount":    true,
+	"commentsReadAt":        true,
+	"suggestedReminders":    true,
+	"linkPreviews":          true,
+	"cover":                 true,
+	"viewedAt":              true,
+	"unread":                true,
+}
+
+type Expansion struct {
+	Category      bool
+	Subtasks      bool
+	Comments      bool
+	LatestComment bool
+	Attachments   bool
+}
+
+// ShapeQuery holds the sparse fieldset and expansion parameters shared by todo read endpoints
+type ShapeQuery struct {
+	Fields *string `query:"fields" validate:"omitempty,max=500"`
+	Expand *string `query:"expand" validate:"omitempty,max=200"`
+}
+
+func (q *ShapeQuery) validateShape() validation.CustomValidationErrors {
+	var fieldErrors validation.CustomValidationErrors
+
+	for _, field := range splitList(q.Fields) {
+		if !SparseFields[field] {
+			fieldErrors = append(fieldErrors, validation.CustomValidationError{
+				Field:   "fields",
+				Code:    errs.FieldCodeInvalidChoice,
+				Message: "unknown field: " + field,
+			})
+		}
+	}
+
+	for _, expansion := range splitList(q.Expand) {
+		if _, ok := expandKeys[expansion]; !ok {
+			fieldErrors = append(fieldErrors, validation.CustomValidationError{
+				Field:   "expand",
+				Code:    errs.FieldCodeInvalidChoice,
+				Message: "unknown expansion: " + expansion,
+			})
+		}
+	}
+
+	return fieldErrors
+}
+
+// IsShaped reports whether the client asked for anything but the default representation
+func (q *ShapeQuery) IsShaped() bool {
+	return q.Fields != nil || q.Expand != nil
+}
+
+// Expansion returns the requested relations, nil when expand wasn't given
+func (q *ShapeQuery) Expansion() *Expansion {
+	if q.Expand == nil {
+		return nil
+	}
+
+	expansion := &Expansion{}
+	for _, value := range splitList(q.Expand) {
+		switch value {
+		case ExpandCategory:
+			expansion.Category = true
+		case ExpandSubtasks:
+			expansion.Subtasks = true
+		case ExpandComments:
+			expansion.Comments = true
+		case ExpandLatestComment:
+			expansion.LatestComment = true
+		case ExpandAttachments:
+			expansion.Attachments = true
+		}
+	}
+
+	return expansion
+}
+
+// Projection returns the JSON keys a shaped todo should be serialized with
+func (q *ShapeQuery) Projection() map[string]bool {
+	keys := map[string]bool{}
+
+	if q.Fields != nil {
+		keys["id"] = true
+		for _, field := range splitList(q.Fields) {
+			keys[field] = true
+		}
+	} else {
+		for field := range SparseFields {
+			keys[field] = true
+		}
+	}
+
+	// Links are navigation rather than data, every shape keeps them
+	keys["_links"] = true
+
+	if q.Expand != nil {
+		for _, expansion := range splitList(q.Expand) {
+			keys[expandKeys[expansion]] = true
+		}
+	} else {
+		// Without expand the populated relations are part of the default representation
+		for _, key := range expandKeys {
+			keys[key] = true
+		}
+	}
+
+	return keys
+}
+
+func splitList(value *string) []string {
+	if value == nil {
+		return nil
+	}
+
+	var items []string
+	for _, item := range strings.Split(*value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// -----------------------------------------------------------------------------------------
+
+// Project limits the keys the todo is serialized with
+func (t *PopulatedTodo) Project(keys map[string]bool) {
+	t.projection = keys
+}
+
+func (t PopulatedTodo) MarshalJSON() ([]byte, error) {
+	type populatedTodo PopulatedTodo
+
+	encoded, err := json.Marshal(populatedTodo(t))
+	if err != nil || t.projection == nil {
+		return encoded, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &all); err != nil {
+		return nil, err
+	}
+
+	projected := make(map[string]json.RawMessage, len(t.projection))
+	for key := range t.projection {
+		if value, ok := all[key]; ok {
+			projected[key] = value
+		}
+	}
+
+	return json.Marshal(projected)
+}
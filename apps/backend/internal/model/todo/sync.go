@@ -0,0 +1,98 @@
+package todo
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+// SyncableFields are the todo fields offline clients are allowed to push
+var SyncableFields = map[string]bool{
+	"title":       true,
+	"description": true,
+	"status":      true,
+	"priority":    true,
+	"dueDate":     true,
+	"categoryId":  true,
+	"metadata":    true,
+}
+
+type SyncStatus string
+
+const (
+	SyncStatusApplied   SyncStatus = "applied"
+	SyncStatusMerged    SyncStatus = "merged"
+	SyncStatusRejected  SyncStatus = "rejected"
+	SyncStatusUnchanged SyncStatus = "unchanged"
+	SyncStatusMissing   SyncStatus = "missing"
+)
+
+// -----------------------------------------------------------------------------------------
+
+type SyncChange struct {
+	TodoID      uuid.UUID                  `json:"todoId" validate:"required,uuid"`
+	BaseVersion int                        `json:"baseVersion" validate:"required,min=1"`
+	Base        map[string]json.RawMessage `json:"base"`
+	Fields      map[string]json.RawMessage `json:"fields" validate:"required,min=1"`
+}
+
+type SyncTodosPayload struct {
+	Since    *time.Time   `json:"since"`
+	Strategy *string      `json:"strategy" validate:"omitempty,oneof=server_wins client_wins field_merge"`
+	Changes  []SyncChange `json:"changes" validate:"omitempty,max=100,dive"`
+}
+
+func (p *SyncTodosPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	var fieldErrors validation.CustomValidationErrors
+	for i, change := range p.Changes {
+		for field := range change.Fields {
+			if !SyncableFields[field] {
+				fieldErrors = append(fieldErrors, validation.CustomValidationError{
+					Field:   fmt.Sprintf("changes[%d].fields.%s", i, field),
+					Code:    errs.FieldCodeUnknownField,
+					Message: "is not a syncable field",
+				})
+				continue
+			}
+
+			if _, ok := change.Base[field]; !ok {
+				fieldErrors = append(fieldErrors, validation.CustomValidationError{
+					Field:   fmt.Sprintf("changes[%d].base.%s", i, field),
+					Code:    errs.FieldCodeRequired,
+					Message: "is required for every changed field",
+				})
+			}
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+
+	return nil
+}
+
+// -----------------------------------------------------------------------------------------
+
+type SyncChangeResult struct {
+	TodoID    uuid.UUID  `json:"todoId"`
+	Status    SyncStatus `json:"status"`
+	Conflicts []string   `json:"conflicts"`
+	Discarded []string   `json:"discarded"`
+	Todo      *Todo      `json:"todo"`
+}
+
+type SyncResponse struct {
+	Strategy   string             `json:"strategy"`
+	Results    []SyncChangeResult `json:"results"`
+	Changed    []Todo             `json:"changed"`
+	ServerTime time.Time          `json:"serverTime"`
+}
@@ -1,15 +1,20 @@
 package todo
 
 import (
+	"strconv"
 	"time"
 
+	"github.com/Sameer16536/ExecuTask/internal/lib/etag"
 	"github.com/Sameer16536/ExecuTask/internal/model"
 	"github.com/Sameer16536/ExecuTask/internal/model/category"
 	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/cover"
+	"github.com/Sameer16536/ExecuTask/internal/model/preview"
 	"github.com/google/uuid"
 )
 
-// Enum --> provides a way to define a set of named constants [type safety]
+// Status is a key of the status scale of the workspace of a todo. The built-in statuses are
+// the keys of the default scale and the categories every status belongs to.
 type Status string
 
 const (
@@ -19,6 +24,8 @@ const (
 	StatusArchived  Status = "archived"
 )
 
+// Priority is a key of the priority scale of the workspace of a todo, the built-in priorities
+// are the keys of the default scale
 type Priority string
 
 const (
@@ -31,6 +38,7 @@ const (
 type Todo struct {
 	model.Base
 	UserID       string     `json:"userId" db:"user_id"`
+	WorkspaceID  *string    `json:"workspaceId" db:"workspace_id"`
 	Title        string     `json:"title" db:"title"`
 	Description  string     `json:"description" db:"description"`
 	Priority     Priority   `json:"priority" db:"priority"`
@@ -41,14 +49,28 @@ type Todo struct {
 	CategoryID   *uuid.UUID `json:"categoryId" db:"category_id"`
 	Metadata     *Metadata  `json:"metadata" db:"metadata"`
 	SortOrder    int        `json:"sortOrder" db:"sort_order"`
+	Version      int        `json:"version" db:"version"`
+	// The category of the status and the level of the priority on the scales of the workspace,
+	// maintained by database triggers. Whether a todo is open or done is read from the category.
+	StatusCategory Status `json:"statusCategory" db:"status_category"`
+	PriorityLevel  int    `json:"priorityLevel" db:"priority_level"`
+
+	// Denormalized counters, maintained by database triggers
+	SubtaskCount          int        `json:"subtaskCount" db:"subtask_count"`
+	CompletedSubtaskCount int        `json:"completedSubtaskCount" db:"completed_subtask_count"`
+	CommentCount          int        `json:"commentCount" db:"comment_count"`
+	UnreadCommentCount    int        `json:"unreadCommentCount" db:"unread_comment_count"`
+	CommentsReadAt        *time.Time `json:"commentsReadAt" db:"comments_read_at"`
 }
 
 // Embedded struct -->
 type Metadata struct {
-	Tags       []string `json:"tags"`
+	Tags       []string `json:"tags" validate:"omitempty,dive,text,textmin=1,textmax=50"`
 	Reminder   *string  `json:"reminder"`
 	Color      *string  `json:"color"`
 	Difficulty *string  `json:"difficulty"`
+	// EstimatedMinutes is how long the user expects the todo to take, the planner sums it per day
+	EstimatedMinutes *int `json:"estimatedMinutes" validate:"omitempty,min=1,max=1440"`
 }
 
 type PopulatedTodo struct {
@@ -57,6 +79,107 @@ type PopulatedTodo struct {
 	Children    []Todo             `json:"children" db:"children"`
 	Comments    []comment.Comment  `json:"comments" db:"comments"`
 	Attachments []TodoAttachment   `json:"attachments" db:"attachments"`
+	// SuggestedReminders are only worked out when a single open todo is read
+	SuggestedReminders []ReminderSuggestion `json:"suggestedReminders,omitempty" db:"-"`
+	// LinkPreviews are the cards of the links in the description, attached like the reminders
+	LinkPreviews []preview.Preview `json:"linkPreviews,omitempty" db:"-"`
+	// Cover is only attached when a single todo is read, its variant links expire
+	Cover *cover.Cover `json:"cover,omitempty" db:"-"`
+	// ViewedAt is when the reader last looked at the todo, before the read it comes with
+	ViewedAt *time.Time `json:"viewedAt" db:"-"`
+	// Unread tells the todo changed or got comments since the reader last looked at it
+	Unread bool `json:"unread" db:"-"`
+
+	projection map[string]bool
+}
+
+// CreatedTodo is the todo as created, with suggestions for what the user left unset
+type CreatedTodo struct {
+	Todo
+	Suggestions *Suggestions `json:"suggestions,omitempty"`
+	// Duplicates are only looked for when the payload asks to
+	Duplicates []DuplicateCandidate `json:"duplicates,omitempty"`
+}
+
+// DuplicateCandidate is an open todo of the same category with a similar title
+type DuplicateCandidate struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	Title      string     `json:"title" db:"title"`
+	Status     Status     `json:"status" db:"status"`
+	CategoryID *uuid.UUID `json:"categoryId" db:"category_id"`
+	Similarity float64    `json:"similarity" db:"similarity"`
+}
+
+// Suggestions are learned from the earlier todos of the user, nothing is applied until the user
+// updates the todo with them
+type Suggestions struct {
+	Category *CategorySuggestion `json:"category"`
+	Priority *PrioritySuggestion `json:"priority"`
+	Tags     []TagSuggestion     `json:"tags"`
+}
+
+type CategorySuggestion struct {
+	CategoryID uuid.UUID `json:"categoryId"`
+	Confidence float64   `json:"confidence"`
+}
+
+type PrioritySuggestion struct {
+	Priority   Priority `json:"priority"`
+	Confidence float64  `json:"confidence"`
+}
+
+type TagSuggestion struct {
+	Tag        string  `json:"tag"`
+	Confidence float64 `json:"confidence"`
+}
+
+// ReminderBasis tells which of the earlier completions a reminder suggestion was learned from
+type ReminderBasis string
+
+const (
+	ReminderBasisCategory ReminderBasis = "category"
+	ReminderBasisTags     ReminderBasis = "tags"
+	ReminderBasisHistory  ReminderBasis = "history"
+)
+
+// ReminderSuggestion is a time to be reminded of the todo, an hour ahead of when the user usually
+// completes similar todos. Nothing is set until the user updates the todo's reminder with it.
+type ReminderSuggestion struct {
+	RemindAt   time.Time     `json:"remindAt"`
+	Basis      ReminderBasis `json:"basis"`
+	Confidence float64       `json:"confidence"`
+	// SampleSize is how many completions the suggestion was learned from
+	SampleSize int `json:"sampleSize"`
+}
+
+// SubtaskSuggestion is a proposed subtask, it becomes one once the user creates it with the
+// todo as its parent
+type SubtaskSuggestion struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type SubtaskSuggestions struct {
+	TodoID      uuid.UUID           `json:"todoId"`
+	Provider    string              `json:"provider"`
+	Model       string              `json:"model"`
+	Suggestions []SubtaskSuggestion `json:"suggestions"`
+}
+
+// BulkCompletion is the outcome of completing several todos in one request
+type BulkCompletion struct {
+	Completed []Todo `json:"completed"`
+	// AlreadyCompleted todos were left as they were
+	AlreadyCompleted []uuid.UUID `json:"alreadyCompleted"`
+	// Blocked todos were completed while todos they depend on are still open
+	Blocked []BlockedTodo `json:"blocked"`
+}
+
+type BlockedTodo struct {
+	TodoID uuid.UUID `json:"todoId"`
+	Title  string    `json:"title"`
+	// BlockedBy are the open todos it depends on, those completed along with it aren't counted
+	BlockedBy []uuid.UUID `json:"blockedBy"`
 }
 
 type TodoStats struct {
@@ -77,9 +200,69 @@ type UserWeeklyStats struct {
 }
 
 func (t *Todo) IsOverdue() bool {
-	return t.DueDate != nil && t.DueDate.Before(time.Now()) && t.Status != StatusCompleted
+	return t.DueDate != nil && t.DueDate.Before(time.Now()) && t.StatusCategory != StatusCompleted
+}
+
+// IsUnread reports whether the todo changed or got unread comments since it was viewed, a todo
+// never viewed is unread
+func (t *Todo) IsUnread(viewedAt *time.Time) bool {
+	return viewedAt == nil || t.UpdatedAt.After(*viewedAt) || t.UnreadCommentCount > 0
 }
 
 func (t *Todo) CanHaveChildren() bool {
 	return t.ParentTodoID == nil
 }
+
+// counterParts tag the counters, they change without bumping the todo version
+func (t *Todo) counterParts() []string {
+	return []string{
+		strconv.Itoa(t.SubtaskCount), strconv.Itoa(t.CompletedSubtaskCount),
+		strconv.Itoa(t.CommentCount), strconv.Itoa(t.UnreadCommentCount),
+	}
+}
+
+// ETag covers the embedded relations too, since they change without bumping the todo version
+func (t *PopulatedTodo) ETag() string {
+	// The view state is left out, reading the todo changes it and If-Match must still hold after
+	parts := []string{"todo", t.ID.String(), strconv.Itoa(t.Version)}
+	parts = append(parts, t.counterParts()...)
+
+	if t.Category != nil {
+		parts = append(parts, "category", t.Category.ID.String(), strconv.Itoa(t.Category.Version),
+			strconv.Itoa(t.Category.OpenTodoCount))
+	}
+	for _, child := range t.Children {
+		parts = append(parts, "child", child.ID.String(), strconv.Itoa(child.Version))
+		parts = append(parts, child.counterParts()...)
+	}
+	for _, c := range t.Comments {
+		parts = append(parts, "comment", c.ID.String(), strconv.FormatInt(c.UpdatedAt.UnixNano(), 10))
+		parts = append(parts, previewParts(c.LinkPreviews)...)
+	}
+	for _, attachment := range t.Attachments {
+		parts = append(parts, "attachment", attachment.ID.String())
+	}
+	for _, reminder := range t.SuggestedReminders {
+		parts = append(parts, "reminder", strconv.FormatInt(reminder.RemindAt.Unix(), 10), string(reminder.Basis))
+	}
+	parts = append(parts, previewParts(t.LinkPreviews)...)
+	// The variant links are signed anew on every read, the generation stands in for them
+	if t.Cover != nil {
+		parts = append(parts, "cover", t.Cover.Generation.String(), string(t.Cover.Status))
+	}
+
+	return etag.Strong(parts...)
+}
+
+// previewParts covers the link previews, which are fetched after the text linking them is saved
+func previewParts(previews []preview.Preview) []string {
+	parts := []string{}
+	for _, card := range previews {
+		parts = append(parts, "preview", card.URL, strconv.FormatInt(card.FetchedAt.UnixNano(), 10))
+	}
+	return parts
+}
+
+func (t *Todo) JSONAPIType() string {
+	return "todos"
+}
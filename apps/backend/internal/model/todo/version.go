@@ -0,0 +1,24 @@
+package todo
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TodoVersion is a snapshot of the editable fields of a todo as they were at a version. The
+// most recent versions of every todo are kept.
+type TodoVersion struct {
+	TodoID       uuid.UUID  `json:"todoId" db:"todo_id"`
+	Version      int        `json:"version" db:"version"`
+	CreatedAt    time.Time  `json:"createdAt" db:"created_at"`
+	Title        string     `json:"title" db:"title"`
+	Description  *string    `json:"description" db:"description"`
+	Priority     Priority   `json:"priority" db:"priority"`
+	Status       Status     `json:"status" db:"status"`
+	DueDate      *time.Time `json:"dueDate" db:"due_date"`
+	CompletedAt  *time.Time `json:"completedAt" db:"completed_at"`
+	ParentTodoID *uuid.UUID `json:"parentTodoId" db:"parent_todo_id"`
+	CategoryID   *uuid.UUID `json:"categoryId" db:"category_id"`
+	Metadata     *Metadata  `json:"metadata" db:"metadata"`
+}
@@ -0,0 +1,71 @@
+package usage
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+)
+
+// maxRange caps the buckets of a response, about a week of hours or a year of days
+var maxRange = map[Granularity]time.Duration{
+	GranularityHour: 7 * 24 * time.Hour,
+	GranularityDay:  366 * 24 * time.Hour,
+}
+
+// ------------------------------------------------------------
+
+type GetUsageQuery struct {
+	// Scope selects the usage of the user or of their active workspace
+	Scope       *SubjectType `query:"scope" validate:"omitempty,oneof=user workspace"`
+	Granularity *Granularity `query:"granularity" validate:"omitempty,oneof=hour day"`
+	From        *time.Time   `query:"from"`
+	To          *time.Time   `query:"to"`
+}
+
+func (q *GetUsageQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	// Set defaults
+	if q.Scope == nil {
+		defaultScope := SubjectUser
+		q.Scope = &defaultScope
+	}
+	if q.Granularity == nil {
+		defaultGranularity := GranularityDay
+		q.Granularity = &defaultGranularity
+	}
+	if q.To == nil {
+		to := time.Now().UTC()
+		q.To = &to
+	}
+	if q.From == nil {
+		from := q.To.AddDate(0, 0, -30)
+		if *q.Granularity == GranularityHour {
+			from = q.To.Add(-24 * time.Hour)
+		}
+		q.From = &from
+	}
+
+	if q.To.Before(*q.From) {
+		return validation.CustomValidationErrors{
+			{Field: "to", Message: "must not be before from"},
+		}
+	}
+	if q.To.Sub(*q.From) > maxRange[*q.Granularity] {
+		return validation.CustomValidationErrors{
+			{Field: "from", Message: "range is too long for the granularity, use day or a shorter range"},
+		}
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetAPIUsagePayload struct{}
+
+func (p *GetAPIUsagePayload) Validate() error {
+	return nil
+}
@@ -0,0 +1,110 @@
+package usage
+
+import (
+	"time"
+)
+
+type Metric string
+
+const (
+	MetricAPICalls      Metric = "api_calls"
+	MetricNotifications Metric = "notifications"
+	// MetricStorageBytes is a gauge, the usage-rollup job snapshots it from the attachments
+	MetricStorageBytes Metric = "storage_bytes"
+)
+
+type SubjectType string
+
+const (
+	SubjectUser      SubjectType = "user"
+	SubjectWorkspace SubjectType = "workspace"
+)
+
+type Granularity string
+
+const (
+	GranularityHour Granularity = "hour"
+	GranularityDay  Granularity = "day"
+)
+
+// Record is the usage of a subject during one hour, as stored by the rollup
+type Record struct {
+	Hour        time.Time
+	SubjectType SubjectType
+	SubjectID   string
+	Metric      Metric
+	Quantity    int64
+}
+
+// Bucket is the usage during an hour or a day. Calls and notifications are summed, storage is
+// the latest snapshot of the bucket, nil when none was taken.
+type Bucket struct {
+	Start         time.Time `json:"start" db:"start"`
+	APICalls      int64     `json:"apiCalls" db:"api_calls"`
+	Notifications int64     `json:"notifications" db:"notifications"`
+	StorageBytes  *int64    `json:"storageBytes" db:"storage_bytes"`
+}
+
+type Totals struct {
+	APICalls      int64 `json:"apiCalls"`
+	Notifications int64 `json:"notifications"`
+	// StorageBytes is the latest snapshot in the range
+	StorageBytes int64 `json:"storageBytes"`
+}
+
+// Usage covers completed hours only, the current hour shows up after the next rollup
+type Usage struct {
+	SubjectType SubjectType `json:"subjectType"`
+	SubjectID   string      `json:"subjectId"`
+	From        time.Time   `json:"from"`
+	To          time.Time   `json:"to"`
+	Granularity Granularity `json:"granularity"`
+	Totals      Totals      `json:"totals"`
+	Buckets     []Bucket    `json:"buckets"`
+}
+
+// APIUsageWindow is how far back the API usage summary looks
+const APIUsageWindow = 30 * 24 * time.Hour
+
+// APIUsage summarizes the API consumption of a user over the last 30 days, from the same
+// metered hours as Usage
+type APIUsage struct {
+	From          time.Time         `json:"from"`
+	To            time.Time         `json:"to"`
+	Requests      RequestStats      `json:"requests"`
+	RateLimit     RateLimitStats    `json:"rateLimit"`
+	Notifications NotificationStats `json:"notifications"`
+}
+
+type RequestStats struct {
+	Total        int64   `json:"total"`
+	DailyAverage float64 `json:"dailyAverage"`
+	// PeakDay is nil when no calls were made in the window
+	PeakDay         *time.Time   `json:"peakDay"`
+	PeakDayRequests int64        `json:"peakDayRequests"`
+	Daily           []DailyCount `json:"daily"`
+}
+
+type DailyCount struct {
+	Day      time.Time `json:"day"`
+	Requests int64     `json:"requests"`
+}
+
+// RateLimitStats compares the busiest hour of the user with the rate limit. The limit applies
+// per client IP, so requests from several addresses can add up to more than one hour allows.
+type RateLimitStats struct {
+	RequestsPerSecond float64 `json:"requestsPerSecond"`
+	Burst             int     `json:"burst"`
+	// HourlyAllowance is the requests one client can make in an hour at the sustained rate
+	HourlyAllowance  int64      `json:"hourlyAllowance"`
+	PeakHour         *time.Time `json:"peakHour"`
+	PeakHourRequests int64      `json:"peakHourRequests"`
+	// PeakHourUtilization is the share of the hourly allowance used in the busiest hour
+	PeakHourUtilization float64 `json:"peakHourUtilization"`
+}
+
+// NotificationStats counts the notifications delivered to the user by the background jobs
+type NotificationStats struct {
+	Delivered    int64   `json:"delivered"`
+	DailyAverage float64 `json:"dailyAverage"`
+}
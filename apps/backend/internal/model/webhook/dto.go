@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"net/url"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type GetEventTypesPayload struct{}
+
+func (p *GetEventTypesPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type GetEndpointsPayload struct{}
+
+func (p *GetEndpointsPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type CreateEndpointPayload struct {
+	URL    string   `json:"url" validate:"required,url,max=2048"`
+	Events []string `json:"events" validate:"required,min=1,unique,dive,oneof=todo_created todo_updated todo_completed todo_uncompleted todo_deleted comment_added comment_updated comment_deleted"`
+	// Enabled is true when nil
+	Enabled *bool `json:"enabled"`
+}
+
+func (p *CreateEndpointPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if err := validateURL(p.URL); err != nil {
+		return err
+	}
+
+	if p.Enabled == nil {
+		enabled := true
+		p.Enabled = &enabled
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type UpdateEndpointPayload struct {
+	ID      uuid.UUID `param:"id" validate:"required,uuid"`
+	URL     *string   `json:"url" validate:"omitempty,url,max=2048"`
+	Events  []string  `json:"events" validate:"omitempty,min=1,unique,dive,oneof=todo_created todo_updated todo_completed todo_uncompleted todo_deleted comment_added comment_updated comment_deleted"`
+	Enabled *bool     `json:"enabled"`
+}
+
+func (p *UpdateEndpointPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if p.URL != nil {
+		return validateURL(*p.URL)
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type DeleteEndpointPayload struct {
+	ID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+func (p *DeleteEndpointPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+// TestEndpointPayload sends a sample payload of Event, the first event the endpoint subscribes
+// to when nil
+type TestEndpointPayload struct {
+	ID    uuid.UUID `param:"id" validate:"required,uuid"`
+	Event *string   `json:"event" validate:"omitempty,oneof=todo_created todo_updated todo_completed todo_uncompleted todo_deleted comment_added comment_updated comment_deleted"`
+}
+
+func (p *TestEndpointPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// validateURL allows http and https URLs without credentials, deliveries never carry them
+func validateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil {
+		return validation.CustomValidationErrors{{
+			Field:   "url",
+			Code:    errs.FieldCodeInvalidFormat,
+			Message: "must be an http or https URL without credentials",
+		}}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+// The event types an endpoint can subscribe to, named after the events they are delivered for
+const (
+	EventTodoCreated     = "todo_created"
+	EventTodoUpdated     = "todo_updated"
+	EventTodoCompleted   = "todo_completed"
+	EventTodoUncompleted = "todo_uncompleted"
+	EventTodoDeleted     = "todo_deleted"
+	EventCommentAdded    = "comment_added"
+	EventCommentUpdated  = "comment_updated"
+	EventCommentDeleted  = "comment_deleted"
+)
+
+// Endpoint is a URL the events of a workspace are posted to
+type Endpoint struct {
+	model.BaseWithId
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+	WorkspaceID string `json:"workspaceId" db:"workspace_id"`
+	URL         string `json:"url" db:"url"`
+	// Secret signs the deliveries, it is only returned when the endpoint is created
+	Secret    string   `json:"-" db:"secret"`
+	Events    []string `json:"events" db:"events"`
+	Enabled   bool     `json:"enabled" db:"enabled"`
+	CreatedBy string   `json:"-" db:"created_by"`
+}
+
+// Subscribes tells whether the endpoint is delivered events of the type
+func (e *Endpoint) Subscribes(event string) bool {
+	if !e.Enabled {
+		return false
+	}
+	for _, subscribed := range e.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// CreatedEndpoint is a new endpoint with the secret its deliveries are signed with, which can't
+// be shown again
+type CreatedEndpoint struct {
+	Endpoint
+	Secret string `json:"secret"`
+}
+
+// EventType is an entry of the event catalog
+type EventType struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	// Schema is the JSON Schema of the payloads delivered for the event
+	Schema map[string]interface{} `json:"schema"`
+}
+
+// Payload is the body of every delivery, Data depends on the event type
+type Payload[T any] struct {
+	// ID identifies the delivery, its retries keep it so receivers can skip those they handled
+	ID          uuid.UUID `json:"id"`
+	Type        string    `json:"type"`
+	WorkspaceID string    `json:"workspaceId"`
+	CreatedAt   time.Time `json:"createdAt"`
+	// Test is true for the sample payloads of test deliveries
+	Test bool `json:"test"`
+	Data T    `json:"data"`
+}
+
+// TodoData is delivered for the events of a todo that still exists
+type TodoData struct {
+	Todo todo.Todo `json:"todo"`
+}
+
+type TodoDeletedData struct {
+	TodoID uuid.UUID `json:"todoId"`
+}
+
+// CommentData is delivered for the events of a comment that still exists
+type CommentData struct {
+	Comment comment.Comment `json:"comment"`
+}
+
+type CommentDeletedData struct {
+	CommentID uuid.UUID `json:"commentId"`
+}
+
+// TestDelivery is how an endpoint answered a sample payload
+type TestDelivery struct {
+	DeliveryID uuid.UUID `json:"deliveryId"`
+	Event      string    `json:"event"`
+	// Success is true when the endpoint answered with a 2xx status
+	Success bool `json:"success"`
+	// StatusCode is nil when the endpoint couldn't be reached
+	StatusCode *int  `json:"statusCode"`
+	DurationMs int64 `json:"durationMs"`
+	// ResponseBody is the start of the answer of the endpoint
+	ResponseBody string `json:"responseBody"`
+	// Error tells why the endpoint couldn't be reached
+	Error *string `json:"error"`
+}
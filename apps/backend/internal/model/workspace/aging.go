@@ -0,0 +1,126 @@
+package workspace
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+// DefaultBreachTag is added to the todos breaching a policy that names no tag of its own
+const DefaultBreachTag = "sla-breach"
+
+// AgingPolicy escalates the open todos of a workspace left open longer than MaxAgeHours: they
+// are tagged and their owners notified, once per policy
+type AgingPolicy struct {
+	model.BaseWithId
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+	WorkspaceID string `json:"workspaceId" db:"workspace_id"`
+	Name        string `json:"name" db:"name"`
+	// Priority limits the policy to the todos of a priority of the scale of the workspace, it
+	// covers every open todo when nil
+	Priority    *todo.Priority `json:"priority" db:"priority"`
+	MaxAgeHours int            `json:"maxAgeHours" db:"max_age_hours"`
+	Tag         string         `json:"tag" db:"tag"`
+	Notify      bool           `json:"notify" db:"notify"`
+	CreatedBy   string         `json:"createdBy" db:"created_by"`
+}
+
+// MaxAge is how long a todo may stay open
+func (p *AgingPolicy) MaxAge() time.Duration {
+	return time.Duration(p.MaxAgeHours) * time.Hour
+}
+
+// Breaches tells whether the todo is open past the age of the policy at now
+func (p *AgingPolicy) Breaches(item *todo.Todo, now time.Time) bool {
+	if item.StatusCategory != todo.StatusDraft && item.StatusCategory != todo.StatusActive {
+		return false
+	}
+	if p.Priority != nil && item.Priority != *p.Priority {
+		return false
+	}
+	return !item.CreatedAt.Add(p.MaxAge()).After(now)
+}
+
+// Breach is an open todo left open past the age of a policy
+type Breach struct {
+	PolicyID    uuid.UUID     `json:"policyId" db:"policy_id"`
+	PolicyName  string        `json:"policyName" db:"policy_name"`
+	MaxAgeHours int           `json:"maxAgeHours" db:"max_age_hours"`
+	TodoID      uuid.UUID     `json:"todoId" db:"todo_id"`
+	TodoTitle   string        `json:"todoTitle" db:"todo_title"`
+	UserID      string        `json:"userId" db:"user_id"`
+	Priority    todo.Priority `json:"priority" db:"priority"`
+	OpenedAt    time.Time     `json:"openedAt" db:"opened_at"`
+	BreachedAt  time.Time     `json:"breachedAt" db:"breached_at"`
+	// HoursOverLimit is how long the todo has been open past the age of the policy
+	HoursOverLimit int `json:"hoursOverLimit" db:"-"`
+}
+
+// PolicyBreaches counts the open breaches of a policy, policies without any are listed too
+type PolicyBreaches struct {
+	PolicyID   uuid.UUID `json:"policyId"`
+	PolicyName string    `json:"policyName"`
+	Breaches   int       `json:"breaches"`
+}
+
+// MemberBreaches counts the open breaches of the todos a member owns
+type MemberBreaches struct {
+	UserID   string `json:"userId"`
+	Breaches int    `json:"breaches"`
+}
+
+// BreachReport lists the open todos of the workspace breaching its aging policies
+type BreachReport struct {
+	WorkspaceID string    `json:"workspaceId"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	// Breaches are ordered longest past their limit first
+	Breaches []Breach         `json:"breaches"`
+	ByPolicy []PolicyBreaches `json:"byPolicy"`
+	ByMember []MemberBreaches `json:"byMember"`
+}
+
+func NewBreachReport(workspaceID string, policies []AgingPolicy, breaches []Breach, now time.Time) *BreachReport {
+	report := &BreachReport{
+		WorkspaceID: workspaceID,
+		GeneratedAt: now,
+		Breaches:    breaches,
+		ByPolicy:    make([]PolicyBreaches, 0, len(policies)),
+		ByMember:    []MemberBreaches{},
+	}
+
+	perPolicy := map[uuid.UUID]int{}
+	perMember := map[string]int{}
+	for i := range report.Breaches {
+		breach := &report.Breaches[i]
+		over := now.Sub(breach.OpenedAt) - time.Duration(breach.MaxAgeHours)*time.Hour
+		breach.HoursOverLimit = max(int(over.Hours()), 0)
+		perPolicy[breach.PolicyID]++
+		perMember[breach.UserID]++
+	}
+
+	slices.SortStableFunc(report.Breaches, func(a, b Breach) int {
+		return cmp.Or(cmp.Compare(b.HoursOverLimit, a.HoursOverLimit), strings.Compare(a.TodoTitle, b.TodoTitle))
+	})
+
+	for _, policy := range policies {
+		report.ByPolicy = append(report.ByPolicy, PolicyBreaches{
+			PolicyID:   policy.ID,
+			PolicyName: policy.Name,
+			Breaches:   perPolicy[policy.ID],
+		})
+	}
+	for userID, count := range perMember {
+		report.ByMember = append(report.ByMember, MemberBreaches{UserID: userID, Breaches: count})
+	}
+	slices.SortFunc(report.ByMember, func(a, b MemberBreaches) int {
+		return cmp.Or(cmp.Compare(b.Breaches, a.Breaches), strings.Compare(a.UserID, b.UserID))
+	})
+
+	return report
+}
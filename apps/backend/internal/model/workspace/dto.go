@@ -0,0 +1,457 @@
+package workspace
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+
+type GetTemplatesPayload struct {
+	WorkspaceID string `param:"workspaceId" validate:"required,min=1,max=255"`
+}
+
+func (p *GetTemplatesPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type CreateTemplatePayload struct {
+	WorkspaceID string         `param:"workspaceId" validate:"required,min=1,max=255"`
+	Name        string         `json:"name" validate:"required,min=1,max=100"`
+	Title       string         `json:"title" validate:"required,text,textmin=1,textmax=255"`
+	Description *string        `json:"description" validate:"omitempty,max=1000"`
+	Priority    *todo.Priority `json:"priority" validate:"omitempty,oneof=low medium high"`
+	Subtasks    []string       `json:"subtasks" validate:"omitempty,max=50,dive,min=1,max=255"`
+	DueInDays   *int           `json:"dueInDays" validate:"omitempty,min=0,max=3650"`
+}
+
+func (p *CreateTemplatePayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if p.Subtasks == nil {
+		p.Subtasks = []string{}
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type DeleteTemplatePayload struct {
+	WorkspaceID string    `param:"workspaceId" validate:"required,min=1,max=255"`
+	TemplateID  uuid.UUID `param:"templateId" validate:"required,uuid"`
+}
+
+func (p *DeleteTemplatePayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetOnboardingKitPayload struct {
+	WorkspaceID string `param:"workspaceId" validate:"required,min=1,max=255"`
+}
+
+func (p *GetOnboardingKitPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type SetOnboardingKitPayload struct {
+	WorkspaceID string        `param:"workspaceId" validate:"required,min=1,max=255"`
+	Categories  []KitCategory `json:"categories" validate:"omitempty,max=50,unique=Name,dive"`
+	Todos       []KitTodo     `json:"todos" validate:"omitempty,max=100,dive"`
+}
+
+func (p *SetOnboardingKitPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if len(p.Categories) == 0 && len(p.Todos) == 0 {
+		return validation.NewFieldError("categories", errs.FieldCodeRequired, "the kit needs categories or todos")
+	}
+
+	for i, item := range p.Todos {
+		if item.CategoryName == nil {
+			continue
+		}
+		if !slices.ContainsFunc(p.Categories, func(c KitCategory) bool { return c.Name == *item.CategoryName }) {
+			return validation.NewFieldError(fmt.Sprintf("todos[%d].categoryName", i), errs.FieldCodeInvalidReference,
+				"must name a category of the kit")
+		}
+	}
+
+	if p.Categories == nil {
+		p.Categories = []KitCategory{}
+	}
+	if p.Todos == nil {
+		p.Todos = []KitTodo{}
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type DeleteOnboardingKitPayload struct {
+	WorkspaceID string `param:"workspaceId" validate:"required,min=1,max=255"`
+}
+
+func (p *DeleteOnboardingKitPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+// SetWorkflowPayload replaces the scales of the workspace. The mappings move the todos off
+// keys renamed or removed, old key to new; the todos left on a status the workflow no longer
+// has land in the first status of its category, those on a priority it no longer has stay at
+// their level.
+type SetWorkflowPayload struct {
+	WorkspaceID     string             `param:"workspaceId" validate:"required,min=1,max=255"`
+	Statuses        []WorkflowStatus   `json:"statuses" validate:"omitempty,max=20,unique=Key,dive"`
+	Priorities      []WorkflowPriority `json:"priorities" validate:"omitempty,max=10,unique=Key,dive"`
+	StatusMapping   map[string]string  `json:"statusMapping" validate:"omitempty,max=100"`
+	PriorityMapping map[string]string  `json:"priorityMapping" validate:"omitempty,max=100"`
+}
+
+func (p *SetWorkflowPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if len(p.Statuses) == 0 && len(p.Priorities) == 0 {
+		return validation.NewFieldError("statuses", errs.FieldCodeRequired, "the workflow needs statuses or priorities")
+	}
+
+	for i, status := range p.Statuses {
+		if !workflowKeyPattern.MatchString(status.Key) {
+			return validation.NewFieldError(fmt.Sprintf("statuses[%d].key", i), errs.FieldCodeInvalidFormat,
+				"must be lowercase letters, digits, dashes and underscores")
+		}
+	}
+	for i, priority := range p.Priorities {
+		if !workflowKeyPattern.MatchString(priority.Key) {
+			return validation.NewFieldError(fmt.Sprintf("priorities[%d].key", i), errs.FieldCodeInvalidFormat,
+				"must be lowercase letters, digits, dashes and underscores")
+		}
+	}
+
+	// The built-in statuses and priorities stand for the same status or level on every scale,
+	// keys named like them have to be those
+	for i, status := range p.Statuses {
+		if slices.Contains(StatusCategories, todo.Status(status.Key)) && status.Category != todo.Status(status.Key) {
+			return validation.NewFieldError(fmt.Sprintf("statuses[%d].category", i), errs.FieldCodeInvalidValue,
+				"must be "+status.Key+", like the built-in status of that key")
+		}
+	}
+	for i, priority := range p.Priorities {
+		if level, ok := builtInLevel(priority.Key, len(p.Priorities)); ok && level != i {
+			return validation.NewFieldError(fmt.Sprintf("priorities[%d].key", i), errs.FieldCodeInvalidValue,
+				"must be the lowest level for low, the middle one for medium and the highest for high")
+		}
+	}
+
+	// Todos are created, completed, reopened and archived into the first status of a category,
+	// the scale needs one of each
+	if len(p.Statuses) > 0 {
+		for _, category := range StatusCategories {
+			if !slices.ContainsFunc(p.Statuses, func(s WorkflowStatus) bool { return s.Category == category }) {
+				return validation.NewFieldError("statuses", errs.FieldCodeInvalidValue,
+					fmt.Sprintf("needs a status of the %s category", category))
+			}
+		}
+	}
+
+	// Mappings move todos onto the scales the workspace ends up with, built-in ones included
+	scales := &Workflow{Statuses: p.Statuses, Priorities: p.Priorities}
+	for from, to := range p.StatusMapping {
+		if !slices.ContainsFunc(scales.StatusScale(), func(s WorkflowStatus) bool { return s.Key == to }) {
+			return validation.NewFieldError("statusMapping."+from, errs.FieldCodeInvalidReference,
+				"must name a status of the workflow")
+		}
+	}
+	for from, to := range p.PriorityMapping {
+		if !slices.ContainsFunc(scales.PriorityScale(), func(l WorkflowPriority) bool { return l.Key == to }) {
+			return validation.NewFieldError("priorityMapping."+from, errs.FieldCodeInvalidReference,
+				"must name a priority of the workflow")
+		}
+	}
+
+	if p.Statuses == nil {
+		p.Statuses = []WorkflowStatus{}
+	}
+	if p.Priorities == nil {
+		p.Priorities = []WorkflowPriority{}
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type DeleteWorkflowPayload struct {
+	WorkspaceID string `param:"workspaceId" validate:"required,min=1,max=255"`
+}
+
+func (p *DeleteWorkflowPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+// GetActiveTemplatesPayload lists the templates of the workspace active in the session
+type GetActiveTemplatesPayload struct{}
+
+func (p *GetActiveTemplatesPayload) Validate() error {
+	return nil
+}
+
+// ------------------------------------------------------------
+
+// ApplyTemplatePayload creates a todo from a template of the active workspace
+type ApplyTemplatePayload struct {
+	ID         uuid.UUID  `param:"id" validate:"required,uuid"`
+	CategoryID *uuid.UUID `json:"categoryId" validate:"omitempty,uuid"`
+}
+
+func (p *ApplyTemplatePayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetWorkloadQuery struct {
+	WorkspaceID string  `param:"workspaceId" validate:"required,min=1,max=255"`
+	Weeks       *int    `query:"weeks" validate:"omitempty,min=1,max=12"`
+	Timezone    *string `query:"tz" validate:"omitempty,timezone"`
+	// CapacityMinutes is the work a member fits in a week
+	CapacityMinutes *int `query:"capacity" validate:"omitempty,min=1,max=10080"`
+}
+
+func (q *GetWorkloadQuery) Validate() error {
+	if err := validation.Struct(q); err != nil {
+		return err
+	}
+
+	if q.Weeks == nil {
+		defaultWeeks := DefaultWorkloadWeeks
+		q.Weeks = &defaultWeeks
+	}
+	if q.Timezone == nil {
+		defaultTimezone := "UTC"
+		q.Timezone = &defaultTimezone
+	}
+	if q.CapacityMinutes == nil {
+		defaultCapacity := DefaultWeeklyCapacityMinutes
+		q.CapacityMinutes = &defaultCapacity
+	}
+
+	return nil
+}
+
+// Location is the timezone of the query, it was checked by Validate
+func (q *GetWorkloadQuery) Location() *time.Location {
+	location, _ := time.LoadLocation(*q.Timezone)
+	return location
+}
+
+// ------------------------------------------------------------
+
+type GetAgingPoliciesPayload struct {
+	WorkspaceID string `param:"workspaceId" validate:"required,min=1,max=255"`
+}
+
+func (p *GetAgingPoliciesPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type CreateAgingPolicyPayload struct {
+	WorkspaceID string `param:"workspaceId" validate:"required,min=1,max=255"`
+	Name        string `json:"name" validate:"required,min=1,max=100"`
+	// Priority is a key of the priority scale of the workspace
+	Priority *todo.Priority `json:"priority" validate:"omitempty,min=1,max=40"`
+	// MaxAgeHours is how long a todo may stay open, a year at most
+	MaxAgeHours int     `json:"maxAgeHours" validate:"required,min=1,max=8760"`
+	Tag         *string `json:"tag" validate:"omitempty,text,textmin=1,textmax=50"`
+	Notify      *bool   `json:"notify"`
+}
+
+func (p *CreateAgingPolicyPayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if p.Tag == nil {
+		defaultTag := DefaultBreachTag
+		p.Tag = &defaultTag
+	}
+	if p.Notify == nil {
+		defaultNotify := true
+		p.Notify = &defaultNotify
+	}
+
+	return nil
+}
+
+// ------------------------------------------------------------
+
+type DeleteAgingPolicyPayload struct {
+	WorkspaceID string    `param:"workspaceId" validate:"required,min=1,max=255"`
+	PolicyID    uuid.UUID `param:"policyId" validate:"required,uuid"`
+}
+
+func (p *DeleteAgingPolicyPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetBreachReportPayload struct {
+	WorkspaceID string `param:"workspaceId" validate:"required,min=1,max=255"`
+}
+
+func (p *GetBreachReportPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+// GetWorkspaceWorkflowPayload reads the workflow of the active workspace, for the members to
+// show the statuses and priorities of their todos
+type GetWorkspaceWorkflowPayload struct {
+	WorkspaceID string `param:"workspaceId" validate:"required,min=1,max=255"`
+}
+
+func (p *GetWorkspaceWorkflowPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetInvitesPayload struct {
+	WorkspaceID string `param:"workspaceId" validate:"required,min=1,max=255"`
+}
+
+func (p *GetInvitesPayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type CreateInvitePayload struct {
+	WorkspaceID    string   `param:"workspaceId" validate:"required,min=1,max=255"`
+	AllowedDomains []string `json:"allowedDomains" validate:"omitempty,max=20,dive,fqdn"`
+	MaxUses        *int     `json:"maxUses" validate:"omitempty,min=1,max=10000"`
+	// ExpiresInHours is how long the link works, a year at most. It never expires when unset.
+	ExpiresInHours *int `json:"expiresInHours" validate:"omitempty,min=1,max=8760"`
+}
+
+func (p *CreateInvitePayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	domains := make([]string, 0, len(p.AllowedDomains))
+	for _, domain := range p.AllowedDomains {
+		domain = strings.ToLower(domain)
+		if !slices.Contains(domains, domain) {
+			domains = append(domains, domain)
+		}
+	}
+	p.AllowedDomains = domains
+
+	return nil
+}
+
+// ExpiresAt is when a link created at now stops working, nil when it never does
+func (p *CreateInvitePayload) ExpiresAt(now time.Time) *time.Time {
+	if p.ExpiresInHours == nil {
+		return nil
+	}
+	expiresAt := now.Add(time.Duration(*p.ExpiresInHours) * time.Hour)
+	return &expiresAt
+}
+
+// ------------------------------------------------------------
+
+type RevokeInvitePayload struct {
+	WorkspaceID string    `param:"workspaceId" validate:"required,min=1,max=255"`
+	InviteID    uuid.UUID `param:"inviteId" validate:"required,uuid"`
+}
+
+func (p *RevokeInvitePayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type AcceptInvitePayload struct {
+	Token string `param:"token" validate:"required,hexadecimal,len=64"`
+}
+
+func (p *AcceptInvitePayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type GetFreezePayload struct {
+	WorkspaceID string `param:"workspaceId" validate:"required,min=1,max=255"`
+}
+
+func (p *GetFreezePayload) Validate() error {
+	return validation.Struct(p)
+}
+
+// ------------------------------------------------------------
+
+type FreezeWorkspacePayload struct {
+	WorkspaceID string  `param:"workspaceId" validate:"required,min=1,max=255"`
+	Reason      *string `json:"reason" validate:"omitempty,min=1,max=500"`
+	// RetentionDays is how long the data is kept before it is purged, a year at most
+	RetentionDays *int `json:"retentionDays" validate:"omitempty,min=1,max=365"`
+}
+
+func (p *FreezeWorkspacePayload) Validate() error {
+	if err := validation.Struct(p); err != nil {
+		return err
+	}
+
+	if p.RetentionDays == nil {
+		defaultRetention := DefaultFreezeRetentionDays
+		p.RetentionDays = &defaultRetention
+	}
+
+	return nil
+}
+
+// PurgeAfter is when the data of a workspace frozen at now is purged
+func (p *FreezeWorkspacePayload) PurgeAfter(now time.Time) time.Time {
+	return now.AddDate(0, 0, *p.RetentionDays)
+}
+
+// ------------------------------------------------------------
+
+type ReactivateWorkspacePayload struct {
+	WorkspaceID string `param:"workspaceId" validate:"required,min=1,max=255"`
+}
+
+func (p *ReactivateWorkspacePayload) Validate() error {
+	return validation.Struct(p)
+}
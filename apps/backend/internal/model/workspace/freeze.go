@@ -0,0 +1,35 @@
+package workspace
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultFreezeRetentionDays is how long the data of a frozen workspace is kept by default
+	DefaultFreezeRetentionDays = 30
+	// MaxPurgesPerPass bounds the workspaces a purge pass picks up, the rest wait for the next
+	MaxPurgesPerPass = 100
+)
+
+// Freeze archives a workspace: writes are rejected and its data is purged after PurgeAfter.
+// The workspace can be reactivated until then.
+type Freeze struct {
+	WorkspaceID string `json:"workspaceId" db:"workspace_id"`
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+	// The admin who froze the workspace
+	FrozenBy string  `json:"frozenBy" db:"frozen_by"`
+	Reason   *string `json:"reason" db:"reason"`
+	// BackupID is the final export bundle, GetBackup tells when it is stored
+	BackupID   *uuid.UUID `json:"backupId" db:"backup_id"`
+	PurgeAfter time.Time  `json:"purgeAfter" db:"purge_after"`
+	PurgedAt   *time.Time `json:"purgedAt" db:"purged_at"`
+}
+
+// Purged tells whether the data of the workspace is gone, it can't be reactivated anymore
+func (f *Freeze) Purged() bool {
+	return f.PurgedAt != nil
+}
@@ -0,0 +1,118 @@
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/google/uuid"
+)
+
+type InviteStatus string
+
+const (
+	InviteStatusPending InviteStatus = "pending"
+	InviteStatusExpired InviteStatus = "expired"
+	InviteStatusUsedUp  InviteStatus = "used_up"
+	InviteStatusRevoked InviteStatus = "revoked"
+)
+
+// InviteTokenBytes is how much randomness an invite token carries, it is hex encoded in links
+const InviteTokenBytes = 32
+
+// HashInviteToken is what is stored of a token, a leaked table doesn't let anyone join
+func HashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Invite is a shareable link to join a workspace. Only the hash of its token is stored, Token
+// is filled in once, when the invite is created.
+type Invite struct {
+	model.BaseWithId
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+	WorkspaceID string `json:"workspaceId" db:"workspace_id"`
+	TokenHash   string `json:"-" db:"token_hash"`
+	// AllowedDomains limits who may join to the users with a verified email of these domains,
+	// anyone with the link may join when empty
+	AllowedDomains []string   `json:"allowedDomains" db:"allowed_domains"`
+	MaxUses        *int       `json:"maxUses" db:"max_uses"`
+	UseCount       int        `json:"useCount" db:"use_count"`
+	ExpiresAt      *time.Time `json:"expiresAt" db:"expires_at"`
+	RevokedAt      *time.Time `json:"revokedAt" db:"revoked_at"`
+	CreatedBy      string     `json:"createdBy" db:"created_by"`
+
+	Status InviteStatus `json:"status" db:"-"`
+	Token  *string      `json:"token,omitempty" db:"-"`
+}
+
+// StatusAt tells whether the invite can still be accepted at now, and why not
+func (i *Invite) StatusAt(now time.Time) InviteStatus {
+	switch {
+	case i.RevokedAt != nil:
+		return InviteStatusRevoked
+	case i.ExpiresAt != nil && !i.ExpiresAt.After(now):
+		return InviteStatusExpired
+	case i.MaxUses != nil && i.UseCount >= *i.MaxUses:
+		return InviteStatusUsedUp
+	default:
+		return InviteStatusPending
+	}
+}
+
+// Allows tells whether the invite lets a user with the email join, domains match exactly
+func (i *Invite) Allows(email string) bool {
+	if len(i.AllowedDomains) == 0 {
+		return true
+	}
+
+	at := strings.LastIndexByte(email, '@')
+	if at < 0 {
+		return false
+	}
+	return slices.Contains(i.AllowedDomains, strings.ToLower(email[at+1:]))
+}
+
+// InviteAcceptance records a user who joined the workspace through an invite
+type InviteAcceptance struct {
+	InviteID    uuid.UUID `json:"inviteId" db:"invite_id"`
+	WorkspaceID string    `json:"workspaceId" db:"workspace_id"`
+	UserID      string    `json:"userId" db:"user_id"`
+	Email       string    `json:"email" db:"email"`
+	AcceptedAt  time.Time `json:"acceptedAt" db:"accepted_at"`
+}
+
+// Invitations is the admin view of the invites of a workspace
+type Invitations struct {
+	WorkspaceID string `json:"workspaceId"`
+	// Pending are the invites that can still be accepted
+	Pending []Invite `json:"pending"`
+	// Closed are the invites that expired, were used up or were revoked
+	Closed []Invite `json:"closed"`
+	// Accepted lists who joined through the invites, latest first
+	Accepted []InviteAcceptance `json:"accepted"`
+}
+
+func NewInvitations(workspaceID string, invites []Invite, acceptances []InviteAcceptance, now time.Time) *Invitations {
+	invitations := &Invitations{
+		WorkspaceID: workspaceID,
+		Pending:     []Invite{},
+		Closed:      []Invite{},
+		Accepted:    acceptances,
+	}
+
+	for _, invite := range invites {
+		invite.Status = invite.StatusAt(now)
+		if invite.Status == InviteStatusPending {
+			invitations.Pending = append(invitations.Pending, invite)
+		} else {
+			invitations.Closed = append(invitations.Closed, invite)
+		}
+	}
+
+	return invitations
+}
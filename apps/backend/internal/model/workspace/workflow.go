@@ -0,0 +1,283 @@
+package workspace
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+)
+
+// workflowKeyPattern keeps keys safe to list in the comma separated todo filters
+var workflowKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,39}$`)
+
+// StatusCategories are the stages of the lifecycle of a todo, every status scale has statuses
+// of each of them
+var StatusCategories = []todo.Status{todo.StatusDraft, todo.StatusActive, todo.StatusCompleted, todo.StatusArchived}
+
+// WorkflowStatus is a status of the scale of a workspace. Its category is the stage of the
+// lifecycle the todos in it are at, the completed statuses are those counting as done.
+type WorkflowStatus struct {
+	Key      string      `json:"key" validate:"required,min=1,max=40"`
+	Name     string      `json:"name" validate:"required,text,textmin=1,textmax=60"`
+	Color    string      `json:"color" validate:"required,hexcolor"`
+	Category todo.Status `json:"category" validate:"required,oneof=draft active completed archived"`
+}
+
+// WorkflowPriority is a level of the priority scale of the workspace
+type WorkflowPriority struct {
+	Key   string `json:"key" validate:"required,min=1,max=40"`
+	Name  string `json:"name" validate:"required,text,textmin=1,textmax=60"`
+	Color string `json:"color" validate:"required,hexcolor"`
+}
+
+// defaultStatuses and defaultPriorities are the scales of the todos outside a workspace with a
+// workflow, their keys are the built-in statuses and priorities
+var (
+	defaultStatuses = []WorkflowStatus{
+		{Key: string(todo.StatusDraft), Name: "Draft", Color: "#9ca3af", Category: todo.StatusDraft},
+		{Key: string(todo.StatusActive), Name: "Active", Color: "#3b82f6", Category: todo.StatusActive},
+		{Key: string(todo.StatusCompleted), Name: "Completed", Color: "#22c55e", Category: todo.StatusCompleted},
+		{Key: string(todo.StatusArchived), Name: "Archived", Color: "#6b7280", Category: todo.StatusArchived},
+	}
+	defaultPriorities = []WorkflowPriority{
+		{Key: string(todo.PriorityLow), Name: "Low", Color: "#22c55e"},
+		{Key: string(todo.PriorityMedium), Name: "Medium", Color: "#eab308"},
+		{Key: string(todo.PriorityHigh), Name: "High", Color: "#ef4444"},
+	}
+)
+
+// Workflow is the status and priority scales of a workspace. The status and priority of its
+// todos are keys of the scales; the todos triggers keep the category of the status and the
+// level of the priority next to them, for the queries and counters to read.
+type Workflow struct {
+	WorkspaceID string `json:"workspaceId" db:"workspace_id"`
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+	// Statuses are in the order they are shown, empty to keep the built-in ones
+	Statuses []WorkflowStatus `json:"statuses" db:"statuses"`
+	// Priorities are ordered from the lowest level, empty to keep the built-in ones
+	Priorities []WorkflowPriority `json:"priorities" db:"priorities"`
+	// The admin who set the workflow
+	UpdatedBy string `json:"updatedBy" db:"updated_by"`
+}
+
+// StatusScale is the statuses the todos of the workspace can be in. The workflow is nil when
+// the workspace has none.
+func (w *Workflow) StatusScale() []WorkflowStatus {
+	if w == nil || len(w.Statuses) == 0 {
+		return defaultStatuses
+	}
+	return w.Statuses
+}
+
+// PriorityScale is the priorities of the todos of the workspace, from the lowest level
+func (w *Workflow) PriorityScale() []WorkflowPriority {
+	if w == nil || len(w.Priorities) == 0 {
+		return defaultPriorities
+	}
+	return w.Priorities
+}
+
+// ResolveStatus finds the status key stands for: a status of the scale, or for a built-in
+// status the scale doesn't have the first status of its category
+func (w *Workflow) ResolveStatus(key string) (WorkflowStatus, bool) {
+	scale := w.StatusScale()
+	if i := slices.IndexFunc(scale, func(s WorkflowStatus) bool { return s.Key == key }); i >= 0 {
+		return scale[i], true
+	}
+	if slices.Contains(StatusCategories, todo.Status(key)) {
+		return w.FirstStatus(todo.Status(key)), true
+	}
+	return WorkflowStatus{}, false
+}
+
+// FirstStatus is the status todos moved to a category land in
+func (w *Workflow) FirstStatus(category todo.Status) WorkflowStatus {
+	scale := w.StatusScale()
+	if i := slices.IndexFunc(scale, func(s WorkflowStatus) bool { return s.Category == category }); i >= 0 {
+		return scale[i]
+	}
+	return scale[0]
+}
+
+// ResolvePriority finds the level key stands for, from 0 for the lowest: a level of the scale,
+// or for a built-in priority the scale doesn't have its lowest, middle or highest level
+func (w *Workflow) ResolvePriority(key string) (int, bool) {
+	scale := w.PriorityScale()
+	if level := slices.IndexFunc(scale, func(p WorkflowPriority) bool { return p.Key == key }); level >= 0 {
+		return level, true
+	}
+	return builtInLevel(key, len(scale))
+}
+
+// builtInLevel is the level a built-in priority stands for on a scale of levels levels: low is
+// the lowest, medium the middle and high the highest
+func builtInLevel(key string, levels int) (int, bool) {
+	switch todo.Priority(key) {
+	case todo.PriorityLow:
+		return 0, true
+	case todo.PriorityMedium:
+		return (levels - 1) / 2, true
+	case todo.PriorityHigh:
+		return levels - 1, true
+	}
+	return 0, false
+}
+
+// DefaultLevel is the level of the todos created without a priority, the middle of the scale
+func (w *Workflow) DefaultLevel() int {
+	return (len(w.PriorityScale()) - 1) / 2
+}
+
+// Resolve settles the status and priority of a todo on the scales, the way the todos trigger
+// does: a built-in key stands for the status or level the scale has for it, any other key the
+// scale doesn't have leaves the todo in the first status of its category and at its level. It
+// keeps the category, level and completion time in step. before is nil for a new todo, the
+// workflow is nil when the workspace has none.
+func (w *Workflow) Resolve(before, after *todo.Todo, now time.Time) {
+	status, ok := w.ResolveStatus(string(after.Status))
+	if !ok {
+		status = w.FirstStatus(cmp.Or(after.StatusCategory, todo.StatusDraft))
+	}
+	after.Status = todo.Status(status.Key)
+	after.StatusCategory = status.Category
+
+	// Only a move to another category completes or reopens the todo, an archived todo keeps
+	// when it was completed
+	if before == nil || before.StatusCategory != after.StatusCategory {
+		switch after.StatusCategory {
+		case todo.StatusCompleted:
+			if after.CompletedAt == nil || (before != nil && sameTime(before.CompletedAt, after.CompletedAt)) {
+				after.CompletedAt = &now
+			}
+		case todo.StatusDraft, todo.StatusActive:
+			after.CompletedAt = nil
+		}
+	}
+
+	scale := w.PriorityScale()
+	level, ok := w.ResolvePriority(string(after.Priority))
+	if !ok {
+		level = w.DefaultLevel()
+		if before != nil {
+			level = min(before.PriorityLevel, len(scale)-1)
+		}
+	}
+	after.Priority = todo.Priority(scale[level].Key)
+	after.PriorityLevel = level
+}
+
+// ApplyToCreate checks the status and priority of a new todo against the scales, replacing the
+// built-in keys with those of the scales. New todos start as drafts or active, never done.
+func (w *Workflow) ApplyToCreate(payload *todo.CreateTodoPayload) error {
+	if payload.Status != nil {
+		status, err := w.lookupStatus(*payload.Status)
+		if err != nil {
+			return err
+		}
+		if status.Category != todo.StatusDraft && status.Category != todo.StatusActive {
+			return validation.NewFieldError("status", errs.FieldCodeInvalidValue,
+				"new todos start in a draft or active status")
+		}
+		key := todo.Status(status.Key)
+		payload.Status = &key
+	}
+
+	if payload.Priority != nil {
+		priority, err := w.PriorityKey("priority", *payload.Priority)
+		if err != nil {
+			return err
+		}
+		payload.Priority = &priority
+	}
+
+	return nil
+}
+
+// ApplyToUpdate checks the status and priority of an edit of current against the scales,
+// replacing the built-in keys with those of the scales. It returns the category the todo ends
+// up in, which the due date rule of the edit is checked against.
+func (w *Workflow) ApplyToUpdate(payload *todo.UpdateTodoPayload, current *todo.Todo) (todo.Status, error) {
+	category := current.StatusCategory
+	if payload.Status != nil {
+		status, err := w.lookupStatus(*payload.Status)
+		if err != nil {
+			return "", err
+		}
+		key := todo.Status(status.Key)
+		payload.Status = &key
+		category = status.Category
+	}
+
+	if payload.Priority != nil {
+		priority, err := w.PriorityKey("priority", *payload.Priority)
+		if err != nil {
+			return "", err
+		}
+		payload.Priority = &priority
+	}
+
+	if err := payload.ValidateAgainst(category); err != nil {
+		return "", validation.Error(err)
+	}
+
+	return category, nil
+}
+
+// CategoryMapping maps the statuses of the workflow onto the built-in statuses of their
+// categories, for the todos of a workspace going back to the built-in scale
+func (w *Workflow) CategoryMapping() map[string]string {
+	mapping := map[string]string{}
+	for _, status := range w.StatusScale() {
+		mapping[status.Key] = string(status.Category)
+	}
+	return mapping
+}
+
+// LevelMapping maps the levels of the workflow onto the built-in priorities: the lowest level
+// is low, the highest is high and the levels between spread evenly over the three
+func (w *Workflow) LevelMapping() map[string]string {
+	priorities := []todo.Priority{todo.PriorityLow, todo.PriorityMedium, todo.PriorityHigh}
+	scale := w.PriorityScale()
+	mapping := map[string]string{}
+	for level, priority := range scale {
+		spread := 1
+		if len(scale) > 1 {
+			spread = (2*level + (len(scale)-1)/2) / (len(scale) - 1)
+		}
+		mapping[priority.Key] = string(priorities[spread])
+	}
+	return mapping
+}
+
+func (w *Workflow) lookupStatus(key todo.Status) (WorkflowStatus, error) {
+	status, ok := w.ResolveStatus(string(key))
+	if !ok {
+		return WorkflowStatus{}, validation.NewFieldError("status", errs.FieldCodeInvalidReference,
+			"must be a status of the workspace")
+	}
+	return status, nil
+}
+
+// PriorityKey is the key of the scale key stands for, the error reports field when the scale
+// has none
+func (w *Workflow) PriorityKey(field string, key todo.Priority) (todo.Priority, error) {
+	level, ok := w.ResolvePriority(string(key))
+	if !ok {
+		return "", validation.NewFieldError(field, errs.FieldCodeInvalidReference,
+			"must be a priority of the workspace")
+	}
+	return todo.Priority(w.PriorityScale()[level].Key), nil
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,139 @@
+package workspace
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+const (
+	// DefaultWorkloadWeeks is how many weeks the workload covers unless the client says otherwise
+	DefaultWorkloadWeeks = 4
+	// DefaultWeeklyCapacityMinutes is five days of the planner's daily capacity
+	DefaultWeeklyCapacityMinutes = 5 * 480
+)
+
+// Buckets of the workload besides the weeks, which are keyed by their Monday (YYYY-MM-DD)
+const (
+	BucketOverdue     = "overdue"
+	BucketUnscheduled = "unscheduled"
+)
+
+// WorkloadBucket sums the open todos of a member falling in a bucket
+type WorkloadBucket struct {
+	UserID string `db:"user_id"`
+	Bucket string `db:"bucket"`
+	Load
+}
+
+// Load sums open todos and their estimates, todos without an estimate are counted apart
+type Load struct {
+	OpenTodos        int `json:"openTodos" db:"open_todos"`
+	EstimatedMinutes int `json:"estimatedMinutes" db:"estimated_minutes"`
+	UnestimatedCount int `json:"unestimatedCount" db:"unestimated_count"`
+}
+
+func (l *Load) add(other Load) {
+	l.OpenTodos += other.OpenTodos
+	l.EstimatedMinutes += other.EstimatedMinutes
+	l.UnestimatedCount += other.UnestimatedCount
+}
+
+type WeekLoad struct {
+	// Week is the Monday the week starts on
+	Week string `json:"week"`
+	Load
+	OverCapacity bool `json:"overCapacity"`
+}
+
+// MemberWorkload is the open work of a member. Todos have no separate assignee, a member is
+// assigned the todos they own in the workspace.
+type MemberWorkload struct {
+	UserID string     `json:"userId"`
+	Weeks  []WeekLoad `json:"weeks"`
+	// Overdue is the open todos due before the first week
+	Overdue     Load `json:"overdue"`
+	Unscheduled Load `json:"unscheduled"`
+	// OverloadedWeeks counts the weeks estimated above the capacity
+	OverloadedWeeks int `json:"overloadedWeeks"`
+}
+
+// Workload lays the open todos of a workspace out per member and week, the most overloaded
+// members first
+type Workload struct {
+	WorkspaceID     string           `json:"workspaceId"`
+	Timezone        string           `json:"timezone"`
+	CapacityMinutes int              `json:"capacityMinutes"`
+	Weeks           []string         `json:"weeks"`
+	Members         []MemberWorkload `json:"members"`
+}
+
+// NewWorkload spreads the buckets over the weeks, a member with nothing open is left out
+func NewWorkload(workspaceID, timezone string, weeks []string, capacityMinutes int,
+	buckets []WorkloadBucket,
+) *Workload {
+	workload := &Workload{
+		WorkspaceID:     workspaceID,
+		Timezone:        timezone,
+		CapacityMinutes: capacityMinutes,
+		Weeks:           weeks,
+		Members:         []MemberWorkload{},
+	}
+
+	members := map[string]*MemberWorkload{}
+	order := []string{}
+	for _, bucket := range buckets {
+		member, ok := members[bucket.UserID]
+		if !ok {
+			member = &MemberWorkload{UserID: bucket.UserID, Weeks: make([]WeekLoad, len(weeks))}
+			for i, week := range weeks {
+				member.Weeks[i].Week = week
+			}
+			members[bucket.UserID] = member
+			order = append(order, bucket.UserID)
+		}
+
+		switch bucket.Bucket {
+		case BucketOverdue:
+			member.Overdue.add(bucket.Load)
+		case BucketUnscheduled:
+			member.Unscheduled.add(bucket.Load)
+		default:
+			for i := range member.Weeks {
+				if member.Weeks[i].Week == bucket.Bucket {
+					member.Weeks[i].add(bucket.Load)
+				}
+			}
+		}
+	}
+
+	for _, userID := range order {
+		member := members[userID]
+		for i := range member.Weeks {
+			member.Weeks[i].OverCapacity = member.Weeks[i].EstimatedMinutes > capacityMinutes
+			if member.Weeks[i].OverCapacity {
+				member.OverloadedWeeks++
+			}
+		}
+		workload.Members = append(workload.Members, *member)
+	}
+
+	slices.SortStableFunc(workload.Members, func(a, b MemberWorkload) int {
+		return cmp.Or(
+			cmp.Compare(b.OverloadedWeeks, a.OverloadedWeeks),
+			cmp.Compare(b.scheduledMinutes(), a.scheduledMinutes()),
+			strings.Compare(a.UserID, b.UserID),
+		)
+	})
+
+	return workload
+}
+
+// scheduledMinutes sums the estimates of the weeks
+func (m *MemberWorkload) scheduledMinutes() int {
+	minutes := 0
+	for _, week := range m.Weeks {
+		minutes += week.EstimatedMinutes
+	}
+	return minutes
+}
@@ -0,0 +1,93 @@
+package workspace
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/category"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+// Template is a todo the admins of a workspace publish for its members to start from
+type Template struct {
+	model.BaseWithId
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+	WorkspaceID string         `json:"workspaceId" db:"workspace_id"`
+	Name        string         `json:"name" db:"name"`
+	Title       string         `json:"title" db:"title"`
+	Description *string        `json:"description" db:"description"`
+	Priority    *todo.Priority `json:"priority" db:"priority"`
+	// Subtasks are the titles of the subtasks created with the todo
+	Subtasks []string `json:"subtasks" db:"subtasks"`
+	// DueInDays sets the due date that many days after the template is applied
+	DueInDays *int   `json:"dueInDays" db:"due_in_days"`
+	CreatedBy string `json:"createdBy" db:"created_by"`
+}
+
+// KitCategory is a category the onboarding kit creates
+type KitCategory struct {
+	Name        string  `json:"name" validate:"required,text,textmin=1,textmax=100"`
+	Color       string  `json:"color" validate:"required,hexcolor"`
+	Description *string `json:"description" validate:"omitempty,max=255"`
+}
+
+// KitTodo is a sample todo the onboarding kit creates
+type KitTodo struct {
+	Title       string         `json:"title" validate:"required,text,textmin=1,textmax=255"`
+	Description *string        `json:"description" validate:"omitempty,max=1000"`
+	Priority    *todo.Priority `json:"priority" validate:"omitempty,oneof=low medium high"`
+	// CategoryName files the todo under a category of the kit
+	CategoryName *string `json:"categoryName" validate:"omitempty,text,textmin=1,textmax=100"`
+	DueInDays    *int    `json:"dueInDays" validate:"omitempty,min=0,max=3650"`
+}
+
+// OnboardingKit is applied once to every member joining the workspace after it was set
+type OnboardingKit struct {
+	WorkspaceID string `json:"workspaceId" db:"workspace_id"`
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+	Categories []KitCategory `json:"categories" db:"categories"`
+	Todos      []KitTodo     `json:"todos" db:"todos"`
+	// The admin who set the kit
+	UpdatedBy string `json:"updatedBy" db:"updated_by"`
+}
+
+// Onboarding is what applying the kit created for a new member
+type Onboarding struct {
+	WorkspaceID string              `json:"workspaceId"`
+	Categories  []category.Category `json:"categories"`
+	Todos       []todo.Todo         `json:"todos"`
+}
+
+// dueIn is the due date of something due days from now, nil without a due date
+func dueIn(days *int, now time.Time) *time.Time {
+	if days == nil {
+		return nil
+	}
+	due := now.AddDate(0, 0, *days)
+	return &due
+}
+
+// CreatePayload is the todo the template turns into
+func (t *Template) CreatePayload(categoryID *uuid.UUID, now time.Time) *todo.CreateTodoPayload {
+	return &todo.CreateTodoPayload{
+		Title:       t.Title,
+		Description: t.Description,
+		Priority:    t.Priority,
+		DueDate:     dueIn(t.DueInDays, now),
+		CategoryID:  categoryID,
+	}
+}
+
+// CreatePayload is the todo the kit todo turns into
+func (t *KitTodo) CreatePayload(categoryID *uuid.UUID, now time.Time) *todo.CreateTodoPayload {
+	return &todo.CreateTodoPayload{
+		Title:       t.Title,
+		Description: t.Description,
+		Priority:    t.Priority,
+		DueDate:     dueIn(t.DueInDays, now),
+		CategoryID:  categoryID,
+	}
+}
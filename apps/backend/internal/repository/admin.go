@@ -0,0 +1,311 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/admin"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type AdminRepository struct {
+	server *server.Server
+}
+
+func NewAdminRepository(server *server.Server) *AdminRepository {
+	return &AdminRepository{server: server}
+}
+
+// GetUserStats counts what a user owns across every table, users themselves live in Clerk
+func (r *AdminRepository) GetUserStats(ctx context.Context, userID string) (*admin.UserStats, error) {
+	stmt := `
+		SELECT
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					todos
+				WHERE
+					user_id = @user_id
+			) AS todos,
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					todos
+				WHERE
+					user_id = @user_id
+					AND status_category = 'completed'
+			) AS completed_todos,
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					todo_categories
+				WHERE
+					user_id = @user_id
+			) AS categories,
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					todo_comments
+				WHERE
+					user_id = @user_id
+			) AS comments,
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					todo_attachments
+				WHERE
+					uploaded_by = @user_id
+			) AS attachments,
+			(
+				SELECT
+					COALESCE(SUM(file_size), 0)
+				FROM
+					todo_attachments
+				WHERE
+					uploaded_by = @user_id
+			) AS attachment_bytes,
+			(
+				SELECT
+					MAX(updated_at)
+				FROM
+					todos
+				WHERE
+					user_id = @user_id
+			) AS last_activity_at
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get user stats query for user_id=%s: %w", userID, err)
+	}
+
+	stats, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[admin.UserStats])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect user stats for user_id=%s: %w", userID, err)
+	}
+
+	return &stats, nil
+}
+
+func (r *AdminRepository) GetStorageQuota(ctx context.Context, userID string) (*admin.StorageQuota, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			storage_quotas
+		WHERE
+			user_id = @user_id
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get storage quota query for user_id=%s: %w", userID, err)
+	}
+
+	quota, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[admin.StorageQuota])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeStorageQuotaNotFound
+			return nil, errs.NewNotFoundError("the user has no storage quota override", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:storage_quotas for user_id=%s: %w", userID, err)
+	}
+
+	return &quota, nil
+}
+
+// SetStorageQuota creates or replaces the storage quota override of a user
+func (r *AdminRepository) SetStorageQuota(ctx context.Context, updatedBy string,
+	payload *admin.SetStorageQuotaPayload,
+) (*admin.StorageQuota, error) {
+	stmt := `
+		INSERT INTO
+			storage_quotas (
+				user_id,
+				quota_bytes,
+				updated_by
+			)
+		VALUES
+			(
+				@user_id,
+				@quota_bytes,
+				@updated_by
+			)
+		ON CONFLICT (user_id) DO UPDATE
+		SET
+			quota_bytes = EXCLUDED.quota_bytes,
+			updated_by = EXCLUDED.updated_by
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":     payload.UserID,
+		"quota_bytes": *payload.QuotaBytes,
+		"updated_by":  updatedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute set storage quota query for user_id=%s: %w", payload.UserID, err)
+	}
+
+	quota, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[admin.StorageQuota])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:storage_quotas for user_id=%s: %w", payload.UserID, err)
+	}
+
+	return &quota, nil
+}
+
+// DeleteStorageQuota removes an override and returns it as it was, the user follows the config again
+func (r *AdminRepository) DeleteStorageQuota(ctx context.Context, userID string) (*admin.StorageQuota, error) {
+	stmt := `
+		DELETE FROM storage_quotas
+		WHERE
+			user_id = @user_id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute delete storage quota query for user_id=%s: %w", userID, err)
+	}
+
+	quota, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[admin.StorageQuota])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeStorageQuotaNotFound
+			return nil, errs.NewNotFoundError("the user has no storage quota override", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:storage_quotas for user_id=%s: %w", userID, err)
+	}
+
+	return &quota, nil
+}
+
+// GetWorkspaceStats counts what a workspace holds, workspaces themselves live in Clerk
+func (r *AdminRepository) GetWorkspaceStats(ctx context.Context, workspaceID string) (*admin.WorkspaceStats, error) {
+	stmt := `
+		SELECT
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					workspace_members
+				WHERE
+					workspace_id = @workspace_id
+			) AS members,
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					todos
+				WHERE
+					workspace_id = @workspace_id
+			) AS todos,
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					todos
+				WHERE
+					workspace_id = @workspace_id
+					AND status_category = 'completed'
+			) AS completed_todos,
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					workspace_templates
+				WHERE
+					workspace_id = @workspace_id
+			) AS templates,
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					workspace_aging_policies
+				WHERE
+					workspace_id = @workspace_id
+			) AS aging_policies,
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					workspace_invites
+				WHERE
+					workspace_id = @workspace_id
+					AND revoked_at IS NULL
+					AND (
+						expires_at IS NULL
+						OR expires_at > CURRENT_TIMESTAMP
+					)
+					AND (
+						max_uses IS NULL
+						OR use_count < max_uses
+					)
+			) AS pending_invites,
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					webhook_endpoints
+				WHERE
+					workspace_id = @workspace_id
+			) AS webhooks,
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					todo_attachments a
+					JOIN todos t ON t.id = a.todo_id
+				WHERE
+					t.workspace_id = @workspace_id
+			) AS attachments,
+			(
+				SELECT
+					COALESCE(SUM(a.file_size), 0)
+				FROM
+					todo_attachments a
+					JOIN todos t ON t.id = a.todo_id
+				WHERE
+					t.workspace_id = @workspace_id
+			) AS attachment_bytes,
+			(
+				SELECT
+					MAX(updated_at)
+				FROM
+					todos
+				WHERE
+					workspace_id = @workspace_id
+			) AS last_activity_at
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get workspace stats query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	stats, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[admin.WorkspaceStats])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect workspace stats for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/apiclient"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type APIClientRepository struct {
+	server *server.Server
+}
+
+func NewAPIClientRepository(server *server.Server) *APIClientRepository {
+	return &APIClientRepository{server: server}
+}
+
+// GetClients returns every client sorted by name
+func (r *APIClientRepository) GetClients(ctx context.Context) ([]apiclient.APIClient, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			api_clients
+		ORDER BY
+			name ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get API clients query: %w", err)
+	}
+
+	clients, err := pgx.CollectRows(rows, pgx.RowToStructByName[apiclient.APIClient])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:api_clients: %w", err)
+	}
+
+	return clients, nil
+}
+
+// GetAllowedOrigins returns the origins of the enabled clients
+func (r *APIClientRepository) GetAllowedOrigins(ctx context.Context) ([]string, error) {
+	stmt := `
+		SELECT DISTINCT
+			UNNEST(allowed_origins)
+		FROM
+			api_clients
+		WHERE
+			enabled
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get allowed origins query: %w", err)
+	}
+
+	origins, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:api_clients: %w", err)
+	}
+
+	return origins, nil
+}
+
+func (r *APIClientRepository) CreateClient(ctx context.Context, createdBy string,
+	payload *apiclient.CreateAPIClientPayload,
+) (*apiclient.APIClient, error) {
+	stmt := `
+		INSERT INTO
+			api_clients (name, allowed_origins, enabled, created_by)
+		VALUES
+			(@name, @allowed_origins, @enabled, @created_by)
+		RETURNING
+			*
+	`
+
+	return r.clientRow(ctx, "create API client", stmt, pgx.NamedArgs{
+		"name":            payload.Name,
+		"allowed_origins": payload.AllowedOrigins,
+		"enabled":         *payload.Enabled,
+		"created_by":      createdBy,
+	})
+}
+
+func (r *APIClientRepository) UpdateClient(ctx context.Context,
+	payload *apiclient.UpdateAPIClientPayload,
+) (*apiclient.APIClient, error) {
+	args := pgx.NamedArgs{
+		"id": payload.ID,
+	}
+	setClauses := []string{}
+
+	if payload.Name != nil {
+		setClauses = append(setClauses, "name = @name")
+		args["name"] = *payload.Name
+	}
+	if payload.AllowedOrigins != nil {
+		setClauses = append(setClauses, "allowed_origins = @allowed_origins")
+		args["allowed_origins"] = payload.AllowedOrigins
+	}
+	if payload.Enabled != nil {
+		setClauses = append(setClauses, "enabled = @enabled")
+		args["enabled"] = *payload.Enabled
+	}
+
+	if len(setClauses) == 0 {
+		return nil, errs.NewBadRequestError("no fields to update", false, nil, nil, nil)
+	}
+
+	stmt := `UPDATE api_clients SET ` + strings.Join(setClauses, ", ") + ` WHERE id = @id RETURNING *`
+
+	return r.clientRow(ctx, "update API client", stmt, args)
+}
+
+func (r *APIClientRepository) DeleteClient(ctx context.Context, clientID uuid.UUID) (*apiclient.APIClient, error) {
+	stmt := `
+		DELETE FROM api_clients
+		WHERE
+			id = @id
+		RETURNING
+			*
+	`
+
+	return r.clientRow(ctx, "delete API client", stmt, pgx.NamedArgs{
+		"id": clientID,
+	})
+}
+
+func (r *APIClientRepository) clientRow(ctx context.Context, operation, stmt string,
+	args pgx.NamedArgs,
+) (*apiclient.APIClient, error) {
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s query for api_client_id=%v: %w", operation, args["id"], err)
+	}
+
+	client, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[apiclient.APIClient])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeAPIClientNotFound
+			return nil, errs.NewNotFoundError("API client not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:api_clients for api_client_id=%v: %w", args["id"], err)
+	}
+
+	return &client, nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/apikey"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type APIKeyRepository struct {
+	server *server.Server
+}
+
+func NewAPIKeyRepository(server *server.Server) *APIKeyRepository {
+	return &APIKeyRepository{server: server}
+}
+
+// GetKeys returns the keys of the user, latest first
+func (r *APIKeyRepository) GetKeys(ctx context.Context, userID string) ([]apikey.Key, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			api_keys
+		WHERE
+			user_id = @user_id
+		ORDER BY
+			created_at DESC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get API keys query for user_id=%s: %w", userID, err)
+	}
+
+	keys, err := pgx.CollectRows(rows, pgx.RowToStructByName[apikey.Key])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:api_keys for user_id=%s: %w", userID, err)
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyRepository) CountKeys(ctx context.Context, userID string) (int, error) {
+	stmt := `
+		SELECT
+			COUNT(*)
+		FROM
+			api_keys
+		WHERE
+			user_id = @user_id
+	`
+
+	var count int
+	err := r.server.DB.Writer(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	}).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute count API keys query for user_id=%s: %w", userID, err)
+	}
+
+	return count, nil
+}
+
+func (r *APIKeyRepository) CreateKey(ctx context.Context, key *apikey.Key) (*apikey.Key, error) {
+	stmt := `
+		INSERT INTO
+			api_keys (user_id, name, prefix, secret_hash, plan)
+		VALUES
+			(@user_id, @name, @prefix, @secret_hash, @plan)
+		RETURNING
+			*
+	`
+
+	return r.keyRow(ctx, "create API key", stmt, pgx.NamedArgs{
+		"user_id":     key.UserID,
+		"name":        key.Name,
+		"prefix":      key.Prefix,
+		"secret_hash": key.SecretHash,
+		"plan":        key.Plan,
+	})
+}
+
+// DeleteKey revokes a key of the user
+func (r *APIKeyRepository) DeleteKey(ctx context.Context, userID string, keyID uuid.UUID) (*apikey.Key, error) {
+	stmt := `
+		DELETE FROM api_keys
+		WHERE
+			id = @id
+			AND user_id = @user_id
+		RETURNING
+			*
+	`
+
+	return r.keyRow(ctx, "delete API key", stmt, pgx.NamedArgs{
+		"id":      keyID,
+		"user_id": userID,
+	})
+}
+
+// GetKeyBySecretHash finds the key a request authenticates with
+func (r *APIKeyRepository) GetKeyBySecretHash(ctx context.Context, secretHash []byte) (*apikey.Key, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			api_keys
+		WHERE
+			secret_hash = @secret_hash
+	`
+
+	return r.keyRow(ctx, "get API key", stmt, pgx.NamedArgs{
+		"secret_hash": secretHash,
+	})
+}
+
+func (r *APIKeyRepository) SetKeyPlan(ctx context.Context, keyID uuid.UUID, plan string) (*apikey.Key, error) {
+	stmt := `
+		UPDATE api_keys
+		SET
+			plan = @plan
+		WHERE
+			id = @id
+		RETURNING
+			*
+	`
+
+	return r.keyRow(ctx, "set API key plan", stmt, pgx.NamedArgs{
+		"id":   keyID,
+		"plan": plan,
+	})
+}
+
+// TouchKey records that the key was used
+func (r *APIKeyRepository) TouchKey(ctx context.Context, keyID uuid.UUID) error {
+	stmt := `
+		UPDATE api_keys
+		SET
+			last_used_at = CURRENT_TIMESTAMP
+		WHERE
+			id = @id
+	`
+
+	_, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"id": keyID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute touch API key query for api_key_id=%s: %w", keyID, err)
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) keyRow(ctx context.Context, operation, stmt string, args pgx.NamedArgs) (*apikey.Key, error) {
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s query for api_key_id=%v: %w", operation, args["id"], err)
+	}
+
+	key, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[apikey.Key])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeAPIKeyNotFound
+			return nil, errs.NewNotFoundError("API key not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:api_keys for api_key_id=%v: %w", args["id"], err)
+	}
+
+	return &key, nil
+}
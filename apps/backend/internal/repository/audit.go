@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type AuditRepository struct {
+	server *server.Server
+}
+
+func NewAuditRepository(server *server.Server) *AuditRepository {
+	return &AuditRepository{server: server}
+}
+
+// CreateEntry appends an entry to the audit log, the database rejects any later change to it
+func (r *AuditRepository) CreateEntry(ctx context.Context, entry *audit.Entry) error {
+	stmt := `
+		INSERT INTO
+			audit_log (
+				actor_id,
+				actor_role,
+				impersonator_id,
+				action,
+				entity_type,
+				entity_id,
+				ip_address,
+				user_agent,
+				request_id,
+				before,
+				after
+			)
+		VALUES
+			(
+				@actor_id,
+				@actor_role,
+				@impersonator_id,
+				@action,
+				@entity_type,
+				@entity_id,
+				@ip_address,
+				@user_agent,
+				@request_id,
+				@before,
+				@after
+			)
+	`
+
+	_, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"actor_id":        entry.ActorID,
+		"actor_role":      entry.ActorRole,
+		"impersonator_id": entry.ImpersonatorID,
+		"action":          entry.Action,
+		"entity_type":     entry.EntityType,
+		"entity_id":       entry.EntityID,
+		"ip_address":      entry.IPAddress,
+		"user_agent":      entry.UserAgent,
+		"request_id":      entry.RequestID,
+		"before":          entry.Before,
+		"after":           entry.After,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute create audit entry query for action=%s actor_id=%s: %w",
+			entry.Action, entry.ActorID, err)
+	}
+
+	return nil
+}
+
+func (r *AuditRepository) GetEntries(ctx context.Context, query *audit.GetAuditLogQuery) (*model.PaginatedResponse[audit.Entry], error) {
+	args := pgx.NamedArgs{}
+	where := auditFilterCondition(&query.Filter, args)
+
+	var total int
+	err := r.server.DB.Reader(ctx).QueryRow(ctx, "SELECT COUNT(*) FROM audit_log"+where, args).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total count for audit log: %w", err)
+	}
+
+	stmt := "SELECT * FROM audit_log" + where + " ORDER BY id DESC LIMIT @limit OFFSET @offset"
+	args["limit"] = *query.Limit
+	args["offset"] = (*query.Page - 1) * (*query.Limit)
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get audit log query: %w", err)
+	}
+
+	entries, err := pgx.CollectRows(rows, pgx.RowToStructByName[audit.Entry])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:audit_log: %w", err)
+	}
+
+	return &model.PaginatedResponse[audit.Entry]{
+		Data:       entries,
+		Page:       *query.Page,
+		Limit:      *query.Limit,
+		Total:      total,
+		TotalPages: (total + *query.Limit - 1) / *query.Limit,
+	}, nil
+}
+
+// StreamEntries calls fn for every entry matching the filter, oldest first and one row at a
+// time. Returning an error from fn stops the stream.
+func (r *AuditRepository) StreamEntries(ctx context.Context, filter *audit.Filter,
+	fn func(entry *audit.Entry) error,
+) error {
+	args := pgx.NamedArgs{}
+	stmt := "SELECT * FROM audit_log" + auditFilterCondition(filter, args) + " ORDER BY id ASC"
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, args)
+	if err != nil {
+		return fmt.Errorf("failed to execute stream audit log query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := pgx.RowToStructByName[audit.Entry](rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan row from table:audit_log: %w", err)
+		}
+
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to stream rows from table:audit_log: %w", err)
+	}
+
+	return nil
+}
+
+// auditFilterCondition builds the WHERE clause of a filter, empty when it matches everything
+func auditFilterCondition(filter *audit.Filter, args pgx.NamedArgs) string {
+	conditions := []string{}
+
+	if filter.ActorID != nil {
+		conditions = append(conditions, "actor_id = @actor_id")
+		args["actor_id"] = *filter.ActorID
+	}
+
+	if filter.Action != nil {
+		conditions = append(conditions, "action = @action")
+		args["action"] = *filter.Action
+	}
+
+	if filter.EntityType != nil {
+		conditions = append(conditions, "entity_type = @entity_type")
+		args["entity_type"] = *filter.EntityType
+	}
+
+	if filter.EntityID != nil {
+		conditions = append(conditions, "entity_id = @entity_id")
+		args["entity_id"] = *filter.EntityID
+	}
+
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= @from")
+		args["from"] = *filter.From
+	}
+
+	if filter.To != nil {
+		conditions = append(conditions, "created_at <= @to")
+		args["to"] = *filter.To
+	}
+
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
@@ -0,0 +1,604 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/backup"
+	"github.com/Sameer16536/ExecuTask/internal/model/category"
+	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type BackupRepository struct {
+	server *server.Server
+}
+
+func NewBackupRepository(server *server.Server) *BackupRepository {
+	return &BackupRepository{server: server}
+}
+
+// CreateBackup queues a new backup, a workspace can't have two in progress at once
+func (r *BackupRepository) CreateBackup(ctx context.Context, workspaceID, requestedBy string) (*backup.Backup, error) {
+	stmt := `
+		INSERT INTO
+			workspace_backups (workspace_id, requested_by)
+		VALUES
+			(@workspace_id, @requested_by)
+		ON CONFLICT (workspace_id)
+		WHERE
+			status IN ('pending', 'running') DO NOTHING
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+		"requested_by": requestedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create backup query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	backupItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[backup.Backup])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeBackupInProgress
+			return nil, errs.NewConflictError("a backup of this workspace is already in progress", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_backups for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	return &backupItem, nil
+}
+
+// GetBackups lists the backups of a workspace, newest first
+func (r *BackupRepository) GetBackups(ctx context.Context, workspaceID string) ([]backup.Backup, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			workspace_backups
+		WHERE
+			workspace_id=@workspace_id
+		ORDER BY
+			created_at DESC,
+			id DESC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get backups query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	backups, err := pgx.CollectRows(rows, pgx.RowToStructByName[backup.Backup])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:workspace_backups for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	return backups, nil
+}
+
+func (r *BackupRepository) GetBackup(ctx context.Context, backupID uuid.UUID) (*backup.Backup, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			workspace_backups
+		WHERE
+			id=@id
+	`
+
+	return r.backupRow(ctx, "get backup", stmt, backupID, nil)
+}
+
+// StartBackup moves a pending backup to running. A backup already running was picked up by an
+// attempt that died, it is claimed again. Finished backups are returned unchanged.
+func (r *BackupRepository) StartBackup(ctx context.Context, backupID uuid.UUID) (*backup.Backup, error) {
+	stmt := `
+		UPDATE workspace_backups
+		SET
+			status=CASE
+				WHEN status IN ('pending', 'running') THEN 'running'
+				ELSE status
+			END
+		WHERE
+			id=@id
+		RETURNING
+			*
+	`
+
+	return r.backupRow(ctx, "start backup", stmt, backupID, nil)
+}
+
+func (r *BackupRepository) CompleteBackup(ctx context.Context, backupID uuid.UUID, objectKey string, sizeBytes int64,
+	counts map[string]int,
+) (*backup.Backup, error) {
+	stmt := `
+		UPDATE workspace_backups
+		SET
+			status='completed',
+			object_key=@object_key,
+			size_bytes=@size_bytes,
+			counts=@counts,
+			error=NULL,
+			completed_at=NOW()
+		WHERE
+			id=@id
+		RETURNING
+			*
+	`
+
+	return r.backupRow(ctx, "complete backup", stmt, backupID, pgx.NamedArgs{
+		"object_key": objectKey,
+		"size_bytes": sizeBytes,
+		"counts":     counts,
+	})
+}
+
+// FailBackup gives up on a backup that is still in progress
+func (r *BackupRepository) FailBackup(ctx context.Context, backupID uuid.UUID, reason string) error {
+	stmt := `
+		UPDATE workspace_backups
+		SET
+			status='failed',
+			error=@error
+		WHERE
+			id=@id
+			AND status IN ('pending', 'running')
+	`
+
+	_, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"id":    backupID,
+		"error": reason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute fail backup query for backup_id=%s: %w", backupID.String(), err)
+	}
+
+	return nil
+}
+
+func (r *BackupRepository) backupRow(ctx context.Context, operation, stmt string, backupID uuid.UUID,
+	args pgx.NamedArgs,
+) (*backup.Backup, error) {
+	if args == nil {
+		args = pgx.NamedArgs{}
+	}
+	args["id"] = backupID
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s query for backup_id=%s: %w", operation, backupID.String(), err)
+	}
+
+	backupItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[backup.Backup])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeBackupNotFound
+			return nil, errs.NewNotFoundError("backup not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_backups for backup_id=%s: %w", backupID.String(), err)
+	}
+
+	return &backupItem, nil
+}
+
+// CreateRestore queues a restore, a workspace can't be restored into twice at once
+func (r *BackupRepository) CreateRestore(ctx context.Context, backupID uuid.UUID, targetWorkspaceID,
+	requestedBy string,
+) (*backup.Restore, error) {
+	stmt := `
+		INSERT INTO
+			workspace_restores (backup_id, target_workspace_id, requested_by)
+		VALUES
+			(@backup_id, @target_workspace_id, @requested_by)
+		ON CONFLICT (target_workspace_id)
+		WHERE
+			status IN ('pending', 'running') DO NOTHING
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"backup_id":           backupID,
+		"target_workspace_id": targetWorkspaceID,
+		"requested_by":        requestedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create restore query for backup_id=%s: %w", backupID.String(), err)
+	}
+
+	restore, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[backup.Restore])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeRestoreInProgress
+			return nil, errs.NewConflictError("a restore into this workspace is already in progress", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_restores for backup_id=%s: %w", backupID.String(), err)
+	}
+
+	return &restore, nil
+}
+
+func (r *BackupRepository) GetRestore(ctx context.Context, restoreID uuid.UUID) (*backup.Restore, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			workspace_restores
+		WHERE
+			id=@id
+	`
+
+	return r.restoreRow(ctx, "get restore", stmt, restoreID, nil)
+}
+
+// StartRestore moves a pending restore to running, like StartBackup
+func (r *BackupRepository) StartRestore(ctx context.Context, restoreID uuid.UUID) (*backup.Restore, error) {
+	stmt := `
+		UPDATE workspace_restores
+		SET
+			status=CASE
+				WHEN status IN ('pending', 'running') THEN 'running'
+				ELSE status
+			END
+		WHERE
+			id=@id
+		RETURNING
+			*
+	`
+
+	return r.restoreRow(ctx, "start restore", stmt, restoreID, nil)
+}
+
+func (r *BackupRepository) CompleteRestore(ctx context.Context, restoreID uuid.UUID, counts map[string]int) (*backup.Restore, error) {
+	stmt := `
+		UPDATE workspace_restores
+		SET
+			status='completed',
+			counts=@counts,
+			error=NULL,
+			completed_at=NOW()
+		WHERE
+			id=@id
+		RETURNING
+			*
+	`
+
+	return r.restoreRow(ctx, "complete restore", stmt, restoreID, pgx.NamedArgs{
+		"counts": counts,
+	})
+}
+
+// FailRestore gives up on a restore that is still in progress
+func (r *BackupRepository) FailRestore(ctx context.Context, restoreID uuid.UUID, reason string) error {
+	stmt := `
+		UPDATE workspace_restores
+		SET
+			status='failed',
+			error=@error
+		WHERE
+			id=@id
+			AND status IN ('pending', 'running')
+	`
+
+	_, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"id":    restoreID,
+		"error": reason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute fail restore query for restore_id=%s: %w", restoreID.String(), err)
+	}
+
+	return nil
+}
+
+func (r *BackupRepository) restoreRow(ctx context.Context, operation, stmt string, restoreID uuid.UUID,
+	args pgx.NamedArgs,
+) (*backup.Restore, error) {
+	if args == nil {
+		args = pgx.NamedArgs{}
+	}
+	args["id"] = restoreID
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s query for restore_id=%s: %w", operation, restoreID.String(), err)
+	}
+
+	restore, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[backup.Restore])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeRestoreNotFound
+			return nil, errs.NewNotFoundError("restore not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_restores for restore_id=%s: %w", restoreID.String(), err)
+	}
+
+	return &restore, nil
+}
+
+// CountWorkspaceTodos tells how many todos a workspace holds, restores need it empty
+func (r *BackupRepository) CountWorkspaceTodos(ctx context.Context, workspaceID string) (int64, error) {
+	stmt := `
+		SELECT
+			COUNT(*)
+		FROM
+			todos
+		WHERE
+			workspace_id=@workspace_id
+	`
+
+	var count int64
+	err := r.server.DB.Writer(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	}).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute count workspace todos query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	return count, nil
+}
+
+// GetWorkspaceContents reads everything a backup of the workspace holds. Categories are per
+// user, only those the todos of the workspace use are included.
+func (r *BackupRepository) GetWorkspaceContents(ctx context.Context, workspaceID string) (*backup.Contents, error) {
+	db := r.server.DB.Reader(ctx)
+	args := pgx.NamedArgs{"workspace_id": workspaceID}
+
+	todoRows, err := db.Query(ctx, `
+		SELECT
+			*
+		FROM
+			todos
+		WHERE
+			workspace_id=@workspace_id
+		ORDER BY
+			created_at ASC,
+			id ASC
+	`, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get workspace todos query for workspace_id=%s: %w", workspaceID, err)
+	}
+	todos, err := pgx.CollectRows(todoRows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	categoryRows, err := db.Query(ctx, `
+		SELECT
+			c.*
+		FROM
+			todo_categories c
+		WHERE
+			EXISTS (
+				SELECT
+					1
+				FROM
+					todos t
+				WHERE
+					t.workspace_id=@workspace_id
+					AND t.category_id=c.id
+			)
+		ORDER BY
+			c.created_at ASC,
+			c.id ASC
+	`, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get workspace categories query for workspace_id=%s: %w", workspaceID, err)
+	}
+	categories, err := pgx.CollectRows(categoryRows, pgx.RowToStructByName[category.Category])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_categories for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	commentRows, err := db.Query(ctx, `
+		SELECT
+			c.*
+		FROM
+			todo_comments c
+			JOIN todos t ON t.id=c.todo_id
+		WHERE
+			t.workspace_id=@workspace_id
+		ORDER BY
+			c.created_at ASC,
+			c.id ASC
+	`, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get workspace comments query for workspace_id=%s: %w", workspaceID, err)
+	}
+	comments, err := pgx.CollectRows(commentRows, pgx.RowToStructByName[comment.Comment])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_comments for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	attachmentRows, err := db.Query(ctx, `
+		SELECT
+			a.*
+		FROM
+			todo_attachments a
+			JOIN todos t ON t.id=a.todo_id
+		WHERE
+			t.workspace_id=@workspace_id
+		ORDER BY
+			a.created_at ASC,
+			a.id ASC
+	`, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get workspace attachments query for workspace_id=%s: %w", workspaceID, err)
+	}
+	attachments, err := pgx.CollectRows(attachmentRows, pgx.RowToStructByName[todo.TodoAttachment])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_attachments for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	return &backup.Contents{
+		Categories:  categories,
+		Todos:       todos,
+		Comments:    comments,
+		Attachments: attachments,
+	}, nil
+}
+
+// RestoreCategory returns the category of the user a restored todo should use: the original
+// when it still exists, else one with the same name, else the original recreated
+func (r *BackupRepository) RestoreCategory(ctx context.Context, categoryItem *category.Category) (*category.Category, error) {
+	stmt := `
+		WITH
+			inserted AS (
+				INSERT INTO
+					todo_categories (id, user_id, name, color, description)
+				VALUES
+					(@id, @user_id, @name, @color, @description)
+				ON CONFLICT DO NOTHING
+				RETURNING
+					*
+			)
+		SELECT
+			*
+		FROM
+			inserted
+		UNION ALL
+		(
+			SELECT
+				*
+			FROM
+				todo_categories
+			WHERE
+				user_id=@user_id
+				AND (
+					id=@id
+					OR name=@name
+				)
+			ORDER BY
+				id=@id DESC
+			LIMIT
+				1
+		)
+		LIMIT
+			1
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"id":          categoryItem.ID,
+		"user_id":     categoryItem.UserID,
+		"name":        categoryItem.Name,
+		"color":       categoryItem.Color,
+		"description": categoryItem.Description,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute restore category query for category_id=%s: %w", categoryItem.ID.String(), err)
+	}
+
+	restored, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[category.Category])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todo_categories for category_id=%s: %w", categoryItem.ID.String(), err)
+	}
+
+	return &restored, nil
+}
+
+// InsertRestoredContents writes remapped contents, todos must come parents first. The
+// triggers keep counters, snapshots and change events as for any other write.
+func (r *BackupRepository) InsertRestoredContents(ctx context.Context, contents *backup.Contents) error {
+	batch := &pgx.Batch{}
+
+	for i := range contents.Todos {
+		item := &contents.Todos[i]
+		// The category is the fallback of a status the scales of the workspace no longer have
+		batch.Queue(`
+			INSERT INTO
+				todos (
+					id, created_at, user_id, workspace_id, title, description, priority, status, due_date,
+					completed_at, parent_todo_id, category_id, metadata, comments_read_at, status_category
+				)
+			VALUES
+				(
+					@id, @created_at, @user_id, @workspace_id, @title, @description, @priority, @status, @due_date,
+					@completed_at, @parent_todo_id, @category_id, @metadata, @comments_read_at,
+					COALESCE(NULLIF(@status_category, ''), 'draft')
+				)
+		`, pgx.NamedArgs{
+			"id":               item.ID,
+			"created_at":       item.CreatedAt,
+			"user_id":          item.UserID,
+			"workspace_id":     item.WorkspaceID,
+			"title":            item.Title,
+			"description":      item.Description,
+			"priority":         item.Priority,
+			"status":           item.Status,
+			"due_date":         item.DueDate,
+			"completed_at":     item.CompletedAt,
+			"parent_todo_id":   item.ParentTodoID,
+			"category_id":      item.CategoryID,
+			"metadata":         item.Metadata,
+			"comments_read_at": item.CommentsReadAt,
+			"status_category":  item.StatusCategory,
+		})
+	}
+
+	for i := range contents.Comments {
+		commentItem := &contents.Comments[i]
+		batch.Queue(`
+			INSERT INTO
+				todo_comments (id, created_at, todo_id, user_id, content)
+			VALUES
+				(@id, @created_at, @todo_id, @user_id, @content)
+		`, pgx.NamedArgs{
+			"id":         commentItem.ID,
+			"created_at": commentItem.CreatedAt,
+			"todo_id":    commentItem.TodoID,
+			"user_id":    commentItem.UserID,
+			"content":    commentItem.Content,
+		})
+	}
+
+	for i := range contents.Attachments {
+		attachment := &contents.Attachments[i]
+		batch.Queue(`
+			INSERT INTO
+				todo_attachments (
+					id, created_at, todo_id, name, uploaded_by, download_key, file_size, mime_type,
+					transcription_status, transcript, thumbnail_key, processed_at, scan_status, scan_signature,
+					scanned_at
+				)
+			VALUES
+				(
+					@id, @created_at, @todo_id, @name, @uploaded_by, @download_key, @file_size, @mime_type,
+					@transcription_status, @transcript, @thumbnail_key, @processed_at, @scan_status, @scan_signature,
+					@scanned_at
+				)
+		`, pgx.NamedArgs{
+			"id":           attachment.ID,
+			"created_at":   attachment.CreatedAt,
+			"todo_id":      attachment.TodoID,
+			"name":         attachment.Name,
+			"uploaded_by":  attachment.UploadedBy,
+			"download_key": attachment.DownloadKey,
+			"file_size":    attachment.FileSize,
+			"mime_type":    attachment.MimeType,
+
+			"transcription_status": attachment.TranscriptionStatus,
+			"transcript":           attachment.Transcript,
+			"thumbnail_key":        attachment.ThumbnailKey,
+			"processed_at":         attachment.ProcessedAt,
+			"scan_status":          attachment.ScanStatus,
+			"scan_signature":       attachment.ScanSignature,
+			"scanned_at":           attachment.ScannedAt,
+		})
+	}
+
+	if err := r.server.DB.Writer(ctx).SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to execute insert restored contents batch: %w", err)
+	}
+
+	return nil
+}
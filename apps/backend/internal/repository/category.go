@@ -43,7 +43,7 @@ func (r *CategoryRepository) CreateCategory(ctx context.Context, userID string,
 		*
 	`
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
 		"user_id":     userID,
 		"name":        payload.Name,
 		"color":       payload.Color,
@@ -72,7 +72,7 @@ func (r *CategoryRepository) GetCategoryByID(ctx context.Context, userID string,
 			AND user_id=@user_id
 	`
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
 		"id":      categoryID,
 		"user_id": userID,
 	})
@@ -88,6 +88,38 @@ func (r *CategoryRepository) GetCategoryByID(ctx context.Context, userID string,
 	return &categoryItem, nil
 }
 
+func (r *CategoryRepository) GetCategoriesByIDs(ctx context.Context, userID string,
+	categoryIDs []uuid.UUID,
+) ([]category.Category, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_categories
+		WHERE
+			id = ANY(@ids)
+			AND user_id=@user_id
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"ids":     categoryIDs,
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get categories by ids query for user_id=%s: %w", userID, err)
+	}
+
+	categories, err := pgx.CollectRows(rows, pgx.RowToStructByName[category.Category])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return []category.Category{}, nil
+		}
+		return nil, fmt.Errorf("failed to collect rows from table:todo_categories for user_id=%s: %w", userID, err)
+	}
+
+	return categories, nil
+}
+
 func (r *CategoryRepository) GetCategories(ctx context.Context, userID string,
 	query *category.GetCategoriesQuery,
 ) (*model.PaginatedResponse[category.Category], error) {
@@ -106,8 +138,7 @@ func (r *CategoryRepository) GetCategories(ctx context.Context, userID string,
 
 	// Add search filter if provided
 	if query.Search != nil {
-		stmt += ` AND name ILIKE '%' || @search || '%'`
-		args["search"] = *query.Search
+		stmt += " AND " + nameSearchCondition(r.server, args, "name", *query.Search)
 	}
 
 	// Add sorting
@@ -126,7 +157,7 @@ func (r *CategoryRepository) GetCategories(ctx context.Context, userID string,
 	args["limit"] = *query.Limit
 	args["offset"] = (*query.Page - 1) * (*query.Limit)
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute get categories query for user_id=%s: %w", userID, err)
 	}
@@ -160,12 +191,11 @@ func (r *CategoryRepository) GetCategories(ctx context.Context, userID string,
 	}
 
 	if query.Search != nil {
-		countStmt += ` AND name ILIKE '%' || @search || '%'`
-		countArgs["search"] = *query.Search
+		countStmt += " AND " + nameSearchCondition(r.server, countArgs, "name", *query.Search)
 	}
 
 	var total int
-	err = r.server.DB.Pool.QueryRow(ctx, countStmt, countArgs).Scan(&total)
+	err = r.server.DB.Reader(ctx).QueryRow(ctx, countStmt, countArgs).Scan(&total)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total count of categories for user_id=%s: %w", userID, err)
 	}
@@ -209,7 +239,7 @@ func (r *CategoryRepository) UpdateCategory(ctx context.Context, userID string,
 	stmt += strings.Join(setClauses, ", ")
 	stmt += ` WHERE id = @id AND user_id = @user_id RETURNING *`
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute update category query for category_id=%s user_id=%s: %w", categoryID.String(), userID, err)
 	}
@@ -222,21 +252,27 @@ func (r *CategoryRepository) UpdateCategory(ctx context.Context, userID string,
 	return &categoryItem, nil
 }
 
-func (r *CategoryRepository) DeleteCategory(ctx context.Context, userID string, categoryID uuid.UUID) error {
-	result, err := r.server.DB.Pool.Exec(ctx, `
+// DeleteCategory removes a category and returns it as it was
+func (r *CategoryRepository) DeleteCategory(ctx context.Context, userID string, categoryID uuid.UUID) (*category.Category, error) {
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, `
 		DELETE FROM todo_categories
 		WHERE id = @id AND user_id = @user_id
+		RETURNING *
 	`, pgx.NamedArgs{
 		"id":      categoryID,
 		"user_id": userID,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to delete category: %w", err)
+		return nil, fmt.Errorf("failed to delete category: %w", err)
 	}
 
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("category not found")
+	categoryItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[category.Category])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("category not found")
+		}
+		return nil, fmt.Errorf("failed to collect row from table:todo_categories for category_id=%s user_id=%s: %w", categoryID.String(), userID, err)
 	}
 
-	return nil
+	return &categoryItem, nil
 }
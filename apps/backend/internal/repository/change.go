@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/change"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type ChangeRepository struct {
+	server *server.Server
+}
+
+func NewChangeRepository(server *server.Server) *ChangeRepository {
+	return &ChangeRepository{server: server}
+}
+
+func (r *ChangeRepository) GetChangesAfter(ctx context.Context, userID string, afterID int64, limit int) ([]change.Change, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			change_events
+		WHERE
+			user_id = @user_id
+			AND id > @after_id
+		ORDER BY
+			id ASC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":  userID,
+		"after_id": afterID,
+		"limit":    limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get changes query for user_id=%s after_id=%d: %w", userID, afterID, err)
+	}
+
+	changes, err := pgx.CollectRows(rows, pgx.RowToStructByName[change.Change])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:change_events for user_id=%s: %w", userID, err)
+	}
+
+	return changes, nil
+}
+
+// GetOldestChangeID returns the id of the oldest retained event, 0 when the log is empty
+func (r *ChangeRepository) GetOldestChangeID(ctx context.Context) (int64, error) {
+	stmt := `
+		SELECT
+			COALESCE(MIN(id), 0)
+		FROM
+			change_events
+	`
+
+	var id int64
+	if err := r.server.DB.Writer(ctx).QueryRow(ctx, stmt).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to get oldest change id: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetLatestChangeIDBefore returns the id of the newest event recorded before the given time,
+// 0 when there is none
+func (r *ChangeRepository) GetLatestChangeIDBefore(ctx context.Context, before time.Time) (int64, error) {
+	stmt := `
+		SELECT
+			COALESCE(MAX(id), 0)
+		FROM
+			change_events
+		WHERE
+			created_at < @before
+	`
+
+	var id int64
+	if err := r.server.DB.Writer(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{"before": before}).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to get latest change id: %w", err)
+	}
+
+	return id, nil
+}
+
+// CRON REQUIREMENTS
+
+func (r *ChangeRepository) DeleteChangesOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	stmt := `
+		DELETE FROM change_events
+		WHERE
+			created_at < @cutoff
+	`
+
+	result, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"cutoff": cutoff,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete change events older than %s: %w", cutoff.Format(time.RFC3339), err)
+	}
+
+	return result.RowsAffected(), nil
+}
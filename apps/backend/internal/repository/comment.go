@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/Sameer16536/ExecuTask/internal/model/comment"
 	"github.com/Sameer16536/ExecuTask/internal/server"
@@ -38,7 +39,7 @@ func (r *CommentRepository) AddComment(ctx context.Context, userID string, todoI
 		*
 	`
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
 		"todo_id": todoID,
 		"user_id": userID,
 		"content": payload.Content,
@@ -68,7 +69,7 @@ func (r *CommentRepository) GetCommentsByTodoID(ctx context.Context, userID stri
 			created_at ASC
 	`
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
 		"todo_id": todoID,
 		"user_id": userID,
 	})
@@ -84,6 +85,95 @@ func (r *CommentRepository) GetCommentsByTodoID(ctx context.Context, userID stri
 	return comments, nil
 }
 
+// GetCommentsByTodoIDs loads the comments of several todos at once. With latestOnly
+// only the most recent comment of each todo is returned.
+func (r *CommentRepository) GetCommentsByTodoIDs(ctx context.Context, userID string, todoIDs []uuid.UUID,
+	latestOnly bool,
+) ([]comment.Comment, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_comments
+		WHERE
+			todo_id = ANY(@todo_ids)
+			AND user_id=@user_id
+		ORDER BY
+			created_at ASC
+	`
+
+	if latestOnly {
+		stmt = `
+		SELECT DISTINCT ON (todo_id)
+			*
+		FROM
+			todo_comments
+		WHERE
+			todo_id = ANY(@todo_ids)
+			AND user_id=@user_id
+		ORDER BY
+			todo_id,
+			created_at DESC
+	`
+	}
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"todo_ids": todoIDs,
+		"user_id":  userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get comments by todo ids query for user_id=%s: %w", userID, err)
+	}
+
+	comments, err := pgx.CollectRows(rows, pgx.RowToStructByName[comment.Comment])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_comments for user_id=%s: %w", userID, err)
+	}
+
+	return comments, nil
+}
+
+// GetCommentCounts counts the comments of several todos at once, todos without comments are absent
+func (r *CommentRepository) GetCommentCounts(ctx context.Context, userID string, todoIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	stmt := `
+		SELECT
+			todo_id,
+			COUNT(*)
+		FROM
+			todo_comments
+		WHERE
+			todo_id = ANY(@todo_ids)
+			AND user_id=@user_id
+		GROUP BY
+			todo_id
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"todo_ids": todoIDs,
+		"user_id":  userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get comment counts query for user_id=%s: %w", userID, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[uuid.UUID]int, len(todoIDs))
+	for rows.Next() {
+		var todoID uuid.UUID
+		var count int
+		if err := rows.Scan(&todoID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan comment count for user_id=%s: %w", userID, err)
+		}
+		counts[todoID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to collect comment counts from table:todo_comments for user_id=%s: %w", userID, err)
+	}
+
+	return counts, nil
+}
+
 func (r *CommentRepository) GetCommentByID(ctx context.Context, userID string, commentID uuid.UUID) (*comment.Comment, error) {
 	stmt := `
 		SELECT
@@ -95,7 +185,7 @@ func (r *CommentRepository) GetCommentByID(ctx context.Context, userID string, c
 			AND user_id=@user_id
 	`
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
 		"id":      commentID,
 		"user_id": userID,
 	})
@@ -124,7 +214,7 @@ func (r *CommentRepository) UpdateComment(ctx context.Context, userID string, co
 		*
 	`
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
 		"id":      commentID,
 		"user_id": userID,
 		"content": content,
@@ -142,7 +232,7 @@ func (r *CommentRepository) UpdateComment(ctx context.Context, userID string, co
 }
 
 func (r *CommentRepository) DeleteComment(ctx context.Context, userID string, commentID uuid.UUID) error {
-	result, err := r.server.DB.Pool.Exec(ctx, `
+	result, err := r.server.DB.Writer(ctx).Exec(ctx, `
 		DELETE FROM todo_comments
 		WHERE id = @id AND user_id = @user_id
 	`, pgx.NamedArgs{
@@ -159,3 +249,68 @@ func (r *CommentRepository) DeleteComment(ctx context.Context, userID string, co
 
 	return nil
 }
+
+func (r *CommentRepository) CreateReplyToken(ctx context.Context, token *comment.ReplyToken) error {
+	stmt := `
+		INSERT INTO
+			comment_reply_tokens (token, expires_at, user_id, todo_id)
+		VALUES
+			(@token, @expires_at, @user_id, @todo_id)
+	`
+
+	_, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"token":      token.Token,
+		"expires_at": token.ExpiresAt,
+		"user_id":    token.UserID,
+		"todo_id":    token.TodoID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute create reply token query for todo_id=%s: %w", token.TodoID.String(), err)
+	}
+
+	return nil
+}
+
+// GetReplyToken returns a token that hasn't expired, pgx.ErrNoRows when there is none
+func (r *CommentRepository) GetReplyToken(ctx context.Context, token string) (*comment.ReplyToken, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			comment_reply_tokens
+		WHERE
+			token=@token
+			AND expires_at>NOW()
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"token": token,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get reply token query: %w", err)
+	}
+
+	replyToken, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[comment.ReplyToken])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:comment_reply_tokens: %w", err)
+	}
+
+	return &replyToken, nil
+}
+
+func (r *CommentRepository) DeleteExpiredReplyTokens(ctx context.Context, now time.Time) (int64, error) {
+	stmt := `
+		DELETE FROM comment_reply_tokens
+		WHERE
+			expires_at <= @now
+	`
+
+	result, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"now": now,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete reply tokens expired by %s: %w", now.Format(time.RFC3339), err)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/cover"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type CoverRepository struct {
+	server *server.Server
+}
+
+func NewCoverRepository(server *server.Server) *CoverRepository {
+	return &CoverRepository{server: server}
+}
+
+func (r *CoverRepository) GetCover(ctx context.Context, userID string, todoID uuid.UUID) (*cover.Cover, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_covers
+		WHERE
+			todo_id = @todo_id
+			AND user_id = @user_id
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id": todoID,
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get cover query for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	item, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[cover.Cover])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeCoverNotFound
+			return nil, errs.NewNotFoundError("the todo has no cover", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:todo_covers for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	return &item, nil
+}
+
+// SetCover stores the cover of a todo in place of the one it had, as pending and without
+// thumbnails
+func (r *CoverRepository) SetCover(ctx context.Context, item *cover.Cover) (*cover.Cover, error) {
+	stmt := `
+		INSERT INTO
+			todo_covers (
+				todo_id,
+				user_id,
+				source,
+				attachment_id,
+				stock_key,
+				status,
+				generation
+			)
+		VALUES
+			(
+				@todo_id,
+				@user_id,
+				@source,
+				@attachment_id,
+				@stock_key,
+				@status,
+				@generation
+			)
+		ON CONFLICT (todo_id) DO UPDATE
+		SET
+			source = EXCLUDED.source,
+			attachment_id = EXCLUDED.attachment_id,
+			stock_key = EXCLUDED.stock_key,
+			status = EXCLUDED.status,
+			generation = EXCLUDED.generation,
+			variant_keys = '{}'
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id":       item.TodoID,
+		"user_id":       item.UserID,
+		"source":        item.Source,
+		"attachment_id": item.AttachmentID,
+		"stock_key":     item.StockKey,
+		"status":        cover.StatusPending,
+		"generation":    item.Generation,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute set cover query for todo_id=%s: %w", item.TodoID.String(), err)
+	}
+
+	saved, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[cover.Cover])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todo_covers for todo_id=%s: %w", item.TodoID.String(), err)
+	}
+
+	return &saved, nil
+}
+
+// SaveVariants stores the outcome of a render. It reports false when the cover was replaced or
+// removed since the generation was rendered, the thumbnails belong to nothing then.
+func (r *CoverRepository) SaveVariants(ctx context.Context, todoID, generation uuid.UUID, status cover.Status,
+	variantKeys map[string]string,
+) (bool, error) {
+	stmt := `
+		UPDATE todo_covers
+		SET
+			status = @status,
+			variant_keys = @variant_keys
+		WHERE
+			todo_id = @todo_id
+			AND generation = @generation
+	`
+
+	if variantKeys == nil {
+		variantKeys = map[string]string{}
+	}
+
+	tag, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"todo_id":      todoID,
+		"generation":   generation,
+		"status":       status,
+		"variant_keys": variantKeys,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to execute save cover variants query for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// DeleteCover removes the cover of a todo and returns it, its thumbnails are left to the caller
+func (r *CoverRepository) DeleteCover(ctx context.Context, userID string, todoID uuid.UUID) (*cover.Cover, error) {
+	stmt := `
+		DELETE FROM todo_covers
+		WHERE
+			todo_id = @todo_id
+			AND user_id = @user_id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id": todoID,
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute delete cover query for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	deleted, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[cover.Cover])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeCoverNotFound
+			return nil, errs.NewNotFoundError("the todo has no cover", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:todo_covers for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	return &deleted, nil
+}
+
+// GetLiveGenerations returns which of the generations are those of a current cover, the
+// thumbnails of the others can go
+func (r *CoverRepository) GetLiveGenerations(ctx context.Context, generations []uuid.UUID) ([]uuid.UUID, error) {
+	stmt := `
+		SELECT
+			generation
+		FROM
+			todo_covers
+		WHERE
+			generation = ANY (@generations)
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"generations": generations,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get live cover generations query: %w", err)
+	}
+
+	live, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_covers: %w", err)
+	}
+
+	return live, nil
+}
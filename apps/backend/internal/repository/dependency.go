@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/dependency"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type DependencyRepository struct {
+	server *server.Server
+}
+
+func NewDependencyRepository(server *server.Server) *DependencyRepository {
+	return &DependencyRepository{server: server}
+}
+
+// AddDependency links a todo to a todo it depends on, both must belong to the user
+func (r *DependencyRepository) AddDependency(ctx context.Context, userID string, todoID, dependsOnID uuid.UUID,
+) (*dependency.Dependency, error) {
+	stmt := `
+		INSERT INTO
+			todo_dependencies (user_id, todo_id, depends_on_id)
+		VALUES
+			(@user_id, @todo_id, @depends_on_id)
+		ON CONFLICT DO NOTHING
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":       userID,
+		"todo_id":       todoID,
+		"depends_on_id": dependsOnID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute add dependency query for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	link, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[dependency.Dependency])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeDependencyExists
+			return nil, errs.NewConflictError("the todo already depends on that todo", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:todo_dependencies for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	return &link, nil
+}
+
+func (r *DependencyRepository) RemoveDependency(ctx context.Context, userID string, todoID, dependsOnID uuid.UUID) error {
+	stmt := `
+		DELETE FROM todo_dependencies
+		WHERE
+			user_id = @user_id
+			AND todo_id = @todo_id
+			AND depends_on_id = @depends_on_id
+	`
+
+	result, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"user_id":       userID,
+		"todo_id":       todoID,
+		"depends_on_id": dependsOnID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute remove dependency query for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	if result.RowsAffected() == 0 {
+		code := errs.CodeDependencyNotFound
+		return errs.NewNotFoundError("the todo doesn't depend on that todo", false, &code)
+	}
+
+	return nil
+}
+
+// GetBlockedBy returns the todos the todo depends on, soonest due first
+func (r *DependencyRepository) GetBlockedBy(ctx context.Context, userID string, todoID uuid.UUID) ([]todo.Todo, error) {
+	return r.linkedTodos(ctx, userID, todoID, `
+		SELECT
+			t.*
+		FROM
+			todo_dependencies d
+			JOIN todos t ON t.id = d.depends_on_id
+		WHERE
+			d.user_id = @user_id
+			AND d.todo_id = @todo_id
+		ORDER BY
+			t.due_date ASC NULLS LAST,
+			t.created_at ASC
+	`)
+}
+
+// GetBlocking returns the todos depending on the todo, soonest due first
+func (r *DependencyRepository) GetBlocking(ctx context.Context, userID string, todoID uuid.UUID) ([]todo.Todo, error) {
+	return r.linkedTodos(ctx, userID, todoID, `
+		SELECT
+			t.*
+		FROM
+			todo_dependencies d
+			JOIN todos t ON t.id = d.todo_id
+		WHERE
+			d.user_id = @user_id
+			AND d.depends_on_id = @todo_id
+		ORDER BY
+			t.due_date ASC NULLS LAST,
+			t.created_at ASC
+	`)
+}
+
+func (r *DependencyRepository) linkedTodos(ctx context.Context, userID string, todoID uuid.UUID, stmt string,
+) ([]todo.Todo, error) {
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"todo_id": todoID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get dependencies query for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	todos, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	return todos, nil
+}
+
+// GetDownstreamDependencies returns every link reachable from the todo to the todos it blocks,
+// directly or through others. Reads go to the primary as callers write based on the result.
+func (r *DependencyRepository) GetDownstreamDependencies(ctx context.Context, userID string, todoID uuid.UUID,
+) ([]dependency.Dependency, error) {
+	stmt := `
+		WITH RECURSIVE
+			downstream AS (
+				SELECT
+					*
+				FROM
+					todo_dependencies
+				WHERE
+					user_id = @user_id
+					AND depends_on_id = @todo_id
+				UNION
+				SELECT
+					d.*
+				FROM
+					todo_dependencies d
+					JOIN downstream ON d.depends_on_id = downstream.todo_id
+				WHERE
+					d.user_id = @user_id
+			)
+		SELECT
+			*
+		FROM
+			downstream
+		ORDER BY
+			created_at ASC
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"todo_id": todoID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get downstream dependencies query for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	links, err := pgx.CollectRows(rows, pgx.RowToStructByName[dependency.Dependency])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_dependencies for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	return links, nil
+}
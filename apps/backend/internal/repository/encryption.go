@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/aws"
+	"github.com/Sameer16536/ExecuTask/internal/lib/envelope"
+	"github.com/Sameer16536/ExecuTask/internal/model/encryption"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// sealedColumn is a text column whose values the repositories seal with the keyring on write
+// and open on read. keys are the primary key columns of its table.
+type sealedColumn struct {
+	table  string
+	column string
+	keys   []string
+}
+
+// sealedColumns are moved to the active data key by ReencryptColumns, which also seals the
+// plaintext values stored before a column was listed. Rewriting a value fires the update
+// triggers of its table.
+var sealedColumns = []sealedColumn{
+	{table: "todo_private_notes", column: "body", keys: []string{"todo_id", "user_id"}},
+	{table: "webhook_endpoints", column: "secret", keys: []string{"id"}},
+}
+
+type EncryptionRepository struct {
+	server *server.Server
+}
+
+func NewEncryptionRepository(server *server.Server) *EncryptionRepository {
+	return &EncryptionRepository{server: server}
+}
+
+// NewKeyring wraps data keys with the configured KMS key or local master key. Without either
+// the keyring can't seal values, see envelope.ErrNotConfigured.
+func NewKeyring(s *server.Server, store envelope.KeyStore) (*envelope.Keyring, error) {
+	cfg := s.Config.Encryption
+
+	switch {
+	case cfg.KMSKeyID != "":
+		return envelope.NewKeyring(aws.NewKMSClient(s, cfg.KMSKeyID, cfg.KMSEndpointURL), store), nil
+	case cfg.MasterKey != "":
+		wrapper, err := envelope.NewLocalWrapper(cfg.MasterKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption master key: %w", err)
+		}
+		return envelope.NewKeyring(wrapper, store), nil
+	default:
+		return envelope.NewKeyring(nil, store), nil
+	}
+}
+
+// GetActiveDataKey returns nil before the first key is created
+func (r *EncryptionRepository) GetActiveDataKey(ctx context.Context) (*encryption.DataKey, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			data_keys
+		WHERE
+			retired_at IS NULL
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get active data key query: %w", err)
+	}
+
+	dataKey, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[encryption.DataKey])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to collect row from table:data_keys: %w", err)
+	}
+
+	return &dataKey, nil
+}
+
+func (r *EncryptionRepository) GetDataKey(ctx context.Context, id uuid.UUID) (*encryption.DataKey, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			data_keys
+		WHERE
+			id=@id
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"id": id,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get data key query for id=%s: %w", id.String(), err)
+	}
+
+	dataKey, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[encryption.DataKey])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:data_keys for id=%s: %w", id.String(), err)
+	}
+
+	return &dataKey, nil
+}
+
+// CreateDataKey stores a key as the active one and retires the previous. Concurrent callers
+// fail on data_keys_unique_active, all but one.
+func (r *EncryptionRepository) CreateDataKey(ctx context.Context, wrapped []byte, masterKeyID string,
+) (*encryption.DataKey, error) {
+	stmt := `
+		WITH
+			retired AS (
+				UPDATE data_keys
+				SET
+					retired_at=CURRENT_TIMESTAMP
+				WHERE
+					retired_at IS NULL
+			)
+		INSERT INTO
+			data_keys (wrapped_key, master_key_id)
+		VALUES
+			(@wrapped_key, @master_key_id)
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"wrapped_key":   wrapped,
+		"master_key_id": masterKeyID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data key: %w", err)
+	}
+
+	dataKey, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[encryption.DataKey])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:data_keys: %w", err)
+	}
+
+	return &dataKey, nil
+}
+
+// ReencryptColumns seals up to limit values of every sealed column with the active data key,
+// returning how many it rewrote. A value edited meanwhile is left for the next call.
+func (r *EncryptionRepository) ReencryptColumns(ctx context.Context, keyring *envelope.Keyring, limit int) (int64, error) {
+	if len(sealedColumns) == 0 {
+		return 0, nil
+	}
+
+	activeID, err := keyring.ActiveKeyID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var rewritten int64
+	for _, column := range sealedColumns {
+		count, err := r.reencryptColumn(ctx, keyring, column, activeID, limit)
+		if err != nil {
+			return rewritten, err
+		}
+		rewritten += count
+	}
+
+	return rewritten, nil
+}
+
+func (r *EncryptionRepository) reencryptColumn(ctx context.Context, keyring *envelope.Keyring, column sealedColumn,
+	activeID uuid.UUID, limit int,
+) (int64, error) {
+	table := pgx.Identifier{column.table}.Sanitize()
+	name := pgx.Identifier{column.column}.Sanitize()
+
+	// The keys are read as text and compared as parameters, which Postgres casts back
+	keys := make([]string, len(column.keys))
+	matches := make([]string, len(column.keys))
+	for i, key := range column.keys {
+		keys[i] = pgx.Identifier{key}.Sanitize() + "::text"
+		matches[i] = fmt.Sprintf("%s=@key_%d", pgx.Identifier{key}.Sanitize(), i)
+	}
+
+	stmt := fmt.Sprintf(`
+		SELECT
+			%[3]s,
+			%[2]s
+		FROM
+			%[1]s
+		WHERE
+			%[2]s IS NOT NULL
+			AND %[2]s NOT LIKE @active_prefix
+		LIMIT
+			@limit
+	`, table, name, strings.Join(keys, ", "))
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"active_prefix": "enc:v1:" + activeID.String() + ":%",
+		"limit":         limit,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to select values of %s.%s to encrypt: %w", column.table, column.column, err)
+	}
+
+	type sealedValue struct {
+		Keys  []string
+		Value string
+	}
+	values, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (sealedValue, error) {
+		value := sealedValue{Keys: make([]string, len(column.keys))}
+		dest := make([]any, 0, len(column.keys)+1)
+		for i := range value.Keys {
+			dest = append(dest, &value.Keys[i])
+		}
+		return value, row.Scan(append(dest, &value.Value)...)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to collect rows from table:%s: %w", column.table, err)
+	}
+
+	update := fmt.Sprintf(`
+		UPDATE %[1]s
+		SET
+			%[2]s=@sealed
+		WHERE
+			%[3]s
+			AND %[2]s=@previous
+	`, table, name, strings.Join(matches, "\n\t\t\tAND "))
+
+	var rewritten int64
+	for _, value := range values {
+		plaintext, err := keyring.Decrypt(ctx, value.Value)
+		if err != nil {
+			return rewritten, fmt.Errorf("failed to decrypt %s.%s of %s: %w", column.table, column.column,
+				column.describe(value.Keys), err)
+		}
+
+		sealed, err := keyring.Encrypt(ctx, plaintext)
+		if err != nil {
+			return rewritten, err
+		}
+
+		args := pgx.NamedArgs{
+			"sealed":   sealed,
+			"previous": value.Value,
+		}
+		for i, key := range value.Keys {
+			args[fmt.Sprintf("key_%d", i)] = key
+		}
+
+		result, err := r.server.DB.Writer(ctx).Exec(ctx, update, args)
+		if err != nil {
+			return rewritten, fmt.Errorf("failed to update %s.%s of %s: %w", column.table, column.column,
+				column.describe(value.Keys), err)
+		}
+		rewritten += result.RowsAffected()
+	}
+
+	return rewritten, nil
+}
+
+// describe names the row of the key values for errors, e.g. todo_id=... user_id=...
+func (c sealedColumn) describe(values []string) string {
+	parts := make([]string, len(c.keys))
+	for i, key := range c.keys {
+		parts[i] = key + "=" + values[i]
+	}
+	return strings.Join(parts, " ")
+}
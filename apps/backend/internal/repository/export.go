@@ -0,0 +1,449 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/envelope"
+	"github.com/Sameer16536/ExecuTask/internal/model/category"
+	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/export"
+	"github.com/Sameer16536/ExecuTask/internal/model/note"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type ExportRepository struct {
+	server  *server.Server
+	keyring *envelope.Keyring
+}
+
+func NewExportRepository(server *server.Server, keyring *envelope.Keyring) *ExportRepository {
+	return &ExportRepository{server: server, keyring: keyring}
+}
+
+// CreateAccountExport queues a new export, a user can't have two in progress at once
+func (r *ExportRepository) CreateAccountExport(ctx context.Context, userID string) (*export.AccountExport, error) {
+	stmt := `
+		INSERT INTO
+			account_exports (user_id)
+		VALUES
+			(@user_id)
+		ON CONFLICT (user_id, kind)
+		WHERE
+			status IN ('pending', 'running') DO NOTHING
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create account export query for user_id=%s: %w", userID, err)
+	}
+
+	exportItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[export.AccountExport])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeExportInProgress
+			return nil, errs.NewConflictError("an account export is already in progress", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:account_exports for user_id=%s: %w", userID, err)
+	}
+
+	return &exportItem, nil
+}
+
+// CreateTodoListExport queues a PDF of the todos matching the filters, a user can't have two
+// in progress at once
+func (r *ExportRepository) CreateTodoListExport(ctx context.Context, userID string, filters *todo.ExportTodosPDFQuery,
+) (*export.AccountExport, error) {
+	stmt := `
+		INSERT INTO
+			account_exports (user_id, kind, filters)
+		VALUES
+			(@user_id, @kind, @filters)
+		ON CONFLICT (user_id, kind)
+		WHERE
+			status IN ('pending', 'running') DO NOTHING
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"kind":    export.KindTodoList,
+		"filters": filters,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create todo list export query for user_id=%s: %w", userID, err)
+	}
+
+	exportItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[export.AccountExport])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeExportInProgress
+			return nil, errs.NewConflictError("a todo list export is already in progress", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:account_exports for user_id=%s: %w", userID, err)
+	}
+
+	return &exportItem, nil
+}
+
+func (r *ExportRepository) GetAccountExport(ctx context.Context, userID string, exportID uuid.UUID) (*export.AccountExport, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			account_exports
+		WHERE
+			id=@id
+			AND user_id=@user_id
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"id":      exportID,
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get account export query for export_id=%s user_id=%s: %w", exportID.String(), userID, err)
+	}
+
+	exportItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[export.AccountExport])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeExportNotFound
+			return nil, errs.NewNotFoundError("account export not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:account_exports for export_id=%s user_id=%s: %w", exportID.String(), userID, err)
+	}
+
+	return &exportItem, nil
+}
+
+// StartAccountExport moves a pending export to running. An export already running was picked
+// up by an attempt that died, it is claimed again. Finished exports are returned unchanged.
+func (r *ExportRepository) StartAccountExport(ctx context.Context, exportID uuid.UUID) (*export.AccountExport, error) {
+	stmt := `
+		UPDATE account_exports
+		SET
+			status=CASE
+				WHEN status IN ('pending', 'running') THEN 'running'
+				ELSE status
+			END
+		WHERE
+			id=@id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"id": exportID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute start account export query for export_id=%s: %w", exportID.String(), err)
+	}
+
+	exportItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[export.AccountExport])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeExportNotFound
+			return nil, errs.NewNotFoundError("account export not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:account_exports for export_id=%s: %w", exportID.String(), err)
+	}
+
+	return &exportItem, nil
+}
+
+func (r *ExportRepository) CompleteAccountExport(ctx context.Context, exportID uuid.UUID, objectKey string,
+	sizeBytes int64, expiresAt time.Time,
+) (*export.AccountExport, error) {
+	stmt := `
+		UPDATE account_exports
+		SET
+			status='completed',
+			object_key=@object_key,
+			size_bytes=@size_bytes,
+			error=NULL,
+			completed_at=NOW(),
+			expires_at=@expires_at
+		WHERE
+			id=@id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"id":         exportID,
+		"object_key": objectKey,
+		"size_bytes": sizeBytes,
+		"expires_at": expiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute complete account export query for export_id=%s: %w", exportID.String(), err)
+	}
+
+	exportItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[export.AccountExport])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:account_exports for export_id=%s: %w", exportID.String(), err)
+	}
+
+	return &exportItem, nil
+}
+
+// CreateReportExport stores a run of a scheduled report as a completed export, it is deleted with
+// the other exports once expiresAt passes
+func (r *ExportRepository) CreateReportExport(ctx context.Context, userID string, filters *todo.ExportTodosPDFQuery,
+	objectKey string, sizeBytes int64, expiresAt time.Time,
+) (*export.AccountExport, error) {
+	stmt := `
+		INSERT INTO
+			account_exports (
+				user_id,
+				kind,
+				status,
+				filters,
+				object_key,
+				size_bytes,
+				completed_at,
+				expires_at
+			)
+		VALUES
+			(
+				@user_id,
+				@kind,
+				'completed',
+				@filters,
+				@object_key,
+				@size_bytes,
+				NOW(),
+				@expires_at
+			)
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":    userID,
+		"kind":       export.KindReport,
+		"filters":    filters,
+		"object_key": objectKey,
+		"size_bytes": sizeBytes,
+		"expires_at": expiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create report export query for user_id=%s: %w", userID, err)
+	}
+
+	exportItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[export.AccountExport])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:account_exports for user_id=%s: %w", userID, err)
+	}
+
+	return &exportItem, nil
+}
+
+// FailAccountExport gives up on an export that is still in progress
+func (r *ExportRepository) FailAccountExport(ctx context.Context, exportID uuid.UUID, reason string) error {
+	stmt := `
+		UPDATE account_exports
+		SET
+			status='failed',
+			error=@error
+		WHERE
+			id=@id
+			AND status IN ('pending', 'running')
+	`
+
+	_, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"id":    exportID,
+		"error": reason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute fail account export query for export_id=%s: %w", exportID.String(), err)
+	}
+
+	return nil
+}
+
+// GetExpiredAccountExports returns completed exports past their expiry whose archive is still stored
+func (r *ExportRepository) GetExpiredAccountExports(ctx context.Context, now time.Time, limit int) ([]export.AccountExport, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			account_exports
+		WHERE
+			status='completed'
+			AND object_key IS NOT NULL
+			AND expires_at<=@now
+		ORDER BY
+			expires_at ASC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"now":   now,
+		"limit": limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get expired account exports query: %w", err)
+	}
+
+	exports, err := pgx.CollectRows(rows, pgx.RowToStructByName[export.AccountExport])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:account_exports: %w", err)
+	}
+
+	return exports, nil
+}
+
+// ClearAccountExportObject forgets the archive of an export once it is deleted from S3
+func (r *ExportRepository) ClearAccountExportObject(ctx context.Context, exportID uuid.UUID) error {
+	stmt := `
+		UPDATE account_exports
+		SET
+			object_key=NULL
+		WHERE
+			id=@id
+	`
+
+	_, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"id": exportID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute clear account export object query for export_id=%s: %w", exportID.String(), err)
+	}
+
+	return nil
+}
+
+func (r *ExportRepository) GetCategoriesForUser(ctx context.Context, userID string) ([]category.Category, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_categories
+		WHERE
+			user_id=@user_id
+		ORDER BY
+			created_at ASC,
+			id ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get categories for user query for user_id=%s: %w", userID, err)
+	}
+
+	categories, err := pgx.CollectRows(rows, pgx.RowToStructByName[category.Category])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_categories for user_id=%s: %w", userID, err)
+	}
+
+	return categories, nil
+}
+
+func (r *ExportRepository) GetCommentsForUser(ctx context.Context, userID string) ([]comment.Comment, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_comments
+		WHERE
+			user_id=@user_id
+		ORDER BY
+			created_at ASC,
+			id ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get comments for user query for user_id=%s: %w", userID, err)
+	}
+
+	comments, err := pgx.CollectRows(rows, pgx.RowToStructByName[comment.Comment])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_comments for user_id=%s: %w", userID, err)
+	}
+
+	return comments, nil
+}
+
+// GetAttachmentsForUser returns the attachments of every todo the user owns
+func (r *ExportRepository) GetAttachmentsForUser(ctx context.Context, userID string) ([]todo.TodoAttachment, error) {
+	stmt := `
+		SELECT
+			a.*
+		FROM
+			todo_attachments a
+			JOIN todos t ON t.id=a.todo_id
+		WHERE
+			t.user_id=@user_id
+		ORDER BY
+			a.created_at ASC,
+			a.id ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get attachments for user query for user_id=%s: %w", userID, err)
+	}
+
+	attachments, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.TodoAttachment])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_attachments for user_id=%s: %w", userID, err)
+	}
+
+	return attachments, nil
+}
+
+// GetNotesForUser returns the private notes the user wrote
+func (r *ExportRepository) GetNotesForUser(ctx context.Context, userID string) ([]note.Note, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_private_notes
+		WHERE
+			user_id=@user_id
+		ORDER BY
+			created_at ASC,
+			todo_id ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get private notes for user query for user_id=%s: %w", userID, err)
+	}
+
+	notes, err := pgx.CollectRows(rows, pgx.RowToStructByName[note.Note])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_private_notes for user_id=%s: %w", userID, err)
+	}
+
+	for i := range notes {
+		if err := openNote(ctx, r.keyring, &notes[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return notes, nil
+}
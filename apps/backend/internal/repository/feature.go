@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/feature"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type FeatureRepository struct {
+	server *server.Server
+}
+
+func NewFeatureRepository(server *server.Server) *FeatureRepository {
+	return &FeatureRepository{server: server}
+}
+
+// GetOverridesFor returns the overrides that apply to a user: their own, their workspace's and
+// the global ones. workspaceID may be empty.
+func (r *FeatureRepository) GetOverridesFor(ctx context.Context, userID, workspaceID string) ([]feature.Override, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			feature_flag_overrides
+		WHERE
+			(
+				scope = 'user'
+				AND subject_id = @user_id
+			)
+			OR (
+				scope = 'workspace'
+				AND subject_id = @workspace_id
+				AND @workspace_id <> ''
+			)
+			OR scope = 'global'
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":      userID,
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get feature overrides query for user_id=%s: %w", userID, err)
+	}
+
+	overrides, err := pgx.CollectRows(rows, pgx.RowToStructByName[feature.Override])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:feature_flag_overrides for user_id=%s: %w", userID, err)
+	}
+
+	return overrides, nil
+}
+
+func (r *FeatureRepository) GetOverrides(ctx context.Context) ([]feature.Override, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			feature_flag_overrides
+		ORDER BY
+			flag ASC,
+			scope ASC,
+			subject_id ASC
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get feature overrides query: %w", err)
+	}
+
+	overrides, err := pgx.CollectRows(rows, pgx.RowToStructByName[feature.Override])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:feature_flag_overrides: %w", err)
+	}
+
+	return overrides, nil
+}
+
+func (r *FeatureRepository) SetOverride(ctx context.Context, updatedBy string,
+	payload *feature.SetOverridePayload,
+) (*feature.Override, error) {
+	stmt := `
+		INSERT INTO
+			feature_flag_overrides (flag, scope, subject_id, enabled, updated_by)
+		VALUES
+			(@flag, @scope, @subject_id, @enabled, @updated_by)
+		ON CONFLICT (flag, scope, subject_id) DO UPDATE
+		SET
+			enabled = EXCLUDED.enabled,
+			updated_by = EXCLUDED.updated_by
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"flag":       payload.Flag,
+		"scope":      payload.Scope,
+		"subject_id": payload.SubjectID,
+		"enabled":    *payload.Enabled,
+		"updated_by": updatedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute set feature override query for flag=%s: %w", payload.Flag, err)
+	}
+
+	override, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[feature.Override])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:feature_flag_overrides for flag=%s: %w", payload.Flag, err)
+	}
+
+	return &override, nil
+}
+
+// DeleteOverride removes an override and returns it as it was
+func (r *FeatureRepository) DeleteOverride(ctx context.Context, payload *feature.DeleteOverridePayload) (*feature.Override, error) {
+	stmt := `
+		DELETE FROM feature_flag_overrides
+		WHERE
+			flag = @flag
+			AND scope = @scope
+			AND subject_id = @subject_id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"flag":       payload.Flag,
+		"scope":      payload.Scope,
+		"subject_id": payload.SubjectID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute delete feature override query for flag=%s: %w", payload.Flag, err)
+	}
+
+	override, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[feature.Override])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeFeatureOverrideNotFound
+			return nil, errs.NewNotFoundError("feature flag override not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:feature_flag_overrides for flag=%s: %w", payload.Flag, err)
+	}
+
+	return &override, nil
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/focus"
+	"github.com/Sameer16536/ExecuTask/internal/model/planner"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type FocusRepository struct {
+	server *server.Server
+}
+
+func NewFocusRepository(server *server.Server) *FocusRepository {
+	return &FocusRepository{server: server}
+}
+
+// StartSession opens an active session on a todo, a user can't have two sessions open at once
+func (r *FocusRepository) StartSession(ctx context.Context, userID string,
+	payload *focus.StartSessionPayload,
+) (*focus.Session, error) {
+	stmt := `
+		INSERT INTO
+			focus_sessions (user_id, todo_id, planned_minutes)
+		VALUES
+			(@user_id, @todo_id, @planned_minutes)
+		ON CONFLICT (user_id)
+		WHERE
+			status IN ('active', 'paused') DO NOTHING
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":         userID,
+		"todo_id":         payload.TodoID,
+		"planned_minutes": *payload.PlannedMinutes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute start focus session query for user_id=%s: %w", userID, err)
+	}
+
+	session, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[focus.Session])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeFocusSessionOpen
+			return nil, errs.NewConflictError("another focus session is still open", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:focus_sessions for user_id=%s: %w", userID, err)
+	}
+
+	return &session, nil
+}
+
+func (r *FocusRepository) GetSession(ctx context.Context, userID string, sessionID uuid.UUID) (*focus.Session, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			focus_sessions
+		WHERE
+			id=@id
+			AND user_id=@user_id
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"id":      sessionID,
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get focus session query for session_id=%s: %w", sessionID.String(), err)
+	}
+
+	session, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[focus.Session])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeFocusSessionNotFound
+			return nil, errs.NewNotFoundError("focus session not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:focus_sessions for session_id=%s: %w", sessionID.String(), err)
+	}
+
+	return &session, nil
+}
+
+// GetOpenSession returns the active or paused session of the user
+func (r *FocusRepository) GetOpenSession(ctx context.Context, userID string) (*focus.Session, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			focus_sessions
+		WHERE
+			user_id=@user_id
+			AND status IN ('active', 'paused')
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get open focus session query for user_id=%s: %w", userID, err)
+	}
+
+	session, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[focus.Session])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeFocusSessionNotFound
+			return nil, errs.NewNotFoundError("no focus session is open", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:focus_sessions for user_id=%s: %w", userID, err)
+	}
+
+	return &session, nil
+}
+
+// GetSessions lists the latest sessions of the user, of one todo when the query names it, newest first
+func (r *FocusRepository) GetSessions(ctx context.Context, userID string, query *focus.GetSessionsQuery) ([]focus.Session, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			focus_sessions
+		WHERE
+			user_id=@user_id
+			AND (
+				@todo_id::UUID IS NULL
+				OR todo_id=@todo_id
+			)
+		ORDER BY
+			created_at DESC,
+			id DESC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"todo_id": query.TodoID,
+		"limit":   *query.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get focus sessions query for user_id=%s: %w", userID, err)
+	}
+
+	sessions, err := pgx.CollectRows(rows, pgx.RowToStructByName[focus.Session])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:focus_sessions for user_id=%s: %w", userID, err)
+	}
+
+	return sessions, nil
+}
+
+// UpdateSession saves the state of a session that was in the from status, a session another
+// request moved on meanwhile is a conflict
+func (r *FocusRepository) UpdateSession(ctx context.Context, session *focus.Session,
+	from focus.Status,
+) (*focus.Session, error) {
+	stmt := `
+		UPDATE focus_sessions
+		SET
+			status=@status,
+			focused_seconds=@focused_seconds,
+			resumed_at=@resumed_at,
+			completed_at=@completed_at
+		WHERE
+			id=@id
+			AND user_id=@user_id
+			AND status=@from
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"id":              session.ID,
+		"user_id":         session.UserID,
+		"from":            from,
+		"status":          session.Status,
+		"focused_seconds": session.FocusedSeconds,
+		"resumed_at":      session.ResumedAt,
+		"completed_at":    session.CompletedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute update focus session query for session_id=%s: %w", session.ID.String(), err)
+	}
+
+	updated, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[focus.Session])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeFocusSessionState
+			return nil, errs.NewConflictError("the focus session changed meanwhile", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:focus_sessions for session_id=%s: %w", session.ID.String(), err)
+	}
+
+	return &updated, nil
+}
+
+// GetEstimateSamples returns the latest completed todos of the user with an estimate and the
+// time focused on them in completed sessions, todos nobody tracked time on are left out
+func (r *FocusRepository) GetEstimateSamples(ctx context.Context, userID string, limit int,
+) ([]planner.EstimateSample, error) {
+	stmt := `
+		SELECT
+			t.id AS todo_id,
+			(t.metadata ->> 'estimatedMinutes')::INT AS estimated_minutes,
+			SUM(f.focused_seconds)::INT AS actual_seconds
+		FROM
+			todos t
+			JOIN focus_sessions f ON f.todo_id = t.id
+			AND f.user_id = t.user_id
+			AND f.status = 'completed'
+		WHERE
+			t.user_id = @user_id
+			AND t.status_category = 'completed'
+			AND t.completed_at IS NOT NULL
+			AND t.metadata ->> 'estimatedMinutes' IS NOT NULL
+		GROUP BY
+			t.id
+		HAVING
+			SUM(f.focused_seconds) > 0
+		ORDER BY
+			t.completed_at DESC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"limit":   limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get estimate samples query for user_id=%s: %w", userID, err)
+	}
+
+	samples, err := pgx.CollectRows(rows, pgx.RowToStructByName[planner.EstimateSample])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:focus_sessions for user_id=%s: %w", userID, err)
+	}
+
+	return samples, nil
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/gamification"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type GamificationRepository struct {
+	server *server.Server
+}
+
+func NewGamificationRepository(server *server.Server) *GamificationRepository {
+	return &GamificationRepository{server: server}
+}
+
+// GetAwards lists the badges awarded to the user, oldest first
+func (r *GamificationRepository) GetAwards(ctx context.Context, userID string) ([]gamification.Award, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			user_badges
+		WHERE
+			user_id=@user_id
+		ORDER BY
+			created_at ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get awards query for user_id=%s: %w", userID, err)
+	}
+
+	awards, err := pgx.CollectRows(rows, pgx.RowToStructByName[gamification.Award])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:user_badges for user_id=%s: %w", userID, err)
+	}
+
+	return awards, nil
+}
+
+// AwardBadge records a badge for the user, it returns nil when they already had it
+func (r *GamificationRepository) AwardBadge(ctx context.Context, userID string,
+	badge gamification.BadgeCode,
+) (*gamification.Award, error) {
+	stmt := `
+		INSERT INTO
+			user_badges (user_id, badge)
+		VALUES
+			(@user_id, @badge)
+		ON CONFLICT (user_id, badge) DO NOTHING
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"badge":   badge,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute award badge query for user_id=%s: %w", userID, err)
+	}
+
+	award, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[gamification.Award])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to collect row from table:user_badges for user_id=%s: %w", userID, err)
+	}
+
+	return &award, nil
+}
+
+// GetGoal returns nil for a user without a weekly goal
+func (r *GamificationRepository) GetGoal(ctx context.Context, userID string) (*gamification.Goal, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			weekly_goals
+		WHERE
+			user_id=@user_id
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get weekly goal query for user_id=%s: %w", userID, err)
+	}
+
+	goal, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[gamification.Goal])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to collect row from table:weekly_goals for user_id=%s: %w", userID, err)
+	}
+
+	return &goal, nil
+}
+
+func (r *GamificationRepository) SetGoal(ctx context.Context, userID string, target int) (*gamification.Goal, error) {
+	stmt := `
+		INSERT INTO
+			weekly_goals (user_id, target)
+		VALUES
+			(@user_id, @target)
+		ON CONFLICT (user_id) DO UPDATE
+		SET
+			target = EXCLUDED.target
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"target":  target,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute set weekly goal query for user_id=%s: %w", userID, err)
+	}
+
+	goal, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[gamification.Goal])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:weekly_goals for user_id=%s: %w", userID, err)
+	}
+
+	return &goal, nil
+}
+
+func (r *GamificationRepository) DeleteGoal(ctx context.Context, userID string) error {
+	stmt := `
+		DELETE FROM weekly_goals
+		WHERE
+			user_id=@user_id
+	`
+
+	result, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute delete weekly goal query for user_id=%s: %w", userID, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		code := errs.CodeGoalNotFound
+		return errs.NewNotFoundError("weekly goal not found", false, &code)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/inbox"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type InboxRepository struct {
+	server *server.Server
+}
+
+func NewInboxRepository(server *server.Server) *InboxRepository {
+	return &InboxRepository{server: server}
+}
+
+func (r *InboxRepository) CaptureItem(ctx context.Context, userID string, payload *inbox.CapturePayload,
+) (*inbox.Item, error) {
+	stmt := `
+		INSERT INTO
+			inbox_items (user_id, title)
+		VALUES
+			(@user_id, @title)
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"title":   payload.Title,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute capture inbox item query for user_id=%s: %w", userID, err)
+	}
+
+	item, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[inbox.Item])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:inbox_items for user_id=%s: %w", userID, err)
+	}
+
+	return &item, nil
+}
+
+// GetItems lists the inbox of the user oldest first, the order it is triaged in
+func (r *InboxRepository) GetItems(ctx context.Context, userID string, query *inbox.GetItemsQuery) ([]inbox.Item, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			inbox_items
+		WHERE
+			user_id=@user_id
+		ORDER BY
+			created_at ASC,
+			id ASC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"limit":   *query.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get inbox items query for user_id=%s: %w", userID, err)
+	}
+
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[inbox.Item])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:inbox_items for user_id=%s: %w", userID, err)
+	}
+
+	return items, nil
+}
+
+// RemoveItem takes an item out of the inbox and returns it, of two concurrent triages only one
+// gets the item
+func (r *InboxRepository) RemoveItem(ctx context.Context, userID string, itemID uuid.UUID) (*inbox.Item, error) {
+	stmt := `
+		DELETE FROM inbox_items
+		WHERE
+			id=@id
+			AND user_id=@user_id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"id":      itemID,
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute remove inbox item query for item_id=%s: %w", itemID.String(), err)
+	}
+
+	item, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[inbox.Item])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeInboxItemNotFound
+			return nil, errs.NewNotFoundError("inbox item not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:inbox_items for item_id=%s: %w", itemID.String(), err)
+	}
+
+	return &item, nil
+}
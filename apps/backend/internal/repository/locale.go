@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type LocaleRepository struct {
+	server *server.Server
+}
+
+func NewLocaleRepository(server *server.Server) *LocaleRepository {
+	return &LocaleRepository{server: server}
+}
+
+// GetLocale returns nil for a user who never chose a locale
+func (r *LocaleRepository) GetLocale(ctx context.Context, userID string) (*string, error) {
+	stmt := `
+		SELECT
+			locale
+		FROM
+			user_locales
+		WHERE
+			user_id = @user_id
+	`
+
+	var locale string
+	err := r.server.DB.Reader(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	}).Scan(&locale)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get locale for user_id=%s: %w", userID, err)
+	}
+
+	return &locale, nil
+}
+
+func (r *LocaleRepository) SetLocale(ctx context.Context, userID, locale string) error {
+	stmt := `
+		INSERT INTO
+			user_locales (user_id, locale)
+		VALUES
+			(@user_id, @locale)
+		ON CONFLICT (user_id) DO UPDATE
+		SET
+			locale = EXCLUDED.locale
+	`
+
+	_, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"locale":  locale,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute set locale query for user_id=%s: %w", userID, err)
+	}
+
+	return nil
+}
+
+// DeleteLocale tells whether the user had chosen a locale
+func (r *LocaleRepository) DeleteLocale(ctx context.Context, userID string) (bool, error) {
+	stmt := `
+		DELETE FROM user_locales
+		WHERE
+			user_id = @user_id
+	`
+
+	result, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to execute delete locale query for user_id=%s: %w", userID, err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/magictag"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type MagicTagRepository struct {
+	server *server.Server
+}
+
+func NewMagicTagRepository(server *server.Server) *MagicTagRepository {
+	return &MagicTagRepository{server: server}
+}
+
+// GetMagicTags lists the magic tags of a user by tag
+func (r *MagicTagRepository) GetMagicTags(ctx context.Context, userID string) ([]magictag.MagicTag, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			magic_tags
+		WHERE
+			user_id=@user_id
+		ORDER BY
+			tag ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get magic tags query for user_id=%s: %w", userID, err)
+	}
+
+	tags, err := pgx.CollectRows(rows, pgx.RowToStructByName[magictag.MagicTag])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:magic_tags for user_id=%s: %w", userID, err)
+	}
+
+	return tags, nil
+}
+
+func (r *MagicTagRepository) CountMagicTags(ctx context.Context, userID string) (int, error) {
+	stmt := `
+		SELECT
+			COUNT(*)
+		FROM
+			magic_tags
+		WHERE
+			user_id=@user_id
+	`
+
+	var count int
+	err := r.server.DB.Writer(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	}).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute count magic tags query for user_id=%s: %w", userID, err)
+	}
+
+	return count, nil
+}
+
+func (r *MagicTagRepository) CreateMagicTag(ctx context.Context, userID string,
+	definition *magictag.Definition,
+) (*magictag.MagicTag, error) {
+	stmt := `
+		INSERT INTO
+			magic_tags (user_id, tag, priority, due_in_days, category_id, timezone)
+		VALUES
+			(@user_id, @tag, @priority, @due_in_days, @category_id, @timezone)
+		RETURNING
+			*
+	`
+
+	args := definitionArgs(definition)
+	args["user_id"] = userID
+
+	return r.magicTagRow(ctx, "create magic tag", stmt, args)
+}
+
+// UpdateMagicTag replaces the definition of a magic tag
+func (r *MagicTagRepository) UpdateMagicTag(ctx context.Context, userID string, tagID uuid.UUID,
+	definition *magictag.Definition,
+) (*magictag.MagicTag, error) {
+	stmt := `
+		UPDATE magic_tags
+		SET
+			tag=@tag,
+			priority=@priority,
+			due_in_days=@due_in_days,
+			category_id=@category_id,
+			timezone=@timezone
+		WHERE
+			id=@id
+			AND user_id=@user_id
+		RETURNING
+			*
+	`
+
+	args := definitionArgs(definition)
+	args["id"] = tagID
+	args["user_id"] = userID
+
+	return r.magicTagRow(ctx, "update magic tag", stmt, args)
+}
+
+func (r *MagicTagRepository) DeleteMagicTag(ctx context.Context, userID string, tagID uuid.UUID) (*magictag.MagicTag, error) {
+	stmt := `
+		DELETE FROM magic_tags
+		WHERE
+			id=@id
+			AND user_id=@user_id
+		RETURNING
+			*
+	`
+
+	return r.magicTagRow(ctx, "delete magic tag", stmt, pgx.NamedArgs{
+		"id":      tagID,
+		"user_id": userID,
+	})
+}
+
+func (r *MagicTagRepository) magicTagRow(ctx context.Context, operation, stmt string,
+	args pgx.NamedArgs,
+) (*magictag.MagicTag, error) {
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s query for user_id=%s: %w", operation, args["user_id"], err)
+	}
+
+	tag, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[magictag.MagicTag])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeMagicTagNotFound
+			return nil, errs.NewNotFoundError("magic tag not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:magic_tags for user_id=%s: %w", args["user_id"], err)
+	}
+
+	return &tag, nil
+}
+
+func definitionArgs(definition *magictag.Definition) pgx.NamedArgs {
+	return pgx.NamedArgs{
+		"tag":         definition.Tag,
+		"priority":    definition.Priority,
+		"due_in_days": definition.DueInDays,
+		"category_id": definition.CategoryID,
+		"timezone":    *definition.Timezone,
+	}
+}
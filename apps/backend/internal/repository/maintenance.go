@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/admin"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type MaintenanceRepository struct {
+	server *server.Server
+}
+
+func NewMaintenanceRepository(server *server.Server) *MaintenanceRepository {
+	return &MaintenanceRepository{server: server}
+}
+
+// IsPartitioned reports whether a table was converted to a partitioned table, see partition_todos
+func (r *MaintenanceRepository) IsPartitioned(ctx context.Context, table string) (bool, error) {
+	stmt := `
+		SELECT
+			EXISTS (
+				SELECT
+					1
+				FROM
+					pg_partitioned_table
+				WHERE
+					partrelid=@table::text::regclass
+			)
+	`
+
+	var partitioned bool
+	err := r.server.DB.Writer(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{
+		"table": table,
+	}).Scan(&partitioned)
+	if err != nil {
+		return false, fmt.Errorf("failed to check whether table:%s is partitioned: %w", table, err)
+	}
+
+	return partitioned, nil
+}
+
+// GetTablePartitions returns the partitions of a table, or the table itself when it isn't partitioned
+func (r *MaintenanceRepository) GetTablePartitions(ctx context.Context, table string) ([]admin.TablePartition, error) {
+	stmt := `
+		SELECT
+			c.relname AS name,
+			COALESCE(s.n_live_tup, 0) AS live_tuples,
+			COALESCE(s.n_dead_tup, 0) AS dead_tuples,
+			pg_table_size(c.oid) AS table_bytes,
+			pg_indexes_size(c.oid) AS index_bytes,
+			GREATEST(s.last_vacuum, s.last_autovacuum) AS last_vacuum_at,
+			GREATEST(s.last_analyze, s.last_autoanalyze) AS last_analyze_at
+		FROM
+			pg_class c
+			LEFT JOIN pg_stat_user_tables s ON s.relid=c.oid
+		WHERE
+			c.oid IN (
+				SELECT
+					inhrelid
+				FROM
+					pg_inherits
+				WHERE
+					inhparent=@table::text::regclass
+			)
+			OR (
+				c.oid=@table::text::regclass
+				AND c.relkind='r'
+			)
+		ORDER BY
+			c.relname ASC
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"table": table,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get partitions query for table:%s: %w", table, err)
+	}
+
+	partitions, err := pgx.CollectRows(rows, pgx.RowToStructByName[admin.TablePartition])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect partitions of table:%s: %w", table, err)
+	}
+
+	return partitions, nil
+}
+
+// VacuumTables vacuums and analyzes each table in turn, VACUUM can't run inside a transaction
+func (r *MaintenanceRepository) VacuumTables(ctx context.Context, tables []string) error {
+	return withoutStatementTimeout(ctx, r.server, func(conn *pgxpool.Conn) error {
+		for _, table := range tables {
+			if _, err := conn.Exec(ctx, "VACUUM (ANALYZE) "+pgx.Identifier{table}.Sanitize()); err != nil {
+				return fmt.Errorf("failed to vacuum table:%s: %w", table, err)
+			}
+		}
+		return nil
+	})
+}
+
+// AnalyzeTable collects planner statistics for a table. Autovacuum never analyzes the parent of
+// a partitioned table, only its partitions.
+func (r *MaintenanceRepository) AnalyzeTable(ctx context.Context, table string) error {
+	return withoutStatementTimeout(ctx, r.server, func(conn *pgxpool.Conn) error {
+		if _, err := conn.Exec(ctx, "ANALYZE "+pgx.Identifier{table}.Sanitize()); err != nil {
+			return fmt.Errorf("failed to analyze table:%s: %w", table, err)
+		}
+		return nil
+	})
+}
+
+// withoutStatementTimeout runs fn on a dedicated connection exempt from the statement timeout
+// meant for requests
+func withoutStatementTimeout(ctx context.Context, s *server.Server, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := s.DB.Pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SET statement_timeout = 0"); err != nil {
+		return fmt.Errorf("failed to lift statement timeout: %w", err)
+	}
+	defer func() {
+		// RESET restores the timeout the connection was opened with before it goes back to the pool
+		_, _ = conn.Exec(context.WithoutCancel(ctx), "RESET statement_timeout")
+	}()
+
+	return fn(conn)
+}
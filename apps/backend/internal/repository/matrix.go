@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/matrix"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type MatrixRepository struct {
+	server *server.Server
+}
+
+func NewMatrixRepository(server *server.Server) *MatrixRepository {
+	return &MatrixRepository{server: server}
+}
+
+// GetSettings returns nil for a user who never changed the defaults
+func (r *MatrixRepository) GetSettings(ctx context.Context, userID string) (*matrix.Settings, error) {
+	stmt := `
+		SELECT
+			urgent_within_hours,
+			important_priority
+		FROM
+			matrix_settings
+		WHERE
+			user_id = @user_id
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get matrix settings query for user_id=%s: %w", userID, err)
+	}
+
+	settings, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[matrix.Settings])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to collect row from table:matrix_settings for user_id=%s: %w", userID, err)
+	}
+
+	return &settings, nil
+}
+
+func (r *MatrixRepository) SetSettings(ctx context.Context, userID string, settings matrix.Settings) (*matrix.Settings, error) {
+	stmt := `
+		INSERT INTO
+			matrix_settings (user_id, urgent_within_hours, important_priority)
+		VALUES
+			(@user_id, @urgent_within_hours, @important_priority)
+		ON CONFLICT (user_id) DO UPDATE
+		SET
+			urgent_within_hours = EXCLUDED.urgent_within_hours,
+			important_priority = EXCLUDED.important_priority
+		RETURNING
+			urgent_within_hours,
+			important_priority
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":             userID,
+		"urgent_within_hours": settings.UrgentWithinHours,
+		"important_priority":  settings.ImportantPriority,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute set matrix settings query for user_id=%s: %w", userID, err)
+	}
+
+	saved, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[matrix.Settings])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:matrix_settings for user_id=%s: %w", userID, err)
+	}
+
+	return &saved, nil
+}
@@ -0,0 +1,183 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/admin"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/model/workspace"
+)
+
+type AdminRepository struct {
+	store *Store
+}
+
+func NewAdminRepository(store *Store) *AdminRepository {
+	return &AdminRepository{store: store}
+}
+
+func (r *AdminRepository) GetUserStats(ctx context.Context, userID string) (*admin.UserStats, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := &admin.UserStats{}
+	for _, item := range s.todos {
+		if item.UserID != userID {
+			continue
+		}
+
+		stats.Todos++
+		if item.StatusCategory == todo.StatusCompleted {
+			stats.CompletedTodos++
+		}
+		if stats.LastActivityAt == nil || item.UpdatedAt.After(*stats.LastActivityAt) {
+			lastActivityAt := item.UpdatedAt
+			stats.LastActivityAt = &lastActivityAt
+		}
+	}
+
+	for _, categoryItem := range s.categories {
+		if categoryItem.UserID == userID {
+			stats.Categories++
+		}
+	}
+
+	for _, c := range s.comments {
+		if c.UserID == userID {
+			stats.Comments++
+		}
+	}
+
+	for _, attachment := range s.attachments {
+		if attachment.UploadedBy == userID {
+			stats.Attachments++
+			if attachment.FileSize != nil {
+				stats.AttachmentBytes += *attachment.FileSize
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+func (r *AdminRepository) GetStorageQuota(ctx context.Context, userID string) (*admin.StorageQuota, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	quota, ok := s.storageQuotas[userID]
+	if !ok {
+		code := errs.CodeStorageQuotaNotFound
+		return nil, errs.NewNotFoundError("the user has no storage quota override", false, &code)
+	}
+
+	copied := *quota
+	return &copied, nil
+}
+
+func (r *AdminRepository) SetStorageQuota(ctx context.Context, updatedBy string,
+	payload *admin.SetStorageQuotaPayload,
+) (*admin.StorageQuota, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	quota, ok := s.storageQuotas[payload.UserID]
+	if !ok {
+		quota = &admin.StorageQuota{UserID: payload.UserID}
+		quota.CreatedAt = now
+		s.storageQuotas[payload.UserID] = quota
+	}
+
+	quota.QuotaBytes = *payload.QuotaBytes
+	quota.UpdatedBy = updatedBy
+	quota.UpdatedAt = now
+
+	copied := *quota
+	return &copied, nil
+}
+
+func (r *AdminRepository) DeleteStorageQuota(ctx context.Context, userID string) (*admin.StorageQuota, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	quota, ok := s.storageQuotas[userID]
+	if !ok {
+		code := errs.CodeStorageQuotaNotFound
+		return nil, errs.NewNotFoundError("the user has no storage quota override", false, &code)
+	}
+
+	delete(s.storageQuotas, userID)
+	return quota, nil
+}
+
+func (r *AdminRepository) GetWorkspaceStats(ctx context.Context, workspaceID string) (*admin.WorkspaceStats, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := &admin.WorkspaceStats{}
+	for key := range s.workspaceMembers {
+		if key.workspaceID == workspaceID {
+			stats.Members++
+		}
+	}
+
+	for _, item := range s.todos {
+		if item.WorkspaceID == nil || *item.WorkspaceID != workspaceID {
+			continue
+		}
+
+		stats.Todos++
+		if item.StatusCategory == todo.StatusCompleted {
+			stats.CompletedTodos++
+		}
+		if stats.LastActivityAt == nil || item.UpdatedAt.After(*stats.LastActivityAt) {
+			lastActivityAt := item.UpdatedAt
+			stats.LastActivityAt = &lastActivityAt
+		}
+	}
+
+	for _, template := range s.templates {
+		if template.WorkspaceID == workspaceID {
+			stats.Templates++
+		}
+	}
+
+	for _, policy := range s.agingPolicies {
+		if policy.WorkspaceID == workspaceID {
+			stats.AgingPolicies++
+		}
+	}
+
+	now := s.now()
+	for _, invite := range s.invites {
+		if invite.WorkspaceID == workspaceID && invite.StatusAt(now) == workspace.InviteStatusPending {
+			stats.PendingInvites++
+		}
+	}
+
+	for _, endpoint := range s.webhookEndpoints {
+		if endpoint.WorkspaceID == workspaceID {
+			stats.Webhooks++
+		}
+	}
+
+	for _, attachment := range s.attachments {
+		item, ok := s.todos[attachment.TodoID]
+		if !ok || item.WorkspaceID == nil || *item.WorkspaceID != workspaceID {
+			continue
+		}
+
+		stats.Attachments++
+		if attachment.FileSize != nil {
+			stats.AttachmentBytes += *attachment.FileSize
+		}
+	}
+
+	return stats, nil
+}
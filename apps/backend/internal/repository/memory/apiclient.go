@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/apiclient"
+	"github.com/google/uuid"
+)
+
+type APIClientRepository struct {
+	store *Store
+}
+
+func NewAPIClientRepository(store *Store) *APIClientRepository {
+	return &APIClientRepository{store: store}
+}
+
+func (r *APIClientRepository) GetClients(ctx context.Context) ([]apiclient.APIClient, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clients := []apiclient.APIClient{}
+	for _, client := range s.apiClients {
+		clients = append(clients, *copyAPIClient(client))
+	}
+	slices.SortFunc(clients, func(a, b apiclient.APIClient) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return clients, nil
+}
+
+func (r *APIClientRepository) GetAllowedOrigins(ctx context.Context) ([]string, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	origins := []string{}
+	for _, client := range s.apiClients {
+		if !client.Enabled {
+			continue
+		}
+		for _, origin := range client.AllowedOrigins {
+			if !slices.Contains(origins, origin) {
+				origins = append(origins, origin)
+			}
+		}
+	}
+
+	return origins, nil
+}
+
+func (r *APIClientRepository) CreateClient(ctx context.Context, createdBy string,
+	payload *apiclient.CreateAPIClientPayload,
+) (*apiclient.APIClient, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	client := &apiclient.APIClient{
+		Name:           payload.Name,
+		AllowedOrigins: slices.Clone(payload.AllowedOrigins),
+		Enabled:        *payload.Enabled,
+		CreatedBy:      createdBy,
+	}
+	client.ID = uuid.New()
+	client.CreatedAt = now
+	client.UpdatedAt = now
+	s.apiClients[client.ID] = client
+
+	return copyAPIClient(client), nil
+}
+
+func (r *APIClientRepository) UpdateClient(ctx context.Context,
+	payload *apiclient.UpdateAPIClientPayload,
+) (*apiclient.APIClient, error) {
+	if payload.Name == nil && payload.AllowedOrigins == nil && payload.Enabled == nil {
+		return nil, errs.NewBadRequestError("no fields to update", false, nil, nil, nil)
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.apiClients[payload.ID]
+	if !ok {
+		return nil, apiClientNotFound()
+	}
+
+	client := copyAPIClient(stored)
+	if payload.Name != nil {
+		client.Name = *payload.Name
+	}
+	if payload.AllowedOrigins != nil {
+		client.AllowedOrigins = slices.Clone(payload.AllowedOrigins)
+	}
+	if payload.Enabled != nil {
+		client.Enabled = *payload.Enabled
+	}
+	client.UpdatedAt = s.now()
+	s.apiClients[client.ID] = client
+
+	return copyAPIClient(client), nil
+}
+
+func (r *APIClientRepository) DeleteClient(ctx context.Context, clientID uuid.UUID) (*apiclient.APIClient, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, ok := s.apiClients[clientID]
+	if !ok {
+		return nil, apiClientNotFound()
+	}
+	delete(s.apiClients, clientID)
+
+	return client, nil
+}
+
+func copyAPIClient(client *apiclient.APIClient) *apiclient.APIClient {
+	copied := *client
+	copied.AllowedOrigins = slices.Clone(client.AllowedOrigins)
+	return &copied
+}
+
+func apiClientNotFound() error {
+	code := errs.CodeAPIClientNotFound
+	return errs.NewNotFoundError("API client not found", false, &code)
+}
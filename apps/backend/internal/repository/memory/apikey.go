@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/apikey"
+	"github.com/google/uuid"
+)
+
+type APIKeyRepository struct {
+	store *Store
+}
+
+func NewAPIKeyRepository(store *Store) *APIKeyRepository {
+	return &APIKeyRepository{store: store}
+}
+
+func (r *APIKeyRepository) GetKeys(ctx context.Context, userID string) ([]apikey.Key, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := []apikey.Key{}
+	for _, key := range s.apiKeys {
+		if key.UserID == userID {
+			keys = append(keys, *key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b apikey.Key) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return keys, nil
+}
+
+func (r *APIKeyRepository) CountKeys(ctx context.Context, userID string) (int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, key := range s.apiKeys {
+		if key.UserID == userID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *APIKeyRepository) CreateKey(ctx context.Context, key *apikey.Key) (*apikey.Key, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	created := &apikey.Key{
+		UserID:     key.UserID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		SecretHash: slices.Clone(key.SecretHash),
+		Plan:       key.Plan,
+	}
+	created.ID = uuid.New()
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	s.apiKeys[created.ID] = created
+
+	copied := *created
+	return &copied, nil
+}
+
+func (r *APIKeyRepository) DeleteKey(ctx context.Context, userID string, keyID uuid.UUID) (*apikey.Key, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.apiKeys[keyID]
+	if !ok || key.UserID != userID {
+		return nil, apiKeyNotFound()
+	}
+	delete(s.apiKeys, keyID)
+
+	return key, nil
+}
+
+func (r *APIKeyRepository) GetKeyBySecretHash(ctx context.Context, secretHash []byte) (*apikey.Key, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range s.apiKeys {
+		if bytes.Equal(key.SecretHash, secretHash) {
+			copied := *key
+			return &copied, nil
+		}
+	}
+
+	return nil, apiKeyNotFound()
+}
+
+func (r *APIKeyRepository) SetKeyPlan(ctx context.Context, keyID uuid.UUID, plan string) (*apikey.Key, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.apiKeys[keyID]
+	if !ok {
+		return nil, apiKeyNotFound()
+	}
+
+	key := *stored
+	key.Plan = plan
+	key.UpdatedAt = s.now()
+	s.apiKeys[keyID] = &key
+
+	copied := key
+	return &copied, nil
+}
+
+func (r *APIKeyRepository) TouchKey(ctx context.Context, keyID uuid.UUID) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.apiKeys[keyID]
+	if !ok {
+		return nil
+	}
+
+	key := *stored
+	now := s.now()
+	key.LastUsedAt = &now
+	key.UpdatedAt = now
+	s.apiKeys[keyID] = &key
+
+	return nil
+}
+
+func apiKeyNotFound() error {
+	code := errs.CodeAPIKeyNotFound
+	return errs.NewNotFoundError("API key not found", false, &code)
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+)
+
+type AuditRepository struct {
+	store *Store
+}
+
+func NewAuditRepository(store *Store) *AuditRepository {
+	return &AuditRepository{store: store}
+}
+
+// CreateEntry appends an entry to the audit log, entries are never changed afterwards
+func (r *AuditRepository) CreateEntry(ctx context.Context, entry *audit.Entry) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextAuditID++
+	stored := copyEntry(*entry)
+	stored.ID = s.nextAuditID
+	stored.CreatedAt = s.now()
+	s.auditLog = append(s.auditLog, stored)
+
+	return nil
+}
+
+func (r *AuditRepository) GetEntries(ctx context.Context, query *audit.GetAuditLogQuery) (*model.PaginatedResponse[audit.Entry], error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Newest first, the log is appended in id order
+	matching := []audit.Entry{}
+	for _, entry := range slices.Backward(s.auditLog) {
+		if matchesFilter(&entry, &query.Filter) {
+			matching = append(matching, copyEntry(entry))
+		}
+	}
+
+	total := len(matching)
+	offset := min((*query.Page-1)*(*query.Limit), total)
+	end := min(offset+*query.Limit, total)
+
+	return &model.PaginatedResponse[audit.Entry]{
+		Data:       matching[offset:end],
+		Page:       *query.Page,
+		Limit:      *query.Limit,
+		Total:      total,
+		TotalPages: (total + *query.Limit - 1) / *query.Limit,
+	}, nil
+}
+
+func (r *AuditRepository) StreamEntries(ctx context.Context, filter *audit.Filter,
+	fn func(entry *audit.Entry) error,
+) error {
+	s := r.store
+	s.mu.Lock()
+	matching := []audit.Entry{}
+	for _, entry := range s.auditLog {
+		if matchesFilter(&entry, filter) {
+			matching = append(matching, copyEntry(entry))
+		}
+	}
+	s.mu.Unlock()
+
+	// fn may call back into the store, so it runs without the lock held
+	for i := range matching {
+		if err := fn(&matching[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func matchesFilter(entry *audit.Entry, filter *audit.Filter) bool {
+	if filter.ActorID != nil && entry.ActorID != *filter.ActorID {
+		return false
+	}
+	if filter.Action != nil && entry.Action != *filter.Action {
+		return false
+	}
+	if filter.EntityType != nil && (entry.EntityType == nil || *entry.EntityType != *filter.EntityType) {
+		return false
+	}
+	if filter.EntityID != nil && (entry.EntityID == nil || *entry.EntityID != *filter.EntityID) {
+		return false
+	}
+	if filter.From != nil && entry.CreatedAt.Before(*filter.From) {
+		return false
+	}
+	if filter.To != nil && entry.CreatedAt.After(*filter.To) {
+		return false
+	}
+	return true
+}
+
+func copyEntry(entry audit.Entry) audit.Entry {
+	entry.Before = slices.Clone(entry.Before)
+	entry.After = slices.Clone(entry.After)
+	return entry
+}
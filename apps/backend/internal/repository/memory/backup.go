@@ -0,0 +1,428 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/backup"
+	"github.com/Sameer16536/ExecuTask/internal/model/category"
+	"github.com/Sameer16536/ExecuTask/internal/model/change"
+	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+type BackupRepository struct {
+	store *Store
+}
+
+func NewBackupRepository(store *Store) *BackupRepository {
+	return &BackupRepository{store: store}
+}
+
+// CreateBackup queues a new backup, a workspace can't have two in progress at once
+func (r *BackupRepository) CreateBackup(ctx context.Context, workspaceID, requestedBy string) (*backup.Backup, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, backupItem := range s.backups {
+		if backupItem.WorkspaceID == workspaceID && statusInProgress(backupItem.Status) {
+			code := errs.CodeBackupInProgress
+			return nil, errs.NewConflictError("a backup of this workspace is already in progress", false, &code)
+		}
+	}
+
+	now := s.now()
+	backupItem := &backup.Backup{
+		WorkspaceID: workspaceID,
+		RequestedBy: requestedBy,
+		Status:      backup.StatusPending,
+	}
+	backupItem.ID = uuid.New()
+	backupItem.CreatedAt = now
+	backupItem.UpdatedAt = now
+	s.backups[backupItem.ID] = backupItem
+
+	copied := *backupItem
+	return &copied, nil
+}
+
+// GetBackups lists the backups of a workspace, newest first
+func (r *BackupRepository) GetBackups(ctx context.Context, workspaceID string) ([]backup.Backup, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	backups := []backup.Backup{}
+	for _, backupItem := range s.backups {
+		if backupItem.WorkspaceID == workspaceID {
+			backups = append(backups, *backupItem)
+		}
+	}
+
+	slices.SortFunc(backups, func(a, b backup.Backup) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), strings.Compare(b.ID.String(), a.ID.String()))
+	})
+
+	return backups, nil
+}
+
+func (r *BackupRepository) GetBackup(ctx context.Context, backupID uuid.UUID) (*backup.Backup, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	backupItem, ok := s.backups[backupID]
+	if !ok {
+		return nil, errBackupNotFound()
+	}
+
+	copied := *backupItem
+	return &copied, nil
+}
+
+// StartBackup moves a pending backup to running. A backup already running was picked up by an
+// attempt that died, it is claimed again. Finished backups are returned unchanged.
+func (r *BackupRepository) StartBackup(ctx context.Context, backupID uuid.UUID) (*backup.Backup, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	backupItem, ok := s.backups[backupID]
+	if !ok {
+		return nil, errBackupNotFound()
+	}
+
+	if statusInProgress(backupItem.Status) {
+		backupItem.Status = backup.StatusRunning
+	}
+	backupItem.UpdatedAt = s.now()
+
+	copied := *backupItem
+	return &copied, nil
+}
+
+func (r *BackupRepository) CompleteBackup(ctx context.Context, backupID uuid.UUID, objectKey string, sizeBytes int64,
+	counts map[string]int,
+) (*backup.Backup, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	backupItem, ok := s.backups[backupID]
+	if !ok {
+		return nil, errBackupNotFound()
+	}
+
+	now := s.now()
+	backupItem.Status = backup.StatusCompleted
+	backupItem.ObjectKey = &objectKey
+	backupItem.SizeBytes = &sizeBytes
+	backupItem.Counts = counts
+	backupItem.Error = nil
+	backupItem.CompletedAt = &now
+	backupItem.UpdatedAt = now
+
+	copied := *backupItem
+	return &copied, nil
+}
+
+// FailBackup gives up on a backup that is still in progress
+func (r *BackupRepository) FailBackup(ctx context.Context, backupID uuid.UUID, reason string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if backupItem, ok := s.backups[backupID]; ok && statusInProgress(backupItem.Status) {
+		backupItem.Status = backup.StatusFailed
+		backupItem.Error = &reason
+		backupItem.UpdatedAt = s.now()
+	}
+
+	return nil
+}
+
+// CreateRestore queues a restore, a workspace can't be restored into twice at once
+func (r *BackupRepository) CreateRestore(ctx context.Context, backupID uuid.UUID, targetWorkspaceID,
+	requestedBy string,
+) (*backup.Restore, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.backups[backupID]; !ok {
+		return nil, fmt.Errorf("failed to execute create restore query for backup_id=%s: %w", backupID.String(),
+			foreignKeyViolation("workspace_restores", "workspace_restores_backup_id_fkey",
+				`insert or update on table "workspace_restores" violates foreign key constraint "workspace_restores_backup_id_fkey"`))
+	}
+
+	for _, restore := range s.restores {
+		if restore.TargetWorkspaceID == targetWorkspaceID && statusInProgress(restore.Status) {
+			code := errs.CodeRestoreInProgress
+			return nil, errs.NewConflictError("a restore into this workspace is already in progress", false, &code)
+		}
+	}
+
+	now := s.now()
+	restore := &backup.Restore{
+		BackupID:          backupID,
+		TargetWorkspaceID: targetWorkspaceID,
+		RequestedBy:       requestedBy,
+		Status:            backup.StatusPending,
+	}
+	restore.ID = uuid.New()
+	restore.CreatedAt = now
+	restore.UpdatedAt = now
+	s.restores[restore.ID] = restore
+
+	copied := *restore
+	return &copied, nil
+}
+
+func (r *BackupRepository) GetRestore(ctx context.Context, restoreID uuid.UUID) (*backup.Restore, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	restore, ok := s.restores[restoreID]
+	if !ok {
+		return nil, errRestoreNotFound()
+	}
+
+	copied := *restore
+	return &copied, nil
+}
+
+// StartRestore moves a pending restore to running, like StartBackup
+func (r *BackupRepository) StartRestore(ctx context.Context, restoreID uuid.UUID) (*backup.Restore, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	restore, ok := s.restores[restoreID]
+	if !ok {
+		return nil, errRestoreNotFound()
+	}
+
+	if statusInProgress(restore.Status) {
+		restore.Status = backup.StatusRunning
+	}
+	restore.UpdatedAt = s.now()
+
+	copied := *restore
+	return &copied, nil
+}
+
+func (r *BackupRepository) CompleteRestore(ctx context.Context, restoreID uuid.UUID, counts map[string]int) (*backup.Restore, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	restore, ok := s.restores[restoreID]
+	if !ok {
+		return nil, errRestoreNotFound()
+	}
+
+	now := s.now()
+	restore.Status = backup.StatusCompleted
+	restore.Counts = counts
+	restore.Error = nil
+	restore.CompletedAt = &now
+	restore.UpdatedAt = now
+
+	copied := *restore
+	return &copied, nil
+}
+
+// FailRestore gives up on a restore that is still in progress
+func (r *BackupRepository) FailRestore(ctx context.Context, restoreID uuid.UUID, reason string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if restore, ok := s.restores[restoreID]; ok && statusInProgress(restore.Status) {
+		restore.Status = backup.StatusFailed
+		restore.Error = &reason
+		restore.UpdatedAt = s.now()
+	}
+
+	return nil
+}
+
+// CountWorkspaceTodos tells how many todos a workspace holds, restores need it empty
+func (r *BackupRepository) CountWorkspaceTodos(ctx context.Context, workspaceID string) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var count int64
+	for _, item := range s.todos {
+		if item.WorkspaceID != nil && *item.WorkspaceID == workspaceID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// GetWorkspaceContents reads everything a backup of the workspace holds. Categories are per
+// user, only those the todos of the workspace use are included.
+func (r *BackupRepository) GetWorkspaceContents(ctx context.Context, workspaceID string) (*backup.Contents, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inWorkspace := func(todoID uuid.UUID) bool {
+		item, ok := s.todos[todoID]
+		return ok && item.WorkspaceID != nil && *item.WorkspaceID == workspaceID
+	}
+
+	todos := s.filterTodos(func(item *todo.Todo) bool {
+		return inWorkspace(item.ID)
+	})
+	slices.SortFunc(todos, func(a, b todo.Todo) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+
+	used := map[uuid.UUID]bool{}
+	for _, item := range todos {
+		if item.CategoryID != nil {
+			used[*item.CategoryID] = true
+		}
+	}
+
+	attachments := s.filterAttachments(func(attachment *todo.TodoAttachment) bool {
+		return inWorkspace(attachment.TodoID)
+	})
+	slices.SortFunc(attachments, func(a, b todo.TodoAttachment) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+
+	return &backup.Contents{
+		Categories: s.filterCategories(func(categoryItem *category.Category) bool {
+			return used[categoryItem.ID]
+		}),
+		Todos: todos,
+		Comments: s.filterComments(func(c *comment.Comment) bool {
+			return inWorkspace(c.TodoID)
+		}),
+		Attachments: attachments,
+	}, nil
+}
+
+// RestoreCategory returns the category of the user a restored todo should use: the original
+// when it still exists, else one with the same name, else the original recreated
+func (r *BackupRepository) RestoreCategory(ctx context.Context, categoryItem *category.Category) (*category.Category, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.categories[categoryItem.ID]; ok && existing.UserID == categoryItem.UserID {
+		copied := *existing
+		return &copied, nil
+	}
+	for _, existing := range s.categories {
+		if existing.UserID == categoryItem.UserID && existing.Name == categoryItem.Name {
+			copied := *existing
+			return &copied, nil
+		}
+	}
+
+	now := s.now()
+	restored := &category.Category{
+		UserID:      categoryItem.UserID,
+		Name:        categoryItem.Name,
+		Color:       categoryItem.Color,
+		Description: categoryItem.Description,
+		Version:     1,
+	}
+	restored.ID = categoryItem.ID
+	restored.CreatedAt = now
+	restored.UpdatedAt = now
+
+	s.categories[restored.ID] = restored
+	s.recordChange(restored.UserID, "category", restored.ID, change.ActionCreated, &restored.Version)
+
+	copied := *restored
+	return &copied, nil
+}
+
+// InsertRestoredContents writes remapped contents, todos must come parents first. Counters,
+// snapshots and change events are kept up as the triggers would.
+func (r *BackupRepository) InsertRestoredContents(ctx context.Context, contents *backup.Contents) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, restored := range contents.Todos {
+		if restored.ParentTodoID != nil {
+			if _, ok := s.todos[*restored.ParentTodoID]; !ok {
+				return fmt.Errorf("failed to execute insert restored contents batch: %w",
+					foreignKeyViolation("todos", "todos_parent_todo_id_fkey",
+						`insert or update on table "todos" violates foreign key constraint "todos_parent_todo_id_fkey"`))
+			}
+		}
+
+		item := copyTodo(&restored)
+		createdAt := item.CreatedAt
+		item.SubtaskCount = 0
+		item.CompletedSubtaskCount = 0
+		item.CommentCount = 0
+		item.UnreadCommentCount = 0
+		s.insertTodo(&item)
+		item.CreatedAt = createdAt
+	}
+
+	for _, restored := range contents.Comments {
+		item, ok := s.todos[restored.TodoID]
+		if !ok {
+			return fmt.Errorf("failed to execute insert restored contents batch: %w",
+				foreignKeyViolation("todo_comments", "todo_comments_todo_id_fkey",
+					`insert or update on table "todo_comments" violates foreign key constraint "todo_comments_todo_id_fkey"`))
+		}
+
+		commentItem := restored
+		commentItem.UpdatedAt = s.now()
+		s.comments[commentItem.ID] = &commentItem
+
+		item.CommentCount++
+		if commentItem.UserID != item.UserID &&
+			(item.CommentsReadAt == nil || commentItem.CreatedAt.After(*item.CommentsReadAt)) {
+			item.UnreadCommentCount++
+		}
+		s.recordChange(commentItem.UserID, "comment", commentItem.ID, change.ActionCreated, nil)
+	}
+
+	for _, restored := range contents.Attachments {
+		if _, ok := s.todos[restored.TodoID]; !ok {
+			return fmt.Errorf("failed to execute insert restored contents batch: %w",
+				foreignKeyViolation("todo_attachments", "todo_attachments_todo_id_fkey",
+					`insert or update on table "todo_attachments" violates foreign key constraint "todo_attachments_todo_id_fkey"`))
+		}
+
+		attachment := restored
+		attachment.UpdatedAt = s.now()
+		s.attachments[attachment.ID] = &attachment
+	}
+
+	return nil
+}
+
+func statusInProgress(status backup.Status) bool {
+	return status == backup.StatusPending || status == backup.StatusRunning
+}
+
+func errBackupNotFound() error {
+	code := errs.CodeBackupNotFound
+	return errs.NewNotFoundError("backup not found", false, &code)
+}
+
+func errRestoreNotFound() error {
+	code := errs.CodeRestoreNotFound
+	return errs.NewNotFoundError("restore not found", false, &code)
+}
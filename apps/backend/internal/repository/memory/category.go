@@ -0,0 +1,223 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/category"
+	"github.com/Sameer16536/ExecuTask/internal/model/change"
+	"github.com/google/uuid"
+)
+
+type CategoryRepository struct {
+	store *Store
+}
+
+func NewCategoryRepository(store *Store) *CategoryRepository {
+	return &CategoryRepository{store: store}
+}
+
+func (r *CategoryRepository) CreateCategory(ctx context.Context, userID string,
+	payload *category.CreateCategoryPayload,
+) (*category.Category, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.categoryNameTaken(userID, payload.Name, uuid.Nil) {
+		return nil, fmt.Errorf("failed to execute create category query for user_id=%s name=%s: %w", userID, payload.Name,
+			uniqueViolation("todo_categories", "todo_categories_unique_name"))
+	}
+
+	now := s.now()
+	categoryItem := &category.Category{
+		UserID:      userID,
+		Name:        payload.Name,
+		Color:       payload.Color,
+		Description: payload.Description,
+		Version:     1,
+	}
+	categoryItem.ID = uuid.New()
+	categoryItem.CreatedAt = now
+	categoryItem.UpdatedAt = now
+
+	s.categories[categoryItem.ID] = categoryItem
+	s.recordChange(userID, "category", categoryItem.ID, change.ActionCreated, &categoryItem.Version)
+
+	copied := *categoryItem
+	return &copied, nil
+}
+
+func (r *CategoryRepository) GetCategoryByID(ctx context.Context, userID string, categoryID uuid.UUID) (*category.Category, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	categoryItem, ok := s.categories[categoryID]
+	if !ok || categoryItem.UserID != userID {
+		return nil, noRows("todo_categories", "category_id=%s user_id=%s", categoryID.String(), userID)
+	}
+
+	copied := *categoryItem
+	return &copied, nil
+}
+
+func (r *CategoryRepository) GetCategoriesByIDs(ctx context.Context, userID string,
+	categoryIDs []uuid.UUID,
+) ([]category.Category, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filterCategories(func(categoryItem *category.Category) bool {
+		return categoryItem.UserID == userID && containsID(categoryIDs, categoryItem.ID)
+	}), nil
+}
+
+func (r *CategoryRepository) GetCategories(ctx context.Context, userID string,
+	query *category.GetCategoriesQuery,
+) (*model.PaginatedResponse[category.Category], error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	categories := s.filterCategories(func(categoryItem *category.Category) bool {
+		if categoryItem.UserID != userID {
+			return false
+		}
+		// The fuzzy similarity match of Postgres is left out, a substring has to match
+		return query.Search == nil || strings.Contains(strings.ToLower(categoryItem.Name), strings.ToLower(*query.Search))
+	})
+
+	sortColumn := "name"
+	if query.Sort != nil {
+		sortColumn = *query.Sort
+	}
+	slices.SortStableFunc(categories, func(a, b category.Category) int {
+		var result int
+		switch sortColumn {
+		case "created_at":
+			result = a.CreatedAt.Compare(b.CreatedAt)
+		case "updated_at":
+			result = a.UpdatedAt.Compare(b.UpdatedAt)
+		default:
+			result = strings.Compare(a.Name, b.Name)
+		}
+
+		if query.Order != nil && *query.Order == "desc" {
+			return -result
+		}
+		return result
+	})
+
+	total := len(categories)
+	offset := min((*query.Page-1)*(*query.Limit), total)
+	end := min(offset+*query.Limit, total)
+
+	return &model.PaginatedResponse[category.Category]{
+		Data:       categories[offset:end],
+		Page:       *query.Page,
+		Limit:      *query.Limit,
+		Total:      total,
+		TotalPages: (total + *query.Limit - 1) / *query.Limit,
+	}, nil
+}
+
+func (r *CategoryRepository) UpdateCategory(ctx context.Context, userID string,
+	categoryID uuid.UUID, payload *category.UpdateCategoryPayload,
+) (*category.Category, error) {
+	if payload.Name == nil && payload.Color == nil && payload.Description == nil {
+		return nil, fmt.Errorf("no fields to update")
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	categoryItem, ok := s.categories[categoryID]
+	if !ok || categoryItem.UserID != userID {
+		return nil, noRows("todo_categories", "category_id=%s user_id=%s", categoryID.String(), userID)
+	}
+
+	if payload.Name != nil && s.categoryNameTaken(userID, *payload.Name, categoryID) {
+		return nil, fmt.Errorf("failed to execute update category query for category_id=%s user_id=%s: %w",
+			categoryID.String(), userID, uniqueViolation("todo_categories", "todo_categories_unique_name"))
+	}
+
+	if payload.Name != nil {
+		categoryItem.Name = *payload.Name
+	}
+	if payload.Color != nil {
+		categoryItem.Color = *payload.Color
+	}
+	if payload.Description != nil {
+		description := *payload.Description
+		categoryItem.Description = &description
+	}
+	categoryItem.UpdatedAt = s.now()
+	categoryItem.Version++
+	s.recordChange(userID, "category", categoryID, change.ActionUpdated, &categoryItem.Version)
+
+	copied := *categoryItem
+	return &copied, nil
+}
+
+func (r *CategoryRepository) DeleteCategory(ctx context.Context, userID string, categoryID uuid.UUID) (*category.Category, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	categoryItem, ok := s.categories[categoryID]
+	if !ok || categoryItem.UserID != userID {
+		return nil, fmt.Errorf("category not found")
+	}
+
+	// Todos reference their category without a cascade
+	for _, item := range s.todos {
+		if item.CategoryID != nil && *item.CategoryID == categoryID {
+			return nil, fmt.Errorf("failed to delete category: %w", foreignKeyViolation("todo_categories", "todos_category_id_fkey",
+				`update or delete on table "todo_categories" violates foreign key constraint "todos_category_id_fkey" on table "todos"`))
+		}
+	}
+
+	delete(s.categories, categoryID)
+	for _, tag := range s.magicTags {
+		if tag.CategoryID != nil && *tag.CategoryID == categoryID {
+			tag.CategoryID = nil
+		}
+	}
+	s.recordChange(userID, "category", categoryID, change.ActionDeleted, &categoryItem.Version)
+
+	copied := *categoryItem
+	return &copied, nil
+}
+
+// categoryNameTaken checks the unique index on user and name, except for the category with the given id
+func (s *Store) categoryNameTaken(userID, name string, except uuid.UUID) bool {
+	for _, categoryItem := range s.categories {
+		if categoryItem.UserID == userID && categoryItem.Name == name && categoryItem.ID != except {
+			return true
+		}
+	}
+	return false
+}
+
+// filterCategories copies out the categories matching keep, oldest first
+func (s *Store) filterCategories(keep func(categoryItem *category.Category) bool) []category.Category {
+	categories := []category.Category{}
+	for _, categoryItem := range s.categories {
+		if keep(categoryItem) {
+			categories = append(categories, *categoryItem)
+		}
+	}
+
+	slices.SortFunc(categories, func(a, b category.Category) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+
+	return categories
+}
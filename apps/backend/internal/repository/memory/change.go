@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/change"
+)
+
+type ChangeRepository struct {
+	store *Store
+}
+
+func NewChangeRepository(store *Store) *ChangeRepository {
+	return &ChangeRepository{store: store}
+}
+
+func (r *ChangeRepository) GetChangesAfter(ctx context.Context, userID string, afterID int64, limit int) ([]change.Change, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The log is appended in id order
+	changes := []change.Change{}
+	for _, c := range s.changes {
+		if len(changes) == limit {
+			break
+		}
+		if c.UserID == userID && c.ID > afterID {
+			changes = append(changes, copyChange(c))
+		}
+	}
+
+	return changes, nil
+}
+
+func (r *ChangeRepository) GetOldestChangeID(ctx context.Context) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.changes) == 0 {
+		return 0, nil
+	}
+	return s.changes[0].ID, nil
+}
+
+func (r *ChangeRepository) GetLatestChangeIDBefore(ctx context.Context, before time.Time) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var id int64
+	for _, c := range s.changes {
+		if c.CreatedAt.Before(before) {
+			id = max(id, c.ID)
+		}
+	}
+	return id, nil
+}
+
+// CRON REQUIREMENTS
+
+func (r *ChangeRepository) DeleteChangesOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.changes[:0]
+	for _, c := range s.changes {
+		if !c.CreatedAt.Before(cutoff) {
+			kept = append(kept, c)
+		}
+	}
+
+	deleted := int64(len(s.changes) - len(kept))
+	s.changes = kept
+	return deleted, nil
+}
+
+func copyChange(c change.Change) change.Change {
+	if c.Version != nil {
+		version := *c.Version
+		c.Version = &version
+	}
+	return c
+}
@@ -0,0 +1,238 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/change"
+	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/google/uuid"
+)
+
+type CommentRepository struct {
+	store *Store
+}
+
+func NewCommentRepository(store *Store) *CommentRepository {
+	return &CommentRepository{store: store}
+}
+
+func (r *CommentRepository) AddComment(ctx context.Context, userID string, todoID uuid.UUID,
+	payload *comment.AddCommentPayload,
+) (*comment.Comment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.todos[todoID]
+	if !ok {
+		return nil, fmt.Errorf("failed to execute add comment query for todo_id=%s user_id=%s: %w", todoID.String(), userID,
+			foreignKeyViolation("todo_comments", "todo_comments_todo_id_fkey",
+				`insert or update on table "todo_comments" violates foreign key constraint "todo_comments_todo_id_fkey"`))
+	}
+
+	now := s.now()
+	commentItem := &comment.Comment{
+		TodoID:  todoID,
+		UserID:  userID,
+		Content: payload.Content,
+	}
+	commentItem.ID = uuid.New()
+	commentItem.CreatedAt = now
+	commentItem.UpdatedAt = now
+	s.comments[commentItem.ID] = commentItem
+
+	// Comments by anyone but the owner are unread until the owner marks them as read
+	item.CommentCount++
+	if userID != item.UserID {
+		item.UnreadCommentCount++
+	}
+	s.recordChange(userID, "comment", commentItem.ID, change.ActionCreated, nil)
+
+	copied := *commentItem
+	return &copied, nil
+}
+
+func (r *CommentRepository) GetCommentsByTodoID(ctx context.Context, userID string, todoID uuid.UUID) ([]comment.Comment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filterComments(func(c *comment.Comment) bool {
+		return c.TodoID == todoID && c.UserID == userID
+	}), nil
+}
+
+func (r *CommentRepository) GetCommentsByTodoIDs(ctx context.Context, userID string, todoIDs []uuid.UUID,
+	latestOnly bool,
+) ([]comment.Comment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	comments := s.filterComments(func(c *comment.Comment) bool {
+		return containsID(todoIDs, c.TodoID) && c.UserID == userID
+	})
+	if !latestOnly {
+		return comments, nil
+	}
+
+	// The last comment of every todo, ordered by todo like DISTINCT ON
+	latest := map[uuid.UUID]comment.Comment{}
+	for _, c := range comments {
+		latest[c.TodoID] = c
+	}
+
+	result := make([]comment.Comment, 0, len(latest))
+	for _, c := range latest {
+		result = append(result, c)
+	}
+	slices.SortFunc(result, func(a, b comment.Comment) int {
+		return strings.Compare(a.TodoID.String(), b.TodoID.String())
+	})
+
+	return result, nil
+}
+
+func (r *CommentRepository) GetCommentCounts(ctx context.Context, userID string, todoIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make(map[uuid.UUID]int, len(todoIDs))
+	for _, c := range s.comments {
+		if c.UserID == userID && containsID(todoIDs, c.TodoID) {
+			counts[c.TodoID]++
+		}
+	}
+
+	return counts, nil
+}
+
+func (r *CommentRepository) GetCommentByID(ctx context.Context, userID string, commentID uuid.UUID) (*comment.Comment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commentItem, ok := s.comments[commentID]
+	if !ok || commentItem.UserID != userID {
+		return nil, noRows("todo_comments", "comment_id=%s user_id=%s", commentID.String(), userID)
+	}
+
+	copied := *commentItem
+	return &copied, nil
+}
+
+func (r *CommentRepository) UpdateComment(ctx context.Context, userID string, commentID uuid.UUID, content string) (*comment.Comment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commentItem, ok := s.comments[commentID]
+	if !ok || commentItem.UserID != userID {
+		return nil, noRows("todo_comments", "comment_id=%s user_id=%s", commentID.String(), userID)
+	}
+
+	commentItem.Content = content
+	commentItem.UpdatedAt = s.now()
+	s.recordChange(userID, "comment", commentID, change.ActionUpdated, nil)
+
+	copied := *commentItem
+	return &copied, nil
+}
+
+func (r *CommentRepository) DeleteComment(ctx context.Context, userID string, commentID uuid.UUID) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commentItem, ok := s.comments[commentID]
+	if !ok || commentItem.UserID != userID {
+		return fmt.Errorf("comment not found")
+	}
+
+	s.deleteComment(commentItem)
+
+	return nil
+}
+
+// deleteComment removes a comment and takes it off the counters of its todo
+func (s *Store) deleteComment(commentItem *comment.Comment) {
+	delete(s.comments, commentItem.ID)
+	if item, ok := s.todos[commentItem.TodoID]; ok {
+		item.CommentCount--
+		if commentItem.UserID != item.UserID &&
+			(item.CommentsReadAt == nil || commentItem.CreatedAt.After(*item.CommentsReadAt)) {
+			item.UnreadCommentCount--
+		}
+	}
+	s.recordChange(commentItem.UserID, "comment", commentItem.ID, change.ActionDeleted, nil)
+}
+
+// filterComments copies out the comments matching keep, oldest first
+func (s *Store) filterComments(keep func(c *comment.Comment) bool) []comment.Comment {
+	comments := []comment.Comment{}
+	for _, c := range s.comments {
+		if keep(c) {
+			comments = append(comments, *c)
+		}
+	}
+
+	slices.SortFunc(comments, func(a, b comment.Comment) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+
+	return comments
+}
+
+func (r *CommentRepository) CreateReplyToken(ctx context.Context, token *comment.ReplyToken) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if item, ok := s.todos[token.TodoID]; !ok || item.UserID != token.UserID {
+		return fmt.Errorf("failed to execute create reply token query for todo_id=%s: %w", token.TodoID.String(),
+			foreignKeyViolation("comment_reply_tokens", "comment_reply_tokens_todo_id_user_id_fkey",
+				`insert or update on table "comment_reply_tokens" violates foreign key constraint "comment_reply_tokens_todo_id_user_id_fkey"`))
+	}
+
+	stored := *token
+	stored.CreatedAt = s.now()
+	s.replyTokens[token.Token] = &stored
+
+	return nil
+}
+
+func (r *CommentRepository) GetReplyToken(ctx context.Context, token string) (*comment.ReplyToken, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.replyTokens[token]
+	if !ok || !stored.ExpiresAt.After(s.now()) {
+		return nil, noRows("comment_reply_tokens", "token")
+	}
+
+	copied := *stored
+	return &copied, nil
+}
+
+func (r *CommentRepository) DeleteExpiredReplyTokens(ctx context.Context, now time.Time) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for token, stored := range s.replyTokens {
+		if !stored.ExpiresAt.After(now) {
+			delete(s.replyTokens, token)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/cover"
+	"github.com/google/uuid"
+)
+
+type CoverRepository struct {
+	store *Store
+}
+
+func NewCoverRepository(store *Store) *CoverRepository {
+	return &CoverRepository{store: store}
+}
+
+func (r *CoverRepository) GetCover(ctx context.Context, userID string, todoID uuid.UUID) (*cover.Cover, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.covers[todoID]
+	if !ok || item.UserID != userID {
+		return nil, errCoverNotFound()
+	}
+
+	copied := *item
+	return &copied, nil
+}
+
+func (r *CoverRepository) SetCover(ctx context.Context, item *cover.Cover) (*cover.Cover, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if todoItem, ok := s.todos[item.TodoID]; !ok || todoItem.UserID != item.UserID {
+		return nil, foreignKeyViolation("todo_covers", "todo_covers_todo_id_user_id_fkey",
+			`insert or update on table "todo_covers" violates foreign key constraint "todo_covers_todo_id_user_id_fkey"`)
+	}
+
+	now := s.now()
+	saved := &cover.Cover{
+		TodoID:       item.TodoID,
+		UserID:       item.UserID,
+		Source:       item.Source,
+		AttachmentID: item.AttachmentID,
+		StockKey:     item.StockKey,
+		Status:       cover.StatusPending,
+		Generation:   item.Generation,
+		VariantKeys:  map[string]string{},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if existing, ok := s.covers[item.TodoID]; ok {
+		saved.CreatedAt = existing.CreatedAt
+	}
+	s.covers[item.TodoID] = saved
+
+	copied := *saved
+	return &copied, nil
+}
+
+func (r *CoverRepository) SaveVariants(ctx context.Context, todoID, generation uuid.UUID, status cover.Status,
+	variantKeys map[string]string,
+) (bool, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.covers[todoID]
+	if !ok || item.Generation != generation {
+		return false, nil
+	}
+
+	updated := *item
+	updated.Status = status
+	updated.VariantKeys = map[string]string{}
+	for size, key := range variantKeys {
+		updated.VariantKeys[size] = key
+	}
+	updated.UpdatedAt = s.now()
+	s.covers[todoID] = &updated
+
+	return true, nil
+}
+
+func (r *CoverRepository) DeleteCover(ctx context.Context, userID string, todoID uuid.UUID) (*cover.Cover, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.covers[todoID]
+	if !ok || item.UserID != userID {
+		return nil, errCoverNotFound()
+	}
+	delete(s.covers, todoID)
+
+	return item, nil
+}
+
+func (r *CoverRepository) GetLiveGenerations(ctx context.Context, generations []uuid.UUID) ([]uuid.UUID, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	live := []uuid.UUID{}
+	for _, item := range s.covers {
+		if slices.Contains(generations, item.Generation) {
+			live = append(live, item.Generation)
+		}
+	}
+
+	return live, nil
+}
+
+func errCoverNotFound() error {
+	code := errs.CodeCoverNotFound
+	return errs.NewNotFoundError("the todo has no cover", false, &code)
+}
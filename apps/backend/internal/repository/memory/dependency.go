@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/dependency"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+type DependencyRepository struct {
+	store *Store
+}
+
+func NewDependencyRepository(store *Store) *DependencyRepository {
+	return &DependencyRepository{store: store}
+}
+
+func (r *DependencyRepository) AddDependency(ctx context.Context, userID string, todoID, dependsOnID uuid.UUID,
+) (*dependency.Dependency, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range []uuid.UUID{todoID, dependsOnID} {
+		if item, ok := s.todos[id]; !ok || item.UserID != userID {
+			return nil, fmt.Errorf("failed to execute add dependency query for todo_id=%s: %w", todoID.String(),
+				foreignKeyViolation("todo_dependencies", "todo_dependencies_todo_id_user_id_fkey",
+					`insert or update on table "todo_dependencies" violates foreign key constraint "todo_dependencies_todo_id_user_id_fkey"`))
+		}
+	}
+
+	if slices.ContainsFunc(s.dependencies, func(link dependency.Dependency) bool {
+		return link.TodoID == todoID && link.DependsOnID == dependsOnID
+	}) {
+		code := errs.CodeDependencyExists
+		return nil, errs.NewConflictError("the todo already depends on that todo", false, &code)
+	}
+
+	link := dependency.Dependency{
+		UserID:      userID,
+		TodoID:      todoID,
+		DependsOnID: dependsOnID,
+	}
+	link.CreatedAt = s.now()
+	s.dependencies = append(s.dependencies, link)
+
+	return &link, nil
+}
+
+func (r *DependencyRepository) RemoveDependency(ctx context.Context, userID string, todoID, dependsOnID uuid.UUID) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before := len(s.dependencies)
+	s.dependencies = slices.DeleteFunc(s.dependencies, func(link dependency.Dependency) bool {
+		return link.UserID == userID && link.TodoID == todoID && link.DependsOnID == dependsOnID
+	})
+	if len(s.dependencies) == before {
+		code := errs.CodeDependencyNotFound
+		return errs.NewNotFoundError("the todo doesn't depend on that todo", false, &code)
+	}
+
+	return nil
+}
+
+func (r *DependencyRepository) GetBlockedBy(ctx context.Context, userID string, todoID uuid.UUID) ([]todo.Todo, error) {
+	return r.linkedTodos(userID, func(link dependency.Dependency) (uuid.UUID, bool) {
+		return link.DependsOnID, link.TodoID == todoID
+	}), nil
+}
+
+func (r *DependencyRepository) GetBlocking(ctx context.Context, userID string, todoID uuid.UUID) ([]todo.Todo, error) {
+	return r.linkedTodos(userID, func(link dependency.Dependency) (uuid.UUID, bool) {
+		return link.TodoID, link.DependsOnID == todoID
+	}), nil
+}
+
+// linkedTodos copies out the todos at the other end of the matching links, soonest due first
+func (r *DependencyRepository) linkedTodos(userID string, other func(link dependency.Dependency) (uuid.UUID, bool),
+) []todo.Todo {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todos := []todo.Todo{}
+	for _, link := range s.dependencies {
+		id, ok := other(link)
+		if !ok || link.UserID != userID {
+			continue
+		}
+		if item, ok := s.todos[id]; ok {
+			todos = append(todos, copyTodo(item))
+		}
+	}
+
+	slices.SortStableFunc(todos, func(a, b todo.Todo) int {
+		return cmp.Or(compareNullableTimes(a.DueDate, b.DueDate), a.CreatedAt.Compare(b.CreatedAt))
+	})
+
+	return todos
+}
+
+func (r *DependencyRepository) GetDownstreamDependencies(ctx context.Context, userID string, todoID uuid.UUID,
+) ([]dependency.Dependency, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reached := map[uuid.UUID]bool{todoID: true}
+	queue := []uuid.UUID{todoID}
+	links := []dependency.Dependency{}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, link := range s.dependencies {
+			if link.UserID != userID || link.DependsOnID != current {
+				continue
+			}
+			links = append(links, link)
+			if !reached[link.TodoID] {
+				reached[link.TodoID] = true
+				queue = append(queue, link.TodoID)
+			}
+		}
+	}
+
+	slices.SortStableFunc(links, func(a, b dependency.Dependency) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return links, nil
+}
@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/envelope"
+	"github.com/Sameer16536/ExecuTask/internal/model/encryption"
+	"github.com/google/uuid"
+)
+
+type EncryptionRepository struct {
+	store *Store
+}
+
+func NewEncryptionRepository(store *Store) *EncryptionRepository {
+	return &EncryptionRepository{store: store}
+}
+
+func (r *EncryptionRepository) GetActiveDataKey(ctx context.Context) (*encryption.DataKey, error) {
+	s := r.store
+	s.keysMu.Lock()
+	defer s.keysMu.Unlock()
+
+	for _, dataKey := range s.dataKeys {
+		if dataKey.RetiredAt == nil {
+			copied := *dataKey
+			copied.WrappedKey = slices.Clone(dataKey.WrappedKey)
+			return &copied, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *EncryptionRepository) GetDataKey(ctx context.Context, id uuid.UUID) (*encryption.DataKey, error) {
+	s := r.store
+	s.keysMu.Lock()
+	defer s.keysMu.Unlock()
+
+	dataKey, ok := s.dataKeys[id]
+	if !ok {
+		return nil, noRows("data_keys", "id=%s", id.String())
+	}
+
+	copied := *dataKey
+	copied.WrappedKey = slices.Clone(dataKey.WrappedKey)
+	return &copied, nil
+}
+
+func (r *EncryptionRepository) CreateDataKey(ctx context.Context, wrapped []byte, masterKeyID string,
+) (*encryption.DataKey, error) {
+	s := r.store
+	s.keysMu.Lock()
+	defer s.keysMu.Unlock()
+
+	now := s.now()
+	for _, dataKey := range s.dataKeys {
+		if dataKey.RetiredAt == nil {
+			dataKey.RetiredAt = &now
+		}
+	}
+
+	dataKey := &encryption.DataKey{
+		ID:          uuid.New(),
+		CreatedAt:   now,
+		WrappedKey:  slices.Clone(wrapped),
+		MasterKeyID: masterKeyID,
+	}
+	s.dataKeys[dataKey.ID] = dataKey
+
+	copied := *dataKey
+	return &copied, nil
+}
+
+// ReencryptColumns seals up to limit values of every sealed column that aren't sealed with the
+// active data key: the note bodies and the webhook secrets
+func (r *EncryptionRepository) ReencryptColumns(ctx context.Context, keyring *envelope.Keyring, limit int) (int64, error) {
+	activeID, err := keyring.ActiveKeyID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bodies := make([]*string, 0, len(s.notes))
+	for _, item := range s.notes {
+		bodies = append(bodies, &item.Body)
+	}
+	secrets := make([]*string, 0, len(s.webhookEndpoints))
+	for _, endpoint := range s.webhookEndpoints {
+		secrets = append(secrets, &endpoint.Secret)
+	}
+
+	var rewritten int64
+	for _, column := range [][]*string{bodies, secrets} {
+		count, err := reencryptValues(ctx, keyring, column, activeID, limit)
+		rewritten += count
+		if err != nil {
+			return rewritten, err
+		}
+	}
+
+	return rewritten, nil
+}
+
+// reencryptValues seals up to limit of the values again with the active data key
+func reencryptValues(ctx context.Context, keyring *envelope.Keyring, values []*string, activeID uuid.UUID,
+	limit int,
+) (int64, error) {
+	var rewritten int64
+	for _, value := range values {
+		if rewritten == int64(limit) {
+			break
+		}
+		if id, ok := envelope.KeyID(*value); ok && id == activeID {
+			continue
+		}
+
+		plaintext, err := keyring.Decrypt(ctx, *value)
+		if err != nil {
+			return rewritten, err
+		}
+		sealed, err := keyring.Encrypt(ctx, plaintext)
+		if err != nil {
+			return rewritten, err
+		}
+
+		*value = sealed
+		rewritten++
+	}
+
+	return rewritten, nil
+}
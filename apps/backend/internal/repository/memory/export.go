@@ -0,0 +1,275 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/envelope"
+	"github.com/Sameer16536/ExecuTask/internal/model/category"
+	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/export"
+	"github.com/Sameer16536/ExecuTask/internal/model/note"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+type ExportRepository struct {
+	store   *Store
+	keyring *envelope.Keyring
+}
+
+func NewExportRepository(store *Store, keyring *envelope.Keyring) *ExportRepository {
+	return &ExportRepository{store: store, keyring: keyring}
+}
+
+// CreateAccountExport queues a new export, a user can't have two in progress at once
+func (r *ExportRepository) CreateAccountExport(ctx context.Context, userID string) (*export.AccountExport, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, exportItem := range s.exports {
+		if exportItem.UserID == userID && exportItem.Kind == export.KindAccount && inProgress(exportItem) {
+			code := errs.CodeExportInProgress
+			return nil, errs.NewConflictError("an account export is already in progress", false, &code)
+		}
+	}
+
+	return s.createExport(userID, export.KindAccount, nil), nil
+}
+
+// CreateTodoListExport queues a PDF of the todos matching the filters, a user can't have two
+// in progress at once
+func (r *ExportRepository) CreateTodoListExport(ctx context.Context, userID string, filters *todo.ExportTodosPDFQuery,
+) (*export.AccountExport, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, exportItem := range s.exports {
+		if exportItem.UserID == userID && exportItem.Kind == export.KindTodoList && inProgress(exportItem) {
+			code := errs.CodeExportInProgress
+			return nil, errs.NewConflictError("a todo list export is already in progress", false, &code)
+		}
+	}
+
+	return s.createExport(userID, export.KindTodoList, filters), nil
+}
+
+func (r *ExportRepository) GetAccountExport(ctx context.Context, userID string, exportID uuid.UUID) (*export.AccountExport, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exportItem, ok := s.exports[exportID]
+	if !ok || exportItem.UserID != userID {
+		code := errs.CodeExportNotFound
+		return nil, errs.NewNotFoundError("account export not found", false, &code)
+	}
+
+	return copyExport(exportItem), nil
+}
+
+// StartAccountExport moves a pending export to running. An export already running was picked
+// up by an attempt that died, it is claimed again. Finished exports are returned unchanged.
+func (r *ExportRepository) StartAccountExport(ctx context.Context, exportID uuid.UUID) (*export.AccountExport, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exportItem, ok := s.exports[exportID]
+	if !ok {
+		code := errs.CodeExportNotFound
+		return nil, errs.NewNotFoundError("account export not found", false, &code)
+	}
+
+	if inProgress(exportItem) {
+		exportItem.Status = export.StatusRunning
+	}
+	exportItem.UpdatedAt = s.now()
+
+	return copyExport(exportItem), nil
+}
+
+func (r *ExportRepository) CompleteAccountExport(ctx context.Context, exportID uuid.UUID, objectKey string,
+	sizeBytes int64, expiresAt time.Time,
+) (*export.AccountExport, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exportItem, ok := s.exports[exportID]
+	if !ok {
+		return nil, noRows("account_exports", "export_id=%s", exportID.String())
+	}
+
+	now := s.now()
+	exportItem.Status = export.StatusCompleted
+	exportItem.ObjectKey = &objectKey
+	exportItem.SizeBytes = &sizeBytes
+	exportItem.Error = nil
+	exportItem.CompletedAt = &now
+	exportItem.ExpiresAt = &expiresAt
+	exportItem.UpdatedAt = now
+
+	return copyExport(exportItem), nil
+}
+
+func (r *ExportRepository) CreateReportExport(ctx context.Context, userID string, filters *todo.ExportTodosPDFQuery,
+	objectKey string, sizeBytes int64, expiresAt time.Time,
+) (*export.AccountExport, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	created := s.createExport(userID, export.KindReport, filters)
+	exportItem := s.exports[created.ID]
+	exportItem.Status = export.StatusCompleted
+	exportItem.ObjectKey = &objectKey
+	exportItem.SizeBytes = &sizeBytes
+	completedAt := exportItem.CreatedAt
+	exportItem.CompletedAt = &completedAt
+	exportItem.ExpiresAt = &expiresAt
+
+	return copyExport(exportItem), nil
+}
+
+// FailAccountExport gives up on an export that is still in progress
+func (r *ExportRepository) FailAccountExport(ctx context.Context, exportID uuid.UUID, reason string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if exportItem, ok := s.exports[exportID]; ok && inProgress(exportItem) {
+		exportItem.Status = export.StatusFailed
+		exportItem.Error = &reason
+		exportItem.UpdatedAt = s.now()
+	}
+
+	return nil
+}
+
+// GetExpiredAccountExports returns completed exports past their expiry whose archive is still stored
+func (r *ExportRepository) GetExpiredAccountExports(ctx context.Context, now time.Time, limit int) ([]export.AccountExport, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exports := []export.AccountExport{}
+	for _, exportItem := range s.exports {
+		if exportItem.Status == export.StatusCompleted && exportItem.ObjectKey != nil &&
+			exportItem.ExpiresAt != nil && !exportItem.ExpiresAt.After(now) {
+			exports = append(exports, *copyExport(exportItem))
+		}
+	}
+
+	slices.SortFunc(exports, func(a, b export.AccountExport) int {
+		return a.ExpiresAt.Compare(*b.ExpiresAt)
+	})
+
+	return exports[:min(limit, len(exports))], nil
+}
+
+// ClearAccountExportObject forgets the archive of an export once it is deleted from S3
+func (r *ExportRepository) ClearAccountExportObject(ctx context.Context, exportID uuid.UUID) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if exportItem, ok := s.exports[exportID]; ok {
+		exportItem.ObjectKey = nil
+		exportItem.UpdatedAt = s.now()
+	}
+
+	return nil
+}
+
+func (r *ExportRepository) GetCategoriesForUser(ctx context.Context, userID string) ([]category.Category, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filterCategories(func(categoryItem *category.Category) bool {
+		return categoryItem.UserID == userID
+	}), nil
+}
+
+func (r *ExportRepository) GetCommentsForUser(ctx context.Context, userID string) ([]comment.Comment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filterComments(func(c *comment.Comment) bool {
+		return c.UserID == userID
+	}), nil
+}
+
+// GetAttachmentsForUser returns the attachments of every todo the user owns, oldest first
+func (r *ExportRepository) GetAttachmentsForUser(ctx context.Context, userID string) ([]todo.TodoAttachment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attachments := s.filterAttachments(func(attachment *todo.TodoAttachment) bool {
+		item, ok := s.todos[attachment.TodoID]
+		return ok && item.UserID == userID
+	})
+	slices.SortFunc(attachments, func(a, b todo.TodoAttachment) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+
+	return attachments, nil
+}
+
+// GetNotesForUser returns the private notes the user wrote, oldest first
+func (r *ExportRepository) GetNotesForUser(ctx context.Context, userID string) ([]note.Note, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	notes := []note.Note{}
+	for key, item := range s.notes {
+		if key.userID == userID {
+			opened, err := openNote(ctx, r.keyring, item)
+			if err != nil {
+				return nil, err
+			}
+			notes = append(notes, *opened)
+		}
+	}
+	slices.SortFunc(notes, func(a, b note.Note) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.TodoID.String(), b.TodoID.String()))
+	})
+
+	return notes, nil
+}
+
+// createExport stores a pending export, the caller holds the lock
+func (s *Store) createExport(userID string, kind export.Kind, filters *todo.ExportTodosPDFQuery) *export.AccountExport {
+	now := s.now()
+	exportItem := &export.AccountExport{
+		UserID:  userID,
+		Kind:    kind,
+		Status:  export.StatusPending,
+		Filters: filters,
+	}
+	exportItem.ID = uuid.New()
+	exportItem.CreatedAt = now
+	exportItem.UpdatedAt = now
+	s.exports[exportItem.ID] = exportItem
+
+	return copyExport(exportItem)
+}
+
+func inProgress(exportItem *export.AccountExport) bool {
+	return exportItem.Status == export.StatusPending || exportItem.Status == export.StatusRunning
+}
+
+func copyExport(exportItem *export.AccountExport) *export.AccountExport {
+	copied := *exportItem
+	return &copied
+}
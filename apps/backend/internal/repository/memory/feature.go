@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/feature"
+	"github.com/google/uuid"
+)
+
+// overrideKey is the unique key of an override
+type overrideKey struct {
+	flag      string
+	scope     feature.Scope
+	subjectID string
+}
+
+type FeatureRepository struct {
+	store *Store
+}
+
+func NewFeatureRepository(store *Store) *FeatureRepository {
+	return &FeatureRepository{store: store}
+}
+
+func (r *FeatureRepository) GetOverridesFor(ctx context.Context, userID, workspaceID string) ([]feature.Override, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filterOverrides(func(override *feature.Override) bool {
+		switch override.Scope {
+		case feature.ScopeUser:
+			return override.SubjectID == userID
+		case feature.ScopeWorkspace:
+			return workspaceID != "" && override.SubjectID == workspaceID
+		default:
+			return true
+		}
+	}), nil
+}
+
+func (r *FeatureRepository) GetOverrides(ctx context.Context) ([]feature.Override, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filterOverrides(func(override *feature.Override) bool { return true }), nil
+}
+
+func (r *FeatureRepository) SetOverride(ctx context.Context, updatedBy string,
+	payload *feature.SetOverridePayload,
+) (*feature.Override, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	key := overrideKey{flag: payload.Flag, scope: payload.Scope, subjectID: payload.SubjectID}
+	override, ok := s.overrides[key]
+	if !ok {
+		override = &feature.Override{
+			Flag:      payload.Flag,
+			Scope:     payload.Scope,
+			SubjectID: payload.SubjectID,
+		}
+		override.ID = uuid.New()
+		override.CreatedAt = now
+		s.overrides[key] = override
+	}
+	override.Enabled = *payload.Enabled
+	override.UpdatedBy = updatedBy
+	override.UpdatedAt = now
+
+	copied := *override
+	return &copied, nil
+}
+
+func (r *FeatureRepository) DeleteOverride(ctx context.Context, payload *feature.DeleteOverridePayload) (*feature.Override, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := overrideKey{flag: payload.Flag, scope: payload.Scope, subjectID: payload.SubjectID}
+	override, ok := s.overrides[key]
+	if !ok {
+		code := errs.CodeFeatureOverrideNotFound
+		return nil, errs.NewNotFoundError("feature flag override not found", false, &code)
+	}
+
+	delete(s.overrides, key)
+	return override, nil
+}
+
+// filterOverrides copies out the overrides matching keep, ordered by flag, scope and subject
+func (s *Store) filterOverrides(keep func(override *feature.Override) bool) []feature.Override {
+	overrides := []feature.Override{}
+	for _, override := range s.overrides {
+		if keep(override) {
+			overrides = append(overrides, *override)
+		}
+	}
+
+	slices.SortFunc(overrides, func(a, b feature.Override) int {
+		return cmp.Or(
+			strings.Compare(a.Flag, b.Flag),
+			strings.Compare(string(a.Scope), string(b.Scope)),
+			strings.Compare(a.SubjectID, b.SubjectID),
+		)
+	})
+
+	return overrides
+}
@@ -0,0 +1,177 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/focus"
+	"github.com/Sameer16536/ExecuTask/internal/model/planner"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+type FocusRepository struct {
+	store *Store
+}
+
+func NewFocusRepository(store *Store) *FocusRepository {
+	return &FocusRepository{store: store}
+}
+
+func (r *FocusRepository) StartSession(ctx context.Context, userID string,
+	payload *focus.StartSessionPayload,
+) (*focus.Session, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.openSession(userID) != nil {
+		code := errs.CodeFocusSessionOpen
+		return nil, errs.NewConflictError("another focus session is still open", false, &code)
+	}
+
+	item, ok := s.todos[payload.TodoID]
+	if !ok || item.UserID != userID {
+		return nil, fmt.Errorf("failed to execute start focus session query for user_id=%s: %w", userID,
+			foreignKeyViolation("focus_sessions", "focus_sessions_todo_id_user_id_fkey",
+				`insert or update on table "focus_sessions" violates foreign key constraint "focus_sessions_todo_id_user_id_fkey"`))
+	}
+
+	now := s.now()
+	session := &focus.Session{
+		UserID:         userID,
+		TodoID:         payload.TodoID,
+		Status:         focus.StatusActive,
+		PlannedMinutes: *payload.PlannedMinutes,
+		ResumedAt:      &now,
+	}
+	session.ID = uuid.New()
+	session.CreatedAt = now
+	session.UpdatedAt = now
+	s.focusSessions[session.ID] = session
+
+	copied := *session
+	return &copied, nil
+}
+
+func (r *FocusRepository) GetSession(ctx context.Context, userID string, sessionID uuid.UUID) (*focus.Session, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.focusSessions[sessionID]
+	if !ok || session.UserID != userID {
+		code := errs.CodeFocusSessionNotFound
+		return nil, errs.NewNotFoundError("focus session not found", false, &code)
+	}
+
+	copied := *session
+	return &copied, nil
+}
+
+func (r *FocusRepository) GetOpenSession(ctx context.Context, userID string) (*focus.Session, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session := s.openSession(userID)
+	if session == nil {
+		code := errs.CodeFocusSessionNotFound
+		return nil, errs.NewNotFoundError("no focus session is open", false, &code)
+	}
+
+	copied := *session
+	return &copied, nil
+}
+
+func (r *FocusRepository) GetSessions(ctx context.Context, userID string, query *focus.GetSessionsQuery) ([]focus.Session, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions := []focus.Session{}
+	for _, session := range s.focusSessions {
+		if session.UserID == userID && (query.TodoID == nil || session.TodoID == *query.TodoID) {
+			sessions = append(sessions, *session)
+		}
+	}
+	slices.SortFunc(sessions, func(a, b focus.Session) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), strings.Compare(b.ID.String(), a.ID.String()))
+	})
+
+	return sessions[:min(*query.Limit, len(sessions))], nil
+}
+
+func (r *FocusRepository) UpdateSession(ctx context.Context, session *focus.Session,
+	from focus.Status,
+) (*focus.Session, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.focusSessions[session.ID]
+	if !ok || stored.UserID != session.UserID || stored.Status != from {
+		code := errs.CodeFocusSessionState
+		return nil, errs.NewConflictError("the focus session changed meanwhile", false, &code)
+	}
+
+	stored.Status = session.Status
+	stored.FocusedSeconds = session.FocusedSeconds
+	stored.ResumedAt = session.ResumedAt
+	stored.CompletedAt = session.CompletedAt
+	stored.UpdatedAt = s.now()
+
+	copied := *stored
+	return &copied, nil
+}
+
+func (s *Store) openSession(userID string) *focus.Session {
+	for _, session := range s.focusSessions {
+		if session.UserID == userID && session.Status != focus.StatusCompleted {
+			return session
+		}
+	}
+	return nil
+}
+
+func (r *FocusRepository) GetEstimateSamples(ctx context.Context, userID string, limit int,
+) ([]planner.EstimateSample, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	actual := map[uuid.UUID]int{}
+	for _, session := range s.focusSessions {
+		if session.UserID == userID && session.Status == focus.StatusCompleted {
+			actual[session.TodoID] += session.FocusedSeconds
+		}
+	}
+
+	completed := []*todo.Todo{}
+	for todoID, seconds := range actual {
+		item, ok := s.todos[todoID]
+		if !ok || seconds == 0 || item.StatusCategory != todo.StatusCompleted || item.CompletedAt == nil ||
+			item.Metadata == nil || item.Metadata.EstimatedMinutes == nil {
+			continue
+		}
+		completed = append(completed, item)
+	}
+	slices.SortFunc(completed, func(a, b *todo.Todo) int {
+		return b.CompletedAt.Compare(*a.CompletedAt)
+	})
+
+	samples := []planner.EstimateSample{}
+	for _, item := range completed[:min(len(completed), limit)] {
+		samples = append(samples, planner.EstimateSample{
+			TodoID:           item.ID,
+			EstimatedMinutes: *item.Metadata.EstimatedMinutes,
+			ActualSeconds:    actual[item.ID],
+		})
+	}
+
+	return samples, nil
+}
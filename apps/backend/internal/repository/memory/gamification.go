@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/change"
+	"github.com/Sameer16536/ExecuTask/internal/model/gamification"
+	"github.com/google/uuid"
+)
+
+type GamificationRepository struct {
+	store *Store
+}
+
+func NewGamificationRepository(store *Store) *GamificationRepository {
+	return &GamificationRepository{store: store}
+}
+
+func (r *GamificationRepository) GetAwards(ctx context.Context, userID string) ([]gamification.Award, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	awards := []gamification.Award{}
+	for _, award := range s.awards {
+		if award.UserID == userID {
+			awards = append(awards, award)
+		}
+	}
+	slices.SortFunc(awards, func(a, b gamification.Award) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return awards, nil
+}
+
+func (r *GamificationRepository) AwardBadge(ctx context.Context, userID string,
+	badge gamification.BadgeCode,
+) (*gamification.Award, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if slices.ContainsFunc(s.awards, func(award gamification.Award) bool {
+		return award.UserID == userID && award.Badge == badge
+	}) {
+		return nil, nil
+	}
+
+	award := gamification.Award{UserID: userID, Badge: badge}
+	award.ID = uuid.New()
+	award.CreatedAt = s.now()
+	s.awards = append(s.awards, award)
+	s.recordChange(userID, "badge", award.ID, change.ActionCreated, nil)
+
+	return &award, nil
+}
+
+func (r *GamificationRepository) GetGoal(ctx context.Context, userID string) (*gamification.Goal, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	goal, ok := s.goals[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	copied := *goal
+	return &copied, nil
+}
+
+func (r *GamificationRepository) SetGoal(ctx context.Context, userID string, target int) (*gamification.Goal, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	goal, ok := s.goals[userID]
+	if !ok {
+		goal = &gamification.Goal{UserID: userID}
+		goal.CreatedAt = now
+		s.goals[userID] = goal
+	}
+	goal.Target = target
+	goal.UpdatedAt = now
+
+	copied := *goal
+	return &copied, nil
+}
+
+func (r *GamificationRepository) DeleteGoal(ctx context.Context, userID string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.goals[userID]; !ok {
+		code := errs.CodeGoalNotFound
+		return errs.NewNotFoundError("weekly goal not found", false, &code)
+	}
+
+	delete(s.goals, userID)
+	return nil
+}
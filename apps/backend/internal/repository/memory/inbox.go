@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/inbox"
+	"github.com/google/uuid"
+)
+
+type InboxRepository struct {
+	store *Store
+}
+
+func NewInboxRepository(store *Store) *InboxRepository {
+	return &InboxRepository{store: store}
+}
+
+func (r *InboxRepository) CaptureItem(ctx context.Context, userID string, payload *inbox.CapturePayload,
+) (*inbox.Item, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := inbox.Item{
+		UserID: userID,
+		Title:  payload.Title,
+	}
+	item.ID = uuid.New()
+	item.CreatedAt = s.now()
+	s.inboxItems = append(s.inboxItems, item)
+
+	return &item, nil
+}
+
+func (r *InboxRepository) GetItems(ctx context.Context, userID string, query *inbox.GetItemsQuery) ([]inbox.Item, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Items are appended as they are captured, already oldest first
+	items := []inbox.Item{}
+	for _, item := range s.inboxItems {
+		if item.UserID != userID {
+			continue
+		}
+		items = append(items, item)
+		if len(items) == *query.Limit {
+			break
+		}
+	}
+
+	return items, nil
+}
+
+func (r *InboxRepository) RemoveItem(ctx context.Context, userID string, itemID uuid.UUID) (*inbox.Item, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.inboxItems, func(item inbox.Item) bool {
+		return item.ID == itemID && item.UserID == userID
+	})
+	if i < 0 {
+		code := errs.CodeInboxItemNotFound
+		return nil, errs.NewNotFoundError("inbox item not found", false, &code)
+	}
+
+	item := s.inboxItems[i]
+	s.inboxItems = slices.Delete(s.inboxItems, i, i+1)
+
+	return &item, nil
+}
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+)
+
+type LocaleRepository struct {
+	store *Store
+}
+
+func NewLocaleRepository(store *Store) *LocaleRepository {
+	return &LocaleRepository{store: store}
+}
+
+func (r *LocaleRepository) GetLocale(ctx context.Context, userID string) (*string, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	locale, ok := s.userLocales[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &locale, nil
+}
+
+func (r *LocaleRepository) SetLocale(ctx context.Context, userID, locale string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.userLocales[userID] = locale
+
+	return nil
+}
+
+func (r *LocaleRepository) DeleteLocale(ctx context.Context, userID string) (bool, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.userLocales[userID]
+	delete(s.userLocales, userID)
+
+	return ok, nil
+}
@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/magictag"
+	"github.com/google/uuid"
+)
+
+type MagicTagRepository struct {
+	store *Store
+}
+
+func NewMagicTagRepository(store *Store) *MagicTagRepository {
+	return &MagicTagRepository{store: store}
+}
+
+// GetMagicTags lists the magic tags of a user by tag
+func (r *MagicTagRepository) GetMagicTags(ctx context.Context, userID string) ([]magictag.MagicTag, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tags := []magictag.MagicTag{}
+	for _, tag := range s.magicTags {
+		if tag.UserID == userID {
+			tags = append(tags, *tag)
+		}
+	}
+	slices.SortFunc(tags, func(a, b magictag.MagicTag) int {
+		return strings.Compare(a.Tag, b.Tag)
+	})
+
+	return tags, nil
+}
+
+func (r *MagicTagRepository) CountMagicTags(ctx context.Context, userID string) (int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, tag := range s.magicTags {
+		if tag.UserID == userID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *MagicTagRepository) CreateMagicTag(ctx context.Context, userID string,
+	definition *magictag.Definition,
+) (*magictag.MagicTag, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkMagicTag(userID, uuid.Nil, definition); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	tag := &magictag.MagicTag{UserID: userID}
+	tag.ID = uuid.New()
+	tag.CreatedAt = now
+	setDefinition(tag, definition)
+	tag.UpdatedAt = now
+	s.magicTags[tag.ID] = tag
+
+	copied := *tag
+	return &copied, nil
+}
+
+func (r *MagicTagRepository) UpdateMagicTag(ctx context.Context, userID string, tagID uuid.UUID,
+	definition *magictag.Definition,
+) (*magictag.MagicTag, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tag, ok := s.magicTags[tagID]
+	if !ok || tag.UserID != userID {
+		return nil, errMagicTagNotFound()
+	}
+	if err := s.checkMagicTag(userID, tagID, definition); err != nil {
+		return nil, err
+	}
+
+	setDefinition(tag, definition)
+	tag.UpdatedAt = s.now()
+
+	copied := *tag
+	return &copied, nil
+}
+
+func (r *MagicTagRepository) DeleteMagicTag(ctx context.Context, userID string, tagID uuid.UUID) (*magictag.MagicTag, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tag, ok := s.magicTags[tagID]
+	if !ok || tag.UserID != userID {
+		return nil, errMagicTagNotFound()
+	}
+	delete(s.magicTags, tagID)
+
+	return tag, nil
+}
+
+// checkMagicTag checks the unique tag per user and the category reference, except for the
+// magic tag with the given id
+func (s *Store) checkMagicTag(userID string, except uuid.UUID, definition *magictag.Definition) error {
+	for _, tag := range s.magicTags {
+		if tag.UserID == userID && tag.Tag == definition.Tag && tag.ID != except {
+			return uniqueViolation("magic_tags", "magic_tags_tag_key")
+		}
+	}
+
+	if definition.CategoryID != nil {
+		if _, ok := s.categories[*definition.CategoryID]; !ok {
+			return foreignKeyViolation("magic_tags", "magic_tags_category_id_fkey",
+				`insert or update on table "magic_tags" violates foreign key constraint "magic_tags_category_id_fkey"`)
+		}
+	}
+
+	return nil
+}
+
+func setDefinition(tag *magictag.MagicTag, definition *magictag.Definition) {
+	tag.Tag = definition.Tag
+	tag.Priority = definition.Priority
+	tag.DueInDays = definition.DueInDays
+	tag.CategoryID = definition.CategoryID
+	tag.Timezone = *definition.Timezone
+}
+
+func errMagicTagNotFound() error {
+	code := errs.CodeMagicTagNotFound
+	return errs.NewNotFoundError("magic tag not found", false, &code)
+}
@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/matrix"
+)
+
+type MatrixRepository struct {
+	store *Store
+}
+
+func NewMatrixRepository(store *Store) *MatrixRepository {
+	return &MatrixRepository{store: store}
+}
+
+func (r *MatrixRepository) GetSettings(ctx context.Context, userID string) (*matrix.Settings, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	settings, ok := s.matrixSettings[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &settings, nil
+}
+
+func (r *MatrixRepository) SetSettings(ctx context.Context, userID string, settings matrix.Settings) (*matrix.Settings, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.matrixSettings[userID] = settings
+
+	return &settings, nil
+}
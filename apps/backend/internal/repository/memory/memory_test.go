@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/envelope"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/repository/storetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreContract(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) *repository.Repositories {
+		return NewRepositories(NewStore())
+	})
+}
+
+func TestNoteBodiesAreSealed(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	repos := NewRepositories(store)
+
+	created, err := repos.Todo.CreateTodo(ctx, "user_test", "user_test", &todo.CreateTodoPayload{Title: "Safe"})
+	require.NoError(t, err)
+	_, err = repos.Note.SetNote(ctx, "user_test", created.ID, "door code 1234")
+	require.NoError(t, err)
+
+	stored := store.notes[noteKey{todoID: created.ID, userID: "user_test"}]
+	require.True(t, envelope.IsSealed(stored.Body))
+	require.NotContains(t, stored.Body, "1234")
+}
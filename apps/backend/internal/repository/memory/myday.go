@@ -0,0 +1,203 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/planner"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+type myDayKey struct {
+	userID string
+	day    string
+	todoID uuid.UUID
+}
+
+type myDayRow struct {
+	endsAt  time.Time
+	state   planner.MyDayState
+	addedAt time.Time
+}
+
+type MyDayRepository struct {
+	store *Store
+}
+
+func NewMyDayRepository(store *Store) *MyDayRepository {
+	return &MyDayRepository{store: store}
+}
+
+func (r *MyDayRepository) AddTodos(ctx context.Context, userID, day string, endsAt time.Time,
+	todoIDs []uuid.UUID,
+) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, todoID := range todoIDs {
+		if item, ok := s.todos[todoID]; !ok || item.UserID != userID {
+			return foreignKeyViolation("my_day_todos", "my_day_todos_todo_id_user_id_fkey",
+				`insert or update on table "my_day_todos" violates foreign key constraint "my_day_todos_todo_id_user_id_fkey"`)
+		}
+	}
+
+	for key, row := range s.myDayTodos {
+		if key.userID == userID && row.state == planner.MyDayReview && slices.Contains(todoIDs, key.todoID) {
+			row.state = planner.MyDayReviewed
+		}
+	}
+
+	now := s.now()
+	for _, todoID := range todoIDs {
+		key := myDayKey{userID: userID, day: day, todoID: todoID}
+		if _, ok := s.myDayTodos[key]; !ok {
+			s.myDayTodos[key] = &myDayRow{endsAt: endsAt, state: planner.MyDayPlanned, addedAt: now}
+		}
+	}
+
+	return nil
+}
+
+func (r *MyDayRepository) RemoveTodo(ctx context.Context, userID, day string, todoID uuid.UUID) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	affected := 0
+	for key, row := range s.myDayTodos {
+		if key.userID != userID || key.todoID != todoID {
+			continue
+		}
+		switch {
+		case key.day == day && row.state == planner.MyDayPlanned:
+			delete(s.myDayTodos, key)
+			affected++
+		case row.state == planner.MyDayReview:
+			row.state = planner.MyDayReviewed
+			affected++
+		}
+	}
+
+	if affected == 0 {
+		code := errs.CodeMyDayTodoNotFound
+		return errs.NewNotFoundError("the todo is not on your day", false, &code)
+	}
+
+	return nil
+}
+
+func (r *MyDayRepository) GetTodos(ctx context.Context, userID, day string) ([]planner.MyDayTodo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todos := []planner.MyDayTodo{}
+	for key, row := range s.myDayTodos {
+		if key.userID != userID || key.day != day {
+			continue
+		}
+		if item, ok := s.todos[key.todoID]; ok {
+			todos = append(todos, planner.MyDayTodo{Todo: copyTodo(item), AddedAt: row.addedAt})
+		}
+	}
+
+	slices.SortFunc(todos, func(a, b planner.MyDayTodo) int {
+		return cmp.Or(a.AddedAt.Compare(b.AddedAt), cmp.Compare(a.SortOrder, b.SortOrder))
+	})
+
+	return todos, nil
+}
+
+func (r *MyDayRepository) GetReview(ctx context.Context, userID string) ([]planner.ReviewTodo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The latest day each todo was left open on
+	latest := map[uuid.UUID]string{}
+	for key, row := range s.myDayTodos {
+		if key.userID != userID || row.state != planner.MyDayReview {
+			continue
+		}
+		if key.day > latest[key.todoID] {
+			latest[key.todoID] = key.day
+		}
+	}
+
+	todos := []planner.ReviewTodo{}
+	for todoID, day := range latest {
+		if item, ok := s.todos[todoID]; ok && item.StatusCategory != todo.StatusCompleted {
+			todos = append(todos, planner.ReviewTodo{Todo: copyTodo(item), PlannedFor: day})
+		}
+	}
+
+	slices.SortFunc(todos, func(a, b planner.ReviewTodo) int {
+		return cmp.Or(cmp.Compare(a.PlannedFor, b.PlannedFor), cmp.Compare(a.SortOrder, b.SortOrder))
+	})
+
+	return todos, nil
+}
+
+func (r *MyDayRepository) GetSummaries(ctx context.Context, userID, from, to string) ([]planner.DaySummary, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byDay := map[string]*planner.DaySummary{}
+	for key, row := range s.myDayTodos {
+		if key.userID != userID || key.day < from || key.day > to {
+			continue
+		}
+		item, ok := s.todos[key.todoID]
+		if !ok {
+			continue
+		}
+
+		summary, ok := byDay[key.day]
+		if !ok {
+			summary = &planner.DaySummary{Date: key.day}
+			byDay[key.day] = summary
+		}
+		summary.Planned++
+		if item.StatusCategory == todo.StatusCompleted && item.CompletedAt != nil && item.CompletedAt.Before(row.endsAt) {
+			summary.Completed++
+		}
+		if row.state != planner.MyDayPlanned {
+			summary.Rolled++
+		}
+	}
+
+	summaries := []planner.DaySummary{}
+	for _, summary := range byDay {
+		summaries = append(summaries, *summary)
+	}
+	slices.SortFunc(summaries, func(a, b planner.DaySummary) int {
+		return cmp.Compare(b.Date, a.Date)
+	})
+
+	return summaries, nil
+}
+
+func (r *MyDayRepository) RollOver(ctx context.Context, now time.Time) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rolled int64
+	for key, row := range s.myDayTodos {
+		if row.state != planner.MyDayPlanned || row.endsAt.After(now) {
+			continue
+		}
+		if item, ok := s.todos[key.todoID]; ok && item.StatusCategory != todo.StatusCompleted {
+			row.state = planner.MyDayReview
+			rolled++
+		}
+	}
+
+	return rolled, nil
+}
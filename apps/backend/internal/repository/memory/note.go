@@ -0,0 +1,160 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/envelope"
+	"github.com/Sameer16536/ExecuTask/internal/model/note"
+	"github.com/google/uuid"
+)
+
+type noteKey struct {
+	todoID uuid.UUID
+	userID string
+}
+
+// NoteRepository keeps the bodies sealed like the Postgres repository does
+type NoteRepository struct {
+	store   *Store
+	keyring *envelope.Keyring
+}
+
+func NewNoteRepository(store *Store, keyring *envelope.Keyring) *NoteRepository {
+	return &NoteRepository{store: store, keyring: keyring}
+}
+
+func (r *NoteRepository) GetNote(ctx context.Context, userID string, todoID uuid.UUID) (*note.Note, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.notes[noteKey{todoID: todoID, userID: userID}]
+	if !ok {
+		return nil, errNoteNotFound()
+	}
+
+	return openNote(ctx, r.keyring, item)
+}
+
+func (r *NoteRepository) SetNote(ctx context.Context, userID string, todoID uuid.UUID, body string) (*note.Note, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if todoItem, ok := s.todos[todoID]; !ok || todoItem.UserID != userID {
+		return nil, foreignKeyViolation("todo_private_notes", "todo_private_notes_todo_id_user_id_fkey",
+			`insert or update on table "todo_private_notes" violates foreign key constraint "todo_private_notes_todo_id_user_id_fkey"`)
+	}
+
+	sealed, err := r.keyring.Seal(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	key := noteKey{todoID: todoID, userID: userID}
+	now := s.now()
+	saved := &note.Note{
+		TodoID:    todoID,
+		UserID:    userID,
+		Body:      sealed,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if existing, ok := s.notes[key]; ok {
+		saved.CreatedAt = existing.CreatedAt
+	}
+	s.notes[key] = saved
+
+	copied := *saved
+	copied.Body = body
+	return &copied, nil
+}
+
+func (r *NoteRepository) DeleteNote(ctx context.Context, userID string, todoID uuid.UUID) (*note.Note, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := noteKey{todoID: todoID, userID: userID}
+	item, ok := s.notes[key]
+	if !ok {
+		return nil, errNoteNotFound()
+	}
+	delete(s.notes, key)
+
+	return openNote(ctx, r.keyring, item)
+}
+
+// openNote copies a stored note with its body opened
+func openNote(ctx context.Context, keyring *envelope.Keyring, item *note.Note) (*note.Note, error) {
+	body, err := keyring.Decrypt(ctx, item.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := *item
+	copied.Body = body
+	return &copied, nil
+}
+
+// mergeNotes appends the notes on the sources to those on the target, oldest first, sealing
+// the joined bodies again. The caller holds the lock.
+func (s *Store) mergeNotes(ctx context.Context, keyring *envelope.Keyring, targetID uuid.UUID,
+	sourceIDs []uuid.UUID,
+) error {
+	merged := []*note.Note{}
+	for key, item := range s.notes {
+		if containsID(sourceIDs, key.todoID) {
+			merged = append(merged, item)
+		}
+	}
+	slices.SortFunc(merged, func(a, b *note.Note) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	bodies := map[noteKey]string{}
+	for _, item := range merged {
+		key := noteKey{todoID: targetID, userID: item.UserID}
+		if _, ok := bodies[key]; !ok {
+			if target, ok := s.notes[key]; ok {
+				opened, err := openNote(ctx, keyring, target)
+				if err != nil {
+					return err
+				}
+				bodies[key] = opened.Body + "\n\n"
+			}
+		}
+
+		opened, err := openNote(ctx, keyring, item)
+		if err != nil {
+			return err
+		}
+		bodies[key] += opened.Body + "\n\n"
+	}
+
+	now := s.now()
+	for key, body := range bodies {
+		sealed, err := keyring.Seal(ctx, strings.TrimSuffix(body, "\n\n"))
+		if err != nil {
+			return err
+		}
+
+		target, ok := s.notes[key]
+		if !ok {
+			s.notes[key] = &note.Note{TodoID: key.todoID, UserID: key.userID, Body: sealed, CreatedAt: now, UpdatedAt: now}
+			continue
+		}
+		target.Body = sealed
+		target.UpdatedAt = now
+	}
+
+	return nil
+}
+
+func errNoteNotFound() error {
+	code := errs.CodeNoteNotFound
+	return errs.NewNotFoundError("the todo has no private note", false, &code)
+}
@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/preview"
+)
+
+type LinkPreviewRepository struct {
+	store *Store
+}
+
+func NewLinkPreviewRepository(store *Store) *LinkPreviewRepository {
+	return &LinkPreviewRepository{store: store}
+}
+
+func (r *LinkPreviewRepository) GetPreviews(ctx context.Context, urls []string) ([]preview.Preview, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previews := []preview.Preview{}
+	for _, url := range urls {
+		if card, ok := s.linkPreviews[url]; ok {
+			previews = append(previews, *card)
+		}
+	}
+
+	return previews, nil
+}
+
+func (r *LinkPreviewRepository) SavePreview(ctx context.Context, card *preview.Preview) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *card
+	s.linkPreviews[card.URL] = &copied
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/report"
+	"github.com/google/uuid"
+)
+
+type ReportRepository struct {
+	store *Store
+}
+
+func NewReportRepository(store *Store) *ReportRepository {
+	return &ReportRepository{store: store}
+}
+
+// GetSchedules lists the report schedules of a user by name
+func (r *ReportRepository) GetSchedules(ctx context.Context, userID string) ([]report.Schedule, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedules := []report.Schedule{}
+	for _, schedule := range s.reportSchedules {
+		if schedule.UserID == userID {
+			schedules = append(schedules, copySchedule(schedule))
+		}
+	}
+	slices.SortFunc(schedules, func(a, b report.Schedule) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), a.CreatedAt.Compare(b.CreatedAt))
+	})
+
+	return schedules, nil
+}
+
+func (r *ReportRepository) GetSchedule(ctx context.Context, userID string, scheduleID uuid.UUID) (*report.Schedule, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, ok := s.reportSchedules[scheduleID]
+	if !ok || schedule.UserID != userID {
+		return nil, errReportScheduleNotFound()
+	}
+
+	copied := copySchedule(schedule)
+	return &copied, nil
+}
+
+func (r *ReportRepository) CountSchedules(ctx context.Context, userID string) (int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, schedule := range s.reportSchedules {
+		if schedule.UserID == userID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *ReportRepository) CreateSchedule(ctx context.Context, userID string, definition *report.Definition,
+	nextRunAt time.Time,
+) (*report.Schedule, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	schedule := &report.Schedule{UserID: userID}
+	schedule.ID = uuid.New()
+	schedule.CreatedAt = now
+	setScheduleDefinition(schedule, definition)
+	schedule.NextRunAt = nextRunAt
+	schedule.UpdatedAt = now
+	s.reportSchedules[schedule.ID] = schedule
+
+	copied := copySchedule(schedule)
+	return &copied, nil
+}
+
+func (r *ReportRepository) UpdateSchedule(ctx context.Context, userID string, scheduleID uuid.UUID,
+	definition *report.Definition, nextRunAt time.Time,
+) (*report.Schedule, error) {
+	return r.updateSchedule(userID, scheduleID, func(schedule *report.Schedule, now time.Time) {
+		setScheduleDefinition(schedule, definition)
+		schedule.NextRunAt = nextRunAt
+	})
+}
+
+func (r *ReportRepository) PauseSchedule(ctx context.Context, userID string, scheduleID uuid.UUID) (*report.Schedule, error) {
+	return r.updateSchedule(userID, scheduleID, func(schedule *report.Schedule, now time.Time) {
+		if schedule.PausedAt == nil {
+			schedule.PausedAt = &now
+		}
+	})
+}
+
+func (r *ReportRepository) ResumeSchedule(ctx context.Context, userID string, scheduleID uuid.UUID,
+	nextRunAt time.Time,
+) (*report.Schedule, error) {
+	return r.updateSchedule(userID, scheduleID, func(schedule *report.Schedule, now time.Time) {
+		schedule.PausedAt = nil
+		schedule.NextRunAt = nextRunAt
+	})
+}
+
+func (r *ReportRepository) DeleteSchedule(ctx context.Context, userID string, scheduleID uuid.UUID) (*report.Schedule, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, ok := s.reportSchedules[scheduleID]
+	if !ok || schedule.UserID != userID {
+		return nil, errReportScheduleNotFound()
+	}
+	delete(s.reportSchedules, scheduleID)
+
+	return schedule, nil
+}
+
+func (r *ReportRepository) GetDueSchedules(ctx context.Context, now time.Time, limit int) ([]report.Schedule, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedules := []report.Schedule{}
+	for _, schedule := range s.reportSchedules {
+		if schedule.PausedAt == nil && !schedule.NextRunAt.After(now) {
+			schedules = append(schedules, copySchedule(schedule))
+		}
+	}
+	slices.SortFunc(schedules, func(a, b report.Schedule) int {
+		return a.NextRunAt.Compare(b.NextRunAt)
+	})
+
+	return schedules[:min(limit, len(schedules))], nil
+}
+
+func (r *ReportRepository) SetNextRun(ctx context.Context, scheduleID uuid.UUID, nextRunAt time.Time) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if schedule, ok := s.reportSchedules[scheduleID]; ok {
+		schedule.NextRunAt = nextRunAt
+		schedule.UpdatedAt = s.now()
+	}
+
+	return nil
+}
+
+func (r *ReportRepository) RecordRun(ctx context.Context, scheduleID uuid.UUID, runAt time.Time, failure *string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if schedule, ok := s.reportSchedules[scheduleID]; ok {
+		schedule.LastRunAt = &runAt
+		schedule.LastError = failure
+		schedule.UpdatedAt = s.now()
+	}
+
+	return nil
+}
+
+func (r *ReportRepository) updateSchedule(userID string, scheduleID uuid.UUID,
+	update func(schedule *report.Schedule, now time.Time),
+) (*report.Schedule, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, ok := s.reportSchedules[scheduleID]
+	if !ok || schedule.UserID != userID {
+		return nil, errReportScheduleNotFound()
+	}
+
+	now := s.now()
+	update(schedule, now)
+	schedule.UpdatedAt = now
+
+	copied := copySchedule(schedule)
+	return &copied, nil
+}
+
+func setScheduleDefinition(schedule *report.Schedule, definition *report.Definition) {
+	schedule.Name = definition.Name
+	schedule.Format = definition.Format
+	schedule.Filters = definition.Filters
+	schedule.Recipients = slices.Clone(definition.Recipients)
+	schedule.Timing = definition.Timing
+}
+
+func copySchedule(schedule *report.Schedule) report.Schedule {
+	copied := *schedule
+	copied.Recipients = slices.Clone(schedule.Recipients)
+	return copied
+}
+
+func errReportScheduleNotFound() error {
+	code := errs.CodeReportScheduleNotFound
+	return errs.NewNotFoundError("report schedule not found", false, &code)
+}
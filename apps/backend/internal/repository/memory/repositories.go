@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/envelope"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+)
+
+// NewRepositories wires every fake to one store, in place of repository.NewRepositories. The
+// keyring wraps its data keys with a random master key.
+func NewRepositories(store *Store) *repository.Repositories {
+	masterKey := make([]byte, 32)
+	_, _ = rand.Read(masterKey)
+	wrapper, _ := envelope.NewLocalWrapper(base64.StdEncoding.EncodeToString(masterKey))
+	encryption := NewEncryptionRepository(store)
+	keyring := envelope.NewKeyring(wrapper, encryption)
+
+	return &repository.Repositories{
+		Tx:           NewTxManager(store),
+		Todo:         NewTodoRepository(store, keyring),
+		Category:     NewCategoryRepository(store),
+		Comment:      NewCommentRepository(store),
+		Change:       NewChangeRepository(store),
+		Admin:        NewAdminRepository(store),
+		Stats:        NewStatsRepository(store),
+		Maintenance:  NewMaintenanceRepository(store),
+		Search:       NewSearchRepository(store),
+		Audit:        NewAuditRepository(store),
+		Feature:      NewFeatureRepository(store),
+		Usage:        NewUsageRepository(store),
+		Export:       NewExportRepository(store, keyring),
+		Retention:    NewRetentionRepository(store),
+		Encryption:   encryption,
+		Backup:       NewBackupRepository(store),
+		Rule:         NewRuleRepository(store),
+		Matrix:       NewMatrixRepository(store),
+		Focus:        NewFocusRepository(store),
+		MyDay:        NewMyDayRepository(store),
+		Gamification: NewGamificationRepository(store),
+		Dependency:   NewDependencyRepository(store),
+		Inbox:        NewInboxRepository(store),
+		Workspace:    NewWorkspaceRepository(store),
+		LinkPreview:  NewLinkPreviewRepository(store),
+		Cover:        NewCoverRepository(store),
+		Note:         NewNoteRepository(store, keyring),
+		MagicTag:     NewMagicTagRepository(store),
+		Locale:       NewLocaleRepository(store),
+		StatusPage:   NewStatusPageRepository(store),
+		APIKey:       NewAPIKeyRepository(store),
+		APIClient:    NewAPIClientRepository(store),
+		Report:       NewReportRepository(store),
+		Webhook:      NewWebhookRepository(store, keyring),
+		Keyring:      keyring,
+	}
+}
+
+var (
+	_ repository.TxManager         = (*TxManager)(nil)
+	_ repository.TodoStore         = (*TodoRepository)(nil)
+	_ repository.CategoryStore     = (*CategoryRepository)(nil)
+	_ repository.CommentStore      = (*CommentRepository)(nil)
+	_ repository.ChangeStore       = (*ChangeRepository)(nil)
+	_ repository.AdminStore        = (*AdminRepository)(nil)
+	_ repository.StatsStore        = (*StatsRepository)(nil)
+	_ repository.MaintenanceStore  = (*MaintenanceRepository)(nil)
+	_ repository.SearchStore       = (*SearchRepository)(nil)
+	_ repository.AuditStore        = (*AuditRepository)(nil)
+	_ repository.FeatureStore      = (*FeatureRepository)(nil)
+	_ repository.UsageStore        = (*UsageRepository)(nil)
+	_ repository.ExportStore       = (*ExportRepository)(nil)
+	_ repository.RetentionStore    = (*RetentionRepository)(nil)
+	_ repository.EncryptionStore   = (*EncryptionRepository)(nil)
+	_ repository.BackupStore       = (*BackupRepository)(nil)
+	_ repository.RuleStore         = (*RuleRepository)(nil)
+	_ repository.MatrixStore       = (*MatrixRepository)(nil)
+	_ repository.FocusStore        = (*FocusRepository)(nil)
+	_ repository.MyDayStore        = (*MyDayRepository)(nil)
+	_ repository.GamificationStore = (*GamificationRepository)(nil)
+	_ repository.DependencyStore   = (*DependencyRepository)(nil)
+	_ repository.InboxStore        = (*InboxRepository)(nil)
+	_ repository.WorkspaceStore    = (*WorkspaceRepository)(nil)
+	_ repository.LinkPreviewStore  = (*LinkPreviewRepository)(nil)
+	_ repository.CoverStore        = (*CoverRepository)(nil)
+	_ repository.NoteStore         = (*NoteRepository)(nil)
+	_ repository.MagicTagStore     = (*MagicTagRepository)(nil)
+	_ repository.LocaleStore       = (*LocaleRepository)(nil)
+	_ repository.StatusPageStore   = (*StatusPageRepository)(nil)
+	_ repository.APIKeyStore       = (*APIKeyRepository)(nil)
+	_ repository.APIClientStore    = (*APIClientRepository)(nil)
+	_ repository.ReportStore       = (*ReportRepository)(nil)
+	_ repository.WebhookStore      = (*WebhookRepository)(nil)
+)
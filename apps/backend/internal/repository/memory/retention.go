@@ -0,0 +1,280 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/retention"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+type RetentionRepository struct {
+	store *Store
+}
+
+func NewRetentionRepository(store *Store) *RetentionRepository {
+	return &RetentionRepository{store: store}
+}
+
+func (r *RetentionRepository) GetPolicies(ctx context.Context) ([]retention.Policy, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	policies := make([]retention.Policy, 0, len(s.retentionPolicies))
+	for _, policy := range s.retentionPolicies {
+		policies = append(policies, *policy)
+	}
+	slices.SortFunc(policies, func(a, b retention.Policy) int {
+		return strings.Compare(a.WorkspaceID, b.WorkspaceID)
+	})
+
+	return policies, nil
+}
+
+func (r *RetentionRepository) GetPolicy(ctx context.Context, workspaceID string) (*retention.Policy, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	policy, ok := s.retentionPolicies[workspaceID]
+	if !ok {
+		code := errs.CodeRetentionPolicyNotFound
+		return nil, errs.NewNotFoundError("retention policy not found", false, &code)
+	}
+
+	copied := *policy
+	return &copied, nil
+}
+
+func (r *RetentionRepository) SetPolicy(ctx context.Context, updatedBy string,
+	payload *retention.SetPolicyPayload,
+) (*retention.Policy, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	policy, ok := s.retentionPolicies[payload.WorkspaceID]
+	if !ok {
+		policy = &retention.Policy{WorkspaceID: payload.WorkspaceID}
+		policy.CreatedAt = now
+		s.retentionPolicies[payload.WorkspaceID] = policy
+	}
+
+	policy.ArchivedTodoMonths = payload.ArchivedTodoMonths
+	policy.MaxCommentsPerTodo = payload.MaxCommentsPerTodo
+	policy.AttachmentDays = payload.AttachmentDays
+	policy.Enforce = *payload.Enforce
+	policy.UpdatedBy = updatedBy
+	policy.UpdatedAt = now
+
+	copied := *policy
+	return &copied, nil
+}
+
+func (r *RetentionRepository) DeletePolicy(ctx context.Context, workspaceID string) (*retention.Policy, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	policy, ok := s.retentionPolicies[workspaceID]
+	if !ok {
+		code := errs.CodeRetentionPolicyNotFound
+		return nil, errs.NewNotFoundError("retention policy not found", false, &code)
+	}
+
+	delete(s.retentionPolicies, workspaceID)
+	return policy, nil
+}
+
+func (r *RetentionRepository) PreviewRetention(ctx context.Context, workspaceID string, rules retention.Rules,
+	now time.Time,
+) (*retention.Report, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := &retention.Report{
+		WorkspaceID: workspaceID,
+		Rules:       rules,
+	}
+
+	if archivedBefore := rules.ArchivedBefore(now); archivedBefore != nil {
+		report.ArchivedTodos = int64(len(s.purgeableTodos(workspaceID, *archivedBefore)))
+	}
+	if rules.MaxCommentsPerTodo != nil {
+		report.Comments = int64(len(s.excessComments(workspaceID, *rules.MaxCommentsPerTodo)))
+	}
+	if uploadedBefore := rules.UploadedBefore(now); uploadedBefore != nil {
+		for _, attachment := range s.expiredAttachments(workspaceID, *uploadedBefore) {
+			report.Attachments++
+			if attachment.FileSize != nil {
+				report.AttachmentBytes += *attachment.FileSize
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func (r *RetentionRepository) PurgeArchivedTodos(ctx context.Context, workspaceID string, archivedBefore time.Time,
+	limit int,
+) (int64, []string, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purgeable := s.purgeableTodos(workspaceID, archivedBefore)
+	purgeable = purgeable[:min(limit, len(purgeable))]
+
+	downloadKeys := []string{}
+	for _, item := range purgeable {
+		for _, attachment := range s.attachments {
+			if attachment.TodoID == item.ID {
+				downloadKeys = append(downloadKeys, attachment.DownloadKey)
+			}
+		}
+		s.deleteTodo(item)
+	}
+
+	return int64(len(purgeable)), downloadKeys, nil
+}
+
+func (r *RetentionRepository) TrimComments(ctx context.Context, workspaceID string, maxPerTodo, limit int) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	excess := s.excessComments(workspaceID, maxPerTodo)
+	excess = excess[:min(limit, len(excess))]
+
+	for _, commentItem := range excess {
+		s.deleteComment(commentItem)
+	}
+
+	return int64(len(excess)), nil
+}
+
+func (r *RetentionRepository) GetExpiredAttachments(ctx context.Context, workspaceID string, uploadedBefore time.Time,
+	limit int,
+) ([]todo.TodoAttachment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expired := s.expiredAttachments(workspaceID, uploadedBefore)
+	expired = expired[:min(limit, len(expired))]
+
+	attachments := make([]todo.TodoAttachment, len(expired))
+	for i, attachment := range expired {
+		attachments[i] = *attachment
+	}
+
+	return attachments, nil
+}
+
+func (r *RetentionRepository) DeleteAttachments(ctx context.Context, attachmentIDs []uuid.UUID) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for _, id := range attachmentIDs {
+		if _, ok := s.attachments[id]; ok {
+			delete(s.attachments, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// inRetentionScope tells whether a policy covers a todo: the todos created in its workspace,
+// or for the default policy the personal ones and those of workspaces without a policy
+func (s *Store) inRetentionScope(item *todo.Todo, workspaceID string) bool {
+	if workspaceID != "" {
+		return item.WorkspaceID != nil && *item.WorkspaceID == workspaceID
+	}
+	if item.WorkspaceID == nil {
+		return true
+	}
+	_, ok := s.retentionPolicies[*item.WorkspaceID]
+	return !ok
+}
+
+// purgeableTodos are the archived leaf todos last touched before the cutoff, least recently
+// touched first
+func (s *Store) purgeableTodos(workspaceID string, archivedBefore time.Time) []*todo.Todo {
+	var purgeable []*todo.Todo
+	for _, item := range s.todos {
+		if item.StatusCategory != todo.StatusArchived || !item.UpdatedAt.Before(archivedBefore) ||
+			!s.inRetentionScope(item, workspaceID) || s.hasChildren(item) {
+			continue
+		}
+		purgeable = append(purgeable, item)
+	}
+
+	slices.SortFunc(purgeable, func(a, b *todo.Todo) int {
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	})
+
+	return purgeable
+}
+
+func (s *Store) hasChildren(item *todo.Todo) bool {
+	for _, child := range s.todos {
+		if child.ParentTodoID != nil && *child.ParentTodoID == item.ID && child.UserID == item.UserID {
+			return true
+		}
+	}
+	return false
+}
+
+// excessComments are the comments beyond the newest maxPerTodo of their todo
+func (s *Store) excessComments(workspaceID string, maxPerTodo int) []*comment.Comment {
+	byTodo := map[uuid.UUID][]*comment.Comment{}
+	for _, commentItem := range s.comments {
+		item, ok := s.todos[commentItem.TodoID]
+		if !ok || !s.inRetentionScope(item, workspaceID) {
+			continue
+		}
+		byTodo[commentItem.TodoID] = append(byTodo[commentItem.TodoID], commentItem)
+	}
+
+	var excess []*comment.Comment
+	for _, comments := range byTodo {
+		slices.SortFunc(comments, func(a, b *comment.Comment) int {
+			return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), strings.Compare(b.ID.String(), a.ID.String()))
+		})
+		if len(comments) > maxPerTodo {
+			excess = append(excess, comments[maxPerTodo:]...)
+		}
+	}
+
+	return excess
+}
+
+// expiredAttachments are the attachments uploaded before the cutoff, oldest first
+func (s *Store) expiredAttachments(workspaceID string, uploadedBefore time.Time) []*todo.TodoAttachment {
+	var expired []*todo.TodoAttachment
+	for _, attachment := range s.attachments {
+		item, ok := s.todos[attachment.TodoID]
+		if !ok || !attachment.CreatedAt.Before(uploadedBefore) || !s.inRetentionScope(item, workspaceID) {
+			continue
+		}
+		expired = append(expired, attachment)
+	}
+
+	slices.SortFunc(expired, func(a, b *todo.TodoAttachment) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return expired
+}
@@ -0,0 +1,229 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/rule"
+	"github.com/google/uuid"
+)
+
+type RuleRepository struct {
+	store *Store
+}
+
+func NewRuleRepository(store *Store) *RuleRepository {
+	return &RuleRepository{store: store}
+}
+
+func (r *RuleRepository) CreateRule(ctx context.Context, userID string, payload *rule.CreateRulePayload) (*rule.Rule, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	ruleItem := &rule.Rule{
+		UserID:     userID,
+		Name:       payload.Name,
+		Trigger:    payload.Trigger,
+		Conditions: *payload.Conditions,
+		Actions:    slices.Clone(payload.Actions),
+		Enabled:    *payload.Enabled,
+	}
+	ruleItem.ID = uuid.New()
+	ruleItem.CreatedAt = now
+	ruleItem.UpdatedAt = now
+	s.rules[ruleItem.ID] = ruleItem
+
+	return copyRule(ruleItem), nil
+}
+
+// GetRules lists the rules of a user, oldest first
+func (r *RuleRepository) GetRules(ctx context.Context, userID string) ([]rule.Rule, error) {
+	return r.rulesWhere(func(ruleItem *rule.Rule) bool {
+		return ruleItem.UserID == userID
+	}), nil
+}
+
+// GetEnabledRules lists the enabled rules of a user listening to the trigger, oldest first
+func (r *RuleRepository) GetEnabledRules(ctx context.Context, userID string, trigger rule.Trigger) ([]rule.Rule, error) {
+	return r.rulesWhere(func(ruleItem *rule.Rule) bool {
+		return ruleItem.UserID == userID && ruleItem.Trigger == trigger && ruleItem.Enabled
+	}), nil
+}
+
+func (r *RuleRepository) rulesWhere(match func(ruleItem *rule.Rule) bool) []rule.Rule {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rules := []rule.Rule{}
+	for _, ruleItem := range s.rules {
+		if match(ruleItem) {
+			rules = append(rules, *copyRule(ruleItem))
+		}
+	}
+
+	slices.SortFunc(rules, func(a, b rule.Rule) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+
+	return rules
+}
+
+// CountRules counts the rules of a user, disabled ones included
+func (r *RuleRepository) CountRules(ctx context.Context, userID string) (int, error) {
+	rules, _ := r.GetRules(ctx, userID)
+	return len(rules), nil
+}
+
+// GetUserIDsWithEnabledRules lists the users with an enabled rule listening to the trigger
+func (r *RuleRepository) GetUserIDsWithEnabledRules(ctx context.Context, trigger rule.Trigger) ([]string, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	userIDs := []string{}
+	for _, ruleItem := range s.rules {
+		if ruleItem.Trigger == trigger && ruleItem.Enabled && !slices.Contains(userIDs, ruleItem.UserID) {
+			userIDs = append(userIDs, ruleItem.UserID)
+		}
+	}
+	slices.Sort(userIDs)
+
+	return userIDs, nil
+}
+
+func (r *RuleRepository) GetRule(ctx context.Context, userID string, ruleID uuid.UUID) (*rule.Rule, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ruleItem, err := s.userRule(userID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return copyRule(ruleItem), nil
+}
+
+func (r *RuleRepository) UpdateRule(ctx context.Context, userID string, payload *rule.UpdateRulePayload) (*rule.Rule, error) {
+	if payload.Name == nil && payload.Trigger == nil && payload.Conditions == nil && payload.Actions == nil &&
+		payload.Enabled == nil {
+		return nil, errs.NewBadRequestError("no fields to update", false, nil, nil, nil)
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ruleItem, err := s.userRule(userID, payload.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.Name != nil {
+		ruleItem.Name = *payload.Name
+	}
+	if payload.Trigger != nil {
+		ruleItem.Trigger = *payload.Trigger
+	}
+	if payload.Conditions != nil {
+		ruleItem.Conditions = *payload.Conditions
+	}
+	if payload.Actions != nil {
+		ruleItem.Actions = slices.Clone(payload.Actions)
+	}
+	if payload.Enabled != nil {
+		ruleItem.Enabled = *payload.Enabled
+	}
+	ruleItem.UpdatedAt = s.now()
+
+	return copyRule(ruleItem), nil
+}
+
+// DeleteRule removes a rule with its execution log and returns it as it was
+func (r *RuleRepository) DeleteRule(ctx context.Context, userID string, ruleID uuid.UUID) (*rule.Rule, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ruleItem, err := s.userRule(userID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(s.rules, ruleID)
+	s.ruleExecutions = slices.DeleteFunc(s.ruleExecutions, func(execution rule.Execution) bool {
+		return execution.RuleID == ruleID
+	})
+
+	return ruleItem, nil
+}
+
+func (s *Store) userRule(userID string, ruleID uuid.UUID) (*rule.Rule, error) {
+	ruleItem, ok := s.rules[ruleID]
+	if !ok || ruleItem.UserID != userID {
+		code := errs.CodeRuleNotFound
+		return nil, errs.NewNotFoundError("rule not found", false, &code)
+	}
+	return ruleItem, nil
+}
+
+func (r *RuleRepository) RecordExecution(ctx context.Context, execution *rule.Execution) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	execution.ID = uuid.New()
+	execution.CreatedAt = s.now()
+
+	copied := *execution
+	copied.ActionsApplied = slices.Clone(execution.ActionsApplied)
+	s.ruleExecutions = append(s.ruleExecutions, copied)
+
+	return nil
+}
+
+// GetExecutions lists the latest runs of a rule of the user, newest first
+func (r *RuleRepository) GetExecutions(ctx context.Context, userID string, ruleID uuid.UUID,
+	limit int,
+) ([]rule.Execution, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	executions := []rule.Execution{}
+	for _, execution := range s.ruleExecutions {
+		if execution.RuleID == ruleID && execution.UserID == userID {
+			executions = append(executions, execution)
+		}
+	}
+
+	// Appended in order, so the newest are last
+	slices.Reverse(executions)
+
+	return executions[:min(limit, len(executions))], nil
+}
+
+// HasExecution tells whether the rule already ran against the todo off the trigger, skipped
+// runs don't count
+func (r *RuleRepository) HasExecution(ctx context.Context, ruleID, todoID uuid.UUID, trigger rule.Trigger) (bool, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.ContainsFunc(s.ruleExecutions, func(execution rule.Execution) bool {
+		return execution.RuleID == ruleID && execution.TodoID == todoID && execution.Trigger == trigger &&
+			execution.Status != rule.ExecutionSkipped
+	}), nil
+}
+
+func copyRule(ruleItem *rule.Rule) *rule.Rule {
+	copied := *ruleItem
+	copied.Actions = slices.Clone(ruleItem.Actions)
+	return &copied
+}
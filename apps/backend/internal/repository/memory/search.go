@@ -0,0 +1,288 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/search"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+type embeddingRow struct {
+	userID      string
+	model       string
+	contentHash string
+	vector      []float32
+}
+
+type SearchRepository struct {
+	store *Store
+}
+
+func NewSearchRepository(store *Store) *SearchRepository {
+	return &SearchRepository{store: store}
+}
+
+// SearchTodos matches the query as a case insensitive substring, see searchScore
+func (r *SearchRepository) SearchTodos(ctx context.Context, userID, query string, limit int) ([]search.Result, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := []search.Result{}
+	for _, item := range s.filterTodos(func(item *todo.Todo) bool { return item.UserID == userID }) {
+		if score := searchScore(&item, query); score > 0 {
+			results = append(results, search.Result{Todo: item, Score: score})
+		}
+	}
+
+	slices.SortStableFunc(results, func(a, b search.Result) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), b.CreatedAt.Compare(a.CreatedAt))
+	})
+
+	return results[:min(limit, len(results))], nil
+}
+
+func (r *SearchRepository) SearchTodosByEmbedding(ctx context.Context, userID, model string, vector []float32,
+	minSimilarity float64, limit int,
+) ([]search.Result, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := []search.Result{}
+	for todoID, row := range s.embeddings {
+		item, ok := s.todos[todoID]
+		if !ok || item.UserID != userID || row.userID != userID || row.model != model {
+			continue
+		}
+
+		if similarity := cosineSimilarity(row.vector, vector); similarity >= minSimilarity {
+			results = append(results, search.Result{Todo: copyTodo(item), Score: similarity})
+		}
+	}
+
+	slices.SortFunc(results, func(a, b search.Result) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.SortOrder, b.SortOrder))
+	})
+
+	return results[:min(limit, len(results))], nil
+}
+
+// SuggestCandidates matches the query as a case insensitive substring, like the Postgres
+// repository
+func (r *SearchRepository) SuggestCandidates(ctx context.Context, userID, query string, limit int) ([]search.Candidate, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query = strings.ToLower(query)
+	match := func(label string) (bool, bool) {
+		label = strings.ToLower(label)
+		return strings.Contains(label, query), strings.HasPrefix(label, query)
+	}
+
+	todos := []search.Candidate{}
+	categories := map[uuid.UUID]*search.Candidate{}
+	tags := map[string]*search.Candidate{}
+	for _, item := range s.todos {
+		if item.UserID != userID {
+			continue
+		}
+
+		if ok, prefix := match(item.Title); ok && item.StatusCategory != todo.StatusArchived {
+			todoID := item.ID
+			key := viewKey{todoID: todoID, userID: userID}
+			lastUsedAt := item.UpdatedAt
+			if viewedAt := s.views[key]; viewedAt.After(lastUsedAt) {
+				lastUsedAt = viewedAt
+			}
+			todos = append(todos, search.Candidate{
+				Type:       search.SuggestionTodo,
+				ID:         &todoID,
+				Label:      item.Title,
+				Prefix:     prefix,
+				Uses:       s.viewCounts[key] + 1,
+				LastUsedAt: lastUsedAt,
+			})
+		}
+
+		if item.CategoryID != nil {
+			if candidate, ok := categories[*item.CategoryID]; ok {
+				candidate.Uses++
+				candidate.LastUsedAt = laterOf(candidate.LastUsedAt, item.UpdatedAt)
+			} else {
+				categories[*item.CategoryID] = &search.Candidate{Uses: 1, LastUsedAt: item.UpdatedAt}
+			}
+		}
+
+		if item.Metadata == nil {
+			continue
+		}
+		for _, tag := range item.Metadata.Tags {
+			ok, prefix := match(tag)
+			if !ok {
+				continue
+			}
+			key := strings.ToLower(tag)
+			if candidate, ok := tags[key]; ok {
+				candidate.Uses++
+				candidate.LastUsedAt = laterOf(candidate.LastUsedAt, item.UpdatedAt)
+				candidate.Label = min(candidate.Label, tag)
+			} else {
+				tags[key] = &search.Candidate{
+					Type:       search.SuggestionTag,
+					Label:      tag,
+					Prefix:     prefix,
+					Uses:       1,
+					LastUsedAt: item.UpdatedAt,
+				}
+			}
+		}
+	}
+
+	found := []search.Candidate{}
+	for _, item := range s.categories {
+		ok, prefix := match(item.Name)
+		if item.UserID != userID || !ok {
+			continue
+		}
+
+		categoryID := item.ID
+		candidate := search.Candidate{
+			Type:       search.SuggestionCategory,
+			ID:         &categoryID,
+			Label:      item.Name,
+			Prefix:     prefix,
+			LastUsedAt: item.UpdatedAt,
+		}
+		if used, ok := categories[item.ID]; ok {
+			candidate.Uses = used.Uses
+			candidate.LastUsedAt = laterOf(candidate.LastUsedAt, used.LastUsedAt)
+		}
+		found = append(found, candidate)
+	}
+
+	candidates := latestUsed(todos, limit)
+	candidates = append(candidates, latestUsed(found, limit)...)
+	found = []search.Candidate{}
+	for _, candidate := range tags {
+		found = append(found, *candidate)
+	}
+	candidates = append(candidates, latestUsed(found, limit)...)
+
+	return candidates, nil
+}
+
+// latestUsed keeps the limit candidates used last
+func latestUsed(candidates []search.Candidate, limit int) []search.Candidate {
+	slices.SortFunc(candidates, func(a, b search.Candidate) int {
+		return b.LastUsedAt.Compare(a.LastUsedAt)
+	})
+	return candidates[:min(limit, len(candidates))]
+}
+
+func laterOf(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// CRON REQUIREMENTS
+
+func (r *SearchRepository) GetPendingEmbeddings(ctx context.Context, model string, limit int) ([]search.PendingEmbedding, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := s.filterTodos(func(item *todo.Todo) bool {
+		row, ok := s.embeddings[item.ID]
+		return !ok || row.model != model || row.contentHash != contentHash(embeddingText(item))
+	})
+	slices.SortStableFunc(items, func(a, b todo.Todo) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+
+	pending := make([]search.PendingEmbedding, 0, min(limit, len(items)))
+	for _, item := range items[:min(limit, len(items))] {
+		text := embeddingText(&item)
+		pending = append(pending, search.PendingEmbedding{
+			TodoID:      item.ID,
+			UserID:      item.UserID,
+			Text:        text,
+			ContentHash: contentHash(text),
+		})
+	}
+
+	return pending, nil
+}
+
+func (r *SearchRepository) UpsertEmbeddings(ctx context.Context, model string, pending []search.PendingEmbedding, vectors [][]float32) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, item := range pending {
+		s.embeddings[item.TodoID] = &embeddingRow{
+			userID:      item.UserID,
+			model:       model,
+			contentHash: item.ContentHash,
+			vector:      slices.Clone(vectors[i]),
+		}
+	}
+
+	return nil
+}
+
+func (r *SearchRepository) DeleteOrphanedEmbeddings(ctx context.Context) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for todoID, row := range s.embeddings {
+		if item, ok := s.todos[todoID]; !ok || item.UserID != row.userID {
+			delete(s.embeddings, todoID)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// embeddingText is what gets embedded for a todo, like todoEmbeddingText of the Postgres repository
+func embeddingText(item *todo.Todo) string {
+	return item.Title + "\n\n" + item.Description
+}
+
+func contentHash(text string) string {
+	sum := md5.Sum([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// cosineSimilarity is 1 minus the cosine distance of pgvector's <=> operator
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
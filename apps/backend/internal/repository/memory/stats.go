@@ -0,0 +1,377 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/focus"
+	"github.com/Sameer16536/ExecuTask/internal/model/stats"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+type StatsRepository struct {
+	store *Store
+}
+
+func NewStatsRepository(store *Store) *StatsRepository {
+	return &StatsRepository{store: store}
+}
+
+// GetSummary returns the user's totals, nil when the user had no todos at the last refresh
+func (r *StatsRepository) GetSummary(ctx context.Context, userID string) (*stats.Summary, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	summary, ok := s.summaries[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &summary, nil
+}
+
+func (r *StatsRepository) GetDailyCompletions(ctx context.Context, userID string, since time.Time) ([]stats.DailyCompletions, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Days are ISO dates, they sort as text
+	sinceDay := since.UTC().Format(time.DateOnly)
+	days := []stats.DailyCompletions{}
+	for _, day := range s.dailyCompletions[userID] {
+		if day.Day >= sinceDay {
+			days = append(days, day)
+		}
+	}
+
+	return days, nil
+}
+
+func (r *StatsRepository) GetCategoryStats(ctx context.Context, userID string) ([]stats.CategoryStats, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Names and colors are joined at read time, categories deleted since the refresh drop out
+	categories := []stats.CategoryStats{}
+	for _, categoryStats := range s.categoryStats[userID] {
+		categoryItem, ok := s.categories[categoryStats.CategoryID]
+		if !ok {
+			continue
+		}
+		categoryStats.Name = categoryItem.Name
+		categoryStats.Color = categoryItem.Color
+		categories = append(categories, categoryStats)
+	}
+
+	slices.SortFunc(categories, func(a, b stats.CategoryStats) int {
+		return cmp.Or(cmp.Compare(b.Total, a.Total), strings.Compare(a.Name, b.Name))
+	})
+
+	return categories, nil
+}
+
+// PRODUCTIVITY REQUIREMENTS
+
+// completedSince lists the todos of the user completed since the given date
+func (s *Store) completedSince(userID string, since time.Time) []*todo.Todo {
+	items := []*todo.Todo{}
+	for _, item := range s.todos {
+		if item.UserID == userID && item.CompletedAt != nil && !item.CompletedAt.Before(since) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (r *StatsRepository) GetCompletionDays(ctx context.Context, userID string, since time.Time) ([]string, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	days := []string{}
+	for _, item := range s.completedSince(userID, since) {
+		day := item.CompletedAt.UTC().Format(time.DateOnly)
+		if !slices.Contains(days, day) {
+			days = append(days, day)
+		}
+	}
+	slices.Sort(days)
+
+	return days, nil
+}
+
+func (r *StatsRepository) GetCompletionHeatmap(ctx context.Context, userID string, since time.Time) ([]stats.HeatmapCell, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type slot struct{ weekday, hour int }
+	counts := map[slot]int{}
+	for _, item := range s.completedSince(userID, since) {
+		completedAt := item.CompletedAt.UTC()
+		counts[slot{int(completedAt.Weekday()), completedAt.Hour()}]++
+	}
+
+	cells := []stats.HeatmapCell{}
+	for key, completed := range counts {
+		cells = append(cells, stats.HeatmapCell{Weekday: key.weekday, Hour: key.hour, Completed: completed})
+	}
+	slices.SortFunc(cells, func(a, b stats.HeatmapCell) int {
+		return cmp.Or(cmp.Compare(a.Weekday, b.Weekday), cmp.Compare(a.Hour, b.Hour))
+	})
+
+	return cells, nil
+}
+
+func (r *StatsRepository) GetCompletionsByPriority(ctx context.Context, userID string,
+	since time.Time,
+) ([]stats.PriorityCompletion, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seconds := map[todo.Priority]float64{}
+	levels := map[todo.Priority]int{}
+	byPriority := map[todo.Priority]*stats.PriorityCompletion{}
+	for _, item := range s.completedSince(userID, since) {
+		if byPriority[item.Priority] == nil {
+			byPriority[item.Priority] = &stats.PriorityCompletion{Priority: item.Priority}
+			levels[item.Priority] = item.PriorityLevel
+		}
+		levels[item.Priority] = min(levels[item.Priority], item.PriorityLevel)
+		byPriority[item.Priority].Completed++
+		seconds[item.Priority] += item.CompletedAt.Sub(item.CreatedAt).Seconds()
+	}
+
+	priorities := []stats.PriorityCompletion{}
+	for priority, completion := range byPriority {
+		average := seconds[priority] / float64(completion.Completed)
+		completion.AverageCompletionSeconds = &average
+		priorities = append(priorities, *completion)
+	}
+	slices.SortFunc(priorities, func(a, b stats.PriorityCompletion) int {
+		return cmp.Or(cmp.Compare(levels[a.Priority], levels[b.Priority]),
+			strings.Compare(string(a.Priority), string(b.Priority)))
+	})
+
+	return priorities, nil
+}
+
+func (r *StatsRepository) GetCompletionsByCategory(ctx context.Context, userID string,
+	since time.Time,
+) ([]stats.CategoryCompletion, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seconds := map[uuid.UUID]float64{}
+	byCategory := map[uuid.UUID]*stats.CategoryCompletion{}
+	for _, item := range s.completedSince(userID, since) {
+		if item.CategoryID == nil {
+			continue
+		}
+		categoryItem, ok := s.categories[*item.CategoryID]
+		if !ok {
+			continue
+		}
+		if byCategory[categoryItem.ID] == nil {
+			byCategory[categoryItem.ID] = &stats.CategoryCompletion{
+				CategoryID: categoryItem.ID,
+				Name:       categoryItem.Name,
+				Color:      categoryItem.Color,
+			}
+		}
+		byCategory[categoryItem.ID].Completed++
+		seconds[categoryItem.ID] += item.CompletedAt.Sub(item.CreatedAt).Seconds()
+	}
+
+	categories := []stats.CategoryCompletion{}
+	for categoryID, completion := range byCategory {
+		average := seconds[categoryID] / float64(completion.Completed)
+		completion.AverageCompletionSeconds = &average
+		categories = append(categories, *completion)
+	}
+	slices.SortFunc(categories, func(a, b stats.CategoryCompletion) int {
+		return cmp.Or(cmp.Compare(b.Completed, a.Completed), strings.Compare(a.Name, b.Name))
+	})
+
+	return categories, nil
+}
+
+func (r *StatsRepository) GetOverdueRatio(ctx context.Context, userID string, since, now time.Time) (*stats.OverdueRatio, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ratio := &stats.OverdueRatio{}
+	for _, item := range s.todos {
+		if item.UserID != userID || item.DueDate == nil || item.DueDate.Before(since) || !item.DueDate.Before(now) {
+			continue
+		}
+		ratio.Due++
+		if item.CompletedAt == nil || item.CompletedAt.After(*item.DueDate) {
+			ratio.Overdue++
+		}
+	}
+
+	return ratio, nil
+}
+
+// focusedSince lists the focus sessions of the user completed since the given date
+func (s *Store) focusedSince(userID string, since time.Time) []*focus.Session {
+	sessions := []*focus.Session{}
+	for _, session := range s.focusSessions {
+		if session.UserID == userID && session.Status == focus.StatusCompleted && session.CompletedAt != nil &&
+			!session.CompletedAt.Before(since) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
+
+func (r *StatsRepository) GetFocusDays(ctx context.Context, userID string, since time.Time) ([]string, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	days := []string{}
+	for _, session := range s.focusedSince(userID, since) {
+		day := session.CompletedAt.UTC().Format(time.DateOnly)
+		if !slices.Contains(days, day) {
+			days = append(days, day)
+		}
+	}
+	slices.Sort(days)
+
+	return days, nil
+}
+
+func (r *StatsRepository) GetFocusTotals(ctx context.Context, userID string, since time.Time) (*stats.FocusTotals, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	totals := &stats.FocusTotals{}
+	seconds := 0
+	for _, session := range s.focusedSince(userID, since) {
+		totals.Sessions++
+		seconds += session.FocusedSeconds
+	}
+	totals.FocusedMinutes = seconds / 60
+
+	return totals, nil
+}
+
+func (r *StatsRepository) CountCompletions(ctx context.Context, userID string) (int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.completedSince(userID, time.Time{})), nil
+}
+
+// CRON REQUIREMENTS
+
+// RefreshStats recomputes every dashboard aggregate from the current todos
+func (r *StatsRepository) RefreshStats(ctx context.Context) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type average struct {
+		seconds float64
+		count   int
+	}
+
+	refreshedAt := s.now()
+	summaries := map[string]stats.Summary{}
+	summaryAverages := map[string]*average{}
+	daily := map[string]map[string]int{}
+	byCategory := map[string]map[uuid.UUID]*stats.CategoryStats{}
+	categoryAverages := map[uuid.UUID]*average{}
+
+	for _, item := range s.todos {
+		summary := summaries[item.UserID]
+		summary.Total++
+		if item.StatusCategory == todo.StatusDraft || item.StatusCategory == todo.StatusActive {
+			summary.Open++
+		}
+
+		var categoryStats *stats.CategoryStats
+		if item.CategoryID != nil {
+			if byCategory[item.UserID] == nil {
+				byCategory[item.UserID] = map[uuid.UUID]*stats.CategoryStats{}
+			}
+			categoryStats = byCategory[item.UserID][*item.CategoryID]
+			if categoryStats == nil {
+				categoryStats = &stats.CategoryStats{CategoryID: *item.CategoryID}
+				byCategory[item.UserID][*item.CategoryID] = categoryStats
+				categoryAverages[*item.CategoryID] = &average{}
+			}
+			categoryStats.Total++
+		}
+
+		if item.CompletedAt != nil {
+			seconds := item.CompletedAt.Sub(item.CreatedAt).Seconds()
+
+			summary.Completed++
+			if summaryAverages[item.UserID] == nil {
+				summaryAverages[item.UserID] = &average{}
+			}
+			summaryAverages[item.UserID].seconds += seconds
+			summaryAverages[item.UserID].count++
+
+			day := item.CompletedAt.UTC().Format(time.DateOnly)
+			if daily[item.UserID] == nil {
+				daily[item.UserID] = map[string]int{}
+			}
+			daily[item.UserID][day]++
+
+			if categoryStats != nil {
+				categoryStats.Completed++
+				categoryAverages[categoryStats.CategoryID].seconds += seconds
+				categoryAverages[categoryStats.CategoryID].count++
+			}
+		}
+
+		summaries[item.UserID] = summary
+	}
+
+	s.summaries = map[string]stats.Summary{}
+	for userID, summary := range summaries {
+		if avg := summaryAverages[userID]; avg != nil {
+			seconds := avg.seconds / float64(avg.count)
+			summary.AverageCompletionSeconds = &seconds
+		}
+		summary.RefreshedAt = &refreshedAt
+		s.summaries[userID] = summary
+	}
+
+	s.dailyCompletions = map[string][]stats.DailyCompletions{}
+	for userID, days := range daily {
+		for day, completed := range days {
+			s.dailyCompletions[userID] = append(s.dailyCompletions[userID], stats.DailyCompletions{Day: day, Completed: completed})
+		}
+		slices.SortFunc(s.dailyCompletions[userID], func(a, b stats.DailyCompletions) int {
+			return strings.Compare(a.Day, b.Day)
+		})
+	}
+
+	s.categoryStats = map[string][]stats.CategoryStats{}
+	for userID, categories := range byCategory {
+		for categoryID, categoryStats := range categories {
+			if avg := categoryAverages[categoryID]; avg.count > 0 {
+				seconds := avg.seconds / float64(avg.count)
+				categoryStats.AverageCompletionSeconds = &seconds
+			}
+			s.categoryStats[userID] = append(s.categoryStats[userID], *categoryStats)
+		}
+	}
+
+	return nil
+}
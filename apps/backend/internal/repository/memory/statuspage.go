@@ -0,0 +1,201 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/health"
+	"github.com/Sameer16536/ExecuTask/internal/model/statuspage"
+	"github.com/google/uuid"
+)
+
+type statusDayKey struct {
+	component statuspage.Component
+	day       time.Time
+}
+
+type StatusPageRepository struct {
+	store *Store
+}
+
+func NewStatusPageRepository(store *Store) *StatusPageRepository {
+	return &StatusPageRepository{store: store}
+}
+
+func (r *StatusPageRepository) RecordChecks(ctx context.Context, checkedAt time.Time,
+	statuses map[statuspage.Component]health.Status,
+) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkedAt = checkedAt.UTC()
+	day := checkedAt.Truncate(24 * time.Hour)
+	for component, status := range statuses {
+		key := statusDayKey{component: component, day: day}
+		row, ok := s.statusChecks[key]
+		if !ok {
+			row = &statuspage.Day{Component: component, Day: day}
+			s.statusChecks[key] = row
+		}
+
+		row.Checks++
+		switch status {
+		case health.StatusDegraded:
+			row.Degraded++
+		case health.StatusUnhealthy:
+			row.Failures++
+		}
+		if !checkedAt.Before(row.LastCheckedAt) {
+			row.LastStatus = status
+			row.LastCheckedAt = checkedAt
+		}
+	}
+
+	return nil
+}
+
+func (r *StatusPageRepository) GetHistory(ctx context.Context, since time.Time) ([]statuspage.Day, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	since = since.UTC().Truncate(24 * time.Hour)
+	days := []statuspage.Day{}
+	for _, row := range s.statusChecks {
+		if !row.Day.Before(since) {
+			days = append(days, *row)
+		}
+	}
+	slices.SortFunc(days, func(a, b statuspage.Day) int {
+		if c := a.Day.Compare(b.Day); c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.Component), string(b.Component))
+	})
+
+	return days, nil
+}
+
+func (r *StatusPageRepository) GetIncidents(ctx context.Context, since time.Time) ([]statuspage.Incident, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	incidents := []statuspage.Incident{}
+	for _, incident := range s.incidents {
+		if !incident.StartedAt.Before(since) || incident.ResolvedAt == nil {
+			incidents = append(incidents, *copyIncident(incident))
+		}
+	}
+	slices.SortFunc(incidents, func(a, b statuspage.Incident) int {
+		return b.StartedAt.Compare(a.StartedAt)
+	})
+
+	return incidents, nil
+}
+
+func (r *StatusPageRepository) CreateIncident(ctx context.Context, createdBy string,
+	payload *statuspage.CreateIncidentPayload,
+) (*statuspage.Incident, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	incident := &statuspage.Incident{
+		Title:      payload.Title,
+		Message:    payload.Message,
+		Status:     *payload.Status,
+		Impact:     payload.Impact,
+		Components: slices.Clone(payload.Components),
+		StartedAt:  now,
+		CreatedBy:  createdBy,
+	}
+	incident.ID = uuid.New()
+	incident.CreatedAt = now
+	incident.UpdatedAt = now
+	if payload.StartedAt != nil {
+		incident.StartedAt = *payload.StartedAt
+	}
+	if incident.Status == statuspage.IncidentResolved {
+		incident.ResolvedAt = &now
+	}
+	s.incidents[incident.ID] = incident
+
+	return copyIncident(incident), nil
+}
+
+func (r *StatusPageRepository) UpdateIncident(ctx context.Context,
+	payload *statuspage.UpdateIncidentPayload,
+) (*statuspage.Incident, error) {
+	if payload.Title == nil && payload.Message == nil && payload.Status == nil && payload.Impact == nil &&
+		payload.Components == nil {
+		return nil, errs.NewBadRequestError("no fields to update", false, nil, nil, nil)
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.incidents[payload.ID]
+	if !ok {
+		return nil, incidentNotFound()
+	}
+
+	incident := copyIncident(stored)
+	if payload.Title != nil {
+		incident.Title = *payload.Title
+	}
+	if payload.Message != nil {
+		incident.Message = *payload.Message
+	}
+	if payload.Status != nil {
+		incident.Status = *payload.Status
+		switch {
+		case incident.Status != statuspage.IncidentResolved:
+			incident.ResolvedAt = nil
+		case incident.ResolvedAt == nil:
+			now := s.now()
+			incident.ResolvedAt = &now
+		}
+	}
+	if payload.Impact != nil {
+		incident.Impact = *payload.Impact
+	}
+	if payload.Components != nil {
+		incident.Components = slices.Clone(payload.Components)
+	}
+	incident.UpdatedAt = s.now()
+	s.incidents[incident.ID] = incident
+
+	return copyIncident(incident), nil
+}
+
+func (r *StatusPageRepository) DeleteIncident(ctx context.Context, incidentID uuid.UUID) (*statuspage.Incident, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	incident, ok := s.incidents[incidentID]
+	if !ok {
+		return nil, incidentNotFound()
+	}
+	delete(s.incidents, incidentID)
+
+	return incident, nil
+}
+
+func copyIncident(incident *statuspage.Incident) *statuspage.Incident {
+	copied := *incident
+	copied.Components = slices.Clone(incident.Components)
+	return &copied
+}
+
+func incidentNotFound() error {
+	code := errs.CodeIncidentNotFound
+	return errs.NewNotFoundError("incident not found", false, &code)
+}
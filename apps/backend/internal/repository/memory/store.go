@@ -0,0 +1,450 @@
+// Package memory has in-memory fakes of the repository stores, for unit testing services
+// without Postgres. The fakes share a Store and keep up what the database triggers do:
+// versions, counters, snapshots and change events. Errors have the shape the Postgres
+// repositories return, so sqlerr maps them to the same responses.
+package memory
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/admin"
+	"github.com/Sameer16536/ExecuTask/internal/model/apiclient"
+	"github.com/Sameer16536/ExecuTask/internal/model/apikey"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/model/backup"
+	"github.com/Sameer16536/ExecuTask/internal/model/category"
+	"github.com/Sameer16536/ExecuTask/internal/model/change"
+	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/cover"
+	"github.com/Sameer16536/ExecuTask/internal/model/dependency"
+	"github.com/Sameer16536/ExecuTask/internal/model/encryption"
+	"github.com/Sameer16536/ExecuTask/internal/model/export"
+	"github.com/Sameer16536/ExecuTask/internal/model/feature"
+	"github.com/Sameer16536/ExecuTask/internal/model/focus"
+	"github.com/Sameer16536/ExecuTask/internal/model/gamification"
+	"github.com/Sameer16536/ExecuTask/internal/model/inbox"
+	"github.com/Sameer16536/ExecuTask/internal/model/magictag"
+	"github.com/Sameer16536/ExecuTask/internal/model/matrix"
+	"github.com/Sameer16536/ExecuTask/internal/model/note"
+	"github.com/Sameer16536/ExecuTask/internal/model/preview"
+	"github.com/Sameer16536/ExecuTask/internal/model/report"
+	"github.com/Sameer16536/ExecuTask/internal/model/retention"
+	"github.com/Sameer16536/ExecuTask/internal/model/rule"
+	"github.com/Sameer16536/ExecuTask/internal/model/stats"
+	"github.com/Sameer16536/ExecuTask/internal/model/statuspage"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/model/webhook"
+	"github.com/Sameer16536/ExecuTask/internal/model/workspace"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// maxSnapshots matches the versions kept by the record_todo_snapshot trigger
+const maxSnapshots = 50
+
+// Store holds the rows of every fake, a write through one fake is seen by the others
+type Store struct {
+	mu  sync.Mutex
+	now func() time.Time
+
+	todos       map[uuid.UUID]*todo.Todo
+	sortOrder   int
+	snapshots   map[uuid.UUID][]todo.TodoVersion
+	categories  map[uuid.UUID]*category.Category
+	comments    map[uuid.UUID]*comment.Comment
+	attachments map[uuid.UUID]*todo.TodoAttachment
+	embeddings  map[uuid.UUID]*embeddingRow
+
+	changes      []change.Change
+	nextChangeID int64
+
+	auditLog    []audit.Entry
+	nextAuditID int64
+
+	overrides map[overrideKey]*feature.Override
+	usage     map[usageKey]int64
+	exports   map[uuid.UUID]*export.AccountExport
+
+	retentionPolicies map[string]*retention.Policy
+
+	storageQuotas map[string]*admin.StorageQuota
+
+	// keysMu guards dataKeys on its own, the keyring reads them while a fake holds mu to seal
+	// or open a value
+	keysMu   sync.Mutex
+	dataKeys map[uuid.UUID]*encryption.DataKey
+
+	backups  map[uuid.UUID]*backup.Backup
+	restores map[uuid.UUID]*backup.Restore
+
+	rules          map[uuid.UUID]*rule.Rule
+	ruleExecutions []rule.Execution
+
+	matrixSettings map[string]matrix.Settings
+
+	focusSessions map[uuid.UUID]*focus.Session
+
+	awards []gamification.Award
+	goals  map[string]*gamification.Goal
+
+	dependencies []dependency.Dependency
+
+	inboxItems []inbox.Item
+
+	templates        map[uuid.UUID]*workspace.Template
+	onboardingKits   map[string]*workspace.OnboardingKit
+	workflows        map[string]*workspace.Workflow
+	workspaceMembers map[memberKey]time.Time
+	agingPolicies    map[uuid.UUID]*workspace.AgingPolicy
+	slaBreaches      map[breachKey]time.Time
+
+	invites           map[uuid.UUID]*workspace.Invite
+	inviteAcceptances []workspace.InviteAcceptance
+	freezes           map[string]*workspace.Freeze
+
+	linkPreviews map[string]*preview.Preview
+
+	covers map[uuid.UUID]*cover.Cover
+
+	notes map[noteKey]*note.Note
+
+	// views holds when each user last viewed a todo, viewCounts how many times they did
+	views      map[viewKey]time.Time
+	viewCounts map[viewKey]int
+
+	myDayTodos map[myDayKey]*myDayRow
+
+	reportSchedules map[uuid.UUID]*report.Schedule
+
+	magicTags map[uuid.UUID]*magictag.MagicTag
+
+	// userLocales holds the locale each user chose
+	userLocales map[string]string
+
+	replyTokens map[string]*comment.ReplyToken
+
+	statusChecks map[statusDayKey]*statuspage.Day
+	incidents    map[uuid.UUID]*statuspage.Incident
+
+	apiKeys    map[uuid.UUID]*apikey.Key
+	apiClients map[uuid.UUID]*apiclient.APIClient
+
+	webhookEndpoints map[uuid.UUID]*webhook.Endpoint
+
+	// The materialized dashboard aggregates, computed by RefreshStats
+	summaries        map[string]stats.Summary
+	dailyCompletions map[string][]stats.DailyCompletions
+	categoryStats    map[string][]stats.CategoryStats
+
+	vacuumedAt map[string]time.Time
+	analyzedAt map[string]time.Time
+}
+
+func NewStore() *Store {
+	return &Store{
+		now:               time.Now,
+		todos:             map[uuid.UUID]*todo.Todo{},
+		snapshots:         map[uuid.UUID][]todo.TodoVersion{},
+		categories:        map[uuid.UUID]*category.Category{},
+		comments:          map[uuid.UUID]*comment.Comment{},
+		attachments:       map[uuid.UUID]*todo.TodoAttachment{},
+		embeddings:        map[uuid.UUID]*embeddingRow{},
+		overrides:         map[overrideKey]*feature.Override{},
+		usage:             map[usageKey]int64{},
+		exports:           map[uuid.UUID]*export.AccountExport{},
+		retentionPolicies: map[string]*retention.Policy{},
+		storageQuotas:     map[string]*admin.StorageQuota{},
+		dataKeys:          map[uuid.UUID]*encryption.DataKey{},
+		backups:           map[uuid.UUID]*backup.Backup{},
+		restores:          map[uuid.UUID]*backup.Restore{},
+		rules:             map[uuid.UUID]*rule.Rule{},
+		matrixSettings:    map[string]matrix.Settings{},
+		focusSessions:     map[uuid.UUID]*focus.Session{},
+		goals:             map[string]*gamification.Goal{},
+		templates:         map[uuid.UUID]*workspace.Template{},
+		onboardingKits:    map[string]*workspace.OnboardingKit{},
+		workflows:         map[string]*workspace.Workflow{},
+		workspaceMembers:  map[memberKey]time.Time{},
+		agingPolicies:     map[uuid.UUID]*workspace.AgingPolicy{},
+		slaBreaches:       map[breachKey]time.Time{},
+		invites:           map[uuid.UUID]*workspace.Invite{},
+		freezes:           map[string]*workspace.Freeze{},
+		linkPreviews:      map[string]*preview.Preview{},
+		covers:            map[uuid.UUID]*cover.Cover{},
+		notes:             map[noteKey]*note.Note{},
+		views:             map[viewKey]time.Time{},
+		viewCounts:        map[viewKey]int{},
+		myDayTodos:        map[myDayKey]*myDayRow{},
+		reportSchedules:   map[uuid.UUID]*report.Schedule{},
+		magicTags:         map[uuid.UUID]*magictag.MagicTag{},
+		userLocales:       map[string]string{},
+		replyTokens:       map[string]*comment.ReplyToken{},
+		statusChecks:      map[statusDayKey]*statuspage.Day{},
+		incidents:         map[uuid.UUID]*statuspage.Incident{},
+		apiKeys:           map[uuid.UUID]*apikey.Key{},
+		apiClients:        map[uuid.UUID]*apiclient.APIClient{},
+		webhookEndpoints:  map[uuid.UUID]*webhook.Endpoint{},
+		summaries:         map[string]stats.Summary{},
+		dailyCompletions:  map[string][]stats.DailyCompletions{},
+		categoryStats:     map[string][]stats.CategoryStats{},
+		vacuumedAt:        map[string]time.Time{},
+		analyzedAt:        map[string]time.Time{},
+	}
+}
+
+// SetClock replaces the clock stamping created_at, updated_at and the other times the
+// database would take from NOW()
+func (s *Store) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// insertTodo stores a new todo the way the todo triggers would
+func (s *Store) insertTodo(item *todo.Todo) {
+	now := s.now()
+	s.sortOrder++
+
+	item.CreatedAt = now
+	item.UpdatedAt = now
+	item.SortOrder = s.sortOrder
+	item.Version = 1
+	s.resolveWorkflow(nil, item)
+
+	s.todos[item.ID] = item
+	s.countTodo(item, 1)
+	s.recordChange(item.UserID, "todo", item.ID, change.ActionCreated, &item.Version)
+	s.recordSnapshot(item)
+}
+
+// updateTodo stores an edit of a todo, bumping its version
+func (s *Store) updateTodo(before, after *todo.Todo) {
+	s.countTodo(before, -1)
+
+	after.UpdatedAt = s.now()
+	after.Version = before.Version + 1
+	s.resolveWorkflow(before, after)
+	s.todos[after.ID] = after
+
+	s.countTodo(after, 1)
+	s.recordChange(after.UserID, "todo", after.ID, change.ActionUpdated, &after.Version)
+	s.recordSnapshot(after)
+}
+
+// resolveWorkflow settles the status and priority of a todo on the scales of its workspace the
+// way the resolve_workflow_todos trigger does, before is nil for a new todo
+func (s *Store) resolveWorkflow(before, after *todo.Todo) {
+	var workflow *workspace.Workflow
+	if after.WorkspaceID != nil {
+		workflow = s.workflows[*after.WorkspaceID]
+	}
+	workflow.Resolve(before, after, s.now())
+}
+
+// deleteTodo removes a todo with its comments, attachments and snapshots
+func (s *Store) deleteTodo(item *todo.Todo) {
+	s.countTodo(item, -1)
+	delete(s.todos, item.ID)
+	delete(s.snapshots, item.ID)
+
+	for id, c := range s.comments {
+		if c.TodoID == item.ID {
+			delete(s.comments, id)
+			s.recordChange(c.UserID, "comment", c.ID, change.ActionDeleted, nil)
+		}
+	}
+	for id, attachment := range s.attachments {
+		if attachment.TodoID == item.ID {
+			delete(s.attachments, id)
+		}
+	}
+	for id, session := range s.focusSessions {
+		if session.TodoID == item.ID && session.UserID == item.UserID {
+			delete(s.focusSessions, id)
+		}
+	}
+	s.dependencies = slices.DeleteFunc(s.dependencies, func(link dependency.Dependency) bool {
+		return link.UserID == item.UserID && (link.TodoID == item.ID || link.DependsOnID == item.ID)
+	})
+	for token, stored := range s.replyTokens {
+		if stored.TodoID == item.ID {
+			delete(s.replyTokens, token)
+		}
+	}
+	delete(s.covers, item.ID)
+	for key := range s.notes {
+		if key.todoID == item.ID {
+			delete(s.notes, key)
+		}
+	}
+	for key := range s.views {
+		if key.todoID == item.ID {
+			delete(s.views, key)
+			delete(s.viewCounts, key)
+		}
+	}
+	for key := range s.myDayTodos {
+		if key.todoID == item.ID {
+			delete(s.myDayTodos, key)
+		}
+	}
+
+	s.recordChange(item.UserID, "todo", item.ID, change.ActionDeleted, &item.Version)
+}
+
+// countTodo adds or removes a todo from the subtask counters of its parent and the open todo
+// counter of its category. Counter updates don't bump versions.
+func (s *Store) countTodo(item *todo.Todo, delta int) {
+	if item.ParentTodoID != nil {
+		if parent, ok := s.todos[*item.ParentTodoID]; ok && parent.UserID == item.UserID {
+			parent.SubtaskCount += delta
+			if item.StatusCategory == todo.StatusCompleted {
+				parent.CompletedSubtaskCount += delta
+			}
+		}
+	}
+
+	if item.CategoryID != nil && item.StatusCategory != todo.StatusCompleted && item.StatusCategory != todo.StatusArchived {
+		if categoryItem, ok := s.categories[*item.CategoryID]; ok {
+			categoryItem.OpenTodoCount += delta
+		}
+	}
+}
+
+func (s *Store) recordSnapshot(item *todo.Todo) {
+	var description *string
+	if item.Description != "" {
+		description = &item.Description
+	}
+
+	versions := append(s.snapshots[item.ID], todo.TodoVersion{
+		TodoID:       item.ID,
+		Version:      item.Version,
+		CreatedAt:    s.now(),
+		Title:        item.Title,
+		Description:  description,
+		Priority:     item.Priority,
+		Status:       item.Status,
+		DueDate:      item.DueDate,
+		CompletedAt:  item.CompletedAt,
+		ParentTodoID: item.ParentTodoID,
+		CategoryID:   item.CategoryID,
+		Metadata:     copyMetadata(item.Metadata),
+	})
+
+	versions = slices.DeleteFunc(versions, func(v todo.TodoVersion) bool {
+		return v.Version <= item.Version-maxSnapshots
+	})
+	s.snapshots[item.ID] = versions
+}
+
+func (s *Store) recordChange(userID, entityType string, entityID uuid.UUID, action change.Action, version *int) {
+	s.nextChangeID++
+
+	var v *int
+	if version != nil {
+		copied := *version
+		v = &copied
+	}
+
+	s.changes = append(s.changes, change.Change{
+		ID:         s.nextChangeID,
+		CreatedAt:  s.now(),
+		UserID:     userID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Version:    v,
+	})
+}
+
+// noRows is what pgx.CollectOneRow returns for a missing row, wrapped like the repositories do
+func noRows(table, format string, args ...any) error {
+	return fmt.Errorf("failed to collect row from table:%s for %s: %w", table, fmt.Sprintf(format, args...), pgx.ErrNoRows)
+}
+
+func uniqueViolation(table, constraint string) error {
+	return &pgconn.PgError{
+		Severity:       "ERROR",
+		Code:           "23505",
+		Message:        fmt.Sprintf("duplicate key value violates unique constraint \"%s\"", constraint),
+		TableName:      table,
+		ConstraintName: constraint,
+	}
+}
+
+// foreignKeyViolation is reported on the referencing table for a write there, on the
+// referenced table for a delete of a row still referenced
+func foreignKeyViolation(table, constraint, message string) error {
+	return &pgconn.PgError{
+		Severity:       "ERROR",
+		Code:           "23503",
+		Message:        message,
+		TableName:      table,
+		ConstraintName: constraint,
+	}
+}
+
+func copyMetadata(metadata *todo.Metadata) *todo.Metadata {
+	if metadata == nil {
+		return nil
+	}
+	copied := *metadata
+	copied.Tags = slices.Clone(metadata.Tags)
+	return &copied
+}
+
+func copyTodo(item *todo.Todo) todo.Todo {
+	copied := *item
+	copied.Metadata = copyMetadata(item.Metadata)
+	return copied
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	return slices.Contains(ids, id)
+}
+
+// sameID compares nullable references the way IS NOT DISTINCT FROM does
+func sameID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// trigramSimilarity mirrors pg_trgm's similarity: the share of trigrams the two texts have in
+// common, each word padded with two spaces in front and one behind
+func trigramSimilarity(a, b string) float64 {
+	trigramsA, trigramsB := trigrams(a), trigrams(b)
+	if len(trigramsA) == 0 || len(trigramsB) == 0 {
+		return 0
+	}
+
+	common := 0
+	for trigram := range trigramsA {
+		if trigramsB[trigram] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(trigramsA)+len(trigramsB)-common)
+}
+
+func trigrams(text string) map[string]bool {
+	set := map[string]bool{}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
@@ -0,0 +1,1278 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/envelope"
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/change"
+	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+type TodoRepository struct {
+	store *Store
+	// keyring opens and seals the private notes merged along with todos
+	keyring *envelope.Keyring
+}
+
+func NewTodoRepository(store *Store, keyring *envelope.Keyring) *TodoRepository {
+	return &TodoRepository{store: store, keyring: keyring}
+}
+
+func (r *TodoRepository) CreateTodo(ctx context.Context, userID, workspaceID string,
+	payload *todo.CreateTodoPayload,
+) (*todo.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkTodoReferences(userID, payload.ParentTodoID, payload.CategoryID); err != nil {
+		return nil, err
+	}
+
+	// Check for empty priority field --> put default value, the built-in keys stand for the
+	// first draft status and the middle level of the scales
+	priority := todo.PriorityMedium
+	if payload.Priority != nil {
+		priority = *payload.Priority
+	}
+	status := todo.StatusDraft
+	if payload.Status != nil {
+		status = *payload.Status
+	}
+
+	item := &todo.Todo{
+		UserID:       userID,
+		Title:        payload.Title,
+		Priority:     priority,
+		Status:       status,
+		DueDate:      payload.DueDate,
+		ParentTodoID: payload.ParentTodoID,
+		CategoryID:   payload.CategoryID,
+		Metadata:     copyMetadata(payload.Metadata),
+	}
+	item.ID = uuid.New()
+	if workspaceID != "" {
+		item.WorkspaceID = &workspaceID
+	}
+	if payload.Description != nil {
+		item.Description = *payload.Description
+	}
+
+	s.insertTodo(item)
+
+	todoItem := copyTodo(item)
+	return &todoItem, nil
+}
+
+func (r *TodoRepository) GetTodoByID(ctx context.Context, userID string, todoID uuid.UUID) (*todo.PopulatedTodo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.todos[todoID]
+	if !ok || item.UserID != userID {
+		return nil, noRows("todos", "todo_id=%s user_id=%s", todoID.String(), userID)
+	}
+
+	populated := s.populateTodo(item)
+	return &populated, nil
+}
+
+func (r *TodoRepository) CheckTodoExists(ctx context.Context, userID string, todoID uuid.UUID) (*todo.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.todos[todoID]
+	if !ok || item.UserID != userID {
+		return nil, noRows("todos", "todo_id=%s user_id=%s", todoID.String(), userID)
+	}
+
+	todoItem := copyTodo(item)
+	return &todoItem, nil
+}
+
+func (r *TodoRepository) GetTodosByIDs(ctx context.Context, userID string, todoIDs []uuid.UUID) ([]todo.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filterTodos(func(item *todo.Todo) bool {
+		return item.UserID == userID && containsID(todoIDs, item.ID)
+	}), nil
+}
+
+func (r *TodoRepository) GetTodos(ctx context.Context, userID string, query *todo.GetTodosQuery) (*model.PaginatedResponse[todo.PopulatedTodo], error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	items := s.filterTodos(func(item *todo.Todo) bool {
+		if item.UserID != userID {
+			return false
+		}
+		if keys := query.Statuses(); keys != nil && !matchesStatus(keys, item) {
+			return false
+		}
+		if keys := query.Priorities(); keys != nil && !slices.Contains(keys, string(item.Priority)) {
+			return false
+		}
+		if query.CategoryID != nil && (item.CategoryID == nil || *item.CategoryID != *query.CategoryID) {
+			return false
+		}
+		// By default, only show root todos (no parent)
+		if query.ParentTodoID != nil {
+			if item.ParentTodoID == nil || *item.ParentTodoID != *query.ParentTodoID {
+				return false
+			}
+		} else if item.ParentTodoID != nil {
+			return false
+		}
+		if query.DueFrom != nil && (item.DueDate == nil || item.DueDate.Before(*query.DueFrom)) {
+			return false
+		}
+		if query.DueTo != nil && (item.DueDate == nil || item.DueDate.After(*query.DueTo)) {
+			return false
+		}
+		if query.Overdue != nil && *query.Overdue &&
+			(item.DueDate == nil || !item.DueDate.Before(now) || item.StatusCategory == todo.StatusCompleted) {
+			return false
+		}
+		if query.Completed != nil && *query.Completed != (item.StatusCategory == todo.StatusCompleted) {
+			return false
+		}
+		if query.Search != nil && searchScore(item, *query.Search) == 0 {
+			return false
+		}
+		if query.Unread != nil && *query.Unread != item.IsUnread(s.viewedAt(userID, item.ID)) {
+			return false
+		}
+		return true
+	})
+
+	sortTodos(items, query)
+
+	total := len(items)
+	offset := min((*query.Page-1)*(*query.Limit), total)
+	end := min(offset+*query.Limit, total)
+
+	todos := make([]todo.PopulatedTodo, 0, end-offset)
+	for i := range items[offset:end] {
+		item := &items[offset+i]
+		// Expanded requests load their relations separately in batches
+		if query.Expand != nil {
+			todos = append(todos, todo.PopulatedTodo{
+				Todo:        *item,
+				Children:    []todo.Todo{},
+				Comments:    []comment.Comment{},
+				Attachments: []todo.TodoAttachment{},
+			})
+			continue
+		}
+		todos = append(todos, s.populateTodo(item))
+	}
+
+	return &model.PaginatedResponse[todo.PopulatedTodo]{
+		Data:       todos,
+		Page:       *query.Page,
+		Limit:      *query.Limit,
+		Total:      total,
+		TotalPages: (total + *query.Limit - 1) / *query.Limit,
+	}, nil
+}
+
+func (r *TodoRepository) StreamTodos(ctx context.Context, userID string, query *todo.ExportTodosQuery,
+	fn func(todoItem *todo.Todo) error,
+) error {
+	s := r.store
+	s.mu.Lock()
+	items := s.filterTodos(func(item *todo.Todo) bool {
+		if item.UserID != userID {
+			return false
+		}
+		if keys := query.Statuses(); keys != nil && !matchesStatus(keys, item) {
+			return false
+		}
+		if keys := query.Priorities(); keys != nil && !slices.Contains(keys, string(item.Priority)) {
+			return false
+		}
+		if query.CategoryID != nil && (item.CategoryID == nil || *item.CategoryID != *query.CategoryID) {
+			return false
+		}
+		if query.Search != nil && searchScore(item, *query.Search) == 0 {
+			return false
+		}
+		return true
+	})
+	s.mu.Unlock()
+
+	slices.SortFunc(items, func(a, b todo.Todo) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+
+	// fn may call back into the store, so it runs without the lock held
+	for i := range items {
+		if err := fn(&items[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *TodoRepository) UpdateTodo(ctx context.Context, userID string, payload *todo.UpdateTodoPayload) (*todo.Todo, error) {
+	if payload.Title == nil && payload.Description == nil && payload.Status == nil && payload.Priority == nil &&
+		payload.DueDate == nil && payload.ParentTodoID == nil && payload.CategoryID == nil && payload.Metadata == nil &&
+		len(payload.Clear) == 0 {
+		return nil, errs.NewBadRequestError("no fields to update", false, nil, nil, nil)
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, ok := s.todos[payload.ID]
+	if !ok || before.UserID != userID || (payload.Version != nil && before.Version != *payload.Version) {
+		if payload.Version != nil {
+			code := errs.CodeTodoVersionConflict
+			return nil, errs.NewConflictError("todo was modified since the given version", false, &code)
+		}
+		return nil, noRows("todos", "todo_id=%s", payload.ID.String())
+	}
+
+	if err := s.checkTodoReferences(userID, payload.ParentTodoID, payload.CategoryID); err != nil {
+		return nil, err
+	}
+
+	after := copyTodo(before)
+	if payload.Title != nil {
+		after.Title = *payload.Title
+	}
+	if payload.Description != nil {
+		after.Description = *payload.Description
+	}
+	// completed_at follows the category of the status, see resolveWorkflow
+	if payload.Status != nil {
+		after.Status = *payload.Status
+	}
+	if payload.Priority != nil {
+		after.Priority = *payload.Priority
+	}
+	if payload.DueDate != nil {
+		dueDate := *payload.DueDate
+		after.DueDate = &dueDate
+	}
+	if payload.ParentTodoID != nil {
+		parentID := *payload.ParentTodoID
+		after.ParentTodoID = &parentID
+	}
+	if payload.CategoryID != nil {
+		categoryID := *payload.CategoryID
+		after.CategoryID = &categoryID
+	}
+	if payload.Metadata != nil {
+		after.Metadata = copyMetadata(payload.Metadata)
+	}
+	for _, field := range payload.Clear {
+		switch field {
+		case "dueDate":
+			after.DueDate = nil
+		case "parentTodoId":
+			after.ParentTodoID = nil
+		case "categoryId":
+			after.CategoryID = nil
+		case "metadata":
+			after.Metadata = nil
+		}
+	}
+
+	s.updateTodo(before, &after)
+
+	updatedTodo := copyTodo(&after)
+	return &updatedTodo, nil
+}
+
+func (r *TodoRepository) GetTodoVersions(ctx context.Context, userID string, todoID uuid.UUID) ([]todo.TodoVersion, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions := []todo.TodoVersion{}
+	if item, ok := s.todos[todoID]; ok && item.UserID == userID {
+		for _, v := range slices.Backward(s.snapshots[todoID]) {
+			v.Metadata = copyMetadata(v.Metadata)
+			versions = append(versions, v)
+		}
+	}
+
+	return versions, nil
+}
+
+func (r *TodoRepository) RestoreTodoVersion(ctx context.Context, userID string, todoID uuid.UUID, version int,
+	expectedVersion *int,
+) (*todo.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, ok := s.todos[todoID]
+	var snapshot *todo.TodoVersion
+	if ok && before.UserID == userID {
+		for i, v := range s.snapshots[todoID] {
+			if v.Version == version {
+				snapshot = &s.snapshots[todoID][i]
+			}
+		}
+	}
+
+	if snapshot == nil || (expectedVersion != nil && before.Version != *expectedVersion) {
+		if expectedVersion != nil {
+			code := errs.CodeTodoVersionConflict
+			return nil, errs.NewConflictError("todo was modified since the given version", false, &code)
+		}
+		code := errs.CodeTodoVersionNotFound
+		return nil, errs.NewNotFoundError("todo version not found", false, &code)
+	}
+
+	// The parent is left as is, a category deleted since is cleared
+	after := copyTodo(before)
+	after.Title = snapshot.Title
+	after.Description = ""
+	if snapshot.Description != nil {
+		after.Description = *snapshot.Description
+	}
+	after.Priority = snapshot.Priority
+	after.Status = snapshot.Status
+	after.DueDate = snapshot.DueDate
+	after.CompletedAt = snapshot.CompletedAt
+	after.CategoryID = nil
+	if snapshot.CategoryID != nil {
+		if categoryItem, ok := s.categories[*snapshot.CategoryID]; ok && categoryItem.UserID == userID {
+			after.CategoryID = snapshot.CategoryID
+		}
+	}
+	after.Metadata = copyMetadata(snapshot.Metadata)
+
+	s.updateTodo(before, &after)
+
+	restoredTodo := copyTodo(&after)
+	return &restoredTodo, nil
+}
+
+func (r *TodoRepository) MarkCommentsRead(ctx context.Context, userID string, todoID uuid.UUID) (*todo.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, ok := s.todos[todoID]
+	if !ok || before.UserID != userID {
+		code := errs.CodeTodoNotFound
+		return nil, errs.NewNotFoundError("todo not found", false, &code)
+	}
+
+	after := copyTodo(before)
+	readAt := s.now()
+	after.CommentsReadAt = &readAt
+	after.UnreadCommentCount = 0
+
+	s.updateTodo(before, &after)
+
+	todoItem := copyTodo(&after)
+	return &todoItem, nil
+}
+
+type viewKey struct {
+	todoID uuid.UUID
+	userID string
+}
+
+func (r *TodoRepository) RecordTodoView(ctx context.Context, userID string, todoID uuid.UUID) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if item, ok := s.todos[todoID]; !ok || item.UserID != userID {
+		code := errs.CodeTodoNotFound
+		return errs.NewNotFoundError("todo not found", false, &code)
+	}
+	key := viewKey{todoID: todoID, userID: userID}
+	s.views[key] = s.now()
+	s.viewCounts[key]++
+
+	return nil
+}
+
+func (r *TodoRepository) GetTodoViews(ctx context.Context, userID string, todoIDs []uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	views := make(map[uuid.UUID]time.Time, len(todoIDs))
+	for _, todoID := range todoIDs {
+		if viewedAt := s.viewedAt(userID, todoID); viewedAt != nil {
+			views[todoID] = *viewedAt
+		}
+	}
+
+	return views, nil
+}
+
+// viewedAt is when the user last viewed the todo, nil when they never did. The caller holds the lock.
+func (s *Store) viewedAt(userID string, todoID uuid.UUID) *time.Time {
+	viewedAt, ok := s.views[viewKey{todoID: todoID, userID: userID}]
+	if !ok {
+		return nil
+	}
+	return &viewedAt
+}
+
+func (r *TodoRepository) DeleteTodo(ctx context.Context, userID string, todoID uuid.UUID) (*todo.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.todos[todoID]
+	if !ok || item.UserID != userID {
+		code := errs.CodeTodoNotFound
+		return nil, errs.NewNotFoundError("todo not found", false, &code)
+	}
+
+	// Subtasks reference their parent without a cascade
+	for _, child := range s.todos {
+		if child.ParentTodoID != nil && *child.ParentTodoID == todoID {
+			return nil, foreignKeyViolation("todos", "todos_parent_todo_id_fkey",
+				`update or delete on table "todos" violates foreign key constraint "todos_parent_todo_id_fkey" on table "todos"`)
+		}
+	}
+
+	s.deleteTodo(item)
+
+	deletedTodo := copyTodo(item)
+	return &deletedTodo, nil
+}
+
+func (r *TodoRepository) FindDuplicateTodos(ctx context.Context, userID, title string, categoryID *uuid.UUID,
+	excludeID uuid.UUID, threshold float64, limit int,
+) ([]todo.DuplicateCandidate, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	candidates := []todo.DuplicateCandidate{}
+	for _, item := range s.filterTodos(func(item *todo.Todo) bool {
+		return item.UserID == userID && item.ID != excludeID &&
+			(item.StatusCategory == todo.StatusDraft || item.StatusCategory == todo.StatusActive) &&
+			sameID(item.CategoryID, categoryID)
+	}) {
+		if similarity := trigramSimilarity(item.Title, title); similarity >= threshold {
+			candidates = append(candidates, todo.DuplicateCandidate{
+				ID:         item.ID,
+				Title:      item.Title,
+				Status:     item.Status,
+				CategoryID: item.CategoryID,
+				Similarity: similarity,
+			})
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a, b todo.DuplicateCandidate) int {
+		return cmp.Compare(b.Similarity, a.Similarity)
+	})
+
+	return candidates[:min(limit, len(candidates))], nil
+}
+
+func (r *TodoRepository) MergeTodos(ctx context.Context, userID string, targetID uuid.UUID,
+	sourceIDs []uuid.UUID,
+) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	target, ok := s.todos[targetID]
+	if !ok || target.UserID != userID {
+		return noRows("todos", "todo_id=%s", targetID.String())
+	}
+
+	if err := s.mergeNotes(ctx, r.keyring, targetID, sourceIDs); err != nil {
+		return err
+	}
+
+	for _, child := range s.todos {
+		if child.UserID == userID && child.ParentTodoID != nil && containsID(sourceIDs, *child.ParentTodoID) {
+			moved := copyTodo(child)
+			moved.ParentTodoID = &targetID
+			s.updateTodo(child, &moved)
+		}
+	}
+
+	for _, commentItem := range s.comments {
+		// The counters of the sources go with them
+		if containsID(sourceIDs, commentItem.TodoID) {
+			commentItem.TodoID = targetID
+			target.CommentCount++
+			if commentItem.UserID != target.UserID &&
+				(target.CommentsReadAt == nil || commentItem.CreatedAt.After(*target.CommentsReadAt)) {
+				target.UnreadCommentCount++
+			}
+			s.recordChange(commentItem.UserID, "comment", commentItem.ID, change.ActionUpdated, nil)
+		}
+	}
+
+	for _, attachment := range s.attachments {
+		if containsID(sourceIDs, attachment.TodoID) {
+			attachment.TodoID = targetID
+		}
+	}
+
+	for _, session := range s.focusSessions {
+		if session.UserID == userID && containsID(sourceIDs, session.TodoID) {
+			session.TodoID = targetID
+		}
+	}
+
+	for _, sourceID := range sourceIDs {
+		if item, ok := s.todos[sourceID]; ok && item.UserID == userID {
+			s.deleteTodo(item)
+		}
+	}
+
+	return nil
+}
+
+func (r *TodoRepository) GetTodoStats(ctx context.Context, userID string) (*todo.TodoStats, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	stats := &todo.TodoStats{}
+	for _, item := range s.todos {
+		if item.UserID != userID {
+			continue
+		}
+
+		stats.Total++
+		switch item.StatusCategory {
+		case todo.StatusDraft:
+			stats.Draft++
+		case todo.StatusActive:
+			stats.Active++
+		case todo.StatusCompleted:
+			stats.Completed++
+		case todo.StatusArchived:
+			stats.Archived++
+		}
+		if item.DueDate != nil && item.DueDate.Before(now) && item.StatusCategory != todo.StatusCompleted {
+			stats.Overdue++
+		}
+	}
+
+	return stats, nil
+}
+
+func (r *TodoRepository) GetTodoAttachment(ctx context.Context, todoID uuid.UUID, attachmentID uuid.UUID) (*todo.TodoAttachment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attachment, ok := s.attachments[attachmentID]
+	if !ok || attachment.TodoID != todoID {
+		code := errs.CodeAttachmentNotFound
+		return nil, errs.NewNotFoundError("attachment not found", false, &code)
+	}
+
+	copied := *attachment
+	return &copied, nil
+}
+
+func (r *TodoRepository) GetTodoAttachments(ctx context.Context, todoID uuid.UUID) ([]todo.TodoAttachment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filterAttachments(func(attachment *todo.TodoAttachment) bool {
+		return attachment.TodoID == todoID
+	}), nil
+}
+
+func (r *TodoRepository) DeleteTodoAttachment(ctx context.Context, todoID uuid.UUID, attachmentID uuid.UUID) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attachment, ok := s.attachments[attachmentID]
+	if !ok || attachment.TodoID != todoID {
+		code := errs.CodeAttachmentNotFound
+		return errs.NewNotFoundError("attachment not found", false, &code)
+	}
+
+	delete(s.attachments, attachmentID)
+	return nil
+}
+
+func (r *TodoRepository) UploadTodoAttachment(ctx context.Context, todoID uuid.UUID, userID string, s3Key string,
+	fileName string, fileSize int64, mimeType string, scanStatus *todo.ScanStatus,
+) (*todo.TodoAttachment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.todos[todoID]; !ok {
+		return nil, fmt.Errorf("failed to create todo attachment for todo_id=%s: %w", todoID.String(),
+			foreignKeyViolation("todo_attachments", "todo_attachments_todo_id_fkey",
+				`insert or update on table "todo_attachments" violates foreign key constraint "todo_attachments_todo_id_fkey"`))
+	}
+
+	now := s.now()
+	attachment := &todo.TodoAttachment{
+		TodoID:      todoID,
+		Name:        fileName,
+		UploadedBy:  userID,
+		DownloadKey: s3Key,
+		FileSize:    &fileSize,
+		MimeType:    &mimeType,
+		ScanStatus:  scanStatus,
+	}
+	attachment.ID = uuid.New()
+	attachment.CreatedAt = now
+	attachment.UpdatedAt = now
+	s.attachments[attachment.ID] = attachment
+
+	copied := *attachment
+	return &copied, nil
+}
+
+func (r *TodoRepository) SetAttachmentScan(ctx context.Context, todoID uuid.UUID, attachmentID uuid.UUID,
+	status todo.ScanStatus, signature *string,
+) (*todo.TodoAttachment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attachment, ok := s.attachments[attachmentID]
+	if !ok || attachment.TodoID != todoID {
+		code := errs.CodeAttachmentNotFound
+		return nil, errs.NewNotFoundError("attachment not found", false, &code)
+	}
+
+	now := s.now()
+	attachment.ScanStatus = &status
+	attachment.ScanSignature = signature
+	attachment.ScannedAt = &now
+	attachment.UpdatedAt = now
+
+	copied := *attachment
+	return &copied, nil
+}
+
+func (r *TodoRepository) SetAttachmentTranscription(ctx context.Context, todoID uuid.UUID, attachmentID uuid.UUID,
+	status todo.TranscriptionStatus, transcript *string,
+) (*todo.TodoAttachment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attachment, ok := s.attachments[attachmentID]
+	if !ok || attachment.TodoID != todoID {
+		code := errs.CodeAttachmentNotFound
+		return nil, errs.NewNotFoundError("attachment not found", false, &code)
+	}
+
+	attachment.TranscriptionStatus = &status
+	attachment.Transcript = transcript
+	attachment.UpdatedAt = s.now()
+
+	copied := *attachment
+	return &copied, nil
+}
+
+func (r *TodoRepository) SetAttachmentProcessed(ctx context.Context, todoID uuid.UUID, attachmentID uuid.UUID,
+	fileSize int64, thumbnailKey *string,
+) (*todo.TodoAttachment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attachment, ok := s.attachments[attachmentID]
+	if !ok || attachment.TodoID != todoID {
+		code := errs.CodeAttachmentNotFound
+		return nil, errs.NewNotFoundError("attachment not found", false, &code)
+	}
+
+	now := s.now()
+	attachment.FileSize = &fileSize
+	attachment.ThumbnailKey = thumbnailKey
+	attachment.ProcessedAt = &now
+	attachment.UpdatedAt = now
+
+	copied := *attachment
+	return &copied, nil
+}
+
+func (r *TodoRepository) GetUserAttachmentBytes(ctx context.Context, userID string) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var bytes int64
+	for _, attachment := range s.attachments {
+		item, ok := s.todos[attachment.TodoID]
+		if !ok || item.UserID != userID || attachment.FileSize == nil {
+			continue
+		}
+		bytes += *attachment.FileSize
+	}
+
+	return bytes, nil
+}
+
+func (r *TodoRepository) GetUserQuotaBytes(ctx context.Context, userID string) (*int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	quota, ok := s.storageQuotas[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	quotaBytes := quota.QuotaBytes
+	return &quotaBytes, nil
+}
+
+func (r *TodoRepository) GetRecordedAttachmentKeys(ctx context.Context, downloadKeys []string) ([]string, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := []string{}
+	for _, attachment := range s.attachments {
+		if slices.Contains(downloadKeys, attachment.DownloadKey) {
+			keys = append(keys, attachment.DownloadKey)
+		}
+	}
+
+	return keys, nil
+}
+
+func (r *TodoRepository) GetRecordedThumbnailKeys(ctx context.Context, thumbnailKeys []string) ([]string, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := []string{}
+	for _, attachment := range s.attachments {
+		if attachment.ThumbnailKey != nil && slices.Contains(thumbnailKeys, *attachment.ThumbnailKey) {
+			keys = append(keys, *attachment.ThumbnailKey)
+		}
+	}
+
+	return keys, nil
+}
+
+func (r *TodoRepository) GetTodosUpdatedSince(ctx context.Context, userID string, since time.Time, limit int) ([]todo.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := s.filterTodos(func(item *todo.Todo) bool {
+		return item.UserID == userID && item.UpdatedAt.After(since)
+	})
+	slices.SortStableFunc(items, func(a, b todo.Todo) int {
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	})
+
+	return items[:min(limit, len(items))], nil
+}
+
+// GetRecentTodos returns the latest todos created by the user, newest first
+func (r *TodoRepository) GetRecentTodos(ctx context.Context, userID string, limit int) ([]todo.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := s.filterTodos(func(item *todo.Todo) bool {
+		return item.UserID == userID
+	})
+	slices.SortStableFunc(items, func(a, b todo.Todo) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return items[:min(limit, len(items))], nil
+}
+
+func (r *TodoRepository) GetTodosDueBetween(ctx context.Context, userID string, from, to time.Time) ([]todo.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := s.filterTodos(func(item *todo.Todo) bool {
+		return item.UserID == userID && item.DueDate != nil && !item.DueDate.Before(from) && item.DueDate.Before(to) &&
+			item.StatusCategory != todo.StatusArchived
+	})
+	slices.SortStableFunc(items, func(a, b todo.Todo) int {
+		return cmp.Or(a.DueDate.Compare(*b.DueDate), cmp.Compare(a.SortOrder, b.SortOrder))
+	})
+
+	return items, nil
+}
+
+func (r *TodoRepository) GetUnscheduledTodos(ctx context.Context, userID string, limit int) ([]todo.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := s.filterTodos(func(item *todo.Todo) bool {
+		return item.UserID == userID && item.DueDate == nil &&
+			(item.StatusCategory == todo.StatusDraft || item.StatusCategory == todo.StatusActive)
+	})
+	slices.SortStableFunc(items, func(a, b todo.Todo) int {
+		return cmp.Or(cmp.Compare(b.PriorityLevel, a.PriorityLevel), cmp.Compare(a.SortOrder, b.SortOrder))
+	})
+
+	return items[:min(limit, len(items))], nil
+}
+
+func (r *TodoRepository) GetOpenTodos(ctx context.Context, userID string, limit int) ([]todo.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := s.filterTodos(func(item *todo.Todo) bool {
+		return item.UserID == userID && (item.StatusCategory == todo.StatusDraft || item.StatusCategory == todo.StatusActive)
+	})
+	slices.SortStableFunc(items, func(a, b todo.Todo) int {
+		return cmp.Or(compareNullableTimes(a.DueDate, b.DueDate), cmp.Compare(a.SortOrder, b.SortOrder))
+	})
+
+	return items[:min(limit, len(items))], nil
+}
+
+func (r *TodoRepository) GetRecentlyCompletedTodos(ctx context.Context, userID string, limit int) ([]todo.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := s.filterTodos(func(item *todo.Todo) bool {
+		return item.UserID == userID && item.StatusCategory == todo.StatusCompleted && item.CompletedAt != nil
+	})
+	slices.SortStableFunc(items, func(a, b todo.Todo) int {
+		return b.CompletedAt.Compare(*a.CompletedAt)
+	})
+
+	return items[:min(limit, len(items))], nil
+}
+
+func (r *TodoRepository) GetChildrenByParentIDs(ctx context.Context, userID string, parentIDs []uuid.UUID) ([]todo.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	children := s.filterTodos(func(item *todo.Todo) bool {
+		return item.UserID == userID && item.ParentTodoID != nil && containsID(parentIDs, *item.ParentTodoID)
+	})
+	sortChildren(children)
+
+	return children, nil
+}
+
+func (r *TodoRepository) GetAttachmentsByTodoIDs(ctx context.Context, todoIDs []uuid.UUID) ([]todo.TodoAttachment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.filterAttachments(func(attachment *todo.TodoAttachment) bool {
+		return containsID(todoIDs, attachment.TodoID)
+	}), nil
+}
+
+// CRON REQUIREMENTS
+
+func (r *TodoRepository) GetTodosDueInHours(ctx context.Context, hours int, limit int) ([]todo.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	until := now.Add(time.Duration(hours) * time.Hour)
+	items := s.filterTodos(func(item *todo.Todo) bool {
+		return item.DueDate != nil && item.DueDate.After(now) && !item.DueDate.After(until) && isOpen(item)
+	})
+	sortByDueDate(items)
+
+	return items[:min(limit, len(items))], nil
+}
+
+func (r *TodoRepository) GetOverdueTodos(ctx context.Context, limit int) ([]todo.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	items := s.filterTodos(func(item *todo.Todo) bool {
+		return item.DueDate != nil && item.DueDate.Before(now) && isOpen(item)
+	})
+	sortByDueDate(items)
+
+	return items[:min(limit, len(items))], nil
+}
+
+func (r *TodoRepository) GetCompletedTodosOlderThan(ctx context.Context, cutoffDate time.Time, limit int) ([]todo.Todo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := s.filterTodos(func(item *todo.Todo) bool {
+		return item.StatusCategory == todo.StatusCompleted && item.CompletedAt != nil && item.CompletedAt.Before(cutoffDate)
+	})
+	slices.SortStableFunc(items, func(a, b todo.Todo) int {
+		return a.CompletedAt.Compare(*b.CompletedAt)
+	})
+
+	return items[:min(limit, len(items))], nil
+}
+
+func (r *TodoRepository) ArchiveTodos(ctx context.Context, todoIDs []uuid.UUID, userIDs []string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var archived []*todo.Todo
+	for _, id := range todoIDs {
+		if item, ok := s.todos[id]; ok && slices.Contains(userIDs, item.UserID) {
+			archived = append(archived, item)
+		}
+	}
+
+	// The update runs in one statement, it archives everything or nothing
+	if len(archived) != len(todoIDs) {
+		return fmt.Errorf("expected to archive %d todos, but archived %d", len(todoIDs), len(archived))
+	}
+
+	for _, before := range archived {
+		after := copyTodo(before)
+		after.Status = todo.StatusArchived
+		s.updateTodo(before, &after)
+	}
+
+	return nil
+}
+
+func (r *TodoRepository) ApplyWorkflow(ctx context.Context, workspaceID string,
+	statusMapping, priorityMapping map[string]string,
+) (int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var inWorkspace []*todo.Todo
+	for _, item := range s.todos {
+		if item.WorkspaceID != nil && *item.WorkspaceID == workspaceID {
+			inWorkspace = append(inWorkspace, item)
+		}
+	}
+
+	applied := 0
+	for _, before := range inWorkspace {
+		after := copyTodo(before)
+		after.Status = todo.Status(remapKey(statusMapping, string(before.Status)))
+		after.Priority = todo.Priority(remapKey(priorityMapping, string(before.Priority)))
+
+		// Only the todos the scales move are written
+		resolved := copyTodo(&after)
+		s.resolveWorkflow(before, &resolved)
+		if resolved.Status == before.Status && resolved.StatusCategory == before.StatusCategory &&
+			resolved.Priority == before.Priority && resolved.PriorityLevel == before.PriorityLevel {
+			continue
+		}
+
+		s.updateTodo(before, &after)
+		applied++
+	}
+
+	return applied, nil
+}
+
+// matchesStatus tells whether the todo is in one of the statuses, a built-in status standing
+// for any status of its category
+func matchesStatus(keys []string, item *todo.Todo) bool {
+	return slices.Contains(keys, string(item.Status)) || slices.Contains(keys, string(item.StatusCategory))
+}
+
+// remapKey is the key a mapping renames key to, key itself when the mapping doesn't name it
+func remapKey(mapping map[string]string, key string) string {
+	if to, ok := mapping[key]; ok {
+		return to
+	}
+	return key
+}
+
+func (r *TodoRepository) GetWeeklyStatsForUsers(ctx context.Context, startDate, endDate time.Time) ([]todo.UserWeeklyStats, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	byUser := map[string]*todo.UserWeeklyStats{}
+	for _, item := range s.todos {
+		stats, ok := byUser[item.UserID]
+		if !ok {
+			stats = &todo.UserWeeklyStats{UserID: item.UserID}
+			byUser[item.UserID] = stats
+		}
+
+		if !item.CreatedAt.Before(startDate) && !item.CreatedAt.After(endDate) {
+			stats.CreatedCount++
+		}
+		if item.StatusCategory == todo.StatusCompleted && item.CompletedAt != nil &&
+			!item.CompletedAt.Before(startDate) && !item.CompletedAt.After(endDate) {
+			stats.CompletedCount++
+		}
+		if isOpen(item) {
+			stats.ActiveCount++
+			if item.DueDate != nil && item.DueDate.Before(now) {
+				stats.OverdueCount++
+			}
+		}
+	}
+
+	result := make([]todo.UserWeeklyStats, 0, len(byUser))
+	for _, stats := range byUser {
+		result = append(result, *stats)
+	}
+	slices.SortFunc(result, func(a, b todo.UserWeeklyStats) int {
+		return strings.Compare(a.UserID, b.UserID)
+	})
+
+	return result, nil
+}
+
+func (r *TodoRepository) GetCompletedTodosForUser(ctx context.Context, userID string,
+	startDate, endDate time.Time,
+) ([]todo.PopulatedTodo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := s.filterTodos(func(item *todo.Todo) bool {
+		return item.UserID == userID && item.StatusCategory == todo.StatusCompleted && item.CompletedAt != nil &&
+			!item.CompletedAt.Before(startDate) && !item.CompletedAt.After(endDate)
+	})
+	slices.SortStableFunc(items, func(a, b todo.Todo) int {
+		return b.CompletedAt.Compare(*a.CompletedAt)
+	})
+
+	return s.populateTodos(items[:min(10, len(items))]), nil
+}
+
+func (r *TodoRepository) GetOverdueTodosForUser(ctx context.Context, userID string) ([]todo.PopulatedTodo, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	items := s.filterTodos(func(item *todo.Todo) bool {
+		return item.UserID == userID && item.DueDate != nil && item.DueDate.Before(now) && isOpen(item)
+	})
+	sortByDueDate(items)
+
+	return s.populateTodos(items[:min(10, len(items))]), nil
+}
+
+// -----------------------------------------------------------------------------------------
+
+// checkTodoReferences fails like the foreign keys of todos do. Parents are keyed by id and
+// owner, categories by id alone.
+func (s *Store) checkTodoReferences(userID string, parentTodoID, categoryID *uuid.UUID) error {
+	if parentTodoID != nil {
+		if parent, ok := s.todos[*parentTodoID]; !ok || parent.UserID != userID {
+			return foreignKeyViolation("todos", "todos_parent_todo_id_fkey",
+				`insert or update on table "todos" violates foreign key constraint "todos_parent_todo_id_fkey"`)
+		}
+	}
+
+	if categoryID != nil {
+		if _, ok := s.categories[*categoryID]; !ok {
+			return foreignKeyViolation("todos", "todos_category_id_fkey",
+				`insert or update on table "todos" violates foreign key constraint "todos_category_id_fkey"`)
+		}
+	}
+
+	return nil
+}
+
+// filterTodos copies out the todos matching keep, in insertion order
+func (s *Store) filterTodos(keep func(item *todo.Todo) bool) []todo.Todo {
+	items := []todo.Todo{}
+	for _, item := range s.todos {
+		if keep(item) {
+			items = append(items, copyTodo(item))
+		}
+	}
+
+	slices.SortFunc(items, func(a, b todo.Todo) int {
+		return cmp.Compare(a.SortOrder, b.SortOrder)
+	})
+
+	return items
+}
+
+// filterAttachments copies out the attachments matching keep, newest first
+func (s *Store) filterAttachments(keep func(attachment *todo.TodoAttachment) bool) []todo.TodoAttachment {
+	attachments := []todo.TodoAttachment{}
+	for _, attachment := range s.attachments {
+		if keep(attachment) {
+			attachments = append(attachments, *attachment)
+		}
+	}
+
+	slices.SortFunc(attachments, func(a, b todo.TodoAttachment) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return attachments
+}
+
+// populateTodo joins the relations the Postgres queries aggregate into a populated todo:
+// the category, the subtasks, the owner's comments and the attachments
+func (s *Store) populateTodo(item *todo.Todo) todo.PopulatedTodo {
+	populated := todo.PopulatedTodo{Todo: copyTodo(item)}
+
+	if item.CategoryID != nil {
+		if categoryItem, ok := s.categories[*item.CategoryID]; ok && categoryItem.UserID == item.UserID {
+			copied := *categoryItem
+			populated.Category = &copied
+		}
+	}
+
+	populated.Children = s.filterTodos(func(child *todo.Todo) bool {
+		return child.UserID == item.UserID && child.ParentTodoID != nil && *child.ParentTodoID == item.ID
+	})
+	sortChildren(populated.Children)
+
+	populated.Comments = s.filterComments(func(c *comment.Comment) bool {
+		return c.TodoID == item.ID && c.UserID == item.UserID
+	})
+
+	populated.Attachments = s.filterAttachments(func(attachment *todo.TodoAttachment) bool {
+		return attachment.TodoID == item.ID
+	})
+
+	return populated
+}
+
+func (s *Store) populateTodos(items []todo.Todo) []todo.PopulatedTodo {
+	populated := make([]todo.PopulatedTodo, 0, len(items))
+	for i := range items {
+		populated = append(populated, s.populateTodo(&items[i]))
+	}
+	return populated
+}
+
+func isOpen(item *todo.Todo) bool {
+	return item.StatusCategory != todo.StatusCompleted && item.StatusCategory != todo.StatusArchived
+}
+
+func sortChildren(children []todo.Todo) {
+	slices.SortStableFunc(children, func(a, b todo.Todo) int {
+		return cmp.Or(cmp.Compare(a.SortOrder, b.SortOrder), a.CreatedAt.Compare(b.CreatedAt))
+	})
+}
+
+func sortByDueDate(items []todo.Todo) {
+	slices.SortStableFunc(items, func(a, b todo.Todo) int {
+		return compareNullableTimes(a.DueDate, b.DueDate)
+	})
+}
+
+// compareNullableTimes sorts NULLs after every value, as Postgres does in ascending order
+func compareNullableTimes(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	default:
+		return a.Compare(*b)
+	}
+}
+
+// sortTodos orders todos like the ORDER BY of the Postgres query. Text columns compare as
+// text, so priorities sort high, low, medium.
+func sortTodos(items []todo.Todo, query *todo.GetTodosQuery) {
+	sortField := "created_at"
+	if query.Sort != nil {
+		sortField = *query.Sort
+	}
+	desc := query.Sort == nil || (query.Order != nil && *query.Order == "desc")
+
+	var search string
+	if query.Search != nil {
+		search = *query.Search
+	}
+
+	slices.SortStableFunc(items, func(a, b todo.Todo) int {
+		var result int
+		switch sortField {
+		case "updated_at":
+			result = a.UpdatedAt.Compare(b.UpdatedAt)
+		case "title":
+			result = strings.Compare(a.Title, b.Title)
+		case "priority":
+			result = cmp.Compare(a.PriorityLevel, b.PriorityLevel)
+		case "status":
+			result = strings.Compare(string(a.Status), string(b.Status))
+		case "due_date":
+			result = compareNullableTimes(a.DueDate, b.DueDate)
+		case todo.SortRelevance:
+			if query.Search != nil {
+				// Ties on the score go to the newest first, whatever the direction
+				result = cmp.Compare(searchScore(&a, search), searchScore(&b, search))
+				if result == 0 {
+					return b.CreatedAt.Compare(a.CreatedAt)
+				}
+				break
+			}
+			// Nothing to rank without a search, fall back to the creation time
+			result = a.CreatedAt.Compare(b.CreatedAt)
+		default:
+			result = a.CreatedAt.Compare(b.CreatedAt)
+		}
+
+		if desc {
+			return -result
+		}
+		return result
+	})
+}
+
+// searchScore stands in for the full text rank: a case insensitive substring match, title
+// matches weigh more than description matches. Zero means the todo doesn't match.
+func searchScore(item *todo.Todo, query string) float64 {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return 0
+	}
+
+	var score float64
+	if strings.Contains(strings.ToLower(item.Title), query) {
+		score += 1
+	}
+	if strings.Contains(strings.ToLower(item.Description), query) {
+		score += 0.5
+	}
+	return score
+}
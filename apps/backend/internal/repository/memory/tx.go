@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+)
+
+// TxManager fakes transactions by restoring the store when a unit of work fails. Writes of
+// a unit of work are seen by concurrent callers before it ends, tests run units one at a time.
+type TxManager struct {
+	store *Store
+}
+
+func NewTxManager(store *Store) *TxManager {
+	return &TxManager{store: store}
+}
+
+// WithinTx runs fn and rolls the store back to where it was if fn fails. A nested call rolls
+// back only its own writes, like a savepoint.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	s := m.store
+
+	s.mu.Lock()
+	saved := s.clone()
+	s.mu.Unlock()
+
+	if err := fn(ctx); err != nil {
+		s.mu.Lock()
+		s.restore(saved)
+		s.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// clone copies every row of the store, so later writes leave the copy untouched
+func (s *Store) clone() *Store {
+	todos := make(map[uuid.UUID]*todo.Todo, len(s.todos))
+	for id, item := range s.todos {
+		copied := copyTodo(item)
+		todos[id] = &copied
+	}
+
+	snapshots := make(map[uuid.UUID][]todo.TodoVersion, len(s.snapshots))
+	for id, versions := range s.snapshots {
+		snapshots[id] = slices.Clone(versions)
+	}
+
+	s.keysMu.Lock()
+	dataKeys := cloneRows(s.dataKeys)
+	s.keysMu.Unlock()
+
+	return &Store{
+		todos:             todos,
+		sortOrder:         s.sortOrder,
+		snapshots:         snapshots,
+		categories:        cloneRows(s.categories),
+		comments:          cloneRows(s.comments),
+		attachments:       cloneRows(s.attachments),
+		embeddings:        cloneRows(s.embeddings),
+		changes:           slices.Clone(s.changes),
+		nextChangeID:      s.nextChangeID,
+		auditLog:          slices.Clone(s.auditLog),
+		nextAuditID:       s.nextAuditID,
+		overrides:         cloneRows(s.overrides),
+		usage:             maps.Clone(s.usage),
+		exports:           cloneRows(s.exports),
+		retentionPolicies: cloneRows(s.retentionPolicies),
+		storageQuotas:     cloneRows(s.storageQuotas),
+		dataKeys:          dataKeys,
+		backups:           cloneRows(s.backups),
+		restores:          cloneRows(s.restores),
+		rules:             cloneRows(s.rules),
+		ruleExecutions:    slices.Clone(s.ruleExecutions),
+		templates:         cloneRows(s.templates),
+		onboardingKits:    cloneRows(s.onboardingKits),
+		workflows:         cloneRows(s.workflows),
+		workspaceMembers:  maps.Clone(s.workspaceMembers),
+		agingPolicies:     cloneRows(s.agingPolicies),
+		slaBreaches:       maps.Clone(s.slaBreaches),
+		invites:           cloneRows(s.invites),
+		inviteAcceptances: slices.Clone(s.inviteAcceptances),
+		freezes:           cloneRows(s.freezes),
+		linkPreviews:      cloneRows(s.linkPreviews),
+		covers:            cloneRows(s.covers),
+		notes:             cloneRows(s.notes),
+		views:             maps.Clone(s.views),
+		viewCounts:        maps.Clone(s.viewCounts),
+		myDayTodos:        cloneRows(s.myDayTodos),
+		reportSchedules:   cloneRows(s.reportSchedules),
+		magicTags:         cloneRows(s.magicTags),
+		userLocales:       maps.Clone(s.userLocales),
+		statusChecks:      cloneRows(s.statusChecks),
+		incidents:         cloneRows(s.incidents),
+		apiKeys:           cloneRows(s.apiKeys),
+		webhookEndpoints:  cloneRows(s.webhookEndpoints),
+		summaries:         maps.Clone(s.summaries),
+		dailyCompletions:  maps.Clone(s.dailyCompletions),
+		categoryStats:     maps.Clone(s.categoryStats),
+		vacuumedAt:        maps.Clone(s.vacuumedAt),
+		analyzedAt:        maps.Clone(s.analyzedAt),
+	}
+}
+
+// restore puts back the rows of a clone, the clock is kept
+func (s *Store) restore(saved *Store) {
+	s.todos = saved.todos
+	s.sortOrder = saved.sortOrder
+	s.snapshots = saved.snapshots
+	s.categories = saved.categories
+	s.comments = saved.comments
+	s.attachments = saved.attachments
+	s.embeddings = saved.embeddings
+	s.changes = saved.changes
+	s.nextChangeID = saved.nextChangeID
+	s.auditLog = saved.auditLog
+	s.nextAuditID = saved.nextAuditID
+	s.overrides = saved.overrides
+	s.usage = saved.usage
+	s.exports = saved.exports
+	s.retentionPolicies = saved.retentionPolicies
+	s.storageQuotas = saved.storageQuotas
+	s.keysMu.Lock()
+	s.dataKeys = saved.dataKeys
+	s.keysMu.Unlock()
+	s.backups = saved.backups
+	s.restores = saved.restores
+	s.rules = saved.rules
+	s.ruleExecutions = saved.ruleExecutions
+	s.templates = saved.templates
+	s.onboardingKits = saved.onboardingKits
+	s.workflows = saved.workflows
+	s.workspaceMembers = saved.workspaceMembers
+	s.agingPolicies = saved.agingPolicies
+	s.slaBreaches = saved.slaBreaches
+	s.invites = saved.invites
+	s.inviteAcceptances = saved.inviteAcceptances
+	s.freezes = saved.freezes
+	s.linkPreviews = saved.linkPreviews
+	s.covers = saved.covers
+	s.notes = saved.notes
+	s.views = saved.views
+	s.viewCounts = saved.viewCounts
+	s.myDayTodos = saved.myDayTodos
+	s.reportSchedules = saved.reportSchedules
+	s.magicTags = saved.magicTags
+	s.userLocales = saved.userLocales
+	s.statusChecks = saved.statusChecks
+	s.incidents = saved.incidents
+	s.apiKeys = saved.apiKeys
+	s.webhookEndpoints = saved.webhookEndpoints
+	s.summaries = saved.summaries
+	s.dailyCompletions = saved.dailyCompletions
+	s.categoryStats = saved.categoryStats
+	s.vacuumedAt = saved.vacuumedAt
+	s.analyzedAt = saved.analyzedAt
+}
+
+func cloneRows[K comparable, V any](rows map[K]*V) map[K]*V {
+	cloned := make(map[K]*V, len(rows))
+	for key, row := range rows {
+		copied := *row
+		cloned[key] = &copied
+	}
+	return cloned
+}
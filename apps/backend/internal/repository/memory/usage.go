@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/usage"
+)
+
+// usageKey is the unique key of an hourly usage row, hours are kept in UTC so equal instants
+// compare equal
+type usageKey struct {
+	hour        time.Time
+	subjectType usage.SubjectType
+	subjectID   string
+	metric      usage.Metric
+}
+
+type UsageRepository struct {
+	store *Store
+}
+
+func NewUsageRepository(store *Store) *UsageRepository {
+	return &UsageRepository{store: store}
+}
+
+// UpsertHourly stores rolled up usage, quantities replace what is stored
+func (r *UsageRepository) UpsertHourly(ctx context.Context, records []usage.Record) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, record := range records {
+		s.usage[usageKey{
+			hour:        record.Hour.UTC(),
+			subjectType: record.SubjectType,
+			subjectID:   record.SubjectID,
+			metric:      record.Metric,
+		}] = record.Quantity
+	}
+
+	return nil
+}
+
+// SnapshotStorage stores the attachment bytes of every todo owner for an hour. Owners who had
+// storage the hour before get an explicit zero once their attachments are gone.
+func (r *UsageRepository) SnapshotStorage(ctx context.Context, hour time.Time) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hour = hour.UTC()
+	bytes := map[string]int64{}
+	for _, attachment := range s.attachments {
+		item, ok := s.todos[attachment.TodoID]
+		if !ok {
+			continue
+		}
+		var size int64
+		if attachment.FileSize != nil {
+			size = *attachment.FileSize
+		}
+		bytes[item.UserID] += size
+	}
+
+	previousHour := hour.Add(-time.Hour)
+	for key, quantity := range s.usage {
+		if key.hour.Equal(previousHour) && key.subjectType == usage.SubjectUser &&
+			key.metric == usage.MetricStorageBytes && quantity > 0 {
+			if _, ok := bytes[key.subjectID]; !ok {
+				bytes[key.subjectID] = 0
+			}
+		}
+	}
+
+	for userID, quantity := range bytes {
+		s.usage[usageKey{
+			hour:        hour,
+			subjectType: usage.SubjectUser,
+			subjectID:   userID,
+			metric:      usage.MetricStorageBytes,
+		}] = quantity
+	}
+
+	return int64(len(bytes)), nil
+}
+
+// GetBuckets returns the usage of a subject per hour or day in [from, to), oldest first.
+// Buckets without usage are left out.
+func (r *UsageRepository) GetBuckets(ctx context.Context, subjectType usage.SubjectType, subjectID string,
+	from, to time.Time, granularity usage.Granularity,
+) ([]usage.Bucket, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type bucket struct {
+		usage.Bucket
+		// storageHour is the hour the storage value was taken from, the latest one wins
+		storageHour time.Time
+	}
+
+	buckets := map[time.Time]*bucket{}
+	for key, quantity := range s.usage {
+		if key.subjectType != subjectType || key.subjectID != subjectID || key.hour.Before(from) || !key.hour.Before(to) {
+			continue
+		}
+
+		start := key.hour.Truncate(time.Hour)
+		if granularity == usage.GranularityDay {
+			start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+		}
+
+		b, ok := buckets[start]
+		if !ok {
+			b = &bucket{Bucket: usage.Bucket{Start: start}}
+			buckets[start] = b
+		}
+
+		switch key.metric {
+		case usage.MetricAPICalls:
+			b.APICalls += quantity
+		case usage.MetricNotifications:
+			b.Notifications += quantity
+		case usage.MetricStorageBytes:
+			if b.StorageBytes == nil || key.hour.After(b.storageHour) {
+				storageBytes := quantity
+				b.StorageBytes = &storageBytes
+				b.storageHour = key.hour
+			}
+		}
+	}
+
+	result := make([]usage.Bucket, 0, len(buckets))
+	for _, b := range buckets {
+		result = append(result, b.Bucket)
+	}
+	slices.SortFunc(result, func(a, b usage.Bucket) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	return result, nil
+}
@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/envelope"
+	"github.com/Sameer16536/ExecuTask/internal/model/webhook"
+	"github.com/google/uuid"
+)
+
+// WebhookRepository keeps the secrets sealed like the Postgres repository does
+type WebhookRepository struct {
+	store   *Store
+	keyring *envelope.Keyring
+}
+
+func NewWebhookRepository(store *Store, keyring *envelope.Keyring) *WebhookRepository {
+	return &WebhookRepository{store: store, keyring: keyring}
+}
+
+func (r *WebhookRepository) GetEndpoints(ctx context.Context, workspaceID string) ([]webhook.Endpoint, error) {
+	return r.endpoints(ctx, func(endpoint *webhook.Endpoint) bool {
+		return endpoint.WorkspaceID == workspaceID
+	})
+}
+
+func (r *WebhookRepository) GetEndpoint(ctx context.Context, workspaceID string,
+	endpointID uuid.UUID,
+) (*webhook.Endpoint, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	endpoint, ok := s.webhookEndpoints[endpointID]
+	if !ok || endpoint.WorkspaceID != workspaceID {
+		return nil, webhookNotFound()
+	}
+
+	return openEndpoint(ctx, r.keyring, endpoint)
+}
+
+func (r *WebhookRepository) GetSubscribedEndpoints(ctx context.Context, workspaceID,
+	event string,
+) ([]webhook.Endpoint, error) {
+	return r.endpoints(ctx, func(endpoint *webhook.Endpoint) bool {
+		return endpoint.WorkspaceID == workspaceID && endpoint.Subscribes(event)
+	})
+}
+
+func (r *WebhookRepository) CountEndpoints(ctx context.Context, workspaceID string) (int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, endpoint := range s.webhookEndpoints {
+		if endpoint.WorkspaceID == workspaceID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *WebhookRepository) CreateEndpoint(ctx context.Context, workspaceID, createdBy, secret string,
+	payload *webhook.CreateEndpointPayload,
+) (*webhook.Endpoint, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sealed, err := r.keyring.Seal(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	endpoint := &webhook.Endpoint{
+		WorkspaceID: workspaceID,
+		URL:         payload.URL,
+		Secret:      sealed,
+		Events:      slices.Clone(payload.Events),
+		Enabled:     *payload.Enabled,
+		CreatedBy:   createdBy,
+	}
+	endpoint.ID = uuid.New()
+	endpoint.CreatedAt = now
+	endpoint.UpdatedAt = now
+	s.webhookEndpoints[endpoint.ID] = endpoint
+
+	return openEndpoint(ctx, r.keyring, endpoint)
+}
+
+func (r *WebhookRepository) UpdateEndpoint(ctx context.Context, workspaceID string,
+	payload *webhook.UpdateEndpointPayload,
+) (*webhook.Endpoint, error) {
+	if payload.URL == nil && payload.Events == nil && payload.Enabled == nil {
+		return nil, errs.NewBadRequestError("no fields to update", false, nil, nil, nil)
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.webhookEndpoints[payload.ID]
+	if !ok || stored.WorkspaceID != workspaceID {
+		return nil, webhookNotFound()
+	}
+
+	endpoint := *stored
+	if payload.URL != nil {
+		endpoint.URL = *payload.URL
+	}
+	if payload.Events != nil {
+		endpoint.Events = slices.Clone(payload.Events)
+	}
+	if payload.Enabled != nil {
+		endpoint.Enabled = *payload.Enabled
+	}
+	endpoint.UpdatedAt = s.now()
+	s.webhookEndpoints[endpoint.ID] = &endpoint
+
+	return openEndpoint(ctx, r.keyring, &endpoint)
+}
+
+func (r *WebhookRepository) DeleteEndpoint(ctx context.Context, workspaceID string,
+	endpointID uuid.UUID,
+) (*webhook.Endpoint, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	endpoint, ok := s.webhookEndpoints[endpointID]
+	if !ok || endpoint.WorkspaceID != workspaceID {
+		return nil, webhookNotFound()
+	}
+	delete(s.webhookEndpoints, endpointID)
+
+	return openEndpoint(ctx, r.keyring, endpoint)
+}
+
+// endpoints returns the matching endpoints oldest first, with their secrets opened
+func (r *WebhookRepository) endpoints(ctx context.Context,
+	match func(endpoint *webhook.Endpoint) bool,
+) ([]webhook.Endpoint, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	endpoints := []webhook.Endpoint{}
+	for _, endpoint := range s.webhookEndpoints {
+		if !match(endpoint) {
+			continue
+		}
+		opened, err := openEndpoint(ctx, r.keyring, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, *opened)
+	}
+	slices.SortFunc(endpoints, func(a, b webhook.Endpoint) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return endpoints, nil
+}
+
+// openEndpoint copies a stored endpoint with its secret opened
+func openEndpoint(ctx context.Context, keyring *envelope.Keyring, endpoint *webhook.Endpoint) (*webhook.Endpoint, error) {
+	secret, err := keyring.Decrypt(ctx, endpoint.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := *endpoint
+	copied.Secret = secret
+	copied.Events = slices.Clone(endpoint.Events)
+	return &copied, nil
+}
+
+func webhookNotFound() error {
+	code := errs.CodeWebhookNotFound
+	return errs.NewNotFoundError("webhook endpoint not found", false, &code)
+}
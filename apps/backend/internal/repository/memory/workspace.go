@@ -0,0 +1,814 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/planner"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/model/workspace"
+	"github.com/google/uuid"
+)
+
+type memberKey struct {
+	workspaceID string
+	userID      string
+}
+
+type breachKey struct {
+	policyID uuid.UUID
+	todoID   uuid.UUID
+}
+
+type WorkspaceRepository struct {
+	store *Store
+}
+
+func NewWorkspaceRepository(store *Store) *WorkspaceRepository {
+	return &WorkspaceRepository{store: store}
+}
+
+func (r *WorkspaceRepository) GetTemplates(ctx context.Context, workspaceID string) ([]workspace.Template, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	templates := []workspace.Template{}
+	for _, template := range s.templates {
+		if template.WorkspaceID == workspaceID {
+			templates = append(templates, copyTemplate(template))
+		}
+	}
+	slices.SortFunc(templates, func(a, b workspace.Template) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return templates, nil
+}
+
+func (r *WorkspaceRepository) GetTemplate(ctx context.Context, workspaceID string, templateID uuid.UUID,
+) (*workspace.Template, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	template, ok := s.templates[templateID]
+	if !ok || template.WorkspaceID != workspaceID {
+		code := errs.CodeTemplateNotFound
+		return nil, errs.NewNotFoundError("template not found", false, &code)
+	}
+
+	copied := copyTemplate(template)
+	return &copied, nil
+}
+
+func (r *WorkspaceRepository) CreateTemplate(ctx context.Context, createdBy string,
+	payload *workspace.CreateTemplatePayload,
+) (*workspace.Template, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.templates {
+		if existing.WorkspaceID == payload.WorkspaceID && existing.Name == payload.Name {
+			code := errs.CodeTemplateExists
+			return nil, errs.NewConflictError("the workspace already has a template with that name", false, &code)
+		}
+	}
+
+	template := &workspace.Template{
+		WorkspaceID: payload.WorkspaceID,
+		Name:        payload.Name,
+		Title:       payload.Title,
+		Description: payload.Description,
+		Priority:    payload.Priority,
+		Subtasks:    slices.Clone(payload.Subtasks),
+		DueInDays:   payload.DueInDays,
+		CreatedBy:   createdBy,
+	}
+	template.ID = uuid.New()
+	template.CreatedAt = s.now()
+	template.UpdatedAt = template.CreatedAt
+	s.templates[template.ID] = template
+
+	copied := copyTemplate(template)
+	return &copied, nil
+}
+
+func (r *WorkspaceRepository) DeleteTemplate(ctx context.Context, workspaceID string, templateID uuid.UUID,
+) (*workspace.Template, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	template, ok := s.templates[templateID]
+	if !ok || template.WorkspaceID != workspaceID {
+		code := errs.CodeTemplateNotFound
+		return nil, errs.NewNotFoundError("template not found", false, &code)
+	}
+	delete(s.templates, templateID)
+
+	return template, nil
+}
+
+func (r *WorkspaceRepository) GetOnboardingKit(ctx context.Context, workspaceID string) (*workspace.OnboardingKit, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kit, ok := s.onboardingKits[workspaceID]
+	if !ok {
+		code := errs.CodeOnboardingKitNotFound
+		return nil, errs.NewNotFoundError("the workspace has no onboarding kit", false, &code)
+	}
+
+	copied := copyOnboardingKit(kit)
+	return &copied, nil
+}
+
+func (r *WorkspaceRepository) SetOnboardingKit(ctx context.Context, updatedBy string,
+	payload *workspace.SetOnboardingKitPayload,
+) (*workspace.OnboardingKit, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	kit, ok := s.onboardingKits[payload.WorkspaceID]
+	if !ok {
+		kit = &workspace.OnboardingKit{WorkspaceID: payload.WorkspaceID}
+		kit.CreatedAt = now
+		s.onboardingKits[payload.WorkspaceID] = kit
+	}
+
+	kit.Categories = slices.Clone(payload.Categories)
+	kit.Todos = slices.Clone(payload.Todos)
+	kit.UpdatedBy = updatedBy
+	kit.UpdatedAt = now
+
+	copied := copyOnboardingKit(kit)
+	return &copied, nil
+}
+
+func (r *WorkspaceRepository) DeleteOnboardingKit(ctx context.Context, workspaceID string) (*workspace.OnboardingKit, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kit, ok := s.onboardingKits[workspaceID]
+	if !ok {
+		code := errs.CodeOnboardingKitNotFound
+		return nil, errs.NewNotFoundError("the workspace has no onboarding kit", false, &code)
+	}
+	delete(s.onboardingKits, workspaceID)
+
+	return kit, nil
+}
+
+func (r *WorkspaceRepository) GetWorkflow(ctx context.Context, workspaceID string) (*workspace.Workflow, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	workflow, ok := s.workflows[workspaceID]
+	if !ok {
+		return nil, errWorkflowNotFound()
+	}
+
+	copied := copyWorkflow(workflow)
+	return &copied, nil
+}
+
+func (r *WorkspaceRepository) SetWorkflow(ctx context.Context, updatedBy string,
+	payload *workspace.SetWorkflowPayload,
+) (*workspace.Workflow, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	workflow, ok := s.workflows[payload.WorkspaceID]
+	if !ok {
+		workflow = &workspace.Workflow{WorkspaceID: payload.WorkspaceID}
+		workflow.CreatedAt = now
+		s.workflows[payload.WorkspaceID] = workflow
+	}
+
+	workflow.Statuses = slices.Clone(payload.Statuses)
+	workflow.Priorities = slices.Clone(payload.Priorities)
+	workflow.UpdatedBy = updatedBy
+	workflow.UpdatedAt = now
+
+	copied := copyWorkflow(workflow)
+	return &copied, nil
+}
+
+func (r *WorkspaceRepository) DeleteWorkflow(ctx context.Context, workspaceID string) (*workspace.Workflow, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	workflow, ok := s.workflows[workspaceID]
+	if !ok {
+		return nil, errWorkflowNotFound()
+	}
+	delete(s.workflows, workspaceID)
+
+	return workflow, nil
+}
+
+func (r *WorkspaceRepository) AddMember(ctx context.Context, workspaceID, userID string) (bool, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := memberKey{workspaceID: workspaceID, userID: userID}
+	if _, ok := s.workspaceMembers[key]; ok {
+		return false, nil
+	}
+	s.workspaceMembers[key] = s.now()
+
+	return true, nil
+}
+
+func (r *WorkspaceRepository) GetWorkload(ctx context.Context, workspaceID string, from, to time.Time,
+	location *time.Location,
+) ([]workspace.WorkloadBucket, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type bucketKey struct {
+		userID string
+		bucket string
+	}
+	loads := map[bucketKey]*workspace.Load{}
+	for _, item := range s.todos {
+		if item.WorkspaceID == nil || *item.WorkspaceID != workspaceID ||
+			(item.StatusCategory != todo.StatusDraft && item.StatusCategory != todo.StatusActive) ||
+			(item.DueDate != nil && !item.DueDate.Before(to)) {
+			continue
+		}
+
+		key := bucketKey{userID: item.UserID}
+		switch {
+		case item.DueDate == nil:
+			key.bucket = workspace.BucketUnscheduled
+		case item.DueDate.Before(from):
+			key.bucket = workspace.BucketOverdue
+		default:
+			key.bucket = planner.WeekStart(item.DueDate.In(location)).Format(time.DateOnly)
+		}
+
+		load, ok := loads[key]
+		if !ok {
+			load = &workspace.Load{}
+			loads[key] = load
+		}
+		load.OpenTodos++
+		if item.Metadata != nil && item.Metadata.EstimatedMinutes != nil {
+			load.EstimatedMinutes += *item.Metadata.EstimatedMinutes
+		} else {
+			load.UnestimatedCount++
+		}
+	}
+
+	buckets := make([]workspace.WorkloadBucket, 0, len(loads))
+	for key, load := range loads {
+		buckets = append(buckets, workspace.WorkloadBucket{UserID: key.userID, Bucket: key.bucket, Load: *load})
+	}
+	slices.SortFunc(buckets, func(a, b workspace.WorkloadBucket) int {
+		return cmp.Or(strings.Compare(a.UserID, b.UserID), strings.Compare(a.Bucket, b.Bucket))
+	})
+
+	return buckets, nil
+}
+
+func (r *WorkspaceRepository) GetAgingPolicies(ctx context.Context, workspaceID string) ([]workspace.AgingPolicy, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	policies := []workspace.AgingPolicy{}
+	for _, policy := range s.agingPolicies {
+		if policy.WorkspaceID == workspaceID {
+			policies = append(policies, *policy)
+		}
+	}
+	slices.SortFunc(policies, func(a, b workspace.AgingPolicy) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return policies, nil
+}
+
+func (r *WorkspaceRepository) CreateAgingPolicy(ctx context.Context, createdBy string,
+	payload *workspace.CreateAgingPolicyPayload,
+) (*workspace.AgingPolicy, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.agingPolicies {
+		if existing.WorkspaceID == payload.WorkspaceID && existing.Name == payload.Name {
+			code := errs.CodeAgingPolicyExists
+			return nil, errs.NewConflictError("the workspace already has an aging policy with that name", false, &code)
+		}
+	}
+
+	policy := &workspace.AgingPolicy{
+		WorkspaceID: payload.WorkspaceID,
+		Name:        payload.Name,
+		Priority:    payload.Priority,
+		MaxAgeHours: payload.MaxAgeHours,
+		Tag:         *payload.Tag,
+		Notify:      *payload.Notify,
+		CreatedBy:   createdBy,
+	}
+	policy.ID = uuid.New()
+	policy.CreatedAt = s.now()
+	policy.UpdatedAt = policy.CreatedAt
+	s.agingPolicies[policy.ID] = policy
+
+	copied := *policy
+	return &copied, nil
+}
+
+func (r *WorkspaceRepository) DeleteAgingPolicy(ctx context.Context, workspaceID string, policyID uuid.UUID,
+) (*workspace.AgingPolicy, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	policy, ok := s.agingPolicies[policyID]
+	if !ok || policy.WorkspaceID != workspaceID {
+		code := errs.CodeAgingPolicyNotFound
+		return nil, errs.NewNotFoundError("aging policy not found", false, &code)
+	}
+	delete(s.agingPolicies, policyID)
+	for key := range s.slaBreaches {
+		if key.policyID == policyID {
+			delete(s.slaBreaches, key)
+		}
+	}
+
+	return policy, nil
+}
+
+func (r *WorkspaceRepository) GetAgingPolicyWorkspaceIDs(ctx context.Context) ([]string, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	workspaceIDs := []string{}
+	for _, policy := range s.agingPolicies {
+		if !slices.Contains(workspaceIDs, policy.WorkspaceID) {
+			workspaceIDs = append(workspaceIDs, policy.WorkspaceID)
+		}
+	}
+	slices.Sort(workspaceIDs)
+
+	return workspaceIDs, nil
+}
+
+func (r *WorkspaceRepository) RecordBreaches(ctx context.Context, policy *workspace.AgingPolicy, now time.Time,
+	limit int,
+) ([]workspace.Breach, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	breached := []*todo.Todo{}
+	for _, item := range s.todos {
+		if item.WorkspaceID == nil || *item.WorkspaceID != policy.WorkspaceID || !policy.Breaches(item, now) {
+			continue
+		}
+		if _, ok := s.slaBreaches[breachKey{policyID: policy.ID, todoID: item.ID}]; ok {
+			continue
+		}
+		breached = append(breached, item)
+	}
+	slices.SortFunc(breached, func(a, b *todo.Todo) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	if len(breached) > limit {
+		breached = breached[:limit]
+	}
+
+	breaches := make([]workspace.Breach, 0, len(breached))
+	for _, item := range breached {
+		s.slaBreaches[breachKey{policyID: policy.ID, todoID: item.ID}] = now
+		breaches = append(breaches, newBreach(policy, item, now))
+	}
+
+	return breaches, nil
+}
+
+func (r *WorkspaceRepository) GetBreaches(ctx context.Context, workspaceID string) ([]workspace.Breach, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	breaches := []workspace.Breach{}
+	for key, breachedAt := range s.slaBreaches {
+		policy, ok := s.agingPolicies[key.policyID]
+		if !ok || policy.WorkspaceID != workspaceID {
+			continue
+		}
+		item, ok := s.todos[key.todoID]
+		if !ok || (item.StatusCategory != todo.StatusDraft && item.StatusCategory != todo.StatusActive) {
+			continue
+		}
+		breaches = append(breaches, newBreach(policy, item, breachedAt))
+	}
+	slices.SortFunc(breaches, func(a, b workspace.Breach) int {
+		return a.OpenedAt.Compare(b.OpenedAt)
+	})
+
+	return breaches, nil
+}
+
+func newBreach(policy *workspace.AgingPolicy, item *todo.Todo, breachedAt time.Time) workspace.Breach {
+	return workspace.Breach{
+		PolicyID:    policy.ID,
+		PolicyName:  policy.Name,
+		MaxAgeHours: policy.MaxAgeHours,
+		TodoID:      item.ID,
+		TodoTitle:   item.Title,
+		UserID:      item.UserID,
+		Priority:    item.Priority,
+		OpenedAt:    item.CreatedAt,
+		BreachedAt:  breachedAt,
+	}
+}
+
+// copyTemplate copies a template with its subtasks, the way a row comes out of the database
+func copyTemplate(template *workspace.Template) workspace.Template {
+	copied := *template
+	copied.Subtasks = slices.Clone(template.Subtasks)
+	return copied
+}
+
+func copyOnboardingKit(kit *workspace.OnboardingKit) workspace.OnboardingKit {
+	copied := *kit
+	copied.Categories = slices.Clone(kit.Categories)
+	copied.Todos = slices.Clone(kit.Todos)
+	return copied
+}
+
+func copyWorkflow(workflow *workspace.Workflow) workspace.Workflow {
+	copied := *workflow
+	copied.Statuses = slices.Clone(workflow.Statuses)
+	copied.Priorities = slices.Clone(workflow.Priorities)
+	return copied
+}
+
+func errWorkflowNotFound() error {
+	code := errs.CodeWorkflowNotFound
+	return errs.NewNotFoundError("the workspace has no workflow", false, &code)
+}
+
+func (r *WorkspaceRepository) GetInvites(ctx context.Context, workspaceID string) ([]workspace.Invite, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invites := []workspace.Invite{}
+	for _, invite := range s.invites {
+		if invite.WorkspaceID == workspaceID {
+			invites = append(invites, copyInvite(invite))
+		}
+	}
+	slices.SortFunc(invites, func(a, b workspace.Invite) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return invites, nil
+}
+
+func (r *WorkspaceRepository) GetInviteAcceptances(ctx context.Context, workspaceID string,
+) ([]workspace.InviteAcceptance, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acceptances := []workspace.InviteAcceptance{}
+	for _, acceptance := range s.inviteAcceptances {
+		if acceptance.WorkspaceID == workspaceID {
+			acceptances = append(acceptances, acceptance)
+		}
+	}
+	slices.SortStableFunc(acceptances, func(a, b workspace.InviteAcceptance) int {
+		return b.AcceptedAt.Compare(a.AcceptedAt)
+	})
+
+	return acceptances, nil
+}
+
+func (r *WorkspaceRepository) CreateInvite(ctx context.Context, invite *workspace.Invite) (*workspace.Invite, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.invites {
+		if existing.TokenHash == invite.TokenHash {
+			return nil, uniqueViolation("workspace_invites", "workspace_invites_token_hash_key")
+		}
+	}
+
+	created := copyInvite(invite)
+	created.ID = uuid.New()
+	created.CreatedAt = s.now()
+	created.UpdatedAt = created.CreatedAt
+	created.UseCount = 0
+	created.RevokedAt = nil
+	created.Status = ""
+	created.Token = nil
+	s.invites[created.ID] = &created
+
+	copied := copyInvite(&created)
+	return &copied, nil
+}
+
+func (r *WorkspaceRepository) GetInviteByToken(ctx context.Context, tokenHash string) (*workspace.Invite, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, invite := range s.invites {
+		if invite.TokenHash == tokenHash {
+			copied := copyInvite(invite)
+			return &copied, nil
+		}
+	}
+
+	code := errs.CodeInviteNotFound
+	return nil, errs.NewNotFoundError("invite not found", false, &code)
+}
+
+func (r *WorkspaceRepository) RevokeInvite(ctx context.Context, workspaceID string, inviteID uuid.UUID,
+) (*workspace.Invite, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invite, ok := s.invites[inviteID]
+	if !ok || invite.WorkspaceID != workspaceID {
+		code := errs.CodeInviteNotFound
+		return nil, errs.NewNotFoundError("invite not found", false, &code)
+	}
+	if invite.RevokedAt == nil {
+		now := s.now()
+		invite.RevokedAt = &now
+		invite.UpdatedAt = now
+	}
+
+	copied := copyInvite(invite)
+	return &copied, nil
+}
+
+func (r *WorkspaceRepository) HasAcceptedInvite(ctx context.Context, inviteID uuid.UUID, userID string) (bool, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.ContainsFunc(s.inviteAcceptances, func(a workspace.InviteAcceptance) bool {
+		return a.InviteID == inviteID && a.UserID == userID
+	}), nil
+}
+
+func (r *WorkspaceRepository) AcceptInvite(ctx context.Context, invite *workspace.Invite, userID, email string,
+) (*workspace.InviteAcceptance, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	stored, ok := s.invites[invite.ID]
+	if !ok || stored.StatusAt(now) != workspace.InviteStatusPending {
+		code := errs.CodeInviteUsedUp
+		return nil, errs.NewGoneError("the invite can no longer be accepted", false, &code)
+	}
+	if slices.ContainsFunc(s.inviteAcceptances, func(a workspace.InviteAcceptance) bool {
+		return a.InviteID == invite.ID && a.UserID == userID
+	}) {
+		return nil, uniqueViolation("workspace_invite_acceptances", "workspace_invite_acceptances_pkey")
+	}
+
+	stored.UseCount++
+	stored.UpdatedAt = now
+	acceptance := workspace.InviteAcceptance{
+		InviteID:    stored.ID,
+		WorkspaceID: stored.WorkspaceID,
+		UserID:      userID,
+		Email:       email,
+		AcceptedAt:  now,
+	}
+	s.inviteAcceptances = append(s.inviteAcceptances, acceptance)
+
+	return &acceptance, nil
+}
+
+func copyInvite(invite *workspace.Invite) workspace.Invite {
+	copied := *invite
+	copied.AllowedDomains = slices.Clone(invite.AllowedDomains)
+	return copied
+}
+
+func (r *WorkspaceRepository) GetFreeze(ctx context.Context, workspaceID string) (*workspace.Freeze, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	freeze, ok := s.freezes[workspaceID]
+	if !ok {
+		code := errs.CodeWorkspaceNotFrozen
+		return nil, errs.NewNotFoundError("the workspace is not frozen", false, &code)
+	}
+
+	copied := *freeze
+	return &copied, nil
+}
+
+func (r *WorkspaceRepository) IsFrozen(ctx context.Context, workspaceID string) (bool, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.freezes[workspaceID]
+	return ok, nil
+}
+
+func (r *WorkspaceRepository) CreateFreeze(ctx context.Context, freeze *workspace.Freeze) (*workspace.Freeze, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.freezes[freeze.WorkspaceID]; ok {
+		code := errs.CodeWorkspaceFrozen
+		return nil, errs.NewConflictError("the workspace is already frozen", false, &code)
+	}
+
+	created := *freeze
+	created.CreatedAt = s.now()
+	created.UpdatedAt = created.CreatedAt
+	created.BackupID = nil
+	created.PurgedAt = nil
+	s.freezes[created.WorkspaceID] = &created
+
+	copied := created
+	return &copied, nil
+}
+
+func (r *WorkspaceRepository) SetFreezeBackup(ctx context.Context, workspaceID string, backupID uuid.UUID,
+) (*workspace.Freeze, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	freeze, ok := s.freezes[workspaceID]
+	if !ok {
+		code := errs.CodeWorkspaceNotFrozen
+		return nil, errs.NewNotFoundError("the workspace is not frozen", false, &code)
+	}
+	freeze.BackupID = &backupID
+	freeze.UpdatedAt = s.now()
+
+	copied := *freeze
+	return &copied, nil
+}
+
+func (r *WorkspaceRepository) DeleteFreeze(ctx context.Context, workspaceID string) (*workspace.Freeze, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	freeze, ok := s.freezes[workspaceID]
+	if !ok {
+		code := errs.CodeWorkspaceNotFrozen
+		return nil, errs.NewNotFoundError("the workspace is not frozen", false, &code)
+	}
+	if freeze.Purged() {
+		code := errs.CodeWorkspacePurged
+		return nil, errs.NewGoneError("the workspace was purged and can't be reactivated", false, &code)
+	}
+	delete(s.freezes, workspaceID)
+
+	return freeze, nil
+}
+
+func (r *WorkspaceRepository) GetPurgeDueWorkspaceIDs(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	due := []*workspace.Freeze{}
+	for _, freeze := range s.freezes {
+		if !freeze.Purged() && !freeze.PurgeAfter.After(now) {
+			due = append(due, freeze)
+		}
+	}
+	slices.SortFunc(due, func(a, b *workspace.Freeze) int {
+		return a.PurgeAfter.Compare(b.PurgeAfter)
+	})
+
+	workspaceIDs := []string{}
+	for _, freeze := range due[:min(len(due), limit)] {
+		workspaceIDs = append(workspaceIDs, freeze.WorkspaceID)
+	}
+
+	return workspaceIDs, nil
+}
+
+func (r *WorkspaceRepository) PurgeWorkspace(ctx context.Context, workspaceID string) (map[string]int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	freeze, ok := s.freezes[workspaceID]
+	if !ok || freeze.Purged() || freeze.PurgeAfter.After(now) {
+		code := errs.CodeWorkspaceNotFrozen
+		return nil, errs.NewNotFoundError("the workspace is not frozen or was purged", false, &code)
+	}
+	freeze.PurgedAt = &now
+	freeze.UpdatedAt = now
+
+	inWorkspace := func(item *todo.Todo) bool {
+		return item.WorkspaceID != nil && *item.WorkspaceID == workspaceID
+	}
+
+	counts := map[string]int{
+		"detachedSubtasks": 0, "todos": 0, "templates": 0, "onboardingKits": 0, "agingPolicies": 0,
+		"retentionPolicies": 0, "invites": 0, "members": 0, "webhookEndpoints": 0,
+	}
+	for _, item := range s.todos {
+		if inWorkspace(item) || item.ParentTodoID == nil {
+			continue
+		}
+		if parent, ok := s.todos[*item.ParentTodoID]; ok && inWorkspace(parent) {
+			item.ParentTodoID = nil
+			counts["detachedSubtasks"]++
+		}
+	}
+	for _, item := range s.todos {
+		if inWorkspace(item) {
+			s.deleteTodo(item)
+			counts["todos"]++
+		}
+	}
+
+	for id, template := range s.templates {
+		if template.WorkspaceID == workspaceID {
+			delete(s.templates, id)
+			counts["templates"]++
+		}
+	}
+	if _, ok := s.onboardingKits[workspaceID]; ok {
+		delete(s.onboardingKits, workspaceID)
+		counts["onboardingKits"]++
+	}
+	for id, policy := range s.agingPolicies {
+		if policy.WorkspaceID != workspaceID {
+			continue
+		}
+		delete(s.agingPolicies, id)
+		for key := range s.slaBreaches {
+			if key.policyID == id {
+				delete(s.slaBreaches, key)
+			}
+		}
+		counts["agingPolicies"]++
+	}
+	if _, ok := s.retentionPolicies[workspaceID]; ok {
+		delete(s.retentionPolicies, workspaceID)
+		counts["retentionPolicies"]++
+	}
+	for id, invite := range s.invites {
+		if invite.WorkspaceID == workspaceID {
+			delete(s.invites, id)
+			counts["invites"]++
+		}
+	}
+	s.inviteAcceptances = slices.DeleteFunc(s.inviteAcceptances, func(a workspace.InviteAcceptance) bool {
+		return a.WorkspaceID == workspaceID
+	})
+	for key := range s.workspaceMembers {
+		if key.workspaceID == workspaceID {
+			delete(s.workspaceMembers, key)
+			counts["members"]++
+		}
+	}
+	for id, endpoint := range s.webhookEndpoints {
+		if endpoint.WorkspaceID == workspaceID {
+			delete(s.webhookEndpoints, id)
+			counts["webhookEndpoints"]++
+		}
+	}
+
+	return counts, nil
+}
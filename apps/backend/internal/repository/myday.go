@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/planner"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type MyDayRepository struct {
+	server *server.Server
+}
+
+func NewMyDayRepository(server *server.Server) *MyDayRepository {
+	return &MyDayRepository{server: server}
+}
+
+// AddTodos puts the todos on the list of the day, those already on it are left as they were.
+// Planning a todo waiting for review again reviews it.
+func (r *MyDayRepository) AddTodos(ctx context.Context, userID, day string, endsAt time.Time,
+	todoIDs []uuid.UUID,
+) error {
+	stmt := `
+		WITH
+			reviewed AS (
+				UPDATE my_day_todos
+				SET
+					state='reviewed'
+				WHERE
+					user_id=@user_id
+					AND todo_id=ANY (@todo_ids::UUID[])
+					AND state='review'
+			)
+		INSERT INTO
+			my_day_todos (user_id, todo_id, day, ends_at)
+		SELECT
+			@user_id,
+			todo_id,
+			@day::DATE,
+			@ends_at
+		FROM
+			UNNEST(@todo_ids::UUID[]) AS t (todo_id)
+		ON CONFLICT (user_id, day, todo_id) DO NOTHING
+	`
+
+	_, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"user_id":  userID,
+		"day":      day,
+		"ends_at":  endsAt,
+		"todo_ids": todoIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute add my day todos query for user_id=%s: %w", userID, err)
+	}
+
+	return nil
+}
+
+// RemoveTodo takes the todo off the list of the day, and drops it from review when it waits
+// for one
+func (r *MyDayRepository) RemoveTodo(ctx context.Context, userID, day string, todoID uuid.UUID) error {
+	stmt := `
+		WITH
+			removed AS (
+				DELETE FROM my_day_todos
+				WHERE
+					user_id=@user_id
+					AND todo_id=@todo_id
+					AND day=@day::DATE
+					AND state='planned'
+				RETURNING
+					1
+			),
+			dropped AS (
+				UPDATE my_day_todos
+				SET
+					state='reviewed'
+				WHERE
+					user_id=@user_id
+					AND todo_id=@todo_id
+					AND state='review'
+				RETURNING
+					1
+			)
+		SELECT
+			(SELECT count(*) FROM removed) + (SELECT count(*) FROM dropped)
+	`
+
+	var affected int64
+	err := r.server.DB.Writer(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"todo_id": todoID,
+		"day":     day,
+	}).Scan(&affected)
+	if err != nil {
+		return fmt.Errorf("failed to execute remove my day todo query for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	if affected == 0 {
+		code := errs.CodeMyDayTodoNotFound
+		return errs.NewNotFoundError("the todo is not on your day", false, &code)
+	}
+
+	return nil
+}
+
+// GetTodos returns the todos on the list of the day in the order they were added
+func (r *MyDayRepository) GetTodos(ctx context.Context, userID, day string) ([]planner.MyDayTodo, error) {
+	stmt := `
+		SELECT
+			t.*,
+			d.added_at
+		FROM
+			my_day_todos d
+			JOIN todos t ON t.id=d.todo_id
+			AND t.user_id=d.user_id
+		WHERE
+			d.user_id=@user_id
+			AND d.day=@day::DATE
+		ORDER BY
+			d.added_at ASC,
+			t.sort_order ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"day":     day,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get my day todos query for user_id=%s: %w", userID, err)
+	}
+
+	todos, err := pgx.CollectRows(rows, pgx.RowToStructByName[planner.MyDayTodo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:my_day_todos for user_id=%s: %w", userID, err)
+	}
+
+	return todos, nil
+}
+
+// GetReview returns the todos waiting for review that are still open, oldest day first
+func (r *MyDayRepository) GetReview(ctx context.Context, userID string) ([]planner.ReviewTodo, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			(
+				SELECT DISTINCT
+					ON (t.id) t.*,
+					to_char(d.day, 'YYYY-MM-DD') AS planned_for
+				FROM
+					my_day_todos d
+					JOIN todos t ON t.id=d.todo_id
+					AND t.user_id=d.user_id
+				WHERE
+					d.user_id=@user_id
+					AND d.state='review'
+					AND t.status_category<>'completed'
+				ORDER BY
+					t.id,
+					d.day DESC
+			) review
+		ORDER BY
+			planned_for ASC,
+			sort_order ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get my day review query for user_id=%s: %w", userID, err)
+	}
+
+	todos, err := pgx.CollectRows(rows, pgx.RowToStructByName[planner.ReviewTodo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:my_day_todos for user_id=%s: %w", userID, err)
+	}
+
+	return todos, nil
+}
+
+// GetSummaries counts the todos planned for every day from from to to, with those completed
+// before the day ended and those left open
+func (r *MyDayRepository) GetSummaries(ctx context.Context, userID, from, to string) ([]planner.DaySummary, error) {
+	stmt := `
+		SELECT
+			to_char(d.day, 'YYYY-MM-DD') AS date,
+			count(*) AS planned,
+			count(*) FILTER (
+				WHERE
+					t.status_category='completed'
+					AND t.completed_at<d.ends_at
+			) AS completed,
+			count(*) FILTER (
+				WHERE
+					d.state<>'planned'
+			) AS rolled
+		FROM
+			my_day_todos d
+			JOIN todos t ON t.id=d.todo_id
+			AND t.user_id=d.user_id
+		WHERE
+			d.user_id=@user_id
+			AND d.day BETWEEN @from::DATE AND @to::DATE
+		GROUP BY
+			d.day
+		ORDER BY
+			d.day DESC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"from":    from,
+		"to":      to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get day summaries query for user_id=%s: %w", userID, err)
+	}
+
+	summaries, err := pgx.CollectRows(rows, pgx.RowToStructByName[planner.DaySummary])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:my_day_todos for user_id=%s: %w", userID, err)
+	}
+
+	return summaries, nil
+}
+
+// RollOver moves the todos still open on the days ended by now to review, the completed ones
+// stay planned
+func (r *MyDayRepository) RollOver(ctx context.Context, now time.Time) (int64, error) {
+	stmt := `
+		UPDATE my_day_todos d
+		SET
+			state='review'
+		FROM
+			todos t
+		WHERE
+			t.id=d.todo_id
+			AND t.user_id=d.user_id
+			AND d.state='planned'
+			AND d.ends_at<=@now
+			AND t.status_category<>'completed'
+	`
+
+	result, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"now": now,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute roll over my day todos query: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/envelope"
+	"github.com/Sameer16536/ExecuTask/internal/model/note"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// NoteRepository seals the bodies of the notes with the keyring, see sealedColumns
+type NoteRepository struct {
+	server  *server.Server
+	keyring *envelope.Keyring
+}
+
+func NewNoteRepository(server *server.Server, keyring *envelope.Keyring) *NoteRepository {
+	return &NoteRepository{server: server, keyring: keyring}
+}
+
+// GetNote returns the note the user wrote on a todo, notes of other authors are never read
+func (r *NoteRepository) GetNote(ctx context.Context, userID string, todoID uuid.UUID) (*note.Note, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_private_notes
+		WHERE
+			todo_id = @todo_id
+			AND user_id = @user_id
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id": todoID,
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get private note query for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	item, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[note.Note])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeNoteNotFound
+			return nil, errs.NewNotFoundError("the todo has no private note", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:todo_private_notes for todo_id=%s: %w",
+			todoID.String(), err)
+	}
+
+	if err := openNote(ctx, r.keyring, &item); err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// SetNote stores the note of the user on a todo in place of the one they had
+func (r *NoteRepository) SetNote(ctx context.Context, userID string, todoID uuid.UUID, body string) (*note.Note, error) {
+	sealed, err := r.keyring.Seal(ctx, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to seal private note for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	stmt := `
+		INSERT INTO
+			todo_private_notes (todo_id, user_id, body)
+		VALUES
+			(@todo_id, @user_id, @body)
+		ON CONFLICT (todo_id, user_id) DO UPDATE
+		SET
+			body = EXCLUDED.body
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id": todoID,
+		"user_id": userID,
+		"body":    sealed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute set private note query for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	saved, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[note.Note])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todo_private_notes for todo_id=%s: %w",
+			todoID.String(), err)
+	}
+
+	saved.Body = body
+	return &saved, nil
+}
+
+func (r *NoteRepository) DeleteNote(ctx context.Context, userID string, todoID uuid.UUID) (*note.Note, error) {
+	stmt := `
+		DELETE FROM todo_private_notes
+		WHERE
+			todo_id = @todo_id
+			AND user_id = @user_id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id": todoID,
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute delete private note query for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	deleted, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[note.Note])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeNoteNotFound
+			return nil, errs.NewNotFoundError("the todo has no private note", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:todo_private_notes for todo_id=%s: %w",
+			todoID.String(), err)
+	}
+
+	if err := openNote(ctx, r.keyring, &deleted); err != nil {
+		return nil, err
+	}
+
+	return &deleted, nil
+}
+
+// openNote replaces the sealed body of a note read from the table with its plaintext
+func openNote(ctx context.Context, keyring *envelope.Keyring, item *note.Note) error {
+	body, err := keyring.Decrypt(ctx, item.Body)
+	if err != nil {
+		return fmt.Errorf("failed to open private note for todo_id=%s: %w", item.TodoID.String(), err)
+	}
+
+	item.Body = body
+	return nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/preview"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type LinkPreviewRepository struct {
+	server *server.Server
+}
+
+func NewLinkPreviewRepository(server *server.Server) *LinkPreviewRepository {
+	return &LinkPreviewRepository{server: server}
+}
+
+// GetPreviews returns the previews kept of the URLs, URLs never previewed are left out
+func (r *LinkPreviewRepository) GetPreviews(ctx context.Context, urls []string) ([]preview.Preview, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			link_previews
+		WHERE
+			url = ANY (@urls)
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"urls": urls,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get link previews query: %w", err)
+	}
+
+	previews, err := pgx.CollectRows(rows, pgx.RowToStructByName[preview.Preview])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:link_previews: %w", err)
+	}
+
+	return previews, nil
+}
+
+// SavePreview stores the preview of a URL in place of the one kept before
+func (r *LinkPreviewRepository) SavePreview(ctx context.Context, card *preview.Preview) error {
+	stmt := `
+		INSERT INTO
+			link_previews (
+				url,
+				status,
+				title,
+				description,
+				image_url,
+				site_name,
+				fetched_at
+			)
+		VALUES
+			(
+				@url,
+				@status,
+				@title,
+				@description,
+				@image_url,
+				@site_name,
+				@fetched_at
+			)
+		ON CONFLICT (url) DO UPDATE
+		SET
+			status = EXCLUDED.status,
+			title = EXCLUDED.title,
+			description = EXCLUDED.description,
+			image_url = EXCLUDED.image_url,
+			site_name = EXCLUDED.site_name,
+			fetched_at = EXCLUDED.fetched_at
+	`
+
+	_, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"url":         card.URL,
+		"status":      card.Status,
+		"title":       card.Title,
+		"description": card.Description,
+		"image_url":   card.ImageURL,
+		"site_name":   card.SiteName,
+		"fetched_at":  card.FetchedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute save link preview query for url=%s: %w", card.URL, err)
+	}
+
+	return nil
+}
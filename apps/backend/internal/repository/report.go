@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/report"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type ReportRepository struct {
+	server *server.Server
+}
+
+func NewReportRepository(server *server.Server) *ReportRepository {
+	return &ReportRepository{server: server}
+}
+
+// GetSchedules lists the report schedules of a user by name
+func (r *ReportRepository) GetSchedules(ctx context.Context, userID string) ([]report.Schedule, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			report_schedules
+		WHERE
+			user_id=@user_id
+		ORDER BY
+			name ASC,
+			created_at ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get report schedules query for user_id=%s: %w", userID, err)
+	}
+
+	schedules, err := pgx.CollectRows(rows, pgx.RowToStructByName[report.Schedule])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:report_schedules for user_id=%s: %w", userID, err)
+	}
+
+	return schedules, nil
+}
+
+func (r *ReportRepository) GetSchedule(ctx context.Context, userID string, scheduleID uuid.UUID) (*report.Schedule, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			report_schedules
+		WHERE
+			id=@id
+			AND user_id=@user_id
+	`
+
+	return r.scheduleRow(ctx, "get report schedule", stmt, pgx.NamedArgs{
+		"id":      scheduleID,
+		"user_id": userID,
+	})
+}
+
+func (r *ReportRepository) CountSchedules(ctx context.Context, userID string) (int, error) {
+	stmt := `
+		SELECT
+			COUNT(*)
+		FROM
+			report_schedules
+		WHERE
+			user_id=@user_id
+	`
+
+	var count int
+	err := r.server.DB.Writer(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	}).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute count report schedules query for user_id=%s: %w", userID, err)
+	}
+
+	return count, nil
+}
+
+func (r *ReportRepository) CreateSchedule(ctx context.Context, userID string, definition *report.Definition,
+	nextRunAt time.Time,
+) (*report.Schedule, error) {
+	stmt := `
+		INSERT INTO
+			report_schedules (
+				user_id,
+				name,
+				format,
+				filters,
+				recipients,
+				frequency,
+				hour,
+				weekday,
+				day_of_month,
+				timezone,
+				next_run_at
+			)
+		VALUES
+			(
+				@user_id,
+				@name,
+				@format,
+				@filters,
+				@recipients,
+				@frequency,
+				@hour,
+				@weekday,
+				@day_of_month,
+				@timezone,
+				@next_run_at
+			)
+		RETURNING
+			*
+	`
+
+	args := scheduleArgs(definition)
+	args["user_id"] = userID
+	args["next_run_at"] = nextRunAt
+
+	return r.scheduleRow(ctx, "create report schedule", stmt, args)
+}
+
+// UpdateSchedule replaces the definition of a schedule and when it runs next
+func (r *ReportRepository) UpdateSchedule(ctx context.Context, userID string, scheduleID uuid.UUID,
+	definition *report.Definition, nextRunAt time.Time,
+) (*report.Schedule, error) {
+	stmt := `
+		UPDATE report_schedules
+		SET
+			name=@name,
+			format=@format,
+			filters=@filters,
+			recipients=@recipients,
+			frequency=@frequency,
+			hour=@hour,
+			weekday=@weekday,
+			day_of_month=@day_of_month,
+			timezone=@timezone,
+			next_run_at=@next_run_at
+		WHERE
+			id=@id
+			AND user_id=@user_id
+		RETURNING
+			*
+	`
+
+	args := scheduleArgs(definition)
+	args["id"] = scheduleID
+	args["user_id"] = userID
+	args["next_run_at"] = nextRunAt
+
+	return r.scheduleRow(ctx, "update report schedule", stmt, args)
+}
+
+// PauseSchedule stops the runs of a schedule, pausing it again keeps when it was first paused
+func (r *ReportRepository) PauseSchedule(ctx context.Context, userID string, scheduleID uuid.UUID) (*report.Schedule, error) {
+	stmt := `
+		UPDATE report_schedules
+		SET
+			paused_at=COALESCE(paused_at, NOW())
+		WHERE
+			id=@id
+			AND user_id=@user_id
+		RETURNING
+			*
+	`
+
+	return r.scheduleRow(ctx, "pause report schedule", stmt, pgx.NamedArgs{
+		"id":      scheduleID,
+		"user_id": userID,
+	})
+}
+
+// ResumeSchedule runs a paused schedule again from nextRunAt
+func (r *ReportRepository) ResumeSchedule(ctx context.Context, userID string, scheduleID uuid.UUID,
+	nextRunAt time.Time,
+) (*report.Schedule, error) {
+	stmt := `
+		UPDATE report_schedules
+		SET
+			paused_at=NULL,
+			next_run_at=@next_run_at
+		WHERE
+			id=@id
+			AND user_id=@user_id
+		RETURNING
+			*
+	`
+
+	return r.scheduleRow(ctx, "resume report schedule", stmt, pgx.NamedArgs{
+		"id":          scheduleID,
+		"user_id":     userID,
+		"next_run_at": nextRunAt,
+	})
+}
+
+func (r *ReportRepository) DeleteSchedule(ctx context.Context, userID string, scheduleID uuid.UUID) (*report.Schedule, error) {
+	stmt := `
+		DELETE FROM report_schedules
+		WHERE
+			id=@id
+			AND user_id=@user_id
+		RETURNING
+			*
+	`
+
+	return r.scheduleRow(ctx, "delete report schedule", stmt, pgx.NamedArgs{
+		"id":      scheduleID,
+		"user_id": userID,
+	})
+}
+
+// GetDueSchedules returns the schedules not paused whose next run is at or before now, the
+// most overdue first
+func (r *ReportRepository) GetDueSchedules(ctx context.Context, now time.Time, limit int) ([]report.Schedule, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			report_schedules
+		WHERE
+			paused_at IS NULL
+			AND next_run_at<=@now
+		ORDER BY
+			next_run_at ASC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"now":   now,
+		"limit": limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get due report schedules query: %w", err)
+	}
+
+	schedules, err := pgx.CollectRows(rows, pgx.RowToStructByName[report.Schedule])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:report_schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// SetNextRun moves the next run of a schedule once its due run was queued
+func (r *ReportRepository) SetNextRun(ctx context.Context, scheduleID uuid.UUID, nextRunAt time.Time) error {
+	stmt := `
+		UPDATE report_schedules
+		SET
+			next_run_at=@next_run_at
+		WHERE
+			id=@id
+	`
+
+	_, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"id":          scheduleID,
+		"next_run_at": nextRunAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute set report schedule next run query for schedule_id=%s: %w", scheduleID.String(), err)
+	}
+
+	return nil
+}
+
+// RecordRun keeps when the schedule last ran and why it failed, a nil failure clears the last one
+func (r *ReportRepository) RecordRun(ctx context.Context, scheduleID uuid.UUID, runAt time.Time, failure *string) error {
+	stmt := `
+		UPDATE report_schedules
+		SET
+			last_run_at=@last_run_at,
+			last_error=@last_error
+		WHERE
+			id=@id
+	`
+
+	_, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"id":          scheduleID,
+		"last_run_at": runAt,
+		"last_error":  failure,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute record report run query for schedule_id=%s: %w", scheduleID.String(), err)
+	}
+
+	return nil
+}
+
+func (r *ReportRepository) scheduleRow(ctx context.Context, operation, stmt string,
+	args pgx.NamedArgs,
+) (*report.Schedule, error) {
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s query for user_id=%s: %w", operation, args["user_id"], err)
+	}
+
+	schedule, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[report.Schedule])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeReportScheduleNotFound
+			return nil, errs.NewNotFoundError("report schedule not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:report_schedules for user_id=%s: %w", args["user_id"], err)
+	}
+
+	return &schedule, nil
+}
+
+func scheduleArgs(definition *report.Definition) pgx.NamedArgs {
+	return pgx.NamedArgs{
+		"name":         definition.Name,
+		"format":       definition.Format,
+		"filters":      definition.Filters,
+		"recipients":   definition.Recipients,
+		"frequency":    definition.Frequency,
+		"hour":         definition.Hour,
+		"weekday":      definition.Weekday,
+		"day_of_month": definition.DayOfMonth,
+		"timezone":     definition.Timezone,
+	}
+}
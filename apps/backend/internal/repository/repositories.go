@@ -1,17 +1,93 @@
 package repository
 
-import "github.com/Sameer16536/ExecuTask/internal/server"
+import (
+	"github.com/Sameer16536/ExecuTask/internal/database"
+	"github.com/Sameer16536/ExecuTask/internal/lib/envelope"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+)
 
+// Repositories are the stores backed by Postgres, see the memory package for the fakes
 type Repositories struct {
-	Todo     *TodoRepository
-	Comment  *CommentRepository
-	Category *CategoryRepository
+	Tx           TxManager
+	Todo         TodoStore
+	Comment      CommentStore
+	Category     CategoryStore
+	Change       ChangeStore
+	Admin        AdminStore
+	Stats        StatsStore
+	Maintenance  MaintenanceStore
+	Search       SearchStore
+	Audit        AuditStore
+	Feature      FeatureStore
+	Usage        UsageStore
+	Export       ExportStore
+	Retention    RetentionStore
+	Encryption   EncryptionStore
+	Backup       BackupStore
+	Rule         RuleStore
+	Matrix       MatrixStore
+	Focus        FocusStore
+	MyDay        MyDayStore
+	Gamification GamificationStore
+	Dependency   DependencyStore
+	Inbox        InboxStore
+	Workspace    WorkspaceStore
+	LinkPreview  LinkPreviewStore
+	Cover        CoverStore
+	Note         NoteStore
+	MagicTag     MagicTagStore
+	Locale       LocaleStore
+	StatusPage   StatusPageStore
+	APIKey       APIKeyStore
+	APIClient    APIClientStore
+	Report       ReportStore
+	Webhook      WebhookStore
+	// Keyring seals the sensitive fields the stores write and opens those they read
+	Keyring *envelope.Keyring
 }
 
-func NewRepositories(s *server.Server) *Repositories {
-	return &Repositories{
-		Todo:     NewTodoRepository(s),
-		Comment:  NewCommentRepository(s),
-		Category: NewCategoryRepository(s),
+func NewRepositories(s *server.Server) (*Repositories, error) {
+	encryption := NewEncryptionRepository(s)
+	keyring, err := NewKeyring(s, encryption)
+	if err != nil {
+		return nil, err
 	}
+
+	return &Repositories{
+		Tx:           database.NewTxManager(s.DB),
+		Todo:         NewTodoRepository(s, keyring),
+		Comment:      NewCommentRepository(s),
+		Category:     NewCategoryRepository(s),
+		Change:       NewChangeRepository(s),
+		Admin:        NewAdminRepository(s),
+		Stats:        NewStatsRepository(s),
+		Maintenance:  NewMaintenanceRepository(s),
+		Search:       NewSearchRepository(s),
+		Audit:        NewAuditRepository(s),
+		Feature:      NewFeatureRepository(s),
+		Usage:        NewUsageRepository(s),
+		Export:       NewExportRepository(s, keyring),
+		Retention:    NewRetentionRepository(s),
+		Encryption:   encryption,
+		Backup:       NewBackupRepository(s),
+		Rule:         NewRuleRepository(s),
+		Matrix:       NewMatrixRepository(s),
+		Focus:        NewFocusRepository(s),
+		MyDay:        NewMyDayRepository(s),
+		Gamification: NewGamificationRepository(s),
+		Dependency:   NewDependencyRepository(s),
+		Inbox:        NewInboxRepository(s),
+		Workspace:    NewWorkspaceRepository(s),
+		LinkPreview:  NewLinkPreviewRepository(s),
+		Cover:        NewCoverRepository(s),
+		Note:         NewNoteRepository(s, keyring),
+		MagicTag:     NewMagicTagRepository(s),
+		Locale:       NewLocaleRepository(s),
+		StatusPage:   NewStatusPageRepository(s),
+		APIKey:       NewAPIKeyRepository(s),
+		APIClient:    NewAPIClientRepository(s),
+		Report:       NewReportRepository(s),
+		Webhook:      NewWebhookRepository(s, keyring),
+		Keyring:      keyring,
+	}, nil
 }
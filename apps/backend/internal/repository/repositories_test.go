@@ -0,0 +1,34 @@
+package repository_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/repository/storetest"
+	testutil "github.com/Sameer16536/ExecuTask/internal/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// postgresTestsEnv enables the tests that start a Postgres container, they need Docker
+const postgresTestsEnv = "EXECUTASK_TEST_POSTGRES"
+
+// TestStoreContract runs the contract suite of the memory fakes against the Postgres
+// repositories, on one migrated database
+func TestStoreContract(t *testing.T) {
+	if os.Getenv(postgresTestsEnv) == "" {
+		t.Skipf("set %s=1 to run the Postgres tests", postgresTestsEnv)
+	}
+
+	_, s, cleanup := testutil.SetupTest(t)
+	t.Cleanup(cleanup)
+	s.Config.Encryption = &config.EncryptionConfig{MasterKey: "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="}
+
+	repos, err := repository.NewRepositories(s)
+	require.NoError(t, err)
+
+	storetest.Run(t, func(t *testing.T) *repository.Repositories {
+		return repos
+	})
+}
@@ -0,0 +1,431 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/retention"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// retentionScope selects the todos t a policy covers: those created in @workspace_id, or with
+// an empty @workspace_id the personal ones and those of workspaces without a policy
+const retentionScope = `
+	(
+		(
+			@workspace_id::TEXT <> ''
+			AND t.workspace_id = @workspace_id
+		)
+		OR (
+			@workspace_id::TEXT = ''
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					retention_policies p
+				WHERE
+					p.workspace_id = t.workspace_id
+			)
+		)
+	)
+`
+
+type RetentionRepository struct {
+	server *server.Server
+}
+
+func NewRetentionRepository(server *server.Server) *RetentionRepository {
+	return &RetentionRepository{server: server}
+}
+
+func (r *RetentionRepository) GetPolicies(ctx context.Context) ([]retention.Policy, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			retention_policies
+		ORDER BY
+			workspace_id ASC
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get retention policies query: %w", err)
+	}
+
+	policies, err := pgx.CollectRows(rows, pgx.RowToStructByName[retention.Policy])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:retention_policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+func (r *RetentionRepository) GetPolicy(ctx context.Context, workspaceID string) (*retention.Policy, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			retention_policies
+		WHERE
+			workspace_id = @workspace_id
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get retention policy query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	policy, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[retention.Policy])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeRetentionPolicyNotFound
+			return nil, errs.NewNotFoundError("retention policy not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:retention_policies for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	return &policy, nil
+}
+
+func (r *RetentionRepository) SetPolicy(ctx context.Context, updatedBy string,
+	payload *retention.SetPolicyPayload,
+) (*retention.Policy, error) {
+	stmt := `
+		INSERT INTO
+			retention_policies (
+				workspace_id,
+				archived_todo_months,
+				max_comments_per_todo,
+				attachment_days,
+				enforce,
+				updated_by
+			)
+		VALUES
+			(
+				@workspace_id,
+				@archived_todo_months,
+				@max_comments_per_todo,
+				@attachment_days,
+				@enforce,
+				@updated_by
+			)
+		ON CONFLICT (workspace_id) DO UPDATE
+		SET
+			archived_todo_months = EXCLUDED.archived_todo_months,
+			max_comments_per_todo = EXCLUDED.max_comments_per_todo,
+			attachment_days = EXCLUDED.attachment_days,
+			enforce = EXCLUDED.enforce,
+			updated_by = EXCLUDED.updated_by
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id":          payload.WorkspaceID,
+		"archived_todo_months":  payload.ArchivedTodoMonths,
+		"max_comments_per_todo": payload.MaxCommentsPerTodo,
+		"attachment_days":       payload.AttachmentDays,
+		"enforce":               *payload.Enforce,
+		"updated_by":            updatedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute set retention policy query for workspace_id=%s: %w", payload.WorkspaceID, err)
+	}
+
+	policy, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[retention.Policy])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:retention_policies for workspace_id=%s: %w",
+			payload.WorkspaceID, err)
+	}
+
+	return &policy, nil
+}
+
+// DeletePolicy removes a policy and returns it as it was, the workspace follows the config again
+func (r *RetentionRepository) DeletePolicy(ctx context.Context, workspaceID string) (*retention.Policy, error) {
+	stmt := `
+		DELETE FROM retention_policies
+		WHERE
+			workspace_id = @workspace_id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute delete retention policy query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	policy, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[retention.Policy])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeRetentionPolicyNotFound
+			return nil, errs.NewNotFoundError("retention policy not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:retention_policies for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	return &policy, nil
+}
+
+// PreviewRetention counts what the rules select in the scope of a policy without deleting
+// anything. The counts match what an enforced run would delete, batch limits aside.
+func (r *RetentionRepository) PreviewRetention(ctx context.Context, workspaceID string, rules retention.Rules,
+	now time.Time,
+) (*retention.Report, error) {
+	stmt := `
+		SELECT
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					todos t
+				WHERE
+					@archived_before::TIMESTAMPTZ IS NOT NULL
+					AND t.status_category = 'archived'
+					AND t.updated_at < @archived_before::TIMESTAMPTZ
+					AND NOT EXISTS (
+						SELECT
+							1
+						FROM
+							todos c
+						WHERE
+							c.parent_todo_id = t.id
+							AND c.user_id = t.user_id
+					)
+					AND ` + retentionScope + `
+			) AS archived_todos,
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					(
+						SELECT
+							ROW_NUMBER() OVER (
+								PARTITION BY
+									c.todo_id
+								ORDER BY
+									c.created_at DESC,
+									c.id DESC
+							) AS position
+						FROM
+							todo_comments c
+							JOIN todos t ON t.id = c.todo_id
+						WHERE
+							@max_comments::INT IS NOT NULL
+							AND ` + retentionScope + `
+					) ranked
+				WHERE
+					position > @max_comments::INT
+			) AS comments,
+			COUNT(a.id) AS attachments,
+			COALESCE(SUM(a.file_size), 0)::BIGINT AS attachment_bytes
+		FROM
+			todo_attachments a
+			JOIN todos t ON t.id = a.todo_id
+		WHERE
+			@uploaded_before::TIMESTAMPTZ IS NOT NULL
+			AND a.created_at < @uploaded_before::TIMESTAMPTZ
+			AND ` + retentionScope + `
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id":    workspaceID,
+		"archived_before": rules.ArchivedBefore(now),
+		"max_comments":    rules.MaxCommentsPerTodo,
+		"uploaded_before": rules.UploadedBefore(now),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute preview retention query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	report, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[retention.Report])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todos for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	report.WorkspaceID = workspaceID
+	report.Rules = rules
+	return &report, nil
+}
+
+// PurgeArchivedTodos deletes up to limit archived todos last touched before the cutoff, with
+// their comments and attachments. A parent is purged once its subtasks are gone. It returns
+// the storage keys of the attachments deleted along.
+func (r *RetentionRepository) PurgeArchivedTodos(ctx context.Context, workspaceID string, archivedBefore time.Time,
+	limit int,
+) (int64, []string, error) {
+	stmt := `
+		WITH
+			purged AS (
+				DELETE FROM todos
+				WHERE
+					(id, user_id) IN (
+						SELECT
+							t.id,
+							t.user_id
+						FROM
+							todos t
+						WHERE
+							t.status_category = 'archived'
+							AND t.updated_at < @archived_before
+							AND NOT EXISTS (
+								SELECT
+									1
+								FROM
+									todos c
+								WHERE
+									c.parent_todo_id = t.id
+									AND c.user_id = t.user_id
+							)
+							AND ` + retentionScope + `
+						ORDER BY
+							t.updated_at ASC
+						LIMIT
+							@limit
+					)
+				RETURNING
+					id
+			)
+		SELECT
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					purged
+			) AS purged_count,
+			COALESCE(
+				(
+					-- The statement snapshot still has the attachments the delete takes along
+					SELECT
+						ARRAY_AGG(a.download_key)
+					FROM
+						todo_attachments a
+						JOIN purged p ON p.id = a.todo_id
+				),
+				'{}'
+			) AS download_keys
+	`
+
+	var purgedCount int64
+	var downloadKeys []string
+	err := r.server.DB.Writer(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{
+		"workspace_id":    workspaceID,
+		"archived_before": archivedBefore,
+		"limit":           limit,
+	}).Scan(&purgedCount, &downloadKeys)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to execute purge archived todos query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	return purgedCount, downloadKeys, nil
+}
+
+// TrimComments deletes up to limit comments beyond the newest maxPerTodo of their todo
+func (r *RetentionRepository) TrimComments(ctx context.Context, workspaceID string, maxPerTodo, limit int) (int64, error) {
+	stmt := `
+		DELETE FROM todo_comments
+		WHERE
+			id IN (
+				SELECT
+					id
+				FROM
+					(
+						SELECT
+							c.id,
+							ROW_NUMBER() OVER (
+								PARTITION BY
+									c.todo_id
+								ORDER BY
+									c.created_at DESC,
+									c.id DESC
+							) AS position
+						FROM
+							todo_comments c
+							JOIN todos t ON t.id = c.todo_id
+						WHERE
+							` + retentionScope + `
+					) ranked
+				WHERE
+					position > @max_per_todo
+				LIMIT
+					@limit
+			)
+	`
+
+	result, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+		"max_per_todo": maxPerTodo,
+		"limit":        limit,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute trim comments query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// GetExpiredAttachments returns up to limit attachments uploaded before the cutoff, oldest first
+func (r *RetentionRepository) GetExpiredAttachments(ctx context.Context, workspaceID string, uploadedBefore time.Time,
+	limit int,
+) ([]todo.TodoAttachment, error) {
+	stmt := `
+		SELECT
+			a.*
+		FROM
+			todo_attachments a
+			JOIN todos t ON t.id = a.todo_id
+		WHERE
+			a.created_at < @uploaded_before
+			AND ` + retentionScope + `
+		ORDER BY
+			a.created_at ASC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id":    workspaceID,
+		"uploaded_before": uploadedBefore,
+		"limit":           limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get expired attachments query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	attachments, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.TodoAttachment])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_attachments for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	return attachments, nil
+}
+
+// DeleteAttachments deletes the records of attachments whose objects are gone from storage
+func (r *RetentionRepository) DeleteAttachments(ctx context.Context, attachmentIDs []uuid.UUID) (int64, error) {
+	stmt := `
+		DELETE FROM todo_attachments
+		WHERE
+			id = ANY (@attachment_ids)
+	`
+
+	result, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"attachment_ids": attachmentIDs,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute delete attachments query: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/rule"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type RuleRepository struct {
+	server *server.Server
+}
+
+func NewRuleRepository(server *server.Server) *RuleRepository {
+	return &RuleRepository{server: server}
+}
+
+func (r *RuleRepository) CreateRule(ctx context.Context, userID string, payload *rule.CreateRulePayload) (*rule.Rule, error) {
+	stmt := `
+		INSERT INTO
+			automation_rules (user_id, name, trigger, conditions, actions, enabled)
+		VALUES
+			(@user_id, @name, @trigger, @conditions, @actions, @enabled)
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":    userID,
+		"name":       payload.Name,
+		"trigger":    payload.Trigger,
+		"conditions": payload.Conditions,
+		"actions":    payload.Actions,
+		"enabled":    *payload.Enabled,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create rule query for user_id=%s: %w", userID, err)
+	}
+
+	ruleItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[rule.Rule])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:automation_rules for user_id=%s: %w", userID, err)
+	}
+
+	return &ruleItem, nil
+}
+
+// GetRules lists the rules of a user, oldest first
+func (r *RuleRepository) GetRules(ctx context.Context, userID string) ([]rule.Rule, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			automation_rules
+		WHERE
+			user_id=@user_id
+		ORDER BY
+			created_at ASC,
+			id ASC
+	`
+
+	return r.ruleRows(ctx, "get rules", stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+}
+
+// GetEnabledRules lists the enabled rules of a user listening to the trigger, oldest first
+func (r *RuleRepository) GetEnabledRules(ctx context.Context, userID string, trigger rule.Trigger) ([]rule.Rule, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			automation_rules
+		WHERE
+			user_id=@user_id
+			AND trigger=@trigger
+			AND enabled
+		ORDER BY
+			created_at ASC,
+			id ASC
+	`
+
+	return r.ruleRows(ctx, "get enabled rules", stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"trigger": trigger,
+	})
+}
+
+func (r *RuleRepository) ruleRows(ctx context.Context, operation, stmt string, args pgx.NamedArgs) ([]rule.Rule, error) {
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s query: %w", operation, err)
+	}
+
+	rules, err := pgx.CollectRows(rows, pgx.RowToStructByName[rule.Rule])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:automation_rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// CountRules counts the rules of a user, disabled ones included
+func (r *RuleRepository) CountRules(ctx context.Context, userID string) (int, error) {
+	stmt := `
+		SELECT
+			COUNT(*)
+		FROM
+			automation_rules
+		WHERE
+			user_id=@user_id
+	`
+
+	var count int
+	err := r.server.DB.Writer(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	}).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute count rules query for user_id=%s: %w", userID, err)
+	}
+
+	return count, nil
+}
+
+// GetUserIDsWithEnabledRules lists the users with an enabled rule listening to the trigger
+func (r *RuleRepository) GetUserIDsWithEnabledRules(ctx context.Context, trigger rule.Trigger) ([]string, error) {
+	stmt := `
+		SELECT DISTINCT
+			user_id
+		FROM
+			automation_rules
+		WHERE
+			trigger=@trigger
+			AND enabled
+		ORDER BY
+			user_id
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"trigger": trigger,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get rule users query for trigger=%s: %w", trigger, err)
+	}
+
+	userIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:automation_rules for trigger=%s: %w", trigger, err)
+	}
+
+	return userIDs, nil
+}
+
+func (r *RuleRepository) GetRule(ctx context.Context, userID string, ruleID uuid.UUID) (*rule.Rule, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			automation_rules
+		WHERE
+			id=@id
+			AND user_id=@user_id
+	`
+
+	return r.ruleRow(ctx, "get rule", stmt, pgx.NamedArgs{
+		"id":      ruleID,
+		"user_id": userID,
+	})
+}
+
+func (r *RuleRepository) UpdateRule(ctx context.Context, userID string, payload *rule.UpdateRulePayload) (*rule.Rule, error) {
+	args := pgx.NamedArgs{
+		"id":      payload.ID,
+		"user_id": userID,
+	}
+	setClauses := []string{}
+
+	if payload.Name != nil {
+		setClauses = append(setClauses, "name = @name")
+		args["name"] = *payload.Name
+	}
+	if payload.Trigger != nil {
+		setClauses = append(setClauses, "trigger = @trigger")
+		args["trigger"] = *payload.Trigger
+	}
+	if payload.Conditions != nil {
+		setClauses = append(setClauses, "conditions = @conditions")
+		args["conditions"] = payload.Conditions
+	}
+	if payload.Actions != nil {
+		setClauses = append(setClauses, "actions = @actions")
+		args["actions"] = payload.Actions
+	}
+	if payload.Enabled != nil {
+		setClauses = append(setClauses, "enabled = @enabled")
+		args["enabled"] = *payload.Enabled
+	}
+
+	if len(setClauses) == 0 {
+		return nil, errs.NewBadRequestError("no fields to update", false, nil, nil, nil)
+	}
+
+	stmt := `UPDATE automation_rules SET ` + strings.Join(setClauses, ", ") +
+		` WHERE id = @id AND user_id = @user_id RETURNING *`
+
+	return r.ruleRow(ctx, "update rule", stmt, args)
+}
+
+// DeleteRule removes a rule with its execution log and returns it as it was
+func (r *RuleRepository) DeleteRule(ctx context.Context, userID string, ruleID uuid.UUID) (*rule.Rule, error) {
+	stmt := `
+		DELETE FROM automation_rules
+		WHERE
+			id=@id
+			AND user_id=@user_id
+		RETURNING
+			*
+	`
+
+	return r.ruleRow(ctx, "delete rule", stmt, pgx.NamedArgs{
+		"id":      ruleID,
+		"user_id": userID,
+	})
+}
+
+func (r *RuleRepository) ruleRow(ctx context.Context, operation, stmt string, args pgx.NamedArgs) (*rule.Rule, error) {
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s query for rule_id=%s: %w", operation, args["id"], err)
+	}
+
+	ruleItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[rule.Rule])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeRuleNotFound
+			return nil, errs.NewNotFoundError("rule not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:automation_rules for rule_id=%s: %w", args["id"], err)
+	}
+
+	return &ruleItem, nil
+}
+
+func (r *RuleRepository) RecordExecution(ctx context.Context, execution *rule.Execution) error {
+	stmt := `
+		INSERT INTO
+			rule_executions (rule_id, user_id, todo_id, trigger, status, depth, actions_applied, error)
+		VALUES
+			(@rule_id, @user_id, @todo_id, @trigger, @status, @depth, @actions_applied, @error)
+		RETURNING
+			id,
+			created_at
+	`
+
+	err := r.server.DB.Writer(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{
+		"rule_id":         execution.RuleID,
+		"user_id":         execution.UserID,
+		"todo_id":         execution.TodoID,
+		"trigger":         execution.Trigger,
+		"status":          execution.Status,
+		"depth":           execution.Depth,
+		"actions_applied": execution.ActionsApplied,
+		"error":           execution.Error,
+	}).Scan(&execution.ID, &execution.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to execute record rule execution query for rule_id=%s: %w", execution.RuleID.String(), err)
+	}
+
+	return nil
+}
+
+// GetExecutions lists the latest runs of a rule of the user, newest first
+func (r *RuleRepository) GetExecutions(ctx context.Context, userID string, ruleID uuid.UUID,
+	limit int,
+) ([]rule.Execution, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			rule_executions
+		WHERE
+			rule_id=@rule_id
+			AND user_id=@user_id
+		ORDER BY
+			created_at DESC,
+			id DESC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"rule_id": ruleID,
+		"user_id": userID,
+		"limit":   limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get rule executions query for rule_id=%s: %w", ruleID.String(), err)
+	}
+
+	executions, err := pgx.CollectRows(rows, pgx.RowToStructByName[rule.Execution])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:rule_executions for rule_id=%s: %w", ruleID.String(), err)
+	}
+
+	return executions, nil
+}
+
+// HasExecution tells whether the rule already ran against the todo off the trigger, skipped
+// runs don't count
+func (r *RuleRepository) HasExecution(ctx context.Context, ruleID, todoID uuid.UUID, trigger rule.Trigger) (bool, error) {
+	stmt := `
+		SELECT
+			EXISTS (
+				SELECT
+					1
+				FROM
+					rule_executions
+				WHERE
+					rule_id=@rule_id
+					AND todo_id=@todo_id
+					AND trigger=@trigger
+					AND status <> 'skipped'
+			)
+	`
+
+	var exists bool
+	err := r.server.DB.Writer(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{
+		"rule_id": ruleID,
+		"todo_id": todoID,
+		"trigger": trigger,
+	}).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute has rule execution query for rule_id=%s todo_id=%s: %w",
+			ruleID.String(), todoID.String(), err)
+	}
+
+	return exists, nil
+}
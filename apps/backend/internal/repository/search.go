@@ -0,0 +1,356 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/lib/embedding"
+	"github.com/Sameer16536/ExecuTask/internal/model/search"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+// todoSearchVector must match the expression of the idx_todos_search index
+const todoSearchVector = `to_tsvector('english', t.title || ' ' || coalesce(t.description, ''))`
+
+// todoSearchCondition matches todos by full text, by substring or, to tolerate typos,
+// by trigram similarity of the title. It binds @search, @search_pattern and @similarity_threshold.
+func todoSearchCondition(s *server.Server, args pgx.NamedArgs, search string) string {
+	bindSearch(s, args, search)
+	return `(` + todoSearchVector + ` @@ websearch_to_tsquery('english', @search)
+		OR t.title ILIKE @search_pattern
+		OR t.description ILIKE @search_pattern
+		OR word_similarity(@search, t.title) >= @similarity_threshold)`
+}
+
+// todoSearchRank combines the full text score with the title similarity, it needs the
+// arguments bound by todoSearchCondition
+const todoSearchRank = `(ts_rank(` + todoSearchVector + `, websearch_to_tsquery('english', @search))
+		+ word_similarity(@search, t.title))`
+
+// nameSearchCondition matches a name column by substring or trigram similarity
+func nameSearchCondition(s *server.Server, args pgx.NamedArgs, column, search string) string {
+	bindSearch(s, args, search)
+	return `(` + column + ` ILIKE @search_pattern OR word_similarity(@search, ` + column + `) >= @similarity_threshold)`
+}
+
+func bindSearch(s *server.Server, args pgx.NamedArgs, search string) {
+	threshold := config.DefaultSearchConfig().SimilarityThreshold
+	if s.Config.Search != nil {
+		threshold = s.Config.Search.SimilarityThreshold
+	}
+
+	args["search"] = search
+	args["search_pattern"] = "%" + search + "%"
+	args["similarity_threshold"] = threshold
+}
+
+// todoEmbeddingText is what gets embedded for a todo, its hash tells when an embedding is stale
+const todoEmbeddingText = `t.title || E'\n\n' || coalesce(t.description, '')`
+
+type SearchRepository struct {
+	server *server.Server
+}
+
+func NewSearchRepository(server *server.Server) *SearchRepository {
+	return &SearchRepository{server: server}
+}
+
+// SearchTodos returns the user's todos matching the search by keyword, best match first
+func (r *SearchRepository) SearchTodos(ctx context.Context, userID, query string, limit int) ([]search.Result, error) {
+	args := pgx.NamedArgs{
+		"user_id": userID,
+		"limit":   limit,
+	}
+
+	stmt := `
+		SELECT
+			t.*,
+			` + todoSearchRank + ` AS score
+		FROM
+			todos t
+		WHERE
+			t.user_id=@user_id
+			AND ` + todoSearchCondition(r.server, args, query) + `
+		ORDER BY
+			score DESC,
+			t.created_at DESC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute keyword search query for user_id=%s: %w", userID, err)
+	}
+
+	results, err := pgx.CollectRows(rows, pgx.RowToStructByName[search.Result])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	return results, nil
+}
+
+// SearchTodosByEmbedding returns the user's todos closest in meaning to the vector, most similar first.
+// Only embeddings of the given model are compared.
+func (r *SearchRepository) SearchTodosByEmbedding(
+	ctx context.Context,
+	userID, model string,
+	vector []float32,
+	minSimilarity float64,
+	limit int,
+) ([]search.Result, error) {
+	stmt := `
+		SELECT
+			t.*,
+			1 - (e.embedding <=> @embedding::text::vector) AS score
+		FROM
+			todo_embeddings e
+			JOIN todos t ON t.id=e.todo_id
+			AND t.user_id=e.user_id
+		WHERE
+			e.user_id=@user_id
+			AND e.model=@model
+			AND 1 - (e.embedding <=> @embedding::text::vector)>=@min_similarity
+		ORDER BY
+			e.embedding <=> @embedding::text::vector
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":        userID,
+		"model":          model,
+		"embedding":      embedding.Format(vector),
+		"min_similarity": minSimilarity,
+		"limit":          limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute semantic search query for user_id=%s: %w", userID, err)
+	}
+
+	results, err := pgx.CollectRows(rows, pgx.RowToStructByName[search.Result])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_embeddings for user_id=%s: %w", userID, err)
+	}
+
+	return results, nil
+}
+
+// SuggestCandidates returns the user's todos, categories and tags matching the query, at most
+// limit of each type, the latest used first. Every part is served by an index on the user and
+// the lower cased text, or by a trigram index for matches past the start.
+func (r *SearchRepository) SuggestCandidates(ctx context.Context, userID, query string, limit int) ([]search.Candidate, error) {
+	stmt := `
+		(
+			SELECT
+				'todo' AS type,
+				t.id,
+				t.title AS label,
+				lower(t.title) LIKE @prefix_pattern AS prefix,
+				coalesce(v.view_count, 0) + 1 AS uses,
+				greatest(t.updated_at, v.viewed_at) AS last_used_at
+			FROM
+				todos t
+				LEFT JOIN todo_views v ON v.todo_id=t.id
+				AND v.user_id=t.user_id
+			WHERE
+				t.user_id=@user_id
+				AND t.status_category<>'archived'
+				AND (
+					lower(t.title) LIKE @prefix_pattern
+					OR t.title ILIKE @search_pattern
+				)
+			ORDER BY
+				last_used_at DESC
+			LIMIT
+				@limit
+		)
+		UNION ALL
+		(
+			SELECT
+				'category' AS type,
+				c.id,
+				c.name AS label,
+				lower(c.name) LIKE @prefix_pattern AS prefix,
+				count(t.id) AS uses,
+				greatest(c.updated_at, max(t.updated_at)) AS last_used_at
+			FROM
+				todo_categories c
+				LEFT JOIN todos t ON t.category_id=c.id
+				AND t.user_id=c.user_id
+			WHERE
+				c.user_id=@user_id
+				AND (
+					lower(c.name) LIKE @prefix_pattern
+					OR c.name ILIKE @search_pattern
+				)
+			GROUP BY
+				c.id
+			ORDER BY
+				last_used_at DESC
+			LIMIT
+				@limit
+		)
+		UNION ALL
+		(
+			SELECT
+				'tag' AS type,
+				NULL::UUID AS id,
+				min(tag.value) AS label,
+				bool_or(lower(tag.value) LIKE @prefix_pattern) AS prefix,
+				count(*) AS uses,
+				max(t.updated_at) AS last_used_at
+			FROM
+				todos t
+				CROSS JOIN LATERAL jsonb_array_elements_text(t.metadata->'tags') AS tag (value)
+			WHERE
+				t.user_id=@user_id
+				AND jsonb_typeof(t.metadata->'tags')='array'
+				AND tag.value ILIKE @search_pattern
+			GROUP BY
+				lower(tag.value)
+			ORDER BY
+				last_used_at DESC
+			LIMIT
+				@limit
+		)
+	`
+
+	pattern := escapeLike(strings.ToLower(query))
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":        userID,
+		"prefix_pattern": pattern + "%",
+		"search_pattern": "%" + pattern + "%",
+		"limit":          limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute suggest query for user_id=%s: %w", userID, err)
+	}
+
+	candidates, err := pgx.CollectRows(rows, pgx.RowToStructByName[search.Candidate])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	return candidates, nil
+}
+
+// escapeLike makes the wildcards of LIKE match themselves
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// CRON REQUIREMENTS
+
+// GetPendingEmbeddings returns todos never embedded, edited since or embedded by another model
+func (r *SearchRepository) GetPendingEmbeddings(ctx context.Context, model string, limit int) ([]search.PendingEmbedding, error) {
+	stmt := `
+		SELECT
+			t.id AS todo_id,
+			t.user_id,
+			` + todoEmbeddingText + ` AS text,
+			md5(` + todoEmbeddingText + `) AS content_hash
+		FROM
+			todos t
+			LEFT JOIN todo_embeddings e ON e.todo_id=t.id
+		WHERE
+			e.todo_id IS NULL
+			OR e.model<>@model
+			OR e.content_hash<>md5(` + todoEmbeddingText + `)
+		ORDER BY
+			t.updated_at DESC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"model": model,
+		"limit": limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get pending embeddings query: %w", err)
+	}
+
+	pending, err := pgx.CollectRows(rows, pgx.RowToStructByName[search.PendingEmbedding])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos: %w", err)
+	}
+
+	return pending, nil
+}
+
+// UpsertEmbeddings stores the vectors computed for pending todos, vectors[i] belongs to pending[i]
+func (r *SearchRepository) UpsertEmbeddings(ctx context.Context, model string, pending []search.PendingEmbedding, vectors [][]float32) error {
+	todoIDs := make([]string, len(pending))
+	userIDs := make([]string, len(pending))
+	contentHashes := make([]string, len(pending))
+	embeddings := make([]string, len(pending))
+	for i, item := range pending {
+		todoIDs[i] = item.TodoID.String()
+		userIDs[i] = item.UserID
+		contentHashes[i] = item.ContentHash
+		embeddings[i] = embedding.Format(vectors[i])
+	}
+
+	stmt := `
+		INSERT INTO
+			todo_embeddings (todo_id, user_id, model, content_hash, embedding)
+		SELECT
+			todo_id::uuid,
+			user_id,
+			@model,
+			content_hash,
+			embedding::vector
+		FROM
+			UNNEST(@todo_ids::text[], @user_ids::text[], @content_hashes::text[], @embeddings::text[])
+			AS e (todo_id, user_id, content_hash, embedding)
+		ON CONFLICT (todo_id) DO UPDATE
+		SET
+			user_id=EXCLUDED.user_id,
+			model=EXCLUDED.model,
+			content_hash=EXCLUDED.content_hash,
+			embedding=EXCLUDED.embedding,
+			updated_at=NOW()
+	`
+
+	_, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"model":          model,
+		"todo_ids":       todoIDs,
+		"user_ids":       userIDs,
+		"content_hashes": contentHashes,
+		"embeddings":     embeddings,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert %d todo embeddings: %w", len(pending), err)
+	}
+
+	return nil
+}
+
+// DeleteOrphanedEmbeddings removes the embeddings of deleted todos
+func (r *SearchRepository) DeleteOrphanedEmbeddings(ctx context.Context) (int64, error) {
+	stmt := `
+		DELETE FROM todo_embeddings e
+		WHERE
+			NOT EXISTS (
+				SELECT
+					1
+				FROM
+					todos t
+				WHERE
+					t.id=e.todo_id
+					AND t.user_id=e.user_id
+			)
+	`
+
+	result, err := r.server.DB.Writer(ctx).Exec(ctx, stmt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete orphaned todo embeddings: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
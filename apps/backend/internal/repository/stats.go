@@ -0,0 +1,402 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/stats"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// statsViews are the materialized dashboard aggregates, see migration 009
+var statsViews = []string{"user_stats_summary", "user_daily_completions", "user_category_stats"}
+
+type StatsRepository struct {
+	server *server.Server
+}
+
+func NewStatsRepository(server *server.Server) *StatsRepository {
+	return &StatsRepository{server: server}
+}
+
+// GetSummary returns the user's totals, nil when the user had no todos at the last refresh
+func (r *StatsRepository) GetSummary(ctx context.Context, userID string) (*stats.Summary, error) {
+	stmt := `
+		SELECT
+			total,
+			completed,
+			open,
+			avg_completion_seconds,
+			refreshed_at
+		FROM
+			user_stats_summary
+		WHERE
+			user_id=@user_id
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get stats summary query for user_id=%s: %w", userID, err)
+	}
+
+	summary, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[stats.Summary])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to collect row from view:user_stats_summary for user_id=%s: %w", userID, err)
+	}
+
+	return &summary, nil
+}
+
+// GetDailyCompletions returns the days since the given date with at least one completion, oldest first
+func (r *StatsRepository) GetDailyCompletions(ctx context.Context, userID string, since time.Time) ([]stats.DailyCompletions, error) {
+	stmt := `
+		SELECT
+			TO_CHAR(day, 'YYYY-MM-DD') AS day,
+			completed
+		FROM
+			user_daily_completions
+		WHERE
+			user_id=@user_id
+			AND day>=@since
+		ORDER BY
+			day ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"since":   since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get daily completions query for user_id=%s: %w", userID, err)
+	}
+
+	days, err := pgx.CollectRows(rows, pgx.RowToStructByName[stats.DailyCompletions])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from view:user_daily_completions for user_id=%s: %w", userID, err)
+	}
+
+	return days, nil
+}
+
+func (r *StatsRepository) GetCategoryStats(ctx context.Context, userID string) ([]stats.CategoryStats, error) {
+	stmt := `
+		SELECT
+			s.category_id,
+			c.name,
+			c.color,
+			s.total,
+			s.completed,
+			s.avg_completion_seconds
+		FROM
+			user_category_stats s
+			JOIN todo_categories c ON c.id=s.category_id
+		WHERE
+			s.user_id=@user_id
+		ORDER BY
+			s.total DESC,
+			c.name ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get category stats query for user_id=%s: %w", userID, err)
+	}
+
+	categories, err := pgx.CollectRows(rows, pgx.RowToStructByName[stats.CategoryStats])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from view:user_category_stats for user_id=%s: %w", userID, err)
+	}
+
+	return categories, nil
+}
+
+// PRODUCTIVITY REQUIREMENTS
+// Productivity windows are picked per request, so these read the todos table rather than the views
+
+// GetCompletionDays returns the UTC days since the given date with at least one completion, oldest first
+func (r *StatsRepository) GetCompletionDays(ctx context.Context, userID string, since time.Time) ([]string, error) {
+	stmt := `
+		SELECT DISTINCT
+			TO_CHAR(completed_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day
+		FROM
+			todos
+		WHERE
+			user_id=@user_id
+			AND completed_at>=@since
+		ORDER BY
+			day ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"since":   since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get completion days query for user_id=%s: %w", userID, err)
+	}
+
+	days, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	return days, nil
+}
+
+// GetCompletionHeatmap returns the UTC weekday and hour slots with at least one completion since the given date
+func (r *StatsRepository) GetCompletionHeatmap(ctx context.Context, userID string, since time.Time) ([]stats.HeatmapCell, error) {
+	stmt := `
+		SELECT
+			EXTRACT(DOW FROM completed_at AT TIME ZONE 'UTC')::INT AS weekday,
+			EXTRACT(HOUR FROM completed_at AT TIME ZONE 'UTC')::INT AS hour,
+			COUNT(*) AS completed
+		FROM
+			todos
+		WHERE
+			user_id=@user_id
+			AND completed_at>=@since
+		GROUP BY
+			weekday,
+			hour
+		ORDER BY
+			weekday ASC,
+			hour ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"since":   since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get completion heatmap query for user_id=%s: %w", userID, err)
+	}
+
+	cells, err := pgx.CollectRows(rows, pgx.RowToStructByName[stats.HeatmapCell])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	return cells, nil
+}
+
+func (r *StatsRepository) GetCompletionsByPriority(ctx context.Context, userID string,
+	since time.Time,
+) ([]stats.PriorityCompletion, error) {
+	stmt := `
+		SELECT
+			priority,
+			COUNT(*) AS completed,
+			AVG(EXTRACT(EPOCH FROM (completed_at - created_at)))::DOUBLE PRECISION AS avg_completion_seconds
+		FROM
+			todos
+		WHERE
+			user_id=@user_id
+			AND completed_at>=@since
+		GROUP BY
+			priority
+		ORDER BY
+			MIN(priority_level) ASC,
+			priority ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"since":   since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get completions by priority query for user_id=%s: %w", userID, err)
+	}
+
+	priorities, err := pgx.CollectRows(rows, pgx.RowToStructByName[stats.PriorityCompletion])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	return priorities, nil
+}
+
+func (r *StatsRepository) GetCompletionsByCategory(ctx context.Context, userID string,
+	since time.Time,
+) ([]stats.CategoryCompletion, error) {
+	stmt := `
+		SELECT
+			t.category_id,
+			c.name,
+			c.color,
+			COUNT(*) AS completed,
+			AVG(EXTRACT(EPOCH FROM (t.completed_at - t.created_at)))::DOUBLE PRECISION AS avg_completion_seconds
+		FROM
+			todos t
+			JOIN todo_categories c ON c.id=t.category_id
+		WHERE
+			t.user_id=@user_id
+			AND t.completed_at>=@since
+		GROUP BY
+			t.category_id,
+			c.name,
+			c.color
+		ORDER BY
+			completed DESC,
+			c.name ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"since":   since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get completions by category query for user_id=%s: %w", userID, err)
+	}
+
+	categories, err := pgx.CollectRows(rows, pgx.RowToStructByName[stats.CategoryCompletion])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	return categories, nil
+}
+
+// GetOverdueRatio counts the todos due between since and now, and how many of them were overdue
+func (r *StatsRepository) GetOverdueRatio(ctx context.Context, userID string, since, now time.Time) (*stats.OverdueRatio, error) {
+	stmt := `
+		SELECT
+			COUNT(*) AS due,
+			COUNT(*) FILTER (
+				WHERE
+					completed_at IS NULL
+					OR completed_at>due_date
+			) AS overdue
+		FROM
+			todos
+		WHERE
+			user_id=@user_id
+			AND due_date>=@since
+			AND due_date<@now
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"since":   since,
+		"now":     now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get overdue ratio query for user_id=%s: %w", userID, err)
+	}
+
+	ratio, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[stats.OverdueRatio])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	return &ratio, nil
+}
+
+// GetFocusDays returns the UTC days since the given date with at least one completed focus session, oldest first
+func (r *StatsRepository) GetFocusDays(ctx context.Context, userID string, since time.Time) ([]string, error) {
+	stmt := `
+		SELECT DISTINCT
+			TO_CHAR(completed_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day
+		FROM
+			focus_sessions
+		WHERE
+			user_id=@user_id
+			AND status='completed'
+			AND completed_at>=@since
+		ORDER BY
+			day ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"since":   since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get focus days query for user_id=%s: %w", userID, err)
+	}
+
+	days, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:focus_sessions for user_id=%s: %w", userID, err)
+	}
+
+	return days, nil
+}
+
+// GetFocusTotals sums the focus sessions completed since the given date
+func (r *StatsRepository) GetFocusTotals(ctx context.Context, userID string, since time.Time) (*stats.FocusTotals, error) {
+	stmt := `
+		SELECT
+			COUNT(*) AS sessions,
+			(COALESCE(SUM(focused_seconds), 0) / 60)::INT AS focused_minutes
+		FROM
+			focus_sessions
+		WHERE
+			user_id=@user_id
+			AND status='completed'
+			AND completed_at>=@since
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"since":   since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get focus totals query for user_id=%s: %w", userID, err)
+	}
+
+	totals, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[stats.FocusTotals])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:focus_sessions for user_id=%s: %w", userID, err)
+	}
+
+	return &totals, nil
+}
+
+// CountCompletions counts the todos of the user that are completed, archived ones included
+func (r *StatsRepository) CountCompletions(ctx context.Context, userID string) (int, error) {
+	stmt := `
+		SELECT
+			COUNT(*)
+		FROM
+			todos
+		WHERE
+			user_id=@user_id
+			AND completed_at IS NOT NULL
+	`
+
+	var count int
+	err := r.server.DB.Reader(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	}).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute count completions query for user_id=%s: %w", userID, err)
+	}
+
+	return count, nil
+}
+
+// CRON REQUIREMENTS
+
+// RefreshStats recomputes every dashboard aggregate, concurrently so readers are never blocked
+func (r *StatsRepository) RefreshStats(ctx context.Context) error {
+	// Refreshes scan every todo, they're exempt from the statement timeout meant for requests
+	return withoutStatementTimeout(ctx, r.server, func(conn *pgxpool.Conn) error {
+		for _, view := range statsViews {
+			if _, err := conn.Exec(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY "+view); err != nil {
+				return fmt.Errorf("failed to refresh materialized view %s: %w", view, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/health"
+	"github.com/Sameer16536/ExecuTask/internal/model/statuspage"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type StatusPageRepository struct {
+	server *server.Server
+}
+
+func NewStatusPageRepository(server *server.Server) *StatusPageRepository {
+	return &StatusPageRepository{server: server}
+}
+
+// RecordChecks folds the outcome of a health check into the day of every component. An
+// instance answering late doesn't overwrite the latest status with an older one.
+func (r *StatusPageRepository) RecordChecks(ctx context.Context, checkedAt time.Time,
+	statuses map[statuspage.Component]health.Status,
+) error {
+	components := make([]string, 0, len(statuses))
+	outcomes := make([]string, 0, len(statuses))
+	for _, component := range statuspage.Components {
+		if status, ok := statuses[component]; ok {
+			components = append(components, string(component))
+			outcomes = append(outcomes, string(status))
+		}
+	}
+
+	stmt := `
+		INSERT INTO
+			status_checks (
+				component,
+				day,
+				checks,
+				degraded,
+				failures,
+				last_status,
+				last_checked_at
+			)
+		SELECT
+			c.component,
+			@day,
+			1,
+			(c.status = 'degraded')::INT,
+			(c.status = 'unhealthy')::INT,
+			c.status,
+			@checked_at
+		FROM
+			UNNEST(@components::TEXT[], @statuses::TEXT[]) AS c (component, status)
+		ON CONFLICT (component, day) DO UPDATE
+		SET
+			checks = status_checks.checks + 1,
+			degraded = status_checks.degraded + EXCLUDED.degraded,
+			failures = status_checks.failures + EXCLUDED.failures,
+			last_status = CASE
+				WHEN EXCLUDED.last_checked_at >= status_checks.last_checked_at THEN EXCLUDED.last_status
+				ELSE status_checks.last_status
+			END,
+			last_checked_at = GREATEST(status_checks.last_checked_at, EXCLUDED.last_checked_at)
+	`
+
+	checkedAt = checkedAt.UTC()
+	_, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"day":        checkedAt.Truncate(24 * time.Hour),
+		"checked_at": checkedAt,
+		"components": components,
+		"statuses":   outcomes,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute record status checks query: %w", err)
+	}
+
+	return nil
+}
+
+// GetHistory returns the days of every component since the day of since, oldest first
+func (r *StatusPageRepository) GetHistory(ctx context.Context, since time.Time) ([]statuspage.Day, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			status_checks
+		WHERE
+			day >= @since
+		ORDER BY
+			day ASC,
+			component ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"since": since.UTC().Truncate(24 * time.Hour),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get status history query: %w", err)
+	}
+
+	days, err := pgx.CollectRows(rows, pgx.RowToStructByName[statuspage.Day])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:status_checks: %w", err)
+	}
+
+	return days, nil
+}
+
+// GetIncidents returns the incidents started since, and the open ones whenever they started,
+// latest first
+func (r *StatusPageRepository) GetIncidents(ctx context.Context, since time.Time) ([]statuspage.Incident, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			status_incidents
+		WHERE
+			started_at >= @since
+			OR resolved_at IS NULL
+		ORDER BY
+			started_at DESC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"since": since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get incidents query: %w", err)
+	}
+
+	incidents, err := pgx.CollectRows(rows, pgx.RowToStructByName[statuspage.Incident])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:status_incidents: %w", err)
+	}
+
+	return incidents, nil
+}
+
+func (r *StatusPageRepository) CreateIncident(ctx context.Context, createdBy string,
+	payload *statuspage.CreateIncidentPayload,
+) (*statuspage.Incident, error) {
+	stmt := `
+		INSERT INTO
+			status_incidents (
+				title,
+				message,
+				status,
+				impact,
+				components,
+				started_at,
+				resolved_at,
+				created_by
+			)
+		VALUES
+			(
+				@title,
+				@message,
+				@status,
+				@impact,
+				@components,
+				COALESCE(@started_at, CURRENT_TIMESTAMP),
+				CASE
+					WHEN @status = 'resolved' THEN CURRENT_TIMESTAMP
+				END,
+				@created_by
+			)
+		RETURNING
+			*
+	`
+
+	return r.incidentRow(ctx, "create incident", stmt, pgx.NamedArgs{
+		"title":      payload.Title,
+		"message":    payload.Message,
+		"status":     *payload.Status,
+		"impact":     payload.Impact,
+		"components": payload.Components,
+		"started_at": payload.StartedAt,
+		"created_by": createdBy,
+	})
+}
+
+// UpdateIncident edits an incident. Resolving it stamps resolved_at, moving it back to another
+// status reopens it.
+func (r *StatusPageRepository) UpdateIncident(ctx context.Context,
+	payload *statuspage.UpdateIncidentPayload,
+) (*statuspage.Incident, error) {
+	args := pgx.NamedArgs{
+		"id": payload.ID,
+	}
+	setClauses := []string{}
+
+	if payload.Title != nil {
+		setClauses = append(setClauses, "title = @title")
+		args["title"] = *payload.Title
+	}
+	if payload.Message != nil {
+		setClauses = append(setClauses, "message = @message")
+		args["message"] = *payload.Message
+	}
+	if payload.Status != nil {
+		setClauses = append(setClauses, "status = @status",
+			"resolved_at = CASE WHEN @status = 'resolved' THEN COALESCE(resolved_at, CURRENT_TIMESTAMP) END")
+		args["status"] = *payload.Status
+	}
+	if payload.Impact != nil {
+		setClauses = append(setClauses, "impact = @impact")
+		args["impact"] = *payload.Impact
+	}
+	if payload.Components != nil {
+		setClauses = append(setClauses, "components = @components")
+		args["components"] = payload.Components
+	}
+
+	if len(setClauses) == 0 {
+		return nil, errs.NewBadRequestError("no fields to update", false, nil, nil, nil)
+	}
+
+	stmt := `UPDATE status_incidents SET ` + strings.Join(setClauses, ", ") + ` WHERE id = @id RETURNING *`
+
+	return r.incidentRow(ctx, "update incident", stmt, args)
+}
+
+func (r *StatusPageRepository) DeleteIncident(ctx context.Context, incidentID uuid.UUID) (*statuspage.Incident, error) {
+	stmt := `
+		DELETE FROM status_incidents
+		WHERE
+			id = @id
+		RETURNING
+			*
+	`
+
+	return r.incidentRow(ctx, "delete incident", stmt, pgx.NamedArgs{
+		"id": incidentID,
+	})
+}
+
+func (r *StatusPageRepository) incidentRow(ctx context.Context, operation, stmt string,
+	args pgx.NamedArgs,
+) (*statuspage.Incident, error) {
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s query for incident_id=%v: %w", operation, args["id"], err)
+	}
+
+	incident, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[statuspage.Incident])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeIncidentNotFound
+			return nil, errs.NewNotFoundError("incident not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:status_incidents for incident_id=%v: %w", args["id"], err)
+	}
+
+	return &incident, nil
+}
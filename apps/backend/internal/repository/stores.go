@@ -0,0 +1,494 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/database"
+	"github.com/Sameer16536/ExecuTask/internal/lib/envelope"
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/admin"
+	"github.com/Sameer16536/ExecuTask/internal/model/apiclient"
+	"github.com/Sameer16536/ExecuTask/internal/model/apikey"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/model/backup"
+	"github.com/Sameer16536/ExecuTask/internal/model/category"
+	"github.com/Sameer16536/ExecuTask/internal/model/change"
+	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/cover"
+	"github.com/Sameer16536/ExecuTask/internal/model/dependency"
+	"github.com/Sameer16536/ExecuTask/internal/model/export"
+	"github.com/Sameer16536/ExecuTask/internal/model/feature"
+	"github.com/Sameer16536/ExecuTask/internal/model/focus"
+	"github.com/Sameer16536/ExecuTask/internal/model/gamification"
+	"github.com/Sameer16536/ExecuTask/internal/model/health"
+	"github.com/Sameer16536/ExecuTask/internal/model/inbox"
+	"github.com/Sameer16536/ExecuTask/internal/model/magictag"
+	"github.com/Sameer16536/ExecuTask/internal/model/matrix"
+	"github.com/Sameer16536/ExecuTask/internal/model/note"
+	"github.com/Sameer16536/ExecuTask/internal/model/planner"
+	"github.com/Sameer16536/ExecuTask/internal/model/preview"
+	"github.com/Sameer16536/ExecuTask/internal/model/report"
+	"github.com/Sameer16536/ExecuTask/internal/model/retention"
+	"github.com/Sameer16536/ExecuTask/internal/model/rule"
+	"github.com/Sameer16536/ExecuTask/internal/model/search"
+	"github.com/Sameer16536/ExecuTask/internal/model/stats"
+	"github.com/Sameer16536/ExecuTask/internal/model/statuspage"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/model/usage"
+	"github.com/Sameer16536/ExecuTask/internal/model/webhook"
+	"github.com/Sameer16536/ExecuTask/internal/model/workspace"
+	"github.com/google/uuid"
+)
+
+// The stores are what services and jobs consume. The Postgres repositories of this package
+// implement them, the memory package has fakes for tests that run without a database.
+
+// TxManager runs a unit of work in one transaction. Stores called with the ctx handed to fn
+// take part in it, the work is committed when fn returns nil and rolled back otherwise.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type TodoStore interface {
+	CreateTodo(ctx context.Context, userID, workspaceID string, payload *todo.CreateTodoPayload) (*todo.Todo, error)
+	GetTodoByID(ctx context.Context, userID string, todoID uuid.UUID) (*todo.PopulatedTodo, error)
+	CheckTodoExists(ctx context.Context, userID string, todoID uuid.UUID) (*todo.Todo, error)
+	GetTodosByIDs(ctx context.Context, userID string, todoIDs []uuid.UUID) ([]todo.Todo, error)
+	GetTodos(ctx context.Context, userID string, query *todo.GetTodosQuery) (*model.PaginatedResponse[todo.PopulatedTodo], error)
+	StreamTodos(ctx context.Context, userID string, query *todo.ExportTodosQuery, fn func(todoItem *todo.Todo) error) error
+	UpdateTodo(ctx context.Context, userID string, payload *todo.UpdateTodoPayload) (*todo.Todo, error)
+	GetTodoVersions(ctx context.Context, userID string, todoID uuid.UUID) ([]todo.TodoVersion, error)
+	RestoreTodoVersion(ctx context.Context, userID string, todoID uuid.UUID, version int, expectedVersion *int) (*todo.Todo, error)
+	MarkCommentsRead(ctx context.Context, userID string, todoID uuid.UUID) (*todo.Todo, error)
+	RecordTodoView(ctx context.Context, userID string, todoID uuid.UUID) error
+	GetTodoViews(ctx context.Context, userID string, todoIDs []uuid.UUID) (map[uuid.UUID]time.Time, error)
+	DeleteTodo(ctx context.Context, userID string, todoID uuid.UUID) (*todo.Todo, error)
+	FindDuplicateTodos(ctx context.Context, userID, title string, categoryID *uuid.UUID, excludeID uuid.UUID,
+		threshold float64, limit int) ([]todo.DuplicateCandidate, error)
+	MergeTodos(ctx context.Context, userID string, targetID uuid.UUID, sourceIDs []uuid.UUID) error
+	GetTodoStats(ctx context.Context, userID string) (*todo.TodoStats, error)
+
+	GetTodoAttachment(ctx context.Context, todoID uuid.UUID, attachmentID uuid.UUID) (*todo.TodoAttachment, error)
+	GetTodoAttachments(ctx context.Context, todoID uuid.UUID) ([]todo.TodoAttachment, error)
+	DeleteTodoAttachment(ctx context.Context, todoID uuid.UUID, attachmentID uuid.UUID) error
+	UploadTodoAttachment(ctx context.Context, todoID uuid.UUID, userID string, s3Key string, fileName string,
+		fileSize int64, mimeType string, scanStatus *todo.ScanStatus) (*todo.TodoAttachment, error)
+	SetAttachmentScan(ctx context.Context, todoID uuid.UUID, attachmentID uuid.UUID, status todo.ScanStatus,
+		signature *string) (*todo.TodoAttachment, error)
+	SetAttachmentTranscription(ctx context.Context, todoID uuid.UUID, attachmentID uuid.UUID,
+		status todo.TranscriptionStatus, transcript *string) (*todo.TodoAttachment, error)
+	SetAttachmentProcessed(ctx context.Context, todoID uuid.UUID, attachmentID uuid.UUID, fileSize int64,
+		thumbnailKey *string) (*todo.TodoAttachment, error)
+	GetUserAttachmentBytes(ctx context.Context, userID string) (int64, error)
+	GetUserQuotaBytes(ctx context.Context, userID string) (*int64, error)
+	GetRecordedAttachmentKeys(ctx context.Context, downloadKeys []string) ([]string, error)
+	GetRecordedThumbnailKeys(ctx context.Context, thumbnailKeys []string) ([]string, error)
+
+	GetTodosUpdatedSince(ctx context.Context, userID string, since time.Time, limit int) ([]todo.Todo, error)
+	GetRecentTodos(ctx context.Context, userID string, limit int) ([]todo.Todo, error)
+	GetTodosDueBetween(ctx context.Context, userID string, from, to time.Time) ([]todo.Todo, error)
+	GetUnscheduledTodos(ctx context.Context, userID string, limit int) ([]todo.Todo, error)
+	GetOpenTodos(ctx context.Context, userID string, limit int) ([]todo.Todo, error)
+	GetRecentlyCompletedTodos(ctx context.Context, userID string, limit int) ([]todo.Todo, error)
+	GetChildrenByParentIDs(ctx context.Context, userID string, parentIDs []uuid.UUID) ([]todo.Todo, error)
+	GetAttachmentsByTodoIDs(ctx context.Context, todoIDs []uuid.UUID) ([]todo.TodoAttachment, error)
+
+	GetTodosDueInHours(ctx context.Context, hours int, limit int) ([]todo.Todo, error)
+	GetOverdueTodos(ctx context.Context, limit int) ([]todo.Todo, error)
+	GetCompletedTodosOlderThan(ctx context.Context, cutoffDate time.Time, limit int) ([]todo.Todo, error)
+	ArchiveTodos(ctx context.Context, todoIDs []uuid.UUID, userIDs []string) error
+	ApplyWorkflow(ctx context.Context, workspaceID string, statusMapping, priorityMapping map[string]string) (int, error)
+	GetWeeklyStatsForUsers(ctx context.Context, startDate, endDate time.Time) ([]todo.UserWeeklyStats, error)
+	GetCompletedTodosForUser(ctx context.Context, userID string, startDate, endDate time.Time) ([]todo.PopulatedTodo, error)
+	GetOverdueTodosForUser(ctx context.Context, userID string) ([]todo.PopulatedTodo, error)
+}
+
+type CategoryStore interface {
+	CreateCategory(ctx context.Context, userID string, payload *category.CreateCategoryPayload) (*category.Category, error)
+	GetCategoryByID(ctx context.Context, userID string, categoryID uuid.UUID) (*category.Category, error)
+	GetCategoriesByIDs(ctx context.Context, userID string, categoryIDs []uuid.UUID) ([]category.Category, error)
+	GetCategories(ctx context.Context, userID string, query *category.GetCategoriesQuery) (*model.PaginatedResponse[category.Category], error)
+	UpdateCategory(ctx context.Context, userID string, categoryID uuid.UUID, payload *category.UpdateCategoryPayload) (*category.Category, error)
+	DeleteCategory(ctx context.Context, userID string, categoryID uuid.UUID) (*category.Category, error)
+}
+
+type CommentStore interface {
+	AddComment(ctx context.Context, userID string, todoID uuid.UUID, payload *comment.AddCommentPayload) (*comment.Comment, error)
+	GetCommentsByTodoID(ctx context.Context, userID string, todoID uuid.UUID) ([]comment.Comment, error)
+	GetCommentsByTodoIDs(ctx context.Context, userID string, todoIDs []uuid.UUID, latestOnly bool) ([]comment.Comment, error)
+	GetCommentCounts(ctx context.Context, userID string, todoIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	GetCommentByID(ctx context.Context, userID string, commentID uuid.UUID) (*comment.Comment, error)
+	UpdateComment(ctx context.Context, userID string, commentID uuid.UUID, content string) (*comment.Comment, error)
+	DeleteComment(ctx context.Context, userID string, commentID uuid.UUID) error
+	CreateReplyToken(ctx context.Context, token *comment.ReplyToken) error
+	GetReplyToken(ctx context.Context, token string) (*comment.ReplyToken, error)
+	DeleteExpiredReplyTokens(ctx context.Context, now time.Time) (int64, error)
+}
+
+type ChangeStore interface {
+	GetChangesAfter(ctx context.Context, userID string, afterID int64, limit int) ([]change.Change, error)
+	GetOldestChangeID(ctx context.Context) (int64, error)
+	GetLatestChangeIDBefore(ctx context.Context, before time.Time) (int64, error)
+	DeleteChangesOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type AdminStore interface {
+	GetUserStats(ctx context.Context, userID string) (*admin.UserStats, error)
+	GetStorageQuota(ctx context.Context, userID string) (*admin.StorageQuota, error)
+	SetStorageQuota(ctx context.Context, updatedBy string, payload *admin.SetStorageQuotaPayload) (*admin.StorageQuota, error)
+	DeleteStorageQuota(ctx context.Context, userID string) (*admin.StorageQuota, error)
+	GetWorkspaceStats(ctx context.Context, workspaceID string) (*admin.WorkspaceStats, error)
+}
+
+type StatsStore interface {
+	GetSummary(ctx context.Context, userID string) (*stats.Summary, error)
+	GetDailyCompletions(ctx context.Context, userID string, since time.Time) ([]stats.DailyCompletions, error)
+	GetCategoryStats(ctx context.Context, userID string) ([]stats.CategoryStats, error)
+	GetCompletionDays(ctx context.Context, userID string, since time.Time) ([]string, error)
+	GetCompletionHeatmap(ctx context.Context, userID string, since time.Time) ([]stats.HeatmapCell, error)
+	GetCompletionsByPriority(ctx context.Context, userID string, since time.Time) ([]stats.PriorityCompletion, error)
+	GetCompletionsByCategory(ctx context.Context, userID string, since time.Time) ([]stats.CategoryCompletion, error)
+	GetOverdueRatio(ctx context.Context, userID string, since, now time.Time) (*stats.OverdueRatio, error)
+	GetFocusDays(ctx context.Context, userID string, since time.Time) ([]string, error)
+	GetFocusTotals(ctx context.Context, userID string, since time.Time) (*stats.FocusTotals, error)
+	CountCompletions(ctx context.Context, userID string) (int, error)
+	RefreshStats(ctx context.Context) error
+}
+
+type MaintenanceStore interface {
+	IsPartitioned(ctx context.Context, table string) (bool, error)
+	GetTablePartitions(ctx context.Context, table string) ([]admin.TablePartition, error)
+	VacuumTables(ctx context.Context, tables []string) error
+	AnalyzeTable(ctx context.Context, table string) error
+}
+
+type SearchStore interface {
+	SearchTodos(ctx context.Context, userID, query string, limit int) ([]search.Result, error)
+	SearchTodosByEmbedding(ctx context.Context, userID, model string, vector []float32, minSimilarity float64,
+		limit int) ([]search.Result, error)
+	SuggestCandidates(ctx context.Context, userID, query string, limit int) ([]search.Candidate, error)
+	GetPendingEmbeddings(ctx context.Context, model string, limit int) ([]search.PendingEmbedding, error)
+	UpsertEmbeddings(ctx context.Context, model string, pending []search.PendingEmbedding, vectors [][]float32) error
+	DeleteOrphanedEmbeddings(ctx context.Context) (int64, error)
+}
+
+type AuditStore interface {
+	CreateEntry(ctx context.Context, entry *audit.Entry) error
+	GetEntries(ctx context.Context, query *audit.GetAuditLogQuery) (*model.PaginatedResponse[audit.Entry], error)
+	StreamEntries(ctx context.Context, filter *audit.Filter, fn func(entry *audit.Entry) error) error
+}
+
+type FeatureStore interface {
+	GetOverridesFor(ctx context.Context, userID, workspaceID string) ([]feature.Override, error)
+	GetOverrides(ctx context.Context) ([]feature.Override, error)
+	SetOverride(ctx context.Context, updatedBy string, payload *feature.SetOverridePayload) (*feature.Override, error)
+	DeleteOverride(ctx context.Context, payload *feature.DeleteOverridePayload) (*feature.Override, error)
+}
+
+type UsageStore interface {
+	UpsertHourly(ctx context.Context, records []usage.Record) error
+	SnapshotStorage(ctx context.Context, hour time.Time) (int64, error)
+	GetBuckets(ctx context.Context, subjectType usage.SubjectType, subjectID string, from, to time.Time,
+		granularity usage.Granularity) ([]usage.Bucket, error)
+}
+
+type ExportStore interface {
+	CreateAccountExport(ctx context.Context, userID string) (*export.AccountExport, error)
+	CreateTodoListExport(ctx context.Context, userID string, filters *todo.ExportTodosPDFQuery) (*export.AccountExport, error)
+	GetAccountExport(ctx context.Context, userID string, exportID uuid.UUID) (*export.AccountExport, error)
+	StartAccountExport(ctx context.Context, exportID uuid.UUID) (*export.AccountExport, error)
+	CompleteAccountExport(ctx context.Context, exportID uuid.UUID, objectKey string, sizeBytes int64,
+		expiresAt time.Time) (*export.AccountExport, error)
+	FailAccountExport(ctx context.Context, exportID uuid.UUID, reason string) error
+	GetExpiredAccountExports(ctx context.Context, now time.Time, limit int) ([]export.AccountExport, error)
+	ClearAccountExportObject(ctx context.Context, exportID uuid.UUID) error
+	// CreateReportExport stores a run of a scheduled report, its file is already uploaded
+	CreateReportExport(ctx context.Context, userID string, filters *todo.ExportTodosPDFQuery, objectKey string,
+		sizeBytes int64, expiresAt time.Time) (*export.AccountExport, error)
+	GetCategoriesForUser(ctx context.Context, userID string) ([]category.Category, error)
+	GetCommentsForUser(ctx context.Context, userID string) ([]comment.Comment, error)
+	GetAttachmentsForUser(ctx context.Context, userID string) ([]todo.TodoAttachment, error)
+	GetNotesForUser(ctx context.Context, userID string) ([]note.Note, error)
+}
+
+type RetentionStore interface {
+	GetPolicies(ctx context.Context) ([]retention.Policy, error)
+	GetPolicy(ctx context.Context, workspaceID string) (*retention.Policy, error)
+	SetPolicy(ctx context.Context, updatedBy string, payload *retention.SetPolicyPayload) (*retention.Policy, error)
+	DeletePolicy(ctx context.Context, workspaceID string) (*retention.Policy, error)
+	PreviewRetention(ctx context.Context, workspaceID string, rules retention.Rules, now time.Time) (*retention.Report, error)
+	PurgeArchivedTodos(ctx context.Context, workspaceID string, archivedBefore time.Time, limit int) (int64, []string, error)
+	TrimComments(ctx context.Context, workspaceID string, maxPerTodo, limit int) (int64, error)
+	GetExpiredAttachments(ctx context.Context, workspaceID string, uploadedBefore time.Time, limit int) ([]todo.TodoAttachment, error)
+	DeleteAttachments(ctx context.Context, attachmentIDs []uuid.UUID) (int64, error)
+}
+
+// EncryptionStore keeps the data keys of the keyring and rewrites the sealed columns
+type EncryptionStore interface {
+	envelope.KeyStore
+	ReencryptColumns(ctx context.Context, keyring *envelope.Keyring, limit int) (int64, error)
+}
+
+// BackupStore tracks workspace backups and restores and reads and writes the data they move
+type BackupStore interface {
+	CreateBackup(ctx context.Context, workspaceID, requestedBy string) (*backup.Backup, error)
+	GetBackups(ctx context.Context, workspaceID string) ([]backup.Backup, error)
+	GetBackup(ctx context.Context, backupID uuid.UUID) (*backup.Backup, error)
+	StartBackup(ctx context.Context, backupID uuid.UUID) (*backup.Backup, error)
+	CompleteBackup(ctx context.Context, backupID uuid.UUID, objectKey string, sizeBytes int64,
+		counts map[string]int) (*backup.Backup, error)
+	FailBackup(ctx context.Context, backupID uuid.UUID, reason string) error
+	CreateRestore(ctx context.Context, backupID uuid.UUID, targetWorkspaceID, requestedBy string) (*backup.Restore, error)
+	GetRestore(ctx context.Context, restoreID uuid.UUID) (*backup.Restore, error)
+	StartRestore(ctx context.Context, restoreID uuid.UUID) (*backup.Restore, error)
+	CompleteRestore(ctx context.Context, restoreID uuid.UUID, counts map[string]int) (*backup.Restore, error)
+	FailRestore(ctx context.Context, restoreID uuid.UUID, reason string) error
+	CountWorkspaceTodos(ctx context.Context, workspaceID string) (int64, error)
+	GetWorkspaceContents(ctx context.Context, workspaceID string) (*backup.Contents, error)
+	RestoreCategory(ctx context.Context, categoryItem *category.Category) (*category.Category, error)
+	InsertRestoredContents(ctx context.Context, contents *backup.Contents) error
+}
+
+// RuleStore keeps the automation rules of users and the log of their executions
+type RuleStore interface {
+	CreateRule(ctx context.Context, userID string, payload *rule.CreateRulePayload) (*rule.Rule, error)
+	GetRules(ctx context.Context, userID string) ([]rule.Rule, error)
+	GetEnabledRules(ctx context.Context, userID string, trigger rule.Trigger) ([]rule.Rule, error)
+	CountRules(ctx context.Context, userID string) (int, error)
+	GetUserIDsWithEnabledRules(ctx context.Context, trigger rule.Trigger) ([]string, error)
+	GetRule(ctx context.Context, userID string, ruleID uuid.UUID) (*rule.Rule, error)
+	UpdateRule(ctx context.Context, userID string, payload *rule.UpdateRulePayload) (*rule.Rule, error)
+	DeleteRule(ctx context.Context, userID string, ruleID uuid.UUID) (*rule.Rule, error)
+	RecordExecution(ctx context.Context, execution *rule.Execution) error
+	GetExecutions(ctx context.Context, userID string, ruleID uuid.UUID, limit int) ([]rule.Execution, error)
+	HasExecution(ctx context.Context, ruleID, todoID uuid.UUID, trigger rule.Trigger) (bool, error)
+}
+
+// MatrixStore keeps the Eisenhower matrix thresholds of users
+type MatrixStore interface {
+	GetSettings(ctx context.Context, userID string) (*matrix.Settings, error)
+	SetSettings(ctx context.Context, userID string, settings matrix.Settings) (*matrix.Settings, error)
+}
+
+// FocusStore keeps the focus sessions users spend on their todos
+type FocusStore interface {
+	StartSession(ctx context.Context, userID string, payload *focus.StartSessionPayload) (*focus.Session, error)
+	GetSession(ctx context.Context, userID string, sessionID uuid.UUID) (*focus.Session, error)
+	GetOpenSession(ctx context.Context, userID string) (*focus.Session, error)
+	GetSessions(ctx context.Context, userID string, query *focus.GetSessionsQuery) ([]focus.Session, error)
+	UpdateSession(ctx context.Context, session *focus.Session, from focus.Status) (*focus.Session, error)
+	GetEstimateSamples(ctx context.Context, userID string, limit int) ([]planner.EstimateSample, error)
+}
+
+// MyDayStore keeps the todos users pick for their days, apart from the due dates. Days are
+// dates (YYYY-MM-DD) in the timezone of the user.
+type MyDayStore interface {
+	// AddTodos puts the todos on the list of the day, those waiting for review are reviewed
+	AddTodos(ctx context.Context, userID, day string, endsAt time.Time, todoIDs []uuid.UUID) error
+	// RemoveTodo takes the todo off the list of the day, or drops it from review
+	RemoveTodo(ctx context.Context, userID, day string, todoID uuid.UUID) error
+	GetTodos(ctx context.Context, userID, day string) ([]planner.MyDayTodo, error)
+	GetReview(ctx context.Context, userID string) ([]planner.ReviewTodo, error)
+	// GetSummaries returns the days from from to to with todos on their list, latest first
+	GetSummaries(ctx context.Context, userID, from, to string) ([]planner.DaySummary, error)
+	// RollOver moves the todos still open on the days ended by now to review
+	RollOver(ctx context.Context, now time.Time) (int64, error)
+}
+
+// GamificationStore keeps the badges awarded to users and their weekly goals
+type GamificationStore interface {
+	GetAwards(ctx context.Context, userID string) ([]gamification.Award, error)
+	AwardBadge(ctx context.Context, userID string, badge gamification.BadgeCode) (*gamification.Award, error)
+	GetGoal(ctx context.Context, userID string) (*gamification.Goal, error)
+	SetGoal(ctx context.Context, userID string, target int) (*gamification.Goal, error)
+	DeleteGoal(ctx context.Context, userID string) error
+}
+
+// DependencyStore keeps which todos block which
+type DependencyStore interface {
+	AddDependency(ctx context.Context, userID string, todoID, dependsOnID uuid.UUID) (*dependency.Dependency, error)
+	RemoveDependency(ctx context.Context, userID string, todoID, dependsOnID uuid.UUID) error
+	GetBlockedBy(ctx context.Context, userID string, todoID uuid.UUID) ([]todo.Todo, error)
+	GetBlocking(ctx context.Context, userID string, todoID uuid.UUID) ([]todo.Todo, error)
+	GetDownstreamDependencies(ctx context.Context, userID string, todoID uuid.UUID) ([]dependency.Dependency, error)
+}
+
+// InboxStore keeps the quick captures of users until they are triaged
+type InboxStore interface {
+	CaptureItem(ctx context.Context, userID string, payload *inbox.CapturePayload) (*inbox.Item, error)
+	GetItems(ctx context.Context, userID string, query *inbox.GetItemsQuery) ([]inbox.Item, error)
+	RemoveItem(ctx context.Context, userID string, itemID uuid.UUID) (*inbox.Item, error)
+}
+
+// WorkspaceStore keeps what the admins of a workspace set up for its members, who the members
+// are and which todos its aging policies escalated
+type WorkspaceStore interface {
+	GetTemplates(ctx context.Context, workspaceID string) ([]workspace.Template, error)
+	GetTemplate(ctx context.Context, workspaceID string, templateID uuid.UUID) (*workspace.Template, error)
+	CreateTemplate(ctx context.Context, createdBy string, payload *workspace.CreateTemplatePayload) (*workspace.Template, error)
+	DeleteTemplate(ctx context.Context, workspaceID string, templateID uuid.UUID) (*workspace.Template, error)
+	GetOnboardingKit(ctx context.Context, workspaceID string) (*workspace.OnboardingKit, error)
+	SetOnboardingKit(ctx context.Context, updatedBy string, payload *workspace.SetOnboardingKitPayload) (*workspace.OnboardingKit, error)
+	DeleteOnboardingKit(ctx context.Context, workspaceID string) (*workspace.OnboardingKit, error)
+	GetWorkflow(ctx context.Context, workspaceID string) (*workspace.Workflow, error)
+	SetWorkflow(ctx context.Context, updatedBy string, payload *workspace.SetWorkflowPayload) (*workspace.Workflow, error)
+	DeleteWorkflow(ctx context.Context, workspaceID string) (*workspace.Workflow, error)
+	AddMember(ctx context.Context, workspaceID, userID string) (bool, error)
+	GetWorkload(ctx context.Context, workspaceID string, from, to time.Time, location *time.Location) ([]workspace.WorkloadBucket, error)
+	GetAgingPolicies(ctx context.Context, workspaceID string) ([]workspace.AgingPolicy, error)
+	CreateAgingPolicy(ctx context.Context, createdBy string, payload *workspace.CreateAgingPolicyPayload) (*workspace.AgingPolicy, error)
+	DeleteAgingPolicy(ctx context.Context, workspaceID string, policyID uuid.UUID) (*workspace.AgingPolicy, error)
+	GetAgingPolicyWorkspaceIDs(ctx context.Context) ([]string, error)
+	RecordBreaches(ctx context.Context, policy *workspace.AgingPolicy, now time.Time, limit int) ([]workspace.Breach, error)
+	GetBreaches(ctx context.Context, workspaceID string) ([]workspace.Breach, error)
+	GetInvites(ctx context.Context, workspaceID string) ([]workspace.Invite, error)
+	GetInviteAcceptances(ctx context.Context, workspaceID string) ([]workspace.InviteAcceptance, error)
+	CreateInvite(ctx context.Context, invite *workspace.Invite) (*workspace.Invite, error)
+	GetInviteByToken(ctx context.Context, tokenHash string) (*workspace.Invite, error)
+	RevokeInvite(ctx context.Context, workspaceID string, inviteID uuid.UUID) (*workspace.Invite, error)
+	HasAcceptedInvite(ctx context.Context, inviteID uuid.UUID, userID string) (bool, error)
+	AcceptInvite(ctx context.Context, invite *workspace.Invite, userID, email string) (*workspace.InviteAcceptance, error)
+	GetFreeze(ctx context.Context, workspaceID string) (*workspace.Freeze, error)
+	IsFrozen(ctx context.Context, workspaceID string) (bool, error)
+	CreateFreeze(ctx context.Context, freeze *workspace.Freeze) (*workspace.Freeze, error)
+	SetFreezeBackup(ctx context.Context, workspaceID string, backupID uuid.UUID) (*workspace.Freeze, error)
+	DeleteFreeze(ctx context.Context, workspaceID string) (*workspace.Freeze, error)
+	GetPurgeDueWorkspaceIDs(ctx context.Context, now time.Time, limit int) ([]string, error)
+	PurgeWorkspace(ctx context.Context, workspaceID string) (map[string]int, error)
+}
+
+// LinkPreviewStore caches the preview cards of linked pages by URL
+type LinkPreviewStore interface {
+	GetPreviews(ctx context.Context, urls []string) ([]preview.Preview, error)
+	SavePreview(ctx context.Context, card *preview.Preview) error
+}
+
+// CoverStore keeps the cover image of each todo and the thumbnails rendered of it
+type CoverStore interface {
+	GetCover(ctx context.Context, userID string, todoID uuid.UUID) (*cover.Cover, error)
+	SetCover(ctx context.Context, item *cover.Cover) (*cover.Cover, error)
+	SaveVariants(ctx context.Context, todoID, generation uuid.UUID, status cover.Status, variantKeys map[string]string) (bool, error)
+	DeleteCover(ctx context.Context, userID string, todoID uuid.UUID) (*cover.Cover, error)
+	GetLiveGenerations(ctx context.Context, generations []uuid.UUID) ([]uuid.UUID, error)
+}
+
+// MagicTagStore keeps the shortcuts users type into todo titles
+type MagicTagStore interface {
+	GetMagicTags(ctx context.Context, userID string) ([]magictag.MagicTag, error)
+	CountMagicTags(ctx context.Context, userID string) (int, error)
+	CreateMagicTag(ctx context.Context, userID string, definition *magictag.Definition) (*magictag.MagicTag, error)
+	UpdateMagicTag(ctx context.Context, userID string, tagID uuid.UUID, definition *magictag.Definition) (*magictag.MagicTag, error)
+	DeleteMagicTag(ctx context.Context, userID string, tagID uuid.UUID) (*magictag.MagicTag, error)
+}
+
+// LocaleStore keeps the locale each user chose
+type LocaleStore interface {
+	GetLocale(ctx context.Context, userID string) (*string, error)
+	SetLocale(ctx context.Context, userID, locale string) error
+	DeleteLocale(ctx context.Context, userID string) (bool, error)
+}
+
+// StatusPageStore keeps the history of the deep health checks and the incidents announced on
+// the status page
+type StatusPageStore interface {
+	RecordChecks(ctx context.Context, checkedAt time.Time, statuses map[statuspage.Component]health.Status) error
+	GetHistory(ctx context.Context, since time.Time) ([]statuspage.Day, error)
+	GetIncidents(ctx context.Context, since time.Time) ([]statuspage.Incident, error)
+	CreateIncident(ctx context.Context, createdBy string, payload *statuspage.CreateIncidentPayload) (*statuspage.Incident, error)
+	UpdateIncident(ctx context.Context, payload *statuspage.UpdateIncidentPayload) (*statuspage.Incident, error)
+	DeleteIncident(ctx context.Context, incidentID uuid.UUID) (*statuspage.Incident, error)
+}
+
+// APIKeyStore keeps the API keys of users, by the hash of their secret
+type APIKeyStore interface {
+	GetKeys(ctx context.Context, userID string) ([]apikey.Key, error)
+	CountKeys(ctx context.Context, userID string) (int, error)
+	CreateKey(ctx context.Context, key *apikey.Key) (*apikey.Key, error)
+	DeleteKey(ctx context.Context, userID string, keyID uuid.UUID) (*apikey.Key, error)
+	GetKeyBySecretHash(ctx context.Context, secretHash []byte) (*apikey.Key, error)
+	SetKeyPlan(ctx context.Context, keyID uuid.UUID, plan string) (*apikey.Key, error)
+	TouchKey(ctx context.Context, keyID uuid.UUID) error
+}
+
+// APIClientStore keeps the frontends allowed to call the API from browsers of their origins
+type APIClientStore interface {
+	GetClients(ctx context.Context) ([]apiclient.APIClient, error)
+	GetAllowedOrigins(ctx context.Context) ([]string, error)
+	CreateClient(ctx context.Context, createdBy string, payload *apiclient.CreateAPIClientPayload) (*apiclient.APIClient, error)
+	UpdateClient(ctx context.Context, payload *apiclient.UpdateAPIClientPayload) (*apiclient.APIClient, error)
+	DeleteClient(ctx context.Context, clientID uuid.UUID) (*apiclient.APIClient, error)
+}
+
+// NoteStore keeps the private notes on todos, every method is scoped to the author
+type NoteStore interface {
+	GetNote(ctx context.Context, userID string, todoID uuid.UUID) (*note.Note, error)
+	SetNote(ctx context.Context, userID string, todoID uuid.UUID, body string) (*note.Note, error)
+	DeleteNote(ctx context.Context, userID string, todoID uuid.UUID) (*note.Note, error)
+}
+
+// ReportStore keeps the reports users schedule, the report-schedules job reads those due
+type ReportStore interface {
+	GetSchedules(ctx context.Context, userID string) ([]report.Schedule, error)
+	GetSchedule(ctx context.Context, userID string, scheduleID uuid.UUID) (*report.Schedule, error)
+	CountSchedules(ctx context.Context, userID string) (int, error)
+	CreateSchedule(ctx context.Context, userID string, definition *report.Definition, nextRunAt time.Time) (*report.Schedule, error)
+	UpdateSchedule(ctx context.Context, userID string, scheduleID uuid.UUID, definition *report.Definition,
+		nextRunAt time.Time) (*report.Schedule, error)
+	PauseSchedule(ctx context.Context, userID string, scheduleID uuid.UUID) (*report.Schedule, error)
+	ResumeSchedule(ctx context.Context, userID string, scheduleID uuid.UUID, nextRunAt time.Time) (*report.Schedule, error)
+	DeleteSchedule(ctx context.Context, userID string, scheduleID uuid.UUID) (*report.Schedule, error)
+	GetDueSchedules(ctx context.Context, now time.Time, limit int) ([]report.Schedule, error)
+	SetNextRun(ctx context.Context, scheduleID uuid.UUID, nextRunAt time.Time) error
+	RecordRun(ctx context.Context, scheduleID uuid.UUID, runAt time.Time, failure *string) error
+}
+
+// WebhookStore keeps the endpoints workspaces deliver their events to, with their secrets opened
+type WebhookStore interface {
+	GetEndpoints(ctx context.Context, workspaceID string) ([]webhook.Endpoint, error)
+	GetEndpoint(ctx context.Context, workspaceID string, endpointID uuid.UUID) (*webhook.Endpoint, error)
+	// GetSubscribedEndpoints returns the enabled endpoints of the workspace delivered the event
+	GetSubscribedEndpoints(ctx context.Context, workspaceID, event string) ([]webhook.Endpoint, error)
+	CountEndpoints(ctx context.Context, workspaceID string) (int, error)
+	CreateEndpoint(ctx context.Context, workspaceID, createdBy, secret string,
+		payload *webhook.CreateEndpointPayload) (*webhook.Endpoint, error)
+	UpdateEndpoint(ctx context.Context, workspaceID string, payload *webhook.UpdateEndpointPayload) (*webhook.Endpoint, error)
+	DeleteEndpoint(ctx context.Context, workspaceID string, endpointID uuid.UUID) (*webhook.Endpoint, error)
+}
+
+var (
+	_ TxManager         = (*database.TxManager)(nil)
+	_ TodoStore         = (*TodoRepository)(nil)
+	_ CategoryStore     = (*CategoryRepository)(nil)
+	_ CommentStore      = (*CommentRepository)(nil)
+	_ ChangeStore       = (*ChangeRepository)(nil)
+	_ AdminStore        = (*AdminRepository)(nil)
+	_ StatsStore        = (*StatsRepository)(nil)
+	_ MaintenanceStore  = (*MaintenanceRepository)(nil)
+	_ SearchStore       = (*SearchRepository)(nil)
+	_ AuditStore        = (*AuditRepository)(nil)
+	_ FeatureStore      = (*FeatureRepository)(nil)
+	_ UsageStore        = (*UsageRepository)(nil)
+	_ ExportStore       = (*ExportRepository)(nil)
+	_ RetentionStore    = (*RetentionRepository)(nil)
+	_ EncryptionStore   = (*EncryptionRepository)(nil)
+	_ BackupStore       = (*BackupRepository)(nil)
+	_ RuleStore         = (*RuleRepository)(nil)
+	_ MatrixStore       = (*MatrixRepository)(nil)
+	_ FocusStore        = (*FocusRepository)(nil)
+	_ MyDayStore        = (*MyDayRepository)(nil)
+	_ GamificationStore = (*GamificationRepository)(nil)
+	_ DependencyStore   = (*DependencyRepository)(nil)
+	_ InboxStore        = (*InboxRepository)(nil)
+	_ WorkspaceStore    = (*WorkspaceRepository)(nil)
+	_ LinkPreviewStore  = (*LinkPreviewRepository)(nil)
+	_ CoverStore        = (*CoverRepository)(nil)
+	_ NoteStore         = (*NoteRepository)(nil)
+	_ MagicTagStore     = (*MagicTagRepository)(nil)
+	_ LocaleStore       = (*LocaleRepository)(nil)
+	_ StatusPageStore   = (*StatusPageRepository)(nil)
+	_ APIKeyStore       = (*APIKeyRepository)(nil)
+	_ APIClientStore    = (*APIClientRepository)(nil)
+	_ ReportStore       = (*ReportRepository)(nil)
+	_ WebhookStore      = (*WebhookRepository)(nil)
+)
@@ -0,0 +1,440 @@
+// Package storetest is a contract suite for the repository stores. It runs against the memory
+// fakes and the Postgres repositories alike, so the fakes keep doing what the database does.
+package storetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/admin"
+	"github.com/Sameer16536/ExecuTask/internal/model/category"
+	"github.com/Sameer16536/ExecuTask/internal/model/change"
+	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+// Run checks the stores of the repositories returned by setup. Every case writes as a user of
+// its own, so setup may hand out the same repositories each time.
+func Run(t *testing.T, setup func(t *testing.T) *repository.Repositories) {
+	cases := []struct {
+		name string
+		run  func(t *testing.T, repos *repository.Repositories)
+	}{
+		{name: "TodoVersions", run: testTodoVersions},
+		{name: "TodoClear", run: testTodoClear},
+		{name: "TodoCounters", run: testTodoCounters},
+		{name: "TodoDelete", run: testTodoDelete},
+		{name: "TodoChanges", run: testTodoChanges},
+		{name: "CategoryUniqueName", run: testCategoryUniqueName},
+		{name: "Notes", run: testNotes},
+		{name: "SealedColumnRotation", run: testSealedColumnRotation},
+		{name: "TxRollback", run: testTxRollback},
+		{name: "StorageQuotas", run: testStorageQuotas},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.run(t, setup(t))
+		})
+	}
+}
+
+func newUserID() string {
+	return "user_" + uuid.NewString()
+}
+
+// createTodo creates a personal todo, personal todos belong to no workspace
+func createTodo(t *testing.T, repos *repository.Repositories, userID string, payload *todo.CreateTodoPayload) *todo.Todo {
+	t.Helper()
+
+	created, err := repos.Todo.CreateTodo(context.Background(), userID, "", payload)
+	require.NoError(t, err)
+	return created
+}
+
+func updateTodo(t *testing.T, repos *repository.Repositories, userID string, payload *todo.UpdateTodoPayload) *todo.Todo {
+	t.Helper()
+
+	updated, err := repos.Todo.UpdateTodo(context.Background(), userID, payload)
+	require.NoError(t, err)
+	return updated
+}
+
+func createCategory(t *testing.T, repos *repository.Repositories, userID, name string) *category.Category {
+	t.Helper()
+
+	created, err := repos.Category.CreateCategory(context.Background(), userID, &category.CreateCategoryPayload{
+		Name:  name,
+		Color: "#336699",
+	})
+	require.NoError(t, err)
+	return created
+}
+
+func requireNoRows(t *testing.T, err error) {
+	t.Helper()
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+// testTodoVersions checks that edits bump the version, keep a snapshot each and that a stale
+// version is refused
+func testTodoVersions(t *testing.T, repos *repository.Repositories) {
+	ctx := context.Background()
+	userID := newUserID()
+
+	created := createTodo(t, repos, userID, &todo.CreateTodoPayload{Title: "Draft"})
+	require.Equal(t, 1, created.Version)
+	require.Equal(t, todo.StatusDraft, created.Status)
+	require.Equal(t, todo.PriorityMedium, created.Priority)
+
+	title := "Final"
+	updated := updateTodo(t, repos, userID, &todo.UpdateTodoPayload{ID: created.ID, Title: &title, Version: &created.Version})
+	require.Equal(t, 2, updated.Version)
+	require.Equal(t, "Final", updated.Title)
+
+	stale := "Stale"
+	_, err := repos.Todo.UpdateTodo(ctx, userID, &todo.UpdateTodoPayload{ID: created.ID, Title: &stale, Version: &created.Version})
+	var httpErr *errs.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, errs.CodeTodoVersionConflict, httpErr.Code)
+
+	versions, err := repos.Todo.GetTodoVersions(ctx, userID, created.ID)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, 2, versions[0].Version)
+	require.Equal(t, "Final", versions[0].Title)
+	require.Equal(t, 1, versions[1].Version)
+	require.Equal(t, "Draft", versions[1].Title)
+
+	// Todos of other users stay out of reach
+	_, err = repos.Todo.CheckTodoExists(ctx, newUserID(), created.ID)
+	requireNoRows(t, err)
+	versions, err = repos.Todo.GetTodoVersions(ctx, newUserID(), created.ID)
+	require.NoError(t, err)
+	require.Empty(t, versions)
+}
+
+// testTodoClear checks that cleared fields are set back to null and left out fields are kept
+func testTodoClear(t *testing.T, repos *repository.Repositories) {
+	userID := newUserID()
+	categoryItem := createCategory(t, repos, userID, "Errands")
+
+	due := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	created := createTodo(t, repos, userID, &todo.CreateTodoPayload{
+		Title:      "Groceries",
+		DueDate:    &due,
+		CategoryID: &categoryItem.ID,
+		Metadata:   &todo.Metadata{Tags: []string{"home"}},
+	})
+
+	updated := updateTodo(t, repos, userID, &todo.UpdateTodoPayload{
+		ID:    created.ID,
+		Clear: []string{"dueDate", "categoryId"},
+	})
+	require.Nil(t, updated.DueDate)
+	require.Nil(t, updated.CategoryID)
+	require.NotNil(t, updated.Metadata)
+	require.Equal(t, []string{"home"}, updated.Metadata.Tags)
+	require.Equal(t, "Groceries", updated.Title)
+}
+
+// testTodoCounters checks the subtask counters of parents and the open todo counter of
+// categories, neither bumps a version
+func testTodoCounters(t *testing.T, repos *repository.Repositories) {
+	ctx := context.Background()
+	userID := newUserID()
+	categoryItem := createCategory(t, repos, userID, "Work")
+
+	parent := createTodo(t, repos, userID, &todo.CreateTodoPayload{Title: "Release"})
+	child := createTodo(t, repos, userID, &todo.CreateTodoPayload{
+		Title:        "Changelog",
+		ParentTodoID: &parent.ID,
+		CategoryID:   &categoryItem.ID,
+	})
+
+	counted, err := repos.Todo.CheckTodoExists(ctx, userID, parent.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, counted.SubtaskCount)
+	require.Equal(t, 0, counted.CompletedSubtaskCount)
+	require.Equal(t, parent.Version, counted.Version)
+
+	categoryCounted, err := repos.Category.GetCategoryByID(ctx, userID, categoryItem.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, categoryCounted.OpenTodoCount)
+	require.Equal(t, categoryItem.Version, categoryCounted.Version)
+
+	completed := todo.StatusCompleted
+	updateTodo(t, repos, userID, &todo.UpdateTodoPayload{ID: child.ID, Status: &completed})
+
+	counted, err = repos.Todo.CheckTodoExists(ctx, userID, parent.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, counted.SubtaskCount)
+	require.Equal(t, 1, counted.CompletedSubtaskCount)
+
+	categoryCounted, err = repos.Category.GetCategoryByID(ctx, userID, categoryItem.ID)
+	require.NoError(t, err)
+	require.Equal(t, 0, categoryCounted.OpenTodoCount)
+
+	_, err = repos.Todo.DeleteTodo(ctx, userID, child.ID)
+	require.NoError(t, err)
+
+	counted, err = repos.Todo.CheckTodoExists(ctx, userID, parent.ID)
+	require.NoError(t, err)
+	require.Equal(t, 0, counted.SubtaskCount)
+	require.Equal(t, 0, counted.CompletedSubtaskCount)
+}
+
+// testTodoDelete checks that deleting a todo takes its comments along
+func testTodoDelete(t *testing.T, repos *repository.Repositories) {
+	ctx := context.Background()
+	userID := newUserID()
+
+	created := createTodo(t, repos, userID, &todo.CreateTodoPayload{Title: "Discuss"})
+	added, err := repos.Comment.AddComment(ctx, userID, created.ID, &comment.AddCommentPayload{
+		TodoID:  created.ID,
+		Content: "Let's talk",
+	})
+	require.NoError(t, err)
+
+	populated, err := repos.Todo.GetTodoByID(ctx, userID, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, populated.CommentCount)
+
+	deleted, err := repos.Todo.DeleteTodo(ctx, userID, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, created.ID, deleted.ID)
+
+	_, err = repos.Todo.GetTodoByID(ctx, userID, created.ID)
+	requireNoRows(t, err)
+	_, err = repos.Comment.GetCommentByID(ctx, userID, added.ID)
+	requireNoRows(t, err)
+}
+
+// testTodoChanges checks the change events recorded for the lifetime of a todo
+func testTodoChanges(t *testing.T, repos *repository.Repositories) {
+	ctx := context.Background()
+	userID := newUserID()
+
+	created := createTodo(t, repos, userID, &todo.CreateTodoPayload{Title: "Track"})
+	title := "Tracked"
+	updateTodo(t, repos, userID, &todo.UpdateTodoPayload{ID: created.ID, Title: &title})
+	_, err := repos.Todo.DeleteTodo(ctx, userID, created.ID)
+	require.NoError(t, err)
+
+	changes, err := repos.Change.GetChangesAfter(ctx, userID, 0, 100)
+	require.NoError(t, err)
+
+	var actions []change.Action
+	var versions []int
+	for _, c := range changes {
+		if c.EntityType != "todo" || c.EntityID != created.ID {
+			continue
+		}
+		actions = append(actions, c.Action)
+		require.NotNil(t, c.Version)
+		versions = append(versions, *c.Version)
+	}
+	require.Equal(t, []change.Action{change.ActionCreated, change.ActionUpdated, change.ActionDeleted}, actions)
+	require.Equal(t, []int{1, 2, 2}, versions)
+
+	for i := 1; i < len(changes); i++ {
+		require.Greater(t, changes[i].ID, changes[i-1].ID)
+	}
+
+	others, err := repos.Change.GetChangesAfter(ctx, newUserID(), 0, 100)
+	require.NoError(t, err)
+	require.Empty(t, others)
+}
+
+// testCategoryUniqueName checks that a taken name fails with the unique violation sqlerr maps
+func testCategoryUniqueName(t *testing.T, repos *repository.Repositories) {
+	ctx := context.Background()
+	userID := newUserID()
+	createCategory(t, repos, userID, "Home")
+
+	_, err := repos.Category.CreateCategory(ctx, userID, &category.CreateCategoryPayload{Name: "Home", Color: "#000000"})
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, err, &pgErr)
+	require.Equal(t, "23505", pgErr.Code)
+	require.Equal(t, "todo_categories_unique_name", pgErr.ConstraintName)
+
+	// Names are per user
+	createCategory(t, repos, newUserID(), "Home")
+}
+
+// testNotes checks that private notes read back as written, through every store reading them
+func testNotes(t *testing.T, repos *repository.Repositories) {
+	ctx := context.Background()
+	userID := newUserID()
+
+	target := createTodo(t, repos, userID, &todo.CreateTodoPayload{Title: "Target"})
+	first := createTodo(t, repos, userID, &todo.CreateTodoPayload{Title: "First"})
+	second := createTodo(t, repos, userID, &todo.CreateTodoPayload{Title: "Second"})
+
+	saved, err := repos.Note.SetNote(ctx, userID, target.ID, "door code 1234")
+	require.NoError(t, err)
+	require.Equal(t, "door code 1234", saved.Body)
+
+	read, err := repos.Note.GetNote(ctx, userID, target.ID)
+	require.NoError(t, err)
+	require.Equal(t, "door code 1234", read.Body)
+
+	_, err = repos.Note.SetNote(ctx, userID, first.ID, "first")
+	require.NoError(t, err)
+	_, err = repos.Note.SetNote(ctx, userID, second.ID, "second")
+	require.NoError(t, err)
+
+	require.NoError(t, repos.Todo.MergeTodos(ctx, userID, target.ID, []uuid.UUID{first.ID, second.ID}))
+
+	merged, err := repos.Note.GetNote(ctx, userID, target.ID)
+	require.NoError(t, err)
+	require.Equal(t, "door code 1234\n\nfirst\n\nsecond", merged.Body)
+
+	exported, err := repos.Export.GetNotesForUser(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, exported, 1)
+	require.Equal(t, merged.Body, exported[0].Body)
+
+	deleted, err := repos.Note.DeleteNote(ctx, userID, target.ID)
+	require.NoError(t, err)
+	require.Equal(t, merged.Body, deleted.Body)
+
+	_, err = repos.Note.GetNote(ctx, userID, target.ID)
+	var httpErr *errs.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, errs.CodeNoteNotFound, httpErr.Code)
+}
+
+// testSealedColumnRotation checks that re-encryption moves sealed values to a rotated data key
+// and that they still open afterwards. Re-encryption goes over the values of every user.
+func testSealedColumnRotation(t *testing.T, repos *repository.Repositories) {
+	ctx := context.Background()
+	userID := newUserID()
+	require.True(t, repos.Keyring.Enabled(), "the keyring needs a master key")
+
+	created := createTodo(t, repos, userID, &todo.CreateTodoPayload{Title: "Vault"})
+	_, err := repos.Note.SetNote(ctx, userID, created.ID, "combination 4-8-15")
+	require.NoError(t, err)
+
+	previous, err := repos.Keyring.ActiveKeyID(ctx)
+	require.NoError(t, err)
+	rotated, err := repos.Keyring.Rotate(ctx)
+	require.NoError(t, err)
+	require.NotEqual(t, previous, rotated.ID)
+
+	active, err := repos.Encryption.GetActiveDataKey(ctx)
+	require.NoError(t, err)
+	require.Equal(t, rotated.ID, active.ID)
+
+	var rewritten int64
+	for {
+		count, err := repos.Encryption.ReencryptColumns(ctx, repos.Keyring, 10)
+		require.NoError(t, err)
+		if count == 0 {
+			break
+		}
+		rewritten += count
+	}
+	require.Positive(t, rewritten)
+
+	read, err := repos.Note.GetNote(ctx, userID, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, "combination 4-8-15", read.Body)
+}
+
+// testTxRollback checks that a failed unit of work leaves no writes behind, while a nested
+// failure only rolls back its own
+func testTxRollback(t *testing.T, repos *repository.Repositories) {
+	ctx := context.Background()
+	userID := newUserID()
+	failure := errors.New("unit of work failed")
+
+	var rolledBack *todo.Todo
+	err := repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		rolledBack, err = repos.Todo.CreateTodo(ctx, userID, "", &todo.CreateTodoPayload{Title: "Gone"})
+		require.NoError(t, err)
+		return failure
+	})
+	require.ErrorIs(t, err, failure)
+	_, err = repos.Todo.CheckTodoExists(ctx, userID, rolledBack.ID)
+	requireNoRows(t, err)
+
+	var kept, nested *todo.Todo
+	err = repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		kept, err = repos.Todo.CreateTodo(ctx, userID, "", &todo.CreateTodoPayload{Title: "Kept"})
+		require.NoError(t, err)
+
+		nestedErr := repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+			var err error
+			nested, err = repos.Todo.CreateTodo(ctx, userID, "", &todo.CreateTodoPayload{Title: "Nested"})
+			require.NoError(t, err)
+			return failure
+		})
+		require.ErrorIs(t, nestedErr, failure)
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, err = repos.Todo.CheckTodoExists(ctx, userID, kept.ID)
+	require.NoError(t, err)
+	_, err = repos.Todo.CheckTodoExists(ctx, userID, nested.ID)
+	requireNoRows(t, err)
+}
+
+// testStorageQuotas checks that an override replaces the quota of the config for the upload path
+// until it is removed
+func testStorageQuotas(t *testing.T, repos *repository.Repositories) {
+	ctx := context.Background()
+	userID := newUserID()
+
+	quotaBytes, err := repos.Todo.GetUserQuotaBytes(ctx, userID)
+	require.NoError(t, err)
+	require.Nil(t, quotaBytes)
+	_, err = repos.Admin.GetStorageQuota(ctx, userID)
+	var httpErr *errs.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, errs.CodeStorageQuotaNotFound, httpErr.Code)
+
+	limit := int64(1 << 20)
+	quota, err := repos.Admin.SetStorageQuota(ctx, "user_admin", &admin.SetStorageQuotaPayload{
+		UserID: userID, QuotaBytes: &limit,
+	})
+	require.NoError(t, err)
+	require.Equal(t, limit, quota.QuotaBytes)
+	require.Equal(t, "user_admin", quota.UpdatedBy)
+
+	// Setting it again replaces the override, 0 lifts the quota
+	var unlimited int64
+	quota, err = repos.Admin.SetStorageQuota(ctx, "user_other_admin", &admin.SetStorageQuotaPayload{
+		UserID: userID, QuotaBytes: &unlimited,
+	})
+	require.NoError(t, err)
+	require.Equal(t, unlimited, quota.QuotaBytes)
+	require.Equal(t, "user_other_admin", quota.UpdatedBy)
+
+	quotaBytes, err = repos.Todo.GetUserQuotaBytes(ctx, userID)
+	require.NoError(t, err)
+	require.NotNil(t, quotaBytes)
+	require.Equal(t, unlimited, *quotaBytes)
+
+	deleted, err := repos.Admin.DeleteStorageQuota(ctx, userID)
+	require.NoError(t, err)
+	require.Equal(t, unlimited, deleted.QuotaBytes)
+
+	quotaBytes, err = repos.Todo.GetUserQuotaBytes(ctx, userID)
+	require.NoError(t, err)
+	require.Nil(t, quotaBytes)
+	_, err = repos.Admin.DeleteStorageQuota(ctx, userID)
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, errs.CodeStorageQuotaNotFound, httpErr.Code)
+}
@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/envelope"
 	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/note"
 	"github.com/Sameer16536/ExecuTask/internal/model/todo"
 	"github.com/Sameer16536/ExecuTask/internal/server"
 	"github.com/google/uuid"
@@ -17,20 +19,26 @@ import (
 
 type TodoRepository struct {
 	server *server.Server
+	// keyring opens and seals the private notes merged along with todos
+	keyring *envelope.Keyring
 }
 
-func NewTodoRepository(server *server.Server) *TodoRepository {
-	return &TodoRepository{server: server}
+func NewTodoRepository(server *server.Server, keyring *envelope.Keyring) *TodoRepository {
+	return &TodoRepository{server: server, keyring: keyring}
 }
 
-func (r *TodoRepository) CreateTodo(ctx context.Context, userID string, payload *todo.CreateTodoPayload) (*todo.Todo, error) {
+func (r *TodoRepository) CreateTodo(ctx context.Context, userID, workspaceID string,
+	payload *todo.CreateTodoPayload,
+) (*todo.Todo, error) {
 	stmt := `
 		INSERT INTO
 			todos (
 				user_id,
+				workspace_id,
 				title,
 				description,
 				priority,
+				status,
 				due_date,
 				parent_todo_id,
 				category_id,
@@ -39,9 +47,11 @@ func (r *TodoRepository) CreateTodo(ctx context.Context, userID string, payload
 		VALUES
 			(
 				@user_id,
+				NULLIF(@workspace_id, ''),
 				@title,
 				@description,
 				@priority,
+				@status,
 				@due_date,
 				@parent_todo_id,
 				@category_id,
@@ -50,16 +60,23 @@ func (r *TodoRepository) CreateTodo(ctx context.Context, userID string, payload
 		RETURNING
 		*
 	`
-	// Check for empty priority field --> put default value
+	// Check for empty priority field --> put default value, the built-in keys stand for the
+	// first draft status and the middle level of the scales
 	priority := todo.PriorityMedium
 	if payload.Priority != nil {
 		priority = *payload.Priority
 	}
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+	status := todo.StatusDraft
+	if payload.Status != nil {
+		status = *payload.Status
+	}
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
 		"user_id":        userID,
+		"workspace_id":   workspaceID,
 		"title":          payload.Title,
 		"description":    payload.Description,
 		"priority":       priority,
+		"status":         status,
 		"due_date":       payload.DueDate,
 		"parent_todo_id": payload.ParentTodoID,
 		"category_id":    payload.CategoryID,
@@ -137,7 +154,7 @@ func (r *TodoRepository) GetTodoByID(ctx context.Context, userID string, todoID
 		c.id
 `
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
 		"id":      todoID,
 		"user_id": userID,
 	})
@@ -164,7 +181,7 @@ func (r *TodoRepository) CheckTodoExists(ctx context.Context, userID string, tod
 			AND user_id=@user_id
 	`
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
 		"id":      todoID,
 		"user_id": userID,
 	})
@@ -180,6 +197,35 @@ func (r *TodoRepository) CheckTodoExists(ctx context.Context, userID string, tod
 	return &todoItem, nil
 }
 
+// GetTodosByIDs loads several todos in one query, ids that don't exist or belong to
+// another user are left out. Reads go to the primary as callers write based on the result.
+func (r *TodoRepository) GetTodosByIDs(ctx context.Context, userID string, todoIDs []uuid.UUID) ([]todo.Todo, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todos
+		WHERE
+			id = ANY(@ids)
+			AND user_id=@user_id
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"ids":     todoIDs,
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get todos by ids query for user_id=%s: %w", userID, err)
+	}
+
+	todos, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	return todos, nil
+}
+
 func (r *TodoRepository) GetTodos(ctx context.Context, userID string, query *todo.GetTodosQuery) (*model.PaginatedResponse[todo.PopulatedTodo], error) {
 	stmt := `
 	SELECT
@@ -232,20 +278,37 @@ func (r *TodoRepository) GetTodos(ctx context.Context, userID string, query *tod
 		AND com.user_id=@user_id
 		LEFT JOIN todo_attachments att ON att.todo_id=t.id
 `
+	groupBy := " GROUP BY t.id, c.id"
+
+	// Expanded requests load their relations separately in batches
+	if query.Expand != nil {
+		stmt = `
+	SELECT
+		t.*,
+		NULL::JSONB AS category,
+		'[]'::JSONB AS children,
+		'[]'::JSONB AS comments,
+		'[]'::JSONB AS attachments
+	FROM
+		todos t
+`
+		groupBy = ""
+	}
 
 	args := pgx.NamedArgs{
 		"user_id": userID,
 	}
 	conditions := []string{"t.user_id = @user_id"}
 
-	if query.Status != nil {
-		conditions = append(conditions, "t.status = @status")
-		args["status"] = *query.Status
+	// A built-in status stands for any status of its category
+	if keys := query.Statuses(); keys != nil {
+		conditions = append(conditions, "(t.status = ANY(@statuses) OR t.status_category = ANY(@statuses))")
+		args["statuses"] = keys
 	}
 
-	if query.Priority != nil {
-		conditions = append(conditions, "t.priority = @priority")
-		args["priority"] = *query.Priority
+	if keys := query.Priorities(); keys != nil {
+		conditions = append(conditions, "t.priority = ANY(@priorities)")
+		args["priorities"] = keys
 	}
 
 	if query.CategoryID != nil {
@@ -272,20 +335,30 @@ func (r *TodoRepository) GetTodos(ctx context.Context, userID string, query *tod
 	}
 
 	if query.Overdue != nil && *query.Overdue {
-		conditions = append(conditions, "t.due_date < NOW() AND t.status != 'completed'")
+		conditions = append(conditions, "t.due_date < NOW() AND t.status_category != 'completed'")
 	}
 
 	if query.Completed != nil {
 		if *query.Completed {
-			conditions = append(conditions, "t.status = 'completed'")
+			conditions = append(conditions, "t.status_category = 'completed'")
 		} else {
-			conditions = append(conditions, "t.status != 'completed'")
+			conditions = append(conditions, "t.status_category != 'completed'")
 		}
 	}
 
 	if query.Search != nil {
-		conditions = append(conditions, "(t.title ILIKE @search OR t.description ILIKE @search)")
-		args["search"] = "%" + *query.Search + "%"
+		conditions = append(conditions, todoSearchCondition(r.server, args, *query.Search))
+	}
+
+	if query.Unread != nil {
+		unread := `(t.unread_comment_count > 0 OR NOT EXISTS (
+			SELECT 1 FROM todo_views v
+			WHERE v.todo_id = t.id AND v.user_id = @user_id AND v.viewed_at >= t.updated_at
+		))`
+		if !*query.Unread {
+			unread = "NOT " + unread
+		}
+		conditions = append(conditions, unread)
 	}
 
 	if len(conditions) > 0 {
@@ -298,19 +371,30 @@ func (r *TodoRepository) GetTodos(ctx context.Context, userID string, query *tod
 	}
 
 	var total int
-	err := r.server.DB.Pool.QueryRow(ctx, countStmt, args).Scan(&total)
+	err := r.server.DB.Reader(ctx).QueryRow(ctx, countStmt, args).Scan(&total)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total count for todos user_id=%s: %w", userID, err)
 	}
 
-	stmt += " GROUP BY t.id, c.id"
+	stmt += groupBy
 
 	if query.Sort != nil {
-		stmt += " ORDER BY t." + *query.Sort
+		direction := " ASC"
 		if query.Order != nil && *query.Order == "desc" {
-			stmt += " DESC"
-		} else {
-			stmt += " ASC"
+			direction = " DESC"
+		}
+
+		switch {
+		case *query.Sort == todo.SortRelevance && query.Search != nil:
+			stmt += " ORDER BY " + todoSearchRank + direction + ", t.created_at DESC"
+		case *query.Sort == todo.SortRelevance:
+			// Nothing to rank without a search, fall back to the newest first
+			stmt += " ORDER BY t.created_at" + direction
+		case *query.Sort == "priority":
+			// Priorities are ordered by their level on the scale, not by key
+			stmt += " ORDER BY t.priority_level" + direction
+		default:
+			stmt += " ORDER BY t." + *query.Sort + direction
 		}
 	} else {
 		stmt += " ORDER BY t.created_at DESC"
@@ -320,7 +404,7 @@ func (r *TodoRepository) GetTodos(ctx context.Context, userID string, query *tod
 	args["limit"] = *query.Limit
 	args["offset"] = (*query.Page - 1) * (*query.Limit)
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute get todos query for user_id=%s: %w", userID, err)
 	}
@@ -348,6 +432,69 @@ func (r *TodoRepository) GetTodos(ctx context.Context, userID string, query *tod
 	}, nil
 }
 
+// StreamTodos calls fn for every todo matching the query, one row at a time, so exports
+// don't hold the whole result set in memory. Returning an error from fn stops the stream.
+func (r *TodoRepository) StreamTodos(ctx context.Context, userID string, query *todo.ExportTodosQuery,
+	fn func(todoItem *todo.Todo) error,
+) error {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todos t
+	`
+
+	args := pgx.NamedArgs{
+		"user_id": userID,
+	}
+	conditions := []string{"t.user_id = @user_id"}
+
+	if keys := query.Statuses(); keys != nil {
+		conditions = append(conditions, "(t.status = ANY(@statuses) OR t.status_category = ANY(@statuses))")
+		args["statuses"] = keys
+	}
+
+	if keys := query.Priorities(); keys != nil {
+		conditions = append(conditions, "t.priority = ANY(@priorities)")
+		args["priorities"] = keys
+	}
+
+	if query.CategoryID != nil {
+		conditions = append(conditions, "t.category_id = @category_id")
+		args["category_id"] = *query.CategoryID
+	}
+
+	if query.Search != nil {
+		conditions = append(conditions, todoSearchCondition(r.server, args, *query.Search))
+	}
+
+	stmt += " WHERE " + strings.Join(conditions, " AND ")
+	stmt += " ORDER BY t.created_at ASC, t.id ASC"
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, args)
+	if err != nil {
+		return fmt.Errorf("failed to execute stream todos query for user_id=%s: %w", userID, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		todoItem, err := pgx.RowToStructByName[todo.Todo](rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan row from table:todos for user_id=%s: %w", userID, err)
+		}
+
+		if err := fn(&todoItem); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to stream rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	return nil
+}
+
 func (r *TodoRepository) UpdateTodo(ctx context.Context, userID string, payload *todo.UpdateTodoPayload) (*todo.Todo, error) {
 	stmt := "UPDATE todos SET "
 	args := pgx.NamedArgs{
@@ -367,16 +514,9 @@ func (r *TodoRepository) UpdateTodo(ctx context.Context, userID string, payload
 	}
 
 	if payload.Status != nil {
+		// The resolve_workflow_todos trigger sets completed_at along with the category
 		setClauses = append(setClauses, "status = @status")
 		args["status"] = *payload.Status
-
-		// Auto-set completed_at when status changes to completed
-		if *payload.Status == todo.StatusCompleted {
-			setClauses = append(setClauses, "completed_at = @completed_at")
-			args["completed_at"] = time.Now()
-		} else if *payload.Status != todo.StatusCompleted {
-			setClauses = append(setClauses, "completed_at = NULL")
-		}
 	}
 
 	if payload.Priority != nil {
@@ -404,246 +544,1170 @@ func (r *TodoRepository) UpdateTodo(ctx context.Context, userID string, payload
 		args["metadata"] = payload.Metadata
 	}
 
+	for _, field := range payload.Clear {
+		setClauses = append(setClauses, todo.ClearableFields[field]+" = NULL")
+	}
+
 	if len(setClauses) == 0 {
 		return nil, errs.NewBadRequestError("no fields to update", false, nil, nil, nil)
 	}
 
 	stmt += strings.Join(setClauses, ", ")
-	stmt += " WHERE id = @todo_id AND user_id = @user_id RETURNING *"
+	stmt += " WHERE id = @todo_id AND user_id = @user_id"
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	// Optimistic concurrency: only update if the caller saw the latest version
+	if payload.Version != nil {
+		stmt += " AND version = @version"
+		args["version"] = *payload.Version
+	}
+
+	stmt += " RETURNING *"
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
 
 	updatedTodo, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.Todo])
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) && payload.Version != nil {
+			code := errs.CodeTodoVersionConflict
+			return nil, errs.NewConflictError("todo was modified since the given version", false, &code)
+		}
 		return nil, fmt.Errorf("failed to collect row from table:todos: %w", err)
 	}
 
 	return &updatedTodo, nil
 }
 
-func (r *TodoRepository) DeleteTodo(ctx context.Context, userID string, todoID uuid.UUID) error {
+// GetTodoVersions returns the kept snapshots of a todo, newest first
+func (r *TodoRepository) GetTodoVersions(ctx context.Context, userID string, todoID uuid.UUID) ([]todo.TodoVersion, error) {
 	stmt := `
-		DELETE FROM todos
+		SELECT
+			todo_id,
+			version,
+			created_at,
+			title,
+			description,
+			priority,
+			status,
+			due_date,
+			completed_at,
+			parent_todo_id,
+			category_id,
+			metadata
+		FROM
+			todo_snapshots
 		WHERE
-			id=@todo_id
-			AND user_id=@user_id
+			todo_id = @todo_id
+			AND user_id = @user_id
+		ORDER BY
+			version DESC
 	`
 
-	result, err := r.server.DB.Pool.Exec(ctx, stmt, pgx.NamedArgs{
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
 		"todo_id": todoID,
 		"user_id": userID,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to execute query: %w", err)
+		return nil, fmt.Errorf("failed to execute get todo versions query for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
 	}
 
-	if result.RowsAffected() == 0 {
-		code := "TODO_NOT_FOUND"
-		return errs.NewNotFoundError("todo not found", false, &code)
+	versions, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.TodoVersion])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_snapshots for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
 	}
 
-	return nil
+	return versions, nil
 }
 
-func (r *TodoRepository) GetTodoStats(ctx context.Context, userID string) (*todo.TodoStats, error) {
+// RestoreTodoVersion puts the fields of a snapshot back on the todo. The restore is an edit
+// like any other: it bumps the version and is snapshotted itself, so it can be undone too.
+// The parent is left as is, a category deleted since is cleared.
+func (r *TodoRepository) RestoreTodoVersion(ctx context.Context, userID string, todoID uuid.UUID, version int,
+	expectedVersion *int,
+) (*todo.Todo, error) {
 	stmt := `
-		SELECT
-			COUNT(*) AS total,
-			COUNT(
-				CASE
-					WHEN status='draft' THEN 1
-				END
-			) AS draft,
-			COUNT(
-				CASE
-					WHEN status='active' THEN 1
-				END
-			) AS active,
-			COUNT(
-				CASE
-					WHEN status='completed' THEN 1
-				END
-			) AS completed,
-			COUNT(
-				CASE
-					WHEN status='archived' THEN 1
-				END
-			) AS archived,
-			COUNT(
-				CASE
-					WHEN due_date<NOW()
-					AND status!='completed' THEN 1
-				END
-			) AS overdue
+		UPDATE todos t
+		SET
+			title = s.title,
+			description = s.description,
+			priority = s.priority,
+			status = s.status,
+			due_date = s.due_date,
+			completed_at = s.completed_at,
+			category_id = (
+				SELECT
+					c.id
+				FROM
+					todo_categories c
+				WHERE
+					c.id = s.category_id
+					AND c.user_id = t.user_id
+			),
+			metadata = s.metadata
 		FROM
-			todos
+			todo_snapshots s
 		WHERE
-			user_id=@user_id
+			t.id = @todo_id
+			AND t.user_id = @user_id
+			AND s.todo_id = t.id
+			AND s.version = @version
 	`
-
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+	args := pgx.NamedArgs{
+		"todo_id": todoID,
 		"user_id": userID,
-	})
+		"version": version,
+	}
+
+	// Optimistic concurrency: only restore if the caller saw the latest version
+	if expectedVersion != nil {
+		stmt += " AND t.version = @expected_version"
+		args["expected_version"] = *expectedVersion
+	}
+
+	stmt += " RETURNING t.*"
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, args)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return nil, fmt.Errorf("failed to execute restore todo version query for todo_id=%s version=%d: %w", todoID.String(), version, err)
 	}
 
-	stats, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.TodoStats])
+	restoredTodo, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.Todo])
 	if err != nil {
-		return nil, fmt.Errorf("failed to collect row from table:todos: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			if expectedVersion != nil {
+				code := errs.CodeTodoVersionConflict
+				return nil, errs.NewConflictError("todo was modified since the given version", false, &code)
+			}
+			code := errs.CodeTodoVersionNotFound
+			return nil, errs.NewNotFoundError("todo version not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:todos for todo_id=%s: %w", todoID.String(), err)
 	}
 
-	return &stats, nil
+	return &restoredTodo, nil
 }
-func (r *TodoRepository) GetTodoAttachment(
-	ctx context.Context,
-	todoID uuid.UUID,
-	attachmentID uuid.UUID,
-) (*todo.TodoAttachment, error) {
+
+// MarkCommentsRead clears the unread comment counter of a todo
+func (r *TodoRepository) MarkCommentsRead(ctx context.Context, userID string, todoID uuid.UUID) (*todo.Todo, error) {
 	stmt := `
-		SELECT
-			*
-		FROM
-			todo_attachments
+		UPDATE todos
+		SET
+			comments_read_at = NOW(),
+			unread_comment_count = 0
 		WHERE
-			todo_id = @todo_id
-			AND id = @attachment_id
+			id=@todo_id
+			AND user_id=@user_id
+		RETURNING
+			*
 	`
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
-		"todo_id":       todoID,
-		"attachment_id": attachmentID,
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id": todoID,
+		"user_id": userID,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get todo attachment: %w", err)
+		return nil, fmt.Errorf("failed to execute mark comments read query for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
 	}
 
-	attachment, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.TodoAttachment])
+	todoItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.Todo])
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			code := "ATTACHMENT_NOT_FOUND"
-			return nil, errs.NewNotFoundError("attachment not found", false, &code)
+			code := errs.CodeTodoNotFound
+			return nil, errs.NewNotFoundError("todo not found", false, &code)
 		}
-		return nil, fmt.Errorf("failed to collect row from table:todo_attachments: %w", err)
+		return nil, fmt.Errorf("failed to collect row from table:todos for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
 	}
 
-	return &attachment, nil
+	return &todoItem, nil
 }
 
-func (r *TodoRepository) GetTodoAttachments(
-	ctx context.Context,
-	todoID uuid.UUID,
-) ([]todo.TodoAttachment, error) {
+// DeleteTodo removes a todo and returns it as it was
+// RecordTodoView marks the todo as viewed by the user now
+func (r *TodoRepository) RecordTodoView(ctx context.Context, userID string, todoID uuid.UUID) error {
 	stmt := `
+		INSERT INTO
+			todo_views (todo_id, user_id)
 		SELECT
-			*
+			id,
+			user_id
 		FROM
-			todo_attachments
+			todos
 		WHERE
-			todo_id = @todo_id
-		ORDER BY
-			created_at DESC
+			id=@todo_id
+			AND user_id=@user_id
+		ON CONFLICT (todo_id, user_id) DO UPDATE
+		SET
+			viewed_at=CURRENT_TIMESTAMP,
+			view_count=todo_views.view_count + 1
 	`
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+	tag, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
 		"todo_id": todoID,
+		"user_id": userID,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get todo attachments: %w", err)
+		return fmt.Errorf("failed to execute record todo view query for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
 	}
 
-	attachments, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.TodoAttachment])
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return []todo.TodoAttachment{}, nil
-		}
-		return nil, fmt.Errorf("failed to collect rows from table:todo_attachments: %w", err)
+	if tag.RowsAffected() == 0 {
+		code := errs.CodeTodoNotFound
+		return errs.NewNotFoundError("todo not found", false, &code)
 	}
 
-	return attachments, nil
+	return nil
 }
 
-func (r *TodoRepository) DeleteTodoAttachment(
-	ctx context.Context,
-	todoID uuid.UUID,
-	attachmentID uuid.UUID,
-) error {
+// GetTodoViews returns when the user last viewed each of the todos, those never viewed are left out
+func (r *TodoRepository) GetTodoViews(ctx context.Context, userID string, todoIDs []uuid.UUID) (map[uuid.UUID]time.Time, error) {
 	stmt := `
-		DELETE FROM todo_attachments
+		SELECT
+			todo_id,
+			viewed_at
+		FROM
+			todo_views
 		WHERE
-			todo_id = @todo_id
-			AND id = @attachment_id
+			user_id=@user_id
+			AND todo_id=ANY(@todo_ids)
 	`
 
-	result, err := r.server.DB.Pool.Exec(ctx, stmt, pgx.NamedArgs{
-		"todo_id":       todoID,
-		"attachment_id": attachmentID,
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":  userID,
+		"todo_ids": todoIDs,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to delete todo attachment: %w", err)
+		return nil, fmt.Errorf("failed to execute get todo views query for user_id=%s: %w", userID, err)
 	}
-
-	if result.RowsAffected() == 0 {
-		code := "ATTACHMENT_NOT_FOUND"
-		return errs.NewNotFoundError("attachment not found", false, &code)
+	defer rows.Close()
+
+	views := make(map[uuid.UUID]time.Time, len(todoIDs))
+	for rows.Next() {
+		var todoID uuid.UUID
+		var viewedAt time.Time
+		if err := rows.Scan(&todoID, &viewedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row from table:todo_views for user_id=%s: %w", userID, err)
+		}
+		views[todoID] = viewedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows from table:todo_views for user_id=%s: %w", userID, err)
 	}
 
-	return nil
+	return views, nil
 }
 
-func (r *TodoRepository) UploadTodoAttachment(
-	ctx context.Context,
-	todoID uuid.UUID,
-	userID string,
-	s3Key string,
-	fileName string,
-	fileSize int64,
-	mimeType string,
-) (*todo.TodoAttachment, error) {
+func (r *TodoRepository) DeleteTodo(ctx context.Context, userID string, todoID uuid.UUID) (*todo.Todo, error) {
 	stmt := `
-		INSERT INTO
-			todo_attachments (
-				todo_id,
-				name,
-				uploaded_by,
-				download_key,
-				file_size,
-				mime_type
-			)
-		VALUES
-			(
-				@todo_id,
-				@name,
-				@uploaded_by,
-				@download_key,
-				@file_size,
-				@mime_type
-			)
+		DELETE FROM todos
+		WHERE
+			id=@todo_id
+			AND user_id=@user_id
 		RETURNING
 			*
 	`
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
-		"todo_id":      todoID,
-		"name":         fileName,
-		"uploaded_by":  userID,
-		"download_key": s3Key,
-		"file_size":    fileSize,
-		"mime_type":    mimeType,
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id": todoID,
+		"user_id": userID,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create todo attachment for todo_id=%s: %w", todoID.String(), err)
+		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
 
-	attachment, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.TodoAttachment])
+	deletedTodo, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.Todo])
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeTodoNotFound
+			return nil, errs.NewNotFoundError("todo not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:todos for todo_id=%s user_id=%s: %w", todoID.String(), userID, err)
+	}
+
+	return &deletedTodo, nil
+}
+
+// FindDuplicateTodos returns the open todos of the category (or without one when categoryID is
+// nil) whose title is at least threshold similar to the given one, most similar first
+func (r *TodoRepository) FindDuplicateTodos(ctx context.Context, userID, title string, categoryID *uuid.UUID,
+	excludeID uuid.UUID, threshold float64, limit int,
+) ([]todo.DuplicateCandidate, error) {
+	stmt := `
+		SELECT
+			id,
+			title,
+			status,
+			category_id,
+			similarity(title, @title)::DOUBLE PRECISION AS similarity
+		FROM
+			todos
+		WHERE
+			user_id=@user_id
+			AND id<>@exclude_id
+			AND status_category IN ('draft', 'active')
+			AND category_id IS NOT DISTINCT FROM @category_id
+			AND similarity(title, @title)>=@threshold
+		ORDER BY
+			similarity DESC,
+			created_at DESC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":     userID,
+		"title":       title,
+		"category_id": categoryID,
+		"exclude_id":  excludeID,
+		"threshold":   threshold,
+		"limit":       limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute find duplicate todos query for user_id=%s: %w", userID, err)
+	}
+
+	candidates, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.DuplicateCandidate])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	return candidates, nil
+}
+
+// MergeTodos moves the subtasks, comments and attachments of the source todos to the target,
+// then deletes the sources. The caller checks ownership and runs it in a transaction.
+func (r *TodoRepository) MergeTodos(ctx context.Context, userID string, targetID uuid.UUID,
+	sourceIDs []uuid.UUID,
+) error {
+	args := pgx.NamedArgs{
+		"user_id":    userID,
+		"target_id":  targetID,
+		"source_ids": sourceIDs,
+	}
+
+	stmts := []struct {
+		name string
+		stmt string
+	}{
+		{"move subtasks", `
+			UPDATE todos
+			SET
+				parent_todo_id=@target_id
+			WHERE
+				user_id=@user_id
+				AND parent_todo_id=ANY(@source_ids)
+		`},
+		{"move comments", `
+			UPDATE todo_comments
+			SET
+				todo_id=@target_id
+			WHERE
+				todo_id=ANY(@source_ids)
+		`},
+		{"move attachments", `
+			UPDATE todo_attachments
+			SET
+				todo_id=@target_id
+			WHERE
+				todo_id=ANY(@source_ids)
+		`},
+		{"move focus sessions", `
+			UPDATE focus_sessions
+			SET
+				todo_id=@target_id
+			WHERE
+				user_id=@user_id
+				AND todo_id=ANY(@source_ids)
+		`},
+		{"delete sources", `
+			DELETE FROM todos
+			WHERE
+				user_id=@user_id
+				AND id=ANY(@source_ids)
+		`},
+	}
+
+	// The notes go first, deleting the sources takes theirs along
+	if err := r.mergeNotes(ctx, userID, targetID, sourceIDs); err != nil {
+		return err
+	}
+
+	for _, s := range stmts {
+		if _, err := r.server.DB.Writer(ctx).Exec(ctx, s.stmt, args); err != nil {
+			return fmt.Errorf("failed to %s merging into todo_id=%s: %w", s.name, targetID.String(), err)
+		}
+	}
+
+	return nil
+}
+
+// mergeNotes appends the private notes on the sources to the note on the target, oldest first.
+// The bodies are sealed, they are joined here rather than by the database.
+func (r *TodoRepository) mergeNotes(ctx context.Context, userID string, targetID uuid.UUID,
+	sourceIDs []uuid.UUID,
+) error {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_private_notes
+		WHERE
+			user_id=@user_id
+			AND (
+				todo_id=@target_id
+				OR todo_id=ANY(@source_ids)
+			)
+		ORDER BY
+			todo_id<>@target_id,
+			created_at ASC
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":    userID,
+		"target_id":  targetID,
+		"source_ids": sourceIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute get private notes to merge query for todo_id=%s: %w", targetID.String(), err)
+	}
+
+	notes, err := pgx.CollectRows(rows, pgx.RowToStructByName[note.Note])
+	if err != nil {
+		return fmt.Errorf("failed to collect rows from table:todo_private_notes for todo_id=%s: %w", targetID.String(), err)
+	}
+	if len(notes) == 0 || (len(notes) == 1 && notes[0].TodoID == targetID) {
+		return nil
+	}
+
+	bodies := make([]string, len(notes))
+	for i := range notes {
+		if err := openNote(ctx, r.keyring, &notes[i]); err != nil {
+			return err
+		}
+		bodies[i] = notes[i].Body
+	}
+
+	sealed, err := r.keyring.Seal(ctx, strings.Join(bodies, "\n\n"))
+	if err != nil {
+		return fmt.Errorf("failed to seal private note for todo_id=%s: %w", targetID.String(), err)
+	}
+
+	upsert := `
+		INSERT INTO
+			todo_private_notes (todo_id, user_id, body)
+		VALUES
+			(@todo_id, @user_id, @body)
+		ON CONFLICT (todo_id, user_id) DO UPDATE
+		SET
+			body=EXCLUDED.body
+	`
+
+	if _, err := r.server.DB.Writer(ctx).Exec(ctx, upsert, pgx.NamedArgs{
+		"todo_id": targetID,
+		"user_id": userID,
+		"body":    sealed,
+	}); err != nil {
+		return fmt.Errorf("failed to merge private notes into todo_id=%s: %w", targetID.String(), err)
+	}
+
+	return nil
+}
+
+func (r *TodoRepository) GetTodoStats(ctx context.Context, userID string) (*todo.TodoStats, error) {
+	stmt := `
+		SELECT
+			COUNT(*) AS total,
+			COUNT(
+				CASE
+					WHEN status_category='draft' THEN 1
+				END
+			) AS draft,
+			COUNT(
+				CASE
+					WHEN status_category='active' THEN 1
+				END
+			) AS active,
+			COUNT(
+				CASE
+					WHEN status_category='completed' THEN 1
+				END
+			) AS completed,
+			COUNT(
+				CASE
+					WHEN status_category='archived' THEN 1
+				END
+			) AS archived,
+			COUNT(
+				CASE
+					WHEN due_date<NOW()
+					AND status_category!='completed' THEN 1
+				END
+			) AS overdue
+		FROM
+			todos
+		WHERE
+			user_id=@user_id
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	stats, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.TodoStats])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todos: %w", err)
+	}
+
+	return &stats, nil
+}
+func (r *TodoRepository) GetTodoAttachment(
+	ctx context.Context,
+	todoID uuid.UUID,
+	attachmentID uuid.UUID,
+) (*todo.TodoAttachment, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_attachments
+		WHERE
+			todo_id = @todo_id
+			AND id = @attachment_id
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id":       todoID,
+		"attachment_id": attachmentID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get todo attachment: %w", err)
+	}
+
+	attachment, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.TodoAttachment])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeAttachmentNotFound
+			return nil, errs.NewNotFoundError("attachment not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:todo_attachments: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+func (r *TodoRepository) GetTodoAttachments(
+	ctx context.Context,
+	todoID uuid.UUID,
+) ([]todo.TodoAttachment, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_attachments
+		WHERE
+			todo_id = @todo_id
+		ORDER BY
+			created_at DESC
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id": todoID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get todo attachments: %w", err)
+	}
+
+	attachments, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.TodoAttachment])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return []todo.TodoAttachment{}, nil
+		}
+		return nil, fmt.Errorf("failed to collect rows from table:todo_attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+func (r *TodoRepository) DeleteTodoAttachment(
+	ctx context.Context,
+	todoID uuid.UUID,
+	attachmentID uuid.UUID,
+) error {
+	stmt := `
+		DELETE FROM todo_attachments
+		WHERE
+			todo_id = @todo_id
+			AND id = @attachment_id
+	`
+
+	result, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"todo_id":       todoID,
+		"attachment_id": attachmentID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete todo attachment: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		code := errs.CodeAttachmentNotFound
+		return errs.NewNotFoundError("attachment not found", false, &code)
+	}
+
+	return nil
+}
+
+func (r *TodoRepository) UploadTodoAttachment(
+	ctx context.Context,
+	todoID uuid.UUID,
+	userID string,
+	s3Key string,
+	fileName string,
+	fileSize int64,
+	mimeType string,
+	scanStatus *todo.ScanStatus,
+) (*todo.TodoAttachment, error) {
+	stmt := `
+		INSERT INTO
+			todo_attachments (
+				todo_id,
+				name,
+				uploaded_by,
+				download_key,
+				file_size,
+				mime_type,
+				scan_status
+			)
+		VALUES
+			(
+				@todo_id,
+				@name,
+				@uploaded_by,
+				@download_key,
+				@file_size,
+				@mime_type,
+				@scan_status
+			)
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id":      todoID,
+		"name":         fileName,
+		"uploaded_by":  userID,
+		"download_key": s3Key,
+		"file_size":    fileSize,
+		"mime_type":    mimeType,
+		"scan_status":  scanStatus,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create todo attachment for todo_id=%s: %w", todoID.String(), err)
+	}
+
+	attachment, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.TodoAttachment])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:todo_attachments: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+// SetAttachmentScan records the outcome of the virus scan of an attachment
+func (r *TodoRepository) SetAttachmentScan(
+	ctx context.Context,
+	todoID uuid.UUID,
+	attachmentID uuid.UUID,
+	status todo.ScanStatus,
+	signature *string,
+) (*todo.TodoAttachment, error) {
+	stmt := `
+		UPDATE todo_attachments
+		SET
+			scan_status = @scan_status,
+			scan_signature = @scan_signature,
+			scanned_at = CURRENT_TIMESTAMP
+		WHERE
+			todo_id = @todo_id
+			AND id = @attachment_id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id":        todoID,
+		"attachment_id":  attachmentID,
+		"scan_status":    status,
+		"scan_signature": signature,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update scan of attachment_id=%s: %w", attachmentID.String(), err)
+	}
+
+	attachment, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.TodoAttachment])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeAttachmentNotFound
+			return nil, errs.NewNotFoundError("attachment not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:todo_attachments: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+// SetAttachmentTranscription records where the transcription of a voice note stands, the
+// transcript is only set once it completed
+func (r *TodoRepository) SetAttachmentTranscription(
+	ctx context.Context,
+	todoID uuid.UUID,
+	attachmentID uuid.UUID,
+	status todo.TranscriptionStatus,
+	transcript *string,
+) (*todo.TodoAttachment, error) {
+	stmt := `
+		UPDATE todo_attachments
+		SET
+			transcription_status = @transcription_status,
+			transcript = @transcript
+		WHERE
+			todo_id = @todo_id
+			AND id = @attachment_id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id":              todoID,
+		"attachment_id":        attachmentID,
+		"transcription_status": status,
+		"transcript":           transcript,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update transcription of attachment_id=%s: %w", attachmentID.String(), err)
+	}
+
+	attachment, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.TodoAttachment])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeAttachmentNotFound
+			return nil, errs.NewNotFoundError("attachment not found", false, &code)
+		}
 		return nil, fmt.Errorf("failed to collect row from table:todo_attachments: %w", err)
 	}
 
-	return &attachment, nil
+	return &attachment, nil
+}
+
+// SetAttachmentProcessed records that the metadata of an image was stripped, the size is that
+// of the object stripped
+func (r *TodoRepository) SetAttachmentProcessed(
+	ctx context.Context,
+	todoID uuid.UUID,
+	attachmentID uuid.UUID,
+	fileSize int64,
+	thumbnailKey *string,
+) (*todo.TodoAttachment, error) {
+	stmt := `
+		UPDATE todo_attachments
+		SET
+			file_size = @file_size,
+			thumbnail_key = @thumbnail_key,
+			processed_at = CURRENT_TIMESTAMP
+		WHERE
+			todo_id = @todo_id
+			AND id = @attachment_id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"todo_id":       todoID,
+		"attachment_id": attachmentID,
+		"file_size":     fileSize,
+		"thumbnail_key": thumbnailKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update processing of attachment_id=%s: %w", attachmentID.String(), err)
+	}
+
+	attachment, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[todo.TodoAttachment])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeAttachmentNotFound
+			return nil, errs.NewNotFoundError("attachment not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:todo_attachments: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+// GetUserAttachmentBytes sums the attachment sizes across the todos of a user
+func (r *TodoRepository) GetUserAttachmentBytes(ctx context.Context, userID string) (int64, error) {
+	stmt := `
+		SELECT
+			COALESCE(SUM(a.file_size), 0)::BIGINT
+		FROM
+			todo_attachments a
+			JOIN todos t ON t.id=a.todo_id
+		WHERE
+			t.user_id=@user_id
+	`
+
+	var bytes int64
+	err := r.server.DB.Writer(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	}).Scan(&bytes)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get attachment bytes for user_id=%s: %w", userID, err)
+	}
+
+	return bytes, nil
+}
+
+// GetUserQuotaBytes returns the storage quota an admin set for a user, nil when the user follows
+// the config
+func (r *TodoRepository) GetUserQuotaBytes(ctx context.Context, userID string) (*int64, error) {
+	stmt := `
+		SELECT
+			quota_bytes
+		FROM
+			storage_quotas
+		WHERE
+			user_id=@user_id
+	`
+
+	var quotaBytes int64
+	err := r.server.DB.Writer(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+	}).Scan(&quotaBytes)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get storage quota for user_id=%s: %w", userID, err)
+	}
+
+	return &quotaBytes, nil
+}
+
+// GetRecordedAttachmentKeys returns those of the download keys an attachment record points at
+func (r *TodoRepository) GetRecordedAttachmentKeys(ctx context.Context, downloadKeys []string) ([]string, error) {
+	stmt := `
+		SELECT
+			download_key
+		FROM
+			todo_attachments
+		WHERE
+			download_key = ANY(@download_keys)
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"download_keys": downloadKeys,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recorded attachment keys: %w", err)
+	}
+
+	keys, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_attachments: %w", err)
+	}
+
+	return keys, nil
+}
+
+// GetRecordedThumbnailKeys returns those of the thumbnail keys an attachment record points at
+func (r *TodoRepository) GetRecordedThumbnailKeys(ctx context.Context, thumbnailKeys []string) ([]string, error) {
+	stmt := `
+		SELECT
+			thumbnail_key
+		FROM
+			todo_attachments
+		WHERE
+			thumbnail_key = ANY(@thumbnail_keys)
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"thumbnail_keys": thumbnailKeys,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recorded thumbnail keys: %w", err)
+	}
+
+	keys, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_attachments: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (r *TodoRepository) GetTodosUpdatedSince(ctx context.Context, userID string, since time.Time, limit int) ([]todo.Todo, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todos
+		WHERE
+			user_id = @user_id
+			AND updated_at > @since
+		ORDER BY
+			updated_at ASC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"since":   since,
+		"limit":   limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get todos updated since query for user_id=%s: %w", userID, err)
+	}
+
+	todos, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return []todo.Todo{}, nil
+		}
+		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	return todos, nil
+}
+
+// GetRecentTodos returns the latest todos created by the user, newest first
+func (r *TodoRepository) GetRecentTodos(ctx context.Context, userID string, limit int) ([]todo.Todo, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todos
+		WHERE
+			user_id = @user_id
+		ORDER BY
+			created_at DESC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"limit":   limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get recent todos query for user_id=%s: %w", userID, err)
+	}
+
+	todos, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	return todos, nil
+}
+
+// GetTodosDueBetween returns the todos of the user due in [from, to) that aren't archived, soonest first
+func (r *TodoRepository) GetTodosDueBetween(ctx context.Context, userID string, from, to time.Time) ([]todo.Todo, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todos
+		WHERE
+			user_id = @user_id
+			AND due_date >= @from
+			AND due_date < @to
+			AND status_category <> 'archived'
+		ORDER BY
+			due_date ASC,
+			sort_order ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"from":    from,
+		"to":      to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get todos due between query for user_id=%s: %w", userID, err)
+	}
+
+	todos, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	return todos, nil
+}
+
+// GetUnscheduledTodos returns the open todos of the user without a due date, high priority first
+func (r *TodoRepository) GetUnscheduledTodos(ctx context.Context, userID string, limit int) ([]todo.Todo, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todos
+		WHERE
+			user_id = @user_id
+			AND due_date IS NULL
+			AND status_category IN ('draft', 'active')
+		ORDER BY
+			priority_level DESC,
+			sort_order ASC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"limit":   limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get unscheduled todos query for user_id=%s: %w", userID, err)
+	}
+
+	todos, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	return todos, nil
+}
+
+// GetOpenTodos returns the draft and active todos of the user, soonest due first and those
+// without a due date last
+func (r *TodoRepository) GetOpenTodos(ctx context.Context, userID string, limit int) ([]todo.Todo, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todos
+		WHERE
+			user_id = @user_id
+			AND status_category IN ('draft', 'active')
+		ORDER BY
+			due_date ASC NULLS LAST,
+			sort_order ASC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"limit":   limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get open todos query for user_id=%s: %w", userID, err)
+	}
+
+	todos, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	return todos, nil
+}
+
+// GetRecentlyCompletedTodos returns the completed todos of the user, latest completed first
+func (r *TodoRepository) GetRecentlyCompletedTodos(ctx context.Context, userID string, limit int) ([]todo.Todo, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todos
+		WHERE
+			user_id = @user_id
+			AND status_category = 'completed'
+			AND completed_at IS NOT NULL
+		ORDER BY
+			completed_at DESC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"limit":   limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get recently completed todos query for user_id=%s: %w", userID, err)
+	}
+
+	todos, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	return todos, nil
+}
+
+func (r *TodoRepository) GetChildrenByParentIDs(ctx context.Context, userID string, parentIDs []uuid.UUID) ([]todo.Todo, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todos
+		WHERE
+			user_id = @user_id
+			AND parent_todo_id = ANY(@parent_ids)
+		ORDER BY
+			sort_order ASC,
+			created_at ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"user_id":    userID,
+		"parent_ids": parentIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get children by parent ids query for user_id=%s: %w", userID, err)
+	}
+
+	children, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.Todo])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return []todo.Todo{}, nil
+		}
+		return nil, fmt.Errorf("failed to collect rows from table:todos for user_id=%s: %w", userID, err)
+	}
+
+	return children, nil
+}
+
+func (r *TodoRepository) GetAttachmentsByTodoIDs(ctx context.Context, todoIDs []uuid.UUID) ([]todo.TodoAttachment, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			todo_attachments
+		WHERE
+			todo_id = ANY(@todo_ids)
+		ORDER BY
+			created_at DESC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"todo_ids": todoIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments by todo ids: %w", err)
+	}
+
+	attachments, err := pgx.CollectRows(rows, pgx.RowToStructByName[todo.TodoAttachment])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return []todo.TodoAttachment{}, nil
+		}
+		return nil, fmt.Errorf("failed to collect rows from table:todo_attachments: %w", err)
+	}
+
+	return attachments, nil
 }
 
 // CRON REQUIREMENTS
@@ -658,7 +1722,7 @@ func (r *TodoRepository) GetTodosDueInHours(ctx context.Context, hours int, limi
 			due_date IS NOT NULL
 			AND due_date > NOW()
 			AND due_date <= NOW() + INTERVAL '%d hours'
-			AND status NOT IN ('completed', 'archived')
+			AND status_category NOT IN ('completed', 'archived')
 		ORDER BY
 			due_date ASC
 		LIMIT
@@ -666,7 +1730,7 @@ func (r *TodoRepository) GetTodosDueInHours(ctx context.Context, hours int, limi
 	`
 
 	query := fmt.Sprintf(stmt, hours, limit)
-	rows, err := r.server.DB.Pool.Query(ctx, query)
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute get todos due in %d hours query: %w", hours, err)
 	}
@@ -691,14 +1755,14 @@ func (r *TodoRepository) GetOverdueTodos(ctx context.Context, limit int) ([]todo
 		WHERE
 			due_date IS NOT NULL
 			AND due_date < NOW()
-			AND status NOT IN ('completed', 'archived')
+			AND status_category NOT IN ('completed', 'archived')
 		ORDER BY
 			due_date ASC
 		LIMIT
 			@limit
 	`
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
 		"limit": limit,
 	})
 	if err != nil {
@@ -723,7 +1787,7 @@ func (r *TodoRepository) GetCompletedTodosOlderThan(ctx context.Context, cutoffD
 		FROM
 			todos
 		WHERE
-			status = 'completed'
+			status_category = 'completed'
 			AND completed_at IS NOT NULL
 			AND completed_at < @cutoff_date
 		ORDER BY
@@ -732,7 +1796,7 @@ func (r *TodoRepository) GetCompletedTodosOlderThan(ctx context.Context, cutoffD
 			@limit
 	`
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
 		"cutoff_date": cutoffDate,
 		"limit":       limit,
 	})
@@ -751,17 +1815,20 @@ func (r *TodoRepository) GetCompletedTodosOlderThan(ctx context.Context, cutoffD
 	return todos, nil
 }
 
-func (r *TodoRepository) ArchiveTodos(ctx context.Context, todoIDs []uuid.UUID) error {
+// ArchiveTodos archives the given todos, userIDs are their owners so that only their partitions are scanned
+func (r *TodoRepository) ArchiveTodos(ctx context.Context, todoIDs []uuid.UUID, userIDs []string) error {
 	stmt := `
 		UPDATE todos
 		SET
 			status = 'archived'
 		WHERE
 			id = ANY(@todo_ids::uuid[])
+			AND user_id = ANY(@user_ids::text[])
 	`
 
-	result, err := r.server.DB.Pool.Exec(ctx, stmt, pgx.NamedArgs{
+	result, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
 		"todo_ids": todoIDs,
+		"user_ids": userIDs,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to archive todos: %w", err)
@@ -774,14 +1841,56 @@ func (r *TodoRepository) ArchiveTodos(ctx context.Context, todoIDs []uuid.UUID)
 	return nil
 }
 
+// ApplyWorkflow moves the todos of the workspace onto its scales after they changed. The
+// mappings rename keys, old to new, and the resolve_workflow_todos trigger settles the todos
+// left on a key the scales no longer have. Only the todos whose keys, category or level change
+// are written, it returns how many.
+func (r *TodoRepository) ApplyWorkflow(ctx context.Context, workspaceID string,
+	statusMapping, priorityMapping map[string]string,
+) (int, error) {
+	stmt := `
+		WITH
+			scales AS (
+				SELECT
+					workflow_statuses(@workspace_id) AS statuses,
+					workflow_priorities(@workspace_id) AS priorities
+			)
+		UPDATE todos t
+		SET
+			status = COALESCE(@status_mapping::JSONB ->> t.status, t.status),
+			priority = COALESCE(@priority_mapping::JSONB ->> t.priority, t.priority)
+		FROM
+			scales s
+		WHERE
+			t.workspace_id = @workspace_id
+			AND (
+				@status_mapping::JSONB ? t.status
+				OR @priority_mapping::JSONB ? t.priority
+				OR workflow_status_category(s.statuses, t.status) IS DISTINCT FROM t.status_category
+				OR workflow_priority_level(s.priorities, t.priority) IS DISTINCT FROM t.priority_level
+			)
+	`
+
+	result, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"workspace_id":     workspaceID,
+		"status_mapping":   statusMapping,
+		"priority_mapping": priorityMapping,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to apply workflow to todos for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
 func (r *TodoRepository) GetWeeklyStatsForUsers(ctx context.Context, startDate, endDate time.Time) ([]todo.UserWeeklyStats, error) {
 	stmt := `
 		SELECT
 			user_id,
 			COUNT(*) FILTER (WHERE created_at >= @start_date AND created_at <= @end_date) AS created_count,
-			COUNT(*) FILTER (WHERE status = 'completed' AND completed_at >= @start_date AND completed_at <= @end_date) AS completed_count,
-			COUNT(*) FILTER (WHERE status NOT IN ('completed', 'archived')) AS active_count,
-			COUNT(*) FILTER (WHERE due_date < NOW() AND status NOT IN ('completed', 'archived')) AS overdue_count
+			COUNT(*) FILTER (WHERE status_category = 'completed' AND completed_at >= @start_date AND completed_at <= @end_date) AS completed_count,
+			COUNT(*) FILTER (WHERE status_category NOT IN ('completed', 'archived')) AS active_count,
+			COUNT(*) FILTER (WHERE due_date < NOW() AND status_category NOT IN ('completed', 'archived')) AS overdue_count
 		FROM
 			todos
 		GROUP BY
@@ -790,7 +1899,7 @@ func (r *TodoRepository) GetWeeklyStatsForUsers(ctx context.Context, startDate,
 			COUNT(*) > 0
 	`
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
 		"start_date": startDate,
 		"end_date":   endDate,
 	})
@@ -860,7 +1969,7 @@ func (r *TodoRepository) GetCompletedTodosForUser(ctx context.Context, userID st
 			LEFT JOIN todo_attachments att ON att.todo_id=t.id
 		WHERE
 			t.user_id = @user_id
-			AND t.status = 'completed'
+			AND t.status_category = 'completed'
 			AND t.completed_at >= @start_date
 			AND t.completed_at <= @end_date
 		GROUP BY
@@ -870,7 +1979,7 @@ func (r *TodoRepository) GetCompletedTodosForUser(ctx context.Context, userID st
 		LIMIT 10
 	`
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
 		"user_id":    userID,
 		"start_date": startDate,
 		"end_date":   endDate,
@@ -940,7 +2049,7 @@ func (r *TodoRepository) GetOverdueTodosForUser(ctx context.Context, userID stri
 		WHERE
 			t.user_id = @user_id
 			AND t.due_date < NOW()
-			AND t.status NOT IN ('completed', 'archived')
+			AND t.status_category NOT IN ('completed', 'archived')
 		GROUP BY
 			t.id, c.id
 		ORDER BY
@@ -948,7 +2057,7 @@ func (r *TodoRepository) GetOverdueTodosForUser(ctx context.Context, userID stri
 		LIMIT 10
 	`
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
 		"user_id": userID,
 	})
 	if err != nil {
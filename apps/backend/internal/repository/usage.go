@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/usage"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type UsageRepository struct {
+	server *server.Server
+}
+
+func NewUsageRepository(server *server.Server) *UsageRepository {
+	return &UsageRepository{server: server}
+}
+
+// UpsertHourly stores rolled up usage. Quantities replace what is stored, so rolling up an hour
+// again corrects it instead of counting it twice.
+func (r *UsageRepository) UpsertHourly(ctx context.Context, records []usage.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	hours := make([]time.Time, len(records))
+	subjectTypes := make([]string, len(records))
+	subjectIDs := make([]string, len(records))
+	metrics := make([]string, len(records))
+	quantities := make([]int64, len(records))
+	for i, record := range records {
+		hours[i] = record.Hour
+		subjectTypes[i] = string(record.SubjectType)
+		subjectIDs[i] = record.SubjectID
+		metrics[i] = string(record.Metric)
+		quantities[i] = record.Quantity
+	}
+
+	stmt := `
+		INSERT INTO
+			usage_hourly (hour, subject_type, subject_id, metric, quantity)
+		SELECT
+			*
+		FROM
+			UNNEST(@hours::timestamptz[], @subject_types::text[], @subject_ids::text[], @metrics::text[], @quantities::bigint[])
+		ON CONFLICT (subject_type, subject_id, metric, hour) DO UPDATE
+		SET
+			quantity=EXCLUDED.quantity,
+			updated_at=NOW()
+	`
+
+	_, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"hours":         hours,
+		"subject_types": subjectTypes,
+		"subject_ids":   subjectIDs,
+		"metrics":       metrics,
+		"quantities":    quantities,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert %d usage records: %w", len(records), err)
+	}
+
+	return nil
+}
+
+// SnapshotStorage stores the attachment bytes of every todo owner for an hour. Owners who had
+// storage the hour before get an explicit zero once their attachments are gone.
+func (r *UsageRepository) SnapshotStorage(ctx context.Context, hour time.Time) (int64, error) {
+	stmt := `
+		WITH
+			storage AS (
+				SELECT
+					t.user_id,
+					SUM(COALESCE(a.file_size, 0))::BIGINT AS bytes
+				FROM
+					todo_attachments a
+					JOIN todos t ON t.id=a.todo_id
+				GROUP BY
+					t.user_id
+			),
+			owners AS (
+				SELECT
+					user_id
+				FROM
+					storage
+				UNION
+				SELECT
+					subject_id
+				FROM
+					usage_hourly
+				WHERE
+					subject_type='user'
+					AND metric='storage_bytes'
+					AND hour=@previous_hour
+					AND quantity>0
+			)
+		INSERT INTO
+			usage_hourly (hour, subject_type, subject_id, metric, quantity)
+		SELECT
+			@hour::timestamptz,
+			'user',
+			o.user_id,
+			'storage_bytes',
+			COALESCE(s.bytes, 0)
+		FROM
+			owners o
+			LEFT JOIN storage s ON s.user_id=o.user_id
+		ON CONFLICT (subject_type, subject_id, metric, hour) DO UPDATE
+		SET
+			quantity=EXCLUDED.quantity,
+			updated_at=NOW()
+	`
+
+	result, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"hour":          hour,
+		"previous_hour": hour.Add(-time.Hour),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to snapshot storage usage for hour=%s: %w", hour.Format(time.RFC3339), err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// GetBuckets returns the usage of a subject per hour or day in [from, to), oldest first.
+// Buckets without usage are left out.
+func (r *UsageRepository) GetBuckets(ctx context.Context, subjectType usage.SubjectType, subjectID string,
+	from, to time.Time, granularity usage.Granularity,
+) ([]usage.Bucket, error) {
+	stmt := `
+		SELECT
+			DATE_TRUNC(@granularity::text, hour AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS start,
+			COALESCE(SUM(quantity) FILTER (WHERE metric='api_calls'), 0)::BIGINT AS api_calls,
+			COALESCE(SUM(quantity) FILTER (WHERE metric='notifications'), 0)::BIGINT AS notifications,
+			(ARRAY_AGG(quantity ORDER BY hour DESC) FILTER (WHERE metric='storage_bytes'))[1] AS storage_bytes
+		FROM
+			usage_hourly
+		WHERE
+			subject_type=@subject_type
+			AND subject_id=@subject_id
+			AND hour>=@from
+			AND hour<@to
+		GROUP BY
+			1
+		ORDER BY
+			1 ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"granularity":  string(granularity),
+		"subject_type": string(subjectType),
+		"subject_id":   subjectID,
+		"from":         from,
+		"to":           to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get usage query for %s=%s: %w", subjectType, subjectID, err)
+	}
+
+	buckets, err := pgx.CollectRows(rows, pgx.RowToStructByName[usage.Bucket])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:usage_hourly for %s=%s: %w", subjectType, subjectID, err)
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/envelope"
+	"github.com/Sameer16536/ExecuTask/internal/model/webhook"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// WebhookRepository seals the secrets of the endpoints with the keyring, see sealedColumns
+type WebhookRepository struct {
+	server  *server.Server
+	keyring *envelope.Keyring
+}
+
+func NewWebhookRepository(server *server.Server, keyring *envelope.Keyring) *WebhookRepository {
+	return &WebhookRepository{server: server, keyring: keyring}
+}
+
+// GetEndpoints returns the endpoints of the workspace, oldest first
+func (r *WebhookRepository) GetEndpoints(ctx context.Context, workspaceID string) ([]webhook.Endpoint, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			webhook_endpoints
+		WHERE
+			workspace_id = @workspace_id
+		ORDER BY
+			created_at ASC
+	`
+
+	return r.endpointRows(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	})
+}
+
+func (r *WebhookRepository) GetEndpoint(ctx context.Context, workspaceID string,
+	endpointID uuid.UUID,
+) (*webhook.Endpoint, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			webhook_endpoints
+		WHERE
+			id = @id
+			AND workspace_id = @workspace_id
+	`
+
+	return r.endpointRow(ctx, "get webhook endpoint", stmt, pgx.NamedArgs{
+		"id":           endpointID,
+		"workspace_id": workspaceID,
+	})
+}
+
+func (r *WebhookRepository) GetSubscribedEndpoints(ctx context.Context, workspaceID,
+	event string,
+) ([]webhook.Endpoint, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			webhook_endpoints
+		WHERE
+			workspace_id = @workspace_id
+			AND enabled
+			AND @event = ANY(events)
+	`
+
+	return r.endpointRows(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+		"event":        event,
+	})
+}
+
+func (r *WebhookRepository) CountEndpoints(ctx context.Context, workspaceID string) (int, error) {
+	stmt := `
+		SELECT
+			COUNT(*)
+		FROM
+			webhook_endpoints
+		WHERE
+			workspace_id = @workspace_id
+	`
+
+	var count int
+	err := r.server.DB.Reader(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	}).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count webhook endpoints for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	return count, nil
+}
+
+func (r *WebhookRepository) CreateEndpoint(ctx context.Context, workspaceID, createdBy, secret string,
+	payload *webhook.CreateEndpointPayload,
+) (*webhook.Endpoint, error) {
+	sealed, err := r.keyring.Seal(ctx, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to seal webhook secret for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	stmt := `
+		INSERT INTO
+			webhook_endpoints (workspace_id, url, secret, events, enabled, created_by)
+		VALUES
+			(@workspace_id, @url, @secret, @events, @enabled, @created_by)
+		RETURNING
+			*
+	`
+
+	return r.endpointRow(ctx, "create webhook endpoint", stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+		"url":          payload.URL,
+		"secret":       sealed,
+		"events":       payload.Events,
+		"enabled":      *payload.Enabled,
+		"created_by":   createdBy,
+	})
+}
+
+func (r *WebhookRepository) UpdateEndpoint(ctx context.Context, workspaceID string,
+	payload *webhook.UpdateEndpointPayload,
+) (*webhook.Endpoint, error) {
+	args := pgx.NamedArgs{
+		"id":           payload.ID,
+		"workspace_id": workspaceID,
+	}
+	setClauses := []string{}
+
+	if payload.URL != nil {
+		setClauses = append(setClauses, "url = @url")
+		args["url"] = *payload.URL
+	}
+	if payload.Events != nil {
+		setClauses = append(setClauses, "events = @events")
+		args["events"] = payload.Events
+	}
+	if payload.Enabled != nil {
+		setClauses = append(setClauses, "enabled = @enabled")
+		args["enabled"] = *payload.Enabled
+	}
+
+	if len(setClauses) == 0 {
+		return nil, errs.NewBadRequestError("no fields to update", false, nil, nil, nil)
+	}
+
+	stmt := `UPDATE webhook_endpoints SET ` + strings.Join(setClauses, ", ") +
+		` WHERE id = @id AND workspace_id = @workspace_id RETURNING *`
+
+	return r.endpointRow(ctx, "update webhook endpoint", stmt, args)
+}
+
+func (r *WebhookRepository) DeleteEndpoint(ctx context.Context, workspaceID string,
+	endpointID uuid.UUID,
+) (*webhook.Endpoint, error) {
+	stmt := `
+		DELETE FROM webhook_endpoints
+		WHERE
+			id = @id
+			AND workspace_id = @workspace_id
+		RETURNING
+			*
+	`
+
+	return r.endpointRow(ctx, "delete webhook endpoint", stmt, pgx.NamedArgs{
+		"id":           endpointID,
+		"workspace_id": workspaceID,
+	})
+}
+
+func (r *WebhookRepository) endpointRows(ctx context.Context, stmt string, args pgx.NamedArgs,
+) ([]webhook.Endpoint, error) {
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get webhook endpoints query for workspace_id=%v: %w",
+			args["workspace_id"], err)
+	}
+
+	endpoints, err := pgx.CollectRows(rows, pgx.RowToStructByName[webhook.Endpoint])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:webhook_endpoints for workspace_id=%v: %w",
+			args["workspace_id"], err)
+	}
+
+	for i := range endpoints {
+		if err := openSecret(ctx, r.keyring, &endpoints[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return endpoints, nil
+}
+
+func (r *WebhookRepository) endpointRow(ctx context.Context, operation, stmt string,
+	args pgx.NamedArgs,
+) (*webhook.Endpoint, error) {
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s query for webhook_endpoint_id=%v: %w", operation, args["id"], err)
+	}
+
+	endpoint, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[webhook.Endpoint])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeWebhookNotFound
+			return nil, errs.NewNotFoundError("webhook endpoint not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:webhook_endpoints for webhook_endpoint_id=%v: %w",
+			args["id"], err)
+	}
+
+	if err := openSecret(ctx, r.keyring, &endpoint); err != nil {
+		return nil, err
+	}
+
+	return &endpoint, nil
+}
+
+// openSecret replaces the sealed secret of an endpoint read from the table with its plaintext
+func openSecret(ctx context.Context, keyring *envelope.Keyring, endpoint *webhook.Endpoint) error {
+	secret, err := keyring.Decrypt(ctx, endpoint.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to open webhook secret for webhook_endpoint_id=%s: %w", endpoint.ID.String(), err)
+	}
+
+	endpoint.Secret = secret
+	return nil
+}
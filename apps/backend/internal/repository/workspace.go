@@ -0,0 +1,1239 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/workspace"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type WorkspaceRepository struct {
+	server *server.Server
+}
+
+func NewWorkspaceRepository(server *server.Server) *WorkspaceRepository {
+	return &WorkspaceRepository{server: server}
+}
+
+func (r *WorkspaceRepository) GetTemplates(ctx context.Context, workspaceID string) ([]workspace.Template, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			workspace_templates
+		WHERE
+			workspace_id = @workspace_id
+		ORDER BY
+			name ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get templates query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	templates, err := pgx.CollectRows(rows, pgx.RowToStructByName[workspace.Template])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:workspace_templates for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	return templates, nil
+}
+
+func (r *WorkspaceRepository) GetTemplate(ctx context.Context, workspaceID string, templateID uuid.UUID,
+) (*workspace.Template, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			workspace_templates
+		WHERE
+			id = @id
+			AND workspace_id = @workspace_id
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"id":           templateID,
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get template query for template_id=%s: %w", templateID.String(), err)
+	}
+
+	template, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workspace.Template])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeTemplateNotFound
+			return nil, errs.NewNotFoundError("template not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_templates for template_id=%s: %w",
+			templateID.String(), err)
+	}
+
+	return &template, nil
+}
+
+func (r *WorkspaceRepository) CreateTemplate(ctx context.Context, createdBy string,
+	payload *workspace.CreateTemplatePayload,
+) (*workspace.Template, error) {
+	stmt := `
+		INSERT INTO
+			workspace_templates (
+				workspace_id,
+				name,
+				title,
+				description,
+				priority,
+				subtasks,
+				due_in_days,
+				created_by
+			)
+		VALUES
+			(
+				@workspace_id,
+				@name,
+				@title,
+				@description,
+				@priority,
+				@subtasks,
+				@due_in_days,
+				@created_by
+			)
+		ON CONFLICT (workspace_id, name) DO NOTHING
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": payload.WorkspaceID,
+		"name":         payload.Name,
+		"title":        payload.Title,
+		"description":  payload.Description,
+		"priority":     payload.Priority,
+		"subtasks":     payload.Subtasks,
+		"due_in_days":  payload.DueInDays,
+		"created_by":   createdBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create template query for workspace_id=%s: %w", payload.WorkspaceID, err)
+	}
+
+	template, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workspace.Template])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeTemplateExists
+			return nil, errs.NewConflictError("the workspace already has a template with that name", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_templates for workspace_id=%s: %w",
+			payload.WorkspaceID, err)
+	}
+
+	return &template, nil
+}
+
+// DeleteTemplate removes a template and returns it, todos created from it are kept
+func (r *WorkspaceRepository) DeleteTemplate(ctx context.Context, workspaceID string, templateID uuid.UUID,
+) (*workspace.Template, error) {
+	stmt := `
+		DELETE FROM workspace_templates
+		WHERE
+			id = @id
+			AND workspace_id = @workspace_id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"id":           templateID,
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute delete template query for template_id=%s: %w", templateID.String(), err)
+	}
+
+	template, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workspace.Template])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeTemplateNotFound
+			return nil, errs.NewNotFoundError("template not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_templates for template_id=%s: %w",
+			templateID.String(), err)
+	}
+
+	return &template, nil
+}
+
+// GetOnboardingKit reads from the primary, a member joining right after the kit was set gets it
+func (r *WorkspaceRepository) GetOnboardingKit(ctx context.Context, workspaceID string) (*workspace.OnboardingKit, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			workspace_onboarding_kits
+		WHERE
+			workspace_id = @workspace_id
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get onboarding kit query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	kit, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workspace.OnboardingKit])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeOnboardingKitNotFound
+			return nil, errs.NewNotFoundError("the workspace has no onboarding kit", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_onboarding_kits for workspace_id=%s: %w",
+			workspaceID, err)
+	}
+
+	return &kit, nil
+}
+
+func (r *WorkspaceRepository) SetOnboardingKit(ctx context.Context, updatedBy string,
+	payload *workspace.SetOnboardingKitPayload,
+) (*workspace.OnboardingKit, error) {
+	stmt := `
+		INSERT INTO
+			workspace_onboarding_kits (workspace_id, categories, todos, updated_by)
+		VALUES
+			(@workspace_id, @categories, @todos, @updated_by)
+		ON CONFLICT (workspace_id) DO UPDATE
+		SET
+			categories = EXCLUDED.categories,
+			todos = EXCLUDED.todos,
+			updated_by = EXCLUDED.updated_by
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": payload.WorkspaceID,
+		"categories":   payload.Categories,
+		"todos":        payload.Todos,
+		"updated_by":   updatedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute set onboarding kit query for workspace_id=%s: %w", payload.WorkspaceID, err)
+	}
+
+	kit, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workspace.OnboardingKit])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:workspace_onboarding_kits for workspace_id=%s: %w",
+			payload.WorkspaceID, err)
+	}
+
+	return &kit, nil
+}
+
+func (r *WorkspaceRepository) DeleteOnboardingKit(ctx context.Context, workspaceID string) (*workspace.OnboardingKit, error) {
+	stmt := `
+		DELETE FROM workspace_onboarding_kits
+		WHERE
+			workspace_id = @workspace_id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute delete onboarding kit query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	kit, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workspace.OnboardingKit])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeOnboardingKitNotFound
+			return nil, errs.NewNotFoundError("the workspace has no onboarding kit", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_onboarding_kits for workspace_id=%s: %w",
+			workspaceID, err)
+	}
+
+	return &kit, nil
+}
+
+// GetWorkflow reads from the primary, todos written right after the workflow was set follow it
+func (r *WorkspaceRepository) GetWorkflow(ctx context.Context, workspaceID string) (*workspace.Workflow, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			workspace_workflows
+		WHERE
+			workspace_id = @workspace_id
+	`
+
+	return r.workflowRow(ctx, "get workflow", stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	})
+}
+
+func (r *WorkspaceRepository) SetWorkflow(ctx context.Context, updatedBy string,
+	payload *workspace.SetWorkflowPayload,
+) (*workspace.Workflow, error) {
+	stmt := `
+		INSERT INTO
+			workspace_workflows (workspace_id, statuses, priorities, updated_by)
+		VALUES
+			(@workspace_id, @statuses, @priorities, @updated_by)
+		ON CONFLICT (workspace_id) DO UPDATE
+		SET
+			statuses = EXCLUDED.statuses,
+			priorities = EXCLUDED.priorities,
+			updated_by = EXCLUDED.updated_by
+		RETURNING
+			*
+	`
+
+	return r.workflowRow(ctx, "set workflow", stmt, pgx.NamedArgs{
+		"workspace_id": payload.WorkspaceID,
+		"statuses":     payload.Statuses,
+		"priorities":   payload.Priorities,
+		"updated_by":   updatedBy,
+	})
+}
+
+func (r *WorkspaceRepository) DeleteWorkflow(ctx context.Context, workspaceID string) (*workspace.Workflow, error) {
+	stmt := `
+		DELETE FROM workspace_workflows
+		WHERE
+			workspace_id = @workspace_id
+		RETURNING
+			*
+	`
+
+	return r.workflowRow(ctx, "delete workflow", stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	})
+}
+
+func (r *WorkspaceRepository) workflowRow(ctx context.Context, operation, stmt string,
+	args pgx.NamedArgs,
+) (*workspace.Workflow, error) {
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s query for workspace_id=%s: %w", operation, args["workspace_id"], err)
+	}
+
+	workflow, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workspace.Workflow])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeWorkflowNotFound
+			return nil, errs.NewNotFoundError("the workspace has no workflow", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_workflows for workspace_id=%s: %w",
+			args["workspace_id"], err)
+	}
+
+	return &workflow, nil
+}
+
+// AddMember records the user as a member of the workspace, it tells whether they joined just
+// now. Of two concurrent calls only one sees the user join.
+func (r *WorkspaceRepository) AddMember(ctx context.Context, workspaceID, userID string) (bool, error) {
+	stmt := `
+		INSERT INTO
+			workspace_members (workspace_id, user_id)
+		VALUES
+			(@workspace_id, @user_id)
+		ON CONFLICT DO NOTHING
+	`
+
+	result, err := r.server.DB.Writer(ctx).Exec(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+		"user_id":      userID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to execute add workspace member query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+// GetWorkload sums the open todos of the workspace per owner: the todos due in [from, to) per
+// week of the timezone, the todos due earlier and those without a due date
+func (r *WorkspaceRepository) GetWorkload(ctx context.Context, workspaceID string, from, to time.Time,
+	location *time.Location,
+) ([]workspace.WorkloadBucket, error) {
+	stmt := `
+		SELECT
+			user_id,
+			CASE
+				WHEN due_date IS NULL THEN 'unscheduled'
+				WHEN due_date < @from THEN 'overdue'
+				ELSE TO_CHAR(DATE_TRUNC('week', due_date AT TIME ZONE @timezone), 'YYYY-MM-DD')
+			END AS bucket,
+			COUNT(*)::INT AS open_todos,
+			COALESCE(SUM((metadata ->> 'estimatedMinutes')::INT), 0)::INT AS estimated_minutes,
+			COUNT(*) FILTER (
+				WHERE
+					metadata ->> 'estimatedMinutes' IS NULL
+			)::INT AS unestimated_count
+		FROM
+			todos
+		WHERE
+			workspace_id = @workspace_id
+			AND status_category IN ('draft', 'active')
+			AND (
+				due_date IS NULL
+				OR due_date < @to
+			)
+		GROUP BY
+			1,
+			2
+		ORDER BY
+			1,
+			2
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+		"from":         from,
+		"to":           to,
+		"timezone":     location.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get workload query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	buckets, err := pgx.CollectRows(rows, pgx.RowToStructByName[workspace.WorkloadBucket])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todos for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	return buckets, nil
+}
+
+func (r *WorkspaceRepository) GetAgingPolicies(ctx context.Context, workspaceID string) ([]workspace.AgingPolicy, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			workspace_aging_policies
+		WHERE
+			workspace_id = @workspace_id
+		ORDER BY
+			name ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get aging policies query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	policies, err := pgx.CollectRows(rows, pgx.RowToStructByName[workspace.AgingPolicy])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:workspace_aging_policies for workspace_id=%s: %w",
+			workspaceID, err)
+	}
+
+	return policies, nil
+}
+
+func (r *WorkspaceRepository) CreateAgingPolicy(ctx context.Context, createdBy string,
+	payload *workspace.CreateAgingPolicyPayload,
+) (*workspace.AgingPolicy, error) {
+	stmt := `
+		INSERT INTO
+			workspace_aging_policies (
+				workspace_id,
+				name,
+				priority,
+				max_age_hours,
+				tag,
+				notify,
+				created_by
+			)
+		VALUES
+			(
+				@workspace_id,
+				@name,
+				@priority,
+				@max_age_hours,
+				@tag,
+				@notify,
+				@created_by
+			)
+		ON CONFLICT (workspace_id, name) DO NOTHING
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id":  payload.WorkspaceID,
+		"name":          payload.Name,
+		"priority":      payload.Priority,
+		"max_age_hours": payload.MaxAgeHours,
+		"tag":           *payload.Tag,
+		"notify":        *payload.Notify,
+		"created_by":    createdBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create aging policy query for workspace_id=%s: %w",
+			payload.WorkspaceID, err)
+	}
+
+	policy, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workspace.AgingPolicy])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeAgingPolicyExists
+			return nil, errs.NewConflictError("the workspace already has an aging policy with that name", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_aging_policies for workspace_id=%s: %w",
+			payload.WorkspaceID, err)
+	}
+
+	return &policy, nil
+}
+
+// DeleteAgingPolicy removes a policy and returns it, the tags it added stay on the todos
+func (r *WorkspaceRepository) DeleteAgingPolicy(ctx context.Context, workspaceID string, policyID uuid.UUID,
+) (*workspace.AgingPolicy, error) {
+	stmt := `
+		DELETE FROM workspace_aging_policies
+		WHERE
+			id = @id
+			AND workspace_id = @workspace_id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"id":           policyID,
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute delete aging policy query for policy_id=%s: %w", policyID.String(), err)
+	}
+
+	policy, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workspace.AgingPolicy])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeAgingPolicyNotFound
+			return nil, errs.NewNotFoundError("aging policy not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_aging_policies for policy_id=%s: %w",
+			policyID.String(), err)
+	}
+
+	return &policy, nil
+}
+
+// GetAgingPolicyWorkspaceIDs returns the workspaces with at least one aging policy
+func (r *WorkspaceRepository) GetAgingPolicyWorkspaceIDs(ctx context.Context) ([]string, error) {
+	stmt := `
+		SELECT DISTINCT
+			workspace_id
+		FROM
+			workspace_aging_policies
+		ORDER BY
+			workspace_id ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get aging policy workspaces query: %w", err)
+	}
+
+	workspaceIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:workspace_aging_policies: %w", err)
+	}
+
+	return workspaceIDs, nil
+}
+
+// RecordBreaches records the open todos of the workspace breaching the policy at now that were
+// not recorded before, oldest first and at most limit of them, and returns them. A todo is
+// recorded once per policy, so concurrent passes never escalate it twice.
+func (r *WorkspaceRepository) RecordBreaches(ctx context.Context, policy *workspace.AgingPolicy, now time.Time,
+	limit int,
+) ([]workspace.Breach, error) {
+	stmt := `
+		WITH
+			breached AS (
+				INSERT INTO
+					todo_sla_breaches (policy_id, todo_id, user_id, breached_at)
+				SELECT
+					@policy_id,
+					t.id,
+					t.user_id,
+					@now
+				FROM
+					todos t
+				WHERE
+					t.workspace_id = @workspace_id
+					AND t.status_category IN ('draft', 'active')
+					AND (
+						@priority::TEXT IS NULL
+						OR t.priority = @priority::TEXT
+					)
+					AND t.created_at <= @opened_before
+					AND NOT EXISTS (
+						SELECT
+							1
+						FROM
+							todo_sla_breaches b
+						WHERE
+							b.policy_id = @policy_id
+							AND b.todo_id = t.id
+					)
+				ORDER BY
+					t.created_at ASC
+				LIMIT
+					@limit
+				ON CONFLICT DO NOTHING
+				RETURNING
+					todo_id,
+					user_id,
+					breached_at
+			)
+		SELECT
+			@policy_id::UUID AS policy_id,
+			@policy_name::TEXT AS policy_name,
+			@max_age_hours::INT AS max_age_hours,
+			t.id AS todo_id,
+			t.title AS todo_title,
+			t.user_id,
+			t.priority,
+			t.created_at AS opened_at,
+			breached.breached_at
+		FROM
+			breached
+			JOIN todos t ON t.id = breached.todo_id
+			AND t.user_id = breached.user_id
+		ORDER BY
+			t.created_at ASC
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"policy_id":     policy.ID,
+		"policy_name":   policy.Name,
+		"max_age_hours": policy.MaxAgeHours,
+		"workspace_id":  policy.WorkspaceID,
+		"priority":      policy.Priority,
+		"opened_before": now.Add(-policy.MaxAge()),
+		"now":           now,
+		"limit":         limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute record breaches query for policy_id=%s: %w", policy.ID.String(), err)
+	}
+
+	breaches, err := pgx.CollectRows(rows, pgx.RowToStructByName[workspace.Breach])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_sla_breaches for policy_id=%s: %w",
+			policy.ID.String(), err)
+	}
+
+	return breaches, nil
+}
+
+// GetBreaches returns the recorded breaches of the workspace whose todos are still open
+func (r *WorkspaceRepository) GetBreaches(ctx context.Context, workspaceID string) ([]workspace.Breach, error) {
+	stmt := `
+		SELECT
+			b.policy_id,
+			p.name AS policy_name,
+			p.max_age_hours,
+			t.id AS todo_id,
+			t.title AS todo_title,
+			t.user_id,
+			t.priority,
+			t.created_at AS opened_at,
+			b.breached_at
+		FROM
+			todo_sla_breaches b
+			JOIN workspace_aging_policies p ON p.id = b.policy_id
+			JOIN todos t ON t.id = b.todo_id
+			AND t.user_id = b.user_id
+		WHERE
+			p.workspace_id = @workspace_id
+			AND t.status_category IN ('draft', 'active')
+		ORDER BY
+			t.created_at ASC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get breaches query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	breaches, err := pgx.CollectRows(rows, pgx.RowToStructByName[workspace.Breach])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:todo_sla_breaches for workspace_id=%s: %w",
+			workspaceID, err)
+	}
+
+	return breaches, nil
+}
+
+// GetInvites returns the invites of the workspace, latest first
+func (r *WorkspaceRepository) GetInvites(ctx context.Context, workspaceID string) ([]workspace.Invite, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			workspace_invites
+		WHERE
+			workspace_id = @workspace_id
+		ORDER BY
+			created_at DESC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get invites query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	invites, err := pgx.CollectRows(rows, pgx.RowToStructByName[workspace.Invite])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:workspace_invites for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	return invites, nil
+}
+
+// GetInviteAcceptances returns who joined the workspace through its invites, latest first
+func (r *WorkspaceRepository) GetInviteAcceptances(ctx context.Context, workspaceID string,
+) ([]workspace.InviteAcceptance, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			workspace_invite_acceptances
+		WHERE
+			workspace_id = @workspace_id
+		ORDER BY
+			accepted_at DESC
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get invite acceptances query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	acceptances, err := pgx.CollectRows(rows, pgx.RowToStructByName[workspace.InviteAcceptance])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:workspace_invite_acceptances for workspace_id=%s: %w",
+			workspaceID, err)
+	}
+
+	return acceptances, nil
+}
+
+func (r *WorkspaceRepository) CreateInvite(ctx context.Context, invite *workspace.Invite) (*workspace.Invite, error) {
+	stmt := `
+		INSERT INTO
+			workspace_invites (
+				workspace_id,
+				token_hash,
+				allowed_domains,
+				max_uses,
+				expires_at,
+				created_by
+			)
+		VALUES
+			(
+				@workspace_id,
+				@token_hash,
+				@allowed_domains,
+				@max_uses,
+				@expires_at,
+				@created_by
+			)
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id":    invite.WorkspaceID,
+		"token_hash":      invite.TokenHash,
+		"allowed_domains": invite.AllowedDomains,
+		"max_uses":        invite.MaxUses,
+		"expires_at":      invite.ExpiresAt,
+		"created_by":      invite.CreatedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create invite query for workspace_id=%s: %w", invite.WorkspaceID, err)
+	}
+
+	created, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workspace.Invite])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row from table:workspace_invites for workspace_id=%s: %w",
+			invite.WorkspaceID, err)
+	}
+
+	return &created, nil
+}
+
+// GetInviteByToken looks an invite up by the hash of its token, revoked and expired invites too
+func (r *WorkspaceRepository) GetInviteByToken(ctx context.Context, tokenHash string) (*workspace.Invite, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			workspace_invites
+		WHERE
+			token_hash = @token_hash
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"token_hash": tokenHash,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get invite by token query: %w", err)
+	}
+
+	invite, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workspace.Invite])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeInviteNotFound
+			return nil, errs.NewNotFoundError("invite not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_invites: %w", err)
+	}
+
+	return &invite, nil
+}
+
+// RevokeInvite stops an invite from being accepted, revoking it again keeps the first time
+func (r *WorkspaceRepository) RevokeInvite(ctx context.Context, workspaceID string, inviteID uuid.UUID,
+) (*workspace.Invite, error) {
+	stmt := `
+		UPDATE workspace_invites
+		SET
+			revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+		WHERE
+			id = @id
+			AND workspace_id = @workspace_id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"id":           inviteID,
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute revoke invite query for invite_id=%s: %w", inviteID.String(), err)
+	}
+
+	invite, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workspace.Invite])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeInviteNotFound
+			return nil, errs.NewNotFoundError("invite not found", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_invites for invite_id=%s: %w",
+			inviteID.String(), err)
+	}
+
+	return &invite, nil
+}
+
+func (r *WorkspaceRepository) HasAcceptedInvite(ctx context.Context, inviteID uuid.UUID, userID string) (bool, error) {
+	stmt := `
+		SELECT
+			EXISTS (
+				SELECT
+					1
+				FROM
+					workspace_invite_acceptances
+				WHERE
+					invite_id = @invite_id
+					AND user_id = @user_id
+			)
+	`
+
+	var accepted bool
+	err := r.server.DB.Reader(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{
+		"invite_id": inviteID,
+		"user_id":   userID,
+	}).Scan(&accepted)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute has accepted invite query for invite_id=%s: %w", inviteID.String(), err)
+	}
+
+	return accepted, nil
+}
+
+// AcceptInvite uses up one use of the invite and records the user joining, in one statement so
+// concurrent users can't take more uses than the invite allows
+func (r *WorkspaceRepository) AcceptInvite(ctx context.Context, invite *workspace.Invite, userID, email string,
+) (*workspace.InviteAcceptance, error) {
+	stmt := `
+		WITH
+			used AS (
+				UPDATE workspace_invites
+				SET
+					use_count = use_count + 1
+				WHERE
+					id = @invite_id
+					AND revoked_at IS NULL
+					AND (
+						expires_at IS NULL
+						OR expires_at > CURRENT_TIMESTAMP
+					)
+					AND (
+						max_uses IS NULL
+						OR use_count < max_uses
+					)
+				RETURNING
+					id,
+					workspace_id
+			)
+		INSERT INTO
+			workspace_invite_acceptances (invite_id, workspace_id, user_id, email)
+		SELECT
+			id,
+			workspace_id,
+			@user_id,
+			@email
+		FROM
+			used
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"invite_id": invite.ID,
+		"user_id":   userID,
+		"email":     email,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute accept invite query for invite_id=%s: %w", invite.ID.String(), err)
+	}
+
+	acceptance, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workspace.InviteAcceptance])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Another user took the last use since the invite was read
+			code := errs.CodeInviteUsedUp
+			return nil, errs.NewGoneError("the invite can no longer be accepted", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_invite_acceptances for invite_id=%s: %w",
+			invite.ID.String(), err)
+	}
+
+	return &acceptance, nil
+}
+
+func (r *WorkspaceRepository) GetFreeze(ctx context.Context, workspaceID string) (*workspace.Freeze, error) {
+	stmt := `
+		SELECT
+			*
+		FROM
+			workspace_freezes
+		WHERE
+			workspace_id = @workspace_id
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get freeze query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	freeze, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workspace.Freeze])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeWorkspaceNotFrozen
+			return nil, errs.NewNotFoundError("the workspace is not frozen", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_freezes for workspace_id=%s: %w",
+			workspaceID, err)
+	}
+
+	return &freeze, nil
+}
+
+func (r *WorkspaceRepository) IsFrozen(ctx context.Context, workspaceID string) (bool, error) {
+	stmt := `
+		SELECT
+			EXISTS (
+				SELECT
+					1
+				FROM
+					workspace_freezes
+				WHERE
+					workspace_id = @workspace_id
+			)
+	`
+
+	var frozen bool
+	err := r.server.DB.Reader(ctx).QueryRow(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	}).Scan(&frozen)
+	if err != nil {
+		return false, fmt.Errorf("failed to check freeze for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	return frozen, nil
+}
+
+// CreateFreeze freezes the workspace, a workspace frozen before is left as is and answered
+// with a conflict
+func (r *WorkspaceRepository) CreateFreeze(ctx context.Context, freeze *workspace.Freeze) (*workspace.Freeze, error) {
+	stmt := `
+		INSERT INTO
+			workspace_freezes (workspace_id, frozen_by, reason, purge_after)
+		VALUES
+			(@workspace_id, @frozen_by, @reason, @purge_after)
+		ON CONFLICT (workspace_id) DO NOTHING
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": freeze.WorkspaceID,
+		"frozen_by":    freeze.FrozenBy,
+		"reason":       freeze.Reason,
+		"purge_after":  freeze.PurgeAfter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute create freeze query for workspace_id=%s: %w", freeze.WorkspaceID, err)
+	}
+
+	created, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workspace.Freeze])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeWorkspaceFrozen
+			return nil, errs.NewConflictError("the workspace is already frozen", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_freezes for workspace_id=%s: %w",
+			freeze.WorkspaceID, err)
+	}
+
+	return &created, nil
+}
+
+func (r *WorkspaceRepository) SetFreezeBackup(ctx context.Context, workspaceID string, backupID uuid.UUID,
+) (*workspace.Freeze, error) {
+	stmt := `
+		UPDATE workspace_freezes
+		SET
+			backup_id = @backup_id
+		WHERE
+			workspace_id = @workspace_id
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+		"backup_id":    backupID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute set freeze backup query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	freeze, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workspace.Freeze])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			code := errs.CodeWorkspaceNotFrozen
+			return nil, errs.NewNotFoundError("the workspace is not frozen", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_freezes for workspace_id=%s: %w",
+			workspaceID, err)
+	}
+
+	return &freeze, nil
+}
+
+// DeleteFreeze reactivates the workspace. A purged workspace stays frozen and is answered as
+// gone.
+func (r *WorkspaceRepository) DeleteFreeze(ctx context.Context, workspaceID string) (*workspace.Freeze, error) {
+	freeze, err := r.GetFreeze(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if freeze.Purged() {
+		code := errs.CodeWorkspacePurged
+		return nil, errs.NewGoneError("the workspace was purged and can't be reactivated", false, &code)
+	}
+
+	stmt := `
+		DELETE FROM workspace_freezes
+		WHERE
+			workspace_id = @workspace_id
+			AND purged_at IS NULL
+		RETURNING
+			*
+	`
+
+	rows, err := r.server.DB.Writer(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"workspace_id": workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute delete freeze query for workspace_id=%s: %w", workspaceID, err)
+	}
+
+	deleted, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workspace.Freeze])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Purged or reactivated since it was read
+			code := errs.CodeWorkspaceNotFrozen
+			return nil, errs.NewNotFoundError("the workspace is not frozen", false, &code)
+		}
+		return nil, fmt.Errorf("failed to collect row from table:workspace_freezes for workspace_id=%s: %w",
+			workspaceID, err)
+	}
+
+	return &deleted, nil
+}
+
+// GetPurgeDueWorkspaceIDs lists the frozen workspaces whose retention ran out at now and were
+// not purged yet, those due the longest first
+func (r *WorkspaceRepository) GetPurgeDueWorkspaceIDs(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	stmt := `
+		SELECT
+			workspace_id
+		FROM
+			workspace_freezes
+		WHERE
+			purged_at IS NULL
+			AND purge_after <= @now
+		ORDER BY
+			purge_after ASC
+		LIMIT
+			@limit
+	`
+
+	rows, err := r.server.DB.Reader(ctx).Query(ctx, stmt, pgx.NamedArgs{
+		"now":   now,
+		"limit": limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute get purge due workspaces query: %w", err)
+	}
+
+	workspaceIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows from table:workspace_freezes: %w", err)
+	}
+
+	return workspaceIDs, nil
+}
+
+// PurgeWorkspace deletes the data of a frozen workspace due for purging and marks it purged,
+// it returns the rows changed per resource. Subtasks kept in another workspace are detached
+// from the todos deleted. The backups of the workspace are kept. Run it in a transaction.
+func (r *WorkspaceRepository) PurgeWorkspace(ctx context.Context, workspaceID string) (map[string]int, error) {
+	args := pgx.NamedArgs{"workspace_id": workspaceID}
+
+	// Marked first, a concurrent purge waits on the row and finds it purged
+	tag, err := r.server.DB.Writer(ctx).Exec(ctx, `
+		UPDATE workspace_freezes
+		SET
+			purged_at = CURRENT_TIMESTAMP
+		WHERE
+			workspace_id = @workspace_id
+			AND purged_at IS NULL
+			AND purge_after <= CURRENT_TIMESTAMP
+	`, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark workspace_id=%s as purged: %w", workspaceID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		code := errs.CodeWorkspaceNotFrozen
+		return nil, errs.NewNotFoundError("the workspace is not frozen or was purged", false, &code)
+	}
+
+	stmts := []struct {
+		resource string
+		stmt     string
+	}{
+		{"detachedSubtasks", `
+			UPDATE todos
+			SET
+				parent_todo_id = NULL
+			WHERE
+				workspace_id IS DISTINCT FROM @workspace_id
+				AND parent_todo_id IN (
+					SELECT
+						id
+					FROM
+						todos
+					WHERE
+						workspace_id = @workspace_id
+				)
+		`},
+		{"todos", `
+			DELETE FROM todos
+			WHERE
+				workspace_id = @workspace_id
+		`},
+		{"templates", `
+			DELETE FROM workspace_templates
+			WHERE
+				workspace_id = @workspace_id
+		`},
+		{"onboardingKits", `
+			DELETE FROM workspace_onboarding_kits
+			WHERE
+				workspace_id = @workspace_id
+		`},
+		{"agingPolicies", `
+			DELETE FROM workspace_aging_policies
+			WHERE
+				workspace_id = @workspace_id
+		`},
+		{"retentionPolicies", `
+			DELETE FROM retention_policies
+			WHERE
+				workspace_id = @workspace_id
+		`},
+		{"invites", `
+			DELETE FROM workspace_invites
+			WHERE
+				workspace_id = @workspace_id
+		`},
+		{"members", `
+			DELETE FROM workspace_members
+			WHERE
+				workspace_id = @workspace_id
+		`},
+		{"webhookEndpoints", `
+			DELETE FROM webhook_endpoints
+			WHERE
+				workspace_id = @workspace_id
+		`},
+	}
+
+	counts := map[string]int{}
+	for _, s := range stmts {
+		tag, err = r.server.DB.Writer(ctx).Exec(ctx, s.stmt, args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to purge %s of workspace_id=%s: %w", s.resource, workspaceID, err)
+		}
+		counts[s.resource] = int(tag.RowsAffected())
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,46 @@
+package admin
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterAdminRoutes registers the operations API. Every route requires at least the operator role.
+func RegisterAdminRoutes(router *echo.Group, handlers *handler.Handlers, middleware *middleware.Middlewares) {
+	// Register user routes
+	registerUserRoutes(router, handlers.Admin, middleware.RBAC)
+
+	// Register job routes
+	registerJobRoutes(router, handlers.Admin)
+
+	// Register server mode and config reload routes
+	registerRuntimeRoutes(router, handlers.Admin, middleware.RBAC)
+
+	// Register runtime diagnostics routes
+	registerDebugRoutes(router, handlers.Admin, handlers.Debug)
+
+	// Register audit log routes
+	registerAuditRoutes(router, handlers.Admin, middleware.RBAC)
+
+	// Register feature flag routes
+	registerFeatureRoutes(router, handlers.Admin, middleware.RBAC)
+
+	// Register data retention routes
+	registerRetentionRoutes(router, handlers.Admin, middleware.RBAC)
+
+	// Register workspace backup and restore routes
+	registerBackupRoutes(router, handlers.Admin, middleware.RBAC)
+
+	// Register workspace inspection routes
+	registerWorkspaceRoutes(router, handlers.Admin)
+
+	// Register status page incident routes
+	registerStatusRoutes(router, handlers.Admin, middleware.RBAC)
+
+	// Register API key plan routes
+	registerAPIKeyRoutes(router, handlers.Admin, middleware.RBAC)
+
+	// Register API client CORS routes
+	registerAPIClientRoutes(router, handlers.Admin, middleware.RBAC)
+}
@@ -0,0 +1,18 @@
+package admin
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerAPIClientRoutes(r *echo.Group, h *handler.AdminHandler, rbac *middleware.RBACMiddleware) {
+	// Frontends calling the API from browsers of other origins. Allowing an origin lets its pages
+	// act with the session of the user, only admins may change them.
+	apiClients := r.Group("/api-clients")
+
+	apiClients.GET("", h.GetAPIClients)
+	apiClients.POST("", h.CreateAPIClient, rbac.RequireRole(middleware.RoleAdmin))
+	apiClients.PATCH("/:id", h.UpdateAPIClient, rbac.RequireRole(middleware.RoleAdmin))
+	apiClients.DELETE("/:id", h.DeleteAPIClient, rbac.RequireRole(middleware.RoleAdmin))
+}
@@ -0,0 +1,15 @@
+package admin
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerAPIKeyRoutes(r *echo.Group, h *handler.AdminHandler, rbac *middleware.RBACMiddleware) {
+	// Operators look up the API keys of a user, only admins move a key to another plan
+	apiKeys := r.Group("/api-keys")
+
+	apiKeys.GET("", h.GetUserAPIKeys)
+	apiKeys.PUT("/:id/plan", h.SetAPIKeyPlan, rbac.RequireRole(middleware.RoleAdmin))
+}
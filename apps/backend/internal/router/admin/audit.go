@@ -0,0 +1,15 @@
+package admin
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerAuditRoutes(r *echo.Group, h *handler.AdminHandler, rbac *middleware.RBACMiddleware) {
+	// Security audit log, it records what operators do so only admins may read it
+	auditLog := r.Group("/audit-log", rbac.RequireRole(middleware.RoleAdmin))
+
+	auditLog.GET("", h.GetAuditLog)
+	auditLog.GET("/export", h.ExportAuditLog)
+}
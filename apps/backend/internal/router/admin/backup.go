@@ -0,0 +1,20 @@
+package admin
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerBackupRoutes(r *echo.Group, h *handler.AdminHandler, rbac *middleware.RBACMiddleware) {
+	// Backups copy user data out and restores write it back, only admins may start them
+	r.GET("/workspaces/:workspaceId/backups", h.GetWorkspaceBackups)
+	r.POST("/workspaces/:workspaceId/backups", h.CreateWorkspaceBackup, rbac.RequireRole(middleware.RoleAdmin))
+
+	backups := r.Group("/backups")
+
+	backups.GET("/:id", h.GetWorkspaceBackup)
+	backups.POST("/:id/restores", h.RestoreWorkspaceBackup, rbac.RequireRole(middleware.RoleAdmin))
+
+	r.GET("/restores/:id", h.GetWorkspaceRestore)
+}
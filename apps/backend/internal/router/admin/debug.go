@@ -0,0 +1,22 @@
+package admin
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/labstack/echo/v4"
+)
+
+func registerDebugRoutes(r *echo.Group, admin *handler.AdminHandler, h *handler.DebugHandler) {
+	// Runtime diagnostics for production latency spikes
+	debug := r.Group("/debug")
+
+	debug.GET("/runtime", admin.GetRuntimeStats)
+	debug.GET("/goroutines", h.GoroutineDump)
+
+	debug.GET("/pprof/", h.PprofIndex)
+	debug.GET("/pprof/cmdline", h.PprofCmdline)
+	debug.GET("/pprof/profile", h.PprofProfile)
+	debug.GET("/pprof/symbol", h.PprofSymbol)
+	debug.POST("/pprof/symbol", h.PprofSymbol)
+	debug.GET("/pprof/trace", h.PprofTrace)
+	debug.GET("/pprof/:profile", h.PprofIndex)
+}
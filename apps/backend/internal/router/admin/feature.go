@@ -0,0 +1,16 @@
+package admin
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerFeatureRoutes(r *echo.Group, h *handler.AdminHandler, rbac *middleware.RBACMiddleware) {
+	// Feature flags, overrides change what users get without a redeploy so only admins may set them
+	flags := r.Group("/feature-flags", rbac.RequireRole(middleware.RoleAdmin))
+
+	flags.GET("", h.GetFeatureFlags)
+	flags.PUT("/:flag/overrides", h.SetFeatureFlagOverride)
+	flags.DELETE("/:flag/overrides", h.DeleteFeatureFlagOverride)
+}
@@ -0,0 +1,14 @@
+package admin
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/labstack/echo/v4"
+)
+
+func registerJobRoutes(r *echo.Group, h *handler.AdminHandler) {
+	// Failed background jobs
+	jobs := r.Group("/jobs")
+
+	jobs.GET("", h.GetJobs)
+	jobs.POST("/:queue/:id/retry", h.RetryJob)
+}
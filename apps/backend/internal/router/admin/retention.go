@@ -0,0 +1,17 @@
+package admin
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerRetentionRoutes(r *echo.Group, h *handler.AdminHandler, rbac *middleware.RBACMiddleware) {
+	// Retention policies decide what user data gets deleted, only admins may change them
+	policies := r.Group("/retention-policies")
+
+	policies.GET("", h.GetRetentionPolicies)
+	policies.GET("/preview", h.PreviewRetentionPolicy)
+	policies.PUT("/:workspaceId", h.SetRetentionPolicy, rbac.RequireRole(middleware.RoleAdmin))
+	policies.DELETE("/:workspaceId", h.DeleteRetentionPolicy, rbac.RequireRole(middleware.RoleAdmin))
+}
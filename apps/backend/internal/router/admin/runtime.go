@@ -0,0 +1,25 @@
+package admin
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerRuntimeRoutes(r *echo.Group, h *handler.AdminHandler, rbac *middleware.RBACMiddleware) {
+	// Maintenance and read-only switches, operators can see them but only admins flip them
+	mode := r.Group("/server-mode")
+
+	mode.GET("", h.GetServerMode)
+	mode.PUT("", h.SetServerMode, rbac.RequireRole(middleware.RoleAdmin))
+
+	// Fault injection for resilience testing, only when the config enables it
+	faults := r.Group("/faults")
+
+	faults.GET("", h.GetFaultInjection)
+	faults.PUT("", h.SetFaultInjection)
+	faults.DELETE("", h.ClearFaultInjection)
+
+	// Config reload of the instance serving the request
+	r.POST("/config/reload", h.ReloadConfig, rbac.RequireRole(middleware.RoleAdmin))
+}
@@ -0,0 +1,18 @@
+package admin
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerStatusRoutes(r *echo.Group, h *handler.AdminHandler, rbac *middleware.RBACMiddleware) {
+	// Operators on call announce and follow up incidents on the public status page, only
+	// admins may take one off it
+	incidents := r.Group("/status/incidents")
+
+	incidents.GET("", h.GetIncidents)
+	incidents.POST("", h.CreateIncident)
+	incidents.PATCH("/:id", h.UpdateIncident)
+	incidents.DELETE("/:id", h.DeleteIncident, rbac.RequireRole(middleware.RoleAdmin))
+}
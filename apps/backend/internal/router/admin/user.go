@@ -0,0 +1,17 @@
+package admin
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerUserRoutes(r *echo.Group, h *handler.AdminHandler, rbac *middleware.RBACMiddleware) {
+	// User lookup, operators see the storage quota of a user, only admins adjust it
+	users := r.Group("/users")
+
+	users.GET("/:id", h.GetUser)
+	users.GET("/:id/storage-quota", h.GetStorageQuota)
+	users.PUT("/:id/storage-quota", h.SetStorageQuota, rbac.RequireRole(middleware.RoleAdmin))
+	users.DELETE("/:id/storage-quota", h.DeleteStorageQuota, rbac.RequireRole(middleware.RoleAdmin))
+}
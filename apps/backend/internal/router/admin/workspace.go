@@ -0,0 +1,13 @@
+package admin
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/labstack/echo/v4"
+)
+
+func registerWorkspaceRoutes(r *echo.Group, h *handler.AdminHandler) {
+	// Operators inspect a workspace, its admins manage it from the API
+	workspace := r.Group("/workspaces/:workspaceId")
+
+	workspace.GET("", h.GetWorkspace)
+}
@@ -5,16 +5,27 @@ import (
 
 	"github.com/Sameer16536/ExecuTask/internal/handler"
 	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/router/admin"
 	v1 "github.com/Sameer16536/ExecuTask/internal/router/v1"
+	v2 "github.com/Sameer16536/ExecuTask/internal/router/v2"
 	"github.com/Sameer16536/ExecuTask/internal/server"
 	"github.com/Sameer16536/ExecuTask/internal/service"
 	"github.com/labstack/echo/v4"
 	echoMiddleware "github.com/labstack/echo/v4/middleware"
-	"golang.org/x/time/rate"
 )
 
 func NewRouter(s *server.Server, h *handler.Handlers, services *service.Services) *echo.Echo {
 	middlewares := middleware.NewMiddlewares(s)
+	if services != nil {
+		middlewares.Auth.SetAPIKeyAuthenticator(services.APIKey)
+		middlewares.Auth.AddSessionGuard(services.APIKey)
+		middlewares.Auth.AddSessionGuard(services.Workspace)
+		middlewares.Auth.AddSessionRecorder(services.Audit)
+		middlewares.Auth.AddSessionRecorder(services.Workspace)
+		middlewares.Auth.AddSessionRecorder(services.Locale)
+		middlewares.FeatureFlags.SetEvaluator(services.Features)
+		middlewares.Global.SetOriginPolicy(services.APIClient)
+	}
 
 	router := echo.New()
 
@@ -23,7 +34,7 @@ func NewRouter(s *server.Server, h *handler.Handlers, services *service.Services
 	// global middlewares
 	router.Use(
 		echoMiddleware.RateLimiterWithConfig(echoMiddleware.RateLimiterConfig{
-			Store: echoMiddleware.NewRateLimiterMemoryStore(rate.Limit(20)),
+			Store: middlewares.RateLimit.Store(),
 			DenyHandler: func(c echo.Context, identifier string, err error) error {
 				// Record rate limit hit metrics
 				if rateLimitMiddleware := middlewares.RateLimit; rateLimitMiddleware != nil {
@@ -42,21 +53,48 @@ func NewRouter(s *server.Server, h *handler.Handlers, services *service.Services
 			},
 		}),
 		middlewares.Global.CORS(),
-		middlewares.Global.Secure(),
+		middlewares.SecurityHeaders.Apply,
 		middleware.RequestID(),
+		middlewares.Locale.Negotiate,
+		middlewares.Timeout.RequestContext,
+		middlewares.Tracing.OpenTelemetryMiddleware(),
 		middlewares.Tracing.NewRelicMiddleware(),
 		middlewares.Tracing.EnhanceTracing(),
 		middlewares.ContextEnhancer.EnhanceContext(),
+		middlewares.ReadRouting.RouteReads,
 		middlewares.Global.RequestLogger(),
 		middlewares.Global.Recover(),
+		middlewares.Mode.Enforce,
+		middlewares.Usage.Meter,
+		middlewares.FaultInjection.Inject,
+		middlewares.Compression.Compress,
+		middlewares.PayloadBudget.Guard,
 	)
 
 	// register system routes
-	registerSystemRoutes(router, h)
+	registerSystemRoutes(router, h, middlewares)
 
 	// register versioned routes
-	v1Router := router.Group("/api/v1")
+	v1Router := router.Group("/api/v1", middlewares.Version.Serve(middleware.APIVersionV1))
 	v1.RegisterV1Routes(v1Router, h, middlewares)
 
+	v2Router := router.Group("/api/v2", middlewares.Version.Serve(middleware.APIVersionV2))
+	v2.RegisterV2Routes(v2Router, h, middlewares)
+
+	// register operations routes, restricted to operators and admins
+	adminRouter := router.Group("/admin/v1",
+		middlewares.Auth.RequireAuth, middlewares.RBAC.RequireRole(middleware.RoleOperator))
+	admin.RegisterAdminRoutes(adminRouter, h, middlewares)
+
+	// Generate the OpenAPI document from the registered routes and enforce its request schemas,
+	// once the body is known to be within the limit of the route
+	document := handler.BuildOpenAPIDocument(router.Routes())
+	h.OpenAPI.SetDocument(document)
+	router.Use(middlewares.BodyLimit.Limit(document))
+	router.Use(middlewares.Global.SchemaValidation(document))
+
+	// Resolve _links against the registered routes
+	router.Use(handler.NewLinkBuilder(router.Routes()).Middleware())
+
 	return router
 }
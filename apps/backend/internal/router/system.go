@@ -2,14 +2,29 @@ package router
 
 import (
 	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
 
 	"github.com/labstack/echo/v4"
 )
 
-func registerSystemRoutes(r *echo.Echo, h *handler.Handlers) {
-	r.GET("/status", h.Health.CheckHealth)
+// docsPolicy lets the API reference load its script, styles and fonts from their CDNs and fetch
+// the document and the API itself
+const docsPolicy = "default-src 'none'; script-src https://cdn.jsdelivr.net; " +
+	"style-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net https://fonts.scalar.com; " +
+	"font-src 'self' data: https://fonts.scalar.com; img-src 'self' data: https:; connect-src 'self'; " +
+	"frame-ancestors 'none'; base-uri 'none'; form-action 'none'"
+
+func registerSystemRoutes(r *echo.Echo, h *handler.Handlers, middlewares *middleware.Middlewares) {
+	r.GET("/status", h.Health.GetStatusPage)
+	r.GET("/healthz", h.Health.Liveness)
+	r.GET("/readyz", h.Health.Readiness)
 
 	r.Static("/static", "static")
 
-	r.GET("/docs", h.OpenAPI.ServeOpenAPIUI)
+	// Signed download links of the filesystem storage driver
+	r.GET("/blobs/*", h.Blob.GetBlob)
+
+	r.GET("/docs", h.OpenAPI.ServeOpenAPIUI, middlewares.SecurityHeaders.Policy(docsPolicy))
+	r.GET("/openapi.json", h.OpenAPI.ServeOpenAPISpec)
+	r.GET("/errors", h.OpenAPI.ServeErrorCatalog)
 }
@@ -0,0 +1,17 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerAPIKeyRoutes(r *echo.Group, h *handler.APIKeyHandler, auth *middleware.AuthMiddleware) {
+	// Keys to call the API with instead of a session, they can only be managed with a session
+	apiKeys := r.Group("/api-keys")
+	apiKeys.Use(auth.RequireAuth)
+
+	apiKeys.GET("", h.GetKeys)
+	apiKeys.POST("", h.CreateKey)
+	apiKeys.DELETE("/:id", h.DeleteKey)
+}
@@ -0,0 +1,17 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerBatchRoutes(r *echo.Group, h *handler.BatchHandler, auth *middleware.AuthMiddleware,
+	idempotency *middleware.IdempotencyMiddleware,
+) {
+	// Multiple API calls in a single round trip
+	batch := r.Group("/batch")
+	batch.Use(auth.RequireAuth)
+
+	batch.POST("", h.ExecuteBatch, idempotency.Idempotent)
+}
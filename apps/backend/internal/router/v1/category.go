@@ -6,15 +6,21 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func registerCategoryRoutes(r *echo.Group, h *handler.CategoryHandler, auth *middleware.AuthMiddleware) {
+func registerCategoryRoutes(r *echo.Group, h *handler.CategoryHandler, auth *middleware.AuthMiddleware,
+	idempotency *middleware.IdempotencyMiddleware,
+) {
 	// Category operations
 	categories := r.Group("/categories")
 	categories.Use(auth.RequireAuth)
 
 	// Category collection operations
-	categories.POST("", h.CreateCategory)
+	categories.POST("", h.CreateCategory, idempotency.Idempotent)
 	categories.GET("", h.GetCategories)
 
+	// Shareable bundles of the category setup, without todos
+	categories.GET("/export", h.ExportCategoryBundle)
+	categories.POST("/import", h.ImportCategoryBundle, idempotency.Idempotent)
+
 	// Individual category operations
 	dynamicCategory := categories.Group("/:id")
 	dynamicCategory.PATCH("", h.UpdateCategory)
@@ -0,0 +1,15 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerChangeRoutes(r *echo.Group, h *handler.ChangeHandler, auth *middleware.AuthMiddleware) {
+	// Change feed for polling clients
+	changes := r.Group("/changes")
+	changes.Use(auth.RequireAuth)
+
+	changes.GET("", h.GetChanges)
+}
@@ -15,4 +15,8 @@ func registerCommentRoutes(r *echo.Group, h *handler.CommentHandler, auth *middl
 	dynamicComment := comments.Group("/:id")
 	dynamicComment.PATCH("", h.UpdateComment)
 	dynamicComment.DELETE("", h.DeleteComment)
+
+	// Replies to notification emails, forwarded by the inbound email provider
+	inbound := r.Group("/inbound")
+	inbound.POST("/email-replies", h.ReceiveEmailReply)
 }
@@ -0,0 +1,21 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerCoverRoutes(r *echo.Group, h *handler.CoverHandler, auth *middleware.AuthMiddleware) {
+	todos := r.Group("/todos")
+	todos.Use(auth.RequireAuth)
+
+	// The stock library covers can be picked from
+	todos.GET("/covers/stock", h.GetStockImages)
+
+	// Thumbnails are rendered in the background, the cover is pending until they're ready
+	todoCover := todos.Group("/:id/cover")
+	todoCover.GET("", h.GetCover)
+	todoCover.PUT("", h.SetCover)
+	todoCover.DELETE("", h.DeleteCover)
+}
@@ -0,0 +1,21 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerExportRoutes(r *echo.Group, h *handler.ExportHandler, auth *middleware.AuthMiddleware,
+	idempotency *middleware.IdempotencyMiddleware,
+) {
+	// Account exports and todo list PDFs are built by a background job, poll the export or wait
+	// for the email
+	exports := r.Group("/export")
+	exports.Use(auth.RequireAuth)
+
+	exports.POST("/account", h.RequestAccountExport, idempotency.Idempotent)
+	exports.GET("/account/:id", h.GetAccountExport)
+	exports.POST("/todos", h.RequestTodoListExport, idempotency.Idempotent)
+	exports.GET("/todos/:id", h.GetTodoListExport)
+}
@@ -0,0 +1,26 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerFocusRoutes(r *echo.Group, h *handler.FocusHandler, auth *middleware.AuthMiddleware,
+	idempotency *middleware.IdempotencyMiddleware,
+) {
+	// Focus sessions
+	sessions := r.Group("/focus/sessions")
+	sessions.Use(auth.RequireAuth)
+
+	sessions.POST("", h.StartSession, idempotency.Idempotent)
+	sessions.GET("", h.GetSessions)
+	sessions.GET("/current", h.GetCurrentSession)
+
+	// Dynamic session operations
+	dynamicSession := sessions.Group("/:id")
+
+	dynamicSession.POST("/pause", h.PauseSession)
+	dynamicSession.POST("/resume", h.ResumeSession)
+	dynamicSession.POST("/complete", h.CompleteSession)
+}
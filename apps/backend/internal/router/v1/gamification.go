@@ -0,0 +1,17 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerGamificationRoutes(r *echo.Group, h *handler.GamificationHandler, auth *middleware.AuthMiddleware) {
+	// Streaks, badges and the weekly goal
+	gamification := r.Group("/gamification")
+	gamification.Use(auth.RequireAuth)
+
+	gamification.GET("", h.GetOverview)
+	gamification.PUT("/goal", h.SetGoal)
+	gamification.DELETE("/goal", h.DeleteGoal)
+}
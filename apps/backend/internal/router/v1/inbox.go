@@ -0,0 +1,24 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerInboxRoutes(r *echo.Group, h *handler.InboxHandler, auth *middleware.AuthMiddleware,
+	idempotency *middleware.IdempotencyMiddleware,
+) {
+	// Quick capture inbox, items wait here until triaged into todos
+	items := r.Group("/inbox")
+	items.Use(auth.RequireAuth)
+
+	items.POST("", h.CaptureItem, idempotency.Idempotent)
+	items.GET("", h.GetItems)
+
+	// Dynamic item operations
+	dynamicItem := items.Group("/:id")
+
+	dynamicItem.POST("/triage", h.TriageItem, idempotency.Idempotent)
+	dynamicItem.DELETE("", h.DiscardItem)
+}
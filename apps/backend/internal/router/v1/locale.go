@@ -0,0 +1,17 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerLocaleRoutes(r *echo.Group, h *handler.LocaleHandler, auth *middleware.AuthMiddleware) {
+	// The locale the user reads the app and its emails in, over the Accept-Language of requests
+	locale := r.Group("/locale")
+	locale.Use(auth.RequireAuth)
+
+	locale.GET("", h.GetPreference)
+	locale.PUT("", h.UpdatePreference)
+	locale.DELETE("", h.ResetPreference)
+}
@@ -0,0 +1,22 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerMagicTagRoutes(r *echo.Group, h *handler.MagicTagHandler, auth *middleware.AuthMiddleware,
+	idempotency *middleware.IdempotencyMiddleware,
+) {
+	// Shortcuts such as #tomorrow, applied to the titles of todos as they are created and updated
+	magicTags := r.Group("/magic-tags")
+	magicTags.Use(auth.RequireAuth)
+
+	magicTags.POST("", h.CreateMagicTag, idempotency.Idempotent)
+	magicTags.GET("", h.GetMagicTags)
+
+	dynamicMagicTag := magicTags.Group("/:id")
+	dynamicMagicTag.PUT("", h.UpdateMagicTag)
+	dynamicMagicTag.DELETE("", h.DeleteMagicTag)
+}
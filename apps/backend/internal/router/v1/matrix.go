@@ -0,0 +1,17 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerMatrixRoutes(r *echo.Group, h *handler.MatrixHandler, auth *middleware.AuthMiddleware) {
+	// Eisenhower matrix of the open todos and the thresholds it buckets them with
+	matrix := r.Group("/matrix")
+	matrix.Use(auth.RequireAuth)
+
+	matrix.GET("", h.GetMatrix)
+	matrix.GET("/settings", h.GetSettings)
+	matrix.PATCH("/settings", h.UpdateSettings)
+}
@@ -0,0 +1,18 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerNoteRoutes(r *echo.Group, h *handler.NoteHandler, auth *middleware.AuthMiddleware) {
+	todos := r.Group("/todos")
+	todos.Use(auth.RequireAuth)
+
+	// Only the author of a note ever reads it back
+	todoNote := todos.Group("/:id/private-note")
+	todoNote.GET("", h.GetNote)
+	todoNote.PUT("", h.SetNote)
+	todoNote.DELETE("", h.DeleteNote)
+}
@@ -0,0 +1,28 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerPlannerRoutes(r *echo.Group, h *handler.PlannerHandler, auth *middleware.AuthMiddleware,
+	idempotency *middleware.IdempotencyMiddleware,
+) {
+	// Weekly planning, days are taken in the timezone the client passes
+	planner := r.Group("/planner")
+	planner.Use(auth.RequireAuth)
+
+	planner.GET("", h.GetPlanner)
+	planner.GET("/forecast", h.GetForecast)
+	planner.POST("/schedule", h.Schedule, idempotency.Idempotent)
+
+	// The todos picked for today apart from their due dates, open ones go to review at day end
+	myDay := r.Group("/my-day")
+	myDay.Use(auth.RequireAuth)
+
+	myDay.GET("", h.GetMyDay)
+	myDay.POST("/todos", h.AddMyDayTodos, idempotency.Idempotent)
+	myDay.DELETE("/todos/:todoId", h.RemoveMyDayTodo)
+	myDay.GET("/summaries", h.GetDaySummaries)
+}
@@ -0,0 +1,25 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerReportRoutes(r *echo.Group, h *handler.ReportHandler, auth *middleware.AuthMiddleware,
+	idempotency *middleware.IdempotencyMiddleware,
+) {
+	// Reports of the todos matching a set of filters, emailed to their recipients on a schedule
+	schedules := r.Group("/reports/schedules")
+	schedules.Use(auth.RequireAuth)
+
+	schedules.POST("", h.CreateSchedule, idempotency.Idempotent)
+	schedules.GET("", h.GetSchedules)
+
+	dynamicSchedule := schedules.Group("/:id")
+	dynamicSchedule.GET("", h.GetSchedule)
+	dynamicSchedule.PUT("", h.UpdateSchedule)
+	dynamicSchedule.DELETE("", h.DeleteSchedule)
+	dynamicSchedule.POST("/pause", h.PauseSchedule)
+	dynamicSchedule.POST("/resume", h.ResumeSchedule)
+}
@@ -0,0 +1,24 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerRuleRoutes(r *echo.Group, h *handler.RuleHandler, auth *middleware.AuthMiddleware,
+	idempotency *middleware.IdempotencyMiddleware,
+) {
+	// Automation rules, evaluated by a background job as the user's todos change
+	rules := r.Group("/rules")
+	rules.Use(auth.RequireAuth)
+
+	rules.POST("", h.CreateRule, idempotency.Idempotent)
+	rules.GET("", h.GetRules)
+
+	dynamicRule := rules.Group("/:id")
+	dynamicRule.GET("", h.GetRule)
+	dynamicRule.PATCH("", h.UpdateRule)
+	dynamicRule.DELETE("", h.DeleteRule)
+	dynamicRule.GET("/executions", h.GetRuleExecutions)
+}
@@ -0,0 +1,21 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerSearchRoutes(r *echo.Group, h *handler.SearchHandler, auth *middleware.AuthMiddleware) {
+	// Keyword or semantic search across the user's todos
+	search := r.Group("/search")
+	search.Use(auth.RequireAuth)
+
+	search.GET("", h.Search)
+
+	// Typeahead across the user's todos, categories and tags
+	suggest := r.Group("/suggest")
+	suggest.Use(auth.RequireAuth)
+
+	suggest.GET("", h.Suggest)
+}
@@ -0,0 +1,17 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerSessionRoutes(r *echo.Group, h *handler.SessionHandler, auth *middleware.AuthMiddleware) {
+	// Cookie sessions for browsers, created from the session token they signed in with
+	sessions := r.Group("/auth")
+	sessions.Use(auth.RequireAuth)
+
+	sessions.POST("/session", h.CreateSession)
+	sessions.DELETE("/session", h.DeleteSession)
+	sessions.GET("/csrf", h.GetCSRFToken)
+}
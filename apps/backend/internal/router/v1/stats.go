@@ -0,0 +1,17 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerStatsRoutes(r *echo.Group, h *handler.StatsHandler, auth *middleware.AuthMiddleware) {
+	stats := r.Group("/stats")
+	stats.Use(auth.RequireAuth)
+
+	// Dashboard aggregates, served from materialized views
+	stats.GET("/overview", h.GetOverview)
+	// Computed live over the selected window
+	stats.GET("/productivity", h.GetProductivity)
+}
@@ -0,0 +1,18 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerSyncRoutes(r *echo.Group, h *handler.SyncHandler, auth *middleware.AuthMiddleware,
+	idempotency *middleware.IdempotencyMiddleware, features *middleware.FeatureFlagMiddleware,
+) {
+	// Offline client sync, still being rolled out
+	sync := r.Group("/sync")
+	sync.Use(auth.RequireAuth, features.Require(config.FeatureSync))
+
+	sync.POST("", h.SyncTodos, idempotency.Idempotent)
+}
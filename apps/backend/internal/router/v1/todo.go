@@ -6,30 +6,57 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func registerTodoRoutes(r *echo.Group, h *handler.TodoHandler, ch *handler.CommentHandler, auth *middleware.AuthMiddleware) {
+func registerTodoRoutes(r *echo.Group, h *handler.TodoHandler, ch *handler.CommentHandler,
+	dh *handler.DependencyHandler, eh *handler.ExportHandler, auth *middleware.AuthMiddleware,
+	idempotency *middleware.IdempotencyMiddleware,
+) {
 	// Todo operations
 	todos := r.Group("/todos")
 	todos.Use(auth.RequireAuth)
 
 	// Collection operations
-	todos.POST("", h.CreateTodo)
+	todos.POST("", h.CreateTodo, idempotency.Idempotent)
 	todos.GET("", h.GetTodos)
 	todos.GET("/stats", h.GetTodoStats)
+	todos.POST("/complete", h.CompleteTodos, idempotency.Idempotent)
+	todos.GET("/export", h.ExportTodos)
+	// Printable PDFs, longer lists are exported in the background through /export/todos
+	todos.GET("/export.pdf", eh.ExportTodoListPDF)
 
 	// Individual todo operations
 	dynamicTodo := todos.Group("/:id")
 	dynamicTodo.GET("", h.GetTodoByID)
 	dynamicTodo.PATCH("", h.UpdateTodo)
 	dynamicTodo.DELETE("", h.DeleteTodo)
+	dynamicTodo.POST("/uncomplete", h.UncompleteTodo)
+	dynamicTodo.POST("/read", h.MarkTodoRead)
+	dynamicTodo.POST("/merge", h.MergeTodos)
+	dynamicTodo.POST("/suggest-subtasks", h.SuggestSubtasks)
+	dynamicTodo.GET("/export.pdf", eh.ExportTodoPDF)
+
+	// Todo versions, snapshots of earlier edits
+	todoVersions := dynamicTodo.Group("/versions")
+	todoVersions.GET("", h.GetTodoVersions)
+	todoVersions.POST("/:version/restore", h.RestoreTodoVersion)
 
 	// Todo comments
 	todoComments := dynamicTodo.Group("/comments")
-	todoComments.POST("", ch.AddComment)
+	todoComments.POST("", ch.AddComment, idempotency.Idempotent)
 	todoComments.GET("", ch.GetCommentsByTodoID)
+	todoComments.POST("/read", ch.MarkCommentsRead)
 
 	// Todo attachments
 	todoAttachments := dynamicTodo.Group("/attachments")
-	todoAttachments.POST("", h.UploadTodoAttachment)
+	todoAttachments.POST("", h.UploadTodoAttachment, idempotency.Idempotent)
 	todoAttachments.DELETE("/:attachmentId", h.DeleteTodoAttachment)
 	todoAttachments.GET("/:attachmentId/download", h.GetAttachmentPresignedURL)
+	todoAttachments.GET("/:attachmentId/thumbnail", h.GetAttachmentThumbnailURL)
+
+	// Todo dependencies, moving a due date can carry the todos waiting on it along
+	todoDependencies := dynamicTodo.Group("/dependencies")
+	todoDependencies.GET("", dh.GetDependencies)
+	todoDependencies.POST("", dh.AddDependency, idempotency.Idempotent)
+	todoDependencies.DELETE("/:dependsOnId", dh.RemoveDependency)
+	dynamicTodo.POST("/due-date/preview", dh.PreviewDueDateShift)
+	dynamicTodo.POST("/due-date", dh.ShiftDueDate)
 }
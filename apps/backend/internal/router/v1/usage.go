@@ -0,0 +1,21 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerUsageRoutes(r *echo.Group, h *handler.UsageHandler, auth *middleware.AuthMiddleware) {
+	// Metered usage, rolled up hourly by the usage-rollup job
+	usage := r.Group("/usage")
+	usage.Use(auth.RequireAuth)
+
+	usage.GET("", h.GetUsage)
+
+	// Summaries about the caller
+	me := r.Group("/me")
+	me.Use(auth.RequireAuth)
+
+	me.GET("/api-usage", h.GetAPIUsage)
+}
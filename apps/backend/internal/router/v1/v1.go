@@ -8,11 +8,78 @@ import (
 
 func RegisterV1Routes(router *echo.Group, handlers *handler.Handlers, middleware *middleware.Middlewares) {
 	// Register todo routes
-	registerTodoRoutes(router, handlers.Todo, handlers.Comment, middleware.Auth)
+	registerTodoRoutes(router, handlers.Todo, handlers.Comment, handlers.Dependency, handlers.Export, middleware.Auth,
+		middleware.Idempotency)
+
+	// Register todo cover routes
+	registerCoverRoutes(router, handlers.Cover, middleware.Auth)
+
+	// Register private note routes
+	registerNoteRoutes(router, handlers.Note, middleware.Auth)
 
 	// Register category routes
-	registerCategoryRoutes(router, handlers.Category, middleware.Auth)
+	registerCategoryRoutes(router, handlers.Category, middleware.Auth, middleware.Idempotency)
 
 	// Register comment routes
 	registerCommentRoutes(router, handlers.Comment, middleware.Auth)
+
+	// Register sync routes
+	registerSyncRoutes(router, handlers.Sync, middleware.Auth, middleware.Idempotency, middleware.FeatureFlags)
+
+	// Register batch routes
+	registerBatchRoutes(router, handlers.Batch, middleware.Auth, middleware.Idempotency)
+
+	// Register change feed routes
+	registerChangeRoutes(router, handlers.Change, middleware.Auth)
+
+	// Register stats routes
+	registerStatsRoutes(router, handlers.Stats, middleware.Auth)
+
+	// Register search routes
+	registerSearchRoutes(router, handlers.Search, middleware.Auth)
+
+	// Register usage routes
+	registerUsageRoutes(router, handlers.Usage, middleware.Auth)
+
+	// Register export routes
+	registerExportRoutes(router, handlers.Export, middleware.Auth, middleware.Idempotency)
+
+	// Register scheduled report routes
+	registerReportRoutes(router, handlers.Report, middleware.Auth, middleware.Idempotency)
+
+	// Register automation rule routes
+	registerRuleRoutes(router, handlers.Rule, middleware.Auth, middleware.Idempotency)
+
+	// Register magic tag routes
+	registerMagicTagRoutes(router, handlers.MagicTag, middleware.Auth, middleware.Idempotency)
+
+	// Register planner routes
+	registerPlannerRoutes(router, handlers.Planner, middleware.Auth, middleware.Idempotency)
+
+	// Register matrix routes
+	registerMatrixRoutes(router, handlers.Matrix, middleware.Auth)
+
+	// Register locale routes
+	registerLocaleRoutes(router, handlers.Locale, middleware.Auth)
+
+	// Register API key routes
+	registerAPIKeyRoutes(router, handlers.APIKey, middleware.Auth)
+
+	// Register webhook routes
+	registerWebhookRoutes(router, handlers.Webhook, middleware.Auth, middleware.Idempotency)
+
+	// Register cookie session routes
+	registerSessionRoutes(router, handlers.Session, middleware.Auth)
+
+	// Register focus session routes
+	registerFocusRoutes(router, handlers.Focus, middleware.Auth, middleware.Idempotency)
+
+	// Register gamification routes
+	registerGamificationRoutes(router, handlers.Gamification, middleware.Auth)
+
+	// Register inbox routes
+	registerInboxRoutes(router, handlers.Inbox, middleware.Auth, middleware.Idempotency)
+
+	// Register workspace template routes
+	registerWorkspaceRoutes(router, handlers.Workspace, middleware.Auth, middleware.Idempotency)
 }
@@ -0,0 +1,24 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerWebhookRoutes(r *echo.Group, h *handler.WebhookHandler, auth *middleware.AuthMiddleware,
+	idempotency *middleware.IdempotencyMiddleware,
+) {
+	// Endpoints the events of the active workspace are posted to, managed by its admins
+	webhooks := r.Group("/webhooks")
+	webhooks.Use(auth.RequireAuth)
+
+	webhooks.GET("", h.GetEndpoints)
+	webhooks.POST("", h.CreateEndpoint, idempotency.Idempotent)
+	webhooks.GET("/events", h.GetEventTypes)
+
+	dynamicWebhook := webhooks.Group("/:id")
+	dynamicWebhook.PATCH("", h.UpdateEndpoint)
+	dynamicWebhook.DELETE("", h.DeleteEndpoint)
+	dynamicWebhook.POST("/test", h.TestEndpoint)
+}
@@ -0,0 +1,55 @@
+package v1
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerWorkspaceRoutes(r *echo.Group, h *handler.WorkspaceHandler, auth *middleware.AuthMiddleware,
+	idempotency *middleware.IdempotencyMiddleware,
+) {
+	// Templates published by the admins of the active workspace
+	templates := r.Group("/templates")
+	templates.Use(auth.RequireAuth)
+
+	templates.GET("", h.GetTemplates)
+	templates.POST("/:id/apply", h.ApplyTemplate, idempotency.Idempotent)
+
+	// Team views of the active workspace, and the settings its admins manage from within it
+	workspaces := r.Group("/workspaces/:workspaceId")
+	workspaces.Use(auth.RequireAuth)
+
+	workspaces.GET("/workload", h.GetWorkload)
+	workspaces.GET("/sla-breaches", h.GetBreachReport)
+	workspaces.GET("/workflow", h.GetWorkflow)
+	workspaces.PUT("/workflow", h.SetWorkflow)
+	workspaces.DELETE("/workflow", h.DeleteWorkflow)
+
+	workspaces.GET("/templates", h.GetWorkspaceTemplates)
+	workspaces.POST("/templates", h.CreateTemplate, idempotency.Idempotent)
+	workspaces.DELETE("/templates/:templateId", h.DeleteTemplate)
+
+	workspaces.GET("/onboarding-kit", h.GetOnboardingKit)
+	workspaces.PUT("/onboarding-kit", h.SetOnboardingKit)
+	workspaces.DELETE("/onboarding-kit", h.DeleteOnboardingKit)
+
+	workspaces.GET("/aging-policies", h.GetAgingPolicies)
+	workspaces.POST("/aging-policies", h.CreateAgingPolicy, idempotency.Idempotent)
+	workspaces.DELETE("/aging-policies/:policyId", h.DeleteAgingPolicy)
+
+	workspaces.GET("/invites", h.GetInvites)
+	workspaces.POST("/invites", h.CreateInvite, idempotency.Idempotent)
+	workspaces.DELETE("/invites/:inviteId", h.RevokeInvite)
+
+	// A frozen workspace rejects writes until it is reactivated, its data is purged after the retention
+	workspaces.GET("/freeze", h.GetFreeze)
+	workspaces.POST("/freeze", h.FreezeWorkspace, idempotency.Idempotent)
+	workspaces.DELETE("/freeze", h.ReactivateWorkspace)
+
+	// Accepting an invite link shared by the admins of a workspace joins the workspace
+	invites := r.Group("/invites")
+	invites.Use(auth.RequireAuth)
+
+	invites.POST("/:token/accept", h.AcceptInvite, idempotency.Idempotent)
+}
@@ -0,0 +1,24 @@
+package v2
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerCategoryRoutes(r *echo.Group, h *handler.CategoryHandler, auth *middleware.AuthMiddleware,
+	idempotency *middleware.IdempotencyMiddleware,
+) {
+	// Category operations
+	categories := r.Group("/categories")
+	categories.Use(auth.RequireAuth)
+
+	// Category collection operations
+	categories.POST("", h.CreateCategory, idempotency.Idempotent)
+	categories.GET("", h.GetCategories)
+
+	// Individual category operations
+	dynamicCategory := categories.Group("/:id")
+	dynamicCategory.PATCH("", h.UpdateCategory)
+	dynamicCategory.DELETE("", h.DeleteCategory)
+}
@@ -0,0 +1,18 @@
+package v2
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerCommentRoutes(r *echo.Group, h *handler.CommentHandler, auth *middleware.AuthMiddleware) {
+	// Comment operations
+	comments := r.Group("/comments")
+	comments.Use(auth.RequireAuth)
+
+	// Individual comment operations
+	dynamicComment := comments.Group("/:id")
+	dynamicComment.PATCH("", h.UpdateComment)
+	dynamicComment.DELETE("", h.DeleteComment)
+}
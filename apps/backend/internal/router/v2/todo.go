@@ -0,0 +1,41 @@
+package v2
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func registerTodoRoutes(r *echo.Group, h *handler.TodoHandler, ch *handler.CommentHandler, auth *middleware.AuthMiddleware,
+	idempotency *middleware.IdempotencyMiddleware,
+) {
+	// Todo operations
+	todos := r.Group("/todos")
+	todos.Use(auth.RequireAuth)
+
+	// Collection operations
+	todos.POST("", h.CreateTodo, idempotency.Idempotent)
+	todos.GET("", h.GetTodos)
+	todos.GET("/stats", h.GetTodoStats)
+	todos.GET("/export", h.ExportTodos)
+
+	// Individual todo operations
+	dynamicTodo := todos.Group("/:id")
+	dynamicTodo.GET("", h.GetTodoByID)
+	dynamicTodo.PATCH("", h.UpdateTodo)
+	dynamicTodo.DELETE("", h.DeleteTodo)
+	dynamicTodo.POST("/read", h.MarkTodoRead)
+
+	// Todo comments
+	todoComments := dynamicTodo.Group("/comments")
+	todoComments.POST("", ch.AddComment, idempotency.Idempotent)
+	todoComments.GET("", ch.GetCommentsByTodoID)
+	todoComments.POST("/read", ch.MarkCommentsRead)
+
+	// Todo attachments
+	todoAttachments := dynamicTodo.Group("/attachments")
+	todoAttachments.POST("", h.UploadTodoAttachment, idempotency.Idempotent)
+	todoAttachments.DELETE("/:attachmentId", h.DeleteTodoAttachment)
+	todoAttachments.GET("/:attachmentId/download", h.GetAttachmentPresignedURL)
+	todoAttachments.GET("/:attachmentId/thumbnail", h.GetAttachmentThumbnailURL)
+}
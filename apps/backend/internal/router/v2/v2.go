@@ -0,0 +1,20 @@
+package v2
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/handler"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterV2Routes registers the v2 API. It shares handlers and services with v1,
+// responses are reshaped by the handler compatibility shims.
+func RegisterV2Routes(router *echo.Group, handlers *handler.Handlers, middleware *middleware.Middlewares) {
+	// Register todo routes
+	registerTodoRoutes(router, handlers.Todo, handlers.Comment, middleware.Auth, middleware.Idempotency)
+
+	// Register category routes
+	registerCategoryRoutes(router, handlers.Category, middleware.Auth, middleware.Idempotency)
+
+	// Register comment routes
+	registerCommentRoutes(router, handlers.Comment, middleware.Auth)
+}
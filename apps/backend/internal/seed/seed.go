@@ -0,0 +1,380 @@
+// Package seed fills a development database with realistic users, categories, nested todos,
+// comments and attachments, for load testing repository queries and pagination. Every seeded
+// user ID starts with UserPrefix so a reset never touches real accounts.
+package seed
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// UserPrefix marks seeded users
+const UserPrefix = "seed_user_"
+
+// Config sets the scale and shape of the seeded data
+type Config struct {
+	Users int
+	// Todos is the total across all users, subtasks included
+	Todos int
+	// SubtaskRatio is the share of todos created as a subtask of another one
+	SubtaskRatio float64
+	// CommentsPerTodo is the average number of comments on a todo
+	CommentsPerTodo float64
+	// AttachmentRatio is the share of todos with an attachment
+	AttachmentRatio float64
+	// Seed makes a run reproducible, the same seed generates the same data
+	Seed uint64
+}
+
+// Presets are the scales used for load testing
+var Presets = map[string]Config{
+	"small": {
+		Users:           10,
+		Todos:           1_000,
+		SubtaskRatio:    0.3,
+		CommentsPerTodo: 1.5,
+		AttachmentRatio: 0.1,
+		Seed:            1,
+	},
+	"large": {
+		Users:           100,
+		Todos:           100_000,
+		SubtaskRatio:    0.3,
+		CommentsPerTodo: 1.5,
+		AttachmentRatio: 0.1,
+		Seed:            1,
+	},
+}
+
+// Result counts the seeded rows
+type Result struct {
+	Users       int
+	Categories  int
+	Todos       int
+	Comments    int
+	Attachments int
+}
+
+type Seeder struct {
+	pool   *pgxpool.Pool
+	logger *zerolog.Logger
+}
+
+func NewSeeder(pool *pgxpool.Pool, logger *zerolog.Logger) *Seeder {
+	return &Seeder{
+		pool:   pool,
+		logger: logger,
+	}
+}
+
+// Run seeds every user in its own transaction, so an interrupted run keeps the users it finished.
+// Users are numbered, running again with the same config replaces their data.
+func (s *Seeder) Run(ctx context.Context, cfg Config) (*Result, error) {
+	if cfg.Users <= 0 || cfg.Todos < cfg.Users {
+		return nil, fmt.Errorf("seeding needs at least one user and one todo per user, got %d users and %d todos",
+			cfg.Users, cfg.Todos)
+	}
+
+	rng := rand.New(rand.NewPCG(cfg.Seed, cfg.Seed))
+	result := &Result{}
+	started := time.Now()
+
+	for i := range cfg.Users {
+		userID := fmt.Sprintf("%s%05d", UserPrefix, i+1)
+
+		// The remainder goes to the first users
+		todoCount := cfg.Todos / cfg.Users
+		if i < cfg.Todos%cfg.Users {
+			todoCount++
+		}
+
+		data := generate(rng, cfg, userID, todoCount)
+
+		err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+			if err := deleteUsers(ctx, tx, userID); err != nil {
+				return err
+			}
+			return data.insert(ctx, tx)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to seed user_id=%s: %w", userID, err)
+		}
+
+		result.Users++
+		result.Categories += len(data.categories)
+		result.Todos += len(data.todos)
+		result.Comments += len(data.comments)
+		result.Attachments += len(data.attachments)
+
+		s.logger.Info().
+			Str("user_id", userID).
+			Int("todo_count", len(data.todos)).
+			Int("progress", i+1).
+			Int("total", cfg.Users).
+			Msg("seeded user")
+	}
+
+	s.logger.Info().
+		Int("users", result.Users).
+		Int("todos", result.Todos).
+		Dur("duration", time.Since(started)).
+		Msg("seeding finished")
+
+	return result, nil
+}
+
+// Reset deletes everything owned by seeded users
+func (s *Seeder) Reset(ctx context.Context) error {
+	return pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		return deleteUsers(ctx, tx, UserPrefix+"%")
+	})
+}
+
+// deleteUsers removes the rows of the users matching pattern. Comments, attachments and
+// snapshots go with their todo.
+func deleteUsers(ctx context.Context, tx pgx.Tx, pattern string) error {
+	stmts := []string{
+		`DELETE FROM todos WHERE user_id LIKE @pattern`,
+		`DELETE FROM todo_categories WHERE user_id LIKE @pattern`,
+		`DELETE FROM change_events WHERE user_id LIKE @pattern`,
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(ctx, stmt, pgx.NamedArgs{"pattern": pattern}); err != nil {
+			return fmt.Errorf("failed to delete seeded rows for %s: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// dataset is the generated data of one user
+type dataset struct {
+	categories  [][]any
+	todos       [][]any
+	subtasks    [][]any
+	comments    [][]any
+	attachments [][]any
+}
+
+var (
+	categoryColumns = []string{"id", "created_at", "updated_at", "user_id", "name", "color", "description"}
+	todoColumns     = []string{
+		"id", "created_at", "updated_at", "user_id", "title", "description", "priority", "status",
+		"due_date", "completed_at", "parent_todo_id", "category_id", "metadata",
+	}
+	commentColumns    = []string{"id", "created_at", "updated_at", "todo_id", "user_id", "content"}
+	attachmentColumns = []string{
+		"id", "created_at", "updated_at", "todo_id", "name", "uploaded_by", "download_key", "file_size", "mime_type",
+	}
+)
+
+// insert copies the rows in dependency order. Subtasks follow their parents, the counter
+// triggers update the parents as they go in.
+func (d *dataset) insert(ctx context.Context, tx pgx.Tx) error {
+	copies := []struct {
+		table   string
+		columns []string
+		rows    [][]any
+	}{
+		{"todo_categories", categoryColumns, d.categories},
+		{"todos", todoColumns, d.todos},
+		{"todos", todoColumns, d.subtasks},
+		{"todo_comments", commentColumns, d.comments},
+		{"todo_attachments", attachmentColumns, d.attachments},
+	}
+
+	for _, c := range copies {
+		if len(c.rows) == 0 {
+			continue
+		}
+		_, err := tx.CopyFrom(ctx, pgx.Identifier{c.table}, c.columns, pgx.CopyFromRows(c.rows))
+		if err != nil {
+			return fmt.Errorf("failed to copy rows into table:%s: %w", c.table, err)
+		}
+	}
+
+	// todos holds both passes for the result counts
+	d.todos = append(d.todos, d.subtasks...)
+	return nil
+}
+
+func generate(rng *rand.Rand, cfg Config, userID string, todoCount int) *dataset {
+	data := &dataset{}
+	now := time.Now().UTC()
+
+	// Categories
+	categoryIDs := make([]uuid.UUID, 0, len(categoryNames))
+	for _, i := range rng.Perm(len(categoryNames))[:3+rng.IntN(len(categoryNames)-2)] {
+		id := newID(rng)
+		createdAt := now.Add(-time.Duration(180+rng.IntN(185)) * 24 * time.Hour)
+		data.categories = append(data.categories, []any{
+			id, createdAt, createdAt, userID, categoryNames[i], categoryColors[rng.IntN(len(categoryColors))],
+			optional(rng, 0.5, "Everything about "+strings.ToLower(categoryNames[i])),
+		})
+		categoryIDs = append(categoryIDs, id)
+	}
+
+	// Root todos first, subtasks pick a parent among them
+	subtaskCount := int(float64(todoCount) * cfg.SubtaskRatio)
+	rootCount := max(todoCount-subtaskCount, 1)
+	subtaskCount = todoCount - rootCount
+
+	type parent struct {
+		id         uuid.UUID
+		createdAt  time.Time
+		categoryID *uuid.UUID
+	}
+	parents := make([]parent, 0, rootCount)
+	todoIDs := make([]uuid.UUID, 0, todoCount)
+	todoCreated := make([]time.Time, 0, todoCount)
+
+	for range rootCount {
+		var categoryID *uuid.UUID
+		if rng.Float64() < 0.7 {
+			categoryID = &categoryIDs[rng.IntN(len(categoryIDs))]
+		}
+
+		id, createdAt, row := generateTodo(rng, now, userID, nil, categoryID, now.Add(-180*24*time.Hour))
+		data.todos = append(data.todos, row)
+		parents = append(parents, parent{id: id, createdAt: createdAt, categoryID: categoryID})
+		todoIDs = append(todoIDs, id)
+		todoCreated = append(todoCreated, createdAt)
+	}
+
+	for range subtaskCount {
+		p := parents[rng.IntN(len(parents))]
+		id, createdAt, row := generateTodo(rng, now, userID, &p.id, p.categoryID, p.createdAt)
+		data.subtasks = append(data.subtasks, row)
+		todoIDs = append(todoIDs, id)
+		todoCreated = append(todoCreated, createdAt)
+	}
+
+	// Comments, a few todos collect long threads
+	for i, todoID := range todoIDs {
+		count := rng.IntN(int(cfg.CommentsPerTodo*2) + 1)
+		if rng.Float64() < 0.02 {
+			count += 20 + rng.IntN(30)
+		}
+		for range count {
+			createdAt := between(rng, todoCreated[i], now)
+			data.comments = append(data.comments, []any{
+				newID(rng), createdAt, createdAt, todoID, userID, sentence(rng, commentPhrases, 1+rng.IntN(3)),
+			})
+		}
+	}
+
+	// Attachments point at keys that were never uploaded, downloads of seeded files fail
+	for i, todoID := range todoIDs {
+		if rng.Float64() >= cfg.AttachmentRatio {
+			continue
+		}
+		file := attachmentFiles[rng.IntN(len(attachmentFiles))]
+		id := newID(rng)
+		createdAt := between(rng, todoCreated[i], now)
+		data.attachments = append(data.attachments, []any{
+			id, createdAt, createdAt, todoID, file.name, userID,
+			fmt.Sprintf("seed/%s/%s/%s", userID, id.String(), file.name),
+			int64(1_000 + rng.IntN(5_000_000)), file.mimeType,
+		})
+	}
+
+	return data
+}
+
+// generateTodo returns the ID, creation time and COPY row of a todo created after notBefore
+func generateTodo(rng *rand.Rand, now time.Time, userID string, parentID, categoryID *uuid.UUID,
+	notBefore time.Time,
+) (uuid.UUID, time.Time, []any) {
+	id := newID(rng)
+	createdAt := between(rng, notBefore, now)
+	updatedAt := between(rng, createdAt, now)
+
+	status := weighted(rng, statusWeights)
+	priority := weighted(rng, priorityWeights)
+
+	// Due dates fall both in the past and in the future, some todos are overdue
+	var dueDate *time.Time
+	if rng.Float64() < 0.6 {
+		due := createdAt.Add(time.Duration(rng.IntN(60*24)) * time.Hour)
+		dueDate = &due
+	}
+
+	var completedAt *time.Time
+	if status == todo.StatusCompleted {
+		completedAt = &updatedAt
+	}
+
+	var metadata *todo.Metadata
+	if rng.Float64() < 0.4 {
+		metadata = &todo.Metadata{Tags: pick(rng, tags, 1+rng.IntN(3))}
+	}
+
+	title := titleVerbs[rng.IntN(len(titleVerbs))] + " " + titleObjects[rng.IntN(len(titleObjects))]
+
+	return id, createdAt, []any{
+		id, createdAt, updatedAt, userID, title, optional(rng, 0.6, sentence(rng, descriptionPhrases, 1+rng.IntN(4))),
+		string(priority), string(status), dueDate, completedAt, parentID, categoryID, metadata,
+	}
+}
+
+// newID draws a version 4 UUID from rng, keeping IDs reproducible
+func newID(rng *rand.Rand) uuid.UUID {
+	var id uuid.UUID
+	binary.BigEndian.PutUint64(id[:8], rng.Uint64())
+	binary.BigEndian.PutUint64(id[8:], rng.Uint64())
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+	return id
+}
+
+func between(rng *rand.Rand, from, to time.Time) time.Time {
+	if !to.After(from) {
+		return from
+	}
+	return from.Add(time.Duration(rng.Int64N(int64(to.Sub(from)))))
+}
+
+func optional(rng *rand.Rand, chance float64, value string) *string {
+	if rng.Float64() >= chance {
+		return nil
+	}
+	return &value
+}
+
+func sentence(rng *rand.Rand, phrases []string, count int) string {
+	return strings.Join(pick(rng, phrases, count), " ")
+}
+
+func pick(rng *rand.Rand, values []string, count int) []string {
+	picked := make([]string, 0, count)
+	for _, i := range rng.Perm(len(values))[:min(count, len(values))] {
+		picked = append(picked, values[i])
+	}
+	return picked
+}
+
+func weighted[T any](rng *rand.Rand, weights []weight[T]) T {
+	total := 0
+	for _, w := range weights {
+		total += w.weight
+	}
+	n := rng.IntN(total)
+	for _, w := range weights {
+		if n < w.weight {
+			return w.value
+		}
+		n -= w.weight
+	}
+	return weights[len(weights)-1].value
+}
@@ -0,0 +1,84 @@
+package seed
+
+import "github.com/Sameer16536/ExecuTask/internal/model/todo"
+
+type weight[T any] struct {
+	value  T
+	weight int
+}
+
+// Most todos in a real account are open, few are archived
+var statusWeights = []weight[todo.Status]{
+	{todo.StatusDraft, 15},
+	{todo.StatusActive, 45},
+	{todo.StatusCompleted, 30},
+	{todo.StatusArchived, 10},
+}
+
+var priorityWeights = []weight[todo.Priority]{
+	{todo.PriorityLow, 30},
+	{todo.PriorityMedium, 50},
+	{todo.PriorityHigh, 20},
+}
+
+var categoryNames = []string{
+	"Work", "Personal", "Errands", "Health", "Finance", "Home", "Learning", "Travel", "Side Project", "Family",
+}
+
+var categoryColors = []string{
+	"#6b7280", "#ef4444", "#f59e0b", "#10b981", "#3b82f6", "#8b5cf6", "#ec4899",
+}
+
+var titleVerbs = []string{
+	"Review", "Draft", "Update", "Schedule", "Call about", "Fix", "Plan", "Book", "Prepare", "Clean up",
+	"Follow up on", "Research", "Pay", "Renew", "Organize",
+}
+
+var titleObjects = []string{
+	"quarterly report", "dentist appointment", "team offsite", "car insurance", "onboarding docs",
+	"grocery list", "flight to Berlin", "budget spreadsheet", "pull request backlog", "garage",
+	"birthday gift", "passport", "conference talk", "gym membership", "landing page copy",
+	"tax documents", "weekly sync notes", "home network", "reading list", "client proposal",
+}
+
+var descriptionPhrases = []string{
+	"Needs to happen before the end of the week.",
+	"Check with the team first.",
+	"Last time this took longer than expected.",
+	"Keep the receipts.",
+	"Low effort, just has to get done.",
+	"Blocked until we hear back.",
+	"Split into smaller steps if it drags on.",
+	"Notes from the last meeting are in the shared folder.",
+	"Ask for a second opinion.",
+	"Set a reminder for the follow up.",
+}
+
+var commentPhrases = []string{
+	"Started on this today.",
+	"Waiting on a reply.",
+	"Moved the deadline by a week.",
+	"Done with the first half.",
+	"This turned out bigger than planned.",
+	"Found the missing details.",
+	"Pinged them again.",
+	"Should be wrapped up tomorrow.",
+	"Added the links we talked about.",
+	"Not urgent anymore.",
+}
+
+var tags = []string{
+	"urgent", "waiting", "quick", "deep-work", "phone", "online", "weekend", "someday",
+}
+
+var attachmentFiles = []struct {
+	name     string
+	mimeType string
+}{
+	{"notes.pdf", "application/pdf"},
+	{"receipt.jpg", "image/jpeg"},
+	{"screenshot.png", "image/png"},
+	{"budget.xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+	{"agenda.docx", "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+	{"export.csv", "text/csv"},
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const faultsRedisKey = "server:faults"
+
+// FaultKind is the failure an error rule injects, each maps to the database error a real
+// failure of that kind would surface as
+type FaultKind string
+
+const (
+	FaultDatabaseBusy         FaultKind = "database_busy"
+	FaultSerializationFailure FaultKind = "serialization_failure"
+	FaultQueryTimeout         FaultKind = "query_timeout"
+	FaultInternal             FaultKind = "internal"
+)
+
+// FaultRule injects faults into a share of the requests to a route. Percentages are rolled
+// independently: a request may be delayed and then fail.
+type FaultRule struct {
+	// Route is the registered path, e.g. /api/v1/todos/:id, * matches every route
+	Route string `json:"route"`
+	// Method restricts the rule to one HTTP method, empty matches all
+	Method         string        `json:"method"`
+	LatencyPercent int           `json:"latencyPercent"`
+	Latency        time.Duration `json:"latency"`
+	ErrorPercent   int           `json:"errorPercent"`
+	ErrorKind      FaultKind     `json:"errorKind"`
+	// DropPercent closes the connection without a response
+	DropPercent int `json:"dropPercent"`
+}
+
+// FaultState holds the active rules of every instance, shared through Redis
+type FaultState struct {
+	Rules     []FaultRule `json:"rules"`
+	UpdatedBy string      `json:"updatedBy"`
+	UpdatedAt *time.Time  `json:"updatedAt"`
+	ExpiresAt *time.Time  `json:"expiresAt"`
+}
+
+// Match returns the first rule covering a request, nil when none does
+func (f FaultState) Match(method, route string) *FaultRule {
+	for i := range f.Rules {
+		rule := &f.Rules[i]
+		if (rule.Route == "*" || rule.Route == route) && (rule.Method == "" || rule.Method == method) {
+			return rule
+		}
+	}
+	return nil
+}
+
+type faultCache struct {
+	mu        sync.Mutex
+	state     FaultState
+	fetchedAt time.Time
+}
+
+// Faults returns the active fault rules, none unless fault injection is enabled. Like the mode
+// they are read from Redis at most once per refresh interval.
+func (s *Server) Faults(ctx context.Context) FaultState {
+	if !s.Config.Server.FaultInjection.Enabled {
+		return FaultState{}
+	}
+
+	s.faults.mu.Lock()
+	defer s.faults.mu.Unlock()
+
+	if !s.faults.fetchedAt.IsZero() && time.Since(s.faults.fetchedAt) < s.Config.Server.Mode.RefreshInterval {
+		return s.faults.state
+	}
+	s.faults.fetchedAt = time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, modeReadTimeout)
+	defer cancel()
+
+	state, err := s.loadFaults(ctx)
+	if err != nil {
+		s.Logger.Warn().Err(err).Msg("failed to refresh fault rules, keeping the last known ones")
+		return s.faults.state
+	}
+	s.faults.state = state
+
+	return state
+}
+
+// SetFaults replaces the rules of every instance until they expire
+func (s *Server) SetFaults(ctx context.Context, state FaultState, ttl time.Duration) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal fault rules: %w", err)
+	}
+
+	if err := s.Redis.Set(ctx, faultsRedisKey, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store fault rules: %w", err)
+	}
+
+	s.faults.mu.Lock()
+	s.faults.state = state
+	s.faults.fetchedAt = time.Now()
+	s.faults.mu.Unlock()
+
+	return nil
+}
+
+// ClearFaults removes every rule, instances stop injecting within the refresh interval
+func (s *Server) ClearFaults(ctx context.Context) error {
+	if err := s.Redis.Del(ctx, faultsRedisKey).Err(); err != nil {
+		return fmt.Errorf("failed to clear fault rules: %w", err)
+	}
+
+	s.faults.mu.Lock()
+	s.faults.state = FaultState{}
+	s.faults.fetchedAt = time.Now()
+	s.faults.mu.Unlock()
+
+	return nil
+}
+
+func (s *Server) loadFaults(ctx context.Context) (FaultState, error) {
+	data, err := s.Redis.Get(ctx, faultsRedisKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return FaultState{}, nil
+	}
+	if err != nil {
+		return FaultState{}, fmt.Errorf("failed to read fault rules: %w", err)
+	}
+
+	var state FaultState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return FaultState{}, fmt.Errorf("failed to unmarshal fault rules: %w", err)
+	}
+
+	return state, nil
+}
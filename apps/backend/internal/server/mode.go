@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	modeRedisKey = "server:mode"
+	// modeReadTimeout bounds the Redis read on the request path, a slow Redis keeps the last mode
+	modeReadTimeout = 500 * time.Millisecond
+)
+
+// ModeState is the mode every instance serves in, shared through Redis
+type ModeState struct {
+	Mode config.ServerMode `json:"mode"`
+	// Message is shown to rejected clients, a generic one is used when empty
+	Message   string     `json:"message"`
+	UpdatedBy string     `json:"updatedBy"`
+	UpdatedAt *time.Time `json:"updatedAt"`
+}
+
+type modeCache struct {
+	mu        sync.Mutex
+	state     ModeState
+	fetchedAt time.Time
+}
+
+// Mode returns the current mode. It is read from Redis at most once per refresh interval; when
+// Redis fails the last known mode, or the configured default, stays in effect.
+func (s *Server) Mode(ctx context.Context) ModeState {
+	s.mode.mu.Lock()
+	defer s.mode.mu.Unlock()
+
+	cfg := s.Config.Server.Mode
+	if !s.mode.fetchedAt.IsZero() && time.Since(s.mode.fetchedAt) < cfg.RefreshInterval {
+		return s.mode.state
+	}
+
+	if s.mode.fetchedAt.IsZero() {
+		s.mode.state = ModeState{Mode: cfg.Default}
+	}
+	// Also set on failure, an unreachable Redis is retried once per interval and not per request
+	s.mode.fetchedAt = time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, modeReadTimeout)
+	defer cancel()
+
+	state, err := s.loadMode(ctx)
+	if err != nil {
+		s.Logger.Warn().Err(err).Str("mode", string(s.mode.state.Mode)).Msg("failed to refresh server mode, keeping the last known one")
+		return s.mode.state
+	}
+
+	if state.Mode != s.mode.state.Mode {
+		s.Logger.Info().
+			Str("previous_mode", string(s.mode.state.Mode)).
+			Str("mode", string(state.Mode)).
+			Msg("server mode changed")
+	}
+	s.mode.state = state
+
+	return state
+}
+
+// SetMode switches every instance to a mode, each picks it up within the refresh interval
+func (s *Server) SetMode(ctx context.Context, state ModeState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal server mode: %w", err)
+	}
+
+	if err := s.Redis.Set(ctx, modeRedisKey, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to store server mode: %w", err)
+	}
+
+	s.mode.mu.Lock()
+	s.mode.state = state
+	s.mode.fetchedAt = time.Now()
+	s.mode.mu.Unlock()
+
+	return nil
+}
+
+func (s *Server) loadMode(ctx context.Context) (ModeState, error) {
+	data, err := s.Redis.Get(ctx, modeRedisKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return ModeState{Mode: s.Config.Server.Mode.Default}, nil
+	}
+	if err != nil {
+		return ModeState{}, fmt.Errorf("failed to read server mode: %w", err)
+	}
+
+	var state ModeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return ModeState{}, fmt.Errorf("failed to unmarshal server mode: %w", err)
+	}
+
+	return state, nil
+}
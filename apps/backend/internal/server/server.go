@@ -5,15 +5,23 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/Sameer16536/ExecuTask/internal/config"
 	"github.com/Sameer16536/ExecuTask/internal/database"
+	"github.com/Sameer16536/ExecuTask/internal/events"
+	"github.com/Sameer16536/ExecuTask/internal/lib/alert"
+	"github.com/Sameer16536/ExecuTask/internal/lib/analytics"
+	"github.com/Sameer16536/ExecuTask/internal/lib/breaker"
 	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/lib/metering"
 	loggerPkg "github.com/Sameer16536/ExecuTask/internal/logger"
 	"github.com/newrelic/go-agent/v3/integrations/nrredis-v9"
+	"github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 type Server struct {
@@ -24,9 +32,36 @@ type Server struct {
 	Redis         *redis.Client
 	httpServer    *http.Server
 	Job           *job.JobService
+	// Usage meters API calls and notification sends, nil outside the API server
+	Usage *metering.Meter
+	// Analytics emits the anonymized product events, nil unless analytics is enabled
+	Analytics *analytics.Emitter
+	// Alerts pages on-call about operational trouble, nil unless alerts are enabled
+	Alerts *alert.Monitor
+	// Breakers fail the calls to a third party that keeps failing or answering slowly
+	Breakers *breaker.Registry
+	// Events delivers what happened to todos and comments to the subscribers the services
+	// register
+	Events *events.Bus
+	// shuttingDown fails readiness checks while the server drains
+	shuttingDown atomic.Bool
+	// mode caches the maintenance and read-only switches shared through Redis
+	mode modeCache
+	// faults caches the fault injection rules shared through Redis
+	faults faultCache
+	// live holds the config values a reload may change, see CurrentConfig
+	live *config.Live
+	// TracerProvider exports OpenTelemetry spans, nil when tracing is disabled
+	TracerProvider *sdktrace.TracerProvider
 }
 
 func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerPkg.LoggerService) (*Server, error) {
+	// Installed first so the database and job clients trace from the start
+	tracerProvider, err := NewTracerProvider(cfg.Observability)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+
 	db, err := database.New(cfg, logger, loggerService)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
@@ -51,22 +86,44 @@ func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerPkg.Lo
 		// Don't fail startup if Redis is unavailable
 	}
 
+	usageMeter := metering.NewMeter(redisClient, logger)
+	usageMeter.Start(cfg.Usage.FlushInterval)
+
+	breakers := breaker.NewRegistry(cfg.Breakers, logger)
+
 	// job service
 	jobService := job.NewJobService(logger, cfg)
-	jobService.InitHandlers(cfg, logger)
+	var nrApp *newrelic.Application
+	if loggerService != nil {
+		nrApp = loggerService.GetApplication()
+	}
+	if err := jobService.InitHandlers(cfg, logger, nrApp, breakers); err != nil {
+		return nil, err
+	}
+	jobService.SetUsageMeter(usageMeter)
 
 	// Start job server
 	if err := jobService.Start(); err != nil {
 		return nil, err
 	}
 
+	live := config.NewLive(cfg)
+	live.OnReload(func(cfg *config.Config) {
+		loggerPkg.SetLevel(cfg.Observability.GetLogLevel())
+	})
+
 	server := &Server{
-		Config:        cfg,
-		Logger:        logger,
-		LoggerService: loggerService,
-		DB:            db,
-		Redis:         redisClient,
-		Job:           jobService,
+		Config:         cfg,
+		live:           live,
+		Logger:         logger,
+		LoggerService:  loggerService,
+		DB:             db,
+		Redis:          redisClient,
+		Job:            jobService,
+		Usage:          usageMeter,
+		Breakers:       breakers,
+		Events:         events.NewBus(),
+		TracerProvider: tracerProvider,
 	}
 
 	// Start metrics collection
@@ -98,18 +155,111 @@ func (s *Server) Start() error {
 	return s.httpServer.ListenAndServe()
 }
 
+// CurrentConfig returns the config with the latest reloaded values. Config keeps the values
+// the server started with, which is what everything that can't change at runtime reads.
+func (s *Server) CurrentConfig() *config.Config {
+	if s.live == nil {
+		return s.Config
+	}
+	return s.live.Get()
+}
+
+// ReloadConfig reads the config sources again and applies the values that are safe to change
+// at runtime, such as rate limits, the log level and feature flags
+func (s *Server) ReloadConfig() (*config.ReloadResult, error) {
+	if s.live == nil {
+		return nil, errors.New("config reload is not available")
+	}
+
+	result, err := s.live.Reload()
+	if err != nil {
+		s.Logger.Error().Err(err).Msg("config reload rejected, keeping the current config")
+		return nil, err
+	}
+
+	s.Logger.Info().
+		Strs("applied", result.Applied).
+		Strs("restart_required", result.RestartRequired).
+		Msg("config reloaded")
+
+	return result, nil
+}
+
+// ShuttingDown reports whether Shutdown was called, the server only finishes what it started
+func (s *Server) ShuttingDown() bool {
+	return s.shuttingDown.Load()
+}
+
+// Shutdown stops the server in dependency order: new traffic first, then in-flight requests,
+// running jobs and buffered telemetry, and finally the pools all of them rely on. Every step
+// runs even when an earlier one fails.
 func (s *Server) Shutdown(ctx context.Context) error {
-	if err := s.httpServer.Shutdown(ctx); err != nil {
-		return fmt.Errorf("failed to shutdown HTTP server: %w", err)
+	shutdownConfig := s.Config.Server.Shutdown
+	if shutdownConfig == nil {
+		shutdownConfig = config.DefaultShutdownConfig()
 	}
 
-	if err := s.DB.Close(); err != nil {
-		return fmt.Errorf("failed to close database connection: %w", err)
+	s.shuttingDown.Store(true)
+	var shutdownErrs []error
+
+	if s.httpServer != nil {
+		// Clients reconnect, ideally to another instance, instead of reusing this one
+		s.httpServer.SetKeepAlivesEnabled(false)
+
+		if shutdownConfig.DrainDelay > 0 {
+			s.Logger.Info().Dur("drain_delay", shutdownConfig.DrainDelay).Msg("failing readiness before closing the listener")
+			select {
+			case <-time.After(shutdownConfig.DrainDelay):
+			case <-ctx.Done():
+			}
+		}
+
+		s.Logger.Info().Dur("timeout", shutdownConfig.Timeout).Msg("draining in-flight requests")
+		drainCtx, cancel := context.WithTimeout(ctx, shutdownConfig.Timeout)
+		if err := s.httpServer.Shutdown(drainCtx); err != nil {
+			// Out of time, cut the requests that are still running
+			shutdownErrs = append(shutdownErrs, fmt.Errorf("failed to drain HTTP server: %w", err))
+			if err := s.httpServer.Close(); err != nil {
+				shutdownErrs = append(shutdownErrs, fmt.Errorf("failed to close HTTP server: %w", err))
+			}
+		}
+		cancel()
 	}
 
+	// Running jobs finish or are requeued, the database and Redis are still there for them
 	if s.Job != nil {
 		s.Job.Stop()
 	}
 
-	return nil
+	// Counts of the drained requests and jobs, Redis is still there for them
+	if s.Usage != nil {
+		s.Usage.Stop(ctx)
+	}
+
+	// Product events of the drained requests, the sink doesn't depend on the pools
+	s.Analytics.Stop(ctx)
+
+	// The alarm checks read the pools closed below
+	s.Alerts.Stop()
+
+	// Flush the spans still queued for export
+	if s.TracerProvider != nil {
+		if err := s.TracerProvider.Shutdown(ctx); err != nil {
+			shutdownErrs = append(shutdownErrs, fmt.Errorf("failed to shutdown tracer provider: %w", err))
+		}
+	}
+
+	if s.Redis != nil {
+		if err := s.Redis.Close(); err != nil {
+			shutdownErrs = append(shutdownErrs, fmt.Errorf("failed to close redis client: %w", err))
+		}
+	}
+
+	if err := s.DB.Close(); err != nil {
+		shutdownErrs = append(shutdownErrs, fmt.Errorf("failed to close database connection: %w", err))
+	}
+
+	s.Logger.Info().Msg("server shut down")
+
+	return errors.Join(shutdownErrs...)
 }
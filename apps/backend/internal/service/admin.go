@@ -0,0 +1,627 @@
+package service
+
+import (
+	"errors"
+	"runtime"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/admin"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	clerkUser "github.com/clerk/clerk-sdk-go/v2/user"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/labstack/echo/v4"
+)
+
+type AdminService struct {
+	server        *server.Server
+	adminRepo     repository.AdminStore
+	todoRepo      repository.TodoStore
+	workspaceRepo repository.WorkspaceStore
+	jobs          *job.JobService
+	rbac          *middleware.RBACMiddleware
+	audit         *AuditService
+}
+
+func NewAdminService(server *server.Server, adminRepo repository.AdminStore, todoRepo repository.TodoStore,
+	workspaceRepo repository.WorkspaceStore, auditService *AuditService,
+) *AdminService {
+	return &AdminService{
+		server:        server,
+		adminRepo:     adminRepo,
+		todoRepo:      todoRepo,
+		workspaceRepo: workspaceRepo,
+		jobs:          server.Job,
+		rbac:          middleware.NewRBACMiddleware(server),
+		audit:         auditService,
+	}
+}
+
+func (s *AdminService) GetUser(ctx echo.Context, userID string) (*admin.User, error) {
+	logger := middleware.GetLogger(ctx)
+
+	stats, err := s.adminRepo.GetUserStats(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Str("target_user_id", userID).Msg("failed to fetch user stats")
+		return nil, err
+	}
+
+	result := &admin.User{
+		ID:    userID,
+		Role:  string(s.rbac.RoleOf(userID)),
+		Stats: *stats,
+	}
+
+	// The profile is best effort, data of users deleted in Clerk is still worth inspecting
+	profile, err := clerkUser.Get(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Warn().Err(err).Str("target_user_id", userID).Msg("failed to fetch user profile from Clerk")
+	} else {
+		result.FirstName = profile.FirstName
+		result.LastName = profile.LastName
+		result.Banned = profile.Banned
+		result.Locked = profile.Locked
+		result.CreatedAt = fromUnixMilli(&profile.CreatedAt)
+		result.LastSignInAt = fromUnixMilli(profile.LastSignInAt)
+
+		for _, email := range profile.EmailAddresses {
+			if result.Email == nil || (profile.PrimaryEmailAddressID != nil && email.ID == *profile.PrimaryEmailAddressID) {
+				result.Email = &email.EmailAddress
+			}
+		}
+	}
+
+	if result.Email == nil && stats.Todos == 0 && stats.Categories == 0 && stats.Comments == 0 {
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminUserViewed,
+		EntityType: "user",
+		EntityID:   userID,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "admin_user_viewed").
+		Str("target_user_id", userID).
+		Msg("Admin viewed user")
+
+	return result, nil
+}
+
+// GetStorageQuota tells how much attachment storage a user uses against the quota in effect
+func (s *AdminService) GetStorageQuota(ctx echo.Context, userID string) (*admin.UserStorage, error) {
+	logger := middleware.GetLogger(ctx)
+
+	override, err := s.adminRepo.GetStorageQuota(ctx.Request().Context(), userID)
+	if err != nil && !isStorageQuotaNotFound(err) {
+		logger.Error().Err(err).Str("target_user_id", userID).Msg("failed to fetch storage quota")
+		return nil, err
+	}
+
+	return s.userStorage(ctx, userID, override)
+}
+
+// SetStorageQuota overrides the storage quota of the config for a user, uploads check it right away
+func (s *AdminService) SetStorageQuota(ctx echo.Context, adminID string,
+	payload *admin.SetStorageQuotaPayload,
+) (*admin.UserStorage, error) {
+	logger := middleware.GetLogger(ctx)
+
+	quota, err := s.adminRepo.SetStorageQuota(ctx.Request().Context(), adminID, payload)
+	if err != nil {
+		logger.Error().Err(err).Str("target_user_id", payload.UserID).Msg("failed to set storage quota")
+		return nil, err
+	}
+
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminStorageQuota,
+		EntityType: "storage_quota",
+		EntityID:   payload.UserID,
+		After:      quota,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "storage_quota_set").
+		Str("target_user_id", payload.UserID).
+		Int64("quota_bytes", quota.QuotaBytes).
+		Msg("Storage quota set successfully")
+
+	return s.userStorage(ctx, payload.UserID, quota)
+}
+
+// DeleteStorageQuota removes the override of a user, who follows the config again
+func (s *AdminService) DeleteStorageQuota(ctx echo.Context, userID string) error {
+	logger := middleware.GetLogger(ctx)
+
+	quota, err := s.adminRepo.DeleteStorageQuota(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Str("target_user_id", userID).Msg("failed to delete storage quota")
+		return err
+	}
+
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminStorageQuota,
+		EntityType: "storage_quota",
+		EntityID:   userID,
+		Before:     quota,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "storage_quota_deleted").
+		Str("target_user_id", userID).
+		Msg("Storage quota deleted successfully")
+
+	return nil
+}
+
+func (s *AdminService) userStorage(ctx echo.Context, userID string, override *admin.StorageQuota) (*admin.UserStorage, error) {
+	usedBytes, err := s.todoRepo.GetUserAttachmentBytes(ctx.Request().Context(), userID)
+	if err != nil {
+		middleware.GetLogger(ctx).Error().Err(err).Str("target_user_id", userID).
+			Msg("failed to fetch attachment storage use")
+		return nil, err
+	}
+
+	storage := &admin.UserStorage{
+		UserID:            userID,
+		UsedBytes:         usedBytes,
+		QuotaBytes:        s.server.Config.Attachments.UserQuotaBytes,
+		DefaultQuotaBytes: s.server.Config.Attachments.UserQuotaBytes,
+		Override:          override,
+	}
+	if override != nil {
+		storage.QuotaBytes = override.QuotaBytes
+	}
+
+	return storage, nil
+}
+
+func isStorageQuotaNotFound(err error) bool {
+	var httpErr *errs.HTTPError
+	return errors.As(err, &httpErr) && httpErr.Code == errs.CodeStorageQuotaNotFound
+}
+
+// GetWorkspace shows what a workspace holds and whether it is frozen, the data stays untouched
+func (s *AdminService) GetWorkspace(ctx echo.Context, workspaceID string) (*admin.Workspace, error) {
+	logger := middleware.GetLogger(ctx)
+
+	stats, err := s.adminRepo.GetWorkspaceStats(ctx.Request().Context(), workspaceID)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", workspaceID).Msg("failed to fetch workspace stats")
+		return nil, err
+	}
+
+	result := &admin.Workspace{
+		ID:    workspaceID,
+		Stats: *stats,
+	}
+
+	freeze, err := s.workspaceRepo.GetFreeze(ctx.Request().Context(), workspaceID)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != errs.CodeWorkspaceNotFrozen {
+			logger.Error().Err(err).Str("workspace_id", workspaceID).Msg("failed to fetch workspace freeze")
+			return nil, err
+		}
+	} else {
+		result.Freeze = freeze
+	}
+
+	// Workspaces live in Clerk, one that left no trace here is as good as unknown
+	if result.Freeze == nil && stats.Members == 0 && stats.Todos == 0 && stats.Templates == 0 {
+		code := errs.CodeWorkspaceNotFound
+		return nil, errs.NewNotFoundError("workspace not found", false, &code)
+	}
+
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminWorkspaceViewed,
+		EntityType: "workspace",
+		EntityID:   workspaceID,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "admin_workspace_viewed").
+		Str("workspace_id", workspaceID).
+		Msg("Admin viewed workspace")
+
+	return result, nil
+}
+
+func (s *AdminService) GetJobs(ctx echo.Context, query *admin.GetJobsQuery) (*model.PaginatedResponse[admin.Job], error) {
+	logger := middleware.GetLogger(ctx)
+
+	queueInfo, err := s.jobs.Inspector.GetQueueInfo(*query.Queue)
+	if err != nil && !errors.Is(err, asynq.ErrQueueNotFound) {
+		logger.Error().Err(err).Str("queue", *query.Queue).Msg("failed to fetch queue info")
+		return nil, err
+	}
+
+	response := &model.PaginatedResponse[admin.Job]{
+		Data:  []admin.Job{},
+		Page:  *query.Page,
+		Limit: *query.Limit,
+	}
+
+	// Queues are created lazily, an unknown one simply has no jobs yet
+	if queueInfo == nil {
+		return response, nil
+	}
+
+	list := s.jobs.Inspector.ListRetryTasks
+	response.Total = queueInfo.Retry
+	if *query.State == admin.JobStateArchived {
+		list = s.jobs.Inspector.ListArchivedTasks
+		response.Total = queueInfo.Archived
+	}
+
+	tasks, err := list(*query.Queue, asynq.PageSize(*query.Limit), asynq.Page(*query.Page))
+	if err != nil {
+		logger.Error().Err(err).Str("queue", *query.Queue).Msg("failed to list jobs")
+		return nil, err
+	}
+
+	for _, task := range tasks {
+		response.Data = append(response.Data, admin.Job{
+			ID:            task.ID,
+			Queue:         task.Queue,
+			Type:          task.Type,
+			State:         task.State.String(),
+			Payload:       string(task.Payload),
+			MaxRetry:      task.MaxRetry,
+			Retried:       task.Retried,
+			LastError:     task.LastErr,
+			LastFailedAt:  nonZeroTime(task.LastFailedAt),
+			NextProcessAt: nonZeroTime(task.NextProcessAt),
+		})
+	}
+	response.TotalPages = (response.Total + response.Limit - 1) / response.Limit
+
+	return response, nil
+}
+
+// RetryJob moves a retrying or archived job back to pending so a worker picks it up right away
+func (s *AdminService) RetryJob(ctx echo.Context, payload *admin.RetryJobPayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	if err := s.jobs.Inspector.RunTask(payload.Queue, payload.ID); err != nil {
+		if errors.Is(err, asynq.ErrQueueNotFound) || errors.Is(err, asynq.ErrTaskNotFound) {
+			return errs.NewNotFoundError("job not found", false, nil)
+		}
+		logger.Error().Err(err).Str("queue", payload.Queue).Str("job_id", payload.ID).Msg("failed to retry job")
+		return err
+	}
+
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminJobRetried,
+		EntityType: "job",
+		EntityID:   payload.Queue + "/" + payload.ID,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "admin_job_retried").
+		Str("queue", payload.Queue).
+		Str("job_id", payload.ID).
+		Msg("Admin retried job")
+
+	return nil
+}
+
+func (s *AdminService) GetServerMode(ctx echo.Context) *admin.ServerMode {
+	return toAdminServerMode(s.server.Mode(ctx.Request().Context()))
+}
+
+// SetServerMode switches every instance to maintenance, read-only or back to normal
+func (s *AdminService) SetServerMode(ctx echo.Context, userID string,
+	payload *admin.SetServerModePayload,
+) (*admin.ServerMode, error) {
+	logger := middleware.GetLogger(ctx)
+
+	previous := s.server.Mode(ctx.Request().Context())
+
+	now := time.Now().UTC()
+	state := server.ModeState{
+		Mode:      config.ServerMode(payload.Mode),
+		Message:   payload.Message,
+		UpdatedBy: userID,
+		UpdatedAt: &now,
+	}
+
+	if err := s.server.SetMode(ctx.Request().Context(), state); err != nil {
+		logger.Error().Err(err).Str("mode", payload.Mode).Msg("failed to set server mode")
+		return nil, err
+	}
+
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminServerMode,
+		EntityType: "server_mode",
+		Before:     toAdminServerMode(previous),
+		After:      toAdminServerMode(state),
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "admin_server_mode_changed").
+		Str("previous_mode", string(previous.Mode)).
+		Str("mode", payload.Mode).
+		Msg("Admin changed server mode")
+
+	return toAdminServerMode(state), nil
+}
+
+// defaultFaultTTL is how long fault rules stay active when the operator doesn't say
+const defaultFaultTTL = 15 * time.Minute
+
+func (s *AdminService) GetFaultInjection(ctx echo.Context) *admin.FaultInjection {
+	return s.toAdminFaultInjection(s.server.Faults(ctx.Request().Context()))
+}
+
+// SetFaultInjection replaces the fault rules of every instance. They expire on their own, so a
+// forgotten experiment can't keep failing requests.
+func (s *AdminService) SetFaultInjection(ctx echo.Context, userID string,
+	payload *admin.SetFaultInjectionPayload,
+) (*admin.FaultInjection, error) {
+	logger := middleware.GetLogger(ctx)
+
+	faultConfig := s.server.Config.Server.FaultInjection
+	if !faultConfig.Enabled {
+		code := errs.CodeFaultInjectionDisabled
+		return nil, errs.NewConflictError("fault injection is not enabled on this deployment", false, &code)
+	}
+
+	ttl := min(defaultFaultTTL, faultConfig.MaxTTL)
+	if payload.TTLSeconds != nil {
+		ttl = min(time.Duration(*payload.TTLSeconds)*time.Second, faultConfig.MaxTTL)
+	}
+
+	rules := make([]server.FaultRule, len(payload.Rules))
+	for i, rule := range payload.Rules {
+		errorKind := server.FaultKind(rule.ErrorKind)
+		if errorKind == "" {
+			errorKind = server.FaultDatabaseBusy
+		}
+
+		rules[i] = server.FaultRule{
+			Route:          rule.Route,
+			Method:         rule.Method,
+			LatencyPercent: rule.LatencyPercent,
+			Latency:        time.Duration(rule.LatencyMs) * time.Millisecond,
+			ErrorPercent:   rule.ErrorPercent,
+			ErrorKind:      errorKind,
+			DropPercent:    rule.DropPercent,
+		}
+	}
+
+	previous := s.server.Faults(ctx.Request().Context())
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(ttl)
+	state := server.FaultState{
+		Rules:     rules,
+		UpdatedBy: userID,
+		UpdatedAt: &now,
+		ExpiresAt: &expiresAt,
+	}
+
+	if err := s.server.SetFaults(ctx.Request().Context(), state, ttl); err != nil {
+		logger.Error().Err(err).Msg("failed to set fault rules")
+		return nil, err
+	}
+
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminFaultInjection,
+		EntityType: "fault_injection",
+		Before:     s.toAdminFaultInjection(previous),
+		After:      s.toAdminFaultInjection(state),
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "admin_fault_injection_set").
+		Int("rule_count", len(rules)).
+		Time("expires_at", expiresAt).
+		Msg("Admin set fault injection rules")
+
+	return s.toAdminFaultInjection(state), nil
+}
+
+// ClearFaultInjection stops every injected fault, instances follow within the refresh interval
+func (s *AdminService) ClearFaultInjection(ctx echo.Context) error {
+	logger := middleware.GetLogger(ctx)
+
+	previous := s.server.Faults(ctx.Request().Context())
+
+	if err := s.server.ClearFaults(ctx.Request().Context()); err != nil {
+		logger.Error().Err(err).Msg("failed to clear fault rules")
+		return err
+	}
+
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminFaultInjection,
+		EntityType: "fault_injection",
+		Before:     s.toAdminFaultInjection(previous),
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "admin_fault_injection_cleared").
+		Int("rule_count", len(previous.Rules)).
+		Msg("Admin cleared fault injection rules")
+
+	return nil
+}
+
+func (s *AdminService) toAdminFaultInjection(state server.FaultState) *admin.FaultInjection {
+	rules := make([]admin.FaultRule, len(state.Rules))
+	for i, rule := range state.Rules {
+		rules[i] = admin.FaultRule{
+			Route:          rule.Route,
+			Method:         rule.Method,
+			LatencyPercent: rule.LatencyPercent,
+			LatencyMs:      rule.Latency.Milliseconds(),
+			ErrorPercent:   rule.ErrorPercent,
+			ErrorKind:      string(rule.ErrorKind),
+			DropPercent:    rule.DropPercent,
+		}
+	}
+
+	return &admin.FaultInjection{
+		Enabled:   s.server.Config.Server.FaultInjection.Enabled,
+		Rules:     rules,
+		UpdatedBy: optionalString(state.UpdatedBy),
+		UpdatedAt: state.UpdatedAt,
+		ExpiresAt: state.ExpiresAt,
+	}
+}
+
+// ReloadConfig reloads the config of the instance serving the request, SIGHUP reaches the others
+func (s *AdminService) ReloadConfig(ctx echo.Context) (*admin.ConfigReload, error) {
+	logger := middleware.GetLogger(ctx)
+
+	result, err := s.server.ReloadConfig()
+	if err != nil {
+		code := errs.CodeConfigInvalid
+		return nil, errs.NewBadRequestError("config reload rejected: "+err.Error(), false, &code, nil, nil)
+	}
+
+	reload := &admin.ConfigReload{
+		Applied:         result.Applied,
+		RestartRequired: result.RestartRequired,
+	}
+
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminConfigReload,
+		EntityType: "config",
+		After:      reload,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "admin_config_reloaded").
+		Int("applied", len(reload.Applied)).
+		Int("restart_required", len(reload.RestartRequired)).
+		Msg("Admin reloaded config")
+
+	return reload, nil
+}
+
+func toAdminServerMode(state server.ModeState) *admin.ServerMode {
+	return &admin.ServerMode{
+		Mode:      string(state.Mode),
+		Message:   state.Message,
+		UpdatedBy: optionalString(state.UpdatedBy),
+		UpdatedAt: state.UpdatedAt,
+	}
+}
+
+// GetRuntimeStats snapshots the process for diagnosing latency spikes. Reading the memory
+// statistics briefly stops the world, so this isn't meant to be scraped.
+func (s *AdminService) GetRuntimeStats(ctx echo.Context) *admin.RuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := &admin.RuntimeStats{
+		Timestamp:  time.Now().UTC(),
+		GoVersion:  runtime.Version(),
+		Goroutines: runtime.NumGoroutine(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		NumCPU:     runtime.NumCPU(),
+		Memory: admin.MemoryStats{
+			HeapAllocBytes:    mem.HeapAlloc,
+			HeapInuseBytes:    mem.HeapInuse,
+			HeapIdleBytes:     mem.HeapIdle,
+			HeapReleasedBytes: mem.HeapReleased,
+			HeapObjects:       mem.HeapObjects,
+			StackInuseBytes:   mem.StackInuse,
+			TotalAllocBytes:   mem.TotalAlloc,
+			SysBytes:          mem.Sys,
+			Mallocs:           mem.Mallocs,
+			Frees:             mem.Frees,
+		},
+		GC: admin.GCStats{
+			NumGC:        mem.NumGC,
+			NumForcedGC:  mem.NumForcedGC,
+			NextGCBytes:  mem.NextGC,
+			PauseTotalMs: durationMs(time.Duration(mem.PauseTotalNs)),
+			CPUFraction:  mem.GCCPUFraction,
+		},
+		Database: admin.DatabasePoolStats{
+			Primary:  poolStats(s.server.DB.Pool.Stat()),
+			Replicas: map[string]admin.PoolStats{},
+		},
+	}
+
+	if mem.NumGC > 0 {
+		// PauseNs is a ring buffer, the latest pause sits before NumGC
+		stats.GC.LastPauseMs = durationMs(time.Duration(mem.PauseNs[(mem.NumGC+255)%256]))
+		stats.GC.LastGCAt = nonZeroTime(time.Unix(0, int64(mem.LastGC)).UTC())
+	}
+
+	for host, replicaStats := range s.server.DB.ReplicaPoolStats() {
+		stats.Database.Replicas[host] = poolStats(replicaStats)
+	}
+
+	if s.server.Redis != nil {
+		redisStats := s.server.Redis.PoolStats()
+		stats.Redis = &admin.RedisPoolStats{
+			Hits:       redisStats.Hits,
+			Misses:     redisStats.Misses,
+			Timeouts:   redisStats.Timeouts,
+			TotalConns: redisStats.TotalConns,
+			IdleConns:  redisStats.IdleConns,
+			StaleConns: redisStats.StaleConns,
+		}
+	}
+
+	middleware.GetLogger(ctx).Info().
+		Int("goroutines", stats.Goroutines).
+		Uint64("heap_alloc_bytes", stats.Memory.HeapAllocBytes).
+		Msg("Admin read runtime stats")
+
+	return stats
+}
+
+func poolStats(stat *pgxpool.Stat) admin.PoolStats {
+	return admin.PoolStats{
+		TotalConns:           stat.TotalConns(),
+		IdleConns:            stat.IdleConns(),
+		AcquiredConns:        stat.AcquiredConns(),
+		ConstructingConns:    stat.ConstructingConns(),
+		MaxConns:             stat.MaxConns(),
+		AcquireCount:         stat.AcquireCount(),
+		EmptyAcquireCount:    stat.EmptyAcquireCount(),
+		CanceledAcquireCount: stat.CanceledAcquireCount(),
+		AcquireDurationMs:    durationMs(stat.AcquireDuration()),
+	}
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func fromUnixMilli(ms *int64) *time.Time {
+	if ms == nil || *ms == 0 {
+		return nil
+	}
+	t := time.UnixMilli(*ms).UTC()
+	return &t
+}
+
+func nonZeroTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/lib/alert"
+	"github.com/Sameer16536/ExecuTask/internal/lib/aws"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+)
+
+const (
+	alertJobQueueBacklog    = "job_queue_backlog"
+	alertDBConnectionsInUse = "db_connections_in_use"
+	alertBreakersOpen       = "circuit_breakers_open"
+)
+
+// newAlertMonitor checks the job queue backlog of the deployment, and the database pool and
+// the circuit breakers of the instance
+func newAlertMonitor(s *server.Server, cfg *config.AlertsConfig) *alert.Monitor {
+	var sink alert.Sink
+	switch cfg.Sink {
+	case config.AlertsSinkSNS:
+		sink = aws.NewSNSSink(s, cfg.SNSTopicARN)
+	default:
+		sink = alert.NewLogSink(s.Logger)
+	}
+
+	var recorder alert.Recorder
+	if cfg.CloudWatchNamespace != "" {
+		recorder = aws.NewCloudWatchRecorder(s, cfg.CloudWatchNamespace)
+	}
+
+	monitor := alert.NewMonitor(sink, recorder, s.Redis, s.Config.Primary.Env, cfg.Cooldown, s.Logger)
+
+	monitor.Register(alert.Check{
+		Name:      alertJobQueueBacklog,
+		Threshold: float64(cfg.QueueBacklog),
+		Summary: func(value float64) string {
+			return fmt.Sprintf("%.0f jobs waiting in the queues, the workers aren't keeping up", value)
+		},
+		Measure: func(ctx context.Context) (float64, error) {
+			queues, err := s.Job.Inspector.Queues()
+			if err != nil {
+				return 0, fmt.Errorf("failed to list job queues: %w", err)
+			}
+
+			pending := 0
+			for _, queue := range queues {
+				info, err := s.Job.Inspector.GetQueueInfo(queue)
+				if err != nil {
+					return 0, fmt.Errorf("failed to get info of job queue %s: %w", queue, err)
+				}
+				pending += info.Pending
+			}
+			return float64(pending), nil
+		},
+	})
+
+	monitor.Register(alert.Check{
+		Name:        alertDBConnectionsInUse,
+		Threshold:   cfg.DBPoolUsage,
+		PerInstance: true,
+		Summary: func(value float64) string {
+			return fmt.Sprintf("%.0f%% of the database connections in use, requests are about to wait for one",
+				value*100)
+		},
+		Measure: func(ctx context.Context) (float64, error) {
+			stat := s.DB.Pool.Stat()
+			if stat.MaxConns() == 0 {
+				return 0, nil
+			}
+			return float64(stat.AcquiredConns()) / float64(stat.MaxConns()), nil
+		},
+	})
+
+	monitor.Register(alert.Check{
+		Name:        alertBreakersOpen,
+		Threshold:   1,
+		PerInstance: true,
+		Summary: func(value float64) string {
+			return fmt.Sprintf("%.0f circuit breakers open (%s), calls to these dependencies fail fast",
+				value, strings.Join(s.Breakers.Open(), ", "))
+		},
+		Measure: func(ctx context.Context) (float64, error) {
+			return float64(len(s.Breakers.Open())), nil
+		},
+	})
+
+	return monitor
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/events"
+	"github.com/Sameer16536/ExecuTask/internal/lib/analytics"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+// trackEvent emits a product analytics event of the user in their active workspace. It never
+// fails the request, and does nothing when analytics is disabled.
+func trackEvent(ctx echo.Context, s *server.Server, userID, name string, properties analytics.Properties) {
+	s.Analytics.Track(userID, middleware.GetWorkspaceID(ctx), name, properties)
+}
+
+// trackEvents emits the analytics events of the changes users make
+func trackEvents(s *server.Server) events.Handler {
+	return func(ctx context.Context, event events.Event) error {
+		switch e := event.(type) {
+		case *events.TodoCreated:
+			s.Analytics.Track(e.UserID, e.WorkspaceID, analytics.EventTodoCreated, analytics.Properties{
+				"priority":     string(e.Todo.Priority),
+				"has_due_date": e.Todo.DueDate != nil,
+				"has_category": e.Todo.CategoryID != nil,
+				"is_subtask":   e.Todo.ParentTodoID != nil,
+			})
+		case *events.TodoCompleted:
+			s.Analytics.Track(e.UserID, e.WorkspaceID, analytics.EventTodoCompleted, analytics.Properties{
+				"priority":  string(e.Todo.Priority),
+				"age_hours": int(time.Since(e.Todo.CreatedAt).Hours()),
+			})
+		case *events.TodosCompleted:
+			for _, completed := range e.Todos {
+				s.Analytics.Track(e.UserID, e.WorkspaceID, analytics.EventTodoCompleted, analytics.Properties{
+					"priority":  string(completed.Priority),
+					"age_hours": int(time.Since(completed.CreatedAt).Hours()),
+					"bulk":      true,
+				})
+			}
+		case *events.TodoUncompleted:
+			s.Analytics.Track(e.UserID, e.WorkspaceID, analytics.EventTodoUncompleted, analytics.Properties{
+				"priority": string(e.Todo.Priority),
+			})
+		case *events.TodoDeleted:
+			s.Analytics.Track(e.UserID, e.WorkspaceID, analytics.EventTodoDeleted, nil)
+		case *events.CommentAdded:
+			s.Analytics.Track(e.UserID, e.WorkspaceID, analytics.EventCommentAdded, nil)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/apiclient"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// apiClientOriginsTTL bounds how long another instance keeps allowing the origins of a client
+	// after it was changed, the instance changing it reloads them at once
+	apiClientOriginsTTL = 30 * time.Second
+	// apiClientOriginsTimeout bounds the reload, preflight requests wait for it
+	apiClientOriginsTimeout = 2 * time.Second
+)
+
+type APIClientService struct {
+	server        *server.Server
+	apiClientRepo repository.APIClientStore
+	auditService  *AuditService
+
+	// origins are those of the enabled clients, as of loadedAt
+	mu       sync.Mutex
+	origins  map[string]bool
+	loadedAt time.Time
+}
+
+func NewAPIClientService(server *server.Server, apiClientRepo repository.APIClientStore,
+	auditService *AuditService,
+) *APIClientService {
+	return &APIClientService{
+		server:        server,
+		apiClientRepo: apiClientRepo,
+		auditService:  auditService,
+	}
+}
+
+// AllowsOrigin tells CORS whether a browser origin belongs to an enabled client. The origins
+// are cached for a short while, if reloading them fails the previous ones are kept.
+func (s *APIClientService) AllowsOrigin(origin string) bool {
+	origin, ok := apiclient.NormalizeOrigin(origin)
+	if !ok {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.origins == nil || time.Since(s.loadedAt) > apiClientOriginsTTL {
+		ctx, cancel := context.WithTimeout(context.Background(), apiClientOriginsTimeout)
+		defer cancel()
+
+		origins, err := s.apiClientRepo.GetAllowedOrigins(ctx)
+		if err != nil {
+			s.server.Logger.Error().Err(err).Msg("failed to load the origins of API clients")
+		} else {
+			s.origins = make(map[string]bool, len(origins))
+			for _, allowed := range origins {
+				s.origins[strings.ToLower(allowed)] = true
+			}
+		}
+		// A failed reload is retried once the TTL passed again, not on every request
+		s.loadedAt = time.Now()
+	}
+
+	return s.origins[origin]
+}
+
+// clearOrigins makes the next request reload the origins
+func (s *APIClientService) clearOrigins() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.origins = nil
+}
+
+func (s *APIClientService) GetClients(ctx echo.Context, _ *apiclient.GetAPIClientsPayload) ([]apiclient.APIClient, error) {
+	logger := middleware.GetLogger(ctx)
+
+	clients, err := s.apiClientRepo.GetClients(ctx.Request().Context())
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch API clients")
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+func (s *APIClientService) CreateClient(ctx echo.Context, userID string,
+	payload *apiclient.CreateAPIClientPayload,
+) (*apiclient.APIClient, error) {
+	logger := middleware.GetLogger(ctx)
+
+	client, err := s.apiClientRepo.CreateClient(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create API client")
+		return nil, err
+	}
+	s.clearOrigins()
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminAPIClient,
+		EntityType: "api_client",
+		EntityID:   client.ID.String(),
+		After:      client,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "api_client_created").
+		Str("api_client_id", client.ID.String()).
+		Strs("allowed_origins", client.AllowedOrigins).
+		Msg("API client created successfully")
+
+	return client, nil
+}
+
+func (s *APIClientService) UpdateClient(ctx echo.Context,
+	payload *apiclient.UpdateAPIClientPayload,
+) (*apiclient.APIClient, error) {
+	logger := middleware.GetLogger(ctx)
+
+	client, err := s.apiClientRepo.UpdateClient(ctx.Request().Context(), payload)
+	if err != nil {
+		logger.Error().Err(err).Str("api_client_id", payload.ID.String()).Msg("failed to update API client")
+		return nil, err
+	}
+	s.clearOrigins()
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminAPIClient,
+		EntityType: "api_client",
+		EntityID:   client.ID.String(),
+		After:      client,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "api_client_updated").
+		Str("api_client_id", client.ID.String()).
+		Strs("allowed_origins", client.AllowedOrigins).
+		Bool("enabled", client.Enabled).
+		Msg("API client updated successfully")
+
+	return client, nil
+}
+
+func (s *APIClientService) DeleteClient(ctx echo.Context, payload *apiclient.DeleteAPIClientPayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	client, err := s.apiClientRepo.DeleteClient(ctx.Request().Context(), payload.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("api_client_id", payload.ID.String()).Msg("failed to delete API client")
+		return err
+	}
+	s.clearOrigins()
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminAPIClient,
+		EntityType: "api_client",
+		EntityID:   client.ID.String(),
+		Before:     client,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "api_client_deleted").
+		Str("api_client_id", client.ID.String()).
+		Msg("API client deleted successfully")
+
+	return nil
+}
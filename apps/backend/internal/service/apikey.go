@@ -0,0 +1,396 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/apikey"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/time/rate"
+)
+
+const (
+	apiKeyRedisKeyPrefix = "api_key"
+	// apiKeyTTL bounds how often a key is looked up, and so how often its last use is recorded.
+	// Revoking a key or changing its plan clears it.
+	apiKeyTTL = 5 * time.Minute
+	// apiQuotaRedisKeyPrefix counts the requests of a key per UTC day
+	apiQuotaRedisKeyPrefix = "api_quota"
+
+	// apiKeySecretBytes is the randomness of a secret, apiKeyPrefixLength how much of it is shown
+	apiKeySecretBytes  = 32
+	apiKeyPrefixLength = len(apikey.SecretPrefix) + 8
+
+	// apiKeyPlanKey holds the plan of the key a request authenticated with
+	apiKeyPlanKey = "api_key_plan"
+
+	headerQuotaLimit     = "X-Quota-Limit"
+	headerQuotaRemaining = "X-Quota-Remaining"
+	headerQuotaReset     = "X-Quota-Reset"
+)
+
+// apiKeyIdentity is what a request authenticating with a key needs, cached by the secret hash
+type apiKeyIdentity struct {
+	UserID string `json:"userId"`
+	KeyID  string `json:"keyId"`
+	Plan   string `json:"plan"`
+}
+
+type APIKeyService struct {
+	server       *server.Server
+	apiKeyRepo   repository.APIKeyStore
+	auditService *AuditService
+	limiters     *apiKeyLimiters
+}
+
+func NewAPIKeyService(server *server.Server, apiKeyRepo repository.APIKeyStore,
+	auditService *AuditService,
+) *APIKeyService {
+	return &APIKeyService{
+		server:       server,
+		apiKeyRepo:   apiKeyRepo,
+		auditService: auditService,
+		limiters:     &apiKeyLimiters{},
+	}
+}
+
+func (s *APIKeyService) GetKeys(ctx echo.Context, userID string, _ *apikey.GetKeysPayload) ([]apikey.Key, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := s.checkManaged(ctx); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.apiKeyRepo.GetKeys(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch API keys")
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// CreateKey creates a key on the default plan. Its secret is in the response only.
+func (s *APIKeyService) CreateKey(ctx echo.Context, userID string,
+	payload *apikey.CreateKeyPayload,
+) (*apikey.CreatedKey, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := s.checkManaged(ctx); err != nil {
+		return nil, err
+	}
+
+	cfg := s.server.CurrentConfig().APIKeys
+	count, err := s.apiKeyRepo.CountKeys(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to count API keys")
+		return nil, err
+	}
+	if count >= cfg.MaxKeysPerUser {
+		code := errs.CodeAPIKeyLimitReached
+		return nil, errs.NewConflictError(fmt.Sprintf("a user can have at most %d API keys", cfg.MaxKeysPerUser),
+			false, &code)
+	}
+
+	random := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(random); err != nil {
+		logger.Error().Err(err).Msg("failed to generate API key secret")
+		return nil, err
+	}
+	secret := apikey.SecretPrefix + base64.RawURLEncoding.EncodeToString(random)
+	hash := sha256.Sum256([]byte(secret))
+
+	key, err := s.apiKeyRepo.CreateKey(ctx.Request().Context(), &apikey.Key{
+		UserID:     userID,
+		Name:       payload.Name,
+		Prefix:     secret[:apiKeyPrefixLength],
+		SecretHash: hash[:],
+		Plan:       cfg.DefaultPlan,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create API key")
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAPIKeyCreated,
+		EntityType: "api_key",
+		EntityID:   key.ID.String(),
+		After:      key,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "api_key_created").
+		Str("api_key_id", key.ID.String()).
+		Str("plan", key.Plan).
+		Msg("API key created successfully")
+
+	return &apikey.CreatedKey{Key: *key, Secret: secret}, nil
+}
+
+// DeleteKey revokes a key of the user, requests made with it are rejected right away
+func (s *APIKeyService) DeleteKey(ctx echo.Context, userID string, payload *apikey.DeleteKeyPayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	if err := s.checkManaged(ctx); err != nil {
+		return err
+	}
+
+	key, err := s.apiKeyRepo.DeleteKey(ctx.Request().Context(), userID, payload.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("api_key_id", payload.ID.String()).Msg("failed to delete API key")
+		return err
+	}
+	s.clearKey(ctx, key)
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAPIKeyRevoked,
+		EntityType: "api_key",
+		EntityID:   key.ID.String(),
+		Before:     key,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "api_key_revoked").
+		Str("api_key_id", key.ID.String()).
+		Msg("API key revoked successfully")
+
+	return nil
+}
+
+// GetUserKeys lists the keys of a user for operators
+func (s *APIKeyService) GetUserKeys(ctx echo.Context, query *apikey.GetUserKeysQuery) ([]apikey.Key, error) {
+	logger := middleware.GetLogger(ctx)
+
+	keys, err := s.apiKeyRepo.GetKeys(ctx.Request().Context(), query.UserID)
+	if err != nil {
+		logger.Error().Err(err).Str("target_user_id", query.UserID).Msg("failed to fetch API keys")
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// SetKeyPlan moves a key to another plan, the next request made with it is held to the new one
+func (s *APIKeyService) SetKeyPlan(ctx echo.Context, payload *apikey.SetKeyPlanPayload) (*apikey.Key, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if _, ok := s.server.CurrentConfig().APIKeys.Plans[payload.Plan]; !ok {
+		code := errs.CodeAPIPlanNotFound
+		return nil, errs.NewBadRequestError(fmt.Sprintf("no API plan is named %s", payload.Plan), false, &code, nil, nil)
+	}
+
+	key, err := s.apiKeyRepo.SetKeyPlan(ctx.Request().Context(), payload.ID, payload.Plan)
+	if err != nil {
+		logger.Error().Err(err).Str("api_key_id", payload.ID.String()).Msg("failed to set API key plan")
+		return nil, err
+	}
+	s.clearKey(ctx, key)
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminAPIKeyPlan,
+		EntityType: "api_key",
+		EntityID:   key.ID.String(),
+		After:      key,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "api_key_plan_changed").
+		Str("api_key_id", key.ID.String()).
+		Str("plan", key.Plan).
+		Msg("API key plan changed successfully")
+
+	return key, nil
+}
+
+// checkManaged lets keys be managed with a session only, so a leaked key can't mint others or
+// revoke those of its user
+func (s *APIKeyService) checkManaged(ctx echo.Context) error {
+	if !s.server.Config.APIKeys.Enabled {
+		code := errs.CodeAPIKeysDisabled
+		return errs.NewNotFoundError("API keys are not available", false, &code)
+	}
+	if middleware.GetAPIKeyID(ctx) != "" {
+		return errs.NewForbiddenError("API keys can only be managed when signed in", false)
+	}
+	return nil
+}
+
+// AuthenticateAPIKey resolves the secret of a request to the user owning the key
+func (s *APIKeyService) AuthenticateAPIKey(ctx echo.Context, secret string) (string, string, error) {
+	if !s.server.Config.APIKeys.Enabled || !strings.HasPrefix(secret, apikey.SecretPrefix) {
+		return "", "", errs.NewUnauthorizedError("Unauthorized", false)
+	}
+
+	hash := sha256.Sum256([]byte(secret))
+	identity, err := s.identify(ctx.Request().Context(), hash[:])
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) && httpErr.Status == http.StatusNotFound {
+			return "", "", errs.NewUnauthorizedError("Unauthorized", false)
+		}
+		return "", "", err
+	}
+
+	ctx.Set(apiKeyPlanKey, identity.Plan)
+
+	return identity.UserID, identity.KeyID, nil
+}
+
+// identify looks the key up by the hash of its secret, the answer is cached in Redis
+func (s *APIKeyService) identify(ctx context.Context, secretHash []byte) (*apiKeyIdentity, error) {
+	redisKey := apiKeyRedisKeyPrefix + ":" + hex.EncodeToString(secretHash)
+	if cached, err := s.server.Redis.Get(ctx, redisKey).Bytes(); err == nil {
+		var identity apiKeyIdentity
+		if err := json.Unmarshal(cached, &identity); err == nil {
+			return &identity, nil
+		}
+	}
+
+	key, err := s.apiKeyRepo.GetKeyBySecretHash(ctx, secretHash)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apiKeyRepo.TouchKey(ctx, key.ID); err != nil {
+		// The key still works, only its last use is off
+		s.server.Logger.Warn().Err(err).Str("api_key_id", key.ID.String()).Msg("failed to record API key use")
+	}
+
+	identity := &apiKeyIdentity{UserID: key.UserID, KeyID: key.ID.String(), Plan: key.Plan}
+	if cached, err := json.Marshal(identity); err == nil {
+		// Without the cached answer the next request looks it up again
+		_ = s.server.Redis.Set(ctx, redisKey, cached, apiKeyTTL).Err()
+	}
+
+	return identity, nil
+}
+
+// clearKey drops the cached identity of a key after it was revoked or moved to another plan
+func (s *APIKeyService) clearKey(ctx echo.Context, key *apikey.Key) {
+	redisKey := apiKeyRedisKeyPrefix + ":" + hex.EncodeToString(key.SecretHash)
+	if err := s.server.Redis.Del(context.WithoutCancel(ctx.Request().Context()), redisKey).Err(); err != nil {
+		middleware.GetLogger(ctx).Warn().Err(err).Str("api_key_id", key.ID.String()).Msg("failed to clear API key")
+	}
+}
+
+// GuardSession holds the requests made with a key to its plan. Past the rate limit they are
+// 429s to retry shortly, past the daily quota 402s until the quota resets at midnight UTC.
+// The quota headers tell clients where they stand on every request.
+func (s *APIKeyService) GuardSession(ctx echo.Context) error {
+	keyID := middleware.GetAPIKeyID(ctx)
+	if keyID == "" {
+		return nil
+	}
+
+	cfg := s.server.CurrentConfig().APIKeys
+	planName, _ := ctx.Get(apiKeyPlanKey).(string)
+	plan, ok := cfg.Plans[planName]
+	if !ok {
+		// A reload removed the plan of the key
+		planName = cfg.DefaultPlan
+		plan = cfg.Plans[planName]
+	}
+
+	allowed, err := s.limiters.allow(cfg.Plans, planName, keyID)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		ctx.Response().Header().Set("Retry-After", "1")
+		code := errs.CodeAPIKeyRateLimited
+		return errs.NewTooManyRequestsError("too many requests with this API key", false, &code)
+	}
+
+	if plan.RequestsPerDay == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	reset := now.Truncate(24*time.Hour).AddDate(0, 0, 1)
+	redisKey := apiQuotaRedisKeyPrefix + ":" + keyID + ":" + now.Format(time.DateOnly)
+
+	var used *redis.IntCmd
+	_, err = s.server.Redis.TxPipelined(ctx.Request().Context(), func(pipe redis.Pipeliner) error {
+		used = pipe.Incr(ctx.Request().Context(), redisKey)
+		// Kept past the reset so a late request of the day still finds its count
+		pipe.ExpireAt(ctx.Request().Context(), redisKey, reset.Add(time.Hour))
+		return nil
+	})
+	if err != nil {
+		// A quota that can't be counted doesn't take the API down
+		middleware.GetLogger(ctx).Warn().Err(err).Str("api_key_id", keyID).Msg("failed to count API quota")
+		return nil
+	}
+
+	remaining := max(plan.RequestsPerDay-used.Val(), 0)
+	header := ctx.Response().Header()
+	header.Set(headerQuotaLimit, strconv.FormatInt(plan.RequestsPerDay, 10))
+	header.Set(headerQuotaRemaining, strconv.FormatInt(remaining, 10))
+	header.Set(headerQuotaReset, strconv.FormatInt(reset.Unix(), 10))
+
+	if used.Val() > plan.RequestsPerDay {
+		header.Set("Retry-After", strconv.Itoa(int(math.Ceil(reset.Sub(now).Seconds()))))
+		code := errs.CodeAPIQuotaExceeded
+		return errs.NewPaymentRequiredError(fmt.Sprintf("the API key used up the %d requests a day of the %s plan",
+			plan.RequestsPerDay, planName), false, &code)
+	}
+
+	return nil
+}
+
+// apiKeyLimiters holds a limiter store per plan, replaced when a reload changes the plans. Keys
+// start over with a full burst then.
+type apiKeyLimiters struct {
+	mu     sync.Mutex
+	plans  map[string]config.APIPlanConfig
+	stores map[string]*echoMiddleware.RateLimiterMemoryStore
+}
+
+func (l *apiKeyLimiters) allow(plans map[string]config.APIPlanConfig, planName, keyID string) (bool, error) {
+	l.mu.Lock()
+	if !maps.Equal(plans, l.plans) {
+		l.plans = plans
+		l.stores = map[string]*echoMiddleware.RateLimiterMemoryStore{}
+	}
+	store, ok := l.stores[planName]
+	if !ok {
+		plan := plans[planName]
+		burst := plan.Burst
+		if burst == 0 {
+			burst = int(math.Ceil(plan.RequestsPerSecond))
+		}
+
+		store = echoMiddleware.NewRateLimiterMemoryStoreWithConfig(echoMiddleware.RateLimiterMemoryStoreConfig{
+			Rate:  rate.Limit(plan.RequestsPerSecond),
+			Burst: burst,
+		})
+		l.stores[planName] = store
+	}
+	l.mu.Unlock()
+
+	return store.Allow(keyID)
+}
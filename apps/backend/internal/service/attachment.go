@@ -0,0 +1,202 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/aws"
+	"github.com/Sameer16536/ExecuTask/internal/lib/imagemeta"
+	"github.com/Sameer16536/ExecuTask/internal/lib/thumbnail"
+	"github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// attachmentThumbnailSize bounds both sides of attachment thumbnails
+	attachmentThumbnailSize = 320
+	// reencodeQuality is that of JPEG images turned upright, close to what cameras store
+	reencodeQuality = 92
+)
+
+// AttachmentProcessingService strips the metadata of uploaded images and renders their
+// thumbnails, it runs in the background after the upload
+type AttachmentProcessingService struct {
+	server    *server.Server
+	todoRepo  repository.TodoStore
+	awsClient *aws.AWS
+}
+
+func NewAttachmentProcessingService(server *server.Server, todoRepo repository.TodoStore,
+	awsClient *aws.AWS,
+) *AttachmentProcessingService {
+	return &AttachmentProcessingService{
+		server:    server,
+		todoRepo:  todoRepo,
+		awsClient: awsClient,
+	}
+}
+
+// ProcessAttachment replaces the stored image with one without EXIF, GPS and text metadata and
+// stores its thumbnail. An image that can't be read is left as uploaded, without a thumbnail.
+func (s *AttachmentProcessingService) ProcessAttachment(ctx context.Context, todoID, attachmentID uuid.UUID) error {
+	log := logger.WithSpanContext(*s.server.Logger, ctx).With().
+		Str("todo_id", todoID.String()).
+		Str("attachment_id", attachmentID.String()).
+		Logger()
+	bucket := s.server.Config.AWS.S3Bucket
+
+	attachment, err := s.todoRepo.GetTodoAttachment(ctx, todoID, attachmentID)
+	if err != nil {
+		if isAttachmentNotFound(err) {
+			// Deleted before it got processed
+			return nil
+		}
+		return err
+	}
+	if attachment.ProcessedAt != nil || !attachment.IsProcessable() || !attachment.IsAvailable() {
+		return nil
+	}
+
+	data, err := s.readAttachment(ctx, attachment.DownloadKey)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > s.server.Config.Attachments.ProcessMaxBytes {
+		log.Warn().Msg("Attachment is too large to process, it is stored as uploaded")
+		fileSize := int64(len(data))
+		if attachment.FileSize != nil {
+			fileSize = *attachment.FileSize
+		}
+		return s.markProcessed(ctx, log, attachment, fileSize, nil)
+	}
+
+	stripped := s.stripMetadata(log, attachment, data)
+	if !bytes.Equal(stripped, data) {
+		err := s.awsClient.Blobs.PutObject(ctx, bucket, attachment.DownloadKey, bytes.NewReader(stripped),
+			*attachment.MimeType)
+		if err != nil {
+			return err
+		}
+	}
+
+	var thumbnailKey *string
+	img, err := thumbnail.Decode(stripped)
+	if err != nil {
+		log.Warn().Err(err).Msg("Attachment thumbnail can't be rendered")
+	} else {
+		var buf bytes.Buffer
+		fitted := thumbnail.Fit(img, attachmentThumbnailSize, attachmentThumbnailSize)
+		if err := thumbnail.EncodeJPEG(&buf, fitted); err != nil {
+			return err
+		}
+
+		key := todo.ThumbnailKey(attachment.ID)
+		if err := s.awsClient.Blobs.PutObject(ctx, bucket, key, &buf, "image/jpeg"); err != nil {
+			return err
+		}
+		thumbnailKey = &key
+	}
+
+	if err := s.markProcessed(ctx, log, attachment, int64(len(stripped)), thumbnailKey); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("event", "attachment_processed").
+		Int("original_bytes", len(data)).
+		Int("stripped_bytes", len(stripped)).
+		Bool("thumbnail", thumbnailKey != nil).
+		Msg("Attachment processed")
+
+	return nil
+}
+
+// stripMetadata returns the image without its metadata. JPEG images lose their EXIF orientation
+// with it, those not upright are turned and encoded again. The image is returned as is when it
+// can't be parsed.
+func (s *AttachmentProcessingService) stripMetadata(log zerolog.Logger, attachment *todo.TodoAttachment,
+	data []byte,
+) []byte {
+	switch *attachment.MimeType {
+	case "image/jpeg":
+		stripped, orientation, err := imagemeta.StripJPEG(data)
+		if err != nil {
+			log.Warn().Err(err).Msg("Attachment metadata can't be stripped")
+			return data
+		}
+		if orientation <= 1 {
+			return stripped
+		}
+
+		img, err := thumbnail.Decode(stripped)
+		if err != nil {
+			log.Warn().Err(err).Msg("Attachment can't be turned upright")
+			return stripped
+		}
+		var buf bytes.Buffer
+		if err := thumbnail.EncodeJPEGQuality(&buf, thumbnail.Orient(img, orientation), reencodeQuality); err != nil {
+			log.Warn().Err(err).Msg("Attachment can't be turned upright")
+			return stripped
+		}
+		return buf.Bytes()
+	case "image/png":
+		stripped, err := imagemeta.StripPNG(data)
+		if err != nil {
+			log.Warn().Err(err).Msg("Attachment metadata can't be stripped")
+			return data
+		}
+		return stripped
+	default:
+		return data
+	}
+}
+
+// markProcessed records the outcome, a thumbnail of an attachment deleted meanwhile is deleted
+func (s *AttachmentProcessingService) markProcessed(ctx context.Context, log zerolog.Logger,
+	attachment *todo.TodoAttachment, fileSize int64, thumbnailKey *string,
+) error {
+	_, err := s.todoRepo.SetAttachmentProcessed(ctx, attachment.TodoID, attachment.ID, fileSize, thumbnailKey)
+	if err == nil {
+		return nil
+	}
+	if !isAttachmentNotFound(err) {
+		return err
+	}
+
+	if thumbnailKey != nil {
+		if err := s.awsClient.Blobs.DeleteObject(ctx, s.server.Config.AWS.S3Bucket, *thumbnailKey); err != nil {
+			log.Error().Err(err).Str("s3_key", *thumbnailKey).Msg("failed to delete attachment thumbnail from S3")
+		}
+	}
+	return nil
+}
+
+// readAttachment reads the stored object, up to one byte past the processing limit so a larger
+// one is told apart without reading it whole
+func (s *AttachmentProcessingService) readAttachment(ctx context.Context, key string) ([]byte, error) {
+	body, err := s.awsClient.Blobs.GetObject(ctx, s.server.Config.AWS.S3Bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, s.server.Config.Attachments.ProcessMaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment %s: %w", key, err)
+	}
+
+	return data, nil
+}
+
+func isAttachmentNotFound(err error) bool {
+	var httpErr *errs.HTTPError
+	return errors.As(err, &httpErr) && httpErr.Code == errs.CodeAttachmentNotFound
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	auditSessionRedisKeyPrefix = "audit:session"
+	// auditSessionTTL bounds how often a long lived session is recorded as a new login
+	auditSessionTTL = 24 * time.Hour
+)
+
+type AuditService struct {
+	server    *server.Server
+	auditRepo repository.AuditStore
+	txManager repository.TxManager
+	rbac      *middleware.RBACMiddleware
+}
+
+func NewAuditService(server *server.Server, auditRepo repository.AuditStore,
+	txManager repository.TxManager,
+) *AuditService {
+	return &AuditService{
+		server:    server,
+		auditRepo: auditRepo,
+		txManager: txManager,
+		rbac:      middleware.NewRBACMiddleware(server),
+	}
+}
+
+// Record appends an event to the audit log on behalf of the authenticated user. The action
+// already happened, so a failed write is logged instead of failing the request.
+func (s *AuditService) Record(ctx echo.Context, event *audit.Event) {
+	logger := middleware.GetLogger(ctx)
+
+	entry, err := s.newEntry(ctx, event)
+	if err == nil {
+		// Written even when the client went away, the action it records is done. Inside a unit
+		// of work the entry gets a savepoint, a failed write must not abort the transaction.
+		err = s.txManager.WithinTx(context.WithoutCancel(ctx.Request().Context()), func(txCtx context.Context) error {
+			return s.auditRepo.CreateEntry(txCtx, entry)
+		})
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("action", string(event.Action)).Msg("failed to record audit entry")
+	}
+}
+
+// RecordBackground appends an event of work a background job did on behalf of a user, the entry
+// has no request details. Like Record, a failed write is only logged.
+func (s *AuditService) RecordBackground(ctx context.Context, actorID string, event *audit.Event) {
+	entry := &audit.Entry{
+		ActorID:    actorID,
+		ActorRole:  string(s.rbac.RoleOf(actorID)),
+		Action:     event.Action,
+		EntityType: optionalString(event.EntityType),
+		EntityID:   optionalString(event.EntityID),
+	}
+
+	err := setSnapshots(entry, event)
+	if err == nil {
+		err = s.txManager.WithinTx(context.WithoutCancel(ctx), func(txCtx context.Context) error {
+			return s.auditRepo.CreateEntry(txCtx, entry)
+		})
+	}
+	if err != nil {
+		log := logger.WithSpanContext(*s.server.Logger, ctx)
+		log.Error().
+			Err(err).
+			Str("action", string(event.Action)).
+			Msg("failed to record audit entry")
+	}
+}
+
+// RecordSession records the first request of every session as a login, or as an impersonation
+// when a support user acts on behalf of someone
+func (s *AuditService) RecordSession(ctx echo.Context) {
+	sessionID := middleware.GetSessionID(ctx)
+	if sessionID == "" {
+		return
+	}
+
+	redisKey := auditSessionRedisKeyPrefix + ":" + sessionID
+	created, err := s.server.Redis.SetNX(ctx.Request().Context(), redisKey, 1, auditSessionTTL).Result()
+	if err != nil {
+		// Without the marker every request would look like a new session
+		middleware.GetLogger(ctx).Warn().Err(err).Msg("failed to check audited session")
+		return
+	}
+	if !created {
+		return
+	}
+
+	action := audit.ActionLogin
+	if middleware.GetImpersonatorID(ctx) != "" {
+		action = audit.ActionImpersonation
+	}
+
+	s.Record(ctx, &audit.Event{
+		Action:     action,
+		EntityType: "session",
+		EntityID:   sessionID,
+	})
+}
+
+func (s *AuditService) GetEntries(ctx echo.Context, query *audit.GetAuditLogQuery) (*model.PaginatedResponse[audit.Entry], error) {
+	logger := middleware.GetLogger(ctx)
+
+	entries, err := s.auditRepo.GetEntries(ctx.Request().Context(), query)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch audit log")
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ExportEntries streams every matching entry to emit, the export itself is audited
+func (s *AuditService) ExportEntries(ctx echo.Context, query *audit.ExportAuditLogQuery,
+	emit func(entry *audit.Entry) error,
+) error {
+	logger := middleware.GetLogger(ctx)
+
+	count := 0
+	err := s.auditRepo.StreamEntries(ctx.Request().Context(), &query.Filter, func(entry *audit.Entry) error {
+		count++
+		return emit(entry)
+	})
+	if err != nil {
+		logger.Error().Err(err).Int("exported", count).Msg("failed to export audit log")
+		return err
+	}
+
+	// The entry tells which part of the log left the system
+	s.Record(ctx, &audit.Event{
+		Action: audit.ActionAdminAuditExported,
+		After: map[string]any{
+			"format":     *query.Format,
+			"count":      count,
+			"actorId":    query.ActorID,
+			"action":     query.Action,
+			"entityType": query.EntityType,
+			"entityId":   query.EntityID,
+			"from":       query.From,
+			"to":         query.To,
+		},
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "audit_log_exported").
+		Str("format", *query.Format).
+		Int("count", count).
+		Msg("Audit log exported successfully")
+
+	return nil
+}
+
+func (s *AuditService) newEntry(ctx echo.Context, event *audit.Event) (*audit.Entry, error) {
+	userID := middleware.GetUserID(ctx)
+
+	entry := &audit.Entry{
+		ActorID:        userID,
+		ActorRole:      string(s.rbac.RoleOf(userID)),
+		ImpersonatorID: optionalString(middleware.GetImpersonatorID(ctx)),
+		Action:         event.Action,
+		EntityType:     optionalString(event.EntityType),
+		EntityID:       optionalString(event.EntityID),
+		IPAddress:      optionalString(ctx.RealIP()),
+		UserAgent:      optionalString(ctx.Request().UserAgent()),
+		RequestID:      optionalString(middleware.GetRequestID(ctx)),
+	}
+
+	if err := setSnapshots(entry, event); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func setSnapshots(entry *audit.Entry, event *audit.Event) error {
+	var err error
+	if event.Before != nil {
+		if entry.Before, err = json.Marshal(event.Before); err != nil {
+			return err
+		}
+	}
+	if event.After != nil {
+		if entry.After, err = json.Marshal(event.After); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -2,11 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Sameer16536/ExecuTask/internal/server"
 
 	"github.com/clerk/clerk-sdk-go/v2"
+	clerkMembership "github.com/clerk/clerk-sdk-go/v2/organizationmembership"
 	clerkUser "github.com/clerk/clerk-sdk-go/v2/user"
 )
 
@@ -39,3 +41,49 @@ func (s *AuthService) GetUserEmail(ctx context.Context, userID string) (string,
 
 	return user.EmailAddresses[0].EmailAddress, nil
 }
+
+// GetVerifiedEmails returns the addresses the user proved they own, the primary one first
+func (s *AuthService) GetVerifiedEmails(ctx context.Context, userID string) ([]string, error) {
+	user, err := clerkUser.Get(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user from Clerk: %w", err)
+	}
+
+	emails := []string{}
+	for _, email := range user.EmailAddresses {
+		if email.Verification == nil || email.Verification.Status != "verified" {
+			continue
+		}
+		if user.PrimaryEmailAddressID != nil && email.ID == *user.PrimaryEmailAddressID {
+			emails = append([]string{email.EmailAddress}, emails...)
+		} else {
+			emails = append(emails, email.EmailAddress)
+		}
+	}
+
+	return emails, nil
+}
+
+// AddWorkspaceMember makes the user a basic member of the Clerk organization behind the
+// workspace, users already in it keep their role
+func (s *AuthService) AddWorkspaceMember(ctx context.Context, workspaceID, userID string) error {
+	role := "org:member"
+	_, err := clerkMembership.Create(ctx, &clerkMembership.CreateParams{
+		OrganizationID: workspaceID,
+		UserID:         &userID,
+		Role:           &role,
+	})
+	if err != nil {
+		var apiErr *clerk.APIErrorResponse
+		if errors.As(err, &apiErr) {
+			for _, e := range apiErr.Errors {
+				if e.Code == "already_a_member_in_organization" {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("failed to add member to Clerk organization: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,529 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/aws"
+	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/model/backup"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+type BackupService struct {
+	server     *server.Server
+	backupRepo repository.BackupStore
+	txManager  repository.TxManager
+	awsClient  *aws.AWS
+	audit      *AuditService
+}
+
+func NewBackupService(server *server.Server, backupRepo repository.BackupStore, txManager repository.TxManager,
+	awsClient *aws.AWS, audit *AuditService,
+) *BackupService {
+	return &BackupService{
+		server:     server,
+		backupRepo: backupRepo,
+		txManager:  txManager,
+		awsClient:  awsClient,
+		audit:      audit,
+	}
+}
+
+// RequestBackup queues a backup of the workspace. The archive is built in the background,
+// GetBackup reports when it is stored.
+func (s *BackupService) RequestBackup(ctx echo.Context, userID string, payload *backup.CreateBackupPayload) (*backup.Backup, error) {
+	logger := middleware.GetLogger(ctx)
+
+	backupItem, err := s.backupRepo.CreateBackup(ctx.Request().Context(), payload.WorkspaceID, userID)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", payload.WorkspaceID).Msg("failed to create workspace backup")
+		return nil, err
+	}
+
+	err = job.EnqueueWorkspaceBackup(ctx.Request().Context(), s.server.Job.Client, &job.WorkspaceBackupTask{
+		BackupID: backupItem.ID,
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("backup_id", backupItem.ID.String()).Msg("failed to enqueue workspace backup")
+
+		// Don't leave a backup behind that blocks the next request
+		failErr := s.backupRepo.FailBackup(context.WithoutCancel(ctx.Request().Context()), backupItem.ID,
+			"The backup could not be started, request a new one")
+		if failErr != nil {
+			logger.Error().Err(failErr).Str("backup_id", backupItem.ID.String()).Msg("failed to mark workspace backup as failed")
+		}
+		return nil, err
+	}
+
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminBackup,
+		EntityType: "workspace_backup",
+		EntityID:   backupItem.ID.String(),
+		After:      backupItem,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "workspace_backup_requested").
+		Str("backup_id", backupItem.ID.String()).
+		Str("workspace_id", backupItem.WorkspaceID).
+		Msg("Workspace backup requested")
+
+	return backupItem, nil
+}
+
+func (s *BackupService) GetBackups(ctx echo.Context, payload *backup.GetBackupsPayload) ([]backup.Backup, error) {
+	logger := middleware.GetLogger(ctx)
+
+	backups, err := s.backupRepo.GetBackups(ctx.Request().Context(), payload.WorkspaceID)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", payload.WorkspaceID).Msg("failed to fetch workspace backups")
+		return nil, err
+	}
+
+	return backups, nil
+}
+
+func (s *BackupService) GetBackup(ctx echo.Context, payload *backup.GetBackupPayload) (*backup.Backup, error) {
+	logger := middleware.GetLogger(ctx)
+
+	backupItem, err := s.backupRepo.GetBackup(ctx.Request().Context(), payload.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("backup_id", payload.ID.String()).Msg("failed to fetch workspace backup")
+		return nil, err
+	}
+
+	return backupItem, nil
+}
+
+// RequestRestore queues a restore of a completed backup into a workspace without todos, such
+// as a new organization replacing a deleted one
+func (s *BackupService) RequestRestore(ctx echo.Context, userID string, payload *backup.RestoreBackupPayload) (*backup.Restore, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	backupItem, err := s.backupRepo.GetBackup(reqCtx, payload.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("backup_id", payload.ID.String()).Msg("failed to fetch workspace backup to restore")
+		return nil, err
+	}
+
+	if backupItem.Status != backup.StatusCompleted || backupItem.ObjectKey == nil {
+		code := errs.CodeBackupNotCompleted
+		return nil, errs.NewConflictError("only a completed backup can be restored", false, &code)
+	}
+
+	if err := s.checkEmptyWorkspace(reqCtx, payload.TargetWorkspaceID); err != nil {
+		logger.Warn().Err(err).Str("workspace_id", payload.TargetWorkspaceID).Msg("restore target rejected")
+		return nil, err
+	}
+
+	restore, err := s.backupRepo.CreateRestore(reqCtx, backupItem.ID, payload.TargetWorkspaceID, userID)
+	if err != nil {
+		logger.Error().Err(err).Str("backup_id", backupItem.ID.String()).Msg("failed to create workspace restore")
+		return nil, err
+	}
+
+	err = job.EnqueueWorkspaceRestore(reqCtx, s.server.Job.Client, &job.WorkspaceRestoreTask{
+		RestoreID: restore.ID,
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("restore_id", restore.ID.String()).Msg("failed to enqueue workspace restore")
+
+		failErr := s.backupRepo.FailRestore(context.WithoutCancel(reqCtx), restore.ID,
+			"The restore could not be started, request a new one")
+		if failErr != nil {
+			logger.Error().Err(failErr).Str("restore_id", restore.ID.String()).Msg("failed to mark workspace restore as failed")
+		}
+		return nil, err
+	}
+
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminRestore,
+		EntityType: "workspace_restore",
+		EntityID:   restore.ID.String(),
+		After:      restore,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "workspace_restore_requested").
+		Str("restore_id", restore.ID.String()).
+		Str("backup_id", backupItem.ID.String()).
+		Str("source_workspace_id", backupItem.WorkspaceID).
+		Str("target_workspace_id", restore.TargetWorkspaceID).
+		Msg("Workspace restore requested")
+
+	return restore, nil
+}
+
+func (s *BackupService) GetRestore(ctx echo.Context, payload *backup.GetRestorePayload) (*backup.Restore, error) {
+	logger := middleware.GetLogger(ctx)
+
+	restore, err := s.backupRepo.GetRestore(ctx.Request().Context(), payload.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("restore_id", payload.ID.String()).Msg("failed to fetch workspace restore")
+		return nil, err
+	}
+
+	return restore, nil
+}
+
+// RunBackup builds the archive of a backup and stores it in S3. It runs in the job worker.
+func (s *BackupService) RunBackup(ctx context.Context, backupID uuid.UUID) error {
+	log := logger.WithSpanContext(*s.server.Logger, ctx).With().
+		Str("backup_id", backupID.String()).
+		Logger()
+
+	backupItem, err := s.backupRepo.StartBackup(ctx, backupID)
+	if err != nil {
+		return err
+	}
+	if backupItem.Status != backup.StatusRunning {
+		return nil
+	}
+
+	contents, err := s.backupRepo.GetWorkspaceContents(ctx, backupItem.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	archive, err := os.CreateTemp("", "workspace-backup-*.zip")
+	if err != nil {
+		return fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer func() {
+		archive.Close()
+		os.Remove(archive.Name())
+	}()
+
+	manifest, err := s.writeArchive(ctx, archive, backupItem, contents, &log)
+	if err != nil {
+		return err
+	}
+
+	sizeBytes, err := archive.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("failed to size archive: %w", err)
+	}
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind archive: %w", err)
+	}
+
+	objectKey := path.Join("backups", backupItem.WorkspaceID, backupID.String()+".zip")
+	err = s.awsClient.Blobs.PutObject(ctx, s.server.Config.AWS.S3Bucket, objectKey, archive, "application/zip")
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.backupRepo.CompleteBackup(ctx, backupID, objectKey, sizeBytes, manifest.Counts); err != nil {
+		return err
+	}
+
+	// Business event log
+	log.Info().
+		Str("event", "workspace_backup_completed").
+		Str("workspace_id", backupItem.WorkspaceID).
+		Int64("size_bytes", sizeBytes).
+		Interface("counts", manifest.Counts).
+		Int("missing_attachment_count", len(manifest.MissingAttachments)).
+		Msg("Workspace backup completed")
+
+	return nil
+}
+
+func (s *BackupService) FailBackup(ctx context.Context, backupID uuid.UUID, reason string) error {
+	return s.backupRepo.FailBackup(ctx, backupID, reason)
+}
+
+// writeArchive zips one JSON file per resource and the attachment files into w
+func (s *BackupService) writeArchive(ctx context.Context, w io.Writer, backupItem *backup.Backup,
+	contents *backup.Contents, log *zerolog.Logger,
+) (*backup.Manifest, error) {
+	zw := zip.NewWriter(w)
+
+	manifest := &backup.Manifest{
+		BackupID:           backupItem.ID.String(),
+		WorkspaceID:        backupItem.WorkspaceID,
+		GeneratedAt:        time.Now().UTC(),
+		Counts:             contents.Counts(),
+		MissingAttachments: []string{},
+	}
+
+	files := []struct {
+		name string
+		rows any
+	}{
+		{"categories.json", contents.Categories},
+		{"todos.json", contents.Todos},
+		{"comments.json", contents.Comments},
+		{"attachments.json", contents.Attachments},
+	}
+	for _, file := range files {
+		if err := writeArchiveJSON(zw, file.name, file.rows); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range contents.Attachments {
+		attachment := &contents.Attachments[i]
+		if !attachment.IsAvailable() {
+			// Files not cleared by the virus scan aren't archived, the record keeps its status
+			manifest.MissingAttachments = append(manifest.MissingAttachments, attachment.ID.String())
+			continue
+		}
+
+		copied, err := copyAttachment(ctx, s.awsClient, s.server.Config.AWS.S3Bucket, zw,
+			backup.ArchiveAttachmentName(attachment.ID), attachment.DownloadKey)
+		if err != nil {
+			return nil, err
+		}
+		if !copied {
+			log.Warn().
+				Str("attachment_id", attachment.ID.String()).
+				Str("s3_key", attachment.DownloadKey).
+				Msg("attachment missing from storage, leaving its file out of the backup")
+			manifest.MissingAttachments = append(manifest.MissingAttachments, attachment.ID.String())
+		}
+	}
+
+	if err := writeArchiveJSON(zw, "manifest.json", manifest); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// RunRestore recreates the data of a backup in the target workspace. It runs in the job worker.
+// A restore that can't succeed is failed right away rather than retried.
+func (s *BackupService) RunRestore(ctx context.Context, restoreID uuid.UUID) error {
+	log := logger.WithSpanContext(*s.server.Logger, ctx).With().
+		Str("restore_id", restoreID.String()).
+		Logger()
+
+	restore, err := s.backupRepo.StartRestore(ctx, restoreID)
+	if err != nil {
+		return err
+	}
+	if restore.Status != backup.StatusRunning {
+		return nil
+	}
+
+	backupItem, err := s.backupRepo.GetBackup(ctx, restore.BackupID)
+	if err != nil {
+		return err
+	}
+	if backupItem.Status != backup.StatusCompleted || backupItem.ObjectKey == nil {
+		return s.backupRepo.FailRestore(ctx, restoreID, "The backup is no longer available")
+	}
+
+	// The workspace may have been used since the restore was requested
+	if err := s.checkEmptyWorkspace(ctx, restore.TargetWorkspaceID); err != nil {
+		return s.backupRepo.FailRestore(ctx, restoreID, "The target workspace holds todos, restore into an empty workspace")
+	}
+
+	archive, err := s.downloadArchive(ctx, *backupItem.ObjectKey)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		archive.Close()
+		os.Remove(archive.Name())
+	}()
+
+	info, err := archive.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to size archive: %w", err)
+	}
+	zr, err := zip.NewReader(archive, info.Size())
+	if err != nil {
+		return fmt.Errorf("failed to open archive of backup %s: %w", backupItem.ID.String(), err)
+	}
+
+	contents, err := readArchiveContents(zr)
+	if err != nil {
+		return fmt.Errorf("failed to read archive of backup %s: %w", backupItem.ID.String(), err)
+	}
+
+	sources := contents.Remap(restore.TargetWorkspaceID)
+
+	// Files go first, a restore failing afterwards leaves orphans the attachment reconciliation
+	// deletes
+	missing := 0
+	attachments := contents.Attachments[:0]
+	for _, attachment := range contents.Attachments {
+		key, err := s.restoreAttachment(ctx, zr, sources[attachment.ID], &attachment)
+		if err != nil {
+			return err
+		}
+		if key == "" {
+			missing++
+			continue
+		}
+		attachment.DownloadKey = key
+		attachments = append(attachments, attachment)
+	}
+	contents.Attachments = attachments
+
+	counts := contents.Counts()
+	counts["missingAttachments"] = missing
+
+	err = s.txManager.WithinTx(ctx, func(txCtx context.Context) error {
+		categoryIDs := make(map[uuid.UUID]uuid.UUID, len(contents.Categories))
+		for i := range contents.Categories {
+			restored, err := s.backupRepo.RestoreCategory(txCtx, &contents.Categories[i])
+			if err != nil {
+				return err
+			}
+			categoryIDs[contents.Categories[i].ID] = restored.ID
+		}
+		contents.RemapCategories(categoryIDs)
+
+		if err := s.backupRepo.InsertRestoredContents(txCtx, contents); err != nil {
+			return err
+		}
+
+		_, err := s.backupRepo.CompleteRestore(txCtx, restoreID, counts)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Business event log
+	log.Info().
+		Str("event", "workspace_restore_completed").
+		Str("backup_id", backupItem.ID.String()).
+		Str("source_workspace_id", backupItem.WorkspaceID).
+		Str("target_workspace_id", restore.TargetWorkspaceID).
+		Interface("counts", counts).
+		Msg("Workspace restore completed")
+
+	return nil
+}
+
+func (s *BackupService) FailRestore(ctx context.Context, restoreID uuid.UUID, reason string) error {
+	return s.backupRepo.FailRestore(ctx, restoreID, reason)
+}
+
+func (s *BackupService) checkEmptyWorkspace(ctx context.Context, workspaceID string) error {
+	count, err := s.backupRepo.CountWorkspaceTodos(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		code := errs.CodeWorkspaceNotEmpty
+		return errs.NewConflictError("a backup can only be restored into a workspace without todos", false, &code)
+	}
+
+	return nil
+}
+
+// downloadArchive copies the archive of a backup to a temporary file, zip needs random access
+func (s *BackupService) downloadArchive(ctx context.Context, objectKey string) (*os.File, error) {
+	object, err := s.awsClient.Blobs.GetObject(ctx, s.server.Config.AWS.S3Bucket, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	archive, err := os.CreateTemp("", "workspace-restore-*.zip")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive file: %w", err)
+	}
+
+	if _, err := io.Copy(archive, object); err != nil {
+		archive.Close()
+		os.Remove(archive.Name())
+		return nil, fmt.Errorf("failed to download archive %s: %w", objectKey, err)
+	}
+
+	return archive, nil
+}
+
+// restoreAttachment uploads the file of an attachment from the archive under a new key. It
+// returns "" when the backup holds no file for it.
+func (s *BackupService) restoreAttachment(ctx context.Context, zr *zip.Reader, sourceID uuid.UUID,
+	attachment *todo.TodoAttachment,
+) (string, error) {
+	file, err := zr.Open(backup.ArchiveAttachmentName(sourceID))
+	if err != nil {
+		return "", nil
+	}
+	defer file.Close()
+
+	// Zip entries can't seek, the S3 client needs the length up front
+	body, err := io.ReadAll(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read attachment %s from archive: %w", sourceID.String(), err)
+	}
+
+	contentType := "application/octet-stream"
+	if attachment.MimeType != nil {
+		contentType = *attachment.MimeType
+	}
+
+	key := todo.AttachmentKeyPrefix + attachment.ID.String() + "_" + path.Base(attachment.Name)
+	if err := s.awsClient.Blobs.PutObject(ctx, s.server.Config.AWS.S3Bucket, key, bytes.NewReader(body), contentType); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+func readArchiveContents(zr *zip.Reader) (*backup.Contents, error) {
+	contents := &backup.Contents{}
+
+	files := []struct {
+		name string
+		rows any
+	}{
+		{"categories.json", &contents.Categories},
+		{"todos.json", &contents.Todos},
+		{"comments.json", &contents.Comments},
+		{"attachments.json", &contents.Attachments},
+	}
+	for _, file := range files {
+		if err := readArchiveJSON(zr, file.name, file.rows); err != nil {
+			return nil, err
+		}
+	}
+
+	return contents, nil
+}
+
+func readArchiveJSON(zr *zip.Reader, name string, v any) error {
+	file, err := zr.Open(name)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer file.Close()
+
+	if err := json.NewDecoder(file).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -1,24 +1,36 @@
 package service
 
 import (
+	"fmt"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/analytics"
 	"github.com/Sameer16536/ExecuTask/internal/middleware"
 	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
 	"github.com/Sameer16536/ExecuTask/internal/model/category"
 	"github.com/Sameer16536/ExecuTask/internal/repository"
 	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
 type CategoryService struct {
 	server       *server.Server
-	categoryRepo *repository.CategoryRepository
+	categoryRepo repository.CategoryStore
+	audit        *AuditService
+	txManager    repository.TxManager
 }
 
-func NewCategoryService(server *server.Server, categoryRepo *repository.CategoryRepository) *CategoryService {
+func NewCategoryService(server *server.Server, categoryRepo repository.CategoryStore,
+	auditService *AuditService, txManager repository.TxManager,
+) *CategoryService {
 	return &CategoryService{
 		server:       server,
 		categoryRepo: categoryRepo,
+		audit:        auditService,
+		txManager:    txManager,
 	}
 }
 
@@ -42,6 +54,8 @@ func (s *CategoryService) CreateCategory(ctx echo.Context, userID string,
 		Str("color", categoryItem.Color).
 		Msg("Category created successfully")
 
+	trackEvent(ctx, s.server, userID, analytics.EventCategoryCreated, nil)
+
 	return categoryItem, nil
 }
 
@@ -96,12 +110,19 @@ func (s *CategoryService) UpdateCategory(ctx echo.Context, userID string, catego
 func (s *CategoryService) DeleteCategory(ctx echo.Context, userID string, categoryID uuid.UUID) error {
 	logger := middleware.GetLogger(ctx)
 
-	err := s.categoryRepo.DeleteCategory(ctx.Request().Context(), userID, categoryID)
+	deletedCategory, err := s.categoryRepo.DeleteCategory(ctx.Request().Context(), userID, categoryID)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to delete category")
 		return err
 	}
 
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionCategoryDeleted,
+		EntityType: "category",
+		EntityID:   categoryID.String(),
+		Before:     deletedCategory,
+	})
+
 	// Business event log
 	eventLogger := middleware.GetLogger(ctx)
 	eventLogger.Info().
@@ -111,3 +132,135 @@ func (s *CategoryService) DeleteCategory(ctx echo.Context, userID string, catego
 
 	return nil
 }
+
+// ExportBundle packs the selected categories, or all of them, into a bundle other users can
+// import. Unknown IDs are reported as not found.
+func (s *CategoryService) ExportBundle(ctx echo.Context, userID string,
+	query *category.ExportBundleQuery,
+) (*category.Bundle, error) {
+	logger := middleware.GetLogger(ctx)
+
+	var categories []category.Category
+	var err error
+	if ids := query.CategoryIDs(); len(ids) > 0 {
+		categories, err = s.categoryRepo.GetCategoriesByIDs(ctx.Request().Context(), userID, ids)
+		if err == nil && len(categories) < len(ids) {
+			err = errs.NewNotFoundError("category not found", false, nil)
+		}
+	} else {
+		categories, err = s.allCategories(ctx, userID)
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch categories to export")
+		return nil, err
+	}
+
+	if len(categories) == 0 {
+		return nil, validation.NewFieldError("ids", errs.FieldCodeRequired, "there are no categories to export")
+	}
+
+	return category.NewBundle(*query.Name, query.Description, categories), nil
+}
+
+// ImportBundle creates the categories of a bundle, all or none. A category named like one
+// the user has is skipped or created under a free name, as the payload asks.
+func (s *CategoryService) ImportBundle(ctx echo.Context, userID string,
+	payload *category.ImportBundlePayload,
+) (*category.ImportResult, error) {
+	logger := middleware.GetLogger(ctx)
+
+	existing, err := s.allCategories(ctx, userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch categories to import into")
+		return nil, err
+	}
+
+	taken := make(map[string]bool, len(existing))
+	for _, categoryItem := range existing {
+		taken[categoryItem.Name] = true
+	}
+
+	result := &category.ImportResult{
+		Created: []category.Category{},
+		Skipped: []string{},
+	}
+
+	err = withinTx(ctx, s.txManager, func() error {
+		for _, item := range payload.Bundle.Categories {
+			name := item.Name
+			if taken[name] {
+				if *payload.OnConflict == category.ImportConflictSkip {
+					result.Skipped = append(result.Skipped, name)
+					continue
+				}
+				name = freeName(name, taken)
+			}
+
+			created, err := s.categoryRepo.CreateCategory(ctx.Request().Context(), userID, &category.CreateCategoryPayload{
+				Name:        name,
+				Color:       item.Color,
+				Description: item.Description,
+			})
+			if err != nil {
+				return err
+			}
+
+			taken[name] = true
+			result.Created = append(result.Created, *created)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to import category bundle")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "category_bundle_imported").
+		Str("bundle", payload.Bundle.Name).
+		Int("created_count", len(result.Created)).
+		Int("skipped_count", len(result.Skipped)).
+		Msg("Category bundle imported successfully")
+
+	trackEvent(ctx, s.server, userID, analytics.EventCategoryBundleImported, analytics.Properties{
+		"created_count": len(result.Created),
+		"skipped_count": len(result.Skipped),
+	})
+
+	return result, nil
+}
+
+// allCategories pages through every category of the user
+func (s *CategoryService) allCategories(ctx echo.Context, userID string) ([]category.Category, error) {
+	categories := []category.Category{}
+	limit, sort, order := 100, "name", "asc"
+
+	for page := 1; ; page++ {
+		result, err := s.categoryRepo.GetCategories(ctx.Request().Context(), userID, &category.GetCategoriesQuery{
+			Page:  &page,
+			Limit: &limit,
+			Sort:  &sort,
+			Order: &order,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		categories = append(categories, result.Data...)
+		if len(result.Data) < limit || len(categories) >= result.Total {
+			return categories, nil
+		}
+	}
+}
+
+// freeName numbers a category name until no category of the user has it
+func freeName(name string, taken map[string]bool) string {
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)", name, i)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
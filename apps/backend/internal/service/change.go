@@ -0,0 +1,85 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/change"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+// idleCursorLag keeps idle cursors behind writes that may still be committing
+const idleCursorLag = time.Minute
+
+type ChangeService struct {
+	server     *server.Server
+	changeRepo repository.ChangeStore
+}
+
+func NewChangeService(server *server.Server, changeRepo repository.ChangeStore) *ChangeService {
+	return &ChangeService{
+		server:     server,
+		changeRepo: changeRepo,
+	}
+}
+
+func (s *ChangeService) GetChanges(ctx echo.Context, userID string, query *change.GetChangesQuery) (*change.ChangesResponse, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	var afterID int64
+	if query.Cursor != nil {
+		var err error
+		afterID, err = change.DecodeCursor(*query.Cursor)
+		if err != nil {
+			return nil, errs.NewBadRequestError("invalid cursor", false, nil, nil, nil)
+		}
+
+		oldestID, err := s.changeRepo.GetOldestChangeID(reqCtx)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to fetch oldest change id")
+			return nil, err
+		}
+
+		// Events after the cursor may have been purged, the client has to resync from scratch
+		if oldestID > 0 && afterID < oldestID-1 {
+			code := errs.CodeCursorExpired
+			return nil, errs.NewGoneError("cursor is older than the change retention window, please resync", false, &code)
+		}
+	}
+
+	changes, err := s.changeRepo.GetChangesAfter(reqCtx, userID, afterID, *query.Limit+1)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch changes")
+		return nil, err
+	}
+
+	hasMore := len(changes) > *query.Limit
+	if hasMore {
+		changes = changes[:*query.Limit]
+	}
+
+	nextID := afterID
+	if len(changes) > 0 {
+		nextID = changes[len(changes)-1].ID
+	} else {
+		// Move idle cursors forward so they don't fall out of the retention window
+		latestID, err := s.changeRepo.GetLatestChangeIDBefore(reqCtx, time.Now().Add(-idleCursorLag))
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to fetch latest change id")
+			return nil, err
+		}
+		if latestID > nextID {
+			nextID = latestID
+		}
+	}
+
+	return &change.ChangesResponse{
+		Changes:    changes,
+		NextCursor: change.EncodeCursor(nextID),
+		HasMore:    hasMore,
+	}, nil
+}
@@ -1,25 +1,54 @@
 package service
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/events"
+	"github.com/Sameer16536/ExecuTask/internal/lib/email"
+	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/logger"
 	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
 	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
 	"github.com/Sameer16536/ExecuTask/internal/repository"
 	"github.com/Sameer16536/ExecuTask/internal/server"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
 )
 
+// commentContentLimit is the longest comment the payloads accept, email replies are cut to fit
+const commentContentLimit = 1000
+
 type CommentService struct {
 	server      *server.Server
-	commentRepo *repository.CommentRepository
-	todoRepo    *repository.TodoRepository
+	commentRepo repository.CommentStore
+	todoRepo    repository.TodoStore
+	audit       *AuditService
+	auth        *AuthService
+	previews    *PreviewService
 }
 
-func NewCommentService(server *server.Server, commentRepo *repository.CommentRepository, todoRepo *repository.TodoRepository) *CommentService {
+func NewCommentService(server *server.Server, commentRepo repository.CommentStore, todoRepo repository.TodoStore,
+	auditService *AuditService, authService *AuthService, previewService *PreviewService,
+) *CommentService {
 	return &CommentService{
 		server:      server,
 		commentRepo: commentRepo,
 		todoRepo:    todoRepo,
+		audit:       auditService,
+		auth:        authService,
+		previews:    previewService,
 	}
 }
 
@@ -41,13 +70,11 @@ func (s *CommentService) AddComment(ctx echo.Context, userID string, todoID uuid
 		return nil, err
 	}
 
-	// Business event log
-	eventLogger := middleware.GetLogger(ctx)
-	eventLogger.Info().
-		Str("event", "comment_added").
-		Str("comment_id", commentItem.ID.String()).
-		Str("todo_id", todoID.String()).
-		Msg("Comment added successfully")
+	publishEvents(ctx, s.server, &events.CommentAdded{
+		Actor:   eventActor(ctx, userID),
+		Comment: commentItem,
+		Source:  events.SourceAPI,
+	})
 
 	return commentItem, nil
 }
@@ -68,6 +95,14 @@ func (s *CommentService) GetCommentsByTodoID(ctx echo.Context, userID string, to
 		return nil, err
 	}
 
+	texts := make([]string, len(comments))
+	for i, c := range comments {
+		texts[i] = c.Content
+	}
+	for i, previews := range s.previews.PreviewsFor(ctx, texts) {
+		comments[i].LinkPreviews = previews
+	}
+
 	return comments, nil
 }
 
@@ -87,12 +122,7 @@ func (s *CommentService) UpdateComment(ctx echo.Context, userID string, commentI
 		return nil, err
 	}
 
-	// Business event log
-	eventLogger := middleware.GetLogger(ctx)
-	eventLogger.Info().
-		Str("event", "comment_updated").
-		Str("comment_id", commentItem.ID.String()).
-		Msg("Comment updated successfully")
+	publishEvents(ctx, s.server, &events.CommentUpdated{Actor: eventActor(ctx, userID), Comment: commentItem})
 
 	return commentItem, nil
 }
@@ -101,7 +131,7 @@ func (s *CommentService) DeleteComment(ctx echo.Context, userID string, commentI
 	logger := middleware.GetLogger(ctx)
 
 	// Validate comment exists and belongs to user
-	_, err := s.commentRepo.GetCommentByID(ctx.Request().Context(), userID, commentID)
+	commentItem, err := s.commentRepo.GetCommentByID(ctx.Request().Context(), userID, commentID)
 	if err != nil {
 		logger.Error().Err(err).Msg("comment validation failed")
 		return err
@@ -113,12 +143,166 @@ func (s *CommentService) DeleteComment(ctx echo.Context, userID string, commentI
 		return err
 	}
 
-	// Business event log
-	eventLogger := middleware.GetLogger(ctx)
-	eventLogger.Info().
-		Str("event", "comment_deleted").
-		Str("comment_id", commentID.String()).
-		Msg("Comment deleted successfully")
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionCommentDeleted,
+		EntityType: "comment",
+		EntityID:   commentID.String(),
+		Before:     commentItem,
+	})
+
+	publishEvents(ctx, s.server, &events.CommentDeleted{Actor: eventActor(ctx, userID), CommentID: commentID})
+
+	return nil
+}
+
+// MarkCommentsRead resets the unread comment counter of a todo
+func (s *CommentService) MarkCommentsRead(ctx echo.Context, userID string, todoID uuid.UUID) (*todo.Todo, error) {
+	logger := middleware.GetLogger(ctx)
+
+	todoItem, err := s.todoRepo.MarkCommentsRead(ctx.Request().Context(), userID, todoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to mark comments as read")
+		return nil, err
+	}
+
+	publishEvents(ctx, s.server, &events.CommentsRead{Actor: eventActor(ctx, userID), TodoID: todoID})
+
+	return todoItem, nil
+}
+
+// ReplyAddress issues the reply address of a notification email about a todo, each email gets
+// its own. It is empty when email replies are disabled.
+func (s *CommentService) ReplyAddress(ctx context.Context, userID string, todoID uuid.UUID) (string, error) {
+	cfg := s.server.Config.InboundEmail
+	if cfg == nil || cfg.ReplyDomain == "" {
+		return "", nil
+	}
+
+	secret := make([]byte, 16)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate reply token: %w", err)
+	}
+
+	token := &comment.ReplyToken{
+		Token:     hex.EncodeToString(secret),
+		ExpiresAt: time.Now().Add(cfg.TokenTTL),
+		UserID:    userID,
+		TodoID:    todoID,
+	}
+	if err := s.commentRepo.CreateReplyToken(ctx, token); err != nil {
+		return "", err
+	}
+
+	return email.ReplyAddress(token.Token, cfg.ReplyDomain), nil
+}
+
+// ReceiveEmailReply queues an email the inbound provider received at a reply address, the
+// provider gets its answer before the reply is looked at
+func (s *CommentService) ReceiveEmailReply(ctx echo.Context, secret string, payload *comment.EmailReplyPayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	cfg := s.server.Config.InboundEmail
+	if cfg == nil || cfg.ReplyDomain == "" {
+		return errs.NewNotFoundError("email replies are not enabled", false, nil)
+	}
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(cfg.Secret)) != 1 {
+		return errs.NewUnauthorizedError("invalid inbound email secret", false)
+	}
+
+	err := job.EnqueueCommentEmailReply(ctx.Request().Context(), s.server.Job.Client, &job.CommentEmailReplyTask{
+		MessageID: payload.MessageID,
+		From:      payload.From,
+		To:        payload.To,
+		Text:      payload.Text,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to enqueue comment email reply")
+		return err
+	}
+
+	logger.Info().
+		Str("event", "comment_email_reply_received").
+		Str("message_id", payload.MessageID).
+		Msg("Comment email reply received")
+
+	return nil
+}
+
+// PostEmailReply posts the reply as a comment of the user the email went to. Replies that can't
+// be posted, to an unknown or expired address, from another sender or without text of their
+// own, are dropped.
+func (s *CommentService) PostEmailReply(ctx context.Context, reply *job.CommentEmailReplyTask) error {
+	log := logger.WithSpanContext(*s.server.Logger, ctx).With().
+		Str("message_id", reply.MessageID).
+		Logger()
+
+	cfg := s.server.Config.InboundEmail
+	if cfg == nil || cfg.ReplyDomain == "" {
+		log.Warn().Msg("Dropping comment email reply, email replies are disabled")
+		return nil
+	}
+
+	tokenValue, ok := email.ParseReplyToken(reply.To, cfg.ReplyDomain)
+	if !ok {
+		log.Warn().Msg("Dropping comment email reply without a reply address")
+		return nil
+	}
+
+	token, err := s.commentRepo.GetReplyToken(ctx, tokenValue)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.Warn().Msg("Dropping comment email reply to an unknown or expired address")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// The address alone would let anyone it was forwarded to comment as the user
+	userEmail, err := s.auth.GetUserEmail(ctx, token.UserID)
+	if err != nil {
+		return err
+	}
+	sender, err := mail.ParseAddress(reply.From)
+	if err != nil || !strings.EqualFold(sender.Address, userEmail) {
+		log.Warn().Str("user_id", token.UserID).Msg("Dropping comment email reply from another sender")
+		return nil
+	}
+
+	content := email.ExtractReply(reply.Text)
+	if content == "" {
+		log.Warn().Str("user_id", token.UserID).Msg("Dropping comment email reply without text")
+		return nil
+	}
+	if runes := []rune(content); len(runes) > commentContentLimit {
+		content = strings.TrimRight(string(runes[:commentContentLimit-1]), " \n") + "…"
+	}
+
+	todoItem, err := s.todoRepo.CheckTodoExists(ctx, token.UserID, token.TodoID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			log.Warn().Str("todo_id", token.TodoID.String()).Msg("Dropping comment email reply to a deleted todo")
+			return nil
+		}
+		return err
+	}
+
+	commentItem, err := s.commentRepo.AddComment(ctx, token.UserID, token.TodoID, &comment.AddCommentPayload{
+		TodoID:  token.TodoID,
+		Content: content,
+	})
+	if err != nil {
+		return err
+	}
+
+	actor := events.Actor{UserID: token.UserID}
+	if todoItem.WorkspaceID != nil {
+		actor.WorkspaceID = *todoItem.WorkspaceID
+	}
+	s.server.Events.Publish(log.WithContext(ctx), &events.CommentAdded{
+		Actor:   actor,
+		Comment: commentItem,
+		Source:  events.SourceEmail,
+	})
 
 	return nil
 }
@@ -0,0 +1,423 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/aws"
+	"github.com/Sameer16536/ExecuTask/internal/lib/blob"
+	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/lib/thumbnail"
+	"github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/cover"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// maxStockImages caps the stock library listed, every image gets a signed link
+const maxStockImages = 200
+
+var stockImageExtensions = []string{".jpg", ".jpeg", ".png", ".gif"}
+
+type CoverService struct {
+	server    *server.Server
+	coverRepo repository.CoverStore
+	todoRepo  repository.TodoStore
+	awsClient *aws.AWS
+}
+
+func NewCoverService(server *server.Server, coverRepo repository.CoverStore, todoRepo repository.TodoStore,
+	awsClient *aws.AWS,
+) *CoverService {
+	return &CoverService{
+		server:    server,
+		coverRepo: coverRepo,
+		todoRepo:  todoRepo,
+		awsClient: awsClient,
+	}
+}
+
+func (s *CoverService) GetCover(ctx echo.Context, userID string, todoID uuid.UUID) (*cover.Cover, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	if _, err := s.todoRepo.CheckTodoExists(reqCtx, userID, todoID); err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return nil, err
+	}
+
+	item, err := s.coverRepo.GetCover(reqCtx, userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.signVariants(reqCtx, item); err != nil {
+		logger.Error().Err(err).Msg("failed to sign cover variant URLs")
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// SetCover picks the image of the cover of a todo and enqueues the rendering of its thumbnails.
+// The thumbnails of the cover it replaces are deleted.
+func (s *CoverService) SetCover(ctx echo.Context, userID string, payload *cover.SetCoverPayload) (*cover.Cover, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	if _, err := s.todoRepo.CheckTodoExists(reqCtx, userID, payload.TodoID); err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return nil, err
+	}
+
+	item := &cover.Cover{
+		TodoID:     payload.TodoID,
+		UserID:     userID,
+		Generation: uuid.New(),
+	}
+
+	if payload.AttachmentID != nil {
+		attachment, err := s.todoRepo.GetTodoAttachment(reqCtx, payload.TodoID, *payload.AttachmentID)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get attachment details")
+			return nil, err
+		}
+		if err := errAttachmentUnavailable(attachment); err != nil {
+			return nil, err
+		}
+		if !cover.IsImage(attachment.MimeType) {
+			return nil, validation.NewFieldError("attachmentId", errs.FieldCodeInvalidReference,
+				"the attachment is not a JPEG, PNG or GIF image")
+		}
+		if attachment.FileSize != nil && *attachment.FileSize > s.server.Config.Covers.MaxSourceBytes {
+			return nil, validation.NewFieldError("attachmentId", errs.FieldCodeTooLarge,
+				"the image is too large to be used as a cover")
+		}
+
+		item.Source = cover.SourceAttachment
+		item.AttachmentID = &attachment.ID
+	} else {
+		exists, err := s.stockImageExists(reqCtx, *payload.StockKey)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to look up stock image")
+			return nil, err
+		}
+		if !exists {
+			return nil, validation.NewFieldError("stockKey", errs.FieldCodeInvalidReference,
+				"stockKey must name an image of the stock library")
+		}
+
+		item.Source = cover.SourceStock
+		item.StockKey = payload.StockKey
+	}
+
+	previous, err := s.coverRepo.GetCover(reqCtx, userID, payload.TodoID)
+	if err != nil && !isCoverNotFound(err) {
+		logger.Error().Err(err).Msg("failed to fetch current cover")
+		return nil, err
+	}
+
+	saved, err := s.coverRepo.SetCover(reqCtx, item)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to set cover")
+		return nil, err
+	}
+
+	if previous != nil {
+		s.deleteVariants(reqCtx, previous)
+	}
+
+	err = job.EnqueueCoverRender(reqCtx, s.server.Job.Client, &job.CoverRenderTask{
+		UserID:     userID,
+		TodoID:     saved.TodoID,
+		Generation: saved.Generation,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to enqueue cover render")
+		if _, failErr := s.coverRepo.SaveVariants(reqCtx, saved.TodoID, saved.Generation, cover.StatusFailed,
+			nil); failErr == nil {
+			saved.Status = cover.StatusFailed
+		}
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "todo_cover_set").
+		Str("todo_id", saved.TodoID.String()).
+		Str("source", string(saved.Source)).
+		Msg("Todo cover set successfully")
+
+	return saved, nil
+}
+
+func (s *CoverService) DeleteCover(ctx echo.Context, userID string, todoID uuid.UUID) error {
+	logger := middleware.GetLogger(ctx)
+
+	deleted, err := s.coverRepo.DeleteCover(ctx.Request().Context(), userID, todoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to delete cover")
+		return err
+	}
+
+	s.deleteVariants(ctx.Request().Context(), deleted)
+
+	logger.Info().Str("todo_id", todoID.String()).Msg("deleted todo cover")
+
+	return nil
+}
+
+// GetStockImages lists the stock library covers can be picked from, with signed links to show
+// them by
+func (s *CoverService) GetStockImages(ctx echo.Context) ([]cover.StockImage, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+	bucket := s.server.Config.AWS.S3Bucket
+
+	keys := []string{}
+	err := s.awsClient.Blobs.ListObjects(reqCtx, bucket, s.server.Config.Covers.StockPrefix,
+		func(objects []blob.ObjectInfo) error {
+			for _, object := range objects {
+				if len(keys) < maxStockImages && isStockImage(object.Key) {
+					keys = append(keys, object.Key)
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to list stock images")
+		return nil, err
+	}
+
+	images := make([]cover.StockImage, 0, len(keys))
+	for _, key := range keys {
+		url, err := s.awsClient.Blobs.CreatePresignedUrl(reqCtx, bucket, key)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to generate presigned URL")
+			return nil, err
+		}
+
+		name := path.Base(key)
+		images = append(images, cover.StockImage{
+			Key:  key,
+			Name: strings.TrimSuffix(name, path.Ext(name)),
+			URL:  url,
+		})
+	}
+
+	return images, nil
+}
+
+// AttachCover attaches the cover of a todo read on its own. A cover is an extra, a failed
+// lookup is logged and leaves it out.
+func (s *CoverService) AttachCover(ctx echo.Context, todoItem *todo.PopulatedTodo) {
+	reqCtx := ctx.Request().Context()
+
+	item, err := s.coverRepo.GetCover(reqCtx, todoItem.UserID, todoItem.ID)
+	if err != nil {
+		if !isCoverNotFound(err) {
+			middleware.GetLogger(ctx).Error().Err(err).Msg("failed to fetch todo cover")
+		}
+		return
+	}
+
+	if err := s.signVariants(reqCtx, item); err != nil {
+		middleware.GetLogger(ctx).Error().Err(err).Msg("failed to sign cover variant URLs")
+		return
+	}
+
+	todoItem.Cover = item
+}
+
+// RenderCover renders and stores the thumbnails of every size. An image that can't be rendered
+// fails the cover for good, only reaching the bucket fails the task.
+func (s *CoverService) RenderCover(ctx context.Context, userID string, todoID, generation uuid.UUID) error {
+	log := logger.WithSpanContext(*s.server.Logger, ctx).With().
+		Str("user_id", userID).
+		Str("todo_id", todoID.String()).
+		Logger()
+	bucket := s.server.Config.AWS.S3Bucket
+
+	item, err := s.coverRepo.GetCover(ctx, userID, todoID)
+	if err != nil {
+		if isCoverNotFound(err) {
+			// Removed before it got rendered
+			return nil
+		}
+		return err
+	}
+	if item.Generation != generation || item.Status != cover.StatusPending {
+		return nil
+	}
+
+	var sourceKey string
+	switch item.Source {
+	case cover.SourceAttachment:
+		attachment, err := s.todoRepo.GetTodoAttachment(ctx, todoID, *item.AttachmentID)
+		if err != nil {
+			var httpErr *errs.HTTPError
+			if errors.As(err, &httpErr) && httpErr.Code == errs.CodeAttachmentNotFound {
+				return s.failCover(ctx, log, item, "the attachment was deleted")
+			}
+			return err
+		}
+		if !attachment.IsAvailable() {
+			return s.failCover(ctx, log, item, "the attachment is quarantined")
+		}
+		sourceKey = attachment.DownloadKey
+	case cover.SourceStock:
+		sourceKey = *item.StockKey
+	}
+
+	data, err := s.readSource(ctx, sourceKey)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > s.server.Config.Covers.MaxSourceBytes {
+		return s.failCover(ctx, log, item, "the image is too large")
+	}
+
+	img, err := thumbnail.Decode(data)
+	if err != nil {
+		return s.failCover(ctx, log, item, err.Error())
+	}
+
+	variantKeys := map[string]string{}
+	for _, size := range cover.Sizes {
+		var buf bytes.Buffer
+		if err := thumbnail.EncodeJPEG(&buf, thumbnail.Fill(img, size.Width, size.Height)); err != nil {
+			return err
+		}
+
+		key := cover.VariantKey(todoID, generation, size.Name)
+		if err := s.awsClient.Blobs.PutObject(ctx, bucket, key, &buf, "image/jpeg"); err != nil {
+			return err
+		}
+		variantKeys[size.Name] = key
+	}
+
+	saved, err := s.coverRepo.SaveVariants(ctx, todoID, generation, cover.StatusReady, variantKeys)
+	if err != nil {
+		return err
+	}
+	if !saved {
+		// Replaced while rendering, the thumbnails belong to nothing
+		s.deleteVariants(ctx, &cover.Cover{TodoID: todoID, VariantKeys: variantKeys})
+		return nil
+	}
+
+	log.Info().
+		Str("event", "todo_cover_rendered").
+		Str("source", string(item.Source)).
+		Int("source_bytes", len(data)).
+		Msg("Todo cover rendered")
+
+	return nil
+}
+
+func (s *CoverService) FailCover(ctx context.Context, todoID, generation uuid.UUID) error {
+	_, err := s.coverRepo.SaveVariants(ctx, todoID, generation, cover.StatusFailed, nil)
+	return err
+}
+
+func (s *CoverService) failCover(ctx context.Context, log zerolog.Logger, item *cover.Cover, reason string) error {
+	log.Warn().Str("reason", reason).Msg("Todo cover can't be rendered")
+	return s.FailCover(ctx, item.TodoID, item.Generation)
+}
+
+// readSource reads the source image, up to one byte past the size limit so a larger one is told
+// apart without reading it whole
+func (s *CoverService) readSource(ctx context.Context, key string) ([]byte, error) {
+	body, err := s.awsClient.Blobs.GetObject(ctx, s.server.Config.AWS.S3Bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, s.server.Config.Covers.MaxSourceBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cover source %s: %w", key, err)
+	}
+
+	return data, nil
+}
+
+// signVariants signs links to the thumbnails of a ready cover
+func (s *CoverService) signVariants(ctx context.Context, item *cover.Cover) error {
+	item.Variants = map[string]string{}
+	if item.Status != cover.StatusReady {
+		return nil
+	}
+
+	for size, key := range item.VariantKeys {
+		url, err := s.awsClient.Blobs.CreatePresignedUrl(ctx, s.server.Config.AWS.S3Bucket, key)
+		if err != nil {
+			return err
+		}
+		item.Variants[size] = url
+	}
+
+	return nil
+}
+
+// deleteVariants deletes the thumbnails of a cover in the background, those left behind are
+// swept by the attachment-reconcile job
+func (s *CoverService) deleteVariants(ctx context.Context, item *cover.Cover) {
+	if len(item.VariantKeys) == 0 {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		for _, key := range item.VariantKeys {
+			if err := s.awsClient.Blobs.DeleteObject(ctx, s.server.Config.AWS.S3Bucket, key); err != nil {
+				s.server.Logger.Error().
+					Err(err).
+					Str("s3_key", key).
+					Str("todo_id", item.TodoID.String()).
+					Msg("failed to delete cover thumbnail from S3")
+			}
+		}
+	}()
+}
+
+// stockImageExists tells whether the key names an image of the stock library
+func (s *CoverService) stockImageExists(ctx context.Context, key string) (bool, error) {
+	if !strings.HasPrefix(key, s.server.Config.Covers.StockPrefix) || !isStockImage(key) {
+		return false, nil
+	}
+
+	exists := false
+	err := s.awsClient.Blobs.ListObjects(ctx, s.server.Config.AWS.S3Bucket, key, func(objects []blob.ObjectInfo) error {
+		for _, object := range objects {
+			if object.Key == key {
+				exists = true
+			}
+		}
+		return nil
+	})
+
+	return exists, err
+}
+
+func isStockImage(key string) bool {
+	return slices.Contains(stockImageExtensions, strings.ToLower(path.Ext(key)))
+}
+
+func isCoverNotFound(err error) bool {
+	var httpErr *errs.HTTPError
+	return errors.As(err, &httpErr) && httpErr.Code == errs.CodeCoverNotFound
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/dependency"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type DependencyService struct {
+	server         *server.Server
+	dependencyRepo repository.DependencyStore
+	todoRepo       repository.TodoStore
+	txManager      repository.TxManager
+}
+
+func NewDependencyService(server *server.Server, dependencyRepo repository.DependencyStore,
+	todoRepo repository.TodoStore, txManager repository.TxManager,
+) *DependencyService {
+	return &DependencyService{
+		server:         server,
+		dependencyRepo: dependencyRepo,
+		todoRepo:       todoRepo,
+		txManager:      txManager,
+	}
+}
+
+func (s *DependencyService) GetDependencies(ctx echo.Context, userID string, todoID uuid.UUID,
+) (*dependency.Dependencies, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	if _, err := s.todoRepo.CheckTodoExists(reqCtx, userID, todoID); err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return nil, err
+	}
+
+	blockedBy, err := s.dependencyRepo.GetBlockedBy(reqCtx, userID, todoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch the todos blocking the todo")
+		return nil, err
+	}
+
+	blocking, err := s.dependencyRepo.GetBlocking(reqCtx, userID, todoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch the todos blocked by the todo")
+		return nil, err
+	}
+
+	return &dependency.Dependencies{
+		TodoID:    todoID,
+		BlockedBy: blockedBy,
+		Blocking:  blocking,
+	}, nil
+}
+
+// AddDependency blocks a todo on another, unless the other already waits on it directly or
+// through others
+func (s *DependencyService) AddDependency(ctx echo.Context, userID string,
+	payload *dependency.AddDependencyPayload,
+) (*dependency.Dependency, error) {
+	logger := middleware.GetLogger(ctx)
+
+	var link *dependency.Dependency
+	err := s.txManager.WithinTx(ctx.Request().Context(), func(txCtx context.Context) error {
+		for _, todoID := range []uuid.UUID{payload.TodoID, payload.DependsOnID} {
+			if _, err := s.todoRepo.CheckTodoExists(txCtx, userID, todoID); err != nil {
+				return err
+			}
+		}
+
+		downstream, err := s.dependencyRepo.GetDownstreamDependencies(txCtx, userID, payload.TodoID)
+		if err != nil {
+			return err
+		}
+		if slices.ContainsFunc(downstream, func(existing dependency.Dependency) bool {
+			return existing.TodoID == payload.DependsOnID
+		}) {
+			code := errs.CodeDependencyCycle
+			return errs.NewConflictError("that todo already waits on this one", false, &code)
+		}
+
+		link, err = s.dependencyRepo.AddDependency(txCtx, userID, payload.TodoID, payload.DependsOnID)
+		return err
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to add todo dependency")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "todo_dependency_added").
+		Str("todo_id", link.TodoID.String()).
+		Str("depends_on_id", link.DependsOnID.String()).
+		Msg("Todo dependency added successfully")
+
+	return link, nil
+}
+
+func (s *DependencyService) RemoveDependency(ctx echo.Context, userID string,
+	payload *dependency.RemoveDependencyPayload,
+) error {
+	logger := middleware.GetLogger(ctx)
+
+	err := s.dependencyRepo.RemoveDependency(ctx.Request().Context(), userID, payload.TodoID, payload.DependsOnID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to remove todo dependency")
+		return err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "todo_dependency_removed").
+		Str("todo_id", payload.TodoID.String()).
+		Str("depends_on_id", payload.DependsOnID.String()).
+		Msg("Todo dependency removed successfully")
+
+	return nil
+}
+
+// PreviewShift returns the due date changes ShiftDueDate would make, nothing is written
+func (s *DependencyService) PreviewShift(ctx echo.Context, userID string,
+	payload *dependency.ShiftDueDatePayload,
+) (*dependency.Shift, error) {
+	logger := middleware.GetLogger(ctx)
+
+	shift, err := s.planShift(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to plan due date shift")
+		return nil, err
+	}
+
+	return shift, nil
+}
+
+// ShiftDueDate moves the due date of the todo and, when asked to cascade, those of the todos
+// it blocks. The plan is worked out again inside the transaction, it may differ from an
+// earlier preview when the todos changed since.
+func (s *DependencyService) ShiftDueDate(ctx echo.Context, userID string,
+	payload *dependency.ShiftDueDatePayload,
+) (*dependency.Shift, error) {
+	logger := middleware.GetLogger(ctx)
+
+	var shift *dependency.Shift
+	err := s.txManager.WithinTx(ctx.Request().Context(), func(txCtx context.Context) error {
+		var err error
+		shift, err = s.planShift(txCtx, userID, payload)
+		if err != nil {
+			return err
+		}
+
+		for _, change := range shift.Changes {
+			if _, err := s.todoRepo.UpdateTodo(txCtx, userID, &todo.UpdateTodoPayload{
+				ID:      change.TodoID,
+				DueDate: &change.To,
+			}); err != nil {
+				return err
+			}
+		}
+
+		shift.Applied = true
+		return nil
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to shift due dates")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "due_dates_shifted").
+		Str("todo_id", payload.TodoID.String()).
+		Int64("shift_seconds", shift.ShiftSeconds).
+		Int("changed_count", len(shift.Changes)).
+		Int("skipped_count", len(shift.Skipped)).
+		Msg("Due dates shifted successfully")
+
+	return shift, nil
+}
+
+// planShift walks from the todo to the todos it blocks, breadth first, moving every due date by
+// as much as the todo's moves. Finished todos no longer wait on anything, the walk stops at them.
+func (s *DependencyService) planShift(ctx context.Context, userID string,
+	payload *dependency.ShiftDueDatePayload,
+) (*dependency.Shift, error) {
+	current, err := s.todoRepo.CheckTodoExists(ctx, userID, payload.TodoID)
+	if err != nil {
+		return nil, err
+	}
+
+	update := &todo.UpdateTodoPayload{ID: current.ID, DueDate: &payload.DueDate}
+	if err := update.ValidateAgainst(current.StatusCategory); err != nil {
+		return nil, err
+	}
+
+	shift := &dependency.Shift{
+		TodoID:  current.ID,
+		Changes: []dependency.Change{{TodoID: current.ID, Title: current.Title, From: current.DueDate, To: payload.DueDate}},
+		Skipped: []dependency.SkippedTodo{},
+	}
+	// Without a due date to move from there is no amount to shift the others by
+	if current.DueDate == nil || !*payload.Cascade {
+		return shift, nil
+	}
+
+	delta := payload.DueDate.Sub(*current.DueDate)
+	shift.ShiftSeconds = int64(delta.Seconds())
+	if delta == 0 {
+		return shift, nil
+	}
+
+	links, err := s.dependencyRepo.GetDownstreamDependencies(ctx, userID, current.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	blocking := map[uuid.UUID][]uuid.UUID{}
+	todoIDs := []uuid.UUID{}
+	for _, link := range links {
+		blocking[link.DependsOnID] = append(blocking[link.DependsOnID], link.TodoID)
+		todoIDs = append(todoIDs, link.TodoID)
+	}
+
+	dependents, err := s.todoRepo.GetTodosByIDs(ctx, userID, todoIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*todo.Todo, len(dependents))
+	for i := range dependents {
+		byID[dependents[i].ID] = &dependents[i]
+	}
+
+	visited := map[uuid.UUID]bool{current.ID: true}
+	queue := []uuid.UUID{current.ID}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+
+		for _, todoID := range blocking[next] {
+			item, ok := byID[todoID]
+			if visited[todoID] || !ok {
+				continue
+			}
+			visited[todoID] = true
+
+			if item.StatusCategory == todo.StatusCompleted || item.StatusCategory == todo.StatusArchived {
+				continue
+			}
+			queue = append(queue, todoID)
+
+			if item.DueDate == nil {
+				shift.Skipped = append(shift.Skipped, dependency.SkippedTodo{
+					TodoID: item.ID, Title: item.Title, Reason: dependency.SkipNoDueDate,
+				})
+				continue
+			}
+
+			to := item.DueDate.Add(delta)
+			if item.StatusCategory != todo.StatusDraft && validation.IsPast(to) {
+				shift.Skipped = append(shift.Skipped, dependency.SkippedTodo{
+					TodoID: item.ID, Title: item.Title, Reason: dependency.SkipPast,
+				})
+				continue
+			}
+
+			shift.Changes = append(shift.Changes, dependency.Change{
+				TodoID: item.ID, Title: item.Title, From: item.DueDate, To: to,
+			})
+		}
+	}
+
+	return shift, nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/events"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+// subscribeEvents registers what reacts to the changes of todos and comments, every change is
+// published once and each subscriber picks the events it cares about
+func subscribeEvents(s *server.Server, previewService *PreviewService, webhookService *WebhookService) {
+	s.Events.Subscribe("log", events.Log)
+	s.Events.Subscribe("analytics", trackEvents(s),
+		events.TypeTodoCreated, events.TypeTodoCompleted, events.TypeTodosCompleted, events.TypeTodoUncompleted,
+		events.TypeTodoDeleted, events.TypeCommentAdded)
+	s.Events.Subscribe("rules", evaluateRules(s),
+		events.TypeTodoCreated, events.TypeTodoCompleted, events.TypeTodosCompleted, events.TypeTodoTagged)
+	s.Events.Subscribe("badges", evaluateBadges(s),
+		events.TypeTodoCompleted, events.TypeTodosCompleted)
+	s.Events.Subscribe("link_previews", previewService.queueEventLinks,
+		events.TypeTodoCreated, events.TypeTodoUpdated, events.TypeCommentAdded, events.TypeCommentUpdated)
+	s.Events.Subscribe("webhooks", webhookService.queueEventDeliveries,
+		events.TypeTodoCreated, events.TypeTodoUpdated, events.TypeTodoCompleted, events.TypeTodosCompleted,
+		events.TypeTodoUncompleted, events.TypeTodoDeleted, events.TypeCommentAdded, events.TypeCommentUpdated,
+		events.TypeCommentDeleted)
+}
+
+// publishEvents publishes the changes a request made, the subscribers log through the logger
+// of the request. It never fails the request.
+func publishEvents(ctx echo.Context, s *server.Server, published ...events.Event) {
+	reqCtx := middleware.GetLogger(ctx).WithContext(ctx.Request().Context())
+	s.Events.Publish(reqCtx, published...)
+}
+
+// eventActor is the user making a change in their active workspace
+func eventActor(ctx echo.Context, userID string) events.Actor {
+	return events.Actor{UserID: userID, WorkspaceID: middleware.GetWorkspaceID(ctx)}
+}
@@ -0,0 +1,799 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/analytics"
+	"github.com/Sameer16536/ExecuTask/internal/lib/aws"
+	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/lib/pdf"
+	"github.com/Sameer16536/ExecuTask/internal/lib/utils"
+	"github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/model/export"
+	"github.com/Sameer16536/ExecuTask/internal/model/report"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+type ExportService struct {
+	server     *server.Server
+	exportRepo repository.ExportStore
+	todoRepo   repository.TodoStore
+	awsClient  *aws.AWS
+	audit      *AuditService
+}
+
+func NewExportService(server *server.Server, exportRepo repository.ExportStore,
+	todoRepo repository.TodoStore, awsClient *aws.AWS, audit *AuditService,
+) *ExportService {
+	return &ExportService{
+		server:     server,
+		exportRepo: exportRepo,
+		todoRepo:   todoRepo,
+		awsClient:  awsClient,
+		audit:      audit,
+	}
+}
+
+// RequestAccountExport queues an export of everything the user stored. The archive is built in
+// the background and the user is emailed a link once it is ready.
+func (s *ExportService) RequestAccountExport(ctx echo.Context, userID string) (*export.AccountExport, error) {
+	logger := middleware.GetLogger(ctx)
+
+	exportItem, err := s.exportRepo.CreateAccountExport(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create account export")
+		return nil, err
+	}
+
+	err = job.EnqueueAccountExport(ctx.Request().Context(), s.server.Job.Client, &job.AccountExportTask{
+		UserID:   userID,
+		ExportID: exportItem.ID,
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("export_id", exportItem.ID.String()).Msg("failed to enqueue account export")
+
+		// Don't leave an export behind that blocks the next request
+		failErr := s.exportRepo.FailAccountExport(context.WithoutCancel(ctx.Request().Context()), exportItem.ID,
+			"The export could not be started, request a new one")
+		if failErr != nil {
+			logger.Error().Err(failErr).Str("export_id", exportItem.ID.String()).Msg("failed to mark account export as failed")
+		}
+		return nil, err
+	}
+
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionAccountExport,
+		EntityType: "account_export",
+		EntityID:   exportItem.ID.String(),
+		After:      exportItem,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "account_export_requested").
+		Str("export_id", exportItem.ID.String()).
+		Msg("Account export requested")
+
+	trackEvent(ctx, s.server, userID, analytics.EventAccountExportRequested, nil)
+
+	return exportItem, nil
+}
+
+// GetAccountExport reports the progress of an export, with a fresh download link once it completed
+func (s *ExportService) GetAccountExport(ctx echo.Context, userID string, exportID uuid.UUID) (*export.AccountExport, error) {
+	return s.getExport(ctx, userID, exportID, export.KindAccount)
+}
+
+// GetTodoListExport reports the progress of a todo list PDF, with a fresh download link once it
+// completed
+func (s *ExportService) GetTodoListExport(ctx echo.Context, userID string, exportID uuid.UUID) (*export.AccountExport, error) {
+	return s.getExport(ctx, userID, exportID, export.KindTodoList)
+}
+
+func (s *ExportService) getExport(ctx echo.Context, userID string, exportID uuid.UUID, kind export.Kind,
+) (*export.AccountExport, error) {
+	logger := middleware.GetLogger(ctx)
+
+	exportItem, err := s.exportRepo.GetAccountExport(ctx.Request().Context(), userID, exportID)
+	if err == nil && exportItem.Kind != kind {
+		code := errs.CodeExportNotFound
+		err = errs.NewNotFoundError("export not found", false, &code)
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("export_id", exportID.String()).Msg("failed to fetch export")
+		return nil, err
+	}
+
+	if err := s.presign(ctx.Request().Context(), exportItem); err != nil {
+		logger.Error().Err(err).Str("export_id", exportID.String()).Msg("failed to sign export link")
+		return nil, err
+	}
+
+	return exportItem, nil
+}
+
+// ExportAccount builds the archive of an export and stores it in S3. It runs in the job worker,
+// attempts after the archive was stored only mint a new link.
+func (s *ExportService) ExportAccount(ctx context.Context, userID string, exportID uuid.UUID) (*export.AccountExport, error) {
+	log := logger.WithSpanContext(*s.server.Logger, ctx).With().
+		Str("user_id", userID).
+		Str("export_id", exportID.String()).
+		Logger()
+
+	exportItem, err := s.exportRepo.StartAccountExport(ctx, exportID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch exportItem.Status {
+	case export.StatusCompleted:
+		if err := s.presign(ctx, exportItem); err != nil {
+			return nil, err
+		}
+		return exportItem, nil
+	case export.StatusFailed:
+		return nil, fmt.Errorf("account export %s failed before", exportID.String())
+	}
+
+	archive, err := os.CreateTemp("", "account-export-*.zip")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer func() {
+		archive.Close()
+		os.Remove(archive.Name())
+	}()
+
+	manifest, err := s.writeArchive(ctx, archive, userID, exportID, &log)
+	if err != nil {
+		return nil, err
+	}
+
+	sizeBytes, err := archive.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to size archive: %w", err)
+	}
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind archive: %w", err)
+	}
+
+	objectKey := fmt.Sprintf("exports/%s/%s.zip", userID, exportID.String())
+	err = s.awsClient.Blobs.PutObject(ctx, s.server.Config.AWS.S3Bucket, objectKey, archive, "application/zip")
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.server.Config.Export.Retention)
+	exportItem, err = s.exportRepo.CompleteAccountExport(ctx, exportID, objectKey, sizeBytes, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.presign(ctx, exportItem); err != nil {
+		return nil, err
+	}
+
+	// Business event log
+	log.Info().
+		Str("event", "account_export_completed").
+		Int64("size_bytes", sizeBytes).
+		Interface("counts", manifest.Counts).
+		Int("missing_attachment_count", len(manifest.MissingAttachments)).
+		Msg("Account export completed")
+
+	return exportItem, nil
+}
+
+// FailAccountExport gives up on an export the worker could not build
+func (s *ExportService) FailAccountExport(ctx context.Context, exportID uuid.UUID, reason string) error {
+	return s.exportRepo.FailAccountExport(ctx, exportID, reason)
+}
+
+// ExportTodoPDF prints a todo with its subtasks, comments and the list of its attachments
+func (s *ExportService) ExportTodoPDF(ctx echo.Context, userID string, payload *todo.ExportTodoPDFPayload) ([]byte, error) {
+	logger := middleware.GetLogger(ctx)
+
+	todoItem, err := s.todoRepo.GetTodoByID(ctx.Request().Context(), userID, payload.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch todo for pdf export")
+		return nil, err
+	}
+
+	doc := pdf.New(todoItem.Title, time.Now())
+	doc.Title(todoItem.Title)
+	writeTodoPDF(doc, todoItem, payload.Location())
+
+	var buffer bytes.Buffer
+	if _, err := doc.WriteTo(&buffer); err != nil {
+		logger.Error().Err(err).Msg("failed to write todo pdf")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "todo_pdf_exported").
+		Str("todo_id", todoItem.ID.String()).
+		Int("size_bytes", buffer.Len()).
+		Msg("Todo exported as PDF successfully")
+
+	return buffer.Bytes(), nil
+}
+
+// ExportTodoListPDF prints the todos matching the filters. Lists longer than the inline limit
+// take too long to render within a request, they are exported with RequestTodoListExport.
+func (s *ExportService) ExportTodoListPDF(ctx echo.Context, userID string, query *todo.ExportTodosPDFQuery) ([]byte, error) {
+	logger := middleware.GetLogger(ctx)
+	limit := s.server.Config.Export.PDFInlineLimit
+
+	todos, err := s.collectTodos(ctx.Request().Context(), userID, query, limit)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to collect todos for pdf export")
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	if err := s.writeTodoListPDF(ctx.Request().Context(), &buffer, userID, todos, query); err != nil {
+		logger.Error().Err(err).Msg("failed to write todo list pdf")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "todo_list_pdf_exported").
+		Int("count", len(todos)).
+		Int("size_bytes", buffer.Len()).
+		Msg("Todo list exported as PDF successfully")
+
+	return buffer.Bytes(), nil
+}
+
+// RequestTodoListExport queues a PDF of the todos matching the filters, however many there
+// are. It is stored like an account export and the user is emailed a link once it is ready.
+func (s *ExportService) RequestTodoListExport(ctx echo.Context, userID string,
+	payload *export.RequestTodoListExportPayload,
+) (*export.AccountExport, error) {
+	logger := middleware.GetLogger(ctx)
+
+	exportItem, err := s.exportRepo.CreateTodoListExport(ctx.Request().Context(), userID, payload.Filters())
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create todo list export")
+		return nil, err
+	}
+
+	err = job.EnqueueTodoListExport(ctx.Request().Context(), s.server.Job.Client, &job.TodoListExportTask{
+		UserID:   userID,
+		ExportID: exportItem.ID,
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("export_id", exportItem.ID.String()).Msg("failed to enqueue todo list export")
+
+		// Don't leave an export behind that blocks the next request
+		failErr := s.exportRepo.FailAccountExport(context.WithoutCancel(ctx.Request().Context()), exportItem.ID,
+			"The export could not be started, request a new one")
+		if failErr != nil {
+			logger.Error().Err(failErr).Str("export_id", exportItem.ID.String()).Msg("failed to mark todo list export as failed")
+		}
+		return nil, err
+	}
+
+	// Business event log
+	logger.Info().
+		Str("event", "todo_list_export_requested").
+		Str("export_id", exportItem.ID.String()).
+		Msg("Todo list export requested")
+
+	return exportItem, nil
+}
+
+// ExportTodoList prints the todos of a todo list export and stores the PDF in S3. It runs in
+// the job worker, attempts after the PDF was stored only mint a new link.
+func (s *ExportService) ExportTodoList(ctx context.Context, userID string, exportID uuid.UUID) (*export.AccountExport, error) {
+	log := logger.WithSpanContext(*s.server.Logger, ctx).With().
+		Str("user_id", userID).
+		Str("export_id", exportID.String()).
+		Logger()
+
+	exportItem, err := s.exportRepo.StartAccountExport(ctx, exportID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch exportItem.Status {
+	case export.StatusCompleted:
+		if err := s.presign(ctx, exportItem); err != nil {
+			return nil, err
+		}
+		return exportItem, nil
+	case export.StatusFailed:
+		return nil, fmt.Errorf("todo list export %s failed before", exportID.String())
+	}
+
+	query := exportItem.Filters
+	if query == nil {
+		query = &todo.ExportTodosPDFQuery{}
+	}
+
+	todos, err := s.collectTodos(ctx, userID, query, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	if err := s.writeTodoListPDF(ctx, &buffer, userID, todos, query); err != nil {
+		return nil, err
+	}
+	sizeBytes := int64(buffer.Len())
+
+	objectKey := fmt.Sprintf("exports/%s/%s.pdf", userID, exportID.String())
+	err = s.awsClient.Blobs.PutObject(ctx, s.server.Config.AWS.S3Bucket, objectKey, &buffer, "application/pdf")
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.server.Config.Export.Retention)
+	exportItem, err = s.exportRepo.CompleteAccountExport(ctx, exportID, objectKey, sizeBytes, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.presign(ctx, exportItem); err != nil {
+		return nil, err
+	}
+
+	// Business event log
+	log.Info().
+		Str("event", "todo_list_export_completed").
+		Int("count", len(todos)).
+		Int64("size_bytes", sizeBytes).
+		Msg("Todo list export completed")
+
+	return exportItem, nil
+}
+
+// ExportReport renders a run of a scheduled report and stores it as an export of its own, which
+// the export cleanup deletes once the export retention passes. It runs in the job worker.
+func (s *ExportService) ExportReport(ctx context.Context, schedule *report.Schedule) (*export.AccountExport, error) {
+	log := logger.WithSpanContext(*s.server.Logger, ctx).With().
+		Str("user_id", schedule.UserID).
+		Str("schedule_id", schedule.ID.String()).
+		Logger()
+
+	query := schedule.Query()
+	todos, err := s.collectTodos(ctx, schedule.UserID, query, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	contentType := "application/pdf"
+	if schedule.Format == report.FormatCSV {
+		contentType = "text/csv"
+		err = writeTodoListCSV(&buffer, todos)
+	} else {
+		err = s.writeTodoListPDF(ctx, &buffer, schedule.UserID, todos, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sizeBytes := int64(buffer.Len())
+
+	objectKey := fmt.Sprintf("exports/%s/%s.%s", schedule.UserID, uuid.New().String(), schedule.Format)
+	err = s.awsClient.Blobs.PutObject(ctx, s.server.Config.AWS.S3Bucket, objectKey, &buffer, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.server.Config.Export.Retention)
+	exportItem, err := s.exportRepo.CreateReportExport(ctx, schedule.UserID, query, objectKey, sizeBytes, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.presign(ctx, exportItem); err != nil {
+		return nil, err
+	}
+
+	// Business event log
+	log.Info().
+		Str("event", "report_exported").
+		Str("format", string(schedule.Format)).
+		Int("count", len(todos)).
+		Int64("size_bytes", sizeBytes).
+		Msg("Scheduled report exported")
+
+	return exportItem, nil
+}
+
+// presign sets a download link on a completed export whose archive is still stored. The link
+// never outlives the archive.
+func (s *ExportService) presign(ctx context.Context, exportItem *export.AccountExport) error {
+	if exportItem.Status != export.StatusCompleted || exportItem.ObjectKey == nil || exportItem.ExpiresAt == nil {
+		return nil
+	}
+
+	ttl := s.server.Config.Export.LinkTTL
+	if remaining := time.Until(*exportItem.ExpiresAt); remaining < ttl {
+		ttl = remaining
+	}
+	if ttl <= 0 {
+		return nil
+	}
+
+	fileName := fmt.Sprintf("executask-export-%s.zip", exportItem.CreatedAt.UTC().Format("2006-01-02"))
+	switch exportItem.Kind {
+	case export.KindTodoList:
+		fileName = fmt.Sprintf("executask-todos-%s.pdf", exportItem.CreatedAt.UTC().Format("2006-01-02"))
+	case export.KindReport:
+		fileName = fmt.Sprintf("executask-report-%s%s", exportItem.CreatedAt.UTC().Format("2006-01-02"),
+			path.Ext(*exportItem.ObjectKey))
+	}
+	url, err := s.awsClient.Blobs.CreatePresignedDownloadUrl(ctx, s.server.Config.AWS.S3Bucket,
+		*exportItem.ObjectKey, ttl, fileName)
+	if err != nil {
+		return fmt.Errorf("failed to sign download link for export %s: %w", exportItem.ID.String(), err)
+	}
+
+	linkExpiresAt := time.Now().Add(ttl)
+	exportItem.DownloadURL = &url
+	exportItem.LinkExpiresAt = &linkExpiresAt
+
+	return nil
+}
+
+// writeArchive zips one JSON file per resource and the attachment files into w
+func (s *ExportService) writeArchive(ctx context.Context, w io.Writer, userID string, exportID uuid.UUID,
+	log *zerolog.Logger,
+) (*export.Manifest, error) {
+	zw := zip.NewWriter(w)
+
+	manifest := &export.Manifest{
+		ExportID:           exportID.String(),
+		UserID:             userID,
+		GeneratedAt:        time.Now().UTC(),
+		Counts:             map[string]int{},
+		MissingAttachments: []string{},
+	}
+
+	// Todos are streamed, accounts can hold a lot of them
+	todoFile, err := zw.Create("todos.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to add todos.json to archive: %w", err)
+	}
+	if _, err := io.WriteString(todoFile, "["); err != nil {
+		return nil, fmt.Errorf("failed to write todos.json: %w", err)
+	}
+	encoder := json.NewEncoder(todoFile)
+	err = s.todoRepo.StreamTodos(ctx, userID, &todo.ExportTodosQuery{}, func(todoItem *todo.Todo) error {
+		if manifest.Counts["todos"] > 0 {
+			if _, err := io.WriteString(todoFile, ","); err != nil {
+				return err
+			}
+		}
+		manifest.Counts["todos"]++
+		return encoder.Encode(todoItem)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write todos.json: %w", err)
+	}
+	if _, err := io.WriteString(todoFile, "]"); err != nil {
+		return nil, fmt.Errorf("failed to write todos.json: %w", err)
+	}
+
+	categories, err := s.exportRepo.GetCategoriesForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Counts["categories"] = len(categories)
+	if err := writeArchiveJSON(zw, "categories.json", categories); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.exportRepo.GetCommentsForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Counts["comments"] = len(comments)
+	if err := writeArchiveJSON(zw, "comments.json", comments); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.exportRepo.GetAttachmentsForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Counts["attachments"] = len(attachments)
+	if err := writeArchiveJSON(zw, "attachments.json", attachments); err != nil {
+		return nil, err
+	}
+
+	// Private notes leave with their author's own data only, never with the workspace backups
+	notes, err := s.exportRepo.GetNotesForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Counts["notes"] = len(notes)
+	if err := writeArchiveJSON(zw, "notes.json", notes); err != nil {
+		return nil, err
+	}
+
+	for i := range attachments {
+		attachment := &attachments[i]
+		name := path.Join("attachments", attachment.TodoID.String(), attachment.ID.String()+"_"+path.Base(attachment.Name))
+		if !attachment.IsAvailable() {
+			// Files not cleared by the virus scan don't leave the system
+			log.Warn().
+				Str("attachment_id", attachment.ID.String()).
+				Msg("attachment quarantined, leaving it out of the export")
+			manifest.MissingAttachments = append(manifest.MissingAttachments, attachment.ID.String())
+			continue
+		}
+
+		copied, err := copyAttachment(ctx, s.awsClient, s.server.Config.AWS.S3Bucket, zw, name, attachment.DownloadKey)
+		if err != nil {
+			return nil, err
+		}
+		if !copied {
+			// A file lost from storage shouldn't cost the user the rest of their data
+			log.Warn().
+				Str("attachment_id", attachment.ID.String()).
+				Str("s3_key", attachment.DownloadKey).
+				Msg("attachment missing from storage, leaving it out of the export")
+			manifest.MissingAttachments = append(manifest.MissingAttachments, attachment.ID.String())
+		}
+	}
+
+	if err := writeArchiveJSON(zw, "manifest.json", manifest); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// copyAttachment streams an attachment from S3 into the archive. It reports false when the
+// object can't be read, a failure writing the archive is an error.
+func copyAttachment(ctx context.Context, awsClient *aws.AWS, bucket string, zw *zip.Writer, name, key string) (bool, error) {
+	object, err := awsClient.Blobs.GetObject(ctx, bucket, key)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		return false, nil
+	}
+	defer object.Close()
+
+	file, err := zw.Create(name)
+	if err != nil {
+		return false, fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+
+	if _, err := io.Copy(file, object); err != nil {
+		return false, fmt.Errorf("failed to copy attachment %s into archive: %w", key, err)
+	}
+
+	return true, nil
+}
+
+func writeArchiveJSON(zw *zip.Writer, name string, v any) error {
+	file, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// collectTodos reads the todos matching the filters, failing once there are more than limit
+// when a limit is set
+func (s *ExportService) collectTodos(ctx context.Context, userID string, query *todo.ExportTodosPDFQuery,
+	limit int,
+) ([]todo.Todo, error) {
+	todos := []todo.Todo{}
+	err := s.todoRepo.StreamTodos(ctx, userID, query.Filters(), func(todoItem *todo.Todo) error {
+		if limit > 0 && len(todos) == limit {
+			code := errs.CodeExportTooLarge
+			return errs.NewBadRequestError(
+				fmt.Sprintf("more than %d todos match, request an export of the list instead", limit),
+				false, &code, nil, nil)
+		}
+		todos = append(todos, *todoItem)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return todos, nil
+}
+
+// writeTodoListPDF prints every todo in turn, subtasks of a printed todo are listed under it
+// rather than on their own
+func (s *ExportService) writeTodoListPDF(ctx context.Context, w io.Writer, userID string, todos []todo.Todo,
+	query *todo.ExportTodosPDFQuery,
+) error {
+	printed := make(map[uuid.UUID]bool, len(todos))
+	for _, todoItem := range todos {
+		printed[todoItem.ID] = true
+	}
+
+	location := query.Location()
+	now := time.Now()
+	doc := pdf.New("ExecuTask todos", now)
+	doc.Title("Todos")
+
+	populated := make([]*todo.PopulatedTodo, 0, len(todos))
+	for _, todoItem := range todos {
+		if todoItem.ParentTodoID != nil && printed[*todoItem.ParentTodoID] {
+			continue
+		}
+
+		item, err := s.todoRepo.GetTodoByID(ctx, userID, todoItem.ID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Deleted since the list was read
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		populated = append(populated, item)
+	}
+
+	summary := fmt.Sprintf("Printed %s, %d in total", formatPDFTime(now, location), len(populated))
+	if filters := describeFilters(query, populated); filters != "" {
+		summary += ". " + filters
+	}
+	doc.Note(summary)
+
+	for i, item := range populated {
+		if i > 0 {
+			doc.Rule()
+		}
+		doc.Heading(item.Title)
+		writeTodoPDF(doc, item, location)
+	}
+
+	if _, err := doc.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write todo list pdf: %w", err)
+	}
+
+	return nil
+}
+
+// writeTodoListCSV writes one row per todo, subtasks included, in the columns of the CSV export
+func writeTodoListCSV(w io.Writer, todos []todo.Todo) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(todo.CSVHeader); err != nil {
+		return err
+	}
+	for _, todoItem := range todos {
+		if err := writer.Write(todoItem.CSVRecord()); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeTodoPDF lays out the details of a todo below its title
+func writeTodoPDF(doc *pdf.Document, item *todo.PopulatedTodo, location *time.Location) {
+	doc.Field("Status", string(item.Status))
+	doc.Field("Priority", string(item.Priority))
+	if item.DueDate != nil {
+		doc.Field("Due", formatPDFTime(*item.DueDate, location))
+	}
+	if item.CompletedAt != nil {
+		doc.Field("Completed", formatPDFTime(*item.CompletedAt, location))
+	}
+	if item.Category != nil {
+		doc.Field("Category", item.Category.Name)
+	}
+	if item.Metadata != nil && len(item.Metadata.Tags) > 0 {
+		doc.Field("Tags", strings.Join(item.Metadata.Tags, ", "))
+	}
+	doc.Field("Created", formatPDFTime(item.CreatedAt, location))
+	doc.Space(6)
+
+	if item.Description != "" {
+		doc.Paragraph(item.Description)
+	}
+
+	if len(item.Children) > 0 {
+		doc.Heading(fmt.Sprintf("Subtasks (%d)", len(item.Children)))
+		for _, child := range item.Children {
+			box := "[ ]"
+			if child.StatusCategory == todo.StatusCompleted {
+				box = "[x]"
+			}
+			line := box + " " + child.Title
+			if child.DueDate != nil {
+				line += ", due " + formatPDFTime(*child.DueDate, location)
+			}
+			doc.Bullet(0, line)
+		}
+	}
+
+	if len(item.Comments) > 0 {
+		doc.Heading(fmt.Sprintf("Comments (%d)", len(item.Comments)))
+		for _, c := range item.Comments {
+			doc.Note(formatPDFTime(c.CreatedAt, location))
+			doc.Paragraph(c.Content)
+		}
+	}
+
+	if len(item.Attachments) > 0 {
+		doc.Heading(fmt.Sprintf("Attachments (%d)", len(item.Attachments)))
+		for _, attachment := range item.Attachments {
+			line := attachment.Name
+			if attachment.FileSize != nil {
+				line += " (" + utils.FormatSize(*attachment.FileSize) + ")"
+			}
+			doc.Bullet(0, line)
+		}
+	}
+}
+
+// describeFilters spells out the filters a list was printed with, the name of a filtered
+// category is taken from the todos in it
+func describeFilters(query *todo.ExportTodosPDFQuery, todos []*todo.PopulatedTodo) string {
+	filters := []string{}
+	if query.Status != nil {
+		filters = append(filters, "status "+string(*query.Status))
+	}
+	if query.Priority != nil {
+		filters = append(filters, "priority "+string(*query.Priority))
+	}
+	if query.CategoryID != nil {
+		name := "selected"
+		for _, item := range todos {
+			if item.Category != nil {
+				name = item.Category.Name
+				break
+			}
+		}
+		filters = append(filters, "category "+name)
+	}
+	if query.Search != nil {
+		filters = append(filters, fmt.Sprintf("matching %q", *query.Search))
+	}
+
+	if len(filters) == 0 {
+		return ""
+	}
+	return "Filtered by " + strings.Join(filters, ", ")
+}
+
+func formatPDFTime(t time.Time, location *time.Location) string {
+	return t.In(location).Format("Jan 2, 2006 3:04 PM MST")
+}
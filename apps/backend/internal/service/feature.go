@@ -0,0 +1,208 @@
+package service
+
+import (
+	"hash/fnv"
+	"sort"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/model/feature"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+type FeatureFlagService struct {
+	server       *server.Server
+	featureRepo  repository.FeatureStore
+	auditService *AuditService
+}
+
+func NewFeatureFlagService(server *server.Server, featureRepo repository.FeatureStore,
+	auditService *AuditService,
+) *FeatureFlagService {
+	return &FeatureFlagService{
+		server:       server,
+		featureRepo:  featureRepo,
+		auditService: auditService,
+	}
+}
+
+// FeatureFlags evaluates every flag for the authenticated user once per request, later calls
+// read them back from the request context
+func (s *FeatureFlagService) FeatureFlags(ctx echo.Context) map[string]bool {
+	if flags, ok := ctx.Get(middleware.FeatureFlagsKey).(map[string]bool); ok {
+		return flags
+	}
+
+	flags := s.evaluate(ctx, middleware.GetUserID(ctx), middleware.GetWorkspaceID(ctx))
+	ctx.Set(middleware.FeatureFlagsKey, flags)
+	return flags
+}
+
+// IsEnabled tells whether a flag is on for the authenticated user, unknown flags are off
+func (s *FeatureFlagService) IsEnabled(ctx echo.Context, flag string) bool {
+	return s.FeatureFlags(ctx)[flag]
+}
+
+// evaluate resolves every configured flag. The most specific override wins, then the
+// configured state, then the rollout bucket of the user.
+func (s *FeatureFlagService) evaluate(ctx echo.Context, userID, workspaceID string) map[string]bool {
+	configured := s.server.CurrentConfig().Features.Flags
+
+	flags := make(map[string]bool, len(configured))
+	for name, flag := range configured {
+		flags[name] = flag.Enabled || inRollout(name, userID, flag.RolloutPercent)
+	}
+
+	if userID == "" {
+		return flags
+	}
+
+	overrides, err := s.featureRepo.GetOverridesFor(ctx.Request().Context(), userID, workspaceID)
+	if err != nil {
+		// The configured state is a safe answer, failing every gated request is not
+		middleware.GetLogger(ctx).Error().Err(err).Msg("failed to load feature flag overrides")
+		return flags
+	}
+
+	precedence := map[feature.Scope]int{
+		feature.ScopeGlobal:    1,
+		feature.ScopeWorkspace: 2,
+		feature.ScopeUser:      3,
+	}
+	applied := make(map[string]int, len(overrides))
+	for _, override := range overrides {
+		if _, ok := configured[override.Flag]; !ok {
+			continue
+		}
+		if precedence[override.Scope] > applied[override.Flag] {
+			flags[override.Flag] = override.Enabled
+			applied[override.Flag] = precedence[override.Scope]
+		}
+	}
+
+	return flags
+}
+
+// inRollout puts a user in a stable bucket per flag, raising the percentage only adds users
+func inRollout(flag, userID string, percent int) bool {
+	if percent <= 0 || userID == "" {
+		return false
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(flag + ":" + userID))
+	return int(h.Sum32()%100) < percent
+}
+
+func (s *FeatureFlagService) GetFlags(ctx echo.Context, _ *feature.GetFlagsPayload) ([]feature.Flag, error) {
+	logger := middleware.GetLogger(ctx)
+
+	overrides, err := s.featureRepo.GetOverrides(ctx.Request().Context())
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch feature flag overrides")
+		return nil, err
+	}
+
+	byFlag := make(map[string][]feature.Override)
+	for _, override := range overrides {
+		byFlag[override.Flag] = append(byFlag[override.Flag], override)
+	}
+
+	configured := s.server.CurrentConfig().Features.Flags
+
+	flags := make([]feature.Flag, 0, len(configured))
+	for name, flag := range configured {
+		flagOverrides := byFlag[name]
+		if flagOverrides == nil {
+			flagOverrides = []feature.Override{}
+		}
+
+		flags = append(flags, feature.Flag{
+			Name:           name,
+			Enabled:        flag.Enabled,
+			RolloutPercent: flag.RolloutPercent,
+			Overrides:      flagOverrides,
+		})
+	}
+
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].Name < flags[j].Name
+	})
+
+	return flags, nil
+}
+
+func (s *FeatureFlagService) SetOverride(ctx echo.Context, userID string,
+	payload *feature.SetOverridePayload,
+) (*feature.Override, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := s.requireKnownFlag(payload.Flag); err != nil {
+		return nil, err
+	}
+
+	override, err := s.featureRepo.SetOverride(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Str("flag", payload.Flag).Msg("failed to set feature flag override")
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminFeatureFlag,
+		EntityType: "feature_flag",
+		EntityID:   payload.Flag,
+		After:      override,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "feature_flag_override_set").
+		Str("flag", override.Flag).
+		Str("scope", string(override.Scope)).
+		Str("subject_id", override.SubjectID).
+		Bool("enabled", override.Enabled).
+		Msg("Feature flag override set successfully")
+
+	return override, nil
+}
+
+func (s *FeatureFlagService) DeleteOverride(ctx echo.Context, payload *feature.DeleteOverridePayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	override, err := s.featureRepo.DeleteOverride(ctx.Request().Context(), payload)
+	if err != nil {
+		logger.Error().Err(err).Str("flag", payload.Flag).Msg("failed to delete feature flag override")
+		return err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminFeatureFlag,
+		EntityType: "feature_flag",
+		EntityID:   payload.Flag,
+		Before:     override,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "feature_flag_override_deleted").
+		Str("flag", override.Flag).
+		Str("scope", string(override.Scope)).
+		Str("subject_id", override.SubjectID).
+		Msg("Feature flag override deleted successfully")
+
+	return nil
+}
+
+// requireKnownFlag rejects overrides of flags the config doesn't declare, they would never
+// be evaluated
+func (s *FeatureFlagService) requireKnownFlag(flag string) error {
+	if _, ok := s.server.CurrentConfig().Features.Flags[flag]; ok {
+		return nil
+	}
+
+	code := errs.CodeFeatureFlagNotFound
+	return errs.NewNotFoundError("feature flag not found", false, &code)
+}
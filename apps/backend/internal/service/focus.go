@@ -0,0 +1,139 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/analytics"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/focus"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+type FocusService struct {
+	server    *server.Server
+	focusRepo repository.FocusStore
+	todoRepo  repository.TodoStore
+}
+
+func NewFocusService(server *server.Server, focusRepo repository.FocusStore,
+	todoRepo repository.TodoStore,
+) *FocusService {
+	return &FocusService{
+		server:    server,
+		focusRepo: focusRepo,
+		todoRepo:  todoRepo,
+	}
+}
+
+func (s *FocusService) StartSession(ctx echo.Context, userID string,
+	payload *focus.StartSessionPayload,
+) (*focus.Session, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if _, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, payload.TodoID); err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return nil, err
+	}
+
+	session, err := s.focusRepo.StartSession(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to start focus session")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "focus_session_started").
+		Str("session_id", session.ID.String()).
+		Str("todo_id", session.TodoID.String()).
+		Int("planned_minutes", session.PlannedMinutes).
+		Msg("Focus session started successfully")
+
+	return session, nil
+}
+
+func (s *FocusService) GetSessions(ctx echo.Context, userID string, query *focus.GetSessionsQuery) ([]focus.Session, error) {
+	logger := middleware.GetLogger(ctx)
+
+	sessions, err := s.focusRepo.GetSessions(ctx.Request().Context(), userID, query)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch focus sessions")
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (s *FocusService) GetCurrentSession(ctx echo.Context, userID string) (*focus.Session, error) {
+	logger := middleware.GetLogger(ctx)
+
+	session, err := s.focusRepo.GetOpenSession(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch open focus session")
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (s *FocusService) PauseSession(ctx echo.Context, userID string, payload *focus.SessionPayload) (*focus.Session, error) {
+	return s.transition(ctx, userID, payload, "paused", (*focus.Session).Pause)
+}
+
+func (s *FocusService) ResumeSession(ctx echo.Context, userID string, payload *focus.SessionPayload) (*focus.Session, error) {
+	return s.transition(ctx, userID, payload, "resumed", (*focus.Session).Resume)
+}
+
+// CompleteSession closes the session, its focused time counts toward the productivity stats
+func (s *FocusService) CompleteSession(ctx echo.Context, userID string, payload *focus.SessionPayload) (*focus.Session, error) {
+	session, err := s.transition(ctx, userID, payload, "completed", (*focus.Session).Complete)
+	if err != nil {
+		return nil, err
+	}
+
+	trackEvent(ctx, s.server, userID, analytics.EventFocusSessionCompleted, analytics.Properties{
+		"planned_minutes": session.PlannedMinutes,
+		"focused_minutes": session.FocusedSeconds / 60,
+	})
+	emitBadgeEvaluation(ctx, s.server, userID)
+
+	return session, nil
+}
+
+// transition moves a session to its next state, a session another request moved on first is
+// a conflict rather than counted twice
+func (s *FocusService) transition(ctx echo.Context, userID string, payload *focus.SessionPayload, verb string,
+	apply func(session *focus.Session, now time.Time) error,
+) (*focus.Session, error) {
+	logger := middleware.GetLogger(ctx)
+
+	session, err := s.focusRepo.GetSession(ctx.Request().Context(), userID, payload.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch focus session")
+		return nil, err
+	}
+
+	from := session.Status
+	if err := apply(session, time.Now()); err != nil {
+		return nil, err
+	}
+
+	session, err = s.focusRepo.UpdateSession(ctx.Request().Context(), session, from)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to update focus session")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "focus_session_"+verb).
+		Str("session_id", session.ID.String()).
+		Int("focused_seconds", session.FocusedSeconds).
+		Msg("Focus session " + verb + " successfully")
+
+	return session, nil
+}
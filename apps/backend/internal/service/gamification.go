@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/events"
+	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/gamification"
+	"github.com/Sameer16536/ExecuTask/internal/model/planner"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+type GamificationService struct {
+	server           *server.Server
+	gamificationRepo repository.GamificationStore
+	statsRepo        repository.StatsStore
+}
+
+func NewGamificationService(server *server.Server, gamificationRepo repository.GamificationStore,
+	statsRepo repository.StatsStore,
+) *GamificationService {
+	return &GamificationService{
+		server:           server,
+		gamificationRepo: gamificationRepo,
+		statsRepo:        statsRepo,
+	}
+}
+
+// GetOverview returns the streaks and weekly goal of the user with every badge of the catalog,
+// those they earned carry when
+func (s *GamificationService) GetOverview(ctx echo.Context, userID string) (*gamification.Overview, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	progress, err := s.progress(reqCtx, userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to compute gamification progress")
+		return nil, err
+	}
+
+	awards, err := s.gamificationRepo.GetAwards(reqCtx, userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch awarded badges")
+		return nil, err
+	}
+
+	awardedAt := make(map[gamification.BadgeCode]time.Time, len(awards))
+	for _, award := range awards {
+		awardedAt[award.Badge] = award.CreatedAt
+	}
+
+	badges := make([]gamification.Badge, 0, len(gamification.Badges))
+	for _, definition := range gamification.Badges {
+		badge := gamification.Badge{
+			Code:        definition.Code,
+			Name:        definition.Name,
+			Description: definition.Description,
+		}
+		if at, ok := awardedAt[definition.Code]; ok {
+			badge.AwardedAt = &at
+		}
+		badges = append(badges, badge)
+	}
+
+	return &gamification.Overview{
+		Progress: *progress,
+		Badges:   badges,
+	}, nil
+}
+
+func (s *GamificationService) SetGoal(ctx echo.Context, userID string,
+	payload *gamification.SetGoalPayload,
+) (*gamification.GoalProgress, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	goal, err := s.gamificationRepo.SetGoal(reqCtx, userID, payload.Target)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to set weekly goal")
+		return nil, err
+	}
+
+	progress, err := s.goalProgress(reqCtx, userID, goal)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to compute weekly goal progress")
+		return nil, err
+	}
+
+	// Business event log
+	logger.Info().
+		Str("event", "weekly_goal_set").
+		Int("target", goal.Target).
+		Msg("Weekly goal set successfully")
+
+	// Lowering the goal can meet it right away
+	emitBadgeEvaluation(ctx, s.server, userID)
+
+	return progress, nil
+}
+
+func (s *GamificationService) DeleteGoal(ctx echo.Context, userID string) error {
+	logger := middleware.GetLogger(ctx)
+
+	if err := s.gamificationRepo.DeleteGoal(ctx.Request().Context(), userID); err != nil {
+		logger.Error().Err(err).Msg("failed to delete weekly goal")
+		return err
+	}
+
+	// Business event log
+	logger.Info().
+		Str("event", "weekly_goal_deleted").
+		Msg("Weekly goal deleted successfully")
+
+	return nil
+}
+
+// EvaluateBadges awards the badges the user earned and were not awarded yet. Each new award
+// shows up in the change feed and is announced by email.
+func (s *GamificationService) EvaluateBadges(ctx context.Context, userID string) error {
+	log := logger.WithSpanContext(*s.server.Logger, ctx).With().
+		Str("user_id", userID).
+		Logger()
+
+	progress, err := s.progress(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, definition := range gamification.Badges {
+		if !definition.EarnedBy(progress) {
+			continue
+		}
+
+		award, err := s.gamificationRepo.AwardBadge(ctx, userID, definition.Code)
+		if err != nil {
+			return err
+		}
+		if award == nil {
+			continue
+		}
+
+		// Business event log
+		log.Info().
+			Str("event", "badge_awarded").
+			Str("badge", string(definition.Code)).
+			Msg("Badge awarded successfully")
+
+		if err := job.EnqueueBadgeAwardedEmail(ctx, s.server.Job.Client, &job.BadgeAwardedEmailTask{
+			UserID:           userID,
+			BadgeName:        definition.Name,
+			BadgeDescription: definition.Description,
+		}); err != nil {
+			log.Error().Err(err).Str("badge", string(definition.Code)).Msg("failed to enqueue badge awarded email")
+		}
+	}
+
+	return nil
+}
+
+// progress gathers what badges are awarded on: the completions of the user, their streaks and
+// the progress toward their weekly goal
+func (s *GamificationService) progress(ctx context.Context, userID string) (*gamification.Progress, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	completed, err := s.statsRepo.CountCompletions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := activeDays(ctx, s.statsRepo, userID, today.AddDate(0, 0, -(gamification.StreakWindowDays-1)))
+	if err != nil {
+		return nil, err
+	}
+
+	goal, err := s.gamificationRepo.GetGoal(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var goalProgress *gamification.GoalProgress
+	if goal != nil {
+		goalProgress, err = s.goalProgress(ctx, userID, goal)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &gamification.Progress{
+		Completed: completed,
+		Streaks:   completionStreaks(days, today),
+		Goal:      goalProgress,
+	}, nil
+}
+
+// goalProgress counts the completions of the current UTC week from the daily aggregates
+func (s *GamificationService) goalProgress(ctx context.Context, userID string,
+	goal *gamification.Goal,
+) (*gamification.GoalProgress, error) {
+	weekStart := planner.WeekStart(time.Now().UTC())
+
+	days, err := s.statsRepo.GetDailyCompletions(ctx, userID, weekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &gamification.GoalProgress{
+		WeekStart: weekStart.Format(time.DateOnly),
+		Target:    goal.Target,
+	}
+	for _, day := range days {
+		progress.Completed += day.Completed
+	}
+	progress.Met = progress.Completed >= progress.Target
+
+	return progress, nil
+}
+
+// emitBadgeEvaluation queues a check of the badges of the user after a request moved them
+// forward. It never fails the request.
+func emitBadgeEvaluation(ctx echo.Context, s *server.Server, userID string) {
+	if err := job.EnqueueBadgeEvaluation(ctx.Request().Context(), s.Job.Client, &job.BadgeEvaluationTask{
+		UserID: userID,
+	}); err != nil {
+		middleware.GetLogger(ctx).Error().Err(err).Msg("failed to enqueue badge evaluation")
+	}
+}
+
+// evaluateBadges queues a check of the badges of the user after completions moved them forward
+func evaluateBadges(s *server.Server) events.Handler {
+	return func(ctx context.Context, event events.Event) error {
+		if completion, ok := event.(*events.TodosCompleted); ok && len(completion.Todos) == 0 {
+			return nil
+		}
+		if err := job.EnqueueBadgeEvaluation(ctx, s.Job.Client, &job.BadgeEvaluationTask{
+			UserID: event.User(),
+		}); err != nil {
+			return fmt.Errorf("failed to enqueue badge evaluation: %w", err)
+		}
+		return nil
+	}
+}
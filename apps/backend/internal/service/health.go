@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/blob"
+	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/model/health"
+	"github.com/Sameer16536/ExecuTask/internal/model/statuspage"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	// healthProbeTimeout bounds each dependency probe, a hanging dependency is reported as failing
+	healthProbeTimeout = 2 * time.Second
+
+	// statusSampleInterval is how often an instance records its readiness for the status page,
+	// however often the load balancer probes it
+	statusSampleInterval = time.Minute
+	// notificationFailureWindow is how recently a notification email must have failed to send
+	// for the notifications to be reported as failing
+	notificationFailureWindow = 15 * time.Minute
+	// notificationProbeTasks bounds the retried tasks the notifications probe looks through
+	notificationProbeTasks = 50
+)
+
+// statusPageCheck puts a component of the status page in the failure status when a readiness
+// check fails
+type statusPageCheck struct {
+	check     string
+	component statuspage.Component
+	failure   health.Status
+}
+
+// statusPageChecks are the readiness checks each component is made of. The api component is
+// the readiness of the instance as a whole.
+var statusPageChecks = []statusPageCheck{
+	{check: "postgres", component: statuspage.ComponentDatabase, failure: health.StatusUnhealthy},
+	// Reads move to the primary while replicas are out of rotation
+	{check: "postgres_replicas", component: statuspage.ComponentDatabase, failure: health.StatusDegraded},
+	{check: "redis", component: statuspage.ComponentJobQueue, failure: health.StatusUnhealthy},
+	{check: "job_queue", component: statuspage.ComponentJobQueue, failure: health.StatusUnhealthy},
+	// Emails are sent by the job workers
+	{check: "job_queue", component: statuspage.ComponentNotifications, failure: health.StatusUnhealthy},
+	{check: "notifications", component: statuspage.ComponentNotifications, failure: health.StatusUnhealthy},
+}
+
+type HealthService struct {
+	server         *server.Server
+	blobs          blob.Store
+	statusPageRepo repository.StatusPageStore
+	// lastSampled is when the instance last recorded its readiness for the status page, in
+	// Unix nanoseconds
+	lastSampled atomic.Int64
+}
+
+func NewHealthService(server *server.Server, blobs blob.Store,
+	statusPageRepo repository.StatusPageStore,
+) *HealthService {
+	return &HealthService{
+		server:         server,
+		blobs:          blobs,
+		statusPageRepo: statusPageRepo,
+	}
+}
+
+type healthProbe struct {
+	name     string
+	critical bool
+	probe    func(ctx context.Context) error
+}
+
+// Liveness reports that the process is up and serving. It probes nothing, an outage of a
+// dependency must not get healthy instances restarted.
+func (s *HealthService) Liveness() *health.Report {
+	return &health.Report{
+		Status:      health.StatusHealthy,
+		Timestamp:   time.Now().UTC(),
+		Environment: s.server.Config.Primary.Env,
+	}
+}
+
+// Readiness probes every dependency concurrently. A failing critical dependency makes the
+// instance unhealthy, any other failure only degrades it.
+func (s *HealthService) Readiness(ctx context.Context) *health.Report {
+	// A draining instance must leave the load balancer whatever its dependencies say
+	if s.server.ShuttingDown() {
+		message := "server is shutting down"
+		return &health.Report{
+			Status:      health.StatusUnhealthy,
+			Timestamp:   time.Now().UTC(),
+			Environment: s.server.Config.Primary.Env,
+			Checks: map[string]health.Check{
+				"server": {Status: health.StatusUnhealthy, Critical: true, Error: &message},
+			},
+		}
+	}
+
+	probes := s.probes()
+	checks := make([]health.Check, len(probes))
+
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checks[i] = runHealthProbe(ctx, probe)
+		}()
+	}
+	wg.Wait()
+
+	report := &health.Report{
+		Status:      health.StatusHealthy,
+		Timestamp:   time.Now().UTC(),
+		Environment: s.server.Config.Primary.Env,
+		Checks:      make(map[string]health.Check, len(probes)),
+	}
+
+	for i, probe := range probes {
+		check := checks[i]
+		report.Checks[probe.name] = check
+
+		switch {
+		case check.Status == health.StatusHealthy:
+		case check.Critical:
+			report.Status = health.StatusUnhealthy
+		case report.Status == health.StatusHealthy:
+			report.Status = health.StatusDegraded
+		}
+
+		if check.Error != nil {
+			s.server.Logger.Warn().
+				Str("operation", "readiness_check").
+				Str("dependency", probe.name).
+				Bool("critical", check.Critical).
+				Int64("latency_ms", check.LatencyMs).
+				Str("error", *check.Error).
+				Msg("dependency health check failed")
+		}
+	}
+
+	s.recordStatus(report)
+
+	return report
+}
+
+// recordStatus folds the report into the history of the status page, at most once per
+// statusSampleInterval. It is written in the background, the probe answers right away.
+func (s *HealthService) recordStatus(report *health.Report) {
+	last := s.lastSampled.Load()
+	if report.Timestamp.Sub(time.Unix(0, last)) < statusSampleInterval {
+		return
+	}
+	if !s.lastSampled.CompareAndSwap(last, report.Timestamp.UnixNano()) {
+		return
+	}
+
+	statuses := componentStatuses(report)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), healthProbeTimeout)
+		defer cancel()
+
+		if err := s.statusPageRepo.RecordChecks(ctx, report.Timestamp, statuses); err != nil {
+			s.server.Logger.Warn().
+				Err(err).
+				Str("operation", "record_status").
+				Msg("failed to record the readiness for the status page")
+		}
+	}()
+}
+
+// componentStatuses turns a readiness report into the status of each component of the status
+// page. A component is left out when none of its checks ran on the instance.
+func componentStatuses(report *health.Report) map[statuspage.Component]health.Status {
+	statuses := map[statuspage.Component]health.Status{
+		statuspage.ComponentAPI: report.Status,
+	}
+
+	for _, mapping := range statusPageChecks {
+		check, ok := report.Checks[mapping.check]
+		if !ok {
+			continue
+		}
+
+		status := health.StatusHealthy
+		if check.Status != health.StatusHealthy {
+			status = mapping.failure
+		}
+		statuses[mapping.component] = worseStatus(statuses[mapping.component], status)
+	}
+
+	return statuses
+}
+
+// worseStatus returns the worse of two statuses, an empty status is no status yet
+func worseStatus(a, b health.Status) health.Status {
+	rank := map[health.Status]int{
+		health.StatusHealthy:   1,
+		health.StatusDegraded:  2,
+		health.StatusUnhealthy: 3,
+	}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
+
+func (s *HealthService) probes() []healthProbe {
+	probes := []healthProbe{
+		{
+			name:     "postgres",
+			critical: true,
+			probe: func(ctx context.Context) error {
+				return s.server.DB.Pool.Ping(ctx)
+			},
+		},
+	}
+
+	if s.server.DB.HasReplicas() {
+		probes = append(probes, healthProbe{
+			name: "postgres_replicas",
+			probe: func(ctx context.Context) error {
+				// Replicas out of rotation only move their reads to the primary
+				if healthy, total := s.server.DB.ReplicasInRotation(); healthy < total {
+					return fmt.Errorf("%d of %d replicas out of rotation", total-healthy, total)
+				}
+				return nil
+			},
+		})
+	}
+
+	if s.server.Redis != nil {
+		probes = append(probes, healthProbe{
+			name: "redis",
+			probe: func(ctx context.Context) error {
+				return s.server.Redis.Ping(ctx).Err()
+			},
+		})
+	}
+
+	if s.blobs != nil {
+		probes = append(probes, healthProbe{
+			name: "storage",
+			probe: func(ctx context.Context) error {
+				return s.blobs.HeadBucket(ctx, s.server.Config.AWS.S3Bucket)
+			},
+		})
+	}
+
+	if s.server.Job != nil {
+		probes = append(probes, healthProbe{
+			name: "job_queue",
+			probe: func(ctx context.Context) error {
+				servers, err := s.server.Job.Inspector.Servers()
+				if err != nil {
+					return err
+				}
+
+				for _, worker := range servers {
+					if worker.Status == "active" {
+						return nil
+					}
+				}
+				return errors.New("no active job worker")
+			},
+		})
+
+		probes = append(probes, healthProbe{
+			name: "notifications",
+			probe: func(ctx context.Context) error {
+				// The emails are sent from the default queue, those failing wait there to be retried
+				tasks, err := s.server.Job.Inspector.ListRetryTasks("default", asynq.PageSize(notificationProbeTasks))
+				if err != nil {
+					if errors.Is(err, asynq.ErrQueueNotFound) {
+						return nil
+					}
+					return err
+				}
+
+				failing := 0
+				for _, task := range tasks {
+					if job.IsNotificationEmail(task.Type) && time.Since(task.LastFailedAt) < notificationFailureWindow {
+						failing++
+					}
+				}
+				if failing > 0 {
+					return fmt.Errorf("%d notification emails failing to send", failing)
+				}
+				return nil
+			},
+		})
+	}
+
+	return probes
+}
+
+func runHealthProbe(ctx context.Context, probe healthProbe) health.Check {
+	ctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+
+	start := time.Now()
+
+	// Not every client takes a context, the probe is abandoned once the timeout passes
+	done := make(chan error, 1)
+	go func() {
+		done <- probe.probe(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	check := health.Check{
+		Status:    health.StatusHealthy,
+		LatencyMs: time.Since(start).Milliseconds(),
+		Critical:  probe.critical,
+	}
+
+	if err != nil {
+		message := err.Error()
+		check.Status = health.StatusUnhealthy
+		check.Error = &message
+	}
+
+	return check
+}
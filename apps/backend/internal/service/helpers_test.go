@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/events"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/repository/memory"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testUserID      = "user_test"
+	testWorkspaceID = "org_test"
+	testAdminID     = "user_admin"
+)
+
+// testEnv runs services against the in-memory fakes of the repositories
+type testEnv struct {
+	server     *server.Server
+	store      *memory.Store
+	repos      *repository.Repositories
+	audit      *AuditService
+	todos      *TodoService
+	workspaces *WorkspaceService
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	logger := zerolog.Nop()
+	s := &server.Server{
+		Config: &config.Config{},
+		Logger: &logger,
+		Events: events.NewBus(),
+	}
+
+	store := memory.NewStore()
+	repos := memory.NewRepositories(store)
+	audit := NewAuditService(s, repos.Audit, repos.Tx)
+	previews := NewPreviewService(s, repos.LinkPreview)
+	covers := NewCoverService(s, repos.Cover, repos.Todo, nil)
+
+	todos := NewTodoService(s, repos.Todo, repos.Category, repos.Comment, repos.MagicTag, repos.Dependency,
+		repos.Workspace, nil, audit, repos.Tx, previews, covers)
+
+	return &testEnv{
+		server:     s,
+		store:      store,
+		repos:      repos,
+		audit:      audit,
+		todos:      todos,
+		workspaces: NewWorkspaceService(s, repos.Workspace, repos.Todo, todos, nil, audit, nil, nil, repos.Tx),
+	}
+}
+
+// context is a request of userID outside of any workspace, the todos are personal
+func (e *testEnv) context(userID string) echo.Context {
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+	c.Set(middleware.UserIDKey, userID)
+	return c
+}
+
+// workspaceContext is a request of userID with workspaceID active
+func (e *testEnv) workspaceContext(userID, workspaceID string) echo.Context {
+	c := e.context(userID)
+	c.Set(middleware.WorkspaceIDKey, workspaceID)
+	return c
+}
+
+// adminContext is a request of an admin of workspaceID with it active
+func (e *testEnv) adminContext(userID, workspaceID string) echo.Context {
+	c := e.workspaceContext(userID, workspaceID)
+	c.Set(middleware.UserRoleKey, workspaceAdminRole)
+	return c
+}
+
+// createTodo creates a personal todo of userID
+func (e *testEnv) createTodo(t *testing.T, userID string, payload *todo.CreateTodoPayload) *todo.Todo {
+	t.Helper()
+
+	created, err := e.repos.Todo.CreateTodo(context.Background(), userID, "", payload)
+	require.NoError(t, err)
+	return created
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/inbox"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+type InboxService struct {
+	server      *server.Server
+	inboxRepo   repository.InboxStore
+	todoService *TodoService
+	txManager   repository.TxManager
+}
+
+func NewInboxService(server *server.Server, inboxRepo repository.InboxStore, todoService *TodoService,
+	txManager repository.TxManager,
+) *InboxService {
+	return &InboxService{
+		server:      server,
+		inboxRepo:   inboxRepo,
+		todoService: todoService,
+		txManager:   txManager,
+	}
+}
+
+// CaptureItem stores the title as is, capture is meant to be as quick as possible
+func (s *InboxService) CaptureItem(ctx echo.Context, userID string, payload *inbox.CapturePayload) (*inbox.Item, error) {
+	logger := middleware.GetLogger(ctx)
+
+	item, err := s.inboxRepo.CaptureItem(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to capture inbox item")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "inbox_item_captured").
+		Str("item_id", item.ID.String()).
+		Msg("Inbox item captured successfully")
+
+	return item, nil
+}
+
+func (s *InboxService) GetItems(ctx echo.Context, userID string, query *inbox.GetItemsQuery) ([]inbox.Item, error) {
+	logger := middleware.GetLogger(ctx)
+
+	items, err := s.inboxRepo.GetItems(ctx.Request().Context(), userID, query)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch inbox items")
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// TriageItem turns an item into a todo. The todo goes through the same checks as one created
+// directly, when they fail the item stays in the inbox.
+func (s *InboxService) TriageItem(ctx echo.Context, userID string, payload *inbox.TriagePayload) (*todo.Todo, error) {
+	logger := middleware.GetLogger(ctx)
+
+	var todoItem *todo.Todo
+	err := withinTx(ctx, s.txManager, func() error {
+		item, err := s.inboxRepo.RemoveItem(ctx.Request().Context(), userID, payload.ID)
+		if err != nil {
+			return err
+		}
+
+		todoItem, err = s.todoService.CreateTodo(ctx, userID, payload.CreatePayload(item))
+		return err
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to triage inbox item")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "inbox_item_triaged").
+		Str("item_id", payload.ID.String()).
+		Str("todo_id", todoItem.ID.String()).
+		Msg("Inbox item triaged successfully")
+
+	return todoItem, nil
+}
+
+func (s *InboxService) DiscardItem(ctx echo.Context, userID string, payload *inbox.DiscardPayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	if _, err := s.inboxRepo.RemoveItem(ctx.Request().Context(), userID, payload.ID); err != nil {
+		logger.Error().Err(err).Msg("failed to discard inbox item")
+		return err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "inbox_item_discarded").
+		Str("item_id", payload.ID.String()).
+		Msg("Inbox item discarded successfully")
+
+	return nil
+}
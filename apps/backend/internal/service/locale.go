@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/i18n"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/locale"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/text/language"
+)
+
+const (
+	localeRedisKeyPrefix = "locale"
+	// localeTTL bounds how often the locale of a user is looked up, choosing one clears it
+	localeTTL = 10 * time.Minute
+)
+
+type LocaleService struct {
+	server     *server.Server
+	localeRepo repository.LocaleStore
+}
+
+func NewLocaleService(server *server.Server, localeRepo repository.LocaleStore) *LocaleService {
+	return &LocaleService{
+		server:     server,
+		localeRepo: localeRepo,
+	}
+}
+
+func (s *LocaleService) GetPreference(ctx echo.Context, userID string, _ *locale.GetPreferencePayload) (*locale.Preference, error) {
+	logger := middleware.GetLogger(ctx)
+
+	chosen, err := s.localeRepo.GetLocale(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch locale")
+		return nil, err
+	}
+
+	return newPreference(chosen), nil
+}
+
+// UpdatePreference saves the locale of the user, the response is already written in it
+func (s *LocaleService) UpdatePreference(ctx echo.Context, userID string,
+	payload *locale.UpdatePreferencePayload,
+) (*locale.Preference, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := s.localeRepo.SetLocale(ctx.Request().Context(), userID, payload.Locale); err != nil {
+		logger.Error().Err(err).Msg("failed to save locale")
+		return nil, err
+	}
+	s.clearLocale(ctx, userID)
+
+	if tag, ok := i18n.Parse(payload.Locale); ok {
+		middleware.SetLocale(ctx, tag)
+	}
+
+	// Business event log
+	logger.Info().
+		Str("event", "locale_updated").
+		Str("locale", payload.Locale).
+		Msg("Locale updated successfully")
+
+	return newPreference(&payload.Locale), nil
+}
+
+// ResetPreference forgets the locale of the user, they follow their browser again
+func (s *LocaleService) ResetPreference(ctx echo.Context, userID string, _ *locale.ResetPreferencePayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	deleted, err := s.localeRepo.DeleteLocale(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to delete locale")
+		return err
+	}
+	s.clearLocale(ctx, userID)
+
+	middleware.SetLocale(ctx, middleware.RequestedLocale(ctx))
+
+	// Business event log
+	logger.Info().
+		Str("event", "locale_reset").
+		Bool("was_set", deleted).
+		Msg("Locale reset successfully")
+
+	return nil
+}
+
+// RecordSession writes the response in the locale the user chose, over the one negotiated from
+// the Accept-Language of the request
+func (s *LocaleService) RecordSession(ctx echo.Context) {
+	userID := middleware.GetUserID(ctx)
+	if userID == "" {
+		return
+	}
+
+	tag, ok, err := s.chosenLocale(ctx.Request().Context(), userID)
+	if err != nil {
+		middleware.GetLogger(ctx).Warn().Err(err).Msg("failed to look up locale")
+		return
+	}
+	if ok {
+		middleware.SetLocale(ctx, tag)
+	}
+}
+
+// UserLocale is the locale emails to the user are written in, the default when they didn't
+// choose one or it can't be looked up
+func (s *LocaleService) UserLocale(ctx context.Context, userID string) language.Tag {
+	tag, ok, err := s.chosenLocale(ctx, userID)
+	if err != nil {
+		s.server.Logger.Warn().Err(err).Str("user_id", userID).Msg("failed to look up locale, using the default")
+		return i18n.Default
+	}
+	if !ok {
+		return i18n.Default
+	}
+	return tag
+}
+
+// chosenLocale returns the locale the user chose, cached as "" for users who didn't
+func (s *LocaleService) chosenLocale(ctx context.Context, userID string) (language.Tag, bool, error) {
+	redisKey := localeRedisKeyPrefix + ":" + userID
+	chosen, err := s.server.Redis.Get(ctx, redisKey).Result()
+	if err != nil {
+		stored, err := s.localeRepo.GetLocale(ctx, userID)
+		if err != nil {
+			return i18n.Default, false, err
+		}
+
+		chosen = ""
+		if stored != nil {
+			chosen = *stored
+		}
+		// Without the cached answer the next request looks it up again
+		_ = s.server.Redis.Set(ctx, redisKey, chosen, localeTTL).Err()
+	}
+
+	if chosen == "" {
+		return i18n.Default, false, nil
+	}
+	// A locale whose catalog was dropped since falls back to the default
+	tag, ok := i18n.Parse(chosen)
+	return tag, ok, nil
+}
+
+// clearLocale drops the cached answer of chosenLocale after the user changed their locale
+func (s *LocaleService) clearLocale(ctx echo.Context, userID string) {
+	redisKey := localeRedisKeyPrefix + ":" + userID
+	if err := s.server.Redis.Del(context.WithoutCancel(ctx.Request().Context()), redisKey).Err(); err != nil {
+		middleware.GetLogger(ctx).Warn().Err(err).Msg("failed to clear cached locale")
+	}
+}
+
+func newPreference(chosen *string) *locale.Preference {
+	supported := make([]string, 0, len(i18n.Supported))
+	for _, tag := range i18n.Supported {
+		supported = append(supported, tag.String())
+	}
+
+	return &locale.Preference{
+		Locale:    chosen,
+		Supported: supported,
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/quickadd"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/magictag"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// MagicTagService manages the shortcuts users type into todo titles, the todo service applies
+// them as todos are written
+type MagicTagService struct {
+	server       *server.Server
+	magicTagRepo repository.MagicTagStore
+	categoryRepo repository.CategoryStore
+}
+
+func NewMagicTagService(server *server.Server, magicTagRepo repository.MagicTagStore,
+	categoryRepo repository.CategoryStore,
+) *MagicTagService {
+	return &MagicTagService{
+		server:       server,
+		magicTagRepo: magicTagRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+func (s *MagicTagService) GetMagicTags(ctx echo.Context, userID string) ([]magictag.MagicTag, error) {
+	logger := middleware.GetLogger(ctx)
+
+	tags, err := s.magicTagRepo.GetMagicTags(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch magic tags")
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (s *MagicTagService) CreateMagicTag(ctx echo.Context, userID string,
+	payload *magictag.CreateMagicTagPayload,
+) (*magictag.MagicTag, error) {
+	logger := middleware.GetLogger(ctx)
+
+	count, err := s.magicTagRepo.CountMagicTags(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to count magic tags")
+		return nil, err
+	}
+	if count >= magictag.MaxPerUser {
+		code := errs.CodeMagicTagLimitReached
+		return nil, errs.NewConflictError(fmt.Sprintf("a user can have at most %d magic tags", magictag.MaxPerUser),
+			false, &code)
+	}
+
+	if err := s.checkCategory(ctx, userID, &payload.Definition); err != nil {
+		logger.Error().Err(err).Msg("magic tag category validation failed")
+		return nil, err
+	}
+
+	tag, err := s.magicTagRepo.CreateMagicTag(ctx.Request().Context(), userID, &payload.Definition)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create magic tag")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "magic_tag_created").
+		Str("magic_tag_id", tag.ID.String()).
+		Str("tag", tag.Tag).
+		Msg("Magic tag created successfully")
+
+	return tag, nil
+}
+
+func (s *MagicTagService) UpdateMagicTag(ctx echo.Context, userID string,
+	payload *magictag.UpdateMagicTagPayload,
+) (*magictag.MagicTag, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := s.checkCategory(ctx, userID, &payload.Definition); err != nil {
+		logger.Error().Err(err).Msg("magic tag category validation failed")
+		return nil, err
+	}
+
+	tag, err := s.magicTagRepo.UpdateMagicTag(ctx.Request().Context(), userID, payload.ID, &payload.Definition)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to update magic tag")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "magic_tag_updated").
+		Str("magic_tag_id", tag.ID.String()).
+		Str("tag", tag.Tag).
+		Msg("Magic tag updated successfully")
+
+	return tag, nil
+}
+
+func (s *MagicTagService) DeleteMagicTag(ctx echo.Context, userID string, tagID uuid.UUID) error {
+	logger := middleware.GetLogger(ctx)
+
+	if _, err := s.magicTagRepo.DeleteMagicTag(ctx.Request().Context(), userID, tagID); err != nil {
+		logger.Error().Err(err).Msg("failed to delete magic tag")
+		return err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "magic_tag_deleted").
+		Str("magic_tag_id", tagID.String()).
+		Msg("Magic tag deleted successfully")
+
+	return nil
+}
+
+// checkCategory makes sure the category a magic tag sets belongs to the user
+func (s *MagicTagService) checkCategory(ctx echo.Context, userID string, definition *magictag.Definition) error {
+	if definition.CategoryID == nil {
+		return nil
+	}
+
+	_, err := s.categoryRepo.GetCategoryByID(ctx.Request().Context(), userID, *definition.CategoryID)
+	return err
+}
+
+// readMagicTags applies the magic tags of the user found in the title. It returns nil when the
+// title carries none. Magic tags are a convenience: when they can't be read the todo is written
+// as typed.
+func readMagicTags(ctx echo.Context, magicTagRepo repository.MagicTagStore, userID,
+	title string,
+) *magictag.Shortcuts {
+	if len(quickadd.Hashtags(title)) == 0 {
+		return nil
+	}
+
+	logger := middleware.GetLogger(ctx)
+	tags, err := magicTagRepo.GetMagicTags(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to fetch magic tags, writing the todo as typed")
+		return nil
+	}
+
+	shortcuts := magictag.Apply(title, tags, time.Now())
+	if len(shortcuts.Applied) == 0 {
+		return nil
+	}
+
+	logger.Debug().Strs("magic_tags", shortcuts.Applied).Msg("applied magic tags")
+	return shortcuts
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/matrix"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+type MatrixService struct {
+	server        *server.Server
+	matrixRepo    repository.MatrixStore
+	todoRepo      repository.TodoStore
+	workspaceRepo repository.WorkspaceStore
+}
+
+func NewMatrixService(server *server.Server, matrixRepo repository.MatrixStore,
+	todoRepo repository.TodoStore, workspaceRepo repository.WorkspaceStore,
+) *MatrixService {
+	return &MatrixService{
+		server:        server,
+		matrixRepo:    matrixRepo,
+		todoRepo:      todoRepo,
+		workspaceRepo: workspaceRepo,
+	}
+}
+
+// GetMatrix buckets the open todos of the user into the urgent and important quadrants with the
+// thresholds of their settings
+func (s *MatrixService) GetMatrix(ctx echo.Context, userID string, _ *matrix.GetMatrixQuery) (*matrix.Matrix, error) {
+	logger := middleware.GetLogger(ctx)
+
+	settings, err := s.settingsFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// One more than laid out tells whether there are more
+	items, err := s.todoRepo.GetOpenTodos(ctx.Request().Context(), userID, matrix.MaxTodos+1)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch open todos")
+		return nil, err
+	}
+
+	result := &matrix.Matrix{
+		Settings:  *settings,
+		Do:        []todo.Todo{},
+		Schedule:  []todo.Todo{},
+		Delegate:  []todo.Todo{},
+		Eliminate: []todo.Todo{},
+		Truncated: len(items) > matrix.MaxTodos,
+	}
+
+	// The important priority stands for a level of the scale of the workspace of each todo
+	importantLevels := map[string]int{}
+	now := time.Now()
+	for _, item := range items[:min(len(items), matrix.MaxTodos)] {
+		workspaceID := ""
+		if item.WorkspaceID != nil {
+			workspaceID = *item.WorkspaceID
+		}
+		importantLevel, ok := importantLevels[workspaceID]
+		if !ok {
+			workflow, err := workspaceWorkflow(ctx.Request().Context(), s.workspaceRepo, item.WorkspaceID)
+			if err != nil {
+				logger.Error().Err(err).Msg("failed to fetch workspace workflow")
+				return nil, err
+			}
+			importantLevel, _ = workflow.ResolvePriority(string(settings.ImportantPriority))
+			importantLevels[workspaceID] = importantLevel
+		}
+
+		urgent, important := settings.IsUrgent(&item, now), settings.IsImportant(&item, importantLevel)
+		switch {
+		case urgent && important:
+			result.Do = append(result.Do, item)
+		case important:
+			result.Schedule = append(result.Schedule, item)
+		case urgent:
+			result.Delegate = append(result.Delegate, item)
+		default:
+			result.Eliminate = append(result.Eliminate, item)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *MatrixService) GetSettings(ctx echo.Context, userID string, _ *matrix.GetSettingsPayload) (*matrix.Settings, error) {
+	return s.settingsFor(ctx, userID)
+}
+
+func (s *MatrixService) UpdateSettings(ctx echo.Context, userID string,
+	payload *matrix.UpdateSettingsPayload,
+) (*matrix.Settings, error) {
+	logger := middleware.GetLogger(ctx)
+
+	current, err := s.settingsFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.matrixRepo.SetSettings(ctx.Request().Context(), userID, payload.Apply(*current))
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to save matrix settings")
+		return nil, err
+	}
+
+	// Business event log
+	logger.Info().
+		Str("event", "matrix_settings_updated").
+		Int("urgent_within_hours", settings.UrgentWithinHours).
+		Str("important_priority", string(settings.ImportantPriority)).
+		Msg("Matrix settings updated successfully")
+
+	return settings, nil
+}
+
+// settingsFor returns the saved settings of the user, or the defaults when they have none
+func (s *MatrixService) settingsFor(ctx echo.Context, userID string) (*matrix.Settings, error) {
+	logger := middleware.GetLogger(ctx)
+
+	settings, err := s.matrixRepo.GetSettings(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch matrix settings")
+		return nil, err
+	}
+	if settings == nil {
+		defaults := matrix.DefaultSettings()
+		return &defaults, nil
+	}
+
+	return settings, nil
+}
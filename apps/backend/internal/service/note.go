@@ -0,0 +1,78 @@
+package service
+
+import (
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/note"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// NoteService keeps the private notes users write on their todos. Notes are only ever read
+// back by their author: they aren't audited, backed up with the workspace or embedded in the
+// todo, and the bodies are never logged.
+type NoteService struct {
+	server   *server.Server
+	noteRepo repository.NoteStore
+	todoRepo repository.TodoStore
+}
+
+func NewNoteService(server *server.Server, noteRepo repository.NoteStore, todoRepo repository.TodoStore) *NoteService {
+	return &NoteService{
+		server:   server,
+		noteRepo: noteRepo,
+		todoRepo: todoRepo,
+	}
+}
+
+func (s *NoteService) GetNote(ctx echo.Context, userID string, todoID uuid.UUID) (*note.Note, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	if _, err := s.todoRepo.CheckTodoExists(reqCtx, userID, todoID); err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return nil, err
+	}
+
+	return s.noteRepo.GetNote(reqCtx, userID, todoID)
+}
+
+// SetNote writes the note of the user on a todo they can access, in place of the one they had
+func (s *NoteService) SetNote(ctx echo.Context, userID string, payload *note.SetNotePayload) (*note.Note, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	if _, err := s.todoRepo.CheckTodoExists(reqCtx, userID, payload.TodoID); err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return nil, err
+	}
+
+	saved, err := s.noteRepo.SetNote(reqCtx, userID, payload.TodoID, payload.Body)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to set private note")
+		return nil, err
+	}
+
+	// Business event log, without the body
+	logger.Info().
+		Str("event", "private_note_set").
+		Str("todo_id", payload.TodoID.String()).
+		Int("length", len(payload.Body)).
+		Msg("Private note set successfully")
+
+	return saved, nil
+}
+
+func (s *NoteService) DeleteNote(ctx echo.Context, userID string, todoID uuid.UUID) error {
+	logger := middleware.GetLogger(ctx)
+
+	if _, err := s.noteRepo.DeleteNote(ctx.Request().Context(), userID, todoID); err != nil {
+		logger.Error().Err(err).Msg("failed to delete private note")
+		return err
+	}
+
+	logger.Info().Str("todo_id", todoID.String()).Msg("deleted private note")
+
+	return nil
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/planner"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type PlannerService struct {
+	server    *server.Server
+	todoRepo  repository.TodoStore
+	focusRepo repository.FocusStore
+	myDayRepo repository.MyDayStore
+	txManager repository.TxManager
+}
+
+func NewPlannerService(server *server.Server, todoRepo repository.TodoStore, focusRepo repository.FocusStore,
+	myDayRepo repository.MyDayStore, txManager repository.TxManager,
+) *PlannerService {
+	return &PlannerService{
+		server:    server,
+		todoRepo:  todoRepo,
+		focusRepo: focusRepo,
+		myDayRepo: myDayRepo,
+		txManager: txManager,
+	}
+}
+
+// GetPlanner lays the todos due in a week out per day of the user's timezone, with the backlog
+// of unscheduled todos to plan from
+func (s *PlannerService) GetPlanner(ctx echo.Context, userID string, query *planner.GetPlannerQuery) (*planner.Planner, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+	location := query.Location()
+
+	day := time.Now().In(location)
+	if query.Week != nil {
+		day, _ = time.ParseInLocation(time.DateOnly, *query.Week, location)
+	}
+	start := planner.WeekStart(day)
+	end := start.AddDate(0, 0, 7)
+
+	due, err := s.todoRepo.GetTodosDueBetween(reqCtx, userID, start, end)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch todos due in the week")
+		return nil, err
+	}
+
+	backlog, err := s.todoRepo.GetUnscheduledTodos(reqCtx, userID, planner.MaxBacklog)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch unscheduled todos")
+		return nil, err
+	}
+
+	accuracy, err := s.accuracy(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]planner.Day, 7)
+	for i := range days {
+		days[i] = planner.Day{Date: start.AddDate(0, 0, i).Format(time.DateOnly), Todos: []todo.Todo{}}
+	}
+	for _, item := range due {
+		// Days are counted on the calendar, a DST change makes one of them 23 or 25 hours long
+		local := item.DueDate.In(location)
+		index := int(time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC).
+			Sub(time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)).Hours() / 24)
+		if index < 0 || index >= len(days) {
+			continue
+		}
+
+		planned := &days[index]
+		planned.Todos = append(planned.Todos, item)
+		if item.StatusCategory == todo.StatusCompleted {
+			continue
+		}
+		if item.Metadata != nil && item.Metadata.EstimatedMinutes != nil {
+			planned.EstimatedMinutes += *item.Metadata.EstimatedMinutes
+			planned.ForecastMinutes += accuracy.Adjust(item.Metadata.EstimatedMinutes)
+		} else {
+			planned.UnestimatedCount++
+			planned.ForecastMinutes += accuracy.Adjust(nil)
+		}
+	}
+	for i := range days {
+		days[i].OverCapacity = days[i].EstimatedMinutes > *query.CapacityMinutes
+		days[i].Overcommitted = days[i].ForecastMinutes > *query.CapacityMinutes
+	}
+
+	return &planner.Planner{
+		Week:            start.Format(time.DateOnly),
+		Timezone:        location.String(),
+		CapacityMinutes: *query.CapacityMinutes,
+		Accuracy:        accuracy,
+		Days:            days,
+		Backlog:         backlog,
+	}, nil
+}
+
+// GetForecast predicts the day each matching open todo gets done, working through them in the
+// order they are due at the capacity of a day. Estimates are corrected by how long the user's
+// past todos took against their estimates.
+func (s *PlannerService) GetForecast(ctx echo.Context, userID string, query *planner.GetForecastQuery,
+) (*planner.Forecast, error) {
+	logger := middleware.GetLogger(ctx)
+
+	accuracy, err := s.accuracy(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filtered here, the open todos are few enough to read at once. One more than forecast
+	// tells whether there are more.
+	items, err := s.todoRepo.GetOpenTodos(ctx.Request().Context(), userID, planner.MaxForecastTodos+1)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch open todos")
+		return nil, err
+	}
+	truncated := len(items) > planner.MaxForecastTodos
+
+	matching := make([]todo.Todo, 0, len(items))
+	for _, item := range items[:min(len(items), planner.MaxForecastTodos)] {
+		if query.Matches(&item) {
+			matching = append(matching, item)
+		}
+	}
+
+	forecast := planner.NewForecast(matching, accuracy, time.Now().In(query.Location()), *query.CapacityMinutes)
+	forecast.Truncated = truncated
+
+	return forecast, nil
+}
+
+// accuracy learns how the user's estimates compare to the time they focused on the todos
+func (s *PlannerService) accuracy(ctx echo.Context, userID string) (planner.Accuracy, error) {
+	samples, err := s.focusRepo.GetEstimateSamples(ctx.Request().Context(), userID, planner.MaxEstimateSamples)
+	if err != nil {
+		middleware.GetLogger(ctx).Error().Err(err).Msg("failed to fetch estimate samples")
+		return planner.Accuracy{}, err
+	}
+
+	return planner.NewAccuracy(samples), nil
+}
+
+// Schedule moves every assigned todo to its day in one transaction, either all of them are
+// scheduled or none
+func (s *PlannerService) Schedule(ctx echo.Context, userID string, payload *planner.SchedulePayload) ([]todo.Todo, error) {
+	logger := middleware.GetLogger(ctx)
+	location := payload.Location()
+
+	todoIDs := make([]uuid.UUID, 0, len(payload.Assignments))
+	for _, assignment := range payload.Assignments {
+		todoIDs = append(todoIDs, assignment.TodoID)
+	}
+
+	scheduled := make([]todo.Todo, 0, len(payload.Assignments))
+	err := s.txManager.WithinTx(ctx.Request().Context(), func(txCtx context.Context) error {
+		items, err := s.todoRepo.GetTodosByIDs(txCtx, userID, todoIDs)
+		if err != nil {
+			return err
+		}
+		byID := make(map[uuid.UUID]todo.Todo, len(items))
+		for _, item := range items {
+			byID[item.ID] = item
+		}
+
+		for _, assignment := range payload.Assignments {
+			item, ok := byID[assignment.TodoID]
+			if !ok {
+				code := errs.CodeTodoNotFound
+				return errs.NewNotFoundError("todo not found", false, &code)
+			}
+
+			day, _ := time.ParseInLocation(time.DateOnly, assignment.Date, location)
+			dueDate := planner.DueAt(day, item.DueDate)
+			updated, err := s.todoRepo.UpdateTodo(txCtx, userID, &todo.UpdateTodoPayload{
+				ID:      item.ID,
+				DueDate: &dueDate,
+			})
+			if err != nil {
+				return err
+			}
+			scheduled = append(scheduled, *updated)
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to schedule todos")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "todos_scheduled").
+		Int("todo_count", len(scheduled)).
+		Msg("Todos scheduled successfully")
+
+	return scheduled, nil
+}
+
+// GetMyDay returns the todos picked for today in the user's timezone, with those left open on
+// earlier days waiting for review and how much of today is done
+func (s *PlannerService) GetMyDay(ctx echo.Context, userID string, location *time.Location) (*planner.MyDay, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+	day, _ := planner.DayBounds(time.Now(), location)
+
+	todos, err := s.myDayRepo.GetTodos(reqCtx, userID, day)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch my day todos")
+		return nil, err
+	}
+
+	review, err := s.myDayRepo.GetReview(reqCtx, userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch my day review")
+		return nil, err
+	}
+
+	summary := planner.DaySummary{Date: day}
+	summaries, err := s.myDayRepo.GetSummaries(reqCtx, userID, day, day)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch my day summary")
+		return nil, err
+	}
+	if len(summaries) > 0 {
+		summary = summaries[0]
+	}
+
+	return &planner.MyDay{
+		Date:     day,
+		Timezone: location.String(),
+		Todos:    todos,
+		Review:   review,
+		Summary:  summary.WithRate(),
+	}, nil
+}
+
+// AddToMyDay picks todos for today, their due dates are left as they were
+func (s *PlannerService) AddToMyDay(ctx echo.Context, userID string, payload *planner.AddMyDayTodosPayload,
+) (*planner.MyDay, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+	location := payload.Location()
+	day, endsAt := planner.DayBounds(time.Now(), location)
+
+	items, err := s.todoRepo.GetTodosByIDs(reqCtx, userID, payload.TodoIDs)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch todos to add to my day")
+		return nil, err
+	}
+	if len(items) != len(payload.TodoIDs) {
+		code := errs.CodeTodoNotFound
+		return nil, errs.NewNotFoundError("todo not found", false, &code)
+	}
+
+	if err := s.myDayRepo.AddTodos(reqCtx, userID, day, endsAt, payload.TodoIDs); err != nil {
+		logger.Error().Err(err).Msg("failed to add todos to my day")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "todos_added_to_my_day").
+		Str("day", day).
+		Int("todo_count", len(payload.TodoIDs)).
+		Msg("Todos added to my day successfully")
+
+	return s.GetMyDay(ctx, userID, location)
+}
+
+// RemoveFromMyDay takes a todo off today, or drops it from review when it was left open on an
+// earlier day
+func (s *PlannerService) RemoveFromMyDay(ctx echo.Context, userID string, payload *planner.RemoveMyDayTodoPayload) error {
+	logger := middleware.GetLogger(ctx)
+	day, _ := planner.DayBounds(time.Now(), payload.Location())
+
+	if err := s.myDayRepo.RemoveTodo(ctx.Request().Context(), userID, day, payload.TodoID); err != nil {
+		logger.Error().Err(err).Msg("failed to remove todo from my day")
+		return err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "todo_removed_from_my_day").
+		Str("todo_id", payload.TodoID.String()).
+		Str("day", day).
+		Msg("Todo removed from my day successfully")
+
+	return nil
+}
+
+// GetDaySummaries returns how much of the list of each of the last days got done, latest
+// first. Days without todos are left out.
+func (s *PlannerService) GetDaySummaries(ctx echo.Context, userID string, query *planner.GetDaySummariesQuery,
+) ([]planner.DaySummary, error) {
+	logger := middleware.GetLogger(ctx)
+	now := time.Now()
+	location := query.Location()
+
+	to, _ := planner.DayBounds(now, location)
+	from, _ := planner.DayBounds(now.In(location).AddDate(0, 0, 1-*query.Days), location)
+
+	summaries, err := s.myDayRepo.GetSummaries(ctx.Request().Context(), userID, from, to)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch day summaries")
+		return nil, err
+	}
+
+	for i := range summaries {
+		summaries[i] = summaries[i].WithRate()
+	}
+
+	return summaries, nil
+}
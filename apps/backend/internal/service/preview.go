@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/events"
+	"github.com/Sameer16536/ExecuTask/internal/lib/breaker"
+	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/lib/unfurl"
+	"github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/preview"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+type PreviewService struct {
+	server      *server.Server
+	previewRepo repository.LinkPreviewStore
+	// client is nil when link previews are disabled
+	client *unfurl.Client
+}
+
+func NewPreviewService(server *server.Server, previewRepo repository.LinkPreviewStore) *PreviewService {
+	var client *unfurl.Client
+	if cfg := server.Config.LinkPreviews; cfg != nil && cfg.Enabled {
+		client = unfurl.New(server.Config.Outbound, cfg.Timeout)
+	}
+
+	return &PreviewService{
+		server:      server,
+		previewRepo: previewRepo,
+		client:      client,
+	}
+}
+
+// queueEventLinks queues the links of the texts a change wrote. A failure never fails the
+// request, the texts are saved without previews at worst.
+func (s *PreviewService) queueEventLinks(ctx context.Context, event events.Event) error {
+	switch e := event.(type) {
+	case *events.TodoCreated:
+		return s.queueLinks(ctx, e.Todo.Description)
+	case *events.TodoUpdated:
+		if e.DescriptionChanged {
+			return s.queueLinks(ctx, e.Todo.Description)
+		}
+	case *events.CommentAdded:
+		return s.queueLinks(ctx, e.Comment.Content)
+	case *events.CommentUpdated:
+		return s.queueLinks(ctx, e.Comment.Content)
+	}
+	return nil
+}
+
+// queueLinks enqueues the unfurling of the links of the texts that have no fresh preview
+func (s *PreviewService) queueLinks(ctx context.Context, texts ...string) error {
+	if s.client == nil {
+		return nil
+	}
+
+	urls := []string{}
+	for _, text := range texts {
+		urls = append(urls, preview.ExtractURLs(text)...)
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+
+	stale, err := s.staleURLs(ctx, urls)
+	if err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	return job.EnqueueLinkPreview(ctx, s.server.Job.Client, &job.LinkPreviewTask{URLs: stale})
+}
+
+// staleURLs leaves out the URLs with a fresh preview, ready or failed
+func (s *PreviewService) staleURLs(ctx context.Context, urls []string) ([]string, error) {
+	cfg := s.server.Config.LinkPreviews
+
+	previews, err := s.previewRepo.GetPreviews(ctx, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	fresh := make(map[string]bool, len(previews))
+	for _, card := range previews {
+		fresh[card.URL] = card.IsFresh(now, cfg.TTL, cfg.FailureTTL)
+	}
+
+	stale := []string{}
+	seen := map[string]bool{}
+	for _, url := range urls {
+		if fresh[url] || seen[url] {
+			continue
+		}
+		seen[url] = true
+		stale = append(stale, url)
+	}
+
+	return stale, nil
+}
+
+// UnfurlLinks fetches the pages without a fresh preview. A page that can't be previewed is
+// stored as failed so it isn't fetched again before the failure TTL, only storing fails the task.
+// While the link previews breaker is open the remaining pages are skipped, they are queued
+// again with the next edit of their text.
+func (s *PreviewService) UnfurlLinks(ctx context.Context, urls []string) error {
+	log := logger.WithSpanContext(*s.server.Logger, ctx)
+
+	if s.client == nil {
+		return errors.New("link previews are not enabled on this server")
+	}
+
+	stale, err := s.staleURLs(ctx, urls)
+	if err != nil {
+		return err
+	}
+
+	fetcher := s.server.Breakers.Get("link_previews")
+
+	ready, skipped := 0, 0
+	for i, url := range stale {
+		card := &preview.Preview{URL: url, Status: preview.StatusFailed}
+
+		var fetched *unfurl.Card
+		err := fetcher.Do(ctx, func(ctx context.Context) error {
+			var err error
+			fetched, err = s.client.Fetch(ctx, url)
+			if unfurl.IsPageError(err) {
+				// A broken link, not a failing network
+				return breaker.Expected(err)
+			}
+			return err
+		})
+		if breaker.IsUnavailable(err) {
+			skipped = len(stale) - i
+			log.Warn().Err(err).Int("skipped_count", skipped).Msg("Skipped unfurling links")
+			break
+		}
+		if err != nil {
+			log.Warn().Str("url", url).Err(err).Msg("Failed to unfurl link")
+		} else {
+			card.Status = preview.StatusReady
+			card.Title = optionalString(fetched.Title)
+			card.Description = optionalString(fetched.Description)
+			card.ImageURL = optionalString(fetched.ImageURL)
+			card.SiteName = optionalString(fetched.SiteName)
+			ready++
+		}
+		card.FetchedAt = time.Now()
+
+		if err := s.previewRepo.SavePreview(ctx, card); err != nil {
+			return err
+		}
+	}
+
+	log.Info().
+		Str("event", "links_unfurled").
+		Int("url_count", len(stale)).
+		Int("ready_count", ready).
+		Int("skipped_count", skipped).
+		Msg("Links unfurled")
+
+	return nil
+}
+
+// PreviewsFor returns the ready previews of the links of each text, in the order the links
+// appear. Previews are an extra, a failed lookup is logged and leaves them out.
+func (s *PreviewService) PreviewsFor(ctx echo.Context, texts []string) [][]preview.Preview {
+	previews := make([][]preview.Preview, len(texts))
+	if s.client == nil {
+		return previews
+	}
+
+	links := make([][]string, len(texts))
+	urls := []string{}
+	for i, text := range texts {
+		links[i] = preview.ExtractURLs(text)
+		urls = append(urls, links[i]...)
+	}
+	if len(urls) == 0 {
+		return previews
+	}
+
+	found, err := s.previewRepo.GetPreviews(ctx.Request().Context(), urls)
+	if err != nil {
+		middleware.GetLogger(ctx).Error().Err(err).Msg("failed to fetch link previews")
+		return previews
+	}
+
+	byURL := make(map[string]preview.Preview, len(found))
+	for _, card := range found {
+		if card.Status == preview.StatusReady {
+			byURL[card.URL] = card
+		}
+	}
+
+	for i, textLinks := range links {
+		for _, url := range textLinks {
+			if card, ok := byURL[url]; ok {
+				previews[i] = append(previews[i], card)
+			}
+		}
+	}
+
+	return previews
+}
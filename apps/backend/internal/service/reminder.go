@@ -0,0 +1,157 @@
+package service
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// reminderHistory is how many of the latest completions reminder times are learned from
+	reminderHistory = 500
+	// reminderMinSamples is how many similar completions a basis needs before it suggests anything
+	reminderMinSamples = 5
+	// reminderMinConfidence is the share of the completions the usual hour must hold
+	reminderMinConfidence = 0.25
+	// reminderLead is how long before the usual completion hour the user is reminded
+	reminderLead = time.Hour
+)
+
+type ReminderService struct {
+	server       *server.Server
+	todoRepo     repository.TodoStore
+	featureFlags *FeatureFlagService
+}
+
+func NewReminderService(server *server.Server, todoRepo repository.TodoStore,
+	featureFlags *FeatureFlagService,
+) *ReminderService {
+	return &ReminderService{
+		server:       server,
+		todoRepo:     todoRepo,
+		featureFlags: featureFlags,
+	}
+}
+
+// SuggestReminders proposes times to be reminded of an open todo from the hour of day the user
+// usually completes todos of the same category, with the same tags, or any todo at all. Hours
+// are UTC. Suggestions are best effort: nil when the history is too thin or can't be read.
+func (s *ReminderService) SuggestReminders(ctx echo.Context, userID string, todoItem *todo.Todo) []todo.ReminderSuggestion {
+	logger := middleware.GetLogger(ctx)
+
+	if todoItem.StatusCategory == todo.StatusCompleted || todoItem.StatusCategory == todo.StatusArchived {
+		return nil
+	}
+	if !s.featureFlags.IsEnabled(ctx, config.FeatureReminderSuggestions) {
+		return nil
+	}
+
+	completed, err := s.todoRepo.GetRecentlyCompletedTodos(ctx.Request().Context(), userID, reminderHistory)
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to fetch completed todos for reminder suggestions")
+		return nil
+	}
+
+	var tags []string
+	if todoItem.Metadata != nil {
+		tags = todoItem.Metadata.Tags
+	}
+
+	bases := []struct {
+		basis   todo.ReminderBasis
+		similar func(item todo.Todo) bool
+	}{
+		{todo.ReminderBasisCategory, func(item todo.Todo) bool {
+			return todoItem.CategoryID != nil && item.CategoryID != nil && *item.CategoryID == *todoItem.CategoryID
+		}},
+		{todo.ReminderBasisTags, func(item todo.Todo) bool {
+			return item.Metadata != nil && slices.ContainsFunc(item.Metadata.Tags, func(tag string) bool {
+				return slices.ContainsFunc(tags, func(own string) bool {
+					return strings.EqualFold(own, tag)
+				})
+			})
+		}},
+		{todo.ReminderBasisHistory, func(item todo.Todo) bool {
+			return true
+		}},
+	}
+
+	now := time.Now().UTC()
+	suggestions := []todo.ReminderSuggestion{}
+	for _, basis := range bases {
+		hours := [24]int{}
+		samples := 0
+		for _, item := range completed {
+			if basis.similar(item) {
+				hours[item.CompletedAt.UTC().Hour()]++
+				samples++
+			}
+		}
+		if samples < reminderMinSamples {
+			continue
+		}
+
+		usualHour := 0
+		for hour, count := range hours {
+			if count > hours[usualHour] {
+				usualHour = hour
+			}
+		}
+		confidence := float64(hours[usualHour]) / float64(samples)
+		if confidence < reminderMinConfidence {
+			continue
+		}
+
+		remindAt, ok := reminderTime(usualHour, todoItem.DueDate, now)
+		if !ok || slices.ContainsFunc(suggestions, func(suggestion todo.ReminderSuggestion) bool {
+			return suggestion.RemindAt.Equal(remindAt)
+		}) {
+			continue
+		}
+
+		suggestions = append(suggestions, todo.ReminderSuggestion{
+			RemindAt:   remindAt,
+			Basis:      basis.basis,
+			Confidence: confidence,
+			SampleSize: samples,
+		})
+	}
+
+	if len(suggestions) == 0 {
+		return nil
+	}
+
+	slices.SortStableFunc(suggestions, func(a, b todo.ReminderSuggestion) int {
+		return cmp.Compare(b.Confidence, a.Confidence)
+	})
+
+	return suggestions
+}
+
+// reminderTime is the reminder lead ahead of the usual completion hour: the last one before the
+// todo is due, or the next one when it has no due date. false when that time has already passed.
+func reminderTime(usualHour int, dueDate *time.Time, now time.Time) (time.Time, bool) {
+	if dueDate == nil {
+		remindAt := time.Date(now.Year(), now.Month(), now.Day(), usualHour, 0, 0, 0, time.UTC).Add(-reminderLead)
+		if !remindAt.After(now) {
+			remindAt = remindAt.AddDate(0, 0, 1)
+		}
+		return remindAt, true
+	}
+
+	due := dueDate.UTC()
+	remindAt := time.Date(due.Year(), due.Month(), due.Day(), usualHour, 0, 0, 0, time.UTC).Add(-reminderLead)
+	if !remindAt.Before(due) {
+		remindAt = remindAt.AddDate(0, 0, -1)
+	}
+
+	return remindAt, remindAt.After(now)
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/report"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// ReportService manages the scheduled reports of users. The report-schedules cron job queues
+// the runs due and the job worker renders them through RunReport.
+type ReportService struct {
+	server        *server.Server
+	reportRepo    repository.ReportStore
+	categoryRepo  repository.CategoryStore
+	exportService *ExportService
+}
+
+func NewReportService(server *server.Server, reportRepo repository.ReportStore,
+	categoryRepo repository.CategoryStore, exportService *ExportService,
+) *ReportService {
+	return &ReportService{
+		server:        server,
+		reportRepo:    reportRepo,
+		categoryRepo:  categoryRepo,
+		exportService: exportService,
+	}
+}
+
+func (s *ReportService) GetSchedules(ctx echo.Context, userID string) ([]report.Schedule, error) {
+	logger := middleware.GetLogger(ctx)
+
+	schedules, err := s.reportRepo.GetSchedules(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch report schedules")
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+func (s *ReportService) GetSchedule(ctx echo.Context, userID string, scheduleID uuid.UUID) (*report.Schedule, error) {
+	logger := middleware.GetLogger(ctx)
+
+	schedule, err := s.reportRepo.GetSchedule(ctx.Request().Context(), userID, scheduleID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch report schedule")
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (s *ReportService) CreateSchedule(ctx echo.Context, userID string,
+	payload *report.CreateSchedulePayload,
+) (*report.Schedule, error) {
+	logger := middleware.GetLogger(ctx)
+
+	count, err := s.reportRepo.CountSchedules(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to count report schedules")
+		return nil, err
+	}
+	if count >= report.MaxPerUser {
+		code := errs.CodeReportScheduleLimitReached
+		return nil, errs.NewConflictError(fmt.Sprintf("a user can have at most %d scheduled reports", report.MaxPerUser),
+			false, &code)
+	}
+
+	if err := s.checkCategory(ctx, userID, &payload.Definition); err != nil {
+		logger.Error().Err(err).Msg("report schedule category validation failed")
+		return nil, err
+	}
+
+	nextRunAt := payload.Timing.NextRun(time.Now())
+	schedule, err := s.reportRepo.CreateSchedule(ctx.Request().Context(), userID, &payload.Definition, nextRunAt)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create report schedule")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "report_schedule_created").
+		Str("schedule_id", schedule.ID.String()).
+		Str("frequency", string(schedule.Frequency)).
+		Int("recipients", len(schedule.Recipients)).
+		Msg("Report schedule created successfully")
+
+	return schedule, nil
+}
+
+// UpdateSchedule replaces the definition of a schedule, its next run is counted again from now
+// with the new timing. A paused schedule stays paused.
+func (s *ReportService) UpdateSchedule(ctx echo.Context, userID string,
+	payload *report.UpdateSchedulePayload,
+) (*report.Schedule, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := s.checkCategory(ctx, userID, &payload.Definition); err != nil {
+		logger.Error().Err(err).Msg("report schedule category validation failed")
+		return nil, err
+	}
+
+	nextRunAt := payload.Timing.NextRun(time.Now())
+	schedule, err := s.reportRepo.UpdateSchedule(ctx.Request().Context(), userID, payload.ID, &payload.Definition,
+		nextRunAt)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to update report schedule")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "report_schedule_updated").
+		Str("schedule_id", schedule.ID.String()).
+		Msg("Report schedule updated successfully")
+
+	return schedule, nil
+}
+
+func (s *ReportService) PauseSchedule(ctx echo.Context, userID string, scheduleID uuid.UUID) (*report.Schedule, error) {
+	logger := middleware.GetLogger(ctx)
+
+	schedule, err := s.reportRepo.PauseSchedule(ctx.Request().Context(), userID, scheduleID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to pause report schedule")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "report_schedule_paused").
+		Str("schedule_id", schedule.ID.String()).
+		Msg("Report schedule paused successfully")
+
+	return schedule, nil
+}
+
+// ResumeSchedule runs a paused schedule again from its next time due, the runs missed while it
+// was paused are not sent
+func (s *ReportService) ResumeSchedule(ctx echo.Context, userID string, scheduleID uuid.UUID) (*report.Schedule, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	schedule, err := s.reportRepo.GetSchedule(reqCtx, userID, scheduleID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch report schedule")
+		return nil, err
+	}
+	if schedule.PausedAt == nil {
+		return schedule, nil
+	}
+
+	schedule, err = s.reportRepo.ResumeSchedule(reqCtx, userID, scheduleID, schedule.Timing.NextRun(time.Now()))
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to resume report schedule")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "report_schedule_resumed").
+		Str("schedule_id", schedule.ID.String()).
+		Msg("Report schedule resumed successfully")
+
+	return schedule, nil
+}
+
+func (s *ReportService) DeleteSchedule(ctx echo.Context, userID string, scheduleID uuid.UUID) error {
+	logger := middleware.GetLogger(ctx)
+
+	if _, err := s.reportRepo.DeleteSchedule(ctx.Request().Context(), userID, scheduleID); err != nil {
+		logger.Error().Err(err).Msg("failed to delete report schedule")
+		return err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "report_schedule_deleted").
+		Str("schedule_id", scheduleID.String()).
+		Msg("Report schedule deleted successfully")
+
+	return nil
+}
+
+// RunReport renders the run of a schedule due at runAt and queues an email to every recipient.
+// It runs in the job worker, schedules deleted or paused since the run was queued are skipped.
+func (s *ReportService) RunReport(ctx context.Context, userID string, scheduleID uuid.UUID, runAt time.Time) error {
+	log := logger.WithSpanContext(*s.server.Logger, ctx).With().
+		Str("user_id", userID).
+		Str("schedule_id", scheduleID.String()).
+		Logger()
+
+	schedule, err := s.reportRepo.GetSchedule(ctx, userID, scheduleID)
+	if err != nil {
+		if isReportScheduleNotFound(err) {
+			// Deleted before it ran
+			return nil
+		}
+		return err
+	}
+	if schedule.PausedAt != nil {
+		return nil
+	}
+
+	exportItem, err := s.exportService.ExportReport(ctx, schedule)
+	if err != nil {
+		return err
+	}
+	if exportItem.DownloadURL == nil {
+		return fmt.Errorf("report export %s has no download link", exportItem.ID.String())
+	}
+
+	for _, recipient := range schedule.Recipients {
+		err := job.EnqueueScheduledReportEmail(ctx, s.server.Job.Client, &job.ScheduledReportEmailTask{
+			UserID:        userID,
+			ScheduleID:    scheduleID,
+			RunAt:         runAt,
+			To:            recipient,
+			ReportName:    schedule.Name,
+			Format:        string(schedule.Format),
+			DownloadURL:   *exportItem.DownloadURL,
+			SizeBytes:     *exportItem.SizeBytes,
+			LinkExpiresAt: *exportItem.LinkExpiresAt,
+			ExpiresAt:     *exportItem.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.reportRepo.RecordRun(ctx, scheduleID, runAt, nil); err != nil {
+		return err
+	}
+
+	// Business event log
+	log.Info().
+		Str("event", "report_run_completed").
+		Str("export_id", exportItem.ID.String()).
+		Int("recipients", len(schedule.Recipients)).
+		Msg("Scheduled report sent")
+
+	return nil
+}
+
+// FailReport records why a run failed on its schedule, the next run is tried as usual
+func (s *ReportService) FailReport(ctx context.Context, scheduleID uuid.UUID, runAt time.Time, reason string) error {
+	return s.reportRepo.RecordRun(ctx, scheduleID, runAt, &reason)
+}
+
+// checkCategory makes sure the category a report filters on belongs to the user
+func (s *ReportService) checkCategory(ctx echo.Context, userID string, definition *report.Definition) error {
+	if definition.Filters.CategoryID == nil {
+		return nil
+	}
+
+	_, err := s.categoryRepo.GetCategoryByID(ctx.Request().Context(), userID, *definition.Filters.CategoryID)
+	return err
+}
+
+func isReportScheduleNotFound(err error) bool {
+	var httpErr *errs.HTTPError
+	return errors.As(err, &httpErr) && httpErr.Code == errs.CodeReportScheduleNotFound
+}
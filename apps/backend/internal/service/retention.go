@@ -0,0 +1,123 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/model/retention"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+type RetentionService struct {
+	server        *server.Server
+	retentionRepo repository.RetentionStore
+	auditService  *AuditService
+}
+
+func NewRetentionService(server *server.Server, retentionRepo repository.RetentionStore,
+	auditService *AuditService,
+) *RetentionService {
+	return &RetentionService{
+		server:        server,
+		retentionRepo: retentionRepo,
+		auditService:  auditService,
+	}
+}
+
+func (s *RetentionService) GetPolicies(ctx echo.Context, _ *retention.GetPoliciesPayload) (*retention.Overview, error) {
+	logger := middleware.GetLogger(ctx)
+
+	policies, err := s.retentionRepo.GetPolicies(ctx.Request().Context())
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch retention policies")
+		return nil, err
+	}
+
+	retentionConfig := s.server.CurrentConfig().Retention
+
+	return &retention.Overview{
+		Default:         retention.RulesFromConfig(retentionConfig),
+		DefaultEnforced: retentionConfig.Enforce,
+		Policies:        policies,
+	}, nil
+}
+
+func (s *RetentionService) SetPolicy(ctx echo.Context, userID string,
+	payload *retention.SetPolicyPayload,
+) (*retention.Policy, error) {
+	logger := middleware.GetLogger(ctx)
+
+	policy, err := s.retentionRepo.SetPolicy(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", payload.WorkspaceID).Msg("failed to set retention policy")
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminRetention,
+		EntityType: "retention_policy",
+		EntityID:   payload.WorkspaceID,
+		After:      policy,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "retention_policy_set").
+		Str("workspace_id", policy.WorkspaceID).
+		Bool("enforce", policy.Enforce).
+		Msg("Retention policy set successfully")
+
+	return policy, nil
+}
+
+func (s *RetentionService) DeletePolicy(ctx echo.Context, payload *retention.DeletePolicyPayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	policy, err := s.retentionRepo.DeletePolicy(ctx.Request().Context(), payload.WorkspaceID)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", payload.WorkspaceID).Msg("failed to delete retention policy")
+		return err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminRetention,
+		EntityType: "retention_policy",
+		EntityID:   payload.WorkspaceID,
+		Before:     policy,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "retention_policy_deleted").
+		Str("workspace_id", policy.WorkspaceID).
+		Msg("Retention policy deleted successfully")
+
+	return nil
+}
+
+// PreviewPolicy reports what a policy would delete right now, enforced or not
+func (s *RetentionService) PreviewPolicy(ctx echo.Context, payload *retention.PreviewPolicyPayload) (*retention.Report, error) {
+	logger := middleware.GetLogger(ctx)
+
+	rules := retention.RulesFromConfig(s.server.CurrentConfig().Retention)
+	if payload.WorkspaceID != "" {
+		policy, err := s.retentionRepo.GetPolicy(ctx.Request().Context(), payload.WorkspaceID)
+		if err != nil {
+			logger.Error().Err(err).Str("workspace_id", payload.WorkspaceID).Msg("failed to fetch retention policy")
+			return nil, err
+		}
+		rules = policy.Rules
+	}
+
+	report, err := s.retentionRepo.PreviewRetention(ctx.Request().Context(), payload.WorkspaceID, rules, time.Now())
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", payload.WorkspaceID).Msg("failed to preview retention policy")
+		return nil, err
+	}
+	report.DryRun = true
+
+	return report, nil
+}
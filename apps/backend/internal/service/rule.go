@@ -0,0 +1,472 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/events"
+	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/model/rule"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+type RuleService struct {
+	server       *server.Server
+	ruleRepo     repository.RuleStore
+	todoRepo     repository.TodoStore
+	categoryRepo repository.CategoryStore
+	audit        *AuditService
+}
+
+func NewRuleService(server *server.Server, ruleRepo repository.RuleStore, todoRepo repository.TodoStore,
+	categoryRepo repository.CategoryStore, auditService *AuditService,
+) *RuleService {
+	return &RuleService{
+		server:       server,
+		ruleRepo:     ruleRepo,
+		todoRepo:     todoRepo,
+		categoryRepo: categoryRepo,
+		audit:        auditService,
+	}
+}
+
+func (s *RuleService) CreateRule(ctx echo.Context, userID string, payload *rule.CreateRulePayload) (*rule.Rule, error) {
+	logger := middleware.GetLogger(ctx)
+
+	count, err := s.ruleRepo.CountRules(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to count rules")
+		return nil, err
+	}
+	if count >= rule.MaxRulesPerUser {
+		code := errs.CodeRuleLimitReached
+		return nil, errs.NewConflictError(fmt.Sprintf("a user can have at most %d rules", rule.MaxRulesPerUser),
+			false, &code)
+	}
+
+	if err := s.checkCategories(ctx, userID, payload.Conditions, payload.Actions); err != nil {
+		logger.Error().Err(err).Msg("rule category validation failed")
+		return nil, err
+	}
+
+	ruleItem, err := s.ruleRepo.CreateRule(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create rule")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "rule_created").
+		Str("rule_id", ruleItem.ID.String()).
+		Str("trigger", string(ruleItem.Trigger)).
+		Int("action_count", len(ruleItem.Actions)).
+		Msg("Rule created successfully")
+
+	return ruleItem, nil
+}
+
+func (s *RuleService) GetRules(ctx echo.Context, userID string) ([]rule.Rule, error) {
+	logger := middleware.GetLogger(ctx)
+
+	rules, err := s.ruleRepo.GetRules(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch rules")
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (s *RuleService) GetRule(ctx echo.Context, userID string, ruleID uuid.UUID) (*rule.Rule, error) {
+	logger := middleware.GetLogger(ctx)
+
+	ruleItem, err := s.ruleRepo.GetRule(ctx.Request().Context(), userID, ruleID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch rule")
+		return nil, err
+	}
+
+	return ruleItem, nil
+}
+
+func (s *RuleService) UpdateRule(ctx echo.Context, userID string, payload *rule.UpdateRulePayload) (*rule.Rule, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := s.checkCategories(ctx, userID, payload.Conditions, payload.Actions); err != nil {
+		logger.Error().Err(err).Msg("rule category validation failed")
+		return nil, err
+	}
+
+	ruleItem, err := s.ruleRepo.UpdateRule(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to update rule")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "rule_updated").
+		Str("rule_id", ruleItem.ID.String()).
+		Bool("enabled", ruleItem.Enabled).
+		Msg("Rule updated successfully")
+
+	return ruleItem, nil
+}
+
+func (s *RuleService) DeleteRule(ctx echo.Context, userID string, ruleID uuid.UUID) error {
+	logger := middleware.GetLogger(ctx)
+
+	deletedRule, err := s.ruleRepo.DeleteRule(ctx.Request().Context(), userID, ruleID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to delete rule")
+		return err
+	}
+
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionRuleDeleted,
+		EntityType: "rule",
+		EntityID:   ruleID.String(),
+		Before:     deletedRule,
+	})
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "rule_deleted").
+		Str("rule_id", ruleID.String()).
+		Msg("Rule deleted successfully")
+
+	return nil
+}
+
+// GetExecutions returns the latest entries of the execution log of a rule, newest first
+func (s *RuleService) GetExecutions(ctx echo.Context, userID string, query *rule.GetExecutionsQuery,
+) ([]rule.Execution, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if _, err := s.ruleRepo.GetRule(ctx.Request().Context(), userID, query.ID); err != nil {
+		logger.Error().Err(err).Msg("failed to fetch rule")
+		return nil, err
+	}
+
+	executions, err := s.ruleRepo.GetExecutions(ctx.Request().Context(), userID, query.ID, *query.Limit)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch rule executions")
+		return nil, err
+	}
+
+	return executions, nil
+}
+
+// checkCategories makes sure the categories a rule refers to belong to the user
+func (s *RuleService) checkCategories(ctx echo.Context, userID string, conditions *rule.Conditions,
+	actions []rule.Action,
+) error {
+	var categoryIDs []uuid.UUID
+	if conditions != nil && conditions.CategoryID != nil {
+		categoryIDs = append(categoryIDs, *conditions.CategoryID)
+	}
+	for _, action := range actions {
+		if action.CategoryID != nil && !slices.Contains(categoryIDs, *action.CategoryID) {
+			categoryIDs = append(categoryIDs, *action.CategoryID)
+		}
+	}
+
+	for _, categoryID := range categoryIDs {
+		if _, err := s.categoryRepo.GetCategoryByID(ctx.Request().Context(), userID, categoryID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EvaluateRules runs the enabled rules of the user listening to the event against its todo,
+// in the order they were created, and logs every run. A rule that led to the event, or one
+// past the depth limit, is logged as skipped instead. Failing actions are logged, not retried,
+// since the actions before them already went through.
+func (s *RuleService) EvaluateRules(ctx context.Context, event *rule.Event) error {
+	return s.evaluate(ctx, event, nil)
+}
+
+// EvaluateBatch evaluates the events of a bulk change one after the other and sends what their
+// notify actions have to say as one digest. An event that fails to evaluate is logged and left
+// out, retrying the batch would run the rules of the others again.
+func (s *RuleService) EvaluateBatch(ctx context.Context, userID string, events []rule.Event) error {
+	log := logger.WithSpanContext(*s.server.Logger, ctx).With().
+		Str("user_id", userID).
+		Logger()
+
+	notices := []rule.Notice{}
+	for i := range events {
+		if err := s.evaluate(ctx, &events[i], &notices); err != nil {
+			log.Error().Err(err).Str("todo_id", events[i].TodoID.String()).Msg("failed to evaluate rules")
+		}
+	}
+	if len(notices) == 0 {
+		return nil
+	}
+
+	if err := job.EnqueueRuleDigestEmail(ctx, s.server.Job.Client, &job.RuleDigestEmailTask{
+		UserID:  userID,
+		Notices: notices,
+	}); err != nil {
+		log.Error().Err(err).Int("notice_count", len(notices)).Msg("failed to enqueue rule digest email")
+	}
+
+	return nil
+}
+
+// evaluate runs the rules listening to the event. The notices of notify actions are collected
+// into notices when given, and sent on their own otherwise.
+func (s *RuleService) evaluate(ctx context.Context, event *rule.Event, notices *[]rule.Notice) error {
+	log := logger.WithSpanContext(*s.server.Logger, ctx).With().
+		Str("user_id", event.UserID).
+		Str("trigger", string(event.Trigger)).
+		Str("todo_id", event.TodoID.String()).
+		Int("depth", event.Depth).
+		Logger()
+
+	rules, err := s.ruleRepo.GetEnabledRules(ctx, event.UserID, event.Trigger)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	todoItem, err := s.todoRepo.CheckTodoExists(ctx, event.UserID, event.TodoID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Deleted before its rules got to run
+			return nil
+		}
+		return err
+	}
+
+	for _, ruleItem := range rules {
+		if !ruleItem.Conditions.Match(todoItem, event) {
+			continue
+		}
+
+		// Overdue todos are found again on every pass of the cron job, a rule runs once per todo
+		if event.Trigger == rule.TriggerTodoOverdue {
+			ran, err := s.ruleRepo.HasExecution(ctx, ruleItem.ID, todoItem.ID, event.Trigger)
+			if err != nil {
+				return err
+			}
+			if ran {
+				continue
+			}
+		}
+
+		execution := rule.NewExecution(&ruleItem, event)
+
+		switch {
+		case event.Undone(todoItem):
+			execution.Status = rule.ExecutionSkipped
+			reason := "The completion was undone before the rule ran"
+			execution.Error = &reason
+		case event.InChain(ruleItem.ID):
+			execution.Status = rule.ExecutionSkipped
+			reason := "The rule led to this change itself, running it again would loop"
+			execution.Error = &reason
+		case event.Depth >= rule.MaxDepth:
+			execution.Status = rule.ExecutionSkipped
+			reason := fmt.Sprintf("Already %d rules ran one after the other off this change", event.Depth)
+			execution.Error = &reason
+		default:
+			updated, err := s.runActions(ctx, log, &ruleItem, todoItem, event, execution, notices)
+			if err != nil {
+				log.Warn().Err(err).Str("rule_id", ruleItem.ID.String()).Msg("rule action failed")
+				execution.Status = rule.ExecutionFailed
+				reason := err.Error()
+				execution.Error = &reason
+			}
+			// Later rules see the changes of the earlier ones
+			todoItem = updated
+		}
+
+		if err := s.ruleRepo.RecordExecution(ctx, execution); err != nil {
+			log.Error().Err(err).Str("rule_id", ruleItem.ID.String()).Msg("failed to record rule execution")
+		}
+
+		log.Info().
+			Str("event", "rule_executed").
+			Str("rule_id", ruleItem.ID.String()).
+			Str("status", string(execution.Status)).
+			Int("actions_applied", len(execution.ActionsApplied)).
+			Msg("Rule evaluated")
+	}
+
+	return nil
+}
+
+// runActions applies the actions of the rule in order, stopping at the first that fails. It
+// returns the todo as the applied actions left it.
+func (s *RuleService) runActions(ctx context.Context, log zerolog.Logger, ruleItem *rule.Rule, todoItem *todo.Todo,
+	event *rule.Event, execution *rule.Execution, notices *[]rule.Notice,
+) (*todo.Todo, error) {
+	for i, action := range ruleItem.Actions {
+		previous := todoItem
+
+		var err error
+		switch action.Type {
+		case rule.ActionSetPriority:
+			todoItem, err = s.todoRepo.UpdateTodo(ctx, event.UserID, &todo.UpdateTodoPayload{
+				ID:       todoItem.ID,
+				Priority: action.Priority,
+			})
+
+		case rule.ActionMoveCategory:
+			if _, err = s.categoryRepo.GetCategoryByID(ctx, event.UserID, *action.CategoryID); err == nil {
+				todoItem, err = s.todoRepo.UpdateTodo(ctx, event.UserID, &todo.UpdateTodoPayload{
+					ID:         todoItem.ID,
+					CategoryID: action.CategoryID,
+				})
+			}
+
+		case rule.ActionAddTag:
+			if rule.HasTag(todoItem, *action.Tag) {
+				break
+			}
+
+			metadata := todo.Metadata{}
+			if todoItem.Metadata != nil {
+				metadata = *todoItem.Metadata
+			}
+			metadata.Tags = append(slices.Clone(metadata.Tags), *action.Tag)
+
+			// The metadata is replaced as a whole, the version keeps concurrent edits from being lost
+			todoItem, err = s.todoRepo.UpdateTodo(ctx, event.UserID, &todo.UpdateTodoPayload{
+				ID:       todoItem.ID,
+				Metadata: &metadata,
+				Version:  &todoItem.Version,
+			})
+			if err == nil {
+				s.raise(ctx, log, event.Next(ruleItem.ID, rule.TriggerTodoTagged, todoItem.ID, action.Tag))
+			}
+
+		case rule.ActionNotify:
+			if notices != nil {
+				*notices = append(*notices, rule.Notice{
+					RuleName:  ruleItem.Name,
+					TodoID:    todoItem.ID,
+					TodoTitle: todoItem.Title,
+					Message:   action.Message,
+				})
+				break
+			}
+			err = job.EnqueueRuleNotificationEmail(ctx, s.server.Job.Client, &job.RuleNotificationEmailTask{
+				UserID:    event.UserID,
+				RuleName:  ruleItem.Name,
+				TodoID:    todoItem.ID,
+				TodoTitle: todoItem.Title,
+				Message:   action.Message,
+			})
+
+		case rule.ActionCreateFollowUp:
+			workspaceID := ""
+			if todoItem.WorkspaceID != nil {
+				workspaceID = *todoItem.WorkspaceID
+			}
+
+			var followUp *todo.Todo
+			followUp, err = s.todoRepo.CreateTodo(ctx, event.UserID, workspaceID, &todo.CreateTodoPayload{
+				Title:      *action.Title,
+				Priority:   action.Priority,
+				DueDate:    action.FollowUpDueDate(time.Now()),
+				CategoryID: todoItem.CategoryID,
+			})
+			if err == nil {
+				s.raise(ctx, log, event.Next(ruleItem.ID, rule.TriggerTodoCreated, followUp.ID, nil))
+			}
+		}
+
+		if err != nil {
+			return previous, fmt.Errorf("action %d (%s) failed: %w", i+1, action.Type, err)
+		}
+		execution.ActionsApplied = append(execution.ActionsApplied, action.Type)
+	}
+
+	return todoItem, nil
+}
+
+// raise queues the evaluation of a change a rule made
+func (s *RuleService) raise(ctx context.Context, log zerolog.Logger, event *rule.Event) {
+	if err := job.EnqueueRuleEvaluation(ctx, s.server.Job.Client, &job.RuleEvaluationTask{Event: *event}); err != nil {
+		log.Error().Err(err).Str("trigger", string(event.Trigger)).Msg("failed to enqueue rule evaluation")
+	}
+}
+
+// evaluateRules queues the evaluation of the user's rules against the changes they trigger on.
+// Todos completed together are evaluated together, so what the rules notify about arrives as
+// one digest. Completions are evaluated once their undo window passed, so undoing one takes
+// back its notifications and follow-ups before they happen.
+func evaluateRules(s *server.Server) events.Handler {
+	return func(ctx context.Context, event events.Event) error {
+		var triggered *rule.Event
+		var delay time.Duration
+		switch e := event.(type) {
+		case *events.TodoCreated:
+			triggered = &rule.Event{UserID: e.UserID, Trigger: rule.TriggerTodoCreated, TodoID: e.Todo.ID}
+		case *events.TodoCompleted:
+			triggered = &rule.Event{
+				UserID:      e.UserID,
+				Trigger:     rule.TriggerTodoCompleted,
+				TodoID:      e.Todo.ID,
+				CompletedAt: e.Todo.CompletedAt,
+			}
+			delay = s.Config.Todos.UndoWindow
+		case *events.TodoTagged:
+			triggered = &rule.Event{UserID: e.UserID, Trigger: rule.TriggerTodoTagged, TodoID: e.TodoID, Tag: &e.Tag}
+		case *events.TodosCompleted:
+			if len(e.Todos) == 0 {
+				return nil
+			}
+			batch := make([]rule.Event, 0, len(e.Todos))
+			for _, completed := range e.Todos {
+				batch = append(batch, rule.Event{
+					UserID:      e.UserID,
+					Trigger:     rule.TriggerTodoCompleted,
+					TodoID:      completed.ID,
+					CompletedAt: completed.CompletedAt,
+				})
+			}
+			if err := job.EnqueueRuleBatchEvaluation(ctx, s.Job.Client, &job.RuleBatchEvaluationTask{
+				UserID: e.UserID,
+				Events: batch,
+				Delay:  s.Config.Todos.UndoWindow,
+			}); err != nil {
+				return fmt.Errorf("failed to enqueue rule batch evaluation of %d events: %w", len(batch), err)
+			}
+			return nil
+		default:
+			return nil
+		}
+
+		if err := job.EnqueueRuleEvaluation(ctx, s.Job.Client, &job.RuleEvaluationTask{
+			Event: *triggered,
+			Delay: delay,
+		}); err != nil {
+			return fmt.Errorf("failed to enqueue rule evaluation of todo_id=%s: %w", triggered.TodoID, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/aws"
+	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/lib/scan"
+	"github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// AttachmentScanService scans uploads for viruses. Attachments are quarantined until their scan
+// clears them, infected ones are deleted from storage and their uploader notified.
+type AttachmentScanService struct {
+	server        *server.Server
+	todoRepo      repository.TodoStore
+	awsClient     *aws.AWS
+	audit         *AuditService
+	transcription *TranscriptionService
+	// scanner is nil when scanning is disabled
+	scanner scan.Scanner
+}
+
+func NewAttachmentScanService(server *server.Server, todoRepo repository.TodoStore, awsClient *aws.AWS,
+	auditService *AuditService, transcriptionService *TranscriptionService, scanner scan.Scanner,
+) *AttachmentScanService {
+	return &AttachmentScanService{
+		server:        server,
+		todoRepo:      todoRepo,
+		awsClient:     awsClient,
+		audit:         auditService,
+		transcription: transcriptionService,
+		scanner:       scanner,
+	}
+}
+
+// QueueScan enqueues the scan of an attachment stored as pending. When that fails the attachment
+// is marked as failed, it stays quarantined either way.
+func (s *AttachmentScanService) QueueScan(ctx echo.Context, userID string,
+	attachment *todo.TodoAttachment,
+) *todo.TodoAttachment {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	if attachment.ScanStatus == nil || *attachment.ScanStatus != todo.ScanPending {
+		return attachment
+	}
+
+	err := job.EnqueueAttachmentScan(reqCtx, s.server.Job.Client, &job.AttachmentScanTask{
+		UserID:       userID,
+		TodoID:       attachment.TodoID,
+		AttachmentID: attachment.ID,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to enqueue attachment scan")
+		if failed, failErr := s.todoRepo.SetAttachmentScan(reqCtx, attachment.TodoID, attachment.ID,
+			todo.ScanFailed, nil); failErr == nil {
+			return failed
+		}
+		return attachment
+	}
+
+	return attachment
+}
+
+// ScanAttachment scans a pending attachment. A clean one is released to the processing and
+// transcription that waited for it, an infected one is deleted from storage.
+func (s *AttachmentScanService) ScanAttachment(ctx context.Context, userID string,
+	todoID, attachmentID uuid.UUID,
+) error {
+	log := logger.WithSpanContext(*s.server.Logger, ctx).With().
+		Str("user_id", userID).
+		Str("attachment_id", attachmentID.String()).
+		Logger()
+
+	if s.scanner == nil {
+		return errors.New("attachment scanning is not enabled on this server")
+	}
+
+	attachment, err := s.todoRepo.GetTodoAttachment(ctx, todoID, attachmentID)
+	if err != nil {
+		if isAttachmentNotFound(err) {
+			// Deleted before it got scanned
+			return nil
+		}
+		return err
+	}
+	if attachment.ScanStatus == nil || *attachment.ScanStatus != todo.ScanPending {
+		return nil
+	}
+
+	body, err := s.awsClient.Blobs.GetObject(ctx, s.server.Config.AWS.S3Bucket, attachment.DownloadKey)
+	if err != nil {
+		return err
+	}
+	verdict, err := s.scanner.Scan(ctx, body)
+	body.Close()
+	if err != nil {
+		return err
+	}
+
+	if verdict.Infected {
+		return s.block(ctx, log, userID, attachment, verdict.Signature)
+	}
+
+	cleared, err := s.todoRepo.SetAttachmentScan(ctx, todoID, attachmentID, todo.ScanClean, nil)
+	if err != nil {
+		if isAttachmentNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if cleared.IsProcessable() {
+		err := job.EnqueueAttachmentProcessing(ctx, s.server.Job.Client, &job.AttachmentProcessingTask{
+			TodoID:       todoID,
+			AttachmentID: attachmentID,
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("failed to enqueue attachment processing")
+		}
+	}
+	s.transcription.QueueScannedTranscription(ctx, userID, cleared)
+
+	log.Info().
+		Str("event", "attachment_scanned").
+		Str("scanner", s.scanner.Name()).
+		Msg("Attachment scanned clean")
+
+	return nil
+}
+
+// block deletes an infected file and records why, the attachment record stays so the uploader
+// sees what happened to their upload
+func (s *AttachmentScanService) block(ctx context.Context, log zerolog.Logger, userID string,
+	attachment *todo.TodoAttachment, signature string,
+) error {
+	if err := s.awsClient.Blobs.DeleteObject(ctx, s.server.Config.AWS.S3Bucket, attachment.DownloadKey); err != nil {
+		// Retried, the attachment stays quarantined meanwhile
+		return err
+	}
+
+	blocked, err := s.todoRepo.SetAttachmentScan(ctx, attachment.TodoID, attachment.ID, todo.ScanInfected, &signature)
+	if err != nil {
+		if isAttachmentNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	s.audit.RecordBackground(ctx, userID, &audit.Event{
+		Action:     audit.ActionAttachmentBlocked,
+		EntityType: "attachment",
+		EntityID:   attachment.ID.String(),
+		Before:     attachment,
+		After:      blocked,
+	})
+
+	todoTitle := ""
+	if todoItem, err := s.todoRepo.CheckTodoExists(ctx, userID, attachment.TodoID); err == nil {
+		todoTitle = todoItem.Title
+	}
+	err = job.EnqueueAttachmentBlockedEmail(ctx, s.server.Job.Client, &job.AttachmentBlockedEmailTask{
+		UserID:    userID,
+		TodoID:    attachment.TodoID,
+		TodoTitle: todoTitle,
+		FileName:  attachment.Name,
+		Signature: signature,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to enqueue attachment blocked email")
+	}
+
+	log.Warn().
+		Str("event", "attachment_blocked").
+		Str("scanner", s.scanner.Name()).
+		Str("signature", signature).
+		Msg("Infected attachment blocked")
+
+	return nil
+}
+
+func (s *AttachmentScanService) FailScan(ctx context.Context, todoID, attachmentID uuid.UUID) error {
+	_, err := s.todoRepo.SetAttachmentScan(ctx, todoID, attachmentID, todo.ScanFailed, nil)
+	if isAttachmentNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// initialScanStatus is the scan status uploads are stored with, pending while scanning is enabled
+func initialScanStatus(cfg *config.ScanningConfig) *todo.ScanStatus {
+	if cfg == nil || cfg.Provider == "" {
+		return nil
+	}
+	status := todo.ScanPending
+	return &status
+}
+
+// errAttachmentUnavailable tells why an attachment can't be downloaded, nil when it can
+func errAttachmentUnavailable(attachment *todo.TodoAttachment) error {
+	if attachment.IsAvailable() {
+		return nil
+	}
+	if *attachment.ScanStatus == todo.ScanInfected {
+		code := errs.CodeAttachmentInfected
+		return errs.NewGoneError("the attachment was blocked by the virus scan", false, &code)
+	}
+	code := errs.CodeAttachmentQuarantined
+	return errs.NewConflictError("the attachment is quarantined until it is scanned for viruses", false, &code)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/analytics"
+	"github.com/Sameer16536/ExecuTask/internal/lib/embedding"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/search"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+type SearchService struct {
+	server     *server.Server
+	searchRepo repository.SearchStore
+	// embedder is nil when semantic search is disabled
+	embedder     embedding.Provider
+	featureFlags *FeatureFlagService
+}
+
+func NewSearchService(server *server.Server, searchRepo repository.SearchStore,
+	featureFlags *FeatureFlagService,
+) *SearchService {
+	return &SearchService{
+		server:       server,
+		searchRepo:   searchRepo,
+		embedder:     embedding.NewProvider(server.Config.Embedding, server.Config.Outbound),
+		featureFlags: featureFlags,
+	}
+}
+
+func (s *SearchService) Search(ctx echo.Context, userID string, query *search.SearchQuery) (*search.Results, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	var results []search.Result
+	var err error
+
+	switch *query.Mode {
+	case search.ModeSemantic:
+		if s.embedder == nil {
+			code := errs.CodeSemanticSearchDisabled
+			return nil, errs.NewServiceUnavailableError("semantic search is not enabled on this server", false, &code)
+		}
+		if !s.featureFlags.IsEnabled(ctx, config.FeatureSemanticSearch) {
+			code := errs.CodeSemanticSearchDisabled
+			return nil, errs.NewBadRequestError("semantic search is not enabled for this account", false, &code, nil, nil)
+		}
+
+		var vectors [][]float32
+		embedErr := s.server.Breakers.Get("embedding").Do(reqCtx, func(reqCtx context.Context) error {
+			var err error
+			vectors, err = s.embedder.Embed(reqCtx, []string{query.Q})
+			return err
+		})
+		if embedErr != nil {
+			logger.Error().Err(embedErr).Msg("failed to embed search query")
+			return nil, embedErr
+		}
+
+		minSimilarity := config.DefaultEmbeddingConfig().MinSimilarity
+		if s.server.Config.Embedding != nil && s.server.Config.Embedding.MinSimilarity > 0 {
+			minSimilarity = s.server.Config.Embedding.MinSimilarity
+		}
+
+		results, err = s.searchRepo.SearchTodosByEmbedding(reqCtx, userID, s.embedder.Model(), vectors[0], minSimilarity, *query.Limit)
+	default:
+		results, err = s.searchRepo.SearchTodos(reqCtx, userID, query.Q, *query.Limit)
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("mode", string(*query.Mode)).Msg("failed to search todos")
+		return nil, err
+	}
+
+	trackEvent(ctx, s.server, userID, analytics.EventSearchPerformed, analytics.Properties{
+		"mode":         string(*query.Mode),
+		"result_count": len(results),
+	})
+
+	return &search.Results{
+		Mode: *query.Mode,
+		Data: results,
+	}, nil
+}
+
+// Suggest returns the quick results of a typeahead: the user's todos, categories and tags
+// matching what they typed so far, those they use often and lately first. It is called on
+// every keystroke, so unlike Search it isn't tracked.
+func (s *SearchService) Suggest(ctx echo.Context, userID string, query *search.SuggestQuery) (*search.Suggestions, error) {
+	logger := middleware.GetLogger(ctx)
+
+	candidates, err := s.searchRepo.SuggestCandidates(ctx.Request().Context(), userID, query.Q, *query.Limit)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch suggestions")
+		return nil, err
+	}
+
+	now := time.Now()
+	suggestions := make([]search.Suggestion, 0, len(candidates))
+	for _, candidate := range candidates {
+		suggestions = append(suggestions, search.Suggestion{
+			Type:  candidate.Type,
+			ID:    candidate.ID,
+			Label: candidate.Label,
+			Score: candidate.Score(now),
+		})
+	}
+	slices.SortStableFunc(suggestions, func(a, b search.Suggestion) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(len(a.Label), len(b.Label)))
+	})
+
+	return &search.Suggestions{
+		Data: suggestions[:min(*query.Limit, len(suggestions))],
+	}, nil
+}
@@ -3,18 +3,58 @@ package service
 import (
 	"fmt"
 
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/lib/analytics"
 	"github.com/Sameer16536/ExecuTask/internal/lib/aws"
+	"github.com/Sameer16536/ExecuTask/internal/lib/blob"
 	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/lib/scan"
+	"github.com/Sameer16536/ExecuTask/internal/lib/transcribe"
 	"github.com/Sameer16536/ExecuTask/internal/repository"
 	"github.com/Sameer16536/ExecuTask/internal/server"
 )
 
 type Services struct {
-	Auth     *AuthService
-	Job      *job.JobService
-	Category *CategoryService
-	Comment  *CommentService
-	Todo     *TodoService
+	Auth          *AuthService
+	Job           *job.JobService
+	Category      *CategoryService
+	Comment       *CommentService
+	Todo          *TodoService
+	Sync          *SyncService
+	Change        *ChangeService
+	Admin         *AdminService
+	Stats         *StatsService
+	Search        *SearchService
+	Audit         *AuditService
+	Health        *HealthService
+	Features      *FeatureFlagService
+	Usage         *UsageService
+	Export        *ExportService
+	Retention     *RetentionService
+	Backup        *BackupService
+	Rule          *RuleService
+	Suggestion    *SuggestionService
+	Reminder      *ReminderService
+	Transcription *TranscriptionService
+	Scan          *AttachmentScanService
+	Planner       *PlannerService
+	Report        *ReportService
+	Matrix        *MatrixService
+	Focus         *FocusService
+	Gamification  *GamificationService
+	Dependency    *DependencyService
+	Inbox         *InboxService
+	Workspace     *WorkspaceService
+	Cover         *CoverService
+	Note          *NoteService
+	MagicTag      *MagicTagService
+	Locale        *LocaleService
+	StatusPage    *StatusPageService
+	APIKey        *APIKeyService
+	APIClient     *APIClientService
+	Session       *SessionService
+	Webhook       *WebhookService
+	Blobs         blob.Store
 }
 
 func NewServices(s *server.Server, repos *repository.Repositories) (*Services, error) {
@@ -27,11 +67,119 @@ func NewServices(s *server.Server, repos *repository.Repositories) (*Services, e
 		return nil, fmt.Errorf("failed to create AWS client: %w", err)
 	}
 
+	if analyticsConfig := s.Config.Analytics; analyticsConfig.Enabled {
+		s.Analytics = newAnalyticsEmitter(s, awsClient, analyticsConfig)
+		s.Analytics.Start(analyticsConfig.FlushInterval)
+	}
+
+	if alertsConfig := s.Config.Alerts; alertsConfig.Enabled {
+		s.Alerts = newAlertMonitor(s, alertsConfig)
+		s.Alerts.Start(alertsConfig.Interval)
+	}
+
+	auditService := NewAuditService(s, repos.Audit, repos.Tx)
+	featureFlagService := NewFeatureFlagService(s, repos.Feature, auditService)
+	previewService := NewPreviewService(s, repos.LinkPreview)
+	coverService := NewCoverService(s, repos.Cover, repos.Todo, awsClient)
+	todoService := NewTodoService(s, repos.Todo, repos.Category, repos.Comment, repos.MagicTag, repos.Dependency,
+		repos.Workspace, awsClient, auditService, repos.Tx, previewService, coverService)
+	exportService := NewExportService(s, repos.Export, repos.Todo, awsClient, auditService)
+	reportService := NewReportService(s, repos.Report, repos.Category, exportService)
+
+	backupService := NewBackupService(s, repos.Backup, repos.Tx, awsClient, auditService)
+	ruleService := NewRuleService(s, repos.Rule, repos.Todo, repos.Category, auditService)
+	gamificationService := NewGamificationService(s, repos.Gamification, repos.Stats)
+	categoryService := NewCategoryService(s, repos.Category, auditService, repos.Tx)
+	commentService := NewCommentService(s, repos.Comment, repos.Todo, auditService, authService, previewService)
+	workspaceService := NewWorkspaceService(s, repos.Workspace, repos.Todo, todoService, categoryService,
+		auditService, authService, backupService, repos.Tx)
+	transcriptionService := NewTranscriptionService(s, repos.Todo, repos.Tx, newTranscriptionProvider(s))
+	localeService := NewLocaleService(s, repos.Locale)
+	scanService := NewAttachmentScanService(s, repos.Todo, awsClient, auditService, transcriptionService,
+		scan.NewScanner(s.Config.Scanning, s.Config.Outbound))
+
+	webhookService := NewWebhookService(s, repos.Webhook, auditService)
+
+	subscribeEvents(s, previewService, webhookService)
+
+	s.Job.SetAccountExporter(exportService)
+	s.Job.SetWorkspaceBackups(backupService)
+	s.Job.SetRuleEvaluator(ruleService)
+	s.Job.SetBadgeEvaluator(gamificationService)
+	s.Job.SetTranscriber(transcriptionService)
+	s.Job.SetCommentReplies(commentService)
+	s.Job.SetLinkUnfurler(previewService)
+	s.Job.SetAgingPolicyEnforcer(workspaceService)
+	s.Job.SetWorkspacePurger(workspaceService)
+	s.Job.SetCoverRenderer(coverService)
+	s.Job.SetAttachmentProcessor(NewAttachmentProcessingService(s, repos.Todo, awsClient))
+	s.Job.SetAttachmentScanner(scanService)
+	s.Job.SetLocaleResolver(localeService)
+	s.Job.SetReportRunner(reportService)
+	s.Job.SetWebhookDeliverer(webhookService)
+
 	return &Services{
-		Job:      s.Job,
-		Auth:     authService,
-		Category: NewCategoryService(s, repos.Category),
-		Comment:  NewCommentService(s, repos.Comment, repos.Todo),
-		Todo:     NewTodoService(s, repos.Todo, repos.Category, awsClient),
+		Job:           s.Job,
+		Auth:          authService,
+		Category:      categoryService,
+		Comment:       commentService,
+		Todo:          todoService,
+		Sync:          NewSyncService(s, todoService, repos.Todo, repos.Tx),
+		Change:        NewChangeService(s, repos.Change),
+		Admin:         NewAdminService(s, repos.Admin, repos.Todo, repos.Workspace, auditService),
+		Stats:         NewStatsService(s, repos.Stats),
+		Search:        NewSearchService(s, repos.Search, featureFlagService),
+		Audit:         auditService,
+		Health:        NewHealthService(s, awsClient.Blobs, repos.StatusPage),
+		Features:      featureFlagService,
+		Usage:         NewUsageService(s, repos.Usage),
+		Export:        exportService,
+		Retention:     NewRetentionService(s, repos.Retention, auditService),
+		Backup:        backupService,
+		Rule:          ruleService,
+		Suggestion:    NewSuggestionService(s, repos.Todo, featureFlagService),
+		Reminder:      NewReminderService(s, repos.Todo, featureFlagService),
+		Transcription: transcriptionService,
+		Planner:       NewPlannerService(s, repos.Todo, repos.Focus, repos.MyDay, repos.Tx),
+		Report:        reportService,
+		Matrix:        NewMatrixService(s, repos.Matrix, repos.Todo, repos.Workspace),
+		Focus:         NewFocusService(s, repos.Focus, repos.Todo),
+		Gamification:  gamificationService,
+		Dependency:    NewDependencyService(s, repos.Dependency, repos.Todo, repos.Tx),
+		Inbox:         NewInboxService(s, repos.Inbox, todoService, repos.Tx),
+		Workspace:     workspaceService,
+		Cover:         coverService,
+		Note:          NewNoteService(s, repos.Note, repos.Todo),
+		MagicTag:      NewMagicTagService(s, repos.MagicTag, repos.Category),
+		Locale:        localeService,
+		StatusPage:    NewStatusPageService(s, repos.StatusPage, auditService),
+		APIKey:        NewAPIKeyService(s, repos.APIKey, auditService),
+		APIClient:     NewAPIClientService(s, repos.APIClient, auditService),
+		Session:       NewSessionService(s),
+		Webhook:       webhookService,
+		Scan:          scanService,
+		Blobs:         awsClient.Blobs,
 	}, nil
 }
+
+func newAnalyticsEmitter(s *server.Server, awsClient *aws.AWS, cfg *config.AnalyticsConfig) *analytics.Emitter {
+	var sink analytics.Sink
+	switch cfg.Sink {
+	case config.AnalyticsSinkKinesis:
+		sink = aws.NewKinesisSink(s, cfg.KinesisStream)
+	default:
+		sink = analytics.NewS3Sink(awsClient.Blobs, s.Config.AWS.S3Bucket, cfg.S3Prefix)
+	}
+
+	return analytics.NewEmitter(sink, cfg.Salt, s.Config.Primary.Env, cfg.BatchSize, s.Logger)
+}
+
+// newTranscriptionProvider returns the configured provider, nil when transcription is disabled
+func newTranscriptionProvider(s *server.Server) transcribe.Provider {
+	switch cfg := s.Config.Transcription; cfg.Provider {
+	case config.TranscriptionProviderAWS:
+		return aws.NewTranscribeProvider(s, cfg)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/session"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/labstack/echo/v4"
+)
+
+// csrfTokenBytes is the randomness of a CSRF token
+const csrfTokenBytes = 32
+
+// SessionService hands out the cookies of cookie sessions. Nothing is stored, the session cookie
+// holds the Clerk session token and is checked by Clerk on every request like the header is.
+type SessionService struct {
+	server *server.Server
+}
+
+func NewSessionService(server *server.Server) *SessionService {
+	return &SessionService{server: server}
+}
+
+// CreateSession moves the session token of the request into an HTTP-only cookie expiring with
+// the token. Browsers call it again with every refreshed token, the CSRF token is kept.
+func (s *SessionService) CreateSession(ctx echo.Context, _ *session.CreateSessionPayload) (*session.CookieSession, error) {
+	logger := middleware.GetLogger(ctx)
+
+	cfg, err := s.checkEnabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := clerk.SessionClaimsFromContext(ctx.Request().Context())
+	if !ok || claims.Expiry == nil {
+		return nil, errs.NewUnauthorizedError("Unauthorized", false)
+	}
+	expiresAt := time.Unix(*claims.Expiry, 0).UTC()
+	token := strings.TrimPrefix(ctx.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+
+	csrfToken, err := s.csrfToken(ctx, cfg)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to generate CSRF token")
+		return nil, err
+	}
+
+	sessionCookie := s.cookie(cfg, cfg.Name, token)
+	sessionCookie.Expires = expiresAt
+	sessionCookie.HttpOnly = true
+	ctx.SetCookie(sessionCookie)
+
+	// Business event log
+	logger.Info().
+		Str("event", "cookie_session_created").
+		Str("session_id", middleware.GetSessionID(ctx)).
+		Time("expires_at", expiresAt).
+		Msg("Cookie session created successfully")
+
+	return &session.CookieSession{ExpiresAt: expiresAt, CSRFToken: csrfToken}, nil
+}
+
+// DeleteSession clears the cookies, the Clerk session itself is signed out by the client
+func (s *SessionService) DeleteSession(ctx echo.Context, _ *session.DeleteSessionPayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	cfg, err := s.checkEnabled(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range []string{cfg.Name, cfg.CSRFName} {
+		cookie := s.cookie(cfg, name, "")
+		cookie.MaxAge = -1
+		cookie.HttpOnly = name == cfg.Name
+		ctx.SetCookie(cookie)
+	}
+
+	// Business event log
+	logger.Info().
+		Str("event", "cookie_session_deleted").
+		Str("session_id", middleware.GetSessionID(ctx)).
+		Msg("Cookie session deleted successfully")
+
+	return nil
+}
+
+// GetCSRFToken returns the CSRF token of the browser, issuing one if it has none
+func (s *SessionService) GetCSRFToken(ctx echo.Context, _ *session.GetCSRFTokenPayload) (*session.CSRFToken, error) {
+	cfg, err := s.checkEnabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.csrfToken(ctx, cfg)
+	if err != nil {
+		middleware.GetLogger(ctx).Error().Err(err).Msg("failed to generate CSRF token")
+		return nil, err
+	}
+
+	return &session.CSRFToken{Token: token}, nil
+}
+
+func (s *SessionService) checkEnabled(ctx echo.Context) (*config.SessionCookieConfig, error) {
+	cfg := s.server.Config.Auth.Cookie
+	if cfg == nil || !cfg.Enabled {
+		code := errs.CodeCookieSessionsDisabled
+		return nil, errs.NewNotFoundError("cookie sessions are not available", false, &code)
+	}
+	if middleware.GetAPIKeyID(ctx) != "" {
+		return nil, errs.NewForbiddenError("cookie sessions can only be used when signed in", false)
+	}
+	return cfg, nil
+}
+
+// csrfToken keeps the CSRF cookie of the browser, or sets a new one. It lives as long as the
+// browser session, scripts must be able to read it so it isn't HTTP-only.
+func (s *SessionService) csrfToken(ctx echo.Context, cfg *config.SessionCookieConfig) (string, error) {
+	if cookie, err := ctx.Cookie(cfg.CSRFName); err == nil && cookie.Value != "" {
+		return cookie.Value, nil
+	}
+
+	random := make([]byte, csrfTokenBytes)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate CSRF token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(random)
+
+	ctx.SetCookie(s.cookie(cfg, cfg.CSRFName, token))
+	return token, nil
+}
+
+func (s *SessionService) cookie(cfg *config.SessionCookieConfig, name, value string) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   cfg.Domain,
+		Secure:   !cfg.Insecure,
+		SameSite: http.SameSiteLaxMode,
+	}
+	switch cfg.SameSite {
+	case "strict":
+		cookie.SameSite = http.SameSiteStrictMode
+	case "none":
+		cookie.SameSite = http.SameSiteNoneMode
+	}
+	return cookie
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/stats"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+type StatsService struct {
+	server    *server.Server
+	statsRepo repository.StatsStore
+}
+
+func NewStatsService(server *server.Server, statsRepo repository.StatsStore) *StatsService {
+	return &StatsService{
+		server:    server,
+		statsRepo: statsRepo,
+	}
+}
+
+func (s *StatsService) GetOverview(ctx echo.Context, userID string, query *stats.GetOverviewQuery) (*stats.Overview, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	summary, err := s.statsRepo.GetSummary(reqCtx, userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch stats summary")
+		return nil, err
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(*query.Days - 1))
+
+	days, err := s.statsRepo.GetDailyCompletions(reqCtx, userID, since)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch daily completions")
+		return nil, err
+	}
+
+	categories, err := s.statsRepo.GetCategoryStats(reqCtx, userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch category stats")
+		return nil, err
+	}
+
+	overview := &stats.Overview{
+		CompletedPerDay: fillDays(days, since, *query.Days),
+		Categories:      categories,
+	}
+	if summary != nil {
+		overview.Summary = *summary
+	}
+
+	return overview, nil
+}
+
+func (s *StatsService) GetProductivity(ctx echo.Context, userID string,
+	query *stats.GetProductivityQuery,
+) (*stats.Productivity, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	now := time.Now().UTC()
+	today := now.Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(*query.Days - 1))
+
+	days, err := activeDays(reqCtx, s.statsRepo, userID, since)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch active days")
+		return nil, err
+	}
+
+	cells, err := s.statsRepo.GetCompletionHeatmap(reqCtx, userID, since)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch completion heatmap")
+		return nil, err
+	}
+
+	priorities, err := s.statsRepo.GetCompletionsByPriority(reqCtx, userID, since)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch completions by priority")
+		return nil, err
+	}
+
+	categories, err := s.statsRepo.GetCompletionsByCategory(reqCtx, userID, since)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch completions by category")
+		return nil, err
+	}
+
+	overdue, err := s.statsRepo.GetOverdueRatio(reqCtx, userID, since, now)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch overdue ratio")
+		return nil, err
+	}
+	if overdue.Due > 0 {
+		overdue.Ratio = float64(overdue.Overdue) / float64(overdue.Due)
+	}
+
+	focusTotals, err := s.statsRepo.GetFocusTotals(reqCtx, userID, since)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch focus totals")
+		return nil, err
+	}
+
+	return &stats.Productivity{
+		Days:         *query.Days,
+		Since:        since,
+		Streaks:      completionStreaks(days, today),
+		Heatmap:      fillHeatmap(cells),
+		ByPriority:   priorities,
+		ByCategory:   categories,
+		OverdueRatio: *overdue,
+		Focus:        *focusTotals,
+	}, nil
+}
+
+// activeDays returns the UTC days since the given date with a completed todo or focus session,
+// oldest first. A completed focus session keeps a streak going like a completed todo.
+func activeDays(ctx context.Context, statsRepo repository.StatsStore, userID string, since time.Time) ([]string, error) {
+	days, err := statsRepo.GetCompletionDays(ctx, userID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	focusDays, err := statsRepo.GetFocusDays(ctx, userID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	days = append(days, focusDays...)
+	slices.Sort(days)
+	return slices.Compact(days), nil
+}
+
+// completionStreaks walks the sorted completion days, the current streak may end today or yesterday
+func completionStreaks(days []string, today time.Time) stats.Streaks {
+	var streaks stats.Streaks
+	run := 0
+	var previous time.Time
+	for _, day := range days {
+		date, err := time.Parse(time.DateOnly, day)
+		if err != nil {
+			continue
+		}
+		if run > 0 && date.Equal(previous.AddDate(0, 0, 1)) {
+			run++
+		} else {
+			run = 1
+		}
+		previous = date
+		streaks.Longest = max(streaks.Longest, run)
+	}
+
+	if run > 0 && !previous.Before(today.AddDate(0, 0, -1)) {
+		streaks.Current = run
+	}
+
+	return streaks
+}
+
+// fillHeatmap returns all 7x24 weekday and hour slots, Sunday midnight first, empty slots count zero
+func fillHeatmap(cells []stats.HeatmapCell) []stats.HeatmapCell {
+	filled := make([]stats.HeatmapCell, 7*24)
+	for i := range filled {
+		filled[i] = stats.HeatmapCell{Weekday: i / 24, Hour: i % 24}
+	}
+	for _, cell := range cells {
+		filled[cell.Weekday*24+cell.Hour].Completed = cell.Completed
+	}
+
+	return filled
+}
+
+// fillDays returns one entry per day starting at since, days without completions count zero
+func fillDays(days []stats.DailyCompletions, since time.Time, count int) []stats.DailyCompletions {
+	completed := make(map[string]int, len(days))
+	for _, day := range days {
+		completed[day.Day] = day.Completed
+	}
+
+	filled := make([]stats.DailyCompletions, 0, count)
+	for i := 0; i < count; i++ {
+		day := since.AddDate(0, 0, i).Format(time.DateOnly)
+		filled = append(filled, stats.DailyCompletions{Day: day, Completed: completed[day]})
+	}
+
+	return filled
+}
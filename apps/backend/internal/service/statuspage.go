@@ -0,0 +1,206 @@
+package service
+
+import (
+	"slices"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/model/health"
+	"github.com/Sameer16536/ExecuTask/internal/model/statuspage"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// statusHistoryDays is how many days of checks the status page shows, today included
+	statusHistoryDays = 90
+	// statusIncidentDays is how long resolved incidents stay on the status page
+	statusIncidentDays = 14
+)
+
+type StatusPageService struct {
+	server         *server.Server
+	statusPageRepo repository.StatusPageStore
+	auditService   *AuditService
+}
+
+func NewStatusPageService(server *server.Server, statusPageRepo repository.StatusPageStore,
+	auditService *AuditService,
+) *StatusPageService {
+	return &StatusPageService{
+		server:         server,
+		statusPageRepo: statusPageRepo,
+		auditService:   auditService,
+	}
+}
+
+// GetPage builds the public status page from the deep health checks the instances recorded
+// and the incidents operators announced. An open incident makes its components at least as
+// bad as its impact, whatever the checks say.
+func (s *StatusPageService) GetPage(ctx echo.Context, _ *statuspage.GetPagePayload) (*statuspage.Page, error) {
+	logger := middleware.GetLogger(ctx)
+
+	now := time.Now().UTC()
+
+	history, err := s.statusPageRepo.GetHistory(ctx.Request().Context(), now.AddDate(0, 0, 1-statusHistoryDays))
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch status history")
+		return nil, err
+	}
+
+	incidents, err := s.statusPageRepo.GetIncidents(ctx.Request().Context(), now.AddDate(0, 0, -statusIncidentDays))
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch incidents")
+		return nil, err
+	}
+
+	page := &statuspage.Page{
+		Status:      health.StatusHealthy,
+		Components:  []statuspage.ComponentStatus{},
+		Incidents:   incidents,
+		HistoryDays: statusHistoryDays,
+	}
+
+	for _, component := range statuspage.Components {
+		summary := statuspage.ComponentStatus{
+			Component: component,
+			History:   []statuspage.Day{},
+		}
+
+		checks, passed := 0, 0
+		for _, day := range history {
+			if day.Component != component {
+				continue
+			}
+			summary.History = append(summary.History, day)
+			checks += day.Checks
+			passed += day.Checks - day.Failures
+
+			// The history is sorted by day, the latest check is on the last one
+			summary.Status = day.LastStatus
+			checkedAt := day.LastCheckedAt
+			summary.CheckedAt = &checkedAt
+		}
+		if checks > 0 {
+			uptime := float64(passed) / float64(checks)
+			summary.Uptime = &uptime
+		}
+
+		for _, incident := range incidents {
+			if incident.IsOpen() && slices.Contains(incident.Components, component) {
+				summary.Status = worseStatus(summary.Status, incident.Impact.Status())
+			}
+		}
+
+		// Components not running on this deployment are never checked, such as the job queue
+		// without a worker configured
+		if summary.Status == "" {
+			continue
+		}
+
+		page.Components = append(page.Components, summary)
+		page.Status = worseStatus(page.Status, summary.Status)
+	}
+
+	return page, nil
+}
+
+// GetIncidents lists the incidents for operators, including those resolved too long ago to be
+// on the status page
+func (s *StatusPageService) GetIncidents(ctx echo.Context, query *statuspage.GetIncidentsQuery) ([]statuspage.Incident, error) {
+	logger := middleware.GetLogger(ctx)
+
+	since := time.Now().AddDate(0, 0, -*query.Days)
+	incidents, err := s.statusPageRepo.GetIncidents(ctx.Request().Context(), since)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch incidents")
+		return nil, err
+	}
+
+	return incidents, nil
+}
+
+func (s *StatusPageService) CreateIncident(ctx echo.Context, userID string,
+	payload *statuspage.CreateIncidentPayload,
+) (*statuspage.Incident, error) {
+	logger := middleware.GetLogger(ctx)
+
+	incident, err := s.statusPageRepo.CreateIncident(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create incident")
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminIncident,
+		EntityType: "status_incident",
+		EntityID:   incident.ID.String(),
+		After:      incident,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "incident_created").
+		Str("incident_id", incident.ID.String()).
+		Str("impact", string(incident.Impact)).
+		Str("status", string(incident.Status)).
+		Msg("Incident created successfully")
+
+	return incident, nil
+}
+
+func (s *StatusPageService) UpdateIncident(ctx echo.Context,
+	payload *statuspage.UpdateIncidentPayload,
+) (*statuspage.Incident, error) {
+	logger := middleware.GetLogger(ctx)
+
+	incident, err := s.statusPageRepo.UpdateIncident(ctx.Request().Context(), payload)
+	if err != nil {
+		logger.Error().Err(err).Str("incident_id", payload.ID.String()).Msg("failed to update incident")
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminIncident,
+		EntityType: "status_incident",
+		EntityID:   incident.ID.String(),
+		After:      incident,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "incident_updated").
+		Str("incident_id", incident.ID.String()).
+		Str("status", string(incident.Status)).
+		Msg("Incident updated successfully")
+
+	return incident, nil
+}
+
+// DeleteIncident takes an incident off the status page, for one announced by mistake
+func (s *StatusPageService) DeleteIncident(ctx echo.Context, payload *statuspage.DeleteIncidentPayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	incident, err := s.statusPageRepo.DeleteIncident(ctx.Request().Context(), payload.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("incident_id", payload.ID.String()).Msg("failed to delete incident")
+		return err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminIncident,
+		EntityType: "status_incident",
+		EntityID:   incident.ID.String(),
+		Before:     incident,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "incident_deleted").
+		Str("incident_id", incident.ID.String()).
+		Msg("Incident deleted successfully")
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/lib/classify"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// suggestionHistory is how many of the latest todos of the user suggestions are learned from
+	suggestionHistory = 500
+	// suggestionMinHistory is how many earlier todos a user needs before anything is suggested
+	suggestionMinHistory = 5
+	// suggestionMinConfidence keeps guesses out of the suggestions
+	suggestionMinConfidence = 0.6
+	// suggestionMaxTags caps the suggested tags, the most used suggestionCandidateTags are tried
+	suggestionMaxTags       = 3
+	suggestionCandidateTags = 30
+)
+
+// noLabel stands for todos without the field, so leaving it unset can win over every value
+const noLabel = ""
+
+type SuggestionService struct {
+	server       *server.Server
+	todoRepo     repository.TodoStore
+	model        classify.Model
+	featureFlags *FeatureFlagService
+}
+
+func NewSuggestionService(server *server.Server, todoRepo repository.TodoStore,
+	featureFlags *FeatureFlagService,
+) *SuggestionService {
+	return &SuggestionService{
+		server:       server,
+		todoRepo:     todoRepo,
+		model:        classify.NewNaiveBayes(),
+		featureFlags: featureFlags,
+	}
+}
+
+// SuggestForTodo proposes a category, priority and tags for the fields the payload of a new todo
+// left unset. Suggestions are best effort: nil when nothing is confident enough or the history
+// can't be read, the todo is created either way.
+func (s *SuggestionService) SuggestForTodo(ctx echo.Context, userID string, payload *todo.CreateTodoPayload,
+	created *todo.Todo,
+) *todo.Suggestions {
+	logger := middleware.GetLogger(ctx)
+
+	wantCategory := payload.CategoryID == nil
+	wantPriority := payload.Priority == nil
+	wantTags := payload.Metadata == nil || len(payload.Metadata.Tags) == 0
+	if !wantCategory && !wantPriority && !wantTags {
+		return nil
+	}
+	if !s.featureFlags.IsEnabled(ctx, config.FeatureTodoSuggestions) {
+		return nil
+	}
+
+	recent, err := s.todoRepo.GetRecentTodos(ctx.Request().Context(), userID, suggestionHistory+1)
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to fetch todo history for suggestions")
+		return nil
+	}
+	history := slices.DeleteFunc(recent, func(item todo.Todo) bool {
+		return item.ID == created.ID
+	})
+	if len(history) < suggestionMinHistory {
+		return nil
+	}
+
+	text := created.Title + " " + created.Description
+	suggestions := &todo.Suggestions{Tags: []todo.TagSuggestion{}}
+
+	if wantCategory {
+		label, confidence := s.best(history, text, func(item todo.Todo) string {
+			if item.CategoryID == nil {
+				return noLabel
+			}
+			return item.CategoryID.String()
+		})
+		if categoryID, err := uuid.Parse(label); err == nil {
+			suggestions.Category = &todo.CategorySuggestion{CategoryID: categoryID, Confidence: confidence}
+		}
+	}
+
+	if wantPriority {
+		label, confidence := s.best(history, text, func(item todo.Todo) string {
+			return string(item.Priority)
+		})
+		// The default the todo was created with needs no suggestion
+		if label != noLabel && todo.Priority(label) != created.Priority {
+			suggestions.Priority = &todo.PrioritySuggestion{Priority: todo.Priority(label), Confidence: confidence}
+		}
+	}
+
+	if wantTags {
+		suggestions.Tags = s.suggestTags(history, text)
+	}
+
+	if suggestions.Category == nil && suggestions.Priority == nil && len(suggestions.Tags) == 0 {
+		return nil
+	}
+
+	return suggestions
+}
+
+// best returns the most likely label of the text, noLabel when it isn't confident enough
+func (s *SuggestionService) best(history []todo.Todo, text string, label func(item todo.Todo) string) (string, float64) {
+	examples := make([]classify.Example, 0, len(history))
+	for _, item := range history {
+		examples = append(examples, classify.Example{Text: item.Title + " " + item.Description, Label: label(item)})
+	}
+
+	predictions := s.model.Rank(examples, text)
+	if len(predictions) == 0 || predictions[0].Confidence < suggestionMinConfidence {
+		return noLabel, 0
+	}
+	return predictions[0].Label, predictions[0].Confidence
+}
+
+// suggestTags asks, for each of the most used tags, whether the text looks like the todos
+// carrying it, a todo can have several tags
+func (s *SuggestionService) suggestTags(history []todo.Todo, text string) []todo.TagSuggestion {
+	usage := map[string]int{}
+	for _, item := range history {
+		if item.Metadata == nil {
+			continue
+		}
+		for _, tag := range item.Metadata.Tags {
+			usage[strings.ToLower(tag)]++
+		}
+	}
+
+	candidates := make([]string, 0, len(usage))
+	for tag, count := range usage {
+		// A tag used once says nothing about what the user tags with it
+		if count > 1 {
+			candidates = append(candidates, tag)
+		}
+	}
+	slices.SortFunc(candidates, func(a, b string) int {
+		return cmp.Or(cmp.Compare(usage[b], usage[a]), strings.Compare(a, b))
+	})
+	candidates = candidates[:min(suggestionCandidateTags, len(candidates))]
+
+	tags := []todo.TagSuggestion{}
+	for _, tag := range candidates {
+		label, confidence := s.best(history, text, func(item todo.Todo) string {
+			if item.Metadata != nil && slices.ContainsFunc(item.Metadata.Tags, func(itemTag string) bool {
+				return strings.EqualFold(itemTag, tag)
+			}) {
+				return tag
+			}
+			return noLabel
+		})
+		if label == tag {
+			tags = append(tags, todo.TagSuggestion{Tag: tag, Confidence: confidence})
+		}
+	}
+
+	slices.SortFunc(tags, func(a, b todo.TagSuggestion) int {
+		return cmp.Or(cmp.Compare(b.Confidence, a.Confidence), strings.Compare(a.Tag, b.Tag))
+	})
+
+	return tags[:min(suggestionMaxTags, len(tags))]
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/merge"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+type SyncService struct {
+	server          *server.Server
+	todoService     *TodoService
+	todoRepo        repository.TodoStore
+	txManager       repository.TxManager
+	defaultStrategy merge.Strategy
+	maxChangedTodos int
+}
+
+func NewSyncService(server *server.Server, todoService *TodoService, todoRepo repository.TodoStore,
+	txManager repository.TxManager,
+) *SyncService {
+	syncConfig := server.Config.Sync
+	if syncConfig == nil {
+		syncConfig = config.DefaultSyncConfig()
+	}
+
+	strategy, err := merge.ParseStrategy(syncConfig.ConflictStrategy)
+	if err != nil {
+		strategy = merge.StrategyFieldMerge
+	}
+
+	maxChangedTodos := syncConfig.MaxChangedTodos
+	if maxChangedTodos <= 0 {
+		maxChangedTodos = config.DefaultSyncConfig().MaxChangedTodos
+	}
+
+	return &SyncService{
+		server:          server,
+		todoService:     todoService,
+		todoRepo:        todoRepo,
+		txManager:       txManager,
+		defaultStrategy: strategy,
+		maxChangedTodos: maxChangedTodos,
+	}
+}
+
+func (s *SyncService) SyncTodos(ctx echo.Context, userID string, payload *todo.SyncTodosPayload) (*todo.SyncResponse, error) {
+	logger := middleware.GetLogger(ctx)
+	serverTime := time.Now()
+
+	strategy := s.defaultStrategy
+	if payload.Strategy != nil {
+		strategy = merge.Strategy(*payload.Strategy)
+	}
+
+	// Load every todo the client touched in one query instead of one per change
+	todoIDs := make([]uuid.UUID, 0, len(payload.Changes))
+	for _, change := range payload.Changes {
+		todoIDs = append(todoIDs, change.TodoID)
+	}
+
+	currentTodos := map[uuid.UUID]*todo.Todo{}
+	if len(todoIDs) > 0 {
+		todos, err := s.todoRepo.GetTodosByIDs(ctx.Request().Context(), userID, todoIDs)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to fetch todos to sync")
+			return nil, err
+		}
+		for i := range todos {
+			currentTodos[todos[i].ID] = &todos[i]
+		}
+	}
+
+	// The pushed changes are applied all or nothing, a client retrying a failed push must not
+	// find part of it applied
+	var results []todo.SyncChangeResult
+	err := withinTx(ctx, s.txManager, func() error {
+		results = make([]todo.SyncChangeResult, 0, len(payload.Changes))
+		for _, change := range payload.Changes {
+			result, err := s.applyChange(ctx, userID, strategy, change, currentTodos[change.TodoID])
+			if err != nil {
+				logger.Error().Err(err).Str("todo_id", change.TodoID.String()).Msg("failed to apply sync change")
+				return err
+			}
+
+			// Later changes to the same todo build on this one
+			if result.Todo != nil {
+				currentTodos[change.TodoID] = result.Todo
+			}
+			results = append(results, *result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changed := []todo.Todo{}
+	if payload.Since != nil {
+		changed, err = s.todoRepo.GetTodosUpdatedSince(ctx.Request().Context(), userID, *payload.Since, s.maxChangedTodos)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to fetch todos changed since last sync")
+			return nil, err
+		}
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "todos_synced").
+		Str("strategy", string(strategy)).
+		Int("pushed_count", len(payload.Changes)).
+		Int("pulled_count", len(changed)).
+		Msg("Todos synced successfully")
+
+	return &todo.SyncResponse{
+		Strategy:   string(strategy),
+		Results:    results,
+		Changed:    changed,
+		ServerTime: serverTime,
+	}, nil
+}
+
+// applyChange applies one pushed change to current, the server copy of the todo or nil when it doesn't exist
+func (s *SyncService) applyChange(ctx echo.Context, userID string, strategy merge.Strategy,
+	change todo.SyncChange, current *todo.Todo,
+) (*todo.SyncChangeResult, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if current == nil {
+		return &todo.SyncChangeResult{
+			TodoID:    change.TodoID,
+			Status:    todo.SyncStatusMissing,
+			Conflicts: []string{},
+			Discarded: sortedFieldNames(change.Fields),
+		}, nil
+	}
+
+	status := todo.SyncStatusApplied
+	apply := merge.Fields(change.Fields)
+	conflicts := []string{}
+	discarded := []string{}
+
+	// Stale write: let the configured strategy decide what survives
+	if change.BaseVersion != current.Version {
+		serverFields, err := todoFields(current)
+		if err != nil {
+			return nil, err
+		}
+
+		resolved, err := merge.Resolve(strategy, merge.Fields(change.Base), serverFields, merge.Fields(change.Fields))
+		if err != nil {
+			return nil, errs.NewBadRequestError(err.Error(), false, nil, nil, nil)
+		}
+
+		apply = resolved.Apply
+		conflicts = resolved.Conflicts
+		discarded = resolved.Discarded
+
+		switch {
+		case !resolved.HasChanges() && len(discarded) > 0:
+			status = todo.SyncStatusRejected
+		case !resolved.HasChanges():
+			status = todo.SyncStatusUnchanged
+		default:
+			status = todo.SyncStatusMerged
+		}
+
+		logger.Debug().
+			Str("todo_id", current.ID.String()).
+			Int("base_version", change.BaseVersion).
+			Int("server_version", current.Version).
+			Strs("conflicts", conflicts).
+			Msg("resolved stale sync change")
+	}
+
+	result := &todo.SyncChangeResult{
+		TodoID:    change.TodoID,
+		Status:    status,
+		Conflicts: conflicts,
+		Discarded: discarded,
+		Todo:      current,
+	}
+
+	if len(apply) == 0 {
+		return result, nil
+	}
+
+	updatePayload, err := updatePayloadFromFields(current, apply)
+	if err != nil {
+		return nil, err
+	}
+
+	// A savepoint lets the batch go on after a rejected write, Postgres aborts the transaction otherwise
+	var updatedTodo *todo.Todo
+	err = withinTx(ctx, s.txManager, func() error {
+		updatedTodo, err = s.todoService.UpdateTodo(ctx, userID, updatePayload)
+		return err
+	})
+	if err != nil {
+		// Someone else wrote in between our read and write, report it instead of failing the batch
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) && httpErr.Status == http.StatusConflict {
+			result.Status = todo.SyncStatusRejected
+			result.Discarded = sortedFieldNames(apply)
+			return result, nil
+		}
+		return nil, err
+	}
+
+	result.Todo = updatedTodo
+	return result, nil
+}
+
+// todoFields extracts the syncable fields of a todo in their JSON representation
+func todoFields(t *todo.Todo) (merge.Fields, error) {
+	encoded, err := json.Marshal(t)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode todo")
+	}
+
+	var all merge.Fields
+	if err := json.Unmarshal(encoded, &all); err != nil {
+		return nil, errors.Wrap(err, "failed to decode todo fields")
+	}
+
+	fields := merge.Fields{}
+	for field := range todo.SyncableFields {
+		fields[field] = all[field]
+	}
+
+	return fields, nil
+}
+
+// updatePayloadFromFields turns resolved sync fields into a version-guarded update. Decoding
+// leaves the pointer fields of nulls unset, the nulls are cleared explicitly: a null description
+// is an empty one, the other nullable fields are set back to null.
+func updatePayloadFromFields(current *todo.Todo, fields merge.Fields) (*todo.UpdateTodoPayload, error) {
+	values := merge.Fields{}
+	var cleared []string
+	for _, field := range sortedFieldNames(fields) {
+		if isNull(fields[field]) {
+			cleared = append(cleared, field)
+			continue
+		}
+		values[field] = fields[field]
+	}
+
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode sync fields")
+	}
+
+	payload := &todo.UpdateTodoPayload{}
+	if err := json.Unmarshal(encoded, payload); err != nil {
+		return nil, errs.NewBadRequestError("invalid sync field value: "+err.Error(), false, nil, nil, nil)
+	}
+
+	for _, field := range cleared {
+		if field == "description" {
+			empty := ""
+			payload.Description = &empty
+			continue
+		}
+		payload.Clear = append(payload.Clear, field)
+	}
+
+	version := current.Version
+	payload.ID = current.ID
+	payload.Version = &version
+
+	if err := payload.Validate(); err != nil {
+		return nil, validation.Error(err)
+	}
+
+	return payload, nil
+}
+
+func isNull(value json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(value)
+	return len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null"))
+}
+
+func sortedFieldNames(fields map[string]json.RawMessage) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
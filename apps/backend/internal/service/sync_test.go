@@ -0,0 +1,188 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/lib/merge"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func rawFields(values map[string]string) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	for field, value := range values {
+		fields[field] = json.RawMessage(value)
+	}
+	return fields
+}
+
+func TestSyncTodosStatuses(t *testing.T) {
+	dueDate := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	dueJSON, err := json.Marshal(dueDate)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		strategy merge.Strategy
+		// stale changes are made against version 1 after the server moved the todo on
+		stale     bool
+		base      map[string]string
+		fields    map[string]string
+		missing   bool
+		status    todo.SyncStatus
+		conflicts []string
+		discarded []string
+		check     func(t *testing.T, synced *todo.Todo)
+	}{
+		{
+			name:   "current change is applied",
+			base:   map[string]string{"title": `"Write report"`},
+			fields: map[string]string{"title": `"Write the report"`},
+			status: todo.SyncStatusApplied,
+			check: func(t *testing.T, synced *todo.Todo) {
+				require.Equal(t, "Write the report", synced.Title)
+			},
+		},
+		{
+			name:     "stale disjoint change is merged",
+			strategy: merge.StrategyFieldMerge,
+			stale:    true,
+			base:     map[string]string{"priority": `"medium"`},
+			fields:   map[string]string{"priority": `"low"`},
+			status:   todo.SyncStatusMerged,
+			check: func(t *testing.T, synced *todo.Todo) {
+				require.Equal(t, todo.PriorityLow, synced.Priority)
+				require.Equal(t, "Edited on the server", synced.Title)
+			},
+		},
+		{
+			name:      "stale conflicting change is rejected by field merge",
+			strategy:  merge.StrategyFieldMerge,
+			stale:     true,
+			base:      map[string]string{"title": `"Write report"`},
+			fields:    map[string]string{"title": `"Edited offline"`},
+			status:    todo.SyncStatusRejected,
+			conflicts: []string{"title"},
+			discarded: []string{"title"},
+		},
+		{
+			name:      "stale conflicting change is merged by client wins",
+			strategy:  merge.StrategyClientWins,
+			stale:     true,
+			base:      map[string]string{"title": `"Write report"`},
+			fields:    map[string]string{"title": `"Edited offline"`},
+			status:    todo.SyncStatusMerged,
+			conflicts: []string{"title"},
+			check: func(t *testing.T, synced *todo.Todo) {
+				require.Equal(t, "Edited offline", synced.Title)
+			},
+		},
+		{
+			name:      "stale clean change is rejected by server wins",
+			strategy:  merge.StrategyServerWins,
+			stale:     true,
+			base:      map[string]string{"priority": `"medium"`},
+			fields:    map[string]string{"priority": `"low"`},
+			status:    todo.SyncStatusRejected,
+			discarded: []string{"priority"},
+		},
+		{
+			name:     "stale change matching the server is unchanged",
+			strategy: merge.StrategyFieldMerge,
+			stale:    true,
+			base:     map[string]string{"title": `"Write report"`},
+			fields:   map[string]string{"title": `"Edited on the server"`},
+			status:   todo.SyncStatusUnchanged,
+		},
+		{
+			name:      "change to an unknown todo is missing",
+			missing:   true,
+			base:      map[string]string{"title": `"Write report"`},
+			fields:    map[string]string{"title": `"Edited offline"`},
+			status:    todo.SyncStatusMissing,
+			discarded: []string{"title"},
+		},
+		{
+			name:   "null clears the due date",
+			base:   map[string]string{"dueDate": string(dueJSON)},
+			fields: map[string]string{"dueDate": `null`},
+			status: todo.SyncStatusApplied,
+			check: func(t *testing.T, synced *todo.Todo) {
+				require.Nil(t, synced.DueDate)
+			},
+		},
+		{
+			name:   "null empties the description",
+			base:   map[string]string{"description": `"Quarterly numbers"`},
+			fields: map[string]string{"description": `null`},
+			status: todo.SyncStatusApplied,
+			check: func(t *testing.T, synced *todo.Todo) {
+				require.Empty(t, synced.Description)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			syncService := NewSyncService(env.server, env.todos, env.repos.Todo, env.repos.Tx)
+
+			description := "Quarterly numbers"
+			created := env.createTodo(t, testUserID, &todo.CreateTodoPayload{
+				Title:       "Write report",
+				Description: &description,
+				DueDate:     &dueDate,
+			})
+
+			if tt.stale {
+				title := "Edited on the server"
+				_, err := env.repos.Todo.UpdateTodo(env.context(testUserID).Request().Context(), testUserID,
+					&todo.UpdateTodoPayload{ID: created.ID, Title: &title})
+				require.NoError(t, err)
+			}
+
+			todoID := created.ID
+			if tt.missing {
+				todoID = uuid.New()
+			}
+
+			var strategy *string
+			if tt.strategy != "" {
+				name := string(tt.strategy)
+				strategy = &name
+			}
+
+			response, err := syncService.SyncTodos(env.context(testUserID), testUserID, &todo.SyncTodosPayload{
+				Strategy: strategy,
+				Changes: []todo.SyncChange{{
+					TodoID:      todoID,
+					BaseVersion: created.Version,
+					Base:        rawFields(tt.base),
+					Fields:      rawFields(tt.fields),
+				}},
+			})
+			require.NoError(t, err)
+			require.Len(t, response.Results, 1)
+
+			result := response.Results[0]
+			require.Equal(t, tt.status, result.Status)
+			require.Equal(t, append([]string{}, tt.conflicts...), result.Conflicts)
+			require.Equal(t, append([]string{}, tt.discarded...), result.Discarded)
+			if tt.check != nil {
+				require.NotNil(t, result.Todo)
+				tt.check(t, result.Todo)
+			}
+		})
+	}
+}
+
+func TestUpdatePayloadFromFieldsRejectsNullRequiredField(t *testing.T) {
+	current := &todo.Todo{Title: "Write report", Version: 3}
+	current.ID = uuid.New()
+
+	_, err := updatePayloadFromFields(current, merge.Fields(rawFields(map[string]string{"title": `null`})))
+	require.Error(t, err)
+}
@@ -1,40 +1,104 @@
 package service
 
 import (
-	"mime/multipart"
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/Sameer16536/ExecuTask/internal/config"
 	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/events"
+	"github.com/Sameer16536/ExecuTask/internal/lib/analytics"
 	"github.com/Sameer16536/ExecuTask/internal/lib/aws"
+	"github.com/Sameer16536/ExecuTask/internal/lib/breaker"
+	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/lib/llm"
+	"github.com/Sameer16536/ExecuTask/internal/lib/sniff"
 	"github.com/Sameer16536/ExecuTask/internal/middleware"
 	"github.com/Sameer16536/ExecuTask/internal/model"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/model/category"
+	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/rule"
 	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/model/workspace"
 	"github.com/Sameer16536/ExecuTask/internal/repository"
 	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/Sameer16536/ExecuTask/internal/validation"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
 )
 
 type TodoService struct {
-	server       *server.Server
-	todoRepo     *repository.TodoRepository
-	categoryRepo *repository.CategoryRepository
-	awsClient    *aws.AWS
+	server         *server.Server
+	todoRepo       repository.TodoStore
+	categoryRepo   repository.CategoryStore
+	commentRepo    repository.CommentStore
+	magicTagRepo   repository.MagicTagStore
+	dependencyRepo repository.DependencyStore
+	workspaceRepo  repository.WorkspaceStore
+	awsClient      *aws.AWS
+	audit          *AuditService
+	txManager      repository.TxManager
+	previews       *PreviewService
+	covers         *CoverService
+	// assistant is nil when subtask suggestions are disabled
+	assistant llm.Provider
 }
 
-func NewTodoService(server *server.Server, todoRepo *repository.TodoRepository, categoryRepo *repository.CategoryRepository, awsClient *aws.AWS) *TodoService {
+func NewTodoService(server *server.Server, todoRepo repository.TodoStore, categoryRepo repository.CategoryStore,
+	commentRepo repository.CommentStore, magicTagRepo repository.MagicTagStore,
+	dependencyRepo repository.DependencyStore, workspaceRepo repository.WorkspaceStore, awsClient *aws.AWS,
+	auditService *AuditService, txManager repository.TxManager, previewService *PreviewService,
+	coverService *CoverService,
+) *TodoService {
 	return &TodoService{
-		server:       server,
-		todoRepo:     todoRepo,
-		categoryRepo: categoryRepo,
-		awsClient:    awsClient,
+		server:         server,
+		todoRepo:       todoRepo,
+		categoryRepo:   categoryRepo,
+		commentRepo:    commentRepo,
+		magicTagRepo:   magicTagRepo,
+		dependencyRepo: dependencyRepo,
+		workspaceRepo:  workspaceRepo,
+		awsClient:      awsClient,
+		audit:          auditService,
+		txManager:      txManager,
+		previews:       previewService,
+		covers:         coverService,
+		assistant:      llm.NewProvider(server.Config.LLM, server.Config.Outbound),
 	}
 }
 
 func (s *TodoService) CreateTodo(ctx echo.Context, userID string, payload *todo.CreateTodoPayload) (*todo.Todo, error) {
 	logger := middleware.GetLogger(ctx)
 
+	// Magic tags only fill in what the payload leaves unset
+	if shortcuts := readMagicTags(ctx, s.magicTagRepo, userID, payload.Title); shortcuts != nil {
+		payload.Title = shortcuts.Title
+		payload.Priority = cmp.Or(payload.Priority, shortcuts.Priority)
+		payload.DueDate = cmp.Or(payload.DueDate, shortcuts.DueDate)
+		payload.CategoryID = cmp.Or(payload.CategoryID, shortcuts.CategoryID)
+	}
+
+	// The status and priority are keys of the scales of the workspace
+	workspaceID := middleware.GetWorkspaceID(ctx)
+	workflow, err := workspaceWorkflow(ctx.Request().Context(), s.workspaceRepo, &workspaceID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch workspace workflow")
+		return nil, err
+	}
+	if err := workflow.ApplyToCreate(payload); err != nil {
+		logger.Warn().Msg("todo rejected by workspace workflow")
+		return nil, err
+	}
+
 	// Validate parent todo exists and belongs to user (if provided)
 	if payload.ParentTodoID != nil {
 		parentTodo, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, *payload.ParentTodoID)
@@ -44,9 +108,8 @@ func (s *TodoService) CreateTodo(ctx echo.Context, userID string, payload *todo.
 		}
 
 		if !parentTodo.CanHaveChildren() {
-			err := errs.NewBadRequestError("Parent todo cannot have children (subtasks can't have subtasks)", false, nil, nil, nil)
 			logger.Warn().Msg("parent todo cannot have children")
-			return nil, err
+			return nil, errParentCannotHaveChildren()
 		}
 	}
 
@@ -59,42 +122,117 @@ func (s *TodoService) CreateTodo(ctx echo.Context, userID string, payload *todo.
 		}
 	}
 
-	todoItem, err := s.todoRepo.CreateTodo(ctx.Request().Context(), userID, payload)
+	todoItem, err := s.todoRepo.CreateTodo(ctx.Request().Context(), userID, middleware.GetWorkspaceID(ctx), payload)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to create todo")
 		return nil, err
 	}
 
-	// Business event log
-	eventLogger := middleware.GetLogger(ctx)
-	eventLogger.Info().
-		Str("event", "todo_created").
-		Str("todo_id", todoItem.ID.String()).
-		Str("title", todoItem.Title).
-		Str("category_id", func() string {
-			if todoItem.CategoryID != nil {
-				return todoItem.CategoryID.String()
-			}
-			return ""
-		}()).
-		Str("priority", string(todoItem.Priority)).
-		Msg("Todo created successfully")
+	actor := eventActor(ctx, userID)
+	published := []events.Event{&events.TodoCreated{Actor: actor, Todo: todoItem}}
+	published = append(published, tagEvents(actor, todoItem, nil)...)
+	publishEvents(ctx, s.server, published...)
 
 	return todoItem, nil
 }
 
-func (s *TodoService) GetTodoByID(ctx echo.Context, userID string, todoID uuid.UUID) (*todo.PopulatedTodo, error) {
+// GetTodoByID returns the populated todo. shape is optional and trims or expands the representation.
+func (s *TodoService) GetTodoByID(ctx echo.Context, userID string, todoID uuid.UUID,
+	shape *todo.ShapeQuery,
+) (*todo.PopulatedTodo, error) {
 	logger := middleware.GetLogger(ctx)
 
+	if shape != nil && shape.Expand != nil {
+		todoItem, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, todoID)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to fetch todo by ID")
+			return nil, err
+		}
+
+		todos := []todo.PopulatedTodo{{Todo: *todoItem}}
+		if err := s.expandTodos(ctx, userID, todos, shape.Expansion()); err != nil {
+			return nil, err
+		}
+		if err := s.attachViews(ctx, userID, &todos[0]); err != nil {
+			return nil, err
+		}
+		s.recordView(ctx, userID, todoID)
+
+		s.attachLinkPreviews(ctx, &todos[0])
+		s.covers.AttachCover(ctx, &todos[0])
+		todos[0].Project(shape.Projection())
+		return &todos[0], nil
+	}
+
 	todoItem, err := s.todoRepo.GetTodoByID(ctx.Request().Context(), userID, todoID)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to fetch todo by ID")
 		return nil, err
 	}
 
+	// The todo comes with the view before this one, so the reader can tell what changed since
+	if err := s.attachViews(ctx, userID, todoItem); err != nil {
+		return nil, err
+	}
+	s.recordView(ctx, userID, todoID)
+
+	s.attachLinkPreviews(ctx, todoItem)
+	s.covers.AttachCover(ctx, todoItem)
+	if shape != nil && shape.IsShaped() {
+		todoItem.Project(shape.Projection())
+	}
+
 	return todoItem, nil
 }
 
+// attachViews sets when the reader last viewed each todo and whether it changed since
+func (s *TodoService) attachViews(ctx echo.Context, userID string, todos ...*todo.PopulatedTodo) error {
+	if len(todos) == 0 {
+		return nil
+	}
+
+	todoIDs := make([]uuid.UUID, 0, len(todos))
+	for _, item := range todos {
+		todoIDs = append(todoIDs, item.ID)
+	}
+
+	views, err := s.todoRepo.GetTodoViews(ctx.Request().Context(), userID, todoIDs)
+	if err != nil {
+		middleware.GetLogger(ctx).Error().Err(err).Msg("failed to fetch todo views")
+		return err
+	}
+
+	for _, item := range todos {
+		if viewedAt, ok := views[item.ID]; ok {
+			item.ViewedAt = &viewedAt
+		}
+		item.Unread = item.IsUnread(item.ViewedAt)
+	}
+
+	return nil
+}
+
+// recordView marks the todo as viewed by the user, a failure only leaves it unread
+func (s *TodoService) recordView(ctx echo.Context, userID string, todoID uuid.UUID) {
+	if err := s.todoRepo.RecordTodoView(ctx.Request().Context(), userID, todoID); err != nil {
+		middleware.GetLogger(ctx).Warn().Err(err).Msg("failed to record todo view")
+	}
+}
+
+// attachLinkPreviews attaches the previews of the links in the description and the comments
+func (s *TodoService) attachLinkPreviews(ctx echo.Context, todoItem *todo.PopulatedTodo) {
+	texts := []string{todoItem.Description}
+	for _, c := range todoItem.Comments {
+		texts = append(texts, c.Content)
+	}
+
+	previews := s.previews.PreviewsFor(ctx, texts)
+	todoItem.LinkPreviews = previews[0]
+	for i := range todoItem.Comments {
+		todoItem.Comments[i].LinkPreviews = previews[i+1]
+	}
+}
+
 func (s *TodoService) GetTodos(ctx echo.Context, userID string, query *todo.GetTodosQuery) (*model.PaginatedResponse[todo.PopulatedTodo], error) {
 	logger := middleware.GetLogger(ctx)
 
@@ -104,12 +242,209 @@ func (s *TodoService) GetTodos(ctx echo.Context, userID string, query *todo.GetT
 		return nil, err
 	}
 
+	if expansion := query.Expansion(); expansion != nil {
+		if err := s.expandTodos(ctx, userID, result.Data, expansion); err != nil {
+			return nil, err
+		}
+	}
+
+	todos := make([]*todo.PopulatedTodo, 0, len(result.Data))
+	for i := range result.Data {
+		todos = append(todos, &result.Data[i])
+	}
+	if err := s.attachViews(ctx, userID, todos...); err != nil {
+		return nil, err
+	}
+
+	if query.IsShaped() {
+		projection := query.Projection()
+		for i := range result.Data {
+			result.Data[i].Project(projection)
+		}
+	}
+
 	return result, nil
 }
 
+// expandTodos loads the requested relations for all todos with one query per relation
+func (s *TodoService) expandTodos(ctx echo.Context, userID string, todos []todo.PopulatedTodo, expansion *todo.Expansion) error {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	if len(todos) == 0 {
+		return nil
+	}
+
+	todoIDs := make([]uuid.UUID, 0, len(todos))
+	indexByID := make(map[uuid.UUID]int, len(todos))
+	for i := range todos {
+		todoIDs = append(todoIDs, todos[i].ID)
+		indexByID[todos[i].ID] = i
+	}
+
+	if expansion.Category {
+		categoryIDs := []uuid.UUID{}
+		for i := range todos {
+			if todos[i].CategoryID != nil {
+				categoryIDs = append(categoryIDs, *todos[i].CategoryID)
+			}
+		}
+
+		if len(categoryIDs) > 0 {
+			categories, err := s.categoryRepo.GetCategoriesByIDs(reqCtx, userID, categoryIDs)
+			if err != nil {
+				logger.Error().Err(err).Msg("failed to expand todo categories")
+				return err
+			}
+
+			categoryByID := make(map[uuid.UUID]*category.Category, len(categories))
+			for i := range categories {
+				categoryByID[categories[i].ID] = &categories[i]
+			}
+
+			for i := range todos {
+				if todos[i].CategoryID != nil {
+					todos[i].Category = categoryByID[*todos[i].CategoryID]
+				}
+			}
+		}
+	}
+
+	if expansion.Subtasks {
+		children, err := s.todoRepo.GetChildrenByParentIDs(reqCtx, userID, todoIDs)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to expand todo subtasks")
+			return err
+		}
+
+		for i := range todos {
+			todos[i].Children = []todo.Todo{}
+		}
+		for _, child := range children {
+			if i, ok := indexByID[*child.ParentTodoID]; ok {
+				todos[i].Children = append(todos[i].Children, child)
+			}
+		}
+	}
+
+	if expansion.Comments || expansion.LatestComment {
+		// A full comment expansion already contains the latest one
+		latestOnly := !expansion.Comments
+
+		comments, err := s.commentRepo.GetCommentsByTodoIDs(reqCtx, userID, todoIDs, latestOnly)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to expand todo comments")
+			return err
+		}
+
+		for i := range todos {
+			todos[i].Comments = []comment.Comment{}
+		}
+		for _, c := range comments {
+			if i, ok := indexByID[c.TodoID]; ok {
+				todos[i].Comments = append(todos[i].Comments, c)
+			}
+		}
+
+		// The latest comment is paired with a live count read alongside it, so clients can show "N more"
+		if latestOnly {
+			counts, err := s.commentRepo.GetCommentCounts(reqCtx, userID, todoIDs)
+			if err != nil {
+				logger.Error().Err(err).Msg("failed to count todo comments")
+				return err
+			}
+
+			for i := range todos {
+				todos[i].CommentCount = counts[todos[i].ID]
+			}
+		}
+	}
+
+	if expansion.Attachments {
+		attachments, err := s.todoRepo.GetAttachmentsByTodoIDs(reqCtx, todoIDs)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to expand todo attachments")
+			return err
+		}
+
+		for i := range todos {
+			todos[i].Attachments = []todo.TodoAttachment{}
+		}
+		for _, attachment := range attachments {
+			if i, ok := indexByID[attachment.TodoID]; ok {
+				todos[i].Attachments = append(todos[i].Attachments, attachment)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ExportTodos streams every matching todo to emit without loading them all at once
+func (s *TodoService) ExportTodos(ctx echo.Context, userID string, query *todo.ExportTodosQuery,
+	emit func(todoItem *todo.Todo) error,
+) error {
+	logger := middleware.GetLogger(ctx)
+
+	count := 0
+	err := s.todoRepo.StreamTodos(ctx.Request().Context(), userID, query, func(todoItem *todo.Todo) error {
+		count++
+		return emit(todoItem)
+	})
+	if err != nil {
+		logger.Error().Err(err).Int("exported", count).Msg("failed to export todos")
+		return err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "todos_exported").
+		Str("format", *query.Format).
+		Int("count", count).
+		Msg("Todos exported successfully")
+
+	return nil
+}
+
 func (s *TodoService) UpdateTodo(ctx echo.Context, userID string, payload *todo.UpdateTodoPayload) (*todo.Todo, error) {
 	logger := middleware.GetLogger(ctx)
 
+	if payload.Title != nil {
+		if shortcuts := readMagicTags(ctx, s.magicTagRepo, userID, *payload.Title); shortcuts != nil {
+			payload.Title = &shortcuts.Title
+			payload.Priority = cmp.Or(payload.Priority, shortcuts.Priority)
+			payload.DueDate = cmp.Or(payload.DueDate, shortcuts.DueDate)
+			payload.CategoryID = cmp.Or(payload.CategoryID, shortcuts.CategoryID)
+		}
+	}
+
+	// The status and priority are keys of the scales of the workspace of the todo and the due
+	// date rule depends on the category it ends up in. The stored todo also tells which changes
+	// the automation rules react to.
+	var current *todo.Todo
+	completing := false
+	if payload.Status != nil || payload.Priority != nil || payload.DueDate != nil || payload.Metadata != nil {
+		var err error
+		current, err = s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, payload.ID)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to load todo for validation")
+			return nil, err
+		}
+
+		workflow, err := workspaceWorkflow(ctx.Request().Context(), s.workspaceRepo, current.WorkspaceID)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to fetch workspace workflow")
+			return nil, err
+		}
+		category, err := workflow.ApplyToUpdate(payload, current)
+		if err != nil {
+			logger.Warn().Msg("todo update rejected by workspace workflow")
+			return nil, err
+		}
+		completing = category == todo.StatusCompleted && current.StatusCategory != todo.StatusCompleted
+	}
+
 	// Validate parent todo exists and belongs to user (if provided)
 	if payload.ParentTodoID != nil {
 		parentTodo, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, *payload.ParentTodoID)
@@ -118,16 +453,9 @@ func (s *TodoService) UpdateTodo(ctx echo.Context, userID string, payload *todo.
 			return nil, err
 		}
 
-		if parentTodo.ID == payload.ID {
-			err := errs.NewBadRequestError("Todo cannot be its own parent", false, nil, nil, nil)
-			logger.Warn().Msg("todo cannot be its own parent")
-			return nil, err
-		}
-
 		if !parentTodo.CanHaveChildren() {
-			err := errs.NewBadRequestError("Parent todo cannot have children (subtasks can't have subtasks)", false, nil, nil, nil)
 			logger.Warn().Msg("parent todo cannot have children")
-			return nil, err
+			return nil, errParentCannotHaveChildren()
 		}
 
 		logger.Debug().Msg("parent todo validation passed")
@@ -150,44 +478,486 @@ func (s *TodoService) UpdateTodo(ctx echo.Context, userID string, payload *todo.
 		return nil, err
 	}
 
-	// Business event log
-	eventLogger := middleware.GetLogger(ctx)
-	eventLogger.Info().
-		Str("event", "todo_updated").
-		Str("todo_id", updatedTodo.ID.String()).
-		Str("title", updatedTodo.Title).
-		Str("category_id", func() string {
-			if updatedTodo.CategoryID != nil {
-				return updatedTodo.CategoryID.String()
-			}
-			return ""
-		}()).
-		Str("priority", string(updatedTodo.Priority)).
-		Str("status", string(updatedTodo.Status)).
-		Msg("Todo updated successfully")
+	actor := eventActor(ctx, userID)
+	published := []events.Event{&events.TodoUpdated{
+		Actor:              actor,
+		Todo:               updatedTodo,
+		DescriptionChanged: payload.Description != nil,
+	}}
+	if completing {
+		published = append(published, &events.TodoCompleted{Actor: actor, Todo: updatedTodo})
+	}
+	if payload.Metadata != nil {
+		published = append(published, tagEvents(actor, updatedTodo, current)...)
+	}
+	publishEvents(ctx, s.server, published...)
+
+	// The user saw the changes they made, they don't make the todo unread to them
+	s.recordView(ctx, userID, updatedTodo.ID)
 
 	return updatedTodo, nil
 }
 
+// CompleteTodos completes the todos in one transaction, either all of them are completed or none.
+// Todos completed already are left as they were, those still waiting on open todos are completed
+// with a warning. The rules listening to the completions are evaluated together, so what they
+// notify about arrives as one digest.
+func (s *TodoService) CompleteTodos(ctx echo.Context, userID string,
+	payload *todo.CompleteTodosPayload,
+) (*todo.BulkCompletion, error) {
+	logger := middleware.GetLogger(ctx)
+
+	result := &todo.BulkCompletion{
+		Completed:        []todo.Todo{},
+		AlreadyCompleted: []uuid.UUID{},
+		Blocked:          []todo.BlockedTodo{},
+	}
+	err := s.txManager.WithinTx(ctx.Request().Context(), func(txCtx context.Context) error {
+		items, err := s.todoRepo.GetTodosByIDs(txCtx, userID, payload.IDs)
+		if err != nil {
+			return err
+		}
+		byID := make(map[uuid.UUID]todo.Todo, len(items))
+		for _, item := range items {
+			byID[item.ID] = item
+		}
+
+		for _, todoID := range payload.IDs {
+			item, ok := byID[todoID]
+			if !ok {
+				code := errs.CodeTodoNotFound
+				return errs.NewNotFoundError("todo not found", false, &code)
+			}
+			if item.StatusCategory == todo.StatusCompleted {
+				result.AlreadyCompleted = append(result.AlreadyCompleted, todoID)
+				continue
+			}
+
+			blockers, err := s.dependencyRepo.GetBlockedBy(txCtx, userID, todoID)
+			if err != nil {
+				return err
+			}
+			openBlockers := []uuid.UUID{}
+			for _, blocker := range blockers {
+				if blocker.StatusCategory != todo.StatusCompleted && !slices.Contains(payload.IDs, blocker.ID) {
+					openBlockers = append(openBlockers, blocker.ID)
+				}
+			}
+			if len(openBlockers) > 0 {
+				result.Blocked = append(result.Blocked, todo.BlockedTodo{
+					TodoID:    todoID,
+					Title:     item.Title,
+					BlockedBy: openBlockers,
+				})
+			}
+
+			status := todo.StatusCompleted
+			completed, err := s.todoRepo.UpdateTodo(txCtx, userID, &todo.UpdateTodoPayload{
+				ID:     todoID,
+				Status: &status,
+			})
+			if err != nil {
+				return err
+			}
+			result.Completed = append(result.Completed, *completed)
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to complete todos")
+		return nil, err
+	}
+
+	publishEvents(ctx, s.server, &events.TodosCompleted{
+		Actor:                 eventActor(ctx, userID),
+		Todos:                 result.Completed,
+		AlreadyCompletedCount: len(result.AlreadyCompleted),
+		BlockedCount:          len(result.Blocked),
+	})
+
+	for _, completed := range result.Completed {
+		s.recordView(ctx, userID, completed.ID)
+	}
+
+	return result, nil
+}
+
+// UncompleteTodo opens a todo completed moments ago again, for a completion made by mistake. It
+// is only allowed within the undo window: the rules listening to the completion run once the
+// window passed, and skip it when the completion was undone, so no notification or follow-up
+// comes out of it.
+func (s *TodoService) UncompleteTodo(ctx echo.Context, userID string, todoID uuid.UUID) (*todo.Todo, error) {
+	logger := middleware.GetLogger(ctx)
+
+	current, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, todoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to load todo to uncomplete")
+		return nil, err
+	}
+
+	if current.StatusCategory != todo.StatusCompleted || current.CompletedAt == nil {
+		code := errs.CodeTodoNotCompleted
+		return nil, errs.NewConflictError("todo is not completed", false, &code)
+	}
+	if time.Since(*current.CompletedAt) > s.server.Config.Todos.UndoWindow {
+		code := errs.CodeUndoWindowExpired
+		return nil, errs.NewGoneError("the completion can no longer be undone", false, &code)
+	}
+
+	// The version keeps a concurrent edit, such as completing it again, from being overwritten
+	status := todo.StatusActive
+	reopened, err := s.todoRepo.UpdateTodo(ctx.Request().Context(), userID, &todo.UpdateTodoPayload{
+		ID:      todoID,
+		Status:  &status,
+		Version: &current.Version,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to uncomplete todo")
+		return nil, err
+	}
+
+	publishEvents(ctx, s.server, &events.TodoUncompleted{
+		Actor:       eventActor(ctx, userID),
+		Todo:        reopened,
+		CompletedAt: *current.CompletedAt,
+	})
+
+	s.recordView(ctx, userID, reopened.ID)
+
+	return reopened, nil
+}
+
+// tagEvents returns a TodoTagged event for each tag the todo gained over before, before is nil
+// for a new todo
+func tagEvents(actor events.Actor, todoItem, before *todo.Todo) []events.Event {
+	if todoItem.Metadata == nil {
+		return nil
+	}
+
+	tagged := []events.Event{}
+	for _, tag := range todoItem.Metadata.Tags {
+		if before != nil && rule.HasTag(before, tag) {
+			continue
+		}
+
+		tagged = append(tagged, &events.TodoTagged{Actor: actor, TodoID: todoItem.ID, Tag: tag})
+	}
+	return tagged
+}
+
+// maxDuplicateCandidates caps the possible duplicates reported when creating a todo
+const maxDuplicateCandidates = 5
+
+// FindDuplicates returns the open todos of the same category with a title like the new todo's.
+// It is best effort, the todo is already created: failures are logged and report none.
+func (s *TodoService) FindDuplicates(ctx echo.Context, userID string, created *todo.Todo) []todo.DuplicateCandidate {
+	logger := middleware.GetLogger(ctx)
+
+	threshold := config.DefaultSearchConfig().DuplicateThreshold
+	if s.server.Config.Search != nil && s.server.Config.Search.DuplicateThreshold > 0 {
+		threshold = s.server.Config.Search.DuplicateThreshold
+	}
+
+	candidates, err := s.todoRepo.FindDuplicateTodos(ctx.Request().Context(), userID, created.Title,
+		created.CategoryID, created.ID, threshold, maxDuplicateCandidates)
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to look for duplicate todos")
+		return nil
+	}
+
+	return candidates
+}
+
+// MergeTodos folds the source todos into the target: their subtasks, comments and attachments
+// move over, tags are combined, the highest priority and earliest due date are kept when the
+// target has none, and the sources are deleted
+func (s *TodoService) MergeTodos(ctx echo.Context, userID string, payload *todo.MergeTodosPayload) (*todo.Todo, error) {
+	logger := middleware.GetLogger(ctx)
+
+	var before, merged *todo.Todo
+	var sources []todo.Todo
+	err := s.txManager.WithinTx(ctx.Request().Context(), func(txCtx context.Context) error {
+		var err error
+		before, err = s.todoRepo.CheckTodoExists(txCtx, userID, payload.ID)
+		if err != nil {
+			return err
+		}
+
+		sources, err = s.todoRepo.GetTodosByIDs(txCtx, userID, payload.SourceIDs)
+		if err != nil {
+			return err
+		}
+		if len(sources) != len(payload.SourceIDs) {
+			code := errs.CodeTodoNotFound
+			return errs.NewNotFoundError("todo not found", false, &code)
+		}
+
+		update, err := mergedFields(before, sources)
+		if err != nil {
+			return err
+		}
+		if update != nil {
+			if _, err := s.todoRepo.UpdateTodo(txCtx, userID, update); err != nil {
+				return err
+			}
+		}
+
+		if err := s.todoRepo.MergeTodos(txCtx, userID, payload.ID, payload.SourceIDs); err != nil {
+			return err
+		}
+
+		// Read again for the counters the moves changed
+		merged, err = s.todoRepo.CheckTodoExists(txCtx, userID, payload.ID)
+		return err
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to merge todos")
+		return nil, err
+	}
+
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionTodoMerged,
+		EntityType: "todo",
+		EntityID:   payload.ID.String(),
+		Before:     map[string]any{"todo": before, "sources": sources},
+		After:      merged,
+	})
+
+	publishEvents(ctx, s.server, &events.TodosMerged{
+		Actor:       eventActor(ctx, userID),
+		Todo:        merged,
+		SourceCount: len(sources),
+	})
+
+	return merged, nil
+}
+
+// mergedFields is the update of the target a merge of the sources makes, nil when the target
+// keeps every field as is
+func mergedFields(target *todo.Todo, sources []todo.Todo) (*todo.UpdateTodoPayload, error) {
+	update := &todo.UpdateTodoPayload{ID: target.ID}
+	changed := false
+
+	var tags []string
+	if target.Metadata != nil {
+		tags = slices.Clone(target.Metadata.Tags)
+	}
+	priority, level := target.Priority, target.PriorityLevel
+	dueDate := target.DueDate
+
+	for _, source := range sources {
+		if target.ParentTodoID != nil && *target.ParentTodoID == source.ID {
+			return nil, errs.NewBadRequestError("a todo can't be merged into one of its own subtasks", false, nil, nil, nil)
+		}
+		if !target.CanHaveChildren() && source.SubtaskCount > 0 {
+			return nil, errs.NewBadRequestError("todos with subtasks can't be merged into a subtask", false, nil, nil, nil)
+		}
+
+		if source.Metadata != nil {
+			for _, tag := range source.Metadata.Tags {
+				if !slices.ContainsFunc(tags, func(existing string) bool { return strings.EqualFold(existing, tag) }) {
+					tags = append(tags, tag)
+					changed = true
+				}
+			}
+		}
+		// Only the sources on the scales of the target lend it their priority
+		if sameWorkspace(&source, target) && source.PriorityLevel > level {
+			priority, level = source.Priority, source.PriorityLevel
+			update.Priority = &priority
+			changed = true
+		}
+		if target.DueDate == nil && source.DueDate != nil && (dueDate == nil || source.DueDate.Before(*dueDate)) {
+			dueDate = source.DueDate
+			update.DueDate = dueDate
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil, nil
+	}
+
+	if target.Metadata != nil || len(tags) > 0 {
+		metadata := todo.Metadata{}
+		if target.Metadata != nil {
+			metadata = *target.Metadata
+		}
+		metadata.Tags = tags
+		update.Metadata = &metadata
+	}
+
+	return update, nil
+}
+
+// SuggestSubtasks asks the provider for a breakdown of the todo. Nothing is created, the user
+// confirms suggestions by creating them as subtasks of the todo.
+func (s *TodoService) SuggestSubtasks(ctx echo.Context, userID string,
+	payload *todo.SuggestSubtasksPayload,
+) (*todo.SubtaskSuggestions, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if s.assistant == nil {
+		code := errs.CodeSuggestionsDisabled
+		return nil, errs.NewServiceUnavailableError("subtask suggestions are not enabled on this server", false, &code)
+	}
+
+	todoItem, err := s.todoRepo.GetTodoByID(ctx.Request().Context(), userID, payload.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch todo by ID")
+		return nil, err
+	}
+
+	if !todoItem.CanHaveChildren() {
+		return nil, errs.NewBadRequestError("subtasks can't have subtasks", false, nil, nil, nil)
+	}
+
+	existing := make([]string, 0, len(todoItem.Children))
+	for _, child := range todoItem.Children {
+		existing = append(existing, child.Title)
+	}
+
+	request := llm.SubtaskRequest{
+		Title:       todoItem.Title,
+		Description: todoItem.Description,
+		Existing:    existing,
+		Limit:       *payload.Limit,
+	}
+
+	assistant := s.assistant
+	var subtasks []llm.Subtask
+	err = s.server.Breakers.Get("llm").Do(ctx.Request().Context(), func(reqCtx context.Context) error {
+		var err error
+		subtasks, err = assistant.SuggestSubtasks(reqCtx, request)
+		return err
+	})
+	if breaker.IsUnavailable(err) && assistant.Name() != llm.ProviderLocal {
+		// The model is down or saturated, the heuristics still answer
+		logger.Warn().Err(err).Str("provider", assistant.Name()).Msg("falling back to local subtask suggestions")
+		assistant = llm.NewLocalProvider()
+		subtasks, err = assistant.SuggestSubtasks(ctx.Request().Context(), request)
+	}
+	if err != nil {
+		logger.Error().Err(err).Str("provider", assistant.Name()).Msg("failed to suggest subtasks")
+		return nil, errs.NewServiceUnavailableError("subtask suggestions are unavailable right now", false, nil)
+	}
+
+	suggestions := make([]todo.SubtaskSuggestion, 0, len(subtasks))
+	for _, subtask := range subtasks {
+		suggestions = append(suggestions, todo.SubtaskSuggestion{Title: subtask.Title, Description: subtask.Description})
+	}
+
+	trackEvent(ctx, s.server, userID, analytics.EventSubtasksSuggested, analytics.Properties{
+		"provider":         assistant.Name(),
+		"suggestion_count": len(suggestions),
+	})
+
+	return &todo.SubtaskSuggestions{
+		TodoID:      todoItem.ID,
+		Provider:    assistant.Name(),
+		Model:       assistant.Model(),
+		Suggestions: suggestions,
+	}, nil
+}
+
+// GetTodoVersions lists the kept snapshots of a todo, newest first
+func (s *TodoService) GetTodoVersions(ctx echo.Context, userID string, todoID uuid.UUID) ([]todo.TodoVersion, error) {
+	logger := middleware.GetLogger(ctx)
+
+	// Verify todo exists and belongs to user
+	_, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, todoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return nil, err
+	}
+
+	versions, err := s.todoRepo.GetTodoVersions(ctx.Request().Context(), userID, todoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch todo versions")
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// RestoreTodoVersion undoes edits by putting a snapshot back. With expectedVersion the restore
+// only goes through while the todo is still at that version.
+func (s *TodoService) RestoreTodoVersion(ctx echo.Context, userID string, payload *todo.RestoreTodoVersionPayload,
+	expectedVersion *int,
+) (*todo.Todo, error) {
+	logger := middleware.GetLogger(ctx)
+
+	current, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, payload.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return nil, err
+	}
+
+	if expectedVersion != nil && current.Version != *expectedVersion {
+		code := errs.CodeTodoVersionConflict
+		return nil, errs.NewConflictError("todo was modified since the given version", false, &code)
+	}
+
+	restoredTodo, err := s.todoRepo.RestoreTodoVersion(ctx.Request().Context(), userID, payload.ID, payload.Version,
+		expectedVersion)
+	if err != nil {
+		logger.Error().Err(err).Int("version", payload.Version).Msg("failed to restore todo version")
+		return nil, err
+	}
+
+	publishEvents(ctx, s.server, &events.TodoVersionRestored{
+		Actor:           eventActor(ctx, userID),
+		Todo:            restoredTodo,
+		RestoredVersion: payload.Version,
+		PreviousVersion: current.Version,
+	})
+
+	return restoredTodo, nil
+}
+
 func (s *TodoService) DeleteTodo(ctx echo.Context, userID string, todoID uuid.UUID) error {
 	logger := middleware.GetLogger(ctx)
 
-	err := s.todoRepo.DeleteTodo(ctx.Request().Context(), userID, todoID)
+	deletedTodo, err := s.todoRepo.DeleteTodo(ctx.Request().Context(), userID, todoID)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to delete todo")
 		return err
 	}
 
-	// Business event log
-	eventLogger := middleware.GetLogger(ctx)
-	eventLogger.Info().
-		Str("event", "todo_deleted").
-		Str("todo_id", todoID.String()).
-		Msg("Todo deleted successfully")
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionTodoDeleted,
+		EntityType: "todo",
+		EntityID:   todoID.String(),
+		Before:     deletedTodo,
+	})
+
+	publishEvents(ctx, s.server, &events.TodoDeleted{Actor: eventActor(ctx, userID), TodoID: todoID})
 
 	return nil
 }
 
+// MarkTodoRead records the todo as viewed and its comments as read
+func (s *TodoService) MarkTodoRead(ctx echo.Context, userID string, todoID uuid.UUID) (*todo.Todo, error) {
+	logger := middleware.GetLogger(ctx)
+
+	todoItem, err := s.todoRepo.MarkCommentsRead(ctx.Request().Context(), userID, todoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to mark comments as read")
+		return nil, err
+	}
+
+	// Recorded after the comments, whose read time updates the todo
+	if err := s.todoRepo.RecordTodoView(ctx.Request().Context(), userID, todoID); err != nil {
+		logger.Error().Err(err).Msg("failed to record todo view")
+		return nil, err
+	}
+
+	publishEvents(ctx, s.server, &events.TodoRead{Actor: eventActor(ctx, userID), TodoID: todoID})
+
+	return todoItem, nil
+}
+
 func (s *TodoService) GetTodoStats(ctx echo.Context, userID string) (*todo.TodoStats, error) {
 	logger := middleware.GetLogger(ctx)
 
@@ -204,7 +974,8 @@ func (s *TodoService) UploadTodoAttachment(
 	ctx echo.Context,
 	userID string,
 	todoID uuid.UUID,
-	file *multipart.FileHeader,
+	fileName string,
+	file io.Reader,
 ) (*todo.TodoAttachment, error) {
 	logger := middleware.GetLogger(ctx)
 
@@ -215,41 +986,73 @@ func (s *TodoService) UploadTodoAttachment(
 		return nil, err
 	}
 
-	// Open uploaded file
-	src, err := file.Open()
+	// The size is only known once the file is stored, the upload fails as soon as it goes over
+	// the storage quota
+	upload := &countingReader{reader: file}
+	quota := s.server.Config.Attachments.UserQuotaBytes
+	override, err := s.todoRepo.GetUserQuotaBytes(ctx.Request().Context(), userID)
 	if err != nil {
-		logger.Error().Err(err).Msg("failed to open uploaded file")
-		return nil, errs.NewBadRequestError("failed to open uploaded file", false, nil, nil, nil)
+		logger.Error().Err(err).Msg("failed to fetch storage quota")
+		return nil, err
+	}
+	if override != nil {
+		// An admin adjusted the quota of the user, 0 lifts it
+		quota = *override
 	}
-	defer src.Close()
+	if quota > 0 {
+		usedBytes, err := s.todoRepo.GetUserAttachmentBytes(ctx.Request().Context(), userID)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to fetch attachment storage use")
+			return nil, err
+		}
 
-	// Upload to S3
-	s3Key, err := s.awsClient.S3.UploadFile(
-		ctx.Request().Context(),
-		s.server.Config.AWS.S3Bucket,
-		"todos/attachments/"+file.Filename,
-		src,
-	)
-	if err != nil {
-		logger.Error().Err(err).Msg("failed to upload file to S3")
-		return nil, errors.Wrap(err, "failed to upload file")
+		upload.limit = quota - usedBytes
+		if upload.limit <= 0 {
+			logger.Warn().
+				Int64("used_bytes", usedBytes).
+				Int64("quota_bytes", quota).
+				Msg("attachment upload exceeds storage quota")
+			return nil, storageQuotaExceeded()
+		}
 	}
 
-	// Detect MIME type
-	src, err = file.Open()
-	if err != nil {
-		logger.Error().Err(err).Msg("failed to reopen file for MIME detection")
+	// Tell the type from the first bytes, the content type the client sends is not trusted
+	head := make([]byte, sniff.HeadSize)
+	n, err := io.ReadFull(upload, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		if rejected, ok := rejectedUpload(err); ok {
+			logger.Warn().Err(err).Msg("attachment upload rejected")
+			return nil, rejected
+		}
+		logger.Error().Err(err).Msg("failed to read file for MIME detection")
 		return nil, errs.NewBadRequestError("failed to process file", false, nil, nil, nil)
 	}
-	defer src.Close()
+	mimeType := sniff.ContentType(head[:n])
 
-	buffer := make([]byte, 512)
-	_, err = src.Read(buffer)
+	if !sniff.Allowed(mimeType, s.server.Config.Attachments.AllowedContentTypes) {
+		logger.Warn().
+			Str("mime_type", mimeType).
+			Msg("attachment upload of a forbidden type")
+		code := errs.CodeAttachmentTypeForbidden
+		return nil, errs.NewUnsupportedMediaTypeError(fmt.Sprintf("%s files can't be attached", mimeType), false, &code)
+	}
+
+	// Streamed to storage, the sniffed bytes first
+	s3Key, err := s.awsClient.Blobs.UploadFile(
+		ctx.Request().Context(),
+		s.server.Config.AWS.S3Bucket,
+		todo.AttachmentKeyPrefix+fileName,
+		io.MultiReader(bytes.NewReader(head[:n]), upload),
+	)
 	if err != nil {
-		logger.Error().Err(err).Msg("failed to read file for MIME detection")
-		return nil, errs.NewBadRequestError("failed to process file", false, nil, nil, nil)
+		if rejected, ok := rejectedUpload(err); ok {
+			logger.Warn().Err(err).Int64("read_bytes", upload.read).Msg("attachment upload rejected")
+			return nil, rejected
+		}
+		logger.Error().Err(err).Msg("failed to upload file to S3")
+		return nil, errors.Wrap(err, "failed to upload file")
 	}
-	mimeType := http.DetectContentType(buffer)
+	size := upload.read
 
 	// Create attachment record
 	attachment, err := s.todoRepo.UploadTodoAttachment(
@@ -257,9 +1060,10 @@ func (s *TodoService) UploadTodoAttachment(
 		todoID,
 		userID,
 		s3Key,
-		file.Filename,
-		file.Size,
+		fileName,
+		size,
 		mimeType,
+		initialScanStatus(s.server.Config.Scanning),
 	)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to create attachment record")
@@ -271,9 +1075,65 @@ func (s *TodoService) UploadTodoAttachment(
 		Str("s3_key", s3Key).
 		Msg("uploaded todo attachment")
 
+	trackEvent(ctx, s.server, userID, analytics.EventAttachmentUploaded, analytics.Properties{
+		"size_bytes": size,
+		"media_type": analytics.MediaType(mimeType),
+	})
+
+	// Images get their metadata stripped and a thumbnail in the background, until then they are
+	// served as uploaded. Quarantined ones wait for their virus scan.
+	if attachment.IsProcessable() && attachment.IsAvailable() {
+		err := job.EnqueueAttachmentProcessing(ctx.Request().Context(), s.server.Job.Client, &job.AttachmentProcessingTask{
+			TodoID:       todoID,
+			AttachmentID: attachment.ID,
+		})
+		if err != nil {
+			logger.Error().Err(err).Str("attachment_id", attachment.ID.String()).
+				Msg("failed to enqueue attachment processing")
+		}
+	}
+
 	return attachment, nil
 }
 
+// countingReader counts the bytes read from an upload, it fails once more than limit were
+// read when limit is set
+type countingReader struct {
+	reader io.Reader
+	limit  int64
+	read   int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	if r.limit > 0 && r.read > r.limit {
+		return n, storageQuotaExceeded()
+	}
+	return n, err
+}
+
+func storageQuotaExceeded() error {
+	code := errs.CodeStorageQuotaExceeded
+	return errs.NewRequestTooLargeError("the upload would exceed your attachment storage quota", false, &code)
+}
+
+// rejectedUpload tells whether an upload failed because its body was refused while it was
+// read, over the quota, over the body limit or with a second file, and the error to answer
+func rejectedUpload(err error) (error, bool) {
+	var httpErr *errs.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr, true
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return errs.NewRequestTooLargeError("", false, nil), true
+	}
+
+	return nil, false
+}
+
 func (s *TodoService) DeleteTodoAttachment(
 	ctx echo.Context,
 	userID string,
@@ -311,18 +1171,31 @@ func (s *TodoService) DeleteTodoAttachment(
 		return err
 	}
 
+	s.audit.Record(ctx, &audit.Event{
+		Action:     audit.ActionAttachmentDeleted,
+		EntityType: "attachment",
+		EntityID:   attachmentID.String(),
+		Before:     attachment,
+	})
+
 	// Delete from S3 asynchronously
+	keys := []string{attachment.DownloadKey}
+	if attachment.ThumbnailKey != nil {
+		keys = append(keys, *attachment.ThumbnailKey)
+	}
 	go func() {
-		err := s.awsClient.S3.DeleteObject(
-			ctx.Request().Context(),
-			s.server.Config.AWS.S3Bucket,
-			attachment.DownloadKey,
-		)
-		if err != nil {
-			s.server.Logger.Error().
-				Err(err).
-				Str("s3_key", attachment.DownloadKey).
-				Msg("failed to delete attachment from S3")
+		for _, key := range keys {
+			err := s.awsClient.Blobs.DeleteObject(
+				ctx.Request().Context(),
+				s.server.Config.AWS.S3Bucket,
+				key,
+			)
+			if err != nil {
+				s.server.Logger.Error().
+					Err(err).
+					Str("s3_key", key).
+					Msg("failed to delete attachment from S3")
+			}
 		}
 	}()
 
@@ -356,9 +1229,12 @@ func (s *TodoService) GetAttachmentPresignedURL(
 		logger.Error().Err(err).Msg("failed to get attachment details")
 		return "", err
 	}
+	if err := errAttachmentUnavailable(attachment); err != nil {
+		return "", err
+	}
 
 	// Generate presigned URL
-	url, err := s.awsClient.S3.CreatePresignedUrl(
+	url, err := s.awsClient.Blobs.CreatePresignedUrl(
 		ctx.Request().Context(),
 		s.server.Config.AWS.S3Bucket,
 		attachment.DownloadKey,
@@ -370,3 +1246,77 @@ func (s *TodoService) GetAttachmentPresignedURL(
 
 	return url, nil
 }
+
+// GetAttachmentThumbnailURL signs a link to the thumbnail of an image attachment, images get one
+// shortly after they are uploaded
+func (s *TodoService) GetAttachmentThumbnailURL(
+	ctx echo.Context,
+	userID string,
+	todoID uuid.UUID,
+	attachmentID uuid.UUID,
+) (string, error) {
+	logger := middleware.GetLogger(ctx)
+
+	// Verify todo exists and belongs to user
+	_, err := s.todoRepo.CheckTodoExists(ctx.Request().Context(), userID, todoID)
+	if err != nil {
+		logger.Error().Err(err).Msg("todo validation failed")
+		return "", err
+	}
+
+	attachment, err := s.todoRepo.GetTodoAttachment(ctx.Request().Context(), todoID, attachmentID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get attachment details")
+		return "", err
+	}
+	if attachment.ThumbnailKey == nil {
+		code := errs.CodeThumbnailNotFound
+		return "", errs.NewNotFoundError("the attachment has no thumbnail", false, &code)
+	}
+
+	url, err := s.awsClient.Blobs.CreatePresignedUrl(
+		ctx.Request().Context(),
+		s.server.Config.AWS.S3Bucket,
+		*attachment.ThumbnailKey,
+	)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to generate presigned URL")
+		return "", err
+	}
+
+	return url, nil
+}
+
+// sameWorkspace tells whether the todos are in the same workspace, or both outside of one
+func sameWorkspace(a, b *todo.Todo) bool {
+	if a.WorkspaceID == nil || b.WorkspaceID == nil {
+		return a.WorkspaceID == nil && b.WorkspaceID == nil
+	}
+	return *a.WorkspaceID == *b.WorkspaceID
+}
+
+// workspaceWorkflow is the workflow of the workspace, nil when the workspace has none or the
+// todo is outside a workspace. The nil workflow has the built-in scales.
+func workspaceWorkflow(ctx context.Context, workspaceRepo repository.WorkspaceStore,
+	workspaceID *string,
+) (*workspace.Workflow, error) {
+	if workspaceID == nil || *workspaceID == "" {
+		return nil, nil
+	}
+
+	workflow, err := workspaceRepo.GetWorkflow(ctx, *workspaceID)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) && httpErr.Code == errs.CodeWorkflowNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return workflow, nil
+}
+
+func errParentCannotHaveChildren() error {
+	return validation.NewFieldError("parentTodoId", errs.FieldCodeInvalidReference,
+		"parent todo cannot have children (subtasks can't have subtasks)")
+}
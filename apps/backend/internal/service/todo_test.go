@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/model/workspace"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func requireErrorCode(t *testing.T, err error, code string) {
+	t.Helper()
+
+	var httpErr *errs.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, code, httpErr.Code)
+}
+
+func requireStatus(t *testing.T, err error, status int, msgAndArgs ...interface{}) {
+	t.Helper()
+
+	var httpErr *errs.HTTPError
+	require.ErrorAs(t, err, &httpErr, msgAndArgs...)
+	require.Equal(t, status, httpErr.Status, msgAndArgs...)
+}
+
+func TestCreateTodoRejectsNestedParent(t *testing.T) {
+	env := newTestEnv(t)
+
+	parent := env.createTodo(t, testUserID, &todo.CreateTodoPayload{Title: "Parent"})
+	child := env.createTodo(t, testUserID, &todo.CreateTodoPayload{Title: "Child", ParentTodoID: &parent.ID})
+
+	_, err := env.todos.CreateTodo(env.context(testUserID), testUserID, &todo.CreateTodoPayload{
+		Title:        "Grandchild",
+		ParentTodoID: &child.ID,
+	})
+	var httpErr *errs.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, errs.CodeValidationFailed, httpErr.Code)
+	require.Len(t, httpErr.Errors, 1)
+	require.Equal(t, "parentTodoId", httpErr.Errors[0].Field)
+	require.Equal(t, errs.FieldCodeInvalidReference, httpErr.Errors[0].Code)
+
+	// The parent of another user is as good as missing
+	_, err = env.todos.CreateTodo(env.context("user_other"), "user_other", &todo.CreateTodoPayload{
+		Title:        "Stranger",
+		ParentTodoID: &parent.ID,
+	})
+	require.Error(t, err)
+}
+
+func TestUpdateTodoRejectsStaleVersion(t *testing.T) {
+	env := newTestEnv(t)
+	created := env.createTodo(t, testUserID, &todo.CreateTodoPayload{Title: "Plan"})
+
+	title := "Plan the week"
+	updated, err := env.todos.UpdateTodo(env.context(testUserID), testUserID, &todo.UpdateTodoPayload{
+		ID:      created.ID,
+		Title:   &title,
+		Version: &created.Version,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, updated.Version)
+
+	_, err = env.todos.UpdateTodo(env.context(testUserID), testUserID, &todo.UpdateTodoPayload{
+		ID:      created.ID,
+		Title:   &title,
+		Version: &created.Version,
+	})
+	requireErrorCode(t, err, errs.CodeTodoVersionConflict)
+}
+
+func TestCompleteTodos(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	blocker := env.createTodo(t, testUserID, &todo.CreateTodoPayload{Title: "Blocker"})
+	blocked := env.createTodo(t, testUserID, &todo.CreateTodoPayload{Title: "Blocked"})
+	done := env.createTodo(t, testUserID, &todo.CreateTodoPayload{Title: "Done"})
+	_, err := env.repos.Dependency.AddDependency(ctx, testUserID, blocked.ID, blocker.ID)
+	require.NoError(t, err)
+
+	completed := todo.StatusCompleted
+	_, err = env.repos.Todo.UpdateTodo(ctx, testUserID, &todo.UpdateTodoPayload{ID: done.ID, Status: &completed})
+	require.NoError(t, err)
+
+	result, err := env.todos.CompleteTodos(env.context(testUserID), testUserID, &todo.CompleteTodosPayload{
+		IDs: []uuid.UUID{blocked.ID, done.ID},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Completed, 1)
+	require.Equal(t, blocked.ID, result.Completed[0].ID)
+	require.Equal(t, []uuid.UUID{done.ID}, result.AlreadyCompleted)
+	require.Len(t, result.Blocked, 1)
+	require.Equal(t, []uuid.UUID{blocker.ID}, result.Blocked[0].BlockedBy)
+}
+
+func TestCompleteTodosIsAllOrNothing(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	created := env.createTodo(t, testUserID, &todo.CreateTodoPayload{Title: "Kept open"})
+
+	_, err := env.todos.CompleteTodos(env.context(testUserID), testUserID, &todo.CompleteTodosPayload{
+		IDs: []uuid.UUID{created.ID, uuid.New()},
+	})
+	requireErrorCode(t, err, errs.CodeTodoNotFound)
+
+	current, err := env.repos.Todo.CheckTodoExists(ctx, testUserID, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, todo.StatusDraft, current.Status)
+	require.Equal(t, created.Version, current.Version)
+}
+
+func TestUncompleteTodo(t *testing.T) {
+	env := newTestEnv(t)
+	env.server.Config.Todos = &config.TodosConfig{UndoWindow: time.Minute}
+	ctx := context.Background()
+
+	created := env.createTodo(t, testUserID, &todo.CreateTodoPayload{Title: "Oops"})
+	_, err := env.todos.UncompleteTodo(env.context(testUserID), testUserID, created.ID)
+	requireErrorCode(t, err, errs.CodeTodoNotCompleted)
+
+	completed := todo.StatusCompleted
+	_, err = env.repos.Todo.UpdateTodo(ctx, testUserID, &todo.UpdateTodoPayload{ID: created.ID, Status: &completed})
+	require.NoError(t, err)
+
+	reopened, err := env.todos.UncompleteTodo(env.context(testUserID), testUserID, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, todo.StatusActive, reopened.Status)
+	require.Nil(t, reopened.CompletedAt)
+
+	// A completion older than the window stays
+	env.store.SetClock(func() time.Time { return time.Now().Add(-time.Hour) })
+	_, err = env.repos.Todo.UpdateTodo(ctx, testUserID, &todo.UpdateTodoPayload{ID: created.ID, Status: &completed})
+	require.NoError(t, err)
+	env.store.SetClock(time.Now)
+
+	_, err = env.todos.UncompleteTodo(env.context(testUserID), testUserID, created.ID)
+	requireErrorCode(t, err, errs.CodeUndoWindowExpired)
+}
+
+func TestRestoreTodoVersion(t *testing.T) {
+	env := newTestEnv(t)
+	created := env.createTodo(t, testUserID, &todo.CreateTodoPayload{Title: "First"})
+
+	title := "Second"
+	updated, err := env.todos.UpdateTodo(env.context(testUserID), testUserID, &todo.UpdateTodoPayload{
+		ID:    created.ID,
+		Title: &title,
+	})
+	require.NoError(t, err)
+
+	payload := &todo.RestoreTodoVersionPayload{ID: created.ID, Version: 1}
+	_, err = env.todos.RestoreTodoVersion(env.context(testUserID), testUserID, payload, &created.Version)
+	requireErrorCode(t, err, errs.CodeTodoVersionConflict)
+
+	restored, err := env.todos.RestoreTodoVersion(env.context(testUserID), testUserID, payload, &updated.Version)
+	require.NoError(t, err)
+	require.Equal(t, "First", restored.Title)
+	require.Equal(t, 3, restored.Version)
+
+	versions, err := env.todos.GetTodoVersions(env.context(testUserID), testUserID, created.ID)
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+}
+
+func TestDeleteTodoRecordsAudit(t *testing.T) {
+	env := newTestEnv(t)
+	created := env.createTodo(t, testUserID, &todo.CreateTodoPayload{Title: "Temporary"})
+
+	require.NoError(t, env.todos.DeleteTodo(env.context(testUserID), testUserID, created.ID))
+
+	_, err := env.repos.Todo.CheckTodoExists(context.Background(), testUserID, created.ID)
+	require.Error(t, err)
+
+	query := &audit.GetAuditLogQuery{}
+	require.NoError(t, query.Validate())
+	entries, err := env.repos.Audit.GetEntries(context.Background(), query)
+	require.NoError(t, err)
+	require.Len(t, entries.Data, 1)
+
+	entry := entries.Data[0]
+	require.Equal(t, audit.ActionTodoDeleted, entry.Action)
+	require.Equal(t, testUserID, entry.ActorID)
+	require.NotNil(t, entry.EntityID)
+	require.Equal(t, created.ID.String(), *entry.EntityID)
+	require.Contains(t, string(entry.Before), "Temporary")
+}
+
+func TestTodosFollowWorkspaceScales(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	member := env.workspaceContext(testUserID, testWorkspaceID)
+
+	setWorkflow := &workspace.SetWorkflowPayload{
+		WorkspaceID: testWorkspaceID,
+		Statuses: []workspace.WorkflowStatus{
+			{Key: "backlog", Name: "Backlog", Color: "#9ca3af", Category: todo.StatusDraft},
+			{Key: "doing", Name: "Doing", Color: "#3b82f6", Category: todo.StatusActive},
+			{Key: "review", Name: "Review", Color: "#a855f7", Category: todo.StatusActive},
+			{Key: "shipped", Name: "Shipped", Color: "#22c55e", Category: todo.StatusCompleted},
+			{Key: "shelved", Name: "Shelved", Color: "#6b7280", Category: todo.StatusArchived},
+		},
+		Priorities: []workspace.WorkflowPriority{
+			{Key: "p3", Name: "P3", Color: "#22c55e"},
+			{Key: "p2", Name: "P2", Color: "#eab308"},
+			{Key: "p1", Name: "P1", Color: "#f97316"},
+			{Key: "p0", Name: "P0", Color: "#ef4444"},
+		},
+	}
+	require.NoError(t, setWorkflow.Validate())
+	workflow, err := env.repos.Workspace.SetWorkflow(ctx, testAdminID, setWorkflow)
+	require.NoError(t, err)
+
+	// New todos start in the first draft status at the middle level
+	created, err := env.todos.CreateTodo(member, testUserID, &todo.CreateTodoPayload{Title: "Ship it"})
+	require.NoError(t, err)
+	require.Equal(t, todo.Status("backlog"), created.Status)
+	require.Equal(t, todo.StatusDraft, created.StatusCategory)
+	require.Equal(t, todo.Priority("p2"), created.Priority)
+	require.Equal(t, testWorkspaceID, *created.WorkspaceID)
+
+	// Personal todos keep the built-in scales
+	personal, err := env.todos.CreateTodo(env.context(testUserID), testUserID, &todo.CreateTodoPayload{Title: "Groceries"})
+	require.NoError(t, err)
+	require.Nil(t, personal.WorkspaceID)
+	require.Equal(t, todo.StatusDraft, personal.Status)
+	require.Equal(t, todo.PriorityMedium, personal.Priority)
+
+	unknown := todo.Status("done")
+	_, err = env.todos.UpdateTodo(member, testUserID, &todo.UpdateTodoPayload{
+		ID:     created.ID,
+		Status: &unknown,
+	})
+	requireErrorCode(t, err, errs.CodeValidationFailed)
+
+	// A completed status completes the todo, a built-in one stands for the first of its category
+	shipped, urgent := todo.Status("shipped"), todo.Priority("p0")
+	updated, err := env.todos.UpdateTodo(member, testUserID, &todo.UpdateTodoPayload{
+		ID:       created.ID,
+		Status:   &shipped,
+		Priority: &urgent,
+	})
+	require.NoError(t, err)
+	require.Equal(t, todo.StatusCompleted, updated.StatusCategory)
+	require.NotNil(t, updated.CompletedAt)
+	require.Equal(t, 3, updated.PriorityLevel)
+
+	for filter, want := range map[string]int{"shipped": 1, "completed": 1, "doing,review": 0} {
+		query := &todo.GetTodosQuery{Status: &filter}
+		require.NoError(t, query.Validate())
+		page, err := env.repos.Todo.GetTodos(ctx, testUserID, query)
+		require.NoError(t, err)
+		require.Len(t, page.Data, want, filter)
+	}
+
+	stats, err := env.repos.Todo.GetTodoStats(ctx, testUserID)
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.Completed)
+
+	// Back on the built-in scales the todo keeps its category and the level it spreads onto
+	_, err = env.repos.Workspace.DeleteWorkflow(ctx, testWorkspaceID)
+	require.NoError(t, err)
+	moved, err := env.repos.Todo.ApplyWorkflow(ctx, testWorkspaceID, workflow.CategoryMapping(), workflow.LevelMapping())
+	require.NoError(t, err)
+	require.Equal(t, 1, moved)
+
+	current, err := env.repos.Todo.CheckTodoExists(ctx, testUserID, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, todo.StatusCompleted, current.Status)
+	require.Equal(t, todo.PriorityHigh, current.Priority)
+	require.Equal(t, updated.CompletedAt, current.CompletedAt)
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/lib/transcribe"
+	"github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// transcriptDescriptionLimit is the longest description the todo payloads accept, the transcript
+// appended to it is cut to fit
+const transcriptDescriptionLimit = 1000
+
+type TranscriptionService struct {
+	server    *server.Server
+	todoRepo  repository.TodoStore
+	txManager repository.TxManager
+	// provider is nil when transcription is disabled
+	provider transcribe.Provider
+}
+
+func NewTranscriptionService(server *server.Server, todoRepo repository.TodoStore, txManager repository.TxManager,
+	provider transcribe.Provider,
+) *TranscriptionService {
+	return &TranscriptionService{
+		server:    server,
+		todoRepo:  todoRepo,
+		txManager: txManager,
+		provider:  provider,
+	}
+}
+
+// QueueTranscription marks a voice note as pending and enqueues its transcription. Other
+// attachments, every attachment while transcription is disabled and those quarantined until
+// their virus scan are returned as is.
+func (s *TranscriptionService) QueueTranscription(ctx echo.Context, userID string,
+	attachment *todo.TodoAttachment,
+) *todo.TodoAttachment {
+	return s.queueTranscription(ctx.Request().Context(), middleware.GetLogger(ctx), userID, attachment)
+}
+
+// QueueScannedTranscription queues the transcription of a voice note its virus scan cleared
+func (s *TranscriptionService) QueueScannedTranscription(ctx context.Context, userID string,
+	attachment *todo.TodoAttachment,
+) *todo.TodoAttachment {
+	log := logger.WithSpanContext(*s.server.Logger, ctx)
+	return s.queueTranscription(ctx, &log, userID, attachment)
+}
+
+func (s *TranscriptionService) queueTranscription(ctx context.Context, log *zerolog.Logger, userID string,
+	attachment *todo.TodoAttachment,
+) *todo.TodoAttachment {
+	if s.provider == nil || !attachment.IsAvailable() {
+		return attachment
+	}
+	if _, ok := transcribe.MediaFormat(attachment.Name); !ok {
+		return attachment
+	}
+
+	pending, err := s.todoRepo.SetAttachmentTranscription(ctx, attachment.TodoID, attachment.ID,
+		todo.TranscriptionPending, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to mark attachment transcription as pending")
+		return attachment
+	}
+
+	err = job.EnqueueAttachmentTranscription(ctx, s.server.Job.Client, &job.AttachmentTranscriptionTask{
+		UserID:       userID,
+		TodoID:       attachment.TodoID,
+		AttachmentID: attachment.ID,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to enqueue attachment transcription")
+		if failed, failErr := s.todoRepo.SetAttachmentTranscription(ctx, attachment.TodoID, attachment.ID,
+			todo.TranscriptionFailed, nil); failErr == nil {
+			return failed
+		}
+		return attachment
+	}
+
+	log.Info().
+		Str("event", "attachment_transcription_queued").
+		Str("attachment_id", attachment.ID.String()).
+		Msg("Attachment transcription queued")
+
+	return pending
+}
+
+// TranscribeAttachment transcribes a pending voice note, then stores the transcript on the
+// attachment and appends it to the description of its todo so search finds it
+func (s *TranscriptionService) TranscribeAttachment(ctx context.Context, userID string,
+	todoID, attachmentID uuid.UUID,
+) error {
+	log := logger.WithSpanContext(*s.server.Logger, ctx).With().
+		Str("user_id", userID).
+		Str("attachment_id", attachmentID.String()).
+		Logger()
+
+	if s.provider == nil {
+		return errors.New("transcription is not enabled on this server")
+	}
+
+	attachment, err := s.todoRepo.GetTodoAttachment(ctx, todoID, attachmentID)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) && httpErr.Code == errs.CodeAttachmentNotFound {
+			// Deleted before it got transcribed
+			return nil
+		}
+		return err
+	}
+	if attachment.TranscriptionStatus == nil || *attachment.TranscriptionStatus != todo.TranscriptionPending {
+		return nil
+	}
+
+	format, _ := transcribe.MediaFormat(attachment.Name)
+	transcript, err := s.provider.Transcribe(ctx, transcribe.Audio{
+		ID:           attachment.ID.String(),
+		Bucket:       s.server.Config.AWS.S3Bucket,
+		Key:          attachment.DownloadKey,
+		Format:       format,
+		LanguageCode: s.server.Config.Transcription.LanguageCode,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		todoItem, err := s.todoRepo.CheckTodoExists(ctx, userID, todoID)
+		if err != nil {
+			return err
+		}
+
+		if transcript != "" {
+			description := appendTranscript(todoItem.Description, attachment.Name, transcript)
+			if description != todoItem.Description {
+				if _, err := s.todoRepo.UpdateTodo(ctx, userID, &todo.UpdateTodoPayload{
+					ID:          todoID,
+					Description: &description,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+
+		_, err = s.todoRepo.SetAttachmentTranscription(ctx, todoID, attachmentID, todo.TranscriptionCompleted,
+			&transcript)
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The todo went away while the provider was working
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("event", "attachment_transcribed").
+		Str("provider", s.provider.Name()).
+		Int("transcript_length", len(transcript)).
+		Msg("Attachment transcribed")
+
+	return nil
+}
+
+func (s *TranscriptionService) FailTranscription(ctx context.Context, todoID, attachmentID uuid.UUID) error {
+	_, err := s.todoRepo.SetAttachmentTranscription(ctx, todoID, attachmentID, todo.TranscriptionFailed, nil)
+	return err
+}
+
+// appendTranscript adds the transcript of the voice note below the description, cut short when
+// the description would get longer than the payloads allow
+func appendTranscript(description, name, transcript string) string {
+	addition := "🎙 " + name + ": " + transcript
+	if description != "" {
+		addition = "\n\n" + addition
+	}
+
+	room := transcriptDescriptionLimit - len([]rune(description))
+	runes := []rune(addition)
+	if len(runes) <= room {
+		return description + addition
+	}
+	// Not even the name fits, the attachment keeps the transcript
+	if room <= len([]rune(name))+len("\n\n🎙 : …") {
+		return description
+	}
+
+	return description + strings.TrimRight(string(runes[:room-1]), " ") + "…"
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/labstack/echo/v4"
+)
+
+// withinTx runs fn as one unit of work. While fn runs, the request context of ctx carries the
+// transaction, so every store a service calls with it takes part.
+func withinTx(ctx echo.Context, txManager repository.TxManager, fn func() error) error {
+	req := ctx.Request()
+	defer ctx.SetRequest(req)
+
+	return txManager.WithinTx(req.Context(), func(txCtx context.Context) error {
+		ctx.SetRequest(req.WithContext(txCtx))
+		return fn()
+	})
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/usage"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+type UsageService struct {
+	server    *server.Server
+	usageRepo repository.UsageStore
+}
+
+func NewUsageService(server *server.Server, usageRepo repository.UsageStore) *UsageService {
+	return &UsageService{
+		server:    server,
+		usageRepo: usageRepo,
+	}
+}
+
+// GetUsage reports the metered usage of the user, or of their active workspace
+func (s *UsageService) GetUsage(ctx echo.Context, userID, workspaceID string, query *usage.GetUsageQuery) (*usage.Usage, error) {
+	logger := middleware.GetLogger(ctx)
+
+	subjectID := userID
+	if *query.Scope == usage.SubjectWorkspace {
+		if workspaceID == "" {
+			code := errs.CodeWorkspaceRequired
+			return nil, errs.NewBadRequestError("workspace usage needs an active workspace", false, &code, nil, nil)
+		}
+		subjectID = workspaceID
+	}
+
+	// Buckets are whole hours, a range starting mid-hour includes that hour
+	from := query.From.UTC().Truncate(time.Hour)
+	to := query.To.UTC()
+
+	buckets, err := s.usageRepo.GetBuckets(ctx.Request().Context(), *query.Scope, subjectID, from, to, *query.Granularity)
+	if err != nil {
+		logger.Error().Err(err).Str("scope", string(*query.Scope)).Msg("failed to fetch usage")
+		return nil, err
+	}
+
+	result := &usage.Usage{
+		SubjectType: *query.Scope,
+		SubjectID:   subjectID,
+		From:        from,
+		To:          to,
+		Granularity: *query.Granularity,
+		Buckets:     buckets,
+	}
+	for _, bucket := range buckets {
+		result.Totals.APICalls += bucket.APICalls
+		result.Totals.Notifications += bucket.Notifications
+		// Buckets are oldest first, the last snapshot wins
+		if bucket.StorageBytes != nil {
+			result.Totals.StorageBytes = *bucket.StorageBytes
+		}
+	}
+
+	return result, nil
+}
+
+// GetAPIUsage summarizes the requests, the rate limit headroom and the notification deliveries of
+// the user over the last 30 days of completed hours
+func (s *UsageService) GetAPIUsage(ctx echo.Context, userID string) (*usage.APIUsage, error) {
+	logger := middleware.GetLogger(ctx)
+
+	to := time.Now().UTC().Truncate(time.Hour)
+	from := to.Add(-usage.APIUsageWindow)
+
+	// Hourly buckets, the daily ones are summed from them so the peak hour comes from the same read
+	buckets, err := s.usageRepo.GetBuckets(ctx.Request().Context(), usage.SubjectUser, userID, from, to,
+		usage.GranularityHour)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch api usage")
+		return nil, err
+	}
+
+	limit := config.DefaultRateLimitConfig()
+	if cfg := s.server.CurrentConfig().Server.RateLimit; cfg != nil {
+		limit = cfg
+	}
+
+	result := &usage.APIUsage{
+		From: from,
+		To:   to,
+		Requests: usage.RequestStats{
+			Daily: []usage.DailyCount{},
+		},
+		RateLimit: usage.RateLimitStats{
+			RequestsPerSecond: limit.RequestsPerSecond,
+			Burst:             limit.Burst,
+			HourlyAllowance:   int64(limit.RequestsPerSecond * time.Hour.Seconds()),
+		},
+	}
+
+	for _, bucket := range buckets {
+		result.Requests.Total += bucket.APICalls
+		result.Notifications.Delivered += bucket.Notifications
+
+		if bucket.APICalls > result.RateLimit.PeakHourRequests {
+			peakHour := bucket.Start
+			result.RateLimit.PeakHour = &peakHour
+			result.RateLimit.PeakHourRequests = bucket.APICalls
+		}
+
+		if bucket.APICalls == 0 {
+			continue
+		}
+		day := bucket.Start.Truncate(24 * time.Hour)
+		if n := len(result.Requests.Daily); n > 0 && result.Requests.Daily[n-1].Day.Equal(day) {
+			result.Requests.Daily[n-1].Requests += bucket.APICalls
+		} else {
+			result.Requests.Daily = append(result.Requests.Daily, usage.DailyCount{Day: day, Requests: bucket.APICalls})
+		}
+	}
+
+	for _, daily := range result.Requests.Daily {
+		if daily.Requests > result.Requests.PeakDayRequests {
+			peakDay := daily.Day
+			result.Requests.PeakDay = &peakDay
+			result.Requests.PeakDayRequests = daily.Requests
+		}
+	}
+
+	days := usage.APIUsageWindow.Hours() / 24
+	result.Requests.DailyAverage = roundTo2(float64(result.Requests.Total) / days)
+	result.Notifications.DailyAverage = roundTo2(float64(result.Notifications.Delivered) / days)
+	if result.RateLimit.HourlyAllowance > 0 {
+		result.RateLimit.PeakHourUtilization = roundTo2(
+			float64(result.RateLimit.PeakHourRequests) / float64(result.RateLimit.HourlyAllowance))
+	}
+
+	return result, nil
+}
+
+func roundTo2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
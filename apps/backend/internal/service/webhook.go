@@ -0,0 +1,507 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/events"
+	"github.com/Sameer16536/ExecuTask/internal/lib/deliver"
+	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/lib/openapi"
+	"github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/model/comment"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/model/webhook"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// webhookSecretPrefix starts every signing secret, so leaked ones are easy to recognize
+	webhookSecretPrefix = "whsec_"
+	webhookSecretBytes  = 32
+	// workspaceAdminRole is the Clerk organization role allowed to manage a workspace: its
+	// webhooks, templates and other settings
+	workspaceAdminRole = "org:admin"
+)
+
+// webhookEvent describes an event type of the catalog, sample builds the data of its test
+// deliveries
+type webhookEvent struct {
+	event       string
+	description string
+	payload     interface{}
+	sample      func(workspaceID string, now time.Time) interface{}
+}
+
+var webhookEvents = []webhookEvent{
+	{
+		event:       webhook.EventTodoCreated,
+		description: "A todo was created",
+		payload:     webhook.Payload[webhook.TodoData]{},
+		sample:      sampleTodoData,
+	},
+	{
+		event:       webhook.EventTodoUpdated,
+		description: "A todo was updated, completions included",
+		payload:     webhook.Payload[webhook.TodoData]{},
+		sample:      sampleTodoData,
+	},
+	{
+		event:       webhook.EventTodoCompleted,
+		description: "A todo was completed, on its own or with others",
+		payload:     webhook.Payload[webhook.TodoData]{},
+		sample:      sampleTodoData,
+	},
+	{
+		event:       webhook.EventTodoUncompleted,
+		description: "The completion of a todo was undone",
+		payload:     webhook.Payload[webhook.TodoData]{},
+		sample:      sampleTodoData,
+	},
+	{
+		event:       webhook.EventTodoDeleted,
+		description: "A todo was deleted",
+		payload:     webhook.Payload[webhook.TodoDeletedData]{},
+		sample: func(string, time.Time) interface{} {
+			return webhook.TodoDeletedData{TodoID: uuid.New()}
+		},
+	},
+	{
+		event:       webhook.EventCommentAdded,
+		description: "A comment was added to a todo",
+		payload:     webhook.Payload[webhook.CommentData]{},
+		sample:      sampleCommentData,
+	},
+	{
+		event:       webhook.EventCommentUpdated,
+		description: "A comment was edited",
+		payload:     webhook.Payload[webhook.CommentData]{},
+		sample:      sampleCommentData,
+	},
+	{
+		event:       webhook.EventCommentDeleted,
+		description: "A comment was deleted",
+		payload:     webhook.Payload[webhook.CommentDeletedData]{},
+		sample: func(string, time.Time) interface{} {
+			return webhook.CommentDeletedData{CommentID: uuid.New()}
+		},
+	},
+}
+
+type WebhookService struct {
+	server       *server.Server
+	webhookRepo  repository.WebhookStore
+	auditService *AuditService
+	client       *deliver.Client
+
+	catalogOnce sync.Once
+	catalog     []webhook.EventType
+}
+
+func NewWebhookService(server *server.Server, webhookRepo repository.WebhookStore,
+	auditService *AuditService,
+) *WebhookService {
+	outboundCfg := server.Config.Outbound
+	if outboundCfg == nil {
+		outboundCfg = config.DefaultOutboundConfig()
+	}
+
+	return &WebhookService{
+		server:       server,
+		webhookRepo:  webhookRepo,
+		auditService: auditService,
+		client:       deliver.New(outboundCfg, webhooksConfig(server.Config)),
+	}
+}
+
+// GetEventTypes is the catalog of the events endpoints can subscribe to, with the JSON Schema
+// of their payloads
+func (s *WebhookService) GetEventTypes(_ echo.Context, _ *webhook.GetEventTypesPayload) []webhook.EventType {
+	s.catalogOnce.Do(func() {
+		s.catalog = make([]webhook.EventType, 0, len(webhookEvents))
+		for _, event := range webhookEvents {
+			s.catalog = append(s.catalog, webhook.EventType{
+				Type:        event.event,
+				Description: event.description,
+				Schema:      openapi.SchemaOf(event.payload),
+			})
+		}
+	})
+
+	return s.catalog
+}
+
+func (s *WebhookService) GetEndpoints(ctx echo.Context, _ *webhook.GetEndpointsPayload) ([]webhook.Endpoint, error) {
+	logger := middleware.GetLogger(ctx)
+
+	workspaceID, err := managedWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints, err := s.webhookRepo.GetEndpoints(ctx.Request().Context(), workspaceID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch webhook endpoints")
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+// CreateEndpoint registers an endpoint of the active workspace. The secret its deliveries are
+// signed with is in the response only.
+func (s *WebhookService) CreateEndpoint(ctx echo.Context, userID string,
+	payload *webhook.CreateEndpointPayload,
+) (*webhook.CreatedEndpoint, error) {
+	logger := middleware.GetLogger(ctx)
+
+	workspaceID, err := managedWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := webhooksConfig(s.server.CurrentConfig())
+	count, err := s.webhookRepo.CountEndpoints(ctx.Request().Context(), workspaceID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to count webhook endpoints")
+		return nil, err
+	}
+	if count >= cfg.MaxEndpointsPerWorkspace {
+		code := errs.CodeWebhookLimitReached
+		return nil, errs.NewConflictError(
+			fmt.Sprintf("a workspace can have at most %d webhook endpoints", cfg.MaxEndpointsPerWorkspace), false, &code)
+	}
+
+	random := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(random); err != nil {
+		logger.Error().Err(err).Msg("failed to generate webhook secret")
+		return nil, err
+	}
+	secret := webhookSecretPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	endpoint, err := s.webhookRepo.CreateEndpoint(ctx.Request().Context(), workspaceID, userID, secret, payload)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create webhook endpoint")
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionWebhookChanged,
+		EntityType: "webhook_endpoint",
+		EntityID:   endpoint.ID.String(),
+		After:      endpoint,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "webhook_endpoint_created").
+		Str("webhook_endpoint_id", endpoint.ID.String()).
+		Str("workspace_id", workspaceID).
+		Strs("events", endpoint.Events).
+		Msg("Webhook endpoint created successfully")
+
+	return &webhook.CreatedEndpoint{Endpoint: *endpoint, Secret: endpoint.Secret}, nil
+}
+
+func (s *WebhookService) UpdateEndpoint(ctx echo.Context, payload *webhook.UpdateEndpointPayload,
+) (*webhook.Endpoint, error) {
+	logger := middleware.GetLogger(ctx)
+
+	workspaceID, err := managedWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := s.webhookRepo.UpdateEndpoint(ctx.Request().Context(), workspaceID, payload)
+	if err != nil {
+		logger.Error().Err(err).Str("webhook_endpoint_id", payload.ID.String()).Msg("failed to update webhook endpoint")
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionWebhookChanged,
+		EntityType: "webhook_endpoint",
+		EntityID:   endpoint.ID.String(),
+		After:      endpoint,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "webhook_endpoint_updated").
+		Str("webhook_endpoint_id", endpoint.ID.String()).
+		Bool("enabled", endpoint.Enabled).
+		Strs("events", endpoint.Events).
+		Msg("Webhook endpoint updated successfully")
+
+	return endpoint, nil
+}
+
+// DeleteEndpoint removes an endpoint, its queued deliveries are dropped when they run
+func (s *WebhookService) DeleteEndpoint(ctx echo.Context, payload *webhook.DeleteEndpointPayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	workspaceID, err := managedWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := s.webhookRepo.DeleteEndpoint(ctx.Request().Context(), workspaceID, payload.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("webhook_endpoint_id", payload.ID.String()).Msg("failed to delete webhook endpoint")
+		return err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionWebhookChanged,
+		EntityType: "webhook_endpoint",
+		EntityID:   endpoint.ID.String(),
+		Before:     endpoint,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "webhook_endpoint_deleted").
+		Str("webhook_endpoint_id", endpoint.ID.String()).
+		Msg("Webhook endpoint deleted successfully")
+
+	return nil
+}
+
+// TestEndpoint posts a signed sample payload to the endpoint and reports how it answered. It
+// is sent whether the endpoint is enabled or not, so it can be checked before it is.
+func (s *WebhookService) TestEndpoint(ctx echo.Context, payload *webhook.TestEndpointPayload,
+) (*webhook.TestDelivery, error) {
+	logger := middleware.GetLogger(ctx)
+
+	workspaceID, err := managedWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := s.webhookRepo.GetEndpoint(ctx.Request().Context(), workspaceID, payload.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := endpoint.Events[0]
+	if payload.Event != nil {
+		event = *payload.Event
+	}
+
+	now := time.Now().UTC()
+	deliveryID := uuid.New()
+	var data interface{}
+	for _, catalogued := range webhookEvents {
+		if catalogued.event == event {
+			data = catalogued.sample(workspaceID, now)
+		}
+	}
+
+	body, err := json.Marshal(webhook.Payload[interface{}]{
+		ID:          deliveryID,
+		Type:        event,
+		WorkspaceID: workspaceID,
+		CreatedAt:   now,
+		Test:        true,
+		Data:        data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &webhook.TestDelivery{DeliveryID: deliveryID, Event: event}
+	resp, err := s.client.Send(ctx.Request().Context(), endpoint.URL, endpoint.Secret, event, deliveryID, body)
+	if err != nil {
+		message := err.Error()
+		result.Error = &message
+	} else {
+		result.Success = resp.OK()
+		result.StatusCode = &resp.StatusCode
+		result.DurationMs = resp.Duration.Milliseconds()
+		result.ResponseBody = resp.Body
+	}
+
+	// Business event log
+	logger.Info().
+		Str("event", "webhook_endpoint_tested").
+		Str("webhook_endpoint_id", endpoint.ID.String()).
+		Str("webhook_event", event).
+		Bool("success", result.Success).
+		Msg("Webhook endpoint tested")
+
+	return result, nil
+}
+
+// queueEventDeliveries queues a delivery of the event to every endpoint of its workspace
+// subscribed to it. Changes of the personal space have no endpoints to go to.
+func (s *WebhookService) queueEventDeliveries(ctx context.Context, event events.Event) error {
+	workspaceID := event.Workspace()
+	if workspaceID == "" {
+		return nil
+	}
+
+	switch e := event.(type) {
+	case *events.TodoCreated:
+		return s.queueDeliveries(ctx, workspaceID, webhook.EventTodoCreated, webhook.TodoData{Todo: *e.Todo})
+	case *events.TodoUpdated:
+		return s.queueDeliveries(ctx, workspaceID, webhook.EventTodoUpdated, webhook.TodoData{Todo: *e.Todo})
+	case *events.TodoCompleted:
+		return s.queueDeliveries(ctx, workspaceID, webhook.EventTodoCompleted, webhook.TodoData{Todo: *e.Todo})
+	case *events.TodosCompleted:
+		for _, completed := range e.Todos {
+			if err := s.queueDeliveries(ctx, workspaceID, webhook.EventTodoCompleted,
+				webhook.TodoData{Todo: completed}); err != nil {
+				return err
+			}
+		}
+	case *events.TodoUncompleted:
+		return s.queueDeliveries(ctx, workspaceID, webhook.EventTodoUncompleted, webhook.TodoData{Todo: *e.Todo})
+	case *events.TodoDeleted:
+		return s.queueDeliveries(ctx, workspaceID, webhook.EventTodoDeleted, webhook.TodoDeletedData{TodoID: e.TodoID})
+	case *events.CommentAdded:
+		return s.queueDeliveries(ctx, workspaceID, webhook.EventCommentAdded,
+			webhook.CommentData{Comment: *e.Comment})
+	case *events.CommentUpdated:
+		return s.queueDeliveries(ctx, workspaceID, webhook.EventCommentUpdated,
+			webhook.CommentData{Comment: *e.Comment})
+	case *events.CommentDeleted:
+		return s.queueDeliveries(ctx, workspaceID, webhook.EventCommentDeleted,
+			webhook.CommentDeletedData{CommentID: e.CommentID})
+	}
+	return nil
+}
+
+// queueDeliveries enqueues one delivery per subscribed endpoint, each with its own ID
+func (s *WebhookService) queueDeliveries(ctx context.Context, workspaceID, event string, data interface{}) error {
+	endpoints, err := s.webhookRepo.GetSubscribedEndpoints(ctx, workspaceID, event)
+	if err != nil || len(endpoints) == 0 {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, endpoint := range endpoints {
+		deliveryID := uuid.New()
+		body, err := json.Marshal(webhook.Payload[interface{}]{
+			ID:          deliveryID,
+			Type:        event,
+			WorkspaceID: workspaceID,
+			CreatedAt:   now,
+			Data:        data,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := job.EnqueueWebhookDelivery(ctx, s.server.Job.Client, &job.WebhookDeliveryTask{
+			WorkspaceID: workspaceID,
+			EndpointID:  endpoint.ID,
+			Event:       event,
+			DeliveryID:  deliveryID,
+			Payload:     body,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeliverWebhook posts a queued delivery. An endpoint deleted, disabled or unsubscribed since
+// the delivery was queued drops it, one that doesn't accept it fails the task to retry it.
+func (s *WebhookService) DeliverWebhook(ctx context.Context, task *job.WebhookDeliveryTask) error {
+	log := logger.WithSpanContext(*s.server.Logger, ctx)
+
+	endpoint, err := s.webhookRepo.GetEndpoint(ctx, task.WorkspaceID, task.EndpointID)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) && httpErr.Code == errs.CodeWebhookNotFound {
+			return nil
+		}
+		return err
+	}
+	if !endpoint.Subscribes(task.Event) {
+		return nil
+	}
+
+	resp, err := s.client.Send(ctx, endpoint.URL, endpoint.Secret, task.Event, task.DeliveryID, task.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to reach webhook endpoint_id=%s: %w", endpoint.ID, err)
+	}
+	if !resp.OK() {
+		return fmt.Errorf("webhook endpoint_id=%s answered with status %d", endpoint.ID, resp.StatusCode)
+	}
+
+	log.Info().
+		Str("event", "webhook_delivered").
+		Str("webhook_endpoint_id", endpoint.ID.String()).
+		Str("webhook_event", task.Event).
+		Str("delivery_id", task.DeliveryID.String()).
+		Dur("duration", resp.Duration).
+		Msg("Webhook delivered")
+
+	return nil
+}
+
+// managedWorkspace is the active workspace of a request allowed to manage its webhooks, which
+// only its admins are
+func managedWorkspace(ctx echo.Context) (string, error) {
+	workspaceID, err := activeWorkspace(ctx)
+	if err != nil {
+		return "", err
+	}
+	if middleware.GetUserRole(ctx) != workspaceAdminRole {
+		return "", errs.NewForbiddenError("only the admins of a workspace manage its webhooks", false)
+	}
+	return workspaceID, nil
+}
+
+// webhooksConfig falls back on the defaults for configs that leave the webhooks out
+func webhooksConfig(cfg *config.Config) *config.WebhooksConfig {
+	if cfg.Webhooks == nil {
+		return config.DefaultWebhooksConfig()
+	}
+	return cfg.Webhooks
+}
+
+func sampleTodoData(workspaceID string, now time.Time) interface{} {
+	sample := todo.Todo{
+		UserID:         "user_sample",
+		WorkspaceID:    &workspaceID,
+		Title:          "Review the quarterly report",
+		Description:    "Check the numbers before Friday",
+		Priority:       todo.PriorityHigh,
+		PriorityLevel:  2,
+		Status:         todo.StatusActive,
+		StatusCategory: todo.StatusActive,
+		Version:        1,
+	}
+	sample.ID = uuid.New()
+	sample.CreatedAt = now
+	sample.UpdatedAt = now
+	return webhook.TodoData{Todo: sample}
+}
+
+func sampleCommentData(_ string, now time.Time) interface{} {
+	sample := comment.Comment{
+		TodoID:  uuid.New(),
+		UserID:  "user_sample",
+		Content: "Looks good to me",
+	}
+	sample.ID = uuid.New()
+	sample.CreatedAt = now
+	sample.UpdatedAt = now
+	return webhook.CommentData{Comment: sample}
+}
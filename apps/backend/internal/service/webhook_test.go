@@ -0,0 +1,129 @@
+package service
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/config"
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/events"
+	"github.com/Sameer16536/ExecuTask/internal/lib/deliver"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/webhook"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/repository/memory"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookEnv runs the webhook service against the in-memory fakes of the repositories
+type webhookEnv struct {
+	server *server.Server
+	repos  *repository.Repositories
+	audit  *AuditService
+}
+
+func newWebhookEnv(t *testing.T) *webhookEnv {
+	t.Helper()
+
+	logger := zerolog.Nop()
+	s := &server.Server{
+		Config: &config.Config{},
+		Logger: &logger,
+		Events: events.NewBus(),
+	}
+	repos := memory.NewRepositories(memory.NewStore())
+
+	return &webhookEnv{
+		server: s,
+		repos:  repos,
+		audit:  NewAuditService(s, repos.Audit, repos.Tx),
+	}
+}
+
+// context is a request of a user of the test workspace with the role
+func (e *webhookEnv) context(role string) echo.Context {
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+	c.Set(middleware.UserIDKey, testAdminID)
+	c.Set(middleware.WorkspaceIDKey, testWorkspaceID)
+	c.Set(middleware.UserRoleKey, role)
+	return c
+}
+
+func TestWebhookTestDeliveryIsSigned(t *testing.T) {
+	env := newWebhookEnv(t)
+	env.server.Config.Outbound = config.DefaultOutboundConfig()
+	env.server.Config.Webhooks = config.DefaultWebhooksConfig()
+	env.server.Config.Webhooks.AllowPrivateAddresses = true
+	webhooks := NewWebhookService(env.server, env.repos.Webhook, env.audit)
+
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	create := &webhook.CreateEndpointPayload{
+		URL:    receiver.URL,
+		Events: []string{webhook.EventTodoCompleted, webhook.EventCommentAdded},
+	}
+	require.NoError(t, create.Validate())
+	created, err := webhooks.CreateEndpoint(env.context(workspaceAdminRole), testAdminID, create)
+	require.NoError(t, err)
+	require.NotEmpty(t, created.Secret)
+
+	commentAdded := webhook.EventCommentAdded
+	result, err := webhooks.TestEndpoint(env.context(workspaceAdminRole), &webhook.TestEndpointPayload{
+		ID:    created.ID,
+		Event: &commentAdded,
+	})
+	require.NoError(t, err)
+	require.True(t, result.Success)
+	require.Equal(t, http.StatusNoContent, *result.StatusCode)
+
+	req, body := <-received, <-bodies
+	require.Equal(t, webhook.EventCommentAdded, req.Header.Get(deliver.EventHeader))
+	require.Equal(t, result.DeliveryID.String(), req.Header.Get(deliver.DeliveryHeader))
+	require.NoError(t, deliver.Verify(created.Secret, req.Header.Get(deliver.SignatureHeader), body, time.Now(),
+		time.Minute))
+	require.ErrorIs(t, deliver.Verify("whsec_other", req.Header.Get(deliver.SignatureHeader), body, time.Now(),
+		time.Minute), deliver.ErrSignature)
+
+	var payload webhook.Payload[webhook.CommentData]
+	require.NoError(t, json.Unmarshal(body, &payload))
+	require.True(t, payload.Test)
+	require.Equal(t, webhook.EventCommentAdded, payload.Type)
+	require.Equal(t, testWorkspaceID, payload.WorkspaceID)
+	require.NotEmpty(t, payload.Data.Comment.Content)
+}
+
+func TestWebhookEndpointsAreManagedByAdmins(t *testing.T) {
+	env := newWebhookEnv(t)
+	webhooks := NewWebhookService(env.server, env.repos.Webhook, env.audit)
+
+	member := env.context("org:member")
+	_, err := webhooks.CreateEndpoint(member, testAdminID, &webhook.CreateEndpointPayload{
+		URL:    "https://example.com/hooks",
+		Events: []string{webhook.EventTodoCreated},
+	})
+	var httpErr *errs.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusForbidden, httpErr.Status)
+
+	// The catalog is open to every member, each type with the schema of its payloads
+	catalog := webhooks.GetEventTypes(member, &webhook.GetEventTypesPayload{})
+	require.Len(t, catalog, 8)
+	for _, eventType := range catalog {
+		require.Equal(t, "https://json-schema.org/draft/2020-12/schema", eventType.Schema["$schema"], eventType.Type)
+	}
+}
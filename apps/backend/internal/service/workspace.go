@@ -0,0 +1,1278 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/lib/job"
+	"github.com/Sameer16536/ExecuTask/internal/logger"
+	"github.com/Sameer16536/ExecuTask/internal/middleware"
+	"github.com/Sameer16536/ExecuTask/internal/model/audit"
+	"github.com/Sameer16536/ExecuTask/internal/model/backup"
+	"github.com/Sameer16536/ExecuTask/internal/model/category"
+	"github.com/Sameer16536/ExecuTask/internal/model/planner"
+	"github.com/Sameer16536/ExecuTask/internal/model/rule"
+	"github.com/Sameer16536/ExecuTask/internal/model/todo"
+	"github.com/Sameer16536/ExecuTask/internal/model/workspace"
+	"github.com/Sameer16536/ExecuTask/internal/repository"
+	"github.com/Sameer16536/ExecuTask/internal/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+const (
+	workspaceMemberRedisKeyPrefix = "workspace:member"
+	// workspaceMemberTTL bounds how often a member is looked up again, joins are only recorded once
+	workspaceMemberTTL = 24 * time.Hour
+	// maxBreachesPerPass bounds the todos a policy escalates in one pass, the rest wait for the next
+	maxBreachesPerPass            = 200
+	workspaceFrozenRedisKeyPrefix = "workspace:frozen"
+	// workspaceFrozenTTL bounds how often the freeze of a workspace is looked up, freezing and
+	// reactivating clear the cached answer
+	workspaceFrozenTTL = time.Minute
+	// workspaceFreezePath is the route a frozen workspace is reactivated through
+	workspaceFreezePath = "/api/v1/workspaces/:workspaceId/freeze"
+)
+
+type WorkspaceService struct {
+	server          *server.Server
+	workspaceRepo   repository.WorkspaceStore
+	todoRepo        repository.TodoStore
+	todoService     *TodoService
+	categoryService *CategoryService
+	auditService    *AuditService
+	authService     *AuthService
+	backupService   *BackupService
+	txManager       repository.TxManager
+}
+
+func NewWorkspaceService(server *server.Server, workspaceRepo repository.WorkspaceStore, todoRepo repository.TodoStore,
+	todoService *TodoService, categoryService *CategoryService, auditService *AuditService, authService *AuthService,
+	backupService *BackupService, txManager repository.TxManager,
+) *WorkspaceService {
+	return &WorkspaceService{
+		server:          server,
+		workspaceRepo:   workspaceRepo,
+		todoRepo:        todoRepo,
+		todoService:     todoService,
+		categoryService: categoryService,
+		auditService:    auditService,
+		authService:     authService,
+		backupService:   backupService,
+		txManager:       txManager,
+	}
+}
+
+// GetTemplates lists the templates of the workspace for its admins to manage them
+func (s *WorkspaceService) GetTemplates(ctx echo.Context, workspaceID string) ([]workspace.Template, error) {
+	if err := administeredWorkspace(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+
+	return s.templates(ctx, workspaceID)
+}
+
+func (s *WorkspaceService) templates(ctx echo.Context, workspaceID string) ([]workspace.Template, error) {
+	logger := middleware.GetLogger(ctx)
+
+	templates, err := s.workspaceRepo.GetTemplates(ctx.Request().Context(), workspaceID)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", workspaceID).Msg("failed to fetch workspace templates")
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+func (s *WorkspaceService) CreateTemplate(ctx echo.Context, userID string,
+	payload *workspace.CreateTemplatePayload,
+) (*workspace.Template, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := administeredWorkspace(ctx, payload.WorkspaceID); err != nil {
+		return nil, err
+	}
+
+	template, err := s.workspaceRepo.CreateTemplate(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", payload.WorkspaceID).Msg("failed to create workspace template")
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminTemplate,
+		EntityType: "workspace_template",
+		EntityID:   template.ID.String(),
+		After:      template,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "workspace_template_created").
+		Str("workspace_id", template.WorkspaceID).
+		Str("template_id", template.ID.String()).
+		Msg("Workspace template created successfully")
+
+	return template, nil
+}
+
+func (s *WorkspaceService) DeleteTemplate(ctx echo.Context, payload *workspace.DeleteTemplatePayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	if err := administeredWorkspace(ctx, payload.WorkspaceID); err != nil {
+		return err
+	}
+
+	template, err := s.workspaceRepo.DeleteTemplate(ctx.Request().Context(), payload.WorkspaceID, payload.TemplateID)
+	if err != nil {
+		logger.Error().Err(err).Str("template_id", payload.TemplateID.String()).Msg("failed to delete workspace template")
+		return err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminTemplate,
+		EntityType: "workspace_template",
+		EntityID:   template.ID.String(),
+		Before:     template,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "workspace_template_deleted").
+		Str("workspace_id", template.WorkspaceID).
+		Str("template_id", template.ID.String()).
+		Msg("Workspace template deleted successfully")
+
+	return nil
+}
+
+func (s *WorkspaceService) GetOnboardingKit(ctx echo.Context, workspaceID string) (*workspace.OnboardingKit, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := administeredWorkspace(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+
+	kit, err := s.workspaceRepo.GetOnboardingKit(ctx.Request().Context(), workspaceID)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", workspaceID).Msg("failed to fetch onboarding kit")
+		return nil, err
+	}
+
+	return kit, nil
+}
+
+// SetOnboardingKit replaces the kit of the workspace, members who joined before keep what they got
+func (s *WorkspaceService) SetOnboardingKit(ctx echo.Context, userID string,
+	payload *workspace.SetOnboardingKitPayload,
+) (*workspace.OnboardingKit, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := administeredWorkspace(ctx, payload.WorkspaceID); err != nil {
+		return nil, err
+	}
+
+	kit, err := s.workspaceRepo.SetOnboardingKit(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", payload.WorkspaceID).Msg("failed to set onboarding kit")
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminOnboardingKit,
+		EntityType: "onboarding_kit",
+		EntityID:   payload.WorkspaceID,
+		After:      kit,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "onboarding_kit_set").
+		Str("workspace_id", kit.WorkspaceID).
+		Int("category_count", len(kit.Categories)).
+		Int("todo_count", len(kit.Todos)).
+		Msg("Onboarding kit set successfully")
+
+	return kit, nil
+}
+
+func (s *WorkspaceService) DeleteOnboardingKit(ctx echo.Context, payload *workspace.DeleteOnboardingKitPayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	if err := administeredWorkspace(ctx, payload.WorkspaceID); err != nil {
+		return err
+	}
+
+	kit, err := s.workspaceRepo.DeleteOnboardingKit(ctx.Request().Context(), payload.WorkspaceID)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", payload.WorkspaceID).Msg("failed to delete onboarding kit")
+		return err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminOnboardingKit,
+		EntityType: "onboarding_kit",
+		EntityID:   payload.WorkspaceID,
+		Before:     kit,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "onboarding_kit_deleted").
+		Str("workspace_id", kit.WorkspaceID).
+		Msg("Onboarding kit deleted successfully")
+
+	return nil
+}
+
+// GetWorkspaceWorkflow is the workflow of the active workspace, for its members to show the
+// statuses and priorities of their todos
+func (s *WorkspaceService) GetWorkspaceWorkflow(ctx echo.Context, payload *workspace.GetWorkspaceWorkflowPayload,
+) (*workspace.Workflow, error) {
+	workspaceID, err := activeWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if workspaceID != payload.WorkspaceID {
+		return nil, errs.NewForbiddenError("the workflow of a workspace is only shown from within it", false)
+	}
+
+	workflow, err := s.workspaceRepo.GetWorkflow(ctx.Request().Context(), workspaceID)
+	if err != nil {
+		middleware.GetLogger(ctx).Error().Err(err).Str("workspace_id", workspaceID).Msg("failed to fetch workflow")
+		return nil, err
+	}
+
+	return workflow, nil
+}
+
+// SetWorkflow replaces the scales of the workspace and moves its todos onto them in the same
+// transaction: the mappings rename keys, the todos left on a status the scale no longer has
+// go to the first status of its category and those left on a priority keep their level
+func (s *WorkspaceService) SetWorkflow(ctx echo.Context, userID string,
+	payload *workspace.SetWorkflowPayload,
+) (*workspace.Workflow, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := administeredWorkspace(ctx, payload.WorkspaceID); err != nil {
+		return nil, err
+	}
+
+	var workflow *workspace.Workflow
+	var moved int
+	err := withinTx(ctx, s.txManager, func() error {
+		previous, err := workspaceWorkflow(ctx.Request().Context(), s.workspaceRepo, &payload.WorkspaceID)
+		if err != nil {
+			return err
+		}
+
+		workflow, err = s.workspaceRepo.SetWorkflow(ctx.Request().Context(), userID, payload)
+		if err != nil {
+			return err
+		}
+
+		// Priorities dropped for the built-in ones spread over them as when the workflow is
+		// deleted, unless the payload maps them
+		priorityMapping := payload.PriorityMapping
+		if previous != nil && len(previous.Priorities) > 0 && len(workflow.Priorities) == 0 {
+			priorityMapping = previous.LevelMapping()
+			maps.Copy(priorityMapping, payload.PriorityMapping)
+		}
+
+		moved, err = s.todoRepo.ApplyWorkflow(ctx.Request().Context(), payload.WorkspaceID, payload.StatusMapping,
+			priorityMapping)
+		return err
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", payload.WorkspaceID).Msg("failed to set workflow")
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminWorkflow,
+		EntityType: "workflow",
+		EntityID:   payload.WorkspaceID,
+		After:      workflow,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "workflow_set").
+		Str("workspace_id", workflow.WorkspaceID).
+		Int("status_count", len(workflow.Statuses)).
+		Int("priority_count", len(workflow.Priorities)).
+		Int("todos_moved", moved).
+		Msg("Workflow set successfully")
+
+	return workflow, nil
+}
+
+// DeleteWorkflow brings the workspace back to the built-in statuses and priorities, its todos
+// take the built-in status of the category of theirs and the built-in priority their level
+// spreads onto
+func (s *WorkspaceService) DeleteWorkflow(ctx echo.Context, payload *workspace.DeleteWorkflowPayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	if err := administeredWorkspace(ctx, payload.WorkspaceID); err != nil {
+		return err
+	}
+
+	var workflow *workspace.Workflow
+	err := withinTx(ctx, s.txManager, func() error {
+		var err error
+		workflow, err = s.workspaceRepo.DeleteWorkflow(ctx.Request().Context(), payload.WorkspaceID)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.todoRepo.ApplyWorkflow(ctx.Request().Context(), payload.WorkspaceID, workflow.CategoryMapping(),
+			workflow.LevelMapping())
+		return err
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", payload.WorkspaceID).Msg("failed to delete workflow")
+		return err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminWorkflow,
+		EntityType: "workflow",
+		EntityID:   payload.WorkspaceID,
+		Before:     workflow,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "workflow_deleted").
+		Str("workspace_id", workflow.WorkspaceID).
+		Msg("Workflow deleted successfully")
+
+	return nil
+}
+
+func (s *WorkspaceService) GetAgingPolicies(ctx echo.Context, workspaceID string) ([]workspace.AgingPolicy, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := administeredWorkspace(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+
+	policies, err := s.workspaceRepo.GetAgingPolicies(ctx.Request().Context(), workspaceID)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", workspaceID).Msg("failed to fetch aging policies")
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+func (s *WorkspaceService) CreateAgingPolicy(ctx echo.Context, userID string,
+	payload *workspace.CreateAgingPolicyPayload,
+) (*workspace.AgingPolicy, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := administeredWorkspace(ctx, payload.WorkspaceID); err != nil {
+		return nil, err
+	}
+
+	if payload.Priority != nil {
+		workflow, err := workspaceWorkflow(ctx.Request().Context(), s.workspaceRepo, &payload.WorkspaceID)
+		if err != nil {
+			logger.Error().Err(err).Str("workspace_id", payload.WorkspaceID).Msg("failed to fetch workspace workflow")
+			return nil, err
+		}
+		priority, err := workflow.PriorityKey("priority", *payload.Priority)
+		if err != nil {
+			return nil, err
+		}
+		payload.Priority = &priority
+	}
+
+	policy, err := s.workspaceRepo.CreateAgingPolicy(ctx.Request().Context(), userID, payload)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", payload.WorkspaceID).Msg("failed to create aging policy")
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminAgingPolicy,
+		EntityType: "aging_policy",
+		EntityID:   policy.ID.String(),
+		After:      policy,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "aging_policy_created").
+		Str("workspace_id", policy.WorkspaceID).
+		Str("policy_id", policy.ID.String()).
+		Int("max_age_hours", policy.MaxAgeHours).
+		Msg("Aging policy created successfully")
+
+	return policy, nil
+}
+
+// DeleteAgingPolicy removes a policy with the breaches it recorded, the tags it added are kept
+func (s *WorkspaceService) DeleteAgingPolicy(ctx echo.Context, payload *workspace.DeleteAgingPolicyPayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	if err := administeredWorkspace(ctx, payload.WorkspaceID); err != nil {
+		return err
+	}
+
+	policy, err := s.workspaceRepo.DeleteAgingPolicy(ctx.Request().Context(), payload.WorkspaceID, payload.PolicyID)
+	if err != nil {
+		logger.Error().Err(err).Str("policy_id", payload.PolicyID.String()).Msg("failed to delete aging policy")
+		return err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminAgingPolicy,
+		EntityType: "aging_policy",
+		EntityID:   policy.ID.String(),
+		Before:     policy,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "aging_policy_deleted").
+		Str("workspace_id", policy.WorkspaceID).
+		Str("policy_id", policy.ID.String()).
+		Msg("Aging policy deleted successfully")
+
+	return nil
+}
+
+// GetInvitations lists the invites of the workspace, split by whether they can still be
+// accepted, with the members who joined through them
+func (s *WorkspaceService) GetInvitations(ctx echo.Context, workspaceID string) (*workspace.Invitations, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := administeredWorkspace(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+
+	invites, err := s.workspaceRepo.GetInvites(ctx.Request().Context(), workspaceID)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", workspaceID).Msg("failed to fetch workspace invites")
+		return nil, err
+	}
+
+	acceptances, err := s.workspaceRepo.GetInviteAcceptances(ctx.Request().Context(), workspaceID)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", workspaceID).Msg("failed to fetch workspace invite acceptances")
+		return nil, err
+	}
+
+	return workspace.NewInvitations(workspaceID, invites, acceptances, time.Now()), nil
+}
+
+// CreateInvite issues a shareable link to the workspace. Its token is only returned here, the
+// invite keeps a hash of it.
+func (s *WorkspaceService) CreateInvite(ctx echo.Context, userID string,
+	payload *workspace.CreateInvitePayload,
+) (*workspace.Invite, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := administeredWorkspace(ctx, payload.WorkspaceID); err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, workspace.InviteTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		logger.Error().Err(err).Msg("failed to generate invite token")
+		return nil, fmt.Errorf("failed to generate invite token: %w", err)
+	}
+	token := hex.EncodeToString(secret)
+
+	invite, err := s.workspaceRepo.CreateInvite(ctx.Request().Context(), &workspace.Invite{
+		WorkspaceID:    payload.WorkspaceID,
+		TokenHash:      workspace.HashInviteToken(token),
+		AllowedDomains: payload.AllowedDomains,
+		MaxUses:        payload.MaxUses,
+		ExpiresAt:      payload.ExpiresAt(time.Now()),
+		CreatedBy:      userID,
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", payload.WorkspaceID).Msg("failed to create workspace invite")
+		return nil, err
+	}
+	invite.Status = invite.StatusAt(time.Now())
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminInvite,
+		EntityType: "workspace_invite",
+		EntityID:   invite.ID.String(),
+		After:      invite,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "workspace_invite_created").
+		Str("workspace_id", invite.WorkspaceID).
+		Str("invite_id", invite.ID.String()).
+		Int("allowed_domain_count", len(invite.AllowedDomains)).
+		Msg("Workspace invite created successfully")
+
+	invite.Token = &token
+	return invite, nil
+}
+
+// RevokeInvite stops an invite from being accepted, members who joined through it stay
+func (s *WorkspaceService) RevokeInvite(ctx echo.Context, payload *workspace.RevokeInvitePayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	if err := administeredWorkspace(ctx, payload.WorkspaceID); err != nil {
+		return err
+	}
+
+	invite, err := s.workspaceRepo.RevokeInvite(ctx.Request().Context(), payload.WorkspaceID, payload.InviteID)
+	if err != nil {
+		logger.Error().Err(err).Str("invite_id", payload.InviteID.String()).Msg("failed to revoke workspace invite")
+		return err
+	}
+	invite.Status = invite.StatusAt(time.Now())
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminInvite,
+		EntityType: "workspace_invite",
+		EntityID:   invite.ID.String(),
+		After:      invite,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "workspace_invite_revoked").
+		Str("workspace_id", invite.WorkspaceID).
+		Str("invite_id", invite.ID.String()).
+		Msg("Workspace invite revoked successfully")
+
+	return nil
+}
+
+// AcceptInvite adds the user to the workspace of the invite. When the invite is limited to
+// some domains one of the user's verified addresses has to belong to them. The use is only
+// recorded once Clerk added the membership. The onboarding kit is applied the first time the
+// user is seen in the workspace, like for members who joined any other way.
+func (s *WorkspaceService) AcceptInvite(ctx echo.Context, userID string,
+	payload *workspace.AcceptInvitePayload,
+) (*workspace.InviteAcceptance, error) {
+	logger := middleware.GetLogger(ctx)
+
+	invite, err := s.workspaceRepo.GetInviteByToken(ctx.Request().Context(), workspace.HashInviteToken(payload.Token))
+	if err != nil {
+		return nil, err
+	}
+
+	switch invite.StatusAt(time.Now()) {
+	case workspace.InviteStatusExpired, workspace.InviteStatusRevoked:
+		code := errs.CodeInviteExpired
+		return nil, errs.NewGoneError("the invite link expired or was revoked", false, &code)
+	case workspace.InviteStatusUsedUp:
+		code := errs.CodeInviteUsedUp
+		return nil, errs.NewGoneError("the invite link was used as many times as it allows", false, &code)
+	}
+
+	frozen, err := s.isFrozen(ctx.Request().Context(), invite.WorkspaceID)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", invite.WorkspaceID).Msg("failed to check workspace freeze")
+		return nil, err
+	}
+	if frozen {
+		code := errs.CodeWorkspaceFrozen
+		return nil, errs.NewConflictError("the workspace is frozen and can't be joined", false, &code)
+	}
+
+	emails, err := s.authService.GetVerifiedEmails(ctx.Request().Context(), userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch verified emails")
+		return nil, err
+	}
+	index := slices.IndexFunc(emails, invite.Allows)
+	if index < 0 {
+		return nil, &errs.HTTPError{
+			Code:    errs.CodeInviteDomainNotAllowed,
+			Message: "none of your verified email addresses may join with this invite",
+			Status:  http.StatusForbidden,
+		}
+	}
+
+	var acceptance *workspace.InviteAcceptance
+	err = withinTx(ctx, s.txManager, func() error {
+		accepted, err := s.workspaceRepo.HasAcceptedInvite(ctx.Request().Context(), invite.ID, userID)
+		if err != nil {
+			return err
+		}
+		if accepted {
+			code := errs.CodeInviteAlreadyAccepted
+			return errs.NewConflictError("you already joined with this invite", false, &code)
+		}
+
+		acceptance, err = s.workspaceRepo.AcceptInvite(ctx.Request().Context(), invite, userID, emails[index])
+		if err != nil {
+			return err
+		}
+
+		// Last, so a failed Clerk call gives the use back
+		return s.authService.AddWorkspaceMember(ctx.Request().Context(), invite.WorkspaceID, userID)
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("invite_id", invite.ID.String()).Msg("failed to accept workspace invite")
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionInviteAccepted,
+		EntityType: "workspace_invite",
+		EntityID:   invite.ID.String(),
+		After:      acceptance,
+	})
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "workspace_invite_accepted").
+		Str("workspace_id", invite.WorkspaceID).
+		Str("invite_id", invite.ID.String()).
+		Msg("Workspace invite accepted successfully")
+
+	return acceptance, nil
+}
+
+// GetFreeze returns the freeze of the workspace, it is not found while the workspace is active
+func (s *WorkspaceService) GetFreeze(ctx echo.Context, workspaceID string) (*workspace.Freeze, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := administeredWorkspace(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+
+	freeze, err := s.workspaceRepo.GetFreeze(ctx.Request().Context(), workspaceID)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", workspaceID).Msg("failed to fetch workspace freeze")
+		return nil, err
+	}
+
+	return freeze, nil
+}
+
+// FreezeWorkspace archives the workspace: writes made in it are rejected from now on, a final
+// backup is queued as its export bundle and its data is purged once the retention runs out.
+// The freeze is undone when the backup can't be queued, e.g. while another one is running.
+func (s *WorkspaceService) FreezeWorkspace(ctx echo.Context, userID string,
+	payload *workspace.FreezeWorkspacePayload,
+) (*workspace.Freeze, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := administeredWorkspace(ctx, payload.WorkspaceID); err != nil {
+		return nil, err
+	}
+	reqCtx := ctx.Request().Context()
+
+	freeze, err := s.workspaceRepo.CreateFreeze(reqCtx, &workspace.Freeze{
+		WorkspaceID: payload.WorkspaceID,
+		FrozenBy:    userID,
+		Reason:      payload.Reason,
+		PurgeAfter:  payload.PurgeAfter(time.Now()),
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", payload.WorkspaceID).Msg("failed to freeze workspace")
+		return nil, err
+	}
+	s.clearFrozen(ctx, payload.WorkspaceID)
+
+	// Queued once writes are rejected, so the bundle holds the final state
+	backupItem, err := s.backupService.RequestBackup(ctx, userID, &backup.CreateBackupPayload{
+		WorkspaceID: payload.WorkspaceID,
+	})
+	if err != nil {
+		if _, undoErr := s.workspaceRepo.DeleteFreeze(context.WithoutCancel(reqCtx), payload.WorkspaceID); undoErr != nil {
+			logger.Error().Err(undoErr).Str("workspace_id", payload.WorkspaceID).Msg("failed to undo workspace freeze")
+		}
+		s.clearFrozen(ctx, payload.WorkspaceID)
+		return nil, err
+	}
+
+	if updated, err := s.workspaceRepo.SetFreezeBackup(reqCtx, payload.WorkspaceID, backupItem.ID); err != nil {
+		// The backup is listed with the others of the workspace all the same
+		logger.Error().Err(err).Str("backup_id", backupItem.ID.String()).Msg("failed to link final backup to freeze")
+		freeze.BackupID = &backupItem.ID
+	} else {
+		freeze = updated
+	}
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminFreeze,
+		EntityType: "workspace_freeze",
+		EntityID:   freeze.WorkspaceID,
+		After:      freeze,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "workspace_frozen").
+		Str("workspace_id", freeze.WorkspaceID).
+		Str("backup_id", backupItem.ID.String()).
+		Time("purge_after", freeze.PurgeAfter).
+		Msg("Workspace frozen successfully")
+
+	return freeze, nil
+}
+
+// ReactivateWorkspace lifts the freeze of a workspace that was not purged yet, its final
+// backup is kept
+func (s *WorkspaceService) ReactivateWorkspace(ctx echo.Context, payload *workspace.ReactivateWorkspacePayload) error {
+	logger := middleware.GetLogger(ctx)
+
+	if err := administeredWorkspace(ctx, payload.WorkspaceID); err != nil {
+		return err
+	}
+
+	freeze, err := s.workspaceRepo.DeleteFreeze(ctx.Request().Context(), payload.WorkspaceID)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", payload.WorkspaceID).Msg("failed to reactivate workspace")
+		return err
+	}
+	s.clearFrozen(ctx, payload.WorkspaceID)
+
+	s.auditService.Record(ctx, &audit.Event{
+		Action:     audit.ActionAdminFreeze,
+		EntityType: "workspace_freeze",
+		EntityID:   freeze.WorkspaceID,
+		Before:     freeze,
+	})
+
+	// Business event log
+	logger.Info().
+		Str("event", "workspace_reactivated").
+		Str("workspace_id", freeze.WorkspaceID).
+		Msg("Workspace reactivated successfully")
+
+	return nil
+}
+
+// PurgeWorkspace deletes the data of a frozen workspace whose retention ran out, its backups
+// are kept. The purge is recorded on behalf of the admin who froze the workspace.
+func (s *WorkspaceService) PurgeWorkspace(ctx context.Context, workspaceID string) error {
+	log := logger.WithSpanContext(*s.server.Logger, ctx).With().
+		Str("workspace_id", workspaceID).
+		Logger()
+
+	var before, after *workspace.Freeze
+	var counts map[string]int
+	err := s.txManager.WithinTx(ctx, func(txCtx context.Context) error {
+		var err error
+		if before, err = s.workspaceRepo.GetFreeze(txCtx, workspaceID); err != nil {
+			return err
+		}
+		if counts, err = s.workspaceRepo.PurgeWorkspace(txCtx, workspaceID); err != nil {
+			return err
+		}
+		after, err = s.workspaceRepo.GetFreeze(txCtx, workspaceID)
+		return err
+	})
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) && httpErr.Code == errs.CodeWorkspaceNotFrozen {
+			// Reactivated or purged since it was queued
+			log.Info().Msg("workspace no longer due for purging")
+			return nil
+		}
+		return err
+	}
+
+	s.auditService.RecordBackground(ctx, before.FrozenBy, &audit.Event{
+		Action:     audit.ActionAdminFreeze,
+		EntityType: "workspace_freeze",
+		EntityID:   workspaceID,
+		Before:     before,
+		After:      after,
+	})
+
+	// Business event log
+	event := log.Info().Str("event", "workspace_purged")
+	for resource, count := range counts {
+		event = event.Int(resource+"_count", count)
+	}
+	event.Msg("Workspace purged")
+
+	return nil
+}
+
+// GuardSession rejects the writes made in a frozen workspace. The admin API and the freeze
+// itself stay open, so its admins can reactivate the workspace.
+func (s *WorkspaceService) GuardSession(ctx echo.Context) error {
+	workspaceID := middleware.GetWorkspaceID(ctx)
+	if workspaceID == "" || strings.HasPrefix(ctx.Request().URL.Path, "/admin/") ||
+		ctx.Path() == workspaceFreezePath {
+		return nil
+	}
+	switch ctx.Request().Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	}
+
+	frozen, err := s.isFrozen(ctx.Request().Context(), workspaceID)
+	if err != nil {
+		// A freeze that can't be checked doesn't take the API down
+		middleware.GetLogger(ctx).Warn().Err(err).Msg("failed to check workspace freeze")
+		return nil
+	}
+	if frozen {
+		code := errs.CodeWorkspaceFrozen
+		return errs.NewConflictError("the workspace is frozen, changes can't be saved", false, &code)
+	}
+
+	return nil
+}
+
+// isFrozen tells whether the workspace is frozen, the answer is cached in Redis
+func (s *WorkspaceService) isFrozen(ctx context.Context, workspaceID string) (bool, error) {
+	redisKey := workspaceFrozenRedisKeyPrefix + ":" + workspaceID
+	if frozen, err := s.server.Redis.Get(ctx, redisKey).Bool(); err == nil {
+		return frozen, nil
+	}
+
+	frozen, err := s.workspaceRepo.IsFrozen(ctx, workspaceID)
+	if err != nil {
+		return false, err
+	}
+	// Without the cached answer the next request looks it up again
+	_ = s.server.Redis.Set(ctx, redisKey, frozen, workspaceFrozenTTL).Err()
+
+	return frozen, nil
+}
+
+// clearFrozen drops the cached answer of isFrozen after the workspace was frozen or reactivated
+func (s *WorkspaceService) clearFrozen(ctx echo.Context, workspaceID string) {
+	redisKey := workspaceFrozenRedisKeyPrefix + ":" + workspaceID
+	if err := s.server.Redis.Del(context.WithoutCancel(ctx.Request().Context()), redisKey).Err(); err != nil {
+		middleware.GetLogger(ctx).Warn().Err(err).Str("workspace_id", workspaceID).Msg("failed to clear workspace freeze")
+	}
+}
+
+// GetActiveTemplates lists the templates of the workspace active in the session
+func (s *WorkspaceService) GetActiveTemplates(ctx echo.Context) ([]workspace.Template, error) {
+	workspaceID, err := activeWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.templates(ctx, workspaceID)
+}
+
+// ApplyTemplate creates the todo of a template of the active workspace with its subtasks, all
+// or none
+func (s *WorkspaceService) ApplyTemplate(ctx echo.Context, userID string,
+	payload *workspace.ApplyTemplatePayload,
+) (*todo.Todo, error) {
+	logger := middleware.GetLogger(ctx)
+
+	workspaceID, err := activeWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := s.workspaceRepo.GetTemplate(ctx.Request().Context(), workspaceID, payload.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("template_id", payload.ID.String()).Msg("failed to fetch workspace template")
+		return nil, err
+	}
+
+	now := time.Now()
+	var todoItem *todo.Todo
+	err = withinTx(ctx, s.txManager, func() error {
+		var err error
+		todoItem, err = s.todoService.CreateTodo(ctx, userID, template.CreatePayload(payload.CategoryID, now))
+		if err != nil {
+			return err
+		}
+
+		for _, title := range template.Subtasks {
+			if _, err := s.todoService.CreateTodo(ctx, userID, &todo.CreateTodoPayload{
+				Title:        title,
+				ParentTodoID: &todoItem.ID,
+				CategoryID:   payload.CategoryID,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("template_id", template.ID.String()).Msg("failed to apply workspace template")
+		return nil, err
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "workspace_template_applied").
+		Str("workspace_id", workspaceID).
+		Str("template_id", template.ID.String()).
+		Str("todo_id", todoItem.ID.String()).
+		Int("subtask_count", len(template.Subtasks)).
+		Msg("Workspace template applied successfully")
+
+	return todoItem, nil
+}
+
+// GetWorkload lays the open todos of the active workspace out per member and week, starting
+// with the current week. Todos have no separate assignee, members are loaded with the todos
+// they own.
+func (s *WorkspaceService) GetWorkload(ctx echo.Context, query *workspace.GetWorkloadQuery) (*workspace.Workload, error) {
+	logger := middleware.GetLogger(ctx)
+
+	workspaceID, err := activeWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if workspaceID != query.WorkspaceID {
+		return nil, errs.NewForbiddenError("the workload of a workspace is only shown from within it", false)
+	}
+
+	location := query.Location()
+	from := planner.WeekStart(time.Now().In(location))
+	weeks := make([]string, *query.Weeks)
+	for i := range weeks {
+		weeks[i] = from.AddDate(0, 0, 7*i).Format(time.DateOnly)
+	}
+	to := from.AddDate(0, 0, 7*len(weeks))
+
+	buckets, err := s.workspaceRepo.GetWorkload(ctx.Request().Context(), workspaceID, from, to, location)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", workspaceID).Msg("failed to fetch workspace workload")
+		return nil, err
+	}
+
+	return workspace.NewWorkload(workspaceID, location.String(), weeks, *query.CapacityMinutes, buckets), nil
+}
+
+// GetBreachReport lists the open todos of the active workspace escalated by its aging policies
+func (s *WorkspaceService) GetBreachReport(ctx echo.Context, payload *workspace.GetBreachReportPayload,
+) (*workspace.BreachReport, error) {
+	logger := middleware.GetLogger(ctx)
+
+	workspaceID, err := activeWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if workspaceID != payload.WorkspaceID {
+		return nil, errs.NewForbiddenError("the breaches of a workspace are only shown from within it", false)
+	}
+
+	policies, err := s.workspaceRepo.GetAgingPolicies(ctx.Request().Context(), workspaceID)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", workspaceID).Msg("failed to fetch aging policies")
+		return nil, err
+	}
+
+	breaches, err := s.workspaceRepo.GetBreaches(ctx.Request().Context(), workspaceID)
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", workspaceID).Msg("failed to fetch sla breaches")
+		return nil, err
+	}
+
+	return workspace.NewBreachReport(workspaceID, policies, breaches, time.Now()), nil
+}
+
+// EnforceAgingPolicies escalates the open todos of the workspace breaching its policies: each
+// is tagged and, when the policy says so, its owner emailed. A breach is recorded before it is
+// escalated, so a todo is escalated once per policy even when tagging or notifying fails.
+func (s *WorkspaceService) EnforceAgingPolicies(ctx context.Context, workspaceID string) error {
+	log := logger.WithSpanContext(*s.server.Logger, ctx).With().
+		Str("workspace_id", workspaceID).
+		Logger()
+
+	// A frozen workspace is left as it was frozen
+	frozen, err := s.isFrozen(ctx, workspaceID)
+	if err != nil || frozen {
+		return err
+	}
+
+	policies, err := s.workspaceRepo.GetAgingPolicies(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	workflow, err := workspaceWorkflow(ctx, s.workspaceRepo, &workspaceID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	escalatedCount := 0
+	for i := range policies {
+		policy := &policies[i]
+
+		// The priority of the policy stands for a level of the scale as it is now, a policy
+		// on a priority the scale no longer has covers no todo
+		if policy.Priority != nil {
+			priority, err := workflow.PriorityKey("priority", *policy.Priority)
+			if err != nil {
+				log.Warn().Str("policy_id", policy.ID.String()).Msg("aging policy priority not on the workflow")
+				continue
+			}
+			policy.Priority = &priority
+		}
+
+		breaches, err := s.workspaceRepo.RecordBreaches(ctx, policy, now, maxBreachesPerPass)
+		if err != nil {
+			return err
+		}
+
+		for _, breach := range breaches {
+			s.escalate(ctx, log, policy, &breach)
+		}
+		escalatedCount += len(breaches)
+	}
+
+	log.Info().
+		Str("event", "aging_policies_enforced").
+		Int("policy_count", len(policies)).
+		Int("escalated_count", escalatedCount).
+		Msg("Aging policies enforced")
+
+	return nil
+}
+
+// escalate tags the todo of the breach and notifies its owner, failures are only logged
+func (s *WorkspaceService) escalate(ctx context.Context, log zerolog.Logger, policy *workspace.AgingPolicy,
+	breach *workspace.Breach,
+) {
+	log = log.With().
+		Str("policy_id", policy.ID.String()).
+		Str("todo_id", breach.TodoID.String()).
+		Logger()
+
+	if err := s.tagBreach(ctx, policy, breach); err != nil {
+		log.Warn().Err(err).Msg("failed to tag todo breaching aging policy")
+	}
+
+	if policy.Notify {
+		if err := job.EnqueueSLABreachEmail(ctx, s.server.Job.Client, &job.SLABreachEmailTask{
+			UserID:      breach.UserID,
+			PolicyName:  policy.Name,
+			MaxAgeHours: policy.MaxAgeHours,
+			TodoID:      breach.TodoID,
+			TodoTitle:   breach.TodoTitle,
+			OpenedAt:    breach.OpenedAt,
+		}); err != nil {
+			log.Error().Err(err).Msg("failed to enqueue sla breach email")
+		}
+	}
+}
+
+// tagBreach adds the tag of the policy to the todo, raising todo_tagged for the owner's rules
+func (s *WorkspaceService) tagBreach(ctx context.Context, policy *workspace.AgingPolicy,
+	breach *workspace.Breach,
+) error {
+	todoItem, err := s.todoRepo.CheckTodoExists(ctx, breach.UserID, breach.TodoID)
+	if err != nil {
+		return err
+	}
+	if rule.HasTag(todoItem, policy.Tag) {
+		return nil
+	}
+
+	metadata := todo.Metadata{}
+	if todoItem.Metadata != nil {
+		metadata = *todoItem.Metadata
+	}
+	metadata.Tags = append(slices.Clone(metadata.Tags), policy.Tag)
+
+	// The metadata is replaced as a whole, the version keeps concurrent edits from being lost
+	if _, err := s.todoRepo.UpdateTodo(ctx, breach.UserID, &todo.UpdateTodoPayload{
+		ID:       todoItem.ID,
+		Metadata: &metadata,
+		Version:  &todoItem.Version,
+	}); err != nil {
+		return err
+	}
+
+	return job.EnqueueRuleEvaluation(ctx, s.server.Job.Client, &job.RuleEvaluationTask{
+		Event: rule.Event{
+			UserID:  breach.UserID,
+			Trigger: rule.TriggerTodoTagged,
+			TodoID:  todoItem.ID,
+			Tag:     &policy.Tag,
+		},
+	})
+}
+
+// RecordSession onboards the user the first time they are seen in the active workspace. A
+// Redis marker keeps the other requests of the day away from the database.
+func (s *WorkspaceService) RecordSession(ctx echo.Context) {
+	workspaceID := middleware.GetWorkspaceID(ctx)
+	userID := middleware.GetUserID(ctx)
+	if workspaceID == "" || userID == "" {
+		return
+	}
+
+	redisKey := workspaceMemberRedisKeyPrefix + ":" + workspaceID + ":" + userID
+	created, err := s.server.Redis.SetNX(ctx.Request().Context(), redisKey, 1, workspaceMemberTTL).Result()
+	if err != nil {
+		// Without the marker every request would look the member up
+		middleware.GetLogger(ctx).Warn().Err(err).Msg("failed to check workspace member")
+		return
+	}
+	if !created {
+		return
+	}
+
+	// Nothing is written to a frozen workspace, the member joins once it is reactivated
+	frozen, err := s.isFrozen(ctx.Request().Context(), workspaceID)
+	if err != nil || frozen {
+		if err := s.server.Redis.Del(ctx.Request().Context(), redisKey).Err(); err != nil {
+			middleware.GetLogger(ctx).Warn().Err(err).Msg("failed to clear workspace member marker")
+		}
+		return
+	}
+
+	if _, err := s.JoinWorkspace(ctx, userID, workspaceID); err != nil {
+		// Let a later request try again
+		if err := s.server.Redis.Del(ctx.Request().Context(), redisKey).Err(); err != nil {
+			middleware.GetLogger(ctx).Warn().Err(err).Msg("failed to clear workspace member marker")
+		}
+	}
+}
+
+// JoinWorkspace records the user as a member and, when they are new to the workspace, applies
+// its onboarding kit in the same transaction. It returns nil for members seen before and when
+// the workspace has no kit.
+func (s *WorkspaceService) JoinWorkspace(ctx echo.Context, userID, workspaceID string) (*workspace.Onboarding, error) {
+	logger := middleware.GetLogger(ctx)
+
+	kit, err := s.workspaceRepo.GetOnboardingKit(ctx.Request().Context(), workspaceID)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != errs.CodeOnboardingKitNotFound {
+			logger.Error().Err(err).Str("workspace_id", workspaceID).Msg("failed to fetch onboarding kit")
+			return nil, err
+		}
+		// The member is still recorded, a kit set later is not applied to them
+		kit = nil
+	}
+
+	var onboarding *workspace.Onboarding
+	err = withinTx(ctx, s.txManager, func() error {
+		joined, err := s.workspaceRepo.AddMember(ctx.Request().Context(), workspaceID, userID)
+		if err != nil || !joined || kit == nil {
+			return err
+		}
+
+		onboarding, err = s.applyOnboardingKit(ctx, userID, kit)
+		return err
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("workspace_id", workspaceID).Msg("failed to join workspace")
+		return nil, err
+	}
+	if onboarding == nil {
+		return nil, nil
+	}
+
+	// Business event log
+	eventLogger := middleware.GetLogger(ctx)
+	eventLogger.Info().
+		Str("event", "workspace_member_onboarded").
+		Str("workspace_id", workspaceID).
+		Int("category_count", len(onboarding.Categories)).
+		Int("todo_count", len(onboarding.Todos)).
+		Msg("Workspace member onboarded successfully")
+
+	return onboarding, nil
+}
+
+// applyOnboardingKit creates the categories and todos of the kit. A category the user already
+// has is used as is rather than created again.
+func (s *WorkspaceService) applyOnboardingKit(ctx echo.Context, userID string, kit *workspace.OnboardingKit,
+) (*workspace.Onboarding, error) {
+	existing, err := s.categoryService.allCategories(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryIDs := make(map[string]uuid.UUID, len(existing))
+	for _, categoryItem := range existing {
+		categoryIDs[categoryItem.Name] = categoryItem.ID
+	}
+
+	onboarding := &workspace.Onboarding{
+		WorkspaceID: kit.WorkspaceID,
+		Categories:  []category.Category{},
+		Todos:       []todo.Todo{},
+	}
+
+	for _, item := range kit.Categories {
+		if _, ok := categoryIDs[item.Name]; ok {
+			continue
+		}
+
+		created, err := s.categoryService.CreateCategory(ctx, userID, &category.CreateCategoryPayload{
+			Name:        item.Name,
+			Color:       item.Color,
+			Description: item.Description,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		categoryIDs[created.Name] = created.ID
+		onboarding.Categories = append(onboarding.Categories, *created)
+	}
+
+	now := time.Now()
+	for _, item := range kit.Todos {
+		var categoryID *uuid.UUID
+		if item.CategoryName != nil {
+			if id, ok := categoryIDs[*item.CategoryName]; ok {
+				categoryID = &id
+			}
+		}
+
+		created, err := s.todoService.CreateTodo(ctx, userID, item.CreatePayload(categoryID, now))
+		if err != nil {
+			return nil, err
+		}
+		onboarding.Todos = append(onboarding.Todos, *created)
+	}
+
+	return onboarding, nil
+}
+
+// activeWorkspace returns the workspace of the session, personal accounts have none
+func activeWorkspace(ctx echo.Context) (string, error) {
+	workspaceID := middleware.GetWorkspaceID(ctx)
+	if workspaceID == "" {
+		code := errs.CodeWorkspaceRequired
+		return "", errs.NewBadRequestError("select the workspace first", false, &code, nil, nil)
+	}
+	return workspaceID, nil
+}
+
+// administeredWorkspace lets a request change the settings of the workspace of its path when
+// that is the active workspace of the session and the user is one of its admins
+func administeredWorkspace(ctx echo.Context, workspaceID string) error {
+	activeID, err := activeWorkspace(ctx)
+	if err != nil {
+		return err
+	}
+	if activeID != workspaceID {
+		return errs.NewForbiddenError("a workspace is only managed from within it", false)
+	}
+	if middleware.GetUserRole(ctx) != workspaceAdminRole {
+		return errs.NewForbiddenError("only the admins of a workspace manage it", false)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Sameer16536/ExecuTask/internal/errs"
+	"github.com/Sameer16536/ExecuTask/internal/model/workspace"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWorkspaceSettingsAreManagedByItsAdmins runs every change to the settings of a workspace
+// as a member, as an admin of another workspace and outside of any workspace before its admin
+// makes it
+func TestWorkspaceSettingsAreManagedByItsAdmins(t *testing.T) {
+	env := newTestEnv(t)
+
+	var templateID, policyID, inviteID uuid.UUID
+	operations := []struct {
+		name string
+		run  func(c echo.Context) error
+	}{
+		{name: "create template", run: func(c echo.Context) error {
+			payload := &workspace.CreateTemplatePayload{
+				WorkspaceID: testWorkspaceID,
+				Name:        "Release",
+				Title:       "Cut the release",
+			}
+			require.NoError(t, payload.Validate())
+			template, err := env.workspaces.CreateTemplate(c, testAdminID, payload)
+			if err == nil {
+				templateID = template.ID
+			}
+			return err
+		}},
+		{name: "list templates", run: func(c echo.Context) error {
+			_, err := env.workspaces.GetTemplates(c, testWorkspaceID)
+			return err
+		}},
+		{name: "delete template", run: func(c echo.Context) error {
+			return env.workspaces.DeleteTemplate(c, &workspace.DeleteTemplatePayload{
+				WorkspaceID: testWorkspaceID,
+				TemplateID:  templateID,
+			})
+		}},
+		{name: "set onboarding kit", run: func(c echo.Context) error {
+			payload := &workspace.SetOnboardingKitPayload{
+				WorkspaceID: testWorkspaceID,
+				Todos:       []workspace.KitTodo{{Title: "Say hello"}},
+			}
+			require.NoError(t, payload.Validate())
+			_, err := env.workspaces.SetOnboardingKit(c, testAdminID, payload)
+			return err
+		}},
+		{name: "get onboarding kit", run: func(c echo.Context) error {
+			_, err := env.workspaces.GetOnboardingKit(c, testWorkspaceID)
+			return err
+		}},
+		{name: "delete onboarding kit", run: func(c echo.Context) error {
+			return env.workspaces.DeleteOnboardingKit(c, &workspace.DeleteOnboardingKitPayload{
+				WorkspaceID: testWorkspaceID,
+			})
+		}},
+		{name: "create aging policy", run: func(c echo.Context) error {
+			payload := &workspace.CreateAgingPolicyPayload{
+				WorkspaceID: testWorkspaceID,
+				Name:        "Stale",
+				MaxAgeHours: 72,
+			}
+			require.NoError(t, payload.Validate())
+			policy, err := env.workspaces.CreateAgingPolicy(c, testAdminID, payload)
+			if err == nil {
+				policyID = policy.ID
+			}
+			return err
+		}},
+		{name: "list aging policies", run: func(c echo.Context) error {
+			_, err := env.workspaces.GetAgingPolicies(c, testWorkspaceID)
+			return err
+		}},
+		{name: "delete aging policy", run: func(c echo.Context) error {
+			return env.workspaces.DeleteAgingPolicy(c, &workspace.DeleteAgingPolicyPayload{
+				WorkspaceID: testWorkspaceID,
+				PolicyID:    policyID,
+			})
+		}},
+		{name: "create invite", run: func(c echo.Context) error {
+			payload := &workspace.CreateInvitePayload{WorkspaceID: testWorkspaceID}
+			require.NoError(t, payload.Validate())
+			invite, err := env.workspaces.CreateInvite(c, testAdminID, payload)
+			if err == nil {
+				inviteID = invite.ID
+			}
+			return err
+		}},
+		{name: "list invites", run: func(c echo.Context) error {
+			_, err := env.workspaces.GetInvitations(c, testWorkspaceID)
+			return err
+		}},
+		{name: "revoke invite", run: func(c echo.Context) error {
+			return env.workspaces.RevokeInvite(c, &workspace.RevokeInvitePayload{
+				WorkspaceID: testWorkspaceID,
+				InviteID:    inviteID,
+			})
+		}},
+		{name: "set workflow", run: func(c echo.Context) error {
+			payload := &workspace.SetWorkflowPayload{
+				WorkspaceID: testWorkspaceID,
+				Priorities: []workspace.WorkflowPriority{
+					{Key: "p2", Name: "P2", Color: "#eab308"},
+					{Key: "p1", Name: "P1", Color: "#f97316"},
+				},
+			}
+			require.NoError(t, payload.Validate())
+			_, err := env.workspaces.SetWorkflow(c, testAdminID, payload)
+			return err
+		}},
+		{name: "delete workflow", run: func(c echo.Context) error {
+			return env.workspaces.DeleteWorkflow(c, &workspace.DeleteWorkflowPayload{
+				WorkspaceID: testWorkspaceID,
+			})
+		}},
+	}
+
+	for _, operation := range operations {
+		err := operation.run(env.workspaceContext(testUserID, testWorkspaceID))
+		requireStatus(t, err, http.StatusForbidden, operation.name)
+
+		err = operation.run(env.adminContext(testAdminID, "org_other"))
+		requireStatus(t, err, http.StatusForbidden, operation.name)
+
+		err = operation.run(env.context(testAdminID))
+		requireErrorCode(t, err, errs.CodeWorkspaceRequired)
+
+		require.NoError(t, operation.run(env.adminContext(testAdminID, testWorkspaceID)), operation.name)
+	}
+}
+
+// TestWorkspaceFreezeIsManagedByItsAdmins stops short of freezing, which queues a backup
+func TestWorkspaceFreezeIsManagedByItsAdmins(t *testing.T) {
+	env := newTestEnv(t)
+
+	operations := []struct {
+		name string
+		run  func(c echo.Context) error
+	}{
+		{name: "get freeze", run: func(c echo.Context) error {
+			_, err := env.workspaces.GetFreeze(c, testWorkspaceID)
+			return err
+		}},
+		{name: "freeze workspace", run: func(c echo.Context) error {
+			payload := &workspace.FreezeWorkspacePayload{WorkspaceID: testWorkspaceID}
+			require.NoError(t, payload.Validate())
+			_, err := env.workspaces.FreezeWorkspace(c, testAdminID, payload)
+			return err
+		}},
+		{name: "reactivate workspace", run: func(c echo.Context) error {
+			return env.workspaces.ReactivateWorkspace(c, &workspace.ReactivateWorkspacePayload{
+				WorkspaceID: testWorkspaceID,
+			})
+		}},
+	}
+
+	for _, operation := range operations {
+		err := operation.run(env.workspaceContext(testUserID, testWorkspaceID))
+		requireStatus(t, err, http.StatusForbidden, operation.name)
+
+		err = operation.run(env.adminContext(testAdminID, "org_other"))
+		requireStatus(t, err, http.StatusForbidden, operation.name)
+
+		err = operation.run(env.context(testAdminID))
+		requireErrorCode(t, err, errs.CodeWorkspaceRequired)
+	}
+
+	_, err := env.workspaces.GetFreeze(env.adminContext(testAdminID, testWorkspaceID), testWorkspaceID)
+	requireErrorCode(t, err, errs.CodeWorkspaceNotFrozen)
+}
+
+func TestMembersSeeTheTemplatesOfTheActiveWorkspace(t *testing.T) {
+	env := newTestEnv(t)
+
+	payload := &workspace.CreateTemplatePayload{WorkspaceID: testWorkspaceID, Name: "Release", Title: "Cut the release"}
+	require.NoError(t, payload.Validate())
+	_, err := env.workspaces.CreateTemplate(env.adminContext(testAdminID, testWorkspaceID), testAdminID, payload)
+	require.NoError(t, err)
+
+	templates, err := env.workspaces.GetActiveTemplates(env.workspaceContext(testUserID, testWorkspaceID))
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	require.Equal(t, "Release", templates[0].Name)
+
+	templates, err = env.workspaces.GetActiveTemplates(env.workspaceContext(testUserID, "org_other"))
+	require.NoError(t, err)
+	require.Empty(t, templates)
+}
@@ -27,6 +27,10 @@ const (
 	// due to some previous command failure.
 	TransactionFailed Code = "transaction_failed"
 
+	// SerializationFailure is reported when a serializable transaction lost a race with a
+	// concurrent one, retrying it can succeed.
+	SerializationFailure Code = "serialization_failure"
+
 	// DeadlockDetected is reported when a deadlock is detected.
 	// Deadlock detection is done on a best-effort basis and not all deadlocks
 	// can be detected.
@@ -36,6 +40,9 @@ const (
 	// due to reaching the maximum number of connections.
 	// This is different from blocking waiting on a connection pool.
 	TooManyConnections Code = "too_many_connections"
+
+	// QueryCanceled is reported when a statement was cancelled, usually by the statement timeout.
+	QueryCanceled Code = "query_canceled"
 )
 
 // MapCode maps an underlying database error to a Code.
@@ -53,10 +60,14 @@ func MapCode(code string) Code {
 		return ExcludeViolation
 	case "25P02":
 		return TransactionFailed
+	case "40001":
+		return SerializationFailure
 	case "40P01":
 		return DeadlockDetected
 	case "53300":
 		return TooManyConnections
+	case "57014":
+		return QueryCanceled
 	default:
 		return Other
 	}
@@ -1,6 +1,7 @@
 package sqlerr
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -188,6 +189,18 @@ func HandleError(err error) error {
 		case CheckViolation:
 			return errs.NewBadRequestError(userMessage, true, &errorCode, nil, nil)
 
+		case QueryCanceled:
+			code := errs.CodeQueryTimeout
+			return errs.NewGatewayTimeoutError("The request took too long to process", false, &code)
+
+		case SerializationFailure, DeadlockDetected:
+			code := errs.CodeTransactionConflict
+			return errs.NewConflictError("The request raced with another change, please retry", false, &code)
+
+		case TooManyConnections:
+			code := errs.CodeDatabaseBusy
+			return errs.NewServiceUnavailableError("The service is busy, please retry shortly", false, &code)
+
 		default:
 			return errs.NewInternalServerError()
 		}
@@ -195,6 +208,10 @@ func HandleError(err error) error {
 
 	// Handle common pgx errors
 	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		code := errs.CodeRequestTimeout
+		return errs.NewGatewayTimeoutError("The request took too long to process", false, &code)
+
 	case errors.Is(err, pgx.ErrNoRows), errors.Is(err, sql.ErrNoRows):
 		errMsg := err.Error()
 		tablePrefix := "table:"
@@ -95,6 +95,22 @@ func SetupTestDB(t *testing.T) (*TestDB, func()) {
 		Auth: config.AuthConfig{
 			SecretKey: "test-secret",
 		},
+		// Objects stay on disk, tests don't need AWS credentials
+		AWS: config.AWSConfig{
+			S3Bucket: "test-bucket",
+		},
+		Storage: &config.StorageConfig{
+			Driver:     config.StorageDriverFileSystem,
+			Path:       t.TempDir(),
+			PublicURL:  "http://localhost:8080",
+			SigningKey: "test-signing-key",
+		},
+		// Emails are logged, never sent
+		Mail: &config.MailConfig{
+			Driver:      config.MailDriverLog,
+			FromName:    "ExecuTask",
+			FromAddress: "test@executask.com",
+		},
 	}
 
 	logger := zerolog.New(zerolog.NewConsoleWriter()).With().Timestamp().Logger()
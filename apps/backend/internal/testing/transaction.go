@@ -48,4 +48,4 @@ func WithRollbackTransaction(ctx context.Context, db *TestDB, fn TxFn) error {
 
 	// Run the function within the transaction
 	return fn(tx)
-}
\ No newline at end of file
+}
@@ -0,0 +1,107 @@
+package validation
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	zeroWidthJoiner   = '\u200d'
+	firstSkinTone     = '\U0001f3fb'
+	lastSkinTone      = '\U0001f3ff'
+	firstTagCharacter = '\U000e0020'
+	lastTagCharacter  = '\U000e007f'
+	firstRegional     = '\U0001f1e6'
+	lastRegional      = '\U0001f1ff'
+)
+
+// NormalizeText is the form titles, names and tags are validated and stored in: line breaks
+// and tabs become spaces, other control characters are dropped and the rest is NFC normalized,
+// so "é" typed as one character or as "e" and an accent compare equal
+func NormalizeText(s string) string {
+	stripped := strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, s)
+
+	return norm.NFC.String(stripped)
+}
+
+// Graphemes counts the characters of s as a user sees them. Combining marks, variation
+// selectors, skin tones and emoji tags belong to the character before them, emoji joined by
+// zero width joiners and the two halves of a flag count once. It approximates Unicode text
+// segmentation closely enough for length limits.
+func Graphemes(s string) int {
+	count := 0
+	joined := false
+	regional := false
+	for _, r := range s {
+		switch {
+		case r == zeroWidthJoiner:
+			joined = count > 0
+			continue
+		case unicode.Is(unicode.M, r) || (r >= firstSkinTone && r <= lastSkinTone) ||
+			(r >= firstTagCharacter && r <= lastTagCharacter):
+			if count == 0 {
+				count = 1
+			}
+			continue
+		case joined:
+			joined = false
+			continue
+		case r >= firstRegional && r <= lastRegional:
+			// Flags are pairs of regional indicators
+			regional = !regional
+			if !regional {
+				continue
+			}
+			count++
+			continue
+		}
+
+		regional = false
+		count++
+	}
+
+	return count
+}
+
+// normalizeText implements `text`: the string is replaced by its NormalizeText form, so the
+// rules after it and whatever stores the payload see the same text. It never fails, put it
+// before the rules it should apply to.
+func normalizeText(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() == reflect.String && field.CanSet() {
+		field.SetString(NormalizeText(field.String()))
+	}
+	return true
+}
+
+// textMin implements `textmin=n`, the length of the string counted in Graphemes is at least n
+func textMin(fl validator.FieldLevel) bool {
+	return Graphemes(fl.Field().String()) >= intParam(fl.Param())
+}
+
+// textMax implements `textmax=n`, the length of the string counted in Graphemes is at most n
+func textMax(fl validator.FieldLevel) bool {
+	return Graphemes(fl.Field().String()) <= intParam(fl.Param())
+}
+
+// intParam parses the parameter of a rule, a malformed one is a mistake in a struct tag
+func intParam(param string) int {
+	value, err := strconv.Atoi(param)
+	if err != nil {
+		panic("validation: invalid integer parameter " + strconv.Quote(param))
+	}
+	return value
+}
@@ -1,10 +1,14 @@
 package validation
 
 import (
+	"errors"
 	"fmt"
+	"mime"
+	"net/http"
 	"reflect"
 	"regexp"
 	"strings"
+	"unicode"
 
 	"github.com/Sameer16536/ExecuTask/internal/errs"
 	"github.com/go-playground/validator/v10"
@@ -17,6 +21,7 @@ type Validatable interface {
 
 type CustomValidationError struct {
 	Field   string
+	Code    string
 	Message string
 }
 
@@ -27,84 +32,164 @@ func (c CustomValidationErrors) Error() string {
 }
 
 func BindAndValidate(c echo.Context, payload Validatable) error {
-	if err := c.Bind(payload); err != nil {
+	if err := bind(c, payload); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return errs.NewRequestTooLargeError("", false, nil)
+		}
+
 		message := strings.Split(strings.Split(err.Error(), ",")[1], "message=")[1]
 		return errs.NewBadRequestError(message, false, nil, nil, nil)
 	}
 
-	if msg, fieldErrors := validateStruct(payload); fieldErrors != nil {
-		return errs.NewBadRequestError(msg, true, nil, fieldErrors, nil)
+	if err := payload.Validate(); err != nil {
+		return Error(err)
 	}
 
 	return nil
 }
 
-func validateStruct(v Validatable) (string, []errs.FieldError) {
-	if err := v.Validate(); err != nil {
-		return extractValidationErrors(err)
+// bind binds the request like echo does, except multipart bodies. They are file uploads
+// their handlers stream, parsing them here would buffer the whole file first.
+func bind(c echo.Context, payload Validatable) error {
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if mediaType != echo.MIMEMultipartForm {
+		return c.Bind(payload)
 	}
-	return "", nil
+
+	return (&echo.DefaultBinder{}).BindPathParams(c, payload)
+}
+
+// Error converts the error of a Validate method into a validation failure response
+func Error(err error) *errs.HTTPError {
+	return errs.NewValidationError(extractValidationErrors(err))
+}
+
+// NewFieldError reports a single invalid field, for rules that can only be checked
+// against stored data such as ownership of a referenced todo
+func NewFieldError(field, code, message string) *errs.HTTPError {
+	return errs.NewValidationError([]errs.FieldError{{
+		Field: field,
+		Code:  code,
+		Error: message,
+	}})
 }
 
-func extractValidationErrors(err error) (string, []errs.FieldError) {
+func extractValidationErrors(err error) []errs.FieldError {
 	var fieldErrors []errs.FieldError
-	validationErrors, ok := err.(validator.ValidationErrors)
-	if !ok {
-		customValidationErrors := err.(CustomValidationErrors)
+
+	var customValidationErrors CustomValidationErrors
+	if errors.As(err, &customValidationErrors) {
 		for _, err := range customValidationErrors {
+			code := err.Code
+			if code == "" {
+				code = errs.FieldCodeInvalidValue
+			}
+
 			fieldErrors = append(fieldErrors, errs.FieldError{
 				Field: err.Field,
+				Code:  code,
 				Error: err.Message,
 			})
 		}
+		return fieldErrors
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []errs.FieldError{{Field: "body", Code: errs.FieldCodeInvalidValue, Error: err.Error()}}
 	}
 
 	for _, err := range validationErrors {
-		field := strings.ToLower(err.Field())
-		var msg string
-
-		switch err.Tag() {
-		case "required":
-			msg = "is required"
-		case "min":
-			if err.Type().Kind() == reflect.String {
-				msg = fmt.Sprintf("must be at least %s characters", err.Param())
-			} else {
-				msg = fmt.Sprintf("must be at least %s", err.Param())
-			}
-		case "max":
-			if err.Type().Kind() == reflect.String {
-				msg = fmt.Sprintf("must not exceed %s characters", err.Param())
-			} else {
-				msg = fmt.Sprintf("must not exceed %s", err.Param())
-			}
-		case "oneof":
-			msg = fmt.Sprintf("must be one of: %s", err.Param())
-		case "email":
-			msg = "must be a valid email address"
-		case "e164":
-			msg = "must be a valid phone number with country code"
-		case "uuid":
-			msg = "must be a valid UUID"
-		case "uuidList":
-			msg = "must be a comma-separated list of valid UUIDs"
-		case "dive":
-			msg = "some items are invalid"
-		default:
-			if err.Param() != "" {
-				msg = fmt.Sprintf("%s: %s:%s", field, err.Tag(), err.Param())
-			} else {
-				msg = fmt.Sprintf("%s: %s", field, err.Tag())
-			}
-		}
+		code, message, args := describe(err)
 
 		fieldErrors = append(fieldErrors, errs.FieldError{
-			Field: strings.ToLower(err.Field()),
-			Error: msg,
+			Field:   fieldPath(err),
+			Code:    code,
+			Error:   fmt.Sprintf(message, args...),
+			Message: message,
+			Args:    args,
 		})
 	}
 
-	return "Validation failed", fieldErrors
+	return fieldErrors
+}
+
+// describe maps a failed validator tag to its field error code, and the message of the catalog
+// with its args
+func describe(err validator.FieldError) (string, string, []any) {
+	isLength := err.Kind() == reflect.String
+	isCount := err.Kind() == reflect.Slice || err.Kind() == reflect.Map || err.Kind() == reflect.Array
+
+	switch err.Tag() {
+	case "required":
+		return errs.FieldCodeRequired, "is required", nil
+	case "min":
+		switch {
+		case isLength:
+			return errs.FieldCodeTooShort, "must be at least %s characters", []any{err.Param()}
+		case isCount:
+			return errs.FieldCodeTooShort, "must contain at least %s items", []any{err.Param()}
+		}
+		return errs.FieldCodeTooSmall, "must be at least %s", []any{err.Param()}
+	case "max":
+		switch {
+		case isLength:
+			return errs.FieldCodeTooLong, "must not exceed %s characters", []any{err.Param()}
+		case isCount:
+			return errs.FieldCodeTooMany, "must not contain more than %s items", []any{err.Param()}
+		}
+		return errs.FieldCodeTooLarge, "must not exceed %s", []any{err.Param()}
+	case "textmin":
+		return errs.FieldCodeTooShort, "must be at least %s characters", []any{err.Param()}
+	case "textmax":
+		return errs.FieldCodeTooLong, "must not exceed %s characters", []any{err.Param()}
+	case "oneof":
+		return errs.FieldCodeInvalidChoice, "must be one of: %s", []any{err.Param()}
+	case "email":
+		return errs.FieldCodeInvalidFormat, "must be a valid email address", nil
+	case "e164":
+		return errs.FieldCodeInvalidFormat, "must be a valid phone number with country code", nil
+	case "uuid":
+		return errs.FieldCodeInvalidFormat, "must be a valid UUID", nil
+	case "uuidList":
+		return errs.FieldCodeInvalidFormat, "must be a comma-separated list of valid UUIDs", nil
+	case "hexcolor":
+		return errs.FieldCodeInvalidFormat, "must be a hex color such as #1a2b3c", nil
+	case "datetime":
+		return errs.FieldCodeInvalidFormat, "must be formatted like %s", []any{err.Param()}
+	case "timezone":
+		return errs.FieldCodeInvalidValue, "must be an IANA timezone such as Europe/Paris", nil
+	case "startswith":
+		return errs.FieldCodeInvalidFormat, "must start with %s", []any{err.Param()}
+	case "notpastunless":
+		name, value, _ := strings.Cut(err.Param(), " ")
+		return errs.FieldCodeDateInPast, "must not be in the past unless %s is %s", []any{fieldName(name), value}
+	case "dive":
+		return errs.FieldCodeInvalidValue, "some items are invalid", nil
+	}
+
+	if err.Param() != "" {
+		return err.Tag(), "%s: %s:%s", []any{err.Field(), err.Tag(), err.Param()}
+	}
+	return err.Tag(), "%s: %s", []any{err.Field(), err.Tag()}
+}
+
+// fieldPath is the request field of a validator error without the root and embedded
+// structs, which keep their exported Go names, e.g. changes[0].todoId
+func fieldPath(err validator.FieldError) string {
+	segments := []string{}
+	for _, segment := range strings.Split(err.Namespace(), ".") {
+		if segment != "" && unicode.IsUpper(rune(segment[0])) {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+
+	if len(segments) == 0 {
+		return err.Field()
+	}
+	return strings.Join(segments, ".")
 }
 
 var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
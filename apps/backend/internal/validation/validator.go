@@ -0,0 +1,92 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// validate is shared by every payload so custom rules and field naming are registered once
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	// Report fields by the name clients send them as
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		// Embedded structs keep their Go name and are dropped from field paths
+		if field.Anonymous {
+			return ""
+		}
+
+		for _, tag := range []string{"json", "query", "param"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return fieldName(field.Name)
+	})
+
+	_ = v.RegisterValidation("notpastunless", notPastUnless)
+	_ = v.RegisterValidation("text", normalizeText)
+	_ = v.RegisterValidation("textmin", textMin)
+	_ = v.RegisterValidation("textmax", textMax)
+
+	return v
+}
+
+// Struct validates a payload against its validate tags and the custom rules below
+func Struct(payload interface{}) error {
+	return validate.Struct(payload)
+}
+
+// IsPast reports whether t lies before the current day, so a due date of today stays valid
+func IsPast(t time.Time) bool {
+	now := time.Now().UTC()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return t.Before(startOfDay)
+}
+
+// notPastUnless implements `notpastunless=Field value`: the date must not be in the past
+// unless the sibling Field equals value. A nil sibling means the payload doesn't change it,
+// the rule is then left to the service which knows the stored value.
+func notPastUnless(fl validator.FieldLevel) bool {
+	date, ok := fl.Field().Interface().(time.Time)
+	if !ok || !IsPast(date) {
+		return true
+	}
+
+	name, value, _ := strings.Cut(fl.Param(), " ")
+
+	parent := fl.Parent()
+	if parent.Kind() == reflect.Pointer {
+		parent = parent.Elem()
+	}
+
+	sibling := parent.FieldByName(name)
+	if !sibling.IsValid() {
+		return false
+	}
+
+	if sibling.Kind() == reflect.Pointer {
+		if sibling.IsNil() {
+			return true
+		}
+		sibling = sibling.Elem()
+	}
+
+	return sibling.String() == value
+}
+
+func fieldName(goName string) string {
+	if goName == "" {
+		return goName
+	}
+	return strings.ToLower(goName[:1]) + goName[1:]
+}
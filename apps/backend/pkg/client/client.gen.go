@@ -0,0 +1,4160 @@
+// Code generated by sdkgen from the ExecuTask OpenAPI document. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// AdminGetApicLients calls GET /admin/v1/api-clients: list the API clients allowed to call the API from browsers
+func (c *Client) AdminGetApicLients(ctx context.Context) ([]APIClient, error) {
+	var out []APIClient
+	if err := c.do(ctx, http.MethodGet, "/admin/v1/api-clients", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminCreateApicLient calls POST /admin/v1/api-clients: register an API client and the origins it calls the API from
+func (c *Client) AdminCreateApicLient(ctx context.Context, body CreateAPIClientPayload) (*APIClient, error) {
+	var out APIClient
+	if err := c.do(ctx, http.MethodPost, "/admin/v1/api-clients", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminUpdateApicLient calls PATCH /admin/v1/api-clients/{id}: change the origins of an API client or disable it
+func (c *Client) AdminUpdateApicLient(ctx context.Context, id string, body UpdateAPIClientPayload) (*APIClient, error) {
+	var out APIClient
+	if err := c.do(ctx, http.MethodPatch, "/admin/v1/api-clients/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminDeleteApicLient calls DELETE /admin/v1/api-clients/{id}: remove an API client, its origins are no longer allowed
+func (c *Client) AdminDeleteApicLient(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/v1/api-clients/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// AdminGetUserApikEysParams are the query parameters of AdminGetUserApikEys
+type AdminGetUserApikEysParams struct {
+	UserID string
+}
+
+func (p *AdminGetUserApikEysParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	values.Set("userId", formatValue(p.UserID))
+	return values
+}
+
+// AdminGetUserApikEys calls GET /admin/v1/api-keys: list the API keys of a user
+func (c *Client) AdminGetUserApikEys(ctx context.Context, params *AdminGetUserApikEysParams) ([]Key, error) {
+	var out []Key
+	if err := c.do(ctx, http.MethodGet, "/admin/v1/api-keys", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminSetApikEyPlan calls PUT /admin/v1/api-keys/{id}/plan: move an API key to another plan
+func (c *Client) AdminSetApikEyPlan(ctx context.Context, id string, body SetKeyPlanPayload) (*Key, error) {
+	var out Key
+	if err := c.do(ctx, http.MethodPut, "/admin/v1/api-keys/"+url.PathEscape(id)+"/plan", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminGetAuditLogParams are the query parameters of AdminGetAuditLog
+type AdminGetAuditLogParams struct {
+	ActorID    *string
+	Action     *string
+	EntityType *string
+	EntityID   *string
+	From       *time.Time
+	To         *time.Time
+	Page       *int
+	Limit      *int
+}
+
+func (p *AdminGetAuditLogParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.ActorID != nil {
+		values.Set("actorId", formatValue(*p.ActorID))
+	}
+	if p.Action != nil {
+		values.Set("action", formatValue(*p.Action))
+	}
+	if p.EntityType != nil {
+		values.Set("entityType", formatValue(*p.EntityType))
+	}
+	if p.EntityID != nil {
+		values.Set("entityId", formatValue(*p.EntityID))
+	}
+	if p.From != nil {
+		values.Set("from", formatValue(*p.From))
+	}
+	if p.To != nil {
+		values.Set("to", formatValue(*p.To))
+	}
+	if p.Page != nil {
+		values.Set("page", formatValue(*p.Page))
+	}
+	if p.Limit != nil {
+		values.Set("limit", formatValue(*p.Limit))
+	}
+	return values
+}
+
+// AdminGetAuditLog calls GET /admin/v1/audit-log: list audit log entries
+func (c *Client) AdminGetAuditLog(ctx context.Context, params *AdminGetAuditLogParams) (*PaginatedResponseEntry, error) {
+	var out PaginatedResponseEntry
+	if err := c.do(ctx, http.MethodGet, "/admin/v1/audit-log", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminGetAuditLogIter iterates over every page of AdminGetAuditLog, starting at params.Page
+func (c *Client) AdminGetAuditLogIter(ctx context.Context, params *AdminGetAuditLogParams) iter.Seq2[Entry, error] {
+	next := AdminGetAuditLogParams{}
+	if params != nil {
+		next = *params
+	}
+
+	return paginate(next.Page, func(page int) ([]Entry, int, error) {
+		next.Page = &page
+		res, err := c.AdminGetAuditLog(ctx, &next)
+		if err != nil {
+			return nil, 0, err
+		}
+		return res.Data, res.TotalPages, nil
+	})
+}
+
+// AdminGetWorkspaceBackup calls GET /admin/v1/backups/{id}: get the progress of a workspace backup
+func (c *Client) AdminGetWorkspaceBackup(ctx context.Context, id string) (*Backup, error) {
+	var out Backup
+	if err := c.do(ctx, http.MethodGet, "/admin/v1/backups/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminRestoreWorkspaceBackup calls POST /admin/v1/backups/{id}/restores: restore a backup into an empty workspace
+func (c *Client) AdminRestoreWorkspaceBackup(ctx context.Context, id string, body RestoreBackupPayload) (*Restore, error) {
+	var out Restore
+	if err := c.do(ctx, http.MethodPost, "/admin/v1/backups/"+url.PathEscape(id)+"/restores", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminReloadConfig calls POST /admin/v1/config/reload: reload the runtime-safe config values of this instance
+func (c *Client) AdminReloadConfig(ctx context.Context) (*ConfigReload, error) {
+	var out ConfigReload
+	if err := c.do(ctx, http.MethodPost, "/admin/v1/config/reload", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminGetRuntimeStats calls GET /admin/v1/debug/runtime: get runtime, GC and connection pool statistics
+func (c *Client) AdminGetRuntimeStats(ctx context.Context) (*RuntimeStats, error) {
+	var out RuntimeStats
+	if err := c.do(ctx, http.MethodGet, "/admin/v1/debug/runtime", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminGetFaultInjection calls GET /admin/v1/faults: get the active fault injection rules
+func (c *Client) AdminGetFaultInjection(ctx context.Context) (*FaultInjection, error) {
+	var out FaultInjection
+	if err := c.do(ctx, http.MethodGet, "/admin/v1/faults", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminSetFaultInjection calls PUT /admin/v1/faults: inject latency, errors or dropped responses into API routes
+func (c *Client) AdminSetFaultInjection(ctx context.Context, body SetFaultInjectionPayload) (*FaultInjection, error) {
+	var out FaultInjection
+	if err := c.do(ctx, http.MethodPut, "/admin/v1/faults", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminClearFaultInjection calls DELETE /admin/v1/faults: stop every injected fault
+func (c *Client) AdminClearFaultInjection(ctx context.Context) error {
+	return c.do(ctx, http.MethodDelete, "/admin/v1/faults", nil, nil, nil)
+}
+
+// AdminGetFeatureFlags calls GET /admin/v1/feature-flags: list feature flags and their overrides
+func (c *Client) AdminGetFeatureFlags(ctx context.Context) ([]Flag, error) {
+	var out []Flag
+	if err := c.do(ctx, http.MethodGet, "/admin/v1/feature-flags", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminSetFeatureFlagOverride calls PUT /admin/v1/feature-flags/{flag}/overrides: force a feature flag on or off
+func (c *Client) AdminSetFeatureFlagOverride(ctx context.Context, flag string, body SetOverridePayload) (*Override, error) {
+	var out Override
+	if err := c.do(ctx, http.MethodPut, "/admin/v1/feature-flags/"+url.PathEscape(flag)+"/overrides", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminDeleteFeatureFlagOverrideParams are the query parameters of AdminDeleteFeatureFlagOverride
+type AdminDeleteFeatureFlagOverrideParams struct {
+	Scope     string
+	SubjectID *string
+}
+
+func (p *AdminDeleteFeatureFlagOverrideParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	values.Set("scope", formatValue(p.Scope))
+	if p.SubjectID != nil {
+		values.Set("subjectId", formatValue(*p.SubjectID))
+	}
+	return values
+}
+
+// AdminDeleteFeatureFlagOverride calls DELETE /admin/v1/feature-flags/{flag}/overrides: remove a feature flag override
+func (c *Client) AdminDeleteFeatureFlagOverride(ctx context.Context, flag string, params *AdminDeleteFeatureFlagOverrideParams) error {
+	return c.do(ctx, http.MethodDelete, "/admin/v1/feature-flags/"+url.PathEscape(flag)+"/overrides", params.query(), nil, nil)
+}
+
+// AdminGetJobsParams are the query parameters of AdminGetJobs
+type AdminGetJobsParams struct {
+	Queue *string
+	State *string
+	Page  *int
+	Limit *int
+}
+
+func (p *AdminGetJobsParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Queue != nil {
+		values.Set("queue", formatValue(*p.Queue))
+	}
+	if p.State != nil {
+		values.Set("state", formatValue(*p.State))
+	}
+	if p.Page != nil {
+		values.Set("page", formatValue(*p.Page))
+	}
+	if p.Limit != nil {
+		values.Set("limit", formatValue(*p.Limit))
+	}
+	return values
+}
+
+// AdminGetJobs calls GET /admin/v1/jobs: list failed background jobs
+func (c *Client) AdminGetJobs(ctx context.Context, params *AdminGetJobsParams) (*PaginatedResponseJob, error) {
+	var out PaginatedResponseJob
+	if err := c.do(ctx, http.MethodGet, "/admin/v1/jobs", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminGetJobsIter iterates over every page of AdminGetJobs, starting at params.Page
+func (c *Client) AdminGetJobsIter(ctx context.Context, params *AdminGetJobsParams) iter.Seq2[Job, error] {
+	next := AdminGetJobsParams{}
+	if params != nil {
+		next = *params
+	}
+
+	return paginate(next.Page, func(page int) ([]Job, int, error) {
+		next.Page = &page
+		res, err := c.AdminGetJobs(ctx, &next)
+		if err != nil {
+			return nil, 0, err
+		}
+		return res.Data, res.TotalPages, nil
+	})
+}
+
+// AdminRetryJob calls POST /admin/v1/jobs/{queue}/{id}/retry: retry a failed background job
+func (c *Client) AdminRetryJob(ctx context.Context, queue string, id string) error {
+	return c.do(ctx, http.MethodPost, "/admin/v1/jobs/"+url.PathEscape(queue)+"/"+url.PathEscape(id)+"/retry", nil, nil, nil)
+}
+
+// AdminGetWorkspaceRestore calls GET /admin/v1/restores/{id}: get the progress of a workspace restore
+func (c *Client) AdminGetWorkspaceRestore(ctx context.Context, id string) (*Restore, error) {
+	var out Restore
+	if err := c.do(ctx, http.MethodGet, "/admin/v1/restores/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminGetRetentionPolicies calls GET /admin/v1/retention-policies: list the default and per workspace retention policies
+func (c *Client) AdminGetRetentionPolicies(ctx context.Context) (*Overview, error) {
+	var out Overview
+	if err := c.do(ctx, http.MethodGet, "/admin/v1/retention-policies", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminPreviewRetentionPolicyParams are the query parameters of AdminPreviewRetentionPolicy
+type AdminPreviewRetentionPolicyParams struct {
+	WorkspaceID *string
+}
+
+func (p *AdminPreviewRetentionPolicyParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.WorkspaceID != nil {
+		values.Set("workspaceId", formatValue(*p.WorkspaceID))
+	}
+	return values
+}
+
+// AdminPreviewRetentionPolicy calls GET /admin/v1/retention-policies/preview: report what a retention policy would delete
+func (c *Client) AdminPreviewRetentionPolicy(ctx context.Context, params *AdminPreviewRetentionPolicyParams) (*Report, error) {
+	var out Report
+	if err := c.do(ctx, http.MethodGet, "/admin/v1/retention-policies/preview", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminSetRetentionPolicy calls PUT /admin/v1/retention-policies/{workspaceId}: set the retention policy of a workspace
+func (c *Client) AdminSetRetentionPolicy(ctx context.Context, workspaceID string, body SetPolicyPayload) (*Policy, error) {
+	var out Policy
+	if err := c.do(ctx, http.MethodPut, "/admin/v1/retention-policies/"+url.PathEscape(workspaceID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminDeleteRetentionPolicy calls DELETE /admin/v1/retention-policies/{workspaceId}: remove the retention policy of a workspace
+func (c *Client) AdminDeleteRetentionPolicy(ctx context.Context, workspaceID string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/v1/retention-policies/"+url.PathEscape(workspaceID), nil, nil, nil)
+}
+
+// AdminGetServerMode calls GET /admin/v1/server-mode: get the maintenance and read-only mode
+func (c *Client) AdminGetServerMode(ctx context.Context) (*ServerMode, error) {
+	var out ServerMode
+	if err := c.do(ctx, http.MethodGet, "/admin/v1/server-mode", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminSetServerMode calls PUT /admin/v1/server-mode: switch every instance to maintenance, read-only or normal mode
+func (c *Client) AdminSetServerMode(ctx context.Context, body SetServerModePayload) (*ServerMode, error) {
+	var out ServerMode
+	if err := c.do(ctx, http.MethodPut, "/admin/v1/server-mode", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminGetIncidentsParams are the query parameters of AdminGetIncidents
+type AdminGetIncidentsParams struct {
+	Days *int
+}
+
+func (p *AdminGetIncidentsParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Days != nil {
+		values.Set("days", formatValue(*p.Days))
+	}
+	return values
+}
+
+// AdminGetIncidents calls GET /admin/v1/status/incidents: list the incidents of the status page
+func (c *Client) AdminGetIncidents(ctx context.Context, params *AdminGetIncidentsParams) ([]Incident, error) {
+	var out []Incident
+	if err := c.do(ctx, http.MethodGet, "/admin/v1/status/incidents", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminCreateIncident calls POST /admin/v1/status/incidents: announce an incident on the status page
+func (c *Client) AdminCreateIncident(ctx context.Context, body CreateIncidentPayload) (*Incident, error) {
+	var out Incident
+	if err := c.do(ctx, http.MethodPost, "/admin/v1/status/incidents", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminUpdateIncident calls PATCH /admin/v1/status/incidents/{id}: follow up an incident of the status page
+func (c *Client) AdminUpdateIncident(ctx context.Context, id string, body UpdateIncidentPayload) (*Incident, error) {
+	var out Incident
+	if err := c.do(ctx, http.MethodPatch, "/admin/v1/status/incidents/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminDeleteIncident calls DELETE /admin/v1/status/incidents/{id}: take an incident off the status page
+func (c *Client) AdminDeleteIncident(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/v1/status/incidents/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// AdminGetUser calls GET /admin/v1/users/{id}: look up a user
+func (c *Client) AdminGetUser(ctx context.Context, id string) (*User, error) {
+	var out User
+	if err := c.do(ctx, http.MethodGet, "/admin/v1/users/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminGetStorageQuota calls GET /admin/v1/users/{id}/storage-quota: get the attachment storage use and quota of a user
+func (c *Client) AdminGetStorageQuota(ctx context.Context, id string) (*UserStorage, error) {
+	var out UserStorage
+	if err := c.do(ctx, http.MethodGet, "/admin/v1/users/"+url.PathEscape(id)+"/storage-quota", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminSetStorageQuota calls PUT /admin/v1/users/{id}/storage-quota: override the attachment storage quota of a user
+func (c *Client) AdminSetStorageQuota(ctx context.Context, id string, body SetStorageQuotaPayload) (*UserStorage, error) {
+	var out UserStorage
+	if err := c.do(ctx, http.MethodPut, "/admin/v1/users/"+url.PathEscape(id)+"/storage-quota", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminDeleteStorageQuota calls DELETE /admin/v1/users/{id}/storage-quota: remove the storage quota override of a user
+func (c *Client) AdminDeleteStorageQuota(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/admin/v1/users/"+url.PathEscape(id)+"/storage-quota", nil, nil, nil)
+}
+
+// AdminGetWorkspace calls GET /admin/v1/workspaces/{workspaceId}: inspect a workspace
+func (c *Client) AdminGetWorkspace(ctx context.Context, workspaceID string) (*Workspace, error) {
+	var out Workspace
+	if err := c.do(ctx, http.MethodGet, "/admin/v1/workspaces/"+url.PathEscape(workspaceID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminGetWorkspaceBackups calls GET /admin/v1/workspaces/{workspaceId}/backups: list the backups of a workspace
+func (c *Client) AdminGetWorkspaceBackups(ctx context.Context, workspaceID string) ([]Backup, error) {
+	var out []Backup
+	if err := c.do(ctx, http.MethodGet, "/admin/v1/workspaces/"+url.PathEscape(workspaceID)+"/backups", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminCreateWorkspaceBackup calls POST /admin/v1/workspaces/{workspaceId}/backups: back up a workspace to S3
+func (c *Client) AdminCreateWorkspaceBackup(ctx context.Context, workspaceID string) (*Backup, error) {
+	var out Backup
+	if err := c.do(ctx, http.MethodPost, "/admin/v1/workspaces/"+url.PathEscape(workspaceID)+"/backups", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetApikEys calls GET /api/v1/api-keys: list the API keys of the user
+func (c *Client) GetApikEys(ctx context.Context) ([]Key, error) {
+	var out []Key
+	if err := c.do(ctx, http.MethodGet, "/api/v1/api-keys", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateApikEy calls POST /api/v1/api-keys: create an API key, its secret is only returned once
+func (c *Client) CreateApikEy(ctx context.Context, body CreateKeyPayload) (*CreatedKey, error) {
+	var out CreatedKey
+	if err := c.do(ctx, http.MethodPost, "/api/v1/api-keys", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteApikEy calls DELETE /api/v1/api-keys/{id}: revoke an API key
+func (c *Client) DeleteApikEy(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/api-keys/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// GetCsrftOken calls GET /api/v1/auth/csrf: get the CSRF token to send with the unsafe requests of a cookie session
+func (c *Client) GetCsrftOken(ctx context.Context) (*CSRFToken, error) {
+	var out CSRFToken
+	if err := c.do(ctx, http.MethodGet, "/api/v1/auth/csrf", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateCookieSession calls POST /api/v1/auth/session: move the session token into an HTTP-only cookie
+func (c *Client) CreateCookieSession(ctx context.Context) (*CookieSession, error) {
+	var out CookieSession
+	if err := c.do(ctx, http.MethodPost, "/api/v1/auth/session", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteCookieSession calls DELETE /api/v1/auth/session: clear the cookies of the cookie session
+func (c *Client) DeleteCookieSession(ctx context.Context) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/auth/session", nil, nil, nil)
+}
+
+// ExecuteBatch calls POST /api/v1/batch: execute several requests at once
+func (c *Client) ExecuteBatch(ctx context.Context, body BatchPayload) (*BatchResponse, error) {
+	var out BatchResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/batch", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCategoriesParams are the query parameters of GetCategories
+type GetCategoriesParams struct {
+	Page   *int
+	Limit  *int
+	Sort   *string
+	Order  *string
+	Search *string
+}
+
+func (p *GetCategoriesParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Page != nil {
+		values.Set("page", formatValue(*p.Page))
+	}
+	if p.Limit != nil {
+		values.Set("limit", formatValue(*p.Limit))
+	}
+	if p.Sort != nil {
+		values.Set("sort", formatValue(*p.Sort))
+	}
+	if p.Order != nil {
+		values.Set("order", formatValue(*p.Order))
+	}
+	if p.Search != nil {
+		values.Set("search", formatValue(*p.Search))
+	}
+	return values
+}
+
+// GetCategories calls GET /api/v1/categories: list categories
+func (c *Client) GetCategories(ctx context.Context, params *GetCategoriesParams) (*PaginatedResponseCategory, error) {
+	var out PaginatedResponseCategory
+	if err := c.do(ctx, http.MethodGet, "/api/v1/categories", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCategoriesIter iterates over every page of GetCategories, starting at params.Page
+func (c *Client) GetCategoriesIter(ctx context.Context, params *GetCategoriesParams) iter.Seq2[Category, error] {
+	next := GetCategoriesParams{}
+	if params != nil {
+		next = *params
+	}
+
+	return paginate(next.Page, func(page int) ([]Category, int, error) {
+		next.Page = &page
+		res, err := c.GetCategories(ctx, &next)
+		if err != nil {
+			return nil, 0, err
+		}
+		return res.Data, res.TotalPages, nil
+	})
+}
+
+// CreateCategory calls POST /api/v1/categories: create a category
+func (c *Client) CreateCategory(ctx context.Context, body CreateCategoryPayload) (*Category, error) {
+	var out Category
+	if err := c.do(ctx, http.MethodPost, "/api/v1/categories", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ExportCategoryBundleParams are the query parameters of ExportCategoryBundle
+type ExportCategoryBundleParams struct {
+	Ids         *string
+	Name        *string
+	Description *string
+}
+
+func (p *ExportCategoryBundleParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Ids != nil {
+		values.Set("ids", formatValue(*p.Ids))
+	}
+	if p.Name != nil {
+		values.Set("name", formatValue(*p.Name))
+	}
+	if p.Description != nil {
+		values.Set("description", formatValue(*p.Description))
+	}
+	return values
+}
+
+// ExportCategoryBundle calls GET /api/v1/categories/export: export categories as a shareable bundle
+func (c *Client) ExportCategoryBundle(ctx context.Context, params *ExportCategoryBundleParams) (*Bundle, error) {
+	var out Bundle
+	if err := c.do(ctx, http.MethodGet, "/api/v1/categories/export", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ImportCategoryBundle calls POST /api/v1/categories/import: import a category bundle
+func (c *Client) ImportCategoryBundle(ctx context.Context, body ImportBundlePayload) (*ImportResult, error) {
+	var out ImportResult
+	if err := c.do(ctx, http.MethodPost, "/api/v1/categories/import", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateCategory calls PATCH /api/v1/categories/{id}: update a category
+func (c *Client) UpdateCategory(ctx context.Context, id string, body UpdateCategoryPayload) (*Category, error) {
+	var out Category
+	if err := c.do(ctx, http.MethodPatch, "/api/v1/categories/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteCategory calls DELETE /api/v1/categories/{id}: delete a category
+func (c *Client) DeleteCategory(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/categories/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// GetChangesParams are the query parameters of GetChanges
+type GetChangesParams struct {
+	Cursor *string
+	Limit  *int
+}
+
+func (p *GetChangesParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Cursor != nil {
+		values.Set("cursor", formatValue(*p.Cursor))
+	}
+	if p.Limit != nil {
+		values.Set("limit", formatValue(*p.Limit))
+	}
+	return values
+}
+
+// GetChanges calls GET /api/v1/changes: list changes after a cursor
+func (c *Client) GetChanges(ctx context.Context, params *GetChangesParams) (*ChangesResponse, error) {
+	var out ChangesResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/changes", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetChangesIter follows the cursor of GetChanges until the feed is caught up, starting at params.Cursor
+func (c *Client) GetChangesIter(ctx context.Context, params *GetChangesParams) iter.Seq2[Change, error] {
+	next := GetChangesParams{}
+	if params != nil {
+		next = *params
+	}
+
+	return follow(next.Cursor, func(cursor *string) ([]Change, string, bool, error) {
+		next.Cursor = cursor
+		res, err := c.GetChanges(ctx, &next)
+		if err != nil {
+			return nil, "", false, err
+		}
+		return res.Changes, res.NextCursor, res.HasMore, nil
+	})
+}
+
+// UpdateComment calls PATCH /api/v1/comments/{id}: update a comment
+func (c *Client) UpdateComment(ctx context.Context, id string, body UpdateCommentPayload) (*Comment, error) {
+	var out Comment
+	if err := c.do(ctx, http.MethodPatch, "/api/v1/comments/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteComment calls DELETE /api/v1/comments/{id}: delete a comment
+func (c *Client) DeleteComment(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/comments/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// RequestAccountExport calls POST /api/v1/export/account: request an export of all account data
+func (c *Client) RequestAccountExport(ctx context.Context) (*AccountExport, error) {
+	var out AccountExport
+	if err := c.do(ctx, http.MethodPost, "/api/v1/export/account", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAccountExport calls GET /api/v1/export/account/{id}: get an account export and its download link
+func (c *Client) GetAccountExport(ctx context.Context, id string) (*AccountExport, error) {
+	var out AccountExport
+	if err := c.do(ctx, http.MethodGet, "/api/v1/export/account/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RequestTodoListExport calls POST /api/v1/export/todos: request a PDF of the todos matching the filters
+func (c *Client) RequestTodoListExport(ctx context.Context, body RequestTodoListExportPayload) (*AccountExport, error) {
+	var out AccountExport
+	if err := c.do(ctx, http.MethodPost, "/api/v1/export/todos", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTodoListExport calls GET /api/v1/export/todos/{id}: get a todo list PDF export and its download link
+func (c *Client) GetTodoListExport(ctx context.Context, id string) (*AccountExport, error) {
+	var out AccountExport
+	if err := c.do(ctx, http.MethodGet, "/api/v1/export/todos/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFocusSessionsParams are the query parameters of GetFocusSessions
+type GetFocusSessionsParams struct {
+	TodoID *string
+	Limit  *int
+}
+
+func (p *GetFocusSessionsParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.TodoID != nil {
+		values.Set("todoId", formatValue(*p.TodoID))
+	}
+	if p.Limit != nil {
+		values.Set("limit", formatValue(*p.Limit))
+	}
+	return values
+}
+
+// GetFocusSessions calls GET /api/v1/focus/sessions: list the latest focus sessions
+func (c *Client) GetFocusSessions(ctx context.Context, params *GetFocusSessionsParams) ([]Session, error) {
+	var out []Session
+	if err := c.do(ctx, http.MethodGet, "/api/v1/focus/sessions", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StartFocusSession calls POST /api/v1/focus/sessions: start a focus session on a todo
+func (c *Client) StartFocusSession(ctx context.Context, body StartSessionPayload) (*Session, error) {
+	var out Session
+	if err := c.do(ctx, http.MethodPost, "/api/v1/focus/sessions", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCurrentFocusSession calls GET /api/v1/focus/sessions/current: get the open focus session
+func (c *Client) GetCurrentFocusSession(ctx context.Context) (*Session, error) {
+	var out Session
+	if err := c.do(ctx, http.MethodGet, "/api/v1/focus/sessions/current", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CompleteFocusSession calls POST /api/v1/focus/sessions/{id}/complete: complete a focus session
+func (c *Client) CompleteFocusSession(ctx context.Context, id string) (*Session, error) {
+	var out Session
+	if err := c.do(ctx, http.MethodPost, "/api/v1/focus/sessions/"+url.PathEscape(id)+"/complete", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PauseFocusSession calls POST /api/v1/focus/sessions/{id}/pause: pause the active focus session
+func (c *Client) PauseFocusSession(ctx context.Context, id string) (*Session, error) {
+	var out Session
+	if err := c.do(ctx, http.MethodPost, "/api/v1/focus/sessions/"+url.PathEscape(id)+"/pause", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ResumeFocusSession calls POST /api/v1/focus/sessions/{id}/resume: resume a paused focus session
+func (c *Client) ResumeFocusSession(ctx context.Context, id string) (*Session, error) {
+	var out Session
+	if err := c.do(ctx, http.MethodPost, "/api/v1/focus/sessions/"+url.PathEscape(id)+"/resume", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetGamification calls GET /api/v1/gamification: get streaks, badges and weekly goal progress
+func (c *Client) GetGamification(ctx context.Context) (*GamificationOverview, error) {
+	var out GamificationOverview
+	if err := c.do(ctx, http.MethodGet, "/api/v1/gamification", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetWeeklyGoal calls PUT /api/v1/gamification/goal: set the weekly completion goal
+func (c *Client) SetWeeklyGoal(ctx context.Context, body SetGoalPayload) (*GoalProgress, error) {
+	var out GoalProgress
+	if err := c.do(ctx, http.MethodPut, "/api/v1/gamification/goal", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteWeeklyGoal calls DELETE /api/v1/gamification/goal: remove the weekly completion goal
+func (c *Client) DeleteWeeklyGoal(ctx context.Context) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/gamification/goal", nil, nil, nil)
+}
+
+// ReceiveEmailReply calls POST /api/v1/inbound/email-replies: receive a reply to a notification email, for the inbound email provider
+func (c *Client) ReceiveEmailReply(ctx context.Context, body EmailReplyPayload) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/inbound/email-replies", nil, body, nil)
+}
+
+// GetInboxItemsParams are the query parameters of GetInboxItems
+type GetInboxItemsParams struct {
+	Limit *int
+}
+
+func (p *GetInboxItemsParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Limit != nil {
+		values.Set("limit", formatValue(*p.Limit))
+	}
+	return values
+}
+
+// GetInboxItems calls GET /api/v1/inbox: list the inbox, oldest first
+func (c *Client) GetInboxItems(ctx context.Context, params *GetInboxItemsParams) ([]Item, error) {
+	var out []Item
+	if err := c.do(ctx, http.MethodGet, "/api/v1/inbox", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CaptureInboxItem calls POST /api/v1/inbox: capture a title into the inbox
+func (c *Client) CaptureInboxItem(ctx context.Context, body CapturePayload) (*Item, error) {
+	var out Item
+	if err := c.do(ctx, http.MethodPost, "/api/v1/inbox", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DiscardInboxItem calls DELETE /api/v1/inbox/{id}: discard an inbox item
+func (c *Client) DiscardInboxItem(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/inbox/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// TriageInboxItem calls POST /api/v1/inbox/{id}/triage: promote an inbox item into a todo
+func (c *Client) TriageInboxItem(ctx context.Context, id string, body TriagePayload) (*Todo, error) {
+	var out Todo
+	if err := c.do(ctx, http.MethodPost, "/api/v1/inbox/"+url.PathEscape(id)+"/triage", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AcceptWorkspaceInvite calls POST /api/v1/invites/{token}/accept: join a workspace through an invite link
+func (c *Client) AcceptWorkspaceInvite(ctx context.Context, token string) (*InviteAcceptance, error) {
+	var out InviteAcceptance
+	if err := c.do(ctx, http.MethodPost, "/api/v1/invites/"+url.PathEscape(token)+"/accept", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetLocale calls GET /api/v1/locale: get the locale the user chose
+func (c *Client) GetLocale(ctx context.Context) (*Preference, error) {
+	var out Preference
+	if err := c.do(ctx, http.MethodGet, "/api/v1/locale", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateLocale calls PUT /api/v1/locale: choose the locale of responses and emails
+func (c *Client) UpdateLocale(ctx context.Context, body UpdatePreferencePayload) (*Preference, error) {
+	var out Preference
+	if err := c.do(ctx, http.MethodPut, "/api/v1/locale", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ResetLocale calls DELETE /api/v1/locale: follow the Accept-Language of requests again
+func (c *Client) ResetLocale(ctx context.Context) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/locale", nil, nil, nil)
+}
+
+// GetMagicTags calls GET /api/v1/magic-tags: list the magic tags applied to todo titles
+func (c *Client) GetMagicTags(ctx context.Context) ([]MagicTag, error) {
+	var out []MagicTag
+	if err := c.do(ctx, http.MethodGet, "/api/v1/magic-tags", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateMagicTag calls POST /api/v1/magic-tags: create a magic tag such as #tomorrow or #p1
+func (c *Client) CreateMagicTag(ctx context.Context, body CreateMagicTagPayload) (*MagicTag, error) {
+	var out MagicTag
+	if err := c.do(ctx, http.MethodPost, "/api/v1/magic-tags", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateMagicTag calls PUT /api/v1/magic-tags/{id}: replace a magic tag
+func (c *Client) UpdateMagicTag(ctx context.Context, id string, body UpdateMagicTagPayload) (*MagicTag, error) {
+	var out MagicTag
+	if err := c.do(ctx, http.MethodPut, "/api/v1/magic-tags/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteMagicTag calls DELETE /api/v1/magic-tags/{id}: delete a magic tag
+func (c *Client) DeleteMagicTag(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/magic-tags/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// GetMatrix calls GET /api/v1/matrix: get the open todos bucketed into urgent and important quadrants
+func (c *Client) GetMatrix(ctx context.Context) (*Matrix, error) {
+	var out Matrix
+	if err := c.do(ctx, http.MethodGet, "/api/v1/matrix", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMatrixSettings calls GET /api/v1/matrix/settings: get the thresholds of the matrix
+func (c *Client) GetMatrixSettings(ctx context.Context) (*Settings, error) {
+	var out Settings
+	if err := c.do(ctx, http.MethodGet, "/api/v1/matrix/settings", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateMatrixSettings calls PATCH /api/v1/matrix/settings: update the thresholds of the matrix
+func (c *Client) UpdateMatrixSettings(ctx context.Context, body UpdateSettingsPayload) (*Settings, error) {
+	var out Settings
+	if err := c.do(ctx, http.MethodPatch, "/api/v1/matrix/settings", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAPIUsage calls GET /api/v1/me/api-usage: summarize the API usage of the caller over the last 30 days
+func (c *Client) GetAPIUsage(ctx context.Context) (*APIUsage, error) {
+	var out APIUsage
+	if err := c.do(ctx, http.MethodGet, "/api/v1/me/api-usage", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMyDayParams are the query parameters of GetMyDay
+type GetMyDayParams struct {
+	Tz *string
+}
+
+func (p *GetMyDayParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Tz != nil {
+		values.Set("tz", formatValue(*p.Tz))
+	}
+	return values
+}
+
+// GetMyDay calls GET /api/v1/my-day: get the todos picked for today and those left open to review
+func (c *Client) GetMyDay(ctx context.Context, params *GetMyDayParams) (*MyDay, error) {
+	var out MyDay
+	if err := c.do(ctx, http.MethodGet, "/api/v1/my-day", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDaySummariesParams are the query parameters of GetDaySummaries
+type GetDaySummariesParams struct {
+	Days *int
+	Tz   *string
+}
+
+func (p *GetDaySummariesParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Days != nil {
+		values.Set("days", formatValue(*p.Days))
+	}
+	if p.Tz != nil {
+		values.Set("tz", formatValue(*p.Tz))
+	}
+	return values
+}
+
+// GetDaySummaries calls GET /api/v1/my-day/summaries: get how much of the todos picked for each day got done
+func (c *Client) GetDaySummaries(ctx context.Context, params *GetDaySummariesParams) ([]DaySummary, error) {
+	var out []DaySummary
+	if err := c.do(ctx, http.MethodGet, "/api/v1/my-day/summaries", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AddMyDayTodos calls POST /api/v1/my-day/todos: pick todos for today without changing their due dates
+func (c *Client) AddMyDayTodos(ctx context.Context, body AddMyDayTodosPayload) (*MyDay, error) {
+	var out MyDay
+	if err := c.do(ctx, http.MethodPost, "/api/v1/my-day/todos", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveMyDayTodoParams are the query parameters of RemoveMyDayTodo
+type RemoveMyDayTodoParams struct {
+	Tz *string
+}
+
+func (p *RemoveMyDayTodoParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Tz != nil {
+		values.Set("tz", formatValue(*p.Tz))
+	}
+	return values
+}
+
+// RemoveMyDayTodo calls DELETE /api/v1/my-day/todos/{todoId}: take a todo off today or drop it from review
+func (c *Client) RemoveMyDayTodo(ctx context.Context, todoID string, params *RemoveMyDayTodoParams) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/my-day/todos/"+url.PathEscape(todoID), params.query(), nil, nil)
+}
+
+// GetPlannerParams are the query parameters of GetPlanner
+type GetPlannerParams struct {
+	Week     *string
+	Tz       *string
+	Capacity *int
+}
+
+func (p *GetPlannerParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Week != nil {
+		values.Set("week", formatValue(*p.Week))
+	}
+	if p.Tz != nil {
+		values.Set("tz", formatValue(*p.Tz))
+	}
+	if p.Capacity != nil {
+		values.Set("capacity", formatValue(*p.Capacity))
+	}
+	return values
+}
+
+// GetPlanner calls GET /api/v1/planner: get the todos of a week bucketed by day
+func (c *Client) GetPlanner(ctx context.Context, params *GetPlannerParams) (*Planner, error) {
+	var out Planner
+	if err := c.do(ctx, http.MethodGet, "/api/v1/planner", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetForecastParams are the query parameters of GetForecast
+type GetForecastParams struct {
+	Tz           *string
+	Capacity     *int
+	Priority     *string
+	CategoryID   *string
+	ParentTodoID *string
+	Tag          *string
+}
+
+func (p *GetForecastParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Tz != nil {
+		values.Set("tz", formatValue(*p.Tz))
+	}
+	if p.Capacity != nil {
+		values.Set("capacity", formatValue(*p.Capacity))
+	}
+	if p.Priority != nil {
+		values.Set("priority", formatValue(*p.Priority))
+	}
+	if p.CategoryID != nil {
+		values.Set("categoryId", formatValue(*p.CategoryID))
+	}
+	if p.ParentTodoID != nil {
+		values.Set("parentTodoId", formatValue(*p.ParentTodoID))
+	}
+	if p.Tag != nil {
+		values.Set("tag", formatValue(*p.Tag))
+	}
+	return values
+}
+
+// GetForecast calls GET /api/v1/planner/forecast: predict when open todos get done going by past estimates
+func (c *Client) GetForecast(ctx context.Context, params *GetForecastParams) (*Forecast, error) {
+	var out Forecast
+	if err := c.do(ctx, http.MethodGet, "/api/v1/planner/forecast", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ScheduleTodos calls POST /api/v1/planner/schedule: assign due dates to todos in bulk
+func (c *Client) ScheduleTodos(ctx context.Context, body SchedulePayload) ([]Todo, error) {
+	var out []Todo
+	if err := c.do(ctx, http.MethodPost, "/api/v1/planner/schedule", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetReportSchedules calls GET /api/v1/reports/schedules: list the scheduled reports
+func (c *Client) GetReportSchedules(ctx context.Context) ([]Schedule, error) {
+	var out []Schedule
+	if err := c.do(ctx, http.MethodGet, "/api/v1/reports/schedules", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateReportSchedule calls POST /api/v1/reports/schedules: schedule a report emailed to its recipients
+func (c *Client) CreateReportSchedule(ctx context.Context, body CreateSchedulePayload) (*Schedule, error) {
+	var out Schedule
+	if err := c.do(ctx, http.MethodPost, "/api/v1/reports/schedules", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetReportSchedule calls GET /api/v1/reports/schedules/{id}: get a scheduled report
+func (c *Client) GetReportSchedule(ctx context.Context, id string) (*Schedule, error) {
+	var out Schedule
+	if err := c.do(ctx, http.MethodGet, "/api/v1/reports/schedules/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateReportSchedule calls PUT /api/v1/reports/schedules/{id}: replace a scheduled report
+func (c *Client) UpdateReportSchedule(ctx context.Context, id string, body UpdateSchedulePayload) (*Schedule, error) {
+	var out Schedule
+	if err := c.do(ctx, http.MethodPut, "/api/v1/reports/schedules/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteReportSchedule calls DELETE /api/v1/reports/schedules/{id}: delete a scheduled report
+func (c *Client) DeleteReportSchedule(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/reports/schedules/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// PauseReportSchedule calls POST /api/v1/reports/schedules/{id}/pause: stop sending a scheduled report
+func (c *Client) PauseReportSchedule(ctx context.Context, id string) (*Schedule, error) {
+	var out Schedule
+	if err := c.do(ctx, http.MethodPost, "/api/v1/reports/schedules/"+url.PathEscape(id)+"/pause", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ResumeReportSchedule calls POST /api/v1/reports/schedules/{id}/resume: send a paused report again from its next time due
+func (c *Client) ResumeReportSchedule(ctx context.Context, id string) (*Schedule, error) {
+	var out Schedule
+	if err := c.do(ctx, http.MethodPost, "/api/v1/reports/schedules/"+url.PathEscape(id)+"/resume", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetRules calls GET /api/v1/rules: list automation rules
+func (c *Client) GetRules(ctx context.Context) ([]Rule, error) {
+	var out []Rule
+	if err := c.do(ctx, http.MethodGet, "/api/v1/rules", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateRule calls POST /api/v1/rules: create an automation rule
+func (c *Client) CreateRule(ctx context.Context, body CreateRulePayload) (*Rule, error) {
+	var out Rule
+	if err := c.do(ctx, http.MethodPost, "/api/v1/rules", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetRule calls GET /api/v1/rules/{id}: get an automation rule
+func (c *Client) GetRule(ctx context.Context, id string) (*Rule, error) {
+	var out Rule
+	if err := c.do(ctx, http.MethodGet, "/api/v1/rules/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateRule calls PATCH /api/v1/rules/{id}: update an automation rule
+func (c *Client) UpdateRule(ctx context.Context, id string, body UpdateRulePayload) (*Rule, error) {
+	var out Rule
+	if err := c.do(ctx, http.MethodPatch, "/api/v1/rules/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteRule calls DELETE /api/v1/rules/{id}: delete an automation rule
+func (c *Client) DeleteRule(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/rules/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// GetRuleExecutionsParams are the query parameters of GetRuleExecutions
+type GetRuleExecutionsParams struct {
+	Limit *int
+}
+
+func (p *GetRuleExecutionsParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Limit != nil {
+		values.Set("limit", formatValue(*p.Limit))
+	}
+	return values
+}
+
+// GetRuleExecutions calls GET /api/v1/rules/{id}/executions: list the latest executions of an automation rule
+func (c *Client) GetRuleExecutions(ctx context.Context, id string, params *GetRuleExecutionsParams) ([]Execution, error) {
+	var out []Execution
+	if err := c.do(ctx, http.MethodGet, "/api/v1/rules/"+url.PathEscape(id)+"/executions", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SearchTodosParams are the query parameters of SearchTodos
+type SearchTodosParams struct {
+	Q     string
+	Mode  *string
+	Limit *int
+}
+
+func (p *SearchTodosParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	values.Set("q", formatValue(p.Q))
+	if p.Mode != nil {
+		values.Set("mode", formatValue(*p.Mode))
+	}
+	if p.Limit != nil {
+		values.Set("limit", formatValue(*p.Limit))
+	}
+	return values
+}
+
+// SearchTodos calls GET /api/v1/search: search todos by keyword or meaning
+func (c *Client) SearchTodos(ctx context.Context, params *SearchTodosParams) (*Results, error) {
+	var out Results
+	if err := c.do(ctx, http.MethodGet, "/api/v1/search", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetStatsOverviewParams are the query parameters of GetStatsOverview
+type GetStatsOverviewParams struct {
+	Days *int
+}
+
+func (p *GetStatsOverviewParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Days != nil {
+		values.Set("days", formatValue(*p.Days))
+	}
+	return values
+}
+
+// GetStatsOverview calls GET /api/v1/stats/overview: get dashboard stats
+func (c *Client) GetStatsOverview(ctx context.Context, params *GetStatsOverviewParams) (*StatsOverview, error) {
+	var out StatsOverview
+	if err := c.do(ctx, http.MethodGet, "/api/v1/stats/overview", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetStatsProductivityParams are the query parameters of GetStatsProductivity
+type GetStatsProductivityParams struct {
+	Days *int
+}
+
+func (p *GetStatsProductivityParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Days != nil {
+		values.Set("days", formatValue(*p.Days))
+	}
+	return values
+}
+
+// GetStatsProductivity calls GET /api/v1/stats/productivity: get productivity analytics over a window
+func (c *Client) GetStatsProductivity(ctx context.Context, params *GetStatsProductivityParams) (*Productivity, error) {
+	var out Productivity
+	if err := c.do(ctx, http.MethodGet, "/api/v1/stats/productivity", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SuggestParams are the query parameters of Suggest
+type SuggestParams struct {
+	Q     string
+	Limit *int
+}
+
+func (p *SuggestParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	values.Set("q", formatValue(p.Q))
+	if p.Limit != nil {
+		values.Set("limit", formatValue(*p.Limit))
+	}
+	return values
+}
+
+// Suggest calls GET /api/v1/suggest: suggest todos, categories and tags as the user types
+func (c *Client) Suggest(ctx context.Context, params *SuggestParams) (*Suggestions, error) {
+	var out Suggestions
+	if err := c.do(ctx, http.MethodGet, "/api/v1/suggest", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SyncTodos calls POST /api/v1/sync: push offline changes and pull updates
+func (c *Client) SyncTodos(ctx context.Context, body SyncTodosPayload) (*SyncResponse, error) {
+	var out SyncResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/sync", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWorkspaceTemplates calls GET /api/v1/templates: list the templates of the active workspace
+func (c *Client) GetWorkspaceTemplates(ctx context.Context) ([]Template, error) {
+	var out []Template
+	if err := c.do(ctx, http.MethodGet, "/api/v1/templates", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ApplyWorkspaceTemplate calls POST /api/v1/templates/{id}/apply: create a todo and its subtasks from a workspace template
+func (c *Client) ApplyWorkspaceTemplate(ctx context.Context, id string, body ApplyTemplatePayload) (*Todo, error) {
+	var out Todo
+	if err := c.do(ctx, http.MethodPost, "/api/v1/templates/"+url.PathEscape(id)+"/apply", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTodosParams are the query parameters of GetTodos
+type GetTodosParams struct {
+	Page         *int
+	Limit        *int
+	Sort         *string
+	Order        *string
+	Search       *string
+	Status       *string
+	Priority     *string
+	CategoryID   *string
+	ParentTodoID *string
+	DueFrom      *time.Time
+	DueTo        *time.Time
+	Overdue      *bool
+	Completed    *bool
+	Unread       *bool
+	Fields       *string
+	Expand       *string
+}
+
+func (p *GetTodosParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Page != nil {
+		values.Set("page", formatValue(*p.Page))
+	}
+	if p.Limit != nil {
+		values.Set("limit", formatValue(*p.Limit))
+	}
+	if p.Sort != nil {
+		values.Set("sort", formatValue(*p.Sort))
+	}
+	if p.Order != nil {
+		values.Set("order", formatValue(*p.Order))
+	}
+	if p.Search != nil {
+		values.Set("search", formatValue(*p.Search))
+	}
+	if p.Status != nil {
+		values.Set("status", formatValue(*p.Status))
+	}
+	if p.Priority != nil {
+		values.Set("priority", formatValue(*p.Priority))
+	}
+	if p.CategoryID != nil {
+		values.Set("categoryId", formatValue(*p.CategoryID))
+	}
+	if p.ParentTodoID != nil {
+		values.Set("parentTodoId", formatValue(*p.ParentTodoID))
+	}
+	if p.DueFrom != nil {
+		values.Set("dueFrom", formatValue(*p.DueFrom))
+	}
+	if p.DueTo != nil {
+		values.Set("dueTo", formatValue(*p.DueTo))
+	}
+	if p.Overdue != nil {
+		values.Set("overdue", formatValue(*p.Overdue))
+	}
+	if p.Completed != nil {
+		values.Set("completed", formatValue(*p.Completed))
+	}
+	if p.Unread != nil {
+		values.Set("unread", formatValue(*p.Unread))
+	}
+	if p.Fields != nil {
+		values.Set("fields", formatValue(*p.Fields))
+	}
+	if p.Expand != nil {
+		values.Set("expand", formatValue(*p.Expand))
+	}
+	return values
+}
+
+// GetTodos calls GET /api/v1/todos: list todos
+func (c *Client) GetTodos(ctx context.Context, params *GetTodosParams) (*PaginatedResponsePopulatedTodo, error) {
+	var out PaginatedResponsePopulatedTodo
+	if err := c.do(ctx, http.MethodGet, "/api/v1/todos", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTodosIter iterates over every page of GetTodos, starting at params.Page
+func (c *Client) GetTodosIter(ctx context.Context, params *GetTodosParams) iter.Seq2[PopulatedTodo, error] {
+	next := GetTodosParams{}
+	if params != nil {
+		next = *params
+	}
+
+	return paginate(next.Page, func(page int) ([]PopulatedTodo, int, error) {
+		next.Page = &page
+		res, err := c.GetTodos(ctx, &next)
+		if err != nil {
+			return nil, 0, err
+		}
+		return res.Data, res.TotalPages, nil
+	})
+}
+
+// CreateTodo calls POST /api/v1/todos: create a todo
+func (c *Client) CreateTodo(ctx context.Context, body CreateTodoPayload) (*CreatedTodo, error) {
+	var out CreatedTodo
+	if err := c.do(ctx, http.MethodPost, "/api/v1/todos", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CompleteTodos calls POST /api/v1/todos/complete: complete several todos at once
+func (c *Client) CompleteTodos(ctx context.Context, body CompleteTodosPayload) (*BulkCompletion, error) {
+	var out BulkCompletion
+	if err := c.do(ctx, http.MethodPost, "/api/v1/todos/complete", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetStockCoverImages calls GET /api/v1/todos/covers/stock: list the stock images todo covers can be picked from
+func (c *Client) GetStockCoverImages(ctx context.Context) ([]StockImage, error) {
+	var out []StockImage
+	if err := c.do(ctx, http.MethodGet, "/api/v1/todos/covers/stock", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetTodoStats calls GET /api/v1/todos/stats: get todo statistics
+func (c *Client) GetTodoStats(ctx context.Context) (*TodoStats, error) {
+	var out TodoStats
+	if err := c.do(ctx, http.MethodGet, "/api/v1/todos/stats", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTodoByIDParams are the query parameters of GetTodoByID
+type GetTodoByIDParams struct {
+	Fields *string
+	Expand *string
+}
+
+func (p *GetTodoByIDParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Fields != nil {
+		values.Set("fields", formatValue(*p.Fields))
+	}
+	if p.Expand != nil {
+		values.Set("expand", formatValue(*p.Expand))
+	}
+	return values
+}
+
+// GetTodoByID calls GET /api/v1/todos/{id}: get a todo
+func (c *Client) GetTodoByID(ctx context.Context, id string, params *GetTodoByIDParams) (*PopulatedTodo, error) {
+	var out PopulatedTodo
+	if err := c.do(ctx, http.MethodGet, "/api/v1/todos/"+url.PathEscape(id), params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateTodo calls PATCH /api/v1/todos/{id}: update a todo
+func (c *Client) UpdateTodo(ctx context.Context, id string, body UpdateTodoPayload) (*Todo, error) {
+	var out Todo
+	if err := c.do(ctx, http.MethodPatch, "/api/v1/todos/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteTodo calls DELETE /api/v1/todos/{id}: delete a todo
+func (c *Client) DeleteTodo(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/todos/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// UploadTodoAttachment calls POST /api/v1/todos/{id}/attachments: upload a todo attachment
+func (c *Client) UploadTodoAttachment(ctx context.Context, id string, filename string, file io.Reader) (*TodoAttachment, error) {
+	var out TodoAttachment
+	if err := c.upload(ctx, http.MethodPost, "/api/v1/todos/"+url.PathEscape(id)+"/attachments", "file", filename, file, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteTodoAttachment calls DELETE /api/v1/todos/{id}/attachments/{attachmentId}: delete a todo attachment
+func (c *Client) DeleteTodoAttachment(ctx context.Context, id string, attachmentID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/todos/"+url.PathEscape(id)+"/attachments/"+url.PathEscape(attachmentID), nil, nil, nil)
+}
+
+// GetAttachmentPresignedURL calls GET /api/v1/todos/{id}/attachments/{attachmentId}/download: get an attachment download URL
+func (c *Client) GetAttachmentPresignedURL(ctx context.Context, id string, attachmentID string) (*GetAttachmentPresignedURLResponse, error) {
+	var out GetAttachmentPresignedURLResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/todos/"+url.PathEscape(id)+"/attachments/"+url.PathEscape(attachmentID)+"/download", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAttachmentThumbnailURL calls GET /api/v1/todos/{id}/attachments/{attachmentId}/thumbnail: get an image attachment thumbnail URL
+func (c *Client) GetAttachmentThumbnailURL(ctx context.Context, id string, attachmentID string) (*GetAttachmentThumbnailURLResponse, error) {
+	var out GetAttachmentThumbnailURLResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/todos/"+url.PathEscape(id)+"/attachments/"+url.PathEscape(attachmentID)+"/thumbnail", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCommentsByTodoID calls GET /api/v1/todos/{id}/comments: list comments of a todo
+func (c *Client) GetCommentsByTodoID(ctx context.Context, id string) ([]Comment, error) {
+	var out []Comment
+	if err := c.do(ctx, http.MethodGet, "/api/v1/todos/"+url.PathEscape(id)+"/comments", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AddComment calls POST /api/v1/todos/{id}/comments: comment on a todo
+func (c *Client) AddComment(ctx context.Context, id string, body AddCommentPayload) (*Comment, error) {
+	var out Comment
+	if err := c.do(ctx, http.MethodPost, "/api/v1/todos/"+url.PathEscape(id)+"/comments", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// MarkCommentsRead calls POST /api/v1/todos/{id}/comments/read: mark the comments of a todo as read
+func (c *Client) MarkCommentsRead(ctx context.Context, id string) (*Todo, error) {
+	var out Todo
+	if err := c.do(ctx, http.MethodPost, "/api/v1/todos/"+url.PathEscape(id)+"/comments/read", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTodoCover calls GET /api/v1/todos/{id}/cover: get the cover of a todo with links to its thumbnails
+func (c *Client) GetTodoCover(ctx context.Context, id string) (*Cover, error) {
+	var out Cover
+	if err := c.do(ctx, http.MethodGet, "/api/v1/todos/"+url.PathEscape(id)+"/cover", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetTodoCover calls PUT /api/v1/todos/{id}/cover: set the cover of a todo from an image attachment or a stock image
+func (c *Client) SetTodoCover(ctx context.Context, id string, body SetCoverPayload) (*Cover, error) {
+	var out Cover
+	if err := c.do(ctx, http.MethodPut, "/api/v1/todos/"+url.PathEscape(id)+"/cover", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteTodoCover calls DELETE /api/v1/todos/{id}/cover: remove the cover of a todo
+func (c *Client) DeleteTodoCover(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/todos/"+url.PathEscape(id)+"/cover", nil, nil, nil)
+}
+
+// GetTodoDependencies calls GET /api/v1/todos/{id}/dependencies: list the todos a todo waits on and the ones waiting on it
+func (c *Client) GetTodoDependencies(ctx context.Context, id string) (*Dependencies, error) {
+	var out Dependencies
+	if err := c.do(ctx, http.MethodGet, "/api/v1/todos/"+url.PathEscape(id)+"/dependencies", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AddTodoDependency calls POST /api/v1/todos/{id}/dependencies: make a todo wait on another
+func (c *Client) AddTodoDependency(ctx context.Context, id string, body AddDependencyPayload) (*Dependency, error) {
+	var out Dependency
+	if err := c.do(ctx, http.MethodPost, "/api/v1/todos/"+url.PathEscape(id)+"/dependencies", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveTodoDependency calls DELETE /api/v1/todos/{id}/dependencies/{dependsOnId}: stop a todo waiting on another
+func (c *Client) RemoveTodoDependency(ctx context.Context, id string, dependsOnID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/todos/"+url.PathEscape(id)+"/dependencies/"+url.PathEscape(dependsOnID), nil, nil, nil)
+}
+
+// ShiftDueDate calls POST /api/v1/todos/{id}/due-date: move a due date along with the todos waiting on it
+func (c *Client) ShiftDueDate(ctx context.Context, id string, body ShiftDueDatePayload) (*Shift, error) {
+	var out Shift
+	if err := c.do(ctx, http.MethodPost, "/api/v1/todos/"+url.PathEscape(id)+"/due-date", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PreviewDueDateShift calls POST /api/v1/todos/{id}/due-date/preview: preview moving a due date along with the todos waiting on it
+func (c *Client) PreviewDueDateShift(ctx context.Context, id string, body ShiftDueDatePayload) (*Shift, error) {
+	var out Shift
+	if err := c.do(ctx, http.MethodPost, "/api/v1/todos/"+url.PathEscape(id)+"/due-date/preview", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// MergeTodos calls POST /api/v1/todos/{id}/merge: merge duplicate todos into one
+func (c *Client) MergeTodos(ctx context.Context, id string, body MergeTodosPayload) (*Todo, error) {
+	var out Todo
+	if err := c.do(ctx, http.MethodPost, "/api/v1/todos/"+url.PathEscape(id)+"/merge", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTodoPrivateNote calls GET /api/v1/todos/{id}/private-note: get the private note the user wrote on a todo
+func (c *Client) GetTodoPrivateNote(ctx context.Context, id string) (*Note, error) {
+	var out Note
+	if err := c.do(ctx, http.MethodGet, "/api/v1/todos/"+url.PathEscape(id)+"/private-note", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetTodoPrivateNote calls PUT /api/v1/todos/{id}/private-note: write a private note on a todo, only its author can read it
+func (c *Client) SetTodoPrivateNote(ctx context.Context, id string, body SetNotePayload) (*Note, error) {
+	var out Note
+	if err := c.do(ctx, http.MethodPut, "/api/v1/todos/"+url.PathEscape(id)+"/private-note", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteTodoPrivateNote calls DELETE /api/v1/todos/{id}/private-note: delete the private note the user wrote on a todo
+func (c *Client) DeleteTodoPrivateNote(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/todos/"+url.PathEscape(id)+"/private-note", nil, nil, nil)
+}
+
+// MarkTodoRead calls POST /api/v1/todos/{id}/read: mark a todo and its comments as read
+func (c *Client) MarkTodoRead(ctx context.Context, id string) (*Todo, error) {
+	var out Todo
+	if err := c.do(ctx, http.MethodPost, "/api/v1/todos/"+url.PathEscape(id)+"/read", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SuggestSubtasks calls POST /api/v1/todos/{id}/suggest-subtasks: suggest a subtask breakdown of a todo
+func (c *Client) SuggestSubtasks(ctx context.Context, id string, body SuggestSubtasksPayload) (*SubtaskSuggestions, error) {
+	var out SubtaskSuggestions
+	if err := c.do(ctx, http.MethodPost, "/api/v1/todos/"+url.PathEscape(id)+"/suggest-subtasks", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UncompleteTodo calls POST /api/v1/todos/{id}/uncomplete: undo the completion of a todo shortly after it
+func (c *Client) UncompleteTodo(ctx context.Context, id string) (*Todo, error) {
+	var out Todo
+	if err := c.do(ctx, http.MethodPost, "/api/v1/todos/"+url.PathEscape(id)+"/uncomplete", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTodoVersions calls GET /api/v1/todos/{id}/versions: list the kept versions of a todo
+func (c *Client) GetTodoVersions(ctx context.Context, id string) ([]TodoVersion, error) {
+	var out []TodoVersion
+	if err := c.do(ctx, http.MethodGet, "/api/v1/todos/"+url.PathEscape(id)+"/versions", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RestoreTodoVersion calls POST /api/v1/todos/{id}/versions/{version}/restore: restore a todo to an earlier version
+func (c *Client) RestoreTodoVersion(ctx context.Context, id string, version string) (*Todo, error) {
+	var out Todo
+	if err := c.do(ctx, http.MethodPost, "/api/v1/todos/"+url.PathEscape(id)+"/versions/"+url.PathEscape(version)+"/restore", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUsageParams are the query parameters of GetUsage
+type GetUsageParams struct {
+	Scope       *string
+	Granularity *string
+	From        *time.Time
+	To          *time.Time
+}
+
+func (p *GetUsageParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Scope != nil {
+		values.Set("scope", formatValue(*p.Scope))
+	}
+	if p.Granularity != nil {
+		values.Set("granularity", formatValue(*p.Granularity))
+	}
+	if p.From != nil {
+		values.Set("from", formatValue(*p.From))
+	}
+	if p.To != nil {
+		values.Set("to", formatValue(*p.To))
+	}
+	return values
+}
+
+// GetUsage calls GET /api/v1/usage: get metered usage of the user or their workspace
+func (c *Client) GetUsage(ctx context.Context, params *GetUsageParams) (*Usage, error) {
+	var out Usage
+	if err := c.do(ctx, http.MethodGet, "/api/v1/usage", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWebhookEndpoints calls GET /api/v1/webhooks: list the webhook endpoints of the workspace
+func (c *Client) GetWebhookEndpoints(ctx context.Context) ([]Endpoint, error) {
+	var out []Endpoint
+	if err := c.do(ctx, http.MethodGet, "/api/v1/webhooks", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateWebhookEndpoint calls POST /api/v1/webhooks: register a webhook endpoint, its signing secret is only returned once
+func (c *Client) CreateWebhookEndpoint(ctx context.Context, body CreateEndpointPayload) (*CreatedEndpoint, error) {
+	var out CreatedEndpoint
+	if err := c.do(ctx, http.MethodPost, "/api/v1/webhooks", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWebhookEventTypes calls GET /api/v1/webhooks/events: list the webhook event types with the JSON Schema of their payloads
+func (c *Client) GetWebhookEventTypes(ctx context.Context) ([]EventType, error) {
+	var out []EventType
+	if err := c.do(ctx, http.MethodGet, "/api/v1/webhooks/events", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateWebhookEndpoint calls PATCH /api/v1/webhooks/{id}: update a webhook endpoint
+func (c *Client) UpdateWebhookEndpoint(ctx context.Context, id string, body UpdateEndpointPayload) (*Endpoint, error) {
+	var out Endpoint
+	if err := c.do(ctx, http.MethodPatch, "/api/v1/webhooks/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteWebhookEndpoint calls DELETE /api/v1/webhooks/{id}: delete a webhook endpoint
+func (c *Client) DeleteWebhookEndpoint(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/webhooks/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// TestWebhookEndpoint calls POST /api/v1/webhooks/{id}/test: send a signed sample payload to a webhook endpoint
+func (c *Client) TestWebhookEndpoint(ctx context.Context, id string, body TestEndpointPayload) (*TestDelivery, error) {
+	var out TestDelivery
+	if err := c.do(ctx, http.MethodPost, "/api/v1/webhooks/"+url.PathEscape(id)+"/test", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWorkspaceAgingPolicies calls GET /api/v1/workspaces/{workspaceId}/aging-policies: list the aging policies of a workspace
+func (c *Client) GetWorkspaceAgingPolicies(ctx context.Context, workspaceID string) ([]AgingPolicy, error) {
+	var out []AgingPolicy
+	if err := c.do(ctx, http.MethodGet, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/aging-policies", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateWorkspaceAgingPolicy calls POST /api/v1/workspaces/{workspaceId}/aging-policies: escalate the todos of a workspace left open too long
+func (c *Client) CreateWorkspaceAgingPolicy(ctx context.Context, workspaceID string, body CreateAgingPolicyPayload) (*AgingPolicy, error) {
+	var out AgingPolicy
+	if err := c.do(ctx, http.MethodPost, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/aging-policies", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteWorkspaceAgingPolicy calls DELETE /api/v1/workspaces/{workspaceId}/aging-policies/{policyId}: remove an aging policy of a workspace
+func (c *Client) DeleteWorkspaceAgingPolicy(ctx context.Context, workspaceID string, policyID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/aging-policies/"+url.PathEscape(policyID), nil, nil, nil)
+}
+
+// GetWorkspaceFreeze calls GET /api/v1/workspaces/{workspaceId}/freeze: get the freeze of a workspace and when its data is purged
+func (c *Client) GetWorkspaceFreeze(ctx context.Context, workspaceID string) (*Freeze, error) {
+	var out Freeze
+	if err := c.do(ctx, http.MethodGet, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/freeze", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FreezeWorkspace calls POST /api/v1/workspaces/{workspaceId}/freeze: freeze a workspace, export it and schedule its data for deletion
+func (c *Client) FreezeWorkspace(ctx context.Context, workspaceID string, body FreezeWorkspacePayload) (*Freeze, error) {
+	var out Freeze
+	if err := c.do(ctx, http.MethodPost, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/freeze", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReactivateWorkspace calls DELETE /api/v1/workspaces/{workspaceId}/freeze: lift the freeze of a workspace not purged yet
+func (c *Client) ReactivateWorkspace(ctx context.Context, workspaceID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/freeze", nil, nil, nil)
+}
+
+// GetWorkspaceInvites calls GET /api/v1/workspaces/{workspaceId}/invites: list the pending and closed invites of a workspace and who accepted them
+func (c *Client) GetWorkspaceInvites(ctx context.Context, workspaceID string) (*Invitations, error) {
+	var out Invitations
+	if err := c.do(ctx, http.MethodGet, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/invites", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateWorkspaceInvite calls POST /api/v1/workspaces/{workspaceId}/invites: create a shareable invite link to a workspace
+func (c *Client) CreateWorkspaceInvite(ctx context.Context, workspaceID string, body CreateInvitePayload) (*Invite, error) {
+	var out Invite
+	if err := c.do(ctx, http.MethodPost, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/invites", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokeWorkspaceInvite calls DELETE /api/v1/workspaces/{workspaceId}/invites/{inviteId}: stop an invite link of a workspace from being accepted
+func (c *Client) RevokeWorkspaceInvite(ctx context.Context, workspaceID string, inviteID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/invites/"+url.PathEscape(inviteID), nil, nil, nil)
+}
+
+// GetWorkspaceOnboardingKit calls GET /api/v1/workspaces/{workspaceId}/onboarding-kit: get the onboarding kit of a workspace
+func (c *Client) GetWorkspaceOnboardingKit(ctx context.Context, workspaceID string) (*OnboardingKit, error) {
+	var out OnboardingKit
+	if err := c.do(ctx, http.MethodGet, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/onboarding-kit", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetWorkspaceOnboardingKit calls PUT /api/v1/workspaces/{workspaceId}/onboarding-kit: set the categories and todos new members of a workspace get
+func (c *Client) SetWorkspaceOnboardingKit(ctx context.Context, workspaceID string, body SetOnboardingKitPayload) (*OnboardingKit, error) {
+	var out OnboardingKit
+	if err := c.do(ctx, http.MethodPut, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/onboarding-kit", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteWorkspaceOnboardingKit calls DELETE /api/v1/workspaces/{workspaceId}/onboarding-kit: remove the onboarding kit of a workspace
+func (c *Client) DeleteWorkspaceOnboardingKit(ctx context.Context, workspaceID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/onboarding-kit", nil, nil, nil)
+}
+
+// GetWorkspaceSlabReaches calls GET /api/v1/workspaces/{workspaceId}/sla-breaches: list the open todos breaching the aging policies of the workspace
+func (c *Client) GetWorkspaceSlabReaches(ctx context.Context, workspaceID string) (*BreachReport, error) {
+	var out BreachReport
+	if err := c.do(ctx, http.MethodGet, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/sla-breaches", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetManagedWorkspaceTemplates calls GET /api/v1/workspaces/{workspaceId}/templates: list the templates of a workspace
+func (c *Client) GetManagedWorkspaceTemplates(ctx context.Context, workspaceID string) ([]Template, error) {
+	var out []Template
+	if err := c.do(ctx, http.MethodGet, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/templates", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateWorkspaceTemplate calls POST /api/v1/workspaces/{workspaceId}/templates: publish a todo template to a workspace
+func (c *Client) CreateWorkspaceTemplate(ctx context.Context, workspaceID string, body CreateTemplatePayload) (*Template, error) {
+	var out Template
+	if err := c.do(ctx, http.MethodPost, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/templates", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteWorkspaceTemplate calls DELETE /api/v1/workspaces/{workspaceId}/templates/{templateId}: remove a template of a workspace
+func (c *Client) DeleteWorkspaceTemplate(ctx context.Context, workspaceID string, templateID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/templates/"+url.PathEscape(templateID), nil, nil, nil)
+}
+
+// GetWorkspaceWorkflow calls GET /api/v1/workspaces/{workspaceId}/workflow: get the statuses and priorities of the workspace
+func (c *Client) GetWorkspaceWorkflow(ctx context.Context, workspaceID string) (*Workflow, error) {
+	var out Workflow
+	if err := c.do(ctx, http.MethodGet, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/workflow", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetWorkspaceWorkflow calls PUT /api/v1/workspaces/{workspaceId}/workflow: replace the statuses and priority scale of a workspace, moving its todos onto them
+func (c *Client) SetWorkspaceWorkflow(ctx context.Context, workspaceID string, body SetWorkflowPayload) (*Workflow, error) {
+	var out Workflow
+	if err := c.do(ctx, http.MethodPut, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/workflow", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteWorkspaceWorkflow calls DELETE /api/v1/workspaces/{workspaceId}/workflow: bring a workspace back to the built-in statuses and priorities
+func (c *Client) DeleteWorkspaceWorkflow(ctx context.Context, workspaceID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/workflow", nil, nil, nil)
+}
+
+// GetWorkspaceWorkloadParams are the query parameters of GetWorkspaceWorkload
+type GetWorkspaceWorkloadParams struct {
+	Weeks    *int
+	Tz       *string
+	Capacity *int
+}
+
+func (p *GetWorkspaceWorkloadParams) query() url.Values {
+	values := url.Values{}
+	if p == nil {
+		return values
+	}
+
+	if p.Weeks != nil {
+		values.Set("weeks", formatValue(*p.Weeks))
+	}
+	if p.Tz != nil {
+		values.Set("tz", formatValue(*p.Tz))
+	}
+	if p.Capacity != nil {
+		values.Set("capacity", formatValue(*p.Capacity))
+	}
+	return values
+}
+
+// GetWorkspaceWorkload calls GET /api/v1/workspaces/{workspaceId}/workload: sum the open todos and estimates of every member per week
+func (c *Client) GetWorkspaceWorkload(ctx context.Context, workspaceID string, params *GetWorkspaceWorkloadParams) (*Workload, error) {
+	var out Workload
+	if err := c.do(ctx, http.MethodGet, "/api/v1/workspaces/"+url.PathEscape(workspaceID)+"/workload", params.query(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetLiveness calls GET /healthz: check that the API process is alive
+func (c *Client) GetLiveness(ctx context.Context) (*HealthReport, error) {
+	var out HealthReport
+	if err := c.do(ctx, http.MethodGet, "/healthz", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetReadiness calls GET /readyz: probe the dependencies of the API
+func (c *Client) GetReadiness(ctx context.Context) (*HealthReport, error) {
+	var out HealthReport
+	if err := c.do(ctx, http.MethodGet, "/readyz", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetStatusPage calls GET /status: get the component health history and incidents of the status page
+func (c *Client) GetStatusPage(ctx context.Context) (*Page, error) {
+	var out Page
+	if err := c.do(ctx, http.MethodGet, "/status", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// APIClient is the APIClient schema of the API
+type APIClient struct {
+	AllowedOrigins []string  `json:"allowedOrigins,omitempty"`
+	CreatedAt      time.Time `json:"createdAt,omitempty"`
+	Enabled        bool      `json:"enabled,omitempty"`
+	ID             string    `json:"id,omitempty"`
+	Name           string    `json:"name,omitempty"`
+	UpdatedAt      time.Time `json:"updatedAt,omitempty"`
+}
+
+// APIUsage is the APIUsage schema of the API
+type APIUsage struct {
+	From          time.Time         `json:"from,omitempty"`
+	Notifications NotificationStats `json:"notifications,omitempty"`
+	RateLimit     RateLimitStats    `json:"rateLimit,omitempty"`
+	Requests      RequestStats      `json:"requests,omitempty"`
+	To            time.Time         `json:"to,omitempty"`
+}
+
+// AccountExport is the AccountExport schema of the API
+type AccountExport struct {
+	Links         map[string]Link      `json:"_links,omitempty"`
+	CompletedAt   *time.Time           `json:"completedAt,omitempty"`
+	CreatedAt     time.Time            `json:"createdAt,omitempty"`
+	DownloadURL   *string              `json:"downloadUrl,omitempty"`
+	Error         *string              `json:"error,omitempty"`
+	ExpiresAt     *time.Time           `json:"expiresAt,omitempty"`
+	Filters       *ExportTodosPDFQuery `json:"filters,omitempty"`
+	ID            string               `json:"id,omitempty"`
+	Kind          string               `json:"kind,omitempty"`
+	LinkExpiresAt *time.Time           `json:"linkExpiresAt,omitempty"`
+	SizeBytes     *int                 `json:"sizeBytes,omitempty"`
+	Status        string               `json:"status,omitempty"`
+	UpdatedAt     time.Time            `json:"updatedAt,omitempty"`
+	UserID        string               `json:"userId,omitempty"`
+}
+
+// Accuracy is the Accuracy schema of the API
+type Accuracy struct {
+	AverageMinutes int     `json:"averageMinutes,omitempty"`
+	Ratio          float64 `json:"ratio,omitempty"`
+	SampleCount    int     `json:"sampleCount,omitempty"`
+}
+
+// Action is the Action schema of the API
+type Action struct {
+	Message string `json:"message,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Value   string `json:"value,omitempty"`
+}
+
+// AddCommentPayload is the AddCommentPayload schema of the API
+type AddCommentPayload struct {
+	Content string `json:"content"`
+}
+
+// AddDependencyPayload is the AddDependencyPayload schema of the API
+type AddDependencyPayload struct {
+	DependsOnID string `json:"dependsOnId"`
+}
+
+// AddMyDayTodosPayload is the AddMyDayTodosPayload schema of the API
+type AddMyDayTodosPayload struct {
+	Timezone *string  `json:"timezone,omitempty"`
+	TodoIds  []string `json:"todoIds"`
+}
+
+// AgingPolicy is the AgingPolicy schema of the API
+type AgingPolicy struct {
+	CreatedAt   time.Time `json:"createdAt,omitempty"`
+	CreatedBy   string    `json:"createdBy,omitempty"`
+	ID          string    `json:"id,omitempty"`
+	MaxAgeHours int       `json:"maxAgeHours,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	Notify      bool      `json:"notify,omitempty"`
+	Priority    *string   `json:"priority,omitempty"`
+	Tag         string    `json:"tag,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
+	WorkspaceID string    `json:"workspaceId,omitempty"`
+}
+
+// ApplyTemplatePayload is the ApplyTemplatePayload schema of the API
+type ApplyTemplatePayload struct {
+	CategoryID *string `json:"categoryId,omitempty"`
+}
+
+// Assignment is the Assignment schema of the API
+type Assignment struct {
+	Date   string `json:"date"`
+	TodoID string `json:"todoId"`
+}
+
+// Backup is the Backup schema of the API
+type Backup struct {
+	Links       map[string]Link `json:"_links,omitempty"`
+	CompletedAt *time.Time      `json:"completedAt,omitempty"`
+	Counts      map[string]int  `json:"counts,omitempty"`
+	CreatedAt   time.Time       `json:"createdAt,omitempty"`
+	Error       *string         `json:"error,omitempty"`
+	ID          string          `json:"id,omitempty"`
+	RequestedBy string          `json:"requestedBy,omitempty"`
+	SizeBytes   *int            `json:"sizeBytes,omitempty"`
+	Status      string          `json:"status,omitempty"`
+	UpdatedAt   time.Time       `json:"updatedAt,omitempty"`
+	WorkspaceID string          `json:"workspaceId,omitempty"`
+}
+
+// Badge is the Badge schema of the API
+type Badge struct {
+	AwardedAt   *time.Time `json:"awardedAt,omitempty"`
+	Code        string     `json:"code,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Name        string     `json:"name,omitempty"`
+}
+
+// BatchPayload is the BatchPayload schema of the API
+type BatchPayload struct {
+	Requests []SubRequest `json:"requests"`
+}
+
+// BatchResponse is the BatchResponse schema of the API
+type BatchResponse struct {
+	Responses []SubResponse `json:"responses,omitempty"`
+}
+
+// BlockedTodo is the BlockedTodo schema of the API
+type BlockedTodo struct {
+	BlockedBy []string `json:"blockedBy,omitempty"`
+	Title     string   `json:"title,omitempty"`
+	TodoID    string   `json:"todoId,omitempty"`
+}
+
+// Breach is the Breach schema of the API
+type Breach struct {
+	BreachedAt     time.Time `json:"breachedAt,omitempty"`
+	HoursOverLimit int       `json:"hoursOverLimit,omitempty"`
+	MaxAgeHours    int       `json:"maxAgeHours,omitempty"`
+	OpenedAt       time.Time `json:"openedAt,omitempty"`
+	PolicyID       string    `json:"policyId,omitempty"`
+	PolicyName     string    `json:"policyName,omitempty"`
+	Priority       string    `json:"priority,omitempty"`
+	TodoID         string    `json:"todoId,omitempty"`
+	TodoTitle      string    `json:"todoTitle,omitempty"`
+	UserID         string    `json:"userId,omitempty"`
+}
+
+// BreachReport is the BreachReport schema of the API
+type BreachReport struct {
+	Breaches    []Breach         `json:"breaches,omitempty"`
+	ByMember    []MemberBreaches `json:"byMember,omitempty"`
+	ByPolicy    []PolicyBreaches `json:"byPolicy,omitempty"`
+	GeneratedAt time.Time        `json:"generatedAt,omitempty"`
+	WorkspaceID string           `json:"workspaceId,omitempty"`
+}
+
+// Bucket is the Bucket schema of the API
+type Bucket struct {
+	APICalls      int       `json:"apiCalls,omitempty"`
+	Notifications int       `json:"notifications,omitempty"`
+	Start         time.Time `json:"start,omitempty"`
+	StorageBytes  *int      `json:"storageBytes,omitempty"`
+}
+
+// BulkCompletion is the BulkCompletion schema of the API
+type BulkCompletion struct {
+	AlreadyCompleted []string      `json:"alreadyCompleted,omitempty"`
+	Blocked          []BlockedTodo `json:"blocked,omitempty"`
+	Completed        []Todo        `json:"completed,omitempty"`
+}
+
+// Bundle is the Bundle schema of the API
+type Bundle struct {
+	Categories  []BundleCategory `json:"categories"`
+	Description *string          `json:"description,omitempty"`
+	ExportedAt  time.Time        `json:"exportedAt,omitempty"`
+	Format      string           `json:"format"`
+	Name        string           `json:"name"`
+	Version     int              `json:"version"`
+}
+
+// BundleCategory is the BundleCategory schema of the API
+type BundleCategory struct {
+	Color       string  `json:"color"`
+	Description *string `json:"description,omitempty"`
+	Name        string  `json:"name"`
+}
+
+// CSRFToken is the CSRFToken schema of the API
+type CSRFToken struct {
+	Token string `json:"token,omitempty"`
+}
+
+// CapturePayload is the CapturePayload schema of the API
+type CapturePayload struct {
+	Title string `json:"title"`
+}
+
+// Category is the Category schema of the API
+type Category struct {
+	Links         map[string]Link `json:"_links,omitempty"`
+	Color         string          `json:"color,omitempty"`
+	CreatedAt     time.Time       `json:"createdAt,omitempty"`
+	Description   *string         `json:"description,omitempty"`
+	ID            string          `json:"id,omitempty"`
+	Name          string          `json:"name,omitempty"`
+	OpenTodoCount int             `json:"openTodoCount,omitempty"`
+	UpdatedAt     time.Time       `json:"updatedAt,omitempty"`
+	UserID        string          `json:"userId,omitempty"`
+	Version       int             `json:"version,omitempty"`
+}
+
+// CategoryCompletion is the CategoryCompletion schema of the API
+type CategoryCompletion struct {
+	AverageCompletionSeconds *float64 `json:"averageCompletionSeconds,omitempty"`
+	CategoryID               string   `json:"categoryId,omitempty"`
+	Color                    string   `json:"color,omitempty"`
+	Completed                int      `json:"completed,omitempty"`
+	Name                     string   `json:"name,omitempty"`
+}
+
+// CategoryStats is the CategoryStats schema of the API
+type CategoryStats struct {
+	AverageCompletionSeconds *float64 `json:"averageCompletionSeconds,omitempty"`
+	CategoryID               string   `json:"categoryId,omitempty"`
+	Color                    string   `json:"color,omitempty"`
+	Completed                int      `json:"completed,omitempty"`
+	Name                     string   `json:"name,omitempty"`
+	Total                    int      `json:"total,omitempty"`
+}
+
+// CategorySuggestion is the CategorySuggestion schema of the API
+type CategorySuggestion struct {
+	CategoryID string  `json:"categoryId,omitempty"`
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+// Change is the Change schema of the API
+type Change struct {
+	Action     string    `json:"action,omitempty"`
+	CreatedAt  time.Time `json:"createdAt,omitempty"`
+	EntityID   string    `json:"entityId,omitempty"`
+	EntityType string    `json:"entityType,omitempty"`
+	Version    *int      `json:"version,omitempty"`
+}
+
+// ChangesResponse is the ChangesResponse schema of the API
+type ChangesResponse struct {
+	Changes    []Change `json:"changes,omitempty"`
+	HasMore    bool     `json:"hasMore,omitempty"`
+	NextCursor string   `json:"nextCursor,omitempty"`
+}
+
+// Check is the Check schema of the API
+type Check struct {
+	Critical  bool    `json:"critical,omitempty"`
+	Error     *string `json:"error,omitempty"`
+	LatencyMs int     `json:"latencyMs,omitempty"`
+	Status    string  `json:"status,omitempty"`
+}
+
+// Comment is the Comment schema of the API
+type Comment struct {
+	Links        map[string]Link `json:"_links,omitempty"`
+	Content      string          `json:"content,omitempty"`
+	CreatedAt    time.Time       `json:"createdAt,omitempty"`
+	ID           string          `json:"id,omitempty"`
+	LinkPreviews []Preview       `json:"linkPreviews,omitempty"`
+	TodoID       string          `json:"todoId,omitempty"`
+	UpdatedAt    time.Time       `json:"updatedAt,omitempty"`
+	UserID       string          `json:"userId,omitempty"`
+}
+
+// CompleteTodosPayload is the CompleteTodosPayload schema of the API
+type CompleteTodosPayload struct {
+	Ids []string `json:"ids"`
+}
+
+// ComponentStatus is the ComponentStatus schema of the API
+type ComponentStatus struct {
+	CheckedAt *time.Time      `json:"checkedAt,omitempty"`
+	Component string          `json:"component,omitempty"`
+	History   []StatuspageDay `json:"history,omitempty"`
+	Status    string          `json:"status,omitempty"`
+	Uptime    *float64        `json:"uptime,omitempty"`
+}
+
+// Conditions is the Conditions schema of the API
+type Conditions struct {
+	CategoryID    *string `json:"categoryId,omitempty"`
+	Priority      *string `json:"priority,omitempty"`
+	Tag           *string `json:"tag,omitempty"`
+	TitleContains *string `json:"titleContains,omitempty"`
+}
+
+// ConfigReload is the ConfigReload schema of the API
+type ConfigReload struct {
+	Applied         []string `json:"applied,omitempty"`
+	RestartRequired []string `json:"restartRequired,omitempty"`
+}
+
+// CookieSession is the CookieSession schema of the API
+type CookieSession struct {
+	CsrfToken string    `json:"csrfToken,omitempty"`
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+}
+
+// Cover is the Cover schema of the API
+type Cover struct {
+	AttachmentID *string           `json:"attachmentId,omitempty"`
+	CreatedAt    time.Time         `json:"createdAt,omitempty"`
+	Source       string            `json:"source,omitempty"`
+	Status       string            `json:"status,omitempty"`
+	StockKey     *string           `json:"stockKey,omitempty"`
+	TodoID       string            `json:"todoId,omitempty"`
+	UpdatedAt    time.Time         `json:"updatedAt,omitempty"`
+	Variants     map[string]string `json:"variants,omitempty"`
+}
+
+// CreateAPIClientPayload is the CreateAPIClientPayload schema of the API
+type CreateAPIClientPayload struct {
+	AllowedOrigins []string `json:"allowedOrigins"`
+	Enabled        *bool    `json:"enabled,omitempty"`
+	Name           string   `json:"name"`
+}
+
+// CreateAgingPolicyPayload is the CreateAgingPolicyPayload schema of the API
+type CreateAgingPolicyPayload struct {
+	MaxAgeHours int     `json:"maxAgeHours"`
+	Name        string  `json:"name"`
+	Notify      *bool   `json:"notify,omitempty"`
+	Priority    *string `json:"priority,omitempty"`
+	Tag         *string `json:"tag,omitempty"`
+}
+
+// CreateCategoryPayload is the CreateCategoryPayload schema of the API
+type CreateCategoryPayload struct {
+	Color       string  `json:"color"`
+	Description *string `json:"description,omitempty"`
+	Name        string  `json:"name"`
+}
+
+// CreateEndpointPayload is the CreateEndpointPayload schema of the API
+type CreateEndpointPayload struct {
+	Enabled *bool    `json:"enabled,omitempty"`
+	Events  []string `json:"events"`
+	URL     string   `json:"url"`
+}
+
+// CreateIncidentPayload is the CreateIncidentPayload schema of the API
+type CreateIncidentPayload struct {
+	Components []string   `json:"components"`
+	Impact     string     `json:"impact"`
+	Message    string     `json:"message"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	Status     *string    `json:"status,omitempty"`
+	Title      string     `json:"title"`
+}
+
+// CreateInvitePayload is the CreateInvitePayload schema of the API
+type CreateInvitePayload struct {
+	AllowedDomains []string `json:"allowedDomains,omitempty"`
+	ExpiresInHours *int     `json:"expiresInHours,omitempty"`
+	MaxUses        *int     `json:"maxUses,omitempty"`
+}
+
+// CreateKeyPayload is the CreateKeyPayload schema of the API
+type CreateKeyPayload struct {
+	Name string `json:"name"`
+}
+
+// CreateMagicTagPayload is the CreateMagicTagPayload schema of the API
+type CreateMagicTagPayload struct {
+	CategoryID *string `json:"categoryId,omitempty"`
+	DueInDays  *int    `json:"dueInDays,omitempty"`
+	Priority   *string `json:"priority,omitempty"`
+	Tag        string  `json:"tag"`
+	Timezone   *string `json:"timezone,omitempty"`
+}
+
+// CreateRulePayload is the CreateRulePayload schema of the API
+type CreateRulePayload struct {
+	Actions    []RuleAction `json:"actions"`
+	Conditions *Conditions  `json:"conditions,omitempty"`
+	Enabled    *bool        `json:"enabled,omitempty"`
+	Name       string       `json:"name"`
+	Trigger    string       `json:"trigger"`
+}
+
+// CreateSchedulePayload is the CreateSchedulePayload schema of the API
+type CreateSchedulePayload struct {
+	DayOfMonth *int     `json:"dayOfMonth,omitempty"`
+	Filters    Filters  `json:"filters,omitempty"`
+	Format     string   `json:"format"`
+	Frequency  string   `json:"frequency"`
+	Hour       int      `json:"hour,omitempty"`
+	Name       string   `json:"name"`
+	Recipients []string `json:"recipients"`
+	Timezone   string   `json:"timezone,omitempty"`
+	Weekday    *int     `json:"weekday,omitempty"`
+}
+
+// CreateTemplatePayload is the CreateTemplatePayload schema of the API
+type CreateTemplatePayload struct {
+	Description *string  `json:"description,omitempty"`
+	DueInDays   *int     `json:"dueInDays,omitempty"`
+	Name        string   `json:"name"`
+	Priority    *string  `json:"priority,omitempty"`
+	Subtasks    []string `json:"subtasks,omitempty"`
+	Title       string   `json:"title"`
+}
+
+// CreateTodoPayload is the CreateTodoPayload schema of the API
+type CreateTodoPayload struct {
+	CategoryID      *string    `json:"categoryId,omitempty"`
+	CheckDuplicates bool       `json:"checkDuplicates,omitempty"`
+	Description     *string    `json:"description,omitempty"`
+	DueDate         *time.Time `json:"dueDate,omitempty"`
+	Metadata        *Metadata  `json:"metadata,omitempty"`
+	ParentTodoID    *string    `json:"parentTodoId,omitempty"`
+	Priority        *string    `json:"priority,omitempty"`
+	Status          *string    `json:"status,omitempty"`
+	Title           string     `json:"title"`
+}
+
+// CreatedEndpoint is the CreatedEndpoint schema of the API
+type CreatedEndpoint struct {
+	CreatedAt   time.Time `json:"createdAt,omitempty"`
+	Enabled     bool      `json:"enabled,omitempty"`
+	Events      []string  `json:"events,omitempty"`
+	ID          string    `json:"id,omitempty"`
+	Secret      string    `json:"secret,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
+	URL         string    `json:"url,omitempty"`
+	WorkspaceID string    `json:"workspaceId,omitempty"`
+}
+
+// CreatedKey is the CreatedKey schema of the API
+type CreatedKey struct {
+	CreatedAt  time.Time  `json:"createdAt,omitempty"`
+	ID         string     `json:"id,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	Plan       string     `json:"plan,omitempty"`
+	Prefix     string     `json:"prefix,omitempty"`
+	Secret     string     `json:"secret,omitempty"`
+	UpdatedAt  time.Time  `json:"updatedAt,omitempty"`
+}
+
+// CreatedTodo is the CreatedTodo schema of the API
+type CreatedTodo struct {
+	Links                 map[string]Link      `json:"_links,omitempty"`
+	CategoryID            *string              `json:"categoryId,omitempty"`
+	CommentCount          int                  `json:"commentCount,omitempty"`
+	CommentsReadAt        *time.Time           `json:"commentsReadAt,omitempty"`
+	CompletedAt           *time.Time           `json:"completedAt,omitempty"`
+	CompletedSubtaskCount int                  `json:"completedSubtaskCount,omitempty"`
+	CreatedAt             time.Time            `json:"createdAt,omitempty"`
+	Description           string               `json:"description,omitempty"`
+	DueDate               *time.Time           `json:"dueDate,omitempty"`
+	Duplicates            []DuplicateCandidate `json:"duplicates,omitempty"`
+	ID                    string               `json:"id,omitempty"`
+	Metadata              *Metadata            `json:"metadata,omitempty"`
+	ParentTodoID          *string              `json:"parentTodoId,omitempty"`
+	Priority              string               `json:"priority,omitempty"`
+	PriorityLevel         int                  `json:"priorityLevel,omitempty"`
+	SortOrder             int                  `json:"sortOrder,omitempty"`
+	Status                string               `json:"status,omitempty"`
+	StatusCategory        string               `json:"statusCategory,omitempty"`
+	SubtaskCount          int                  `json:"subtaskCount,omitempty"`
+	Suggestions           *TodoSuggestions     `json:"suggestions,omitempty"`
+	Title                 string               `json:"title,omitempty"`
+	UnreadCommentCount    int                  `json:"unreadCommentCount,omitempty"`
+	UpdatedAt             time.Time            `json:"updatedAt,omitempty"`
+	UserID                string               `json:"userId,omitempty"`
+	Version               int                  `json:"version,omitempty"`
+	WorkspaceID           *string              `json:"workspaceId,omitempty"`
+}
+
+// DailyCompletions is the DailyCompletions schema of the API
+type DailyCompletions struct {
+	Completed int    `json:"completed,omitempty"`
+	Day       string `json:"day,omitempty"`
+}
+
+// DailyCount is the DailyCount schema of the API
+type DailyCount struct {
+	Day      time.Time `json:"day,omitempty"`
+	Requests int       `json:"requests,omitempty"`
+}
+
+// DatabasePoolStats is the DatabasePoolStats schema of the API
+type DatabasePoolStats struct {
+	Primary  PoolStats            `json:"primary,omitempty"`
+	Replicas map[string]PoolStats `json:"replicas,omitempty"`
+}
+
+// Day is the Day schema of the API
+type Day struct {
+	Date             string `json:"date,omitempty"`
+	EstimatedMinutes int    `json:"estimatedMinutes,omitempty"`
+	ForecastMinutes  int    `json:"forecastMinutes,omitempty"`
+	OverCapacity     bool   `json:"overCapacity,omitempty"`
+	Overcommitted    bool   `json:"overcommitted,omitempty"`
+	Todos            []Todo `json:"todos,omitempty"`
+	UnestimatedCount int    `json:"unestimatedCount,omitempty"`
+}
+
+// DaySummary is the DaySummary schema of the API
+type DaySummary struct {
+	Completed      int     `json:"completed,omitempty"`
+	CompletionRate float64 `json:"completionRate,omitempty"`
+	Date           string  `json:"date,omitempty"`
+	Planned        int     `json:"planned,omitempty"`
+	Rolled         int     `json:"rolled,omitempty"`
+}
+
+// Dependencies is the Dependencies schema of the API
+type Dependencies struct {
+	BlockedBy []Todo `json:"blockedBy,omitempty"`
+	Blocking  []Todo `json:"blocking,omitempty"`
+	TodoID    string `json:"todoId,omitempty"`
+}
+
+// Dependency is the Dependency schema of the API
+type Dependency struct {
+	CreatedAt   time.Time `json:"createdAt,omitempty"`
+	DependsOnID string    `json:"dependsOnId,omitempty"`
+	TodoID      string    `json:"todoId,omitempty"`
+}
+
+// DependencyChange is the DependencyChange schema of the API
+type DependencyChange struct {
+	From   *time.Time `json:"from,omitempty"`
+	Title  string     `json:"title,omitempty"`
+	To     time.Time  `json:"to,omitempty"`
+	TodoID string     `json:"todoId,omitempty"`
+}
+
+// DuplicateCandidate is the DuplicateCandidate schema of the API
+type DuplicateCandidate struct {
+	CategoryID *string `json:"categoryId,omitempty"`
+	ID         string  `json:"id,omitempty"`
+	Similarity float64 `json:"similarity,omitempty"`
+	Status     string  `json:"status,omitempty"`
+	Title      string  `json:"title,omitempty"`
+}
+
+// EmailReplyPayload is the EmailReplyPayload schema of the API
+type EmailReplyPayload struct {
+	From      string   `json:"from"`
+	MessageID string   `json:"messageId,omitempty"`
+	Text      string   `json:"text,omitempty"`
+	To        []string `json:"to"`
+}
+
+// Endpoint is the Endpoint schema of the API
+type Endpoint struct {
+	CreatedAt   time.Time `json:"createdAt,omitempty"`
+	Enabled     bool      `json:"enabled,omitempty"`
+	Events      []string  `json:"events,omitempty"`
+	ID          string    `json:"id,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
+	URL         string    `json:"url,omitempty"`
+	WorkspaceID string    `json:"workspaceId,omitempty"`
+}
+
+// Entry is the Entry schema of the API
+type Entry struct {
+	Action         string          `json:"action,omitempty"`
+	ActorID        string          `json:"actorId,omitempty"`
+	ActorRole      string          `json:"actorRole,omitempty"`
+	After          json.RawMessage `json:"after,omitempty"`
+	Before         json.RawMessage `json:"before,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt,omitempty"`
+	EntityID       *string         `json:"entityId,omitempty"`
+	EntityType     *string         `json:"entityType,omitempty"`
+	ID             int             `json:"id,omitempty"`
+	ImpersonatorID *string         `json:"impersonatorId,omitempty"`
+	IpAddress      *string         `json:"ipAddress,omitempty"`
+	RequestID      *string         `json:"requestId,omitempty"`
+	UserAgent      *string         `json:"userAgent,omitempty"`
+}
+
+// EventType is the EventType schema of the API
+type EventType struct {
+	Description string                     `json:"description,omitempty"`
+	Schema      map[string]json.RawMessage `json:"schema,omitempty"`
+	Type        string                     `json:"type,omitempty"`
+}
+
+// Execution is the Execution schema of the API
+type Execution struct {
+	ActionsApplied []string  `json:"actionsApplied,omitempty"`
+	CreatedAt      time.Time `json:"createdAt,omitempty"`
+	Depth          int       `json:"depth,omitempty"`
+	Error          *string   `json:"error,omitempty"`
+	ID             string    `json:"id,omitempty"`
+	RuleID         string    `json:"ruleId,omitempty"`
+	Status         string    `json:"status,omitempty"`
+	TodoID         string    `json:"todoId,omitempty"`
+	Trigger        string    `json:"trigger,omitempty"`
+	UserID         string    `json:"userId,omitempty"`
+}
+
+// ExportTodosPDFQuery is the ExportTodosPDFQuery schema of the API
+type ExportTodosPDFQuery struct {
+	CategoryID *string `json:"categoryId,omitempty"`
+	Priority   *string `json:"priority,omitempty"`
+	Search     *string `json:"search,omitempty"`
+	Status     *string `json:"status,omitempty"`
+	Tz         *string `json:"tz,omitempty"`
+}
+
+// FaultInjection is the FaultInjection schema of the API
+type FaultInjection struct {
+	Enabled   bool        `json:"enabled,omitempty"`
+	ExpiresAt *time.Time  `json:"expiresAt,omitempty"`
+	Rules     []FaultRule `json:"rules,omitempty"`
+	UpdatedAt *time.Time  `json:"updatedAt,omitempty"`
+	UpdatedBy *string     `json:"updatedBy,omitempty"`
+}
+
+// FaultRule is the FaultRule schema of the API
+type FaultRule struct {
+	DropPercent    int    `json:"dropPercent,omitempty"`
+	ErrorKind      string `json:"errorKind,omitempty"`
+	ErrorPercent   int    `json:"errorPercent,omitempty"`
+	LatencyMs      int    `json:"latencyMs,omitempty"`
+	LatencyPercent int    `json:"latencyPercent,omitempty"`
+	Method         string `json:"method,omitempty"`
+	Route          string `json:"route,omitempty"`
+}
+
+// FaultRulePayload is the FaultRulePayload schema of the API
+type FaultRulePayload struct {
+	DropPercent    int    `json:"dropPercent,omitempty"`
+	ErrorKind      string `json:"errorKind,omitempty"`
+	ErrorPercent   int    `json:"errorPercent,omitempty"`
+	LatencyMs      int    `json:"latencyMs,omitempty"`
+	LatencyPercent int    `json:"latencyPercent,omitempty"`
+	Method         string `json:"method,omitempty"`
+	Route          string `json:"route"`
+}
+
+// FieldError is the FieldError schema of the API
+type FieldError struct {
+	Code  string `json:"code,omitempty"`
+	Error string `json:"error,omitempty"`
+	Field string `json:"field,omitempty"`
+}
+
+// Filters is the Filters schema of the API
+type Filters struct {
+	CategoryID *string `json:"categoryId,omitempty"`
+	Priority   *string `json:"priority,omitempty"`
+	Search     *string `json:"search,omitempty"`
+	Status     *string `json:"status,omitempty"`
+}
+
+// Flag is the Flag schema of the API
+type Flag struct {
+	Enabled        bool       `json:"enabled,omitempty"`
+	Name           string     `json:"name,omitempty"`
+	Overrides      []Override `json:"overrides,omitempty"`
+	RolloutPercent int        `json:"rolloutPercent,omitempty"`
+}
+
+// FocusTotals is the FocusTotals schema of the API
+type FocusTotals struct {
+	FocusedMinutes int `json:"focusedMinutes,omitempty"`
+	Sessions       int `json:"sessions,omitempty"`
+}
+
+// Forecast is the Forecast schema of the API
+type Forecast struct {
+	Accuracy        Accuracy         `json:"accuracy,omitempty"`
+	CapacityMinutes int              `json:"capacityMinutes,omitempty"`
+	CompletionDate  *string          `json:"completionDate,omitempty"`
+	LateCount       int              `json:"lateCount,omitempty"`
+	Timezone        string           `json:"timezone,omitempty"`
+	Todos           []ForecastedTodo `json:"todos,omitempty"`
+	Truncated       bool             `json:"truncated,omitempty"`
+}
+
+// ForecastedTodo is the ForecastedTodo schema of the API
+type ForecastedTodo struct {
+	CompletionDate   string     `json:"completionDate,omitempty"`
+	DueDate          *time.Time `json:"dueDate,omitempty"`
+	EstimatedMinutes *int       `json:"estimatedMinutes,omitempty"`
+	ForecastMinutes  int        `json:"forecastMinutes,omitempty"`
+	Late             bool       `json:"late,omitempty"`
+	Priority         string     `json:"priority,omitempty"`
+	Title            string     `json:"title,omitempty"`
+	TodoID           string     `json:"todoId,omitempty"`
+}
+
+// Freeze is the Freeze schema of the API
+type Freeze struct {
+	BackupID    *string    `json:"backupId,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt,omitempty"`
+	FrozenBy    string     `json:"frozenBy,omitempty"`
+	PurgeAfter  time.Time  `json:"purgeAfter,omitempty"`
+	PurgedAt    *time.Time `json:"purgedAt,omitempty"`
+	Reason      *string    `json:"reason,omitempty"`
+	UpdatedAt   time.Time  `json:"updatedAt,omitempty"`
+	WorkspaceID string     `json:"workspaceId,omitempty"`
+}
+
+// FreezeWorkspacePayload is the FreezeWorkspacePayload schema of the API
+type FreezeWorkspacePayload struct {
+	Reason        *string `json:"reason,omitempty"`
+	RetentionDays *int    `json:"retentionDays,omitempty"`
+}
+
+// GCStats is the GCStats schema of the API
+type GCStats struct {
+	CpuFraction  float64    `json:"cpuFraction,omitempty"`
+	LastGcAt     *time.Time `json:"lastGcAt,omitempty"`
+	LastPauseMs  float64    `json:"lastPauseMs,omitempty"`
+	NextGcBytes  int        `json:"nextGcBytes,omitempty"`
+	NumForcedGc  int        `json:"numForcedGc,omitempty"`
+	NumGc        int        `json:"numGc,omitempty"`
+	PauseTotalMs float64    `json:"pauseTotalMs,omitempty"`
+}
+
+// GamificationOverview is the GamificationOverview schema of the API
+type GamificationOverview struct {
+	Badges    []Badge       `json:"badges,omitempty"`
+	Completed int           `json:"completed,omitempty"`
+	Goal      *GoalProgress `json:"goal,omitempty"`
+	Streaks   Streaks       `json:"streaks,omitempty"`
+}
+
+// GoalProgress is the GoalProgress schema of the API
+type GoalProgress struct {
+	Completed int    `json:"completed,omitempty"`
+	Met       bool   `json:"met,omitempty"`
+	Target    int    `json:"target,omitempty"`
+	WeekStart string `json:"weekStart,omitempty"`
+}
+
+// HealthReport is the HealthReport schema of the API
+type HealthReport struct {
+	Checks      map[string]Check `json:"checks,omitempty"`
+	Environment string           `json:"environment,omitempty"`
+	Status      string           `json:"status,omitempty"`
+	Timestamp   time.Time        `json:"timestamp,omitempty"`
+}
+
+// HeatmapCell is the HeatmapCell schema of the API
+type HeatmapCell struct {
+	Completed int `json:"completed,omitempty"`
+	Hour      int `json:"hour,omitempty"`
+	Weekday   int `json:"weekday,omitempty"`
+}
+
+// ImportBundlePayload is the ImportBundlePayload schema of the API
+type ImportBundlePayload struct {
+	Bundle     Bundle  `json:"bundle"`
+	OnConflict *string `json:"onConflict,omitempty"`
+}
+
+// ImportResult is the ImportResult schema of the API
+type ImportResult struct {
+	Created []Category `json:"created,omitempty"`
+	Skipped []string   `json:"skipped,omitempty"`
+}
+
+// Incident is the Incident schema of the API
+type Incident struct {
+	Components []string   `json:"components,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt,omitempty"`
+	ID         string     `json:"id,omitempty"`
+	Impact     string     `json:"impact,omitempty"`
+	Message    string     `json:"message,omitempty"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+	StartedAt  time.Time  `json:"startedAt,omitempty"`
+	Status     string     `json:"status,omitempty"`
+	Title      string     `json:"title,omitempty"`
+	UpdatedAt  time.Time  `json:"updatedAt,omitempty"`
+}
+
+// Invitations is the Invitations schema of the API
+type Invitations struct {
+	Accepted    []InviteAcceptance `json:"accepted,omitempty"`
+	Closed      []Invite           `json:"closed,omitempty"`
+	Pending     []Invite           `json:"pending,omitempty"`
+	WorkspaceID string             `json:"workspaceId,omitempty"`
+}
+
+// Invite is the Invite schema of the API
+type Invite struct {
+	AllowedDomains []string   `json:"allowedDomains,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt,omitempty"`
+	CreatedBy      string     `json:"createdBy,omitempty"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+	ID             string     `json:"id,omitempty"`
+	MaxUses        *int       `json:"maxUses,omitempty"`
+	RevokedAt      *time.Time `json:"revokedAt,omitempty"`
+	Status         string     `json:"status,omitempty"`
+	Token          *string    `json:"token,omitempty"`
+	UpdatedAt      time.Time  `json:"updatedAt,omitempty"`
+	UseCount       int        `json:"useCount,omitempty"`
+	WorkspaceID    string     `json:"workspaceId,omitempty"`
+}
+
+// InviteAcceptance is the InviteAcceptance schema of the API
+type InviteAcceptance struct {
+	AcceptedAt  time.Time `json:"acceptedAt,omitempty"`
+	Email       string    `json:"email,omitempty"`
+	InviteID    string    `json:"inviteId,omitempty"`
+	UserID      string    `json:"userId,omitempty"`
+	WorkspaceID string    `json:"workspaceId,omitempty"`
+}
+
+// Item is the Item schema of the API
+type Item struct {
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+	ID        string    `json:"id,omitempty"`
+	Title     string    `json:"title,omitempty"`
+}
+
+// Job is the Job schema of the API
+type Job struct {
+	ID            string     `json:"id,omitempty"`
+	LastError     string     `json:"lastError,omitempty"`
+	LastFailedAt  *time.Time `json:"lastFailedAt,omitempty"`
+	MaxRetry      int        `json:"maxRetry,omitempty"`
+	NextProcessAt *time.Time `json:"nextProcessAt,omitempty"`
+	Payload       string     `json:"payload,omitempty"`
+	Queue         string     `json:"queue,omitempty"`
+	Retried       int        `json:"retried,omitempty"`
+	State         string     `json:"state,omitempty"`
+	Type          string     `json:"type,omitempty"`
+}
+
+// Key is the Key schema of the API
+type Key struct {
+	CreatedAt  time.Time  `json:"createdAt,omitempty"`
+	ID         string     `json:"id,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	Plan       string     `json:"plan,omitempty"`
+	Prefix     string     `json:"prefix,omitempty"`
+	UpdatedAt  time.Time  `json:"updatedAt,omitempty"`
+}
+
+// KitCategory is the KitCategory schema of the API
+type KitCategory struct {
+	Color       string  `json:"color"`
+	Description *string `json:"description,omitempty"`
+	Name        string  `json:"name"`
+}
+
+// KitTodo is the KitTodo schema of the API
+type KitTodo struct {
+	CategoryName *string `json:"categoryName,omitempty"`
+	Description  *string `json:"description,omitempty"`
+	DueInDays    *int    `json:"dueInDays,omitempty"`
+	Priority     *string `json:"priority,omitempty"`
+	Title        string  `json:"title"`
+}
+
+// Link is the Link schema of the API
+type Link struct {
+	Href string `json:"href,omitempty"`
+}
+
+// Load is the Load schema of the API
+type Load struct {
+	EstimatedMinutes int `json:"estimatedMinutes,omitempty"`
+	OpenTodos        int `json:"openTodos,omitempty"`
+	UnestimatedCount int `json:"unestimatedCount,omitempty"`
+}
+
+// MagicTag is the MagicTag schema of the API
+type MagicTag struct {
+	Links      map[string]Link `json:"_links,omitempty"`
+	CategoryID *string         `json:"categoryId,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt,omitempty"`
+	DueInDays  *int            `json:"dueInDays,omitempty"`
+	ID         string          `json:"id,omitempty"`
+	Priority   *string         `json:"priority,omitempty"`
+	Tag        string          `json:"tag,omitempty"`
+	Timezone   string          `json:"timezone,omitempty"`
+	UpdatedAt  time.Time       `json:"updatedAt,omitempty"`
+}
+
+// Matrix is the Matrix schema of the API
+type Matrix struct {
+	Delegate  []Todo   `json:"delegate,omitempty"`
+	Do        []Todo   `json:"do,omitempty"`
+	Eliminate []Todo   `json:"eliminate,omitempty"`
+	Schedule  []Todo   `json:"schedule,omitempty"`
+	Settings  Settings `json:"settings,omitempty"`
+	Truncated bool     `json:"truncated,omitempty"`
+}
+
+// MemberBreaches is the MemberBreaches schema of the API
+type MemberBreaches struct {
+	Breaches int    `json:"breaches,omitempty"`
+	UserID   string `json:"userId,omitempty"`
+}
+
+// MemberWorkload is the MemberWorkload schema of the API
+type MemberWorkload struct {
+	Overdue         Load       `json:"overdue,omitempty"`
+	OverloadedWeeks int        `json:"overloadedWeeks,omitempty"`
+	Unscheduled     Load       `json:"unscheduled,omitempty"`
+	UserID          string     `json:"userId,omitempty"`
+	Weeks           []WeekLoad `json:"weeks,omitempty"`
+}
+
+// MemoryStats is the MemoryStats schema of the API
+type MemoryStats struct {
+	Frees             int `json:"frees,omitempty"`
+	HeapAllocBytes    int `json:"heapAllocBytes,omitempty"`
+	HeapIdleBytes     int `json:"heapIdleBytes,omitempty"`
+	HeapInuseBytes    int `json:"heapInuseBytes,omitempty"`
+	HeapObjects       int `json:"heapObjects,omitempty"`
+	HeapReleasedBytes int `json:"heapReleasedBytes,omitempty"`
+	Mallocs           int `json:"mallocs,omitempty"`
+	StackInuseBytes   int `json:"stackInuseBytes,omitempty"`
+	SysBytes          int `json:"sysBytes,omitempty"`
+	TotalAllocBytes   int `json:"totalAllocBytes,omitempty"`
+}
+
+// MergeTodosPayload is the MergeTodosPayload schema of the API
+type MergeTodosPayload struct {
+	SourceIds []string `json:"sourceIds"`
+}
+
+// Metadata is the Metadata schema of the API
+type Metadata struct {
+	Color            *string  `json:"color,omitempty"`
+	Difficulty       *string  `json:"difficulty,omitempty"`
+	EstimatedMinutes *int     `json:"estimatedMinutes,omitempty"`
+	Reminder         *string  `json:"reminder,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+}
+
+// MyDay is the MyDay schema of the API
+type MyDay struct {
+	Date     string       `json:"date,omitempty"`
+	Review   []ReviewTodo `json:"review,omitempty"`
+	Summary  DaySummary   `json:"summary,omitempty"`
+	Timezone string       `json:"timezone,omitempty"`
+	Todos    []MyDayTodo  `json:"todos,omitempty"`
+}
+
+// MyDayTodo is the MyDayTodo schema of the API
+type MyDayTodo struct {
+	Links                 map[string]Link `json:"_links,omitempty"`
+	AddedAt               time.Time       `json:"addedAt,omitempty"`
+	CategoryID            *string         `json:"categoryId,omitempty"`
+	CommentCount          int             `json:"commentCount,omitempty"`
+	CommentsReadAt        *time.Time      `json:"commentsReadAt,omitempty"`
+	CompletedAt           *time.Time      `json:"completedAt,omitempty"`
+	CompletedSubtaskCount int             `json:"completedSubtaskCount,omitempty"`
+	CreatedAt             time.Time       `json:"createdAt,omitempty"`
+	Description           string          `json:"description,omitempty"`
+	DueDate               *time.Time      `json:"dueDate,omitempty"`
+	ID                    string          `json:"id,omitempty"`
+	Metadata              *Metadata       `json:"metadata,omitempty"`
+	ParentTodoID          *string         `json:"parentTodoId,omitempty"`
+	Priority              string          `json:"priority,omitempty"`
+	PriorityLevel         int             `json:"priorityLevel,omitempty"`
+	SortOrder             int             `json:"sortOrder,omitempty"`
+	Status                string          `json:"status,omitempty"`
+	StatusCategory        string          `json:"statusCategory,omitempty"`
+	SubtaskCount          int             `json:"subtaskCount,omitempty"`
+	Title                 string          `json:"title,omitempty"`
+	UnreadCommentCount    int             `json:"unreadCommentCount,omitempty"`
+	UpdatedAt             time.Time       `json:"updatedAt,omitempty"`
+	UserID                string          `json:"userId,omitempty"`
+	Version               int             `json:"version,omitempty"`
+	WorkspaceID           *string         `json:"workspaceId,omitempty"`
+}
+
+// Note is the Note schema of the API
+type Note struct {
+	Body      string    `json:"body,omitempty"`
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+	TodoID    string    `json:"todoId,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
+
+// NotificationStats is the NotificationStats schema of the API
+type NotificationStats struct {
+	DailyAverage float64 `json:"dailyAverage,omitempty"`
+	Delivered    int     `json:"delivered,omitempty"`
+}
+
+// OnboardingKit is the OnboardingKit schema of the API
+type OnboardingKit struct {
+	Categories  []KitCategory `json:"categories,omitempty"`
+	CreatedAt   time.Time     `json:"createdAt,omitempty"`
+	Todos       []KitTodo     `json:"todos,omitempty"`
+	UpdatedAt   time.Time     `json:"updatedAt,omitempty"`
+	UpdatedBy   string        `json:"updatedBy,omitempty"`
+	WorkspaceID string        `json:"workspaceId,omitempty"`
+}
+
+// OverdueRatio is the OverdueRatio schema of the API
+type OverdueRatio struct {
+	Due     int     `json:"due,omitempty"`
+	Overdue int     `json:"overdue,omitempty"`
+	Ratio   float64 `json:"ratio,omitempty"`
+}
+
+// Override is the Override schema of the API
+type Override struct {
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+	Enabled   bool      `json:"enabled,omitempty"`
+	Flag      string    `json:"flag,omitempty"`
+	ID        string    `json:"id,omitempty"`
+	Scope     string    `json:"scope,omitempty"`
+	SubjectID string    `json:"subjectId,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+	UpdatedBy string    `json:"updatedBy,omitempty"`
+}
+
+// Overview is the Overview schema of the API
+type Overview struct {
+	Default         Rules    `json:"default,omitempty"`
+	DefaultEnforced bool     `json:"defaultEnforced,omitempty"`
+	Policies        []Policy `json:"policies,omitempty"`
+}
+
+// Page is the Page schema of the API
+type Page struct {
+	Components  []ComponentStatus `json:"components,omitempty"`
+	HistoryDays int               `json:"historyDays,omitempty"`
+	Incidents   []Incident        `json:"incidents,omitempty"`
+	Status      string            `json:"status,omitempty"`
+}
+
+// PaginatedResponseCategory is the PaginatedResponseCategory schema of the API
+type PaginatedResponseCategory struct {
+	Links      map[string]Link `json:"_links,omitempty"`
+	Data       []Category      `json:"data,omitempty"`
+	Limit      int             `json:"limit,omitempty"`
+	Page       int             `json:"page,omitempty"`
+	Total      int             `json:"total,omitempty"`
+	TotalPages int             `json:"totalPages,omitempty"`
+}
+
+// PaginatedResponseEntry is the PaginatedResponseEntry schema of the API
+type PaginatedResponseEntry struct {
+	Links      map[string]Link `json:"_links,omitempty"`
+	Data       []Entry         `json:"data,omitempty"`
+	Limit      int             `json:"limit,omitempty"`
+	Page       int             `json:"page,omitempty"`
+	Total      int             `json:"total,omitempty"`
+	TotalPages int             `json:"totalPages,omitempty"`
+}
+
+// PaginatedResponseJob is the PaginatedResponseJob schema of the API
+type PaginatedResponseJob struct {
+	Links      map[string]Link `json:"_links,omitempty"`
+	Data       []Job           `json:"data,omitempty"`
+	Limit      int             `json:"limit,omitempty"`
+	Page       int             `json:"page,omitempty"`
+	Total      int             `json:"total,omitempty"`
+	TotalPages int             `json:"totalPages,omitempty"`
+}
+
+// PaginatedResponsePopulatedTodo is the PaginatedResponsePopulatedTodo schema of the API
+type PaginatedResponsePopulatedTodo struct {
+	Links      map[string]Link `json:"_links,omitempty"`
+	Data       []PopulatedTodo `json:"data,omitempty"`
+	Limit      int             `json:"limit,omitempty"`
+	Page       int             `json:"page,omitempty"`
+	Total      int             `json:"total,omitempty"`
+	TotalPages int             `json:"totalPages,omitempty"`
+}
+
+// Planner is the Planner schema of the API
+type Planner struct {
+	Accuracy        Accuracy `json:"accuracy,omitempty"`
+	Backlog         []Todo   `json:"backlog,omitempty"`
+	CapacityMinutes int      `json:"capacityMinutes,omitempty"`
+	Days            []Day    `json:"days,omitempty"`
+	Timezone        string   `json:"timezone,omitempty"`
+	Week            string   `json:"week,omitempty"`
+}
+
+// Policy is the Policy schema of the API
+type Policy struct {
+	ArchivedTodoMonths *int      `json:"archivedTodoMonths,omitempty"`
+	AttachmentDays     *int      `json:"attachmentDays,omitempty"`
+	CreatedAt          time.Time `json:"createdAt,omitempty"`
+	Enforce            bool      `json:"enforce,omitempty"`
+	MaxCommentsPerTodo *int      `json:"maxCommentsPerTodo,omitempty"`
+	UpdatedAt          time.Time `json:"updatedAt,omitempty"`
+	UpdatedBy          string    `json:"updatedBy,omitempty"`
+	WorkspaceID        string    `json:"workspaceId,omitempty"`
+}
+
+// PolicyBreaches is the PolicyBreaches schema of the API
+type PolicyBreaches struct {
+	Breaches   int    `json:"breaches,omitempty"`
+	PolicyID   string `json:"policyId,omitempty"`
+	PolicyName string `json:"policyName,omitempty"`
+}
+
+// PoolStats is the PoolStats schema of the API
+type PoolStats struct {
+	AcquireCount         int     `json:"acquireCount,omitempty"`
+	AcquireDurationMs    float64 `json:"acquireDurationMs,omitempty"`
+	AcquiredConns        int     `json:"acquiredConns,omitempty"`
+	CanceledAcquireCount int     `json:"canceledAcquireCount,omitempty"`
+	ConstructingConns    int     `json:"constructingConns,omitempty"`
+	EmptyAcquireCount    int     `json:"emptyAcquireCount,omitempty"`
+	IdleConns            int     `json:"idleConns,omitempty"`
+	MaxConns             int     `json:"maxConns,omitempty"`
+	TotalConns           int     `json:"totalConns,omitempty"`
+}
+
+// PopulatedTodo is the PopulatedTodo schema of the API
+type PopulatedTodo struct {
+	Links                 map[string]Link      `json:"_links,omitempty"`
+	Attachments           []TodoAttachment     `json:"attachments,omitempty"`
+	Category              *Category            `json:"category,omitempty"`
+	CategoryID            *string              `json:"categoryId,omitempty"`
+	Children              []Todo               `json:"children,omitempty"`
+	CommentCount          int                  `json:"commentCount,omitempty"`
+	Comments              []Comment            `json:"comments,omitempty"`
+	CommentsReadAt        *time.Time           `json:"commentsReadAt,omitempty"`
+	CompletedAt           *time.Time           `json:"completedAt,omitempty"`
+	CompletedSubtaskCount int                  `json:"completedSubtaskCount,omitempty"`
+	Cover                 *Cover               `json:"cover,omitempty"`
+	CreatedAt             time.Time            `json:"createdAt,omitempty"`
+	Description           string               `json:"description,omitempty"`
+	DueDate               *time.Time           `json:"dueDate,omitempty"`
+	ID                    string               `json:"id,omitempty"`
+	LinkPreviews          []Preview            `json:"linkPreviews,omitempty"`
+	Metadata              *Metadata            `json:"metadata,omitempty"`
+	ParentTodoID          *string              `json:"parentTodoId,omitempty"`
+	Priority              string               `json:"priority,omitempty"`
+	PriorityLevel         int                  `json:"priorityLevel,omitempty"`
+	SortOrder             int                  `json:"sortOrder,omitempty"`
+	Status                string               `json:"status,omitempty"`
+	StatusCategory        string               `json:"statusCategory,omitempty"`
+	SubtaskCount          int                  `json:"subtaskCount,omitempty"`
+	SuggestedReminders    []ReminderSuggestion `json:"suggestedReminders,omitempty"`
+	Title                 string               `json:"title,omitempty"`
+	Unread                bool                 `json:"unread,omitempty"`
+	UnreadCommentCount    int                  `json:"unreadCommentCount,omitempty"`
+	UpdatedAt             time.Time            `json:"updatedAt,omitempty"`
+	UserID                string               `json:"userId,omitempty"`
+	Version               int                  `json:"version,omitempty"`
+	ViewedAt              *time.Time           `json:"viewedAt,omitempty"`
+	WorkspaceID           *string              `json:"workspaceId,omitempty"`
+}
+
+// Preference is the Preference schema of the API
+type Preference struct {
+	Locale    *string  `json:"locale,omitempty"`
+	Supported []string `json:"supported,omitempty"`
+}
+
+// Preview is the Preview schema of the API
+type Preview struct {
+	Description *string   `json:"description,omitempty"`
+	FetchedAt   time.Time `json:"fetchedAt,omitempty"`
+	ImageURL    *string   `json:"imageUrl,omitempty"`
+	SiteName    *string   `json:"siteName,omitempty"`
+	Title       *string   `json:"title,omitempty"`
+	URL         string    `json:"url,omitempty"`
+}
+
+// PriorityCompletion is the PriorityCompletion schema of the API
+type PriorityCompletion struct {
+	AverageCompletionSeconds *float64 `json:"averageCompletionSeconds,omitempty"`
+	Completed                int      `json:"completed,omitempty"`
+	Priority                 string   `json:"priority,omitempty"`
+}
+
+// PrioritySuggestion is the PrioritySuggestion schema of the API
+type PrioritySuggestion struct {
+	Confidence float64 `json:"confidence,omitempty"`
+	Priority   string  `json:"priority,omitempty"`
+}
+
+// Problem is the Problem schema of the API
+type Problem struct {
+	Action    *Action      `json:"action,omitempty"`
+	Code      string       `json:"code,omitempty"`
+	Debug     string       `json:"debug,omitempty"`
+	Detail    string       `json:"detail,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Message   string       `json:"message,omitempty"`
+	Override  bool         `json:"override,omitempty"`
+	RequestID string       `json:"requestId,omitempty"`
+	Retryable bool         `json:"retryable,omitempty"`
+	Status    int          `json:"status,omitempty"`
+	Title     string       `json:"title,omitempty"`
+	Type      string       `json:"type,omitempty"`
+}
+
+// Productivity is the Productivity schema of the API
+type Productivity struct {
+	ByCategory   []CategoryCompletion `json:"byCategory,omitempty"`
+	ByPriority   []PriorityCompletion `json:"byPriority,omitempty"`
+	Days         int                  `json:"days,omitempty"`
+	Focus        FocusTotals          `json:"focus,omitempty"`
+	Heatmap      []HeatmapCell        `json:"heatmap,omitempty"`
+	OverdueRatio OverdueRatio         `json:"overdueRatio,omitempty"`
+	Since        time.Time            `json:"since,omitempty"`
+	Streaks      Streaks              `json:"streaks,omitempty"`
+}
+
+// RateLimitStats is the RateLimitStats schema of the API
+type RateLimitStats struct {
+	Burst               int        `json:"burst,omitempty"`
+	HourlyAllowance     int        `json:"hourlyAllowance,omitempty"`
+	PeakHour            *time.Time `json:"peakHour,omitempty"`
+	PeakHourRequests    int        `json:"peakHourRequests,omitempty"`
+	PeakHourUtilization float64    `json:"peakHourUtilization,omitempty"`
+	RequestsPerSecond   float64    `json:"requestsPerSecond,omitempty"`
+}
+
+// RedisPoolStats is the RedisPoolStats schema of the API
+type RedisPoolStats struct {
+	Hits       int `json:"hits,omitempty"`
+	IdleConns  int `json:"idleConns,omitempty"`
+	Misses     int `json:"misses,omitempty"`
+	StaleConns int `json:"staleConns,omitempty"`
+	Timeouts   int `json:"timeouts,omitempty"`
+	TotalConns int `json:"totalConns,omitempty"`
+}
+
+// ReminderSuggestion is the ReminderSuggestion schema of the API
+type ReminderSuggestion struct {
+	Basis      string    `json:"basis,omitempty"`
+	Confidence float64   `json:"confidence,omitempty"`
+	RemindAt   time.Time `json:"remindAt,omitempty"`
+	SampleSize int       `json:"sampleSize,omitempty"`
+}
+
+// Report is the Report schema of the API
+type Report struct {
+	ArchivedTodos   int    `json:"archivedTodos,omitempty"`
+	AttachmentBytes int    `json:"attachmentBytes,omitempty"`
+	Attachments     int    `json:"attachments,omitempty"`
+	Comments        int    `json:"comments,omitempty"`
+	DryRun          bool   `json:"dryRun,omitempty"`
+	Rules           Rules  `json:"rules,omitempty"`
+	WorkspaceID     string `json:"workspaceId,omitempty"`
+}
+
+// RequestStats is the RequestStats schema of the API
+type RequestStats struct {
+	Daily           []DailyCount `json:"daily,omitempty"`
+	DailyAverage    float64      `json:"dailyAverage,omitempty"`
+	PeakDay         *time.Time   `json:"peakDay,omitempty"`
+	PeakDayRequests int          `json:"peakDayRequests,omitempty"`
+	Total           int          `json:"total,omitempty"`
+}
+
+// RequestTodoListExportPayload is the RequestTodoListExportPayload schema of the API
+type RequestTodoListExportPayload struct {
+	CategoryID *string `json:"categoryId,omitempty"`
+	Priority   *string `json:"priority,omitempty"`
+	Search     *string `json:"search,omitempty"`
+	Status     *string `json:"status,omitempty"`
+	Tz         *string `json:"tz,omitempty"`
+}
+
+// Restore is the Restore schema of the API
+type Restore struct {
+	Links             map[string]Link `json:"_links,omitempty"`
+	BackupID          string          `json:"backupId,omitempty"`
+	CompletedAt       *time.Time      `json:"completedAt,omitempty"`
+	Counts            map[string]int  `json:"counts,omitempty"`
+	CreatedAt         time.Time       `json:"createdAt,omitempty"`
+	Error             *string         `json:"error,omitempty"`
+	ID                string          `json:"id,omitempty"`
+	RequestedBy       string          `json:"requestedBy,omitempty"`
+	Status            string          `json:"status,omitempty"`
+	TargetWorkspaceID string          `json:"targetWorkspaceId,omitempty"`
+	UpdatedAt         time.Time       `json:"updatedAt,omitempty"`
+}
+
+// RestoreBackupPayload is the RestoreBackupPayload schema of the API
+type RestoreBackupPayload struct {
+	TargetWorkspaceID string `json:"targetWorkspaceId"`
+}
+
+// Result is the Result schema of the API
+type Result struct {
+	Links                 map[string]Link `json:"_links,omitempty"`
+	CategoryID            *string         `json:"categoryId,omitempty"`
+	CommentCount          int             `json:"commentCount,omitempty"`
+	CommentsReadAt        *time.Time      `json:"commentsReadAt,omitempty"`
+	CompletedAt           *time.Time      `json:"completedAt,omitempty"`
+	CompletedSubtaskCount int             `json:"completedSubtaskCount,omitempty"`
+	CreatedAt             time.Time       `json:"createdAt,omitempty"`
+	Description           string          `json:"description,omitempty"`
+	DueDate               *time.Time      `json:"dueDate,omitempty"`
+	ID                    string          `json:"id,omitempty"`
+	Metadata              *Metadata       `json:"metadata,omitempty"`
+	ParentTodoID          *string         `json:"parentTodoId,omitempty"`
+	Priority              string          `json:"priority,omitempty"`
+	PriorityLevel         int             `json:"priorityLevel,omitempty"`
+	Score                 float64         `json:"score,omitempty"`
+	SortOrder             int             `json:"sortOrder,omitempty"`
+	Status                string          `json:"status,omitempty"`
+	StatusCategory        string          `json:"statusCategory,omitempty"`
+	SubtaskCount          int             `json:"subtaskCount,omitempty"`
+	Title                 string          `json:"title,omitempty"`
+	UnreadCommentCount    int             `json:"unreadCommentCount,omitempty"`
+	UpdatedAt             time.Time       `json:"updatedAt,omitempty"`
+	UserID                string          `json:"userId,omitempty"`
+	Version               int             `json:"version,omitempty"`
+	WorkspaceID           *string         `json:"workspaceId,omitempty"`
+}
+
+// Results is the Results schema of the API
+type Results struct {
+	Data []Result `json:"data,omitempty"`
+	Mode string   `json:"mode,omitempty"`
+}
+
+// ReviewTodo is the ReviewTodo schema of the API
+type ReviewTodo struct {
+	Links                 map[string]Link `json:"_links,omitempty"`
+	CategoryID            *string         `json:"categoryId,omitempty"`
+	CommentCount          int             `json:"commentCount,omitempty"`
+	CommentsReadAt        *time.Time      `json:"commentsReadAt,omitempty"`
+	CompletedAt           *time.Time      `json:"completedAt,omitempty"`
+	CompletedSubtaskCount int             `json:"completedSubtaskCount,omitempty"`
+	CreatedAt             time.Time       `json:"createdAt,omitempty"`
+	Description           string          `json:"description,omitempty"`
+	DueDate               *time.Time      `json:"dueDate,omitempty"`
+	ID                    string          `json:"id,omitempty"`
+	Metadata              *Metadata       `json:"metadata,omitempty"`
+	ParentTodoID          *string         `json:"parentTodoId,omitempty"`
+	PlannedFor            string          `json:"plannedFor,omitempty"`
+	Priority              string          `json:"priority,omitempty"`
+	PriorityLevel         int             `json:"priorityLevel,omitempty"`
+	SortOrder             int             `json:"sortOrder,omitempty"`
+	Status                string          `json:"status,omitempty"`
+	StatusCategory        string          `json:"statusCategory,omitempty"`
+	SubtaskCount          int             `json:"subtaskCount,omitempty"`
+	Title                 string          `json:"title,omitempty"`
+	UnreadCommentCount    int             `json:"unreadCommentCount,omitempty"`
+	UpdatedAt             time.Time       `json:"updatedAt,omitempty"`
+	UserID                string          `json:"userId,omitempty"`
+	Version               int             `json:"version,omitempty"`
+	WorkspaceID           *string         `json:"workspaceId,omitempty"`
+}
+
+// Rule is the Rule schema of the API
+type Rule struct {
+	Links      map[string]Link `json:"_links,omitempty"`
+	Actions    []RuleAction    `json:"actions,omitempty"`
+	Conditions Conditions      `json:"conditions,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt,omitempty"`
+	Enabled    bool            `json:"enabled,omitempty"`
+	ID         string          `json:"id,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	Trigger    string          `json:"trigger,omitempty"`
+	UpdatedAt  time.Time       `json:"updatedAt,omitempty"`
+	UserID     string          `json:"userId,omitempty"`
+}
+
+// RuleAction is the RuleAction schema of the API
+type RuleAction struct {
+	CategoryID *string `json:"categoryId,omitempty"`
+	DueInDays  *int    `json:"dueInDays,omitempty"`
+	Message    *string `json:"message,omitempty"`
+	Priority   *string `json:"priority,omitempty"`
+	Tag        *string `json:"tag,omitempty"`
+	Title      *string `json:"title,omitempty"`
+	Type       string  `json:"type"`
+}
+
+// Rules is the Rules schema of the API
+type Rules struct {
+	ArchivedTodoMonths *int `json:"archivedTodoMonths,omitempty"`
+	AttachmentDays     *int `json:"attachmentDays,omitempty"`
+	MaxCommentsPerTodo *int `json:"maxCommentsPerTodo,omitempty"`
+}
+
+// RuntimeStats is the RuntimeStats schema of the API
+type RuntimeStats struct {
+	Database   DatabasePoolStats `json:"database,omitempty"`
+	Gc         GCStats           `json:"gc,omitempty"`
+	GoVersion  string            `json:"goVersion,omitempty"`
+	Gomaxprocs int               `json:"gomaxprocs,omitempty"`
+	Goroutines int               `json:"goroutines,omitempty"`
+	Memory     MemoryStats       `json:"memory,omitempty"`
+	NumCpu     int               `json:"numCpu,omitempty"`
+	Redis      *RedisPoolStats   `json:"redis,omitempty"`
+	Timestamp  time.Time         `json:"timestamp,omitempty"`
+}
+
+// Schedule is the Schedule schema of the API
+type Schedule struct {
+	Links      map[string]Link `json:"_links,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt,omitempty"`
+	DayOfMonth *int            `json:"dayOfMonth,omitempty"`
+	Filters    Filters         `json:"filters,omitempty"`
+	Format     string          `json:"format,omitempty"`
+	Frequency  string          `json:"frequency"`
+	Hour       int             `json:"hour,omitempty"`
+	ID         string          `json:"id,omitempty"`
+	LastError  *string         `json:"lastError,omitempty"`
+	LastRunAt  *time.Time      `json:"lastRunAt,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	NextRunAt  time.Time       `json:"nextRunAt,omitempty"`
+	PausedAt   *time.Time      `json:"pausedAt,omitempty"`
+	Recipients []string        `json:"recipients,omitempty"`
+	Timezone   string          `json:"timezone,omitempty"`
+	UpdatedAt  time.Time       `json:"updatedAt,omitempty"`
+	Weekday    *int            `json:"weekday,omitempty"`
+}
+
+// SchedulePayload is the SchedulePayload schema of the API
+type SchedulePayload struct {
+	Assignments []Assignment `json:"assignments"`
+	Timezone    *string      `json:"timezone,omitempty"`
+}
+
+// ServerMode is the ServerMode schema of the API
+type ServerMode struct {
+	Message   string     `json:"message,omitempty"`
+	Mode      string     `json:"mode,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	UpdatedBy *string    `json:"updatedBy,omitempty"`
+}
+
+// Session is the Session schema of the API
+type Session struct {
+	Links          map[string]Link `json:"_links,omitempty"`
+	CompletedAt    *time.Time      `json:"completedAt,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt,omitempty"`
+	FocusedSeconds int             `json:"focusedSeconds,omitempty"`
+	ID             string          `json:"id,omitempty"`
+	PlannedMinutes int             `json:"plannedMinutes,omitempty"`
+	ResumedAt      *time.Time      `json:"resumedAt,omitempty"`
+	Status         string          `json:"status,omitempty"`
+	TodoID         string          `json:"todoId,omitempty"`
+	UpdatedAt      time.Time       `json:"updatedAt,omitempty"`
+	UserID         string          `json:"userId,omitempty"`
+}
+
+// SetCoverPayload is the SetCoverPayload schema of the API
+type SetCoverPayload struct {
+	AttachmentID *string `json:"attachmentId,omitempty"`
+	StockKey     *string `json:"stockKey,omitempty"`
+}
+
+// SetFaultInjectionPayload is the SetFaultInjectionPayload schema of the API
+type SetFaultInjectionPayload struct {
+	Rules      []FaultRulePayload `json:"rules"`
+	TTLSeconds *int               `json:"ttlSeconds,omitempty"`
+}
+
+// SetGoalPayload is the SetGoalPayload schema of the API
+type SetGoalPayload struct {
+	Target int `json:"target"`
+}
+
+// SetKeyPlanPayload is the SetKeyPlanPayload schema of the API
+type SetKeyPlanPayload struct {
+	Plan string `json:"plan"`
+}
+
+// SetNotePayload is the SetNotePayload schema of the API
+type SetNotePayload struct {
+	Body string `json:"body"`
+}
+
+// SetOnboardingKitPayload is the SetOnboardingKitPayload schema of the API
+type SetOnboardingKitPayload struct {
+	Categories []KitCategory `json:"categories,omitempty"`
+	Todos      []KitTodo     `json:"todos,omitempty"`
+}
+
+// SetOverridePayload is the SetOverridePayload schema of the API
+type SetOverridePayload struct {
+	Enabled   *bool  `json:"enabled"`
+	Scope     string `json:"scope"`
+	SubjectID string `json:"subjectId,omitempty"`
+}
+
+// SetPolicyPayload is the SetPolicyPayload schema of the API
+type SetPolicyPayload struct {
+	ArchivedTodoMonths *int  `json:"archivedTodoMonths,omitempty"`
+	AttachmentDays     *int  `json:"attachmentDays,omitempty"`
+	Enforce            *bool `json:"enforce"`
+	MaxCommentsPerTodo *int  `json:"maxCommentsPerTodo,omitempty"`
+}
+
+// SetServerModePayload is the SetServerModePayload schema of the API
+type SetServerModePayload struct {
+	Message string `json:"message,omitempty"`
+	Mode    string `json:"mode"`
+}
+
+// SetStorageQuotaPayload is the SetStorageQuotaPayload schema of the API
+type SetStorageQuotaPayload struct {
+	QuotaBytes *int `json:"quotaBytes"`
+}
+
+// SetWorkflowPayload is the SetWorkflowPayload schema of the API
+type SetWorkflowPayload struct {
+	Priorities      []WorkflowPriority `json:"priorities,omitempty"`
+	PriorityMapping map[string]string  `json:"priorityMapping,omitempty"`
+	StatusMapping   map[string]string  `json:"statusMapping,omitempty"`
+	Statuses        []WorkflowStatus   `json:"statuses,omitempty"`
+}
+
+// Settings is the Settings schema of the API
+type Settings struct {
+	ImportantPriority string `json:"importantPriority,omitempty"`
+	UrgentWithinHours int    `json:"urgentWithinHours,omitempty"`
+}
+
+// Shift is the Shift schema of the API
+type Shift struct {
+	Applied      bool               `json:"applied,omitempty"`
+	Changes      []DependencyChange `json:"changes,omitempty"`
+	ShiftSeconds int                `json:"shiftSeconds,omitempty"`
+	Skipped      []SkippedTodo      `json:"skipped,omitempty"`
+	TodoID       string             `json:"todoId,omitempty"`
+}
+
+// ShiftDueDatePayload is the ShiftDueDatePayload schema of the API
+type ShiftDueDatePayload struct {
+	Cascade *bool     `json:"cascade,omitempty"`
+	DueDate time.Time `json:"dueDate"`
+}
+
+// SkippedTodo is the SkippedTodo schema of the API
+type SkippedTodo struct {
+	Reason string `json:"reason,omitempty"`
+	Title  string `json:"title,omitempty"`
+	TodoID string `json:"todoId,omitempty"`
+}
+
+// StartSessionPayload is the StartSessionPayload schema of the API
+type StartSessionPayload struct {
+	PlannedMinutes *int   `json:"plannedMinutes,omitempty"`
+	TodoID         string `json:"todoId"`
+}
+
+// StatsOverview is the StatsOverview schema of the API
+type StatsOverview struct {
+	AverageCompletionSeconds *float64           `json:"averageCompletionSeconds,omitempty"`
+	Categories               []CategoryStats    `json:"categories,omitempty"`
+	Completed                int                `json:"completed,omitempty"`
+	CompletedPerDay          []DailyCompletions `json:"completedPerDay,omitempty"`
+	Open                     int                `json:"open,omitempty"`
+	RefreshedAt              *time.Time         `json:"refreshedAt,omitempty"`
+	Total                    int                `json:"total,omitempty"`
+}
+
+// StatuspageDay is the StatuspageDay schema of the API
+type StatuspageDay struct {
+	Checks   int       `json:"checks,omitempty"`
+	Day      time.Time `json:"day,omitempty"`
+	Degraded int       `json:"degraded,omitempty"`
+	Failures int       `json:"failures,omitempty"`
+}
+
+// StockImage is the StockImage schema of the API
+type StockImage struct {
+	Key  string `json:"key,omitempty"`
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// StorageQuota is the StorageQuota schema of the API
+type StorageQuota struct {
+	CreatedAt  time.Time `json:"createdAt,omitempty"`
+	QuotaBytes int       `json:"quotaBytes,omitempty"`
+	UpdatedAt  time.Time `json:"updatedAt,omitempty"`
+	UpdatedBy  string    `json:"updatedBy,omitempty"`
+	UserID     string    `json:"userId,omitempty"`
+}
+
+// Streaks is the Streaks schema of the API
+type Streaks struct {
+	Current int `json:"current,omitempty"`
+	Longest int `json:"longest,omitempty"`
+}
+
+// SubRequest is the SubRequest schema of the API
+type SubRequest struct {
+	Body    json.RawMessage   `json:"body,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	ID      string            `json:"id,omitempty"`
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+}
+
+// SubResponse is the SubResponse schema of the API
+type SubResponse struct {
+	Body    json.RawMessage   `json:"body,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	ID      string            `json:"id,omitempty"`
+	Status  int               `json:"status,omitempty"`
+}
+
+// SubtaskSuggestion is the SubtaskSuggestion schema of the API
+type SubtaskSuggestion struct {
+	Description string `json:"description,omitempty"`
+	Title       string `json:"title,omitempty"`
+}
+
+// SubtaskSuggestions is the SubtaskSuggestions schema of the API
+type SubtaskSuggestions struct {
+	Model       string              `json:"model,omitempty"`
+	Provider    string              `json:"provider,omitempty"`
+	Suggestions []SubtaskSuggestion `json:"suggestions,omitempty"`
+	TodoID      string              `json:"todoId,omitempty"`
+}
+
+// SuggestSubtasksPayload is the SuggestSubtasksPayload schema of the API
+type SuggestSubtasksPayload struct {
+	Limit *int `json:"limit,omitempty"`
+}
+
+// Suggestion is the Suggestion schema of the API
+type Suggestion struct {
+	ID    *string `json:"id,omitempty"`
+	Label string  `json:"label,omitempty"`
+	Score float64 `json:"score,omitempty"`
+	Type  string  `json:"type,omitempty"`
+}
+
+// Suggestions is the Suggestions schema of the API
+type Suggestions struct {
+	Data []Suggestion `json:"data,omitempty"`
+}
+
+// SyncChange is the SyncChange schema of the API
+type SyncChange struct {
+	Base        map[string]json.RawMessage `json:"base,omitempty"`
+	BaseVersion int                        `json:"baseVersion"`
+	Fields      map[string]json.RawMessage `json:"fields"`
+	TodoID      string                     `json:"todoId"`
+}
+
+// SyncChangeResult is the SyncChangeResult schema of the API
+type SyncChangeResult struct {
+	Conflicts []string `json:"conflicts,omitempty"`
+	Discarded []string `json:"discarded,omitempty"`
+	Status    string   `json:"status,omitempty"`
+	Todo      *Todo    `json:"todo,omitempty"`
+	TodoID    string   `json:"todoId,omitempty"`
+}
+
+// SyncResponse is the SyncResponse schema of the API
+type SyncResponse struct {
+	Changed    []Todo             `json:"changed,omitempty"`
+	Results    []SyncChangeResult `json:"results,omitempty"`
+	ServerTime time.Time          `json:"serverTime,omitempty"`
+	Strategy   string             `json:"strategy,omitempty"`
+}
+
+// SyncTodosPayload is the SyncTodosPayload schema of the API
+type SyncTodosPayload struct {
+	Changes  []SyncChange `json:"changes,omitempty"`
+	Since    *time.Time   `json:"since,omitempty"`
+	Strategy *string      `json:"strategy,omitempty"`
+}
+
+// TagSuggestion is the TagSuggestion schema of the API
+type TagSuggestion struct {
+	Confidence float64 `json:"confidence,omitempty"`
+	Tag        string  `json:"tag,omitempty"`
+}
+
+// Template is the Template schema of the API
+type Template struct {
+	CreatedAt   time.Time `json:"createdAt,omitempty"`
+	CreatedBy   string    `json:"createdBy,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	DueInDays   *int      `json:"dueInDays,omitempty"`
+	ID          string    `json:"id,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	Priority    *string   `json:"priority,omitempty"`
+	Subtasks    []string  `json:"subtasks,omitempty"`
+	Title       string    `json:"title,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
+	WorkspaceID string    `json:"workspaceId,omitempty"`
+}
+
+// TestDelivery is the TestDelivery schema of the API
+type TestDelivery struct {
+	DeliveryID   string  `json:"deliveryId,omitempty"`
+	DurationMs   int     `json:"durationMs,omitempty"`
+	Error        *string `json:"error,omitempty"`
+	Event        string  `json:"event,omitempty"`
+	ResponseBody string  `json:"responseBody,omitempty"`
+	StatusCode   *int    `json:"statusCode,omitempty"`
+	Success      bool    `json:"success,omitempty"`
+}
+
+// TestEndpointPayload is the TestEndpointPayload schema of the API
+type TestEndpointPayload struct {
+	Event *string `json:"event,omitempty"`
+}
+
+// Todo is the Todo schema of the API
+type Todo struct {
+	Links                 map[string]Link `json:"_links,omitempty"`
+	CategoryID            *string         `json:"categoryId,omitempty"`
+	CommentCount          int             `json:"commentCount,omitempty"`
+	CommentsReadAt        *time.Time      `json:"commentsReadAt,omitempty"`
+	CompletedAt           *time.Time      `json:"completedAt,omitempty"`
+	CompletedSubtaskCount int             `json:"completedSubtaskCount,omitempty"`
+	CreatedAt             time.Time       `json:"createdAt,omitempty"`
+	Description           string          `json:"description,omitempty"`
+	DueDate               *time.Time      `json:"dueDate,omitempty"`
+	ID                    string          `json:"id,omitempty"`
+	Metadata              *Metadata       `json:"metadata,omitempty"`
+	ParentTodoID          *string         `json:"parentTodoId,omitempty"`
+	Priority              string          `json:"priority,omitempty"`
+	PriorityLevel         int             `json:"priorityLevel,omitempty"`
+	SortOrder             int             `json:"sortOrder,omitempty"`
+	Status                string          `json:"status,omitempty"`
+	StatusCategory        string          `json:"statusCategory,omitempty"`
+	SubtaskCount          int             `json:"subtaskCount,omitempty"`
+	Title                 string          `json:"title,omitempty"`
+	UnreadCommentCount    int             `json:"unreadCommentCount,omitempty"`
+	UpdatedAt             time.Time       `json:"updatedAt,omitempty"`
+	UserID                string          `json:"userId,omitempty"`
+	Version               int             `json:"version,omitempty"`
+	WorkspaceID           *string         `json:"workspaceId,omitempty"`
+}
+
+// TodoAttachment is the TodoAttachment schema of the API
+type TodoAttachment struct {
+	Links               map[string]Link `json:"_links,omitempty"`
+	CreatedAt           time.Time       `json:"createdAt,omitempty"`
+	DownloadKey         string          `json:"downloadKey,omitempty"`
+	FileSize            *int            `json:"fileSize,omitempty"`
+	ID                  string          `json:"id,omitempty"`
+	MimeType            *string         `json:"mimeType,omitempty"`
+	Name                string          `json:"name,omitempty"`
+	ProcessedAt         *time.Time      `json:"processedAt,omitempty"`
+	ScanSignature       *string         `json:"scanSignature,omitempty"`
+	ScanStatus          *string         `json:"scanStatus,omitempty"`
+	ScannedAt           *time.Time      `json:"scannedAt,omitempty"`
+	ThumbnailKey        *string         `json:"thumbnailKey,omitempty"`
+	TodoID              string          `json:"todoId,omitempty"`
+	Transcript          *string         `json:"transcript,omitempty"`
+	TranscriptionStatus *string         `json:"transcriptionStatus,omitempty"`
+	UpdatedAt           time.Time       `json:"updatedAt,omitempty"`
+	UploadedBy          string          `json:"uploadedBy,omitempty"`
+}
+
+// TodoStats is the TodoStats schema of the API
+type TodoStats struct {
+	Active    int `json:"active,omitempty"`
+	Archived  int `json:"archived,omitempty"`
+	Completed int `json:"completed,omitempty"`
+	Draft     int `json:"draft,omitempty"`
+	Overdue   int `json:"overdue,omitempty"`
+	Total     int `json:"total,omitempty"`
+}
+
+// TodoSuggestions is the TodoSuggestions schema of the API
+type TodoSuggestions struct {
+	Category *CategorySuggestion `json:"category,omitempty"`
+	Priority *PrioritySuggestion `json:"priority,omitempty"`
+	Tags     []TagSuggestion     `json:"tags,omitempty"`
+}
+
+// TodoVersion is the TodoVersion schema of the API
+type TodoVersion struct {
+	CategoryID   *string    `json:"categoryId,omitempty"`
+	CompletedAt  *time.Time `json:"completedAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt,omitempty"`
+	Description  *string    `json:"description,omitempty"`
+	DueDate      *time.Time `json:"dueDate,omitempty"`
+	Metadata     *Metadata  `json:"metadata,omitempty"`
+	ParentTodoID *string    `json:"parentTodoId,omitempty"`
+	Priority     string     `json:"priority,omitempty"`
+	Status       string     `json:"status,omitempty"`
+	Title        string     `json:"title,omitempty"`
+	TodoID       string     `json:"todoId,omitempty"`
+	Version      int        `json:"version,omitempty"`
+}
+
+// Totals is the Totals schema of the API
+type Totals struct {
+	APICalls      int `json:"apiCalls,omitempty"`
+	Notifications int `json:"notifications,omitempty"`
+	StorageBytes  int `json:"storageBytes,omitempty"`
+}
+
+// TriagePayload is the TriagePayload schema of the API
+type TriagePayload struct {
+	CategoryID  *string    `json:"categoryId,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	DueDate     *time.Time `json:"dueDate,omitempty"`
+	Priority    *string    `json:"priority,omitempty"`
+	Title       *string    `json:"title,omitempty"`
+}
+
+// UpdateAPIClientPayload is the UpdateAPIClientPayload schema of the API
+type UpdateAPIClientPayload struct {
+	AllowedOrigins []string `json:"allowedOrigins,omitempty"`
+	Enabled        *bool    `json:"enabled,omitempty"`
+	Name           *string  `json:"name,omitempty"`
+}
+
+// UpdateCategoryPayload is the UpdateCategoryPayload schema of the API
+type UpdateCategoryPayload struct {
+	Color       *string `json:"color,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Name        *string `json:"name,omitempty"`
+}
+
+// UpdateCommentPayload is the UpdateCommentPayload schema of the API
+type UpdateCommentPayload struct {
+	Content string `json:"content"`
+}
+
+// UpdateEndpointPayload is the UpdateEndpointPayload schema of the API
+type UpdateEndpointPayload struct {
+	Enabled *bool    `json:"enabled,omitempty"`
+	Events  []string `json:"events,omitempty"`
+	URL     *string  `json:"url,omitempty"`
+}
+
+// UpdateIncidentPayload is the UpdateIncidentPayload schema of the API
+type UpdateIncidentPayload struct {
+	Components []string `json:"components,omitempty"`
+	Impact     *string  `json:"impact,omitempty"`
+	Message    *string  `json:"message,omitempty"`
+	Status     *string  `json:"status,omitempty"`
+	Title      *string  `json:"title,omitempty"`
+}
+
+// UpdateMagicTagPayload is the UpdateMagicTagPayload schema of the API
+type UpdateMagicTagPayload struct {
+	CategoryID *string `json:"categoryId,omitempty"`
+	DueInDays  *int    `json:"dueInDays,omitempty"`
+	Priority   *string `json:"priority,omitempty"`
+	Tag        string  `json:"tag"`
+	Timezone   *string `json:"timezone,omitempty"`
+}
+
+// UpdatePreferencePayload is the UpdatePreferencePayload schema of the API
+type UpdatePreferencePayload struct {
+	Locale string `json:"locale"`
+}
+
+// UpdateRulePayload is the UpdateRulePayload schema of the API
+type UpdateRulePayload struct {
+	Actions    []RuleAction `json:"actions,omitempty"`
+	Conditions *Conditions  `json:"conditions,omitempty"`
+	Enabled    *bool        `json:"enabled,omitempty"`
+	Name       *string      `json:"name,omitempty"`
+	Trigger    *string      `json:"trigger,omitempty"`
+}
+
+// UpdateSchedulePayload is the UpdateSchedulePayload schema of the API
+type UpdateSchedulePayload struct {
+	DayOfMonth *int     `json:"dayOfMonth,omitempty"`
+	Filters    Filters  `json:"filters,omitempty"`
+	Format     string   `json:"format"`
+	Frequency  string   `json:"frequency"`
+	Hour       int      `json:"hour,omitempty"`
+	Name       string   `json:"name"`
+	Recipients []string `json:"recipients"`
+	Timezone   string   `json:"timezone,omitempty"`
+	Weekday    *int     `json:"weekday,omitempty"`
+}
+
+// UpdateSettingsPayload is the UpdateSettingsPayload schema of the API
+type UpdateSettingsPayload struct {
+	ImportantPriority *string `json:"importantPriority,omitempty"`
+	UrgentWithinHours *int    `json:"urgentWithinHours,omitempty"`
+}
+
+// UpdateTodoPayload is the UpdateTodoPayload schema of the API
+type UpdateTodoPayload struct {
+	CategoryID   *string    `json:"categoryId,omitempty"`
+	Description  *string    `json:"description,omitempty"`
+	DueDate      *time.Time `json:"dueDate,omitempty"`
+	Metadata     *Metadata  `json:"metadata,omitempty"`
+	ParentTodoID *string    `json:"parentTodoId,omitempty"`
+	Priority     *string    `json:"priority,omitempty"`
+	Status       *string    `json:"status,omitempty"`
+	Title        *string    `json:"title,omitempty"`
+	Version      *int       `json:"version,omitempty"`
+}
+
+// Usage is the Usage schema of the API
+type Usage struct {
+	Buckets     []Bucket  `json:"buckets,omitempty"`
+	From        time.Time `json:"from,omitempty"`
+	Granularity string    `json:"granularity,omitempty"`
+	SubjectID   string    `json:"subjectId,omitempty"`
+	SubjectType string    `json:"subjectType,omitempty"`
+	To          time.Time `json:"to,omitempty"`
+	Totals      Totals    `json:"totals,omitempty"`
+}
+
+// User is the User schema of the API
+type User struct {
+	Banned       bool       `json:"banned,omitempty"`
+	CreatedAt    *time.Time `json:"createdAt,omitempty"`
+	Email        *string    `json:"email,omitempty"`
+	FirstName    *string    `json:"firstName,omitempty"`
+	ID           string     `json:"id,omitempty"`
+	LastName     *string    `json:"lastName,omitempty"`
+	LastSignInAt *time.Time `json:"lastSignInAt,omitempty"`
+	Locked       bool       `json:"locked,omitempty"`
+	Role         string     `json:"role,omitempty"`
+	Stats        UserStats  `json:"stats,omitempty"`
+}
+
+// UserStats is the UserStats schema of the API
+type UserStats struct {
+	AttachmentBytes int        `json:"attachmentBytes,omitempty"`
+	Attachments     int        `json:"attachments,omitempty"`
+	Categories      int        `json:"categories,omitempty"`
+	Comments        int        `json:"comments,omitempty"`
+	CompletedTodos  int        `json:"completedTodos,omitempty"`
+	LastActivityAt  *time.Time `json:"lastActivityAt,omitempty"`
+	Todos           int        `json:"todos,omitempty"`
+}
+
+// UserStorage is the UserStorage schema of the API
+type UserStorage struct {
+	DefaultQuotaBytes int           `json:"defaultQuotaBytes,omitempty"`
+	Override          *StorageQuota `json:"override,omitempty"`
+	QuotaBytes        int           `json:"quotaBytes,omitempty"`
+	UsedBytes         int           `json:"usedBytes,omitempty"`
+	UserID            string        `json:"userId,omitempty"`
+}
+
+// WeekLoad is the WeekLoad schema of the API
+type WeekLoad struct {
+	EstimatedMinutes int    `json:"estimatedMinutes,omitempty"`
+	OpenTodos        int    `json:"openTodos,omitempty"`
+	OverCapacity     bool   `json:"overCapacity,omitempty"`
+	UnestimatedCount int    `json:"unestimatedCount,omitempty"`
+	Week             string `json:"week,omitempty"`
+}
+
+// Workflow is the Workflow schema of the API
+type Workflow struct {
+	CreatedAt   time.Time          `json:"createdAt,omitempty"`
+	Priorities  []WorkflowPriority `json:"priorities,omitempty"`
+	Statuses    []WorkflowStatus   `json:"statuses,omitempty"`
+	UpdatedAt   time.Time          `json:"updatedAt,omitempty"`
+	UpdatedBy   string             `json:"updatedBy,omitempty"`
+	WorkspaceID string             `json:"workspaceId,omitempty"`
+}
+
+// WorkflowPriority is the WorkflowPriority schema of the API
+type WorkflowPriority struct {
+	Color string `json:"color"`
+	Key   string `json:"key"`
+	Name  string `json:"name"`
+}
+
+// WorkflowStatus is the WorkflowStatus schema of the API
+type WorkflowStatus struct {
+	Category string `json:"category"`
+	Color    string `json:"color"`
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+}
+
+// Workload is the Workload schema of the API
+type Workload struct {
+	CapacityMinutes int              `json:"capacityMinutes,omitempty"`
+	Members         []MemberWorkload `json:"members,omitempty"`
+	Timezone        string           `json:"timezone,omitempty"`
+	Weeks           []string         `json:"weeks,omitempty"`
+	WorkspaceID     string           `json:"workspaceId,omitempty"`
+}
+
+// Workspace is the Workspace schema of the API
+type Workspace struct {
+	Freeze *Freeze        `json:"freeze,omitempty"`
+	ID     string         `json:"id,omitempty"`
+	Stats  WorkspaceStats `json:"stats,omitempty"`
+}
+
+// WorkspaceStats is the WorkspaceStats schema of the API
+type WorkspaceStats struct {
+	AgingPolicies   int        `json:"agingPolicies,omitempty"`
+	AttachmentBytes int        `json:"attachmentBytes,omitempty"`
+	Attachments     int        `json:"attachments,omitempty"`
+	CompletedTodos  int        `json:"completedTodos,omitempty"`
+	LastActivityAt  *time.Time `json:"lastActivityAt,omitempty"`
+	Members         int        `json:"members,omitempty"`
+	PendingInvites  int        `json:"pendingInvites,omitempty"`
+	Templates       int        `json:"templates,omitempty"`
+	Todos           int        `json:"todos,omitempty"`
+	Webhooks        int        `json:"webhooks,omitempty"`
+}
+
+// GetAttachmentPresignedURLResponse is an inline schema of the API
+type GetAttachmentPresignedURLResponse struct {
+	URL string `json:"url,omitempty"`
+}
+
+// GetAttachmentThumbnailURLResponse is an inline schema of the API
+type GetAttachmentThumbnailURLResponse struct {
+	URL string `json:"url,omitempty"`
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TokenSource returns the bearer token for a request, e.g. a freshly minted Clerk session token
+type TokenSource func(ctx context.Context) (string, error)
+
+// Client calls the ExecuTask API. It is safe for concurrent use.
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	tokenSource TokenSource
+	userAgent   string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient replaces the http.Client used for requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBearerToken authenticates every request with a static token
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.tokenSource = func(context.Context) (string, error) {
+			return token, nil
+		}
+	}
+}
+
+// WithTokenSource authenticates every request with a token fetched right before sending it
+func WithTokenSource(source TokenSource) Option {
+	return func(c *Client) {
+		c.tokenSource = source
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// New creates a client for the API served at baseURL
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		userAgent:  "executask-go",
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Error is returned for every non 2xx response, Body holds the RFC 7807 problem details
+type Error struct {
+	StatusCode int
+	Body       Problem
+}
+
+func (e *Error) Error() string {
+	if e.Body.Message == "" {
+		return fmt.Sprintf("executask: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("executask: %d %s: %s", e.StatusCode, e.Body.Code, e.Body.Message)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request body: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if len(query) > 0 {
+		req.URL.RawQuery = query.Encode()
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.send(req, out)
+}
+
+func (c *Client) upload(ctx context.Context, method, path, field, filename string, file io.Reader, out any) error {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	part, err := writer.CreateFormFile(field, filename)
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("failed to read upload: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to finish multipart body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, &buf)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return c.send(req, out)
+}
+
+func (c *Client) send(req *http.Request, out any) error {
+	req.Header.Set("Accept", "application/json, application/problem+json")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	if c.tokenSource != nil {
+		token, err := c.tokenSource(req.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get bearer token: %w", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		// Error bodies that aren't JSON still produce a usable error
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr.Body)
+		return apiErr
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// formatValue renders a query parameter the way the API binds it
+func formatValue(value any) string {
+	if t, ok := value.(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(value)
+}
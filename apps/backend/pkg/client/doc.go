@@ -0,0 +1,18 @@
+// Package client is the official Go client of the ExecuTask API.
+//
+// Types and operations in client.gen.go are generated from the OpenAPI document the
+// server builds from its handler definitions. Regenerate them after changing a route:
+//
+//	go generate ./pkg/client
+//
+// Usage:
+//
+//	c := client.New("https://api.executask.com", client.WithBearerToken(token))
+//	todo, err := c.GetTodoByID(ctx, id, nil)
+//
+//	for todo, err := range c.GetTodosIter(ctx, &client.GetTodosParams{}) {
+//		...
+//	}
+package client
+
+//go:generate go run ../../cmd/sdkgen -go-out client.gen.go -ts-out ../../../../packages/sdk/src/client.gen.ts
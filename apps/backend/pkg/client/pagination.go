@@ -0,0 +1,59 @@
+package client
+
+import "iter"
+
+// paginate yields the items of every page from start (1 when nil) until the last page
+func paginate[T any](start *int, fetch func(page int) ([]T, int, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		page := 1
+		if start != nil {
+			page = *start
+		}
+
+		for ; ; page++ {
+			items, totalPages, err := fetch(page)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if len(items) == 0 || page >= totalPages {
+				return
+			}
+		}
+	}
+}
+
+// follow yields the items of a cursor feed until the server reports it is caught up
+func follow[T any](start *string, fetch func(cursor *string) ([]T, string, bool, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		cursor := start
+
+		for {
+			items, next, hasMore, err := fetch(cursor)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if !hasMore || next == "" {
+				return
+			}
+			cursor = &next
+		}
+	}
+}
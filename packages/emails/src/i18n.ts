@@ -0,0 +1,23 @@
+// The copy of the emails is translated by the backend when it renders the exported templates,
+// from the catalogs in apps/backend/internal/lib/i18n/locales. These helpers write the template
+// actions looking a message up in the catalog of the recipient's locale.
+//
+// Args are props holding template actions, such as "{{.TodoTitle}}", or numbers.
+
+type Arg = string | number;
+
+const operand = (arg: Arg) => {
+  if (typeof arg === "number") return String(arg);
+  const action = arg.match(/^\{\{(.*)\}\}$/);
+  return action ? action[1] : JSON.stringify(arg);
+};
+
+const action = (name: string, key: string, args: Arg[]) =>
+  `{{${[name, `\`${key}\``, ...args.map(operand)].join(" ")}}}`;
+
+// t translates the message with the key, formatted with the args
+export const t = (key: string, ...args: Arg[]) => action("t", key, args);
+
+// tn translates a message that counts something, count picks its plural form
+export const tn = (key: string, count: Arg, ...args: Arg[]) =>
+  action("tn", key, [count, ...args]);
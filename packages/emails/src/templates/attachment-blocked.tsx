@@ -0,0 +1,95 @@
+import {
+  Body,
+  Button,
+  Container,
+  Head,
+  Heading,
+  Hr,
+  Html,
+  Img,
+  Preview,
+  Section,
+  Text,
+  Tailwind,
+} from "@react-email/components";
+import { t } from "../i18n";
+
+interface AttachmentBlockedEmailProps {
+  fileName: string;
+  todoTitle: string;
+  todoID: string;
+  signature: string;
+}
+
+export const AttachmentBlockedEmail = ({
+  fileName = "{{.FileName}}",
+  todoTitle = "{{.TodoTitle}}",
+  todoID = "{{.TodoID}}",
+  signature = "{{.Signature}}",
+}: AttachmentBlockedEmailProps) => {
+  return (
+    <Html lang="{{.Locale}}">
+      <Head />
+      <Preview>{t("email.attachment_blocked.preview", fileName)}</Preview>
+      <Tailwind>
+        <Body className="bg-gray-100 font-sans">
+          <Container className="bg-white p-8 rounded-lg shadow-sm my-10 mx-auto max-w-[600px]">
+            <Section className="mb-6 text-center">
+              <Img
+                src="http://localhost:8080/static/full_logo.png?height=48&width=48"
+                width="48"
+                height="48"
+                alt="Executask Logo"
+                className="mx-auto"
+              />
+              <Heading className="text-2xl font-bold text-gray-800 mt-4">
+                {t("email.attachment_blocked.heading")}
+              </Heading>
+            </Section>
+
+            <Section className="bg-red-50 border-l-4 border-red-400 p-4 mb-6">
+              <Text className="font-semibold text-red-700 text-lg mb-2">
+                {t("email.attachment_blocked.not_attached", fileName, todoTitle)}
+              </Text>
+              <Text className="text-gray-700 text-base">
+                {t("email.attachment_blocked.found", signature)}
+              </Text>
+            </Section>
+
+            <Section className="my-8 text-center">
+              <Button
+                className="bg-blue-600 hover:bg-blue-700 text-white font-medium rounded-md px-6 py-3"
+                href={`/todos?id=${todoID}`}
+              >
+                {t("email.view_todo")}
+              </Button>
+            </Section>
+
+            <Hr className="border-gray-200 my-6" />
+
+            <Section>
+              <Text className="text-gray-600 text-sm">
+                {t("email.attachment_blocked.footer")}
+              </Text>
+            </Section>
+
+            <Section className="mt-8 text-center">
+              <Text className="text-gray-500 text-xs">
+                © {new Date().getFullYear()} Executask. {t("email.rights_reserved")}
+              </Text>
+            </Section>
+          </Container>
+        </Body>
+      </Tailwind>
+    </Html>
+  );
+};
+
+AttachmentBlockedEmail.PreviewProps = {
+  fileName: "invoice.pdf",
+  todoTitle: "Pay the supplier",
+  todoID: "123e4567-e89b-12d3-a456-426614174000",
+  signature: "Win.Test.EICAR_HDB-1",
+};
+
+export default AttachmentBlockedEmail;
@@ -0,0 +1,87 @@
+import {
+  Body,
+  Button,
+  Container,
+  Head,
+  Heading,
+  Hr,
+  Html,
+  Img,
+  Preview,
+  Section,
+  Text,
+  Tailwind,
+} from "@react-email/components";
+import { t } from "../i18n";
+
+interface BadgeAwardedEmailProps {
+  badgeName: string;
+  badgeDescription: string;
+}
+
+export const BadgeAwardedEmail = ({
+  badgeName = "{{.BadgeName}}",
+  badgeDescription = "{{.BadgeDescription}}",
+}: BadgeAwardedEmailProps) => {
+  return (
+    <Html lang="{{.Locale}}">
+      <Head />
+      <Preview>{t("email.badge.preview", badgeName)}</Preview>
+      <Tailwind>
+        <Body className="bg-gray-100 font-sans">
+          <Container className="bg-white p-8 rounded-lg shadow-sm my-10 mx-auto max-w-[600px]">
+            <Section className="mb-6 text-center">
+              <Img
+                src="http://localhost:8080/static/full_logo.png?height=48&width=48"
+                width="48"
+                height="48"
+                alt="Executask Logo"
+                className="mx-auto"
+              />
+              <Heading className="text-2xl font-bold text-gray-800 mt-4">
+                {t("email.badge.heading")}
+              </Heading>
+            </Section>
+
+            <Section className="bg-green-50 border-l-4 border-green-400 p-4 mb-6">
+              <Text className="font-semibold text-green-700 text-lg mb-2">
+                {badgeName}
+              </Text>
+              <Text className="text-gray-700 text-base">{badgeDescription}</Text>
+            </Section>
+
+            <Section className="my-8 text-center">
+              <Button
+                className="bg-blue-600 hover:bg-blue-700 text-white font-medium rounded-md px-6 py-3"
+                href="/achievements"
+              >
+                {t("email.badge.see_badges")}
+              </Button>
+            </Section>
+
+            <Hr className="border-gray-200 my-6" />
+
+            <Section>
+              <Text className="text-gray-600 text-sm">
+                {t("email.badge.footer")}
+              </Text>
+            </Section>
+
+            <Section className="mt-8 text-center">
+              <Text className="text-gray-500 text-xs">
+                © {new Date().getFullYear()} Executask. {t("email.rights_reserved")}
+              </Text>
+            </Section>
+          </Container>
+        </Body>
+      </Tailwind>
+    </Html>
+  );
+};
+
+BadgeAwardedEmail.PreviewProps = {
+  badgeName: "On a Roll",
+  badgeDescription: "Keep a 7 day streak",
+};
+
+export default BadgeAwardedEmail;
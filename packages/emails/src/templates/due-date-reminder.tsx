@@ -13,12 +13,14 @@ import {
   Text,
   Tailwind,
 } from "@react-email/components";
+import { t, tn } from "../i18n";
 
 interface DueDateReminderEmailProps {
   todoTitle: string;
   todoID: string;
   dueDate: string;
   daysUntilDue: string;
+  replyHint: string;
 }
 
 export const DueDateReminderEmail = ({
@@ -26,17 +28,16 @@ export const DueDateReminderEmail = ({
   todoID = "{{.TodoID}}",
   dueDate = "{{.DueDate}}",
   daysUntilDue = "{{.DaysUntilDue}}",
+  replyHint = "{{.ReplyHint}}",
 }: DueDateReminderEmailProps) => {
   const urgencyColor =
     parseInt(daysUntilDue) <= 1 ? "text-red-600" : "text-orange-600";
-  const urgencyMessage =
-    parseInt(daysUntilDue) <= 1 ? "due tomorrow" : `due in ${daysUntilDue} days`;
 
   return (
-    <Html>
+    <Html lang="{{.Locale}}">
       <Head />
       <Preview>
-        Reminder: "{todoTitle}" is {urgencyMessage}
+        {tn("email.reminder.preview", daysUntilDue, todoTitle)}
       </Preview>
       <Tailwind>
         <Body className="bg-gray-100 font-sans">
@@ -50,23 +51,22 @@ export const DueDateReminderEmail = ({
                 className="mx-auto"
               />
               <Heading className="text-2xl font-bold text-gray-800 mt-4">
-                📅 Todo Reminder
+                {t("email.reminder.heading")}
               </Heading>
             </Section>
 
             <Section className="bg-yellow-50 border-l-4 border-yellow-400 p-4 mb-6">
               <Text className={`font-semibold ${urgencyColor} text-lg mb-2`}>
-                "{todoTitle}" is {urgencyMessage}
+                {tn("email.reminder.due", daysUntilDue, todoTitle)}
               </Text>
               <Text className="text-gray-700 text-base">
-                Due Date: {dueDate}
+                {t("email.reminder.due_date", dueDate)}
               </Text>
             </Section>
 
             <Section>
               <Text className="text-gray-700 text-base">
-                This is a friendly reminder that your todo item is due soon.
-                Don't let it slip through the cracks!
+                {t("email.reminder.intro")}
               </Text>
             </Section>
 
@@ -75,35 +75,35 @@ export const DueDateReminderEmail = ({
                 className="bg-blue-600 hover:bg-blue-700 text-white font-medium rounded-md px-6 py-3 mr-4"
                 href={`/todos?id=${todoID}`}
               >
-                View Todo
+                {t("email.view_todo")}
               </Button>
               <Button
                 className="bg-green-600 hover:bg-green-700 text-white font-medium rounded-md px-6 py-3"
                 href={`/todos?id=${todoID}&action=complete`}
               >
-                Mark Complete
+                {t("email.mark_complete")}
               </Button>
             </Section>
 
             <Section>
               <Text className="text-gray-700 text-base">
-                💡 <strong>Pro tip:</strong> Stay on top of your tasks by
-                checking your Executask dashboard regularly and setting realistic
-                due dates.
+                💡 <strong>{t("email.pro_tip")}</strong>{" "}
+                {t("email.reminder.pro_tip")}
               </Text>
             </Section>
 
+            <Text className="text-gray-500 text-sm text-center">{replyHint}</Text>
+
             <Hr className="border-gray-200 my-6" />
 
             <Section>
               <Text className="text-gray-600 text-sm">
-                You're receiving this reminder because you have an active todo
-                item with an upcoming due date.{" "}
+                {t("email.reminder.footer")}{" "}
                 <Link
                   href={`/settings/notifications`}
                   className="text-blue-600 underline"
                 >
-                  Manage notification preferences
+                  {t("email.manage_notifications")}
                 </Link>
                 .
               </Text>
@@ -111,7 +111,7 @@ export const DueDateReminderEmail = ({
 
             <Section className="mt-8 text-center">
               <Text className="text-gray-500 text-xs">
-                © {new Date().getFullYear()} Executask. All rights reserved.
+                © {new Date().getFullYear()} Executask. {t("email.rights_reserved")}
               </Text>
             </Section>
           </Container>
@@ -126,6 +126,7 @@ DueDateReminderEmail.PreviewProps = {
   todoID: "123e4567-e89b-12d3-a456-426614174000",
   dueDate: "Monday, January 15, 2025 at 5:00 PM",
   daysUntilDue: "1",
+  replyHint: "Reply to this email to comment on the todo.",
 };
 
 export default DueDateReminderEmail;
@@ -13,12 +13,14 @@ import {
   Text,
   Tailwind,
 } from "@react-email/components";
+import { t, tn } from "../i18n";
 
 interface OverdueNotificationEmailProps {
   todoTitle: string;
   todoID: string;
   dueDate: string;
   daysOverdue: string;
+  replyHint: string;
 }
 
 export const OverdueNotificationEmail = ({
@@ -26,14 +28,12 @@ export const OverdueNotificationEmail = ({
   todoID = "{{.TodoID}}",
   dueDate = "{{.DueDate}}",
   daysOverdue = "{{.DaysOverdue}}",
+  replyHint = "{{.ReplyHint}}",
 }: OverdueNotificationEmailProps) => {
-  const overdueMessage =
-    parseInt(daysOverdue) === 1 ? "1 day overdue" : `${daysOverdue} days overdue`;
-
   return (
-    <Html>
+    <Html lang="{{.Locale}}">
       <Head />
-      <Preview>Overdue: "{todoTitle}" needs your attention</Preview>
+      <Preview>{t("email.overdue.preview", todoTitle)}</Preview>
       <Tailwind>
         <Body className="bg-gray-100 font-sans">
           <Container className="bg-white p-8 rounded-lg shadow-sm my-10 mx-auto max-w-[600px]">
@@ -46,23 +46,22 @@ export const OverdueNotificationEmail = ({
                 className="mx-auto"
               />
               <Heading className="text-2xl font-bold text-gray-800 mt-4">
-                ⚠️ Overdue Todo
+                {t("email.overdue.heading")}
               </Heading>
             </Section>
 
             <Section className="bg-red-50 border-l-4 border-red-500 p-4 mb-6">
               <Text className="font-semibold text-red-600 text-lg mb-2">
-                "{todoTitle}" is {overdueMessage}
+                {tn("email.overdue.days", daysOverdue, todoTitle)}
               </Text>
               <Text className="text-gray-700 text-base">
-                Was due: {dueDate}
+                {t("email.overdue.was_due", dueDate)}
               </Text>
             </Section>
 
             <Section>
               <Text className="text-gray-700 text-base">
-                Your todo item is now overdue and needs immediate attention.
-                Don't let important tasks fall behind schedule!
+                {t("email.overdue.intro")}
               </Text>
             </Section>
 
@@ -71,50 +70,49 @@ export const OverdueNotificationEmail = ({
                 className="bg-red-600 hover:bg-red-700 text-white font-medium rounded-md px-6 py-3 mr-4"
                 href={`/todos?id=${todoID}`}
               >
-                View Todo
+                {t("email.view_todo")}
               </Button>
               <Button
                 className="bg-green-600 hover:bg-green-700 text-white font-medium rounded-md px-6 py-3"
                 href={`/todos?id=${todoID}&action=complete`}
               >
-                Mark Complete
+                {t("email.mark_complete")}
               </Button>
             </Section>
 
             <Section className="bg-blue-50 border-l-4 border-blue-400 p-4 mb-6">
               <Text className="text-blue-800 text-base font-medium mb-2">
-                💡 Need to reschedule?
+                {t("email.overdue.reschedule")}
               </Text>
               <Text className="text-blue-700 text-sm">
-                If this todo is no longer relevant or needs a new timeline, you
-                can:
+                {t("email.overdue.options")}
               </Text>
               <ul className="list-disc pl-6 text-blue-700 text-sm mt-2">
-                <li>Update the due date to a more realistic timeline</li>
-                <li>Break it down into smaller, manageable tasks</li>
-                <li>Archive it if it's no longer needed</li>
+                <li>{t("email.overdue.option_due_date")}</li>
+                <li>{t("email.overdue.option_split")}</li>
+                <li>{t("email.overdue.option_archive")}</li>
               </ul>
             </Section>
 
             <Section>
               <Text className="text-gray-700 text-base">
-                🎯 <strong>Stay organized:</strong> Regular review of your todos
-                helps prevent items from becoming overdue. Consider setting
-                aside time each week to review and prioritize your tasks.
+                🎯 <strong>{t("email.overdue.organized")}</strong>{" "}
+                {t("email.overdue.review")}
               </Text>
             </Section>
 
+            <Text className="text-gray-500 text-sm text-center">{replyHint}</Text>
+
             <Hr className="border-gray-200 my-6" />
 
             <Section>
               <Text className="text-gray-600 text-sm">
-                You're receiving this notification because you have an overdue
-                todo item.{" "}
+                {t("email.overdue.footer")}{" "}
                 <Link
                   href={`/settings/notifications`}
                   className="text-blue-600 underline"
                 >
-                  Manage notification preferences
+                  {t("email.manage_notifications")}
                 </Link>
                 .
               </Text>
@@ -122,7 +120,7 @@ export const OverdueNotificationEmail = ({
 
             <Section className="mt-8 text-center">
               <Text className="text-gray-500 text-xs">
-                © {new Date().getFullYear()} Executask. All rights reserved.
+                © {new Date().getFullYear()} Executask. {t("email.rights_reserved")}
               </Text>
             </Section>
           </Container>
@@ -137,6 +135,7 @@ OverdueNotificationEmail.PreviewProps = {
   todoID: "123e4567-e89b-12d3-a456-426614174000",
   dueDate: "Friday, January 12, 2025 at 3:00 PM",
   daysOverdue: "3",
+  replyHint: "Reply to this email to comment on the todo.",
 };
 
 export default OverdueNotificationEmail;
@@ -0,0 +1,87 @@
+import {
+  Body,
+  Container,
+  Head,
+  Heading,
+  Hr,
+  Html,
+  Img,
+  Link,
+  Preview,
+  Section,
+  Text,
+  Tailwind,
+} from "@react-email/components";
+import { t, tn } from "../i18n";
+
+interface RuleDigestEmailProps {
+  count: string;
+}
+
+// The notices are repeated by the Go template, the range markers wrap the one rendered here
+export const RuleDigestEmail = ({ count = "{{.Count}}" }: RuleDigestEmailProps) => {
+  return (
+    <Html lang="{{.Locale}}">
+      <Head />
+      <Preview>{tn("email.rule_digest.preview", count)}</Preview>
+      <Tailwind>
+        <Body className="bg-gray-100 font-sans">
+          <Container className="bg-white p-8 rounded-lg shadow-sm my-10 mx-auto max-w-[600px]">
+            <Section className="mb-6 text-center">
+              <Img
+                src="http://localhost:8080/static/full_logo.png?height=48&width=48"
+                width="48"
+                height="48"
+                alt="Executask Logo"
+                className="mx-auto"
+              />
+              <Heading className="text-2xl font-bold text-gray-800 mt-4">
+                {t("email.rule_digest.heading")}
+              </Heading>
+            </Section>
+
+            <Text className="text-gray-700 text-base">
+              {t("email.rule_digest.intro")}
+            </Text>
+
+            {"{{range .Notices}}"}
+            <Section className="bg-blue-50 border-l-4 border-blue-400 p-4 mb-4">
+              <Text className="font-semibold text-blue-700 text-lg mb-2">
+                {t("email.rule_digest.ran_on", "{{.RuleName}}")}{" "}
+                <Link href="/todos?id={{.TodoID}}" className="text-blue-700 underline">
+                  {t("email.quoted", "{{.TodoTitle}}")}
+                </Link>
+              </Text>
+              <Text className="text-gray-700 text-base">{"{{.Message}}"}</Text>
+            </Section>
+            {"{{end}}"}
+
+            <Hr className="border-gray-200 my-6" />
+
+            <Section>
+              <Text className="text-gray-600 text-sm">
+                {t("email.rule_digest.footer")}{" "}
+                <Link href="/settings/rules" className="text-blue-600 underline">
+                  {t("email.manage_rules")}
+                </Link>
+                .
+              </Text>
+            </Section>
+
+            <Section className="mt-8 text-center">
+              <Text className="text-gray-500 text-xs">
+                © {new Date().getFullYear()} Executask. {t("email.rights_reserved")}
+              </Text>
+            </Section>
+          </Container>
+        </Body>
+      </Tailwind>
+    </Html>
+  );
+};
+
+RuleDigestEmail.PreviewProps = {
+  count: "3",
+};
+
+export default RuleDigestEmail;
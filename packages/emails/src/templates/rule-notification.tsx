@@ -0,0 +1,103 @@
+import {
+  Body,
+  Button,
+  Container,
+  Head,
+  Heading,
+  Hr,
+  Html,
+  Img,
+  Link,
+  Preview,
+  Section,
+  Text,
+  Tailwind,
+} from "@react-email/components";
+import { t } from "../i18n";
+
+interface RuleNotificationEmailProps {
+  ruleName: string;
+  todoTitle: string;
+  todoID: string;
+  message: string;
+  replyHint: string;
+}
+
+export const RuleNotificationEmail = ({
+  ruleName = "{{.RuleName}}",
+  todoTitle = "{{.TodoTitle}}",
+  todoID = "{{.TodoID}}",
+  message = "{{.Message}}",
+  replyHint = "{{.ReplyHint}}",
+}: RuleNotificationEmailProps) => {
+  return (
+    <Html lang="{{.Locale}}">
+      <Head />
+      <Preview>{t("email.rule.preview", ruleName, todoTitle)}</Preview>
+      <Tailwind>
+        <Body className="bg-gray-100 font-sans">
+          <Container className="bg-white p-8 rounded-lg shadow-sm my-10 mx-auto max-w-[600px]">
+            <Section className="mb-6 text-center">
+              <Img
+                src="http://localhost:8080/static/full_logo.png?height=48&width=48"
+                width="48"
+                height="48"
+                alt="Executask Logo"
+                className="mx-auto"
+              />
+              <Heading className="text-2xl font-bold text-gray-800 mt-4">
+                {t("email.rule.heading")}
+              </Heading>
+            </Section>
+
+            <Section className="bg-blue-50 border-l-4 border-blue-400 p-4 mb-6">
+              <Text className="font-semibold text-blue-700 text-lg mb-2">
+                {t("email.rule.ran_on", ruleName, todoTitle)}
+              </Text>
+              <Text className="text-gray-700 text-base">{message}</Text>
+            </Section>
+
+            <Section className="my-8 text-center">
+              <Button
+                className="bg-blue-600 hover:bg-blue-700 text-white font-medium rounded-md px-6 py-3"
+                href={`/todos?id=${todoID}`}
+              >
+                {t("email.view_todo")}
+              </Button>
+            </Section>
+
+            <Text className="text-gray-500 text-sm text-center">{replyHint}</Text>
+
+            <Hr className="border-gray-200 my-6" />
+
+            <Section>
+              <Text className="text-gray-600 text-sm">
+                {t("email.rule.footer")}{" "}
+                <Link href="/settings/rules" className="text-blue-600 underline">
+                  {t("email.manage_rules")}
+                </Link>
+                .
+              </Text>
+            </Section>
+
+            <Section className="mt-8 text-center">
+              <Text className="text-gray-500 text-xs">
+                © {new Date().getFullYear()} Executask. {t("email.rights_reserved")}
+              </Text>
+            </Section>
+          </Container>
+        </Body>
+      </Tailwind>
+    </Html>
+  );
+};
+
+RuleNotificationEmail.PreviewProps = {
+  ruleName: "Escalate urgent work",
+  todoTitle: "Prepare the quarterly report",
+  todoID: "123e4567-e89b-12d3-a456-426614174000",
+  message: "This one is blocking the release, pick it up first.",
+  replyHint: "Reply to this email to comment on the todo.",
+};
+
+export default RuleNotificationEmail;
@@ -0,0 +1,116 @@
+import {
+  Body,
+  Button,
+  Container,
+  Head,
+  Heading,
+  Hr,
+  Html,
+  Img,
+  Preview,
+  Section,
+  Text,
+  Tailwind,
+} from "@react-email/components";
+import { t } from "../i18n";
+
+interface ScheduledReportEmailProps {
+  reportName: string;
+  format: string;
+  ownerEmail: string;
+  downloadURL: string;
+  size: string;
+  linkExpiresAt: string;
+  expiresAt: string;
+}
+
+export const ScheduledReportEmail = ({
+  reportName = "{{.ReportName}}",
+  format = "{{.Format}}",
+  ownerEmail = "{{.OwnerEmail}}",
+  downloadURL = "{{.DownloadURL}}",
+  size = "{{.Size}}",
+  linkExpiresAt = "{{.LinkExpiresAt}}",
+  expiresAt = "{{.ExpiresAt}}",
+}: ScheduledReportEmailProps) => {
+  return (
+    <Html lang="{{.Locale}}">
+      <Head />
+      <Preview>{t("email.scheduled_report.preview", reportName)}</Preview>
+      <Tailwind>
+        <Body className="bg-gray-100 font-sans">
+          <Container className="bg-white p-8 rounded-lg shadow-sm my-10 mx-auto max-w-[600px]">
+            <Section className="mb-6 text-center">
+              <Img
+                src="http://localhost:8080/static/full_logo.png?height=48&width=48"
+                width="48"
+                height="48"
+                alt="Executask Logo"
+                className="mx-auto"
+              />
+              <Heading className="text-2xl font-bold text-gray-800 mt-4">
+                {t("email.scheduled_report.heading")}
+              </Heading>
+            </Section>
+
+            <Section className="bg-blue-50 border-l-4 border-blue-400 p-4 mb-6">
+              <Text className="font-semibold text-blue-700 text-lg mb-2">
+                {t("email.scheduled_report.ready", reportName, format, size)}
+              </Text>
+              <Text className="text-gray-700 text-base">
+                {t("email.link_expires", linkExpiresAt)}
+              </Text>
+            </Section>
+
+            <Section>
+              <Text className="text-gray-700 text-base">
+                {t("email.scheduled_report.contents", ownerEmail)}
+              </Text>
+            </Section>
+
+            <Section className="my-8 text-center">
+              <Button
+                className="bg-blue-600 hover:bg-blue-700 text-white font-medium rounded-md px-6 py-3"
+                href={downloadURL}
+              >
+                {t("email.scheduled_report.download")}
+              </Button>
+            </Section>
+
+            <Section>
+              <Text className="text-gray-700 text-base">
+                {t("email.scheduled_report.deleted_on", expiresAt)}
+              </Text>
+            </Section>
+
+            <Hr className="border-gray-200 my-6" />
+
+            <Section>
+              <Text className="text-gray-600 text-sm">
+                {t("email.scheduled_report.footer", ownerEmail)}
+              </Text>
+            </Section>
+
+            <Section className="mt-8 text-center">
+              <Text className="text-gray-500 text-xs">
+                © {new Date().getFullYear()} Executask. {t("email.rights_reserved")}
+              </Text>
+            </Section>
+          </Container>
+        </Body>
+      </Tailwind>
+    </Html>
+  );
+};
+
+ScheduledReportEmail.PreviewProps = {
+  reportName: "Weekly status",
+  format: "PDF",
+  ownerEmail: "alex@example.com",
+  downloadURL: "https://example.com/exports/report.pdf",
+  size: "248.1 kB",
+  linkExpiresAt: "Tuesday, January 16, 2025 at 5:00 PM",
+  expiresAt: "Monday, January 22, 2025",
+};
+
+export default ScheduledReportEmail;
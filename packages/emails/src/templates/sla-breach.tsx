@@ -0,0 +1,108 @@
+import {
+  Body,
+  Button,
+  Container,
+  Head,
+  Heading,
+  Hr,
+  Html,
+  Img,
+  Link,
+  Preview,
+  Section,
+  Text,
+  Tailwind,
+} from "@react-email/components";
+import { t } from "../i18n";
+
+interface SLABreachEmailProps {
+  policyName: string;
+  maxAge: string;
+  todoTitle: string;
+  todoID: string;
+  openedAt: string;
+  replyHint: string;
+}
+
+export const SLABreachEmail = ({
+  policyName = "{{.PolicyName}}",
+  maxAge = "{{.MaxAge}}",
+  todoTitle = "{{.TodoTitle}}",
+  todoID = "{{.TodoID}}",
+  openedAt = "{{.OpenedAt}}",
+  replyHint = "{{.ReplyHint}}",
+}: SLABreachEmailProps) => {
+  return (
+    <Html lang="{{.Locale}}">
+      <Head />
+      <Preview>{t("email.sla.preview", todoTitle, policyName)}</Preview>
+      <Tailwind>
+        <Body className="bg-gray-100 font-sans">
+          <Container className="bg-white p-8 rounded-lg shadow-sm my-10 mx-auto max-w-[600px]">
+            <Section className="mb-6 text-center">
+              <Img
+                src="http://localhost:8080/static/full_logo.png?height=48&width=48"
+                width="48"
+                height="48"
+                alt="Executask Logo"
+                className="mx-auto"
+              />
+              <Heading className="text-2xl font-bold text-gray-800 mt-4">
+                {t("email.sla.heading")}
+              </Heading>
+            </Section>
+
+            <Section className="bg-amber-50 border-l-4 border-amber-400 p-4 mb-6">
+              <Text className="font-semibold text-amber-700 text-lg mb-2">
+                {t("email.sla.open_since", todoTitle, openedAt)}
+              </Text>
+              <Text className="text-gray-700 text-base">
+                {t("email.sla.policy", policyName, maxAge)}
+              </Text>
+            </Section>
+
+            <Section className="my-8 text-center">
+              <Button
+                className="bg-blue-600 hover:bg-blue-700 text-white font-medium rounded-md px-6 py-3"
+                href={`/todos?id=${todoID}`}
+              >
+                {t("email.view_todo")}
+              </Button>
+            </Section>
+
+            <Text className="text-gray-500 text-sm text-center">{replyHint}</Text>
+
+            <Hr className="border-gray-200 my-6" />
+
+            <Section>
+              <Text className="text-gray-600 text-sm">
+                {t("email.sla.footer")}{" "}
+                <Link href="/workspace/sla" className="text-blue-600 underline">
+                  {t("email.sla.see_escalated")}
+                </Link>
+                .
+              </Text>
+            </Section>
+
+            <Section className="mt-8 text-center">
+              <Text className="text-gray-500 text-xs">
+                © {new Date().getFullYear()} Executask. {t("email.rights_reserved")}
+              </Text>
+            </Section>
+          </Container>
+        </Body>
+      </Tailwind>
+    </Html>
+  );
+};
+
+SLABreachEmail.PreviewProps = {
+  policyName: "High priority within 3 days",
+  maxAge: "3 days",
+  todoTitle: "Prepare the quarterly report",
+  todoID: "123e4567-e89b-12d3-a456-426614174000",
+  openedAt: "Monday, March 2, 2026 at 9:00 AM",
+  replyHint: "Reply to this email to comment on the todo.",
+};
+
+export default SLABreachEmail;
@@ -13,6 +13,7 @@ import {
   Text,
   Tailwind,
 } from "@react-email/components";
+import { t, tn } from "../i18n";
 
 interface Todo {
   id: string;
@@ -52,10 +53,10 @@ export const WeeklyReportEmail = ({
       : 0;
 
   const getMotivationalMessage = () => {
-    if (completionRate >= 80) return "🌟 Outstanding work this week!";
-    if (completionRate >= 60) return "👍 Great progress this week!";
-    if (completionRate >= 40) return "💪 Keep pushing forward!";
-    return "🎯 Let's focus on the priorities ahead!";
+    if (completionRate >= 80) return t("email.weekly.motivation_outstanding");
+    if (completionRate >= 60) return t("email.weekly.motivation_great");
+    if (completionRate >= 40) return t("email.weekly.motivation_push");
+    return t("email.weekly.motivation_focus");
   };
 
   const getPriorityBadgeColor = (priority: string) => {
@@ -72,10 +73,10 @@ export const WeeklyReportEmail = ({
   };
 
   return (
-    <Html>
+    <Html lang="{{.Locale}}">
       <Head />
       <Preview>
-        Your Weekly Productivity Report ({weekStart} - {weekEnd})
+        {t("email.weekly.preview", weekStart, weekEnd)}
       </Preview>
       <Tailwind>
         <Body className="bg-gray-100 font-sans">
@@ -89,7 +90,7 @@ export const WeeklyReportEmail = ({
                 className="mx-auto"
               />
               <Heading className="text-2xl font-bold text-gray-800 mt-4">
-                📊 Weekly Report
+                {t("email.weekly.heading")}
               </Heading>
               <Text className="text-gray-600 text-lg">
                 {weekStart} - {weekEnd}
@@ -109,19 +110,25 @@ export const WeeklyReportEmail = ({
                   <Text className="text-2xl font-bold text-green-600 mb-1">
                     {completedCount}
                   </Text>
-                  <Text className="text-sm text-green-700">Completed</Text>
+                  <Text className="text-sm text-green-700">
+                    {t("email.weekly.completed")}
+                  </Text>
                 </div>
                 <div className="bg-blue-50 p-4 rounded-lg">
                   <Text className="text-2xl font-bold text-blue-600 mb-1">
                     {activeCount}
                   </Text>
-                  <Text className="text-sm text-blue-700">Active</Text>
+                  <Text className="text-sm text-blue-700">
+                    {t("email.weekly.active")}
+                  </Text>
                 </div>
                 <div className="bg-red-50 p-4 rounded-lg">
                   <Text className="text-2xl font-bold text-red-600 mb-1">
                     {overdueCount}
                   </Text>
-                  <Text className="text-sm text-red-700">Overdue</Text>
+                  <Text className="text-sm text-red-700">
+                    {t("email.weekly.overdue")}
+                  </Text>
                 </div>
               </div>
             </Section>
@@ -129,7 +136,7 @@ export const WeeklyReportEmail = ({
             {/* Progress Bar */}
             <Section className="mb-8">
               <Text className="text-lg font-semibold text-gray-800 mb-2">
-                Weekly Completion Rate: {completionRate}%
+                {t("email.weekly.completion_rate", completionRate)}
               </Text>
               <div className="w-full bg-gray-200 rounded-full h-2">
                 <div
@@ -150,7 +157,7 @@ export const WeeklyReportEmail = ({
             {hasCompleted && completedTodos.length > 0 && (
               <Section className="mb-6">
                 <Heading className="text-lg font-semibold text-green-700 mb-3">
-                  ✅ Completed This Week ({completedCount})
+                  {t("email.weekly.completed_this_week", completedCount)}
                 </Heading>
                 <div className="space-y-2">
                   {completedTodos.slice(0, 5).map((todo, index) => (
@@ -168,7 +175,7 @@ export const WeeklyReportEmail = ({
                   ))}
                   {completedTodos.length > 5 && (
                     <Text className="text-sm text-gray-600 text-center">
-                      ... and {completedTodos.length - 5} more completed todos
+                      {tn("email.weekly.more_completed", completedTodos.length - 5)}
                     </Text>
                   )}
                 </div>
@@ -179,7 +186,7 @@ export const WeeklyReportEmail = ({
             {hasOverdue && overdueTodos.length > 0 && (
               <Section className="mb-6">
                 <Heading className="text-lg font-semibold text-red-700 mb-3">
-                  ⚠️ Needs Attention ({overdueCount} overdue)
+                  {t("email.weekly.needs_attention", overdueCount)}
                 </Heading>
                 <div className="space-y-2">
                   {overdueTodos.slice(0, 5).map((todo, index) => (
@@ -191,7 +198,7 @@ export const WeeklyReportEmail = ({
                         <Text className="text-gray-800">{todo.title}</Text>
                         {todo.dueDate && (
                           <Text className="text-sm text-red-600">
-                            Due: {todo.dueDate}
+                            {t("email.weekly.due", todo.dueDate)}
                           </Text>
                         )}
                       </div>
@@ -204,7 +211,7 @@ export const WeeklyReportEmail = ({
                   ))}
                   {overdueTodos.length > 5 && (
                     <Text className="text-sm text-gray-600 text-center">
-                      ... and {overdueTodos.length - 5} more overdue todos
+                      {tn("email.weekly.more_overdue", overdueTodos.length - 5)}
                     </Text>
                   )}
                 </div>
@@ -217,14 +224,14 @@ export const WeeklyReportEmail = ({
                 className="bg-blue-600 hover:bg-blue-700 text-white font-medium rounded-md px-6 py-3 mr-4"
                 href="/dashboard"
               >
-                View Dashboard
+                {t("email.weekly.view_dashboard")}
               </Button>
               {hasOverdue && (
                 <Button
                   className="bg-red-600 hover:bg-red-700 text-white font-medium rounded-md px-6 py-3"
                   href="/todos?filter=overdue"
                 >
-                  Review Overdue
+                  {t("email.weekly.review_overdue")}
                 </Button>
               )}
             </Section>
@@ -232,16 +239,16 @@ export const WeeklyReportEmail = ({
             {/* Weekly Tips */}
             <Section className="bg-blue-50 border-l-4 border-blue-400 p-4 mb-6">
               <Text className="text-blue-800 text-base font-medium mb-2">
-                💡 Productivity Tip
+                {t("email.weekly.tip_heading")}
               </Text>
               <Text className="text-blue-700 text-sm">
                 {completionRate >= 80
-                  ? "You're on fire! Keep up this momentum by planning next week's priorities."
+                  ? t("email.weekly.tip_momentum")
                   : completionRate >= 60
-                    ? "Good progress! Try time-blocking your most important tasks for better focus."
+                    ? t("email.weekly.tip_time_blocking")
                     : parseInt(overdueCount) > 0
-                      ? "Focus on clearing overdue items first, then plan realistic due dates for new tasks."
-                      : "Start your week by identifying 3 key priorities and tackle them first."}
+                      ? t("email.weekly.tip_overdue")
+                      : t("email.weekly.tip_priorities")}
               </Text>
             </Section>
 
@@ -249,16 +256,16 @@ export const WeeklyReportEmail = ({
 
             <Section>
               <Text className="text-gray-600 text-sm">
-                This is your weekly productivity summary.{" "}
+                {t("email.weekly.summary")}{" "}
                 <Link
                   href="/settings/notifications"
                   className="text-blue-600 underline"
                 >
-                  Manage notification preferences
+                  {t("email.manage_notifications")}
                 </Link>{" "}
-                or{" "}
+                {t("email.weekly.or")}{" "}
                 <Link href="/dashboard" className="text-blue-600 underline">
-                  view your full dashboard
+                  {t("email.weekly.full_dashboard")}
                 </Link>
                 .
               </Text>
@@ -266,7 +273,7 @@ export const WeeklyReportEmail = ({
 
             <Section className="mt-8 text-center">
               <Text className="text-gray-500 text-xs">
-                © {new Date().getFullYear()} Executask. All rights reserved.
+                © {new Date().getFullYear()} Executask. {t("email.rights_reserved")}
               </Text>
             </Section>
           </Container>
@@ -13,6 +13,7 @@ import {
   Text,
   Tailwind,
 } from "@react-email/components";
+import { t } from "../i18n";
 
 interface WelcomeEmailProps {
   userFirstName: string;
@@ -22,22 +23,22 @@ export const WelcomeEmail = ({
   userFirstName = "{{.UserFirstName}}",
 }: WelcomeEmailProps) => {
   return (
-    <Html>
+    <Html lang="{{.Locale}}">
       <Head />
-      <Preview>Welcome to ExecuTask</Preview>
+      <Preview>{t("email.welcome.preview")}</Preview>
       <Tailwind>
         <Body className="bg-gray-100 font-sans">
           <Container className="bg-white p-8 rounded-lg shadow-sm my-10 mx-auto max-w-[600px]">
             <Heading className="text-2xl font-bold text-gray-800 mt-4">
-              Welcome to ExecuTask!
+              {t("email.welcome.heading")}
             </Heading>
 
             <Section>
               <Text className="text-gray-700 text-base">
-                Hi {userFirstName},
+                {t("email.welcome.greeting", userFirstName)}
               </Text>
               <Text className="text-gray-700 text-base">
-                Thank you for joining!
+                {t("email.welcome.thanks")}
               </Text>
             </Section>
 
@@ -46,7 +47,7 @@ export const WelcomeEmail = ({
                 className="bg-orange-600 hover:bg-orange-700 text-white font-medium rounded-md px-6 py-3"
                 href={`/dashboard`}
               >
-                Get Started
+                {t("email.welcome.get_started")}
               </Button>
             </Section>
 
@@ -54,9 +55,9 @@ export const WelcomeEmail = ({
 
             <Section>
               <Text className="text-gray-600 text-sm">
-                If you have any questions, feel free to{" "}
+                {t("email.welcome.questions")}{" "}
                 <Link href={`/support`} className="text-orange-600 underline">
-                  contact our support team
+                  {t("email.welcome.contact_support")}
                 </Link>
                 .
               </Text>
@@ -64,7 +65,7 @@ export const WelcomeEmail = ({
 
             <Section className="mt-8 text-center">
               <Text className="text-gray-500 text-xs">
-                © {new Date().getFullYear()} Alfred. All rights reserved.
+                © {new Date().getFullYear()} Alfred. {t("email.rights_reserved")}
               </Text>
               <Text className="text-gray-500 text-xs">
                 123 Project Street, Suite 100, San Francisco, CA 94103
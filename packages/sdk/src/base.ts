@@ -0,0 +1,116 @@
+import type { Problem } from "./client.gen.js";
+
+/** Returns the bearer token of a request, e.g. `() => clerk.session?.getToken()` */
+export type TokenProvider = () => string | null | undefined | Promise<string | null | undefined>;
+
+export interface ClientOptions {
+  /** Base URL of the API, e.g. https://api.executask.com */
+  baseUrl: string;
+  /** Static bearer token or a provider called before every request */
+  token?: string | TokenProvider;
+  /** Custom fetch implementation, defaults to the global fetch */
+  fetch?: typeof fetch;
+  /** Headers sent with every request */
+  headers?: Record<string, string>;
+}
+
+export interface RequestOptions {
+  query?: object;
+  body?: unknown;
+  upload?: { field: string; file: Blob; filename?: string | undefined };
+}
+
+/** Thrown for every non 2xx response, body holds the RFC 7807 problem details */
+export class ApiError extends Error {
+  readonly status: number;
+  readonly body: Problem | undefined;
+
+  constructor(status: number, body: Problem | undefined) {
+    super(body?.message ? `${status} ${body.code ?? ""}: ${body.message}` : `Request failed with status ${status}`);
+    this.name = "ApiError";
+    this.status = status;
+    this.body = body;
+  }
+}
+
+export class BaseClient {
+  private readonly baseUrl: string;
+  private readonly token: ClientOptions["token"];
+  private readonly fetchFn: typeof fetch;
+  private readonly headers: Record<string, string>;
+
+  constructor(options: ClientOptions) {
+    this.baseUrl = options.baseUrl.replace(/\/$/, "");
+    this.token = options.token;
+    this.fetchFn = options.fetch ?? globalThis.fetch.bind(globalThis);
+    this.headers = options.headers ?? {};
+  }
+
+  protected async request<T>(method: string, path: string, options: RequestOptions = {}): Promise<T> {
+    const url = new URL(this.baseUrl + path);
+    for (const [key, value] of Object.entries(options.query ?? {})) {
+      if (value === undefined || value === null) continue;
+      url.searchParams.set(key, value instanceof Date ? value.toISOString() : String(value));
+    }
+
+    const headers: Record<string, string> = { Accept: "application/json, application/problem+json", ...this.headers };
+    const token = typeof this.token === "function" ? await this.token() : this.token;
+    if (token) {
+      headers.Authorization = `Bearer ${token}`;
+    }
+
+    let body: BodyInit | undefined;
+    if (options.upload) {
+      const form = new FormData();
+      form.append(options.upload.field, options.upload.file, options.upload.filename);
+      body = form;
+    } else if (options.body !== undefined) {
+      headers["Content-Type"] = "application/json";
+      body = JSON.stringify(options.body);
+    }
+
+    const res = await this.fetchFn(url, { method, headers, body });
+    if (!res.ok) {
+      const errorBody = (await res.json().catch(() => undefined)) as Problem | undefined;
+      throw new ApiError(res.status, errorBody);
+    }
+
+    if (res.status === 204) {
+      return undefined as T;
+    }
+    return (await res.json()) as T;
+  }
+
+  /** Yields the items of every page from start (1 when omitted) until the last page */
+  protected async *paginate<T>(
+    start: number | undefined,
+    fetchPage: (page: number) => Promise<{ data?: T[]; totalPages?: number }>,
+  ): AsyncGenerator<T> {
+    for (let page = start ?? 1; ; page++) {
+      const res = await fetchPage(page);
+      const items = res.data ?? [];
+      yield* items;
+
+      if (items.length === 0 || page >= (res.totalPages ?? 0)) {
+        return;
+      }
+    }
+  }
+
+  /** Yields the items of a cursor feed until the server reports it is caught up */
+  protected async *follow<T>(
+    start: string | undefined,
+    fetchPage: (cursor: string | undefined) => Promise<{ items: T[]; nextCursor?: string | undefined; hasMore?: boolean | undefined }>,
+  ): AsyncGenerator<T> {
+    let cursor = start;
+    for (;;) {
+      const res = await fetchPage(cursor);
+      yield* res.items;
+
+      if (!res.hasMore || !res.nextCursor) {
+        return;
+      }
+      cursor = res.nextCursor;
+    }
+  }
+}
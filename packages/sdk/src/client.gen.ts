@@ -0,0 +1,2847 @@
+// Code generated by sdkgen from the ExecuTask OpenAPI document. DO NOT EDIT.
+
+import { BaseClient } from "./base.js";
+
+export interface APIClient {
+  allowedOrigins?: string[];
+  createdAt?: string;
+  enabled?: boolean;
+  id?: string;
+  name?: string;
+  updatedAt?: string;
+}
+
+export interface APIUsage {
+  from?: string;
+  notifications?: NotificationStats;
+  rateLimit?: RateLimitStats;
+  requests?: RequestStats;
+  to?: string;
+}
+
+export interface AccountExport {
+  _links?: Record<string, Link>;
+  completedAt?: string | null;
+  createdAt?: string;
+  downloadUrl?: string | null;
+  error?: string | null;
+  expiresAt?: string | null;
+  filters?: ExportTodosPDFQuery | null;
+  id?: string;
+  kind?: string;
+  linkExpiresAt?: string | null;
+  sizeBytes?: number | null;
+  status?: string;
+  updatedAt?: string;
+  userId?: string;
+}
+
+export interface Accuracy {
+  averageMinutes?: number;
+  ratio?: number;
+  sampleCount?: number;
+}
+
+export interface Action {
+  message?: string;
+  type?: string;
+  value?: string;
+}
+
+export interface AddCommentPayload {
+  content: string;
+}
+
+export interface AddDependencyPayload {
+  dependsOnId: string;
+}
+
+export interface AddMyDayTodosPayload {
+  timezone?: string | null;
+  todoIds: string[];
+}
+
+export interface AgingPolicy {
+  createdAt?: string;
+  createdBy?: string;
+  id?: string;
+  maxAgeHours?: number;
+  name?: string;
+  notify?: boolean;
+  priority?: string | null;
+  tag?: string;
+  updatedAt?: string;
+  workspaceId?: string;
+}
+
+export interface ApplyTemplatePayload {
+  categoryId?: string | null;
+}
+
+export interface Assignment {
+  date: string;
+  todoId: string;
+}
+
+export interface Backup {
+  _links?: Record<string, Link>;
+  completedAt?: string | null;
+  counts?: Record<string, number>;
+  createdAt?: string;
+  error?: string | null;
+  id?: string;
+  requestedBy?: string;
+  sizeBytes?: number | null;
+  status?: string;
+  updatedAt?: string;
+  workspaceId?: string;
+}
+
+export interface Badge {
+  awardedAt?: string | null;
+  code?: string;
+  description?: string;
+  name?: string;
+}
+
+export interface BatchPayload {
+  requests: SubRequest[];
+}
+
+export interface BatchResponse {
+  responses?: SubResponse[];
+}
+
+export interface BlockedTodo {
+  blockedBy?: string[];
+  title?: string;
+  todoId?: string;
+}
+
+export interface Breach {
+  breachedAt?: string;
+  hoursOverLimit?: number;
+  maxAgeHours?: number;
+  openedAt?: string;
+  policyId?: string;
+  policyName?: string;
+  priority?: string;
+  todoId?: string;
+  todoTitle?: string;
+  userId?: string;
+}
+
+export interface BreachReport {
+  breaches?: Breach[];
+  byMember?: MemberBreaches[];
+  byPolicy?: PolicyBreaches[];
+  generatedAt?: string;
+  workspaceId?: string;
+}
+
+export interface Bucket {
+  apiCalls?: number;
+  notifications?: number;
+  start?: string;
+  storageBytes?: number | null;
+}
+
+export interface BulkCompletion {
+  alreadyCompleted?: string[];
+  blocked?: BlockedTodo[];
+  completed?: Todo[];
+}
+
+export interface Bundle {
+  categories: BundleCategory[];
+  description?: string | null;
+  exportedAt?: string;
+  format: string;
+  name: string;
+  version: number;
+}
+
+export interface BundleCategory {
+  color: string;
+  description?: string | null;
+  name: string;
+}
+
+export interface CSRFToken {
+  token?: string;
+}
+
+export interface CapturePayload {
+  title: string;
+}
+
+export interface Category {
+  _links?: Record<string, Link>;
+  color?: string;
+  createdAt?: string;
+  description?: string | null;
+  id?: string;
+  name?: string;
+  openTodoCount?: number;
+  updatedAt?: string;
+  userId?: string;
+  version?: number;
+}
+
+export interface CategoryCompletion {
+  averageCompletionSeconds?: number | null;
+  categoryId?: string;
+  color?: string;
+  completed?: number;
+  name?: string;
+}
+
+export interface CategoryStats {
+  averageCompletionSeconds?: number | null;
+  categoryId?: string;
+  color?: string;
+  completed?: number;
+  name?: string;
+  total?: number;
+}
+
+export interface CategorySuggestion {
+  categoryId?: string;
+  confidence?: number;
+}
+
+export interface Change {
+  action?: string;
+  createdAt?: string;
+  entityId?: string;
+  entityType?: string;
+  version?: number | null;
+}
+
+export interface ChangesResponse {
+  changes?: Change[];
+  hasMore?: boolean;
+  nextCursor?: string;
+}
+
+export interface Check {
+  critical?: boolean;
+  error?: string | null;
+  latencyMs?: number;
+  status?: string;
+}
+
+export interface Comment {
+  _links?: Record<string, Link>;
+  content?: string;
+  createdAt?: string;
+  id?: string;
+  linkPreviews?: Preview[];
+  todoId?: string;
+  updatedAt?: string;
+  userId?: string;
+}
+
+export interface CompleteTodosPayload {
+  ids: string[];
+}
+
+export interface ComponentStatus {
+  checkedAt?: string | null;
+  component?: string;
+  history?: StatuspageDay[];
+  status?: string;
+  uptime?: number | null;
+}
+
+export interface Conditions {
+  categoryId?: string | null;
+  priority?: string | null;
+  tag?: string | null;
+  titleContains?: string | null;
+}
+
+export interface ConfigReload {
+  applied?: string[];
+  restartRequired?: string[];
+}
+
+export interface CookieSession {
+  csrfToken?: string;
+  expiresAt?: string;
+}
+
+export interface Cover {
+  attachmentId?: string | null;
+  createdAt?: string;
+  source?: string;
+  status?: string;
+  stockKey?: string | null;
+  todoId?: string;
+  updatedAt?: string;
+  variants?: Record<string, string>;
+}
+
+export interface CreateAPIClientPayload {
+  allowedOrigins: string[];
+  enabled?: boolean | null;
+  name: string;
+}
+
+export interface CreateAgingPolicyPayload {
+  maxAgeHours: number;
+  name: string;
+  notify?: boolean | null;
+  priority?: string | null;
+  tag?: string | null;
+}
+
+export interface CreateCategoryPayload {
+  color: string;
+  description?: string | null;
+  name: string;
+}
+
+export interface CreateEndpointPayload {
+  enabled?: boolean | null;
+  events: string[];
+  url: string;
+}
+
+export interface CreateIncidentPayload {
+  components: string[];
+  impact: "minor" | "major";
+  message: string;
+  startedAt?: string | null;
+  status?: "investigating" | "identified" | "monitoring" | "resolved" | null;
+  title: string;
+}
+
+export interface CreateInvitePayload {
+  allowedDomains?: string[];
+  expiresInHours?: number | null;
+  maxUses?: number | null;
+}
+
+export interface CreateKeyPayload {
+  name: string;
+}
+
+export interface CreateMagicTagPayload {
+  categoryId?: string | null;
+  dueInDays?: number | null;
+  priority?: "low" | "medium" | "high" | null;
+  tag: string;
+  timezone?: string | null;
+}
+
+export interface CreateRulePayload {
+  actions: RuleAction[];
+  conditions?: Conditions | null;
+  enabled?: boolean | null;
+  name: string;
+  trigger: "todo_created" | "todo_completed" | "todo_overdue" | "todo_tagged";
+}
+
+export interface CreateSchedulePayload {
+  dayOfMonth?: number | null;
+  filters?: Filters;
+  format: "pdf" | "csv";
+  frequency: "daily" | "weekly" | "monthly";
+  hour?: number;
+  name: string;
+  recipients: string[];
+  timezone?: string;
+  weekday?: number | null;
+}
+
+export interface CreateTemplatePayload {
+  description?: string | null;
+  dueInDays?: number | null;
+  name: string;
+  priority?: "low" | "medium" | "high" | null;
+  subtasks?: string[];
+  title: string;
+}
+
+export interface CreateTodoPayload {
+  categoryId?: string | null;
+  checkDuplicates?: boolean;
+  description?: string | null;
+  dueDate?: string | null;
+  metadata?: Metadata | null;
+  parentTodoId?: string | null;
+  priority?: string | null;
+  status?: string | null;
+  title: string;
+}
+
+export interface CreatedEndpoint {
+  createdAt?: string;
+  enabled?: boolean;
+  events?: string[];
+  id?: string;
+  secret?: string;
+  updatedAt?: string;
+  url?: string;
+  workspaceId?: string;
+}
+
+export interface CreatedKey {
+  createdAt?: string;
+  id?: string;
+  lastUsedAt?: string | null;
+  name?: string;
+  plan?: string;
+  prefix?: string;
+  secret?: string;
+  updatedAt?: string;
+}
+
+export interface CreatedTodo {
+  _links?: Record<string, Link>;
+  categoryId?: string | null;
+  commentCount?: number;
+  commentsReadAt?: string | null;
+  completedAt?: string | null;
+  completedSubtaskCount?: number;
+  createdAt?: string;
+  description?: string;
+  dueDate?: string | null;
+  duplicates?: DuplicateCandidate[];
+  id?: string;
+  metadata?: Metadata | null;
+  parentTodoId?: string | null;
+  priority?: string;
+  priorityLevel?: number;
+  sortOrder?: number;
+  status?: string;
+  statusCategory?: string;
+  subtaskCount?: number;
+  suggestions?: TodoSuggestions | null;
+  title?: string;
+  unreadCommentCount?: number;
+  updatedAt?: string;
+  userId?: string;
+  version?: number;
+  workspaceId?: string | null;
+}
+
+export interface DailyCompletions {
+  completed?: number;
+  day?: string;
+}
+
+export interface DailyCount {
+  day?: string;
+  requests?: number;
+}
+
+export interface DatabasePoolStats {
+  primary?: PoolStats;
+  replicas?: Record<string, PoolStats>;
+}
+
+export interface Day {
+  date?: string;
+  estimatedMinutes?: number;
+  forecastMinutes?: number;
+  overCapacity?: boolean;
+  overcommitted?: boolean;
+  todos?: Todo[];
+  unestimatedCount?: number;
+}
+
+export interface DaySummary {
+  completed?: number;
+  completionRate?: number;
+  date?: string;
+  planned?: number;
+  rolled?: number;
+}
+
+export interface Dependencies {
+  blockedBy?: Todo[];
+  blocking?: Todo[];
+  todoId?: string;
+}
+
+export interface Dependency {
+  createdAt?: string;
+  dependsOnId?: string;
+  todoId?: string;
+}
+
+export interface DependencyChange {
+  from?: string | null;
+  title?: string;
+  to?: string;
+  todoId?: string;
+}
+
+export interface DuplicateCandidate {
+  categoryId?: string | null;
+  id?: string;
+  similarity?: number;
+  status?: string;
+  title?: string;
+}
+
+export interface EmailReplyPayload {
+  from: string;
+  messageId?: string;
+  text?: string;
+  to: string[];
+}
+
+export interface Endpoint {
+  createdAt?: string;
+  enabled?: boolean;
+  events?: string[];
+  id?: string;
+  updatedAt?: string;
+  url?: string;
+  workspaceId?: string;
+}
+
+export interface Entry {
+  action?: string;
+  actorId?: string;
+  actorRole?: string;
+  after?: unknown;
+  before?: unknown;
+  createdAt?: string;
+  entityId?: string | null;
+  entityType?: string | null;
+  id?: number;
+  impersonatorId?: string | null;
+  ipAddress?: string | null;
+  requestId?: string | null;
+  userAgent?: string | null;
+}
+
+export interface EventType {
+  description?: string;
+  schema?: Record<string, unknown>;
+  type?: string;
+}
+
+export interface Execution {
+  actionsApplied?: string[];
+  createdAt?: string;
+  depth?: number;
+  error?: string | null;
+  id?: string;
+  ruleId?: string;
+  status?: string;
+  todoId?: string;
+  trigger?: string;
+  userId?: string;
+}
+
+export interface ExportTodosPDFQuery {
+  categoryId?: string | null;
+  priority?: string | null;
+  search?: string | null;
+  status?: string | null;
+  tz?: string | null;
+}
+
+export interface FaultInjection {
+  enabled?: boolean;
+  expiresAt?: string | null;
+  rules?: FaultRule[];
+  updatedAt?: string | null;
+  updatedBy?: string | null;
+}
+
+export interface FaultRule {
+  dropPercent?: number;
+  errorKind?: string;
+  errorPercent?: number;
+  latencyMs?: number;
+  latencyPercent?: number;
+  method?: string;
+  route?: string;
+}
+
+export interface FaultRulePayload {
+  dropPercent?: number;
+  errorKind?: "database_busy" | "serialization_failure" | "query_timeout" | "internal";
+  errorPercent?: number;
+  latencyMs?: number;
+  latencyPercent?: number;
+  method?: "GET" | "POST" | "PUT" | "PATCH" | "DELETE";
+  route: string;
+}
+
+export interface FieldError {
+  code?: string;
+  error?: string;
+  field?: string;
+}
+
+export interface Filters {
+  categoryId?: string | null;
+  priority?: string | null;
+  search?: string | null;
+  status?: string | null;
+}
+
+export interface Flag {
+  enabled?: boolean;
+  name?: string;
+  overrides?: Override[];
+  rolloutPercent?: number;
+}
+
+export interface FocusTotals {
+  focusedMinutes?: number;
+  sessions?: number;
+}
+
+export interface Forecast {
+  accuracy?: Accuracy;
+  capacityMinutes?: number;
+  completionDate?: string | null;
+  lateCount?: number;
+  timezone?: string;
+  todos?: ForecastedTodo[];
+  truncated?: boolean;
+}
+
+export interface ForecastedTodo {
+  completionDate?: string;
+  dueDate?: string | null;
+  estimatedMinutes?: number | null;
+  forecastMinutes?: number;
+  late?: boolean;
+  priority?: string;
+  title?: string;
+  todoId?: string;
+}
+
+export interface Freeze {
+  backupId?: string | null;
+  createdAt?: string;
+  frozenBy?: string;
+  purgeAfter?: string;
+  purgedAt?: string | null;
+  reason?: string | null;
+  updatedAt?: string;
+  workspaceId?: string;
+}
+
+export interface FreezeWorkspacePayload {
+  reason?: string | null;
+  retentionDays?: number | null;
+}
+
+export interface GCStats {
+  cpuFraction?: number;
+  lastGcAt?: string | null;
+  lastPauseMs?: number;
+  nextGcBytes?: number;
+  numForcedGc?: number;
+  numGc?: number;
+  pauseTotalMs?: number;
+}
+
+export interface GamificationOverview {
+  badges?: Badge[];
+  completed?: number;
+  goal?: GoalProgress | null;
+  streaks?: Streaks;
+}
+
+export interface GoalProgress {
+  completed?: number;
+  met?: boolean;
+  target?: number;
+  weekStart?: string;
+}
+
+export interface HealthReport {
+  checks?: Record<string, Check>;
+  environment?: string;
+  status?: string;
+  timestamp?: string;
+}
+
+export interface HeatmapCell {
+  completed?: number;
+  hour?: number;
+  weekday?: number;
+}
+
+export interface ImportBundlePayload {
+  bundle: Bundle;
+  onConflict?: "skip" | "rename" | null;
+}
+
+export interface ImportResult {
+  created?: Category[];
+  skipped?: string[];
+}
+
+export interface Incident {
+  components?: string[];
+  createdAt?: string;
+  id?: string;
+  impact?: string;
+  message?: string;
+  resolvedAt?: string | null;
+  startedAt?: string;
+  status?: string;
+  title?: string;
+  updatedAt?: string;
+}
+
+export interface Invitations {
+  accepted?: InviteAcceptance[];
+  closed?: Invite[];
+  pending?: Invite[];
+  workspaceId?: string;
+}
+
+export interface Invite {
+  allowedDomains?: string[];
+  createdAt?: string;
+  createdBy?: string;
+  expiresAt?: string | null;
+  id?: string;
+  maxUses?: number | null;
+  revokedAt?: string | null;
+  status?: string;
+  token?: string | null;
+  updatedAt?: string;
+  useCount?: number;
+  workspaceId?: string;
+}
+
+export interface InviteAcceptance {
+  acceptedAt?: string;
+  email?: string;
+  inviteId?: string;
+  userId?: string;
+  workspaceId?: string;
+}
+
+export interface Item {
+  createdAt?: string;
+  id?: string;
+  title?: string;
+}
+
+export interface Job {
+  id?: string;
+  lastError?: string;
+  lastFailedAt?: string | null;
+  maxRetry?: number;
+  nextProcessAt?: string | null;
+  payload?: string;
+  queue?: string;
+  retried?: number;
+  state?: string;
+  type?: string;
+}
+
+export interface Key {
+  createdAt?: string;
+  id?: string;
+  lastUsedAt?: string | null;
+  name?: string;
+  plan?: string;
+  prefix?: string;
+  updatedAt?: string;
+}
+
+export interface KitCategory {
+  color: string;
+  description?: string | null;
+  name: string;
+}
+
+export interface KitTodo {
+  categoryName?: string | null;
+  description?: string | null;
+  dueInDays?: number | null;
+  priority?: "low" | "medium" | "high" | null;
+  title: string;
+}
+
+export interface Link {
+  href?: string;
+}
+
+export interface Load {
+  estimatedMinutes?: number;
+  openTodos?: number;
+  unestimatedCount?: number;
+}
+
+export interface MagicTag {
+  _links?: Record<string, Link>;
+  categoryId?: string | null;
+  createdAt?: string;
+  dueInDays?: number | null;
+  id?: string;
+  priority?: string | null;
+  tag?: string;
+  timezone?: string;
+  updatedAt?: string;
+}
+
+export interface Matrix {
+  delegate?: Todo[];
+  do?: Todo[];
+  eliminate?: Todo[];
+  schedule?: Todo[];
+  settings?: Settings;
+  truncated?: boolean;
+}
+
+export interface MemberBreaches {
+  breaches?: number;
+  userId?: string;
+}
+
+export interface MemberWorkload {
+  overdue?: Load;
+  overloadedWeeks?: number;
+  unscheduled?: Load;
+  userId?: string;
+  weeks?: WeekLoad[];
+}
+
+export interface MemoryStats {
+  frees?: number;
+  heapAllocBytes?: number;
+  heapIdleBytes?: number;
+  heapInuseBytes?: number;
+  heapObjects?: number;
+  heapReleasedBytes?: number;
+  mallocs?: number;
+  stackInuseBytes?: number;
+  sysBytes?: number;
+  totalAllocBytes?: number;
+}
+
+export interface MergeTodosPayload {
+  sourceIds: string[];
+}
+
+export interface Metadata {
+  color?: string | null;
+  difficulty?: string | null;
+  estimatedMinutes?: number | null;
+  reminder?: string | null;
+  tags?: string[];
+}
+
+export interface MyDay {
+  date?: string;
+  review?: ReviewTodo[];
+  summary?: DaySummary;
+  timezone?: string;
+  todos?: MyDayTodo[];
+}
+
+export interface MyDayTodo {
+  _links?: Record<string, Link>;
+  addedAt?: string;
+  categoryId?: string | null;
+  commentCount?: number;
+  commentsReadAt?: string | null;
+  completedAt?: string | null;
+  completedSubtaskCount?: number;
+  createdAt?: string;
+  description?: string;
+  dueDate?: string | null;
+  id?: string;
+  metadata?: Metadata | null;
+  parentTodoId?: string | null;
+  priority?: string;
+  priorityLevel?: number;
+  sortOrder?: number;
+  status?: string;
+  statusCategory?: string;
+  subtaskCount?: number;
+  title?: string;
+  unreadCommentCount?: number;
+  updatedAt?: string;
+  userId?: string;
+  version?: number;
+  workspaceId?: string | null;
+}
+
+export interface Note {
+  body?: string;
+  createdAt?: string;
+  todoId?: string;
+  updatedAt?: string;
+}
+
+export interface NotificationStats {
+  dailyAverage?: number;
+  delivered?: number;
+}
+
+export interface OnboardingKit {
+  categories?: KitCategory[];
+  createdAt?: string;
+  todos?: KitTodo[];
+  updatedAt?: string;
+  updatedBy?: string;
+  workspaceId?: string;
+}
+
+export interface OverdueRatio {
+  due?: number;
+  overdue?: number;
+  ratio?: number;
+}
+
+export interface Override {
+  createdAt?: string;
+  enabled?: boolean;
+  flag?: string;
+  id?: string;
+  scope?: string;
+  subjectId?: string;
+  updatedAt?: string;
+  updatedBy?: string;
+}
+
+export interface Overview {
+  default?: Rules;
+  defaultEnforced?: boolean;
+  policies?: Policy[];
+}
+
+export interface Page {
+  components?: ComponentStatus[];
+  historyDays?: number;
+  incidents?: Incident[];
+  status?: string;
+}
+
+export interface PaginatedResponseCategory {
+  _links?: Record<string, Link>;
+  data?: Category[];
+  limit?: number;
+  page?: number;
+  total?: number;
+  totalPages?: number;
+}
+
+export interface PaginatedResponseEntry {
+  _links?: Record<string, Link>;
+  data?: Entry[];
+  limit?: number;
+  page?: number;
+  total?: number;
+  totalPages?: number;
+}
+
+export interface PaginatedResponseJob {
+  _links?: Record<string, Link>;
+  data?: Job[];
+  limit?: number;
+  page?: number;
+  total?: number;
+  totalPages?: number;
+}
+
+export interface PaginatedResponsePopulatedTodo {
+  _links?: Record<string, Link>;
+  data?: PopulatedTodo[];
+  limit?: number;
+  page?: number;
+  total?: number;
+  totalPages?: number;
+}
+
+export interface Planner {
+  accuracy?: Accuracy;
+  backlog?: Todo[];
+  capacityMinutes?: number;
+  days?: Day[];
+  timezone?: string;
+  week?: string;
+}
+
+export interface Policy {
+  archivedTodoMonths?: number | null;
+  attachmentDays?: number | null;
+  createdAt?: string;
+  enforce?: boolean;
+  maxCommentsPerTodo?: number | null;
+  updatedAt?: string;
+  updatedBy?: string;
+  workspaceId?: string;
+}
+
+export interface PolicyBreaches {
+  breaches?: number;
+  policyId?: string;
+  policyName?: string;
+}
+
+export interface PoolStats {
+  acquireCount?: number;
+  acquireDurationMs?: number;
+  acquiredConns?: number;
+  canceledAcquireCount?: number;
+  constructingConns?: number;
+  emptyAcquireCount?: number;
+  idleConns?: number;
+  maxConns?: number;
+  totalConns?: number;
+}
+
+export interface PopulatedTodo {
+  _links?: Record<string, Link>;
+  attachments?: TodoAttachment[];
+  category?: Category | null;
+  categoryId?: string | null;
+  children?: Todo[];
+  commentCount?: number;
+  comments?: Comment[];
+  commentsReadAt?: string | null;
+  completedAt?: string | null;
+  completedSubtaskCount?: number;
+  cover?: Cover | null;
+  createdAt?: string;
+  description?: string;
+  dueDate?: string | null;
+  id?: string;
+  linkPreviews?: Preview[];
+  metadata?: Metadata | null;
+  parentTodoId?: string | null;
+  priority?: string;
+  priorityLevel?: number;
+  sortOrder?: number;
+  status?: string;
+  statusCategory?: string;
+  subtaskCount?: number;
+  suggestedReminders?: ReminderSuggestion[];
+  title?: string;
+  unread?: boolean;
+  unreadCommentCount?: number;
+  updatedAt?: string;
+  userId?: string;
+  version?: number;
+  viewedAt?: string | null;
+  workspaceId?: string | null;
+}
+
+export interface Preference {
+  locale?: string | null;
+  supported?: string[];
+}
+
+export interface Preview {
+  description?: string | null;
+  fetchedAt?: string;
+  imageUrl?: string | null;
+  siteName?: string | null;
+  title?: string | null;
+  url?: string;
+}
+
+export interface PriorityCompletion {
+  averageCompletionSeconds?: number | null;
+  completed?: number;
+  priority?: string;
+}
+
+export interface PrioritySuggestion {
+  confidence?: number;
+  priority?: string;
+}
+
+export interface Problem {
+  action?: Action | null;
+  code?: string;
+  debug?: string;
+  detail?: string;
+  errors?: FieldError[];
+  instance?: string;
+  message?: string;
+  override?: boolean;
+  requestId?: string;
+  retryable?: boolean;
+  status?: number;
+  title?: string;
+  type?: string;
+}
+
+export interface Productivity {
+  byCategory?: CategoryCompletion[];
+  byPriority?: PriorityCompletion[];
+  days?: number;
+  focus?: FocusTotals;
+  heatmap?: HeatmapCell[];
+  overdueRatio?: OverdueRatio;
+  since?: string;
+  streaks?: Streaks;
+}
+
+export interface RateLimitStats {
+  burst?: number;
+  hourlyAllowance?: number;
+  peakHour?: string | null;
+  peakHourRequests?: number;
+  peakHourUtilization?: number;
+  requestsPerSecond?: number;
+}
+
+export interface RedisPoolStats {
+  hits?: number;
+  idleConns?: number;
+  misses?: number;
+  staleConns?: number;
+  timeouts?: number;
+  totalConns?: number;
+}
+
+export interface ReminderSuggestion {
+  basis?: string;
+  confidence?: number;
+  remindAt?: string;
+  sampleSize?: number;
+}
+
+export interface Report {
+  archivedTodos?: number;
+  attachmentBytes?: number;
+  attachments?: number;
+  comments?: number;
+  dryRun?: boolean;
+  rules?: Rules;
+  workspaceId?: string;
+}
+
+export interface RequestStats {
+  daily?: DailyCount[];
+  dailyAverage?: number;
+  peakDay?: string | null;
+  peakDayRequests?: number;
+  total?: number;
+}
+
+export interface RequestTodoListExportPayload {
+  categoryId?: string | null;
+  priority?: string | null;
+  search?: string | null;
+  status?: string | null;
+  tz?: string | null;
+}
+
+export interface Restore {
+  _links?: Record<string, Link>;
+  backupId?: string;
+  completedAt?: string | null;
+  counts?: Record<string, number>;
+  createdAt?: string;
+  error?: string | null;
+  id?: string;
+  requestedBy?: string;
+  status?: string;
+  targetWorkspaceId?: string;
+  updatedAt?: string;
+}
+
+export interface RestoreBackupPayload {
+  targetWorkspaceId: string;
+}
+
+export interface Result {
+  _links?: Record<string, Link>;
+  categoryId?: string | null;
+  commentCount?: number;
+  commentsReadAt?: string | null;
+  completedAt?: string | null;
+  completedSubtaskCount?: number;
+  createdAt?: string;
+  description?: string;
+  dueDate?: string | null;
+  id?: string;
+  metadata?: Metadata | null;
+  parentTodoId?: string | null;
+  priority?: string;
+  priorityLevel?: number;
+  score?: number;
+  sortOrder?: number;
+  status?: string;
+  statusCategory?: string;
+  subtaskCount?: number;
+  title?: string;
+  unreadCommentCount?: number;
+  updatedAt?: string;
+  userId?: string;
+  version?: number;
+  workspaceId?: string | null;
+}
+
+export interface Results {
+  data?: Result[];
+  mode?: string;
+}
+
+export interface ReviewTodo {
+  _links?: Record<string, Link>;
+  categoryId?: string | null;
+  commentCount?: number;
+  commentsReadAt?: string | null;
+  completedAt?: string | null;
+  completedSubtaskCount?: number;
+  createdAt?: string;
+  description?: string;
+  dueDate?: string | null;
+  id?: string;
+  metadata?: Metadata | null;
+  parentTodoId?: string | null;
+  plannedFor?: string;
+  priority?: string;
+  priorityLevel?: number;
+  sortOrder?: number;
+  status?: string;
+  statusCategory?: string;
+  subtaskCount?: number;
+  title?: string;
+  unreadCommentCount?: number;
+  updatedAt?: string;
+  userId?: string;
+  version?: number;
+  workspaceId?: string | null;
+}
+
+export interface Rule {
+  _links?: Record<string, Link>;
+  actions?: RuleAction[];
+  conditions?: Conditions;
+  createdAt?: string;
+  enabled?: boolean;
+  id?: string;
+  name?: string;
+  trigger?: string;
+  updatedAt?: string;
+  userId?: string;
+}
+
+export interface RuleAction {
+  categoryId?: string | null;
+  dueInDays?: number | null;
+  message?: string | null;
+  priority?: "low" | "medium" | "high" | null;
+  tag?: string | null;
+  title?: string | null;
+  type: "set_priority" | "move_category" | "add_tag" | "notify" | "create_follow_up";
+}
+
+export interface Rules {
+  archivedTodoMonths?: number | null;
+  attachmentDays?: number | null;
+  maxCommentsPerTodo?: number | null;
+}
+
+export interface RuntimeStats {
+  database?: DatabasePoolStats;
+  gc?: GCStats;
+  goVersion?: string;
+  gomaxprocs?: number;
+  goroutines?: number;
+  memory?: MemoryStats;
+  numCpu?: number;
+  redis?: RedisPoolStats | null;
+  timestamp?: string;
+}
+
+export interface Schedule {
+  _links?: Record<string, Link>;
+  createdAt?: string;
+  dayOfMonth?: number | null;
+  filters?: Filters;
+  format?: string;
+  frequency: "daily" | "weekly" | "monthly";
+  hour?: number;
+  id?: string;
+  lastError?: string | null;
+  lastRunAt?: string | null;
+  name?: string;
+  nextRunAt?: string;
+  pausedAt?: string | null;
+  recipients?: string[];
+  timezone?: string;
+  updatedAt?: string;
+  weekday?: number | null;
+}
+
+export interface SchedulePayload {
+  assignments: Assignment[];
+  timezone?: string | null;
+}
+
+export interface ServerMode {
+  message?: string;
+  mode?: string;
+  updatedAt?: string | null;
+  updatedBy?: string | null;
+}
+
+export interface Session {
+  _links?: Record<string, Link>;
+  completedAt?: string | null;
+  createdAt?: string;
+  focusedSeconds?: number;
+  id?: string;
+  plannedMinutes?: number;
+  resumedAt?: string | null;
+  status?: string;
+  todoId?: string;
+  updatedAt?: string;
+  userId?: string;
+}
+
+export interface SetCoverPayload {
+  attachmentId?: string | null;
+  stockKey?: string | null;
+}
+
+export interface SetFaultInjectionPayload {
+  rules: FaultRulePayload[];
+  ttlSeconds?: number | null;
+}
+
+export interface SetGoalPayload {
+  target: number;
+}
+
+export interface SetKeyPlanPayload {
+  plan: string;
+}
+
+export interface SetNotePayload {
+  body: string;
+}
+
+export interface SetOnboardingKitPayload {
+  categories?: KitCategory[];
+  todos?: KitTodo[];
+}
+
+export interface SetOverridePayload {
+  enabled: boolean | null;
+  scope: "user" | "workspace" | "global";
+  subjectId?: string;
+}
+
+export interface SetPolicyPayload {
+  archivedTodoMonths?: number | null;
+  attachmentDays?: number | null;
+  enforce: boolean | null;
+  maxCommentsPerTodo?: number | null;
+}
+
+export interface SetServerModePayload {
+  message?: string;
+  mode: "normal" | "maintenance" | "read_only";
+}
+
+export interface SetStorageQuotaPayload {
+  quotaBytes: number | null;
+}
+
+export interface SetWorkflowPayload {
+  priorities?: WorkflowPriority[];
+  priorityMapping?: Record<string, string>;
+  statusMapping?: Record<string, string>;
+  statuses?: WorkflowStatus[];
+}
+
+export interface Settings {
+  importantPriority?: string;
+  urgentWithinHours?: number;
+}
+
+export interface Shift {
+  applied?: boolean;
+  changes?: DependencyChange[];
+  shiftSeconds?: number;
+  skipped?: SkippedTodo[];
+  todoId?: string;
+}
+
+export interface ShiftDueDatePayload {
+  cascade?: boolean | null;
+  dueDate: string;
+}
+
+export interface SkippedTodo {
+  reason?: string;
+  title?: string;
+  todoId?: string;
+}
+
+export interface StartSessionPayload {
+  plannedMinutes?: number | null;
+  todoId: string;
+}
+
+export interface StatsOverview {
+  averageCompletionSeconds?: number | null;
+  categories?: CategoryStats[];
+  completed?: number;
+  completedPerDay?: DailyCompletions[];
+  open?: number;
+  refreshedAt?: string | null;
+  total?: number;
+}
+
+export interface StatuspageDay {
+  checks?: number;
+  day?: string;
+  degraded?: number;
+  failures?: number;
+}
+
+export interface StockImage {
+  key?: string;
+  name?: string;
+  url?: string;
+}
+
+export interface StorageQuota {
+  createdAt?: string;
+  quotaBytes?: number;
+  updatedAt?: string;
+  updatedBy?: string;
+  userId?: string;
+}
+
+export interface Streaks {
+  current?: number;
+  longest?: number;
+}
+
+export interface SubRequest {
+  body?: unknown;
+  headers?: Record<string, string>;
+  id?: string;
+  method: "GET" | "POST" | "PUT" | "PATCH" | "DELETE";
+  path: string;
+}
+
+export interface SubResponse {
+  body?: unknown;
+  headers?: Record<string, string>;
+  id?: string;
+  status?: number;
+}
+
+export interface SubtaskSuggestion {
+  description?: string;
+  title?: string;
+}
+
+export interface SubtaskSuggestions {
+  model?: string;
+  provider?: string;
+  suggestions?: SubtaskSuggestion[];
+  todoId?: string;
+}
+
+export interface SuggestSubtasksPayload {
+  limit?: number | null;
+}
+
+export interface Suggestion {
+  id?: string | null;
+  label?: string;
+  score?: number;
+  type?: string;
+}
+
+export interface Suggestions {
+  data?: Suggestion[];
+}
+
+export interface SyncChange {
+  base?: Record<string, unknown>;
+  baseVersion: number;
+  fields: Record<string, unknown>;
+  todoId: string;
+}
+
+export interface SyncChangeResult {
+  conflicts?: string[];
+  discarded?: string[];
+  status?: string;
+  todo?: Todo | null;
+  todoId?: string;
+}
+
+export interface SyncResponse {
+  changed?: Todo[];
+  results?: SyncChangeResult[];
+  serverTime?: string;
+  strategy?: string;
+}
+
+export interface SyncTodosPayload {
+  changes?: SyncChange[];
+  since?: string | null;
+  strategy?: "server_wins" | "client_wins" | "field_merge" | null;
+}
+
+export interface TagSuggestion {
+  confidence?: number;
+  tag?: string;
+}
+
+export interface Template {
+  createdAt?: string;
+  createdBy?: string;
+  description?: string | null;
+  dueInDays?: number | null;
+  id?: string;
+  name?: string;
+  priority?: string | null;
+  subtasks?: string[];
+  title?: string;
+  updatedAt?: string;
+  workspaceId?: string;
+}
+
+export interface TestDelivery {
+  deliveryId?: string;
+  durationMs?: number;
+  error?: string | null;
+  event?: string;
+  responseBody?: string;
+  statusCode?: number | null;
+  success?: boolean;
+}
+
+export interface TestEndpointPayload {
+  event?: "todo_created" | "todo_updated" | "todo_completed" | "todo_uncompleted" | "todo_deleted" | "comment_added" | "comment_updated" | "comment_deleted" | null;
+}
+
+export interface Todo {
+  _links?: Record<string, Link>;
+  categoryId?: string | null;
+  commentCount?: number;
+  commentsReadAt?: string | null;
+  completedAt?: string | null;
+  completedSubtaskCount?: number;
+  createdAt?: string;
+  description?: string;
+  dueDate?: string | null;
+  id?: string;
+  metadata?: Metadata | null;
+  parentTodoId?: string | null;
+  priority?: string;
+  priorityLevel?: number;
+  sortOrder?: number;
+  status?: string;
+  statusCategory?: string;
+  subtaskCount?: number;
+  title?: string;
+  unreadCommentCount?: number;
+  updatedAt?: string;
+  userId?: string;
+  version?: number;
+  workspaceId?: string | null;
+}
+
+export interface TodoAttachment {
+  _links?: Record<string, Link>;
+  createdAt?: string;
+  downloadKey?: string;
+  fileSize?: number | null;
+  id?: string;
+  mimeType?: string | null;
+  name?: string;
+  processedAt?: string | null;
+  scanSignature?: string | null;
+  scanStatus?: string | null;
+  scannedAt?: string | null;
+  thumbnailKey?: string | null;
+  todoId?: string;
+  transcript?: string | null;
+  transcriptionStatus?: string | null;
+  updatedAt?: string;
+  uploadedBy?: string;
+}
+
+export interface TodoStats {
+  active?: number;
+  archived?: number;
+  completed?: number;
+  draft?: number;
+  overdue?: number;
+  total?: number;
+}
+
+export interface TodoSuggestions {
+  category?: CategorySuggestion | null;
+  priority?: PrioritySuggestion | null;
+  tags?: TagSuggestion[];
+}
+
+export interface TodoVersion {
+  categoryId?: string | null;
+  completedAt?: string | null;
+  createdAt?: string;
+  description?: string | null;
+  dueDate?: string | null;
+  metadata?: Metadata | null;
+  parentTodoId?: string | null;
+  priority?: string;
+  status?: string;
+  title?: string;
+  todoId?: string;
+  version?: number;
+}
+
+export interface Totals {
+  apiCalls?: number;
+  notifications?: number;
+  storageBytes?: number;
+}
+
+export interface TriagePayload {
+  categoryId?: string | null;
+  description?: string | null;
+  dueDate?: string | null;
+  priority?: "low" | "medium" | "high" | null;
+  title?: string | null;
+}
+
+export interface UpdateAPIClientPayload {
+  allowedOrigins?: string[];
+  enabled?: boolean | null;
+  name?: string | null;
+}
+
+export interface UpdateCategoryPayload {
+  color?: string | null;
+  description?: string | null;
+  name?: string | null;
+}
+
+export interface UpdateCommentPayload {
+  content: string;
+}
+
+export interface UpdateEndpointPayload {
+  enabled?: boolean | null;
+  events?: string[];
+  url?: string | null;
+}
+
+export interface UpdateIncidentPayload {
+  components?: string[];
+  impact?: "minor" | "major" | null;
+  message?: string | null;
+  status?: "investigating" | "identified" | "monitoring" | "resolved" | null;
+  title?: string | null;
+}
+
+export interface UpdateMagicTagPayload {
+  categoryId?: string | null;
+  dueInDays?: number | null;
+  priority?: "low" | "medium" | "high" | null;
+  tag: string;
+  timezone?: string | null;
+}
+
+export interface UpdatePreferencePayload {
+  locale: "en" | "es" | "fr";
+}
+
+export interface UpdateRulePayload {
+  actions?: RuleAction[];
+  conditions?: Conditions | null;
+  enabled?: boolean | null;
+  name?: string | null;
+  trigger?: "todo_created" | "todo_completed" | "todo_overdue" | "todo_tagged" | null;
+}
+
+export interface UpdateSchedulePayload {
+  dayOfMonth?: number | null;
+  filters?: Filters;
+  format: "pdf" | "csv";
+  frequency: "daily" | "weekly" | "monthly";
+  hour?: number;
+  name: string;
+  recipients: string[];
+  timezone?: string;
+  weekday?: number | null;
+}
+
+export interface UpdateSettingsPayload {
+  importantPriority?: "low" | "medium" | "high" | null;
+  urgentWithinHours?: number | null;
+}
+
+export interface UpdateTodoPayload {
+  categoryId?: string | null;
+  description?: string | null;
+  dueDate?: string | null;
+  metadata?: Metadata | null;
+  parentTodoId?: string | null;
+  priority?: string | null;
+  status?: string | null;
+  title?: string | null;
+  version?: number | null;
+}
+
+export interface Usage {
+  buckets?: Bucket[];
+  from?: string;
+  granularity?: string;
+  subjectId?: string;
+  subjectType?: string;
+  to?: string;
+  totals?: Totals;
+}
+
+export interface User {
+  banned?: boolean;
+  createdAt?: string | null;
+  email?: string | null;
+  firstName?: string | null;
+  id?: string;
+  lastName?: string | null;
+  lastSignInAt?: string | null;
+  locked?: boolean;
+  role?: string;
+  stats?: UserStats;
+}
+
+export interface UserStats {
+  attachmentBytes?: number;
+  attachments?: number;
+  categories?: number;
+  comments?: number;
+  completedTodos?: number;
+  lastActivityAt?: string | null;
+  todos?: number;
+}
+
+export interface UserStorage {
+  defaultQuotaBytes?: number;
+  override?: StorageQuota | null;
+  quotaBytes?: number;
+  usedBytes?: number;
+  userId?: string;
+}
+
+export interface WeekLoad {
+  estimatedMinutes?: number;
+  openTodos?: number;
+  overCapacity?: boolean;
+  unestimatedCount?: number;
+  week?: string;
+}
+
+export interface Workflow {
+  createdAt?: string;
+  priorities?: WorkflowPriority[];
+  statuses?: WorkflowStatus[];
+  updatedAt?: string;
+  updatedBy?: string;
+  workspaceId?: string;
+}
+
+export interface WorkflowPriority {
+  color: string;
+  key: string;
+  name: string;
+}
+
+export interface WorkflowStatus {
+  category: "draft" | "active" | "completed" | "archived";
+  color: string;
+  key: string;
+  name: string;
+}
+
+export interface Workload {
+  capacityMinutes?: number;
+  members?: MemberWorkload[];
+  timezone?: string;
+  weeks?: string[];
+  workspaceId?: string;
+}
+
+export interface Workspace {
+  freeze?: Freeze | null;
+  id?: string;
+  stats?: WorkspaceStats;
+}
+
+export interface WorkspaceStats {
+  agingPolicies?: number;
+  attachmentBytes?: number;
+  attachments?: number;
+  completedTodos?: number;
+  lastActivityAt?: string | null;
+  members?: number;
+  pendingInvites?: number;
+  templates?: number;
+  todos?: number;
+  webhooks?: number;
+}
+
+export interface AdminGetUserAPIKeysQuery {
+  userId: string;
+}
+
+export interface AdminGetAuditLogQuery {
+  actorId?: string;
+  action?: string;
+  entityType?: string;
+  entityId?: string;
+  from?: string;
+  to?: string;
+  page?: number;
+  limit?: number;
+}
+
+export interface AdminDeleteFeatureFlagOverrideQuery {
+  scope: "user" | "workspace" | "global";
+  subjectId?: string;
+}
+
+export interface AdminGetJobsQuery {
+  queue?: "critical" | "default" | "low";
+  state?: "retry" | "archived";
+  page?: number;
+  limit?: number;
+}
+
+export interface AdminPreviewRetentionPolicyQuery {
+  workspaceId?: string;
+}
+
+export interface AdminGetIncidentsQuery {
+  days?: number;
+}
+
+export interface GetCategoriesQuery {
+  page?: number;
+  limit?: number;
+  sort?: "created_at" | "updated_at" | "name";
+  order?: "asc" | "desc";
+  search?: string;
+}
+
+export interface ExportCategoryBundleQuery {
+  ids?: string;
+  name?: string;
+  description?: string;
+}
+
+export interface GetChangesQuery {
+  cursor?: string;
+  limit?: number;
+}
+
+export interface GetFocusSessionsQuery {
+  todoId?: string;
+  limit?: number;
+}
+
+export interface GetInboxItemsQuery {
+  limit?: number;
+}
+
+export interface GetMyDayQuery {
+  tz?: string;
+}
+
+export interface GetDaySummariesQuery {
+  days?: number;
+  tz?: string;
+}
+
+export interface RemoveMyDayTodoQuery {
+  tz?: string;
+}
+
+export interface GetPlannerQuery {
+  week?: string;
+  tz?: string;
+  capacity?: number;
+}
+
+export interface GetForecastQuery {
+  tz?: string;
+  capacity?: number;
+  priority?: string;
+  categoryId?: string;
+  parentTodoId?: string;
+  tag?: string;
+}
+
+export interface GetRuleExecutionsQuery {
+  limit?: number;
+}
+
+export interface SearchTodosQuery {
+  q: string;
+  mode?: "keyword" | "semantic";
+  limit?: number;
+}
+
+export interface GetStatsOverviewQuery {
+  days?: number;
+}
+
+export interface GetStatsProductivityQuery {
+  days?: "7" | "30" | "90" | "365";
+}
+
+export interface SuggestQuery {
+  q: string;
+  limit?: number;
+}
+
+export interface GetTodosQuery {
+  page?: number;
+  limit?: number;
+  sort?: "created_at" | "updated_at" | "title" | "priority" | "status" | "due_date" | "relevance";
+  order?: "asc" | "desc";
+  search?: string;
+  status?: string;
+  priority?: string;
+  categoryId?: string;
+  parentTodoId?: string;
+  dueFrom?: string;
+  dueTo?: string;
+  overdue?: boolean;
+  completed?: boolean;
+  unread?: boolean;
+  fields?: string;
+  expand?: string;
+}
+
+export interface GetTodoByIdQuery {
+  fields?: string;
+  expand?: string;
+}
+
+export interface GetUsageQuery {
+  scope?: "user" | "workspace";
+  granularity?: "hour" | "day";
+  from?: string;
+  to?: string;
+}
+
+export interface GetWorkspaceWorkloadQuery {
+  weeks?: number;
+  tz?: string;
+  capacity?: number;
+}
+
+export class ExecuTaskClient extends BaseClient {
+  /** List the API clients allowed to call the API from browsers */
+  adminGetAPIClients(): Promise<APIClient[]> {
+    return this.request<APIClient[]>("GET", `/admin/v1/api-clients`);
+  }
+
+  /** Register an API client and the origins it calls the API from */
+  adminCreateAPIClient(body: CreateAPIClientPayload): Promise<APIClient> {
+    return this.request<APIClient>("POST", `/admin/v1/api-clients`, { body });
+  }
+
+  /** Change the origins of an API client or disable it */
+  adminUpdateAPIClient(id: string, body: UpdateAPIClientPayload): Promise<APIClient> {
+    return this.request<APIClient>("PATCH", `/admin/v1/api-clients/${encodeURIComponent(id)}`, { body });
+  }
+
+  /** Remove an API client, its origins are no longer allowed */
+  adminDeleteAPIClient(id: string): Promise<void> {
+    return this.request<void>("DELETE", `/admin/v1/api-clients/${encodeURIComponent(id)}`);
+  }
+
+  /** List the API keys of a user */
+  adminGetUserAPIKeys(query: AdminGetUserAPIKeysQuery): Promise<Key[]> {
+    return this.request<Key[]>("GET", `/admin/v1/api-keys`, { query });
+  }
+
+  /** Move an API key to another plan */
+  adminSetAPIKeyPlan(id: string, body: SetKeyPlanPayload): Promise<Key> {
+    return this.request<Key>("PUT", `/admin/v1/api-keys/${encodeURIComponent(id)}/plan`, { body });
+  }
+
+  /** List audit log entries */
+  adminGetAuditLog(query: AdminGetAuditLogQuery = {}): Promise<PaginatedResponseEntry> {
+    return this.request<PaginatedResponseEntry>("GET", `/admin/v1/audit-log`, { query });
+  }
+
+  /** Iterates over every page of adminGetAuditLog, starting at query.page */
+  adminGetAuditLogIter(query: AdminGetAuditLogQuery = {}): AsyncGenerator<Entry> {
+    return this.paginate(query.page, (page) => this.adminGetAuditLog({ ...query, page }));
+  }
+
+  /** Get the progress of a workspace backup */
+  adminGetWorkspaceBackup(id: string): Promise<Backup> {
+    return this.request<Backup>("GET", `/admin/v1/backups/${encodeURIComponent(id)}`);
+  }
+
+  /** Restore a backup into an empty workspace */
+  adminRestoreWorkspaceBackup(id: string, body: RestoreBackupPayload): Promise<Restore> {
+    return this.request<Restore>("POST", `/admin/v1/backups/${encodeURIComponent(id)}/restores`, { body });
+  }
+
+  /** Reload the runtime-safe config values of this instance */
+  adminReloadConfig(): Promise<ConfigReload> {
+    return this.request<ConfigReload>("POST", `/admin/v1/config/reload`);
+  }
+
+  /** Get runtime, GC and connection pool statistics */
+  adminGetRuntimeStats(): Promise<RuntimeStats> {
+    return this.request<RuntimeStats>("GET", `/admin/v1/debug/runtime`);
+  }
+
+  /** Get the active fault injection rules */
+  adminGetFaultInjection(): Promise<FaultInjection> {
+    return this.request<FaultInjection>("GET", `/admin/v1/faults`);
+  }
+
+  /** Inject latency, errors or dropped responses into API routes */
+  adminSetFaultInjection(body: SetFaultInjectionPayload): Promise<FaultInjection> {
+    return this.request<FaultInjection>("PUT", `/admin/v1/faults`, { body });
+  }
+
+  /** Stop every injected fault */
+  adminClearFaultInjection(): Promise<void> {
+    return this.request<void>("DELETE", `/admin/v1/faults`);
+  }
+
+  /** List feature flags and their overrides */
+  adminGetFeatureFlags(): Promise<Flag[]> {
+    return this.request<Flag[]>("GET", `/admin/v1/feature-flags`);
+  }
+
+  /** Force a feature flag on or off */
+  adminSetFeatureFlagOverride(flag: string, body: SetOverridePayload): Promise<Override> {
+    return this.request<Override>("PUT", `/admin/v1/feature-flags/${encodeURIComponent(flag)}/overrides`, { body });
+  }
+
+  /** Remove a feature flag override */
+  adminDeleteFeatureFlagOverride(flag: string, query: AdminDeleteFeatureFlagOverrideQuery): Promise<void> {
+    return this.request<void>("DELETE", `/admin/v1/feature-flags/${encodeURIComponent(flag)}/overrides`, { query });
+  }
+
+  /** List failed background jobs */
+  adminGetJobs(query: AdminGetJobsQuery = {}): Promise<PaginatedResponseJob> {
+    return this.request<PaginatedResponseJob>("GET", `/admin/v1/jobs`, { query });
+  }
+
+  /** Iterates over every page of adminGetJobs, starting at query.page */
+  adminGetJobsIter(query: AdminGetJobsQuery = {}): AsyncGenerator<Job> {
+    return this.paginate(query.page, (page) => this.adminGetJobs({ ...query, page }));
+  }
+
+  /** Retry a failed background job */
+  adminRetryJob(queue: string, id: string): Promise<void> {
+    return this.request<void>("POST", `/admin/v1/jobs/${encodeURIComponent(queue)}/${encodeURIComponent(id)}/retry`);
+  }
+
+  /** Get the progress of a workspace restore */
+  adminGetWorkspaceRestore(id: string): Promise<Restore> {
+    return this.request<Restore>("GET", `/admin/v1/restores/${encodeURIComponent(id)}`);
+  }
+
+  /** List the default and per workspace retention policies */
+  adminGetRetentionPolicies(): Promise<Overview> {
+    return this.request<Overview>("GET", `/admin/v1/retention-policies`);
+  }
+
+  /** Report what a retention policy would delete */
+  adminPreviewRetentionPolicy(query: AdminPreviewRetentionPolicyQuery = {}): Promise<Report> {
+    return this.request<Report>("GET", `/admin/v1/retention-policies/preview`, { query });
+  }
+
+  /** Set the retention policy of a workspace */
+  adminSetRetentionPolicy(workspaceId: string, body: SetPolicyPayload): Promise<Policy> {
+    return this.request<Policy>("PUT", `/admin/v1/retention-policies/${encodeURIComponent(workspaceId)}`, { body });
+  }
+
+  /** Remove the retention policy of a workspace */
+  adminDeleteRetentionPolicy(workspaceId: string): Promise<void> {
+    return this.request<void>("DELETE", `/admin/v1/retention-policies/${encodeURIComponent(workspaceId)}`);
+  }
+
+  /** Get the maintenance and read-only mode */
+  adminGetServerMode(): Promise<ServerMode> {
+    return this.request<ServerMode>("GET", `/admin/v1/server-mode`);
+  }
+
+  /** Switch every instance to maintenance, read-only or normal mode */
+  adminSetServerMode(body: SetServerModePayload): Promise<ServerMode> {
+    return this.request<ServerMode>("PUT", `/admin/v1/server-mode`, { body });
+  }
+
+  /** List the incidents of the status page */
+  adminGetIncidents(query: AdminGetIncidentsQuery = {}): Promise<Incident[]> {
+    return this.request<Incident[]>("GET", `/admin/v1/status/incidents`, { query });
+  }
+
+  /** Announce an incident on the status page */
+  adminCreateIncident(body: CreateIncidentPayload): Promise<Incident> {
+    return this.request<Incident>("POST", `/admin/v1/status/incidents`, { body });
+  }
+
+  /** Follow up an incident of the status page */
+  adminUpdateIncident(id: string, body: UpdateIncidentPayload): Promise<Incident> {
+    return this.request<Incident>("PATCH", `/admin/v1/status/incidents/${encodeURIComponent(id)}`, { body });
+  }
+
+  /** Take an incident off the status page */
+  adminDeleteIncident(id: string): Promise<void> {
+    return this.request<void>("DELETE", `/admin/v1/status/incidents/${encodeURIComponent(id)}`);
+  }
+
+  /** Look up a user */
+  adminGetUser(id: string): Promise<User> {
+    return this.request<User>("GET", `/admin/v1/users/${encodeURIComponent(id)}`);
+  }
+
+  /** Get the attachment storage use and quota of a user */
+  adminGetStorageQuota(id: string): Promise<UserStorage> {
+    return this.request<UserStorage>("GET", `/admin/v1/users/${encodeURIComponent(id)}/storage-quota`);
+  }
+
+  /** Override the attachment storage quota of a user */
+  adminSetStorageQuota(id: string, body: SetStorageQuotaPayload): Promise<UserStorage> {
+    return this.request<UserStorage>("PUT", `/admin/v1/users/${encodeURIComponent(id)}/storage-quota`, { body });
+  }
+
+  /** Remove the storage quota override of a user */
+  adminDeleteStorageQuota(id: string): Promise<void> {
+    return this.request<void>("DELETE", `/admin/v1/users/${encodeURIComponent(id)}/storage-quota`);
+  }
+
+  /** Inspect a workspace */
+  adminGetWorkspace(workspaceId: string): Promise<Workspace> {
+    return this.request<Workspace>("GET", `/admin/v1/workspaces/${encodeURIComponent(workspaceId)}`);
+  }
+
+  /** List the backups of a workspace */
+  adminGetWorkspaceBackups(workspaceId: string): Promise<Backup[]> {
+    return this.request<Backup[]>("GET", `/admin/v1/workspaces/${encodeURIComponent(workspaceId)}/backups`);
+  }
+
+  /** Back up a workspace to S3 */
+  adminCreateWorkspaceBackup(workspaceId: string): Promise<Backup> {
+    return this.request<Backup>("POST", `/admin/v1/workspaces/${encodeURIComponent(workspaceId)}/backups`);
+  }
+
+  /** List the API keys of the user */
+  getAPIKeys(): Promise<Key[]> {
+    return this.request<Key[]>("GET", `/api/v1/api-keys`);
+  }
+
+  /** Create an API key, its secret is only returned once */
+  createAPIKey(body: CreateKeyPayload): Promise<CreatedKey> {
+    return this.request<CreatedKey>("POST", `/api/v1/api-keys`, { body });
+  }
+
+  /** Revoke an API key */
+  deleteAPIKey(id: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/api-keys/${encodeURIComponent(id)}`);
+  }
+
+  /** Get the CSRF token to send with the unsafe requests of a cookie session */
+  getCSRFToken(): Promise<CSRFToken> {
+    return this.request<CSRFToken>("GET", `/api/v1/auth/csrf`);
+  }
+
+  /** Move the session token into an HTTP-only cookie */
+  createCookieSession(): Promise<CookieSession> {
+    return this.request<CookieSession>("POST", `/api/v1/auth/session`);
+  }
+
+  /** Clear the cookies of the cookie session */
+  deleteCookieSession(): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/auth/session`);
+  }
+
+  /** Execute several requests at once */
+  executeBatch(body: BatchPayload): Promise<BatchResponse> {
+    return this.request<BatchResponse>("POST", `/api/v1/batch`, { body });
+  }
+
+  /** List categories */
+  getCategories(query: GetCategoriesQuery = {}): Promise<PaginatedResponseCategory> {
+    return this.request<PaginatedResponseCategory>("GET", `/api/v1/categories`, { query });
+  }
+
+  /** Iterates over every page of getCategories, starting at query.page */
+  getCategoriesIter(query: GetCategoriesQuery = {}): AsyncGenerator<Category> {
+    return this.paginate(query.page, (page) => this.getCategories({ ...query, page }));
+  }
+
+  /** Create a category */
+  createCategory(body: CreateCategoryPayload): Promise<Category> {
+    return this.request<Category>("POST", `/api/v1/categories`, { body });
+  }
+
+  /** Export categories as a shareable bundle */
+  exportCategoryBundle(query: ExportCategoryBundleQuery = {}): Promise<Bundle> {
+    return this.request<Bundle>("GET", `/api/v1/categories/export`, { query });
+  }
+
+  /** Import a category bundle */
+  importCategoryBundle(body: ImportBundlePayload): Promise<ImportResult> {
+    return this.request<ImportResult>("POST", `/api/v1/categories/import`, { body });
+  }
+
+  /** Update a category */
+  updateCategory(id: string, body: UpdateCategoryPayload): Promise<Category> {
+    return this.request<Category>("PATCH", `/api/v1/categories/${encodeURIComponent(id)}`, { body });
+  }
+
+  /** Delete a category */
+  deleteCategory(id: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/categories/${encodeURIComponent(id)}`);
+  }
+
+  /** List changes after a cursor */
+  getChanges(query: GetChangesQuery = {}): Promise<ChangesResponse> {
+    return this.request<ChangesResponse>("GET", `/api/v1/changes`, { query });
+  }
+
+  /** Follows the cursor of getChanges until the feed is caught up, starting at query.cursor */
+  getChangesIter(query: GetChangesQuery = {}): AsyncGenerator<Change> {
+    return this.follow(query.cursor, async (cursor) => {
+      const res = await this.getChanges({ ...query, cursor });
+      return { items: res.changes ?? [], nextCursor: res.nextCursor, hasMore: res.hasMore };
+    });
+  }
+
+  /** Update a comment */
+  updateComment(id: string, body: UpdateCommentPayload): Promise<Comment> {
+    return this.request<Comment>("PATCH", `/api/v1/comments/${encodeURIComponent(id)}`, { body });
+  }
+
+  /** Delete a comment */
+  deleteComment(id: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/comments/${encodeURIComponent(id)}`);
+  }
+
+  /** Request an export of all account data */
+  requestAccountExport(): Promise<AccountExport> {
+    return this.request<AccountExport>("POST", `/api/v1/export/account`);
+  }
+
+  /** Get an account export and its download link */
+  getAccountExport(id: string): Promise<AccountExport> {
+    return this.request<AccountExport>("GET", `/api/v1/export/account/${encodeURIComponent(id)}`);
+  }
+
+  /** Request a PDF of the todos matching the filters */
+  requestTodoListExport(body: RequestTodoListExportPayload): Promise<AccountExport> {
+    return this.request<AccountExport>("POST", `/api/v1/export/todos`, { body });
+  }
+
+  /** Get a todo list PDF export and its download link */
+  getTodoListExport(id: string): Promise<AccountExport> {
+    return this.request<AccountExport>("GET", `/api/v1/export/todos/${encodeURIComponent(id)}`);
+  }
+
+  /** List the latest focus sessions */
+  getFocusSessions(query: GetFocusSessionsQuery = {}): Promise<Session[]> {
+    return this.request<Session[]>("GET", `/api/v1/focus/sessions`, { query });
+  }
+
+  /** Start a focus session on a todo */
+  startFocusSession(body: StartSessionPayload): Promise<Session> {
+    return this.request<Session>("POST", `/api/v1/focus/sessions`, { body });
+  }
+
+  /** Get the open focus session */
+  getCurrentFocusSession(): Promise<Session> {
+    return this.request<Session>("GET", `/api/v1/focus/sessions/current`);
+  }
+
+  /** Complete a focus session */
+  completeFocusSession(id: string): Promise<Session> {
+    return this.request<Session>("POST", `/api/v1/focus/sessions/${encodeURIComponent(id)}/complete`);
+  }
+
+  /** Pause the active focus session */
+  pauseFocusSession(id: string): Promise<Session> {
+    return this.request<Session>("POST", `/api/v1/focus/sessions/${encodeURIComponent(id)}/pause`);
+  }
+
+  /** Resume a paused focus session */
+  resumeFocusSession(id: string): Promise<Session> {
+    return this.request<Session>("POST", `/api/v1/focus/sessions/${encodeURIComponent(id)}/resume`);
+  }
+
+  /** Get streaks, badges and weekly goal progress */
+  getGamification(): Promise<GamificationOverview> {
+    return this.request<GamificationOverview>("GET", `/api/v1/gamification`);
+  }
+
+  /** Set the weekly completion goal */
+  setWeeklyGoal(body: SetGoalPayload): Promise<GoalProgress> {
+    return this.request<GoalProgress>("PUT", `/api/v1/gamification/goal`, { body });
+  }
+
+  /** Remove the weekly completion goal */
+  deleteWeeklyGoal(): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/gamification/goal`);
+  }
+
+  /** Receive a reply to a notification email, for the inbound email provider */
+  receiveEmailReply(body: EmailReplyPayload): Promise<void> {
+    return this.request<void>("POST", `/api/v1/inbound/email-replies`, { body });
+  }
+
+  /** List the inbox, oldest first */
+  getInboxItems(query: GetInboxItemsQuery = {}): Promise<Item[]> {
+    return this.request<Item[]>("GET", `/api/v1/inbox`, { query });
+  }
+
+  /** Capture a title into the inbox */
+  captureInboxItem(body: CapturePayload): Promise<Item> {
+    return this.request<Item>("POST", `/api/v1/inbox`, { body });
+  }
+
+  /** Discard an inbox item */
+  discardInboxItem(id: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/inbox/${encodeURIComponent(id)}`);
+  }
+
+  /** Promote an inbox item into a todo */
+  triageInboxItem(id: string, body: TriagePayload): Promise<Todo> {
+    return this.request<Todo>("POST", `/api/v1/inbox/${encodeURIComponent(id)}/triage`, { body });
+  }
+
+  /** Join a workspace through an invite link */
+  acceptWorkspaceInvite(token: string): Promise<InviteAcceptance> {
+    return this.request<InviteAcceptance>("POST", `/api/v1/invites/${encodeURIComponent(token)}/accept`);
+  }
+
+  /** Get the locale the user chose */
+  getLocale(): Promise<Preference> {
+    return this.request<Preference>("GET", `/api/v1/locale`);
+  }
+
+  /** Choose the locale of responses and emails */
+  updateLocale(body: UpdatePreferencePayload): Promise<Preference> {
+    return this.request<Preference>("PUT", `/api/v1/locale`, { body });
+  }
+
+  /** Follow the Accept-Language of requests again */
+  resetLocale(): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/locale`);
+  }
+
+  /** List the magic tags applied to todo titles */
+  getMagicTags(): Promise<MagicTag[]> {
+    return this.request<MagicTag[]>("GET", `/api/v1/magic-tags`);
+  }
+
+  /** Create a magic tag such as #tomorrow or #p1 */
+  createMagicTag(body: CreateMagicTagPayload): Promise<MagicTag> {
+    return this.request<MagicTag>("POST", `/api/v1/magic-tags`, { body });
+  }
+
+  /** Replace a magic tag */
+  updateMagicTag(id: string, body: UpdateMagicTagPayload): Promise<MagicTag> {
+    return this.request<MagicTag>("PUT", `/api/v1/magic-tags/${encodeURIComponent(id)}`, { body });
+  }
+
+  /** Delete a magic tag */
+  deleteMagicTag(id: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/magic-tags/${encodeURIComponent(id)}`);
+  }
+
+  /** Get the open todos bucketed into urgent and important quadrants */
+  getMatrix(): Promise<Matrix> {
+    return this.request<Matrix>("GET", `/api/v1/matrix`);
+  }
+
+  /** Get the thresholds of the matrix */
+  getMatrixSettings(): Promise<Settings> {
+    return this.request<Settings>("GET", `/api/v1/matrix/settings`);
+  }
+
+  /** Update the thresholds of the matrix */
+  updateMatrixSettings(body: UpdateSettingsPayload): Promise<Settings> {
+    return this.request<Settings>("PATCH", `/api/v1/matrix/settings`, { body });
+  }
+
+  /** Summarize the API usage of the caller over the last 30 days */
+  getApiUsage(): Promise<APIUsage> {
+    return this.request<APIUsage>("GET", `/api/v1/me/api-usage`);
+  }
+
+  /** Get the todos picked for today and those left open to review */
+  getMyDay(query: GetMyDayQuery = {}): Promise<MyDay> {
+    return this.request<MyDay>("GET", `/api/v1/my-day`, { query });
+  }
+
+  /** Get how much of the todos picked for each day got done */
+  getDaySummaries(query: GetDaySummariesQuery = {}): Promise<DaySummary[]> {
+    return this.request<DaySummary[]>("GET", `/api/v1/my-day/summaries`, { query });
+  }
+
+  /** Pick todos for today without changing their due dates */
+  addMyDayTodos(body: AddMyDayTodosPayload): Promise<MyDay> {
+    return this.request<MyDay>("POST", `/api/v1/my-day/todos`, { body });
+  }
+
+  /** Take a todo off today or drop it from review */
+  removeMyDayTodo(todoId: string, query: RemoveMyDayTodoQuery = {}): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/my-day/todos/${encodeURIComponent(todoId)}`, { query });
+  }
+
+  /** Get the todos of a week bucketed by day */
+  getPlanner(query: GetPlannerQuery = {}): Promise<Planner> {
+    return this.request<Planner>("GET", `/api/v1/planner`, { query });
+  }
+
+  /** Predict when open todos get done going by past estimates */
+  getForecast(query: GetForecastQuery = {}): Promise<Forecast> {
+    return this.request<Forecast>("GET", `/api/v1/planner/forecast`, { query });
+  }
+
+  /** Assign due dates to todos in bulk */
+  scheduleTodos(body: SchedulePayload): Promise<Todo[]> {
+    return this.request<Todo[]>("POST", `/api/v1/planner/schedule`, { body });
+  }
+
+  /** List the scheduled reports */
+  getReportSchedules(): Promise<Schedule[]> {
+    return this.request<Schedule[]>("GET", `/api/v1/reports/schedules`);
+  }
+
+  /** Schedule a report emailed to its recipients */
+  createReportSchedule(body: CreateSchedulePayload): Promise<Schedule> {
+    return this.request<Schedule>("POST", `/api/v1/reports/schedules`, { body });
+  }
+
+  /** Get a scheduled report */
+  getReportSchedule(id: string): Promise<Schedule> {
+    return this.request<Schedule>("GET", `/api/v1/reports/schedules/${encodeURIComponent(id)}`);
+  }
+
+  /** Replace a scheduled report */
+  updateReportSchedule(id: string, body: UpdateSchedulePayload): Promise<Schedule> {
+    return this.request<Schedule>("PUT", `/api/v1/reports/schedules/${encodeURIComponent(id)}`, { body });
+  }
+
+  /** Delete a scheduled report */
+  deleteReportSchedule(id: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/reports/schedules/${encodeURIComponent(id)}`);
+  }
+
+  /** Stop sending a scheduled report */
+  pauseReportSchedule(id: string): Promise<Schedule> {
+    return this.request<Schedule>("POST", `/api/v1/reports/schedules/${encodeURIComponent(id)}/pause`);
+  }
+
+  /** Send a paused report again from its next time due */
+  resumeReportSchedule(id: string): Promise<Schedule> {
+    return this.request<Schedule>("POST", `/api/v1/reports/schedules/${encodeURIComponent(id)}/resume`);
+  }
+
+  /** List automation rules */
+  getRules(): Promise<Rule[]> {
+    return this.request<Rule[]>("GET", `/api/v1/rules`);
+  }
+
+  /** Create an automation rule */
+  createRule(body: CreateRulePayload): Promise<Rule> {
+    return this.request<Rule>("POST", `/api/v1/rules`, { body });
+  }
+
+  /** Get an automation rule */
+  getRule(id: string): Promise<Rule> {
+    return this.request<Rule>("GET", `/api/v1/rules/${encodeURIComponent(id)}`);
+  }
+
+  /** Update an automation rule */
+  updateRule(id: string, body: UpdateRulePayload): Promise<Rule> {
+    return this.request<Rule>("PATCH", `/api/v1/rules/${encodeURIComponent(id)}`, { body });
+  }
+
+  /** Delete an automation rule */
+  deleteRule(id: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/rules/${encodeURIComponent(id)}`);
+  }
+
+  /** List the latest executions of an automation rule */
+  getRuleExecutions(id: string, query: GetRuleExecutionsQuery = {}): Promise<Execution[]> {
+    return this.request<Execution[]>("GET", `/api/v1/rules/${encodeURIComponent(id)}/executions`, { query });
+  }
+
+  /** Search todos by keyword or meaning */
+  searchTodos(query: SearchTodosQuery): Promise<Results> {
+    return this.request<Results>("GET", `/api/v1/search`, { query });
+  }
+
+  /** Get dashboard stats */
+  getStatsOverview(query: GetStatsOverviewQuery = {}): Promise<StatsOverview> {
+    return this.request<StatsOverview>("GET", `/api/v1/stats/overview`, { query });
+  }
+
+  /** Get productivity analytics over a window */
+  getStatsProductivity(query: GetStatsProductivityQuery = {}): Promise<Productivity> {
+    return this.request<Productivity>("GET", `/api/v1/stats/productivity`, { query });
+  }
+
+  /** Suggest todos, categories and tags as the user types */
+  suggest(query: SuggestQuery): Promise<Suggestions> {
+    return this.request<Suggestions>("GET", `/api/v1/suggest`, { query });
+  }
+
+  /** Push offline changes and pull updates */
+  syncTodos(body: SyncTodosPayload): Promise<SyncResponse> {
+    return this.request<SyncResponse>("POST", `/api/v1/sync`, { body });
+  }
+
+  /** List the templates of the active workspace */
+  getWorkspaceTemplates(): Promise<Template[]> {
+    return this.request<Template[]>("GET", `/api/v1/templates`);
+  }
+
+  /** Create a todo and its subtasks from a workspace template */
+  applyWorkspaceTemplate(id: string, body: ApplyTemplatePayload): Promise<Todo> {
+    return this.request<Todo>("POST", `/api/v1/templates/${encodeURIComponent(id)}/apply`, { body });
+  }
+
+  /** List todos */
+  getTodos(query: GetTodosQuery = {}): Promise<PaginatedResponsePopulatedTodo> {
+    return this.request<PaginatedResponsePopulatedTodo>("GET", `/api/v1/todos`, { query });
+  }
+
+  /** Iterates over every page of getTodos, starting at query.page */
+  getTodosIter(query: GetTodosQuery = {}): AsyncGenerator<PopulatedTodo> {
+    return this.paginate(query.page, (page) => this.getTodos({ ...query, page }));
+  }
+
+  /** Create a todo */
+  createTodo(body: CreateTodoPayload): Promise<CreatedTodo> {
+    return this.request<CreatedTodo>("POST", `/api/v1/todos`, { body });
+  }
+
+  /** Complete several todos at once */
+  completeTodos(body: CompleteTodosPayload): Promise<BulkCompletion> {
+    return this.request<BulkCompletion>("POST", `/api/v1/todos/complete`, { body });
+  }
+
+  /** List the stock images todo covers can be picked from */
+  getStockCoverImages(): Promise<StockImage[]> {
+    return this.request<StockImage[]>("GET", `/api/v1/todos/covers/stock`);
+  }
+
+  /** Get todo statistics */
+  getTodoStats(): Promise<TodoStats> {
+    return this.request<TodoStats>("GET", `/api/v1/todos/stats`);
+  }
+
+  /** Get a todo */
+  getTodoById(id: string, query: GetTodoByIdQuery = {}): Promise<PopulatedTodo> {
+    return this.request<PopulatedTodo>("GET", `/api/v1/todos/${encodeURIComponent(id)}`, { query });
+  }
+
+  /** Update a todo */
+  updateTodo(id: string, body: UpdateTodoPayload): Promise<Todo> {
+    return this.request<Todo>("PATCH", `/api/v1/todos/${encodeURIComponent(id)}`, { body });
+  }
+
+  /** Delete a todo */
+  deleteTodo(id: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/todos/${encodeURIComponent(id)}`);
+  }
+
+  /** Upload a todo attachment */
+  uploadTodoAttachment(id: string, file: Blob, filename?: string): Promise<TodoAttachment> {
+    return this.request<TodoAttachment>("POST", `/api/v1/todos/${encodeURIComponent(id)}/attachments`, { upload: { field: "file", file, filename } });
+  }
+
+  /** Delete a todo attachment */
+  deleteTodoAttachment(id: string, attachmentId: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/todos/${encodeURIComponent(id)}/attachments/${encodeURIComponent(attachmentId)}`);
+  }
+
+  /** Get an attachment download URL */
+  getAttachmentPresignedUrl(id: string, attachmentId: string): Promise<{
+    url?: string;
+  }> {
+    return this.request<{
+    url?: string;
+  }>("GET", `/api/v1/todos/${encodeURIComponent(id)}/attachments/${encodeURIComponent(attachmentId)}/download`);
+  }
+
+  /** Get an image attachment thumbnail URL */
+  getAttachmentThumbnailUrl(id: string, attachmentId: string): Promise<{
+    url?: string;
+  }> {
+    return this.request<{
+    url?: string;
+  }>("GET", `/api/v1/todos/${encodeURIComponent(id)}/attachments/${encodeURIComponent(attachmentId)}/thumbnail`);
+  }
+
+  /** List comments of a todo */
+  getCommentsByTodoId(id: string): Promise<Comment[]> {
+    return this.request<Comment[]>("GET", `/api/v1/todos/${encodeURIComponent(id)}/comments`);
+  }
+
+  /** Comment on a todo */
+  addComment(id: string, body: AddCommentPayload): Promise<Comment> {
+    return this.request<Comment>("POST", `/api/v1/todos/${encodeURIComponent(id)}/comments`, { body });
+  }
+
+  /** Mark the comments of a todo as read */
+  markCommentsRead(id: string): Promise<Todo> {
+    return this.request<Todo>("POST", `/api/v1/todos/${encodeURIComponent(id)}/comments/read`);
+  }
+
+  /** Get the cover of a todo with links to its thumbnails */
+  getTodoCover(id: string): Promise<Cover> {
+    return this.request<Cover>("GET", `/api/v1/todos/${encodeURIComponent(id)}/cover`);
+  }
+
+  /** Set the cover of a todo from an image attachment or a stock image */
+  setTodoCover(id: string, body: SetCoverPayload): Promise<Cover> {
+    return this.request<Cover>("PUT", `/api/v1/todos/${encodeURIComponent(id)}/cover`, { body });
+  }
+
+  /** Remove the cover of a todo */
+  deleteTodoCover(id: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/todos/${encodeURIComponent(id)}/cover`);
+  }
+
+  /** List the todos a todo waits on and the ones waiting on it */
+  getTodoDependencies(id: string): Promise<Dependencies> {
+    return this.request<Dependencies>("GET", `/api/v1/todos/${encodeURIComponent(id)}/dependencies`);
+  }
+
+  /** Make a todo wait on another */
+  addTodoDependency(id: string, body: AddDependencyPayload): Promise<Dependency> {
+    return this.request<Dependency>("POST", `/api/v1/todos/${encodeURIComponent(id)}/dependencies`, { body });
+  }
+
+  /** Stop a todo waiting on another */
+  removeTodoDependency(id: string, dependsOnId: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/todos/${encodeURIComponent(id)}/dependencies/${encodeURIComponent(dependsOnId)}`);
+  }
+
+  /** Move a due date along with the todos waiting on it */
+  shiftDueDate(id: string, body: ShiftDueDatePayload): Promise<Shift> {
+    return this.request<Shift>("POST", `/api/v1/todos/${encodeURIComponent(id)}/due-date`, { body });
+  }
+
+  /** Preview moving a due date along with the todos waiting on it */
+  previewDueDateShift(id: string, body: ShiftDueDatePayload): Promise<Shift> {
+    return this.request<Shift>("POST", `/api/v1/todos/${encodeURIComponent(id)}/due-date/preview`, { body });
+  }
+
+  /** Merge duplicate todos into one */
+  mergeTodos(id: string, body: MergeTodosPayload): Promise<Todo> {
+    return this.request<Todo>("POST", `/api/v1/todos/${encodeURIComponent(id)}/merge`, { body });
+  }
+
+  /** Get the private note the user wrote on a todo */
+  getTodoPrivateNote(id: string): Promise<Note> {
+    return this.request<Note>("GET", `/api/v1/todos/${encodeURIComponent(id)}/private-note`);
+  }
+
+  /** Write a private note on a todo, only its author can read it */
+  setTodoPrivateNote(id: string, body: SetNotePayload): Promise<Note> {
+    return this.request<Note>("PUT", `/api/v1/todos/${encodeURIComponent(id)}/private-note`, { body });
+  }
+
+  /** Delete the private note the user wrote on a todo */
+  deleteTodoPrivateNote(id: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/todos/${encodeURIComponent(id)}/private-note`);
+  }
+
+  /** Mark a todo and its comments as read */
+  markTodoRead(id: string): Promise<Todo> {
+    return this.request<Todo>("POST", `/api/v1/todos/${encodeURIComponent(id)}/read`);
+  }
+
+  /** Suggest a subtask breakdown of a todo */
+  suggestSubtasks(id: string, body: SuggestSubtasksPayload): Promise<SubtaskSuggestions> {
+    return this.request<SubtaskSuggestions>("POST", `/api/v1/todos/${encodeURIComponent(id)}/suggest-subtasks`, { body });
+  }
+
+  /** Undo the completion of a todo shortly after it */
+  uncompleteTodo(id: string): Promise<Todo> {
+    return this.request<Todo>("POST", `/api/v1/todos/${encodeURIComponent(id)}/uncomplete`);
+  }
+
+  /** List the kept versions of a todo */
+  getTodoVersions(id: string): Promise<TodoVersion[]> {
+    return this.request<TodoVersion[]>("GET", `/api/v1/todos/${encodeURIComponent(id)}/versions`);
+  }
+
+  /** Restore a todo to an earlier version */
+  restoreTodoVersion(id: string, version: string): Promise<Todo> {
+    return this.request<Todo>("POST", `/api/v1/todos/${encodeURIComponent(id)}/versions/${encodeURIComponent(version)}/restore`);
+  }
+
+  /** Get metered usage of the user or their workspace */
+  getUsage(query: GetUsageQuery = {}): Promise<Usage> {
+    return this.request<Usage>("GET", `/api/v1/usage`, { query });
+  }
+
+  /** List the webhook endpoints of the workspace */
+  getWebhookEndpoints(): Promise<Endpoint[]> {
+    return this.request<Endpoint[]>("GET", `/api/v1/webhooks`);
+  }
+
+  /** Register a webhook endpoint, its signing secret is only returned once */
+  createWebhookEndpoint(body: CreateEndpointPayload): Promise<CreatedEndpoint> {
+    return this.request<CreatedEndpoint>("POST", `/api/v1/webhooks`, { body });
+  }
+
+  /** List the webhook event types with the JSON Schema of their payloads */
+  getWebhookEventTypes(): Promise<EventType[]> {
+    return this.request<EventType[]>("GET", `/api/v1/webhooks/events`);
+  }
+
+  /** Update a webhook endpoint */
+  updateWebhookEndpoint(id: string, body: UpdateEndpointPayload): Promise<Endpoint> {
+    return this.request<Endpoint>("PATCH", `/api/v1/webhooks/${encodeURIComponent(id)}`, { body });
+  }
+
+  /** Delete a webhook endpoint */
+  deleteWebhookEndpoint(id: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/webhooks/${encodeURIComponent(id)}`);
+  }
+
+  /** Send a signed sample payload to a webhook endpoint */
+  testWebhookEndpoint(id: string, body: TestEndpointPayload): Promise<TestDelivery> {
+    return this.request<TestDelivery>("POST", `/api/v1/webhooks/${encodeURIComponent(id)}/test`, { body });
+  }
+
+  /** List the aging policies of a workspace */
+  getWorkspaceAgingPolicies(workspaceId: string): Promise<AgingPolicy[]> {
+    return this.request<AgingPolicy[]>("GET", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/aging-policies`);
+  }
+
+  /** Escalate the todos of a workspace left open too long */
+  createWorkspaceAgingPolicy(workspaceId: string, body: CreateAgingPolicyPayload): Promise<AgingPolicy> {
+    return this.request<AgingPolicy>("POST", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/aging-policies`, { body });
+  }
+
+  /** Remove an aging policy of a workspace */
+  deleteWorkspaceAgingPolicy(workspaceId: string, policyId: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/aging-policies/${encodeURIComponent(policyId)}`);
+  }
+
+  /** Get the freeze of a workspace and when its data is purged */
+  getWorkspaceFreeze(workspaceId: string): Promise<Freeze> {
+    return this.request<Freeze>("GET", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/freeze`);
+  }
+
+  /** Freeze a workspace, export it and schedule its data for deletion */
+  freezeWorkspace(workspaceId: string, body: FreezeWorkspacePayload): Promise<Freeze> {
+    return this.request<Freeze>("POST", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/freeze`, { body });
+  }
+
+  /** Lift the freeze of a workspace not purged yet */
+  reactivateWorkspace(workspaceId: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/freeze`);
+  }
+
+  /** List the pending and closed invites of a workspace and who accepted them */
+  getWorkspaceInvites(workspaceId: string): Promise<Invitations> {
+    return this.request<Invitations>("GET", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/invites`);
+  }
+
+  /** Create a shareable invite link to a workspace */
+  createWorkspaceInvite(workspaceId: string, body: CreateInvitePayload): Promise<Invite> {
+    return this.request<Invite>("POST", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/invites`, { body });
+  }
+
+  /** Stop an invite link of a workspace from being accepted */
+  revokeWorkspaceInvite(workspaceId: string, inviteId: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/invites/${encodeURIComponent(inviteId)}`);
+  }
+
+  /** Get the onboarding kit of a workspace */
+  getWorkspaceOnboardingKit(workspaceId: string): Promise<OnboardingKit> {
+    return this.request<OnboardingKit>("GET", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/onboarding-kit`);
+  }
+
+  /** Set the categories and todos new members of a workspace get */
+  setWorkspaceOnboardingKit(workspaceId: string, body: SetOnboardingKitPayload): Promise<OnboardingKit> {
+    return this.request<OnboardingKit>("PUT", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/onboarding-kit`, { body });
+  }
+
+  /** Remove the onboarding kit of a workspace */
+  deleteWorkspaceOnboardingKit(workspaceId: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/onboarding-kit`);
+  }
+
+  /** List the open todos breaching the aging policies of the workspace */
+  getWorkspaceSLABreaches(workspaceId: string): Promise<BreachReport> {
+    return this.request<BreachReport>("GET", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/sla-breaches`);
+  }
+
+  /** List the templates of a workspace */
+  getManagedWorkspaceTemplates(workspaceId: string): Promise<Template[]> {
+    return this.request<Template[]>("GET", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/templates`);
+  }
+
+  /** Publish a todo template to a workspace */
+  createWorkspaceTemplate(workspaceId: string, body: CreateTemplatePayload): Promise<Template> {
+    return this.request<Template>("POST", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/templates`, { body });
+  }
+
+  /** Remove a template of a workspace */
+  deleteWorkspaceTemplate(workspaceId: string, templateId: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/templates/${encodeURIComponent(templateId)}`);
+  }
+
+  /** Get the statuses and priorities of the workspace */
+  getWorkspaceWorkflow(workspaceId: string): Promise<Workflow> {
+    return this.request<Workflow>("GET", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/workflow`);
+  }
+
+  /** Replace the statuses and priority scale of a workspace, moving its todos onto them */
+  setWorkspaceWorkflow(workspaceId: string, body: SetWorkflowPayload): Promise<Workflow> {
+    return this.request<Workflow>("PUT", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/workflow`, { body });
+  }
+
+  /** Bring a workspace back to the built-in statuses and priorities */
+  deleteWorkspaceWorkflow(workspaceId: string): Promise<void> {
+    return this.request<void>("DELETE", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/workflow`);
+  }
+
+  /** Sum the open todos and estimates of every member per week */
+  getWorkspaceWorkload(workspaceId: string, query: GetWorkspaceWorkloadQuery = {}): Promise<Workload> {
+    return this.request<Workload>("GET", `/api/v1/workspaces/${encodeURIComponent(workspaceId)}/workload`, { query });
+  }
+
+  /** Check that the API process is alive */
+  getLiveness(): Promise<HealthReport> {
+    return this.request<HealthReport>("GET", `/healthz`);
+  }
+
+  /** Probe the dependencies of the API */
+  getReadiness(): Promise<HealthReport> {
+    return this.request<HealthReport>("GET", `/readyz`);
+  }
+
+  /** Get the component health history and incidents of the status page */
+  getStatusPage(): Promise<Page> {
+    return this.request<Page>("GET", `/status`);
+  }
+}
@@ -0,0 +1,2 @@
+export { ApiError, type ClientOptions, type TokenProvider } from "./base.js";
+export * from "./client.gen.js";